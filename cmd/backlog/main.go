@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"github.com/alexbrand/backlog/internal/cli"
+	"github.com/alexbrand/backlog/internal/execbackend"
 	"github.com/alexbrand/backlog/internal/github"
 	"github.com/alexbrand/backlog/internal/linear"
 	"github.com/alexbrand/backlog/internal/local"
@@ -14,6 +15,7 @@ func init() {
 	local.Register()
 	github.Register()
 	linear.Register()
+	execbackend.Register()
 }
 
 func main() {
@@ -1,7 +1,11 @@
 // Package backend defines the core types and interfaces for backlog backends.
 package backend
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 // Status represents the canonical status of a task.
 type Status string
@@ -79,6 +83,9 @@ type Task struct {
 	// Labels are tags/labels associated with the task.
 	Labels []string `json:"labels,omitempty" yaml:"labels,omitempty"`
 
+	// Parent is the ID of the task this task is a sub-task of, if any.
+	Parent string `json:"parent,omitempty" yaml:"parent,omitempty"`
+
 	// Created is the creation timestamp.
 	Created time.Time `json:"created" yaml:"created"`
 
@@ -92,6 +99,40 @@ type Task struct {
 	// Zero value means no explicit ordering has been set.
 	SortOrder float64 `json:"sort_order,omitempty" yaml:"sort_order,omitempty"`
 
+	// Estimate is the estimated effort for the task. Zero means no estimate.
+	Estimate Duration `json:"estimate,omitempty" yaml:"estimate,omitempty"`
+
+	// Spent is the total time tracked against the task.
+	Spent Duration `json:"spent,omitempty" yaml:"spent,omitempty"`
+
+	// DeletedAt is when the task was soft-deleted. Nil for live tasks.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" yaml:"deleted_at,omitempty"`
+
+	// StartedAt is when work on the task started, normally its first claim.
+	// Nil if the task was never started.
+	StartedAt *time.Time `json:"started_at,omitempty" yaml:"started_at,omitempty"`
+
+	// CompletedAt is when the task was last moved to done. Nil for tasks
+	// that are not done.
+	CompletedAt *time.Time `json:"completed_at,omitempty" yaml:"completed_at,omitempty"`
+
+	// Duration is how long the task took from StartedAt to CompletedAt, or
+	// has taken so far if it is not done. It is computed, not stored.
+	Duration Duration `json:"duration,omitempty" yaml:"-"`
+
+	// TimeApproximate indicates StartedAt and CompletedAt were derived from
+	// other data, such as label or state change timestamps, rather than
+	// recorded by backlog.
+	TimeApproximate bool `json:"time_approximate,omitempty" yaml:"-"`
+
+	// Checklist holds the markdown checklist items in Description. It is
+	// parsed from the description, not stored.
+	Checklist []ChecklistItem `json:"checklist,omitempty" yaml:"-"`
+
+	// ChecklistProgress counts the checked items of Checklist. Nil if the
+	// description has no checklist.
+	ChecklistProgress *ChecklistProgress `json:"checklist_progress,omitempty" yaml:"-"`
+
 	// Meta contains backend-specific fields.
 	Meta map[string]any `json:"meta,omitempty" yaml:"meta,omitempty"`
 }
@@ -109,6 +150,10 @@ type Comment struct {
 
 	// Created is the creation timestamp.
 	Created time.Time `json:"created" yaml:"created"`
+
+	// TaskID is the task the comment belongs to. It is set on comments
+	// returned by AddComment and EditComment.
+	TaskID string `json:"task_id,omitempty" yaml:"-"`
 }
 
 // TaskList represents a paginated list of tasks.
@@ -121,6 +166,17 @@ type TaskList struct {
 
 	// HasMore indicates if there are more tasks available.
 	HasMore bool `json:"hasMore"`
+
+	// Warnings describes problems encountered while listing, such as task
+	// files that could not be parsed and were left out of Tasks.
+	Warnings []string `json:"warnings,omitempty"`
+
+	// Skipped is the number of task files that could not be read and were
+	// left out of Tasks. Each also has an entry in Warnings.
+	Skipped int `json:"skipped,omitempty"`
+
+	// Filters echoes the time cutoffs applied, if any.
+	Filters *AppliedFilters `json:"filters,omitempty"`
 }
 
 // TaskFilters specifies filtering options for listing tasks.
@@ -137,34 +193,88 @@ type TaskFilters struct {
 	// Labels filters by labels (task must have all specified labels).
 	Labels []string
 
+	// Parent filters to sub-tasks of the given task ID.
+	Parent string
+
 	// Limit is the maximum number of tasks to return.
 	Limit int
 
 	// IncludeDone includes tasks with done status (excluded by default).
 	IncludeDone bool
+
+	// IncludeDeleted includes soft-deleted tasks (excluded by default).
+	IncludeDeleted bool
+
+	// CreatedSince, if set, excludes tasks created before it.
+	CreatedSince time.Time
+
+	// UpdatedSince, if set, excludes tasks last updated before it.
+	UpdatedSince time.Time
+
+	// HasComments, if set, keeps only tasks with at least one comment
+	// (true) or with none (false).
+	HasComments *bool
+}
+
+// AppliedFilters echoes the absolute time cutoffs a list was filtered with,
+// so that scripts can verify what relative durations resolved to.
+type AppliedFilters struct {
+	// CreatedSince is the resolved --created-since cutoff.
+	CreatedSince *time.Time `json:"created_since,omitempty"`
+
+	// UpdatedSince is the resolved --updated-since cutoff.
+	UpdatedSince *time.Time `json:"updated_since,omitempty"`
 }
 
 // TaskInput specifies fields for creating a new task.
 type TaskInput struct {
 	// Title is the task title (required).
-	Title string
+	Title string `json:"title"`
 
 	// Description is the task description (optional).
-	Description string
+	Description string `json:"description,omitempty"`
 
 	// Status is the initial status (defaults to backlog).
-	Status Status
+	Status Status `json:"status,omitempty"`
 
 	// Priority is the priority level (defaults to none).
-	Priority Priority
+	Priority Priority `json:"priority,omitempty"`
 
 	// Labels are initial labels for the task.
-	Labels []string
+	Labels []string `json:"labels,omitempty"`
 
 	// Assignee is the initial assignee (optional).
-	Assignee string
+	Assignee string `json:"assignee,omitempty"`
+
+	// Parent is the ID of the parent task (optional).
+	Parent string `json:"parent,omitempty"`
+
+	// IdempotencyKey makes creation safe to retry (optional). If a task was
+	// created with the same key within the workspace's idempotency window,
+	// Create returns that task instead of creating another.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+
+	// ID pins the task ID instead of generating one (optional). Only the
+	// local backend supports it, and Create fails if the ID is taken.
+	ID string `json:"id,omitempty"`
+
+	// IfAbsent makes Create return a matching task that isn't done instead
+	// of creating a duplicate (optional). See FindExisting for what matches.
+	IfAbsent bool `json:"if_absent,omitempty"`
 }
 
+// DefaultIdempotencyWindow is how long idempotency keys are remembered when
+// the workspace doesn't configure idempotency_window.
+const DefaultIdempotencyWindow = 24 * time.Hour
+
+// MetaIdempotentReplay is set in Task.Meta when Create returns a task made
+// earlier with the same idempotency key rather than creating a new one.
+const MetaIdempotentReplay = "idempotent_replay"
+
+// MetaAlreadyExists is set in Task.Meta when Create returns an existing task
+// matched by TaskInput.IfAbsent rather than creating a new one.
+const MetaAlreadyExists = "already_exists"
+
 // TaskChanges specifies fields to update on an existing task.
 type TaskChanges struct {
 	// Title is the new title (nil means no change).
@@ -184,6 +294,16 @@ type TaskChanges struct {
 
 	// RemoveLabels are labels to remove.
 	RemoveLabels []string
+
+	// Estimate is the new estimate (nil means no change, zero clears it).
+	Estimate *Duration
+
+	// Spent is the new total tracked time (nil means no change).
+	Spent *Duration
+
+	// Parent is the new parent task ID (nil means no change, empty string
+	// makes the task top-level).
+	Parent *string
 }
 
 // HealthStatus represents the health of a backend connection.
@@ -196,6 +316,22 @@ type HealthStatus struct {
 
 	// Latency is the response time of the health check.
 	Latency time.Duration
+
+	// RateLimit is the API rate limit reported by the health check, or nil
+	// if the backend doesn't report one.
+	RateLimit *RateLimit
+}
+
+// RateLimit is an API rate limit as last reported by a remote backend.
+type RateLimit struct {
+	// Limit is the number of requests allowed per window.
+	Limit int `json:"limit"`
+
+	// Remaining is the number of requests left in the current window.
+	Remaining int `json:"remaining"`
+
+	// Reset is when the current window ends and Remaining is restored.
+	Reset time.Time `json:"reset"`
 }
 
 // ClaimResult represents the result of a claim operation.
@@ -205,6 +341,49 @@ type ClaimResult struct {
 
 	// AlreadyOwned indicates if the task was already claimed by this agent.
 	AlreadyOwned bool
+
+	// ExpiresAt is when the claim's lock expires, or zero if the claim
+	// doesn't expire.
+	ExpiresAt time.Time
+}
+
+// BatchClaimResult is the result of claiming a batch of tasks.
+type BatchClaimResult struct {
+	// Claimed holds the tasks claimed, in candidate order.
+	Claimed []ClaimResult
+
+	// Skipped holds the candidates that could not be claimed and why.
+	Skipped []SkippedCandidate
+}
+
+// DryRunResult describes the change a mutating command would make, computed
+// without writing anything.
+type DryRunResult struct {
+	// Action is the operation that would run (e.g., "add", "move").
+	Action string `json:"action"`
+
+	// Task is the task as it would be after the change.
+	Task *Task `json:"task"`
+
+	// FromStatus is the task's status before a status change.
+	FromStatus Status `json:"from_status,omitempty"`
+
+	// ToStatus is the task's status after a status change.
+	ToStatus Status `json:"to_status,omitempty"`
+
+	// Detail describes the change further, such as a reorder position.
+	Detail string `json:"detail,omitempty"`
+}
+
+// SkippedCandidate describes a task that was passed over while picking the
+// next task to claim, such as one that is blocked or was claimed first by
+// another agent.
+type SkippedCandidate struct {
+	// ID is the ID of the skipped task.
+	ID string `json:"id"`
+
+	// Reason explains why the task was skipped.
+	Reason string `json:"reason"`
 }
 
 // SyncResult represents the result of a sync operation.
@@ -223,6 +402,92 @@ type SyncResult struct {
 
 	// Conflicts is the number of conflicts encountered.
 	Conflicts int
+
+	// Resolved lists the IDs of tasks whose conflicting changes were merged.
+	Resolved []string
+}
+
+// ConflictSide picks the local or remote side of a sync conflict.
+type ConflictSide string
+
+const (
+	// ConflictOurs keeps the local side.
+	ConflictOurs ConflictSide = "ours"
+
+	// ConflictTheirs keeps the remote side.
+	ConflictTheirs ConflictSide = "theirs"
+)
+
+// FieldConflict is a task field that the local and remote sides of a sync
+// changed to different values, or a file that isn't a task and conflicts as
+// a whole.
+type FieldConflict struct {
+	// TaskID is the task's ID, or empty for a file that isn't a task.
+	TaskID string `json:"task_id,omitempty"`
+
+	// File is the conflicting file, relative to the repository.
+	File string `json:"file"`
+
+	// Field is the frontmatter field, "description", "deleted" when one side
+	// deleted the task, or "content" for a file that isn't a task.
+	Field string `json:"field"`
+
+	// Ours is the local value.
+	Ours string `json:"ours"`
+
+	// Theirs is the remote value.
+	Theirs string `json:"theirs"`
+}
+
+// ConflictChooser picks which side of a field conflict a sync keeps. An
+// error aborts the sync.
+type ConflictChooser func(conflict FieldConflict) (ConflictSide, error)
+
+// SyncStatus describes how a local backlog has diverged from its remote,
+// without changing either side.
+type SyncStatus struct {
+	// Remote is the URL of the remote the upstream branch belongs to.
+	Remote string `json:"remote,omitempty"`
+
+	// Upstream is the upstream branch (e.g., "origin/main").
+	Upstream string `json:"upstream,omitempty"`
+
+	// Ahead is the number of local commits not on the upstream.
+	Ahead int `json:"ahead"`
+
+	// Behind is the number of upstream commits not present locally.
+	Behind int `json:"behind"`
+
+	// Dirty indicates uncommitted changes in the repository, which block
+	// mutations and sync until they are committed or stashed.
+	Dirty bool `json:"dirty"`
+
+	// Updated lists tasks a pull would change.
+	Updated []string `json:"updated"`
+
+	// Added lists tasks a pull would add.
+	Added []string `json:"added"`
+
+	// Removed lists tasks a pull would remove.
+	Removed []string `json:"removed"`
+
+	// InSync is true when neither side has commits the other lacks.
+	InSync bool `json:"in_sync"`
+
+	// Message explains why divergence could not be checked, such as when
+	// no remote is configured.
+	Message string `json:"message,omitempty"`
+}
+
+// AgentIdentity is the resolved identity of the agent running a command and
+// where it came from.
+type AgentIdentity struct {
+	ID          string `json:"agent_id"`
+	Source      string `json:"source"`           // flag, env, file, config, or generated
+	Origin      string `json:"origin,omitempty"` // Flag, variable, config key, or file that supplied the ID
+	Workspace   string `json:"workspace,omitempty"`
+	LabelPrefix string `json:"agent_label_prefix"`
+	Label       string `json:"label"` // Label applied to tasks this agent claims
 }
 
 // Config holds backend-specific configuration.
@@ -235,6 +500,10 @@ type Config struct {
 
 	// AgentLabelPrefix is the prefix for agent labels (e.g., "agent").
 	AgentLabelPrefix string
+
+	// WorkspaceName is the workspace's name in the config, used to find its
+	// own credentials. It is empty without a config.
+	WorkspaceName string
 }
 
 // Backend defines the interface that all backlog backends must implement.
@@ -285,6 +554,23 @@ type Backend interface {
 	AddComment(id string, body string) (*Comment, error)
 }
 
+// Counter is an optional interface for backends that can count matching tasks
+// more cheaply than listing them.
+type Counter interface {
+	// Count returns the number of tasks matching the given filters. Limit
+	// is ignored.
+	Count(filters TaskFilters) (int, error)
+}
+
+// Sorter is an optional interface for backends that can list tasks in a
+// requested order, applying Limit after sorting. Backends without it are
+// listed in full and sorted by the caller.
+type Sorter interface {
+	// ListSorted is List with the tasks sorted by keys, in turn, instead of
+	// the backend's default order.
+	ListSorted(filters TaskFilters, keys []SortKey) (*TaskList, error)
+}
+
 // Claimer is an optional interface for backends that support agent claim/release.
 type Claimer interface {
 	// Claim claims a task for an agent. Returns ClaimResult with the task.
@@ -295,12 +581,95 @@ type Claimer interface {
 	Release(id string) error
 }
 
+// ForceReleaser is an optional interface for claimers that can release a task
+// claimed by another agent, such as one left behind by an agent that died
+// holding its claim.
+type ForceReleaser interface {
+	// ForceRelease releases a claimed task back to todo like Release, but
+	// without checking that the current agent holds the claim. It returns
+	// the agent the task was claimed by.
+	ForceRelease(id string) (claimedBy string, err error)
+}
+
+// ClockRestarter is an optional interface for claimers that record when work
+// on a task started. A plain Claim keeps the start time of a task that was
+// claimed and released before.
+type ClockRestarter interface {
+	// ClaimRestartClock claims a task like Claim, but resets its start
+	// time to now.
+	ClaimRestartClock(id string, agentID string) (*ClaimResult, error)
+}
+
+// Stealer is an optional interface for claimers that can take over a task
+// claimed by another agent, such as one whose agent crashed while its claim
+// was still active.
+type Stealer interface {
+	// Steal claims a task for an agent like Claim, but takes it over even
+	// if another agent holds the claim. It returns the agent the task was
+	// taken from, or "" if no other agent held it.
+	Steal(id string, agentID string) (result *ClaimResult, claimedBy string, err error)
+}
+
+// BatchClaimer is an optional interface for backends that can claim several
+// tasks at once more cheaply than one at a time, such as the local backend in
+// git lock mode, which pulls and pushes once for the whole batch.
+type BatchClaimer interface {
+	// ClaimBatch claims up to count of the candidate tasks for an agent,
+	// trying them in order. Each claim is independent: a candidate already
+	// claimed by another agent, or deleted, is reported in Skipped and the
+	// next candidate is tried.
+	ClaimBatch(ids []string, agentID string, count int) (*BatchClaimResult, error)
+}
+
+// CreateClaimer is an optional interface for claimers that can create a task
+// and claim it in one step, such as the local backend recording both in a
+// single git commit. Backends without it are sent Create and then Claim.
+type CreateClaimer interface {
+	// CreateAndClaim creates a task and claims it for an agent. If the task
+	// is created but can't be claimed, the task is returned along with the
+	// claim error and left in place.
+	CreateAndClaim(input TaskInput, agentID string) (*Task, *ClaimResult, error)
+}
+
 // Syncer is an optional interface for backends that support sync operations.
 type Syncer interface {
 	// Sync synchronizes local state with remote.
 	Sync(force bool) (*SyncResult, error)
 }
 
+// ConflictResolver is an optional interface for syncing backends that can
+// merge conflicting changes to tasks during a sync.
+type ConflictResolver interface {
+	// SyncResolving is Sync, except that conflicting task changes are merged
+	// field by field. Fields both sides changed differently are passed to
+	// choose; with a nil choose they abort the sync with a conflict error
+	// listing them.
+	SyncResolving(force bool, choose ConflictChooser) (*SyncResult, error)
+}
+
+// Puller is an optional interface for syncing backends that can bring in
+// remote changes before a mutation instead of refusing it.
+type Puller interface {
+	// Pull rebases local state onto the remote. It returns a conflict
+	// error only if the rebase itself conflicts.
+	Pull() error
+}
+
+// SyncInspector is an optional interface for syncing backends that can
+// report divergence from the remote without pulling or pushing.
+type SyncInspector interface {
+	// SyncStatus fetches from the remote and compares it with local state.
+	SyncStatus() (*SyncStatus, error)
+}
+
+// CleanChecker is an optional interface for backends that keep the backlog
+// in a git working tree and can report backlog files not yet committed.
+type CleanChecker interface {
+	// UncommittedFiles returns the paths, relative to the repository root,
+	// of the backlog files with staged, unstaged, or untracked changes.
+	UncommittedFiles() ([]string, error)
+}
+
 // ReorderPosition specifies where to place a task in the sort order.
 // Exactly one field should be set.
 type ReorderPosition struct {
@@ -315,6 +684,10 @@ type ReorderPosition struct {
 
 	// Last moves the task to the bottom of its group.
 	Last bool
+
+	// Position moves the task to this 1-based position within its group,
+	// between the tasks now at Position-1 and Position. Zero means unset.
+	Position int
 }
 
 // Reorderer is an optional interface for backends that support explicit task reordering.
@@ -330,11 +703,16 @@ type RelationType string
 const (
 	RelationBlocks    RelationType = "blocks"
 	RelationBlockedBy RelationType = "blocked-by"
+
+	// RelationParent and RelationChild describe the sub-task hierarchy. They
+	// are reported by ListRelations but set through Task.Parent, not Link.
+	RelationParent RelationType = "parent"
+	RelationChild  RelationType = "child"
 )
 
 // Relation represents a dependency relationship between two tasks.
 type Relation struct {
-	// Type is the relationship type (blocks or blocked-by).
+	// Type is the relationship type (blocks, blocked-by, parent, or child).
 	Type RelationType `json:"type"`
 
 	// TaskID is the ID of the related task.
@@ -355,6 +733,532 @@ type Relater interface {
 	// Unlink removes a dependency relationship between two tasks.
 	Unlink(sourceID, targetID string, relationType RelationType) error
 
-	// ListRelations returns all dependency relationships for a task.
+	// ListRelations returns all dependency relationships for a task,
+	// including its parent and children.
 	ListRelations(id string) ([]Relation, error)
 }
+
+// RawGetter is an optional interface for backends that can return a task in
+// its underlying storage representation, bypassing Task normalization.
+type RawGetter interface {
+	// GetRaw returns the backend's native representation of a task
+	// (e.g., the task file for local, the API response for Linear).
+	GetRaw(id string) ([]byte, error)
+}
+
+// SoftDeleter is an optional interface for backends that can set a task aside
+// instead of removing it, so it can be restored later.
+type SoftDeleter interface {
+	// SoftDelete hides a task from listings and lookups without losing it.
+	SoftDelete(id string) error
+
+	// Restore brings back a soft-deleted task and returns it.
+	Restore(id string) (*Task, error)
+}
+
+// CommentFilters narrows a comment thread to its recent comments.
+type CommentFilters struct {
+	// Since only includes comments created after this time (zero means no
+	// limit).
+	Since time.Time
+
+	// Limit only includes the most recent N comments (0 means no limit).
+	Limit int
+}
+
+// CommentFilterer is an optional interface for backends that can filter
+// comments when fetching them, instead of returning the whole thread.
+type CommentFilterer interface {
+	// ListCommentsFiltered returns the comments on a task that match the
+	// given filters, oldest first.
+	ListCommentsFiltered(id string, filters CommentFilters) ([]Comment, error)
+}
+
+// CommentEditor is an optional interface for backends that can change or
+// remove existing comments. Unless force is set, both refuse a comment
+// written by someone else with a *CommentAuthorError.
+type CommentEditor interface {
+	// EditComment replaces the body of a comment on a task and returns the
+	// updated comment.
+	EditComment(taskID, commentID, body string, force bool) (*Comment, error)
+
+	// DeleteComment removes a comment from a task.
+	DeleteComment(taskID, commentID string, force bool) error
+}
+
+// CommentAuthorError is returned when a comment written by someone else is
+// edited or deleted without force.
+type CommentAuthorError struct {
+	// CommentID is the comment that was refused.
+	CommentID string
+
+	// Author is the comment's author.
+	Author string
+}
+
+func (e *CommentAuthorError) Error() string {
+	return fmt.Sprintf("comment %s was written by %s", e.CommentID, e.Author)
+}
+
+// NotFoundError is returned by every backend when a task does not exist.
+// Callers check for it with errors.As rather than matching error text.
+type NotFoundError struct {
+	// ID is the task ID that was looked up.
+	ID string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("task not found: %s", e.ID)
+}
+
+// IsNotFound reports whether err, or any error it wraps, is a
+// *NotFoundError.
+func IsNotFound(err error) bool {
+	var notFound *NotFoundError
+	return errors.As(err, &notFound)
+}
+
+// AlreadyExistsError is returned when a task can't be created or restored
+// because its ID is taken. Callers check for it with errors.As rather than
+// matching error text.
+type AlreadyExistsError struct {
+	// ID is the task ID that is taken.
+	ID string
+	// Where says where the existing task is, such as "in the trash", or is
+	// empty for the backlog itself.
+	Where string
+}
+
+func (e *AlreadyExistsError) Error() string {
+	if e.Where != "" {
+		return fmt.Sprintf("task %s already exists %s", e.ID, e.Where)
+	}
+	return fmt.Sprintf("task %s already exists", e.ID)
+}
+
+// IsAlreadyExists reports whether err, or any error it wraps, is an
+// *AlreadyExistsError.
+func IsAlreadyExists(err error) bool {
+	var exists *AlreadyExistsError
+	return errors.As(err, &exists)
+}
+
+// TimeReport summarizes how long completed tasks took.
+type TimeReport struct {
+	// Tasks is the number of completed tasks with a recorded duration.
+	Tasks int `json:"tasks"`
+
+	// Total is the sum of their durations.
+	Total Duration `json:"total"`
+
+	// Average is the mean duration per task.
+	Average Duration `json:"average"`
+
+	// Labels breaks the totals down by label, sorted by name. A task with
+	// several labels counts toward each of them.
+	Labels []LabelTime `json:"labels"`
+
+	// Approximate indicates some durations were derived rather than
+	// recorded (see Task.TimeApproximate).
+	Approximate bool `json:"approximate,omitempty"`
+}
+
+// LabelTime is the share of a TimeReport belonging to one label.
+type LabelTime struct {
+	Label   string   `json:"label"`
+	Tasks   int      `json:"tasks"`
+	Total   Duration `json:"total"`
+	Average Duration `json:"average"`
+}
+
+// Changelog lists the tasks completed in a window, grouped into sections by
+// their type label.
+type Changelog struct {
+	// Since is the start of the window, or nil for all completed tasks.
+	Since *time.Time `json:"since,omitempty"`
+
+	// SinceTag is the git tag Since was taken from, if any.
+	SinceTag string `json:"since_tag,omitempty"`
+
+	// Count is the number of tasks across all sections.
+	Count int `json:"count"`
+
+	// Sections holds the configured sections in order, then one per other
+	// type label by name, then "Other". Empty sections are left out.
+	Sections []ChangelogSection `json:"sections"`
+}
+
+// ChangelogSection is the share of a Changelog with one type label.
+type ChangelogSection struct {
+	// Title is the section heading, such as "Bug Fixes".
+	Title string `json:"title"`
+
+	// Label is the type label of the section's tasks, such as "type:bug",
+	// or empty for the tasks without one.
+	Label string `json:"label,omitempty"`
+
+	// Tasks are the section's tasks, in the order they were completed.
+	Tasks []ChangelogEntry `json:"tasks"`
+}
+
+// ChangelogEntry is a completed task listed in a Changelog.
+type ChangelogEntry struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url,omitempty"`
+	CompletedAt time.Time `json:"completed_at"`
+
+	// Authors are the task's assignee and the agents named by its agent
+	// labels, when requested.
+	Authors []string `json:"authors,omitempty"`
+}
+
+// Label describes a task label and its display metadata.
+type Label struct {
+	// Name is the label as it appears on tasks.
+	Name string `json:"name" yaml:"-"`
+
+	// Color is the label's color, either a hex value such as "#d73a4a" or
+	// an ANSI color name such as "red".
+	Color string `json:"color,omitempty" yaml:"color,omitempty"`
+
+	// Description explains what the label is for.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// Tasks is the number of tasks carrying the label, when counted.
+	Tasks *int `json:"tasks,omitempty" yaml:"-"`
+}
+
+// LabelLister is an optional interface for backends that keep a registry of
+// known labels with their colors and descriptions.
+type LabelLister interface {
+	// ListLabels returns the known labels, sorted by name.
+	ListLabels() ([]Label, error)
+}
+
+// LabelManager is an optional interface for backends that can count how
+// labels are used and rename or remove a label across every task.
+type LabelManager interface {
+	// LabelUsage returns every label in the workspace, whether registered
+	// or only carried by tasks, with Tasks set, sorted by name.
+	LabelUsage() ([]Label, error)
+
+	// RenameLabel replaces oldName with newName on every task carrying it
+	// and in the label registry. A task that already has newName keeps a
+	// single copy. Agent labels are refused with an AgentLabelError.
+	RenameLabel(oldName, newName string) (*LabelChange, error)
+
+	// RemoveLabel removes name from the label registry and, with force,
+	// from every task carrying it. Without force it refuses with a
+	// LabelInUseError while any task carries the label.
+	RemoveLabel(name string, force bool) (*LabelChange, error)
+}
+
+// ErrLabelNotFound is returned when renaming or removing a label that is
+// neither registered nor carried by any task.
+var ErrLabelNotFound = errors.New("label not found")
+
+// LabelChange is the result of renaming or removing a label.
+type LabelChange struct {
+	// Action is "rename" or "remove".
+	Action string `json:"action"`
+
+	// Label is the label renamed or removed.
+	Label string `json:"label"`
+
+	// NewName is the label's new name, for a rename.
+	NewName string `json:"new_name,omitempty"`
+
+	// Tasks lists the IDs of the tasks whose labels changed.
+	Tasks []string `json:"tasks"`
+}
+
+// AgentLabelError is returned when a label operation targets an agent label
+// such as "agent:claude-1", which only claim and release may change.
+type AgentLabelError struct {
+	Label string
+}
+
+func (e *AgentLabelError) Error() string {
+	return fmt.Sprintf("%q is an agent label; use claim and release to change it", e.Label)
+}
+
+// LabelInUseError is returned when removing a label that tasks still carry.
+type LabelInUseError struct {
+	Label string
+	Tasks int
+}
+
+func (e *LabelInUseError) Error() string {
+	return fmt.Sprintf("label %q is on %d task(s); use --force to remove it from them", e.Label, e.Tasks)
+}
+
+// Reopener is an optional interface for backends that can reopen a completed
+// task as a single operation. Backends without it are reopened with Move
+// followed by AddComment.
+type Reopener interface {
+	// Reopen moves a done task to the given status and adds the comment.
+	// Returns an error if the task is not currently done.
+	Reopen(id string, status Status, comment string) (*Task, error)
+}
+
+// MoveAssigner is an optional interface for backends that can move a task
+// and set its assignee as a single operation. Backends without it are moved
+// with Move followed by Assign or Unassign.
+type MoveAssigner interface {
+	// MoveAndAssign transitions a task to status and assigns it to
+	// assignee. An empty assignee unassigns the task.
+	MoveAndAssign(id string, status Status, assignee string) (*Task, error)
+}
+
+// Severity indicates how serious a doctor finding is.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// StatusSource selects which side wins when a task's recorded status
+// disagrees with where it is stored.
+type StatusSource string
+
+const (
+	// StatusSourceDirectory keeps the file where it is and rewrites the
+	// recorded status to match its directory.
+	StatusSourceDirectory StatusSource = "directory"
+	// StatusSourceFrontmatter moves the file to the directory matching its
+	// recorded status.
+	StatusSourceFrontmatter StatusSource = "frontmatter"
+)
+
+// DoctorOptions configures a workspace integrity check.
+type DoctorOptions struct {
+	// Fix applies safe repairs for the problems found.
+	Fix bool
+
+	// StatusSource decides how status mismatches are repaired.
+	StatusSource StatusSource
+}
+
+// DoctorFinding is a single problem found by a workspace integrity check.
+type DoctorFinding struct {
+	// Check identifies the kind of problem (e.g., "duplicate_id").
+	Check string `json:"check"`
+
+	// Severity is how serious the problem is.
+	Severity Severity `json:"severity"`
+
+	// TaskID is the task the problem concerns, if known.
+	TaskID string `json:"task_id,omitempty"`
+
+	// Path is the affected file, relative to the workspace.
+	Path string `json:"path,omitempty"`
+
+	// Message describes the problem.
+	Message string `json:"message"`
+
+	// Suggestion describes how to repair the problem.
+	Suggestion string `json:"suggestion"`
+
+	// Fixed indicates the problem was repaired by this run.
+	Fixed bool `json:"fixed"`
+}
+
+// DoctorReport is the result of a workspace integrity check.
+type DoctorReport struct {
+	// Findings lists every problem found, including repaired ones.
+	Findings []DoctorFinding `json:"findings"`
+
+	// Fixed is the number of findings repaired by this run.
+	Fixed int `json:"fixed"`
+
+	// OK is true when no unrepaired problems remain.
+	OK bool `json:"ok"`
+}
+
+// Diagnoser is an optional interface for backends that can check the
+// integrity of their storage and repair common problems.
+type Diagnoser interface {
+	// Diagnose scans the workspace and reports problems, repairing the
+	// safe ones when opts.Fix is set.
+	Diagnose(opts DoctorOptions) (*DoctorReport, error)
+}
+
+// ReindexResult is the result of rebuilding a backend's task index.
+type ReindexResult struct {
+	// Path is the index file that was written.
+	Path string `json:"path"`
+
+	// Tasks is the number of task files indexed.
+	Tasks int `json:"tasks"`
+
+	// Skipped describes task files that could not be parsed.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// Reindexer is an optional interface for backends that keep an index of
+// their tasks that can be rebuilt from scratch.
+type Reindexer interface {
+	// Reindex discards the index and rebuilds it from the stored tasks.
+	Reindex() (*ReindexResult, error)
+}
+
+// HistoryEntry is a single event in a task's history.
+type HistoryEntry struct {
+	// Commit is the hash of the commit that recorded the event, if any.
+	Commit string `json:"commit,omitempty"`
+
+	// Action is the kind of change (e.g., "add", "move", "claim").
+	Action string `json:"action"`
+
+	// Agent is the agent that made the change, when recorded.
+	Agent string `json:"agent,omitempty"`
+
+	// Author is the commit author, if any.
+	Author string `json:"author,omitempty"`
+
+	// Timestamp is when the change was made.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Status is the task's status after the change.
+	Status Status `json:"status,omitempty"`
+
+	// Message is the full commit subject.
+	Message string `json:"message,omitempty"`
+
+	// Diff is the patch for the change, when requested.
+	Diff string `json:"diff,omitempty"`
+}
+
+// TaskHistory is the chronological history of a task.
+type TaskHistory struct {
+	// TaskID is the task the history belongs to.
+	TaskID string `json:"task_id"`
+
+	// Entries lists the events, oldest first.
+	Entries []HistoryEntry `json:"entries"`
+
+	// Note explains why the history is incomplete, if it is.
+	Note string `json:"note,omitempty"`
+}
+
+// Historian is an optional interface for backends that can report the
+// history of changes to a task.
+type Historian interface {
+	// History returns the task's history. With includeDiff set, each entry
+	// carries the patch it applied.
+	History(id string, includeDiff bool) (*TaskHistory, error)
+}
+
+// TagDater is an optional interface for backends kept in a git repository,
+// which can tell when a tag was made so that a tag can mark the start of a
+// window such as "changelog --since-tag".
+type TagDater interface {
+	// TagTime returns the date of an annotated tag, or of the commit a
+	// lightweight tag points to.
+	TagTime(tag string) (time.Time, error)
+}
+
+// FieldBlame attributes the current value of a task field to the change
+// that last set it.
+type FieldBlame struct {
+	// Field is the task field, such as "status" or "labels".
+	Field string `json:"field"`
+
+	// Value is the field's current value, with labels comma-separated.
+	Value string `json:"value"`
+
+	// Commit is the hash of the commit that last changed the field.
+	Commit string `json:"commit,omitempty"`
+
+	// Author is the author of that commit.
+	Author string `json:"author,omitempty"`
+
+	// Agent is the agent named in the commit, when recorded.
+	Agent string `json:"agent,omitempty"`
+
+	// Timestamp is when the commit was made.
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+
+	// Message is the commit subject.
+	Message string `json:"message,omitempty"`
+
+	// Uncommitted is set when the current value has not been committed yet.
+	Uncommitted bool `json:"uncommitted,omitempty"`
+}
+
+// TaskBlame is the last change to each field of a task.
+type TaskBlame struct {
+	// TaskID is the task the fields belong to.
+	TaskID string `json:"task_id"`
+
+	// Fields lists the fields in a fixed order.
+	Fields []FieldBlame `json:"fields"`
+
+	// Note explains why changes could not be attributed, if they couldn't.
+	Note string `json:"note,omitempty"`
+}
+
+// Blamer is an optional interface for backends that can attribute each
+// field of a task to the change that last set it.
+type Blamer interface {
+	// Blame returns the last change to each field of the task.
+	Blame(id string) (*TaskBlame, error)
+}
+
+// UndoEntry is a recent mutation that can be undone.
+type UndoEntry struct {
+	// Action is the operation, such as "edit", "move" or "delete".
+	Action string `json:"action"`
+
+	// TaskID is the task it changed, or a summary for doctor and label
+	// changes.
+	TaskID string `json:"task_id"`
+
+	// Agent is the agent that made the change, when known.
+	Agent string `json:"agent,omitempty"`
+
+	// Timestamp is when the change was made.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Files lists the task files the change wrote or removed.
+	Files []string `json:"files"`
+}
+
+// ErrNothingToUndo is returned by Undo when no mutation is recorded.
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// Undoer is an optional interface for backends that record their recent
+// mutations and can revert them.
+type Undoer interface {
+	// UndoEntries returns the mutations that can be undone, most recent
+	// first.
+	UndoEntries() ([]UndoEntry, error)
+
+	// Undo reverts the most recent mutation and returns it, or returns
+	// ErrNothingToUndo if there is none.
+	Undo() (*UndoEntry, error)
+}
+
+// ChangeWatcher is an optional interface for backends that can tell when
+// their tasks change, so that next --wait wakes as soon as a task may have
+// become available instead of at its next poll. Backends without it are
+// only polled.
+type ChangeWatcher interface {
+	// WatchChanges starts watching for changes to tasks. The channel
+	// receives a value after tasks may have changed; changes made while a
+	// value is pending are folded into it. stop ends the watch.
+	WatchChanges() (changes <-chan struct{}, stop func(), err error)
+}
+
+// Negotiator is an optional interface for backends that only learn which
+// optional operations they support once connected, such as the exec backend,
+// whose program advertises them in a handshake. Callers replace the backend
+// with Negotiated after Connect, so that checks for optional interfaces see
+// what the connection supports.
+type Negotiator interface {
+	// Negotiated returns the connected backend, implementing only the
+	// optional interfaces the remote end supports.
+	Negotiated() Backend
+}
@@ -0,0 +1,90 @@
+package backend
+
+// Capability is an optional operation and whether a backend supports it.
+type Capability struct {
+	// Name identifies the operation, such as "reorder".
+	Name string `json:"name"`
+
+	// Commands lists the commands and flags that need the operation.
+	Commands string `json:"commands"`
+
+	// Supported is true if the backend implements the operation.
+	Supported bool `json:"supported"`
+}
+
+// Capabilities lists the optional operations of a backend.
+type Capabilities struct {
+	// Backend is the backend's name.
+	Backend string `json:"backend"`
+
+	// Operations lists every optional operation, in a fixed order.
+	Operations []Capability `json:"operations"`
+}
+
+// Supports reports whether the named operation is supported.
+func (c *Capabilities) Supports(name string) bool {
+	for _, op := range c.Operations {
+		if op.Name == name {
+			return op.Supported
+		}
+	}
+	return false
+}
+
+// WorkspaceCapabilities are the capabilities of a configured workspace's
+// backend.
+type WorkspaceCapabilities struct {
+	// Workspace is the workspace's name in the config.
+	Workspace string `json:"workspace"`
+
+	Capabilities
+
+	// Error describes why the capabilities couldn't be determined, such as
+	// an exec program that failed its handshake. Operations is empty then.
+	Error string `json:"error,omitempty"`
+}
+
+// capabilityChecks maps each optional operation to the interface a backend
+// implements to support it. Interfaces with a fallback for backends without
+// them, such as Counter or Reopener, are not listed.
+var capabilityChecks = []struct {
+	name     string
+	commands string
+	check    func(Backend) bool
+}{
+	{"claim", "claim, release, next --claim, add --claim", func(b Backend) bool { _, ok := b.(Claimer); return ok }},
+	{"force-release", "release --force", func(b Backend) bool { _, ok := b.(ForceReleaser); return ok }},
+	{"restart-clock", "claim --restart-clock", func(b Backend) bool { _, ok := b.(ClockRestarter); return ok }},
+	{"steal", "claim --steal", func(b Backend) bool { _, ok := b.(Stealer); return ok }},
+	{"reorder", "reorder", func(b Backend) bool { _, ok := b.(Reorderer); return ok }},
+	{"dependencies", "link, unlink, --blocks, --blocked-by", func(b Backend) bool { _, ok := b.(Relater); return ok }},
+	{"sync", "sync", func(b Backend) bool { _, ok := b.(Syncer); return ok }},
+	{"sync-status", "sync --status", func(b Backend) bool { _, ok := b.(SyncInspector); return ok }},
+	{"sync-resolve", "sync --strategy", func(b Backend) bool { _, ok := b.(ConflictResolver); return ok }},
+	{"raw", "show --raw", func(b Backend) bool { _, ok := b.(RawGetter); return ok }},
+	{"soft-delete", "delete --soft, restore", func(b Backend) bool { _, ok := b.(SoftDeleter); return ok }},
+	{"comment-edit", "comment --edit, comment --delete", func(b Backend) bool { _, ok := b.(CommentEditor); return ok }},
+	{"labels", "label list", func(b Backend) bool { _, ok := b.(LabelLister); return ok }},
+	{"history", "history", func(b Backend) bool { _, ok := b.(Historian); return ok }},
+	{"blame", "blame", func(b Backend) bool { _, ok := b.(Blamer); return ok }},
+	{"doctor", "doctor", func(b Backend) bool { _, ok := b.(Diagnoser); return ok }},
+	{"reindex", "reindex", func(b Backend) bool { _, ok := b.(Reindexer); return ok }},
+	{"tag-dates", "changelog --since-tag", func(b Backend) bool { _, ok := b.(TagDater); return ok }},
+	{"undo", "undo", func(b Backend) bool { _, ok := b.(Undoer); return ok }},
+	{"label-manage", "label rename, label rm", func(b Backend) bool { _, ok := b.(LabelManager); return ok }},
+	{"verify-clean", "verify-clean", func(b Backend) bool { _, ok := b.(CleanChecker); return ok }},
+}
+
+// CapabilitiesOf reports which optional operations b supports, detected
+// from the optional interfaces it implements.
+func CapabilitiesOf(b Backend) *Capabilities {
+	caps := &Capabilities{Backend: b.Name()}
+	for _, c := range capabilityChecks {
+		caps.Operations = append(caps.Operations, Capability{
+			Name:      c.name,
+			Commands:  c.commands,
+			Supported: c.check(b),
+		})
+	}
+	return caps
+}
@@ -0,0 +1,38 @@
+package backend
+
+import "testing"
+
+// reorderingBackend adds Reorderer to mockBackend.
+type reorderingBackend struct {
+	mockBackend
+}
+
+func (r *reorderingBackend) Reorder(id string, position ReorderPosition) (*Task, error) {
+	return nil, nil
+}
+
+func TestCapabilitiesOf(t *testing.T) {
+	caps := CapabilitiesOf(&mockBackend{name: "mock"})
+	if caps.Backend != "mock" {
+		t.Errorf("Backend = %q, want %q", caps.Backend, "mock")
+	}
+	if len(caps.Operations) != len(capabilityChecks) {
+		t.Fatalf("got %d operations, want %d", len(caps.Operations), len(capabilityChecks))
+	}
+	for _, op := range caps.Operations {
+		if op.Supported {
+			t.Errorf("%s supported by a backend without optional interfaces", op.Name)
+		}
+	}
+
+	caps = CapabilitiesOf(&reorderingBackend{mockBackend{name: "mock"}})
+	if !caps.Supports("reorder") {
+		t.Error("Supports(reorder) = false, want true")
+	}
+	if caps.Supports("claim") {
+		t.Error("Supports(claim) = true, want false")
+	}
+	if caps.Supports("unknown") {
+		t.Error("Supports(unknown) = true, want false")
+	}
+}
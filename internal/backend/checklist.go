@@ -0,0 +1,164 @@
+package backend
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ChecklistItem is one markdown checklist item ("- [ ] text" or "- [x] text")
+// in a task's description.
+type ChecklistItem struct {
+	// Index is the item's 1-based position among the description's items.
+	Index int `json:"index"`
+
+	// Text is the item's text after the checkbox.
+	Text string `json:"text"`
+
+	// Done indicates the item is checked.
+	Done bool `json:"done"`
+}
+
+// ChecklistProgress counts the checked items of a task's checklist.
+type ChecklistProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// String formats the progress as "done/total", such as "3/7".
+func (p ChecklistProgress) String() string {
+	return fmt.Sprintf("%d/%d", p.Done, p.Total)
+}
+
+// checklistItemPattern matches a checklist item line: an optional indent, a
+// "-", "*", "+" or "1." bullet, and a checkbox. The box character is
+// submatch 1 and the text submatch 2.
+var checklistItemPattern = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[([ xX])\](?:\s+(.*))?$`)
+
+// checklistLines splits description into lines and returns them with its
+// checklist items and the line each item is on. Lines inside fenced code
+// blocks are skipped.
+func checklistLines(description string) (lines []string, items []ChecklistItem, at []int) {
+	lines = strings.Split(description, "\n")
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		m := checklistItemPattern.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if m == nil {
+			continue
+		}
+		items = append(items, ChecklistItem{
+			Index: len(items) + 1,
+			Text:  strings.TrimSpace(m[2]),
+			Done:  m[1] != " ",
+		})
+		at = append(at, i)
+	}
+	return lines, items, at
+}
+
+// ParseChecklist returns the checklist items in a markdown description, in
+// order. Nested items are included; items in fenced code blocks are not.
+func ParseChecklist(description string) []ChecklistItem {
+	_, items, _ := checklistLines(description)
+	return items
+}
+
+// SetChecklist computes task.Checklist and task.ChecklistProgress from its
+// description. Tasks without checklist items get neither.
+func SetChecklist(task *Task) {
+	task.Checklist = ParseChecklist(task.Description)
+	task.ChecklistProgress = nil
+	if len(task.Checklist) == 0 {
+		task.Checklist = nil
+		return
+	}
+	progress := ChecklistProgress{Total: len(task.Checklist)}
+	for _, item := range task.Checklist {
+		if item.Done {
+			progress.Done++
+		}
+	}
+	task.ChecklistProgress = &progress
+}
+
+// SetChecklistItem returns description with the checkbox of the item at the
+// 1-based index checked or unchecked. Only the box character changes, so
+// the rest of the description is kept byte for byte.
+func SetChecklistItem(description string, index int, done bool) (string, error) {
+	lines, items, at := checklistLines(description)
+	if index < 1 || index > len(items) {
+		return "", fmt.Errorf("checklist item %d out of range (1-%d)", index, len(items))
+	}
+
+	line := lines[at[index-1]]
+	open := strings.Index(line, "[")
+	box := byte(' ')
+	if done {
+		box = 'x'
+	}
+	lines[at[index-1]] = line[:open+1] + string(box) + line[open+2:]
+	return strings.Join(lines, "\n"), nil
+}
+
+// AmbiguousChecklistItemError is returned by FindChecklistItem when a
+// substring matches more than one item.
+type AmbiguousChecklistItemError struct {
+	Ref        string
+	Candidates []ChecklistItem
+}
+
+func (e *AmbiguousChecklistItemError) Error() string {
+	return fmt.Sprintf("%q matches %d checklist items", e.Ref, len(e.Candidates))
+}
+
+// FindChecklistItem returns the 1-based index of the item ref refers to: a
+// number is an item index, and anything else a case-insensitive substring
+// of the item's text. An item whose whole text matches wins over items that
+// merely contain ref; more than one match otherwise returns an
+// *AmbiguousChecklistItemError.
+func FindChecklistItem(items []ChecklistItem, ref string) (int, error) {
+	if len(items) == 0 {
+		return 0, fmt.Errorf("no checklist items")
+	}
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n < 1 || n > len(items) {
+			return 0, fmt.Errorf("checklist item %d out of range (1-%d)", n, len(items))
+		}
+		return n, nil
+	}
+
+	needle := strings.ToLower(strings.TrimSpace(ref))
+	if needle == "" {
+		return 0, fmt.Errorf("empty checklist item reference")
+	}
+	var matches, exact []ChecklistItem
+	for _, item := range items {
+		text := strings.ToLower(item.Text)
+		if text == needle {
+			exact = append(exact, item)
+		}
+		if strings.Contains(text, needle) {
+			matches = append(matches, item)
+		}
+	}
+	if len(exact) > 0 {
+		matches = exact
+	}
+	switch len(matches) {
+	case 0:
+		return 0, fmt.Errorf("no checklist item matches %q", ref)
+	case 1:
+		return matches[0].Index, nil
+	default:
+		return 0, &AmbiguousChecklistItemError{Ref: ref, Candidates: matches}
+	}
+}
@@ -0,0 +1,102 @@
+package backend
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const checklistDescription = "Plan:\n\n" +
+	"- [x] Write the parser\n" +
+	"- [ ] Write tests\n" +
+	"  * [X] Nested item\n" +
+	"1. [ ] Run tests in CI\n" +
+	"```\n" +
+	"- [ ] Not an item\n" +
+	"```\n" +
+	"- [] Not an item either\n"
+
+func TestParseChecklist(t *testing.T) {
+	want := []ChecklistItem{
+		{Index: 1, Text: "Write the parser", Done: true},
+		{Index: 2, Text: "Write tests"},
+		{Index: 3, Text: "Nested item", Done: true},
+		{Index: 4, Text: "Run tests in CI"},
+	}
+	if got := ParseChecklist(checklistDescription); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseChecklist() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSetChecklist(t *testing.T) {
+	task := &Task{Description: checklistDescription}
+	SetChecklist(task)
+	if task.ChecklistProgress == nil || task.ChecklistProgress.String() != "2/4" {
+		t.Errorf("ChecklistProgress = %v, want 2/4", task.ChecklistProgress)
+	}
+
+	task.Description = "No checklist"
+	SetChecklist(task)
+	if task.Checklist != nil || task.ChecklistProgress != nil {
+		t.Errorf("SetChecklist() kept %+v and %v for a description without a checklist", task.Checklist, task.ChecklistProgress)
+	}
+}
+
+func TestSetChecklistItem(t *testing.T) {
+	got, err := SetChecklistItem(checklistDescription, 2, true)
+	if err != nil {
+		t.Fatalf("SetChecklistItem() error = %v", err)
+	}
+	want := "Plan:\n\n" +
+		"- [x] Write the parser\n" +
+		"- [x] Write tests\n" +
+		"  * [X] Nested item\n" +
+		"1. [ ] Run tests in CI\n" +
+		"```\n" +
+		"- [ ] Not an item\n" +
+		"```\n" +
+		"- [] Not an item either\n"
+	if got != want {
+		t.Errorf("SetChecklistItem() = %q, want %q", got, want)
+	}
+
+	if got, _ := SetChecklistItem(checklistDescription, 3, false); !reflect.DeepEqual(ParseChecklist(got)[2], ChecklistItem{Index: 3, Text: "Nested item"}) {
+		t.Errorf("unchecking item 3 gave %q", got)
+	}
+	if _, err := SetChecklistItem(checklistDescription, 5, true); err == nil {
+		t.Error("SetChecklistItem() with an out-of-range index should fail")
+	}
+}
+
+func TestFindChecklistItem(t *testing.T) {
+	items := ParseChecklist(checklistDescription)
+	tests := []struct {
+		ref  string
+		want int
+	}{
+		{"3", 3},
+		{"parser", 1},
+		{"WRITE TESTS", 2},
+		{"ci", 4},
+	}
+	for _, tt := range tests {
+		if got, err := FindChecklistItem(items, tt.ref); err != nil || got != tt.want {
+			t.Errorf("FindChecklistItem(%q) = %d, %v; want %d", tt.ref, got, err, tt.want)
+		}
+	}
+
+	for _, ref := range []string{"0", "5", "deploy", " "} {
+		if _, err := FindChecklistItem(items, ref); err == nil {
+			t.Errorf("FindChecklistItem(%q) should fail", ref)
+		}
+	}
+
+	_, err := FindChecklistItem(items, "tests")
+	var ambiguous *AmbiguousChecklistItemError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("FindChecklistItem(tests) error = %v, want an ambiguous match", err)
+	}
+	if len(ambiguous.Candidates) != 2 || ambiguous.Candidates[0].Index != 2 || ambiguous.Candidates[1].Index != 4 {
+		t.Errorf("candidates = %+v, want items 2 and 4", ambiguous.Candidates)
+	}
+}
@@ -0,0 +1,22 @@
+package backend
+
+import "sort"
+
+// FilterComments returns the comments that match filters, oldest first, for
+// backends that can only fetch a whole comment thread.
+func FilterComments(comments []Comment, filters CommentFilters) []Comment {
+	filtered := make([]Comment, 0, len(comments))
+	for _, comment := range comments {
+		if !filters.Since.IsZero() && !comment.Created.After(filters.Since) {
+			continue
+		}
+		filtered = append(filtered, comment)
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].Created.Before(filtered[j].Created)
+	})
+	if filters.Limit > 0 && len(filtered) > filters.Limit {
+		filtered = filtered[len(filtered)-filters.Limit:]
+	}
+	return filtered
+}
@@ -0,0 +1,41 @@
+package backend
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFilterComments(t *testing.T) {
+	base := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	comments := []Comment{
+		{ID: "c1", Created: base},
+		{ID: "c3", Created: base.Add(2 * time.Hour)},
+		{ID: "c2", Created: base.Add(time.Hour)},
+		{ID: "c4", Created: base.Add(3 * time.Hour)},
+	}
+
+	tests := []struct {
+		name    string
+		filters CommentFilters
+		want    []string
+	}{
+		{"no filters sorts oldest first", CommentFilters{}, []string{"c1", "c2", "c3", "c4"}},
+		{"since is exclusive", CommentFilters{Since: base.Add(time.Hour)}, []string{"c3", "c4"}},
+		{"limit keeps the most recent", CommentFilters{Limit: 2}, []string{"c3", "c4"}},
+		{"since and limit", CommentFilters{Since: base, Limit: 1}, []string{"c4"}},
+		{"limit above count", CommentFilters{Limit: 10}, []string{"c1", "c2", "c3", "c4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FilterComments(comments, tt.filters)
+			var ids []string
+			for _, c := range got {
+				ids = append(ids, c.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("FilterComments() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
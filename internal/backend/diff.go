@@ -0,0 +1,104 @@
+package backend
+
+import (
+	"sort"
+	"strings"
+)
+
+// FieldChange is a change to one field of a task, as reported by edit and
+// move. Before and After hold the field's values: strings for text fields,
+// Status, Priority and Duration values, sorted label slices for labels, and
+// "done/total" strings for the checklist.
+type FieldChange struct {
+	// Field is the task field's JSON name, such as "priority".
+	Field string
+
+	// Before is the value before the change.
+	Before any
+
+	// After is the value after the change.
+	After any
+
+	// Added and Removed are the labels added and removed. They are only set
+	// for the labels field.
+	Added   []string
+	Removed []string
+}
+
+// DiffTasks returns the changes between two versions of a task, in a fixed
+// field order. Fields a backend sets itself, like timestamps and URLs, are
+// not compared, and labels are compared as a set.
+func DiffTasks(before, after *Task) []FieldChange {
+	var changes []FieldChange
+	if before.Title != after.Title {
+		changes = append(changes, FieldChange{Field: "title", Before: before.Title, After: after.Title})
+	}
+	if strings.TrimSpace(before.Description) != strings.TrimSpace(after.Description) {
+		changes = append(changes, FieldChange{Field: "description", Before: before.Description, After: after.Description})
+		if b, a := checklistText(before.Description), checklistText(after.Description); b != a {
+			changes = append(changes, FieldChange{Field: "checklist", Before: b, After: a})
+		}
+	}
+	if before.Status != after.Status {
+		changes = append(changes, FieldChange{Field: "status", Before: before.Status, After: after.Status})
+	}
+	if before.Priority != after.Priority {
+		changes = append(changes, FieldChange{Field: "priority", Before: before.Priority, After: after.Priority})
+	}
+	if before.Assignee != after.Assignee {
+		changes = append(changes, FieldChange{Field: "assignee", Before: before.Assignee, After: after.Assignee})
+	}
+
+	added := labelsNotIn(after.Labels, before.Labels)
+	removed := labelsNotIn(before.Labels, after.Labels)
+	if len(added) > 0 || len(removed) > 0 {
+		changes = append(changes, FieldChange{
+			Field:   "labels",
+			Before:  sortedLabels(before.Labels),
+			After:   sortedLabels(after.Labels),
+			Added:   added,
+			Removed: removed,
+		})
+	}
+
+	if before.Parent != after.Parent {
+		changes = append(changes, FieldChange{Field: "parent", Before: before.Parent, After: after.Parent})
+	}
+	if before.Estimate != after.Estimate {
+		changes = append(changes, FieldChange{Field: "estimate", Before: before.Estimate, After: after.Estimate})
+	}
+	if before.Spent != after.Spent {
+		changes = append(changes, FieldChange{Field: "spent", Before: before.Spent, After: after.Spent})
+	}
+	return changes
+}
+
+// checklistText returns the checklist progress of a description as
+// "done/total", or "" if it has no checklist.
+func checklistText(description string) string {
+	task := Task{Description: description}
+	SetChecklist(&task)
+	if task.ChecklistProgress == nil {
+		return ""
+	}
+	return task.ChecklistProgress.String()
+}
+
+// labelsNotIn returns the labels in labels that are not in other, sorted.
+func labelsNotIn(labels, other []string) []string {
+	var missing []string
+	for _, label := range labels {
+		if !hasLabel(other, label) {
+			missing = append(missing, label)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// sortedLabels returns a sorted copy of labels, never nil.
+func sortedLabels(labels []string) []string {
+	sorted := append([]string{}, labels...)
+	sort.Strings(sorted)
+	return sorted
+}
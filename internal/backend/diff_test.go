@@ -0,0 +1,51 @@
+package backend
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffTasks(t *testing.T) {
+	before := &Task{
+		Title:    "Fix login",
+		Status:   StatusTodo,
+		Priority: PriorityMedium,
+		Labels:   []string{"bug", "auth"},
+	}
+	after := &Task{
+		Title:    "Fix login",
+		Status:   StatusInProgress,
+		Priority: PriorityHigh,
+		Assignee: "agent-7",
+		Labels:   []string{"infra", "auth"},
+		Estimate: Duration(2 * Day),
+	}
+
+	changes := DiffTasks(before, after)
+	var fields []string
+	for _, change := range changes {
+		fields = append(fields, change.Field)
+	}
+	if want := []string{"status", "priority", "assignee", "labels", "estimate"}; !reflect.DeepEqual(fields, want) {
+		t.Fatalf("DiffTasks() fields = %v, want %v", fields, want)
+	}
+
+	labels := changes[3]
+	if !reflect.DeepEqual(labels.Added, []string{"infra"}) || !reflect.DeepEqual(labels.Removed, []string{"bug"}) {
+		t.Errorf("labels added %v removed %v, want [infra] and [bug]", labels.Added, labels.Removed)
+	}
+	if !reflect.DeepEqual(labels.Before, []string{"auth", "bug"}) || !reflect.DeepEqual(labels.After, []string{"auth", "infra"}) {
+		t.Errorf("labels before %v after %v, want sorted labels", labels.Before, labels.After)
+	}
+	if changes[2].Before != "" || changes[2].After != "agent-7" {
+		t.Errorf("assignee change = %+v", changes[2])
+	}
+}
+
+func TestDiffTasksNoChanges(t *testing.T) {
+	before := &Task{Title: "Fix login", Description: "Steps", Labels: []string{"bug", "auth"}}
+	after := &Task{Title: "Fix login", Description: "Steps\n", Labels: []string{"auth", "bug"}, URL: "https://example.com/1"}
+	if changes := DiffTasks(before, after); len(changes) != 0 {
+		t.Errorf("DiffTasks() = %+v, want no changes", changes)
+	}
+}
@@ -0,0 +1,172 @@
+package backend
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Day is the length of the "d" duration shorthand.
+	Day = 24 * time.Hour
+
+	// Week is the length of the "w" duration shorthand.
+	Week = 7 * Day
+)
+
+// Duration is a length of time used for task estimates and time tracking.
+// It serializes as a human-readable string (e.g., "1d4h") in JSON and YAML.
+type Duration time.Duration
+
+// ParseDuration parses a duration string. In addition to the formats accepted
+// by time.ParseDuration, it accepts "d" (day) and "w" (week) units, which may
+// be combined with other units (e.g., "1w2d", "1d4h30m").
+func ParseDuration(s string) (Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return Duration(d), nil
+	}
+
+	// Consume leading week/day components, then hand the rest to time.ParseDuration
+	var total time.Duration
+	rest := s
+	for rest != "" {
+		i := 0
+		for i < len(rest) && (rest[i] >= '0' && rest[i] <= '9' || rest[i] == '.') {
+			i++
+		}
+		if i == 0 || i == len(rest) {
+			break
+		}
+		var unit time.Duration
+		switch rest[i] {
+		case 'd':
+			unit = Day
+		case 'w':
+			unit = Week
+		}
+		if unit == 0 {
+			break
+		}
+		n, err := strconv.ParseFloat(rest[:i], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		total += time.Duration(n * float64(unit))
+		rest = rest[i+1:]
+	}
+
+	if rest == s {
+		return 0, fmt.Errorf("invalid duration %q (examples: 30m, 2h, 1d, 1w)", s)
+	}
+	if rest != "" {
+		d, err := time.ParseDuration(rest)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q (examples: 30m, 2h, 1d, 1w)", s)
+		}
+		total += d
+	}
+
+	return Duration(total), nil
+}
+
+// ParseSince parses a list cutoff given either as an RFC3339 timestamp or
+// as a duration before now, such as "2h", "3d", or "1w".
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	d, err := ParseDuration(s)
+	if err != nil || d <= 0 {
+		return time.Time{}, fmt.Errorf("invalid time %q: use an RFC3339 timestamp (2025-01-15T09:00:00Z) or a duration ago (30m, 2h, 3d, 1w)", s)
+	}
+	return now.Add(-time.Duration(d)).UTC(), nil
+}
+
+// SetDuration computes task.Duration from its start and completion times.
+// Tasks that are not done are measured up to now; tasks never started get
+// no duration.
+func SetDuration(task *Task, now time.Time) {
+	task.Duration = 0
+	if task.StartedAt == nil {
+		return
+	}
+	end := now
+	if task.CompletedAt != nil {
+		end = *task.CompletedAt
+	}
+	if d := end.Sub(*task.StartedAt); d > 0 {
+		task.Duration = Duration(d)
+	}
+}
+
+// String formats the duration using day, hour, minute and second units,
+// omitting zero components (e.g., "1d4h", "45m"). A zero duration is "0s".
+func (d Duration) String() string {
+	if d == 0 {
+		return "0s"
+	}
+
+	remaining := time.Duration(d)
+	var b strings.Builder
+	if remaining < 0 {
+		b.WriteString("-")
+		remaining = -remaining
+	}
+
+	units := []struct {
+		size   time.Duration
+		suffix string
+	}{
+		{Day, "d"},
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+		{time.Second, "s"},
+	}
+	for _, u := range units {
+		if n := remaining / u.size; n > 0 {
+			fmt.Fprintf(&b, "%d%s", n, u.suffix)
+			remaining -= n * u.size
+		}
+	}
+
+	// Sub-second durations are not meaningful for time tracking
+	if b.Len() == 0 || b.String() == "-" {
+		return "0s"
+	}
+	return b.String()
+}
+
+// MarshalText implements encoding.TextMarshaler so durations are written as strings.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler so durations are written as strings.
+func (d Duration) MarshalYAML() (any, error) {
+	return d.String(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (d *Duration) UnmarshalYAML(unmarshal func(any) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	return d.UnmarshalText([]byte(s))
+}
@@ -0,0 +1,150 @@
+package backend
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+	}{
+		{"30m", 30 * time.Minute},
+		{"2h", 2 * time.Hour},
+		{"1h30m", 90 * time.Minute},
+		{"1d", 24 * time.Hour},
+		{"1w", 7 * 24 * time.Hour},
+		{"1w2d", 9 * 24 * time.Hour},
+		{"1d4h30m", 28*time.Hour + 30*time.Minute},
+		{"0.5d", 12 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDuration(tt.input)
+			if err != nil {
+				t.Fatalf("ParseDuration(%q) returned error: %v", tt.input, err)
+			}
+			if time.Duration(got) != tt.want {
+				t.Errorf("ParseDuration(%q) = %v, want %v", tt.input, time.Duration(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDurationInvalid(t *testing.T) {
+	for _, input := range []string{"", "abc", "5", "1x", "1d2x"} {
+		if _, err := ParseDuration(input); err == nil {
+			t.Errorf("ParseDuration(%q) expected error, got nil", input)
+		}
+	}
+}
+
+func TestDurationString(t *testing.T) {
+	tests := []struct {
+		input time.Duration
+		want  string
+	}{
+		{0, "0s"},
+		{45 * time.Minute, "45m"},
+		{2 * time.Hour, "2h"},
+		{26 * time.Hour, "1d2h"},
+		{9*24*time.Hour + 30*time.Minute, "9d30m"},
+	}
+
+	for _, tt := range tests {
+		if got := Duration(tt.input).String(); got != tt.want {
+			t.Errorf("Duration(%v).String() = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestDurationJSONRoundTrip(t *testing.T) {
+	task := Task{ID: "001", Estimate: Duration(26 * time.Hour), Spent: Duration(90 * time.Minute)}
+
+	data, err := json.Marshal(task)
+	if err != nil {
+		t.Fatalf("json.Marshal() returned error: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() returned error: %v", err)
+	}
+	if decoded["estimate"] != "1d2h" {
+		t.Errorf("estimate = %v, want %q", decoded["estimate"], "1d2h")
+	}
+	if decoded["spent"] != "1h30m" {
+		t.Errorf("spent = %v, want %q", decoded["spent"], "1h30m")
+	}
+
+	var roundTrip Task
+	if err := json.Unmarshal(data, &roundTrip); err != nil {
+		t.Fatalf("json.Unmarshal() returned error: %v", err)
+	}
+	if roundTrip.Estimate != task.Estimate || roundTrip.Spent != task.Spent {
+		t.Errorf("round trip = (%v, %v), want (%v, %v)", roundTrip.Estimate, roundTrip.Spent, task.Estimate, task.Spent)
+	}
+}
+
+func TestSetDuration(t *testing.T) {
+	start := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+	done := start.Add(26 * time.Hour)
+	now := start.Add(3 * time.Hour)
+
+	tests := []struct {
+		name string
+		task Task
+		want Duration
+	}{
+		{"not started", Task{}, 0},
+		{"in progress", Task{StartedAt: &start}, Duration(3 * time.Hour)},
+		{"completed", Task{StartedAt: &start, CompletedAt: &done}, Duration(26 * time.Hour)},
+		{"completed before start", Task{StartedAt: &done, CompletedAt: &start}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := tt.task
+			task.Duration = Duration(time.Minute)
+			SetDuration(&task, now)
+			if task.Duration != tt.want {
+				t.Errorf("Duration = %v, want %v", task.Duration, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"2h", now.Add(-2 * time.Hour)},
+		{"3d", now.Add(-3 * Day)},
+		{"1w", now.Add(-Week)},
+		{"2025-01-10T08:00:00Z", time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC)},
+		{"2025-01-10T10:00:00+02:00", time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSince(tt.input, now)
+			if err != nil {
+				t.Fatalf("ParseSince(%q) returned error: %v", tt.input, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseSince(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	for _, input := range []string{"", "yesterday", "0h", "2025-01-10"} {
+		if _, err := ParseSince(input, now); err == nil {
+			t.Errorf("ParseSince(%q) expected error, got nil", input)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package backend
+
+import "strings"
+
+// SourceLabelPrefix starts the label recording where a task came from, such
+// as "source:plan-42" from "backlog add --source-key plan-42".
+const SourceLabelPrefix = "source:"
+
+// FindExisting returns the task an IfAbsent create of input would return
+// instead of creating one, or nil. A task matches if it isn't done and has
+// the input's source label, or, when the input has no source label, the
+// same title.
+func FindExisting(tasks []Task, input TaskInput) *Task {
+	source := sourceLabel(input.Labels)
+	for i := range tasks {
+		task := &tasks[i]
+		if task.Status == StatusDone {
+			continue
+		}
+		if source != "" {
+			if hasLabel(task.Labels, source) {
+				return task
+			}
+		} else if task.Title == input.Title {
+			return task
+		}
+	}
+	return nil
+}
+
+// LookupExisting does the search that comes before an IfAbsent create: it
+// lists the open tasks with list and returns the one FindExisting picks,
+// marked with MarkExisting. It returns nil if input isn't IfAbsent or no
+// task matches.
+//
+// The search and the create aren't atomic, so unless the caller holds a
+// lock across both, two identical adds racing can still both create a task.
+func LookupExisting(input TaskInput, list func(TaskFilters) (*TaskList, error)) (*Task, error) {
+	if !input.IfAbsent {
+		return nil, nil
+	}
+	open, err := list(TaskFilters{})
+	if err != nil {
+		return nil, err
+	}
+	if task := FindExisting(open.Tasks, input); task != nil {
+		return MarkExisting(task), nil
+	}
+	return nil, nil
+}
+
+// MarkExisting flags a task returned by an IfAbsent create in its Meta.
+func MarkExisting(task *Task) *Task {
+	if task.Meta == nil {
+		task.Meta = make(map[string]any)
+	}
+	task.Meta[MetaAlreadyExists] = true
+	return task
+}
+
+// hasLabel reports whether labels include label.
+func hasLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// sourceLabel returns the first source label, or "".
+func sourceLabel(labels []string) string {
+	for _, label := range labels {
+		if strings.HasPrefix(label, SourceLabelPrefix) {
+			return label
+		}
+	}
+	return ""
+}
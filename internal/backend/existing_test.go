@@ -0,0 +1,75 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestFindExisting(t *testing.T) {
+	tasks := []Task{
+		{ID: "001", Title: "Write docs", Status: StatusDone},
+		{ID: "002", Title: "Write docs", Status: StatusTodo},
+		{ID: "003", Title: "Split parser", Status: StatusBacklog, Labels: []string{"backend", "source:plan-42"}},
+	}
+
+	tests := []struct {
+		name  string
+		input TaskInput
+		want  string
+	}{
+		{"title match skips done tasks", TaskInput{Title: "Write docs"}, "002"},
+		{"title must match exactly", TaskInput{Title: "write docs"}, ""},
+		{"source label match", TaskInput{Title: "Another title", Labels: []string{"source:plan-42"}}, "003"},
+		{"source label ignores title", TaskInput{Title: "Write docs", Labels: []string{"source:plan-7"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FindExisting(tasks, tt.input)
+			switch {
+			case got == nil && tt.want != "":
+				t.Errorf("FindExisting() = nil, want %s", tt.want)
+			case got != nil && got.ID != tt.want:
+				t.Errorf("FindExisting() = %s, want %q", got.ID, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupExisting(t *testing.T) {
+	calls := 0
+	list := func(TaskFilters) (*TaskList, error) {
+		calls++
+		return &TaskList{Tasks: []Task{{ID: "001", Title: "Write docs", Status: StatusTodo}}}, nil
+	}
+
+	if task, err := LookupExisting(TaskInput{Title: "Write docs"}, list); task != nil || err != nil || calls != 0 {
+		t.Errorf("without IfAbsent: got %v, %v after %d lists, want nothing listed", task, err, calls)
+	}
+	task, err := LookupExisting(TaskInput{Title: "Write docs", IfAbsent: true}, list)
+	if err != nil || task == nil || task.ID != "001" || task.Meta[MetaAlreadyExists] != true {
+		t.Errorf("match: got %+v, %v, want 001 marked as existing", task, err)
+	}
+	if task, err := LookupExisting(TaskInput{Title: "Other", IfAbsent: true}, list); task != nil || err != nil {
+		t.Errorf("no match: got %v, %v, want nil", task, err)
+	}
+
+	listErr := errors.New("offline")
+	failing := func(TaskFilters) (*TaskList, error) { return nil, listErr }
+	if _, err := LookupExisting(TaskInput{Title: "Write docs", IfAbsent: true}, failing); !errors.Is(err, listErr) {
+		t.Errorf("list error: got %v, want %v", err, listErr)
+	}
+}
+
+func TestIsAlreadyExists(t *testing.T) {
+	err := fmt.Errorf("create: %w", &AlreadyExistsError{ID: "007", Where: "in the trash"})
+	if !IsAlreadyExists(err) {
+		t.Errorf("IsAlreadyExists(%v) = false", err)
+	}
+	if got, want := err.Error(), "create: task 007 already exists in the trash"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if IsAlreadyExists(&NotFoundError{ID: "007"}) {
+		t.Error("IsAlreadyExists(NotFoundError) = true")
+	}
+}
@@ -0,0 +1,181 @@
+package backend
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SchemaDialect is the JSON Schema version of the documents Schema returns.
+const SchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// schemaRoots are the types Schema can describe, by the name used to pick
+// one.
+var schemaRoots = map[string]reflect.Type{
+	"task":       reflect.TypeOf(Task{}),
+	"task-input": reflect.TypeOf(TaskInput{}),
+	"task-list":  reflect.TypeOf(TaskList{}),
+}
+
+// SchemaNames returns the names Schema accepts, sorted.
+func SchemaNames() []string {
+	names := make([]string, 0, len(schemaRoots))
+	for name := range schemaRoots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(Duration(0))
+	statusType   = reflect.TypeOf(Status(""))
+	priorityType = reflect.TypeOf(Priority(""))
+)
+
+// Schema returns a JSON Schema describing Task, TaskInput and TaskList as
+// they are written with --format json. The schema is generated from the
+// structs' json tags, so it follows them as fields are added. With a name
+// from SchemaNames the document validates that type; with "" it validates
+// any of them.
+func Schema(name string) (map[string]any, error) {
+	g := &schemaGenerator{defs: make(map[string]any)}
+	for _, t := range schemaRoots {
+		g.ref(t)
+	}
+	g.addTaskExtras()
+
+	doc := map[string]any{
+		"$schema": SchemaDialect,
+		"title":   "backlog",
+		"$defs":   g.defs,
+	}
+	if name == "" {
+		var refs []any
+		for _, n := range SchemaNames() {
+			refs = append(refs, g.ref(schemaRoots[n]))
+		}
+		doc["anyOf"] = refs
+		return doc, nil
+	}
+	t, ok := schemaRoots[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema %q (valid: %s)", name, strings.Join(SchemaNames(), ", "))
+	}
+	doc["title"] = "backlog " + name
+	doc["$ref"] = g.ref(t)["$ref"]
+	return doc, nil
+}
+
+// schemaGenerator builds schemas from Go types, collecting the schema of
+// each struct type under its name in defs.
+type schemaGenerator struct {
+	defs map[string]any
+}
+
+// ref returns a reference to the schema of struct type t, generating it
+// the first time.
+func (g *schemaGenerator) ref(t reflect.Type) map[string]any {
+	ref := map[string]any{"$ref": "#/$defs/" + t.Name()}
+	if _, ok := g.defs[t.Name()]; ok {
+		return ref
+	}
+	// Mark the type as seen before generating it, for recursive types
+	g.defs[t.Name()] = nil
+
+	properties := make(map[string]any)
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		omitEmpty := strings.Contains(","+opts+",", ",omitempty,")
+		schema := g.typeSchema(field.Type)
+		// encoding/json writes a nil slice or map it must include as null
+		if !omitEmpty && (field.Type.Kind() == reflect.Slice || field.Type.Kind() == reflect.Map) {
+			schema["type"] = []any{schema["type"], "null"}
+		}
+		properties[name] = schema
+		if !omitEmpty {
+			required = append(required, name)
+		}
+	}
+	g.defs[t.Name()] = map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+	return ref
+}
+
+// typeSchema returns the schema of a field of type t.
+func (g *schemaGenerator) typeSchema(t reflect.Type) map[string]any {
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case durationType:
+		return map[string]any{"type": "string", "description": "A duration such as 30m, 4h or 1d4h"}
+	case statusType:
+		return map[string]any{"type": "string", "enum": ValidStatuses()}
+	case priorityType:
+		return map[string]any{"type": "string", "enum": ValidPriorities()}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.typeSchema(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object"}
+	case reflect.Struct:
+		return g.ref(t)
+	}
+	// Interfaces hold anything
+	return map[string]any{}
+}
+
+// addTaskExtras adds the fields show writes alongside a task's own: its
+// dependencies, when it has any, and its comments with --comments.
+func (g *schemaGenerator) addTaskExtras() {
+	related := map[string]any{
+		"type": "array",
+		"items": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"id":     map[string]any{"type": "string"},
+				"title":  map[string]any{"type": "string"},
+				"status": g.typeSchema(statusType),
+			},
+			"required": []string{"id", "title", "status"},
+		},
+	}
+	properties := g.defs["Task"].(map[string]any)["properties"].(map[string]any)
+	// show --comments writes labels and meta even when they are unset
+	properties["labels"].(map[string]any)["type"] = []any{"array", "null"}
+	properties["meta"].(map[string]any)["type"] = []any{"object", "null"}
+	properties["blocks"] = related
+	properties["blocked_by"] = related
+	properties["children"] = related
+	properties["comments"] = map[string]any{"type": "array", "items": g.ref(reflect.TypeOf(Comment{}))}
+}
@@ -0,0 +1,101 @@
+package backend
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestSchemaCoversTaskJSON(t *testing.T) {
+	now := time.Now()
+	task := Task{
+		ID: "001", Title: "Fix login", Description: "- [ ] repro", Status: StatusTodo, Priority: PriorityHigh,
+		Assignee: "alex", Labels: []string{"bug"}, Parent: "000", Created: now, Updated: now,
+		URL: "https://example.com/1", SortOrder: 1, Estimate: Duration(time.Hour), Spent: Duration(time.Hour),
+		DeletedAt: &now, StartedAt: &now, CompletedAt: &now, Duration: Duration(time.Hour), TimeApproximate: true,
+		Checklist: []ChecklistItem{{Index: 1, Text: "repro"}}, ChecklistProgress: &ChecklistProgress{Total: 1},
+		Meta: map[string]any{"key": "value"},
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys map[string]any
+	if err := json.Unmarshal(data, &keys); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := Schema("task")
+	if err != nil {
+		t.Fatalf("Schema() error = %v", err)
+	}
+	if doc["$ref"] != "#/$defs/Task" {
+		t.Errorf("$ref = %v, want #/$defs/Task", doc["$ref"])
+	}
+	def := doc["$defs"].(map[string]any)["Task"].(map[string]any)
+	properties := def["properties"].(map[string]any)
+	for key := range keys {
+		if _, ok := properties[key]; !ok {
+			t.Errorf("schema has no property for JSON key %q", key)
+		}
+	}
+
+	required := def["required"].([]string)
+	sort.Strings(required)
+	want := []string{"assignee", "created", "id", "priority", "status", "title", "updated"}
+	if !reflect.DeepEqual(required, want) {
+		t.Errorf("required = %v, want %v", required, want)
+	}
+
+	status := properties["status"].(map[string]any)
+	if !reflect.DeepEqual(status["enum"], ValidStatuses()) {
+		t.Errorf("status enum = %v, want %v", status["enum"], ValidStatuses())
+	}
+	priority := properties["priority"].(map[string]any)
+	if !reflect.DeepEqual(priority["enum"], ValidPriorities()) {
+		t.Errorf("priority enum = %v, want %v", priority["enum"], ValidPriorities())
+	}
+	if properties["created"].(map[string]any)["format"] != "date-time" {
+		t.Errorf("created = %v, want a date-time string", properties["created"])
+	}
+	if properties["estimate"].(map[string]any)["type"] != "string" {
+		t.Errorf("estimate = %v, want a string", properties["estimate"])
+	}
+}
+
+func TestSchemaDefinitions(t *testing.T) {
+	doc, err := Schema("")
+	if err != nil {
+		t.Fatalf("Schema() error = %v", err)
+	}
+	if len(doc["anyOf"].([]any)) != len(SchemaNames()) {
+		t.Errorf("anyOf = %v, want one entry per schema name", doc["anyOf"])
+	}
+	if _, err := json.Marshal(doc); err != nil {
+		t.Errorf("schema doesn't marshal: %v", err)
+	}
+
+	defs := doc["$defs"].(map[string]any)
+	for _, name := range []string{"Task", "TaskInput", "TaskList", "Comment", "ChecklistItem", "ChecklistProgress", "AppliedFilters"} {
+		if defs[name] == nil {
+			t.Errorf("$defs has no %s", name)
+		}
+	}
+
+	input := defs["TaskInput"].(map[string]any)
+	if !reflect.DeepEqual(input["required"], []string{"title"}) {
+		t.Errorf("TaskInput required = %v, want [title]", input["required"])
+	}
+	list := defs["TaskList"].(map[string]any)["properties"].(map[string]any)
+	if !reflect.DeepEqual(list["tasks"].(map[string]any)["items"], map[string]any{"$ref": "#/$defs/Task"}) {
+		t.Errorf("TaskList tasks = %v, want an array of Task", list["tasks"])
+	}
+}
+
+func TestSchemaUnknownName(t *testing.T) {
+	if _, err := Schema("comment"); err == nil {
+		t.Error("Schema(\"comment\") succeeded, want an error")
+	}
+}
@@ -0,0 +1,130 @@
+package backend
+
+import (
+	"cmp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SortFields lists the fields tasks can be sorted by.
+var SortFields = []string{"priority", "created", "updated", "id", "title", "status", "duration"}
+
+// SortKey is one key of a task sort order.
+type SortKey struct {
+	// Field is one of SortFields.
+	Field string
+
+	// Desc sorts from the largest value down: urgent priority, the newest
+	// time, the longest duration, or the status furthest along.
+	Desc bool
+}
+
+// SortTasks stably reorders tasks by keys, comparing by each key in turn
+// until one differs. Priorities rank none < low < medium < high < urgent,
+// statuses follow the workflow, IDs compare their numeric parts as numbers,
+// and titles ignore case. Ties keep their list order.
+func SortTasks(tasks []Task, keys []SortKey) {
+	if len(keys) == 0 {
+		return
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		for _, key := range keys {
+			c := compareTasks(&tasks[i], &tasks[j], key.Field)
+			if c == 0 {
+				continue
+			}
+			if key.Desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+}
+
+// compareTasks returns -1, 0, or 1 as a sorts before, with, or after b by
+// field in ascending order.
+func compareTasks(a, b *Task, field string) int {
+	switch field {
+	case "priority":
+		return cmp.Compare(priorityRank(a.Priority), priorityRank(b.Priority))
+	case "created":
+		return a.Created.Compare(b.Created)
+	case "updated":
+		return a.Updated.Compare(b.Updated)
+	case "id":
+		return compareIDs(a.ID, b.ID)
+	case "title":
+		return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	case "status":
+		return cmp.Compare(statusRank(a.Status), statusRank(b.Status))
+	case "duration":
+		return cmp.Compare(int64(a.Duration), int64(b.Duration))
+	}
+	return 0
+}
+
+// priorityRank orders priorities from least to most urgent. Unknown
+// priorities sort below none.
+func priorityRank(p Priority) int {
+	switch p {
+	case PriorityNone:
+		return 1
+	case PriorityLow:
+		return 2
+	case PriorityMedium:
+		return 3
+	case PriorityHigh:
+		return 4
+	case PriorityUrgent:
+		return 5
+	}
+	return 0
+}
+
+// statusRank orders statuses along the workflow, with unknown statuses last.
+func statusRank(s Status) int {
+	for i, status := range ValidStatuses() {
+		if s == status {
+			return i
+		}
+	}
+	return len(ValidStatuses())
+}
+
+// compareIDs compares IDs piece by piece, numbers by value and the text
+// between them as strings, so that "task2" sorts before "task10" and
+// "ENG-9" before "ENG-10".
+func compareIDs(a, b string) int {
+	for a != "" && b != "" {
+		pa, restA := splitIDPart(a)
+		pb, restB := splitIDPart(b)
+		if isDigits(pa) && isDigits(pb) {
+			na, _ := strconv.ParseUint(pa, 10, 64)
+			nb, _ := strconv.ParseUint(pb, 10, 64)
+			if c := cmp.Compare(na, nb); c != 0 {
+				return c
+			}
+		} else if c := strings.Compare(pa, pb); c != 0 {
+			return c
+		}
+		a, b = restA, restB
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+// splitIDPart splits off the leading run of digits or of non-digits.
+func splitIDPart(s string) (string, string) {
+	digits := unicode.IsDigit(rune(s[0]))
+	i := 1
+	for i < len(s) && unicode.IsDigit(rune(s[i])) == digits {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func isDigits(s string) bool {
+	return s != "" && unicode.IsDigit(rune(s[0]))
+}
@@ -0,0 +1,62 @@
+package backend
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSortTasks(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	tasks := func() []Task {
+		return []Task{
+			{ID: "task10", Title: "beta", Status: StatusDone, Priority: PriorityHigh, Created: day(3), Duration: Duration(time.Hour)},
+			{ID: "task2", Title: "Alpha", Status: StatusTodo, Priority: PriorityLow, Created: day(1)},
+			{ID: "task3", Title: "gamma", Status: StatusInProgress, Priority: PriorityHigh, Created: day(2), Duration: Duration(3 * time.Hour)},
+			{ID: "task1", Title: "delta", Status: StatusBacklog, Priority: PriorityUrgent, Created: day(4)},
+		}
+	}
+
+	tests := []struct {
+		keys []SortKey
+		want []string
+	}{
+		{[]SortKey{{Field: "priority", Desc: true}, {Field: "created"}}, []string{"task1", "task3", "task10", "task2"}},
+		{[]SortKey{{Field: "priority"}, {Field: "created", Desc: true}}, []string{"task2", "task10", "task3", "task1"}},
+		{[]SortKey{{Field: "id"}}, []string{"task1", "task2", "task3", "task10"}},
+		{[]SortKey{{Field: "title"}}, []string{"task2", "task10", "task1", "task3"}},
+		{[]SortKey{{Field: "status"}}, []string{"task1", "task2", "task3", "task10"}},
+		{[]SortKey{{Field: "duration", Desc: true}}, []string{"task3", "task10", "task2", "task1"}},
+		{nil, []string{"task10", "task2", "task3", "task1"}},
+	}
+	for _, tt := range tests {
+		list := tasks()
+		SortTasks(list, tt.keys)
+		var ids []string
+		for _, task := range list {
+			ids = append(ids, task.ID)
+		}
+		if !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("SortTasks(%v) = %v, want %v", tt.keys, ids, tt.want)
+		}
+	}
+}
+
+func TestCompareIDs(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"task2", "task10", -1},
+		{"ENG-10", "ENG-9", 1},
+		{"001", "002", -1},
+		{"GH-1", "ENG-1", 1},
+		{"task1", "task1", 0},
+		{"task", "task1", -1},
+	}
+	for _, tt := range tests {
+		if got := compareIDs(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareIDs(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
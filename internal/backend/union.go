@@ -0,0 +1,38 @@
+package backend
+
+// ListAny lists the tasks matching any of filterSets. Backends AND every
+// filter they are given, so an OR is expressed as one filter set per
+// alternative, each listed with list and the results merged. A task matched
+// by several sets appears once, in the position the first set found it,
+// and the warnings of every listing are kept. Skipped is the highest count
+// of any one listing, since the listings overlap. Limits are ignored, since a
+// union can only be trimmed once it is complete and sorted.
+func ListAny(list func(TaskFilters) (*TaskList, error), filterSets ...TaskFilters) (*TaskList, error) {
+	union := &TaskList{Tasks: []Task{}}
+	seenTasks := make(map[string]bool)
+	seenWarnings := make(map[string]bool)
+	for _, filters := range filterSets {
+		filters.Limit = 0
+		taskList, err := list(filters)
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range taskList.Tasks {
+			if seenTasks[task.ID] {
+				continue
+			}
+			seenTasks[task.ID] = true
+			union.Tasks = append(union.Tasks, task)
+		}
+		for _, warning := range taskList.Warnings {
+			if seenWarnings[warning] {
+				continue
+			}
+			seenWarnings[warning] = true
+			union.Warnings = append(union.Warnings, warning)
+		}
+		union.Skipped = max(union.Skipped, taskList.Skipped)
+	}
+	union.Count = len(union.Tasks)
+	return union, nil
+}
@@ -0,0 +1,60 @@
+package backend
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestListAny(t *testing.T) {
+	tasks := []Task{
+		{ID: "001", Assignee: "me"},
+		{ID: "002", Labels: []string{"agent:me"}},
+		{ID: "003", Assignee: "me", Labels: []string{"agent:me"}},
+		{ID: "004", Assignee: "someone-else"},
+	}
+	var limits []int
+	list := func(filters TaskFilters) (*TaskList, error) {
+		limits = append(limits, filters.Limit)
+		result := &TaskList{Warnings: []string{"skipped bad.md"}, Skipped: 1}
+		for _, task := range tasks {
+			if filters.Assignee != "" && task.Assignee != filters.Assignee {
+				continue
+			}
+			if len(filters.Labels) > 0 && !slices.Contains(task.Labels, filters.Labels[0]) {
+				continue
+			}
+			result.Tasks = append(result.Tasks, task)
+		}
+		return result, nil
+	}
+
+	got, err := ListAny(list,
+		TaskFilters{Assignee: "me", Limit: 1},
+		TaskFilters{Labels: []string{"agent:me"}, Limit: 1})
+	if err != nil {
+		t.Fatalf("ListAny() error = %v", err)
+	}
+	var ids []string
+	for _, task := range got.Tasks {
+		ids = append(ids, task.ID)
+	}
+	if !slices.Equal(ids, []string{"001", "003", "002"}) {
+		t.Errorf("ListAny() ids = %v, want 001 003 002", ids)
+	}
+	if got.Count != 3 {
+		t.Errorf("Count = %d, want 3", got.Count)
+	}
+	if len(got.Warnings) != 1 || got.Skipped != 1 {
+		t.Errorf("Warnings = %v, Skipped = %d; want the shared warning once", got.Warnings, got.Skipped)
+	}
+	if !slices.Equal(limits, []int{0, 0}) {
+		t.Errorf("limits passed = %v, want every set listed in full", limits)
+	}
+
+	failure := errors.New("rate limited")
+	_, err = ListAny(func(TaskFilters) (*TaskList, error) { return nil, failure }, TaskFilters{})
+	if !errors.Is(err, failure) {
+		t.Errorf("ListAny() error = %v, want %v", err, failure)
+	}
+}
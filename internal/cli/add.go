@@ -2,7 +2,8 @@ package cli
 
 import (
 	"fmt"
-	"os"
+	"log/slog"
+	"regexp"
 
 	"github.com/alexbrand/backlog/internal/backend"
 	"github.com/alexbrand/backlog/internal/output"
@@ -13,12 +14,24 @@ var (
 	addPriority    string
 	addLabels      []string
 	addDescription string
-	addBodyFile    string
+	addDescFile    string
 	addStatus      string
 	addBlocks      []string
 	addBlockedBy   []string
+	addParent      string
+	addTemplate    string
+	addSet         []string
+	addIdemKey     string
+	addID          string
+	addIfAbsent    bool
+	addSourceKey   string
+	addClaim       bool
 )
 
+// idempotencyKeyPattern limits idempotency keys to characters that are safe
+// to store in task files and issue descriptions.
+var idempotencyKeyPattern = regexp.MustCompile(`^[A-Za-z0-9._:/-]{1,128}$`)
+
 var addCmd = &cobra.Command{
 	Use:   "add <title>",
 	Short: "Create a new task",
@@ -27,14 +40,62 @@ var addCmd = &cobra.Command{
 The title is required and provided as the first argument. Additional fields
 can be set using flags.
 
+With --idempotency-key, retrying a create is safe: if a task was already
+created with the same key within the workspace's idempotency_window
+(default 24h), that task is returned instead of creating a duplicate.
+Supported by the local and linear backends.
+
+With --if-absent, nothing is created if a task that isn't done already has
+the same title, or with --source-key, the same source:<key> label. The
+existing task is printed instead ("created": false in JSON output) and the
+command exits 0. On the local backend the check and the create happen under
+the lock used to pick task IDs, so two identical adds racing can't both
+create a task; clones synced with git, and the github and linear backends,
+still have a short window where they can.
+
+With --id (local backend only), the task gets that ID instead of the next
+number, and the command fails with a conflict if the ID is taken.
+
+With --claim, the new task is claimed for the current agent and moved to
+in-progress, as "backlog claim" would. With git_sync, the local backend
+records the create and the claim in one commit. If the claim fails, the task
+is still created and printed, and the claim error is reported with a
+non-zero exit code.
+
+With --template, defaults are loaded from .backlog/templates/<name>.md and
+flags override them (labels are combined). Template placeholders such as
+{{summary}} are filled in with --set key=value. The title argument may be
+omitted when the template defines one. See "backlog template --help".
+
 Examples:
   backlog add "Implement rate limiting"
   backlog add "Fix login bug" --priority=urgent --label=bug
   backlog add "Refactor API" --description="Split into modules" --status=todo
-  backlog add "Research caching" --body-file=./task-details.md`,
-	Args: cobra.ExactArgs(1),
+  backlog add "Research caching" --description-file=./task-details.md
+  backlog add "Write migration" --parent 012
+  backlog add "Nightly report failed" --idempotency-key report-2024-05-01
+  backlog add "Split the parser" --if-absent --source-key plan-42
+  backlog add "Split the parser" --id parser-split
+  backlog add "Fix flaky test" --claim
+  generate-spec | backlog add "Write spec" --description -
+  backlog add --template bug --set summary="Login fails" --set steps="Submit the form"`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		// A template can supply the title
+		if addTemplate != "" {
+			return cobra.RangeArgs(0, 1)(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runAdd(args[0])
+		description, _, err := resolveDescription(cmd, addDescription, addDescFile)
+		if err != nil {
+			return err
+		}
+		var title string
+		if len(args) > 0 {
+			title = args[0]
+		}
+		return runAdd(title, description)
 	},
 }
 
@@ -43,49 +104,94 @@ func init() {
 
 	addCmd.Flags().StringVarP(&addPriority, "priority", "p", "", "Priority: urgent, high, medium, low, none (default: none)")
 	addCmd.Flags().StringSliceVarP(&addLabels, "label", "l", nil, "Add labels (can be specified multiple times)")
-	addCmd.Flags().StringVarP(&addDescription, "description", "d", "", "Task description")
-	addCmd.Flags().StringVar(&addBodyFile, "body-file", "", "Read description from file")
+	addCmd.Flags().StringVarP(&addDescription, "description", "d", "", "Task description (use - to read from stdin)")
+	addCmd.Flags().StringVar(&addDescFile, "description-file", "", "Read description from file")
+	addCmd.Flags().StringVar(&addDescFile, "body-file", "", "Read description from file (alias for --description-file)")
 	addCmd.Flags().StringVarP(&addStatus, "status", "s", "", "Initial status: backlog, todo, in-progress, review, done (default: backlog)")
 	addCmd.Flags().StringSliceVar(&addBlocks, "blocks", nil, "Task IDs that this task blocks")
 	addCmd.Flags().StringSliceVar(&addBlockedBy, "blocked-by", nil, "Task IDs that block this task")
+	addCmd.Flags().StringVar(&addParent, "parent", "", "Create the task as a sub-task of this task ID")
+	addCmd.Flags().StringVar(&addTemplate, "template", "", "Create the task from a template in .backlog/templates")
+	addCmd.Flags().StringArrayVar(&addSet, "set", nil, "Template placeholder value as key=value (can be specified multiple times)")
+	addCmd.Flags().StringVar(&addIdemKey, "idempotency-key", "", "Return the existing task if one was already created with this key")
+	addCmd.Flags().StringVar(&addID, "id", "", "Create the task with this ID instead of the next one (local backend only)")
+	addCmd.Flags().BoolVar(&addIfAbsent, "if-absent", false, "Print the existing task instead if one that isn't done has the same title or source key")
+	addCmd.Flags().StringVar(&addSourceKey, "source-key", "", "Label the task source:<key>, and match --if-absent on it instead of the title")
+	addCmd.Flags().BoolVar(&addClaim, "claim", false, "Claim the new task for the current agent and move it to in-progress")
+
+	addCmd.RegisterFlagCompletionFunc("status", completeStatuses)
+	addCmd.RegisterFlagCompletionFunc("priority", completePriorities)
+	addCmd.RegisterFlagCompletionFunc("label", completeLabels)
+	addCmd.RegisterFlagCompletionFunc("blocks", completeTaskIDs)
+	addCmd.RegisterFlagCompletionFunc("blocked-by", completeTaskIDs)
+	addCmd.RegisterFlagCompletionFunc("parent", completeTaskIDs)
 }
 
-func runAdd(title string) error {
+func runAdd(title, description string) error {
+	priorityStr, statusStr, labels := addPriority, addStatus, addLabels
+	var assignee string
+
+	// Apply template defaults; flags take precedence
+	if addTemplate != "" {
+		tmpl, err := loadTemplate(addTemplate, addSet)
+		if err != nil {
+			return err
+		}
+		if title == "" {
+			title = tmpl.Title
+		}
+		if description == "" {
+			description = tmpl.Description
+		}
+		if priorityStr == "" {
+			priorityStr = string(tmpl.Priority)
+		}
+		if statusStr == "" {
+			statusStr = string(tmpl.Status)
+		}
+		labels = mergeLabels(tmpl.Labels, addLabels)
+		assignee = tmpl.Assignee
+	} else if len(addSet) > 0 {
+		return InvalidInputError("--set requires --template")
+	}
+
 	// Validate title
 	if title == "" {
 		return fmt.Errorf("title is required")
 	}
 
-	// Handle description from file
-	description := addDescription
-	if addBodyFile != "" {
-		content, err := os.ReadFile(addBodyFile)
-		if err != nil {
-			return fmt.Errorf("failed to read body file: %w", err)
+	if addIdemKey != "" && !idempotencyKeyPattern.MatchString(addIdemKey) {
+		return InvalidInputError(fmt.Sprintf("invalid idempotency key %q: use 1-128 letters, digits, or . _ : / -", addIdemKey))
+	}
+	if addSourceKey != "" {
+		if !idempotencyKeyPattern.MatchString(addSourceKey) {
+			return InvalidInputError(fmt.Sprintf("invalid source key %q: use 1-128 letters, digits, or . _ : / -", addSourceKey))
 		}
-		description = string(content)
+		labels = mergeLabels(labels, []string{backend.SourceLabelPrefix + addSourceKey})
 	}
 
 	// Validate and parse priority
 	var priority backend.Priority
-	if addPriority != "" {
-		priority = backend.Priority(addPriority)
-		if !priority.IsValid() {
-			return InvalidInputError(fmt.Sprintf("invalid priority %q (valid: urgent, high, medium, low, none)", addPriority))
+	if priorityStr != "" {
+		p, err := parsePriority(priorityStr)
+		if err != nil {
+			return err
 		}
+		priority = p
 	}
 
 	// Validate and parse status
 	var status backend.Status
-	if addStatus != "" {
-		status = backend.Status(addStatus)
-		if !status.IsValid() {
-			return InvalidInputError(fmt.Sprintf("invalid status %q (valid: backlog, todo, in-progress, review, done)", addStatus))
+	if statusStr != "" {
+		s, err := parseStatus(statusStr)
+		if err != nil {
+			return err
 		}
+		status = s
 	}
 
 	// Get backend and connect
-	b, _, cleanup, err := connectBackend()
+	b, ws, cleanup, err := connectWritableBackend()
 	if err != nil {
 		return err
 	}
@@ -93,23 +199,61 @@ func runAdd(title string) error {
 
 	// Create the task
 	input := backend.TaskInput{
-		Title:       title,
-		Description: description,
-		Status:      status,
-		Priority:    priority,
-		Labels:      addLabels,
+		Title:          title,
+		Description:    description,
+		Status:         status,
+		Priority:       priority,
+		Labels:         labels,
+		Assignee:       assignee,
+		Parent:         addParent,
+		IdempotencyKey: addIdemKey,
+		ID:             addID,
+		IfAbsent:       addIfAbsent,
 	}
 
-	task, err := b.Create(input)
+	if err := warnUnknownLabels(b, ws, labels); err != nil {
+		return err
+	}
+
+	var agentID string
+	if addClaim {
+		if _, ok := b.(backend.Claimer); !ok {
+			return UnsupportedError(b, "task claiming")
+		}
+		if agentID, err = requireAgentID(ws); err != nil {
+			return err
+		}
+	}
+
+	if IsDryRun() {
+		return previewAdd(b, input, agentID)
+	}
+
+	task, claim, claimErr, err := createTask(b, input, agentID)
 	if err != nil {
+		if backend.IsAlreadyExists(err) {
+			return ConflictError(err.Error()).WithCause(err)
+		}
 		return fmt.Errorf("failed to create task: %w", err)
 	}
 
+	// A replayed create already linked the task and ran the hooks, and an
+	// existing task matched by --if-absent is left as it is
+	replay, _ := task.Meta[backend.MetaIdempotentReplay].(bool)
+	if replay {
+		slog.Info("idempotency key matched existing task", "key", addIdemKey, "task", task.ID)
+	}
+	exists, _ := task.Meta[backend.MetaAlreadyExists].(bool)
+	if exists {
+		slog.Info("--if-absent matched existing task", "task", task.ID)
+	}
+	created := !replay && !exists
+
 	// Create dependency links if specified
-	if len(addBlocks) > 0 || len(addBlockedBy) > 0 {
+	if created && (len(addBlocks) > 0 || len(addBlockedBy) > 0) {
 		relater, ok := b.(backend.Relater)
 		if !ok {
-			return fmt.Errorf("backend %q does not support task dependencies", b.Name())
+			return UnsupportedError(b, "task dependencies")
 		}
 		for _, targetID := range addBlocks {
 			if _, err := relater.Link(task.ID, targetID, backend.RelationBlocks); err != nil {
@@ -123,10 +267,74 @@ func runAdd(title string) error {
 		}
 	}
 
-	// Output the result (unless quiet mode is enabled)
-	if IsQuiet() {
-		return nil
+	if created {
+		if err := runHooks(ws, hookEvent{Event: hookEventCreate, Task: task}); err != nil {
+			return err
+		}
+	}
+	if claim != nil && !claim.AlreadyOwned {
+		announceClaim(b, ws, claim, agentID, "")
+		event := hookEvent{Event: hookEventClaim, Task: claim.Task, PreviousStatus: task.Status, Agent: agentID}
+		if err := runHooks(ws, event); err != nil {
+			return err
+		}
+		task = claim.Task
+		if !created {
+			backend.MarkExisting(task)
+		}
 	}
+
+	// Output the result. A task whose claim failed is still printed, so
+	// the caller knows it was created.
 	formatter := output.New(output.Format(GetFormat()))
-	return formatter.FormatCreated(os.Stdout, task)
+	if err := formatter.FormatCreated(statusOutput(), task); err != nil {
+		return err
+	}
+	if claimErr != nil {
+		message := fmt.Sprintf("created task %s, but failed to claim it: %v", task.ID, claimErr)
+		if isClaimConflict(claimErr) {
+			return ConflictError(message).WithCause(claimErr)
+		}
+		return GeneralError(message).WithCause(claimErr)
+	}
+	return nil
+}
+
+// createTask creates a task and, if agentID is set, claims it for that
+// agent, in one step where the backend supports it. A claim that fails
+// after the task was created is returned as claimErr along with the task,
+// which is left in place.
+func createTask(b backend.Backend, input backend.TaskInput, agentID string) (task *backend.Task, claim *backend.ClaimResult, claimErr, err error) {
+	if agentID == "" {
+		task, err = b.Create(input)
+		return task, nil, nil, err
+	}
+
+	if creator, ok := b.(backend.CreateClaimer); ok {
+		task, claim, err = creator.CreateAndClaim(input, agentID)
+		if task != nil && err != nil {
+			return task, nil, err, nil
+		}
+		return task, claim, nil, err
+	}
+
+	if task, err = b.Create(input); err != nil {
+		return nil, nil, nil, err
+	}
+	claim, claimErr = b.(backend.Claimer).Claim(task.ID, agentID)
+	return task, claim, claimErr, nil
+}
+
+// mergeLabels combines template labels with labels given on the command line,
+// dropping duplicates while keeping their order.
+func mergeLabels(base, extra []string) []string {
+	seen := make(map[string]bool)
+	var merged []string
+	for _, label := range append(append([]string{}, base...), extra...) {
+		if !seen[label] {
+			seen[label] = true
+			merged = append(merged, label)
+		}
+	}
+	return merged
 }
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/alexbrand/backlog/internal/config"
+	"github.com/alexbrand/backlog/internal/credentials"
+	"github.com/alexbrand/backlog/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var authStore string
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage GitHub and Linear credentials",
+	Long: `Manage the GitHub tokens and Linear API keys backends connect with.
+
+Credentials are looked up in this order:
+  1. GITHUB_TOKEN or LINEAR_API_KEY
+  2. The workspace's own credential in the keychain or encrypted store
+  3. The backend's shared credential in the keychain or encrypted store
+  4. ~/.config/backlog/credentials.yaml (plaintext)
+
+The keychain store uses the OS keychain: the macOS login keychain through
+security, or the Secret Service (GNOME Keyring, KWallet) through secret-tool
+on Linux. The encrypted store keeps credentials in
+~/.config/backlog/credentials.enc, encrypted with a key generated in
+~/.config/backlog/credentials.key on first use.`,
+}
+
+var authSetCmd = &cobra.Command{
+	Use:   "set <github|linear>",
+	Short: "Store a credential in the keychain or encrypted store",
+	Long: `Prompt for a GitHub token or Linear API key and store it.
+
+With --workspace, the credential is used by that workspace only; without
+it, by every workspace of the backend without one of its own. The secret is
+read from the terminal without echoing it, or from the first line of stdin
+when it is piped.
+
+--store chooses keychain or encrypted. The default is the keychain where
+the system has one, and the encrypted file otherwise.
+
+Examples:
+  backlog auth set github --workspace work
+  backlog auth set linear --store encrypted
+  op read op://dev/github/token | backlog auth set github`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{credentials.BackendGitHub, credentials.BackendLinear},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAuthSet(args[0], authStore)
+	},
+}
+
+var authListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show which workspaces have credentials, and from where",
+	Long: `List the GitHub and Linear workspaces in the config with the store their
+credential comes from: env, keychain, encrypted, or file. Secrets are never
+printed.
+
+Examples:
+  backlog auth list
+  backlog auth list -f json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAuthList()
+	},
+}
+
+func init() {
+	authSetCmd.Flags().StringVar(&authStore, "store", "", "Where to store the credential: keychain or encrypted")
+	authCmd.AddCommand(authSetCmd)
+	authCmd.AddCommand(authListCmd)
+	rootCmd.AddCommand(authCmd)
+}
+
+func runAuthSet(backendName, store string) error {
+	if backendName != credentials.BackendGitHub && backendName != credentials.BackendLinear {
+		return InvalidInputError(fmt.Sprintf("invalid backend %q: must be github or linear", backendName))
+	}
+	if store == "" {
+		store = credentials.DefaultStore()
+	}
+	if store != credentials.StoreKeychain && store != credentials.StoreEncrypted {
+		return InvalidInputError(fmt.Sprintf("invalid --store %q: must be keychain or encrypted", store))
+	}
+
+	workspace := GetWorkspace()
+	if workspace != "" {
+		if ws, _, err := config.GetWorkspace(workspace); err == nil && ws.Backend != backendName {
+			return InvalidInputError(fmt.Sprintf("workspace %q uses the %s backend, not %s", workspace, ws.Backend, backendName))
+		}
+	}
+
+	prompt := fmt.Sprintf("%s credential: ", backendName)
+	if workspace != "" {
+		prompt = fmt.Sprintf("%s credential for workspace %s: ", backendName, workspace)
+	}
+	secret, err := readSecret(prompt)
+	if err != nil {
+		return err
+	}
+	if secret == "" {
+		return InvalidInputError("no credential given")
+	}
+
+	if err := credentials.Set(store, backendName, workspace, secret); err != nil {
+		return WrapError("failed to store credential", err)
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatCredentials(statusOutput(), []credentials.WorkspaceCredential{
+		{Workspace: workspace, Backend: backendName, Store: store},
+	})
+}
+
+func runAuthList() error {
+	var creds []credentials.WorkspaceCredential
+	if cfg := config.Get(); cfg != nil {
+		names := make([]string, 0, len(cfg.Workspaces))
+		for name := range cfg.Workspaces {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			backendName := cfg.Workspaces[name].Backend
+			if backendName != credentials.BackendGitHub && backendName != credentials.BackendLinear {
+				continue
+			}
+			_, store, err := credentials.Lookup(backendName, name)
+			if err != nil {
+				return WrapError(fmt.Sprintf("failed to read credentials for workspace %s", name), err)
+			}
+			creds = append(creds, credentials.WorkspaceCredential{Workspace: name, Backend: backendName, Store: store})
+		}
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatCredentials(os.Stdout, creds)
+}
+
+// readSecret reads a secret from the terminal without echoing it, or the
+// first line of stdin when it isn't a terminal.
+func readSecret(prompt string) (string, error) {
+	if isTerminal(os.Stdin) {
+		fmt.Fprint(os.Stderr, prompt)
+		// Without stty the secret is echoed, which is still better than failing
+		if err := stty("-echo"); err == nil {
+			defer func() {
+				stty("echo")
+				fmt.Fprintln(os.Stderr)
+			}()
+		}
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read credential: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// stty changes a setting of the terminal on stdin.
+func stty(setting string) error {
+	cmd := exec.Command("stty", setting)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
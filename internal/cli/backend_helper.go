@@ -2,15 +2,23 @@ package cli
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/alexbrand/backlog/internal/backend"
 	"github.com/alexbrand/backlog/internal/config"
+	"github.com/alexbrand/backlog/internal/execbackend"
 	"github.com/alexbrand/backlog/internal/github"
 	"github.com/alexbrand/backlog/internal/linear"
 	"github.com/alexbrand/backlog/internal/local"
 )
 
+// linearCachePath is where backlog sync mirrors Linear issues for reading
+// offline with show --cached.
+var linearCachePath = filepath.Join(".backlog", ".cache", "linear")
+
 // getBackendAndConfig returns the appropriate backend and configuration based on
 // the workspace settings. If no config is found, it falls back to checking for
 // a local .backlog directory.
@@ -20,55 +28,14 @@ func getBackendAndConfig() (backend.Backend, backend.Config, *config.Workspace,
 	var ws *config.Workspace
 
 	// Try to get workspace from config
-	workspace, _, err := config.GetWorkspace(GetWorkspace())
+	workspace, workspaceName, err := config.GetWorkspace(GetWorkspace())
 	if err == nil {
 		ws = workspace
 		// Have config - use it
-		b, err = backend.Get(ws.Backend)
+		b, backendCfg, err = workspaceBackend(ws, workspaceName)
 		if err != nil {
 			return nil, backend.Config{}, nil, err
 		}
-
-		cfg := config.Get()
-		backendCfg = backend.Config{
-			AgentID:          ResolveAgentID(ws),
-			AgentLabelPrefix: ws.AgentLabelPrefix,
-		}
-
-		switch ws.Backend {
-		case "local":
-			path := ws.Path
-			if path == "" {
-				path = ".backlog"
-			}
-			backendCfg.Workspace = &local.WorkspaceConfig{
-				Path:     path,
-				LockMode: local.LockMode(ws.LockMode),
-				GitSync:  ws.GitSync,
-			}
-		case "github":
-			backendCfg.Workspace = &github.WorkspaceConfig{
-				Repo:        ws.Repo,
-				Project:     ws.Project,
-				StatusField: ws.StatusField,
-				StatusMap:   convertStatusMap(ws.StatusMap),
-			}
-			// AgentID is already set above via ResolveAgentID
-			if cfg != nil && cfg.Defaults.AgentID != "" && backendCfg.AgentID == "" {
-				backendCfg.AgentID = cfg.Defaults.AgentID
-			}
-		case "linear":
-			backendCfg.Workspace = &linear.WorkspaceConfig{
-				TeamKey:   ws.Team,
-				StatusMap: convertLinearStatusMap(ws.StatusMap),
-			}
-			// AgentID is already set above via ResolveAgentID
-			if cfg != nil && cfg.Defaults.AgentID != "" && backendCfg.AgentID == "" {
-				backendCfg.AgentID = cfg.Defaults.AgentID
-			}
-		default:
-			return nil, backend.Config{}, nil, fmt.Errorf("unsupported backend: %s", ws.Backend)
-		}
 	} else {
 		// No config - check for local .backlog directory
 		if _, statErr := os.Stat(".backlog"); statErr == nil {
@@ -80,7 +47,7 @@ func getBackendAndConfig() (backend.Backend, backend.Config, *config.Workspace,
 			backendCfg = backend.Config{
 				AgentID:          ResolveAgentID(nil),
 				AgentLabelPrefix: "agent",
-				Workspace:        &local.WorkspaceConfig{Path: ".backlog"},
+				Workspace:        &local.WorkspaceConfig{Path: ".backlog", UndoLimit: local.DefaultUndoLimit, CommitMessage: GetCommitMessage()},
 			}
 		} else {
 			// No config and no local .backlog directory
@@ -91,6 +58,72 @@ func getBackendAndConfig() (backend.Backend, backend.Config, *config.Workspace,
 	return b, backendCfg, ws, nil
 }
 
+// workspaceBackend returns a new, unconnected backend for a configured
+// workspace and the configuration to connect it with.
+func workspaceBackend(ws *config.Workspace, workspaceName string) (backend.Backend, backend.Config, error) {
+	b, err := backend.Get(ws.Backend)
+	if err != nil {
+		return nil, backend.Config{}, err
+	}
+
+	backendCfg := backend.Config{
+		AgentID:          ResolveAgentID(ws),
+		AgentLabelPrefix: ws.AgentLabelPrefix,
+		WorkspaceName:    workspaceName,
+	}
+
+	switch ws.Backend {
+	case "local":
+		path := ws.Path
+		if path == "" {
+			path = ".backlog"
+		}
+		undoLimit := local.DefaultUndoLimit
+		if ws.UndoLimit != nil {
+			undoLimit = *ws.UndoLimit
+		}
+		backendCfg.Workspace = &local.WorkspaceConfig{
+			Path:              path,
+			LockMode:          local.LockMode(ws.LockMode),
+			GitSync:           ws.GitSync,
+			IdempotencyWindow: ws.IdempotencyWindow,
+			GitTimeout:        ws.GitTimeout,
+			DisableIndex:      ws.Index != nil && !*ws.Index,
+			UndoLimit:         undoLimit,
+			CommitMessage:     GetCommitMessage(),
+			LowercaseLabels:   ws.Labels.Normalize == config.LabelNormalizeLower,
+		}
+	case "github":
+		backendCfg.Workspace = &github.WorkspaceConfig{
+			Repo:                ws.Repo,
+			Project:             ws.Project,
+			StatusField:         ws.StatusField,
+			StatusMap:           convertStatusMap(ws.StatusMap),
+			Timeout:             ws.Timeout,
+			PriorityLabelPrefix: ws.PriorityLabelPrefix,
+			NoRateLimitWait:     noWait,
+			AssigneeMap:         ws.AssigneeMap,
+			LabelOnlyClaims:     ws.AssignOnClaim == config.AssignOnClaimFalse || ws.AssignOnClaim == config.AssignOnClaimLabelOnly,
+		}
+	case "linear":
+		backendCfg.Workspace = &linear.WorkspaceConfig{
+			TeamKey:           ws.Team,
+			StatusMap:         convertLinearStatusMap(ws.StatusMap),
+			IdempotencyWindow: ws.IdempotencyWindow,
+			CachePath:         linearCachePath,
+		}
+	case "exec":
+		backendCfg.Workspace = &execbackend.WorkspaceConfig{
+			Command: ws.Command,
+			Args:    ws.Args,
+			Timeout: ws.Timeout,
+		}
+	default:
+		return nil, backend.Config{}, fmt.Errorf("unsupported backend: %s", ws.Backend)
+	}
+	return b, backendCfg, nil
+}
+
 // convertStatusMap converts the config.Status map to github.StatusMapping map.
 func convertStatusMap(statusMap map[string]config.Status) map[backend.Status]github.StatusMapping {
 	if statusMap == nil {
@@ -130,9 +163,17 @@ func connectBackend() (backend.Backend, *config.Workspace, func(), error) {
 		return nil, nil, nil, err
 	}
 
+	start := time.Now()
 	if err := b.Connect(backendCfg); err != nil {
 		return nil, nil, nil, WrapError("failed to connect to backend", err)
 	}
+	slog.Info("connected to backend", "backend", b.Name(), "agent", backendCfg.AgentID, "duration", time.Since(start))
+
+	// Backends that learn their optional operations on connecting hand back
+	// one that implements exactly those
+	if negotiator, ok := b.(backend.Negotiator); ok {
+		b = negotiator.Negotiated()
+	}
 
 	cleanup := func() {
 		b.Disconnect()
@@ -140,3 +181,25 @@ func connectBackend() (backend.Backend, *config.Workspace, func(), error) {
 
 	return b, ws, cleanup, nil
 }
+
+// connectCachedBackend opens the local cache that backlog sync keeps for a
+// Linear workspace, without touching the network. The cache is read like a
+// local backlog; callers must not write to it.
+func connectCachedBackend() (backend.Backend, func(), error) {
+	ws, _, err := config.GetWorkspace(GetWorkspace())
+	if err != nil {
+		return nil, nil, ConfigError(err.Error())
+	}
+	if ws.Backend != "linear" {
+		return nil, nil, InvalidInputError(fmt.Sprintf("--cached is only supported for linear workspaces, not %s", ws.Backend))
+	}
+	if _, err := os.Stat(linearCachePath); err != nil {
+		return nil, nil, NotFoundError(fmt.Sprintf("no cached issues in %s; run 'backlog sync' first", linearCachePath))
+	}
+
+	b := local.New()
+	if err := b.Connect(backend.Config{Workspace: &local.WorkspaceConfig{Path: linearCachePath}}); err != nil {
+		return nil, nil, WrapError("failed to open cache", err)
+	}
+	return b, func() { b.Disconnect() }, nil
+}
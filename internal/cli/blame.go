@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var blameCmd = &cobra.Command{
+	Use:   "blame <id>",
+	Short: "Show who last changed each field of a task",
+	Long: `Show who last changed each field of a task: its title, status, priority,
+assignee, labels, and parent.
+
+For the local backend with git_sync enabled, each field is attributed to the
+commit that last changed it, found by comparing the task file at every commit
+in its git log, followed across renames between status directories. Each row
+shows the field's current value, the commit author and the agent named in the
+commit message, when it was made, and the commit. A value not yet committed is
+marked uncommitted.
+
+Without git_sync, or when git is unavailable, the fields are shown without
+attribution, with a note explaining why.
+
+Examples:
+  backlog blame 001
+  backlog blame 001 -f json
+  backlog blame 001 -f id-only   # the commits that last changed the task`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTaskIDArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBlame(args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(blameCmd)
+}
+
+func runBlame(id string) error {
+	// Get backend and connect
+	b, _, cleanup, err := connectBackend()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	blamer, ok := b.(backend.Blamer)
+	if !ok {
+		return UnsupportedError(b, "blame")
+	}
+
+	blame, err := blamer.Blame(id)
+	if err != nil {
+		if isNotFound(err) {
+			return NotFoundError(err.Error()).WithCause(err)
+		}
+		return err
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatBlame(os.Stdout, blame)
+}
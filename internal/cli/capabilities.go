@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var capabilitiesCmd = &cobra.Command{
+	Use:   "capabilities",
+	Short: "Show which optional operations the backend supports",
+	Long: `List the optional operations of the active backend and whether each is
+supported, with the commands and flags that need it.
+
+Not every backend can claim, reorder, link, or sync tasks. Agents can check
+here before running such a command instead of handling its failure. The
+backend is not contacted, so this works offline and without credentials,
+except for exec workspaces, whose program is started to ask which
+operations it supports. A command that needs an unsupported operation fails
+with exit code 1 and error_code UNSUPPORTED in JSON output.
+
+With -f json the output is {"backend": ..., "operations": [{"name",
+"commands", "supported"}]}. With -f id-only only the supported operations are
+printed, one per line. 'backlog workspace capabilities' compares every
+configured workspace.
+
+Examples:
+  backlog capabilities
+  backlog capabilities -w linear-main
+  backlog capabilities -f json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCapabilities()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(capabilitiesCmd)
+}
+
+func runCapabilities() error {
+	// Support is usually a property of the backend's type, so no connection
+	// is needed
+	b, _, _, err := getBackendAndConfig()
+	if err != nil {
+		return err
+	}
+	// Negotiating backends only know once connected
+	if _, ok := b.(backend.Negotiator); ok {
+		var cleanup func()
+		b, _, cleanup, err = connectBackend()
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatCapabilities(os.Stdout, backend.CapabilitiesOf(b))
+}
@@ -0,0 +1,297 @@
+package cli
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/config"
+	"github.com/alexbrand/backlog/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	changelogSince          string
+	changelogSinceTag       string
+	changelogLabelPrefix    string
+	changelogIncludeAuthors bool
+)
+
+// defaultChangelogSections are the sections used when the workspace
+// doesn't configure any.
+var defaultChangelogSections = []config.ChangelogSection{
+	{Label: "feature", Title: "Features"},
+	{Label: "bug", Title: "Bug Fixes"},
+}
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "Summarize the tasks completed since a date or tag",
+	Long: `Summarize the tasks completed since a date or git tag as markdown release
+notes, grouped into sections by their type label.
+
+A task's completion time is its completed_at. Local tasks completed before
+backlog recorded it fall back to the commit that moved them into done/, with
+git_sync, and then to their last update. GitHub and Linear use the time the
+issue was closed or completed.
+
+Tasks are grouped by the label with the workspace's changelog label prefix,
+"type" by default: type:feature goes under "Features" and type:bug under
+"Bug Fixes". Other type labels get a section named after them, and tasks
+without one are listed under "Other". Sections are configured per
+workspace:
+
+  changelog:
+    label_prefix: kind
+    sections:
+      - label: feature
+        title: New Features
+      - label: perf
+        title: Performance
+
+Task IDs link to the task's URL when it has one. --include-authors credits
+each task's assignee and the agents in its agent labels.
+
+Examples:
+  backlog changelog --since 2025-05-01
+  backlog changelog --since 2w
+  backlog changelog --since-tag v1.2.0 --include-authors
+  backlog changelog --since-tag v1.2.0 -f json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runChangelog()
+	},
+}
+
+func init() {
+	changelogCmd.Flags().StringVar(&changelogSince, "since", "", "Only tasks completed since a date (2025-05-01), an RFC3339 time, or a duration ago (e.g. 2w)")
+	changelogCmd.Flags().StringVar(&changelogSinceTag, "since-tag", "", "Only tasks completed since a git tag was made (requires git_sync)")
+	changelogCmd.Flags().StringVar(&changelogLabelPrefix, "label-prefix", "", "Group by labels with this prefix instead of the workspace's (default \"type\")")
+	changelogCmd.Flags().BoolVar(&changelogIncludeAuthors, "include-authors", false, "Credit each task's assignee and agents")
+	rootCmd.AddCommand(changelogCmd)
+}
+
+func runChangelog() error {
+	if changelogSince != "" && changelogSinceTag != "" {
+		return InvalidInputError("--since and --since-tag cannot be used together")
+	}
+
+	b, ws, cleanup, err := connectBackend()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	changelog := &backend.Changelog{SinceTag: changelogSinceTag}
+	var since time.Time
+	switch {
+	case changelogSince != "":
+		since, err = parseChangelogSince(changelogSince, time.Now())
+		if err != nil {
+			return InvalidInputError("--since: " + err.Error())
+		}
+		changelog.Since = &since
+	case changelogSinceTag != "":
+		dater, ok := b.(backend.TagDater)
+		if !ok {
+			return UnsupportedError(b, "--since-tag")
+		}
+		since, err = dater.TagTime(changelogSinceTag)
+		if err != nil {
+			return InvalidInputError("--since-tag: " + err.Error())
+		}
+		changelog.Since = &since
+	}
+
+	// A task is updated when it is completed, so tasks last updated before
+	// the window can't have been completed in it
+	taskList, err := b.List(backend.TaskFilters{
+		Status:       []backend.Status{backend.StatusDone},
+		IncludeDone:  true,
+		UpdatedSince: since,
+	})
+	if err != nil {
+		return WrapError("failed to list tasks", err)
+	}
+	for i := range taskList.Tasks {
+		resolveCompletion(b, &taskList.Tasks[i])
+	}
+
+	opts := changelogOptions{
+		labelPrefix:    changelogLabelPrefix,
+		agentPrefix:    agentLabelPrefix(ws),
+		includeAuthors: changelogIncludeAuthors,
+	}
+	if ws != nil {
+		if opts.labelPrefix == "" {
+			opts.labelPrefix = ws.Changelog.LabelPrefix
+		}
+		opts.sections = ws.Changelog.Sections
+	}
+	if opts.labelPrefix == "" {
+		opts.labelPrefix = "type"
+	}
+	if len(opts.sections) == 0 {
+		opts.sections = defaultChangelogSections
+	}
+	buildChangelog(changelog, taskList.Tasks, opts)
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatChangelog(os.Stdout, changelog)
+}
+
+// parseChangelogSince parses --since, which also takes a plain date for
+// the start of that day in UTC.
+func parseChangelogSince(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", strings.TrimSpace(s)); err == nil {
+		return t, nil
+	}
+	return backend.ParseSince(s, now)
+}
+
+// resolveCompletion sets CompletedAt on a done task that has none, from the
+// last move into done in its history or else its last update.
+func resolveCompletion(b backend.Backend, task *backend.Task) {
+	if task.CompletedAt != nil {
+		return
+	}
+	if historian, ok := b.(backend.Historian); ok {
+		// A history with a note was made up from the task's timestamps
+		if history, err := historian.History(task.ID, false); err == nil && history.Note == "" {
+			if at, ok := lastMoveToDone(history.Entries); ok {
+				task.CompletedAt = &at
+				return
+			}
+		}
+	}
+	if !task.Updated.IsZero() {
+		updated := task.Updated
+		task.CompletedAt = &updated
+	}
+}
+
+// lastMoveToDone returns the time of the last history entry that moved the
+// task into done from another status.
+func lastMoveToDone(entries []backend.HistoryEntry) (time.Time, bool) {
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Status != backend.StatusDone {
+			continue
+		}
+		if i == 0 || entries[i-1].Status != backend.StatusDone {
+			return entries[i].Timestamp, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// changelogOptions control how buildChangelog groups tasks.
+type changelogOptions struct {
+	labelPrefix    string                    // Prefix of the type labels, without the colon
+	sections       []config.ChangelogSection // Titles of known type labels, in order
+	agentPrefix    string                    // Prefix of the agent labels credited as authors
+	includeAuthors bool
+}
+
+// buildChangelog fills changelog with the done tasks completed since its
+// start, grouped into sections by type label. Tasks without a completion
+// time are left out.
+func buildChangelog(changelog *backend.Changelog, tasks []backend.Task, opts changelogOptions) {
+	typePrefix := opts.labelPrefix + ":"
+	titles := make(map[string]string)
+	for _, section := range opts.sections {
+		titles[typePrefix+section.Label] = section.Title
+	}
+
+	byLabel := make(map[string]*backend.ChangelogSection)
+	for _, task := range tasks {
+		if task.Status != backend.StatusDone || task.CompletedAt == nil {
+			continue
+		}
+		if changelog.Since != nil && task.CompletedAt.Before(*changelog.Since) {
+			continue
+		}
+
+		label := ""
+		for _, l := range task.Labels {
+			if strings.HasPrefix(l, typePrefix) {
+				label = l
+				break
+			}
+		}
+		section, ok := byLabel[label]
+		if !ok {
+			section = &backend.ChangelogSection{Title: titles[label], Label: label}
+			switch {
+			case label == "":
+				section.Title = "Other"
+			case section.Title == "":
+				section.Title = strings.TrimPrefix(label, typePrefix)
+			}
+			byLabel[label] = section
+		}
+
+		entry := backend.ChangelogEntry{ID: task.ID, Title: task.Title, URL: task.URL, CompletedAt: *task.CompletedAt}
+		if opts.includeAuthors {
+			entry.Authors = taskAuthors(&task, opts.agentPrefix)
+		}
+		section.Tasks = append(section.Tasks, entry)
+		changelog.Count++
+	}
+
+	// Configured sections first, in order, then other type labels by name,
+	// then the tasks without one
+	var labels []string
+	for _, section := range opts.sections {
+		if _, ok := byLabel[typePrefix+section.Label]; ok {
+			labels = append(labels, typePrefix+section.Label)
+		}
+	}
+	var others []string
+	for label := range byLabel {
+		if _, configured := titles[label]; !configured && label != "" {
+			others = append(others, label)
+		}
+	}
+	sort.Strings(others)
+	labels = append(labels, others...)
+	if _, ok := byLabel[""]; ok {
+		labels = append(labels, "")
+	}
+
+	changelog.Sections = []backend.ChangelogSection{}
+	for _, label := range labels {
+		section := byLabel[label]
+		sort.SliceStable(section.Tasks, func(i, j int) bool {
+			if !section.Tasks[i].CompletedAt.Equal(section.Tasks[j].CompletedAt) {
+				return section.Tasks[i].CompletedAt.Before(section.Tasks[j].CompletedAt)
+			}
+			return section.Tasks[i].ID < section.Tasks[j].ID
+		})
+		changelog.Sections = append(changelog.Sections, *section)
+	}
+}
+
+// taskAuthors returns a task's assignee followed by the agents named in its
+// agent labels, without duplicates.
+func taskAuthors(task *backend.Task, agentPrefix string) []string {
+	var authors []string
+	add := func(name string) {
+		for _, a := range authors {
+			if a == name {
+				return
+			}
+		}
+		authors = append(authors, name)
+	}
+	if task.Assignee != "" {
+		add(task.Assignee)
+	}
+	for _, label := range task.Labels {
+		if agent, ok := strings.CutPrefix(label, agentPrefix+":"); ok && agent != "" {
+			add(agent)
+		}
+	}
+	return authors
+}
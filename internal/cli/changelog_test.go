@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+func TestBuildChangelog(t *testing.T) {
+	since := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	at := func(day int) *time.Time {
+		t := time.Date(2025, 5, day, 12, 0, 0, 0, time.UTC)
+		return &t
+	}
+	tasks := []backend.Task{
+		{ID: "001", Title: "Fix crash", Status: backend.StatusDone, Labels: []string{"type:bug"}, CompletedAt: at(3)},
+		{ID: "002", Title: "Dark mode", Status: backend.StatusDone, Labels: []string{"ui", "type:feature"}, CompletedAt: at(5), URL: "https://example.com/2"},
+		{ID: "003", Title: "Earlier fix", Status: backend.StatusDone, Labels: []string{"type:bug"}, CompletedAt: at(2)},
+		{ID: "004", Title: "Tidy docs", Status: backend.StatusDone, Assignee: "alex", Labels: []string{"agent:claude-1", "agent:alex"}, CompletedAt: at(4)},
+		{ID: "005", Title: "Bump deps", Status: backend.StatusDone, Labels: []string{"type:chore"}, CompletedAt: at(6)},
+		{ID: "006", Title: "Before the window", Status: backend.StatusDone, Labels: []string{"type:bug"}, CompletedAt: &since},
+		{ID: "007", Title: "Last sprint", Status: backend.StatusDone, CompletedAt: func() *time.Time { t := since.Add(-time.Hour); return &t }()},
+		{ID: "008", Title: "Still open", Status: backend.StatusReview, Labels: []string{"type:bug"}},
+	}
+
+	changelog := &backend.Changelog{Since: &since}
+	buildChangelog(changelog, tasks, changelogOptions{
+		labelPrefix:    "type",
+		sections:       defaultChangelogSections,
+		agentPrefix:    "agent",
+		includeAuthors: true,
+	})
+
+	if changelog.Count != 6 {
+		t.Errorf("Count = %d, want 6", changelog.Count)
+	}
+	var got [][]string
+	for _, section := range changelog.Sections {
+		ids := []string{section.Title, section.Label}
+		for _, entry := range section.Tasks {
+			ids = append(ids, entry.ID)
+		}
+		got = append(got, ids)
+	}
+	want := [][]string{
+		{"Features", "type:feature", "002"},
+		{"Bug Fixes", "type:bug", "006", "003", "001"},
+		{"chore", "type:chore", "005"},
+		{"Other", "", "004"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sections = %v, want %v", got, want)
+	}
+
+	other := changelog.Sections[3].Tasks[0]
+	if !reflect.DeepEqual(other.Authors, []string{"alex", "claude-1"}) {
+		t.Errorf("Authors = %v, want [alex claude-1]", other.Authors)
+	}
+	if changelog.Sections[0].Tasks[0].URL != "https://example.com/2" {
+		t.Errorf("URL = %q, want the task's URL", changelog.Sections[0].Tasks[0].URL)
+	}
+}
+
+func TestLastMoveToDone(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 5, d, 0, 0, 0, 0, time.UTC) }
+	entries := []backend.HistoryEntry{
+		{Action: "add", Status: backend.StatusTodo, Timestamp: day(1)},
+		{Action: "move", Status: backend.StatusDone, Timestamp: day(2)},
+		{Action: "reopen", Status: backend.StatusTodo, Timestamp: day(3)},
+		{Action: "move", Status: backend.StatusDone, Timestamp: day(4)},
+		{Action: "edit", Status: backend.StatusDone, Timestamp: day(5)},
+	}
+	if got, ok := lastMoveToDone(entries); !ok || !got.Equal(day(4)) {
+		t.Errorf("lastMoveToDone() = %v, %v; want %v", got, ok, day(4))
+	}
+	if _, ok := lastMoveToDone(entries[:1]); ok {
+		t.Error("lastMoveToDone() found a move in a history without one")
+	}
+}
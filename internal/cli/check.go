@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check <id> <item>",
+	Short: "Check off an item of a task's checklist",
+	Long: `Check off an item of the markdown checklist in a task's description.
+
+Checklist items are description lines like "- [ ] Write tests". The item is
+given by its 1-based index, as shown by 'backlog show -f json', or by a
+case-insensitive piece of its text. Text that matches more than one item
+fails and lists the candidates; an item whose whole text matches wins over
+items that only contain it.
+
+The description is rewritten through the backend's update, changing only
+the item's checkbox.
+
+Examples:
+  backlog check 001 2
+  backlog check 001 "write tests"
+  backlog check GH-42 docs --dry-run`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeTaskIDArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCheck(args[0], args[1], true)
+	},
+}
+
+var uncheckCmd = &cobra.Command{
+	Use:   "uncheck <id> <item>",
+	Short: "Uncheck an item of a task's checklist",
+	Long: `Uncheck an item of the markdown checklist in a task's description.
+
+The item is given the same way as for 'backlog check': by its 1-based index
+or by a case-insensitive piece of its text.
+
+Examples:
+  backlog uncheck 001 2
+  backlog uncheck 001 "write tests"`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeTaskIDArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCheck(args[0], args[1], false)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+	rootCmd.AddCommand(uncheckCmd)
+}
+
+func runCheck(id, ref string, done bool) error {
+	// Get backend and connect
+	b, _, cleanup, err := connectWritableBackend()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	before, err := b.Get(id)
+	if err != nil {
+		if isNotFound(err) {
+			return NotFoundError(err.Error()).WithCause(err)
+		}
+		return err
+	}
+
+	description, err := checklistDescription(before, ref, done)
+	if err != nil {
+		return err
+	}
+	changes := backend.TaskChanges{Description: &description}
+
+	if IsDryRun() {
+		return previewEdit(b, id, changes)
+	}
+
+	// Checking an item that is already checked changes nothing
+	task := before
+	if description != before.Description {
+		task, err = b.Update(id, changes)
+		if err != nil {
+			if isNotFound(err) {
+				return NotFoundError(err.Error()).WithCause(err)
+			}
+			return err
+		}
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatUpdated(statusOutput(), task, backend.DiffTasks(before, task))
+}
+
+// checklistDescription returns the task's description with the checklist
+// item ref refers to checked or unchecked. An ambiguous ref fails with the
+// candidate items in the message and the error's details.
+func checklistDescription(task *backend.Task, ref string, done bool) (string, error) {
+	items := backend.ParseChecklist(task.Description)
+	index, err := backend.FindChecklistItem(items, ref)
+	if err != nil {
+		var ambiguous *backend.AmbiguousChecklistItemError
+		if errors.As(err, &ambiguous) {
+			lines := make([]string, len(ambiguous.Candidates))
+			for i, item := range ambiguous.Candidates {
+				lines[i] = fmt.Sprintf("  %d. %s", item.Index, item.Text)
+			}
+			e := InvalidInputError(fmt.Sprintf("%s on task %s; use an index to choose one:\n%s",
+				err, task.ID, strings.Join(lines, "\n")))
+			e.Details = map[string]any{"candidates": ambiguous.Candidates}
+			return "", e
+		}
+		return "", InvalidInputError(fmt.Sprintf("%s on task %s", err, task.ID))
+	}
+
+	description, err := backend.SetChecklistItem(task.Description, index, done)
+	if err != nil {
+		return "", InvalidInputError(err.Error())
+	}
+	return description, nil
+}
@@ -2,10 +2,12 @@ package cli
 
 import (
 	"fmt"
-	"os"
-	"strings"
+	"log/slog"
+	"time"
 
 	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/config"
+	"github.com/alexbrand/backlog/internal/execbackend"
 	"github.com/alexbrand/backlog/internal/github"
 	"github.com/alexbrand/backlog/internal/linear"
 	"github.com/alexbrand/backlog/internal/local"
@@ -13,6 +15,12 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	claimRestartClock bool
+	claimSteal        bool
+	claimComment      string
+)
+
 var claimCmd = &cobra.Command{
 	Use:   "claim <id>",
 	Short: "Claim a task for the current agent",
@@ -26,23 +34,50 @@ The claim operation:
 If the task is already claimed by the same agent, this is a no-op and returns success.
 If the task is already claimed by a different agent, returns exit code 2 (conflict).
 
+Use --steal to take over a task another agent holds, such as one whose
+agent crashed while its claim was still active. The local backend
+overwrites the other agent's lock even if it hasn't expired and replaces
+its agent label. A comment such as "claim stolen from claude-1 by claude-2"
+is added as an audit trail.
+
+The local backend records when a task is first claimed as its started_at
+time. Claiming a task that was released keeps the original start time;
+use --restart-clock to start timing it again from now.
+
+On GitHub, a claim also assigns the issue to the agent's login in the
+workspace's assignee_map, or to the token's user. Set assign_on_claim:
+label-only to claim with the agent label alone.
+
+With claim_comment: true on the workspace, or --comment, a comment is
+posted on the task recording which agent claimed it and when, so people
+watching the issue can see why it was picked up. The --comment message is
+added to it. A comment that can't be posted only prints a warning; the claim
+stands.
+
 Examples:
   backlog claim 001
   backlog claim 001 --agent-id=claude-2
+  backlog claim 001 --restart-clock
+  backlog claim 001 --steal
+  backlog claim 001 --comment="starting work on the retry logic"
   backlog claim 001 -f json`,
-	Args: cobra.ExactArgs(1),
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTaskIDArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runClaim(args[0])
 	},
 }
 
 func init() {
+	claimCmd.Flags().BoolVar(&claimRestartClock, "restart-clock", false, "Reset the task's started_at to now instead of keeping an earlier start")
+	claimCmd.Flags().BoolVar(&claimSteal, "steal", false, "Take over the task even if another agent holds the claim")
+	claimCmd.Flags().StringVar(&claimComment, "comment", "", "Post a comment announcing the claim, with this message")
 	rootCmd.AddCommand(claimCmd)
 }
 
 func runClaim(id string) error {
 	// Get backend and connect
-	b, ws, cleanup, err := connectBackend()
+	b, ws, cleanup, err := connectWritableBackend()
 	if err != nil {
 		return err
 	}
@@ -51,34 +86,123 @@ func runClaim(id string) error {
 	// Check if backend supports claiming
 	claimer, ok := b.(backend.Claimer)
 	if !ok {
-		return fmt.Errorf("backend %q does not support task claiming", b.Name())
+		return UnsupportedError(b, "task claiming")
+	}
+	claim := claimer.Claim
+	if claimRestartClock {
+		restarter, ok := b.(backend.ClockRestarter)
+		if !ok {
+			return UnsupportedError(b, "--restart-clock")
+		}
+		claim = restarter.ClaimRestartClock
+	}
+	stealer, canSteal := b.(backend.Stealer)
+	var stolenFrom string
+	if claimSteal {
+		if claimRestartClock {
+			return InvalidInputError("--steal and --restart-clock cannot be used together")
+		}
+		if !canSteal {
+			return UnsupportedError(b, "--steal")
+		}
+		claim = func(id, agentID string) (*backend.ClaimResult, error) {
+			result, claimedBy, err := stealer.Steal(id, agentID)
+			stolenFrom = claimedBy
+			return result, err
+		}
 	}
 
 	// Resolve agent ID
-	resolvedAgentID := ResolveAgentID(ws)
+	resolvedAgentID, err := requireAgentID(ws)
+	if err != nil {
+		return err
+	}
+
+	if IsDryRun() {
+		return previewClaim(b, ws, id, resolvedAgentID, claimSteal)
+	}
+
+	// The claim result only has the new status, so look up the old one
+	// for hooks that want it
+	var previousStatus backend.Status
+	if ws != nil && len(ws.Hooks.OnClaim) > 0 {
+		if task, err := b.Get(id); err == nil {
+			previousStatus = task.Status
+		}
+	}
 
 	// Attempt to claim the task
-	result, err := claimer.Claim(id, resolvedAgentID)
+	result, err := claim(id, resolvedAgentID)
 	if err != nil {
 		// Check for conflict error (task already claimed by another agent)
-		if _, isLocalConflict := err.(*local.ClaimConflictError); isLocalConflict {
-			return ConflictError(err.Error())
+		if isClaimConflict(err) {
+			message := err.Error()
+			if canSteal {
+				message += "; use --steal to take it over"
+			}
+			return ConflictError(message).WithCause(err)
 		}
-		if _, isGitHubConflict := err.(*github.ClaimConflictError); isGitHubConflict {
-			return ConflictError(err.Error())
+		if isNotFound(err) {
+			return NotFoundError(err.Error()).WithCause(err)
 		}
-		if _, isLinearConflict := err.(*linear.ClaimConflictError); isLinearConflict {
-			return ConflictError(err.Error())
+		return err
+	}
+
+	// Record a claim taken over from another agent
+	if stolenFrom != "" {
+		audit := fmt.Sprintf("claim stolen from %s by %s", stolenFrom, resolvedAgentID)
+		if _, err := b.AddComment(id, audit); err != nil {
+			return fmt.Errorf("task claimed but failed to record the stolen claim: %w", err)
 		}
-		// Check for not found error (case-insensitive check for 404/Not Found)
-		errLower := strings.ToLower(err.Error())
-		if strings.Contains(errLower, "not found") || strings.Contains(errLower, "404") {
-			return NotFoundError(err.Error())
+	}
+
+	if !result.AlreadyOwned {
+		announceClaim(b, ws, result, resolvedAgentID, claimComment)
+		event := hookEvent{Event: hookEventClaim, Task: result.Task, PreviousStatus: previousStatus, Agent: resolvedAgentID}
+		if err := runHooks(ws, event); err != nil {
+			return err
 		}
-		return err
 	}
 
 	// Output the result
 	formatter := output.New(output.Format(GetFormat()))
-	return formatter.FormatClaimed(os.Stdout, result.Task, resolvedAgentID, result.AlreadyOwned)
+	return formatter.FormatClaimed(statusOutput(), result.Task, resolvedAgentID, result.AlreadyOwned)
+}
+
+// isClaimConflict reports whether err means the task is already claimed by
+// another agent.
+func isClaimConflict(err error) bool {
+	switch err.(type) {
+	case *local.ClaimConflictError, *github.ClaimConflictError, *linear.ClaimConflictError, *execbackend.ClaimConflictError:
+		return true
+	default:
+		return false
+	}
+}
+
+// announceClaim posts a comment recording a new claim when the workspace
+// sets claim_comment or a message is given. A comment that can't be posted
+// only logs a warning, since the claim itself succeeded.
+func announceClaim(b backend.Backend, ws *config.Workspace, claim *backend.ClaimResult, agentID, message string) {
+	if message == "" && (ws == nil || !ws.ClaimComment) {
+		return
+	}
+	body := claimNote("claimed", agentID, time.Now(), claim.ExpiresAt, message)
+	if _, err := b.AddComment(claim.Task.ID, body); err != nil {
+		slog.Warn("task claimed but failed to post the claim comment", "task", claim.Task.ID, "error", err)
+	}
+}
+
+// claimNote formats the comment posted on claim and release, such as
+// "🤖 claimed by agent `claude-1` at 2025-06-01T12:00Z — expires 12:30Z",
+// followed by message as its own paragraph.
+func claimNote(action, agentID string, at, expiresAt time.Time, message string) string {
+	note := fmt.Sprintf("🤖 %s by agent `%s` at %s", action, agentID, at.UTC().Format("2006-01-02T15:04Z"))
+	if !expiresAt.IsZero() {
+		note += " — expires " + expiresAt.UTC().Format("15:04Z")
+	}
+	if message != "" {
+		note += "\n\n" + message
+	}
+	return note
 }
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClaimNote(t *testing.T) {
+	at := time.Date(2025, 6, 1, 12, 0, 30, 0, time.UTC)
+	tests := []struct {
+		name      string
+		action    string
+		expiresAt time.Time
+		message   string
+		want      string
+	}{
+		{
+			name:   "no expiry",
+			action: "claimed",
+			want:   "🤖 claimed by agent `build-agent-3` at 2025-06-01T12:00Z",
+		},
+		{
+			name:      "expiry and message",
+			action:    "claimed",
+			expiresAt: at.Add(30 * time.Minute),
+			message:   "starting work on the retry logic",
+			want:      "🤖 claimed by agent `build-agent-3` at 2025-06-01T12:00Z — expires 12:30Z\n\nstarting work on the retry logic",
+		},
+		{
+			name:   "release",
+			action: "released",
+			want:   "🤖 released by agent `build-agent-3` at 2025-06-01T12:00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := claimNote(tt.action, "build-agent-3", at, tt.expiresAt, tt.message); got != tt.want {
+				t.Errorf("claimNote() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
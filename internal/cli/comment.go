@@ -1,86 +1,265 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"sort"
 	"strings"
 
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/config"
 	"github.com/alexbrand/backlog/internal/output"
 	"github.com/spf13/cobra"
 )
 
-var commentBodyFile string
+var (
+	commentBodyFile string
+	commentBody     string
+	commentEdit     string
+	commentDelete   string
+	commentForce    bool
+	commentReverse  bool
+)
 
 var commentCmd = &cobra.Command{
 	Use:   "comment <id> <message>",
-	Short: "Add a comment to a task",
-	Long: `Add a comment to a task.
+	Short: "Add, edit, or delete a comment on a task",
+	Long: `Add, edit, or delete a comment on a task.
 
 The comment is attributed to the current agent (resolved via --agent-id, BACKLOG_AGENT_ID,
-workspace config, or hostname fallback).
+workspace config, or the auto-generated ID shown by "backlog whoami").
+
+With --edit, the body of an existing comment is replaced; with --delete, the
+comment is removed. Comment IDs are shown by "backlog show --comments" and
+returned when a comment is added, and listed by "backlog comment list".
+Comments written by someone else are refused unless --force is given.
+Editing is supported by the local and Linear backends.
 
 Examples:
   backlog comment 001 "Found the bug, working on fix"
   backlog comment 001 "Starting work on implementation" -f json
-  backlog comment 001 --body-file=./analysis.md`,
+  backlog comment 001 --body-file=./analysis.md
+  backlog comment 001 --edit 2 --body "Fixed in the retry handler"
+  backlog comment 001 --delete 2`,
 	Args: func(cmd *cobra.Command, args []string) error {
-		// With --body-file, we only need the ID
-		if commentBodyFile != "" {
+		// With --body, --body-file, --edit, or --delete, we only need the ID
+		if commentBodyFile != "" || cmd.Flags().Changed("body") || commentEdit != "" || commentDelete != "" {
 			if len(args) != 1 {
-				return fmt.Errorf("requires exactly 1 argument (task ID) when using --body-file")
+				return fmt.Errorf("requires exactly 1 argument (task ID) when using --body, --body-file, --edit, or --delete")
 			}
 			return nil
 		}
-		// Without --body-file, we need both ID and message
+		// Otherwise, we need both ID and message
 		if len(args) != 2 {
 			return fmt.Errorf("requires exactly 2 arguments: <id> <message>")
 		}
 		return nil
 	},
+	ValidArgsFunction: completeTaskIDArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		id := args[0]
-		var message string
+		hasBody := commentBodyFile != "" || cmd.Flags().Changed("body")
+
+		switch {
+		case commentEdit != "" && commentDelete != "":
+			return InvalidInputError("--edit and --delete cannot be used together")
+		case commentBodyFile != "" && cmd.Flags().Changed("body"):
+			return InvalidInputError("--body and --body-file cannot be used together")
+		case commentDelete != "" && hasBody:
+			return InvalidInputError("--delete does not take a comment body")
+		case commentEdit != "" && !hasBody:
+			return InvalidInputError("--edit requires --body or --body-file")
+		case commentForce && commentEdit == "" && commentDelete == "":
+			return InvalidInputError("--force only applies to --edit and --delete")
+		}
 
-		if commentBodyFile != "" {
+		if commentDelete != "" {
+			return runDeleteComment(id, commentDelete, commentForce)
+		}
+
+		var message string
+		switch {
+		case commentBodyFile != "":
 			// Read message from file
 			content, err := os.ReadFile(commentBodyFile)
 			if err != nil {
 				return fmt.Errorf("failed to read body file: %w", err)
 			}
 			message = string(content)
-		} else {
+		case cmd.Flags().Changed("body"):
+			message = commentBody
+		default:
 			message = args[1]
 		}
 
+		if commentEdit != "" {
+			return runEditComment(id, commentEdit, message, commentForce)
+		}
 		return runComment(id, message)
 	},
 }
 
+var commentListCmd = &cobra.Command{
+	Use:   "list <id>",
+	Short: "List the comments on a task",
+	Long: `List the comments on a task, oldest first, or newest first with --reverse.
+
+The table has the same columns for every backend: the comment's ID, its
+author, how long ago it was written, and the first line of its body. With
+-f json, each comment carries its ID, author, full body, creation time, the
+task ID, and "source", the name of the workspace it came from, so comments
+gathered from several workspaces can be told apart.
+
+Examples:
+  backlog comment list 001
+  backlog comment list 001 --reverse
+  backlog comment list GH-42 -f json`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTaskIDArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCommentList(args[0], commentReverse)
+	},
+}
+
 func init() {
+	commentListCmd.Flags().BoolVar(&commentReverse, "reverse", false, "List the newest comments first")
+	commentCmd.AddCommand(commentListCmd)
 	commentCmd.Flags().StringVar(&commentBodyFile, "body-file", "", "Read comment body from file")
+	commentCmd.Flags().StringVar(&commentBody, "body", "", "Comment body")
+	commentCmd.Flags().StringVar(&commentEdit, "edit", "", "Replace the body of the comment with this ID")
+	commentCmd.Flags().StringVar(&commentDelete, "delete", "", "Delete the comment with this ID")
+	commentCmd.Flags().BoolVar(&commentForce, "force", false, "Edit or delete a comment written by someone else")
 	rootCmd.AddCommand(commentCmd)
 }
 
 func runComment(id string, message string) error {
+	// A blank comment would only add an empty heading to the task
+	if strings.TrimSpace(message) == "" {
+		return InvalidInputError("comment message cannot be empty")
+	}
+
 	// Get backend and connect
-	b, _, cleanup, err := connectBackend()
+	b, ws, cleanup, err := connectWritableBackend()
 	if err != nil {
 		return err
 	}
 	defer cleanup()
 
+	if _, err := requireAgentID(ws); err != nil {
+		return err
+	}
+
 	// Add the comment
 	comment, err := b.AddComment(id, message)
 	if err != nil {
-		// Check for not found error (case-insensitive)
-		errLower := strings.ToLower(err.Error())
-		if strings.Contains(errLower, "not found") || strings.Contains(errLower, "404") {
-			return NotFoundError(err.Error())
-		}
-		return err
+		return commentError(err)
 	}
 
 	// Output the result
 	formatter := output.New(output.Format(GetFormat()))
-	return formatter.FormatComment(os.Stdout, comment)
+	return formatter.FormatComment(statusOutput(), comment)
+}
+
+func runCommentList(id string, reverse bool) error {
+	b, _, cleanup, err := connectBackend()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	comments, err := b.ListComments(id)
+	if err != nil {
+		return commentError(err)
+	}
+
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].Created.Before(comments[j].Created)
+	})
+	if reverse {
+		slices.Reverse(comments)
+	}
+	for i := range comments {
+		comments[i].TaskID = id
+	}
+
+	// Without a config, the backlog is a bare local one
+	source := b.Name()
+	if _, name, err := config.GetWorkspace(GetWorkspace()); err == nil {
+		source = name
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatCommentList(os.Stdout, source, comments)
+}
+
+func runEditComment(id, commentID, message string, force bool) error {
+	if strings.TrimSpace(message) == "" {
+		return InvalidInputError("comment message cannot be empty")
+	}
+
+	editor, cleanup, err := connectCommentEditor()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	comment, err := editor.EditComment(id, commentID, message, force)
+	if err != nil {
+		return commentError(err)
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatCommentEdited(statusOutput(), comment)
+}
+
+func runDeleteComment(id, commentID string, force bool) error {
+	editor, cleanup, err := connectCommentEditor()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if err := editor.DeleteComment(id, commentID, force); err != nil {
+		return commentError(err)
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatCommentDeleted(statusOutput(), id, commentID)
+}
+
+// connectCommentEditor connects to the backend and checks that it can edit
+// comments. Comments are only changed by an identified agent, since the
+// agent decides whose comments may be changed without --force.
+func connectCommentEditor() (backend.CommentEditor, func(), error) {
+	b, ws, cleanup, err := connectWritableBackend()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := requireAgentID(ws); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	editor, ok := b.(backend.CommentEditor)
+	if !ok {
+		cleanup()
+		return nil, nil, UnsupportedError(b, "editing comments")
+	}
+	return editor, cleanup, nil
+}
+
+// commentError maps a comment operation error to its exit code.
+func commentError(err error) error {
+	var authorErr *backend.CommentAuthorError
+	if errors.As(err, &authorErr) {
+		return ConflictError(err.Error() + "; use --force to change it anyway").WithCause(err)
+	}
+
+	if isNotFound(err) {
+		return NotFoundError(err.Error()).WithCause(err)
+	}
+	return err
 }
@@ -0,0 +1,256 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// completionTitleWidth is the longest title shown next to a completed task ID.
+const completionTitleWidth = 40
+
+var completionCmd = &cobra.Command{
+	Use:   "completion <bash|zsh|fish>",
+	Short: "Generate shell completion scripts",
+	Long: `Generate a completion script for bash, zsh, or fish.
+
+Task IDs, statuses, priorities, workspaces, and labels complete dynamically.
+Local workspaces are read from disk. GitHub and Linear workspaces complete
+from a cache refreshed by each "backlog list", so completion never waits on
+the network; set completion.remote: true in the config to query them
+directly instead.
+
+Examples:
+  # bash (add to ~/.bashrc)
+  source <(backlog completion bash)
+
+  # zsh (add to ~/.zshrc)
+  source <(backlog completion zsh)
+
+  # fish
+  backlog completion fish > ~/.config/fish/completions/backlog.fish`,
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	// Generating a script needs no configuration
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCompletion(args[0])
+	},
+}
+
+func init() {
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(completionCmd)
+}
+
+func runCompletion(shell string) error {
+	switch shell {
+	case "bash":
+		return rootCmd.GenBashCompletionV2(os.Stdout, true)
+	case "zsh":
+		return rootCmd.GenZshCompletion(os.Stdout)
+	default:
+		return rootCmd.GenFishCompletion(os.Stdout, true)
+	}
+}
+
+// disableFileCompletion stops shells from offering file names for
+// positional arguments of commands that don't complete them, since no
+// backlog command takes a file path.
+func disableFileCompletion(cmd *cobra.Command) {
+	if cmd.ValidArgsFunction == nil && len(cmd.ValidArgs) == 0 {
+		cmd.ValidArgsFunction = cobra.NoFileCompletions
+	}
+	for _, sub := range cmd.Commands() {
+		disableFileCompletion(sub)
+	}
+}
+
+// completionTask is a task as remembered for shell completion.
+type completionTask struct {
+	ID     string   `json:"id"`
+	Title  string   `json:"title"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+// completeTaskIDArg completes a task ID as the first positional argument.
+func completeTaskIDArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeTaskIDs(cmd, args, toComplete)
+}
+
+// completeTaskIDs completes task IDs, described by their titles.
+func completeTaskIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var completions []string
+	for _, task := range completionTasks() {
+		if !strings.HasPrefix(strings.ToLower(task.ID), strings.ToLower(toComplete)) {
+			continue
+		}
+		completions = append(completions, cobra.CompletionWithDesc(task.ID, truncateTitle(task.Title)))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeStatuses completes the canonical status names.
+func completeStatuses(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var completions []string
+	for _, status := range backend.ValidStatuses() {
+		completions = append(completions, string(status))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completePriorities completes the canonical priority names.
+func completePriorities(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var completions []string
+	for _, priority := range backend.ValidPriorities() {
+		completions = append(completions, string(priority))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeWorkspaces completes the configured workspace names, described by
+// their backends.
+func completeWorkspaces(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if err := initConfig(); err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	for name, ws := range config.Get().Workspaces {
+		completions = append(completions, cobra.CompletionWithDesc(name, ws.Backend))
+	}
+	sort.Strings(completions)
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeLabels completes the labels used by existing tasks.
+func completeLabels(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	seen := make(map[string]bool)
+	var completions []string
+	for _, task := range completionTasks() {
+		for _, label := range task.Labels {
+			if !seen[label] {
+				seen[label] = true
+				completions = append(completions, label)
+			}
+		}
+	}
+	sort.Strings(completions)
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionTasks returns the tasks of the active workspace for completion.
+// Remote workspaces are read from the completion cache unless
+// completion.remote is set, so pressing tab never blocks on the network.
+// Errors yield no completions rather than noise in the shell.
+func completionTasks() []completionTask {
+	// Completion requests skip the root command's PersistentPreRunE
+	if err := initConfig(); err != nil {
+		return nil
+	}
+
+	ws, name, err := config.GetWorkspace(GetWorkspace())
+	remote := err == nil && ws.Backend != "local"
+	if remote && !config.Get().Completion.Remote {
+		tasks, _ := readCompletionCache(name)
+		return tasks
+	}
+
+	b, _, cleanup, err := connectBackend()
+	if err != nil {
+		return nil
+	}
+	defer cleanup()
+
+	list, err := b.List(backend.TaskFilters{IncludeDone: true})
+	if err != nil {
+		return nil
+	}
+	if remote {
+		writeCompletionCache(name, list.Tasks)
+	}
+	return toCompletionTasks(list.Tasks)
+}
+
+func toCompletionTasks(tasks []backend.Task) []completionTask {
+	result := make([]completionTask, len(tasks))
+	for i, task := range tasks {
+		result[i] = completionTask{ID: task.ID, Title: task.Title, Labels: task.Labels}
+	}
+	return result
+}
+
+// truncateTitle shortens a title to completionTitleWidth characters.
+func truncateTitle(title string) string {
+	runes := []rune(title)
+	if len(runes) <= completionTitleWidth {
+		return title
+	}
+	return string(runes[:completionTitleWidth-1]) + "…"
+}
+
+// completionCachePath returns the cache file for a workspace, kept next to
+// the config file. It returns "" when no config file is in use.
+func completionCachePath(workspaceName string) string {
+	cfgPath := config.ConfigFilePath()
+	if cfgPath == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(cfgPath), ".cache", fmt.Sprintf("completion-%s.json", workspaceName))
+}
+
+// readCompletionCache returns the tasks cached for a workspace.
+func readCompletionCache(workspaceName string) ([]completionTask, error) {
+	path := completionCachePath(workspaceName)
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var tasks []completionTask
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// writeCompletionCache replaces the tasks cached for a workspace. The cache
+// only speeds up completion, so failures are ignored.
+func writeCompletionCache(workspaceName string, tasks []backend.Task) {
+	path := completionCachePath(workspaceName)
+	if path == "" {
+		return
+	}
+	data, err := json.Marshal(toCompletionTasks(tasks))
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	os.WriteFile(path, data, 0644)
+}
+
+// refreshCompletionCache caches listed tasks for remote workspaces so later
+// completions can offer their IDs without a network call.
+func refreshCompletionCache(tasks []backend.Task) {
+	ws, name, err := config.GetWorkspace(GetWorkspace())
+	if err != nil || ws.Backend == "local" {
+		return
+	}
+	writeCompletionCache(name, tasks)
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/alexbrand/backlog/internal/config"
+	"github.com/alexbrand/backlog/internal/credentials"
 	"github.com/alexbrand/backlog/internal/output"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -16,11 +17,26 @@ var configCmd = &cobra.Command{
 	Long:  `Manage backlog configuration settings.`,
 }
 
+var configShowResolved bool
+
 var configShowCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Display current configuration",
-	Long:  `Display the current configuration in YAML format.`,
+	Long: `Display the current configuration in YAML format.
+
+With --resolved, print every effective value after merging built-in
+defaults, the config file (with ${VAR} references expanded), BACKLOG_*
+environment variables, and command-line flags, annotated with where each
+value came from. Credentials are redacted.
+
+Examples:
+  backlog config show
+  backlog config show --resolved
+  backlog config show --resolved -f json`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if configShowResolved {
+			return runConfigShowResolved(cmd)
+		}
 		return runConfigShow()
 	},
 }
@@ -34,10 +50,60 @@ var configHealthCmd = &cobra.Command{
 	},
 }
 
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a workspace setting",
+	Long: `Print the effective value of a setting in the active workspace,
+including built-in defaults for keys that are not set.
+
+Examples:
+  backlog config get lock_mode
+  backlog -w work config get repo`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSettingKeyArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfigGet(args[0])
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a workspace setting",
+	Long: `Change a setting in the active workspace and save it to the config file.
+
+Values are validated before the file is written: lock_mode must be file or
+git, and lock_mode git requires git_sync: true. Comments and key order in
+the config file are preserved.
+
+Examples:
+  backlog config set git_sync true
+  backlog config set lock_mode git`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeSettingKeyArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfigSet(args[0], args[1])
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List workspace settings",
+	Long:  `List the effective settings of the active workspace, including built-in defaults.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfigList()
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(configCmd)
 	configCmd.AddCommand(configShowCmd)
 	configCmd.AddCommand(configHealthCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configListCmd)
+
+	configShowCmd.Flags().BoolVar(&configShowResolved, "resolved", false, "Show effective values and their sources")
 }
 
 func runConfigShow() error {
@@ -63,6 +129,61 @@ func runConfigShow() error {
 	return nil
 }
 
+func runConfigShowResolved(cmd *cobra.Command) error {
+	if config.Get() == nil {
+		return ConfigError("no configuration loaded")
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatResolvedConfig(os.Stdout, config.Resolve(configOverrides(cmd)))
+}
+
+// configOverrides returns the values supplied by flags and environment
+// variables rather than the config file, along with the credentials in use.
+func configOverrides(cmd *cobra.Command) []config.Override {
+	var overrides []config.Override
+
+	flags := cmd.Flags()
+	for _, f := range []struct{ flag, key string }{
+		{"format", "defaults.format"},
+		{"workspace", "defaults.workspace"},
+		{"agent-id", "defaults.agent_id"},
+	} {
+		if flags.Changed(f.flag) {
+			value, _ := flags.GetString(f.flag)
+			overrides = append(overrides, config.Override{Key: f.key, Value: value, Source: config.SourceFlag, Origin: "--" + f.flag})
+		}
+	}
+	if value := os.Getenv("BACKLOG_AGENT_ID"); value != "" {
+		overrides = append(overrides, config.Override{Key: "defaults.agent_id", Value: value, Source: config.SourceEnv, Origin: "BACKLOG_AGENT_ID"})
+	}
+
+	// Credentials are listed so it's clear which one is used, but never shown
+	backends := make(map[string]bool)
+	for _, ws := range config.Get().Workspaces {
+		backends[ws.Backend] = true
+	}
+	creds := credentials.Get()
+	if backends["github"] {
+		switch {
+		case os.Getenv("GITHUB_TOKEN") != "":
+			overrides = append(overrides, config.Override{Key: "credentials.github.token", Value: os.Getenv("GITHUB_TOKEN"), Source: config.SourceEnv, Origin: "GITHUB_TOKEN"})
+		case creds != nil && creds.GitHub != nil && creds.GitHub.Token != "":
+			overrides = append(overrides, config.Override{Key: "credentials.github.token", Value: creds.GitHub.Token, Source: config.SourceFile, Origin: credentials.CredentialsFilePath()})
+		}
+	}
+	if backends["linear"] {
+		switch {
+		case os.Getenv("LINEAR_API_KEY") != "":
+			overrides = append(overrides, config.Override{Key: "credentials.linear.api_key", Value: os.Getenv("LINEAR_API_KEY"), Source: config.SourceEnv, Origin: "LINEAR_API_KEY"})
+		case creds != nil && creds.Linear != nil && creds.Linear.APIKey != "":
+			overrides = append(overrides, config.Override{Key: "credentials.linear.api_key", Value: creds.Linear.APIKey, Source: config.SourceFile, Origin: credentials.CredentialsFilePath()})
+		}
+	}
+
+	return overrides
+}
+
 func runConfigHealth() error {
 	// Get backend and connect
 	b, ws, cleanup, err := connectBackend()
@@ -96,3 +217,77 @@ func runConfigHealth() error {
 
 	return nil
 }
+
+// completeSettingKeyArg completes a workspace setting key as the first
+// positional argument.
+func completeSettingKeyArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return config.SettingKeys(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// activeWorkspace returns the workspace selected by --workspace or the
+// config defaults.
+func activeWorkspace() (*config.Workspace, string, error) {
+	ws, name, err := config.GetWorkspace(GetWorkspace())
+	if err != nil {
+		return nil, "", ConfigError(err.Error())
+	}
+	return ws, name, nil
+}
+
+func runConfigGet(key string) error {
+	ws, name, err := activeWorkspace()
+	if err != nil {
+		return err
+	}
+
+	setting, err := config.GetSetting(ws, key)
+	if err != nil {
+		return InvalidInputError(err.Error())
+	}
+
+	if GetFormat() == "json" {
+		formatter := output.New(output.FormatJSON)
+		return formatter.FormatConfigSettings(os.Stdout, name, []config.Setting{setting})
+	}
+
+	// Bare value so scripts can use $(backlog config get key)
+	fmt.Println(setting.Value)
+	return nil
+}
+
+func runConfigSet(key, value string) error {
+	ws, name, err := activeWorkspace()
+	if err != nil {
+		return err
+	}
+
+	path := config.ConfigFilePath()
+	if path == "" {
+		return ConfigError("no config file found; run 'backlog init' first")
+	}
+
+	value, err = config.ValidateSetting(ws, key, value)
+	if err != nil {
+		return InvalidInputError(err.Error())
+	}
+
+	if err := config.SetWorkspaceValue(path, name, key, value); err != nil {
+		return WrapExitCodeError(ExitConfigError, "failed to update config", err)
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatConfigSettings(statusOutput(), name, []config.Setting{{Key: key, Value: value}})
+}
+
+func runConfigList() error {
+	ws, name, err := activeWorkspace()
+	if err != nil {
+		return err
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatConfigSettings(os.Stdout, name, config.Settings(ws))
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// assumeYes is set by --yes on commands that confirm before destroying
+// something.
+var assumeYes bool
+
+// addYesFlag registers --yes/-y on a destructive command.
+func addYesFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Skip the confirmation prompt")
+}
+
+// confirm asks on the terminal before a destructive change, returning a
+// DeclinedError unless the answer is yes. --yes and --dry-run skip the
+// prompt. It never prompts without a terminal on stdin or when the output
+// is JSON, since nobody may be there to answer; those callers get a
+// ConfirmationRequiredError and have to pass --yes.
+func confirm(prompt string) error {
+	if assumeYes || IsDryRun() {
+		return nil
+	}
+	if GetFormat() == "json" || !isTerminal(os.Stdin) {
+		return ConfirmationRequiredError()
+	}
+
+	ok, err := promptYesNo(prompt)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return DeclinedError("cancelled")
+	}
+	return nil
+}
+
+// promptYesNo writes prompt to stderr and reads a y/N answer from stdin.
+// Anything but "y" or "yes" is no.
+func promptYesNo(prompt string) (bool, error) {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", prompt)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
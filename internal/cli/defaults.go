@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/alexbrand/backlog/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// noDefaults disables command_defaults for one invocation.
+var noDefaults bool
+
+// addNoDefaultsFlag registers --no-defaults on a command that reads
+// command_defaults.
+func addNoDefaultsFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&noDefaults, "no-defaults", false, "Ignore command_defaults from the config for this invocation")
+}
+
+// applyCommandDefaults sets the flags configured under
+// command_defaults.<command> that were not given on the command line.
+// Explicit flags always win: a list given as a flag replaces the default
+// list rather than adding to it.
+func applyCommandDefaults(cmd *cobra.Command) error {
+	if noDefaults {
+		return nil
+	}
+	for _, d := range config.CommandDefaults(cmd.Name()) {
+		flag := cmd.Flags().Lookup(d.Flag)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		if err := flag.Value.Set(d.Value); err != nil {
+			return ConfigError(fmt.Sprintf("invalid command_defaults.%s.%s: %v", cmd.Name(), d.Key, err))
+		}
+		slog.Debug("applied command default", "command", cmd.Name(), "key", d.Key, "value", d.Value)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/alexbrand/backlog/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func TestApplyCommandDefaults(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := `version: 1
+command_defaults:
+  list:
+    labels: [team:infra]
+    priority: [urgent, high]
+    limit: 5
+`
+	if err := os.WriteFile(cfgPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if err := config.Init(cfgPath); err != nil {
+		t.Fatalf("config.Init failed: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		args         []string
+		wantLabels   []string
+		wantPriority []string
+		wantLimit    int
+	}{
+		{
+			name:         "defaults fill unset flags",
+			wantLabels:   []string{"team:infra"},
+			wantPriority: []string{"urgent", "high"},
+			wantLimit:    5,
+		},
+		{
+			// An explicit flag replaces the default; it does not append to it.
+			name:         "explicit flag replaces default",
+			args:         []string{"--label", "bug", "--limit", "0"},
+			wantLabels:   []string{"bug"},
+			wantPriority: []string{"urgent", "high"},
+			wantLimit:    0,
+		},
+		{
+			name:      "no-defaults skips all defaults",
+			args:      []string{"--no-defaults"},
+			wantLimit: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var labels, priority []string
+			var limit int
+			noDefaults = false
+			t.Cleanup(func() { noDefaults = false })
+
+			cmd := &cobra.Command{Use: "list"}
+			cmd.Flags().StringSliceVarP(&labels, "label", "l", nil, "")
+			cmd.Flags().StringSliceVarP(&priority, "priority", "p", nil, "")
+			cmd.Flags().IntVar(&limit, "limit", 0, "")
+			addNoDefaultsFlag(cmd)
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("ParseFlags failed: %v", err)
+			}
+
+			if err := applyCommandDefaults(cmd); err != nil {
+				t.Fatalf("applyCommandDefaults failed: %v", err)
+			}
+			if !reflect.DeepEqual(labels, tt.wantLabels) {
+				t.Errorf("labels = %v, want %v", labels, tt.wantLabels)
+			}
+			if !reflect.DeepEqual(priority, tt.wantPriority) {
+				t.Errorf("priority = %v, want %v", priority, tt.wantPriority)
+			}
+			if limit != tt.wantLimit {
+				t.Errorf("limit = %d, want %d", limit, tt.wantLimit)
+			}
+		})
+	}
+}
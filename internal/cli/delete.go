@@ -1,53 +1,93 @@
 package cli
 
 import (
-	"os"
-	"strings"
+	"fmt"
 
+	"github.com/alexbrand/backlog/internal/backend"
 	"github.com/alexbrand/backlog/internal/output"
 	"github.com/spf13/cobra"
 )
 
+var deleteSoft bool
+
 var deleteCmd = &cobra.Command{
 	Use:   "delete <id>",
 	Short: "Delete a task",
 	Long: `Remove a task from the backlog permanently.
 
 This operation cannot be undone. The task file will be deleted from the
-filesystem.
+filesystem. On a terminal, delete asks for confirmation first; pass --yes
+to skip the prompt. Without a terminal, or with -f json, delete fails
+unless --yes is given.
+
+With --soft, the task is set aside instead: the local backend moves it into
+.backlog/.trash/ stamped with deleted_at, and the Linear backend archives it.
+Soft-deleted tasks are hidden from list and show (see list --include-deleted)
+and can be brought back with "backlog restore", so they need no confirmation.
 
 Examples:
   backlog delete 001
-  backlog delete 001 -f json`,
-	Args: cobra.ExactArgs(1),
+  backlog delete 001 --yes
+  backlog delete 001 --soft
+  backlog delete 001 --yes -f json`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTaskIDArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runDelete(args[0])
+		return runDelete(args[0], deleteSoft)
 	},
 }
 
 func init() {
+	deleteCmd.Flags().BoolVar(&deleteSoft, "soft", false, "Move the task to the trash so it can be restored")
+	addYesFlag(deleteCmd)
 	rootCmd.AddCommand(deleteCmd)
 }
 
-func runDelete(id string) error {
+func runDelete(id string, soft bool) error {
 	// Get backend and connect
-	b, _, cleanup, err := connectBackend()
+	b, _, cleanup, err := connectWritableBackend()
 	if err != nil {
 		return err
 	}
 	defer cleanup()
 
+	deleteTask := b.Delete
+	if soft {
+		softDeleter, ok := b.(backend.SoftDeleter)
+		if !ok {
+			return UnsupportedError(b, "soft delete")
+		}
+		deleteTask = softDeleter.SoftDelete
+	}
+
+	if IsDryRun() {
+		return previewDelete(b, id, soft)
+	}
+
+	// A hard delete can't be taken back, so make sure the task exists
+	// before asking
+	if !soft {
+		task, err := b.Get(id)
+		if err != nil {
+			if isNotFound(err) {
+				return NotFoundError(err.Error()).WithCause(err)
+			}
+			return err
+		}
+		if err := confirm(fmt.Sprintf("Delete task %s %q?", task.ID, task.Title)); err != nil {
+			return err
+		}
+	}
+
 	// Delete the task
-	if err := b.Delete(id); err != nil {
-		// Check if this is a "not found" error (case-insensitive check for 404/Not Found)
-		errLower := strings.ToLower(err.Error())
-		if strings.Contains(errLower, "not found") || strings.Contains(errLower, "404") {
-			return NotFoundError(err.Error())
+	if err := deleteTask(id); err != nil {
+		if isNotFound(err) {
+			return NotFoundError(err.Error()).WithCause(err)
 		}
 		return err
 	}
 
 	// Output the result
 	formatter := output.New(output.Format(GetFormat()))
-	return formatter.FormatDeleted(os.Stdout, id)
+	return formatter.FormatDeleted(statusOutput(), id)
 }
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// stdinDescription is the --description value that reads the description from stdin.
+const stdinDescription = "-"
+
+// resolveDescription returns the task description given on the command line
+// and whether one was provided. The description comes from exactly one of the
+// inline --description flag, stdin (--description -), or a file
+// (--description-file). Content read from stdin or a file is returned verbatim.
+func resolveDescription(cmd *cobra.Command, inline, file string) (string, bool, error) {
+	if inline != "" && file != "" {
+		return "", false, InvalidInputError("--description and --description-file cannot be used together")
+	}
+
+	if file != "" {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read description file: %w", err)
+		}
+		return string(content), true, nil
+	}
+
+	if inline == stdinDescription {
+		content, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read description from stdin: %w", err)
+		}
+		return string(content), true, nil
+	}
+
+	return inline, inline != "", nil
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/local"
+	"github.com/alexbrand/backlog/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	doctorFix          bool
+	doctorStatusSource string
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the local workspace for integrity problems",
+	Long: `Check the local workspace for integrity problems.
+
+The doctor reports:
+  duplicate_id       two task files share the same ID
+  status_mismatch    a status field in the frontmatter disagrees with the directory
+  unparseable        a task file cannot be parsed
+  orphaned_lock      a lock file refers to a task that does not exist
+  dangling_relation  blocks/blocked_by refers to a task that does not exist
+  filename_mismatch  the filename does not start with the task ID
+
+Each finding has a severity (error or warning) and a suggested fix.
+
+With --fix, safe repairs are applied: status mismatches are resolved,
+orphaned locks are removed, and dangling relations are pruned. By default
+the frontmatter status is rewritten to match the directory; use
+--status-source=frontmatter to move the file to match the frontmatter
+instead. Duplicate IDs, unparseable files, and filename mismatches always
+need a human decision. With git_sync enabled, repairs are recorded as a
+single "doctor:" commit. When there are problems to repair, --fix asks for
+confirmation on a terminal first; pass --yes to skip the prompt. Without a
+terminal, or with -f json, it fails unless --yes is given.
+
+Exits with code 1 if any problems remain, so CI can gate on a clean run.
+
+Examples:
+  backlog doctor
+  backlog doctor --fix
+  backlog doctor --fix --yes
+  backlog doctor --fix --status-source=frontmatter
+  backlog doctor -f json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDoctor(doctorFix, doctorStatusSource)
+	},
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Apply safe repairs")
+	doctorCmd.Flags().StringVar(&doctorStatusSource, "status-source", string(backend.StatusSourceDirectory), "Which side wins for status mismatches: directory or frontmatter")
+	addYesFlag(doctorCmd)
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(fix bool, statusSource string) error {
+	source := backend.StatusSource(statusSource)
+	if source != backend.StatusSourceDirectory && source != backend.StatusSourceFrontmatter {
+		return InvalidInputError(fmt.Sprintf("invalid --status-source %q (valid: directory, frontmatter)", statusSource))
+	}
+
+	// Get backend and connect; only --fix changes anything
+	connect := connectBackend
+	if fix {
+		connect = connectWritableBackend
+	}
+	b, _, cleanup, err := connect()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	// Check if backend supports diagnostics
+	diagnoser, ok := b.(backend.Diagnoser)
+	if !ok {
+		return UnsupportedError(b, "doctor")
+	}
+
+	// Ask before repairing, unless a first scan finds nothing to repair
+	if fix && !assumeYes {
+		report, err := diagnoser.Diagnose(backend.DoctorOptions{StatusSource: source})
+		if err != nil {
+			return err
+		}
+		if len(report.Findings) == 0 {
+			fix = false
+		} else if err := confirm(fmt.Sprintf("Repair the %d problem(s) found where safe?", len(report.Findings))); err != nil {
+			return err
+		}
+	}
+
+	report, err := diagnoser.Diagnose(backend.DoctorOptions{Fix: fix, StatusSource: source})
+	if err != nil {
+		// Check for uncommitted changes error (exit code 1)
+		if _, ok := err.(*local.UncommittedChangesError); ok {
+			return GeneralError(err.Error()).WithCause(err)
+		}
+		// Check for sync conflict error (exit code 2)
+		if _, ok := err.(*local.SyncConflictError); ok {
+			return ConflictError(err.Error()).WithCause(err)
+		}
+		return err
+	}
+
+	// Output the result
+	formatter := output.New(output.Format(GetFormat()))
+	if err := formatter.FormatDoctorReport(os.Stdout, report); err != nil {
+		return err
+	}
+
+	if !report.OK {
+		return SilentError(ExitError, fmt.Sprintf("%d problem(s) remaining", len(report.Findings)-report.Fixed))
+	}
+	return nil
+}
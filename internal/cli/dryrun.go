@@ -0,0 +1,321 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/config"
+	"github.com/alexbrand/backlog/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// dryRunCommands lists the commands that honor --dry-run. Read-only commands
+// are included since they have nothing to skip; any other command refuses the
+// flag rather than silently making changes.
+var dryRunCommands = map[string]bool{
+	"backlog add":                    true,
+	"backlog edit":                   true,
+	"backlog move":                   true,
+	"backlog claim":                  true,
+	"backlog release":                true,
+	"backlog delete":                 true,
+	"backlog reorder":                true,
+	"backlog check":                  true,
+	"backlog uncheck":                true,
+	"backlog list":                   true,
+	"backlog show":                   true,
+	"backlog history":                true,
+	"backlog blame":                  true,
+	"backlog next":                   true,
+	"backlog comment list":           true,
+	"backlog label list":             true,
+	"backlog time":                   true,
+	"backlog changelog":              true,
+	"backlog verify-clean":           true,
+	"backlog whoami":                 true,
+	"backlog ping":                   true,
+	"backlog capabilities":           true,
+	"backlog workspace capabilities": true,
+	"backlog schema":                 true,
+	"backlog auth list":              true,
+	"backlog config show":            true,
+	"backlog config get":             true,
+	"backlog config list":            true,
+	"backlog config health":          true,
+	"backlog template list":          true,
+	"backlog version":                true,
+	"backlog completion":             true,
+	"backlog help":                   true,
+	"backlog __complete":             true,
+	"backlog __completeNoDesc":       true,
+}
+
+// checkDryRun rejects --dry-run for commands that can't preview their changes.
+func checkDryRun(cmd *cobra.Command) error {
+	if !dryRun || dryRunCommands[cmd.CommandPath()] {
+		return nil
+	}
+	// sync --status only reports divergence
+	if cmd == syncCmd && syncStatus {
+		return nil
+	}
+	return InvalidInputError(fmt.Sprintf("--dry-run is not supported by %q", cmd.CommandPath()))
+}
+
+// printDryRun outputs the change a command would make. A notice goes to
+// stderr so the output on stdout keeps a stable shape for scripts.
+func printDryRun(result *backend.DryRunResult) error {
+	fmt.Fprintln(os.Stderr, "dry run: no changes were made")
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatDryRun(os.Stdout, result)
+}
+
+// getTaskForPreview fetches a task, mapping lookup failures to NotFoundError
+// like the real commands do.
+func getTaskForPreview(b backend.Backend, id string) (*backend.Task, error) {
+	task, err := b.Get(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, NotFoundError(err.Error()).WithCause(err)
+		}
+		return nil, err
+	}
+	return task, nil
+}
+
+// previewAdd builds the task that Create would make from input, or reports
+// the task an --if-absent add would match. The ID is left empty unless
+// pinned, since only the backend can assign one. With claimAgent, the task is
+// shown as claimed by that agent.
+func previewAdd(b backend.Backend, input backend.TaskInput, claimAgent string) error {
+	if input.Parent != "" {
+		if _, err := b.Get(input.Parent); err != nil {
+			return fmt.Errorf("failed to create task: parent task not found: %s", input.Parent)
+		}
+	}
+
+	if input.IfAbsent {
+		existing, err := b.List(backend.TaskFilters{})
+		if err != nil {
+			return err
+		}
+		if match := backend.FindExisting(existing.Tasks, input); match != nil {
+			return printDryRun(&backend.DryRunResult{Action: "add", Task: match, Detail: "a matching task exists, so nothing would be created"})
+		}
+	}
+
+	now := time.Now().UTC()
+	task := &backend.Task{
+		ID:          input.ID,
+		Title:       input.Title,
+		Description: input.Description,
+		Status:      input.Status,
+		Priority:    input.Priority,
+		Labels:      input.Labels,
+		Assignee:    input.Assignee,
+		Parent:      input.Parent,
+		Created:     now,
+		Updated:     now,
+	}
+	if task.Status == "" {
+		task.Status = backend.StatusBacklog
+	}
+	if task.Priority == "" {
+		task.Priority = backend.PriorityNone
+	}
+	result := &backend.DryRunResult{Action: "add", Task: task, ToStatus: task.Status}
+	if claimAgent != "" {
+		task.Status = backend.StatusInProgress
+		task.Assignee = claimAgent
+		result.ToStatus = task.Status
+		result.Detail = fmt.Sprintf("would be claimed by agent %s", claimAgent)
+	}
+	return printDryRun(result)
+}
+
+// previewEdit applies changes to a copy of the task.
+func previewEdit(b backend.Backend, id string, changes backend.TaskChanges) error {
+	task, err := getTaskForPreview(b, id)
+	if err != nil {
+		return err
+	}
+
+	if changes.Title != nil {
+		task.Title = *changes.Title
+	}
+	if changes.Description != nil {
+		task.Description = *changes.Description
+		backend.SetChecklist(task)
+	}
+	if changes.Priority != nil {
+		task.Priority = *changes.Priority
+	}
+	if changes.Assignee != nil {
+		task.Assignee = *changes.Assignee
+	}
+	if changes.Parent != nil {
+		if *changes.Parent == task.ID {
+			return fmt.Errorf("task %s cannot be a sub-task of itself or its own sub-tasks", task.ID)
+		}
+		task.Parent = *changes.Parent
+	}
+	task.Labels = editLabels(task.Labels, changes.AddLabels, changes.RemoveLabels)
+	task.Updated = time.Now().UTC()
+
+	return printDryRun(&backend.DryRunResult{Action: "edit", Task: task})
+}
+
+// editLabels returns labels with add appended and remove dropped.
+func editLabels(labels, add, remove []string) []string {
+	removed := make(map[string]bool)
+	for _, label := range remove {
+		removed[label] = true
+	}
+	var result []string
+	for _, label := range mergeLabels(labels, add) {
+		if !removed[label] {
+			result = append(result, label)
+		}
+	}
+	return result
+}
+
+// previewMove reports the status transition a move would make.
+func previewMove(task *backend.Task, status backend.Status) error {
+	oldStatus := task.Status
+	task.Status = status
+	return printDryRun(&backend.DryRunResult{Action: "move", Task: task, FromStatus: oldStatus, ToStatus: status})
+}
+
+// previewClaim reports the claim that would be made, refusing tasks that
+// carry another agent's label as the backends do unless steal is set.
+func previewClaim(b backend.Backend, ws *config.Workspace, id, agent string, steal bool) error {
+	task, err := getTaskForPreview(b, id)
+	if err != nil {
+		return err
+	}
+
+	prefix := agentLabelPrefix(ws) + ":"
+	ownLabel := prefix + agent
+	claimedBy := ""
+	var labels []string
+	for _, label := range task.Labels {
+		if label == ownLabel {
+			return printDryRun(&backend.DryRunResult{
+				Action: "claim", Task: task, FromStatus: task.Status, ToStatus: task.Status,
+				Detail: fmt.Sprintf("already claimed by agent %s", agent),
+			})
+		}
+		if strings.HasPrefix(label, prefix) {
+			if !steal {
+				err := ConflictError(fmt.Sprintf("conflict: task %s is already claimed by agent %s", task.ID, strings.TrimPrefix(label, prefix)))
+				err.ErrorCode = ErrorCodeClaimConflict
+				return err
+			}
+			claimedBy = strings.TrimPrefix(label, prefix)
+			continue
+		}
+		labels = append(labels, label)
+	}
+
+	detail := fmt.Sprintf("agent %s", agent)
+	if claimedBy != "" {
+		detail += fmt.Sprintf(", stolen from agent %s", claimedBy)
+	}
+	oldStatus := task.Status
+	task.Status = backend.StatusInProgress
+	task.Labels = append(labels, ownLabel)
+	return printDryRun(&backend.DryRunResult{
+		Action: "claim", Task: task, FromStatus: oldStatus, ToStatus: task.Status,
+		Detail: detail,
+	})
+}
+
+// previewRelease reports the release that would be made. Only a task
+// claimed by this agent can be released, unless force is set.
+func previewRelease(b backend.Backend, ws *config.Workspace, id, agent string, force bool) error {
+	task, err := getTaskForPreview(b, id)
+	if err != nil {
+		return err
+	}
+
+	prefix := agentLabelPrefix(ws) + ":"
+	claimedBy := ""
+	var labels []string
+	for _, label := range task.Labels {
+		if strings.HasPrefix(label, prefix) {
+			claimedBy = strings.TrimPrefix(label, prefix)
+			continue
+		}
+		labels = append(labels, label)
+	}
+	var conflict *ExitCodeError
+	switch {
+	case claimedBy == "":
+		conflict = ConflictError(fmt.Sprintf("task %s is not claimed", task.ID))
+	case claimedBy != agent && !force:
+		conflict = ConflictError(fmt.Sprintf("task %s is claimed by agent %s, not %s", task.ID, claimedBy, agent))
+	}
+	if conflict != nil {
+		conflict.ErrorCode = ErrorCodeReleaseConflict
+		return conflict
+	}
+
+	oldStatus := task.Status
+	task.Status = backend.StatusTodo
+	task.Labels = labels
+	return printDryRun(&backend.DryRunResult{Action: "release", Task: task, FromStatus: oldStatus, ToStatus: task.Status})
+}
+
+// previewDelete reports the task that would be deleted.
+func previewDelete(b backend.Backend, id string, soft bool) error {
+	task, err := getTaskForPreview(b, id)
+	if err != nil {
+		return err
+	}
+	result := &backend.DryRunResult{Action: "delete", Task: task}
+	if soft {
+		result.Detail = "soft delete, restorable with 'backlog restore'"
+	}
+	return printDryRun(result)
+}
+
+// previewReorder reports where the task would be placed.
+func previewReorder(b backend.Backend, id string, position backend.ReorderPosition) error {
+	task, err := getTaskForPreview(b, id)
+	if err != nil {
+		return err
+	}
+
+	var detail string
+	switch {
+	case position.BeforeID != "":
+		if _, err := getTaskForPreview(b, position.BeforeID); err != nil {
+			return err
+		}
+		detail = "before " + position.BeforeID
+	case position.AfterID != "":
+		if _, err := getTaskForPreview(b, position.AfterID); err != nil {
+			return err
+		}
+		detail = "after " + position.AfterID
+	case position.First:
+		detail = "first"
+	case position.Position != 0:
+		detail = fmt.Sprintf("to position %d", position.Position)
+	default:
+		detail = "last"
+	}
+	return printDryRun(&backend.DryRunResult{Action: "reorder", Task: task, Detail: detail})
+}
+
+// agentLabelPrefix returns the workspace's agent label prefix.
+func agentLabelPrefix(ws *config.Workspace) string {
+	if ws != nil && ws.AgentLabelPrefix != "" {
+		return ws.AgentLabelPrefix
+	}
+	return "agent"
+}
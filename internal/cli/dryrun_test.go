@@ -0,0 +1,19 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDryRunCommandsExist(t *testing.T) {
+	for path := range dryRunCommands {
+		if strings.HasPrefix(path, "backlog __complete") || path == "backlog help" {
+			continue // added by cobra when the command runs
+		}
+		args := strings.Fields(path)[1:]
+		cmd, rest, err := rootCmd.Find(args)
+		if err != nil || len(rest) != 0 || cmd.CommandPath() != path {
+			t.Errorf("dryRunCommands lists %q, which is not a command", path)
+		}
+	}
+}
@@ -3,7 +3,6 @@ package cli
 import (
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/alexbrand/backlog/internal/backend"
 	"github.com/alexbrand/backlog/internal/output"
@@ -14,10 +13,13 @@ var (
 	editTitle       string
 	editPriority    string
 	editDescription string
+	editDescFile    string
 	editAddLabels   []string
 	editRemoveLabel []string
 	editBlocks      []string
 	editBlockedBy   []string
+	editParent      string
+	editInteractive bool
 )
 
 var editCmd = &cobra.Command{
@@ -25,17 +27,57 @@ var editCmd = &cobra.Command{
 	Short: "Modify task fields",
 	Long: `Edit an existing task's fields.
 
-You can update the title, priority, description, and labels using the
-available flags. Only the fields you specify will be changed.
+You can update the title, priority, description, labels, and parent task
+using the available flags. Only the fields you specify will be changed.
+Use --parent="" to make a sub-task top-level again.
+
+--add-label and --remove-label can be repeated. Labels are a set: adding a
+label the task already has, or removing one it doesn't have, changes nothing
+and is not an error.
+
+The output lists each field that changed, such as "priority: medium → high"
+or "labels: +infra"; JSON output has them under "changes" with before and
+after values. An edit that changes nothing reports "no changes".
+
+With --interactive, or when no flags are given and stdin is a terminal, the
+task opens in $VISUAL or $EDITOR as a markdown file with YAML frontmatter
+(the local backend's task file format, for every backend). Changes to the
+title, priority, assignee, labels, and description are applied on save.
+Saving the file unchanged or empty makes no changes.
 
 Examples:
   backlog edit 001 --title="New title"
   backlog edit 001 --priority=urgent
   backlog edit 001 --add-label=blocked --remove-label=ready
-  backlog edit 001 --description="Updated description"`,
-	Args: cobra.ExactArgs(1),
+  backlog edit 001 --add-label=api --add-label=backend
+  backlog edit 001 --description="Updated description"
+  backlog edit 001 --description-file=./notes.md
+  backlog edit 013 --parent 012
+  backlog edit 001 --interactive
+  generate-notes | backlog edit 001 --description -`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTaskIDArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runEdit(args[0])
+		if editInteractive && hasEditFlags(cmd) {
+			return InvalidInputError("--interactive cannot be combined with other edit flags")
+		}
+		if editInteractive || (!hasEditFlags(cmd) && isTerminal(os.Stdin)) {
+			return runEditInteractive(args[0])
+		}
+
+		description, hasDescription, err := resolveDescription(cmd, editDescription, editDescFile)
+		if err != nil {
+			return err
+		}
+		var descPtr *string
+		if hasDescription {
+			descPtr = &description
+		}
+		var parentPtr *string
+		if cmd.Flags().Changed("parent") {
+			parentPtr = &editParent
+		}
+		return runEdit(args[0], descPtr, parentPtr)
 	},
 }
 
@@ -44,16 +86,39 @@ func init() {
 
 	editCmd.Flags().StringVarP(&editTitle, "title", "t", "", "New title for the task")
 	editCmd.Flags().StringVarP(&editPriority, "priority", "p", "", "New priority: urgent, high, medium, low, none")
-	editCmd.Flags().StringVarP(&editDescription, "description", "d", "", "New description for the task")
-	editCmd.Flags().StringSliceVar(&editAddLabels, "add-label", nil, "Labels to add (can be specified multiple times)")
-	editCmd.Flags().StringSliceVar(&editRemoveLabel, "remove-label", nil, "Labels to remove (can be specified multiple times)")
+	editCmd.Flags().StringVarP(&editDescription, "description", "d", "", "New description for the task (use - to read from stdin)")
+	editCmd.Flags().StringVar(&editDescFile, "description-file", "", "Read new description from file")
+	editCmd.Flags().StringSliceVar(&editAddLabels, "add-label", nil, "Label to add; repeatable, and a label already on the task is left as is")
+	editCmd.Flags().StringSliceVar(&editRemoveLabel, "remove-label", nil, "Label to remove; repeatable, and a label not on the task is ignored")
 	editCmd.Flags().StringSliceVar(&editBlocks, "blocks", nil, "Task IDs that this task blocks")
 	editCmd.Flags().StringSliceVar(&editBlockedBy, "blocked-by", nil, "Task IDs that block this task")
+	editCmd.Flags().StringVar(&editParent, "parent", "", "Make the task a sub-task of this task ID (empty to clear)")
+	editCmd.Flags().BoolVarP(&editInteractive, "interactive", "i", false, "Edit the task in $VISUAL or $EDITOR")
+
+	editCmd.RegisterFlagCompletionFunc("priority", completePriorities)
+	editCmd.RegisterFlagCompletionFunc("add-label", completeLabels)
+	editCmd.RegisterFlagCompletionFunc("remove-label", completeLabels)
+	editCmd.RegisterFlagCompletionFunc("blocks", completeTaskIDs)
+	editCmd.RegisterFlagCompletionFunc("blocked-by", completeTaskIDs)
+	editCmd.RegisterFlagCompletionFunc("parent", completeTaskIDs)
+}
+
+// editFieldFlags are the flags that change a task directly.
+var editFieldFlags = []string{"title", "priority", "description", "description-file", "add-label", "remove-label", "blocks", "blocked-by", "parent"}
+
+// hasEditFlags reports whether any flag that changes a task was given.
+func hasEditFlags(cmd *cobra.Command) bool {
+	for _, name := range editFieldFlags {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
 }
 
-func runEdit(id string) error {
+func runEdit(id string, description, parent *string) error {
 	// Check if any changes were specified
-	if editTitle == "" && editPriority == "" && editDescription == "" &&
+	if editTitle == "" && editPriority == "" && description == nil && parent == nil &&
 		len(editAddLabels) == 0 && len(editRemoveLabel) == 0 &&
 		len(editBlocks) == 0 && len(editBlockedBy) == 0 {
 		return fmt.Errorf("no changes specified")
@@ -62,23 +127,29 @@ func runEdit(id string) error {
 	// Validate priority if specified
 	var priority *backend.Priority
 	if editPriority != "" {
-		p := backend.Priority(editPriority)
-		if !p.IsValid() {
-			return InvalidInputError(fmt.Sprintf("invalid priority %q (valid: urgent, high, medium, low, none)", editPriority))
+		p, err := parsePriority(editPriority)
+		if err != nil {
+			return err
 		}
 		priority = &p
 	}
 
 	// Get backend and connect
-	b, _, cleanup, err := connectBackend()
+	b, ws, cleanup, err := connectWritableBackend()
 	if err != nil {
 		return err
 	}
 	defer cleanup()
 
+	if err := warnUnknownLabels(b, ws, editAddLabels); err != nil {
+		return err
+	}
+
 	// Build the changes struct
 	changes := backend.TaskChanges{
 		Priority:     priority,
+		Description:  description,
+		Parent:       parent,
 		AddLabels:    editAddLabels,
 		RemoveLabels: editRemoveLabel,
 	}
@@ -87,31 +158,29 @@ func runEdit(id string) error {
 		changes.Title = &editTitle
 	}
 
-	if editDescription != "" {
-		changes.Description = &editDescription
+	if IsDryRun() {
+		return previewEdit(b, id, changes)
 	}
 
 	// Only call Update if there are non-relation changes
-	hasFieldChanges := editTitle != "" || editPriority != "" || editDescription != "" ||
+	hasFieldChanges := editTitle != "" || editPriority != "" || description != nil || parent != nil ||
 		len(editAddLabels) > 0 || len(editRemoveLabel) > 0
 
-	var task *backend.Task
+	// Get the task first so the output can show what changed
+	before, err := b.Get(id)
+	if err != nil {
+		if isNotFound(err) {
+			return NotFoundError(err.Error()).WithCause(err)
+		}
+		return err
+	}
+
+	task := before
 	if hasFieldChanges {
 		task, err = b.Update(id, changes)
 		if err != nil {
-			errLower := strings.ToLower(err.Error())
-			if strings.Contains(errLower, "not found") || strings.Contains(errLower, "404") {
-				return NotFoundError(err.Error())
-			}
-			return err
-		}
-	} else {
-		// Still need to get the task for output
-		task, err = b.Get(id)
-		if err != nil {
-			errLower := strings.ToLower(err.Error())
-			if strings.Contains(errLower, "not found") || strings.Contains(errLower, "404") {
-				return NotFoundError(err.Error())
+			if isNotFound(err) {
+				return NotFoundError(err.Error()).WithCause(err)
 			}
 			return err
 		}
@@ -121,7 +190,7 @@ func runEdit(id string) error {
 	if len(editBlocks) > 0 || len(editBlockedBy) > 0 {
 		relater, ok := b.(backend.Relater)
 		if !ok {
-			return fmt.Errorf("backend %q does not support task dependencies", b.Name())
+			return UnsupportedError(b, "task dependencies")
 		}
 		for _, targetID := range editBlocks {
 			if _, err := relater.Link(id, targetID, backend.RelationBlocks); err != nil {
@@ -137,5 +206,5 @@ func runEdit(id string) error {
 
 	// Output the result
 	formatter := output.New(output.Format(GetFormat()))
-	return formatter.FormatUpdated(os.Stdout, task)
+	return formatter.FormatUpdated(statusOutput(), task, backend.DiffTasks(before, task))
 }
@@ -1,11 +1,20 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/config"
+	"github.com/alexbrand/backlog/internal/execbackend"
+	"github.com/alexbrand/backlog/internal/github"
+	"github.com/alexbrand/backlog/internal/linear"
+	"github.com/alexbrand/backlog/internal/local"
 	"github.com/alexbrand/backlog/internal/output"
+	"github.com/alexbrand/backlog/internal/template"
 )
 
 // Exit codes as defined in the PRD
@@ -15,14 +24,22 @@ const (
 	ExitConflict     = 2 // Conflict (task already claimed, state conflict)
 	ExitNotFound     = 3 // Not found (task doesn't exist)
 	ExitConfigError  = 4 // Configuration error
+	ExitReadOnly     = 5 // Workspace is read-only
+	ExitDeclined     = 6 // Confirmation prompt answered no
+	ExitTimeout      = 7 // next --wait timed out before a task became available
+	ExitInterrupted  = 130 // Interrupted by SIGINT or SIGTERM while waiting
 )
 
 // ExitError is an error that carries an exit code.
 type ExitCodeError struct {
-	Code     int
-	JSONCode string // Optional specific error code for JSON output (e.g., "INVALID_INPUT")
-	Message  string
-	Err      error
+	Code      int
+	JSONCode  string // Optional specific error code for JSON output (e.g., "INVALID_INPUT")
+	Message   string
+	Err       error
+	Silent    bool           // The command already reported the failure; only set the exit code
+	Details   map[string]any // Optional structured context for JSON output
+	ErrorCode string         // Optional error_code for JSON output; derived from Cause when empty
+	Cause     error          // Backend error the message came from; used for error_code, not printed
 }
 
 func (e *ExitCodeError) Error() string {
@@ -33,7 +50,17 @@ func (e *ExitCodeError) Error() string {
 }
 
 func (e *ExitCodeError) Unwrap() error {
-	return e.Err
+	if e.Err != nil {
+		return e.Err
+	}
+	return e.Cause
+}
+
+// WithCause records the backend error behind e so that GetErrorCode can
+// report its specific error code. The message is unchanged.
+func (e *ExitCodeError) WithCause(err error) *ExitCodeError {
+	e.Cause = err
+	return e
 }
 
 // NewExitCodeError creates a new ExitCodeError with the given code and message.
@@ -61,6 +88,38 @@ func ConfigError(message string) *ExitCodeError {
 	return NewExitCodeError(ExitConfigError, message)
 }
 
+// ReadOnlyError reports a write to a read-only workspace (exit code 5).
+func ReadOnlyError(workspace string) *ExitCodeError {
+	return NewExitCodeError(ExitReadOnly, fmt.Sprintf(
+		"workspace '%s' is read-only; pass --override-read-only or set %s=1 to write to it anyway", workspace, allowWriteEnv))
+}
+
+// DeclinedError reports a confirmation prompt answered no (exit code 6).
+func DeclinedError(message string) *ExitCodeError {
+	return NewExitCodeError(ExitDeclined, message)
+}
+
+// TimeoutError reports a wait that ended without a result (exit code 7).
+func TimeoutError(message string) *ExitCodeError {
+	return NewExitCodeError(ExitTimeout, message)
+}
+
+// InterruptedError reports a wait cut short by a signal (exit code 130).
+func InterruptedError(sig os.Signal) *ExitCodeError {
+	return NewExitCodeError(ExitInterrupted, fmt.Sprintf("interrupted by signal: %s", sig))
+}
+
+// ConfirmationRequiredError reports a destructive command that can't ask for
+// confirmation because there is no terminal, or the output is JSON
+// (exit code 1).
+func ConfirmationRequiredError() *ExitCodeError {
+	return &ExitCodeError{
+		Code:      ExitError,
+		Message:   "confirmation required, pass --yes",
+		ErrorCode: ErrorCodeConfirmRequired,
+	}
+}
+
 // InvalidInputError creates an invalid input error (exit code 1).
 func InvalidInputError(message string) *ExitCodeError {
 	return &ExitCodeError{Code: ExitError, JSONCode: "INVALID_INPUT", Message: message}
@@ -71,6 +130,17 @@ func GeneralError(message string) *ExitCodeError {
 	return &ExitCodeError{Code: ExitError, Message: message}
 }
 
+// UnsupportedError reports that the active backend doesn't implement an
+// optional operation (exit code 1). 'backlog capabilities' lists what a
+// backend supports.
+func UnsupportedError(b backend.Backend, operation string) *ExitCodeError {
+	return &ExitCodeError{
+		Code:      ExitError,
+		Message:   fmt.Sprintf("backend %q does not support %s", b.Name(), operation),
+		ErrorCode: ErrorCodeUnsupported,
+	}
+}
+
 // AuthError creates an authentication error (exit code 1).
 func AuthError(message string) *ExitCodeError {
 	return &ExitCodeError{Code: ExitError, JSONCode: "AUTH_ERROR", Message: message}
@@ -105,8 +175,15 @@ func WrapError(message string, err error) error {
 	return &ExitCodeError{Code: ExitError, Message: message, Err: err}
 }
 
+// SilentError creates an error that sets the exit code without printing a
+// message, for commands whose output already describes the failure.
+func SilentError(code int, message string) *ExitCodeError {
+	return &ExitCodeError{Code: code, Message: message, Silent: true}
+}
+
 // GetExitCode returns the exit code from an error.
-// If the error is an ExitCodeError, returns its code.
+// If the error is an ExitCodeError, returns its code; a backend's
+// *backend.NotFoundError anywhere in the chain returns 3.
 // Otherwise, returns 1 (general error).
 func GetExitCode(err error) int {
 	if err == nil {
@@ -115,9 +192,24 @@ func GetExitCode(err error) int {
 	if exitErr, ok := err.(*ExitCodeError); ok {
 		return exitErr.Code
 	}
+	if backend.IsNotFound(err) {
+		return ExitNotFound
+	}
 	return ExitError
 }
 
+// isNotFound reports whether a backend error means the task doesn't exist.
+// Backends return *backend.NotFoundError for missing tasks; the text check
+// covers API errors they pass through unchanged, such as a GitHub 404 from
+// commenting on an issue that doesn't exist.
+func isNotFound(err error) bool {
+	if backend.IsNotFound(err) {
+		return true
+	}
+	errLower := strings.ToLower(err.Error())
+	return strings.Contains(errLower, "not found") || strings.Contains(errLower, "404")
+}
+
 // ExitCodeToString converts a numeric exit code to a string error code.
 // These codes are used in JSON error output.
 func ExitCodeToString(code int) string {
@@ -132,6 +224,14 @@ func ExitCodeToString(code int) string {
 		return "NOT_FOUND"
 	case ExitConfigError:
 		return "CONFIG_ERROR"
+	case ExitReadOnly:
+		return "READ_ONLY"
+	case ExitDeclined:
+		return "DECLINED"
+	case ExitTimeout:
+		return "TIMEOUT"
+	case ExitInterrupted:
+		return "INTERRUPTED"
 	default:
 		return "ERROR"
 	}
@@ -147,6 +247,74 @@ func GetJSONCode(err error) string {
 	return ExitCodeToString(GetExitCode(err))
 }
 
+// Stable error codes reported as error_code in JSON error output. Unlike
+// code, which follows the exit code, error_code names the specific failure.
+const (
+	ErrorCodeClaimConflict      = "CLAIM_CONFLICT"
+	ErrorCodeReleaseConflict    = "RELEASE_CONFLICT"
+	ErrorCodeSyncConflict       = "SYNC_CONFLICT"
+	ErrorCodeUncommittedChanges = "UNCOMMITTED_CHANGES"
+	ErrorCodeDetachedHead       = "DETACHED_HEAD"
+	ErrorCodeGitTimeout         = "GIT_TIMEOUT"
+	ErrorCodeRateLimited        = "RATE_LIMITED"
+	ErrorCodeParseError         = "PARSE_ERROR"
+	ErrorCodeTemplateNotFound   = "TEMPLATE_NOT_FOUND"
+	ErrorCodeMissingValues      = "MISSING_TEMPLATE_VALUES"
+	ErrorCodeUnsupported        = "UNSUPPORTED"
+	ErrorCodeHandshakeFailed    = "HANDSHAKE_FAILED"
+	ErrorCodeProtocolError      = "PROTOCOL_ERROR"
+	ErrorCodeBackendTimeout     = "BACKEND_TIMEOUT"
+	ErrorCodeBackendExited      = "BACKEND_EXITED"
+	ErrorCodeConfirmRequired    = "CONFIRMATION_REQUIRED"
+)
+
+// GetErrorCode returns the stable error_code for an error. An explicit
+// ErrorCode wins; otherwise known backend errors anywhere in the chain map
+// to their own code, and anything else falls back to the JSON code (NOT_FOUND, CONFLICT, INVALID_INPUT, ...).
+func GetErrorCode(err error) string {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		switch e := e.(type) {
+		case *ExitCodeError:
+			if e.ErrorCode != "" {
+				return e.ErrorCode
+			}
+		case *local.ClaimConflictError, *github.ClaimConflictError, *linear.ClaimConflictError, *execbackend.ClaimConflictError:
+			return ErrorCodeClaimConflict
+		case *local.ReleaseConflictError, *github.ReleaseError, *linear.ReleaseConflictError, *execbackend.ReleaseConflictError:
+			return ErrorCodeReleaseConflict
+		case *local.SyncConflictError, *local.GitPushConflictError:
+			return ErrorCodeSyncConflict
+		case *local.UncommittedChangesError:
+			return ErrorCodeUncommittedChanges
+		case *local.DetachedHeadError:
+			return ErrorCodeDetachedHead
+		case *local.GitTimeoutError:
+			return ErrorCodeGitTimeout
+		case *github.RateLimitError:
+			return ErrorCodeRateLimited
+		case *execbackend.HandshakeError:
+			return ErrorCodeHandshakeFailed
+		case *execbackend.ProtocolError:
+			return ErrorCodeProtocolError
+		case *execbackend.TimeoutError:
+			return ErrorCodeBackendTimeout
+		case *execbackend.ExitError:
+			return ErrorCodeBackendExited
+		case *execbackend.UnsupportedError:
+			return ErrorCodeUnsupported
+		case *local.ParseError:
+			return ErrorCodeParseError
+		case *template.NotFoundError:
+			return ErrorCodeTemplateNotFound
+		case *template.MissingValuesError:
+			return ErrorCodeMissingValues
+		case *config.InterpolationError:
+			return ExitCodeToString(ExitConfigError)
+		}
+	}
+	return GetJSONCode(err)
+}
+
 // PrintError outputs an error using the appropriate formatter.
 // When format is "json", it outputs a structured JSON error to the writer.
 // For other formats, it outputs a plain text error message.
@@ -154,9 +322,16 @@ func PrintError(w io.Writer, err error, format string) {
 	if err == nil {
 		return
 	}
+	if exitErr, ok := err.(*ExitCodeError); ok && exitErr.Silent {
+		return
+	}
 
 	formatter := output.New(output.Format(format))
 	codeStr := GetJSONCode(err)
 
-	formatter.FormatError(w, codeStr, err.Error(), nil)
+	var details map[string]any
+	if exitErr, ok := err.(*ExitCodeError); ok {
+		details = exitErr.Details
+	}
+	formatter.FormatError(w, codeStr, GetErrorCode(err), err.Error(), details)
 }
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/execbackend"
+	"github.com/alexbrand/backlog/internal/github"
+	"github.com/alexbrand/backlog/internal/linear"
+	"github.com/alexbrand/backlog/internal/local"
+)
+
+func TestGetErrorCode(t *testing.T) {
+	claimConflict := &local.ClaimConflictError{TaskID: "001", ClaimedBy: "alice", CurrentAgent: "bob"}
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"local claim conflict", ConflictError(claimConflict.Error()).WithCause(claimConflict), "CLAIM_CONFLICT"},
+		{"github claim conflict", &github.ClaimConflictError{}, "CLAIM_CONFLICT"},
+		{"linear release conflict", ConflictError("not claimed").WithCause(&linear.ReleaseConflictError{}), "RELEASE_CONFLICT"},
+		{"sync conflict", ConflictError("conflict").WithCause(&local.SyncConflictError{Operation: "pull"}), "SYNC_CONFLICT"},
+		{"push conflict", fmt.Errorf("move: %w", &local.GitPushConflictError{}), "SYNC_CONFLICT"},
+		{"uncommitted changes", GeneralError("dirty").WithCause(&local.UncommittedChangesError{}), "UNCOMMITTED_CHANGES"},
+		{"detached head", fmt.Errorf("failed to pull: %w", &local.DetachedHeadError{}), "DETACHED_HEAD"},
+		{"git timeout", fmt.Errorf("failed to pull: %w", &local.GitTimeoutError{Operation: "pull"}), "GIT_TIMEOUT"},
+		{"rate limited", &url.Error{Op: "Get", URL: "https://api.github.com/repos/o/r", Err: &github.RateLimitError{}}, "RATE_LIMITED"},
+		{"exec handshake", WrapError("failed to connect to backend", &execbackend.HandshakeError{Command: "adapter", Err: &execbackend.TimeoutError{Op: "handshake"}}), "HANDSHAKE_FAILED"},
+		{"exec protocol", &execbackend.ProtocolError{Op: "list"}, "PROTOCOL_ERROR"},
+		{"exec timeout", &execbackend.TimeoutError{Op: "list"}, "BACKEND_TIMEOUT"},
+		{"exec exit", &execbackend.ExitError{Command: "adapter", Code: 3}, "BACKEND_EXITED"},
+		{"exec claim conflict", ConflictError("conflict").WithCause(&execbackend.ClaimConflictError{TaskID: "EXT-1"}), "CLAIM_CONFLICT"},
+		{"exec unsupported", &execbackend.UnsupportedError{Op: "comments"}, "UNSUPPORTED"},
+		{"explicit code", &ExitCodeError{Code: ExitConflict, ErrorCode: "CLAIM_CONFLICT"}, "CLAIM_CONFLICT"},
+		{"not found", NotFoundError("task 999 not found"), "NOT_FOUND"},
+		{"backend not found", fmt.Errorf("failed to get issue: %w", &backend.NotFoundError{ID: "ENG-9"}), "NOT_FOUND"},
+		{"invalid input", InvalidInputError("bad priority"), "INVALID_INPUT"},
+		{"plain conflict", ConflictError("already done"), "CONFLICT"},
+		{"unsupported", UnsupportedError(local.New(), "task history"), "UNSUPPORTED"},
+		{"read-only", ReadOnlyError("prod"), "READ_ONLY"},
+		{"confirmation required", ConfirmationRequiredError(), "CONFIRMATION_REQUIRED"},
+		{"declined", DeclinedError("cancelled"), "DECLINED"},
+		{"timeout", TimeoutError("no task became available"), "TIMEOUT"},
+		{"interrupted", InterruptedError(os.Interrupt), "INTERRUPTED"},
+		{"unknown", errors.New("boom"), "ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetErrorCode(tt.err); got != tt.want {
+				t.Errorf("GetErrorCode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitSuccess},
+		{"exit code error", ConflictError("already claimed"), ExitConflict},
+		{"read-only", ReadOnlyError("prod"), ExitReadOnly},
+		{"declined", DeclinedError("cancelled"), ExitDeclined},
+		{"timeout", TimeoutError("no task became available"), ExitTimeout},
+		{"interrupted", InterruptedError(os.Interrupt), ExitInterrupted},
+		{"confirmation required", ConfirmationRequiredError(), ExitError},
+		{"backend not found", &backend.NotFoundError{ID: "001"}, ExitNotFound},
+		{"wrapped backend not found", fmt.Errorf("failed to get issue: %w", &backend.NotFoundError{ID: "001"}), ExitNotFound},
+		{"other", errors.New("boom"), ExitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetExitCode(tt.err); got != tt.want {
+				t.Errorf("GetExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintErrorKeepsMessageWithCause(t *testing.T) {
+	cause := &local.SyncConflictError{Operation: "pull", Message: "rebase failed"}
+	var buf bytes.Buffer
+	PrintError(&buf, ConflictError(cause.Error()).WithCause(cause), "json")
+
+	var result struct {
+		Error struct {
+			Code      string `json:"code"`
+			ErrorCode string `json:"error_code"`
+			Message   string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if result.Error.Code != "CONFLICT" || result.Error.ErrorCode != "SYNC_CONFLICT" {
+		t.Errorf("code = %q, error_code = %q, want CONFLICT and SYNC_CONFLICT", result.Error.Code, result.Error.ErrorCode)
+	}
+	if result.Error.Message != cause.Error() {
+		t.Errorf("message = %q, want %q", result.Error.Message, cause.Error())
+	}
+}
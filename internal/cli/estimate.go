@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var estimateCmd = &cobra.Command{
+	Use:   "estimate <id> <duration>",
+	Short: "Set the estimated effort for a task",
+	Long: `Set the estimated effort for a task, replacing any existing estimate.
+
+Durations use Go syntax (30m, 1h30m) and also accept d (day) and
+w (week) units (1d, 1w2d). Use 0 to clear the estimate.
+
+For the Linear backend, estimates are stored as points where one point
+is one day, rounded up.
+
+Examples:
+  backlog estimate 001 1d
+  backlog estimate 001 4h
+  backlog estimate 001 0`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeTaskIDArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runEstimate(args[0], args[1])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(estimateCmd)
+}
+
+func runEstimate(id, estimateStr string) error {
+	var estimate backend.Duration
+	if estimateStr != "0" {
+		parsed, err := backend.ParseDuration(estimateStr)
+		if err != nil {
+			return InvalidInputError(err.Error())
+		}
+		if parsed < 0 {
+			return InvalidInputError(fmt.Sprintf("estimate must not be negative, got %q", estimateStr))
+		}
+		estimate = parsed
+	}
+
+	// Get backend and connect
+	b, _, cleanup, err := connectWritableBackend()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	// Get the task first so the output can show what changed
+	before, err := b.Get(id)
+	if err != nil {
+		if isNotFound(err) {
+			return NotFoundError(err.Error()).WithCause(err)
+		}
+		return err
+	}
+
+	task, err := b.Update(id, backend.TaskChanges{Estimate: &estimate})
+	if err != nil {
+		return err
+	}
+
+	// Output the result
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatUpdated(statusOutput(), task, backend.DiffTasks(before, task))
+}
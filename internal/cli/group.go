@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/output"
+)
+
+// groupByFields lists the values accepted by list --group-by.
+var groupByFields = []string{"status", "priority", "assignee", "label"}
+
+// Keys for tasks without a value for the grouped field.
+const (
+	groupUnassigned = "unassigned"
+	groupUnlabeled  = "unlabeled"
+)
+
+// parseGroupBy validates a --group-by value.
+func parseGroupBy(s string) (string, error) {
+	field := normalizeKey(s)
+	for _, valid := range groupByFields {
+		if field == valid {
+			return field, nil
+		}
+	}
+	return "", InvalidInputError(fmt.Sprintf("invalid group-by field %q (valid: %s)", s, strings.Join(groupByFields, ", ")))
+}
+
+// groupTasks partitions tasks by field, keeping list order within each group.
+// Statuses and priorities are ordered as on a board, from backlog to done and
+// from urgent to none; assignees and labels alphabetically, with tasks lacking
+// one last. A task with several labels appears under each of them. Empty
+// groups are omitted.
+func groupTasks(tasks []backend.Task, field string) []output.TaskGroup {
+	byKey := make(map[string][]backend.Task)
+	for _, task := range tasks {
+		for _, key := range taskGroupKeys(task, field) {
+			byKey[key] = append(byKey[key], task)
+		}
+	}
+
+	var keys []string
+	switch field {
+	case "status":
+		for _, status := range backend.ValidStatuses() {
+			keys = append(keys, string(status))
+		}
+	case "priority":
+		for _, priority := range backend.ValidPriorities() {
+			keys = append(keys, string(priority))
+		}
+	default:
+		missing := groupUnassigned
+		if field == "label" {
+			missing = groupUnlabeled
+		}
+		for key := range byKey {
+			if key != missing {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		keys = append(keys, missing)
+	}
+
+	var groups []output.TaskGroup
+	for _, key := range keys {
+		if len(byKey[key]) > 0 {
+			groups = append(groups, output.TaskGroup{Key: key, Tasks: byKey[key]})
+		}
+	}
+	return groups
+}
+
+// taskGroupKeys returns the groups a task belongs to.
+func taskGroupKeys(task backend.Task, field string) []string {
+	switch field {
+	case "status":
+		return []string{string(task.Status)}
+	case "priority":
+		return []string{string(task.Priority)}
+	case "assignee":
+		if task.Assignee == "" {
+			return []string{groupUnassigned}
+		}
+		return []string{task.Assignee}
+	default:
+		if len(task.Labels) == 0 {
+			return []string{groupUnlabeled}
+		}
+		return task.Labels
+	}
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/output"
+)
+
+func TestGroupTasks(t *testing.T) {
+	tasks := []backend.Task{
+		{ID: "001", Status: backend.StatusReview, Priority: backend.PriorityLow, Assignee: "bob", Labels: []string{"ui", "bug"}},
+		{ID: "002", Status: backend.StatusTodo, Priority: backend.PriorityUrgent},
+		{ID: "003", Status: backend.StatusReview, Priority: backend.PriorityUrgent, Assignee: "alice", Labels: []string{"bug"}},
+	}
+
+	tests := []struct {
+		field string
+		want  map[string][]string
+		order []string
+	}{
+		{
+			field: "status",
+			order: []string{"todo", "review"},
+			want:  map[string][]string{"todo": {"002"}, "review": {"001", "003"}},
+		},
+		{
+			field: "priority",
+			order: []string{"urgent", "low"},
+			want:  map[string][]string{"urgent": {"002", "003"}, "low": {"001"}},
+		},
+		{
+			field: "assignee",
+			order: []string{"alice", "bob", "unassigned"},
+			want:  map[string][]string{"alice": {"003"}, "bob": {"001"}, "unassigned": {"002"}},
+		},
+		{
+			field: "label",
+			order: []string{"bug", "ui", "unlabeled"},
+			want:  map[string][]string{"bug": {"001", "003"}, "ui": {"001"}, "unlabeled": {"002"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			groups := groupTasks(tasks, tt.field)
+			if got := groupKeys(groups); !reflect.DeepEqual(got, tt.order) {
+				t.Fatalf("expected groups %v, got %v", tt.order, got)
+			}
+			for _, group := range groups {
+				var ids []string
+				for _, task := range group.Tasks {
+					ids = append(ids, task.ID)
+				}
+				if !reflect.DeepEqual(ids, tt.want[group.Key]) {
+					t.Errorf("group %q: expected %v, got %v", group.Key, tt.want[group.Key], ids)
+				}
+			}
+		})
+	}
+}
+
+func TestParseGroupBy(t *testing.T) {
+	if field, err := parseGroupBy(" Status "); err != nil || field != "status" {
+		t.Errorf("expected status, got %q (%v)", field, err)
+	}
+	if _, err := parseGroupBy("milestone"); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
+
+func groupKeys(groups []output.TaskGroup) []string {
+	keys := make([]string, len(groups))
+	for i, group := range groups {
+		keys[i] = group.Key
+	}
+	return keys
+}
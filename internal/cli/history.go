@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var historyDiff bool
+
+var historyCmd = &cobra.Command{
+	Use:   "history <id>",
+	Short: "Show the history of a task",
+	Long: `Show the lifecycle of a task, oldest event first.
+
+For the local backend with git_sync enabled, history comes from the git log
+of the task file, followed across renames between status directories. Each
+entry shows the action (add, edit, move, claim, release, comment, ...), the
+agent that made it when recorded, the time, and the status after the change.
+
+Without git_sync, or when git is unavailable, a single entry is built from
+the task's created timestamp, with a note explaining why.
+
+Use --diff to include the patch each commit applied to the task file.
+
+Examples:
+  backlog history 001
+  backlog history 001 --diff
+  backlog history 001 -f json`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTaskIDArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runHistory(args[0])
+	},
+}
+
+func init() {
+	historyCmd.Flags().BoolVar(&historyDiff, "diff", false, "Include the patch for each entry")
+	rootCmd.AddCommand(historyCmd)
+}
+
+func runHistory(id string) error {
+	// Get backend and connect
+	b, _, cleanup, err := connectBackend()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	historian, ok := b.(backend.Historian)
+	if !ok {
+		return UnsupportedError(b, "task history")
+	}
+
+	history, err := historian.History(id, historyDiff)
+	if err != nil {
+		if isNotFound(err) {
+			return NotFoundError(err.Error()).WithCause(err)
+		}
+		return err
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatHistory(os.Stdout, history)
+}
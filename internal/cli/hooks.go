@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/config"
+)
+
+// defaultHookTimeout bounds a hook when hooks.timeout is not set.
+const defaultHookTimeout = 10 * time.Second
+
+// Hook event types, matching the hooks.on_<event> config keys.
+const (
+	hookEventCreate = "create"
+	hookEventMove   = "move"
+	hookEventClaim  = "claim"
+)
+
+// hookEvent is the JSON document a hook receives on stdin.
+type hookEvent struct {
+	Event          string         `json:"event"`
+	Workspace      string         `json:"workspace"`
+	Task           *backend.Task  `json:"task"`
+	PreviousStatus backend.Status `json:"previous_status,omitempty"`
+	Agent          string         `json:"agent,omitempty"`
+	Timestamp      time.Time      `json:"timestamp"`
+}
+
+// hookCommand is a configured hook command and the config key it came from.
+type hookCommand struct {
+	key     string
+	command string
+}
+
+// runHooks runs the workspace's hooks for an event after a successful
+// mutation. Hooks run in order, with a move's on_transition hooks after its
+// on_move hooks; a failing hook is reported as a warning and the rest still
+// run, unless hooks.strict is set, in which case the first failure fails
+// the command. Hooks never run under --dry-run.
+func runHooks(ws *config.Workspace, event hookEvent) error {
+	if IsDryRun() || ws == nil {
+		return nil
+	}
+
+	commands := hookCommands(ws.Hooks, event)
+	if len(commands) == 0 {
+		return nil
+	}
+
+	if _, name, err := config.GetWorkspace(GetWorkspace()); err == nil {
+		event.Workspace = name
+	}
+	event.Timestamp = time.Now().UTC()
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s hook event: %w", event.Event, err)
+	}
+
+	timeout := ws.Hooks.Timeout
+	if timeout <= 0 {
+		timeout = defaultHookTimeout
+	}
+
+	for _, hook := range commands {
+		err := runHook(hook.command, event, payload, timeout)
+		if err == nil {
+			continue
+		}
+		message := fmt.Sprintf("%s hook %q failed for task %s: %v", hook.key, hook.command, event.Task.ID, err)
+		if ws.Hooks.Strict {
+			e := GeneralError(message)
+			e.Details = map[string]any{"hook": hook.command, "event": event.Event, "task_id": event.Task.ID}
+			return e
+		}
+		fmt.Fprintf(os.Stderr, "warning: %s\n", message)
+	}
+	return nil
+}
+
+// hookCommands returns the commands configured for an event. A move also
+// runs the on_transition hooks whose from->to key matches it, in key order.
+func hookCommands(hooks config.Hooks, event hookEvent) []hookCommand {
+	var commands []hookCommand
+	add := func(key string, list []string) {
+		for _, command := range list {
+			commands = append(commands, hookCommand{key: key, command: command})
+		}
+	}
+
+	switch event.Event {
+	case hookEventCreate:
+		add("on_create", hooks.OnCreate)
+	case hookEventMove:
+		add("on_move", hooks.OnMove)
+		keys := make([]string, 0, len(hooks.OnTransition))
+		for key := range hooks.OnTransition {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			from, to, err := config.ParseTransition(key)
+			if err != nil {
+				continue // Rejected when the config is loaded
+			}
+			if (from == "*" || from == string(event.PreviousStatus)) && (to == "*" || to == string(event.Task.Status)) {
+				add(fmt.Sprintf("on_transition %q", key), hooks.OnTransition[key])
+			}
+		}
+	case hookEventClaim:
+		add("on_claim", hooks.OnClaim)
+	}
+	return commands
+}
+
+// runHook runs a single hook command through the shell with the event on
+// stdin. The hook's stdout is passed through to stderr so it can't corrupt
+// the command's own output.
+func runHook(command string, event hookEvent, payload []byte, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = os.Stderr
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(),
+		"BACKLOG_EVENT="+event.Event,
+		"BACKLOG_WORKSPACE="+event.Workspace,
+		"BACKLOG_TASK_ID="+event.Task.ID,
+		"BACKLOG_TASK_STATUS="+string(event.Task.Status),
+	)
+	if event.PreviousStatus != "" {
+		cmd.Env = append(cmd.Env, "BACKLOG_PREVIOUS_STATUS="+string(event.PreviousStatus))
+	}
+	if event.Event == hookEventMove {
+		cmd.Env = append(cmd.Env,
+			"BACKLOG_FROM="+string(event.PreviousStatus),
+			"BACKLOG_TO="+string(event.Task.Status),
+		)
+	}
+	if event.Agent != "" {
+		cmd.Env = append(cmd.Env, "BACKLOG_AGENT_ID="+event.Agent)
+	}
+	// Don't wait on background processes the hook left holding its output
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/config"
+)
+
+func TestHookCommands(t *testing.T) {
+	hooks := config.Hooks{
+		OnMove:  []string{"notify"},
+		OnClaim: []string{"start-env"},
+		OnTransition: map[string][]string{
+			"in-progress->review": {"request-review"},
+			"*->done":             {"close-ticket", "celebrate"},
+			"review->*":           {"review-finished"},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		event hookEvent
+		want  []string
+	}{
+		{
+			name:  "matching transition",
+			event: hookEvent{Event: hookEventMove, PreviousStatus: backend.StatusInProgress, Task: &backend.Task{Status: backend.StatusReview}},
+			want:  []string{"notify", "request-review"},
+		},
+		{
+			name:  "wildcards",
+			event: hookEvent{Event: hookEventMove, PreviousStatus: backend.StatusReview, Task: &backend.Task{Status: backend.StatusDone}},
+			want:  []string{"notify", "close-ticket", "celebrate", "review-finished"},
+		},
+		{
+			name:  "no transition",
+			event: hookEvent{Event: hookEventMove, PreviousStatus: backend.StatusTodo, Task: &backend.Task{Status: backend.StatusInProgress}},
+			want:  []string{"notify"},
+		},
+		{
+			name:  "other event",
+			event: hookEvent{Event: hookEventClaim, PreviousStatus: backend.StatusReview, Task: &backend.Task{Status: backend.StatusDone}},
+			want:  []string{"start-env"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, hook := range hookCommands(hooks, tt.event) {
+				got = append(got, hook.command)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("hookCommands() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -40,7 +40,9 @@ Created structure:
   .backlog/review/    - Tasks in review
   .backlog/done/      - Completed tasks
   .backlog/.locks/    - Lock files for agent coordination
-  .backlog/config.yaml - Configuration file`,
+  .backlog/config.yaml - Configuration file
+  .backlog/.gitignore - Keeps credentials.yaml, the index and undo log out of git`,
+	Annotations: map[string]string{annotationConfigOptional: "true"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runInit()
 	},
@@ -147,6 +149,12 @@ func runInit() error {
 		return fmt.Errorf("failed to create config file: %w", err)
 	}
 
+	// Keep a credentials file and the per-checkout index and undo log out of git
+	gitignorePath := filepath.Join(backlogDir, ".gitignore")
+	if err := os.WriteFile(gitignorePath, []byte("credentials.yaml\n.index.json\n.undo/\n"), 0644); err != nil {
+		return fmt.Errorf("failed to create %s: %w", gitignorePath, err)
+	}
+
 	fmt.Println()
 	fmt.Println("Created .backlog/")
 	fmt.Println("  - backlog/")
@@ -155,6 +163,7 @@ func runInit() error {
 	fmt.Println("  - review/")
 	fmt.Println("  - done/")
 	fmt.Println("  - config.yaml")
+	fmt.Println("  - .gitignore")
 	fmt.Println()
 	fmt.Println("Ready! Try: backlog add \"My first task\"")
 
@@ -0,0 +1,258 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/local"
+	"github.com/alexbrand/backlog/internal/output"
+)
+
+// editorNotePrefix starts the comment lines written above the task in the
+// file opened by "edit --interactive". They are stripped before parsing.
+const editorNotePrefix = "# backlog: "
+
+// runEditInteractive opens a task in the user's editor, in the local task
+// file format, and applies the fields that were changed on save.
+func runEditInteractive(id string) error {
+	// Get backend and connect
+	b, ws, cleanup, err := connectWritableBackend()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	task, err := b.Get(id)
+	if err != nil {
+		if isNotFound(err) {
+			return NotFoundError(err.Error()).WithCause(err)
+		}
+		return err
+	}
+
+	content, err := local.MarshalTask(task)
+	if err != nil {
+		return err
+	}
+	notes := []string{
+		fmt.Sprintf("Editing %s. Change the title, priority, assignee, labels, or description.", task.ID),
+		"Other fields are ignored. Save an empty file to abort.",
+	}
+
+	edited, err := editInEditor(withEditorNotes(content, notes...))
+	if err != nil {
+		return err
+	}
+	if edited == nil {
+		fmt.Fprintln(os.Stderr, "Edit aborted; no changes made.")
+		return nil
+	}
+
+	changes, summary := diffTask(task, edited)
+	if len(summary) == 0 {
+		fmt.Fprintln(os.Stderr, "No changes made.")
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "Changes to %s:\n", task.ID)
+	for _, line := range summary {
+		fmt.Fprintf(os.Stderr, "  %s\n", line)
+	}
+
+	if err := warnUnknownLabels(b, ws, changes.AddLabels); err != nil {
+		return err
+	}
+
+	if IsDryRun() {
+		return previewEdit(b, id, changes)
+	}
+
+	updated, err := b.Update(id, changes)
+	if err != nil {
+		return err
+	}
+
+	// Output the result
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatUpdated(statusOutput(), updated, backend.DiffTasks(task, updated))
+}
+
+// editInEditor writes content to a temporary file, opens it in the user's
+// editor, and parses the result as a task. It returns nil if the user saved
+// the file unchanged or emptied it. When the saved file doesn't parse, the
+// error is added as a note at the top and the editor is opened again, so
+// the user's edits aren't lost.
+func editInEditor(content []byte) (*backend.Task, error) {
+	f, err := os.CreateTemp("", "backlog-edit-*.md")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	path := f.Name()
+	defer os.Remove(path)
+	f.Close()
+
+	for {
+		if err := os.WriteFile(path, content, 0600); err != nil {
+			return nil, fmt.Errorf("failed to write temporary file: %w", err)
+		}
+		if err := launchEditor(path); err != nil {
+			return nil, err
+		}
+
+		edited, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read edited file: %w", err)
+		}
+		stripped := stripEditorNotes(edited)
+		if bytes.Equal(edited, content) || len(bytes.TrimSpace(stripped)) == 0 {
+			return nil, nil
+		}
+
+		task, err := parseEditedTask(stripped)
+		if err == nil {
+			return task, nil
+		}
+		content = withEditorNotes(stripped,
+			"error: "+err.Error(),
+			"Fix the problem and save, or save an empty file to abort.")
+	}
+}
+
+// parseEditedTask parses an edited task file and validates the fields that
+// "edit --interactive" applies.
+func parseEditedTask(content []byte) (*backend.Task, error) {
+	task, err := local.UnmarshalTask(content)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(task.Title) == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+	if !task.Priority.IsValid() {
+		return nil, fmt.Errorf("invalid priority %q (valid: urgent, high, medium, low, none)", task.Priority)
+	}
+	return task, nil
+}
+
+// launchEditor opens path in $VISUAL, $EDITOR, or vi. The editor command is
+// run through the shell so it may include arguments, such as "code --wait".
+func launchEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	cmd := exec.Command("sh", "-c", editor+` "$1"`, "sh", path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+	return nil
+}
+
+// withEditorNotes prepends note lines to content.
+func withEditorNotes(content []byte, notes ...string) []byte {
+	var buf bytes.Buffer
+	for _, note := range notes {
+		for _, line := range strings.Split(note, "\n") {
+			buf.WriteString(editorNotePrefix + line + "\n")
+		}
+	}
+	buf.Write(content)
+	return buf.Bytes()
+}
+
+// stripEditorNotes removes the note lines at the top of content.
+func stripEditorNotes(content []byte) []byte {
+	for bytes.HasPrefix(content, []byte(editorNotePrefix)) {
+		end := bytes.IndexByte(content, '\n')
+		if end == -1 {
+			return nil
+		}
+		content = content[end+1:]
+	}
+	return content
+}
+
+// diffTask returns the changes that turn before into after, limited to the
+// fields "edit --interactive" applies, and a line describing each change.
+func diffTask(before, after *backend.Task) (backend.TaskChanges, []string) {
+	var changes backend.TaskChanges
+	var summary []string
+
+	if after.Title != before.Title {
+		changes.Title = &after.Title
+		summary = append(summary, fmt.Sprintf("title: %q -> %q", before.Title, after.Title))
+	}
+
+	if strings.TrimSpace(after.Description) != strings.TrimSpace(before.Description) {
+		description := strings.TrimSpace(after.Description)
+		changes.Description = &description
+		summary = append(summary, "description: updated")
+	}
+
+	if after.Priority != before.Priority {
+		changes.Priority = &after.Priority
+		summary = append(summary, fmt.Sprintf("priority: %s -> %s", before.Priority, after.Priority))
+	}
+
+	if after.Assignee != before.Assignee {
+		changes.Assignee = &after.Assignee
+		summary = append(summary, fmt.Sprintf("assignee: %s -> %s", displayAssignee(before.Assignee), displayAssignee(after.Assignee)))
+	}
+
+	changes.AddLabels = missingLabels(after.Labels, before.Labels)
+	changes.RemoveLabels = missingLabels(before.Labels, after.Labels)
+	if len(changes.AddLabels) > 0 || len(changes.RemoveLabels) > 0 {
+		var parts []string
+		for _, label := range changes.AddLabels {
+			parts = append(parts, "+"+label)
+		}
+		for _, label := range changes.RemoveLabels {
+			parts = append(parts, "-"+label)
+		}
+		summary = append(summary, "labels: "+strings.Join(parts, " "))
+	}
+
+	return changes, summary
+}
+
+// missingLabels returns the labels in labels that are not in other.
+func missingLabels(labels, other []string) []string {
+	present := make(map[string]bool, len(other))
+	for _, label := range other {
+		present[label] = true
+	}
+	var missing []string
+	for _, label := range labels {
+		if !present[label] {
+			missing = append(missing, label)
+		}
+	}
+	return missing
+}
+
+// displayAssignee formats an assignee for a change summary.
+func displayAssignee(assignee string) string {
+	if assignee == "" {
+		return "(none)"
+	}
+	return "@" + assignee
+}
+
+// isTerminal reports whether f is attached to a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
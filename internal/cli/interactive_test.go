@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/local"
+)
+
+func TestDiffTask(t *testing.T) {
+	before := &backend.Task{
+		ID:          "001",
+		Title:       "Fix login",
+		Description: "Old notes",
+		Priority:    backend.PriorityLow,
+		Assignee:    "alex",
+		Labels:      []string{"bug", "ui"},
+	}
+	after := &backend.Task{
+		ID:          "001",
+		Title:       "Fix login form",
+		Description: "New notes\n",
+		Priority:    backend.PriorityHigh,
+		Labels:      []string{"bug", "auth"},
+	}
+
+	changes, summary := diffTask(before, after)
+
+	if changes.Title == nil || *changes.Title != "Fix login form" {
+		t.Errorf("Title = %v, want Fix login form", changes.Title)
+	}
+	if changes.Description == nil || *changes.Description != "New notes" {
+		t.Errorf("Description = %v, want New notes", changes.Description)
+	}
+	if changes.Priority == nil || *changes.Priority != backend.PriorityHigh {
+		t.Errorf("Priority = %v, want high", changes.Priority)
+	}
+	if changes.Assignee == nil || *changes.Assignee != "" {
+		t.Errorf("Assignee = %v, want unassigned", changes.Assignee)
+	}
+	if !reflect.DeepEqual(changes.AddLabels, []string{"auth"}) || !reflect.DeepEqual(changes.RemoveLabels, []string{"ui"}) {
+		t.Errorf("labels +%v -%v, want +[auth] -[ui]", changes.AddLabels, changes.RemoveLabels)
+	}
+
+	want := []string{
+		`title: "Fix login" -> "Fix login form"`,
+		"description: updated",
+		"priority: low -> high",
+		"assignee: @alex -> (none)",
+		"labels: +auth -ui",
+	}
+	if !reflect.DeepEqual(summary, want) {
+		t.Errorf("summary = %q, want %q", summary, want)
+	}
+
+	if _, summary := diffTask(before, before); len(summary) != 0 {
+		t.Errorf("diffTask() of an unchanged task = %q, want no changes", summary)
+	}
+}
+
+func TestStripEditorNotes(t *testing.T) {
+	content := []byte("---\ntitle: x\n---\n")
+	noted := withEditorNotes(content, "error: bad\nyaml", "try again")
+	if !strings.HasPrefix(string(noted), "# backlog: error: bad\n# backlog: yaml\n# backlog: try again\n---") {
+		t.Errorf("withEditorNotes() = %q", noted)
+	}
+	if got := stripEditorNotes(noted); string(got) != string(content) {
+		t.Errorf("stripEditorNotes() = %q, want %q", got, content)
+	}
+}
+
+// writeEditor writes a shell script that stands in for the user's editor and
+// points VISUAL at it.
+func writeEditor(t *testing.T, script string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "editor.sh")
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write editor script: %v", err)
+	}
+	t.Setenv("VISUAL", "sh "+path)
+}
+
+func TestEditInEditor(t *testing.T) {
+	content, err := local.MarshalTask(&backend.Task{ID: "001", Title: "Old title", Priority: backend.PriorityMedium})
+	if err != nil {
+		t.Fatalf("MarshalTask() error = %v", err)
+	}
+
+	t.Run("applies edits", func(t *testing.T) {
+		writeEditor(t, `sed -i 's/Old title/New title/' "$1"`)
+		task, err := editInEditor(content)
+		if err != nil {
+			t.Fatalf("editInEditor() error = %v", err)
+		}
+		if task == nil || task.Title != "New title" {
+			t.Errorf("editInEditor() = %+v, want title New title", task)
+		}
+	})
+
+	t.Run("unchanged file aborts", func(t *testing.T) {
+		writeEditor(t, "true")
+		task, err := editInEditor(content)
+		if err != nil || task != nil {
+			t.Errorf("editInEditor() = %+v, %v; want nil, nil", task, err)
+		}
+	})
+
+	t.Run("empty file aborts", func(t *testing.T) {
+		writeEditor(t, `: > "$1"`)
+		task, err := editInEditor(content)
+		if err != nil || task != nil {
+			t.Errorf("editInEditor() = %+v, %v; want nil, nil", task, err)
+		}
+	})
+
+	t.Run("parse errors reopen the editor with the error", func(t *testing.T) {
+		// The first pass breaks the frontmatter; the second sees the error
+		// note, keeps a copy, and repairs it
+		dir := t.TempDir()
+		writeEditor(t, `if grep -q '^# backlog: error:' "$1"; then
+  cp "$1" `+filepath.Join(dir, "second-pass.md")+`
+  sed -i 's/^priority: .*/priority: high/' "$1"
+else
+  sed -i 's/^priority: .*/priority: [oops/; s/Old title/Kept title/' "$1"
+fi`)
+		task, err := editInEditor(content)
+		if err != nil {
+			t.Fatalf("editInEditor() error = %v", err)
+		}
+		if task == nil || task.Title != "Kept title" || task.Priority != backend.PriorityHigh {
+			t.Errorf("editInEditor() = %+v, want the first pass's edits kept and priority high", task)
+		}
+		second, err := os.ReadFile(filepath.Join(dir, "second-pass.md"))
+		if err != nil {
+			t.Fatalf("editor was not reopened: %v", err)
+		}
+		if !strings.Contains(string(second), "Kept title") {
+			t.Errorf("reopened file lost the user's edits:\n%s", second)
+		}
+	})
+
+	t.Run("invalid priority reopens the editor", func(t *testing.T) {
+		writeEditor(t, `if grep -q 'invalid priority' "$1"; then
+  sed -i 's/^priority: .*/priority: low/' "$1"
+else
+  sed -i 's/^priority: .*/priority: hgh/' "$1"
+fi`)
+		task, err := editInEditor(content)
+		if err != nil {
+			t.Fatalf("editInEditor() error = %v", err)
+		}
+		if task == nil || task.Priority != backend.PriorityLow {
+			t.Errorf("editInEditor() = %+v, want priority low", task)
+		}
+	})
+}
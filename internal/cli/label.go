@@ -0,0 +1,262 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/config"
+	"github.com/alexbrand/backlog/internal/local"
+	"github.com/alexbrand/backlog/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var labelCmd = &cobra.Command{
+	Use:   "label",
+	Short: "Manage task labels",
+	Long: `Manage the labels tasks can carry.
+
+For the local backend, labels are registered in .backlog/labels.yaml, which
+maps each label to a color and description:
+
+  bug:
+    color: "#d73a4a"
+    description: Something isn't working
+  docs:
+    color: blue
+
+Colors are hex values or ANSI color names (red, green, yellow, blue,
+magenta, cyan, gray). Table output of "backlog list" paints labels in their
+registry color. Set strict_labels: true on a workspace to warn when a task
+is given a label that is not registered.
+
+To keep typo variants such as "Infra" and "infra" from accumulating, set
+labels.normalize: lower on a local workspace. Labels are then lowercased
+when tasks are added or edited, and --label filters ignore case. Agent
+labels keep their case.
+
+The Linear backend lists the team's labels with their colors.`,
+}
+
+var labelListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List known labels with their colors",
+	Long: `List the labels known to the backend with their colors and descriptions.
+
+For the local backend, every label in the workspace is listed, registered
+or not, with the number of tasks carrying it, done tasks included.
+
+Examples:
+  backlog label list
+  backlog label list -f json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLabelList()
+	},
+}
+
+var labelRenameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a label on every task",
+	Long: `Rename a label on every task carrying it, done tasks included, and in the
+label registry. A task that already has the new label keeps one copy, so
+renaming a typo variant to the right name merges the two. With git_sync
+enabled, the change is a single commit.
+
+Agent labels such as agent:claude-1 mark claims and are refused; use claim
+and release to change them.
+
+Examples:
+  backlog label rename infrastrucure infra
+  backlog label rename Infra infra -f json`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeLabels,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLabelRename(args[0], args[1])
+	},
+}
+
+var labelRmForce bool
+
+var labelRmCmd = &cobra.Command{
+	Use:   "rm <name>",
+	Short: "Remove a label",
+	Long: `Remove a label from the label registry. A label that tasks still carry is
+refused with exit code 2 unless --force is given, which removes it from
+those tasks too. On a terminal, --force asks for confirmation first; pass
+--yes to skip the prompt. Without a terminal, or with -f json, it fails
+unless --yes is given. With git_sync enabled, the change is a single commit.
+
+Agent labels such as agent:claude-1 mark claims and are refused; use
+release to remove them.
+
+Examples:
+  backlog label rm wontfix
+  backlog label rm stale --force
+  backlog label rm stale --force --yes`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeLabels,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLabelRm(args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(labelCmd)
+	labelCmd.AddCommand(labelListCmd)
+	labelCmd.AddCommand(labelRenameCmd)
+	labelRmCmd.Flags().BoolVar(&labelRmForce, "force", false, "Remove the label from the tasks carrying it")
+	addYesFlag(labelRmCmd)
+	labelCmd.AddCommand(labelRmCmd)
+}
+
+func runLabelList() error {
+	// Get backend and connect
+	b, _, cleanup, err := connectBackend()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	// Backends that track usage list every label with its task count
+	var labels []backend.Label
+	if manager, ok := b.(backend.LabelManager); ok {
+		labels, err = manager.LabelUsage()
+	} else if lister, ok := b.(backend.LabelLister); ok {
+		labels, err = lister.ListLabels()
+	} else {
+		return UnsupportedError(b, "label metadata")
+	}
+	if err != nil {
+		return WrapError("failed to list labels", err)
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatLabels(os.Stdout, labels)
+}
+
+func runLabelRename(oldName, newName string) error {
+	newName = strings.TrimSpace(newName)
+	if newName == "" {
+		return InvalidInputError("new label name cannot be empty")
+	}
+	if newName == oldName {
+		return InvalidInputError(fmt.Sprintf("label %q already has that name", oldName))
+	}
+
+	// Get backend and connect
+	b, _, cleanup, err := connectWritableBackend()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	manager, ok := b.(backend.LabelManager)
+	if !ok {
+		return UnsupportedError(b, "label rename")
+	}
+
+	change, err := manager.RenameLabel(oldName, newName)
+	if err != nil {
+		return labelChangeError(err)
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatLabelChange(statusOutput(), change)
+}
+
+func runLabelRm(name string) error {
+	// Get backend and connect
+	b, _, cleanup, err := connectWritableBackend()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	manager, ok := b.(backend.LabelManager)
+	if !ok {
+		return UnsupportedError(b, "label rm")
+	}
+
+	if labelRmForce {
+		if err := confirm(fmt.Sprintf("Remove label %q from every task carrying it?", name)); err != nil {
+			return err
+		}
+	}
+
+	change, err := manager.RemoveLabel(name, labelRmForce)
+	if err != nil {
+		return labelChangeError(err)
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatLabelChange(statusOutput(), change)
+}
+
+// labelChangeError maps the errors of renaming or removing a label to
+// their exit codes.
+func labelChangeError(err error) error {
+	var agentLabel *backend.AgentLabelError
+	var inUse *backend.LabelInUseError
+	switch {
+	case errors.As(err, &agentLabel):
+		return InvalidInputError(err.Error()).WithCause(err)
+	case errors.As(err, &inUse):
+		return ConflictError(err.Error()).WithCause(err)
+	case errors.Is(err, backend.ErrLabelNotFound):
+		return NotFoundError(err.Error()).WithCause(err)
+	}
+	if _, ok := err.(*local.UncommittedChangesError); ok {
+		return GeneralError(err.Error()).WithCause(err)
+	}
+	if _, ok := err.(*local.SyncConflictError); ok {
+		return ConflictError(err.Error()).WithCause(err)
+	}
+	return WrapError("failed to change label", err)
+}
+
+// loadLabelColors passes the backend's label colors to the table formatter.
+// Colors are cosmetic, so a backend without label metadata or a registry
+// that fails to load leaves labels uncolored.
+func loadLabelColors(b backend.Backend) {
+	lister, ok := b.(backend.LabelLister)
+	if !ok {
+		return
+	}
+	labels, err := lister.ListLabels()
+	if err != nil {
+		slog.Info("could not load label colors", "backend", b.Name(), "error", err)
+		return
+	}
+	output.SetLabelColors(labels)
+}
+
+// warnUnknownLabels prints a warning for each label that is not in the
+// backend's label registry, when the workspace sets strict_labels.
+func warnUnknownLabels(b backend.Backend, ws *config.Workspace, labels []string) error {
+	if ws == nil || !ws.StrictLabels || len(labels) == 0 {
+		return nil
+	}
+	lister, ok := b.(backend.LabelLister)
+	if !ok {
+		return nil
+	}
+	known, err := lister.ListLabels()
+	if err != nil {
+		return WrapError("failed to check labels", err)
+	}
+
+	registered := make(map[string]bool, len(known))
+	for _, label := range known {
+		registered[label.Name] = true
+	}
+	for _, label := range labels {
+		if !registered[label] {
+			fmt.Fprintf(os.Stderr, "warning: label %q is not in the label registry\n", label)
+		}
+	}
+	return nil
+}
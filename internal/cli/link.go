@@ -1,9 +1,6 @@
 package cli
 
 import (
-	"fmt"
-	"os"
-
 	"github.com/alexbrand/backlog/internal/backend"
 	"github.com/alexbrand/backlog/internal/output"
 	"github.com/spf13/cobra"
@@ -24,7 +21,8 @@ Exactly one of --blocks or --blocked-by must be specified.
 Examples:
   backlog link 001 --blocks 002       # 001 blocks 002
   backlog link 001 --blocked-by 002   # 001 is blocked by 002`,
-	Args: cobra.ExactArgs(1),
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTaskIDArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runLink(args[0])
 	},
@@ -35,6 +33,9 @@ func init() {
 
 	linkCmd.Flags().StringVar(&linkBlocks, "blocks", "", "Target task ID that source blocks")
 	linkCmd.Flags().StringVar(&linkBlockedBy, "blocked-by", "", "Target task ID that blocks source")
+
+	linkCmd.RegisterFlagCompletionFunc("blocks", completeTaskIDs)
+	linkCmd.RegisterFlagCompletionFunc("blocked-by", completeTaskIDs)
 }
 
 func runLink(sourceID string) error {
@@ -47,7 +48,7 @@ func runLink(sourceID string) error {
 	}
 
 	// Get backend and connect
-	b, _, cleanup, err := connectBackend()
+	b, _, cleanup, err := connectWritableBackend()
 	if err != nil {
 		return err
 	}
@@ -56,7 +57,7 @@ func runLink(sourceID string) error {
 	// Check if backend supports relations
 	relater, ok := b.(backend.Relater)
 	if !ok {
-		return fmt.Errorf("backend %q does not support task dependencies", b.Name())
+		return UnsupportedError(b, "task dependencies")
 	}
 
 	var relationType backend.RelationType
@@ -75,5 +76,5 @@ func runLink(sourceID string) error {
 	}
 
 	formatter := output.New(output.Format(GetFormat()))
-	return formatter.FormatLinked(os.Stdout, relation, sourceID)
+	return formatter.FormatLinked(statusOutput(), relation, sourceID)
 }
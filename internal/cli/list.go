@@ -2,7 +2,10 @@ package cli
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/alexbrand/backlog/internal/backend"
 	"github.com/alexbrand/backlog/internal/output"
@@ -10,33 +13,78 @@ import (
 )
 
 var (
-	listStatus      []string
-	listPriority    []string
-	listAssignee    string
-	listLabels      []string
-	listLimit       int
-	listIncludeDone bool
+	listStatus         []string
+	listPriority       []string
+	listAssignee       string
+	listLabels         []string
+	listParent         string
+	listLimit          int
+	listIncludeDone    bool
+	listIncludeDeleted bool
+	listGroupBy        string
+	listSort           string
+	listFields         []string
+	listCreatedSince   string
+	listUpdatedSince   string
+	listCountOnly      bool
+	listHasComments    bool
+	listNoComments     bool
+	listMine           bool
 )
 
+// mineSort is the order of --mine results when --sort isn't given, the same
+// as the local backend's default: most urgent first, then oldest.
+var mineSort = []backend.SortKey{{Field: "priority", Desc: true}, {Field: "created"}}
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List tasks in the backlog",
 	Long: `List tasks from the backlog with optional filtering.
 
 By default, lists all non-done tasks. Use flags to filter by status,
-priority, assignee, or labels.
+priority, assignee, labels, or parent task.
+
+--sort takes comma-separated fields, each sorted ascending unless prefixed
+with - (e.g. --sort -priority,created for most urgent first, then oldest).
+Priorities ascend from none to urgent and statuses along the workflow.
+Ties fall back to the backend's default order.
+
+With --count-only, only the number of matching tasks is printed (capped by
+--limit), or {"count": N} with -f json. Backends that can count without
+fetching every task do so.
+
+--has-comments keeps only tasks with at least one comment and --no-comments
+only tasks with none, such as review tasks nobody has looked at yet.
+
+--mine lists the current agent's work: tasks assigned to @me as well as
+tasks claimed with the agent's label (agent:<id>), whoever they are
+assigned to. It combines with the other filters, which narrow both.
 
 Examples:
   backlog list                          # all non-done tasks
   backlog list --status=todo            # filter by status
   backlog list --assignee=@me           # my tasks
+  backlog list --mine --status=in-progress  # what I'm assigned or have claimed
   backlog list --assignee=unassigned    # unclaimed tasks
   backlog list --priority=high,urgent   # multiple values
   backlog list --label=bug              # by label
+  backlog list --parent=012             # sub-tasks of 012
   backlog list --limit=10               # pagination
   backlog list -f json                  # JSON output for agents
-  backlog list --include-done           # include completed tasks`,
+  backlog list --include-done           # include completed tasks
+  backlog list --group-by=status        # board view, one table per status
+  backlog list --sort=-duration --include-done  # longest-running tasks first
+  backlog list --sort=-priority,created  # most urgent first, then oldest
+  backlog list --no-defaults            # ignore command_defaults.list
+  backlog list --fields=id,title,assignee   # choose and order columns
+  backlog list --updated-since=2h       # tasks changed in the last 2 hours
+  backlog list --created-since=2025-01-15T09:00:00Z
+  backlog list --status=todo --count-only  # how many tasks are ready
+  backlog list --status=review --no-comments  # unreviewed work`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := applyCommandDefaults(cmd); err != nil {
+			return err
+		}
 		return runList()
 	},
 }
@@ -48,29 +96,44 @@ func init() {
 	listCmd.Flags().StringSliceVarP(&listPriority, "priority", "p", nil, "Filter by priority (can be specified multiple times or comma-separated)")
 	listCmd.Flags().StringVarP(&listAssignee, "assignee", "a", "", "Filter by assignee (use @me for current user, unassigned for no assignee)")
 	listCmd.Flags().StringSliceVarP(&listLabels, "label", "l", nil, "Filter by labels (task must have all specified labels)")
+	listCmd.Flags().StringVar(&listParent, "parent", "", "Filter to sub-tasks of the given task ID")
 	listCmd.Flags().IntVar(&listLimit, "limit", 0, "Maximum number of tasks to return (0 for no limit)")
 	listCmd.Flags().BoolVar(&listIncludeDone, "include-done", false, "Include tasks with done status")
+	listCmd.Flags().BoolVar(&listIncludeDeleted, "include-deleted", false, "Include soft-deleted tasks")
+	listCmd.Flags().StringVar(&listGroupBy, "group-by", "", "Group tasks by field: status, priority, assignee, label")
+	listCmd.Flags().StringVar(&listSort, "sort", "", "Sort tasks by comma-separated fields, - for descending (e.g. -priority,created): "+strings.Join(sortFields, ", "))
+	listCmd.Flags().StringVar(&listCreatedSince, "created-since", "", "Only tasks created since an RFC3339 time or a duration ago (e.g. 2h, 3d, 1w)")
+	listCmd.Flags().StringVar(&listUpdatedSince, "updated-since", "", "Only tasks updated since an RFC3339 time or a duration ago (e.g. 2h, 3d, 1w)")
+	listCmd.Flags().BoolVar(&listCountOnly, "count-only", false, "Print only the number of matching tasks")
+	listCmd.Flags().BoolVar(&listHasComments, "has-comments", false, "Only tasks with at least one comment")
+	listCmd.Flags().BoolVar(&listNoComments, "no-comments", false, "Only tasks without comments")
+	listCmd.Flags().BoolVar(&listMine, "mine", false, "Only tasks assigned to @me or claimed by the current agent")
+	listCmd.Flags().StringSliceVar(&listFields, "fields", nil, "Fields to output, in order (table, plain, and json): "+strings.Join(output.FieldNames(), ", "))
+	addNoDefaultsFlag(listCmd)
+
+	listCmd.RegisterFlagCompletionFunc("status", completeStatuses)
+	listCmd.RegisterFlagCompletionFunc("priority", completePriorities)
+	listCmd.RegisterFlagCompletionFunc("label", completeLabels)
+	listCmd.RegisterFlagCompletionFunc("parent", completeTaskIDs)
+	listCmd.RegisterFlagCompletionFunc("group-by", cobra.FixedCompletions(groupByFields, cobra.ShellCompDirectiveNoFileComp))
+	listCmd.RegisterFlagCompletionFunc("sort", cobra.FixedCompletions(sortFields, cobra.ShellCompDirectiveNoFileComp))
+	listCmd.RegisterFlagCompletionFunc("fields", cobra.FixedCompletions(output.FieldNames(), cobra.ShellCompDirectiveNoFileComp))
 }
 
 func runList() error {
-	// Output debug information if verbose mode is enabled
-	if IsVerbose() {
-		fmt.Fprintln(os.Stderr, "debug: listing tasks with filters")
-	}
-
 	// Validate and parse statuses
 	var statusFilters []backend.Status
 	includeDone := listIncludeDone
 	for _, s := range listStatus {
 		// Special handling for "all" which means all statuses including done
-		if s == "all" {
+		if normalizeKey(s) == "all" {
 			statusFilters = backend.ValidStatuses()
 			includeDone = true
 			break
 		}
-		status := backend.Status(s)
-		if !status.IsValid() {
-			return InvalidInputError(fmt.Sprintf("invalid status %q (valid: backlog, todo, in-progress, review, done)", s))
+		status, err := parseStatus(s)
+		if err != nil {
+			return err
 		}
 		statusFilters = append(statusFilters, status)
 	}
@@ -78,37 +141,232 @@ func runList() error {
 	// Validate and parse priorities
 	var priorityFilters []backend.Priority
 	for _, p := range listPriority {
-		priority := backend.Priority(p)
-		if !priority.IsValid() {
-			return InvalidInputError(fmt.Sprintf("invalid priority %q (valid: urgent, high, medium, low, none)", p))
+		priority, err := parsePriority(p)
+		if err != nil {
+			return err
 		}
 		priorityFilters = append(priorityFilters, priority)
 	}
 
+	if listCountOnly && listGroupBy != "" {
+		return InvalidInputError("--count-only cannot be combined with --group-by")
+	}
+	if listMine && listAssignee != "" {
+		return InvalidInputError("--mine cannot be combined with --assignee")
+	}
+
+	var hasComments *bool
+	switch {
+	case listHasComments && listNoComments:
+		return InvalidInputError("--has-comments and --no-comments cannot be used together")
+	case listHasComments, listNoComments:
+		hasComments = &listHasComments
+	}
+
+	var groupBy string
+	if listGroupBy != "" {
+		field, err := parseGroupBy(listGroupBy)
+		if err != nil {
+			return err
+		}
+		groupBy = field
+	}
+
+	var sortKeys []backend.SortKey
+	if listSort != "" {
+		keys, err := parseSort(listSort)
+		if err != nil {
+			return err
+		}
+		sortKeys = keys
+	}
+
+	if listFields != nil {
+		fields, err := output.ParseFields(listFields)
+		if err != nil {
+			return InvalidInputError(err.Error())
+		}
+		output.SetFields(fields)
+	}
+
+	// Resolve time cutoffs against a single clock reading
+	var applied backend.AppliedFilters
+	now := time.Now()
+	var createdSince, updatedSince time.Time
+	if listCreatedSince != "" {
+		t, err := backend.ParseSince(listCreatedSince, now)
+		if err != nil {
+			return InvalidInputError("--created-since: " + err.Error())
+		}
+		createdSince = t
+		applied.CreatedSince = &createdSince
+	}
+	if listUpdatedSince != "" {
+		t, err := backend.ParseSince(listUpdatedSince, now)
+		if err != nil {
+			return InvalidInputError("--updated-since: " + err.Error())
+		}
+		updatedSince = t
+		applied.UpdatedSince = &updatedSince
+	}
+
 	// Build filters
 	filters := backend.TaskFilters{
-		Status:      statusFilters,
-		Priority:    priorityFilters,
-		Assignee:    listAssignee,
-		Labels:      listLabels,
-		Limit:       listLimit,
-		IncludeDone: includeDone,
+		Status:         statusFilters,
+		Priority:       priorityFilters,
+		Assignee:       listAssignee,
+		Labels:         listLabels,
+		Parent:         listParent,
+		Limit:          listLimit,
+		IncludeDone:    includeDone,
+		IncludeDeleted: listIncludeDeleted,
+		CreatedSince:   createdSince,
+		UpdatedSince:   updatedSince,
+		HasComments:    hasComments,
 	}
 
+	slog.Info("listing tasks",
+		"status", statusFilters,
+		"priority", priorityFilters,
+		"assignee", filters.Assignee,
+		"labels", filters.Labels,
+		"parent", filters.Parent,
+		"limit", filters.Limit,
+		"include_done", filters.IncludeDone,
+		"include_deleted", filters.IncludeDeleted,
+		"mine", listMine,
+		"created_since", createdSince,
+		"updated_since", updatedSince)
+
 	// Get backend and connect
-	b, _, cleanup, err := connectBackend()
+	b, ws, cleanup, err := connectBackend()
 	if err != nil {
 		return err
 	}
 	defer cleanup()
 
-	// List tasks
-	taskList, err := b.List(filters)
+	// --mine is an OR of two filter sets, which backends can't express in
+	// a single query
+	var filterSets []backend.TaskFilters
+	if listMine {
+		agent, err := requireAgentID(ws)
+		if err != nil {
+			return err
+		}
+		filterSets = mineFilters(filters, agentLabelPrefix(ws)+":"+agent)
+	}
+
+	if listCountOnly {
+		return runListCount(b, filters, filterSets)
+	}
+
+	// Backends that can't sort are sorted here, and since the limit must
+	// apply after sorting, they fetch everything and are trimmed here
+	sorter, canSort := b.(backend.Sorter)
+	list := b.List
+	if sortKeys != nil && canSort {
+		list = func(f backend.TaskFilters) (*backend.TaskList, error) {
+			return sorter.ListSorted(f, sortKeys)
+		}
+	}
+	sortHere := sortKeys != nil && !canSort
+	if sortHere {
+		filters.Limit = 0
+	}
+
+	// List tasks. A union is always sorted and trimmed here.
+	var taskList *backend.TaskList
+	if filterSets != nil {
+		taskList, err = backend.ListAny(list, filterSets...)
+		sortHere = true
+		if sortKeys == nil {
+			sortKeys = mineSort
+		}
+	} else {
+		taskList, err = list(filters)
+	}
 	if err != nil {
 		return WrapError("failed to list tasks", err)
 	}
 
+	if sortHere {
+		backend.SortTasks(taskList.Tasks, sortKeys)
+		if listLimit > 0 && len(taskList.Tasks) > listLimit {
+			taskList.Tasks = taskList.Tasks[:listLimit]
+			taskList.Count = listLimit
+			taskList.HasMore = true
+		}
+	}
+
+	if applied.CreatedSince != nil || applied.UpdatedSince != nil {
+		taskList.Filters = &applied
+	}
+
+	refreshCompletionCache(taskList.Tasks)
+
+	// Surface skipped files on stderr so they don't vanish unnoticed
+	for _, warning := range taskList.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+	}
+
+	// Label colors are only needed to paint table output
+	if output.Format(GetFormat()) == output.FormatTable && output.ColorEnabled() {
+		loadLabelColors(b)
+	}
+
 	// Output the result
 	formatter := output.New(output.Format(GetFormat()))
+	if groupBy != "" {
+		return formatter.FormatGroupedTaskList(os.Stdout, groupBy, groupTasks(taskList.Tasks, groupBy), taskList)
+	}
 	return formatter.FormatTaskList(os.Stdout, taskList)
 }
+
+// runListCount prints the number of tasks matching the filters, or any of
+// filterSets if given, capped by --limit. Backends implementing
+// backend.Counter count without fetching every task; others, and unions,
+// fall back to listing.
+func runListCount(b backend.Backend, filters backend.TaskFilters, filterSets []backend.TaskFilters) error {
+	var count int
+	if filterSets != nil {
+		taskList, err := backend.ListAny(b.List, filterSets...)
+		if err != nil {
+			return WrapError("failed to count tasks", err)
+		}
+		for _, warning := range taskList.Warnings {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+		}
+		count = len(taskList.Tasks)
+	} else if counter, ok := b.(backend.Counter); ok {
+		n, err := counter.Count(filters)
+		if err != nil {
+			return WrapError("failed to count tasks", err)
+		}
+		count = n
+	} else {
+		filters.Limit = 0
+		taskList, err := b.List(filters)
+		if err != nil {
+			return WrapError("failed to count tasks", err)
+		}
+		for _, warning := range taskList.Warnings {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+		}
+		count = len(taskList.Tasks)
+	}
+
+	if listLimit > 0 && count > listLimit {
+		count = listLimit
+	}
+	return output.New(output.Format(GetFormat())).FormatTaskCount(os.Stdout, count)
+}
+
+// mineFilters returns the filter sets --mine lists the union of: tasks
+// assigned to @me and tasks labeled agentLabel, both narrowed by filters.
+func mineFilters(filters backend.TaskFilters, agentLabel string) []backend.TaskFilters {
+	assigned := filters
+	assigned.Assignee = "@me"
+	claimed := filters
+	claimed.Labels = append(append([]string{}, filters.Labels...), agentLabel)
+	return []backend.TaskFilters{assigned, claimed}
+}
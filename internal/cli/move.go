@@ -11,7 +11,12 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var moveComment string
+var (
+	moveComment   string
+	moveStrict    bool
+	moveForceSync bool
+	moveAssignee  string
+)
 
 var moveCmd = &cobra.Command{
 	Use:   "move <id> <status>",
@@ -20,64 +25,144 @@ var moveCmd = &cobra.Command{
 
 Valid statuses: backlog, todo, in-progress, review, done
 
+Moving a task to done while it has sub-tasks that are not done prints a
+warning. With --strict the move is refused instead (exit code 2).
+
+With git_sync enabled, a move is refused while the remote has changes that
+haven't been pulled. --force-sync pulls them first (rebasing local commits)
+and then moves the task, failing only if the rebase itself conflicts.
+
+--assignee sets the assignee along with the status, as a single commit for
+the local backend with git_sync. Use --assignee="" to unassign the task.
+
+After the move, the workspace's hooks.on_move commands run, followed by any
+hooks.on_transition commands whose from->to key matches the move.
+
 Examples:
   backlog move 001 in-progress
+  backlog move 001 in-progress --assignee alice
   backlog move 001 done
+  backlog move 012 done --strict
   backlog move 001 review --comment="Ready for review"
+  backlog move 001 done --force-sync
   backlog move 001 review -f json`,
-	Args: cobra.ExactArgs(2),
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeMoveArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runMove(args[0], args[1], moveComment)
+		var assignee *string
+		if cmd.Flags().Changed("assignee") {
+			assignee = &moveAssignee
+		}
+		return runMove(args[0], args[1], moveComment, assignee)
 	},
 }
 
 func init() {
 	moveCmd.Flags().StringVar(&moveComment, "comment", "", "Add a comment when moving the task")
+	moveCmd.Flags().BoolVar(&moveStrict, "strict", false, "Refuse to move a task to done while it has open sub-tasks")
+	moveCmd.Flags().BoolVar(&moveForceSync, "force-sync", false, "Pull remote changes before moving instead of failing when the remote is ahead")
+	moveCmd.Flags().StringVar(&moveAssignee, "assignee", "", "Also assign the task to this user (empty to unassign)")
 	rootCmd.AddCommand(moveCmd)
 }
 
-func runMove(id, statusStr, comment string) error {
+// completeMoveArgs completes the task ID, then the target status.
+func completeMoveArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return completeTaskIDs(cmd, args, toComplete)
+	case 1:
+		return completeStatuses(cmd, args, toComplete)
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func runMove(id, statusStr, comment string, assignee *string) error {
 	// Validate status
-	status := backend.Status(statusStr)
-	if !status.IsValid() {
-		return InvalidInputError(fmt.Sprintf("invalid status %q (valid: backlog, todo, in-progress, review, done)", statusStr))
+	status, err := parseStatus(statusStr)
+	if err != nil {
+		return err
 	}
 
 	// Get backend and connect
-	b, _, cleanup, err := connectBackend()
+	b, ws, cleanup, err := connectWritableBackend()
 	if err != nil {
 		return err
 	}
 	defer cleanup()
 
+	// Pull first so the task is read as the remote left it
+	if moveForceSync && !IsDryRun() {
+		puller, ok := b.(backend.Puller)
+		if !ok {
+			return UnsupportedError(b, "--force-sync")
+		}
+		if err := puller.Pull(); err != nil {
+			if _, ok := err.(*local.UncommittedChangesError); ok {
+				return GeneralError(err.Error()).WithCause(err)
+			}
+			if _, ok := err.(*local.SyncConflictError); ok {
+				return ConflictError(err.Error()).WithCause(err)
+			}
+			return err
+		}
+	}
+
 	// Get the current task first to capture old status
 	currentTask, err := b.Get(id)
 	if err != nil {
-		// Check if this is a "not found" error (case-insensitive check for 404/Not Found)
-		errLower := strings.ToLower(err.Error())
-		if strings.Contains(errLower, "not found") || strings.Contains(errLower, "404") {
-			return NotFoundError(err.Error())
+		if isNotFound(err) {
+			return NotFoundError(err.Error()).WithCause(err)
 		}
 		return err
 	}
 
 	oldStatus := currentTask.Status
 
+	// Completing a parent with open sub-tasks is allowed, but flagged
+	if status == backend.StatusDone {
+		children, err := b.List(backend.TaskFilters{Parent: currentTask.ID})
+		if err != nil {
+			return fmt.Errorf("failed to list sub-tasks: %w", err)
+		}
+		if children.Count > 0 {
+			ids := make([]string, len(children.Tasks))
+			for i, child := range children.Tasks {
+				ids[i] = child.ID
+			}
+			message := fmt.Sprintf("task %s has %d open sub-task(s): %s", currentTask.ID, children.Count, strings.Join(ids, ", "))
+			if moveStrict {
+				return ConflictError(message)
+			}
+			fmt.Fprintf(os.Stderr, "warning: %s\n", message)
+		}
+	}
+
+	if IsDryRun() {
+		if assignee != nil {
+			currentTask.Assignee = *assignee
+		}
+		return previewMove(currentTask, status)
+	}
+
 	// Move the task
-	task, err := b.Move(id, status)
+	var task *backend.Task
+	if assignee != nil {
+		task, err = moveAndAssign(b, id, status, *assignee)
+	} else {
+		task, err = b.Move(id, status)
+	}
 	if err != nil {
 		// Check for uncommitted changes error (exit code 1)
 		if _, ok := err.(*local.UncommittedChangesError); ok {
-			return GeneralError(err.Error())
+			return GeneralError(err.Error()).WithCause(err)
 		}
 		// Check for sync conflict error (exit code 2)
 		if _, ok := err.(*local.SyncConflictError); ok {
-			return ConflictError(err.Error())
+			return ConflictError(err.Error()).WithCause(err)
 		}
-		// Check if this is a "not found" error (case-insensitive check for 404/Not Found)
-		errLower := strings.ToLower(err.Error())
-		if strings.Contains(errLower, "not found") || strings.Contains(errLower, "404") {
-			return NotFoundError(err.Error())
+		if isNotFound(err) {
+			return NotFoundError(err.Error()).WithCause(err)
 		}
 		return err
 	}
@@ -89,7 +174,34 @@ func runMove(id, statusStr, comment string) error {
 		}
 	}
 
+	if err := runHooks(ws, hookEvent{Event: hookEventMove, Task: task, PreviousStatus: oldStatus, Agent: ResolveAgentID(ws)}); err != nil {
+		return err
+	}
+
 	// Output the result
 	formatter := output.New(output.Format(GetFormat()))
-	return formatter.FormatMoved(os.Stdout, task, oldStatus, status)
+	return formatter.FormatMoved(statusOutput(), task, oldStatus, status, backend.DiffTasks(currentTask, task))
+}
+
+// moveAndAssign moves a task and sets its assignee, in one operation when
+// the backend supports it.
+func moveAndAssign(b backend.Backend, id string, status backend.Status, assignee string) (*backend.Task, error) {
+	if mover, ok := b.(backend.MoveAssigner); ok {
+		return mover.MoveAndAssign(id, status, assignee)
+	}
+
+	if _, err := b.Move(id, status); err != nil {
+		return nil, err
+	}
+	var task *backend.Task
+	var err error
+	if assignee == "" {
+		task, err = b.Unassign(id)
+	} else {
+		task, err = b.Assign(id, assignee)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("task moved but failed to set assignee: %w", err)
+	}
+	return task, nil
 }
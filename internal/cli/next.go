@@ -3,18 +3,26 @@ package cli
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/alexbrand/backlog/internal/backend"
-	"github.com/alexbrand/backlog/internal/github"
-	"github.com/alexbrand/backlog/internal/local"
+	"github.com/alexbrand/backlog/internal/config"
 	"github.com/alexbrand/backlog/internal/output"
 	"github.com/spf13/cobra"
 )
 
 var (
-	nextClaim  bool
-	nextLabels []string
+	nextClaim        bool
+	nextCount        int
+	nextLabels       []string
+	nextMaxAttempts  int
+	nextPriority     []string
+	nextWait         time.Duration
+	nextWaiting      bool
+	nextPollInterval time.Duration
+	nextHeartbeat    bool
 )
 
 var nextCmd = &cobra.Command{
@@ -28,14 +36,44 @@ that need to pick up the next available work item.
 By default, considers tasks with status 'todo' or 'backlog' that have no assignee.
 Tasks are sorted by priority (urgent > high > medium > low > none).
 
-Use --claim to atomically claim the task, preventing other agents from working on it.
+Use --claim to find and claim a task in one step, preventing other agents
+from working on it. If another agent claims a candidate first, the next best
+candidate is tried, up to --max-attempts claims. When every attempt loses,
+the command exits with code 2. JSON output lists the skipped candidates and
+why each was skipped in "candidates_skipped".
+
+Use --count with --claim to reserve a batch of up to N tasks. Each claim is
+independent, so fewer than N may be claimed; the command only fails when
+none are. The claimed tasks are printed as a list (a JSON array). In git
+lock mode the batch is claimed with a single pull, commit, and push,
+retried if another agent pushes first.
+
+Use --wait to block until a task is available instead of returning nothing.
+The backlog is checked every --poll-interval (default 5s, varied a little so
+that agents started together spread out); the local backend also wakes as
+soon as a task file changes. --wait=10m gives up after ten minutes with exit
+code 7; a bare --wait waits forever. SIGINT or SIGTERM ends the wait with
+exit code 130, after any check in progress finishes. With --claim, losing
+every candidate to other agents keeps waiting rather than exiting with
+code 2. With --heartbeat, each poll that finds nothing writes a line:
+{"heartbeat": true, "waited_seconds": N} on stdout in JSON mode, or a
+message on stderr otherwise. Without it, stdout only ever holds the result.
 
 Examples:
   backlog next                    # get highest priority unassigned task
   backlog next --label=backend    # filter by label
+  backlog next --priority=urgent,high  # only urgent or high tasks
   backlog next --claim            # get and claim the task
-  backlog next --claim -f json    # claim and output as JSON`,
+  backlog next --claim -f json    # claim and output as JSON
+  backlog next --claim --max-attempts=5
+  backlog next --claim --count=3 -f json
+  backlog next --claim --wait=10m -f json
+  backlog next --claim --wait --poll-interval=30s --heartbeat -f json`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := applyCommandDefaults(cmd); err != nil {
+			return err
+		}
+		nextWaiting = cmd.Flags().Changed("wait")
 		return runNext()
 	},
 }
@@ -44,7 +82,18 @@ func init() {
 	rootCmd.AddCommand(nextCmd)
 
 	nextCmd.Flags().BoolVar(&nextClaim, "claim", false, "Atomically claim the task after finding it")
+	nextCmd.Flags().IntVar(&nextCount, "count", 1, "With --claim, the number of tasks to claim")
+	nextCmd.Flags().IntVar(&nextMaxAttempts, "max-attempts", 3, "With --claim, the maximum number of candidates to try claiming")
 	nextCmd.Flags().StringSliceVarP(&nextLabels, "label", "l", nil, "Filter by labels (task must have all specified labels)")
+	nextCmd.Flags().StringSliceVarP(&nextPriority, "priority", "p", nil, "Filter by priority (can be specified multiple times or comma-separated)")
+	nextCmd.Flags().DurationVar(&nextWait, "wait", 0, "Wait until a task is available, giving up after the given time (--wait alone waits forever)")
+	nextCmd.Flags().Lookup("wait").NoOptDefVal = "0s"
+	nextCmd.Flags().DurationVar(&nextPollInterval, "poll-interval", 5*time.Second, "With --wait, how often to check for a task")
+	nextCmd.Flags().BoolVar(&nextHeartbeat, "heartbeat", false, "With --wait, report each poll that finds no task")
+	addNoDefaultsFlag(nextCmd)
+
+	nextCmd.RegisterFlagCompletionFunc("label", completeLabels)
+	nextCmd.RegisterFlagCompletionFunc("priority", completePriorities)
 }
 
 // priorityOrder maps priorities to numeric order for sorting (lower = higher priority)
@@ -57,78 +106,228 @@ var priorityOrder = map[backend.Priority]int{
 }
 
 func runNext() error {
+	if nextClaim && IsDryRun() {
+		return InvalidInputError("--dry-run is not supported with next --claim")
+	}
+	if nextMaxAttempts < 1 {
+		return InvalidInputError("--max-attempts must be at least 1")
+	}
+	if nextCount < 1 {
+		return InvalidInputError("--count must be at least 1")
+	}
+	if nextCount > 1 && !nextClaim {
+		return InvalidInputError("--count requires --claim")
+	}
+	if nextWait < 0 {
+		return InvalidInputError("--wait must not be negative")
+	}
+	if nextPollInterval <= 0 {
+		return InvalidInputError("--poll-interval must be positive")
+	}
+	if nextHeartbeat && !nextWaiting {
+		return InvalidInputError("--heartbeat requires --wait")
+	}
+
+	var priorityFilters []backend.Priority
+	for _, p := range nextPriority {
+		priority, err := parsePriority(p)
+		if err != nil {
+			return err
+		}
+		priorityFilters = append(priorityFilters, priority)
+	}
+
 	// Build filters to find unclaimed tasks
 	filters := backend.TaskFilters{
 		Status:      []backend.Status{backend.StatusTodo, backend.StatusBacklog},
+		Priority:    priorityFilters,
 		Assignee:    "unassigned",
 		Labels:      nextLabels,
 		IncludeDone: false,
 	}
 
-	// Get backend and connect
-	b, ws, cleanup, err := connectBackend()
+	// Get backend and connect; only --claim changes anything
+	connect := connectBackend
+	if nextClaim {
+		connect = connectWritableBackend
+	}
+	b, ws, cleanup, err := connect()
 	if err != nil {
 		return err
 	}
 	defer cleanup()
 
-	// List tasks
+	var w *waiter
+	if nextWaiting {
+		w = newWaiter(b, nextWait, nextPollInterval, nextHeartbeat)
+		defer w.close()
+	}
+	for {
+		found, err := findNext(b, ws, filters)
+		// While waiting, losing every candidate to other agents is like
+		// finding none: more may become available
+		if w != nil && err != nil && GetErrorCode(err) == ErrorCodeClaimConflict {
+			found, err = false, nil
+		}
+		if found || err != nil || w == nil {
+			return err
+		}
+		if err := w.wait(); err != nil {
+			return err
+		}
+	}
+}
+
+// findNext looks for the next task once and prints it, or claims and prints
+// it with --claim. It reports whether there was one. Finding none is not an
+// error, so that agents can check for available work without error handling.
+func findNext(b backend.Backend, ws *config.Workspace, filters backend.TaskFilters) (bool, error) {
 	taskList, err := b.List(filters)
 	if err != nil {
-		return fmt.Errorf("failed to list tasks: %w", err)
+		return false, fmt.Errorf("failed to list tasks: %w", err)
 	}
-
-	// If no tasks found, return success with no output
-	// This allows agents to check for available work without error handling
 	if taskList.Count == 0 {
-		return nil
+		return false, nil
 	}
 
-	// Find the highest priority unblocked task
 	var relater backend.Relater
 	if r, ok := b.(backend.Relater); ok {
 		relater = r
 	}
+
+	if nextClaim {
+		return claimNext(b, ws, taskList.Tasks, relater)
+	}
+
+	// Find the highest priority unblocked task
 	nextTask := findHighestPriorityUnblockedTask(taskList.Tasks, relater)
 	if nextTask == nil {
-		return nil
+		return false, nil
 	}
 
 	formatter := output.New(output.Format(GetFormat()))
+	return true, formatter.FormatTask(os.Stdout, nextTask)
+}
 
-	// If --claim flag is set, claim the task
-	if nextClaim {
-		// Check if backend supports claiming
-		claimer, ok := b.(backend.Claimer)
-		if !ok {
-			return fmt.Errorf("backend %q does not support task claiming", b.Name())
+// claimNext claims the best unblocked candidates from tasks, up to --count,
+// trying them in priority order. A candidate claimed first by another agent
+// is skipped in favor of the next one. Every lost claim costs an attempt, and
+// without batch support each attempt is a full backend claim (for git lock
+// mode, a pull/commit/push cycle), so at most --max-attempts - 1 candidates
+// are lost before giving up. Backends that support batch claims claim
+// --count > 1 tasks in one round trip. It reports whether any task was
+// claimed.
+func claimNext(b backend.Backend, ws *config.Workspace, tasks []backend.Task, relater backend.Relater) (bool, error) {
+	claimer, ok := b.(backend.Claimer)
+	if !ok {
+		return false, UnsupportedError(b, "task claiming")
+	}
+
+	// Stable, so equal priorities keep the backend's (oldest first) order
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return priorityOrder[tasks[i].Priority] < priorityOrder[tasks[j].Priority]
+	})
+
+	resolvedAgentID, err := requireAgentID(ws)
+	if err != nil {
+		return false, err
+	}
+
+	// Blocked candidates are passed over without costing an attempt
+	skipped := []backend.SkippedCandidate{}
+	var candidates []string
+	previousStatus := make(map[string]backend.Status)
+	position := make(map[string]int)
+	for i, task := range tasks {
+		if len(candidates) == nextCount+nextMaxAttempts-1 {
+			break
+		}
+		position[task.ID] = i
+		if reason := blockedReason(task.ID, relater); reason != "" {
+			skipped = append(skipped, backend.SkippedCandidate{ID: task.ID, Reason: reason})
+			continue
 		}
+		candidates = append(candidates, task.ID)
+		previousStatus[task.ID] = task.Status
+	}
 
-		// Resolve agent ID
-		resolvedAgentID := ResolveAgentID(ws)
+	// Nothing was claimable, e.g. every candidate is blocked
+	if len(candidates) == 0 {
+		return false, nil
+	}
 
-		// Attempt to claim the task
-		result, err := claimer.Claim(nextTask.ID, resolvedAgentID)
-		if err != nil {
-			// Check for conflict error (task already claimed by another agent)
-			if _, isLocalConflict := err.(*local.ClaimConflictError); isLocalConflict {
-				return ConflictError(err.Error())
-			}
-			if _, isGitHubConflict := err.(*github.ClaimConflictError); isGitHubConflict {
-				return ConflictError(err.Error())
-			}
-			// Check for not found error
-			if strings.Contains(err.Error(), "not found") {
-				return NotFoundError(err.Error())
-			}
-			return err
+	var result *backend.BatchClaimResult
+	if batcher, ok := b.(backend.BatchClaimer); ok && nextCount > 1 {
+		result, err = batcher.ClaimBatch(candidates, resolvedAgentID, nextCount)
+	} else {
+		result, err = claimEach(claimer, candidates, resolvedAgentID, nextCount)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// Report skipped candidates in priority order, however they were skipped
+	skipped = append(skipped, result.Skipped...)
+	sort.SliceStable(skipped, func(i, j int) bool {
+		return position[skipped[i].ID] < position[skipped[j].ID]
+	})
+
+	if len(result.Claimed) == 0 {
+		reasons := make([]string, len(skipped))
+		for i, c := range skipped {
+			reasons[i] = fmt.Sprintf("%s (%s)", c.ID, c.Reason)
+		}
+		return false, &ExitCodeError{
+			Code:      ExitConflict,
+			ErrorCode: ErrorCodeClaimConflict,
+			Message:   fmt.Sprintf("could not claim a task after %d attempt(s); skipped %s", len(result.Skipped), strings.Join(reasons, ", ")),
+			Details:   map[string]any{"candidates_skipped": skipped},
+		}
+	}
+
+	for _, claim := range result.Claimed {
+		if claim.AlreadyOwned {
+			continue
+		}
+		announceClaim(b, ws, &claim, resolvedAgentID, "")
+		event := hookEvent{Event: hookEventClaim, Task: claim.Task, PreviousStatus: previousStatus[claim.Task.ID], Agent: resolvedAgentID}
+		if err := runHooks(ws, event); err != nil {
+			return true, err
 		}
+	}
 
-		return formatter.FormatClaimed(os.Stdout, result.Task, resolvedAgentID, result.AlreadyOwned)
+	formatter := output.New(output.Format(GetFormat()))
+	if nextCount == 1 {
+		return true, formatter.FormatNextClaimed(statusOutput(), &result.Claimed[0], resolvedAgentID, skipped)
 	}
+	return true, formatter.FormatNextClaimedBatch(statusOutput(), result.Claimed, resolvedAgentID, skipped)
+}
 
-	// Output the task without claiming
-	return formatter.FormatTask(os.Stdout, nextTask)
+// claimEach claims up to count of the candidates one at a time, for
+// backends without batch claims. Candidates claimed by another agent or
+// deleted since they were listed are skipped.
+func claimEach(claimer backend.Claimer, ids []string, agentID string, count int) (*backend.BatchClaimResult, error) {
+	result := &backend.BatchClaimResult{Claimed: []backend.ClaimResult{}, Skipped: []backend.SkippedCandidate{}}
+	for _, id := range ids {
+		if len(result.Claimed) == count {
+			break
+		}
+		claim, err := claimer.Claim(id, agentID)
+		if err != nil {
+			switch {
+			case isClaimConflict(err):
+				result.Skipped = append(result.Skipped, backend.SkippedCandidate{ID: id, Reason: err.Error()})
+				continue
+			case isNotFound(err):
+				// Deleted since it was listed
+				result.Skipped = append(result.Skipped, backend.SkippedCandidate{ID: id, Reason: "task no longer exists"})
+				continue
+			}
+			return nil, err
+		}
+		result.Claimed = append(result.Claimed, *claim)
+	}
+	return result, nil
 }
 
 // findHighestPriorityTask returns the task with the highest priority from the list.
@@ -170,24 +369,34 @@ func findHighestPriorityUnblockedTask(tasks []backend.Task, relater backend.Rela
 
 	// Iterate in priority order, checking blockers lazily
 	for i := range sorted {
-		relations, err := relater.ListRelations(sorted[i].ID)
-		if err != nil {
-			// If we can't check relations, treat as unblocked
+		if blockedReason(sorted[i].ID, relater) == "" {
 			return &sorted[i]
 		}
+	}
 
-		blocked := false
-		for _, r := range relations {
-			if r.Type == backend.RelationBlockedBy && r.TaskStatus != backend.StatusDone {
-				blocked = true
-				break
-			}
-		}
+	return nil
+}
 
-		if !blocked {
-			return &sorted[i]
-		}
+// blockedReason describes the unfinished tasks blocking id, or returns an
+// empty string if it is unblocked. Tasks whose relations cannot be loaded
+// are treated as unblocked.
+func blockedReason(id string, relater backend.Relater) string {
+	if relater == nil {
+		return ""
+	}
+	relations, err := relater.ListRelations(id)
+	if err != nil {
+		return ""
 	}
 
-	return nil
+	var blockers []string
+	for _, r := range relations {
+		if r.Type == backend.RelationBlockedBy && r.TaskStatus != backend.StatusDone {
+			blockers = append(blockers, r.TaskID)
+		}
+	}
+	if len(blockers) == 0 {
+		return ""
+	}
+	return "blocked by " + strings.Join(blockers, ", ")
 }
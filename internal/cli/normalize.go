@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// priorityAliases maps normalized user input to canonical priorities.
+var priorityAliases = map[string]backend.Priority{
+	"urgent":   backend.PriorityUrgent,
+	"p0":       backend.PriorityUrgent,
+	"critical": backend.PriorityUrgent,
+	"high":     backend.PriorityHigh,
+	"p1":       backend.PriorityHigh,
+	"medium":   backend.PriorityMedium,
+	"med":      backend.PriorityMedium,
+	"p2":       backend.PriorityMedium,
+	"low":      backend.PriorityLow,
+	"p3":       backend.PriorityLow,
+	"none":     backend.PriorityNone,
+	"p4":       backend.PriorityNone,
+}
+
+// statusAliases maps normalized user input to canonical statuses.
+var statusAliases = map[string]backend.Status{
+	"backlog":     backend.StatusBacklog,
+	"icebox":      backend.StatusBacklog,
+	"todo":        backend.StatusTodo,
+	"to-do":       backend.StatusTodo,
+	"ready":       backend.StatusTodo,
+	"in-progress": backend.StatusInProgress,
+	"inprogress":  backend.StatusInProgress,
+	"wip":         backend.StatusInProgress,
+	"doing":       backend.StatusInProgress,
+	"started":     backend.StatusInProgress,
+	"review":      backend.StatusReview,
+	"in-review":   backend.StatusReview,
+	"reviewing":   backend.StatusReview,
+	"done":        backend.StatusDone,
+	"complete":    backend.StatusDone,
+	"completed":   backend.StatusDone,
+	"closed":      backend.StatusDone,
+	"finished":    backend.StatusDone,
+}
+
+// normalizeKey lowercases input and treats underscores and spaces like
+// hyphens, so "In_Progress" and "in progress" both become "in-progress".
+func normalizeKey(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	return strings.NewReplacer("_", "-", " ", "-").Replace(s)
+}
+
+// parsePriority converts user input to a canonical priority, accepting any
+// case and common aliases (p0-p3, med). Backends only ever see the result.
+func parsePriority(s string) (backend.Priority, error) {
+	if p, ok := priorityAliases[normalizeKey(s)]; ok {
+		return p, nil
+	}
+	return "", InvalidInputError(fmt.Sprintf("invalid priority %q (valid: urgent, high, medium, low, none; aliases: p0, p1, p2, med, p3)", s))
+}
+
+// parseStatus converts user input to a canonical status, accepting any case
+// and common aliases (wip, doing, closed). Backends only ever see the result.
+func parseStatus(s string) (backend.Status, error) {
+	if st, ok := statusAliases[normalizeKey(s)]; ok {
+		return st, nil
+	}
+	return "", InvalidInputError(fmt.Sprintf("invalid status %q (valid: backlog, todo, in-progress, review, done; aliases: inprogress, in_progress, wip, doing, complete, closed)", s))
+}
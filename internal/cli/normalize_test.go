@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected backend.Priority
+		wantErr  bool
+	}{
+		{"urgent", backend.PriorityUrgent, false},
+		{"URGENT", backend.PriorityUrgent, false},
+		{"p0", backend.PriorityUrgent, false},
+		{"critical", backend.PriorityUrgent, false},
+		{"High", backend.PriorityHigh, false},
+		{"P1", backend.PriorityHigh, false},
+		{"medium", backend.PriorityMedium, false},
+		{"med", backend.PriorityMedium, false},
+		{"p2", backend.PriorityMedium, false},
+		{"low", backend.PriorityLow, false},
+		{"p3", backend.PriorityLow, false},
+		{"none", backend.PriorityNone, false},
+		{" low ", backend.PriorityLow, false},
+		{"whenever", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parsePriority(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePriority(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("parsePriority(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+			if tt.wantErr && GetExitCode(err) != ExitError {
+				t.Errorf("parsePriority(%q) exit code = %d, want %d", tt.input, GetExitCode(err), ExitError)
+			}
+		})
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected backend.Status
+		wantErr  bool
+	}{
+		{"backlog", backend.StatusBacklog, false},
+		{"Backlog", backend.StatusBacklog, false},
+		{"todo", backend.StatusTodo, false},
+		{"TODO", backend.StatusTodo, false},
+		{"to-do", backend.StatusTodo, false},
+		{"to_do", backend.StatusTodo, false},
+		{"in-progress", backend.StatusInProgress, false},
+		{"inprogress", backend.StatusInProgress, false},
+		{"in_progress", backend.StatusInProgress, false},
+		{"In Progress", backend.StatusInProgress, false},
+		{"wip", backend.StatusInProgress, false},
+		{"doing", backend.StatusInProgress, false},
+		{"review", backend.StatusReview, false},
+		{"in_review", backend.StatusReview, false},
+		{"done", backend.StatusDone, false},
+		{"complete", backend.StatusDone, false},
+		{"Closed", backend.StatusDone, false},
+		{"someday", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseStatus(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseStatus(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("parseStatus(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAliasesMapToValidValues(t *testing.T) {
+	for alias, p := range priorityAliases {
+		if !p.IsValid() || normalizeKey(alias) != alias {
+			t.Errorf("priority alias %q -> %q is not canonical", alias, p)
+		}
+	}
+	for alias, s := range statusAliases {
+		if !s.IsValid() || normalizeKey(alias) != alias {
+			t.Errorf("status alias %q -> %q is not canonical", alias, s)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/alexbrand/backlog/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var pingCmd = &cobra.Command{
+	Use:   "ping",
+	Short: "Check that the backend is reachable",
+	Long: `Run the active backend's health check and report its latency.
+
+For GitHub and Linear this makes one API call, so it is a quick way to
+verify connectivity and credentials before a batch run. For GitHub it also
+reports the API rate limit left (rate_limit in JSON output). Exits with
+code 1 when the backend is not healthy.
+
+Examples:
+  backlog ping
+  backlog ping -w github-main
+  backlog ping -f json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPing()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pingCmd)
+}
+
+func runPing() error {
+	b, ws, cleanup, err := connectBackend()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	status, err := b.HealthCheck()
+	if err != nil {
+		return WrapError("health check failed", err)
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	if err := formatter.FormatHealthCheck(os.Stdout, b.Name(), ws, &status); err != nil {
+		return err
+	}
+
+	// The status was already printed; only the exit code is left to set
+	if !status.OK {
+		return SilentError(ExitError, status.Message)
+	}
+	return nil
+}
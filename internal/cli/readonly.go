@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/config"
+)
+
+// allowWriteEnv names the environment variable that lets automation write
+// to read-only workspaces without a prompt.
+const allowWriteEnv = "BACKLOG_ALLOW_WRITE"
+
+// connectWritableBackend is connectBackend for commands that change tasks.
+// It fails before connecting if the workspace is read-only.
+func connectWritableBackend() (backend.Backend, *config.Workspace, func(), error) {
+	if err := requireWritable(); err != nil {
+		return nil, nil, nil, err
+	}
+	return connectBackend()
+}
+
+// requireWritable returns a ReadOnlyError if the active workspace sets
+// read_only, unless BACKLOG_ALLOW_WRITE=1 is set or --override-read-only
+// is confirmed at a prompt. Dry runs change nothing, so they are allowed.
+// Config errors are left for connectBackend to report.
+func requireWritable() error {
+	if IsDryRun() {
+		return nil
+	}
+	ws, name, err := config.GetWorkspace(GetWorkspace())
+	if err != nil || !ws.ReadOnly {
+		return nil
+	}
+
+	if os.Getenv(allowWriteEnv) == "1" {
+		slog.Info("writing to read-only workspace", "workspace", name, "reason", allowWriteEnv)
+		return nil
+	}
+	if !overrideReadOnly {
+		return ReadOnlyError(name)
+	}
+	return confirmReadOnlyOverride(name)
+}
+
+// confirmReadOnlyOverride asks on the terminal before writing to a read-only
+// workspace. Without a terminal there is nobody to ask, so automation has
+// to set BACKLOG_ALLOW_WRITE=1 instead.
+func confirmReadOnlyOverride(workspace string) error {
+	if !isTerminal(os.Stdin) {
+		return NewExitCodeError(ExitReadOnly, fmt.Sprintf(
+			"workspace '%s' is read-only; --override-read-only needs confirmation on a terminal, set %s=1 for automation", workspace, allowWriteEnv))
+	}
+
+	ok, err := promptYesNo(fmt.Sprintf("Workspace '%s' is read-only. Write to it anyway?", workspace))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ReadOnlyError(workspace)
+	}
+	slog.Info("writing to read-only workspace", "workspace", workspace, "reason", "--override-read-only")
+	return nil
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var reindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Rebuild the local task index",
+	Long: `Rebuild the local task index.
+
+The local backend caches parsed task files in .backlog/.index.json so that
+listing a large backlog doesn't re-parse every file. A file is re-read
+whenever its size or modification time changes, so edits made by hand or
+pulled with git are picked up without reindexing, and a missing or corrupt
+index is rebuilt automatically.
+
+reindex discards the index and rebuilds it from every task file, including
+soft-deleted ones. Task files that cannot be parsed are reported.
+
+Set index: false on a workspace to disable the index.
+
+Examples:
+  backlog reindex
+  backlog reindex -f json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runReindex()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reindexCmd)
+}
+
+func runReindex() error {
+	// Get backend and connect
+	b, _, cleanup, err := connectBackend()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	// Check if backend keeps an index
+	reindexer, ok := b.(backend.Reindexer)
+	if !ok {
+		return UnsupportedError(b, "reindex")
+	}
+
+	result, err := reindexer.Reindex()
+	if err != nil {
+		return err
+	}
+
+	// Output the result
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatReindexed(statusOutput(), result)
+}
@@ -2,10 +2,11 @@ package cli
 
 import (
 	"fmt"
-	"os"
-	"strings"
+	"log/slog"
+	"time"
 
 	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/execbackend"
 	"github.com/alexbrand/backlog/internal/github"
 	"github.com/alexbrand/backlog/internal/linear"
 	"github.com/alexbrand/backlog/internal/local"
@@ -13,7 +14,10 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var releaseComment string
+var (
+	releaseComment string
+	releaseForce   bool
+)
 
 var releaseCmd = &cobra.Command{
 	Use:   "release <id>",
@@ -29,24 +33,41 @@ The release operation:
 Use this when an agent cannot complete work on a task and wants to make it
 available for other agents.
 
+Only the agent holding the claim can release a task. Use --force to reclaim
+a task left behind by an agent that died holding it: the release goes ahead
+whoever holds the claim, even an active lock, and a comment recording
+"force-released from <agent> by <you>" is added as an audit trail. A task
+whose lock has expired still needs --force while another agent's label is on
+it. On a terminal, --force asks for confirmation first; pass --yes to skip
+the prompt. Without a terminal, or with -f json, it fails unless --yes is
+given.
+
+With claim_comment: true on the workspace, a comment recording the release
+is posted as well; a comment that can't be posted only prints a warning.
+
 Examples:
   backlog release 001
   backlog release 001 --comment="Blocked on external API"
+  backlog release 001 --force
+  backlog release 001 --force --yes
   backlog release 001 -f json`,
-	Args: cobra.ExactArgs(1),
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTaskIDArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runRelease(args[0], releaseComment)
+		return runRelease(args[0], releaseComment, releaseForce)
 	},
 }
 
 func init() {
 	releaseCmd.Flags().StringVar(&releaseComment, "comment", "", "Add a comment when releasing the task")
+	releaseCmd.Flags().BoolVar(&releaseForce, "force", false, "Release the task even if another agent holds the claim")
+	addYesFlag(releaseCmd)
 	rootCmd.AddCommand(releaseCmd)
 }
 
-func runRelease(id, comment string) error {
+func runRelease(id, comment string, force bool) error {
 	// Get backend and connect
-	b, _, cleanup, err := connectBackend()
+	b, ws, cleanup, err := connectWritableBackend()
 	if err != nil {
 		return err
 	}
@@ -55,40 +76,74 @@ func runRelease(id, comment string) error {
 	// Check if backend supports releasing
 	claimer, ok := b.(backend.Claimer)
 	if !ok {
-		return fmt.Errorf("backend %q does not support task releasing", b.Name())
+		return UnsupportedError(b, "task releasing")
+	}
+
+	resolvedAgentID, err := requireAgentID(ws)
+	if err != nil {
+		return err
+	}
+
+	if IsDryRun() {
+		return previewRelease(b, ws, id, resolvedAgentID, force)
 	}
 
 	// Get the task first so we can display it in the output
 	task, err := b.Get(id)
 	if err != nil {
-		// Check if this is a "not found" error (case-insensitive check for 404/Not Found)
-		errLower := strings.ToLower(err.Error())
-		if strings.Contains(errLower, "not found") || strings.Contains(errLower, "404") {
-			return NotFoundError(err.Error())
+		if isNotFound(err) {
+			return NotFoundError(err.Error()).WithCause(err)
 		}
 		return err
 	}
 
 	// Release the task
-	if err := claimer.Release(id); err != nil {
-		// Check if this is a "not found" error (case-insensitive check for 404/Not Found)
-		errLower := strings.ToLower(err.Error())
-		if strings.Contains(errLower, "not found") || strings.Contains(errLower, "404") {
-			return NotFoundError(err.Error())
+	var claimedBy string
+	if force {
+		forceReleaser, ok := b.(backend.ForceReleaser)
+		if !ok {
+			return UnsupportedError(b, "forced releasing")
+		}
+		if err := confirm(fmt.Sprintf("Force-release task %s %q, whoever holds the claim?", task.ID, task.Title)); err != nil {
+			return err
+		}
+		claimedBy, err = forceReleaser.ForceRelease(id)
+	} else {
+		err = claimer.Release(id)
+	}
+	if err != nil {
+		if isNotFound(err) {
+			return NotFoundError(err.Error()).WithCause(err)
 		}
 		// Check for release conflict error (not claimed or claimed by different agent)
 		if _, isReleaseConflict := err.(*local.ReleaseConflictError); isReleaseConflict {
-			return ConflictError(err.Error())
+			return ConflictError(err.Error()).WithCause(err)
 		}
 		if _, isLinearReleaseConflict := err.(*linear.ReleaseConflictError); isLinearReleaseConflict {
-			return ConflictError(err.Error())
+			return ConflictError(err.Error()).WithCause(err)
 		}
 		if _, isGitHubReleaseConflict := err.(*github.ReleaseError); isGitHubReleaseConflict {
-			return ConflictError(err.Error())
+			return ConflictError(err.Error()).WithCause(err)
+		}
+		if _, isExecReleaseConflict := err.(*execbackend.ReleaseConflictError); isExecReleaseConflict {
+			return ConflictError(err.Error()).WithCause(err)
 		}
 		return err
 	}
 
+	// Record a forced release of another agent's claim
+	if claimedBy != "" && claimedBy != resolvedAgentID {
+		audit := fmt.Sprintf("force-released from %s by %s", claimedBy, resolvedAgentID)
+		if _, err := b.AddComment(id, audit); err != nil {
+			return fmt.Errorf("task released but failed to record the forced release: %w", err)
+		}
+	} else if ws != nil && ws.ClaimComment {
+		note := claimNote("released", resolvedAgentID, time.Now(), time.Time{}, "")
+		if _, err := b.AddComment(id, note); err != nil {
+			slog.Warn("task released but failed to post the release comment", "task", id, "error", err)
+		}
+	}
+
 	// Add comment if provided
 	if comment != "" {
 		if _, err := b.AddComment(id, comment); err != nil {
@@ -106,5 +161,5 @@ func runRelease(id, comment string) error {
 
 	// Output the result
 	formatter := output.New(output.Format(GetFormat()))
-	return formatter.FormatReleased(os.Stdout, updatedTask)
+	return formatter.FormatReleased(statusOutput(), updatedTask)
 }
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/local"
+	"github.com/alexbrand/backlog/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reopenReason string
+	reopenStatus string
+)
+
+var reopenCmd = &cobra.Command{
+	Use:   "reopen <id>",
+	Short: "Move a done task back with a reason",
+	Long: `Reopen a completed task.
+
+The reopen operation:
+1. Verifies the task is currently done
+2. Moves it back to todo (or the workspace's reopen_status, or --status)
+3. Adds a comment recording the reason and the agent that reopened it
+
+With git_sync enabled, the local backend records this as a single
+"reopen:" commit.
+
+Examples:
+  backlog reopen 001 --reason="Regression in login flow"
+  backlog reopen 001 --reason="Needs another review pass" --status=review
+  backlog reopen 001 --reason="Tests were skipped" -f json`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTaskIDArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runReopen(args[0], reopenReason, reopenStatus)
+	},
+}
+
+func init() {
+	reopenCmd.Flags().StringVar(&reopenReason, "reason", "", "Why the task is being reopened (required)")
+	reopenCmd.Flags().StringVarP(&reopenStatus, "status", "s", "", "Status to move the task to (default: todo)")
+
+	reopenCmd.RegisterFlagCompletionFunc("status", completeStatuses)
+	rootCmd.AddCommand(reopenCmd)
+}
+
+func runReopen(id, reason, statusStr string) error {
+	if strings.TrimSpace(reason) == "" {
+		return InvalidInputError("--reason is required to reopen a task")
+	}
+
+	// Get backend and connect
+	b, ws, cleanup, err := connectWritableBackend()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	// Resolve the target status: flag, then workspace config, then todo
+	if statusStr == "" && ws != nil {
+		statusStr = ws.ReopenStatus
+	}
+	status := backend.StatusTodo
+	if statusStr != "" {
+		status, err = parseStatus(statusStr)
+	}
+	if err != nil || status == backend.StatusDone {
+		return InvalidInputError(fmt.Sprintf("invalid reopen status %q (valid: backlog, todo, in-progress, review)", statusStr))
+	}
+
+	// Only done tasks can be reopened
+	task, err := b.Get(id)
+	if err != nil {
+		if isNotFound(err) {
+			return NotFoundError(err.Error()).WithCause(err)
+		}
+		return err
+	}
+	if task.Status != backend.StatusDone {
+		return ConflictError(fmt.Sprintf("cannot reopen task %s: status is %s, not done", id, task.Status))
+	}
+
+	comment := fmt.Sprintf("Reopened by %s: %s", ResolveAgentID(ws), reason)
+	before := task
+
+	if reopener, ok := b.(backend.Reopener); ok {
+		task, err = reopener.Reopen(id, status, comment)
+	} else {
+		task, err = b.Move(id, status)
+		if err == nil {
+			if _, commentErr := b.AddComment(id, comment); commentErr != nil {
+				return fmt.Errorf("task reopened but failed to add comment: %w", commentErr)
+			}
+		}
+	}
+	if err != nil {
+		// Check for uncommitted changes error (exit code 1)
+		if _, ok := err.(*local.UncommittedChangesError); ok {
+			return GeneralError(err.Error()).WithCause(err)
+		}
+		// Check for sync conflict error (exit code 2)
+		if _, ok := err.(*local.SyncConflictError); ok {
+			return ConflictError(err.Error()).WithCause(err)
+		}
+		if isNotFound(err) {
+			return NotFoundError(err.Error()).WithCause(err)
+		}
+		return err
+	}
+
+	if err := runHooks(ws, hookEvent{Event: hookEventMove, Task: task, PreviousStatus: backend.StatusDone, Agent: ResolveAgentID(ws)}); err != nil {
+		return err
+	}
+
+	// Output the result
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatMoved(statusOutput(), task, backend.StatusDone, status, backend.DiffTasks(before, task))
+}
@@ -2,8 +2,6 @@ package cli
 
 import (
 	"fmt"
-	"os"
-	"strings"
 
 	"github.com/alexbrand/backlog/internal/backend"
 	"github.com/alexbrand/backlog/internal/output"
@@ -15,6 +13,7 @@ var (
 	reorderAfter  string
 	reorderFirst  bool
 	reorderLast   bool
+	reorderTo     int
 )
 
 var reorderCmd = &cobra.Command{
@@ -22,16 +21,23 @@ var reorderCmd = &cobra.Command{
 	Short: "Change the position of a task in the list",
 	Long: `Reorder a task within its status and priority group.
 
-Specify where to place the task using one of: --before, --after, --first, --last.
-The reference task (for --before/--after) must have the same status as the target task.
+Specify where to place the task using one of: --before, --after, --first,
+--last, --to. The reference task (for --before/--after) must have the same
+status as the target task.
+
+--to N moves the task to the Nth position (1-based) within its status and
+priority group, between the tasks now at N-1 and N. N must be between 1 and
+the number of tasks in the group.
 
 Examples:
   backlog reorder 001 --before 003
   backlog reorder 001 --after 002
   backlog reorder 001 --first
   backlog reorder 001 --last
+  backlog reorder 001 --to 3
   backlog reorder 001 --first -f json`,
-	Args: cobra.ExactArgs(1),
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTaskIDArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runReorder(args[0])
 	},
@@ -44,6 +50,10 @@ func init() {
 	reorderCmd.Flags().StringVar(&reorderAfter, "after", "", "Place task after this task ID")
 	reorderCmd.Flags().BoolVar(&reorderFirst, "first", false, "Move task to the top of its group")
 	reorderCmd.Flags().BoolVar(&reorderLast, "last", false, "Move task to the bottom of its group")
+	reorderCmd.Flags().IntVar(&reorderTo, "to", 0, "Move task to this 1-based position within its group")
+
+	reorderCmd.RegisterFlagCompletionFunc("before", completeTaskIDs)
+	reorderCmd.RegisterFlagCompletionFunc("after", completeTaskIDs)
 }
 
 func runReorder(id string) error {
@@ -54,7 +64,7 @@ func runReorder(id string) error {
 	}
 
 	// Get backend and connect
-	b, _, cleanup, err := connectBackend()
+	b, _, cleanup, err := connectWritableBackend()
 	if err != nil {
 		return err
 	}
@@ -63,22 +73,25 @@ func runReorder(id string) error {
 	// Check if backend supports reordering
 	reorderer, ok := b.(backend.Reorderer)
 	if !ok {
-		return fmt.Errorf("backend %q does not support task reordering", b.Name())
+		return UnsupportedError(b, "task reordering")
+	}
+
+	if IsDryRun() {
+		return previewReorder(b, id, position)
 	}
 
 	// Perform the reorder
 	task, err := reorderer.Reorder(id, position)
 	if err != nil {
-		errLower := strings.ToLower(err.Error())
-		if strings.Contains(errLower, "not found") || strings.Contains(errLower, "404") {
-			return NotFoundError(err.Error())
+		if isNotFound(err) {
+			return NotFoundError(err.Error()).WithCause(err)
 		}
 		return err
 	}
 
 	// Output the result
 	formatter := output.New(output.Format(GetFormat()))
-	return formatter.FormatReordered(os.Stdout, task)
+	return formatter.FormatReordered(statusOutput(), task)
 }
 
 func parseReorderPosition() (backend.ReorderPosition, error) {
@@ -101,12 +114,19 @@ func parseReorderPosition() (backend.ReorderPosition, error) {
 		count++
 		pos.Last = true
 	}
+	if reorderTo != 0 {
+		if reorderTo < 1 {
+			return pos, fmt.Errorf("--to must be a position of 1 or more, got %d", reorderTo)
+		}
+		count++
+		pos.Position = reorderTo
+	}
 
 	if count == 0 {
-		return pos, fmt.Errorf("one of --before, --after, --first, or --last is required")
+		return pos, fmt.Errorf("one of --before, --after, --first, --last, or --to is required")
 	}
 	if count > 1 {
-		return pos, fmt.Errorf("only one of --before, --after, --first, or --last may be specified")
+		return pos, fmt.Errorf("only one of --before, --after, --first, --last, or --to may be specified")
 	}
 
 	return pos, nil
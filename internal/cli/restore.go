@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <id>",
+	Short: "Bring back a soft-deleted task",
+	Long: `Restore a task removed with "backlog delete --soft".
+
+The local backend moves the task out of .backlog/.trash/ and back into the
+status it was deleted from. The Linear backend unarchives the issue.
+
+Examples:
+  backlog restore 001
+  backlog restore 001 -f json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRestore(args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+}
+
+func runRestore(id string) error {
+	// Get backend and connect
+	b, _, cleanup, err := connectWritableBackend()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	softDeleter, ok := b.(backend.SoftDeleter)
+	if !ok {
+		return UnsupportedError(b, "restoring tasks")
+	}
+
+	task, err := softDeleter.Restore(id)
+	if err != nil {
+		if isNotFound(err) {
+			return NotFoundError(err.Error()).WithCause(err)
+		}
+		if backend.IsAlreadyExists(err) {
+			return ConflictError(err.Error()).WithCause(err)
+		}
+		return err
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatRestored(statusOutput(), task)
+}
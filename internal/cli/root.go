@@ -1,24 +1,43 @@
 package cli
 
 import (
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"strings"
 
+	"github.com/alexbrand/backlog/internal/backend"
 	"github.com/alexbrand/backlog/internal/config"
 	"github.com/alexbrand/backlog/internal/credentials"
+	"github.com/alexbrand/backlog/internal/logging"
+	"github.com/alexbrand/backlog/internal/output"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var (
 	// Global flags
-	cfgFile   string
-	workspace string
-	format    string
-	quiet     bool
-	verbose   bool
-	agentID   string
+	cfgFile       string
+	workspace     string
+	format        string
+	quiet         bool
+	verbose       int
+	logFormat     string
+	agentID       string
+	color         string
+	noColor       bool
+	dryRun        bool
+	noWait        bool
+	commitMessage string
+
+	overrideReadOnly bool
 )
 
+// annotationConfigOptional marks commands that don't need the config file,
+// so that they still run when it can't be loaded.
+const annotationConfigOptional = "backlog/config-optional"
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "backlog",
@@ -28,7 +47,24 @@ tracking backends. It provides a unified, agent-friendly interface that
 abstracts away provider-specific APIs, enabling both humans and AI agents
 to manage backlogs through simple, composable commands.`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		return initConfig()
+		if err := initLogging(); err != nil {
+			return err
+		}
+		if err := checkDryRun(cmd); err != nil {
+			return err
+		}
+		if err := checkCommitMessage(cmd); err != nil {
+			return err
+		}
+		if err := initConfig(); err != nil {
+			// Commands that don't read the config still work when it is broken
+			if cmd.Annotations[annotationConfigOptional] != "" {
+				slog.Warn("ignoring config file", "error", err)
+				return nil
+			}
+			return err
+		}
+		return nil
 	},
 	// Silence Cobra's default error/usage printing - we handle it ourselves
 	SilenceErrors: true,
@@ -37,6 +73,7 @@ to manage backlogs through simple, composable commands.`,
 
 // Execute runs the CLI application.
 func Execute() error {
+	disableFileCompletion(rootCmd)
 	return rootCmd.Execute()
 }
 
@@ -46,10 +83,17 @@ func init() {
 
 	// Global flags available to all commands
 	rootCmd.PersistentFlags().StringVarP(&workspace, "workspace", "w", "", "Target workspace (default: workspace with default: true)")
-	rootCmd.PersistentFlags().StringVarP(&format, "format", "f", "", "Output format: table, json, plain, id-only")
-	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Suppress non-essential output")
-	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Show debug information")
+	rootCmd.PersistentFlags().StringVarP(&format, "format", "f", "", "Output format: table, json, plain, id-only, markdown")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Print only requested data and errors, no success messages")
+	rootCmd.PersistentFlags().CountVarP(&verbose, "verbose", "v", "Log progress to stderr (-vv for debug logs)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", logging.FormatText, "Log record format: text, json")
 	rootCmd.PersistentFlags().StringVar(&agentID, "agent-id", "", "Agent identifier for task claiming and coordination")
+	rootCmd.PersistentFlags().StringVar(&color, "color", string(output.ColorAuto), "Colorize table output: auto, always, never")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colors (same as --color=never)")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Show what a command would change without changing anything")
+	rootCmd.PersistentFlags().StringVar(&commitMessage, "message", "", "Commit message for the change when git_sync is on, prefixed with the action")
+	rootCmd.PersistentFlags().BoolVar(&noWait, "no-wait", false, "Fail instead of waiting when the GitHub API rate limit is exhausted")
+	rootCmd.PersistentFlags().BoolVar(&overrideReadOnly, "override-read-only", false, "Write to a read-only workspace after confirming at a prompt")
 
 	// Bind flags to viper
 	viper.BindPFlag("workspace", rootCmd.PersistentFlags().Lookup("workspace"))
@@ -57,6 +101,31 @@ func init() {
 	viper.BindPFlag("quiet", rootCmd.PersistentFlags().Lookup("quiet"))
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 	viper.BindPFlag("agent_id", rootCmd.PersistentFlags().Lookup("agent-id"))
+
+	rootCmd.RegisterFlagCompletionFunc("workspace", completeWorkspaces)
+	rootCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(
+		[]string{"table", "json", "plain", "id-only", "markdown"}, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.RegisterFlagCompletionFunc("color", cobra.FixedCompletions(
+		[]string{"auto", "always", "never"}, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.RegisterFlagCompletionFunc("log-format", cobra.FixedCompletions(
+		[]string{logging.FormatText, logging.FormatJSON}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// initLogging sets the log level from --verbose and BACKLOG_LOG, whichever
+// is more detailed, and sends log records to stderr.
+func initLogging() error {
+	level := logging.VerbosityLevel(verbose)
+	if name := os.Getenv(logging.EnvVar); name != "" {
+		envLevel, err := logging.ParseLevel(name)
+		if err != nil {
+			return InvalidInputError(fmt.Sprintf("%s: %v", logging.EnvVar, err))
+		}
+		level = min(level, envLevel)
+	}
+	if err := logging.Setup(os.Stderr, level, logFormat); err != nil {
+		return InvalidInputError(err.Error())
+	}
+	return nil
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -71,10 +140,7 @@ func initConfig() error {
 	if err := credentials.Init(); err != nil {
 		// Credentials file errors should be reported but not fatal
 		// (credentials can come from environment variables)
-		if verbose {
-			// Only show warning in verbose mode
-			_ = err // Warning suppressed in non-verbose mode
-		}
+		slog.Info("failed to load credentials file", "error", err)
 	}
 
 	// Apply config defaults to flags if not set via CLI
@@ -90,18 +156,15 @@ func initConfig() error {
 		format = "table"
 	}
 
-	// Resolve agent ID with priority chain:
-	// 1. CLI flag (--agent-id) - already set in agentID if provided
-	// 2. Environment variable (BACKLOG_AGENT_ID)
-	// 3. Workspace config (resolved later when workspace is known)
-	// 4. Global default (defaults.agent_id)
-	// 5. Hostname fallback (resolved later if still empty)
-	if agentID == "" {
-		agentID = os.Getenv("BACKLOG_AGENT_ID")
+	// Resolve colors; only the table format uses them
+	colorMode := output.ColorMode(color)
+	if noColor {
+		colorMode = output.ColorNever
 	}
-	if agentID == "" && cfg != nil {
-		agentID = cfg.Defaults.AgentID
+	if !colorMode.IsValid() {
+		return InvalidInputError(fmt.Sprintf("invalid --color value %q (valid: auto, always, never)", color))
 	}
+	output.SetColor(output.ResolveColor(colorMode, os.Stdout))
 
 	return nil
 }
@@ -121,40 +184,83 @@ func IsQuiet() bool {
 	return quiet
 }
 
+// statusOutput returns where a command writes the message confirming a
+// change it made: stdout, or nowhere with --quiet. JSON output is data the
+// caller asked for, so it is written either way.
+func statusOutput() io.Writer {
+	if IsQuiet() && GetFormat() != "json" {
+		return io.Discard
+	}
+	return os.Stdout
+}
+
+// IsDryRun returns true if mutating commands should only preview their changes.
+func IsDryRun() bool {
+	return dryRun
+}
+
+// GetCommitMessage returns the --message given for git_sync commits, or ""
+// for the default message.
+func GetCommitMessage() string {
+	return commitMessage
+}
+
+// checkCommitMessage rejects a --message that can't be a one-line commit
+// subject.
+func checkCommitMessage(cmd *cobra.Command) error {
+	if !cmd.Flags().Changed("message") {
+		return nil
+	}
+	commitMessage = strings.TrimSpace(commitMessage)
+	if commitMessage == "" {
+		return InvalidInputError("--message cannot be empty")
+	}
+	if strings.ContainsAny(commitMessage, "\r\n") {
+		return InvalidInputError("--message must be a single line")
+	}
+	return nil
+}
+
 // IsVerbose returns true if verbose mode is enabled.
 func IsVerbose() bool {
-	return verbose
+	return verbose > 0
 }
 
-// GetAgentID returns the resolved agent ID.
-// Note: This returns the partially resolved agent ID (flag/env/global default).
-// For full resolution including workspace config and hostname fallback,
-// use ResolveAgentID with the workspace.
-func GetAgentID() string {
-	return agentID
-}
+// ResolveAgent resolves the agent identity for a workspace. See
+// config.ResolveAgentID for the order in which sources are consulted.
+// An error means no source yielded an ID.
+func ResolveAgent(ws *config.Workspace) (backend.AgentIdentity, error) {
+	identity := backend.AgentIdentity{LabelPrefix: agentLabelPrefix(ws)}
 
-// ResolveAgentID returns the fully resolved agent ID following the priority chain:
-// 1. CLI flag (--agent-id)
-// 2. Environment variable (BACKLOG_AGENT_ID)
-// 3. Workspace config (workspaces.<name>.agent_id)
-// 4. Global default (defaults.agent_id)
-// 5. Hostname fallback
-func ResolveAgentID(ws *config.Workspace) string {
-	// agentID already contains resolution from flag → env → global default
-	if agentID != "" {
-		return agentID
+	var wsName string
+	if ws != nil {
+		_, wsName, _ = config.GetWorkspace(GetWorkspace())
 	}
 
-	// Try workspace-specific agent ID
-	if ws != nil && ws.AgentID != "" {
-		return ws.AgentID
+	resolved, err := config.ResolveAgentID(agentID, ws, wsName)
+	if err != nil {
+		return identity, fmt.Errorf("no agent ID: %w", err)
 	}
+	identity.ID, identity.Source, identity.Origin = resolved.ID, resolved.Source, resolved.Origin
+	identity.Workspace = wsName
+	identity.Label = identity.LabelPrefix + ":" + identity.ID
+	return identity, nil
+}
+
+// ResolveAgentID returns the resolved agent ID, or an empty string if none
+// could be resolved. Commands that record the agent on a task should use
+// requireAgentID instead.
+func ResolveAgentID(ws *config.Workspace) string {
+	identity, _ := ResolveAgent(ws)
+	return identity.ID
+}
 
-	// Fallback to hostname
-	hostname, err := os.Hostname()
+// requireAgentID returns the resolved agent ID, refusing to continue without
+// one rather than writing an empty agent to labels, locks, or comments.
+func requireAgentID(ws *config.Workspace) (string, error) {
+	identity, err := ResolveAgent(ws)
 	if err != nil {
-		return "unknown"
+		return "", ConfigError(err.Error())
 	}
-	return hostname
+	return identity.ID, nil
 }
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema [task|task-input|task-list]",
+	Short: "Print the JSON Schema of the task objects",
+	Long: `Print a JSON Schema (draft 2020-12) describing the task objects backlog
+writes with --format json, so agent frameworks can validate its responses.
+
+The schema is generated from backlog's own types, so it always matches the
+version that prints it. It defines:
+
+  task        A task, as written by show and in list's "tasks"
+  task-input  The fields of a task to create
+  task-list   The output of list
+
+With no argument, the schema accepts any of them; with one, it validates
+that object. Status and priority are enumerations of their valid values.
+The schema doesn't depend on the workspace, so no config file is needed.
+
+list --fields drops the task keys that aren't selected, including required
+ones.
+
+Examples:
+  backlog schema
+  backlog schema task > task.schema.json`,
+	Args:        cobra.MaximumNArgs(1),
+	ValidArgs:   backend.SchemaNames(),
+	Annotations: map[string]string{annotationConfigOptional: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := ""
+		if len(args) > 0 {
+			name = args[0]
+		}
+		return runSchema(name)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
+
+func runSchema(name string) error {
+	schema, err := backend.Schema(name)
+	if err != nil {
+		return InvalidInputError(err.Error())
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(schema)
+}
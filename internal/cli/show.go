@@ -3,7 +3,7 @@ package cli
 import (
 	"fmt"
 	"os"
-	"strings"
+	"time"
 
 	"github.com/alexbrand/backlog/internal/backend"
 	"github.com/alexbrand/backlog/internal/output"
@@ -12,6 +12,10 @@ import (
 
 var (
 	showComments bool
+	showRaw      bool
+	showCached   bool
+	showSince    string
+	showLimit    int
 )
 
 var showCmd = &cobra.Command{
@@ -19,15 +23,51 @@ var showCmd = &cobra.Command{
 	Short: "Display full task details",
 	Long: `Display the full details of a task including its description.
 
-Use the --comments flag to include the comment thread.
+Use the --comments flag to include the comment thread. On long threads,
+--since limits it to comments created after an RFC3339 time or a duration
+ago (e.g. 2h, 3d, 1w), and --limit to the most recent N comments.
+
+Use -f markdown to render the task as a markdown document for pasting into
+pull requests: a heading with the title and ID, a table of its fields, the
+description, its relations, and with --comments the comment thread. Tasks
+render the same way whichever backend they come from.
+
+Use the --raw flag to print the task exactly as the backend stores it,
+bypassing normalization. For the local backend this is the task file;
+for Linear it is the issue JSON returned by the API.
+
+Use the --cached flag in a Linear workspace to read the task from the local
+cache written by 'backlog sync' instead of the API. It works offline, but
+shows the task as of the last sync.
 
 Examples:
   backlog show 001
   backlog show 001 -f json
-  backlog show 001 --comments`,
-	Args: cobra.ExactArgs(1),
+  backlog show 001 --comments
+  backlog show 001 --comments -f markdown
+  backlog show ENG-42 --comments --since 2d
+  backlog show ENG-42 --comments --limit 10
+  backlog show 001 --raw
+  backlog show ENG-42 --cached`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTaskIDArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runShow(args[0])
+		var filters backend.CommentFilters
+		if showSince != "" {
+			since, err := backend.ParseSince(showSince, time.Now())
+			if err != nil {
+				return InvalidInputError("--since: " + err.Error())
+			}
+			filters.Since = since
+		}
+		if showLimit < 0 {
+			return InvalidInputError(fmt.Sprintf("--limit must not be negative, got %d", showLimit))
+		}
+		filters.Limit = showLimit
+		if (showSince != "" || showLimit > 0) && !showComments {
+			return InvalidInputError("--since and --limit require --comments")
+		}
+		return runShow(args[0], filters)
 	},
 }
 
@@ -35,23 +75,36 @@ func init() {
 	rootCmd.AddCommand(showCmd)
 
 	showCmd.Flags().BoolVar(&showComments, "comments", false, "Include comment thread")
+	showCmd.Flags().BoolVar(&showRaw, "raw", false, "Print the backend's underlying representation of the task")
+	showCmd.Flags().BoolVar(&showCached, "cached", false, "Read the task from the cache kept by 'backlog sync' (Linear only)")
+	showCmd.Flags().StringVar(&showSince, "since", "", "Only comments created since an RFC3339 time or a duration ago (e.g. 2h, 3d, 1w)")
+	showCmd.Flags().IntVar(&showLimit, "limit", 0, "Only the most recent N comments (0 for all)")
 }
 
-func runShow(id string) error {
+func runShow(id string, commentFilters backend.CommentFilters) error {
 	// Get backend and connect
-	b, _, cleanup, err := connectBackend()
+	var b backend.Backend
+	var cleanup func()
+	var err error
+	if showCached {
+		b, cleanup, err = connectCachedBackend()
+	} else {
+		b, _, cleanup, err = connectBackend()
+	}
 	if err != nil {
 		return err
 	}
 	defer cleanup()
 
+	if showRaw {
+		return showRawTask(b, id)
+	}
+
 	// Get the task
 	task, err := b.Get(id)
 	if err != nil {
-		// Check if this is a "not found" error (case-insensitive check for 404/Not Found)
-		errLower := strings.ToLower(err.Error())
-		if strings.Contains(errLower, "not found") || strings.Contains(errLower, "404") {
-			return NotFoundError(err.Error())
+		if isNotFound(err) {
+			return NotFoundError(err.Error()).WithCause(err)
 		}
 		return err
 	}
@@ -71,7 +124,7 @@ func runShow(id string) error {
 	formatter := output.New(output.Format(GetFormat()))
 
 	if showComments {
-		comments, err := b.ListComments(id)
+		comments, err := listComments(b, id, commentFilters)
 		if err != nil {
 			return fmt.Errorf("failed to list comments: %w", err)
 		}
@@ -88,3 +141,43 @@ func runShow(id string) error {
 
 	return nil
 }
+
+// listComments returns the comments on a task that match filters. Backends
+// that can't filter comments when fetching them are filtered in memory.
+func listComments(b backend.Backend, id string, filters backend.CommentFilters) ([]backend.Comment, error) {
+	if filters.Since.IsZero() && filters.Limit == 0 {
+		return b.ListComments(id)
+	}
+	if filterer, ok := b.(backend.CommentFilterer); ok {
+		return filterer.ListCommentsFiltered(id, filters)
+	}
+	comments, err := b.ListComments(id)
+	if err != nil {
+		return nil, err
+	}
+	return backend.FilterComments(comments, filters), nil
+}
+
+// showRawTask prints the backend's native representation of a task.
+func showRawTask(b backend.Backend, id string) error {
+	rawGetter, ok := b.(backend.RawGetter)
+	if !ok {
+		return UnsupportedError(b, "raw output")
+	}
+
+	raw, err := rawGetter.GetRaw(id)
+	if err != nil {
+		if isNotFound(err) {
+			return NotFoundError(err.Error()).WithCause(err)
+		}
+		return err
+	}
+
+	if _, err := os.Stdout.Write(raw); err != nil {
+		return err
+	}
+	if len(raw) > 0 && raw[len(raw)-1] != '\n' {
+		fmt.Fprintln(os.Stdout)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// sortFields lists the fields accepted by list --sort.
+var sortFields = backend.SortFields
+
+// parseSort parses a --sort value: comma-separated fields, each optionally
+// prefixed with - to sort it in descending order.
+func parseSort(s string) ([]backend.SortKey, error) {
+	var keys []backend.SortKey
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		desc := strings.HasPrefix(part, "-")
+		field := normalizeKey(strings.TrimPrefix(part, "-"))
+		if !slices.Contains(sortFields, field) {
+			return nil, InvalidInputError(fmt.Sprintf("invalid sort field %q (valid: %s)", part, strings.Join(sortFields, ", ")))
+		}
+		keys = append(keys, backend.SortKey{Field: field, Desc: desc})
+	}
+	return keys, nil
+}
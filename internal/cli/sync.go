@@ -1,8 +1,11 @@
 package cli
 
 import (
+	"bufio"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/alexbrand/backlog/internal/backend"
 	"github.com/alexbrand/backlog/internal/local"
@@ -10,7 +13,11 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var syncForce bool
+var (
+	syncForce    bool
+	syncStatus   bool
+	syncStrategy string
+)
 
 var syncCmd = &cobra.Command{
 	Use:   "sync",
@@ -23,48 +30,205 @@ The sync operation:
 
 This command requires git_sync to be enabled in your workspace configuration.
 
+In a Linear workspace, sync instead mirrors the team's issues into a
+read-only cache under .backlog/.cache/linear, which 'backlog show --cached'
+reads without network access. After the first sync only issues updated
+since the previous one are fetched; --force fetches everything again and
+drops cached issues that no longer exist.
+
 Use --force to force push/pull even if there are conflicts.
 
+When a pull conflicts, the remote branch is merged instead and conflicting
+task files are merged field by field: labels and relations are combined,
+the later updated time is kept, both sides' comments are kept, and the
+status that moved further along (backlog, todo, in-progress, review, done)
+wins. Fields both sides changed to different values, such as the title or
+description, and conflicts in files that aren't tasks need --strategy:
+ours keeps the local value, theirs the remote one, and interactive asks
+for each field on the terminal. Without it the merge is aborted, nothing
+changes, and the error lists each task ID and field; with -f json they are
+in error.details.conflicts. A resolved merge is committed as "sync: resolve
+conflicts in <ids>".
+
+Use --status to check for divergence without changing anything. It fetches
+from the remote and reports the commits ahead and behind, whether the
+repository has uncommitted changes, the remote URL, and which tasks a pull
+would update, add, or remove. Nothing is merged, rebased, or pushed. With
+-f json the keys are ahead, behind, dirty, and remote. Exits with code 2 when
+local and remote have diverged, so scripts can decide whether to sync.
+
 Examples:
   backlog sync
   backlog sync --force
+  backlog sync --strategy theirs
+  backlog sync --status
   backlog sync -f json`,
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runSync(syncForce)
+		if syncStatus {
+			if syncForce {
+				return InvalidInputError("--status cannot be combined with --force")
+			}
+			if syncStrategy != "" {
+				return InvalidInputError("--status cannot be combined with --strategy")
+			}
+			return runSyncStatus()
+		}
+		return runSync(syncForce, syncStrategy)
 	},
 }
 
 func init() {
-	syncCmd.Flags().BoolVar(&syncForce, "force", false, "Force sync even if there are conflicts")
+	syncCmd.Flags().BoolVar(&syncForce, "force", false, "Force sync even if there are conflicts (Linear: refetch all issues)")
+	syncCmd.Flags().BoolVar(&syncStatus, "status", false, "Report divergence from the remote without syncing")
+	syncCmd.Flags().StringVar(&syncStrategy, "strategy", "", "Resolve conflicting task fields: ours, theirs, or interactive")
+	syncCmd.RegisterFlagCompletionFunc("strategy", cobra.FixedCompletions([]string{"ours", "theirs", "interactive"}, cobra.ShellCompDirectiveNoFileComp))
 	rootCmd.AddCommand(syncCmd)
 }
 
-func runSync(force bool) error {
-	// Get backend and connect
-	b, _, cleanup, err := connectBackend()
+func runSync(force bool, strategy string) error {
+	choose, err := conflictChooser(strategy)
+	if err != nil {
+		return err
+	}
+
+	// Get backend and connect. A git sync pushes, so it is a write; a Linear
+	// sync only mirrors issues into the local cache.
+	b, ws, cleanup, err := connectBackend()
 	if err != nil {
 		return err
 	}
 	defer cleanup()
+	if ws == nil || ws.Backend != "linear" {
+		if err := requireWritable(); err != nil {
+			return err
+		}
+	}
 
 	// Check if backend supports syncing
 	syncer, ok := b.(backend.Syncer)
 	if !ok {
-		return fmt.Errorf("backend %q does not support sync operations", b.Name())
+		return UnsupportedError(b, "sync operations")
 	}
 
-	// Perform the sync
-	result, err := syncer.Sync(force)
+	// Perform the sync, resolving conflicts if the backend can
+	var result *backend.SyncResult
+	if resolver, ok := b.(backend.ConflictResolver); ok {
+		result, err = resolver.SyncResolving(force, choose)
+	} else {
+		if strategy != "" {
+			return UnsupportedError(b, "sync conflict strategies")
+		}
+		result, err = syncer.Sync(force)
+	}
 	if err != nil {
 		// Check if it's a conflict error (exit code 2)
-		if _, ok := err.(*local.SyncConflictError); ok {
-			return ConflictError(err.Error())
+		if conflictErr, ok := err.(*local.SyncConflictError); ok {
+			e := ConflictError(err.Error()).WithCause(err)
+			if len(conflictErr.Conflicts) > 0 {
+				e.Details = map[string]any{
+					"tasks":     conflictTaskIDs(conflictErr.Conflicts),
+					"conflicts": conflictErr.Conflicts,
+				}
+			}
+			return e
 		}
 		return err
 	}
 
 	// Output the result
 	formatter := output.New(output.Format(GetFormat()))
-	return formatter.FormatSynced(os.Stdout, result)
+	return formatter.FormatSynced(statusOutput(), result)
+}
+
+// conflictChooser returns the chooser for a --strategy value, or nil
+// without one, leaving conflicting fields to abort the sync.
+func conflictChooser(strategy string) (backend.ConflictChooser, error) {
+	switch strategy {
+	case "":
+		return nil, nil
+	case string(backend.ConflictOurs), string(backend.ConflictTheirs):
+		side := backend.ConflictSide(strategy)
+		return func(backend.FieldConflict) (backend.ConflictSide, error) { return side, nil }, nil
+	case "interactive":
+		if !isTerminal(os.Stdin) {
+			return nil, InvalidInputError("--strategy interactive needs a terminal; use ours or theirs")
+		}
+		return promptConflictSide(bufio.NewReader(os.Stdin)), nil
+	}
+	return nil, InvalidInputError(fmt.Sprintf("invalid --strategy %q (valid: ours, theirs, interactive)", strategy))
+}
+
+// promptConflictSide asks on the terminal which side of each conflict to
+// keep. Anything other than an answer aborts the sync.
+func promptConflictSide(reader *bufio.Reader) backend.ConflictChooser {
+	return func(c backend.FieldConflict) (backend.ConflictSide, error) {
+		name := c.TaskID
+		if name == "" {
+			name = c.File
+		}
+		fmt.Fprintf(os.Stderr, "Conflict in %s %s:\n  ours:   %s\n  theirs: %s\n", name, c.Field, conflictValue(c.Ours), conflictValue(c.Theirs))
+		for {
+			fmt.Fprint(os.Stderr, "Keep [o]urs or [t]heirs? ")
+			answer, err := reader.ReadString('\n')
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "o", "ours":
+				return backend.ConflictOurs, nil
+			case "t", "theirs":
+				return backend.ConflictTheirs, nil
+			}
+			if err != nil {
+				return "", fmt.Errorf("sync aborted: no side chosen for %s %s", name, c.Field)
+			}
+		}
+	}
+}
+
+// conflictValue shows a conflicting value on one line, indenting the rest
+// of a multi-line value such as a description.
+func conflictValue(v string) string {
+	if v == "" {
+		return "(empty)"
+	}
+	return strings.ReplaceAll(v, "\n", "\n          ")
+}
+
+// conflictTaskIDs returns the distinct task IDs of unresolved conflicts.
+func conflictTaskIDs(conflicts []backend.FieldConflict) []string {
+	ids := []string{}
+	for _, c := range conflicts {
+		if c.TaskID != "" && !slices.Contains(ids, c.TaskID) {
+			ids = append(ids, c.TaskID)
+		}
+	}
+	return ids
+}
+
+func runSyncStatus() error {
+	// Get backend and connect
+	b, _, cleanup, err := connectBackend()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	inspector, ok := b.(backend.SyncInspector)
+	if !ok {
+		return UnsupportedError(b, "sync status")
+	}
+
+	status, err := inspector.SyncStatus()
+	if err != nil {
+		return err
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	if err := formatter.FormatSyncStatus(os.Stdout, status); err != nil {
+		return err
+	}
+
+	if !status.InSync {
+		return SilentError(ExitConflict, fmt.Sprintf("diverged from %s: %d ahead, %d behind", status.Upstream, status.Ahead, status.Behind))
+	}
+	return nil
 }
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alexbrand/backlog/internal/config"
+	"github.com/alexbrand/backlog/internal/output"
+	"github.com/alexbrand/backlog/internal/template"
+	"github.com/spf13/cobra"
+)
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Manage task templates",
+	Long: `Manage task templates stored in .backlog/templates.
+
+A template is a markdown file in the same format as a task file. Its
+frontmatter holds default fields (title, status, priority, labels, assignee)
+and its body is the default description. The title, description, and labels
+may contain {{placeholders}} that are filled in with --set when adding a task.
+
+Example .backlog/templates/bug.md:
+
+  ---
+  title: "Bug: {{summary}}"
+  priority: high
+  labels: [bug]
+  ---
+
+  ## Steps to reproduce
+
+  {{steps}}
+
+Templates work with every backend.`,
+}
+
+var templateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available task templates",
+	Long: `List the task templates available in .backlog/templates.
+
+Examples:
+  backlog template list
+  backlog template list -f json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTemplateList()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(templateCmd)
+	templateCmd.AddCommand(templateListCmd)
+}
+
+func runTemplateList() error {
+	templates, err := template.List(templatesDir())
+	if err != nil {
+		return err
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatTemplates(os.Stdout, templates)
+}
+
+// templatesDir returns the directory holding task templates. Local workspaces
+// keep them inside their backlog directory; other backends use .backlog,
+// alongside the project config.
+func templatesDir() string {
+	backlogDir := ".backlog"
+	if ws, _, err := config.GetWorkspace(GetWorkspace()); err == nil && ws.Backend == "local" && ws.Path != "" {
+		backlogDir = ws.Path
+	}
+	return filepath.Join(backlogDir, template.DirName)
+}
+
+// loadTemplate loads the named template and fills in its placeholders from
+// key=value pairs.
+func loadTemplate(name string, pairs []string) (*template.Template, error) {
+	values := make(map[string]string)
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, InvalidInputError(fmt.Sprintf("invalid --set value %q (expected key=value)", pair))
+		}
+		values[key] = value
+	}
+
+	tmpl, err := template.Load(templatesDir(), name)
+	if err != nil {
+		if _, ok := err.(*template.NotFoundError); ok {
+			return nil, NotFoundError(err.Error())
+		}
+		return nil, err
+	}
+
+	rendered, err := tmpl.Render(values)
+	if err != nil {
+		if _, ok := err.(*template.MissingValuesError); ok {
+			return nil, InvalidInputError(err.Error())
+		}
+		return nil, err
+	}
+
+	return rendered, nil
+}
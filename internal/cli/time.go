@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"os"
+	"sort"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var timeLabels []string
+
+var timeCmd = &cobra.Command{
+	Use:   "time",
+	Short: "Report how long completed tasks took",
+	Long: `Report how long completed tasks took, from their first claim to done,
+in total and per label.
+
+Only done tasks with both a start and a completion time are counted. The
+local backend records these when a task is claimed and when it moves to
+done. For GitHub and Linear they are derived from the issue's own
+timestamps and reported as approximate.
+
+Examples:
+  backlog time
+  backlog time --label=bug
+  backlog time -f json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTime()
+	},
+}
+
+func init() {
+	timeCmd.Flags().StringSliceVarP(&timeLabels, "label", "l", nil, "Only count tasks with all of these labels")
+	timeCmd.RegisterFlagCompletionFunc("label", completeLabels)
+	rootCmd.AddCommand(timeCmd)
+}
+
+func runTime() error {
+	b, _, cleanup, err := connectBackend()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	taskList, err := b.List(backend.TaskFilters{
+		Status:      []backend.Status{backend.StatusDone},
+		Labels:      timeLabels,
+		IncludeDone: true,
+	})
+	if err != nil {
+		return WrapError("failed to list tasks", err)
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatTimeReport(os.Stdout, buildTimeReport(taskList.Tasks))
+}
+
+// buildTimeReport totals the durations of completed tasks, overall and per
+// label. Tasks that are not done or were never started are left out.
+func buildTimeReport(tasks []backend.Task) *backend.TimeReport {
+	report := &backend.TimeReport{Labels: []backend.LabelTime{}}
+	byLabel := make(map[string]*backend.LabelTime)
+
+	for _, task := range tasks {
+		if task.Status != backend.StatusDone || task.CompletedAt == nil || task.Duration <= 0 {
+			continue
+		}
+		report.Tasks++
+		report.Total += task.Duration
+		if task.TimeApproximate {
+			report.Approximate = true
+		}
+		for _, label := range task.Labels {
+			lt, ok := byLabel[label]
+			if !ok {
+				lt = &backend.LabelTime{Label: label}
+				byLabel[label] = lt
+			}
+			lt.Tasks++
+			lt.Total += task.Duration
+		}
+	}
+
+	if report.Tasks > 0 {
+		report.Average = report.Total / backend.Duration(report.Tasks)
+	}
+	for _, lt := range byLabel {
+		lt.Average = lt.Total / backend.Duration(lt.Tasks)
+		report.Labels = append(report.Labels, *lt)
+	}
+	sort.Slice(report.Labels, func(i, j int) bool {
+		return report.Labels[i].Label < report.Labels[j].Label
+	})
+
+	return report
+}
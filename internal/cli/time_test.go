@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+func TestBuildTimeReport(t *testing.T) {
+	start := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+	done := start.Add(8 * time.Hour)
+	tasks := []backend.Task{
+		{ID: "001", Status: backend.StatusDone, Labels: []string{"bug", "ui"}, StartedAt: &start, CompletedAt: &done, Duration: backend.Duration(2 * time.Hour)},
+		{ID: "002", Status: backend.StatusDone, Labels: []string{"bug"}, StartedAt: &start, CompletedAt: &done, Duration: backend.Duration(4 * time.Hour), TimeApproximate: true},
+		{ID: "003", Status: backend.StatusDone},
+		{ID: "004", Status: backend.StatusInProgress, StartedAt: &start, Duration: backend.Duration(time.Hour)},
+	}
+
+	report := buildTimeReport(tasks)
+	if report.Tasks != 2 || report.Total != backend.Duration(6*time.Hour) || report.Average != backend.Duration(3*time.Hour) {
+		t.Errorf("expected 2 tasks, 6h total, 3h average; got %d, %v, %v", report.Tasks, report.Total, report.Average)
+	}
+	if !report.Approximate {
+		t.Error("expected report to be marked approximate")
+	}
+	want := []backend.LabelTime{
+		{Label: "bug", Tasks: 2, Total: backend.Duration(6 * time.Hour), Average: backend.Duration(3 * time.Hour)},
+		{Label: "ui", Tasks: 1, Total: backend.Duration(2 * time.Hour), Average: backend.Duration(2 * time.Hour)},
+	}
+	if !reflect.DeepEqual(report.Labels, want) {
+		t.Errorf("expected labels %+v, got %+v", want, report.Labels)
+	}
+}
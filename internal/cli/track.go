@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var trackSpent string
+
+var trackCmd = &cobra.Command{
+	Use:   "track <id>",
+	Short: "Record time spent on a task",
+	Long: `Record time spent on a task. The duration is added to the task's
+existing spent time.
+
+Durations use Go syntax (30m, 1h30m) and also accept d (day) and
+w (week) units (1d, 1w2d).
+
+Examples:
+  backlog track 001 --spent 2h
+  backlog track 001 --spent 1d4h
+  backlog track 001 --spent 45m -f json`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTaskIDArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTrack(args[0], trackSpent)
+	},
+}
+
+func init() {
+	trackCmd.Flags().StringVar(&trackSpent, "spent", "", "Time spent to add to the task (e.g., 30m, 2h, 1d)")
+	trackCmd.MarkFlagRequired("spent")
+	rootCmd.AddCommand(trackCmd)
+}
+
+func runTrack(id, spentStr string) error {
+	spent, err := backend.ParseDuration(spentStr)
+	if err != nil {
+		return InvalidInputError(err.Error())
+	}
+	if spent <= 0 {
+		return InvalidInputError(fmt.Sprintf("spent time must be positive, got %q", spentStr))
+	}
+
+	// Get backend and connect
+	b, _, cleanup, err := connectWritableBackend()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	// Get the current task to accumulate onto its existing spent time
+	task, err := b.Get(id)
+	if err != nil {
+		if isNotFound(err) {
+			return NotFoundError(err.Error()).WithCause(err)
+		}
+		return err
+	}
+
+	total := task.Spent + spent
+	updated, err := b.Update(id, backend.TaskChanges{Spent: &total})
+	if err != nil {
+		return err
+	}
+
+	// Output the result
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatUpdated(statusOutput(), updated, backend.DiffTasks(task, updated))
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"errors"
+	"os"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/local"
+	"github.com/alexbrand/backlog/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var undoList bool
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Revert the last change to the backlog",
+	Long: `Revert the most recent change made with backlog, such as an edit, a move,
+a claim or a delete.
+
+The local backend records the task files each change writes or removes in
+.backlog/.undo/, keeping the last 20 changes (set undo_limit on the
+workspace to keep more, or 0 to turn recording off). undo restores those
+files as they were: a deleted task is recreated, an edit's frontmatter and
+description are put back, and a moved task returns to its old status
+directory. Running undo again reverts the change before that. The undo
+log belongs to the checkout and is never committed.
+
+With git_sync enabled, the revert is committed as "undo: <action> <id>"
+and pushed. If a file the change touched has changed since, for example
+because a sync pulled someone else's edit to the task, undo refuses with
+exit code 2 rather than overwrite it.
+
+Use --list to show the changes that can be undone, most recent first.
+
+Examples:
+  backlog undo
+  backlog undo --list
+  backlog undo -f json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if undoList {
+			return runUndoList()
+		}
+		return runUndo()
+	},
+}
+
+func init() {
+	undoCmd.Flags().BoolVar(&undoList, "list", false, "List the changes that can be undone")
+	rootCmd.AddCommand(undoCmd)
+}
+
+func runUndo() error {
+	// Get backend and connect
+	b, _, cleanup, err := connectWritableBackend()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	undoer, ok := b.(backend.Undoer)
+	if !ok {
+		return UnsupportedError(b, "undo")
+	}
+
+	entry, err := undoer.Undo()
+	if err != nil {
+		if errors.Is(err, backend.ErrNothingToUndo) {
+			return NotFoundError(err.Error()).WithCause(err)
+		}
+		var conflict *local.UndoConflictError
+		if errors.As(err, &conflict) {
+			return ConflictError(err.Error()).WithCause(err)
+		}
+		if _, ok := err.(*local.UncommittedChangesError); ok {
+			return GeneralError(err.Error()).WithCause(err)
+		}
+		if _, ok := err.(*local.SyncConflictError); ok {
+			return ConflictError(err.Error()).WithCause(err)
+		}
+		return err
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatUndone(statusOutput(), entry)
+}
+
+func runUndoList() error {
+	// Get backend and connect
+	b, _, cleanup, err := connectBackend()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	undoer, ok := b.(backend.Undoer)
+	if !ok {
+		return UnsupportedError(b, "undo")
+	}
+
+	entries, err := undoer.UndoEntries()
+	if err != nil {
+		return err
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatUndoEntries(os.Stdout, entries)
+}
@@ -1,9 +1,6 @@
 package cli
 
 import (
-	"fmt"
-	"os"
-
 	"github.com/alexbrand/backlog/internal/backend"
 	"github.com/alexbrand/backlog/internal/output"
 	"github.com/spf13/cobra"
@@ -24,7 +21,8 @@ Exactly one of --blocks or --blocked-by must be specified.
 Examples:
   backlog unlink 001 --blocks 002       # remove 001 blocks 002
   backlog unlink 001 --blocked-by 002   # remove 001 blocked by 002`,
-	Args: cobra.ExactArgs(1),
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTaskIDArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runUnlink(args[0])
 	},
@@ -35,6 +33,9 @@ func init() {
 
 	unlinkCmd.Flags().StringVar(&unlinkBlocks, "blocks", "", "Target task ID that source blocks")
 	unlinkCmd.Flags().StringVar(&unlinkBlockedBy, "blocked-by", "", "Target task ID that blocks source")
+
+	unlinkCmd.RegisterFlagCompletionFunc("blocks", completeTaskIDs)
+	unlinkCmd.RegisterFlagCompletionFunc("blocked-by", completeTaskIDs)
 }
 
 func runUnlink(sourceID string) error {
@@ -47,7 +48,7 @@ func runUnlink(sourceID string) error {
 	}
 
 	// Get backend and connect
-	b, _, cleanup, err := connectBackend()
+	b, _, cleanup, err := connectWritableBackend()
 	if err != nil {
 		return err
 	}
@@ -56,7 +57,7 @@ func runUnlink(sourceID string) error {
 	// Check if backend supports relations
 	relater, ok := b.(backend.Relater)
 	if !ok {
-		return fmt.Errorf("backend %q does not support task dependencies", b.Name())
+		return UnsupportedError(b, "task dependencies")
 	}
 
 	var relationType backend.RelationType
@@ -74,5 +75,5 @@ func runUnlink(sourceID string) error {
 	}
 
 	formatter := output.New(output.Format(GetFormat()))
-	return formatter.FormatUnlinked(os.Stdout, sourceID, targetID)
+	return formatter.FormatUnlinked(statusOutput(), sourceID, targetID)
 }
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var verifyCleanCmd = &cobra.Command{
+	Use:   "verify-clean",
+	Short: "Check that no backlog changes are left uncommitted",
+	Long: `Check that the backlog directory has no changes git hasn't committed.
+
+Lists the backlog files with staged, unstaged, or untracked changes, with
+paths relative to the repository root, and exits with code 1 if there are
+any. Only the backlog directory is checked, so changes to source files
+don't count. The task index, the undo log, and credentials files are never
+committed and are ignored.
+
+This is meant for a git pre-commit hook, so that committing source changes
+doesn't leave task state behind uncommitted:
+
+  #!/bin/sh
+  exec backlog verify-clean
+
+Examples:
+  backlog verify-clean
+  backlog verify-clean -f json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runVerifyClean()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCleanCmd)
+}
+
+func runVerifyClean() error {
+	// Get backend and connect
+	b, _, cleanup, err := connectBackend()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	checker, ok := b.(backend.CleanChecker)
+	if !ok {
+		return UnsupportedError(b, "verify-clean")
+	}
+
+	files, err := checker.UncommittedFiles()
+	if err != nil {
+		return err
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	if err := formatter.FormatUncommitted(os.Stdout, files); err != nil {
+		return err
+	}
+
+	// The files were already printed; only the exit code is left to set
+	if len(files) > 0 {
+		return SilentError(ExitError, fmt.Sprintf("%d uncommitted backlog file(s)", len(files)))
+	}
+	return nil
+}
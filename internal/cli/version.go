@@ -14,11 +14,12 @@ var (
 )
 
 var versionCmd = &cobra.Command{
-	Use:   "version",
-	Short: "Print version information",
-	Long:  `Print the version, git commit, and build date of the backlog CLI.`,
+	Use:         "version",
+	Short:       "Print version information",
+	Long:        `Print the version, git commit, and build date of the backlog CLI.`,
+	Annotations: map[string]string{annotationConfigOptional: "true"},
 	Run: func(cmd *cobra.Command, args []string) {
-		if verbose {
+		if IsVerbose() {
 			fmt.Printf("backlog version %s\n", Version)
 			fmt.Printf("  git commit: %s\n", GitCommit)
 			fmt.Printf("  build date: %s\n", BuildDate)
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// waiter paces the checks of a command that waits for work to become
+// available, such as next --wait. Between checks it sleeps for the poll
+// interval, waking early when a backend that can watch for changes reports
+// one, and it turns the timeout and SIGINT or SIGTERM into their own exit
+// codes so that supervisors can tell them from failures.
+type waiter struct {
+	timeout   time.Duration // zero waits forever
+	interval  time.Duration
+	heartbeat bool
+	start     time.Time
+	signals   chan os.Signal
+	changes   <-chan struct{}
+	stopWatch func()
+}
+
+// waitHeartbeat is the line written to stdout in JSON mode for each poll
+// that found nothing, with --heartbeat.
+type waitHeartbeat struct {
+	Heartbeat     bool `json:"heartbeat"`
+	WaitedSeconds int  `json:"waited_seconds"`
+}
+
+// newWaiter starts catching signals and, if b can, watching for changes.
+// A backend that fails to start watching is polled instead. Callers must
+// call close.
+func newWaiter(b backend.Backend, timeout, interval time.Duration, heartbeat bool) *waiter {
+	w := &waiter{
+		timeout:   timeout,
+		interval:  interval,
+		heartbeat: heartbeat,
+		start:     time.Now(),
+		signals:   make(chan os.Signal, 1),
+	}
+	signal.Notify(w.signals, os.Interrupt, syscall.SIGTERM)
+	if watcher, ok := b.(backend.ChangeWatcher); ok {
+		if changes, stop, err := watcher.WatchChanges(); err == nil {
+			w.changes, w.stopWatch = changes, stop
+		}
+	}
+	return w
+}
+
+// close stops catching signals and watching for changes.
+func (w *waiter) close() {
+	signal.Stop(w.signals)
+	if w.stopWatch != nil {
+		w.stopWatch()
+	}
+}
+
+// wait blocks until the next check is due: the poll interval, with jitter
+// so that agents started together spread out, has passed or the backend
+// reported a change. It returns a TimeoutError once the timeout has passed
+// and an InterruptedError when a signal arrives. A signal that arrived
+// during a check is handled here, after it, so a claim is never cut short.
+func (w *waiter) wait() error {
+	var deadline <-chan time.Time
+	if w.timeout > 0 {
+		remaining := w.timeout - time.Since(w.start)
+		if remaining <= 0 {
+			return w.timedOut()
+		}
+		timer := time.NewTimer(remaining)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	poll := time.NewTimer(jitter(w.interval))
+	defer poll.Stop()
+
+	select {
+	case sig := <-w.signals:
+		return InterruptedError(sig)
+	case <-deadline:
+		return w.timedOut()
+	case <-w.changes:
+	case <-poll.C:
+		w.beat()
+	}
+	return nil
+}
+
+func (w *waiter) timedOut() error {
+	return TimeoutError(fmt.Sprintf("no task became available within %s", w.timeout))
+}
+
+// beat reports a poll that found nothing, with --heartbeat: a JSON line on
+// stdout in JSON mode, so that a reader sees the command is alive, and a
+// message on stderr otherwise.
+func (w *waiter) beat() {
+	if !w.heartbeat {
+		return
+	}
+	waited := time.Since(w.start).Round(time.Second)
+	if GetFormat() == "json" {
+		json.NewEncoder(os.Stdout).Encode(waitHeartbeat{Heartbeat: true, WaitedSeconds: int(waited.Seconds())})
+		return
+	}
+	fmt.Fprintf(os.Stderr, "waiting for a task (%s)\n", waited)
+}
+
+// jitter returns d varied at random by up to a fifth either way.
+func jitter(d time.Duration) time.Duration {
+	spread := d * 2 / 5
+	if spread <= 0 {
+		return d
+	}
+	return d - spread/2 + rand.N(spread)
+}
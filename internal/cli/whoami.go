@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/alexbrand/backlog/internal/config"
+	"github.com/alexbrand/backlog/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var whoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show the agent ID used for claims and comments",
+	Long: `Show the agent ID this command would act as and where it came from.
+
+The agent ID is resolved in priority order: --agent-id, BACKLOG_AGENT_ID,
+workspaces.<name>.agent_id, defaults.agent_id, and finally an ID generated
+on first use and stored in ~/.config/backlog/agent-id. The workspace's agent
+label prefix and the resulting claim label are shown as well.
+
+Examples:
+  backlog whoami
+  backlog whoami -w github-main
+  backlog whoami -f id-only`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runWhoami()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(whoamiCmd)
+}
+
+func runWhoami() error {
+	// Without a config there is no workspace, but an agent ID still resolves
+	ws, _, err := config.GetWorkspace(GetWorkspace())
+	if err != nil && GetWorkspace() != "" {
+		return ConfigError(err.Error())
+	}
+
+	identity, err := ResolveAgent(ws)
+	if err != nil {
+		return ConfigError(err.Error())
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatWhoami(os.Stdout, &identity)
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/config"
+	"github.com/alexbrand/backlog/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Inspect the configured workspaces",
+	Long:  `Inspect the workspaces configured in the config file.`,
+}
+
+var workspaceCapabilitiesCmd = &cobra.Command{
+	Use:   "capabilities [name]",
+	Short: "Compare the optional operations each workspace supports",
+	Long: `List the optional operations of every configured workspace's backend side
+by side, or of the named workspace (or --workspace) only.
+
+This is 'backlog capabilities' for all workspaces at once, so an agent
+working across trackers can see up front which of claim, reorder, link,
+sync and the rest it can use where. As there, backends are not contacted,
+except for exec workspaces, whose program is started to ask which
+operations it supports. An exec workspace whose program fails is reported
+with its error instead of failing the command.
+
+A command that needs an operation its workspace doesn't support fails with
+exit code 1 and error_code UNSUPPORTED in JSON output, naming the backend
+and the operation.
+
+With -f json the output is {"workspaces": [{"workspace", "backend",
+"operations": [{"name", "commands", "supported"}], "error"}]}. With
+-f id-only only the operations every listed workspace supports are printed,
+one per line.
+
+Examples:
+  backlog workspace capabilities
+  backlog workspace capabilities linear-main
+  backlog workspace capabilities -f json`,
+	Args: cobra.MaximumNArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeWorkspaces(cmd, args, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := GetWorkspace()
+		if len(args) > 0 {
+			name = args[0]
+		}
+		return runWorkspaceCapabilities(name)
+	},
+}
+
+func init() {
+	workspaceCmd.AddCommand(workspaceCapabilitiesCmd)
+	rootCmd.AddCommand(workspaceCmd)
+}
+
+func runWorkspaceCapabilities(name string) error {
+	var names []string
+	cfg := config.Get()
+	if cfg != nil {
+		for wsName := range cfg.Workspaces {
+			names = append(names, wsName)
+		}
+	}
+	sort.Strings(names)
+	if name != "" {
+		if cfg == nil {
+			return ConfigError(fmt.Sprintf("workspace %q not found", name))
+		}
+		if _, ok := cfg.Workspaces[name]; !ok {
+			return ConfigError(fmt.Sprintf("workspace %q not found", name))
+		}
+		names = []string{name}
+	}
+
+	var workspaces []backend.WorkspaceCapabilities
+	for _, wsName := range names {
+		ws := cfg.Workspaces[wsName]
+		workspaces = append(workspaces, workspaceCapabilities(&ws, wsName))
+	}
+
+	formatter := output.New(output.Format(GetFormat()))
+	return formatter.FormatWorkspaceCapabilities(os.Stdout, workspaces)
+}
+
+// workspaceCapabilities reports the optional operations of a workspace's
+// backend, connecting only to backends that learn them on connecting.
+func workspaceCapabilities(ws *config.Workspace, name string) backend.WorkspaceCapabilities {
+	result := backend.WorkspaceCapabilities{
+		Workspace:    name,
+		Capabilities: backend.Capabilities{Backend: ws.Backend, Operations: []backend.Capability{}},
+	}
+	b, backendCfg, err := workspaceBackend(ws, name)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if negotiator, ok := b.(backend.Negotiator); ok {
+		if err := b.Connect(backendCfg); err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		defer b.Disconnect()
+		b = negotiator.Negotiated()
+	}
+	result.Capabilities = *backend.CapabilitiesOf(b)
+	return result
+}
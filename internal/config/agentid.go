@@ -0,0 +1,168 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AgentIDPath returns the file holding the auto-generated agent ID,
+// ~/.config/backlog/agent-id.
+func AgentIDPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "backlog", "agent-id"), nil
+}
+
+// LoadOrCreateAgentID returns the persisted agent ID and the file it was
+// read from. On first use an ID of the form "<host>-<random>" is generated
+// and stored. Concurrent first uses agree on a single ID: whichever process
+// creates the file first wins and the others read its ID.
+func LoadOrCreateAgentID() (id, path string, err error) {
+	path, err = AgentIDPath()
+	if err != nil {
+		return "", "", err
+	}
+
+	id, err = readAgentID(path)
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		return id, path, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	generated, err := generateAgentID()
+	if err != nil {
+		return "", "", err
+	}
+
+	// Write to a temporary file and hard-link it into place, which fails
+	// rather than overwrites if another process got there first
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".agent-id-*")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to write agent ID: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.WriteString(generated + "\n")
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to write agent ID: %w", err)
+	}
+	if err := os.Link(tmp.Name(), path); err != nil && !errors.Is(err, os.ErrExist) {
+		return "", "", fmt.Errorf("failed to write agent ID: %w", err)
+	}
+
+	id, err = readAgentID(path)
+	return id, path, err
+}
+
+// readAgentID reads an agent ID file, rejecting one that is empty.
+func readAgentID(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	id := strings.TrimSpace(string(data))
+	if id == "" {
+		return "", fmt.Errorf("agent ID file %s is empty", path)
+	}
+	return id, nil
+}
+
+// generateAgentID returns a new agent ID made of the short hostname and six
+// random hex characters, e.g. "build-box-3f9a1c".
+func generateAgentID() (string, error) {
+	host, _ := os.Hostname()
+	host, _, _ = strings.Cut(strings.ToLower(host), ".")
+	host = strings.Trim(strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			return r
+		}
+		return '-'
+	}, host), "-")
+	if host == "" {
+		host = "agent"
+	}
+
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate agent ID: %w", err)
+	}
+	return host + "-" + hex.EncodeToString(suffix), nil
+}
+
+// Agent ID sources, from highest to lowest precedence.
+const (
+	AgentSourceFlag      = "flag"
+	AgentSourceEnv       = "env"
+	AgentSourceFile      = "file"
+	AgentSourceConfig    = "config"
+	AgentSourceGenerated = "generated"
+)
+
+// ResolvedAgentID is an agent ID and where it came from.
+type ResolvedAgentID struct {
+	ID     string
+	Source string // One of the AgentSource constants
+	Origin string // The flag, variable, config key, or file that supplied the ID
+}
+
+// ResolveAgentID resolves the agent ID following the priority chain:
+//  1. flagValue (--agent-id)
+//  2. Environment variable (BACKLOG_AGENT_ID)
+//  3. Workspace agent ID file (workspaces.<name>.agent_id_file)
+//  4. Workspace config (workspaces.<name>.agent_id)
+//  5. Global agent ID file (defaults.agent_id_file)
+//  6. Global default (defaults.agent_id)
+//  7. Auto-generated ID persisted at ~/.config/backlog/agent-id
+//
+// Blank values are skipped. A configured agent ID file that cannot be read
+// or is empty is an error rather than a silent fallback.
+func ResolveAgentID(flagValue string, ws *Workspace, wsName string) (ResolvedAgentID, error) {
+	if id := strings.TrimSpace(flagValue); id != "" {
+		return ResolvedAgentID{ID: id, Source: AgentSourceFlag, Origin: "--agent-id"}, nil
+	}
+	if id := strings.TrimSpace(os.Getenv("BACKLOG_AGENT_ID")); id != "" {
+		return ResolvedAgentID{ID: id, Source: AgentSourceEnv, Origin: "BACKLOG_AGENT_ID"}, nil
+	}
+
+	// The workspace's settings, then the global defaults; at each level a
+	// file wins over a literal ID
+	type level struct {
+		prefix, file, id string
+	}
+	var levels []level
+	if ws != nil {
+		levels = append(levels, level{"workspaces." + wsName + ".", ws.AgentIDFile, ws.AgentID})
+	}
+	if cfg := Get(); cfg != nil {
+		levels = append(levels, level{"defaults.", cfg.Defaults.AgentIDFile, cfg.Defaults.AgentID})
+	}
+	for _, l := range levels {
+		if path := strings.TrimSpace(l.file); path != "" {
+			id, err := readAgentID(path)
+			if err != nil {
+				return ResolvedAgentID{}, fmt.Errorf("%sagent_id_file: %w", l.prefix, err)
+			}
+			return ResolvedAgentID{ID: id, Source: AgentSourceFile, Origin: path}, nil
+		}
+		if id := strings.TrimSpace(l.id); id != "" {
+			return ResolvedAgentID{ID: id, Source: AgentSourceConfig, Origin: l.prefix + "agent_id"}, nil
+		}
+	}
+
+	id, path, err := LoadOrCreateAgentID()
+	if err != nil {
+		return ResolvedAgentID{}, fmt.Errorf("set --agent-id or BACKLOG_AGENT_ID (%v)", err)
+	}
+	return ResolvedAgentID{ID: id, Source: AgentSourceGenerated, Origin: path}, nil
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLoadOrCreateAgentID(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	id, path, err := LoadOrCreateAgentID()
+	if err != nil {
+		t.Fatalf("LoadOrCreateAgentID failed: %v", err)
+	}
+	if want := filepath.Join(home, ".config", "backlog", "agent-id"); path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+	if !regexp.MustCompile(`^[a-z0-9-]+-[0-9a-f]{6}$`).MatchString(id) {
+		t.Errorf("generated ID %q does not look like host-shortrand", id)
+	}
+
+	again, _, err := LoadOrCreateAgentID()
+	if err != nil {
+		t.Fatalf("LoadOrCreateAgentID failed: %v", err)
+	}
+	if again != id {
+		t.Errorf("second call returned %q, want persisted %q", again, id)
+	}
+}
+
+func TestLoadOrCreateAgentID_Concurrent(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	ids := make([]string, 8)
+	var wg sync.WaitGroup
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ids[i], _, _ = LoadOrCreateAgentID()
+		}(i)
+	}
+	wg.Wait()
+
+	for _, id := range ids {
+		if id == "" || id != ids[0] {
+			t.Fatalf("concurrent first use returned different IDs: %v", ids)
+		}
+	}
+}
+
+func TestLoadOrCreateAgentID_EmptyFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".config", "backlog")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "agent-id"), []byte("\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if id, _, err := LoadOrCreateAgentID(); err == nil {
+		t.Errorf("expected error for empty agent ID file, got %q", id)
+	}
+}
+
+func TestResolveAgentID_Precedence(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	wsFile := filepath.Join(dir, "ws-agent-id")
+	defaultsFile := filepath.Join(dir, "agent-id")
+	if err := os.WriteFile(wsFile, []byte("  ws-file-agent\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(defaultsFile, []byte("defaults-file-agent\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfgPath := writeTestConfig(t, `version: 1
+defaults:
+  agent_id: defaults-agent
+  agent_id_file: `+defaultsFile+`
+workspaces:
+  main:
+    backend: local
+    agent_id: ws-agent
+    agent_id_file: `+wsFile+`
+  plain:
+    backend: local
+    agent_id: plain-agent
+  bare:
+    backend: local
+`)
+	if err := Init(cfgPath); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	main, _, _ := GetWorkspace("main")
+	plain, _, _ := GetWorkspace("plain")
+	bare, _, _ := GetWorkspace("bare")
+
+	tests := []struct {
+		name       string
+		flag       string
+		env        string
+		ws         *Workspace
+		wsName     string
+		wantID     string
+		wantSource string
+	}{
+		{"flag beats everything", "flag-agent", "env-agent", main, "main", "flag-agent", AgentSourceFlag},
+		{"env beats files", "", "env-agent", main, "main", "env-agent", AgentSourceEnv},
+		{"workspace file beats workspace literal", "", "", main, "main", "ws-file-agent", AgentSourceFile},
+		{"workspace literal beats defaults file", "", "", plain, "plain", "plain-agent", AgentSourceConfig},
+		{"defaults file beats defaults literal", "", "", bare, "bare", "defaults-file-agent", AgentSourceFile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BACKLOG_AGENT_ID", tt.env)
+			got, err := ResolveAgentID(tt.flag, tt.ws, tt.wsName)
+			if err != nil {
+				t.Fatalf("ResolveAgentID failed: %v", err)
+			}
+			if got.ID != tt.wantID || got.Source != tt.wantSource {
+				t.Errorf("ResolveAgentID = %+v, want ID %q from %s", got, tt.wantID, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestResolveAgentID_UnreadableFile(t *testing.T) {
+	t.Setenv("BACKLOG_AGENT_ID", "")
+	missing := filepath.Join(t.TempDir(), "missing")
+	cfgPath := writeTestConfig(t, `version: 1
+workspaces:
+  main:
+    backend: local
+    agent_id: ws-agent
+    agent_id_file: `+missing+`
+`)
+	if err := Init(cfgPath); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	ws, _, _ := GetWorkspace("main")
+	_, err := ResolveAgentID("", ws, "main")
+	if err == nil || !strings.Contains(err.Error(), "workspaces.main.agent_id_file") {
+		t.Errorf("expected error naming workspaces.main.agent_id_file, got %v", err)
+	}
+}
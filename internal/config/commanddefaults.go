@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// commandDefaultFlags lists, for each command that supports
+// command_defaults, the filter keys it accepts and the flag each one sets.
+var commandDefaultFlags = map[string]map[string]string{
+	"list": {
+		"status":       "status",
+		"priority":     "priority",
+		"assignee":     "assignee",
+		"labels":       "label",
+		"parent":       "parent",
+		"limit":        "limit",
+		"include_done": "include-done",
+	},
+	"next": {
+		"priority": "priority",
+		"labels":   "label",
+	},
+}
+
+// FlagDefault is a command flag value supplied by command_defaults.
+type FlagDefault struct {
+	Key   string // Filter key in the config file, e.g. "labels"
+	Flag  string // Flag the key sets, e.g. "label"
+	Value string // Value in flag syntax; lists are comma-separated
+}
+
+// CommandDefaults returns the flag defaults configured for a command, sorted
+// by key. A default replaces the flag's built-in value but never adds to a
+// value given on the command line; callers apply it only to flags that were
+// not set.
+func CommandDefaults(command string) []FlagDefault {
+	if cfg == nil {
+		return nil
+	}
+	flags := commandDefaultFlags[command]
+	var defaults []FlagDefault
+	for key, value := range cfg.CommandDefaults[command] {
+		flag, ok := flags[key]
+		if !ok {
+			continue
+		}
+		defaults = append(defaults, FlagDefault{Key: key, Flag: flag, Value: formatFlagValue(value)})
+	}
+	sort.Slice(defaults, func(i, j int) bool { return defaults[i].Key < defaults[j].Key })
+	return defaults
+}
+
+// formatFlagValue renders a command_defaults value in flag syntax.
+func formatFlagValue(v any) string {
+	switch v := v.(type) {
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, ",")
+	case []string:
+		return strings.Join(v, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// validateCommandDefaults rejects command_defaults entries for commands
+// that don't support them and filter keys those commands don't have.
+func validateCommandDefaults(defaults map[string]map[string]any) error {
+	for _, command := range sortedKeys(defaults) {
+		flags, ok := commandDefaultFlags[command]
+		if !ok {
+			return fmt.Errorf("invalid command_defaults: unknown command %q (valid: %s)",
+				command, strings.Join(sortedKeys(commandDefaultFlags), ", "))
+		}
+		for _, key := range sortedKeys(defaults[command]) {
+			if _, ok := flags[key]; !ok {
+				return fmt.Errorf("invalid command_defaults.%s: unknown filter key %q (valid: %s)",
+					command, key, strings.Join(sortedKeys(flags), ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of a map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
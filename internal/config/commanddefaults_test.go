@@ -0,0 +1,89 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCommandDefaults(t *testing.T) {
+	cfgPath := writeTestConfig(t, `version: 1
+command_defaults:
+  list:
+    labels: [team:infra, backend]
+    priority: [high, urgent]
+    include_done: true
+    limit: 20
+  next:
+    priority: urgent
+`)
+	if err := Init(cfgPath); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	want := []FlagDefault{
+		{Key: "include_done", Flag: "include-done", Value: "true"},
+		{Key: "labels", Flag: "label", Value: "team:infra,backend"},
+		{Key: "limit", Flag: "limit", Value: "20"},
+		{Key: "priority", Flag: "priority", Value: "high,urgent"},
+	}
+	if got := CommandDefaults("list"); !reflect.DeepEqual(got, want) {
+		t.Errorf("list defaults:\n got  %+v\n want %+v", got, want)
+	}
+
+	want = []FlagDefault{{Key: "priority", Flag: "priority", Value: "urgent"}}
+	if got := CommandDefaults("next"); !reflect.DeepEqual(got, want) {
+		t.Errorf("next defaults:\n got  %+v\n want %+v", got, want)
+	}
+
+	if got := CommandDefaults("show"); got != nil {
+		t.Errorf("expected no defaults for show, got %+v", got)
+	}
+
+	var found bool
+	for _, v := range Resolve(nil) {
+		if v.Key == "command_defaults.list.labels" {
+			found = true
+			if v.Value != "team:infra, backend" || v.Source != SourceFile {
+				t.Errorf("resolved command_defaults.list.labels = %+v", v)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected command_defaults.list.labels in resolved config")
+	}
+}
+
+func TestInit_InvalidCommandDefaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name: "unknown command",
+			content: `command_defaults:
+  lst:
+    labels: [bug]
+`,
+			wantErr: `invalid command_defaults: unknown command "lst" (valid: list, next)`,
+		},
+		{
+			name: "unknown filter key",
+			content: `command_defaults:
+  next:
+    status: [todo]
+`,
+			wantErr: `invalid command_defaults.next: unknown filter key "status" (valid: labels, priority)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Init(writeTestConfig(t, "version: 1\n"+tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
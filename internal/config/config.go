@@ -2,8 +2,13 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/alexbrand/backlog/internal/backend"
 	"github.com/spf13/viper"
 )
 
@@ -12,31 +17,62 @@ type Config struct {
 	Version    int                  `mapstructure:"version" json:"version"`
 	Defaults   Defaults             `mapstructure:"defaults" json:"defaults"`
 	Workspaces map[string]Workspace `mapstructure:"workspaces" json:"workspaces"`
+	Completion Completion           `mapstructure:"completion" json:"completion,omitempty"`
+
+	// CommandDefaults holds default filter flags per command, keyed by
+	// command name and then filter key (e.g. list.labels).
+	CommandDefaults map[string]map[string]any `mapstructure:"command_defaults" json:"command_defaults,omitempty"`
+}
+
+// Completion contains shell completion settings.
+type Completion struct {
+	// Remote lets completions query GitHub and Linear directly instead of
+	// reading the cache written by "backlog list".
+	Remote bool `mapstructure:"remote" json:"remote,omitempty"`
 }
 
 // Defaults contains global default settings.
 type Defaults struct {
-	Format    string `mapstructure:"format" json:"format,omitempty"`
-	Workspace string `mapstructure:"workspace" json:"workspace,omitempty"`
-	AgentID   string `mapstructure:"agent_id" json:"agent_id,omitempty"`
+	Format      string `mapstructure:"format" json:"format,omitempty"`
+	Workspace   string `mapstructure:"workspace" json:"workspace,omitempty"`
+	AgentID     string `mapstructure:"agent_id" json:"agent_id,omitempty"`
+	AgentIDFile string `mapstructure:"agent_id_file" json:"agent_id_file,omitempty"`
 }
 
 // Workspace represents a configured connection to a backend.
 type Workspace struct {
-	Backend          string            `mapstructure:"backend" json:"backend,omitempty"`
-	Repo             string            `mapstructure:"repo" json:"repo,omitempty"`
-	Team             string            `mapstructure:"team" json:"team,omitempty"`
-	Path             string            `mapstructure:"path" json:"path,omitempty"`
-	Project          int               `mapstructure:"project" json:"project,omitempty"`
-	StatusField      string            `mapstructure:"status_field" json:"status_field,omitempty"`
-	AgentID          string            `mapstructure:"agent_id" json:"agent_id,omitempty"`
-	AgentLabelPrefix string            `mapstructure:"agent_label_prefix" json:"agent_label_prefix,omitempty"`
-	Default          bool              `mapstructure:"default" json:"default,omitempty"`
-	APIKeyEnv        string            `mapstructure:"api_key_env" json:"api_key_env,omitempty"`
-	LockMode         string            `mapstructure:"lock_mode" json:"lock_mode,omitempty"`
-	GitSync          bool              `mapstructure:"git_sync" json:"git_sync,omitempty"`
-	StatusMap        map[string]Status `mapstructure:"status_map" json:"status_map,omitempty"`
-	DefaultFilters   DefaultFilters    `mapstructure:"default_filters" json:"default_filters,omitempty"`
+	Backend             string            `mapstructure:"backend" json:"backend,omitempty"`
+	Repo                string            `mapstructure:"repo" json:"repo,omitempty"`
+	Team                string            `mapstructure:"team" json:"team,omitempty"`
+	Path                string            `mapstructure:"path" json:"path,omitempty"`
+	Command             string            `mapstructure:"command" json:"command,omitempty"`
+	Args                []string          `mapstructure:"args" json:"args,omitempty"`
+	Project             int               `mapstructure:"project" json:"project,omitempty"`
+	StatusField         string            `mapstructure:"status_field" json:"status_field,omitempty"`
+	AgentID             string            `mapstructure:"agent_id" json:"agent_id,omitempty"`
+	AgentIDFile         string            `mapstructure:"agent_id_file" json:"agent_id_file,omitempty"`
+	AgentLabelPrefix    string            `mapstructure:"agent_label_prefix" json:"agent_label_prefix,omitempty"`
+	Default             bool              `mapstructure:"default" json:"default,omitempty"`
+	APIKeyEnv           string            `mapstructure:"api_key_env" json:"api_key_env,omitempty"`
+	LockMode            string            `mapstructure:"lock_mode" json:"lock_mode,omitempty"`
+	GitSync             bool              `mapstructure:"git_sync" json:"git_sync,omitempty"`
+	StatusMap           map[string]Status `mapstructure:"status_map" json:"status_map,omitempty"`
+	DefaultFilters      DefaultFilters    `mapstructure:"default_filters" json:"default_filters,omitempty"`
+	Timeout             time.Duration     `mapstructure:"timeout" json:"timeout,omitempty"`
+	ReopenStatus        string            `mapstructure:"reopen_status" json:"reopen_status,omitempty"`
+	PriorityLabelPrefix string            `mapstructure:"priority_label_prefix" json:"priority_label_prefix,omitempty"`
+	Hooks               Hooks             `mapstructure:"hooks" json:"hooks,omitempty"`
+	Changelog           Changelog         `mapstructure:"changelog" json:"changelog,omitempty"`
+	IdempotencyWindow   time.Duration     `mapstructure:"idempotency_window" json:"idempotency_window,omitempty"`
+	GitTimeout          time.Duration     `mapstructure:"git_timeout" json:"git_timeout,omitempty"`
+	StrictLabels        bool              `mapstructure:"strict_labels" json:"strict_labels,omitempty"`
+	Labels              LabelConfig       `mapstructure:"labels" json:"labels,omitempty"`
+	AssigneeMap         map[string]string `mapstructure:"assignee_map" json:"assignee_map,omitempty"`
+	AssignOnClaim       string            `mapstructure:"assign_on_claim" json:"assign_on_claim,omitempty"`
+	ClaimComment        bool              `mapstructure:"claim_comment" json:"claim_comment,omitempty"`
+	ReadOnly            bool              `mapstructure:"read_only" json:"read_only,omitempty"`
+	Index               *bool             `mapstructure:"index" json:"index,omitempty"`           // Local task index cache; nil means enabled
+	UndoLimit           *int              `mapstructure:"undo_limit" json:"undo_limit,omitempty"` // Local mutations kept for undo; nil means the default
 }
 
 // Status represents a status mapping configuration.
@@ -50,6 +86,41 @@ type DefaultFilters struct {
 	Labels []string `mapstructure:"labels" json:"labels,omitempty"`
 }
 
+// Hooks lists commands run after successful task mutations. Each command is
+// run through the shell with the event as JSON on stdin.
+type Hooks struct {
+	OnCreate []string      `mapstructure:"on_create" json:"on_create,omitempty"`
+	OnMove   []string      `mapstructure:"on_move" json:"on_move,omitempty"`
+	OnClaim  []string      `mapstructure:"on_claim" json:"on_claim,omitempty"`
+	Strict   bool          `mapstructure:"strict" json:"strict,omitempty"`   // Fail the command when a hook fails
+	Timeout  time.Duration `mapstructure:"timeout" json:"timeout,omitempty"` // Per-hook limit; defaults to 10s
+	// OnTransition runs commands after moves between specific statuses,
+	// keyed by "from->to" such as "in-progress->review". Either side may
+	// be "*" to match any status.
+	OnTransition map[string][]string `mapstructure:"on_transition" json:"on_transition,omitempty"`
+}
+
+// LabelConfig configures how a local workspace writes and matches labels.
+type LabelConfig struct {
+	Normalize string `mapstructure:"normalize" json:"normalize,omitempty"` // "lower" lowercases labels on write
+}
+
+// LabelNormalizeLower is the labels.normalize value that lowercases labels.
+const LabelNormalizeLower = "lower"
+
+// Changelog configures how "backlog changelog" groups completed tasks into
+// sections by a type label, such as type:feature.
+type Changelog struct {
+	LabelPrefix string             `mapstructure:"label_prefix" json:"label_prefix,omitempty"` // Defaults to "type"
+	Sections    []ChangelogSection `mapstructure:"sections" json:"sections,omitempty"`         // In output order
+}
+
+// ChangelogSection names the changelog section for one type label value.
+type ChangelogSection struct {
+	Label string `mapstructure:"label" json:"label"` // Value after the prefix, such as "feature"
+	Title string `mapstructure:"title" json:"title"` // Heading, such as "Features"
+}
+
 var (
 	cfg     *Config
 	cfgFile string
@@ -78,19 +149,124 @@ func Init(cfgPath string) error {
 	// Read config file if it exists
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			// Report syntax errors with their location when possible
+			if used := viper.ConfigFileUsed(); used != "" {
+				if data, readErr := os.ReadFile(used); readErr == nil && isYAMLFile(used) {
+					if err := checkConfigFile(used, data); err != nil {
+						return fmt.Errorf("invalid config file %w", err)
+					}
+				}
+			}
 			return fmt.Errorf("failed to read config file: %w", err)
 		}
 		// Config file not found is OK - we'll use defaults
 	}
 
+	// Expand ${VAR} references so committed configs can defer
+	// machine-specific values to the environment
+	interpolated = nil
+	if used := viper.ConfigFileUsed(); used != "" {
+		data, err := os.ReadFile(used)
+		if err != nil {
+			return fmt.Errorf("failed to read config file: %w", err)
+		}
+		if bytes.Contains(data, []byte("${")) {
+			expanded, doc, sources, err := interpolateConfig(data)
+			if err != nil {
+				return err
+			}
+			viper.SetConfigType("yaml")
+			if err := viper.ReadConfig(bytes.NewReader(expanded)); err != nil {
+				return fmt.Errorf("failed to read config file: %w", err)
+			}
+			interpolated = sources
+			// Check the expanded nodes rather than the re-encoded file, so
+			// errors point at lines in the file the user wrote
+			if isYAMLFile(used) {
+				if err := checkConfigDocument(used, doc); err != nil {
+					return fmt.Errorf("invalid config file %w", err)
+				}
+			}
+		} else if isYAMLFile(used) {
+			if err := checkConfigFile(used, data); err != nil {
+				return fmt.Errorf("invalid config file %w", err)
+			}
+		}
+	}
+
 	cfg = &Config{}
 	if err := viper.Unmarshal(cfg); err != nil {
 		return fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	if err := validateCommandDefaults(cfg.CommandDefaults); err != nil {
+		return err
+	}
+	if err := normalizeAssignOnClaim(cfg.Workspaces); err != nil {
+		return err
+	}
+	for name, ws := range cfg.Workspaces {
+		for key := range ws.Hooks.OnTransition {
+			if _, _, err := ParseTransition(key); err != nil {
+				return fmt.Errorf("workspace %q: hooks.on_transition: %w", name, err)
+			}
+		}
+		if n := ws.Labels.Normalize; n != "" && n != LabelNormalizeLower {
+			return fmt.Errorf("workspace %q: invalid labels.normalize %q (valid: %s)", name, n, LabelNormalizeLower)
+		}
+	}
+
 	return nil
 }
 
+// Values of the assign_on_claim workspace key.
+const (
+	AssignOnClaimTrue      = "true"
+	AssignOnClaimFalse     = "false"
+	AssignOnClaimLabelOnly = "label-only"
+)
+
+// normalizeAssignOnClaim rewrites each workspace's assign_on_claim in
+// canonical form. Unquoted YAML booleans are decoded into the string field
+// as "1" and "0".
+func normalizeAssignOnClaim(workspaces map[string]Workspace) error {
+	for name, ws := range workspaces {
+		var value string
+		switch strings.ToLower(ws.AssignOnClaim) {
+		case "":
+			continue
+		case AssignOnClaimTrue, "1":
+			value = AssignOnClaimTrue
+		case AssignOnClaimFalse, "0":
+			value = AssignOnClaimFalse
+		case AssignOnClaimLabelOnly:
+			value = AssignOnClaimLabelOnly
+		default:
+			return fmt.Errorf("workspace %q: invalid assign_on_claim %q (valid: true, false, label-only)", name, ws.AssignOnClaim)
+		}
+		ws.AssignOnClaim = value
+		workspaces[name] = ws
+	}
+	return nil
+}
+
+// ParseTransition splits a hooks.on_transition key such as
+// "in-progress->review" into its from and to statuses, each a status name
+// or "*" for any status.
+func ParseTransition(key string) (from, to string, err error) {
+	from, to, ok := strings.Cut(key, "->")
+	if !ok {
+		return "", "", fmt.Errorf("invalid transition %q (expected from->to, e.g. in-progress->review)", key)
+	}
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	for _, status := range []string{from, to} {
+		if status != "*" && !backend.Status(status).IsValid() {
+			return "", "", fmt.Errorf("invalid transition %q: unknown status %q", key, status)
+		}
+	}
+	return from, to, nil
+}
+
 // Get returns the current configuration.
 // Returns nil if Init has not been called.
 func Get() *Config {
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -270,3 +271,118 @@ workspaces:
 		t.Errorf("expected backend 'local', got %q", ws.Backend)
 	}
 }
+
+func TestInit_AssignOnClaim(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfgPath := filepath.Join(tmpDir, "config.yaml")
+
+	cfgContent := `
+workspaces:
+  bare:
+    backend: github
+    repo: user/repo
+    assign_on_claim: false
+  quoted:
+    backend: github
+    repo: user/repo
+    assign_on_claim: "true"
+  labels:
+    backend: github
+    repo: user/repo
+    assign_on_claim: label-only
+    assignee_map:
+      claude-1: claude-bot
+  unset:
+    backend: github
+    repo: user/repo
+`
+	if err := os.WriteFile(cfgPath, []byte(cfgContent), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	if err := Init(cfgPath); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	want := map[string]string{"bare": "false", "quoted": "true", "labels": "label-only", "unset": ""}
+	for name, value := range want {
+		if got := Get().Workspaces[name].AssignOnClaim; got != value {
+			t.Errorf("workspace %s: assign_on_claim = %q, want %q", name, got, value)
+		}
+	}
+	if got := Get().Workspaces["labels"].AssigneeMap["claude-1"]; got != "claude-bot" {
+		t.Errorf("assignee_map[claude-1] = %q, want claude-bot", got)
+	}
+}
+
+func TestInit_InvalidAssignOnClaim(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfgPath := filepath.Join(tmpDir, "config.yaml")
+
+	cfgContent := `
+workspaces:
+  main:
+    backend: github
+    repo: user/repo
+    assign_on_claim: sometimes
+`
+	if err := os.WriteFile(cfgPath, []byte(cfgContent), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	if err := Init(cfgPath); err == nil {
+		t.Fatal("Init succeeded with an invalid assign_on_claim, want error")
+	}
+}
+
+func TestInit_LabelNormalize(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfgPath := filepath.Join(tmpDir, "config.yaml")
+
+	cfgContent := `
+workspaces:
+  main:
+    backend: local
+    labels:
+      normalize: lower
+`
+	if err := os.WriteFile(cfgPath, []byte(cfgContent), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	if err := Init(cfgPath); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	if got := Get().Workspaces["main"].Labels.Normalize; got != LabelNormalizeLower {
+		t.Errorf("labels.normalize = %q, want %q", got, LabelNormalizeLower)
+	}
+
+	cfgContent = strings.Replace(cfgContent, "normalize: lower", "normalize: upper", 1)
+	if err := os.WriteFile(cfgPath, []byte(cfgContent), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	if err := Init(cfgPath); err == nil || !strings.Contains(err.Error(), "labels.normalize") {
+		t.Fatalf("Init() error = %v, want an invalid labels.normalize error", err)
+	}
+}
+
+func TestParseTransition(t *testing.T) {
+	tests := []struct {
+		key      string
+		from, to string
+		wantErr  bool
+	}{
+		{key: "in-progress->review", from: "in-progress", to: "review"},
+		{key: "*->done", from: "*", to: "done"},
+		{key: "todo -> *", from: "todo", to: "*"},
+		{key: "in-progress", wantErr: true},
+		{key: "todo->shipped", wantErr: true},
+	}
+	for _, tt := range tests {
+		from, to, err := ParseTransition(tt.key)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTransition(%q) error = %v, wantErr %v", tt.key, err, tt.wantErr)
+			continue
+		}
+		if from != tt.from || to != tt.to {
+			t.Errorf("ParseTransition(%q) = %q, %q, want %q, %q", tt.key, from, to, tt.from, tt.to)
+		}
+	}
+}
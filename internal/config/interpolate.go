@@ -0,0 +1,130 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envRefPattern matches ${VAR} and ${VAR:-default} references.
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// interpolated maps the dotted keys whose values came from environment
+// variables to the variables that supplied them, for "config show --resolved".
+var interpolated map[string][]string
+
+// InterpolationError reports a ${VAR} reference to an unset variable with no
+// default.
+type InterpolationError struct {
+	Key      string
+	Variable string
+}
+
+func (e *InterpolationError) Error() string {
+	return fmt.Sprintf("config key %q: environment variable %s is not set (use ${%s:-default} to provide a default)",
+		e.Key, e.Variable, e.Variable)
+}
+
+// expandEnv replaces ${VAR} and ${VAR:-default} references in s. As in the
+// shell, the default is used when the variable is unset or empty. It returns
+// the variables whose values were used.
+func expandEnv(key, s string) (string, []string, error) {
+	var used []string
+	var err error
+	result := envRefPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		m := envRefPattern.FindStringSubmatch(ref)
+		name, hasDefault, def := m[1], m[2] != "", m[3]
+		value, ok := os.LookupEnv(name)
+		switch {
+		case ok && (value != "" || !hasDefault):
+			used = append(used, name)
+			return value
+		case hasDefault:
+			return def
+		default:
+			if err == nil {
+				err = &InterpolationError{Key: key, Variable: name}
+			}
+			return ref
+		}
+	})
+	return result, used, err
+}
+
+// interpolateConfig expands environment references in the scalar values of
+// a YAML document. Keys and comments are left alone. It returns the expanded
+// document, both re-encoded and as nodes, and the keys whose values came
+// from the environment. The nodes keep their positions in data, which the
+// re-encoded document doesn't.
+func interpolateConfig(data []byte) ([]byte, *yaml.Node, map[string][]string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, nil, err
+	}
+
+	sources := make(map[string][]string)
+	if err := interpolateNode(&doc, "", sources); err != nil {
+		return nil, nil, nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, nil, nil, err
+	}
+	return buf.Bytes(), &doc, sources, nil
+}
+
+func interpolateNode(node *yaml.Node, key string, sources map[string][]string) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			if err := interpolateNode(child, key, sources); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			childKey := strings.ToLower(node.Content[i].Value)
+			if key != "" {
+				childKey = key + "." + childKey
+			}
+			if err := interpolateNode(node.Content[i+1], childKey, sources); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			if err := interpolateNode(child, fmt.Sprintf("%s[%d]", key, i), sources); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		if !strings.Contains(node.Value, "${") {
+			return nil
+		}
+		value, used, err := expandEnv(key, node.Value)
+		if err != nil {
+			return err
+		}
+		node.Value = value
+		// Let the expanded value be typed like a literal one, e.g. a bool
+		node.Tag = ""
+		node.Style = 0
+		node.Tag = node.ShortTag()
+		if len(used) > 0 {
+			// Sequence items are reported under the sequence's key
+			base, _, _ := strings.Cut(key, "[")
+			sources[base] = append(sources[base], used...)
+		}
+	}
+	return nil
+}
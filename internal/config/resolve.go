@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Value sources, from lowest to highest precedence.
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+var sourceRank = map[string]int{
+	SourceDefault: 0,
+	SourceFile:    1,
+	SourceEnv:     2,
+	SourceFlag:    3,
+}
+
+// redactedValue replaces secrets in the resolved view.
+const redactedValue = "[redacted]"
+
+// ResolvedValue is an effective configuration value and where it came from.
+type ResolvedValue struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`           // default, file, env, or flag
+	Origin string `json:"origin,omitempty"` // Variable or flag that supplied the value
+}
+
+// Override is a value supplied outside the config file, such as by a
+// command-line flag, a BACKLOG_* environment variable, or the credentials
+// file.
+type Override struct {
+	Key    string
+	Value  string
+	Source string // One of the Source constants
+	Origin string // Variable, flag, or file that supplied the value
+}
+
+// Resolve returns the effective configuration as a sorted list of keys,
+// merging built-in defaults, the config file (after ${VAR} interpolation),
+// and overrides. For each key the highest-precedence source wins: flag, then
+// env, then file, then default. Secrets are redacted.
+func Resolve(overrides []Override) []ResolvedValue {
+	values := make(map[string]ResolvedValue)
+	set := func(v ResolvedValue) {
+		if current, ok := values[v.Key]; ok && sourceRank[current.Source] > sourceRank[v.Source] {
+			return
+		}
+		values[v.Key] = v
+	}
+
+	for _, key := range viper.AllKeys() {
+		// Skip flags bound to viper that aren't part of the file format
+		section, _, _ := strings.Cut(key, ".")
+		if !configSections[section] {
+			continue
+		}
+		v := ResolvedValue{Key: key, Value: formatValue(viper.Get(key)), Source: SourceDefault}
+		if viper.InConfig(key) {
+			v.Source = SourceFile
+		}
+		if vars, ok := interpolated[key]; ok {
+			v.Source = SourceEnv
+			v.Origin = strings.Join(vars, ", ")
+		}
+		set(v)
+	}
+
+	// Built-in workspace defaults that the file leaves unset
+	if cfg != nil {
+		for name, ws := range cfg.Workspaces {
+			ws := ws
+			for _, setting := range Settings(&ws) {
+				if setting.Default {
+					set(ResolvedValue{Key: "workspaces." + name + "." + setting.Key, Value: setting.Value, Source: SourceDefault})
+				}
+			}
+		}
+	}
+
+	for _, o := range overrides {
+		set(ResolvedValue{Key: o.Key, Value: o.Value, Source: o.Source, Origin: o.Origin})
+	}
+
+	result := make([]ResolvedValue, 0, len(values))
+	for _, v := range values {
+		if isSecretKey(v.Key) && v.Value != "" {
+			v.Value = redactedValue
+		}
+		result = append(result, v)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
+
+// configSections holds the top-level keys of the config file format.
+var configSections = func() map[string]bool {
+	sections := make(map[string]bool)
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		sections[t.Field(i).Tag.Get("mapstructure")] = true
+	}
+	return sections
+}()
+
+// isSecretKey reports whether a key holds a credential. Keys naming where a
+// credential lives, such as api_key_env, are not secrets themselves.
+func isSecretKey(key string) bool {
+	name := key[strings.LastIndex(key, ".")+1:]
+	for _, suffix := range []string{"token", "secret", "password", "api_key"} {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatValue renders a config value for display.
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = formatValue(item)
+		}
+		return strings.Join(parts, ", ")
+	case []string:
+		return strings.Join(v, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("BACKLOG_TEST_DIR", "/srv/tasks")
+	t.Setenv("BACKLOG_TEST_EMPTY", "")
+
+	tests := []struct {
+		input   string
+		want    string
+		used    int
+		wantErr bool
+	}{
+		{input: "plain", want: "plain"},
+		{input: "${BACKLOG_TEST_DIR}/main", want: "/srv/tasks/main", used: 1},
+		{input: "${BACKLOG_TEST_UNSET:-./.backlog}", want: "./.backlog"},
+		{input: "${BACKLOG_TEST_DIR:-./.backlog}", want: "/srv/tasks", used: 1},
+		{input: "${BACKLOG_TEST_EMPTY:-fallback}", want: "fallback"},
+		{input: "${BACKLOG_TEST_EMPTY}", want: "", used: 1},
+		{input: "${BACKLOG_TEST_UNSET}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, used, err := expandEnv("workspaces.main.path", tt.input)
+			if tt.wantErr {
+				var interpErr *InterpolationError
+				if !errors.As(err, &interpErr) || interpErr.Key != "workspaces.main.path" {
+					t.Fatalf("expected interpolation error naming the key, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want || len(used) != tt.used {
+				t.Errorf("expected %q using %d variables, got %q using %v", tt.want, tt.used, got, used)
+			}
+		})
+	}
+}
+
+func TestInit_Interpolation(t *testing.T) {
+	t.Setenv("BACKLOG_TEST_REPO", "owner/repo")
+	t.Setenv("BACKLOG_TEST_SYNC", "true")
+
+	cfgPath := writeTestConfig(t, `version: 1
+workspaces:
+  main:
+    backend: github
+    repo: ${BACKLOG_TEST_REPO}
+    git_sync: ${BACKLOG_TEST_SYNC}
+    path: ${BACKLOG_TEST_UNSET:-./.backlog} # local fallback
+`)
+	if err := Init(cfgPath); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	main := Get().Workspaces["main"]
+	if main.Repo != "owner/repo" || !main.GitSync || main.Path != "./.backlog" {
+		t.Errorf("expected interpolated workspace, got %+v", main)
+	}
+}
+
+func TestInit_InterpolationMissingVariable(t *testing.T) {
+	cfgPath := writeTestConfig(t, `version: 1
+workspaces:
+  main:
+    backend: github
+    repo: ${BACKLOG_TEST_UNSET}
+`)
+	err := Init(cfgPath)
+	var interpErr *InterpolationError
+	if !errors.As(err, &interpErr) {
+		t.Fatalf("expected interpolation error, got %v", err)
+	}
+	if interpErr.Key != "workspaces.main.repo" || interpErr.Variable != "BACKLOG_TEST_UNSET" {
+		t.Errorf("unexpected error details: %+v", interpErr)
+	}
+}
+
+func TestInit_InterpolationReportsOriginalLines(t *testing.T) {
+	t.Setenv("BACKLOG_TEST_REPO", "owner/repo")
+
+	// The blank lines are dropped when the expanded file is re-encoded
+	cfgPath := writeTestConfig(t, `version: 1
+
+workspaces:
+
+  main:
+    backend: github
+    repo: ${BACKLOG_TEST_REPO}
+
+    git_synk: true
+`)
+	err := Init(cfgPath)
+	if err == nil {
+		t.Fatal("expected an error for the unknown key")
+	}
+	if want := cfgPath + ":9:5:"; !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error at %q, got %v", want, err)
+	}
+}
+
+func TestResolve_Precedence(t *testing.T) {
+	t.Setenv("BACKLOG_TEST_PATH", "/data/backlog")
+
+	cfgPath := writeTestConfig(t, `version: 1
+defaults:
+  format: plain
+  agent_id: file-agent
+  workspace: main
+workspaces:
+  main:
+    backend: local
+    path: ${BACKLOG_TEST_PATH}
+`)
+	if err := Init(cfgPath); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	values := resolvedByKey(Resolve([]Override{
+		// Flags win over env regardless of order
+		{Key: "defaults.agent_id", Value: "flag-agent", Source: SourceFlag, Origin: "--agent-id"},
+		{Key: "defaults.agent_id", Value: "env-agent", Source: SourceEnv, Origin: "BACKLOG_AGENT_ID"},
+		{Key: "defaults.workspace", Value: "other", Source: SourceEnv, Origin: "BACKLOG_WORKSPACE"},
+	}))
+
+	tests := []struct {
+		key, value, source, origin string
+	}{
+		{"defaults.agent_id", "flag-agent", SourceFlag, "--agent-id"},
+		{"defaults.workspace", "other", SourceEnv, "BACKLOG_WORKSPACE"},
+		{"defaults.format", "plain", SourceFile, ""},
+		{"workspaces.main.path", "/data/backlog", SourceEnv, "BACKLOG_TEST_PATH"},
+		{"workspaces.main.lock_mode", "file", SourceDefault, ""},
+	}
+	for _, tt := range tests {
+		v, ok := values[tt.key]
+		if !ok {
+			t.Errorf("%s: missing from resolved values", tt.key)
+			continue
+		}
+		if v.Value != tt.value || v.Source != tt.source || v.Origin != tt.origin {
+			t.Errorf("%s: expected %q from %s (%s), got %q from %s (%s)",
+				tt.key, tt.value, tt.source, tt.origin, v.Value, v.Source, v.Origin)
+		}
+	}
+}
+
+func TestIsSecretKey(t *testing.T) {
+	// Unknown keys are rejected, so secrets only reach Resolve through
+	// overrides such as credentials
+	for key, want := range map[string]bool{
+		"credentials.github.token":    true,
+		"workspaces.main.api_key":     true,
+		"workspaces.main.api_key_env": false,
+		"workspaces.main.repo":        false,
+	} {
+		if got := isSecretKey(key); got != want {
+			t.Errorf("isSecretKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestResolve_LowerPrecedenceOverrideIgnored(t *testing.T) {
+	cfgPath := writeTestConfig(t, "version: 1\ndefaults:\n  format: json\n")
+	if err := Init(cfgPath); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	values := resolvedByKey(Resolve([]Override{
+		{Key: "defaults.format", Value: "table", Source: SourceDefault},
+	}))
+	if v := values["defaults.format"]; v.Value != "json" || v.Source != SourceFile {
+		t.Errorf("expected file value to win over a default, got %+v", v)
+	}
+}
+
+func writeTestConfig(t *testing.T, content string) string {
+	t.Helper()
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(cfgPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	return cfgPath
+}
+
+func resolvedByKey(values []ResolvedValue) map[string]ResolvedValue {
+	byKey := make(map[string]ResolvedValue, len(values))
+	for _, v := range values {
+		byKey[v.Key] = v
+	}
+	return byKey
+}
@@ -0,0 +1,293 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Setting is the effective value of a workspace key.
+type Setting struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Default bool   `json:"default,omitempty"` // Value is the built-in default, not set in the file
+}
+
+// settingSpec describes a workspace key that can be read and written with
+// "backlog config get/set".
+type settingSpec struct {
+	key    string
+	values []string                   // Allowed values, when restricted
+	def    func(ws *Workspace) string // Built-in default, if any
+}
+
+// settingSpecs lists the scalar workspace keys in display order. Nested keys
+// such as status_map, default_filters, and assignee_map, and lists such as
+// args, are edited in the file directly.
+var settingSpecs = []settingSpec{
+	{key: "backend", values: []string{"local", "github", "linear", "exec"}},
+	{key: "path", def: localDefault(".backlog")},
+	{key: "repo"},
+	{key: "project"},
+	{key: "status_field"},
+	{key: "team"},
+	{key: "command"},
+	{key: "api_key_env"},
+	{key: "agent_id"},
+	{key: "agent_id_file"},
+	{key: "agent_label_prefix", def: func(*Workspace) string { return "agent" }},
+	{key: "priority_label_prefix", def: func(ws *Workspace) string {
+		if ws.Backend == "github" {
+			return "priority:"
+		}
+		return ""
+	}},
+	{key: "lock_mode", values: []string{"file", "git"}, def: localDefault("file")},
+	{key: "git_sync"},
+	{key: "git_timeout", def: localDefault("30s")},
+	{key: "index", def: localDefault("true")},
+	{key: "undo_limit", def: localDefault("20")},
+	{key: "reopen_status", values: []string{"backlog", "todo", "in-progress", "review"}, def: func(*Workspace) string { return "todo" }},
+	{key: "timeout"},
+	{key: "idempotency_window", def: func(*Workspace) string { return "24h" }},
+	{key: "strict_labels"},
+	{key: "assign_on_claim", values: []string{AssignOnClaimTrue, AssignOnClaimFalse, AssignOnClaimLabelOnly}, def: func(ws *Workspace) string {
+		if ws.Backend == "github" {
+			return AssignOnClaimTrue
+		}
+		return ""
+	}},
+	{key: "claim_comment"},
+	{key: "read_only"},
+	{key: "default"},
+}
+
+// localDefault returns a default that only applies to local workspaces.
+func localDefault(value string) func(ws *Workspace) string {
+	return func(ws *Workspace) string {
+		if ws.Backend == "local" {
+			return value
+		}
+		return ""
+	}
+}
+
+// SettingKeys returns the workspace keys supported by GetSetting and
+// SetWorkspaceValue.
+func SettingKeys() []string {
+	keys := make([]string, len(settingSpecs))
+	for i, spec := range settingSpecs {
+		keys[i] = spec.key
+	}
+	return keys
+}
+
+func findSettingSpec(key string) (*settingSpec, error) {
+	for i := range settingSpecs {
+		if settingSpecs[i].key == key {
+			return &settingSpecs[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unknown workspace key %q (valid: %s)", key, strings.Join(SettingKeys(), ", "))
+}
+
+// workspaceField returns the Workspace field tagged with the given key.
+func workspaceField(ws *Workspace, key string) reflect.Value {
+	v := reflect.ValueOf(ws).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).Tag.Get("mapstructure") == key {
+			return v.Field(i)
+		}
+	}
+	panic("config: no workspace field for key " + key)
+}
+
+// settingKind returns the kind of a workspace key's value, looking through
+// the pointer of keys whose default differs from their zero value.
+func settingKind(ws *Workspace, key string) reflect.Kind {
+	t := workspaceField(ws, key).Type()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind()
+}
+
+// GetSetting returns the effective value of a workspace key, falling back to
+// the built-in default when the key is not set.
+func GetSetting(ws *Workspace, key string) (Setting, error) {
+	spec, err := findSettingSpec(key)
+	if err != nil {
+		return Setting{}, err
+	}
+
+	field := workspaceField(ws, key)
+	if field.IsZero() && spec.def != nil {
+		if def := spec.def(ws); def != "" {
+			return Setting{Key: key, Value: def, Default: true}, nil
+		}
+	}
+
+	if field.Kind() == reflect.Pointer && !field.IsNil() {
+		field = field.Elem()
+	}
+
+	var value string
+	switch field.Kind() {
+	case reflect.String:
+		value = field.String()
+	case reflect.Bool:
+		value = strconv.FormatBool(field.Bool())
+	case reflect.Int64:
+		value = time.Duration(field.Int()).String()
+	case reflect.Int:
+		value = strconv.FormatInt(field.Int(), 10)
+	}
+	return Setting{Key: key, Value: value}, nil
+}
+
+// Settings returns the effective values of the workspace keys that are set
+// or have a default.
+func Settings(ws *Workspace) []Setting {
+	var settings []Setting
+	for _, spec := range settingSpecs {
+		setting, _ := GetSetting(ws, spec.key)
+		if setting.Default || !workspaceField(ws, spec.key).IsZero() {
+			settings = append(settings, setting)
+		}
+	}
+	return settings
+}
+
+// ValidateSetting checks that value is valid for key given the rest of the
+// workspace, and returns it in canonical form.
+func ValidateSetting(ws *Workspace, key, value string) (string, error) {
+	spec, err := findSettingSpec(key)
+	if err != nil {
+		return "", err
+	}
+
+	if len(spec.values) > 0 {
+		valid := false
+		for _, allowed := range spec.values {
+			if value == allowed {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return "", fmt.Errorf("invalid %s %q (valid: %s)", key, value, strings.Join(spec.values, ", "))
+		}
+	}
+
+	switch settingKind(ws, key) {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s %q (expected true or false)", key, value)
+		}
+		value = strconv.FormatBool(b)
+	case reflect.Int64:
+		if _, err := time.ParseDuration(value); err != nil {
+			return "", fmt.Errorf("invalid %s %q (expected a duration such as 30s)", key, value)
+		}
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("invalid %s %q (expected a non-negative number)", key, value)
+		}
+		value = strconv.Itoa(n)
+	}
+
+	switch {
+	case key == "lock_mode" && value == "git" && !ws.GitSync:
+		return "", fmt.Errorf("lock_mode git requires git_sync: true; set git_sync first")
+	case key == "git_sync" && value == "false" && ws.LockMode == "git":
+		return "", fmt.Errorf("git_sync cannot be disabled while lock_mode is git; set lock_mode to file first")
+	}
+
+	return value, nil
+}
+
+// SetWorkspaceValue writes key: value into the named workspace of the config
+// file at path. The file is edited as a YAML node tree so comments and key
+// order survive; value must already be validated with ValidateSetting.
+func SetWorkspaceValue(path, workspace, key, value string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("failed to parse config file: expected a mapping at the top level")
+	}
+
+	workspaces := mappingValue(doc.Content[0], "workspaces")
+	if workspaces == nil || workspaces.Kind != yaml.MappingNode {
+		return fmt.Errorf("no workspaces configured in %s", path)
+	}
+	wsNode := mappingValue(workspaces, workspace)
+	if wsNode == nil || wsNode.Kind != yaml.MappingNode {
+		return fmt.Errorf("workspace %q not found in %s", workspace, path)
+	}
+
+	tag := "!!str"
+	switch settingKind(&Workspace{}, key) {
+	case reflect.Bool:
+		tag = "!!bool"
+	case reflect.Int:
+		tag = "!!int"
+	}
+
+	if node := mappingValue(wsNode, key); node != nil {
+		// Keep the node itself so trailing comments stay attached
+		node.Kind = yaml.ScalarNode
+		node.Tag = tag
+		node.Value = value
+		node.Style = 0
+		node.Content = nil
+	} else {
+		wsNode.Content = append(wsNode.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value},
+		)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// mappingValue returns the value node for key in a YAML mapping node.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGetSetting_Defaults(t *testing.T) {
+	ws := &Workspace{Backend: "local"}
+
+	setting, err := GetSetting(ws, "lock_mode")
+	if err != nil {
+		t.Fatalf("GetSetting failed: %v", err)
+	}
+	if setting.Value != "file" || !setting.Default {
+		t.Errorf("expected default lock_mode 'file', got %+v", setting)
+	}
+
+	ws.LockMode = "git"
+	setting, _ = GetSetting(ws, "lock_mode")
+	if setting.Value != "git" || setting.Default {
+		t.Errorf("expected configured lock_mode 'git', got %+v", setting)
+	}
+
+	setting, _ = GetSetting(ws, "index")
+	if setting.Value != "true" || !setting.Default {
+		t.Errorf("expected default index 'true', got %+v", setting)
+	}
+	disabled := false
+	ws.Index = &disabled
+	setting, _ = GetSetting(ws, "index")
+	if setting.Value != "false" || setting.Default {
+		t.Errorf("expected configured index 'false', got %+v", setting)
+	}
+
+	setting, _ = GetSetting(ws, "undo_limit")
+	if setting.Value != "20" || !setting.Default {
+		t.Errorf("expected default undo_limit '20', got %+v", setting)
+	}
+	none := 0
+	ws.UndoLimit = &none
+	setting, _ = GetSetting(ws, "undo_limit")
+	if setting.Value != "0" || setting.Default {
+		t.Errorf("expected configured undo_limit '0', got %+v", setting)
+	}
+
+	if _, err := GetSetting(ws, "nonexistent"); err == nil {
+		t.Error("expected error for unknown key")
+	}
+}
+
+func TestValidateSetting(t *testing.T) {
+	tests := []struct {
+		name    string
+		ws      Workspace
+		key     string
+		value   string
+		want    string
+		wantErr string
+	}{
+		{name: "valid lock mode", ws: Workspace{GitSync: true}, key: "lock_mode", value: "git", want: "git"},
+		{name: "unknown lock mode", key: "lock_mode", value: "flock", wantErr: "valid: file, git"},
+		{name: "git lock mode without git sync", key: "lock_mode", value: "git", wantErr: "requires git_sync"},
+		{name: "disable git sync in git lock mode", ws: Workspace{GitSync: true, LockMode: "git"}, key: "git_sync", value: "false", wantErr: "lock_mode is git"},
+		{name: "bool is canonicalized", key: "git_sync", value: "TRUE", want: "true"},
+		{name: "invalid bool", key: "default", value: "maybe", wantErr: "expected true or false"},
+		{name: "optional bool", key: "index", value: "False", want: "false"},
+		{name: "read only", key: "read_only", value: "true", want: "true"},
+		{name: "project number", key: "project", value: "7", want: "7"},
+		{name: "negative project", key: "project", value: "-1", wantErr: "non-negative"},
+		{name: "timeout duration", key: "timeout", value: "45s", want: "45s"},
+		{name: "invalid timeout", key: "timeout", value: "soon", wantErr: "duration"},
+		{name: "unknown key", key: "status_map", value: "x", wantErr: "unknown workspace key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateSetting(&tt.ws, tt.key, tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSetWorkspaceValue_PreservesComments(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfgPath := filepath.Join(tmpDir, "config.yaml")
+
+	cfgContent := `# Shared team config
+version: 1
+workspaces:
+  main:
+    backend: local
+    # Locking strategy
+    lock_mode: file # switch once git_sync is on
+  other:
+    backend: github
+    repo: user/repo
+`
+	if err := os.WriteFile(cfgPath, []byte(cfgContent), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	if err := SetWorkspaceValue(cfgPath, "main", "git_sync", "true"); err != nil {
+		t.Fatalf("SetWorkspaceValue failed: %v", err)
+	}
+	if err := SetWorkspaceValue(cfgPath, "main", "lock_mode", "git"); err != nil {
+		t.Fatalf("SetWorkspaceValue failed: %v", err)
+	}
+
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatalf("failed to read config file: %v", err)
+	}
+	content := string(data)
+	for _, want := range []string{
+		"# Shared team config",
+		"# Locking strategy",
+		"lock_mode: git # switch once git_sync is on",
+		"git_sync: true",
+		"repo: user/repo",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected config to contain %q, got:\n%s", want, content)
+		}
+	}
+
+	if err := Init(cfgPath); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	main := Get().Workspaces["main"]
+	if main.LockMode != "git" || !main.GitSync {
+		t.Errorf("expected lock_mode git with git_sync, got %q/%v", main.LockMode, main.GitSync)
+	}
+	if Get().Workspaces["other"].GitSync {
+		t.Error("expected other workspace to be unchanged")
+	}
+}
+
+func TestSetWorkspaceValue_UnknownWorkspace(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfgPath := filepath.Join(tmpDir, "config.yaml")
+
+	if err := os.WriteFile(cfgPath, []byte("workspaces:\n  main:\n    backend: local\n"), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	if err := SetWorkspaceValue(cfgPath, "missing", "lock_mode", "file"); err == nil {
+		t.Error("expected error for unknown workspace")
+	}
+}
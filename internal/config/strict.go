@@ -0,0 +1,206 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlLineRe matches the line number yaml.v3 puts in syntax errors.
+var yamlLineRe = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// checkConfigFile validates a config file's YAML against the Config
+// structure before Viper decodes it. Viper is lenient: it drops unknown keys
+// and converts values between types, so a misindented or misspelled key
+// silently loses its setting. Unknown keys and values of the wrong type are
+// reported with the file, line and column. Quoted booleans and integers,
+// such as git_sync: "true", are accepted with a warning.
+func checkConfigFile(path string, data []byte) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return yamlSyntaxError(path, err)
+	}
+	return checkConfigDocument(path, &doc)
+}
+
+// checkConfigDocument is checkConfigFile for a document that has already
+// been parsed. Positions are taken from the nodes.
+func checkConfigDocument(path string, doc *yaml.Node) error {
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	checker := configChecker{path: path}
+	return checker.check(doc.Content[0], reflect.TypeOf(Config{}), "")
+}
+
+// isYAMLFile reports whether a config file is YAML, rather than one of the
+// other formats Viper reads, judging by its extension.
+func isYAMLFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml" || ext == ""
+}
+
+// yamlSyntaxError rewrites a yaml.v3 syntax error as "path:line: message".
+// yaml.v3 doesn't report the column of syntax errors.
+func yamlSyntaxError(path string, err error) error {
+	if m := yamlLineRe.FindStringSubmatch(err.Error()); m != nil {
+		return fmt.Errorf("%s:%s: %s", path, m[1], m[2])
+	}
+	return fmt.Errorf("%s: %s", path, strings.TrimPrefix(err.Error(), "yaml: "))
+}
+
+// configChecker walks a config file's YAML nodes alongside the Go types
+// they are decoded into.
+type configChecker struct {
+	path string
+}
+
+// errorf returns an error located at node.
+func (c configChecker) errorf(node *yaml.Node, format string, args ...any) error {
+	return fmt.Errorf("%s:%d:%d: %s", c.path, node.Line, node.Column, fmt.Sprintf(format, args...))
+}
+
+// check validates that node can be decoded into a value of type t. key is
+// the dotted path of the node, such as "workspaces.main.git_sync".
+func (c configChecker) check(node *yaml.Node, t reflect.Type, key string) error {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	// An empty value leaves the setting at its default
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return nil
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == durationType {
+		if node.Kind != yaml.ScalarNode {
+			return c.errorf(node, "%s must be a duration such as 30s or 5m", key)
+		}
+		if node.Tag == "!!int" {
+			return nil
+		}
+		if _, err := time.ParseDuration(node.Value); err != nil {
+			return c.errorf(node, "%s must be a duration such as 30s or 5m, got %q", key, node.Value)
+		}
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return c.checkStruct(node, t, key)
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return c.errorf(node, "%s must be a mapping", describeKey(key))
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if err := c.check(node.Content[i+1], t.Elem(), joinKey(key, node.Content[i].Value)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		// A single value is decoded as a one-element list
+		if node.Kind == yaml.ScalarNode {
+			return c.check(node, t.Elem(), key)
+		}
+		if node.Kind != yaml.SequenceNode {
+			return c.errorf(node, "%s must be a list", key)
+		}
+		for _, item := range node.Content {
+			if err := c.check(item, t.Elem(), key); err != nil {
+				return err
+			}
+		}
+	case reflect.Bool:
+		return c.checkScalar(node, key, "!!bool", "a boolean (true or false)", func(s string) error {
+			_, err := strconv.ParseBool(s)
+			return err
+		})
+	case reflect.Int, reflect.Int64:
+		return c.checkScalar(node, key, "!!int", "an integer", func(s string) error {
+			_, err := strconv.Atoi(s)
+			return err
+		})
+	case reflect.String:
+		if node.Kind != yaml.ScalarNode {
+			return c.errorf(node, "%s must be a string", key)
+		}
+	}
+	return nil
+}
+
+// checkStruct validates a mapping decoded into a struct, rejecting keys
+// that have no field.
+func (c configChecker) checkStruct(node *yaml.Node, t reflect.Type, key string) error {
+	if node.Kind != yaml.MappingNode {
+		return c.errorf(node, "%s must be a mapping", describeKey(key))
+	}
+
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("mapstructure"), ",")
+		if name != "" && name != "-" {
+			fields[name] = t.Field(i).Type
+		}
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		// Viper matches keys case-insensitively
+		fieldType, ok := fields[strings.ToLower(keyNode.Value)]
+		if !ok {
+			if key == "" {
+				return c.errorf(keyNode, "unknown key %q", keyNode.Value)
+			}
+			return c.errorf(keyNode, "unknown key %q in %s", keyNode.Value, key)
+		}
+		if err := c.check(node.Content[i+1], fieldType, joinKey(key, keyNode.Value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkScalar validates a boolean or integer value. A string that parses
+// as one, such as "true", is accepted with a warning since Viper converts
+// it; anything else is an error.
+func (c configChecker) checkScalar(node *yaml.Node, key, tag, kind string, parse func(string) error) error {
+	if node.Kind != yaml.ScalarNode {
+		return c.errorf(node, "%s must be %s", key, kind)
+	}
+	if node.Tag == tag {
+		return nil
+	}
+	if parse(node.Value) != nil {
+		return c.errorf(node, "%s must be %s, got %q", key, kind, node.Value)
+	}
+	slog.Warn("config value should be "+kind+", not a string; converting it", "key", key, "value", node.Value,
+		"location", fmt.Sprintf("%s:%d:%d", c.path, node.Line, node.Column))
+	return nil
+}
+
+// joinKey appends name to a dotted key path.
+func joinKey(key, name string) string {
+	if key == "" {
+		return name
+	}
+	return key + "." + name
+}
+
+// describeKey names a key in an error, or the whole file for the root.
+func describeKey(key string) string {
+	if key == "" {
+		return "the config file"
+	}
+	return key
+}
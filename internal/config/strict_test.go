@@ -0,0 +1,166 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckConfigFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "tab indentation",
+			content: "version: 1\nworkspaces:\n\tmain:\n    backend: local\n",
+			want:    "config.yaml:3: found character that cannot start any token",
+		},
+		{
+			name:    "unclosed flow sequence",
+			content: "this is not valid yaml: [\n",
+			want:    "config.yaml:1: did not find expected node content",
+		},
+		{
+			name:    "misindented workspace key",
+			content: "workspaces:\n  main:\n    backend: local\n  lock_mode: git\n",
+			want:    "config.yaml:4:14: workspaces.lock_mode must be a mapping",
+		},
+		{
+			name:    "key under the wrong section",
+			content: "defaults:\n  format: json\n  git_sync: true\n",
+			want:    `config.yaml:3:3: unknown key "git_sync" in defaults`,
+		},
+		{
+			name:    "misspelled top-level key",
+			content: "version: 1\nworkspace:\n  main:\n    backend: local\n",
+			want:    `config.yaml:2:1: unknown key "workspace"`,
+		},
+		{
+			name:    "misspelled workspace key",
+			content: "workspaces:\n  main:\n    backend: local\n    lock_mod: git\n",
+			want:    `config.yaml:4:5: unknown key "lock_mod" in workspaces.main`,
+		},
+		{
+			name:    "workspaces not a mapping",
+			content: "version: 1\nworkspaces: \"not a map\"\n",
+			want:    "config.yaml:2:13: workspaces must be a mapping",
+		},
+		{
+			name:    "boolean that isn't one",
+			content: "workspaces:\n  main:\n    backend: local\n    git_sync: sometimes\n",
+			want:    `config.yaml:4:15: workspaces.main.git_sync must be a boolean (true or false), got "sometimes"`,
+		},
+		{
+			name:    "integer that isn't one",
+			content: "workspaces:\n  main:\n    backend: github\n    project: first\n",
+			want:    `config.yaml:4:14: workspaces.main.project must be an integer, got "first"`,
+		},
+		{
+			name:    "invalid duration",
+			content: "workspaces:\n  main:\n    backend: github\n    timeout: soon\n",
+			want:    `config.yaml:4:14: workspaces.main.timeout must be a duration such as 30s or 5m, got "soon"`,
+		},
+		{
+			name:    "list where a string belongs",
+			content: "workspaces:\n  main:\n    backend: [local]\n",
+			want:    "config.yaml:3:14: workspaces.main.backend must be a string",
+		},
+		{
+			name:    "unknown status mapping key",
+			content: "workspaces:\n  main:\n    backend: github\n    status_map:\n      todo:\n        labels: [ready]\n        color: blue\n",
+			want:    `config.yaml:7:9: unknown key "color" in workspaces.main.status_map.todo`,
+		},
+		{
+			name:    "not a mapping at all",
+			content: "- backend: local\n",
+			want:    "config.yaml:1:1: the config file must be a mapping",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkConfigFile("config.yaml", []byte(tt.content))
+			if err == nil {
+				t.Fatalf("checkConfigFile() = nil, want %q", tt.want)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("checkConfigFile() = %q, want %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckConfigFile_Valid(t *testing.T) {
+	content := `version: 1
+defaults:
+  format: json
+  workspace: main
+workspaces:
+  main:
+    backend: github
+    repo: user/repo
+    project: 3
+    default: true
+    git_sync: "true"
+    timeout: 30s
+    assign_on_claim: false
+    index:
+    status_map:
+      todo:
+        labels: ready
+    default_filters:
+      labels: [bug]
+    hooks:
+      on_move: [notify]
+      on_transition:
+        "*->done": [celebrate]
+      timeout: 5s
+command_defaults:
+  list:
+    labels: [bug]
+    limit: 10
+`
+	if err := checkConfigFile("config.yaml", []byte(content)); err != nil {
+		t.Errorf("checkConfigFile() error = %v", err)
+	}
+	if err := checkConfigFile("config.yaml", nil); err != nil {
+		t.Errorf("checkConfigFile(empty) error = %v", err)
+	}
+}
+
+func TestInit_StrictConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfgPath := filepath.Join(tmpDir, "config.yaml")
+
+	cfgContent := "workspaces:\n  main:\n    backend: local\n  lock_mode: git\n"
+	if err := os.WriteFile(cfgPath, []byte(cfgContent), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	err := Init(cfgPath)
+	want := "invalid config file " + cfgPath + ":4:14: workspaces.lock_mode must be a mapping"
+	if err == nil || err.Error() != want {
+		t.Fatalf("Init() error = %v, want %q", err, want)
+	}
+
+	// Syntax errors are located too
+	if err := os.WriteFile(cfgPath, []byte("workspaces:\n\tmain:\n"), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	err = Init(cfgPath)
+	want = "invalid config file " + cfgPath + ":2: found character that cannot start any token"
+	if err == nil || err.Error() != want {
+		t.Fatalf("Init() error = %v, want %q", err, want)
+	}
+
+	// A quoted boolean is converted
+	if err := os.WriteFile(cfgPath, []byte("workspaces:\n  main:\n    backend: local\n    git_sync: \"true\"\n"), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	if err := Init(cfgPath); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if !Get().Workspaces["main"].GitSync {
+		t.Error("git_sync = false, want the quoted \"true\" converted")
+	}
+}
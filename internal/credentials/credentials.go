@@ -87,40 +87,52 @@ func Get() *Credentials {
 	return creds
 }
 
-// GetGitHubToken returns the GitHub token using the following priority:
+// GetGitHubToken returns the GitHub token shared by all workspaces. See
+// GetGitHubTokenFor.
+func GetGitHubToken() (string, error) {
+	return GetGitHubTokenFor("")
+}
+
+// GetGitHubTokenFor returns the GitHub token for a workspace using the
+// following priority:
 // 1. GITHUB_TOKEN environment variable
-// 2. credentials.yaml github.token
+// 2. The workspace's token in the keychain or encrypted store
+// 3. The token shared by all workspaces in the keychain or encrypted store
+// 4. credentials.yaml github.token
 // Returns an error if no token is found.
-func GetGitHubToken() (string, error) {
-	// Check environment variable first
-	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
-		return token, nil
+func GetGitHubTokenFor(workspace string) (string, error) {
+	token, _, err := Lookup(BackendGitHub, workspace)
+	if err != nil {
+		return "", err
 	}
-
-	// Check credentials file
-	if creds != nil && creds.GitHub != nil && creds.GitHub.Token != "" {
-		return creds.GitHub.Token, nil
+	if token == "" {
+		return "", errors.New("GitHub token not found: set GITHUB_TOKEN environment variable, run 'backlog auth set github', or add token to ~/.config/backlog/credentials.yaml")
 	}
+	return token, nil
+}
 
-	return "", errors.New("GitHub token not found: set GITHUB_TOKEN environment variable or add token to ~/.config/backlog/credentials.yaml")
+// GetLinearAPIKey returns the Linear API key shared by all workspaces. See
+// GetLinearAPIKeyFor.
+func GetLinearAPIKey() (string, error) {
+	return GetLinearAPIKeyFor("")
 }
 
-// GetLinearAPIKey returns the Linear API key using the following priority:
+// GetLinearAPIKeyFor returns the Linear API key for a workspace using the
+// following priority:
 // 1. LINEAR_API_KEY environment variable
-// 2. credentials.yaml linear.api_key
+// 2. The workspace's key in the keychain or encrypted store
+// 3. The key shared by all workspaces in the keychain or encrypted store
+// 4. credentials.yaml linear.api_key
 // Returns an error if no API key is found.
-func GetLinearAPIKey() (string, error) {
-	// Check environment variable first
-	if key := os.Getenv("LINEAR_API_KEY"); key != "" {
-		return key, nil
+func GetLinearAPIKeyFor(workspace string) (string, error) {
+	key, _, err := Lookup(BackendLinear, workspace)
+	if err != nil {
+		return "", err
 	}
-
-	// Check credentials file
-	if creds != nil && creds.Linear != nil && creds.Linear.APIKey != "" {
-		return creds.Linear.APIKey, nil
+	if key == "" {
+		return "", errors.New("Linear API key not found: set LINEAR_API_KEY environment variable, run 'backlog auth set linear', or add api_key to ~/.config/backlog/credentials.yaml")
 	}
-
-	return "", errors.New("Linear API key not found: set LINEAR_API_KEY environment variable or add api_key to ~/.config/backlog/credentials.yaml")
+	return key, nil
 }
 
 // SaveGitHubToken saves a GitHub token to the credentials file.
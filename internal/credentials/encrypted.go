@@ -0,0 +1,188 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The encrypted store keeps secrets in credentials.enc, encrypted with
+// AES-256-GCM under a random key in credentials.key. Both live in
+// ~/.config/backlog with 0600 permissions. The key never leaves the
+// machine, so a copy of credentials.enc that ends up in a repository or a
+// backup is useless on its own.
+//
+// This is the standard library's AEAD rather than an age file: the file
+// format is private to backlog, so age's recipients and armor buy nothing,
+// and it avoids a dependency. The key file plays the part of age's
+// identity file. Either way the key sits next to the ciphertext
+// unencrypted, so the store protects against leaked copies of
+// credentials.enc, not against anyone who can read the user's config
+// directory; the keychain store is the one for that.
+const (
+	encryptedFileName = "credentials.enc"
+	keyFileName       = "credentials.key"
+)
+
+// encryptedSet stores a secret for an account in the encrypted store,
+// creating the key on first use.
+func encryptedSet(account, secret string) error {
+	key, err := readKey(true)
+	if err != nil {
+		return err
+	}
+	secrets, err := readEncrypted(key)
+	if err != nil {
+		return err
+	}
+	secrets[account] = secret
+
+	plaintext, err := yaml.Marshal(secrets)
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials: %w", err)
+	}
+	ciphertext, err := seal(key, plaintext)
+	if err != nil {
+		return err
+	}
+	path, err := configPath(encryptedFileName)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, ciphertext, 0600); err != nil {
+		return fmt.Errorf("failed to write encrypted credentials: %w", err)
+	}
+	return nil
+}
+
+// encryptedGet returns the secret for an account from the encrypted store,
+// or an empty string if there is none.
+func encryptedGet(account string) (string, error) {
+	path, err := configPath(encryptedFileName)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	key, err := readKey(false)
+	if err != nil {
+		return "", err
+	}
+	secrets, err := readEncrypted(key)
+	if err != nil {
+		return "", err
+	}
+	return secrets[account], nil
+}
+
+// readEncrypted decrypts the encrypted store. A missing store is empty.
+func readEncrypted(key []byte) (map[string]string, error) {
+	path, err := configPath(encryptedFileName)
+	if err != nil {
+		return nil, err
+	}
+	secrets := make(map[string]string)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return secrets, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encrypted credentials: %w", err)
+	}
+
+	plaintext, err := open(key, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s (was %s replaced?): %w", path, keyFileName, err)
+	}
+	if err := yaml.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse encrypted credentials: %w", err)
+	}
+	return secrets, nil
+}
+
+// readKey returns the encryption key, generating and saving one if create
+// is set and there is none yet.
+func readKey(create bool) ([]byte, error) {
+	path, err := configPath(keyFileName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if !create {
+			return nil, fmt.Errorf("%s has no key: %s is missing", encryptedFileName, path)
+		}
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate key: %w", err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create credentials directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(hex.EncodeToString(key)+"\n"), 0600); err != nil {
+			return nil, fmt.Errorf("failed to write key: %w", err)
+		}
+		return key, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("invalid key in %s: want 64 hex characters", path)
+	}
+	return key, nil
+}
+
+// seal encrypts plaintext, prefixing the random nonce.
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts data written by seal.
+func open(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("file is truncated")
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// configPath returns the path of a file in the configuration directory.
+func configPath(name string) (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
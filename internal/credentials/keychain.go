@@ -0,0 +1,207 @@
+package credentials
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// keychainService is the service name secrets are stored under.
+const keychainService = "backlog"
+
+// keychain stores secrets in the OS keychain.
+type keychain interface {
+	// available reports whether the keychain can be used on this system.
+	available() bool
+
+	set(account, secret string) error
+	get(account string) (string, error)
+}
+
+// osKeychain is this system's keychain, or nil if it has none that is
+// supported. Tests replace it.
+var osKeychain = newOSKeychain()
+
+func newOSKeychain() keychain {
+	switch runtime.GOOS {
+	case "darwin":
+		return macKeychain{}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return secretService{}
+	default:
+		return nil
+	}
+}
+
+// macKeychain uses the macOS login keychain through the security tool.
+type macKeychain struct{}
+
+func (macKeychain) available() bool {
+	_, err := exec.LookPath("security")
+	return err == nil
+}
+
+func (macKeychain) set(account, secret string) error {
+	if strings.ContainsAny(secret, "\r\n") {
+		return errors.New("secret must be a single line")
+	}
+	// security only takes the password as an argument, so the command is
+	// fed to its interactive mode on stdin to keep the secret out of ps.
+	// -U updates an existing item instead of failing.
+	command := fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n",
+		securityQuote(keychainService), securityQuote(account), securityQuote(secret))
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(command)
+	output, err := cmd.CombinedOutput()
+	// Interactive mode exits 0 even when the command fails, so anything
+	// printed besides its prompt is an error
+	if err == nil && len(bytes.TrimSpace(bytes.ReplaceAll(output, []byte("security>"), nil))) > 0 {
+		err = errors.New("unexpected output")
+	}
+	if err != nil {
+		return fmt.Errorf("security add-generic-password failed: %w\n%s", err, output)
+	}
+	return nil
+}
+
+// securityQuote quotes an argument for a command line read by security's
+// interactive mode.
+func securityQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func (macKeychain) get(account string) (string, error) {
+	output, err := exec.Command("security", "find-generic-password", "-s", keychainService, "-a", account, "-w").Output()
+	if err != nil {
+		return "", fmt.Errorf("security find-generic-password failed: %w", err)
+	}
+	return strings.TrimRight(string(output), "\n"), nil
+}
+
+// secretService uses the Secret Service (GNOME Keyring, KWallet) through
+// libsecret's secret-tool.
+type secretService struct{}
+
+func (secretService) available() bool {
+	_, err := exec.LookPath("secret-tool")
+	return err == nil
+}
+
+func (secretService) set(account, secret string) error {
+	// The secret is read from stdin so it doesn't show up in ps
+	cmd := exec.Command("secret-tool", "store", "--label", "backlog "+account, "service", keychainService, "account", account)
+	cmd.Stdin = strings.NewReader(secret)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("secret-tool store failed: %w\n%s", err, output)
+	}
+	return nil
+}
+
+func (secretService) get(account string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", "lookup", "service", keychainService, "account", account)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("secret-tool lookup failed: %w\n%s", err, stderr.String())
+	}
+	return strings.TrimRight(string(output), "\n"), nil
+}
+
+// keychainSet stores a secret in the OS keychain and records its account in
+// the keychain index.
+func keychainSet(account, secret string) error {
+	if osKeychain == nil || !osKeychain.available() {
+		return fmt.Errorf("no supported keychain on this system (macOS needs security, Linux needs secret-tool); use the %s store", StoreEncrypted)
+	}
+	if err := osKeychain.set(account, secret); err != nil {
+		return err
+	}
+
+	accounts, err := keychainAccounts()
+	if err != nil {
+		return err
+	}
+	for _, a := range accounts {
+		if a == account {
+			return nil
+		}
+	}
+	accounts = append(accounts, account)
+	sort.Strings(accounts)
+	return writeKeychainIndex(accounts)
+}
+
+// keychainGet returns the secret for an account from the OS keychain, or
+// an empty string if none was stored. Only accounts in the keychain index
+// are looked up, so commands don't query (and on macOS, prompt for) the
+// keychain when nothing was stored there.
+func keychainGet(account string) (string, error) {
+	accounts, err := keychainAccounts()
+	if err != nil {
+		return "", err
+	}
+	for _, a := range accounts {
+		if a == account {
+			if osKeychain == nil {
+				return "", fmt.Errorf("credential %s is in the keychain, which is not supported on this system", account)
+			}
+			return osKeychain.get(account)
+		}
+	}
+	return "", nil
+}
+
+// keychainIndexPath returns the path of the keychain index, which lists the
+// accounts stored in the keychain. It holds no secrets.
+func keychainIndexPath() (string, error) {
+	return configPath("keychain.yaml")
+}
+
+// keychainAccounts returns the accounts in the keychain index.
+func keychainAccounts() ([]string, error) {
+	path, err := keychainIndexPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keychain index: %w", err)
+	}
+	var index struct {
+		Accounts []string `yaml:"accounts"`
+	}
+	if err := yaml.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse keychain index %s: %w", path, err)
+	}
+	return index.Accounts, nil
+}
+
+func writeKeychainIndex(accounts []string) error {
+	path, err := keychainIndexPath()
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(map[string][]string{"accounts": accounts})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create credentials directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write keychain index: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package credentials
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Stores a credential can come from, as reported by Lookup. Secrets can be
+// written with Set to the keychain and encrypted stores.
+const (
+	StoreEnv       = "env"       // GITHUB_TOKEN or LINEAR_API_KEY
+	StoreKeychain  = "keychain"  // The OS keychain
+	StoreEncrypted = "encrypted" // credentials.enc, encrypted with credentials.key
+	StoreFile      = "file"      // Plaintext credentials.yaml
+)
+
+// Backends that credentials can be stored for.
+const (
+	BackendGitHub = "github"
+	BackendLinear = "linear"
+)
+
+// envVars maps each backend to the environment variable holding its secret.
+var envVars = map[string]string{
+	BackendGitHub: "GITHUB_TOKEN",
+	BackendLinear: "LINEAR_API_KEY",
+}
+
+// WorkspaceCredential reports where a workspace's credential comes from.
+// It never holds the secret.
+type WorkspaceCredential struct {
+	// Workspace is the workspace's name, or empty for the credential
+	// shared by all workspaces of the backend.
+	Workspace string `json:"workspace"`
+
+	// Backend is the workspace's backend, github or linear.
+	Backend string `json:"backend"`
+
+	// Store is where the credential is found, or empty if there is none.
+	Store string `json:"store"`
+}
+
+// account names a stored secret: the backend's name for a secret shared by
+// all its workspaces, or "backend/workspace" for one workspace's own.
+func account(backendName, workspace string) string {
+	if workspace == "" {
+		return backendName
+	}
+	return backendName + "/" + workspace
+}
+
+// checkBackend returns an error for a backend that takes no credentials.
+func checkBackend(backendName string) error {
+	if _, ok := envVars[backendName]; !ok {
+		return fmt.Errorf("unknown backend %q (valid: %s, %s)", backendName, BackendGitHub, BackendLinear)
+	}
+	return nil
+}
+
+// DefaultStore returns the store Set uses when none is chosen: the keychain
+// where this system has one, and the encrypted file otherwise.
+func DefaultStore() string {
+	if osKeychain != nil && osKeychain.available() {
+		return StoreKeychain
+	}
+	return StoreEncrypted
+}
+
+// Set stores the secret for a backend in the keychain or encrypted store.
+// With a workspace, the secret is used for that workspace only; without
+// one, it is used for every workspace of the backend that has none of its
+// own.
+func Set(store, backendName, workspace, secret string) error {
+	if err := checkBackend(backendName); err != nil {
+		return err
+	}
+	if strings.TrimSpace(secret) == "" {
+		return fmt.Errorf("empty %s credential", backendName)
+	}
+
+	switch store {
+	case StoreKeychain:
+		return keychainSet(account(backendName, workspace), secret)
+	case StoreEncrypted:
+		return encryptedSet(account(backendName, workspace), secret)
+	default:
+		return fmt.Errorf("unknown credential store %q (valid: %s, %s)", store, StoreKeychain, StoreEncrypted)
+	}
+}
+
+// Lookup returns the secret for a workspace of a backend and the store it
+// came from, in priority order: the backend's environment variable, the
+// workspace's own secret in the keychain or encrypted store, the secret
+// shared by the backend's workspaces there, and finally credentials.yaml.
+// A missing secret returns an empty string and no error; a store that can't
+// be read returns an error.
+func Lookup(backendName, workspace string) (secret, store string, err error) {
+	if err := checkBackend(backendName); err != nil {
+		return "", "", err
+	}
+	if value := os.Getenv(envVars[backendName]); value != "" {
+		return value, StoreEnv, nil
+	}
+
+	accounts := []string{account(backendName, "")}
+	if workspace != "" {
+		accounts = []string{account(backendName, workspace), account(backendName, "")}
+	}
+	for _, acct := range accounts {
+		if value, err := keychainGet(acct); err != nil || value != "" {
+			return value, StoreKeychain, err
+		}
+		if value, err := encryptedGet(acct); err != nil || value != "" {
+			return value, StoreEncrypted, err
+		}
+	}
+
+	if value := fileSecret(backendName); value != "" {
+		return value, StoreFile, nil
+	}
+	return "", "", nil
+}
+
+// fileSecret returns the backend's secret from credentials.yaml.
+func fileSecret(backendName string) string {
+	if creds == nil {
+		return ""
+	}
+	switch backendName {
+	case BackendGitHub:
+		if creds.GitHub != nil {
+			return creds.GitHub.Token
+		}
+	case BackendLinear:
+		if creds.Linear != nil {
+			return creds.Linear.APIKey
+		}
+	}
+	return ""
+}
@@ -0,0 +1,181 @@
+package credentials
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeKeychain keeps secrets in memory.
+type fakeKeychain map[string]string
+
+func (k fakeKeychain) available() bool { return true }
+
+func (k fakeKeychain) set(account, secret string) error {
+	k[account] = secret
+	return nil
+}
+
+func (k fakeKeychain) get(account string) (string, error) {
+	return k[account], nil
+}
+
+// setupStores points the stores at a temporary HOME with an in-memory
+// keychain and no plaintext credentials.
+func setupStores(t *testing.T) (home string, kc fakeKeychain) {
+	t.Helper()
+	home = t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("LINEAR_API_KEY", "")
+
+	kc = fakeKeychain{}
+	oldKeychain, oldCreds := osKeychain, creds
+	osKeychain, creds = kc, &Credentials{}
+	t.Cleanup(func() { osKeychain, creds = oldKeychain, oldCreds })
+	return home, kc
+}
+
+func TestLookupPriority(t *testing.T) {
+	_, _ = setupStores(t)
+	creds.GitHub = &GitHubCredentials{Token: "from-file"}
+
+	check := func(workspace, wantSecret, wantStore string) {
+		t.Helper()
+		secret, store, err := Lookup(BackendGitHub, workspace)
+		if err != nil {
+			t.Fatalf("Lookup(%q) error = %v", workspace, err)
+		}
+		if secret != wantSecret || store != wantStore {
+			t.Errorf("Lookup(%q) = %q from %q, want %q from %q", workspace, secret, store, wantSecret, wantStore)
+		}
+	}
+
+	check("work", "from-file", StoreFile)
+
+	if err := Set(StoreEncrypted, BackendGitHub, "", "shared"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	check("work", "shared", StoreEncrypted)
+
+	if err := Set(StoreKeychain, BackendGitHub, "work", "work-token"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	check("work", "work-token", StoreKeychain)
+	check("personal", "shared", StoreEncrypted)
+
+	t.Setenv("GITHUB_TOKEN", "from-env")
+	check("work", "from-env", StoreEnv)
+
+	// Linear credentials are separate
+	if secret, store, err := Lookup(BackendLinear, "work"); err != nil || secret != "" || store != "" {
+		t.Errorf("Lookup(linear) = %q, %q, %v; want nothing", secret, store, err)
+	}
+}
+
+func TestEncryptedStore(t *testing.T) {
+	home, _ := setupStores(t)
+
+	if err := Set(StoreEncrypted, BackendLinear, "team", "lin_api_secret"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := Set(StoreEncrypted, BackendGitHub, "", "ghp_secret"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	dir := filepath.Join(home, ".config", "backlog")
+	data, err := os.ReadFile(filepath.Join(dir, encryptedFileName))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("%s holds a secret in plaintext", encryptedFileName)
+	}
+	for _, name := range []string{encryptedFileName, keyFileName} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("Stat() error = %v", err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("%s mode = %v, want 0600", name, info.Mode().Perm())
+		}
+	}
+
+	if key, err := GetLinearAPIKeyFor("team"); err != nil || key != "lin_api_secret" {
+		t.Errorf("GetLinearAPIKeyFor() = %q, %v; want lin_api_secret", key, err)
+	}
+	if token, err := GetGitHubToken(); err != nil || token != "ghp_secret" {
+		t.Errorf("GetGitHubToken() = %q, %v; want ghp_secret", token, err)
+	}
+
+	// A different key can't decrypt the store
+	if err := os.WriteFile(filepath.Join(dir, keyFileName), []byte(strings.Repeat("ab", 32)), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if _, err := GetGitHubToken(); err == nil || !strings.Contains(err.Error(), "failed to decrypt") {
+		t.Errorf("GetGitHubToken() error = %v, want a decryption error", err)
+	}
+}
+
+func TestKeychainIndex(t *testing.T) {
+	_, kc := setupStores(t)
+
+	// Secrets the index doesn't list are not looked up
+	kc["github"] = "unlisted"
+	if secret, _, err := Lookup(BackendGitHub, ""); err != nil || secret != "" {
+		t.Errorf("Lookup() = %q, %v; want the unlisted secret skipped", secret, err)
+	}
+
+	if err := Set(StoreKeychain, BackendGitHub, "", "listed"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if secret, store, err := Lookup(BackendGitHub, ""); err != nil || secret != "listed" || store != StoreKeychain {
+		t.Errorf("Lookup() = %q from %q, %v; want listed from keychain", secret, store, err)
+	}
+}
+
+func TestSetErrors(t *testing.T) {
+	setupStores(t)
+	osKeychain = nil
+
+	tests := []struct {
+		name    string
+		store   string
+		backend string
+		secret  string
+		want    string
+	}{
+		{"unknown backend", StoreEncrypted, "jira", "x", `unknown backend "jira"`},
+		{"unknown store", "vault", BackendGitHub, "x", `unknown credential store "vault"`},
+		{"empty secret", StoreEncrypted, BackendGitHub, " ", "empty github credential"},
+		{"no keychain", StoreKeychain, BackendGitHub, "x", "no supported keychain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Set(tt.store, tt.backend, "", tt.secret)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Set() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+	if got := DefaultStore(); got != StoreEncrypted {
+		t.Errorf("DefaultStore() = %q without a keychain, want %q", got, StoreEncrypted)
+	}
+}
+
+func TestSecurityQuote(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"ghp_abc", `"ghp_abc"`},
+		{"with space", `"with space"`},
+		{`a"b`, `"a\"b"`},
+		{`a\b`, `"a\\b"`},
+	}
+	for _, tt := range tests {
+		if got := securityQuote(tt.in); got != tt.want {
+			t.Errorf("securityQuote(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
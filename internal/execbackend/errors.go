@@ -0,0 +1,134 @@
+package execbackend
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HandshakeError is returned by Connect when the program can't be started
+// or doesn't answer the handshake with a supported protocol version.
+type HandshakeError struct {
+	// Command is the program that was run.
+	Command string
+
+	// Err is the underlying failure.
+	Err error
+}
+
+func (e *HandshakeError) Error() string {
+	return fmt.Sprintf("handshake with %s failed: %v", e.Command, e.Err)
+}
+
+func (e *HandshakeError) Unwrap() error {
+	return e.Err
+}
+
+// ProtocolError is returned when the program's response to a request isn't
+// valid protocol JSON.
+type ProtocolError struct {
+	// Op is the request's operation, such as "list".
+	Op string
+
+	// Err describes what was wrong with the response.
+	Err error
+}
+
+func (e *ProtocolError) Error() string {
+	return fmt.Sprintf("protocol error in %s response: %v", e.Op, e.Err)
+}
+
+func (e *ProtocolError) Unwrap() error {
+	return e.Err
+}
+
+// TimeoutError is returned when the program doesn't respond to a request in
+// time. The program is killed, so later requests fail too.
+type TimeoutError struct {
+	// Op is the request's operation.
+	Op string
+
+	// Timeout is how long the backend waited.
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s request timed out after %s; raise timeout in the workspace config if the program needs longer", e.Op, e.Timeout)
+}
+
+// ExitError is returned when the program exits while a request is waiting
+// for its response.
+type ExitError struct {
+	// Command is the program that was run.
+	Command string
+
+	// Code is the program's exit status, or -1 if it was killed by a signal.
+	Code int
+
+	// Stderr is the end of what the program wrote to standard error.
+	Stderr string
+}
+
+func (e *ExitError) Error() string {
+	msg := fmt.Sprintf("%s exited with status %d", e.Command, e.Code)
+	if stderr := strings.TrimSpace(e.Stderr); stderr != "" {
+		msg += ": " + stderr
+	}
+	return msg
+}
+
+// RemoteError is an error the program reported in response to a request,
+// other than the codes mapped to the backend's own error types.
+type RemoteError struct {
+	// Op is the request's operation.
+	Op string
+
+	// Code is the program's error code, such as "INVALID_INPUT".
+	Code string
+
+	// Message is the program's description of the error.
+	Message string
+}
+
+func (e *RemoteError) Error() string {
+	if e.Code == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s (%s)", e.Message, e.Code)
+}
+
+// UnsupportedError is returned for an operation the program didn't
+// advertise in the handshake, or answered with the UNSUPPORTED code.
+type UnsupportedError struct {
+	// Op is the operation that was refused.
+	Op string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("the exec backend program does not support %s", e.Op)
+}
+
+// ClaimConflictError represents an error when a task is already claimed by another agent.
+type ClaimConflictError struct {
+	TaskID    string
+	ClaimedBy string
+}
+
+func (e *ClaimConflictError) Error() string {
+	return fmt.Sprintf("task %s is already claimed by agent %s", e.TaskID, e.ClaimedBy)
+}
+
+// ReleaseConflictError represents an error when trying to release a task that isn't claimed
+// by the current agent.
+type ReleaseConflictError struct {
+	TaskID       string
+	ClaimedBy    string
+	CurrentAgent string
+}
+
+func (e *ReleaseConflictError) Error() string {
+	if e.ClaimedBy == "" {
+		return fmt.Sprintf("task %s is not claimed", e.TaskID)
+	}
+	return fmt.Sprintf("task %s is claimed by different agent %s, not by %s", e.TaskID, e.ClaimedBy, e.CurrentAgent)
+}
@@ -0,0 +1,403 @@
+// Package execbackend implements the exec backend, which delegates every
+// operation to an external program, such as an adapter for a tracker
+// backlog has no built-in support for.
+//
+// The program is started on Connect and spoken to over line-delimited
+// JSON: each request is one line on its standard input and each response
+// one line on its standard output. The first request is a handshake, which
+// the program answers with its name, version, protocol version, and the
+// optional capabilities it supports. Closing its standard input asks it to
+// exit.
+package execbackend
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+const (
+	// Version is the current version of the exec backend.
+	Version = "0.1.0"
+
+	// Name is the name of the exec backend.
+	Name = "exec"
+
+	// DefaultTimeout is how long a request waits for its response when the
+	// workspace doesn't set timeout.
+	DefaultTimeout = 30 * time.Second
+
+	// exitGrace is how long Disconnect waits for the program to exit after
+	// closing its input.
+	exitGrace = 2 * time.Second
+)
+
+// Optional capabilities a program can advertise in the handshake.
+const (
+	// CapabilityClaim enables claim and release.
+	CapabilityClaim = "claim"
+
+	// CapabilityComments enables listing and adding comments.
+	CapabilityComments = "comments"
+)
+
+// WorkspaceConfig holds exec backend-specific workspace configuration.
+type WorkspaceConfig struct {
+	// Command is the program to run.
+	Command string
+	// Args are passed to the program.
+	Args []string
+	// Timeout bounds each request. Zero means DefaultTimeout.
+	Timeout time.Duration
+}
+
+// Exec implements the Backend interface by delegating to an external program.
+type Exec struct {
+	command      string
+	timeout      time.Duration
+	agentID      string
+	proc         *process
+	program      string // Program name and version from the handshake
+	capabilities map[string]bool
+	broken       error // Set once the program has timed out or exited
+	mu           sync.Mutex
+	connected    bool
+}
+
+// New creates a new exec backend instance.
+func New() *Exec {
+	return &Exec{}
+}
+
+// Name returns the name of the backend.
+func (e *Exec) Name() string {
+	return Name
+}
+
+// Version returns the version of the backend.
+func (e *Exec) Version() string {
+	return Version
+}
+
+// Connect starts the program and performs the handshake.
+func (e *Exec) Connect(cfg backend.Config) error {
+	wsCfg, ok := cfg.Workspace.(*WorkspaceConfig)
+	if !ok {
+		return errors.New("invalid workspace configuration for exec backend")
+	}
+	if wsCfg.Command == "" {
+		return errors.New("command not configured - set 'command' in workspace config")
+	}
+
+	e.command = wsCfg.Command
+	e.timeout = wsCfg.Timeout
+	if e.timeout <= 0 {
+		e.timeout = DefaultTimeout
+	}
+	e.agentID = cfg.AgentID
+
+	proc, err := start(wsCfg.Command, wsCfg.Args)
+	if err != nil {
+		return &HandshakeError{Command: e.command, Err: err}
+	}
+	e.proc = proc
+
+	resp, err := proc.roundTrip(Request{
+		Op:               "handshake",
+		Protocol:         ProtocolVersion,
+		Workspace:        cfg.WorkspaceName,
+		AgentID:          cfg.AgentID,
+		AgentLabelPrefix: cfg.AgentLabelPrefix,
+	}, e.timeout)
+	if err == nil && resp.Error != nil {
+		err = errors.New(resp.Error.Message)
+	}
+	if err == nil && resp.Protocol != ProtocolVersion {
+		err = fmt.Errorf("program speaks protocol version %d, want %d", resp.Protocol, ProtocolVersion)
+	}
+	if err != nil {
+		proc.close(exitGrace)
+		return &HandshakeError{Command: e.command, Err: err}
+	}
+
+	e.program = resp.Name
+	if resp.Version != "" {
+		e.program += " " + resp.Version
+	}
+	e.capabilities = make(map[string]bool)
+	for _, c := range resp.Capabilities {
+		switch c {
+		case CapabilityClaim, CapabilityComments:
+			e.capabilities[c] = true
+		default:
+			slog.Debug("ignoring unknown capability", "program", e.command, "capability", c)
+		}
+	}
+	e.connected = true
+	slog.Debug("exec backend handshake", "program", e.program, "capabilities", resp.Capabilities)
+	return nil
+}
+
+// Negotiated returns the backend with the optional interfaces the program
+// advertised: a Claimer only if it supports claim. Implements the
+// backend.Negotiator interface.
+func (e *Exec) Negotiated() backend.Backend {
+	if e.capabilities[CapabilityClaim] {
+		return &claimingExec{e}
+	}
+	return e
+}
+
+// Disconnect closes the program's input and waits for it to exit.
+func (e *Exec) Disconnect() error {
+	if e.proc != nil {
+		e.proc.close(exitGrace)
+		e.proc = nil
+	}
+	e.connected = false
+	return nil
+}
+
+// call sends a request and returns the response, mapping a reported error
+// to the backend's error types. After a timeout or exit every call fails
+// with that error.
+func (e *Exec) call(req Request) (*Response, error) {
+	if !e.connected {
+		return nil, errors.New("not connected")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.broken != nil {
+		return nil, e.broken
+	}
+	resp, err := e.proc.roundTrip(req, e.timeout)
+	if err != nil {
+		var protocolErr *ProtocolError
+		if !errors.As(err, &protocolErr) {
+			e.broken = err
+		}
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, e.remoteError(req, resp.Error)
+	}
+	return resp, nil
+}
+
+// remoteError converts an error reported by the program.
+func (e *Exec) remoteError(req Request, r *ResponseError) error {
+	switch r.Code {
+	case CodeNotFound:
+		return &backend.NotFoundError{ID: req.ID}
+	case CodeAlreadyExists:
+		id := req.ID
+		if req.Input != nil {
+			id = req.Input.ID
+		}
+		return &backend.AlreadyExistsError{ID: id}
+	case CodeClaimConflict:
+		return &ClaimConflictError{TaskID: req.ID, ClaimedBy: r.ClaimedBy}
+	case CodeReleaseConflict:
+		return &ReleaseConflictError{TaskID: req.ID, ClaimedBy: r.ClaimedBy, CurrentAgent: e.agentID}
+	case CodeUnsupported:
+		return &UnsupportedError{Op: req.Op}
+	}
+	return &RemoteError{Op: req.Op, Code: r.Code, Message: r.Message}
+}
+
+// callTask sends a request whose response carries a task.
+func (e *Exec) callTask(req Request) (*backend.Task, error) {
+	resp, err := e.call(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Task == nil {
+		return nil, &ProtocolError{Op: req.Op, Err: errors.New("response has no task")}
+	}
+	return resp.Task, nil
+}
+
+// HealthCheck asks the program whether it can reach its tracker.
+func (e *Exec) HealthCheck() (backend.HealthStatus, error) {
+	start := time.Now()
+	if !e.connected {
+		return backend.HealthStatus{OK: false, Message: "not connected", Latency: time.Since(start)}, nil
+	}
+
+	resp, err := e.call(Request{Op: "health"})
+	latency := time.Since(start)
+	if err != nil {
+		return backend.HealthStatus{OK: false, Message: fmt.Sprintf("%s: %v", e.program, err), Latency: latency}, nil
+	}
+	message := resp.Message
+	if message == "" {
+		message = e.program + " is responding"
+	}
+	return backend.HealthStatus{OK: resp.OK, Message: message, Latency: latency}, nil
+}
+
+// List returns tasks matching the given filters.
+func (e *Exec) List(filters backend.TaskFilters) (*backend.TaskList, error) {
+	wire := &Filters{
+		Status:         filters.Status,
+		Priority:       filters.Priority,
+		Assignee:       filters.Assignee,
+		Labels:         filters.Labels,
+		Parent:         filters.Parent,
+		Limit:          filters.Limit,
+		IncludeDone:    filters.IncludeDone,
+		IncludeDeleted: filters.IncludeDeleted,
+		HasComments:    filters.HasComments,
+	}
+	if wire.Assignee == "@me" {
+		wire.Assignee = e.agentID
+	}
+	if !filters.CreatedSince.IsZero() {
+		wire.CreatedSince = &filters.CreatedSince
+	}
+	if !filters.UpdatedSince.IsZero() {
+		wire.UpdatedSince = &filters.UpdatedSince
+	}
+
+	resp, err := e.call(Request{Op: "list", Filters: wire})
+	if err != nil {
+		return nil, err
+	}
+	tasks := resp.Tasks
+	if tasks == nil {
+		tasks = []backend.Task{}
+	}
+	return &backend.TaskList{Tasks: tasks, Count: len(tasks), HasMore: resp.HasMore}, nil
+}
+
+// Get returns a single task by ID.
+func (e *Exec) Get(id string) (*backend.Task, error) {
+	return e.callTask(Request{Op: "get", ID: id})
+}
+
+// Create creates a new task.
+func (e *Exec) Create(input backend.TaskInput) (*backend.Task, error) {
+	if task, err := backend.LookupExisting(input, e.List); err != nil || task != nil {
+		return task, err
+	}
+
+	return e.callTask(Request{Op: "create", Input: &Input{
+		ID:             input.ID,
+		Title:          input.Title,
+		Description:    input.Description,
+		Status:         input.Status,
+		Priority:       input.Priority,
+		Labels:         input.Labels,
+		Assignee:       input.Assignee,
+		Parent:         input.Parent,
+		IdempotencyKey: input.IdempotencyKey,
+	}})
+}
+
+// Update modifies an existing task.
+func (e *Exec) Update(id string, changes backend.TaskChanges) (*backend.Task, error) {
+	return e.callTask(Request{Op: "update", ID: id, Changes: &Changes{
+		Title:        changes.Title,
+		Description:  changes.Description,
+		Priority:     changes.Priority,
+		Assignee:     changes.Assignee,
+		AddLabels:    changes.AddLabels,
+		RemoveLabels: changes.RemoveLabels,
+		Estimate:     changes.Estimate,
+		Spent:        changes.Spent,
+		Parent:       changes.Parent,
+	}})
+}
+
+// Delete removes a task.
+func (e *Exec) Delete(id string) error {
+	_, err := e.call(Request{Op: "delete", ID: id})
+	return err
+}
+
+// Move transitions a task to a new status.
+func (e *Exec) Move(id string, status backend.Status) (*backend.Task, error) {
+	return e.callTask(Request{Op: "move", ID: id, Status: status})
+}
+
+// Assign assigns a task to a user.
+func (e *Exec) Assign(id string, assignee string) (*backend.Task, error) {
+	return e.callTask(Request{Op: "assign", ID: id, Assignee: assignee})
+}
+
+// Unassign removes the assignee from a task.
+func (e *Exec) Unassign(id string) (*backend.Task, error) {
+	return e.callTask(Request{Op: "unassign", ID: id})
+}
+
+// ListComments returns all comments for a task. It needs the comments
+// capability.
+func (e *Exec) ListComments(id string) ([]backend.Comment, error) {
+	if !e.capabilities[CapabilityComments] {
+		return nil, &UnsupportedError{Op: "comments"}
+	}
+	resp, err := e.call(Request{Op: "list_comments", ID: id})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Comments == nil {
+		return []backend.Comment{}, nil
+	}
+	return resp.Comments, nil
+}
+
+// AddComment adds a comment to a task. It needs the comments capability.
+func (e *Exec) AddComment(id string, body string) (*backend.Comment, error) {
+	if !e.capabilities[CapabilityComments] {
+		return nil, &UnsupportedError{Op: "comments"}
+	}
+	resp, err := e.call(Request{Op: "add_comment", ID: id, Body: body})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Comment == nil {
+		return nil, &ProtocolError{Op: "add_comment", Err: errors.New("response has no comment")}
+	}
+	resp.Comment.TaskID = id
+	return resp.Comment, nil
+}
+
+// claimingExec is the backend for a program that advertised the claim
+// capability.
+type claimingExec struct {
+	*Exec
+}
+
+// Claim claims a task for an agent. Implements the backend.Claimer interface.
+func (c *claimingExec) Claim(id string, agentID string) (*backend.ClaimResult, error) {
+	resp, err := c.call(Request{Op: "claim", ID: id, AgentID: agentID})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Task == nil {
+		return nil, &ProtocolError{Op: "claim", Err: errors.New("response has no task")}
+	}
+	result := &backend.ClaimResult{Task: resp.Task, AlreadyOwned: resp.AlreadyOwned}
+	if resp.ExpiresAt != nil {
+		result.ExpiresAt = *resp.ExpiresAt
+	}
+	return result, nil
+}
+
+// Release releases a claimed task. Implements the backend.Claimer interface.
+func (c *claimingExec) Release(id string) error {
+	_, err := c.call(Request{Op: "release", ID: id, AgentID: c.agentID})
+	return err
+}
+
+// Register registers the exec backend with the registry.
+func Register() {
+	backend.Register(Name, func() backend.Backend {
+		return New()
+	})
+}
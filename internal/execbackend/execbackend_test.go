@@ -0,0 +1,280 @@
+package execbackend
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// helperEnv selects how the test binary behaves when run as the program.
+const helperEnv = "EXECBACKEND_HELPER"
+
+// TestHelperProcess is the program the tests connect to. It only runs when
+// started by connectHelper.
+func TestHelperProcess(t *testing.T) {
+	mode := os.Getenv(helperEnv)
+	if mode == "" {
+		return
+	}
+	runHelper(mode)
+	os.Exit(0)
+}
+
+// runHelper answers requests according to mode:
+//
+//	ok           handshake with claim and comments, then serve task-1
+//	nocaps       handshake without capabilities, then serve task-1
+//	badproto     handshake with an unsupported protocol version
+//	nohandshake  exit with status 1 before answering
+//	garbage      answer every request after the handshake with non-JSON
+//	hang         never answer after the handshake
+//	exit         exit with status 3 on the first request after the handshake
+func runHelper(mode string) {
+	if mode == "nohandshake" {
+		fmt.Fprintln(os.Stderr, "tracker credentials missing")
+		os.Exit(1)
+	}
+
+	out := json.NewEncoder(os.Stdout)
+	scanner := bufio.NewScanner(os.Stdin)
+	task := backend.Task{ID: "task-1", Title: "First", Status: backend.StatusTodo, Priority: backend.PriorityHigh}
+	for scanner.Scan() {
+		var req Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			os.Exit(2)
+		}
+
+		if req.Op == "handshake" {
+			resp := Response{Name: "helper", Version: "1.0", Protocol: ProtocolVersion}
+			switch mode {
+			case "ok":
+				resp.Capabilities = []string{CapabilityClaim, CapabilityComments, "teleport"}
+			case "badproto":
+				resp.Protocol = 99
+			}
+			out.Encode(resp)
+			continue
+		}
+
+		switch mode {
+		case "garbage":
+			fmt.Println("this is not json")
+			continue
+		case "hang":
+			time.Sleep(time.Minute)
+		case "exit":
+			fmt.Fprintln(os.Stderr, "boom")
+			os.Exit(3)
+		}
+
+		var resp Response
+		switch {
+		case req.ID != "" && req.ID != task.ID:
+			resp.Error = &ResponseError{Code: CodeNotFound, Message: "no such task"}
+		case req.Op == "health":
+			resp.OK = true
+		case req.Op == "list":
+			if req.Filters.Assignee == "" || req.Filters.Assignee == task.Assignee {
+				resp.Tasks = []backend.Task{task}
+			}
+		case req.Op == "get":
+			resp.Task = &task
+		case req.Op == "create" && req.Input.ID == task.ID:
+			resp.Error = &ResponseError{Code: CodeAlreadyExists, Message: "taken"}
+		case req.Op == "claim":
+			if task.Assignee != "" && task.Assignee != req.AgentID {
+				resp.Error = &ResponseError{Code: CodeClaimConflict, Message: "claimed", ClaimedBy: task.Assignee}
+				break
+			}
+			task.Assignee = req.AgentID
+			task.Status = backend.StatusInProgress
+			resp.Task = &task
+		case req.Op == "add_comment":
+			resp.Comment = &backend.Comment{ID: "c1", Author: "helper", Body: req.Body}
+		default:
+			resp.Error = &ResponseError{Code: CodeUnsupported, Message: "unknown op " + req.Op}
+		}
+		out.Encode(resp)
+	}
+}
+
+// connectHelper connects to the test binary running as the program in mode.
+func connectHelper(t *testing.T, mode string, timeout time.Duration) (*Exec, error) {
+	t.Helper()
+	t.Setenv(helperEnv, mode)
+	e := New()
+	err := e.Connect(backend.Config{
+		AgentID: "agent-1",
+		Workspace: &WorkspaceConfig{
+			Command: os.Args[0],
+			Args:    []string{"-test.run=^TestHelperProcess$"},
+			Timeout: timeout,
+		},
+	})
+	if err == nil {
+		t.Cleanup(func() { e.Disconnect() })
+	}
+	return e, err
+}
+
+func TestNegotiatedCapabilities(t *testing.T) {
+	e, err := connectHelper(t, "ok", 0)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	if _, ok := e.Negotiated().(backend.Claimer); !ok {
+		t.Error("program advertised claim, but the negotiated backend is not a Claimer")
+	}
+
+	e, err = connectHelper(t, "nocaps", 0)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	if _, ok := e.Negotiated().(backend.Claimer); ok {
+		t.Error("program didn't advertise claim, but the negotiated backend is a Claimer")
+	}
+	var unsupported *UnsupportedError
+	if _, err := e.ListComments("task-1"); !errors.As(err, &unsupported) {
+		t.Errorf("ListComments without the comments capability: got %v, want *UnsupportedError", err)
+	}
+}
+
+func TestOperations(t *testing.T) {
+	e, err := connectHelper(t, "ok", 0)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	b := e.Negotiated()
+
+	health, err := b.HealthCheck()
+	if err != nil || !health.OK {
+		t.Errorf("HealthCheck = %+v, %v; want OK", health, err)
+	}
+
+	list, err := b.List(backend.TaskFilters{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if list.Count != 1 || list.Tasks[0].ID != "task-1" {
+		t.Errorf("List = %+v, want task-1", list.Tasks)
+	}
+
+	if _, err := b.Get("task-9"); !backend.IsNotFound(err) {
+		t.Errorf("Get missing task: got %v, want a not found error", err)
+	}
+	var exists *backend.AlreadyExistsError
+	if _, err := b.Create(backend.TaskInput{Title: "Again", ID: "task-1"}); !errors.As(err, &exists) || exists.ID != "task-1" {
+		t.Errorf("Create with a taken ID: got %v, want an already exists error for task-1", err)
+	}
+
+	claimer := b.(backend.Claimer)
+	result, err := claimer.Claim("task-1", "agent-1")
+	if err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	if result.Task.Assignee != "agent-1" || result.Task.Status != backend.StatusInProgress {
+		t.Errorf("claimed task = %+v, want assigned to agent-1 and in-progress", result.Task)
+	}
+	var conflict *ClaimConflictError
+	if _, err := claimer.Claim("task-1", "agent-2"); !errors.As(err, &conflict) || conflict.ClaimedBy != "agent-1" {
+		t.Errorf("Claim by another agent: got %v, want a claim conflict with agent-1", err)
+	}
+
+	// "@me" is resolved to the agent before it reaches the program
+	mine, err := b.List(backend.TaskFilters{Assignee: "@me"})
+	if err != nil {
+		t.Fatalf("List @me: %v", err)
+	}
+	if mine.Count != 1 {
+		t.Errorf("List @me = %d tasks, want 1", mine.Count)
+	}
+
+	comment, err := b.AddComment("task-1", "hello")
+	if err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+	if comment.Body != "hello" || comment.TaskID != "task-1" {
+		t.Errorf("AddComment = %+v, want body hello on task-1", comment)
+	}
+
+	var unsupported *UnsupportedError
+	if err := b.Delete("task-1"); !errors.As(err, &unsupported) {
+		t.Errorf("Delete answered with UNSUPPORTED: got %v, want *UnsupportedError", err)
+	}
+}
+
+func TestHandshakeErrors(t *testing.T) {
+	for _, mode := range []string{"badproto", "nohandshake"} {
+		t.Run(mode, func(t *testing.T) {
+			_, err := connectHelper(t, mode, 0)
+			var handshake *HandshakeError
+			if !errors.As(err, &handshake) {
+				t.Fatalf("Connect: got %v, want *HandshakeError", err)
+			}
+		})
+	}
+
+	t.Run("missing program", func(t *testing.T) {
+		err := New().Connect(backend.Config{Workspace: &WorkspaceConfig{Command: "/nonexistent/backlog-adapter"}})
+		var handshake *HandshakeError
+		if !errors.As(err, &handshake) {
+			t.Fatalf("Connect: got %v, want *HandshakeError", err)
+		}
+	})
+
+	t.Run("exit status", func(t *testing.T) {
+		_, err := connectHelper(t, "nohandshake", 0)
+		var exit *ExitError
+		if !errors.As(err, &exit) || exit.Code != 1 || exit.Stderr != "tracker credentials missing\n" {
+			t.Fatalf("Connect: got %v, want to wrap an ExitError with status 1 and the program's stderr", err)
+		}
+	})
+}
+
+func TestProtocolError(t *testing.T) {
+	e, err := connectHelper(t, "garbage", 0)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	var protocol *ProtocolError
+	if _, err := e.Get("task-1"); !errors.As(err, &protocol) || protocol.Op != "get" {
+		t.Errorf("Get: got %v, want a *ProtocolError for get", err)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	e, err := connectHelper(t, "hang", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	e.timeout = 100 * time.Millisecond
+
+	var timeout *TimeoutError
+	if _, err := e.Get("task-1"); !errors.As(err, &timeout) {
+		t.Fatalf("Get: got %v, want *TimeoutError", err)
+	}
+	// The program was killed, so later calls fail the same way
+	if _, err := e.List(backend.TaskFilters{}); !errors.As(err, &timeout) {
+		t.Errorf("List after timeout: got %v, want *TimeoutError", err)
+	}
+}
+
+func TestExitError(t *testing.T) {
+	e, err := connectHelper(t, "exit", 0)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	var exit *ExitError
+	if _, err := e.Get("task-1"); !errors.As(err, &exit) {
+		t.Fatalf("Get: got %v, want *ExitError", err)
+	}
+	if exit.Code != 3 || exit.Stderr != "boom\n" {
+		t.Errorf("ExitError = %+v, want status 3 with stderr boom", exit)
+	}
+}
@@ -0,0 +1,297 @@
+package execbackend
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// ProtocolVersion is the version of the line protocol spoken with the
+// program, sent in the handshake.
+const ProtocolVersion = 1
+
+// Error codes a program can answer with that map to the backend's own
+// error types. Any other code is returned as a *RemoteError.
+const (
+	CodeNotFound        = "NOT_FOUND"
+	CodeAlreadyExists   = "ALREADY_EXISTS"
+	CodeClaimConflict   = "CLAIM_CONFLICT"
+	CodeReleaseConflict = "RELEASE_CONFLICT"
+	CodeUnsupported     = "UNSUPPORTED"
+)
+
+// Request is one line of JSON sent to the program. Op names the operation;
+// the other fields are set as the operation needs them.
+type Request struct {
+	Op string `json:"op"`
+
+	// Handshake fields
+	Protocol         int    `json:"protocol,omitempty"`
+	Workspace        string `json:"workspace,omitempty"`
+	AgentID          string `json:"agent_id,omitempty"`
+	AgentLabelPrefix string `json:"agent_label_prefix,omitempty"`
+
+	// ID is the task the operation applies to.
+	ID string `json:"id,omitempty"`
+
+	Filters  *Filters       `json:"filters,omitempty"`  // list
+	Input    *Input         `json:"input,omitempty"`    // create
+	Changes  *Changes       `json:"changes,omitempty"`  // update
+	Status   backend.Status `json:"status,omitempty"`   // move
+	Assignee string         `json:"assignee,omitempty"` // assign
+	Body     string         `json:"body,omitempty"`     // add_comment
+}
+
+// Filters are the list filters sent to the program. "@me" as the assignee
+// is replaced by the agent ID before sending.
+type Filters struct {
+	Status         []backend.Status   `json:"status,omitempty"`
+	Priority       []backend.Priority `json:"priority,omitempty"`
+	Assignee       string             `json:"assignee,omitempty"`
+	Labels         []string           `json:"labels,omitempty"`
+	Parent         string             `json:"parent,omitempty"`
+	Limit          int                `json:"limit,omitempty"`
+	IncludeDone    bool               `json:"include_done,omitempty"`
+	IncludeDeleted bool               `json:"include_deleted,omitempty"`
+	CreatedSince   *time.Time         `json:"created_since,omitempty"`
+	UpdatedSince   *time.Time         `json:"updated_since,omitempty"`
+	HasComments    *bool              `json:"has_comments,omitempty"`
+}
+
+// Input is a task to create.
+type Input struct {
+	ID             string           `json:"id,omitempty"`
+	Title          string           `json:"title"`
+	Description    string           `json:"description,omitempty"`
+	Status         backend.Status   `json:"status,omitempty"`
+	Priority       backend.Priority `json:"priority,omitempty"`
+	Labels         []string         `json:"labels,omitempty"`
+	Assignee       string           `json:"assignee,omitempty"`
+	Parent         string           `json:"parent,omitempty"`
+	IdempotencyKey string           `json:"idempotency_key,omitempty"`
+}
+
+// Changes are the fields to update on a task. Missing fields are left
+// alone; an empty assignee or parent clears it.
+type Changes struct {
+	Title        *string           `json:"title,omitempty"`
+	Description  *string           `json:"description,omitempty"`
+	Priority     *backend.Priority `json:"priority,omitempty"`
+	Assignee     *string           `json:"assignee,omitempty"`
+	AddLabels    []string          `json:"add_labels,omitempty"`
+	RemoveLabels []string          `json:"remove_labels,omitempty"`
+	Estimate     *backend.Duration `json:"estimate,omitempty"`
+	Spent        *backend.Duration `json:"spent,omitempty"`
+	Parent       *string           `json:"parent,omitempty"`
+}
+
+// Response is one line of JSON read back from the program. A response with
+// Error set is a failure; otherwise the fields the operation returns are set.
+type Response struct {
+	Error *ResponseError `json:"error,omitempty"`
+
+	// Handshake fields
+	Name         string   `json:"name,omitempty"`
+	Version      string   `json:"version,omitempty"`
+	Protocol     int      `json:"protocol,omitempty"`
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	// Health fields
+	OK      bool   `json:"ok,omitempty"`
+	Message string `json:"message,omitempty"`
+
+	Task     *backend.Task     `json:"task,omitempty"`     // get, create, update, move, assign, unassign, claim
+	Tasks    []backend.Task    `json:"tasks,omitempty"`    // list
+	HasMore  bool              `json:"has_more,omitempty"` // list
+	Comments []backend.Comment `json:"comments,omitempty"` // list_comments
+	Comment  *backend.Comment  `json:"comment,omitempty"`  // add_comment
+
+	// Claim fields
+	AlreadyOwned bool       `json:"already_owned,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+}
+
+// ResponseError is the error object of a failed response.
+type ResponseError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	ClaimedBy string `json:"claimed_by,omitempty"` // CLAIM_CONFLICT, RELEASE_CONFLICT
+}
+
+// maxLine bounds a single response line, which holds a whole task list.
+const maxLine = 64 << 20
+
+// pipeDelay is how long to wait for the program's output to close once it
+// has exited.
+const pipeDelay = 500 * time.Millisecond
+
+// stderrTail is how much of the program's standard error is kept for
+// ExitError.
+const stderrTail = 4096
+
+// process is a running program and the pipes the protocol runs over.
+type process struct {
+	command string
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	stdout  io.ReadCloser
+	stderr  *tailBuffer
+
+	// lines receives each line the program writes and is closed at EOF.
+	lines   chan []byte
+	readErr error // Why reading stopped early, set before lines is closed
+
+	// done is closed once the program has exited and waitErr is set.
+	done    chan struct{}
+	waitErr error
+}
+
+// start runs command and begins reading its output.
+func start(command string, args []string) (*process, error) {
+	cmd := exec.Command(command, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	p := &process{
+		command: command,
+		cmd:     cmd,
+		stdin:   stdin,
+		stdout:  stdout,
+		stderr:  &tailBuffer{max: stderrTail},
+		lines:   make(chan []byte),
+		done:    make(chan struct{}),
+	}
+	cmd.Stderr = p.stderr
+	// Don't wait on output held open by children of a killed program
+	cmd.WaitDelay = pipeDelay
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLine)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			p.lines <- line
+		}
+		p.readErr = scanner.Err()
+		close(p.lines)
+		// Wait closes stdout, so it must come after the last read
+		p.waitErr = cmd.Wait()
+		close(p.done)
+	}()
+	return p, nil
+}
+
+// roundTrip sends req and returns the next line of output, decoded. A
+// timeout kills the program.
+func (p *process) roundTrip(req Request, timeout time.Duration) (*Response, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.stdin.Write(append(data, '\n')); err != nil {
+		// The program has gone away; report how it exited
+		return nil, p.exitError()
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case line, ok := <-p.lines:
+		if !ok {
+			if p.readErr != nil {
+				p.kill()
+				return nil, &ProtocolError{Op: req.Op, Err: p.readErr}
+			}
+			return nil, p.exitError()
+		}
+		var resp Response
+		if err := json.Unmarshal(line, &resp); err != nil {
+			return nil, &ProtocolError{Op: req.Op, Err: fmt.Errorf("invalid JSON %q: %v", truncate(string(line), 200), err)}
+		}
+		return &resp, nil
+	case <-timer.C:
+		p.kill()
+		return nil, &TimeoutError{Op: req.Op, Timeout: timeout}
+	}
+}
+
+// exitError waits for the program to exit and describes how it did.
+func (p *process) exitError() error {
+	<-p.done
+	code := 0
+	var exitErr *exec.ExitError
+	if errors.As(p.waitErr, &exitErr) {
+		code = exitErr.ExitCode()
+	}
+	return &ExitError{Command: p.command, Code: code, Stderr: p.stderr.String()}
+}
+
+// close closes the program's input, which asks it to exit, and waits up to
+// grace for it to do so before killing it.
+func (p *process) close(grace time.Duration) {
+	p.stdin.Close()
+	select {
+	case <-p.done:
+	case <-time.After(grace):
+		p.kill()
+	}
+}
+
+// kill stops the program and waits for it to exit.
+func (p *process) kill() {
+	p.cmd.Process.Kill()
+	// A child the program started may still hold its output open
+	p.stdout.Close()
+	// Drain output so the reader can reach EOF and reap the program
+	go func() {
+		for range p.lines {
+		}
+	}()
+	<-p.done
+}
+
+// tailBuffer is an io.Writer that keeps the last max bytes written to it.
+type tailBuffer struct {
+	mu  sync.Mutex
+	max int
+	buf []byte
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > t.max {
+		t.buf = t.buf[len(t.buf)-t.max:]
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return string(t.buf)
+}
+
+// truncate shortens s to at most n bytes for an error message.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"sort"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/alexbrand/backlog/internal/backend"
 	"github.com/alexbrand/backlog/internal/credentials"
+	"github.com/alexbrand/backlog/internal/logging"
 	gh "github.com/google/go-github/v60/github"
 	"golang.org/x/oauth2"
 )
@@ -24,6 +26,10 @@ const (
 
 	// Name is the name of the GitHub backend.
 	Name = "github"
+
+	// defaultPriorityLabelPrefix is the label prefix that carries a task's
+	// priority, since GitHub issues have no priority field.
+	defaultPriorityLabelPrefix = "priority:"
 )
 
 // Default status label mappings for GitHub Issues.
@@ -46,6 +52,20 @@ type WorkspaceConfig struct {
 	StatusField string
 	// StatusMap allows custom status-to-label mappings.
 	StatusMap map[backend.Status]StatusMapping
+	// Timeout bounds each API request. Defaults to 30 seconds.
+	Timeout time.Duration
+	// PriorityLabelPrefix is the prefix of labels that set the priority,
+	// e.g. "priority:" for "priority:high". Defaults to "priority:".
+	PriorityLabelPrefix string
+	// NoRateLimitWait fails requests instead of waiting for the reset when
+	// the API rate limit is exhausted.
+	NoRateLimitWait bool
+	// AssigneeMap maps agent IDs to the GitHub logins their claims assign.
+	// Agents without a mapping are assigned the authenticated user.
+	AssigneeMap map[string]string
+	// LabelOnlyClaims marks claims with the agent label alone, leaving the
+	// issue's assignees untouched on claim and release.
+	LabelOnlyClaims bool
 }
 
 // StatusMapping defines how a canonical status maps to GitHub state and labels.
@@ -63,6 +83,12 @@ type GitHub struct {
 	repo             string
 	agentID          string
 	agentLabelPrefix string
+	priorityPrefix   string
+	assigneeMap      map[string]string
+	labelOnlyClaims  bool
+	login            string          // authenticated user, looked up on first use
+	knownLabels      map[string]bool // priority labels known to exist in the repo
+	rateLimits       *rateLimiter
 	statusMap        map[backend.Status]StatusMapping
 	connected        bool
 	ctx              context.Context
@@ -75,7 +101,8 @@ type GitHub struct {
 // New creates a new GitHub backend instance.
 func New() *GitHub {
 	return &GitHub{
-		ctx: context.Background(),
+		ctx:            context.Background(),
+		priorityPrefix: defaultPriorityLabelPrefix,
 	}
 }
 
@@ -109,6 +136,11 @@ func (g *GitHub) Connect(cfg backend.Config) error {
 	if g.agentLabelPrefix == "" {
 		g.agentLabelPrefix = "agent"
 	}
+	if wsCfg.PriorityLabelPrefix != "" {
+		g.priorityPrefix = wsCfg.PriorityLabelPrefix
+	}
+	g.assigneeMap = wsCfg.AssigneeMap
+	g.labelOnlyClaims = wsCfg.LabelOnlyClaims
 
 	// Set up status mappings
 	g.statusMap = make(map[backend.Status]StatusMapping)
@@ -128,8 +160,8 @@ func (g *GitHub) Connect(cfg backend.Config) error {
 		}
 	}
 
-	// Get token from credentials (env var or credentials.yaml)
-	token, err := credentials.GetGitHubToken()
+	// Get token from credentials (env var, credential stores, or credentials.yaml)
+	token, err := credentials.GetGitHubTokenFor(cfg.WorkspaceName)
 	if err != nil {
 		return err
 	}
@@ -139,6 +171,15 @@ func (g *GitHub) Connect(cfg backend.Config) error {
 		&oauth2.Token{AccessToken: token},
 	)
 	tc := oauth2.NewClient(g.ctx, ts)
+	g.rateLimits = newRateLimiter(wsCfg.NoRateLimitWait)
+	tc.Transport = &rateLimitTransport{
+		base:   &retryTransport{base: logging.NewTransport(tc.Transport)},
+		limits: g.rateLimits,
+	}
+	tc.Timeout = wsCfg.Timeout
+	if tc.Timeout <= 0 {
+		tc.Timeout = defaultTimeout
+	}
 	g.client = gh.NewClient(tc)
 
 	// Check for GITHUB_API_URL environment variable for testing/enterprise
@@ -184,6 +225,7 @@ func (g *GitHub) Connect(cfg backend.Config) error {
 func (g *GitHub) Disconnect() error {
 	g.connected = false
 	g.client = nil
+	g.login = ""
 	return nil
 }
 
@@ -217,11 +259,15 @@ func (g *GitHub) HealthCheck() (backend.HealthStatus, error) {
 		}, nil
 	}
 
-	return backend.HealthStatus{
+	status := backend.HealthStatus{
 		OK:      true,
 		Message: "ok",
 		Latency: latency,
-	}, nil
+	}
+	if limit, ok := g.rateLimits.latest(coreResource); ok {
+		status.RateLimit = &limit
+	}
+	return status, nil
 }
 
 // List returns tasks matching the given filters.
@@ -241,7 +287,11 @@ func (g *GitHub) List(filters backend.TaskFilters) (*backend.TaskList, error) {
 	// Apply assignee filter
 	if filters.Assignee != "" {
 		if filters.Assignee == "@me" {
-			opts.Assignee = "" // Will be set after getting current user
+			login, err := g.authenticatedLogin()
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve @me: %w", err)
+			}
+			opts.Assignee = login
 		} else if filters.Assignee == "unassigned" {
 			opts.Assignee = "none"
 		} else {
@@ -254,6 +304,21 @@ func (g *GitHub) List(filters backend.TaskFilters) (*backend.TaskList, error) {
 		opts.Labels = filters.Labels
 	}
 
+	// GitHub filters by update time server-side; creation time is checked below
+	if !filters.UpdatedSince.IsZero() {
+		opts.Since = filters.UpdatedSince
+	}
+
+	// Parent IDs are compared in canonical GH-N form
+	var parentID string
+	if filters.Parent != "" {
+		parentNum, err := g.parseIssueNumber(filters.Parent)
+		if err != nil {
+			return nil, err
+		}
+		parentID = fmt.Sprintf("GH-%d", parentNum)
+	}
+
 	// Fetch issues
 	issues, _, err := g.client.Issues.ListByRepo(g.ctx, g.owner, g.repo, opts)
 	if err != nil {
@@ -303,8 +368,27 @@ func (g *GitHub) List(filters backend.TaskFilters) (*backend.TaskList, error) {
 			continue
 		}
 
+		// Apply parent filter
+		if filters.Parent != "" && task.Parent != parentID {
+			continue
+		}
+
+		// Apply time filters
+		if !filters.CreatedSince.IsZero() && task.Created.Before(filters.CreatedSince) {
+			continue
+		}
+		if !filters.UpdatedSince.IsZero() && task.Updated.Before(filters.UpdatedSince) {
+			continue
+		}
+
+		// Apply comments filter
+		if filters.HasComments != nil && (issue.GetComments() > 0) != *filters.HasComments {
+			continue
+		}
+
 		tasks = append(tasks, *task)
 	}
+	slog.Debug("evaluated list filters", "backend", Name, "fetched", len(issues), "matched", len(tasks))
 
 	// Sort by priority (urgent first), then by created (oldest first), then by ID for deterministic order
 	sort.Slice(tasks, func(i, j int) bool {
@@ -344,12 +428,45 @@ func (g *GitHub) Get(id string) (*backend.Task, error) {
 		return nil, err
 	}
 
-	issue, _, err := g.client.Issues.Get(g.ctx, g.owner, g.repo, issueNum)
+	issue, resp, err := g.client.Issues.Get(g.ctx, g.owner, g.repo, issueNum)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get issue: %w", err)
+		return nil, issueError(id, resp, err)
 	}
 
-	return g.issueToTask(issue), nil
+	task := g.issueToTask(issue)
+	if task.Status != backend.StatusBacklog && task.Status != backend.StatusTodo {
+		if started := g.claimStartedAt(issueNum); started != nil {
+			task.StartedAt = started
+			task.TimeApproximate = true
+			backend.SetDuration(task, time.Now().UTC())
+		}
+	}
+	return task, nil
+}
+
+// claimStartedAt returns when the issue first got an agent label, read from
+// its event timeline, or nil if it never did or the events can't be read.
+// Only the first page of events is checked; it costs an extra request, so
+// List does not do this.
+func (g *GitHub) claimStartedAt(issueNum int) *time.Time {
+	events, _, err := g.client.Issues.ListIssueEvents(g.ctx, g.owner, g.repo, issueNum, &gh.ListOptions{PerPage: 100})
+	if err != nil {
+		slog.Debug("could not read issue events", "backend", Name, "issue", issueNum, "error", err)
+		return nil
+	}
+
+	agentLabelPrefix := g.agentLabelPrefix + ":"
+	var started *time.Time
+	for _, event := range events {
+		if event.GetEvent() != "labeled" || !strings.HasPrefix(event.GetLabel().GetName(), agentLabelPrefix) {
+			continue
+		}
+		at := event.GetCreatedAt().Time
+		if started == nil || at.Before(*started) {
+			started = &at
+		}
+	}
+	return started
 }
 
 // Create creates a new task and returns it.
@@ -357,19 +474,39 @@ func (g *GitHub) Create(input backend.TaskInput) (*backend.Task, error) {
 	if !g.connected {
 		return nil, errors.New("not connected")
 	}
+	if input.IdempotencyKey != "" {
+		return nil, errors.New("the github backend does not support idempotency keys")
+	}
+	if input.ID != "" {
+		return nil, errors.New("the github backend does not support pinned task IDs")
+	}
+
+	if task, err := backend.LookupExisting(input, g.List); err != nil || task != nil {
+		return task, err
+	}
 
 	// Build issue request
 	issueReq := &gh.IssueRequest{
 		Title: gh.String(input.Title),
 	}
 
-	if input.Description != "" {
+	if input.Parent != "" {
+		parentNum, err := g.parseIssueNumber(input.Parent)
+		if err != nil {
+			return nil, err
+		}
+		issueReq.Body = gh.String(withParentMarker(input.Description, parentNum))
+	} else if input.Description != "" {
 		issueReq.Body = gh.String(input.Description)
 	}
 
-	// Build labels
-	var labels []string
-	labels = append(labels, input.Labels...)
+	// Build labels. A priority given as a label is treated like the
+	// priority field, which takes precedence.
+	priority := input.Priority
+	if p, ok := g.lastPriorityLabel(input.Labels); ok && priority == "" {
+		priority = p
+	}
+	labels := g.withPriority(input.Labels, priority)
 
 	// Add status labels only if not using project-based status
 	status := input.Status
@@ -382,11 +519,6 @@ func (g *GitHub) Create(input backend.TaskInput) (*backend.Task, error) {
 		}
 	}
 
-	// Add priority label if set
-	if input.Priority != "" && input.Priority != backend.PriorityNone {
-		labels = append(labels, "priority:"+string(input.Priority))
-	}
-
 	if len(labels) > 0 {
 		issueReq.Labels = &labels
 	}
@@ -395,6 +527,8 @@ func (g *GitHub) Create(input backend.TaskInput) (*backend.Task, error) {
 		issueReq.Assignees = &[]string{input.Assignee}
 	}
 
+	g.ensurePriorityLabel(priority)
+
 	// Create the issue
 	issue, _, err := g.client.Issues.Create(g.ctx, g.owner, g.repo, issueReq)
 	if err != nil {
@@ -431,15 +565,19 @@ func (g *GitHub) Update(id string, changes backend.TaskChanges) (*backend.Task,
 		return nil, errors.New("not connected")
 	}
 
+	if changes.Estimate != nil || changes.Spent != nil {
+		return nil, errors.New("github backend does not support time tracking")
+	}
+
 	issueNum, err := g.parseIssueNumber(id)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get current issue to get current labels
-	issue, _, err := g.client.Issues.Get(g.ctx, g.owner, g.repo, issueNum)
+	issue, resp, err := g.client.Issues.Get(g.ctx, g.owner, g.repo, issueNum)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get issue: %w", err)
+		return nil, issueError(id, resp, err)
 	}
 
 	issueReq := &gh.IssueRequest{}
@@ -447,8 +585,26 @@ func (g *GitHub) Update(id string, changes backend.TaskChanges) (*backend.Task,
 	if changes.Title != nil {
 		issueReq.Title = changes.Title
 	}
-	if changes.Description != nil {
-		issueReq.Body = changes.Description
+	if changes.Description != nil || changes.Parent != nil {
+		// The parent is stored as a marker in the body, so either change
+		// rewrites the body while preserving the other part.
+		description, parentNum := splitParentMarker(issue.GetBody())
+		if changes.Description != nil {
+			description = *changes.Description
+		}
+		if changes.Parent != nil {
+			parentNum = 0
+			if *changes.Parent != "" {
+				parentNum, err = g.parseIssueNumber(*changes.Parent)
+				if err != nil {
+					return nil, err
+				}
+				if parentNum == issueNum {
+					return nil, fmt.Errorf("task %s cannot be a sub-task of itself", id)
+				}
+			}
+		}
+		issueReq.Body = gh.String(withParentMarker(description, parentNum))
 	}
 	if changes.Assignee != nil {
 		if *changes.Assignee == "" {
@@ -458,47 +614,40 @@ func (g *GitHub) Update(id string, changes backend.TaskChanges) (*backend.Task,
 		}
 	}
 
-	// Handle label changes
-	if len(changes.AddLabels) > 0 || len(changes.RemoveLabels) > 0 {
-		currentLabels := make(map[string]bool)
-		for _, label := range issue.Labels {
-			currentLabels[label.GetName()] = true
-		}
-
-		// Add new labels
-		for _, l := range changes.AddLabels {
-			currentLabels[l] = true
-		}
-
-		// Remove labels
+	// Handle label and priority changes. The priority lives in a label, so
+	// both rewrite the same label list; a priority label added with
+	// AddLabels replaces the current priority like a priority change does.
+	priority, priorityChanged := g.lastPriorityLabel(changes.AddLabels)
+	if changes.Priority != nil {
+		priority, priorityChanged = *changes.Priority, true
+	}
+	if len(changes.AddLabels) > 0 || len(changes.RemoveLabels) > 0 || priorityChanged {
+		remove := make(map[string]bool)
 		for _, l := range changes.RemoveLabels {
-			delete(currentLabels, l)
+			remove[l] = true
 		}
 
-		labels := make([]string, 0, len(currentLabels))
-		for l := range currentLabels {
-			labels = append(labels, l)
-		}
-		issueReq.Labels = &labels
-	}
-
-	// Handle priority change
-	if changes.Priority != nil {
-		currentLabels := make(map[string]bool)
+		var labels []string
+		seen := make(map[string]bool)
 		for _, label := range issue.Labels {
-			// Remove existing priority labels
-			if !strings.HasPrefix(label.GetName(), "priority:") {
-				currentLabels[label.GetName()] = true
+			if name := label.GetName(); !remove[name] && !seen[name] {
+				labels = append(labels, name)
+				seen[name] = true
 			}
 		}
-		// Add new priority label
-		if *changes.Priority != backend.PriorityNone {
-			currentLabels["priority:"+string(*changes.Priority)] = true
+		for _, l := range changes.AddLabels {
+			if !remove[l] && !seen[l] {
+				labels = append(labels, l)
+				seen[l] = true
+			}
 		}
 
-		labels := make([]string, 0, len(currentLabels))
-		for l := range currentLabels {
-			labels = append(labels, l)
+		if priorityChanged {
+			labels = g.withPriority(labels, priority)
+			g.ensurePriorityLabel(priority)
+		}
+		if labels == nil {
+			labels = []string{}
 		}
 		issueReq.Labels = &labels
 	}
@@ -549,9 +698,9 @@ func (g *GitHub) Move(id string, status backend.Status) (*backend.Task, error) {
 	}
 
 	// Get current issue
-	issue, _, err := g.client.Issues.Get(g.ctx, g.owner, g.repo, issueNum)
+	issue, resp, err := g.client.Issues.Get(g.ctx, g.owner, g.repo, issueNum)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get issue: %w", err)
+		return nil, issueError(id, resp, err)
 	}
 
 	// Update project status if using Projects v2
@@ -698,10 +847,11 @@ func (g *GitHub) AddComment(id string, body string) (*backend.Comment, error) {
 	}
 
 	return &backend.Comment{
-		ID:      id, // Use task ID as the comment ID for consistency
+		ID:      fmt.Sprintf("%d", comment.GetID()),
 		Author:  comment.GetUser().GetLogin(),
 		Body:    comment.GetBody(),
 		Created: comment.GetCreatedAt().Time,
+		TaskID:  id,
 	}, nil
 }
 
@@ -722,9 +872,9 @@ func (g *GitHub) Claim(id string, agentID string) (*backend.ClaimResult, error)
 	}
 
 	// Get current issue
-	issue, _, err := g.client.Issues.Get(g.ctx, g.owner, g.repo, issueNum)
+	issue, resp, err := g.client.Issues.Get(g.ctx, g.owner, g.repo, issueNum)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get issue: %w", err)
+		return nil, issueError(id, resp, err)
 	}
 
 	// Check for existing agent labels
@@ -748,6 +898,28 @@ func (g *GitHub) Claim(id string, agentID string) (*backend.ClaimResult, error)
 		}
 	}
 
+	// Add the agent label through the labels endpoint, which only appends,
+	// so a claim made concurrently by another agent is never overwritten
+	agentLabel := agentLabelPrefix + agentID
+	currentLabels, _, err := g.client.Issues.AddLabelsToIssue(g.ctx, g.owner, g.repo, issueNum, []string{agentLabel})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add agent label: %w", err)
+	}
+
+	// Another agent may have claimed the issue since we read it; back off
+	for _, label := range currentLabels {
+		if name := label.GetName(); strings.HasPrefix(name, agentLabelPrefix) && name != agentLabel {
+			if _, err := g.client.Issues.RemoveLabelForIssue(g.ctx, g.owner, g.repo, issueNum, agentLabel); err != nil {
+				return nil, fmt.Errorf("failed to remove agent label after conflict: %w", err)
+			}
+			return nil, &ClaimConflictError{
+				TaskID:       id,
+				ClaimedBy:    strings.TrimPrefix(name, agentLabelPrefix),
+				CurrentAgent: agentID,
+			}
+		}
+	}
+
 	// Update project status if using Projects v2
 	if g.useProjects {
 		if err := g.updateProjectStatus(issueNum, backend.StatusInProgress); err != nil {
@@ -755,9 +927,10 @@ func (g *GitHub) Claim(id string, agentID string) (*backend.ClaimResult, error)
 		}
 	}
 
-	// Build new labels: existing + agent label + in-progress status (if not using projects)
+	// Build new labels: current labels (including the agent label) with the
+	// status labels replaced by in-progress (if not using projects)
 	newLabels := make([]string, 0)
-	for _, label := range issue.Labels {
+	for _, label := range currentLabels {
 		labelName := label.GetName()
 		// Remove existing status labels
 		isStatusLabel := false
@@ -777,8 +950,6 @@ func (g *GitHub) Claim(id string, agentID string) (*backend.ClaimResult, error)
 		}
 	}
 
-	// Add agent label
-	newLabels = append(newLabels, agentLabelPrefix+agentID)
 	// Add in-progress status labels only if not using project-based status
 	if !g.useProjects {
 		if mapping, ok := g.statusMap[backend.StatusInProgress]; ok {
@@ -786,11 +957,12 @@ func (g *GitHub) Claim(id string, agentID string) (*backend.ClaimResult, error)
 		}
 	}
 
-	// Update the issue with assignment
-	updatedIssue, _, err := g.client.Issues.Edit(g.ctx, g.owner, g.repo, issueNum, &gh.IssueRequest{
-		Labels:    &newLabels,
-		Assignees: &[]string{g.getAssigneeForClaim()},
-	})
+	// Update the issue with assignment, unless claims are label-only
+	req := &gh.IssueRequest{Labels: &newLabels}
+	if !g.labelOnlyClaims {
+		req.Assignees = &[]string{g.claimAssignee(agentID)}
+	}
+	updatedIssue, _, err := g.client.Issues.Edit(g.ctx, g.owner, g.repo, issueNum, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to claim issue: %w", err)
 	}
@@ -804,19 +976,32 @@ func (g *GitHub) Claim(id string, agentID string) (*backend.ClaimResult, error)
 // Release releases a claimed task back to todo status.
 // Implements the backend.Claimer interface.
 func (g *GitHub) Release(id string) error {
+	_, err := g.release(id, false)
+	return err
+}
+
+// ForceRelease releases a claimed task back to todo status whichever agent
+// holds the claim. Implements the backend.ForceReleaser interface.
+func (g *GitHub) ForceRelease(id string) (string, error) {
+	return g.release(id, true)
+}
+
+// release releases a task, skipping the ownership check if force is set,
+// and returns the agent that held the claim.
+func (g *GitHub) release(id string, force bool) (string, error) {
 	if !g.connected {
-		return errors.New("not connected")
+		return "", errors.New("not connected")
 	}
 
 	issueNum, err := g.parseIssueNumber(id)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// Get current issue
-	issue, _, err := g.client.Issues.Get(g.ctx, g.owner, g.repo, issueNum)
+	issue, resp, err := g.client.Issues.Get(g.ctx, g.owner, g.repo, issueNum)
 	if err != nil {
-		return fmt.Errorf("failed to get issue: %w", err)
+		return "", issueError(id, resp, err)
 	}
 
 	// Check if the issue is claimed and by whom
@@ -831,13 +1016,13 @@ func (g *GitHub) Release(id string) error {
 
 	// If not claimed, error
 	if claimedBy == "" {
-		return &ReleaseError{TaskID: id, Message: "task is not claimed"}
+		return "", &ReleaseError{TaskID: id, Message: "task is not claimed"}
 	}
 
 	// If claimed by a different agent, error
 	currentAgent := g.agentID
-	if claimedBy != currentAgent {
-		return &ReleaseError{
+	if claimedBy != currentAgent && !force {
+		return "", &ReleaseError{
 			TaskID:       id,
 			Message:      fmt.Sprintf("task %s is claimed by different agent %s, not by %s", id, claimedBy, currentAgent),
 			ClaimedBy:    claimedBy,
@@ -848,7 +1033,7 @@ func (g *GitHub) Release(id string) error {
 	// Update project status if using Projects v2
 	if g.useProjects {
 		if err := g.updateProjectStatus(issueNum, backend.StatusTodo); err != nil {
-			return fmt.Errorf("failed to update project status: %w", err)
+			return "", fmt.Errorf("failed to update project status: %w", err)
 		}
 	}
 
@@ -885,16 +1070,24 @@ func (g *GitHub) Release(id string) error {
 		}
 	}
 
-	// Update the issue: remove assignees, update labels
-	_, _, err = g.client.Issues.Edit(g.ctx, g.owner, g.repo, issueNum, &gh.IssueRequest{
-		Labels:    &newLabels,
-		Assignees: &[]string{}, // Remove all assignees
-	})
+	// Update the issue: unassign the claim's assignee, update labels
+	req := &gh.IssueRequest{Labels: &newLabels}
+	if !g.labelOnlyClaims {
+		claimAssignee := g.claimAssignee(claimedBy)
+		assignees := make([]string, 0, len(issue.Assignees))
+		for _, user := range issue.Assignees {
+			if login := user.GetLogin(); login != claimAssignee {
+				assignees = append(assignees, login)
+			}
+		}
+		req.Assignees = &assignees
+	}
+	_, _, err = g.client.Issues.Edit(g.ctx, g.owner, g.repo, issueNum, req)
 	if err != nil {
-		return fmt.Errorf("failed to release issue: %w", err)
+		return "", fmt.Errorf("failed to release issue: %w", err)
 	}
 
-	return nil
+	return claimedBy, nil
 }
 
 // Helper functions
@@ -914,6 +1107,15 @@ func (g *GitHub) parseIssueNumber(id string) (int, error) {
 	return num, nil
 }
 
+// issueError converts an error fetching an issue into a
+// *backend.NotFoundError if the issue doesn't exist.
+func issueError(id string, resp *gh.Response, err error) error {
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return &backend.NotFoundError{ID: id}
+	}
+	return fmt.Errorf("failed to get issue: %w", err)
+}
+
 // issueToTask converts a GitHub Issue to a backend Task.
 func (g *GitHub) issueToTask(issue *gh.Issue) *backend.Task {
 	task := &backend.Task{
@@ -925,8 +1127,13 @@ func (g *GitHub) issueToTask(issue *gh.Issue) *backend.Task {
 		Meta:    make(map[string]any),
 	}
 
-	// Description from body
-	task.Description = issue.GetBody()
+	// Description from body, with the parent marker split off
+	description, parentNum := splitParentMarker(issue.GetBody())
+	task.Description = description
+	backend.SetChecklist(task)
+	if parentNum > 0 {
+		task.Parent = fmt.Sprintf("GH-%d", parentNum)
+	}
 
 	// Assignee
 	if len(issue.Assignees) > 0 {
@@ -939,9 +1146,11 @@ func (g *GitHub) issueToTask(issue *gh.Issue) *backend.Task {
 	var priority backend.Priority = backend.PriorityNone
 	for _, label := range issue.Labels {
 		name := label.GetName()
-		// Extract priority
-		if strings.HasPrefix(name, "priority:") {
-			priority = backend.Priority(strings.TrimPrefix(name, "priority:"))
+		// Extract priority; if several labels set one, the most urgent wins
+		if p, ok := g.parsePriorityLabel(name); ok {
+			if priorityOrder(p) < priorityOrder(priority) {
+				priority = p
+			}
 			continue
 		}
 		// Include all labels (status labels, agent labels, custom labels)
@@ -953,6 +1162,13 @@ func (g *GitHub) issueToTask(issue *gh.Issue) *backend.Task {
 	// Determine status from state and labels
 	task.Status = g.determineStatus(issue)
 
+	// A closed issue's close time stands in for when the work was completed
+	if task.Status == backend.StatusDone && issue.ClosedAt != nil {
+		completed := issue.GetClosedAt().Time
+		task.CompletedAt = &completed
+		task.TimeApproximate = true
+	}
+
 	// Store original issue number in meta
 	task.Meta["issue_number"] = issue.GetNumber()
 
@@ -1010,15 +1226,36 @@ func (g *GitHub) determineStatus(issue *gh.Issue) backend.Status {
 	return backend.StatusBacklog
 }
 
-// getAssigneeForClaim returns the username to assign when claiming.
-// This should be the authenticated user.
-func (g *GitHub) getAssigneeForClaim() string {
-	// Try to get the authenticated user
+// claimAssignee returns the login a claim by agentID assigns: the agent's
+// entry in the assignee map, or else the authenticated user. Map keys are
+// also matched lowercased, as the config loader lowercases them.
+func (g *GitHub) claimAssignee(agentID string) string {
+	if login, ok := g.assigneeMap[agentID]; ok {
+		return login
+	}
+	if login, ok := g.assigneeMap[strings.ToLower(agentID)]; ok {
+		return login
+	}
+	login, err := g.authenticatedLogin()
+	if err != nil {
+		return agentID // Fall back to agent ID
+	}
+	return login
+}
+
+// authenticatedLogin returns the login of the user the token belongs to.
+// It is fetched from /user on first use and cached for the connection, so
+// commands that never need it don't spend a request on it.
+func (g *GitHub) authenticatedLogin() (string, error) {
+	if g.login != "" {
+		return g.login, nil
+	}
 	user, _, err := g.client.Users.Get(g.ctx, "")
 	if err != nil {
-		return g.agentID // Fall back to agent ID
+		return "", fmt.Errorf("failed to get authenticated user: %w", err)
 	}
-	return user.GetLogin()
+	g.login = user.GetLogin()
+	return g.login, nil
 }
 
 // ClaimConflictError represents an error when a task is already claimed by another agent.
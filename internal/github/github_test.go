@@ -673,6 +673,26 @@ func TestAgentLabelPrefixCustom(t *testing.T) {
 	}
 }
 
+func TestClaimAssignee(t *testing.T) {
+	g := New()
+	g.login = "token-user"
+	g.assigneeMap = map[string]string{"claude-1": "claude-bot", "codex": "codex-bot"}
+
+	tests := []struct {
+		agentID string
+		want    string
+	}{
+		{"claude-1", "claude-bot"},
+		{"Codex", "codex-bot"}, // map keys are lowercased when config is loaded
+		{"unmapped", "token-user"},
+	}
+	for _, tt := range tests {
+		if got := g.claimAssignee(tt.agentID); got != tt.want {
+			t.Errorf("claimAssignee(%q) = %q, want %q", tt.agentID, got, tt.want)
+		}
+	}
+}
+
 func TestRegister(t *testing.T) {
 	// Clear any existing registration
 	backend.Unregister(Name)
@@ -737,6 +757,34 @@ func TestIssueToTaskPriorityExtraction(t *testing.T) {
 	}
 }
 
+func TestIssueToTaskCustomPriorityPrefix(t *testing.T) {
+	g := New()
+	g.statusMap = make(map[backend.Status]StatusMapping)
+	g.agentLabelPrefix = "agent"
+	g.priorityPrefix = "prio/"
+
+	issue := &gh.Issue{
+		Number: gh.Int(7),
+		Title:  gh.String("Test"),
+		State:  gh.String("open"),
+		Labels: []*gh.Label{
+			{Name: gh.String("prio/High")},
+			{Name: gh.String("priority:low")},
+			{Name: gh.String("prio/someday")},
+		},
+	}
+
+	task := g.issueToTask(issue)
+	if task.Priority != backend.PriorityHigh {
+		t.Errorf("Priority = %s, want %s", task.Priority, backend.PriorityHigh)
+	}
+	// Labels outside the configured convention stay ordinary labels
+	want := []string{"priority:low", "prio/someday"}
+	if len(task.Labels) != len(want) || task.Labels[0] != want[0] || task.Labels[1] != want[1] {
+		t.Errorf("Labels = %v, want %v", task.Labels, want)
+	}
+}
+
 func TestWorkspaceConfigFields(t *testing.T) {
 	wsCfg := &WorkspaceConfig{
 		Repo:        "owner/repo",
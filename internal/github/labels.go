@@ -0,0 +1,100 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	gh "github.com/google/go-github/v60/github"
+)
+
+// priorityLabelColors are the colors of the priority labels the backend
+// creates, from red for urgent to green for low.
+var priorityLabelColors = map[backend.Priority]string{
+	backend.PriorityUrgent: "b60205",
+	backend.PriorityHigh:   "d93f0b",
+	backend.PriorityMedium: "fbca04",
+	backend.PriorityLow:    "0e8a16",
+}
+
+// parsePriorityLabel reports whether a label sets the priority, such as
+// "priority:high", and returns that priority. Both the prefix and the
+// priority name are matched ignoring case. Labels that share the prefix but
+// name an unknown priority are treated as ordinary labels.
+func (g *GitHub) parsePriorityLabel(name string) (backend.Priority, bool) {
+	if len(name) < len(g.priorityPrefix) || !strings.EqualFold(name[:len(g.priorityPrefix)], g.priorityPrefix) {
+		return "", false
+	}
+	p := backend.Priority(strings.ToLower(name[len(g.priorityPrefix):]))
+	if !p.IsValid() {
+		return "", false
+	}
+	return p, true
+}
+
+// priorityLabel returns the label that sets priority p, or "" for none.
+func (g *GitHub) priorityLabel(p backend.Priority) string {
+	if p == "" || p == backend.PriorityNone {
+		return ""
+	}
+	return g.priorityPrefix + string(p)
+}
+
+// withPriority returns labels with every priority label replaced by the one
+// for p, so an issue never carries more than one priority.
+func (g *GitHub) withPriority(labels []string, p backend.Priority) []string {
+	result := make([]string, 0, len(labels)+1)
+	for _, l := range labels {
+		if _, ok := g.parsePriorityLabel(l); !ok {
+			result = append(result, l)
+		}
+	}
+	if label := g.priorityLabel(p); label != "" {
+		result = append(result, label)
+	}
+	return result
+}
+
+// lastPriorityLabel returns the priority named by the last priority label
+// in labels, if any.
+func (g *GitHub) lastPriorityLabel(labels []string) (backend.Priority, bool) {
+	var priority backend.Priority
+	found := false
+	for _, l := range labels {
+		if p, ok := g.parsePriorityLabel(l); ok {
+			priority, found = p, true
+		}
+	}
+	return priority, found
+}
+
+// ensurePriorityLabel creates the label for priority p in the repository if
+// it doesn't exist yet, giving it a color and description rather than the
+// gray default of a label created implicitly by adding it to an issue. This
+// is best effort: if the label can't be checked or created, e.g. because the
+// token lacks permission, the issue is still labeled.
+func (g *GitHub) ensurePriorityLabel(p backend.Priority) {
+	name := g.priorityLabel(p)
+	if name == "" || g.knownLabels[name] {
+		return
+	}
+
+	_, resp, err := g.client.Issues.GetLabel(g.ctx, g.owner, g.repo, url.PathEscape(name))
+	if err != nil && resp != nil && resp.StatusCode == http.StatusNotFound {
+		_, _, err = g.client.Issues.CreateLabel(g.ctx, g.owner, g.repo, &gh.Label{
+			Name:        gh.String(name),
+			Color:       gh.String(priorityLabelColors[p]),
+			Description: gh.String(fmt.Sprintf("Priority: %s", p)),
+		})
+	}
+	if err != nil {
+		return
+	}
+
+	if g.knownLabels == nil {
+		g.knownLabels = make(map[string]bool)
+	}
+	g.knownLabels[name] = true
+}
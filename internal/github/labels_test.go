@@ -0,0 +1,81 @@
+package github
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	gh "github.com/google/go-github/v60/github"
+)
+
+func TestParsePriorityLabel(t *testing.T) {
+	g := New()
+
+	tests := []struct {
+		label  string
+		want   backend.Priority
+		wantOK bool
+	}{
+		{"priority:high", backend.PriorityHigh, true},
+		{"Priority:HIGH", backend.PriorityHigh, true},
+		{"PRIORITY:urgent", backend.PriorityUrgent, true},
+		{"priority:p0", "", false},
+		{"priority:", "", false},
+		{"high-priority", "", false},
+		{"prio", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			got, ok := g.parsePriorityLabel(tt.label)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("parsePriorityLabel(%q) = %q, %v; want %q, %v", tt.label, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestWithPriority(t *testing.T) {
+	g := New()
+
+	tests := []struct {
+		name     string
+		labels   []string
+		priority backend.Priority
+		want     []string
+	}{
+		{"adds label", []string{"bug"}, backend.PriorityHigh, []string{"bug", "priority:high"}},
+		{"replaces label", []string{"priority:low", "bug"}, backend.PriorityHigh, []string{"bug", "priority:high"}},
+		{"replaces every priority label", []string{"Priority:Low", "priority:urgent"}, backend.PriorityMedium, []string{"priority:medium"}},
+		{"none removes label", []string{"bug", "priority:low"}, backend.PriorityNone, []string{"bug"}},
+		{"keeps unknown priorities", []string{"priority:p0"}, backend.PriorityLow, []string{"priority:p0", "priority:low"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.withPriority(tt.labels, tt.priority); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("withPriority(%v, %s) = %v, want %v", tt.labels, tt.priority, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssueToTaskMostUrgentPriorityLabelWins(t *testing.T) {
+	g := New()
+	g.statusMap = make(map[backend.Status]StatusMapping)
+
+	issue := &gh.Issue{
+		Number: gh.Int(9),
+		Title:  gh.String("Test"),
+		State:  gh.String("open"),
+		Labels: []*gh.Label{
+			{Name: gh.String("priority:low")},
+			{Name: gh.String("Priority:Urgent")},
+			{Name: gh.String("priority:medium")},
+		},
+	}
+
+	if task := g.issueToTask(issue); task.Priority != backend.PriorityUrgent {
+		t.Errorf("Priority = %s, want %s", task.Priority, backend.PriorityUrgent)
+	}
+}
@@ -0,0 +1,41 @@
+package github
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// parentMarkerPattern matches the "parent: #N" line that records a sub-task's
+// parent issue in its body. GitHub has no parent field on issues, so the
+// marker is kept on its own line at the end of the body.
+var parentMarkerPattern = regexp.MustCompile(`(?m)^parent: #(\d+)[ \t]*$`)
+
+// splitParentMarker separates the parent marker from an issue body, returning
+// the remaining description and the parent issue number (0 if none).
+func splitParentMarker(body string) (string, int) {
+	match := parentMarkerPattern.FindStringSubmatchIndex(body)
+	if match == nil {
+		return body, 0
+	}
+	num, err := strconv.Atoi(body[match[2]:match[3]])
+	if err != nil {
+		return body, 0
+	}
+	description := body[:match[0]] + body[match[1]:]
+	return strings.TrimSpace(description), num
+}
+
+// withParentMarker appends a parent marker for parentNum to description.
+// A parentNum of 0 returns the description unchanged.
+func withParentMarker(description string, parentNum int) string {
+	if parentNum == 0 {
+		return description
+	}
+	marker := fmt.Sprintf("parent: #%d", parentNum)
+	if description == "" {
+		return marker
+	}
+	return strings.TrimRight(description, "\n") + "\n\n" + marker
+}
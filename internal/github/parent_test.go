@@ -0,0 +1,44 @@
+package github
+
+import "testing"
+
+func TestSplitParentMarker(t *testing.T) {
+	tests := []struct {
+		name            string
+		body            string
+		wantDescription string
+		wantParent      int
+	}{
+		{"no marker", "Some description", "Some description", 0},
+		{"marker only", "parent: #12", "", 12},
+		{"marker after description", "Fix the thing\n\nparent: #7", "Fix the thing", 7},
+		{"marker with trailing space", "Body\n\nparent: #3  \n", "Body", 3},
+		{"inline mention is not a marker", "See parent: #5 for context", "See parent: #5 for context", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			description, parent := splitParentMarker(tt.body)
+			if description != tt.wantDescription || parent != tt.wantParent {
+				t.Errorf("splitParentMarker(%q) = %q, %d; want %q, %d", tt.body, description, parent, tt.wantDescription, tt.wantParent)
+			}
+		})
+	}
+}
+
+func TestWithParentMarkerRoundTrip(t *testing.T) {
+	for _, description := range []string{"", "Fix the thing", "Line one\nLine two\n"} {
+		body := withParentMarker(description, 42)
+		gotDescription, gotParent := splitParentMarker(body)
+		if gotParent != 42 {
+			t.Errorf("parent from %q = %d, want 42", body, gotParent)
+		}
+		if want := withParentMarker(gotDescription, 0); want != gotDescription {
+			t.Errorf("withParentMarker(%q, 0) = %q, want unchanged", gotDescription, want)
+		}
+	}
+
+	if got := withParentMarker("Body", 0); got != "Body" {
+		t.Errorf("withParentMarker without parent = %q, want %q", got, "Body")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/logging"
 	"github.com/shurcooL/githubv4"
 	"golang.org/x/oauth2"
 )
@@ -59,6 +60,7 @@ func NewProjectsClient(ctx context.Context, token, owner, repo string, projectNu
 
 	src := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
 	httpClient := oauth2.NewClient(ctx, src)
+	httpClient.Transport = logging.NewTransport(httpClient.Transport)
 
 	var client *githubv4.Client
 	if apiURL != "" {
@@ -632,6 +634,7 @@ func DefaultStatusOptions() []StatusOption {
 func newGraphQLClient(ctx context.Context, token, apiURL string) *githubv4.Client {
 	src := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
 	httpClient := oauth2.NewClient(ctx, src)
+	httpClient.Transport = logging.NewTransport(httpClient.Transport)
 
 	if apiURL != "" {
 		graphqlURL := apiURL
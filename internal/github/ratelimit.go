@@ -0,0 +1,184 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+const (
+	// rateLimitWarnThreshold is the number of remaining requests below which
+	// a warning is logged, once per run and resource.
+	rateLimitWarnThreshold = 100
+
+	// maxRateLimitWait bounds how long a request waits for an exhausted rate
+	// limit to reset. The wait also counts against the request timeout.
+	maxRateLimitWait = time.Minute
+
+	// coreResource is the rate limit resource of the REST API.
+	coreResource = "core"
+)
+
+// RateLimitError is returned when the GitHub API rate limit is exhausted and
+// the request could not wait for it to reset.
+type RateLimitError struct {
+	Resource string
+	Limit    int
+	Reset    time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("github API rate limit exhausted (%d %s requests per hour); resets at %s",
+		e.Limit, e.Resource, e.Reset.Local().Format(time.Kitchen))
+}
+
+// rateLimiter tracks the rate limits reported in GitHub's X-RateLimit-*
+// response headers, per resource.
+type rateLimiter struct {
+	// noWait fails a request instead of waiting when its limit is exhausted.
+	noWait bool
+
+	mu     sync.Mutex
+	limits map[string]backend.RateLimit
+	warned map[string]bool
+}
+
+func newRateLimiter(noWait bool) *rateLimiter {
+	return &rateLimiter{
+		noWait: noWait,
+		limits: make(map[string]backend.RateLimit),
+		warned: make(map[string]bool),
+	}
+}
+
+// latest returns the last rate limit reported for a resource.
+func (l *rateLimiter) latest(resource string) (backend.RateLimit, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	limit, ok := l.limits[resource]
+	return limit, ok
+}
+
+// update records the rate limit reported in a response, logging a warning
+// the first time the remaining requests drop below rateLimitWarnThreshold.
+// Responses without rate limit headers are ignored.
+func (l *rateLimiter) update(resource string, header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	limit, _ := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	var reset time.Time
+	if secs, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		reset = time.Unix(secs, 0)
+	}
+	if r := header.Get("X-RateLimit-Resource"); r != "" {
+		resource = r
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limits[resource] = backend.RateLimit{Limit: limit, Remaining: remaining, Reset: reset}
+	if remaining < rateLimitWarnThreshold && !l.warned[resource] {
+		l.warned[resource] = true
+		slog.Warn("github API rate limit is running low", "resource", resource,
+			"remaining", remaining, "limit", limit, "reset", reset.Local().Format(time.Kitchen))
+	}
+}
+
+// wait blocks until the rate limit of a resource has reset if it is
+// exhausted. It fails with a *RateLimitError instead when waiting is
+// disabled, or the reset is further away than maxRateLimitWait or the
+// request deadline.
+func (l *rateLimiter) wait(ctx context.Context, resource string) error {
+	limit, ok := l.latest(resource)
+	if !ok || limit.Remaining > 0 || limit.Reset.IsZero() {
+		return nil
+	}
+	delay := time.Until(limit.Reset)
+	if delay <= 0 {
+		return nil
+	}
+
+	bound := maxRateLimitWait
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < bound {
+		bound = time.Until(deadline)
+	}
+	if l.noWait || delay > bound {
+		return &RateLimitError{Resource: resource, Limit: limit.Limit, Reset: limit.Reset}
+	}
+
+	slog.Warn("github API rate limit exhausted, waiting for it to reset", "resource", resource, "wait", delay.Round(time.Second))
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimitTransport records GitHub's rate limit headers and holds requests
+// while the limit is exhausted. A request rejected because the limit ran out
+// is retried once after the reset, since GitHub didn't process it.
+type rateLimitTransport struct {
+	base   http.RoundTripper
+	limits *rateLimiter
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The issue endpoints backlog calls all count against the core limit
+	resource := coreResource
+	if err := t.limits.wait(req.Context(), resource); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	t.limits.update(resource, resp.Header)
+
+	if isRateLimited(resp) && (req.Body == nil || req.GetBody != nil) {
+		// Drain and close the body so the connection can be reused
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if err := t.limits.wait(req.Context(), resource); err != nil {
+			return nil, err
+		}
+
+		retry := req.Clone(req.Context())
+		if req.GetBody != nil {
+			if retry.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+		if resp, err = t.base.RoundTrip(retry); err != nil {
+			return nil, err
+		}
+		t.limits.update(resource, resp.Header)
+	}
+
+	// The limiter enforces the limit, so go-github mustn't also refuse
+	// requests on its own until the reset
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" && !isRateLimited(resp) {
+		resp.Header.Del("X-RateLimit-Reset")
+	}
+	return resp, nil
+}
+
+// isRateLimited reports whether a response was rejected because the rate
+// limit is exhausted.
+func isRateLimited(resp *http.Response) bool {
+	return (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests) &&
+		resp.Header.Get("X-RateLimit-Remaining") == "0"
+}
@@ -0,0 +1,141 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rateLimitResponse is a canned response and the rate limit it leaves.
+type rateLimitResponse struct {
+	status    int
+	remaining int
+}
+
+// rateLimitServer responds with the given responses in turn, repeating the
+// last one, and reports how many requests it received.
+func rateLimitServer(t *testing.T, reset time.Time, responses ...rateLimitResponse) (*httptest.Server, *int) {
+	t.Helper()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := responses[min(requests, len(responses)-1)]
+		requests++
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(resp.remaining))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(resp.status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func doRateLimited(t *testing.T, limits *rateLimiter, method, url string) (*http.Response, error) {
+	t.Helper()
+	client := &http.Client{Transport: &rateLimitTransport{base: http.DefaultTransport, limits: limits}}
+	req, err := http.NewRequest(method, url, strings.NewReader(`{"title":"x"}`))
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	resp, err := client.Do(req)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	return resp, err
+}
+
+func TestRateLimitTransportRecordsLimits(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	server, _ := rateLimitServer(t, reset, rateLimitResponse{http.StatusOK, 4321})
+	limits := newRateLimiter(false)
+
+	if _, err := doRateLimited(t, limits, http.MethodGet, server.URL+"/repos/o/r"); err != nil {
+		t.Fatalf("request error = %v", err)
+	}
+
+	got, ok := limits.latest(coreResource)
+	if !ok {
+		t.Fatal("latest() found no rate limit")
+	}
+	if got.Limit != 5000 || got.Remaining != 4321 || !got.Reset.Equal(reset) {
+		t.Errorf("latest() = %+v, want 4321/5000 resetting at %v", got, reset)
+	}
+}
+
+func TestRateLimitTransportNoWait(t *testing.T) {
+	reset := time.Now().Add(time.Hour)
+	server, requests := rateLimitServer(t, reset, rateLimitResponse{http.StatusForbidden, 0})
+	limits := newRateLimiter(true)
+
+	_, err := doRateLimited(t, limits, http.MethodGet, server.URL+"/repos/o/r")
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("error = %v, want *RateLimitError", err)
+	}
+	if rateErr.Limit != 5000 || rateErr.Reset.Unix() != reset.Unix() {
+		t.Errorf("RateLimitError = %+v", rateErr)
+	}
+
+	// Later requests fail without reaching the API
+	if _, err := doRateLimited(t, limits, http.MethodGet, server.URL+"/repos/o/r"); !errors.As(err, &rateErr) {
+		t.Errorf("second request error = %v, want *RateLimitError", err)
+	}
+	if *requests != 1 {
+		t.Errorf("requests = %d, want 1", *requests)
+	}
+}
+
+func TestRateLimitTransportWaitsForReset(t *testing.T) {
+	reset := time.Now().Add(time.Second).Truncate(time.Second)
+	server, requests := rateLimitServer(t, reset,
+		rateLimitResponse{http.StatusForbidden, 0},
+		rateLimitResponse{http.StatusCreated, 4999},
+	)
+	limits := newRateLimiter(false)
+
+	// A rejected POST is retried with its body once the limit resets
+	resp, err := doRateLimited(t, limits, http.MethodPost, server.URL+"/repos/o/r/issues")
+	if err != nil {
+		t.Fatalf("request error = %v", err)
+	}
+	if resp.StatusCode != http.StatusCreated || *requests != 2 {
+		t.Errorf("status %d after %d requests, want 201 after 2", resp.StatusCode, *requests)
+	}
+	if time.Now().Before(reset) {
+		t.Error("request was retried before the reset")
+	}
+}
+
+func TestRateLimitTransportBoundsWait(t *testing.T) {
+	server, requests := rateLimitServer(t, time.Now().Add(time.Hour), rateLimitResponse{http.StatusOK, 0})
+	limits := newRateLimiter(false)
+
+	// The last request of the window succeeds, and go-github isn't told the
+	// reset so it doesn't refuse later requests itself
+	resp, err := doRateLimited(t, limits, http.MethodGet, server.URL+"/repos/o/r")
+	if err != nil {
+		t.Fatalf("request error = %v", err)
+	}
+	if resp.Header.Get("X-RateLimit-Reset") != "" {
+		t.Errorf("X-RateLimit-Reset = %q, want it removed", resp.Header.Get("X-RateLimit-Reset"))
+	}
+
+	// The reset is further away than the wait is allowed to take
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var rateErr *RateLimitError
+	if err := limits.wait(ctx, coreResource); !errors.As(err, &rateErr) {
+		t.Errorf("wait() error = %v, want *RateLimitError", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("wait() took %v, want it to fail fast", time.Since(start))
+	}
+	if *requests != 1 {
+		t.Errorf("requests = %d, want 1", *requests)
+	}
+}
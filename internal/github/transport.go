@@ -0,0 +1,83 @@
+package github
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	// defaultTimeout bounds each GitHub API request, including retries.
+	defaultTimeout = 30 * time.Second
+
+	// maxRetries is the number of times an idempotent request is retried
+	// after the GitHub API responds with a 5xx status.
+	maxRetries = 2
+
+	// retryBackoff is the base delay between retries. The delay grows
+	// linearly with each attempt.
+	retryBackoff = 200 * time.Millisecond
+)
+
+// APIError is returned when the GitHub API responds with a server error
+// that could not be recovered by retrying.
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Status     string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("github API error: %s %s returned %s", e.Method, e.Path, e.Status)
+}
+
+// retryTransport retries idempotent requests that fail with a 5xx status and
+// converts unrecovered server errors into an *APIError.
+type retryTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attempts := 1
+	if isIdempotent(req.Method) {
+		attempts += maxRetries
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode < http.StatusInternalServerError {
+			return resp, nil
+		}
+
+		// Drain and close the body so the connection can be reused
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if attempt >= attempts {
+			return nil, &APIError{
+				Method:     req.Method,
+				Path:       req.URL.Path,
+				StatusCode: resp.StatusCode,
+				Status:     resp.Status,
+			}
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(time.Duration(attempt) * retryBackoff):
+		}
+	}
+}
+
+// isIdempotent reports whether a request with the given method can be safely
+// retried without risking duplicate side effects.
+func isIdempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
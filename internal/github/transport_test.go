@@ -0,0 +1,65 @@
+package github
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		failures     int
+		wantRequests int
+		wantErr      bool
+	}{
+		{name: "GET recovers after transient failures", method: http.MethodGet, failures: 2, wantRequests: 3},
+		{name: "GET gives up after max retries", method: http.MethodGet, failures: 5, wantRequests: 3, wantErr: true},
+		{name: "POST is not retried", method: http.MethodPost, failures: 1, wantRequests: 1, wantErr: true},
+		{name: "success needs no retry", method: http.MethodGet, failures: 0, wantRequests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests <= tt.failures {
+					w.WriteHeader(http.StatusBadGateway)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client := &http.Client{Transport: &retryTransport{base: http.DefaultTransport}}
+			req, err := http.NewRequest(tt.method, server.URL+"/repos/o/r/issues", strings.NewReader(""))
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+
+			resp, err := client.Do(req)
+			if resp != nil {
+				resp.Body.Close()
+			}
+
+			if requests != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", requests, tt.wantRequests)
+			}
+			if tt.wantErr {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) {
+					t.Fatalf("error = %v, want *APIError", err)
+				}
+				if apiErr.StatusCode != http.StatusBadGateway || apiErr.Method != tt.method || apiErr.Path != "/repos/o/r/issues" {
+					t.Errorf("APIError = %+v", apiErr)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
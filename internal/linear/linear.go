@@ -9,14 +9,17 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/alexbrand/backlog/internal/backend"
 	"github.com/alexbrand/backlog/internal/credentials"
+	"github.com/alexbrand/backlog/internal/logging"
 )
 
 const (
@@ -28,6 +31,11 @@ const (
 
 	// defaultLinearAPIEndpoint is the default Linear GraphQL API endpoint.
 	defaultLinearAPIEndpoint = "https://api.linear.app/graphql"
+
+	// countPageSize is the number of issues fetched per request when
+	// counting. Count fetches only each issue's state, so it uses Linear's
+	// largest page.
+	countPageSize = 250
 )
 
 // Priority mapping from Linear's numeric priority (0-4) to canonical priority.
@@ -48,6 +56,10 @@ var canonicalPriorityToLinear = map[backend.Priority]int{
 	backend.PriorityLow:    4,
 }
 
+// estimatePointDuration is the amount of time represented by one Linear estimate point.
+// Durations are rounded up to whole points when written to Linear.
+const estimatePointDuration = backend.Day
+
 // Default status mappings from Linear workflow state names to canonical statuses.
 // These are common names used in Linear - can be overridden via workspace config.
 var defaultStatusMapping = map[string]backend.Status{
@@ -69,21 +81,29 @@ type WorkspaceConfig struct {
 	TeamKey string
 	// StatusMap allows custom status-to-workflow-state mappings.
 	StatusMap map[backend.Status]string
+	// IdempotencyWindow is how far back Create looks for an issue made with
+	// the same idempotency key. Zero means backend.DefaultIdempotencyWindow.
+	IdempotencyWindow time.Duration
+	// CachePath is the directory Sync mirrors issues into for offline reading.
+	CachePath string
 }
 
 // Linear implements the Backend interface using Linear Issues.
 type Linear struct {
-	client           *http.Client
-	apiKey           string
-	apiEndpoint      string
-	teamKey          string
-	teamID           string
-	agentID          string
-	agentLabelPrefix string
-	statusMap        map[backend.Status]string
-	reverseStatusMap map[string]backend.Status
-	connected        bool
-	ctx              context.Context
+	client            *http.Client
+	apiKey            string
+	apiEndpoint       string
+	teamKey           string
+	teamID            string
+	agentID           string
+	agentLabelPrefix  string
+	statusMap         map[backend.Status]string
+	reverseStatusMap  map[string]backend.Status
+	idempotencyWindow time.Duration
+	cachePath         string
+	users             []linearUser // fetched once by listUsers
+	connected         bool
+	ctx               context.Context
 }
 
 // New creates a new Linear backend instance.
@@ -95,7 +115,7 @@ func New() *Linear {
 	}
 	return &Linear{
 		ctx:         context.Background(),
-		client:      &http.Client{Timeout: 30 * time.Second},
+		client:      &http.Client{Timeout: 30 * time.Second, Transport: logging.NewTransport(nil)},
 		apiEndpoint: apiEndpoint,
 	}
 }
@@ -118,6 +138,11 @@ func (l *Linear) Connect(cfg backend.Config) error {
 	}
 
 	l.teamKey = wsCfg.TeamKey
+	l.cachePath = wsCfg.CachePath
+	l.idempotencyWindow = wsCfg.IdempotencyWindow
+	if l.idempotencyWindow <= 0 {
+		l.idempotencyWindow = backend.DefaultIdempotencyWindow
+	}
 	l.agentID = cfg.AgentID
 	l.agentLabelPrefix = cfg.AgentLabelPrefix
 	if l.agentLabelPrefix == "" {
@@ -146,8 +171,8 @@ func (l *Linear) Connect(cfg backend.Config) error {
 		l.statusMap[backend.StatusDone] = "Done"
 	}
 
-	// Get API key from credentials (env var or credentials.yaml)
-	apiKey, err := credentials.GetLinearAPIKey()
+	// Get API key from credentials (env var, credential stores, or credentials.yaml)
+	apiKey, err := credentials.GetLinearAPIKeyFor(cfg.WorkspaceName)
 	if err != nil {
 		return err
 	}
@@ -213,19 +238,23 @@ func (l *Linear) List(filters backend.TaskFilters) (*backend.TaskList, error) {
 
 	// Build GraphQL query with filters
 	query := `
-		query ListIssues($first: Int, $filter: IssueFilter) {
-			issues(first: $first, filter: $filter) {
+		query ListIssues($first: Int, $filter: IssueFilter, $includeArchived: Boolean) {
+			issues(first: $first, filter: $filter, includeArchived: $includeArchived) {
 				nodes {
 					id
 					identifier
 					title
 					description
 					priority
+					estimate
 					sortOrder
 					prioritySortOrder
 					url
 					createdAt
 					updatedAt
+					archivedAt
+					startedAt
+					completedAt
 					state {
 						id
 						name
@@ -234,6 +263,7 @@ func (l *Linear) List(filters backend.TaskFilters) (*backend.TaskList, error) {
 						id
 						name
 						displayName
+						email
 					}
 					labels {
 						nodes {
@@ -245,6 +275,9 @@ func (l *Linear) List(filters backend.TaskFilters) (*backend.TaskList, error) {
 						id
 						key
 					}
+					parent {
+						identifier
+					}
 				}
 				pageInfo {
 					hasNextPage
@@ -253,53 +286,14 @@ func (l *Linear) List(filters backend.TaskFilters) (*backend.TaskList, error) {
 		}
 	`
 
-	// Build filter
-	filter := make(map[string]any)
-
-	// Team filter
-	if l.teamID != "" {
-		filter["team"] = map[string]any{"id": map[string]any{"eq": l.teamID}}
-	}
-
-	// Assignee filter
-	if filters.Assignee != "" {
-		if filters.Assignee == "@me" {
-			filter["assignee"] = map[string]any{"isMe": map[string]any{"eq": true}}
-		} else if filters.Assignee == "unassigned" {
-			filter["assignee"] = map[string]any{"null": true}
-		}
-		// Note: filtering by specific assignee name would require looking up the user ID first
-	}
-
-	// Label filter
-	if len(filters.Labels) > 0 {
-		labelFilters := make([]map[string]any, len(filters.Labels))
-		for i, label := range filters.Labels {
-			labelFilters[i] = map[string]any{"name": map[string]any{"eq": label}}
-		}
-		if len(labelFilters) == 1 {
-			filter["labels"] = labelFilters[0]
-		} else {
-			filter["labels"] = map[string]any{"and": labelFilters}
-		}
-	}
-
-	// Priority filter
-	if len(filters.Priority) > 0 {
-		priorities := make([]int, 0, len(filters.Priority))
-		for _, p := range filters.Priority {
-			if lp, ok := canonicalPriorityToLinear[p]; ok {
-				priorities = append(priorities, lp)
-			}
-		}
-		if len(priorities) > 0 {
-			filter["priority"] = map[string]any{"in": priorities}
-		}
+	filter, fallback, err := l.issueFilter(filters)
+	if err != nil {
+		return nil, err
 	}
 
-	// Limit
+	// Limit; issues matched by assignee here can't be limited by the query
 	first := 100
-	if filters.Limit > 0 && filters.Limit < 100 {
+	if filters.Limit > 0 && filters.Limit < 100 && fallback == nil {
 		first = filters.Limit
 	}
 
@@ -309,6 +303,10 @@ func (l *Linear) List(filters backend.TaskFilters) (*backend.TaskList, error) {
 	if len(filter) > 0 {
 		variables["filter"] = filter
 	}
+	// Soft-deleted tasks are archived issues
+	if filters.IncludeDeleted {
+		variables["includeArchived"] = true
+	}
 
 	result, err := l.graphQL(query, variables)
 	if err != nil {
@@ -340,30 +338,18 @@ func (l *Linear) List(filters backend.TaskFilters) (*backend.TaskList, error) {
 		if !ok {
 			continue
 		}
-
-		task := l.issueToTask(issue)
-
-		// Apply status filter (done after mapping since we need to map state names)
-		if len(filters.Status) > 0 {
-			found := false
-			for _, s := range filters.Status {
-				if task.Status == s {
-					found = true
-					break
-				}
-			}
-			if !found {
-				continue
-			}
+		if fallback != nil && !fallback.matches(issue) {
+			continue
 		}
 
-		// Exclude done unless explicitly included
-		if !filters.IncludeDone && task.Status == backend.StatusDone {
+		task := l.issueToTask(issue)
+		if !matchesStatusFilters(task.Status, filters) {
 			continue
 		}
 
 		tasks = append(tasks, *task)
 	}
+	slog.Debug("evaluated list filters", "backend", Name, "fetched", len(nodes), "matched", len(tasks))
 
 	// Sort by priority first (urgent > high > medium > low > none),
 	// then by prioritySortOrder ascending within each priority group (lower = top of board).
@@ -388,11 +374,235 @@ func (l *Linear) List(filters backend.TaskFilters) (*backend.TaskList, error) {
 		hasMore = true
 	}
 
-	return &backend.TaskList{
+	list := &backend.TaskList{
 		Tasks:   tasks,
 		Count:   len(tasks),
 		HasMore: hasMore,
-	}, nil
+	}
+	if fallback != nil {
+		list.Warnings = append(list.Warnings, fallback.warning)
+	}
+	return list, nil
+}
+
+// Count returns the number of issues matching the given filters, ignoring
+// Limit. Linear's API has no filtered count, so it pages through the
+// matching issues fetching only their state, which the status filters need.
+// Implements the backend.Counter interface.
+func (l *Linear) Count(filters backend.TaskFilters) (int, error) {
+	if !l.connected {
+		return 0, errors.New("not connected")
+	}
+
+	query := `
+		query CountIssues($first: Int, $after: String, $filter: IssueFilter, $includeArchived: Boolean) {
+			issues(first: $first, after: $after, filter: $filter, includeArchived: $includeArchived) {
+				nodes {
+					state {
+						name
+					}
+					assignee {
+						name
+						displayName
+						email
+					}
+				}
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+			}
+		}
+	`
+
+	filter, fallback, err := l.issueFilter(filters)
+	if err != nil {
+		return 0, err
+	}
+	variables := map[string]any{"first": countPageSize}
+	if len(filter) > 0 {
+		variables["filter"] = filter
+	}
+	if filters.IncludeDeleted {
+		variables["includeArchived"] = true
+	}
+
+	count, fetched := 0, 0
+	for {
+		result, err := l.graphQL(query, variables)
+		if err != nil {
+			return 0, fmt.Errorf("failed to count issues: %w", err)
+		}
+
+		data, _ := result["data"].(map[string]any)
+		issuesData, ok := data["issues"].(map[string]any)
+		if !ok {
+			return 0, errors.New("unexpected response format: missing issues")
+		}
+		nodes, _ := issuesData["nodes"].([]any)
+		fetched += len(nodes)
+
+		for _, node := range nodes {
+			issue, ok := node.(map[string]any)
+			if !ok || (fallback != nil && !fallback.matches(issue)) {
+				continue
+			}
+			status := backend.StatusBacklog // Default for unknown states
+			if state, ok := issue["state"].(map[string]any); ok {
+				if mapped, ok := l.reverseStatusMap[strings.ToLower(getString(state, "name"))]; ok {
+					status = mapped
+				}
+			}
+			if matchesStatusFilters(status, filters) {
+				count++
+			}
+		}
+
+		pageInfo, _ := issuesData["pageInfo"].(map[string]any)
+		if hasNext, _ := pageInfo["hasNextPage"].(bool); !hasNext {
+			break
+		}
+		next := getString(pageInfo, "endCursor")
+		if next == "" {
+			return 0, errors.New("unexpected response format: missing endCursor")
+		}
+		variables["after"] = next
+	}
+
+	slog.Debug("counted issues", "backend", Name, "fetched", fetched, "matched", count)
+	return count, nil
+}
+
+// issueFilter builds the Linear IssueFilter for the given filters. Statuses
+// are matched after mapping state names, so they aren't part of it. A named
+// assignee is looked up to filter by user ID; if the users can't be listed,
+// the returned fallback matches the name against each issue's assignee
+// instead, and carries a warning for the task list.
+func (l *Linear) issueFilter(filters backend.TaskFilters) (map[string]any, *assigneeFallback, error) {
+	filter := make(map[string]any)
+
+	// Team filter
+	if l.teamID != "" {
+		filter["team"] = map[string]any{"id": map[string]any{"eq": l.teamID}}
+	}
+
+	// Assignee filter
+	var fallback *assigneeFallback
+	switch filters.Assignee {
+	case "":
+	case "@me":
+		filter["assignee"] = map[string]any{"isMe": map[string]any{"eq": true}}
+	case "unassigned":
+		filter["assignee"] = map[string]any{"null": true}
+	default:
+		userID, err := l.getUserID(filters.Assignee)
+		var notFound *UserNotFoundError
+		switch {
+		case err == nil:
+			filter["assignee"] = map[string]any{"id": map[string]any{"eq": userID}}
+		case errors.As(err, &notFound):
+			return nil, nil, err
+		default:
+			fallback = &assigneeFallback{
+				name:    filters.Assignee,
+				warning: fmt.Sprintf("could not look up Linear users (%v); matching assignee %q by name", err, filters.Assignee),
+			}
+		}
+	}
+
+	// Label filter
+	if len(filters.Labels) > 0 {
+		labelFilters := make([]map[string]any, len(filters.Labels))
+		for i, label := range filters.Labels {
+			labelFilters[i] = map[string]any{"name": map[string]any{"eq": label}}
+		}
+		if len(labelFilters) == 1 {
+			filter["labels"] = labelFilters[0]
+		} else {
+			filter["labels"] = map[string]any{"and": labelFilters}
+		}
+	}
+
+	// Priority filter
+	if len(filters.Priority) > 0 {
+		priorities := make([]int, 0, len(filters.Priority))
+		for _, p := range filters.Priority {
+			if lp, ok := canonicalPriorityToLinear[p]; ok {
+				priorities = append(priorities, lp)
+			}
+		}
+		if len(priorities) > 0 {
+			filter["priority"] = map[string]any{"in": priorities}
+		}
+	}
+
+	// Parent filter
+	if filters.Parent != "" {
+		parentID, err := l.getLinearID(filters.Parent)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parent issue not found: %w", err)
+		}
+		filter["parent"] = map[string]any{"id": map[string]any{"eq": parentID}}
+	}
+
+	// Time filters
+	if !filters.CreatedSince.IsZero() {
+		filter["createdAt"] = map[string]any{"gte": filters.CreatedSince.UTC().Format(time.RFC3339)}
+	}
+	if !filters.UpdatedSince.IsZero() {
+		filter["updatedAt"] = map[string]any{"gte": filters.UpdatedSince.UTC().Format(time.RFC3339)}
+	}
+
+	// Comments filter
+	if filters.HasComments != nil {
+		if *filters.HasComments {
+			filter["comments"] = map[string]any{"some": map[string]any{}}
+		} else {
+			filter["comments"] = map[string]any{"length": map[string]any{"eq": 0}}
+		}
+	}
+
+	return filter, fallback, nil
+}
+
+// assigneeFallback filters issues by assignee name on the client, for when
+// the named user couldn't be looked up.
+type assigneeFallback struct {
+	name    string
+	warning string
+}
+
+// matches reports whether the issue's assignee has the name, display name
+// or email, ignoring case.
+func (f *assigneeFallback) matches(issue map[string]any) bool {
+	assignee, ok := issue["assignee"].(map[string]any)
+	if !ok {
+		return false
+	}
+	for _, field := range []string{"name", "displayName", "email"} {
+		if strings.EqualFold(getString(assignee, field), f.name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesStatusFilters reports whether a task with the given status passes
+// the status filters, which exclude done tasks unless explicitly included.
+func matchesStatusFilters(status backend.Status, filters backend.TaskFilters) bool {
+	if len(filters.Status) > 0 {
+		found := false
+		for _, s := range filters.Status {
+			if status == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return filters.IncludeDone || status != backend.StatusDone
 }
 
 // Get returns a single task by ID.
@@ -412,9 +622,12 @@ func (l *Linear) Get(id string) (*backend.Task, error) {
 				title
 				description
 				priority
+				estimate
 				url
 				createdAt
 				updatedAt
+				startedAt
+				completedAt
 				state {
 					id
 					name
@@ -434,13 +647,16 @@ func (l *Linear) Get(id string) (*backend.Task, error) {
 					id
 					key
 				}
+				parent {
+					identifier
+				}
 			}
 		}
 	`
 
 	result, err := l.graphQL(query, map[string]any{"id": issueID})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get issue: %w", err)
+		return nil, issueError(id, "failed to get issue", err)
 	}
 
 	data, ok := result["data"].(map[string]any)
@@ -450,12 +666,27 @@ func (l *Linear) Get(id string) (*backend.Task, error) {
 
 	issue, ok := data["issue"].(map[string]any)
 	if !ok || issue == nil {
-		return nil, fmt.Errorf("issue %s not found", id)
+		return nil, &backend.NotFoundError{ID: id}
 	}
 
 	return l.issueToTask(issue), nil
 }
 
+// GetRaw returns the issue as returned by the Linear API, pretty-printed as JSON.
+// Implements the backend.RawGetter interface.
+func (l *Linear) GetRaw(id string) ([]byte, error) {
+	if !l.connected {
+		return nil, errors.New("not connected")
+	}
+
+	issue, err := l.getIssueByIdentifier(l.normalizeID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(issue, "", "  ")
+}
+
 // Create creates a new task and returns it.
 func (l *Linear) Create(input backend.TaskInput) (*backend.Task, error) {
 	if !l.connected {
@@ -465,6 +696,25 @@ func (l *Linear) Create(input backend.TaskInput) (*backend.Task, error) {
 	if l.teamID == "" {
 		return nil, errors.New("team not configured - set 'team' in workspace config")
 	}
+	if input.ID != "" {
+		return nil, errors.New("the linear backend does not support pinned task IDs")
+	}
+
+	// Linear has no idempotency support, so the key is kept in a hidden
+	// marker in the description and searched for before creating
+	if input.IdempotencyKey != "" {
+		task, err := l.findIdempotentIssue(input.IdempotencyKey)
+		if err != nil {
+			return nil, err
+		}
+		if task != nil {
+			return task, nil
+		}
+	}
+
+	if task, err := backend.LookupExisting(input, l.List); err != nil || task != nil {
+		return task, err
+	}
 
 	mutation := `
 		mutation CreateIssue($input: IssueCreateInput!) {
@@ -476,6 +726,7 @@ func (l *Linear) Create(input backend.TaskInput) (*backend.Task, error) {
 					title
 					description
 					priority
+					estimate
 					url
 					createdAt
 					updatedAt
@@ -498,6 +749,9 @@ func (l *Linear) Create(input backend.TaskInput) (*backend.Task, error) {
 						id
 						key
 					}
+					parent {
+						identifier
+					}
 				}
 			}
 		}
@@ -511,6 +765,9 @@ func (l *Linear) Create(input backend.TaskInput) (*backend.Task, error) {
 	if input.Description != "" {
 		issueInput["description"] = input.Description
 	}
+	if input.IdempotencyKey != "" {
+		issueInput["description"] = strings.TrimLeft(input.Description+"\n\n"+idempotencyMarker(input.IdempotencyKey), "\n")
+	}
 
 	// Set priority
 	if input.Priority != "" && input.Priority != backend.PriorityNone {
@@ -535,6 +792,15 @@ func (l *Linear) Create(input backend.TaskInput) (*backend.Task, error) {
 		}
 	}
 
+	// Set parent issue
+	if input.Parent != "" {
+		parentID, err := l.getLinearID(input.Parent)
+		if err != nil {
+			return nil, fmt.Errorf("parent issue not found: %w", err)
+		}
+		issueInput["parentId"] = parentID
+	}
+
 	result, err := l.graphQL(mutation, map[string]any{"input": issueInput})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create issue: %w", err)
@@ -592,6 +858,7 @@ func (l *Linear) Update(id string, changes backend.TaskChanges) (*backend.Task,
 					title
 					description
 					priority
+					estimate
 					url
 					createdAt
 					updatedAt
@@ -614,6 +881,9 @@ func (l *Linear) Update(id string, changes backend.TaskChanges) (*backend.Task,
 						id
 						key
 					}
+					parent {
+						identifier
+					}
 				}
 			}
 		}
@@ -635,6 +905,38 @@ func (l *Linear) Update(id string, changes backend.TaskChanges) (*backend.Task,
 		}
 	}
 
+	if changes.Assignee != nil {
+		if *changes.Assignee == "" {
+			issueInput["assigneeId"] = nil
+		} else {
+			userID, err := l.getUserID(*changes.Assignee)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find user %s: %w", *changes.Assignee, err)
+			}
+			issueInput["assigneeId"] = userID
+		}
+	}
+
+	if changes.Estimate != nil {
+		issueInput["estimate"] = durationToEstimatePoints(*changes.Estimate)
+	}
+
+	if changes.Spent != nil {
+		return nil, errors.New("linear backend does not support tracking spent time")
+	}
+
+	if changes.Parent != nil {
+		if *changes.Parent == "" {
+			issueInput["parentId"] = nil
+		} else {
+			parentID, err := l.getLinearID(*changes.Parent)
+			if err != nil {
+				return nil, fmt.Errorf("parent issue not found: %w", err)
+			}
+			issueInput["parentId"] = parentID
+		}
+	}
+
 	// Handle label changes
 	if len(changes.AddLabels) > 0 || len(changes.RemoveLabels) > 0 {
 		// Get current label IDs
@@ -766,6 +1068,62 @@ func (l *Linear) Delete(id string) error {
 	return nil
 }
 
+// SoftDelete archives the issue. Linear has no permanent deletion, so this
+// is the same as Delete. Implements the backend.SoftDeleter interface.
+func (l *Linear) SoftDelete(id string) error {
+	return l.Delete(id)
+}
+
+// Restore unarchives an archived issue.
+// Implements the backend.SoftDeleter interface.
+func (l *Linear) Restore(id string) (*backend.Task, error) {
+	if !l.connected {
+		return nil, errors.New("not connected")
+	}
+
+	issueID := l.normalizeID(id)
+
+	issue, err := l.getIssueByIdentifier(issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	linearID, ok := issue["id"].(string)
+	if !ok {
+		return nil, errors.New("failed to get issue ID")
+	}
+
+	mutation := `
+		mutation UnarchiveIssue($id: String!) {
+			issueUnarchive(id: $id) {
+				success
+			}
+		}
+	`
+
+	result, err := l.graphQL(mutation, map[string]any{"id": linearID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to unarchive issue: %w", err)
+	}
+
+	data, ok := result["data"].(map[string]any)
+	if !ok {
+		return nil, errors.New("unexpected response format")
+	}
+
+	unarchiveResult, ok := data["issueUnarchive"].(map[string]any)
+	if !ok {
+		return nil, errors.New("unexpected response format: missing issueUnarchive")
+	}
+
+	success, _ := unarchiveResult["success"].(bool)
+	if !success {
+		return nil, errors.New("failed to unarchive issue")
+	}
+
+	return l.Get(issueID)
+}
+
 // Move transitions a task to a new status.
 func (l *Linear) Move(id string, status backend.Status) (*backend.Task, error) {
 	if !l.connected {
@@ -805,6 +1163,7 @@ func (l *Linear) Move(id string, status backend.Status) (*backend.Task, error) {
 					title
 					description
 					priority
+					estimate
 					url
 					createdAt
 					updatedAt
@@ -827,6 +1186,9 @@ func (l *Linear) Move(id string, status backend.Status) (*backend.Task, error) {
 						id
 						key
 					}
+					parent {
+						identifier
+					}
 				}
 			}
 		}
@@ -898,6 +1260,7 @@ func (l *Linear) Assign(id string, assignee string) (*backend.Task, error) {
 					title
 					description
 					priority
+					estimate
 					url
 					createdAt
 					updatedAt
@@ -920,6 +1283,9 @@ func (l *Linear) Assign(id string, assignee string) (*backend.Task, error) {
 						id
 						key
 					}
+					parent {
+						identifier
+					}
 				}
 			}
 		}
@@ -985,6 +1351,7 @@ func (l *Linear) Unassign(id string) (*backend.Task, error) {
 					title
 					description
 					priority
+					estimate
 					url
 					createdAt
 					updatedAt
@@ -1007,6 +1374,9 @@ func (l *Linear) Unassign(id string) (*backend.Task, error) {
 						id
 						key
 					}
+					parent {
+						identifier
+					}
 				}
 			}
 		}
@@ -1046,6 +1416,28 @@ func (l *Linear) Unassign(id string) (*backend.Task, error) {
 
 // ListComments returns all comments for a task.
 func (l *Linear) ListComments(id string) ([]backend.Comment, error) {
+	return l.listComments(id, nil)
+}
+
+// ListCommentsFiltered returns the comments on a task that match filters.
+// The Since cutoff is applied by the API; Limit is applied to the result.
+func (l *Linear) ListCommentsFiltered(id string, filters backend.CommentFilters) ([]backend.Comment, error) {
+	var filter map[string]any
+	if !filters.Since.IsZero() {
+		filter = map[string]any{
+			"createdAt": map[string]any{"gt": filters.Since.UTC().Format(time.RFC3339)},
+		}
+	}
+	comments, err := l.listComments(id, filter)
+	if err != nil {
+		return nil, err
+	}
+	return backend.FilterComments(comments, filters), nil
+}
+
+// listComments returns the comments for a task that match a Linear
+// CommentFilter, or all of them if filter is nil.
+func (l *Linear) listComments(id string, filter map[string]any) ([]backend.Comment, error) {
 	if !l.connected {
 		return nil, errors.New("not connected")
 	}
@@ -1053,9 +1445,9 @@ func (l *Linear) ListComments(id string) ([]backend.Comment, error) {
 	issueID := l.normalizeID(id)
 
 	query := `
-		query GetIssueComments($id: String!) {
+		query GetIssueComments($id: String!, $filter: CommentFilter) {
 			issue(id: $id) {
-				comments {
+				comments(filter: $filter) {
 					nodes {
 						id
 						body
@@ -1071,9 +1463,13 @@ func (l *Linear) ListComments(id string) ([]backend.Comment, error) {
 		}
 	`
 
-	result, err := l.graphQL(query, map[string]any{"id": issueID})
+	variables := map[string]any{"id": issueID}
+	if filter != nil {
+		variables["filter"] = filter
+	}
+	result, err := l.graphQL(query, variables)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get comments: %w", err)
+		return nil, issueError(id, "failed to get comments", err)
 	}
 
 	data, ok := result["data"].(map[string]any)
@@ -1083,7 +1479,7 @@ func (l *Linear) ListComments(id string) ([]backend.Comment, error) {
 
 	issue, ok := data["issue"].(map[string]any)
 	if !ok || issue == nil {
-		return nil, fmt.Errorf("issue %s not found", id)
+		return nil, &backend.NotFoundError{ID: id}
 	}
 
 	commentsData, ok := issue["comments"].(map[string]any)
@@ -1103,25 +1499,7 @@ func (l *Linear) ListComments(id string) ([]backend.Comment, error) {
 			continue
 		}
 
-		comment := backend.Comment{
-			ID:   getString(c, "id"),
-			Body: getString(c, "body"),
-		}
-
-		if createdAt := getString(c, "createdAt"); createdAt != "" {
-			if t, err := time.Parse(time.RFC3339, createdAt); err == nil {
-				comment.Created = t
-			}
-		}
-
-		if user, ok := c["user"].(map[string]any); ok {
-			comment.Author = getString(user, "displayName")
-			if comment.Author == "" {
-				comment.Author = getString(user, "name")
-			}
-		}
-
-		comments = append(comments, comment)
+		comments = append(comments, *commentFromNode(c))
 	}
 
 	return comments, nil
@@ -1152,50 +1530,218 @@ func (l *Linear) AddComment(id string, body string) (*backend.Comment, error) {
 				success
 				comment {
 					id
-					body
-					createdAt
-					user {
-						id
-						name
-						displayName
-					}
+					body
+					createdAt
+					user {
+						id
+						name
+						displayName
+					}
+				}
+			}
+		}
+	`
+
+	result, err := l.graphQL(mutation, map[string]any{
+		"input": map[string]any{
+			"issueId": linearID,
+			"body":    body,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	data, ok := result["data"].(map[string]any)
+	if !ok {
+		return nil, errors.New("unexpected response format")
+	}
+
+	createResult, ok := data["commentCreate"].(map[string]any)
+	if !ok {
+		return nil, errors.New("unexpected response format: missing commentCreate")
+	}
+
+	success, _ := createResult["success"].(bool)
+	if !success {
+		return nil, errors.New("failed to create comment")
+	}
+
+	c, ok := createResult["comment"].(map[string]any)
+	if !ok {
+		return nil, errors.New("unexpected response format: missing comment")
+	}
+
+	comment := commentFromNode(c)
+	comment.TaskID = id
+	return comment, nil
+}
+
+// EditComment replaces the body of a comment on an issue.
+// Implements the backend.CommentEditor interface.
+func (l *Linear) EditComment(taskID, commentID, body string, force bool) (*backend.Comment, error) {
+	if err := l.checkCommentAuthor(taskID, commentID, force); err != nil {
+		return nil, err
+	}
+
+	mutation := `
+		mutation UpdateComment($id: String!, $input: CommentUpdateInput!) {
+			commentUpdate(id: $id, input: $input) {
+				success
+				comment {
+					id
+					body
+					createdAt
+					user {
+						id
+						name
+						displayName
+					}
+				}
+			}
+		}
+	`
+
+	result, err := l.graphQL(mutation, map[string]any{
+		"id":    commentID,
+		"input": map[string]any{"body": body},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	data, ok := result["data"].(map[string]any)
+	if !ok {
+		return nil, errors.New("unexpected response format")
+	}
+
+	updateResult, ok := data["commentUpdate"].(map[string]any)
+	if !ok {
+		return nil, errors.New("unexpected response format: missing commentUpdate")
+	}
+
+	success, _ := updateResult["success"].(bool)
+	if !success {
+		return nil, errors.New("failed to update comment")
+	}
+
+	c, ok := updateResult["comment"].(map[string]any)
+	if !ok {
+		return nil, errors.New("unexpected response format: missing comment")
+	}
+
+	comment := commentFromNode(c)
+	comment.TaskID = taskID
+	return comment, nil
+}
+
+// DeleteComment removes a comment from an issue.
+// Implements the backend.CommentEditor interface.
+func (l *Linear) DeleteComment(taskID, commentID string, force bool) error {
+	if err := l.checkCommentAuthor(taskID, commentID, force); err != nil {
+		return err
+	}
+
+	mutation := `
+		mutation DeleteComment($id: String!) {
+			commentDelete(id: $id) {
+				success
+			}
+		}
+	`
+
+	result, err := l.graphQL(mutation, map[string]any{"id": commentID})
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	data, ok := result["data"].(map[string]any)
+	if !ok {
+		return errors.New("unexpected response format")
+	}
+
+	deleteResult, ok := data["commentDelete"].(map[string]any)
+	if !ok {
+		return errors.New("unexpected response format: missing commentDelete")
+	}
+
+	if success, _ := deleteResult["success"].(bool); !success {
+		return errors.New("failed to delete comment")
+	}
+	return nil
+}
+
+// checkCommentAuthor verifies that a comment belongs to the issue and, unless
+// force is set, that it was written by the user the API key belongs to.
+func (l *Linear) checkCommentAuthor(taskID, commentID string, force bool) error {
+	if !l.connected {
+		return errors.New("not connected")
+	}
+
+	query := `
+		query GetComment($id: String!) {
+			comment(id: $id) {
+				id
+				user {
+					id
+					name
+					displayName
+				}
+				issue {
+					identifier
 				}
 			}
+			viewer {
+				id
+			}
 		}
 	`
 
-	result, err := l.graphQL(mutation, map[string]any{
-		"input": map[string]any{
-			"issueId": linearID,
-			"body":    body,
-		},
-	})
+	result, err := l.graphQL(query, map[string]any{"id": commentID})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create comment: %w", err)
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			return fmt.Errorf("comment %s not found on task %s", commentID, taskID)
+		}
+		return fmt.Errorf("failed to get comment: %w", err)
 	}
 
 	data, ok := result["data"].(map[string]any)
 	if !ok {
-		return nil, errors.New("unexpected response format")
+		return errors.New("unexpected response format")
 	}
 
-	createResult, ok := data["commentCreate"].(map[string]any)
-	if !ok {
-		return nil, errors.New("unexpected response format: missing commentCreate")
+	c, ok := data["comment"].(map[string]any)
+	if !ok || c == nil {
+		return fmt.Errorf("comment %s not found on task %s", commentID, taskID)
 	}
 
-	success, _ := createResult["success"].(bool)
-	if !success {
-		return nil, errors.New("failed to create comment")
+	issue, _ := c["issue"].(map[string]any)
+	if !strings.EqualFold(getString(issue, "identifier"), l.normalizeID(taskID)) {
+		return fmt.Errorf("comment %s not found on task %s", commentID, taskID)
 	}
 
-	c, ok := createResult["comment"].(map[string]any)
-	if !ok {
-		return nil, errors.New("unexpected response format: missing comment")
+	if force {
+		return nil
+	}
+	user, _ := c["user"].(map[string]any)
+	viewer, _ := data["viewer"].(map[string]any)
+	if user == nil || getString(user, "id") != getString(viewer, "id") {
+		author := getString(user, "displayName")
+		if author == "" {
+			author = getString(user, "name")
+		}
+		if author == "" {
+			author = "another user"
+		}
+		return &backend.CommentAuthorError{CommentID: commentID, Author: author}
 	}
+	return nil
+}
 
+// commentFromNode converts a comment node from the API into a Comment.
+func commentFromNode(c map[string]any) *backend.Comment {
 	comment := &backend.Comment{
-		ID:   id, // Use task ID as the comment ID for consistency
+		ID:   getString(c, "id"),
 		Body: getString(c, "body"),
 	}
 
@@ -1212,7 +1758,7 @@ func (l *Linear) AddComment(id string, body string) (*backend.Comment, error) {
 		}
 	}
 
-	return comment, nil
+	return comment
 }
 
 // Claim claims a task for an agent.
@@ -1311,6 +1857,7 @@ func (l *Linear) Claim(id string, agentID string) (*backend.ClaimResult, error)
 					title
 					description
 					priority
+					estimate
 					url
 					createdAt
 					updatedAt
@@ -1333,6 +1880,9 @@ func (l *Linear) Claim(id string, agentID string) (*backend.ClaimResult, error)
 						id
 						key
 					}
+					parent {
+						identifier
+					}
 				}
 			}
 		}
@@ -1379,8 +1929,21 @@ func (l *Linear) Claim(id string, agentID string) (*backend.ClaimResult, error)
 // Release releases a claimed task back to todo status.
 // Implements the backend.Claimer interface.
 func (l *Linear) Release(id string) error {
+	_, err := l.release(id, false)
+	return err
+}
+
+// ForceRelease releases a claimed task back to todo status whichever agent
+// holds the claim. Implements the backend.ForceReleaser interface.
+func (l *Linear) ForceRelease(id string) (string, error) {
+	return l.release(id, true)
+}
+
+// release releases a task, skipping the ownership check if force is set,
+// and returns the agent that held the claim.
+func (l *Linear) release(id string, force bool) (string, error) {
 	if !l.connected {
-		return errors.New("not connected")
+		return "", errors.New("not connected")
 	}
 
 	issueID := l.normalizeID(id)
@@ -1388,12 +1951,12 @@ func (l *Linear) Release(id string) error {
 	// Get the issue
 	issue, err := l.getIssueByIdentifier(issueID)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	linearID, ok := issue["id"].(string)
 	if !ok {
-		return errors.New("failed to get issue ID")
+		return "", errors.New("failed to get issue ID")
 	}
 
 	// Check who currently claims this task
@@ -1419,15 +1982,15 @@ func (l *Linear) Release(id string) error {
 
 	// Check if the task is claimed
 	if claimedBy == "" {
-		return &ReleaseConflictError{
+		return "", &ReleaseConflictError{
 			TaskID:     issueID,
 			NotClaimed: true,
 		}
 	}
 
 	// Check if the task is claimed by the current agent
-	if claimedBy != l.agentID {
-		return &ReleaseConflictError{
+	if claimedBy != l.agentID && !force {
+		return "", &ReleaseConflictError{
 			TaskID:       issueID,
 			ClaimedBy:    claimedBy,
 			CurrentAgent: l.agentID,
@@ -1437,7 +2000,7 @@ func (l *Linear) Release(id string) error {
 	// Get the state ID for todo
 	stateID, err := l.getStateIDForStatus(backend.StatusTodo)
 	if err != nil {
-		return fmt.Errorf("failed to find todo state: %w", err)
+		return "", fmt.Errorf("failed to find todo state: %w", err)
 	}
 
 	// Update the issue: remove agent labels, set to todo, unassign
@@ -1458,25 +2021,25 @@ func (l *Linear) Release(id string) error {
 		},
 	})
 	if err != nil {
-		return fmt.Errorf("failed to release issue: %w", err)
+		return "", fmt.Errorf("failed to release issue: %w", err)
 	}
 
 	data, ok := result["data"].(map[string]any)
 	if !ok {
-		return errors.New("unexpected response format")
+		return "", errors.New("unexpected response format")
 	}
 
 	updateResult, ok := data["issueUpdate"].(map[string]any)
 	if !ok {
-		return errors.New("unexpected response format: missing issueUpdate")
+		return "", errors.New("unexpected response format: missing issueUpdate")
 	}
 
 	success, _ := updateResult["success"].(bool)
 	if !success {
-		return errors.New("failed to release issue")
+		return "", errors.New("failed to release issue")
 	}
 
-	return nil
+	return claimedBy, nil
 }
 
 // Helper functions
@@ -1672,6 +2235,7 @@ func (l *Linear) getIssueByIdentifier(identifier string) (map[string]any, error)
 				title
 				description
 				priority
+				estimate
 				url
 				createdAt
 				updatedAt
@@ -1694,13 +2258,16 @@ func (l *Linear) getIssueByIdentifier(identifier string) (map[string]any, error)
 					id
 					key
 				}
+				parent {
+					identifier
+				}
 			}
 		}
 	`
 
 	result, err := l.graphQL(query, map[string]any{"id": identifier})
 	if err != nil {
-		return nil, err
+		return nil, issueError(identifier, "failed to get issue", err)
 	}
 
 	data, ok := result["data"].(map[string]any)
@@ -1710,14 +2277,52 @@ func (l *Linear) getIssueByIdentifier(identifier string) (map[string]any, error)
 
 	issue, ok := data["issue"].(map[string]any)
 	if !ok || issue == nil {
-		return nil, fmt.Errorf("issue %s not found", identifier)
+		return nil, &backend.NotFoundError{ID: identifier}
 	}
 
 	return issue, nil
 }
 
-// getUserID fetches the user ID for a given username/email.
+// issueError converts a GraphQL error from looking up an issue into a
+// *backend.NotFoundError if the issue doesn't exist. Linear reports a missing
+// issue as an "Entity not found" error rather than a null issue.
+func issueError(id, message string, err error) error {
+	if strings.Contains(strings.ToLower(err.Error()), "not found") {
+		return &backend.NotFoundError{ID: id}
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// getLinearID resolves a task ID (e.g., "ENG-123" or "123") to the issue's
+// Linear UUID.
+func (l *Linear) getLinearID(id string) (string, error) {
+	issue, err := l.getIssueByIdentifier(l.normalizeID(id))
+	if err != nil {
+		return "", err
+	}
+	return getString(issue, "id"), nil
+}
+
+// getUserID resolves a user's name, display name or email to their Linear
+// user ID.
 func (l *Linear) getUserID(name string) (string, error) {
+	users, err := l.listUsers()
+	if err != nil {
+		return "", err
+	}
+	user, err := matchUser(users, name)
+	if err != nil {
+		return "", err
+	}
+	return user.ID, nil
+}
+
+// listUsers returns the workspace's users, fetching them on first use.
+func (l *Linear) listUsers() ([]linearUser, error) {
+	if l.users != nil {
+		return l.users, nil
+	}
+
 	query := `
 		query GetUsers {
 			users {
@@ -1733,40 +2338,131 @@ func (l *Linear) getUserID(name string) (string, error) {
 
 	result, err := l.graphQL(query, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	data, ok := result["data"].(map[string]any)
 	if !ok {
-		return "", errors.New("unexpected response format")
+		return nil, errors.New("unexpected response format")
 	}
 
 	users, ok := data["users"].(map[string]any)
 	if !ok {
-		return "", errors.New("unexpected response format: missing users")
+		return nil, errors.New("unexpected response format: missing users")
 	}
 
 	nodes, ok := users["nodes"].([]any)
 	if !ok {
-		return "", errors.New("unexpected response format: missing nodes")
+		return nil, errors.New("unexpected response format: missing nodes")
 	}
 
-	nameLower := strings.ToLower(name)
+	l.users = make([]linearUser, 0, len(nodes))
 	for _, node := range nodes {
 		user, ok := node.(map[string]any)
 		if !ok {
 			continue
 		}
-		if strings.EqualFold(getString(user, "name"), name) ||
-			strings.EqualFold(getString(user, "displayName"), name) ||
-			strings.EqualFold(getString(user, "email"), name) ||
-			strings.Contains(strings.ToLower(getString(user, "name")), nameLower) ||
-			strings.Contains(strings.ToLower(getString(user, "displayName")), nameLower) {
-			return getString(user, "id"), nil
+		l.users = append(l.users, linearUser{
+			ID:          getString(user, "id"),
+			Name:        getString(user, "name"),
+			DisplayName: getString(user, "displayName"),
+			Email:       getString(user, "email"),
+		})
+	}
+	return l.users, nil
+}
+
+// linearUser is a workspace member that an assignee can name.
+type linearUser struct {
+	ID          string
+	Name        string
+	DisplayName string
+	Email       string
+}
+
+// String formats the user for error messages, as "name <email>".
+func (u linearUser) String() string {
+	if u.Email == "" {
+		return u.Name
+	}
+	return fmt.Sprintf("%s <%s>", u.Name, u.Email)
+}
+
+// maxUserSuggestions caps the close matches listed when no user matches.
+const maxUserSuggestions = 5
+
+// matchUser finds the user that name refers to. A name, display name or
+// email equal to it, ignoring case, wins; otherwise name must be part of
+// exactly one user's name or display name. When no user or several match,
+// the UserNotFoundError lists the candidates.
+func matchUser(users []linearUser, name string) (linearUser, error) {
+	for _, u := range users {
+		if strings.EqualFold(u.Name, name) || strings.EqualFold(u.DisplayName, name) || strings.EqualFold(u.Email, name) {
+			return u, nil
+		}
+	}
+
+	nameLower := strings.ToLower(name)
+	var partial []linearUser
+	for _, u := range users {
+		if strings.Contains(strings.ToLower(u.Name), nameLower) || strings.Contains(strings.ToLower(u.DisplayName), nameLower) {
+			partial = append(partial, u)
+		}
+	}
+	switch len(partial) {
+	case 1:
+		return partial[0], nil
+	case 0:
+	default:
+		return linearUser{}, &UserNotFoundError{Name: name, Ambiguous: true, Candidates: userStrings(partial)}
+	}
+
+	// Suggest users whose name, display name or email local part is a
+	// couple of edits away
+	var close []linearUser
+	for _, u := range users {
+		local, _, _ := strings.Cut(u.Email, "@")
+		for _, field := range []string{u.Name, u.DisplayName, local} {
+			if field != "" && editDistance(strings.ToLower(field), nameLower) <= 2 {
+				close = append(close, u)
+				break
+			}
 		}
 	}
+	return linearUser{}, &UserNotFoundError{Name: name, Candidates: userStrings(close)}
+}
+
+func userStrings(users []linearUser) []string {
+	if len(users) > maxUserSuggestions {
+		users = users[:maxUserSuggestions]
+	}
+	out := make([]string, len(users))
+	for i, u := range users {
+		out[i] = u.String()
+	}
+	return out
+}
 
-	return "", fmt.Errorf("user %s not found", name)
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		cur := make([]int, len(br)+1)
+		cur[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(br)]
 }
 
 // getViewerID fetches the current authenticated user's ID.
@@ -1797,12 +2493,39 @@ func (l *Linear) getLabelIDs(names []string) ([]string, error) {
 		return nil, nil
 	}
 
+	nodes, err := l.teamLabels()
+	if err != nil {
+		return nil, err
+	}
+
+	// Build name -> ID map
+	labelMap := make(map[string]string)
+	for _, label := range nodes {
+		name := getString(label, "name")
+		id := getString(label, "id")
+		labelMap[strings.ToLower(name)] = id
+	}
+
+	ids := make([]string, 0, len(names))
+	for _, name := range names {
+		if id, ok := labelMap[strings.ToLower(name)]; ok {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+// teamLabels fetches the issue labels available to the team.
+func (l *Linear) teamLabels() ([]map[string]any, error) {
 	query := `
 		query GetLabels($teamId: ID) {
 			issueLabels(filter: { team: { id: { eq: $teamId } } }) {
 				nodes {
 					id
 					name
+					color
+					description
 				}
 			}
 		}
@@ -1833,26 +2556,37 @@ func (l *Linear) getLabelIDs(names []string) ([]string, error) {
 		return nil, errors.New("unexpected response format: missing nodes")
 	}
 
-	// Build name -> ID map
-	labelMap := make(map[string]string)
+	found := make([]map[string]any, 0, len(nodes))
 	for _, node := range nodes {
-		label, ok := node.(map[string]any)
-		if !ok {
-			continue
+		if label, ok := node.(map[string]any); ok {
+			found = append(found, label)
 		}
-		name := getString(label, "name")
-		id := getString(label, "id")
-		labelMap[strings.ToLower(name)] = id
 	}
+	return found, nil
+}
 
-	ids := make([]string, 0, len(names))
-	for _, name := range names {
-		if id, ok := labelMap[strings.ToLower(name)]; ok {
-			ids = append(ids, id)
-		}
+// ListLabels returns the team's issue labels with their colors and
+// descriptions. Implements the backend.LabelLister interface.
+func (l *Linear) ListLabels() ([]backend.Label, error) {
+	if !l.connected {
+		return nil, errors.New("not connected")
 	}
 
-	return ids, nil
+	nodes, err := l.teamLabels()
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make([]backend.Label, 0, len(nodes))
+	for _, node := range nodes {
+		labels = append(labels, backend.Label{
+			Name:        getString(node, "name"),
+			Color:       getString(node, "color"),
+			Description: getString(node, "description"),
+		})
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+	return labels, nil
 }
 
 // getOrCreateLabel gets an existing label or creates it if it doesn't exist.
@@ -1906,6 +2640,88 @@ func (l *Linear) getOrCreateLabel(name string) (string, error) {
 	return getString(label, "id"), nil
 }
 
+// idempotencyMarker returns the description marker recording an idempotency key.
+func idempotencyMarker(key string) string {
+	return fmt.Sprintf("<!-- backlog-idempotency-key: %s -->", key)
+}
+
+// idempotencyMarkerPattern matches a marker and the blank line before it.
+var idempotencyMarkerPattern = regexp.MustCompile(`\n*<!-- backlog-idempotency-key: [^>]* -->`)
+
+// stripIdempotencyMarker removes the idempotency marker from a description.
+func stripIdempotencyMarker(description string) string {
+	return idempotencyMarkerPattern.ReplaceAllString(description, "")
+}
+
+// findIdempotentIssue returns the issue created with key within the
+// idempotency window, or nil if there is none.
+func (l *Linear) findIdempotentIssue(key string) (*backend.Task, error) {
+	query := `
+		query FindIdempotentIssues($first: Int, $filter: IssueFilter) {
+			issues(first: $first, filter: $filter) {
+				nodes {
+					id
+					identifier
+					title
+					description
+					priority
+					estimate
+					url
+					createdAt
+					updatedAt
+					state {
+						id
+						name
+					}
+					assignee {
+						id
+						name
+						displayName
+					}
+					labels {
+						nodes {
+							id
+							name
+						}
+					}
+					team {
+						id
+						key
+					}
+					parent {
+						identifier
+					}
+				}
+			}
+		}
+	`
+
+	filter := map[string]any{
+		"team":        map[string]any{"id": map[string]any{"eq": l.teamID}},
+		"description": map[string]any{"contains": idempotencyMarker(key)},
+		"createdAt":   map[string]any{"gt": time.Now().Add(-l.idempotencyWindow).UTC().Format(time.RFC3339)},
+	}
+	result, err := l.graphQL(query, map[string]any{"first": 1, "filter": filter})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check idempotency key: %w", err)
+	}
+
+	data, _ := result["data"].(map[string]any)
+	issues, _ := data["issues"].(map[string]any)
+	nodes, _ := issues["nodes"].([]any)
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+	issue, ok := nodes[0].(map[string]any)
+	if !ok {
+		return nil, errors.New("unexpected response format: invalid issue")
+	}
+
+	task := l.issueToTask(issue)
+	task.Meta[backend.MetaIdempotentReplay] = true
+	return task, nil
+}
+
 // normalizeID removes the LIN- prefix from an ID if present.
 func (l *Linear) normalizeID(id string) string {
 	id = strings.TrimPrefix(id, "LIN-")
@@ -1917,7 +2733,7 @@ func (l *Linear) issueToTask(issue map[string]any) *backend.Task {
 	task := &backend.Task{
 		ID:          getString(issue, "identifier"),
 		Title:       getString(issue, "title"),
-		Description: getString(issue, "description"),
+		Description: stripIdempotencyMarker(getString(issue, "description")),
 		URL:         getString(issue, "url"),
 		Meta:        make(map[string]any),
 	}
@@ -1933,6 +2749,28 @@ func (l *Linear) issueToTask(issue map[string]any) *backend.Task {
 			task.Updated = t
 		}
 	}
+	if archivedAt := getString(issue, "archivedAt"); archivedAt != "" {
+		if t, err := time.Parse(time.RFC3339, archivedAt); err == nil {
+			task.DeletedAt = &t
+		}
+	}
+
+	// Linear stamps issues as they enter started and completed states,
+	// which only approximates when an agent claimed and finished the work
+	if startedAt := getString(issue, "startedAt"); startedAt != "" {
+		if t, err := time.Parse(time.RFC3339, startedAt); err == nil {
+			task.StartedAt = &t
+			task.TimeApproximate = true
+		}
+	}
+	if completedAt := getString(issue, "completedAt"); completedAt != "" {
+		if t, err := time.Parse(time.RFC3339, completedAt); err == nil {
+			task.CompletedAt = &t
+			task.TimeApproximate = true
+		}
+	}
+	backend.SetDuration(task, time.Now().UTC())
+	backend.SetChecklist(task)
 
 	// Priority (Linear uses 0-4)
 	if priority, ok := issue["priority"].(float64); ok {
@@ -1976,6 +2814,11 @@ func (l *Linear) issueToTask(issue map[string]any) *backend.Task {
 		}
 	}
 
+	// Estimate (Linear stores estimates as points)
+	if estimate, ok := issue["estimate"].(float64); ok {
+		task.Estimate = backend.Duration(estimate * float64(estimatePointDuration))
+	}
+
 	// Sort order (used for board position ordering)
 	if sortOrder, ok := issue["sortOrder"].(float64); ok {
 		task.SortOrder = sortOrder
@@ -1997,9 +2840,24 @@ func (l *Linear) issueToTask(issue map[string]any) *backend.Task {
 		task.Meta["team_key"] = getString(team, "key")
 	}
 
+	// Parent issue
+	if parent, ok := issue["parent"].(map[string]any); ok {
+		task.Parent = getString(parent, "identifier")
+	}
+
 	return task
 }
 
+// durationToEstimatePoints converts a duration to Linear estimate points,
+// rounding up so that any non-zero estimate is at least one point.
+func durationToEstimatePoints(d backend.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	unit := backend.Duration(estimatePointDuration)
+	return int((d + unit - 1) / unit)
+}
+
 // getString safely gets a string value from a map.
 func getString(m map[string]any, key string) string {
 	if v, ok := m[key].(string); ok {
@@ -2079,6 +2937,7 @@ func (l *Linear) Reorder(id string, position backend.ReorderPosition) (*backend.
 					title
 					description
 					priority
+					estimate
 					sortOrder
 					prioritySortOrder
 					url
@@ -2103,6 +2962,9 @@ func (l *Linear) Reorder(id string, position backend.ReorderPosition) (*backend.
 						id
 						key
 					}
+					parent {
+						identifier
+					}
 				}
 			}
 		}
@@ -2168,6 +3030,23 @@ func calculateLinearSortOrder(target *backend.Task, sortedTasks []backend.Task,
 		return effectiveSortOrder(others[len(others)-1]) + 1024, nil
 	}
 
+	if position.Position != 0 {
+		n := position.Position
+		if n < 1 || n > len(others)+1 {
+			return 0, fmt.Errorf("position %d is out of range: the task's status and priority group has %d issues (valid: 1-%d)", n, len(others)+1, len(others)+1)
+		}
+		switch {
+		case len(others) == 0:
+			return 1024, nil
+		case n == 1:
+			return effectiveSortOrder(others[0]) - 1024, nil
+		case n == len(others)+1:
+			return effectiveSortOrder(others[len(others)-1]) + 1024, nil
+		}
+		// Between the issues now at n-1 and n
+		return (effectiveSortOrder(others[n-2]) + effectiveSortOrder(others[n-1])) / 2, nil
+	}
+
 	refID := position.BeforeID
 	if refID == "" {
 		refID = position.AfterID
@@ -2398,13 +3277,25 @@ func (l *Linear) ListRelations(id string) ([]backend.Relation, error) {
 						}
 					}
 				}
+				parent {
+					identifier
+					title
+					state { name }
+				}
+				children {
+					nodes {
+						identifier
+						title
+						state { name }
+					}
+				}
 			}
 		}
 	`
 
 	result, err := l.graphQL(query, map[string]any{"id": issueID})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list relations: %w", err)
+		return nil, issueError(id, "failed to list relations", err)
 	}
 
 	data, ok := result["data"].(map[string]any)
@@ -2414,7 +3305,7 @@ func (l *Linear) ListRelations(id string) ([]backend.Relation, error) {
 
 	issue, ok := data["issue"].(map[string]any)
 	if !ok || issue == nil {
-		return nil, fmt.Errorf("issue %s not found", id)
+		return nil, &backend.NotFoundError{ID: id}
 	}
 
 	var relations []backend.Relation
@@ -2493,9 +3384,38 @@ func (l *Linear) ListRelations(id string) ([]backend.Relation, error) {
 		}
 	}
 
+	// Process the sub-issue hierarchy
+	if parent, ok := issue["parent"].(map[string]any); ok {
+		relations = append(relations, l.hierarchyRelation(backend.RelationParent, parent))
+	}
+	if childData, ok := issue["children"].(map[string]any); ok {
+		if nodes, ok := childData["nodes"].([]any); ok {
+			for _, node := range nodes {
+				if child, ok := node.(map[string]any); ok {
+					relations = append(relations, l.hierarchyRelation(backend.RelationChild, child))
+				}
+			}
+		}
+	}
+
 	return relations, nil
 }
 
+// hierarchyRelation builds a parent or child relation from an issue node.
+func (l *Linear) hierarchyRelation(relationType backend.RelationType, related map[string]any) backend.Relation {
+	relation := backend.Relation{
+		Type:      relationType,
+		TaskID:    getString(related, "identifier"),
+		TaskTitle: getString(related, "title"),
+	}
+	if state, ok := related["state"].(map[string]any); ok {
+		if status, ok := l.reverseStatusMap[strings.ToLower(getString(state, "name"))]; ok {
+			relation.TaskStatus = status
+		}
+	}
+	return relation
+}
+
 // findRelationID finds the Linear relation UUID for a given source/target/type.
 func (l *Linear) findRelationID(sourceLinearID, targetLinearID string, relationType backend.RelationType) (string, error) {
 	query := `
@@ -2613,6 +3533,24 @@ func (e *ReleaseConflictError) Error() string {
 	return fmt.Sprintf("task %s is claimed by different agent %s, not by %s", e.TaskID, e.ClaimedBy, e.CurrentAgent)
 }
 
+// UserNotFoundError is returned when an assignee doesn't name exactly one
+// Linear user. Candidates lists the users it could have meant.
+type UserNotFoundError struct {
+	Name       string
+	Ambiguous  bool
+	Candidates []string
+}
+
+func (e *UserNotFoundError) Error() string {
+	if e.Ambiguous {
+		return fmt.Sprintf("%q matches several Linear users: %s", e.Name, strings.Join(e.Candidates, ", "))
+	}
+	if len(e.Candidates) == 0 {
+		return fmt.Sprintf("no Linear user matches %q", e.Name)
+	}
+	return fmt.Sprintf("no Linear user matches %q; did you mean %s?", e.Name, strings.Join(e.Candidates, ", "))
+}
+
 // Register registers the Linear backend with the registry.
 func Register() {
 	backend.Register(Name, func() backend.Backend {
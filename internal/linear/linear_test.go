@@ -3,8 +3,11 @@ package linear
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -924,6 +927,68 @@ func TestIssueToTaskSortOrder(t *testing.T) {
 	})
 }
 
+func TestIssueToTaskEstimate(t *testing.T) {
+	l := New()
+	l.reverseStatusMap = map[string]backend.Status{"todo": backend.StatusTodo}
+
+	issue := map[string]any{
+		"identifier": "ENG-123",
+		"title":      "Test",
+		"estimate":   float64(2),
+		"state":      map[string]any{"name": "Todo"},
+	}
+
+	task := l.issueToTask(issue)
+
+	if want := backend.Duration(2 * backend.Day); task.Estimate != want {
+		t.Errorf("Estimate = %v, want %v", task.Estimate, want)
+	}
+}
+
+func TestIssueToTaskTimes(t *testing.T) {
+	l := New()
+	l.reverseStatusMap = map[string]backend.Status{"done": backend.StatusDone}
+
+	issue := map[string]any{
+		"identifier":  "ENG-123",
+		"title":       "Test",
+		"startedAt":   "2025-01-10T09:00:00Z",
+		"completedAt": "2025-01-11T11:30:00Z",
+		"state":       map[string]any{"name": "Done"},
+	}
+
+	task := l.issueToTask(issue)
+
+	if task.StartedAt == nil || task.CompletedAt == nil {
+		t.Fatalf("expected start and completion times, got %v and %v", task.StartedAt, task.CompletedAt)
+	}
+	if want := backend.Duration(26*time.Hour + 30*time.Minute); task.Duration != want {
+		t.Errorf("Duration = %v, want %v", task.Duration, want)
+	}
+	if !task.TimeApproximate {
+		t.Error("expected times to be marked approximate")
+	}
+}
+
+func TestDurationToEstimatePoints(t *testing.T) {
+	tests := []struct {
+		input time.Duration
+		want  int
+	}{
+		{0, 0},
+		{2 * time.Hour, 1},
+		{24 * time.Hour, 1},
+		{36 * time.Hour, 2},
+		{7 * 24 * time.Hour, 7},
+	}
+
+	for _, tt := range tests {
+		if got := durationToEstimatePoints(backend.Duration(tt.input)); got != tt.want {
+			t.Errorf("durationToEstimatePoints(%v) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
 func TestListSortsBySortOrder(t *testing.T) {
 	server := mockLinearServer(t, func(query string, variables map[string]any) any {
 		if strings.Contains(query, "ListIssues") {
@@ -1006,3 +1071,364 @@ func TestListSortsBySortOrder(t *testing.T) {
 		}
 	}
 }
+
+func TestListTimeFilters(t *testing.T) {
+	var filter map[string]any
+	server := mockLinearServer(t, func(query string, variables map[string]any) any {
+		filter, _ = variables["filter"].(map[string]any)
+		return map[string]any{
+			"data": map[string]any{
+				"issues": map[string]any{
+					"nodes":    []any{},
+					"pageInfo": map[string]any{"hasNextPage": false},
+				},
+			},
+		}
+	})
+	defer server.Close()
+
+	l := &Linear{
+		ctx:         context.Background(),
+		client:      server.Client(),
+		apiKey:      "test-key",
+		apiEndpoint: server.URL,
+		connected:   true,
+	}
+
+	_, err := l.List(backend.TaskFilters{
+		CreatedSince: time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC),
+		UpdatedSince: time.Date(2025, 1, 14, 8, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	createdAt, _ := filter["createdAt"].(map[string]any)
+	if createdAt["gte"] != "2025-01-10T08:00:00Z" {
+		t.Errorf("createdAt filter = %v, want gte 2025-01-10T08:00:00Z", filter["createdAt"])
+	}
+	updatedAt, _ := filter["updatedAt"].(map[string]any)
+	if updatedAt["gte"] != "2025-01-14T08:00:00Z" {
+		t.Errorf("updatedAt filter = %v, want gte 2025-01-14T08:00:00Z", filter["updatedAt"])
+	}
+}
+
+func TestMatchUser(t *testing.T) {
+	users := []linearUser{
+		{ID: "u1", Name: "Alice Smith", DisplayName: "alice", Email: "alice@example.com"},
+		{ID: "u2", Name: "Bob Jones", DisplayName: "bob", Email: "bob@example.com"},
+		{ID: "u3", Name: "Bobby Tables", DisplayName: "bobby", Email: "bobby@example.com"},
+	}
+
+	tests := []struct {
+		name          string
+		input         string
+		wantID        string
+		wantAmbiguous bool
+		wantErr       string
+	}{
+		{name: "exact name", input: "alice smith", wantID: "u1"},
+		{name: "display name", input: "Bob", wantID: "u2"},
+		{name: "email", input: "BOBBY@example.com", wantID: "u3"},
+		{name: "single partial match", input: "Tables", wantID: "u3"},
+		{name: "ambiguous partial match", input: "bo", wantAmbiguous: true, wantErr: `"bo" matches several Linear users: Bob Jones <bob@example.com>, Bobby Tables <bobby@example.com>`},
+		{name: "close match", input: "alise", wantErr: `no Linear user matches "alise"; did you mean Alice Smith <alice@example.com>?`},
+		{name: "no match", input: "zed", wantErr: `no Linear user matches "zed"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := matchUser(users, tt.input)
+			if tt.wantErr == "" {
+				if err != nil || user.ID != tt.wantID {
+					t.Errorf("matchUser(%q) = %v, %v; want %s", tt.input, user.ID, err, tt.wantID)
+				}
+				return
+			}
+			var notFound *UserNotFoundError
+			if !errors.As(err, &notFound) {
+				t.Fatalf("matchUser(%q) error = %v, want UserNotFoundError", tt.input, err)
+			}
+			if notFound.Ambiguous != tt.wantAmbiguous || err.Error() != tt.wantErr {
+				t.Errorf("matchUser(%q) error = %q, want %q", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestListNamedAssignee(t *testing.T) {
+	issues := []any{
+		map[string]any{"identifier": "ENG-1", "title": "One", "assignee": map[string]any{"id": "u1", "name": "Alice Smith", "displayName": "alice", "email": "alice@example.com"}},
+		map[string]any{"identifier": "ENG-2", "title": "Two", "assignee": map[string]any{"id": "u2", "name": "Bob Jones", "displayName": "bob", "email": "bob@example.com"}},
+	}
+	usersErr := false
+	var userQueries int
+	var filter map[string]any
+	server := mockLinearServer(t, func(query string, variables map[string]any) any {
+		if strings.Contains(query, "GetUsers") {
+			userQueries++
+			if usersErr {
+				return map[string]any{"errors": []any{map[string]any{"message": "forbidden"}}}
+			}
+			return map[string]any{
+				"data": map[string]any{
+					"users": map[string]any{
+						"nodes": []any{
+							map[string]any{"id": "u1", "name": "Alice Smith", "displayName": "alice", "email": "alice@example.com"},
+							map[string]any{"id": "u2", "name": "Bob Jones", "displayName": "bob", "email": "bob@example.com"},
+						},
+					},
+				},
+			}
+		}
+		filter, _ = variables["filter"].(map[string]any)
+		return map[string]any{
+			"data": map[string]any{
+				"issues": map[string]any{
+					"nodes":    issues,
+					"pageInfo": map[string]any{"hasNextPage": false},
+				},
+			},
+		}
+	})
+	defer server.Close()
+
+	l := &Linear{
+		ctx:              context.Background(),
+		client:           server.Client(),
+		apiKey:           "test-key",
+		apiEndpoint:      server.URL,
+		connected:        true,
+		reverseStatusMap: make(map[string]backend.Status),
+	}
+
+	for _, name := range []string{"alice@example.com", "alice"} {
+		list, err := l.List(backend.TaskFilters{Assignee: name})
+		if err != nil {
+			t.Fatalf("List(%s) error = %v", name, err)
+		}
+		assignee, _ := filter["assignee"].(map[string]any)
+		id, _ := assignee["id"].(map[string]any)
+		if id["eq"] != "u1" {
+			t.Errorf("List(%s) assignee filter = %v, want id eq u1", name, filter["assignee"])
+		}
+		if len(list.Warnings) != 0 {
+			t.Errorf("List(%s) warnings = %v, want none", name, list.Warnings)
+		}
+	}
+	if userQueries != 1 {
+		t.Errorf("users queried %d times, want once", userQueries)
+	}
+
+	_, err := l.List(backend.TaskFilters{Assignee: "alise"})
+	if err == nil || !strings.Contains(err.Error(), "did you mean Alice Smith <alice@example.com>?") {
+		t.Errorf("List(alise) error = %v, want a suggestion", err)
+	}
+
+	// Without the users, issues are matched on the client
+	l.users = nil
+	usersErr = true
+	list, err := l.List(backend.TaskFilters{Assignee: "Bob Jones"})
+	if err != nil {
+		t.Fatalf("List() fallback error = %v", err)
+	}
+	if _, ok := filter["assignee"]; ok {
+		t.Errorf("assignee filter = %v, want none when falling back", filter["assignee"])
+	}
+	if len(list.Tasks) != 1 || list.Tasks[0].ID != "ENG-2" {
+		t.Errorf("tasks = %v, want only ENG-2", list.Tasks)
+	}
+	if len(list.Warnings) != 1 || !strings.Contains(list.Warnings[0], "matching assignee") {
+		t.Errorf("warnings = %v, want a fallback warning", list.Warnings)
+	}
+}
+
+func TestCountPagesThroughStates(t *testing.T) {
+	pages := [][]string{{"Todo", "Done", "In Progress"}, {"Todo", "Canceled"}}
+	var requests []map[string]any
+	server := mockLinearServer(t, func(query string, variables map[string]any) any {
+		requests = append(requests, variables)
+		page := len(requests) - 1
+		nodes := make([]any, len(pages[page]))
+		for i, state := range pages[page] {
+			nodes[i] = map[string]any{"state": map[string]any{"name": state}}
+		}
+		return map[string]any{
+			"data": map[string]any{
+				"issues": map[string]any{
+					"nodes": nodes,
+					"pageInfo": map[string]any{
+						"hasNextPage": page+1 < len(pages),
+						"endCursor":   fmt.Sprintf("cursor-%d", page),
+					},
+				},
+			},
+		}
+	})
+	defer server.Close()
+
+	l := &Linear{
+		ctx:              context.Background(),
+		client:           server.Client(),
+		apiKey:           "test-key",
+		apiEndpoint:      server.URL,
+		connected:        true,
+		reverseStatusMap: make(map[string]backend.Status),
+	}
+	for state, status := range defaultStatusMapping {
+		l.reverseStatusMap[strings.ToLower(state)] = status
+	}
+
+	count, err := l.Count(backend.TaskFilters{Limit: 1})
+	if err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	// Done and canceled issues are excluded, and the limit is ignored
+	if count != 3 {
+		t.Errorf("Count() = %d, want 3", count)
+	}
+	if len(requests) != 2 || requests[1]["after"] != "cursor-0" {
+		t.Errorf("requests = %v, want a second page after cursor-0", requests)
+	}
+
+	requests = nil
+	count, err = l.Count(backend.TaskFilters{Status: []backend.Status{backend.StatusTodo}})
+	if err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Count(todo) = %d, want 2", count)
+	}
+}
+
+func TestListCommentsFiltered(t *testing.T) {
+	var filter any
+	server := mockLinearServer(t, func(query string, variables map[string]any) any {
+		filter = variables["filter"]
+		nodes := []any{}
+		for i, created := range []string{"2025-01-15T09:00:00Z", "2025-01-15T10:00:00Z", "2025-01-15T11:00:00Z"} {
+			nodes = append(nodes, map[string]any{
+				"id":        fmt.Sprintf("c%d", i+1),
+				"body":      "comment",
+				"createdAt": created,
+				"user":      map[string]any{"id": "user-1", "displayName": "alice"},
+			})
+		}
+		return map[string]any{
+			"data": map[string]any{
+				"issue": map[string]any{"comments": map[string]any{"nodes": nodes}},
+			},
+		}
+	})
+	defer server.Close()
+
+	l := &Linear{
+		ctx:         context.Background(),
+		client:      server.Client(),
+		apiKey:      "test-key",
+		apiEndpoint: server.URL,
+		connected:   true,
+	}
+
+	since := time.Date(2025, 1, 15, 8, 30, 0, 0, time.UTC)
+	comments, err := l.ListCommentsFiltered("ENG-1", backend.CommentFilters{Since: since, Limit: 2})
+	if err != nil {
+		t.Fatalf("ListCommentsFiltered() error = %v", err)
+	}
+
+	// The cutoff goes to the API, and the limit keeps the most recent
+	want := map[string]any{"createdAt": map[string]any{"gt": "2025-01-15T08:30:00Z"}}
+	if !reflect.DeepEqual(filter, want) {
+		t.Errorf("filter = %v, want %v", filter, want)
+	}
+	if len(comments) != 2 || comments[0].ID != "c2" || comments[1].ID != "c3" {
+		t.Errorf("ListCommentsFiltered() = %+v, want c2 and c3", comments)
+	}
+
+	// Without filters, no filter is sent
+	if _, err := l.ListComments("ENG-1"); err != nil {
+		t.Fatalf("ListComments() error = %v", err)
+	}
+	if filter != nil {
+		t.Errorf("filter = %v, want none", filter)
+	}
+}
+
+func TestEditAndDeleteComment(t *testing.T) {
+	commentAuthor := "user-1"
+	var mutations []string
+	server := mockLinearServer(t, func(query string, variables map[string]any) any {
+		switch {
+		case strings.Contains(query, "GetComment"):
+			return map[string]any{
+				"data": map[string]any{
+					"comment": map[string]any{
+						"id":    variables["id"],
+						"user":  map[string]any{"id": commentAuthor, "displayName": "alice"},
+						"issue": map[string]any{"identifier": "ENG-1"},
+					},
+					"viewer": map[string]any{"id": "user-1"},
+				},
+			}
+		case strings.Contains(query, "commentUpdate"):
+			mutations = append(mutations, "update")
+			input, _ := variables["input"].(map[string]any)
+			return map[string]any{
+				"data": map[string]any{
+					"commentUpdate": map[string]any{
+						"success": true,
+						"comment": map[string]any{
+							"id":        variables["id"],
+							"body":      input["body"],
+							"createdAt": "2025-01-15T09:00:00Z",
+							"user":      map[string]any{"id": "user-1", "displayName": "alice"},
+						},
+					},
+				},
+			}
+		case strings.Contains(query, "commentDelete"):
+			mutations = append(mutations, "delete")
+			return map[string]any{"data": map[string]any{"commentDelete": map[string]any{"success": true}}}
+		}
+		t.Errorf("unexpected query: %s", query)
+		return nil
+	})
+	defer server.Close()
+
+	l := &Linear{
+		ctx:         context.Background(),
+		client:      server.Client(),
+		apiKey:      "test-key",
+		apiEndpoint: server.URL,
+		connected:   true,
+	}
+
+	comment, err := l.EditComment("ENG-1", "comment-uuid", "Reworded", false)
+	if err != nil {
+		t.Fatalf("EditComment() error = %v", err)
+	}
+	if comment.ID != "comment-uuid" || comment.Body != "Reworded" || comment.TaskID != "ENG-1" {
+		t.Errorf("EditComment() = %+v, want comment-uuid on ENG-1 with the new body", comment)
+	}
+	if err := l.DeleteComment("ENG-1", "comment-uuid", false); err != nil {
+		t.Fatalf("DeleteComment() error = %v", err)
+	}
+
+	// Another user's comment is refused without force
+	commentAuthor = "user-2"
+	var authorErr *backend.CommentAuthorError
+	if _, err := l.EditComment("ENG-1", "comment-uuid", "Hijacked", false); !errors.As(err, &authorErr) {
+		t.Errorf("EditComment() error = %v, want CommentAuthorError", err)
+	}
+	if err := l.DeleteComment("ENG-1", "comment-uuid", true); err != nil {
+		t.Errorf("DeleteComment(force) error = %v", err)
+	}
+
+	// A comment on another issue is not found
+	if err := l.DeleteComment("ENG-2", "comment-uuid", true); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("DeleteComment() on another issue error = %v, want not found", err)
+	}
+
+	if want := []string{"update", "delete", "delete"}; strings.Join(mutations, ",") != strings.Join(want, ",") {
+		t.Errorf("mutations = %v, want %v", mutations, want)
+	}
+}
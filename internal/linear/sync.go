@@ -0,0 +1,231 @@
+package linear
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/local"
+)
+
+const (
+	// syncPageSize is the number of issues fetched per request when syncing.
+	syncPageSize = 100
+
+	// lastSyncFile records, inside the cache, the updatedAt of the newest
+	// issue seen by the previous sync.
+	lastSyncFile = ".last-sync"
+)
+
+// Sync mirrors the team's issues into the read-only local cache so they can
+// be read without network access. The first sync fetches every issue; later
+// ones fetch only issues updated since the newest one already cached. With
+// force, every issue is fetched again and cached issues that no longer
+// exist on Linear are removed.
+// Implements the backend.Syncer interface.
+func (l *Linear) Sync(force bool) (*backend.SyncResult, error) {
+	if !l.connected {
+		return nil, errors.New("not connected")
+	}
+	if l.cachePath == "" {
+		return nil, errors.New("no cache directory configured for linear sync")
+	}
+
+	cache := local.New()
+	if err := cache.Connect(backend.Config{Workspace: &local.WorkspaceConfig{Path: l.cachePath}}); err != nil {
+		return nil, fmt.Errorf("failed to open cache: %w", err)
+	}
+	defer cache.Disconnect()
+
+	// Keep the cache out of git even when it sits inside a synced backlog
+	if err := os.WriteFile(filepath.Join(l.cachePath, ".gitignore"), []byte("*\n"), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write cache: %w", err)
+	}
+
+	var since time.Time
+	if !force {
+		since = readLastSync(l.cachePath)
+	}
+
+	result := &backend.SyncResult{}
+	seen := make(map[string]bool)
+	newest := since
+	after := ""
+	for {
+		issues, next, err := l.listIssuesUpdatedSince(since, after)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, issue := range issues {
+			task := l.issueToTask(issue)
+			created, err := cache.Put(task)
+			if err != nil {
+				return nil, fmt.Errorf("failed to cache %s: %w", task.ID, err)
+			}
+			if created {
+				result.Created++
+			} else {
+				result.Updated++
+			}
+			seen[task.ID] = true
+
+			if t, err := time.Parse(time.RFC3339Nano, getString(issue, "updatedAt")); err == nil && t.After(newest) {
+				newest = t
+			}
+		}
+
+		if next == "" {
+			break
+		}
+		after = next
+	}
+
+	// A full sync saw every issue, so anything else was deleted or archived
+	if since.IsZero() {
+		cached, err := cache.List(backend.TaskFilters{IncludeDone: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list cache: %w", err)
+		}
+		for _, task := range cached.Tasks {
+			if seen[task.ID] {
+				continue
+			}
+			if err := cache.Delete(task.ID); err != nil {
+				return nil, fmt.Errorf("failed to remove %s from cache: %w", task.ID, err)
+			}
+			result.Deleted++
+		}
+	}
+
+	if newest.After(since) {
+		if err := writeLastSync(l.cachePath, newest); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// listIssuesUpdatedSince fetches one page of the team's issues, restricted
+// to those updated after since unless it is zero. It returns the cursor of
+// the next page, or "" on the last one.
+func (l *Linear) listIssuesUpdatedSince(since time.Time, after string) ([]map[string]any, string, error) {
+	query := `
+		query SyncIssues($first: Int, $after: String, $filter: IssueFilter) {
+			issues(first: $first, after: $after, filter: $filter) {
+				nodes {
+					id
+					identifier
+					title
+					description
+					priority
+					estimate
+					sortOrder
+					prioritySortOrder
+					url
+					createdAt
+					updatedAt
+					state {
+						id
+						name
+					}
+					assignee {
+						id
+						name
+						displayName
+					}
+					labels {
+						nodes {
+							id
+							name
+						}
+					}
+					team {
+						id
+						key
+					}
+					parent {
+						identifier
+					}
+				}
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+			}
+		}
+	`
+
+	filter := make(map[string]any)
+	if l.teamID != "" {
+		filter["team"] = map[string]any{"id": map[string]any{"eq": l.teamID}}
+	}
+	if !since.IsZero() {
+		filter["updatedAt"] = map[string]any{"gt": since.Format(time.RFC3339Nano)}
+	}
+
+	variables := map[string]any{"first": syncPageSize}
+	if after != "" {
+		variables["after"] = after
+	}
+	if len(filter) > 0 {
+		variables["filter"] = filter
+	}
+
+	result, err := l.graphQL(query, variables)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list issues: %w", err)
+	}
+
+	data, _ := result["data"].(map[string]any)
+	issuesData, ok := data["issues"].(map[string]any)
+	if !ok {
+		return nil, "", errors.New("unexpected response format: missing issues")
+	}
+	nodes, _ := issuesData["nodes"].([]any)
+
+	issues := make([]map[string]any, 0, len(nodes))
+	for _, node := range nodes {
+		if issue, ok := node.(map[string]any); ok {
+			issues = append(issues, issue)
+		}
+	}
+
+	pageInfo, _ := issuesData["pageInfo"].(map[string]any)
+	if hasNext, _ := pageInfo["hasNextPage"].(bool); hasNext {
+		next := getString(pageInfo, "endCursor")
+		if next == "" {
+			return nil, "", errors.New("unexpected response format: missing endCursor")
+		}
+		return issues, next, nil
+	}
+	return issues, "", nil
+}
+
+// readLastSync returns the timestamp recorded by the previous sync, or the
+// zero time if there is none.
+func readLastSync(cachePath string) time.Time {
+	data, err := os.ReadFile(filepath.Join(cachePath, lastSyncFile))
+	if err != nil {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(data)))
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// writeLastSync records the updatedAt of the newest cached issue.
+func writeLastSync(cachePath string, t time.Time) error {
+	path := filepath.Join(cachePath, lastSyncFile)
+	if err := os.WriteFile(path, []byte(t.UTC().Format(time.RFC3339Nano)+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to record sync time: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package linear
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+func TestSync(t *testing.T) {
+	issues := []map[string]any{
+		{"id": "a", "identifier": "ENG-1", "title": "First", "updatedAt": "2025-01-01T10:00:00.000Z", "state": map[string]any{"name": "Todo"}},
+		{"id": "b", "identifier": "ENG-2", "title": "Second", "updatedAt": "2025-01-02T10:00:00.000Z", "state": map[string]any{"name": "Done"}},
+	}
+
+	var filters []map[string]any
+	server := mockLinearServer(t, func(query string, variables map[string]any) any {
+		filter, _ := variables["filter"].(map[string]any)
+		filters = append(filters, filter)
+
+		// One issue per page, so the sync has to follow the cursor
+		page, next := issues, ""
+		if filter["updatedAt"] != nil {
+			page = nil
+		} else if variables["after"] == nil {
+			page, next = issues[:1], "cursor-1"
+		} else {
+			page = issues[1:]
+		}
+
+		nodes := make([]any, len(page))
+		for i, issue := range page {
+			nodes[i] = issue
+		}
+		return map[string]any{"data": map[string]any{"issues": map[string]any{
+			"nodes":    nodes,
+			"pageInfo": map[string]any{"hasNextPage": next != "", "endCursor": next},
+		}}}
+	})
+	defer server.Close()
+
+	l := New()
+	l.apiEndpoint = server.URL
+	l.apiKey = "test-key"
+	l.connected = true
+	l.cachePath = filepath.Join(t.TempDir(), "cache")
+	l.reverseStatusMap = make(map[string]backend.Status)
+	for state, status := range defaultStatusMapping {
+		l.reverseStatusMap[strings.ToLower(state)] = status
+	}
+
+	result, err := l.Sync(false)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if result.Created != 2 {
+		t.Errorf("Created = %d, want 2", result.Created)
+	}
+	if _, err := os.Stat(filepath.Join(l.cachePath, "done", "ENG-2-second.md")); err != nil {
+		t.Errorf("ENG-2 not cached in done: %v", err)
+	}
+	if got := readLastSync(l.cachePath).Format("2006-01-02"); got != "2025-01-02" {
+		t.Errorf("last sync = %s, want the newest updatedAt", got)
+	}
+
+	// The next sync asks only for issues updated since the newest one
+	filters = nil
+	result, err = l.Sync(false)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if result.Created+result.Updated+result.Deleted != 0 {
+		t.Errorf("incremental sync changed the cache: %+v", result)
+	}
+	updatedAt, _ := filters[0]["updatedAt"].(map[string]any)
+	if gt, _ := updatedAt["gt"].(string); !strings.HasPrefix(gt, "2025-01-02T10:00:00") {
+		t.Errorf("updatedAt filter = %v, want issues after 2025-01-02T10:00:00", filters[0]["updatedAt"])
+	}
+
+	// A forced sync refetches everything and drops issues that are gone
+	issues = issues[:1]
+	result, err = l.Sync(true)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if result.Updated != 1 || result.Deleted != 1 {
+		t.Errorf("forced sync = %+v, want 1 updated and 1 deleted", result)
+	}
+}
@@ -0,0 +1,141 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// batchPushAttempts bounds how many times a batch claim in git lock mode is
+// pushed. After each rejected push the batch is rebuilt on top of the
+// remote, dropping tasks another agent claimed in the meantime.
+const batchPushAttempts = 3
+
+// ClaimBatch claims up to count of the candidate tasks, trying them in order.
+// Candidates claimed by another agent or deleted are skipped. In git lock
+// mode the batch is claimed with a single pull, commit, and push.
+// Implements the backend.BatchClaimer interface.
+func (l *Local) ClaimBatch(ids []string, agentID string, count int) (*backend.BatchClaimResult, error) {
+	if !l.connected {
+		return nil, errors.New("not connected")
+	}
+
+	// Use the provided agentID, or fall back to the configured one
+	if agentID == "" {
+		agentID = l.agentID
+	} else {
+		// Update l.agentID for use in gitCommit message
+		l.agentID = agentID
+	}
+
+	if l.lockMode == LockModeGit {
+		return l.claimBatchWithGit(ids, agentID, count)
+	}
+
+	result := &backend.BatchClaimResult{Claimed: []backend.ClaimResult{}, Skipped: []backend.SkippedCandidate{}}
+	for _, id := range ids {
+		if len(result.Claimed) == count {
+			break
+		}
+		claim, _, err := l.claimWithFileLock(id, agentID, claimOptions{})
+		if err != nil {
+			if skipped, ok := skippedCandidate(id, err); ok {
+				result.Skipped = append(result.Skipped, skipped)
+				continue
+			}
+			return nil, err
+		}
+		result.Claimed = append(result.Claimed, *claim)
+	}
+	return result, nil
+}
+
+// claimBatchWithGit claims a batch in git lock mode: pull once, claim each
+// candidate in the working tree, then commit and push once. When the push is
+// rejected, the commit is dropped and the batch is retried after pulling
+// again, so only the tasks another agent took in the meantime are lost.
+func (l *Local) claimBatchWithGit(ids []string, agentID string, count int) (*backend.BatchClaimResult, error) {
+	result := &backend.BatchClaimResult{Claimed: []backend.ClaimResult{}, Skipped: []backend.SkippedCandidate{}}
+	pending := ids
+
+	for attempt := 1; ; attempt++ {
+		if err := l.gitPull(); err != nil {
+			return nil, fmt.Errorf("failed to pull: %w", err)
+		}
+		base, _ := l.gitHead()
+
+		var batch []backend.ClaimResult
+		tried := 0
+		for _, id := range pending {
+			if len(result.Claimed)+len(batch) == count {
+				break
+			}
+			tried++
+			claim, _, err := l.claimInWorkingTree(id, agentID, claimOptions{})
+			if err != nil {
+				if skipped, ok := skippedCandidate(id, err); ok {
+					result.Skipped = append(result.Skipped, skipped)
+					continue
+				}
+				if base != "" {
+					l.gitResetHard(base)
+				}
+				return nil, err
+			}
+			if claim.AlreadyOwned {
+				// Nothing to commit for a task this agent already holds
+				result.Claimed = append(result.Claimed, *claim)
+				continue
+			}
+			batch = append(batch, *claim)
+		}
+		if len(batch) == 0 {
+			return result, nil
+		}
+
+		batchIDs := make([]string, len(batch))
+		for i, claim := range batch {
+			batchIDs[i] = claim.Task.ID
+		}
+		if err := l.gitCommit("claim", strings.Join(batchIDs, ", ")); err != nil {
+			return nil, fmt.Errorf("failed to commit: %w", err)
+		}
+
+		err := l.gitPush()
+		if err == nil {
+			result.Claimed = append(result.Claimed, batch...)
+			return result, nil
+		}
+		if _, isConflict := err.(*GitPushConflictError); !isConflict {
+			return nil, fmt.Errorf("failed to push: %w", err)
+		}
+
+		// Another agent pushed first; drop our commit and try again
+		if base != "" {
+			l.gitResetHard(base)
+		}
+		if attempt == batchPushAttempts {
+			for _, id := range batchIDs {
+				conflict := &ClaimConflictError{TaskID: id, ClaimedBy: "another agent (push conflict)", CurrentAgent: agentID}
+				result.Skipped = append(result.Skipped, backend.SkippedCandidate{ID: id, Reason: conflict.Error()})
+			}
+			return result, nil
+		}
+		pending = append(batchIDs, pending[tried:]...)
+	}
+}
+
+// skippedCandidate reports whether a claim error means the candidate should
+// be passed over in favor of the next one, and why.
+func skippedCandidate(id string, err error) (backend.SkippedCandidate, bool) {
+	if _, ok := err.(*ClaimConflictError); ok {
+		return backend.SkippedCandidate{ID: id, Reason: err.Error()}, true
+	}
+	if backend.IsNotFound(err) {
+		// Deleted since it was listed
+		return backend.SkippedCandidate{ID: id, Reason: "task no longer exists"}, true
+	}
+	return backend.SkippedCandidate{}, false
+}
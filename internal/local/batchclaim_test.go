@@ -0,0 +1,48 @@
+package local
+
+import (
+	"testing"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+func TestClaimBatch(t *testing.T) {
+	l, _ := setupBacklog(t)
+
+	var ids []string
+	for _, title := range []string{"First", "Second", "Third", "Fourth"} {
+		task, err := l.Create(backend.TaskInput{Title: title, Status: backend.StatusTodo})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		ids = append(ids, task.ID)
+	}
+
+	// Another agent holds the second task
+	if _, err := l.Claim(ids[1], "other-agent"); err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+
+	result, err := l.ClaimBatch(append([]string{"999"}, ids...), "test-agent", 2)
+	if err != nil {
+		t.Fatalf("ClaimBatch() error = %v", err)
+	}
+
+	if len(result.Claimed) != 2 || result.Claimed[0].Task.ID != ids[0] || result.Claimed[1].Task.ID != ids[2] {
+		t.Fatalf("claimed = %+v, want %s and %s", result.Claimed, ids[0], ids[2])
+	}
+	for _, claim := range result.Claimed {
+		if claim.Task.Status != backend.StatusInProgress {
+			t.Errorf("task %s status = %s, want in-progress", claim.Task.ID, claim.Task.Status)
+		}
+	}
+
+	if len(result.Skipped) != 2 || result.Skipped[0].ID != "999" || result.Skipped[1].ID != ids[1] {
+		t.Errorf("skipped = %+v, want 999 and %s", result.Skipped, ids[1])
+	}
+
+	// The batch stops once count tasks are claimed
+	if task, _ := l.Get(ids[3]); task.Status != backend.StatusTodo {
+		t.Errorf("task %s status = %s, want todo", ids[3], task.Status)
+	}
+}
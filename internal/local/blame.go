@@ -0,0 +1,125 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// blameFields are the task fields blame attributes, in output order.
+var blameFields = []struct {
+	name  string
+	value func(*backend.Task) string
+}{
+	{"title", func(t *backend.Task) string { return t.Title }},
+	{"status", func(t *backend.Task) string { return string(t.Status) }},
+	{"priority", func(t *backend.Task) string { return string(t.Priority) }},
+	{"assignee", func(t *backend.Task) string { return t.Assignee }},
+	{"labels", func(t *backend.Task) string { return strings.Join(t.Labels, ", ") }},
+	{"parent", func(t *backend.Task) string { return t.Parent }},
+}
+
+// Blame attributes each field of the task to the commit that last changed
+// it, by comparing the task file at each commit in its git log, oldest
+// first, followed across renames. A field whose value in the working tree
+// differs from the last commit is marked uncommitted. Without git_sync, or
+// when git has no history for the file, fields are returned unattributed
+// along with a note explaining why. Implements the backend.Blamer interface.
+func (l *Local) Blame(id string) (*backend.TaskBlame, error) {
+	if !l.connected {
+		return nil, errors.New("not connected")
+	}
+
+	task, err := l.findTask(id)
+	if err != nil {
+		return nil, err
+	}
+	filePath, err := l.findTaskFile(id)
+	if err != nil {
+		return nil, err
+	}
+
+	blame := &backend.TaskBlame{TaskID: task.ID}
+	for _, field := range blameFields {
+		blame.Fields = append(blame.Fields, backend.FieldBlame{Field: field.name, Value: field.value(task)})
+	}
+
+	if !l.gitSync {
+		blame.Note = "git_sync is disabled, so changes can't be attributed"
+		return blame, nil
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		blame.Note = "git is not available, so changes can't be attributed"
+		return blame, nil
+	}
+	commits, err := l.gitFileLog(filePath)
+	if err != nil {
+		blame.Note = fmt.Sprintf("git history is unavailable (%v), so changes can't be attributed", err)
+		return blame, nil
+	}
+	if len(commits) == 0 {
+		blame.Note = "the task file has not been committed yet"
+		return blame, nil
+	}
+
+	// Walk the log oldest first, so the last commit to change each field wins
+	var committed []string
+	for i := len(commits) - 1; i >= 0; i-- {
+		entry := commits[i].entry
+		values, ok := l.blameValuesAt(entry.Commit, commits[i].paths)
+		if !ok {
+			continue
+		}
+		for j, value := range values {
+			if committed != nil && committed[j] == value {
+				continue
+			}
+			timestamp := entry.Timestamp
+			field := &blame.Fields[j]
+			field.Commit = entry.Commit
+			field.Author = entry.Author
+			field.Agent = entry.Agent
+			field.Timestamp = &timestamp
+			field.Message = entry.Message
+		}
+		committed = values
+	}
+
+	for j, field := range blame.Fields {
+		if committed == nil || committed[j] != field.Value {
+			blame.Fields[j] = backend.FieldBlame{Field: field.Field, Value: field.Value, Uncommitted: true}
+		}
+	}
+	return blame, nil
+}
+
+// blameValuesAt returns the blamed field values of the task file a commit
+// left behind, or false if the commit deleted it or it can't be parsed.
+// Paths come from the commit's name-status, the last being the file's name
+// after the commit.
+func (l *Local) blameValuesAt(commit string, paths []string) ([]string, bool) {
+	if len(paths) == 0 {
+		return nil, false
+	}
+	path := paths[len(paths)-1]
+	// Paths in "<rev>:<path>" are relative to the top of the repository
+	content, err := l.git("show", commit+":"+path)
+	if err != nil {
+		return nil, false
+	}
+	task, err := UnmarshalTask([]byte(content))
+	if err != nil {
+		return nil, false
+	}
+	task.Status = backend.Status(filepath.Base(filepath.Dir(path)))
+
+	values := make([]string, len(blameFields))
+	for i, field := range blameFields {
+		values[i] = field.value(task)
+	}
+	return values, true
+}
@@ -0,0 +1,94 @@
+package local
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+func TestBlame(t *testing.T) {
+	remoteDir := filepath.Join(t.TempDir(), "remote.git")
+	if out, err := exec.Command("git", "init", "-q", "--bare", "-b", "main", remoteDir).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v\n%s", err, out)
+	}
+	l, repoDir := setupGitBacklog(t, remoteDir, time.Minute)
+	setAuthor := func(name string) {
+		t.Helper()
+		if out, err := exec.Command("git", "-C", repoDir, "config", "user.name", name).CombinedOutput(); err != nil {
+			t.Fatalf("git config: %v\n%s", err, out)
+		}
+	}
+
+	setAuthor("Alice")
+	task, err := l.Create(backend.TaskInput{Title: "Fix login", Priority: backend.PriorityHigh, Labels: []string{"bug"}})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	setAuthor("Bob")
+	l.agentID = "claude-1"
+	if _, err := l.Claim(task.ID, "claude-1"); err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+	setAuthor("Carol")
+	urgent := backend.PriorityUrgent
+	if _, err := l.Update(task.ID, backend.TaskChanges{Priority: &urgent}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	// Reassign the task without committing it
+	current, _ := l.findTask(task.ID)
+	current.Assignee = "alice"
+	if err := l.writeTask(current); err != nil {
+		t.Fatalf("writeTask() error = %v", err)
+	}
+
+	blame, err := l.Blame(task.ID)
+	if err != nil {
+		t.Fatalf("Blame() error = %v", err)
+	}
+	if blame.Note != "" {
+		t.Errorf("Note = %q, want none", blame.Note)
+	}
+	fields := make(map[string]backend.FieldBlame)
+	for _, field := range blame.Fields {
+		fields[field.Field] = field
+	}
+
+	if f := fields["title"]; f.Author != "Alice" || f.Value != "Fix login" || f.Message != "add: "+task.ID {
+		t.Errorf("title = %+v, want set by Alice's add", f)
+	}
+	if f := fields["status"]; f.Author != "Bob" || f.Agent != "claude-1" || f.Value != string(backend.StatusInProgress) {
+		t.Errorf("status = %+v, want set by Bob's claim as claude-1", f)
+	}
+	if f := fields["labels"]; f.Author != "Bob" || f.Value != "agent:claude-1, bug" {
+		t.Errorf("labels = %+v, want the agent label added by Bob's claim", f)
+	}
+	if f := fields["priority"]; f.Author != "Carol" || f.Value != "urgent" || f.Timestamp == nil {
+		t.Errorf("priority = %+v, want set by Carol's edit", f)
+	}
+	if f := fields["assignee"]; !f.Uncommitted || f.Commit != "" || f.Value != "alice" {
+		t.Errorf("assignee = %+v, want the uncommitted value", f)
+	}
+}
+
+func TestBlameWithoutGitSync(t *testing.T) {
+	l, _ := setupBacklog(t)
+	task, err := l.Create(backend.TaskInput{Title: "No git here"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	blame, err := l.Blame(task.ID)
+	if err != nil {
+		t.Fatalf("Blame() error = %v", err)
+	}
+	if blame.Note == "" || len(blame.Fields) == 0 || blame.Fields[0].Commit != "" {
+		t.Errorf("Blame() = %+v, want unattributed fields with a note", blame)
+	}
+	if _, err := l.Blame("999"); err == nil {
+		t.Error("expected error for missing task")
+	}
+}
@@ -0,0 +1,35 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// UncommittedFiles returns the backlog files with changes git has not
+// committed, staged or not, including untracked task files. Paths are
+// relative to the repository root. The index, undo log, and credentials
+// are never committed and are not reported. Implements the
+// backend.CleanChecker interface.
+func (l *Local) UncommittedFiles() ([]string, error) {
+	if !l.connected {
+		return nil, errors.New("not connected")
+	}
+	if _, err := l.git("rev-parse", "--git-dir"); err != nil {
+		if isGitTimeout(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s is not in a git repository", filepath.Dir(l.path))
+	}
+
+	files, err := l.uncommittedFiles(l.path)
+	if err != nil {
+		return nil, err
+	}
+	if files == nil {
+		files = []string{}
+	}
+	sort.Strings(files)
+	return files, nil
+}
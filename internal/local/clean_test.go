@@ -0,0 +1,105 @@
+package local
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+func TestUncommittedFiles(t *testing.T) {
+	l, repoDir := setupGitBacklog(t, filepath.Join(t.TempDir(), "remote.git"), time.Minute)
+
+	// Tasks created with git_sync are committed straight away
+	task, err := l.Create(backend.TaskInput{Title: "Fix login"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	files, err := l.UncommittedFiles()
+	if err != nil {
+		t.Fatalf("UncommittedFiles() error = %v", err)
+	}
+	if len(files) != 0 {
+		t.Fatalf("UncommittedFiles() = %v, want none after a committed create", files)
+	}
+
+	// Changes outside the backlog and to files never committed don't count
+	for _, path := range []string{
+		filepath.Join(repoDir, "main.go"),
+		filepath.Join(l.path, "credentials.yaml"),
+	} {
+		if err := os.WriteFile(path, []byte("x\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files, err = l.UncommittedFiles()
+	if err != nil {
+		t.Fatalf("UncommittedFiles() error = %v", err)
+	}
+	if len(files) != 0 {
+		t.Fatalf("UncommittedFiles() = %v, want files outside the backlog ignored", files)
+	}
+
+	// An edited task file and an untracked one in a new directory are listed
+	taskPath := filepath.Join(l.path, string(task.Status), generateFilename(task.ID, task.Title))
+	data, err := os.ReadFile(taskPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(taskPath, append(data, "More details\n"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(l.path, "archive"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(l.path, "archive", "old task.md"), []byte("x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err = l.UncommittedFiles()
+	if err != nil {
+		t.Fatalf("UncommittedFiles() error = %v", err)
+	}
+	want := []string{
+		".backlog/archive/old task.md",
+		".backlog/" + string(task.Status) + "/" + generateFilename(task.ID, task.Title),
+	}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Errorf("UncommittedFiles() = %q, want %q", files, want)
+	}
+}
+
+func TestUncommittedFilesOutsideGit(t *testing.T) {
+	l, _ := setupBacklog(t)
+	if _, err := l.UncommittedFiles(); err == nil || !strings.Contains(err.Error(), "not in a git repository") {
+		t.Errorf("UncommittedFiles() error = %v, want not in a git repository", err)
+	}
+}
+
+func TestUncommittedFilesRename(t *testing.T) {
+	l, repoDir := setupGitBacklog(t, filepath.Join(t.TempDir(), "remote.git"), time.Minute)
+	task, err := l.Create(backend.TaskInput{Title: "Fix login"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	oldPath := filepath.Join(".backlog", string(task.Status), generateFilename(task.ID, task.Title))
+	newPath := filepath.Join(".backlog", string(task.Status), generateFilename(task.ID, "Renamed"))
+	if out, err := exec.Command("git", "-C", repoDir, "mv", oldPath, newPath).CombinedOutput(); err != nil {
+		t.Fatalf("git mv: %v\n%s", err, out)
+	}
+
+	files, err := l.UncommittedFiles()
+	if err != nil {
+		t.Fatalf("UncommittedFiles() error = %v", err)
+	}
+	if len(files) != 1 || files[0] != filepath.ToSlash(newPath) {
+		t.Errorf("UncommittedFiles() = %q, want only the renamed file", files)
+	}
+	if dirty, err := l.hasUncommittedChanges(); err != nil || !dirty {
+		t.Errorf("hasUncommittedChanges() = %v, %v; want dirty", dirty, err)
+	}
+}
@@ -0,0 +1,368 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// Doctor check names.
+const (
+	checkDuplicateID      = "duplicate_id"
+	checkStatusMismatch   = "status_mismatch"
+	checkUnparseable      = "unparseable"
+	checkOrphanedLock     = "orphaned_lock"
+	checkDanglingRelation = "dangling_relation"
+	checkFilenameMismatch = "filename_mismatch"
+)
+
+// scannedFile is a task file found while diagnosing the workspace.
+type scannedFile struct {
+	path   string
+	status backend.Status
+	task   *backend.Task
+}
+
+// Diagnose scans the workspace for integrity problems. With opts.Fix set it
+// repairs status mismatches, orphaned locks, and dangling relations; other
+// problems need a human decision and are only reported. Repairs are recorded
+// as a single "doctor:" commit when git_sync is enabled.
+func (l *Local) Diagnose(opts backend.DoctorOptions) (*backend.DoctorReport, error) {
+	if !l.connected {
+		return nil, errors.New("not connected")
+	}
+
+	if opts.StatusSource == "" {
+		opts.StatusSource = backend.StatusSourceDirectory
+	}
+	if opts.StatusSource != backend.StatusSourceDirectory && opts.StatusSource != backend.StatusSourceFrontmatter {
+		return nil, fmt.Errorf("invalid status source: %s", opts.StatusSource)
+	}
+
+	if opts.Fix {
+		if err := l.checkGitSyncState(); err != nil {
+			return nil, err
+		}
+	}
+
+	files, findings, err := l.scanTaskFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	// Index tasks by ID. Unparseable files still claim the ID in their
+	// filename so relations and locks pointing at them aren't pruned.
+	byID := make(map[string][]*scannedFile)
+	knownIDs := make(map[string]bool)
+	for i := range files {
+		f := &files[i]
+		if f.task == nil {
+			knownIDs[strings.SplitN(strings.TrimSuffix(filepath.Base(f.path), ".md"), "-", 2)[0]] = true
+			continue
+		}
+		byID[f.task.ID] = append(byID[f.task.ID], f)
+		knownIDs[f.task.ID] = true
+	}
+	// Soft-deleted tasks can be restored, so references to them are kept
+	for _, id := range l.trashedIDs() {
+		knownIDs[id] = true
+	}
+
+	nextID, err := l.generateID()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range files {
+		f := &files[i]
+		if f.task == nil {
+			continue
+		}
+		task := f.task
+		rel := l.relPath(f.path)
+
+		// Files that need manual attention are never rewritten, since a
+		// rewrite would pick a new filename or clobber the other copy.
+		fixable := true
+
+		if len(byID[task.ID]) > 1 {
+			fixable = false
+			if byID[task.ID][0] == f {
+				var paths []string
+				for _, dup := range byID[task.ID] {
+					paths = append(paths, l.relPath(dup.path))
+				}
+				findings = append(findings, backend.DoctorFinding{
+					Check:      checkDuplicateID,
+					Severity:   backend.SeverityError,
+					TaskID:     task.ID,
+					Path:       rel,
+					Message:    fmt.Sprintf("task ID %s is used by %d files: %s", task.ID, len(paths), strings.Join(paths, ", ")),
+					Suggestion: fmt.Sprintf("give all but one of the files a new ID (next free: %s) and rename them to match", nextID),
+				})
+			}
+		}
+
+		baseName := strings.TrimSuffix(filepath.Base(f.path), ".md")
+		if !taskFileMatchesID(baseName, task.ID) {
+			fixable = false
+			findings = append(findings, backend.DoctorFinding{
+				Check:      checkFilenameMismatch,
+				Severity:   backend.SeverityError,
+				TaskID:     task.ID,
+				Path:       rel,
+				Message:    fmt.Sprintf("filename does not match task ID %s, so the task cannot be looked up by ID", task.ID),
+				Suggestion: fmt.Sprintf("rename the file to %s", generateFilename(task.ID, task.Title)),
+			})
+		}
+
+		// pending holds indexes of findings repaired by rewriting this file
+		var pending []int
+		newStatus := f.status
+
+		if extra, ok := task.Meta[extraMetaKey].(map[string]any); ok {
+			if recorded, ok := extra["status"]; ok && fmt.Sprint(recorded) != string(f.status) {
+				recordedStatus := backend.Status(fmt.Sprint(recorded))
+				finding := backend.DoctorFinding{
+					Check:    checkStatusMismatch,
+					Severity: backend.SeverityWarning,
+					TaskID:   task.ID,
+					Path:     rel,
+					Message:  fmt.Sprintf("frontmatter status %q does not match directory %q", recordedStatus, f.status),
+				}
+				repairable := false
+				switch {
+				case opts.StatusSource == backend.StatusSourceDirectory:
+					finding.Suggestion = fmt.Sprintf("set the frontmatter status to %q", f.status)
+					extra["status"] = string(f.status)
+					repairable = true
+				case !recordedStatus.IsValid():
+					finding.Suggestion = "fix the frontmatter status by hand, or use --status-source=directory"
+				case l.statusHasTask(recordedStatus, task.ID):
+					finding.Suggestion = fmt.Sprintf("resolve the conflicting copy of task %s in %s/ by hand", task.ID, recordedStatus)
+				default:
+					finding.Suggestion = fmt.Sprintf("move the file to %s/", recordedStatus)
+					newStatus = recordedStatus
+					repairable = true
+				}
+				findings = append(findings, finding)
+				if repairable {
+					pending = append(pending, len(findings)-1)
+				}
+			}
+		}
+
+		for _, key := range []string{"blocks", "blocked_by"} {
+			targets := metaStringSlice(task.Meta, key)
+			var kept []string
+			for _, target := range targets {
+				if knownIDs[target] {
+					kept = append(kept, target)
+					continue
+				}
+				findings = append(findings, backend.DoctorFinding{
+					Check:      checkDanglingRelation,
+					Severity:   backend.SeverityWarning,
+					TaskID:     task.ID,
+					Path:       rel,
+					Message:    fmt.Sprintf("%s references missing task %s", key, target),
+					Suggestion: fmt.Sprintf("remove %s from %s", target, key),
+				})
+				pending = append(pending, len(findings)-1)
+			}
+			if len(kept) == 0 {
+				delete(task.Meta, key)
+			} else if len(kept) != len(targets) {
+				task.Meta[key] = kept
+			}
+		}
+
+		if task.Parent != "" && !knownIDs[task.Parent] {
+			findings = append(findings, backend.DoctorFinding{
+				Check:      checkDanglingRelation,
+				Severity:   backend.SeverityWarning,
+				TaskID:     task.ID,
+				Path:       rel,
+				Message:    fmt.Sprintf("parent references missing task %s", task.Parent),
+				Suggestion: "clear the parent",
+			})
+			pending = append(pending, len(findings)-1)
+			task.Parent = ""
+		}
+
+		if !opts.Fix || !fixable || len(pending) == 0 {
+			continue
+		}
+
+		task.Status = newStatus
+		l.recordUndo(f.path)
+		if err := os.Remove(f.path); err != nil {
+			return nil, fmt.Errorf("failed to remove old task file: %w", err)
+		}
+		if err := l.writeTask(task); err != nil {
+			return nil, fmt.Errorf("failed to write task: %w", err)
+		}
+		for _, idx := range pending {
+			findings[idx].Fixed = true
+		}
+	}
+
+	lockFindings, err := l.diagnoseLocks(knownIDs, opts.Fix)
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, lockFindings...)
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Path < findings[j].Path
+	})
+
+	report := &backend.DoctorReport{Findings: findings, OK: true}
+	for _, finding := range findings {
+		if finding.Fixed {
+			report.Fixed++
+		} else {
+			report.OK = false
+		}
+	}
+
+	if report.Fixed > 0 {
+		summary := fmt.Sprintf("fixed %d issues", report.Fixed)
+		if report.Fixed == 1 {
+			summary = "fixed 1 issue"
+		}
+		if err := l.gitCommit("doctor", summary); err != nil {
+			return nil, fmt.Errorf("failed to commit: %w", err)
+		}
+		if err := l.pushSyncChanges(); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// scanTaskFiles reads every task file in the status directories. Files that
+// cannot be parsed are returned without a task and reported as findings.
+func (l *Local) scanTaskFiles() ([]scannedFile, []backend.DoctorFinding, error) {
+	var files []scannedFile
+	findings := []backend.DoctorFinding{}
+
+	for _, status := range []backend.Status{
+		backend.StatusBacklog,
+		backend.StatusTodo,
+		backend.StatusInProgress,
+		backend.StatusReview,
+		backend.StatusDone,
+	} {
+		dirPath := filepath.Join(l.path, string(status))
+		entries, err := os.ReadDir(dirPath)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read directory %s: %w", dirPath, err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") || l.isIgnored(status, entry.Name()) {
+				continue
+			}
+
+			filePath := filepath.Join(dirPath, entry.Name())
+			task, err := l.readTaskFile(filePath, status)
+			if err != nil {
+				message := err.Error()
+				var parseErr *ParseError
+				if errors.As(err, &parseErr) {
+					message = parseErr.Err.Error()
+					if parseErr.Line > 0 {
+						message = fmt.Sprintf("line %d: %s", parseErr.Line, message)
+					}
+				}
+				findings = append(findings, backend.DoctorFinding{
+					Check:      checkUnparseable,
+					Severity:   backend.SeverityError,
+					Path:       l.relPath(filePath),
+					Message:    message,
+					Suggestion: "fix the frontmatter by hand, or add the file to .backlogignore if it is not a task",
+				})
+			}
+			files = append(files, scannedFile{path: filePath, status: status, task: task})
+		}
+	}
+
+	return files, findings, nil
+}
+
+// diagnoseLocks reports lock files for tasks that no longer exist, removing
+// them when fix is set.
+func (l *Local) diagnoseLocks(knownIDs map[string]bool, fix bool) ([]backend.DoctorFinding, error) {
+	entries, err := os.ReadDir(filepath.Join(l.path, locksDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read locks directory: %w", err)
+	}
+
+	var findings []backend.DoctorFinding
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".lock") {
+			continue
+		}
+		id := strings.TrimSuffix(entry.Name(), ".lock")
+		if knownIDs[id] || id == createLockID {
+			continue
+		}
+
+		finding := backend.DoctorFinding{
+			Check:      checkOrphanedLock,
+			Severity:   backend.SeverityWarning,
+			TaskID:     id,
+			Path:       l.relPath(l.lockFilePath(id)),
+			Message:    fmt.Sprintf("lock file for missing task %s", id),
+			Suggestion: "remove the lock file",
+		}
+		if fix {
+			if err := os.Remove(l.lockFilePath(id)); err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("failed to remove lock file: %w", err)
+			}
+			finding.Fixed = true
+		}
+		findings = append(findings, finding)
+	}
+
+	return findings, nil
+}
+
+// statusHasTask reports whether a status directory already holds a file for
+// the task ID.
+func (l *Local) statusHasTask(status backend.Status, id string) bool {
+	entries, err := os.ReadDir(filepath.Join(l.path, string(status)))
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		baseName := strings.TrimSuffix(entry.Name(), ".md")
+		if taskFileMatchesID(baseName, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// relPath returns a path relative to the backlog directory, using forward
+// slashes so reports are stable across platforms.
+func (l *Local) relPath(path string) string {
+	rel, err := filepath.Rel(l.path, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
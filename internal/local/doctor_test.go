@@ -0,0 +1,240 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func findingsByCheck(report *backend.DoctorReport) map[string][]backend.DoctorFinding {
+	byCheck := make(map[string][]backend.DoctorFinding)
+	for _, f := range report.Findings {
+		byCheck[f.Check] = append(byCheck[f.Check], f)
+	}
+	return byCheck
+}
+
+func TestDiagnoseCleanWorkspace(t *testing.T) {
+	l, _ := setupBacklog(t)
+
+	task1, _ := l.Create(backend.TaskInput{Title: "Task 1"})
+	task2, _ := l.Create(backend.TaskInput{Title: "Task 2"})
+	if _, err := l.Link(task1.ID, task2.ID, backend.RelationBlocks); err != nil {
+		t.Fatalf("Link() error = %v", err)
+	}
+
+	report, err := l.Diagnose(backend.DoctorOptions{})
+	if err != nil {
+		t.Fatalf("Diagnose() error = %v", err)
+	}
+	if !report.OK || len(report.Findings) != 0 {
+		t.Errorf("Diagnose() = %+v, want a clean report", report)
+	}
+}
+
+func TestDiagnoseReportsProblems(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+
+	task1, _ := l.Create(backend.TaskInput{Title: "Task 1"})
+	task2, _ := l.Create(backend.TaskInput{Title: "Task 2"})
+	if _, err := l.Link(task1.ID, task2.ID, backend.RelationBlocks); err != nil {
+		t.Fatalf("Link() error = %v", err)
+	}
+	if err := l.Delete(task2.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	writeTestFile(t, filepath.Join(backlogDir, "todo", "001-copy.md"), "---\nid: \"001\"\ntitle: Copy\n---\n")
+	writeTestFile(t, filepath.Join(backlogDir, "todo", "notes.md"), "---\nid: \"005\"\ntitle: Notes\n---\n")
+	writeTestFile(t, filepath.Join(backlogDir, "review", "006-broken.md"), "---\ntitle: [oops\n---\n")
+	writeTestFile(t, filepath.Join(backlogDir, "done", "007-moved.md"), "---\nid: \"007\"\ntitle: Moved\nstatus: review\n---\n")
+	writeTestFile(t, filepath.Join(backlogDir, locksDir, "042.lock"), "agent: ghost\n")
+
+	report, err := l.Diagnose(backend.DoctorOptions{})
+	if err != nil {
+		t.Fatalf("Diagnose() error = %v", err)
+	}
+	if report.OK || report.Fixed != 0 {
+		t.Errorf("OK = %v, Fixed = %d; want false, 0", report.OK, report.Fixed)
+	}
+
+	byCheck := findingsByCheck(report)
+	want := map[string]struct {
+		taskID   string
+		path     string
+		severity backend.Severity
+	}{
+		checkDuplicateID:      {"001", "backlog/001-task-1.md", backend.SeverityError},
+		checkFilenameMismatch: {"005", "todo/notes.md", backend.SeverityError},
+		checkUnparseable:      {"", "review/006-broken.md", backend.SeverityError},
+		checkStatusMismatch:   {"007", "done/007-moved.md", backend.SeverityWarning},
+		checkDanglingRelation: {"001", "backlog/001-task-1.md", backend.SeverityWarning},
+		checkOrphanedLock:     {"042", ".locks/042.lock", backend.SeverityWarning},
+	}
+	for check, w := range want {
+		findings := byCheck[check]
+		if len(findings) != 1 {
+			t.Errorf("%s: got %d findings, want 1: %+v", check, len(findings), findings)
+			continue
+		}
+		f := findings[0]
+		if f.TaskID != w.taskID || f.Path != w.path || f.Severity != w.severity {
+			t.Errorf("%s = %+v, want task %q path %q severity %q", check, f, w.taskID, w.path, w.severity)
+		}
+		if f.Suggestion == "" {
+			t.Errorf("%s has no suggestion", check)
+		}
+	}
+	if len(report.Findings) != len(want) {
+		t.Errorf("got %d findings, want %d: %+v", len(report.Findings), len(want), report.Findings)
+	}
+
+	// Nothing should have changed on disk without Fix
+	if _, err := os.Stat(filepath.Join(backlogDir, locksDir, "042.lock")); err != nil {
+		t.Errorf("lock file should still exist: %v", err)
+	}
+}
+
+func TestDiagnoseFix(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+
+	task1, _ := l.Create(backend.TaskInput{Title: "Task 1"})
+	task2, _ := l.Create(backend.TaskInput{Title: "Task 2"})
+	if _, err := l.Link(task1.ID, task2.ID, backend.RelationBlocks); err != nil {
+		t.Fatalf("Link() error = %v", err)
+	}
+	if err := l.Delete(task2.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	writeTestFile(t, filepath.Join(backlogDir, "done", "007-moved.md"), "---\nid: \"007\"\ntitle: Moved\nstatus: review\nsprint: 3\n---\n")
+	writeTestFile(t, filepath.Join(backlogDir, locksDir, "042.lock"), "agent: ghost\n")
+
+	report, err := l.Diagnose(backend.DoctorOptions{Fix: true})
+	if err != nil {
+		t.Fatalf("Diagnose() error = %v", err)
+	}
+	if !report.OK || report.Fixed != 3 {
+		t.Errorf("OK = %v, Fixed = %d; want true, 3: %+v", report.OK, report.Fixed, report.Findings)
+	}
+
+	source, err := l.Get(task1.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if blocks := metaStringSlice(source.Meta, "blocks"); len(blocks) != 0 {
+		t.Errorf("blocks = %v, want dangling relation pruned", blocks)
+	}
+
+	moved, err := l.Get("007")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	extra, _ := moved.Meta[extraMetaKey].(map[string]any)
+	if moved.Status != backend.StatusDone || extra["status"] != "done" {
+		t.Errorf("task 007 status = %q, frontmatter status = %v; want done, done", moved.Status, extra["status"])
+	}
+	if extra["sprint"] != 3 {
+		t.Errorf("sprint = %v, want unknown fields preserved", extra["sprint"])
+	}
+
+	if _, err := os.Stat(filepath.Join(backlogDir, locksDir, "042.lock")); !os.IsNotExist(err) {
+		t.Errorf("orphaned lock should be removed, stat err = %v", err)
+	}
+
+	// A second run should be clean
+	report, err = l.Diagnose(backend.DoctorOptions{})
+	if err != nil {
+		t.Fatalf("Diagnose() error = %v", err)
+	}
+	if !report.OK || len(report.Findings) != 0 {
+		t.Errorf("second Diagnose() = %+v, want clean", report.Findings)
+	}
+}
+
+func TestDiagnoseFixFromFrontmatter(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+
+	writeTestFile(t, filepath.Join(backlogDir, "done", "007-moved.md"), "---\nid: \"007\"\ntitle: Moved\nstatus: review\n---\n")
+	writeTestFile(t, filepath.Join(backlogDir, "todo", "008-odd.md"), "---\nid: \"008\"\ntitle: Odd\nstatus: someday\n---\n")
+
+	report, err := l.Diagnose(backend.DoctorOptions{Fix: true, StatusSource: backend.StatusSourceFrontmatter})
+	if err != nil {
+		t.Fatalf("Diagnose() error = %v", err)
+	}
+	if report.OK || report.Fixed != 1 {
+		t.Errorf("OK = %v, Fixed = %d; want false, 1: %+v", report.OK, report.Fixed, report.Findings)
+	}
+
+	moved, err := l.Get("007")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if moved.Status != backend.StatusReview {
+		t.Errorf("task 007 status = %q, want %q", moved.Status, backend.StatusReview)
+	}
+
+	// An invalid frontmatter status can't be used as a destination
+	odd, err := l.Get("008")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if odd.Status != backend.StatusTodo {
+		t.Errorf("task 008 status = %q, want it left in %q", odd.Status, backend.StatusTodo)
+	}
+}
+
+func TestDiagnoseKeepsRelationsToUnparseableTasks(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+
+	task1, _ := l.Create(backend.TaskInput{Title: "Task 1"})
+	task2, _ := l.Create(backend.TaskInput{Title: "Task 2"})
+	if _, err := l.Link(task1.ID, task2.ID, backend.RelationBlocks); err != nil {
+		t.Fatalf("Link() error = %v", err)
+	}
+
+	// Break task 2; relations pointing at it must not be pruned
+	writeTestFile(t, filepath.Join(backlogDir, "backlog", "002-task-2.md"), "---\ntitle: [oops\n---\n")
+
+	report, err := l.Diagnose(backend.DoctorOptions{Fix: true})
+	if err != nil {
+		t.Fatalf("Diagnose() error = %v", err)
+	}
+	if byCheck := findingsByCheck(report); len(byCheck[checkDanglingRelation]) != 0 {
+		t.Errorf("unexpected dangling relation findings: %+v", byCheck[checkDanglingRelation])
+	}
+}
+
+func TestDiagnoseFixesDanglingParent(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+
+	writeTestFile(t, filepath.Join(backlogDir, "todo", "003-story.md"), "---\nid: \"003\"\ntitle: Story\nparent: \"042\"\n---\n")
+
+	report, err := l.Diagnose(backend.DoctorOptions{Fix: true})
+	if err != nil {
+		t.Fatalf("Diagnose() error = %v", err)
+	}
+	byCheck := findingsByCheck(report)
+	if len(byCheck[checkDanglingRelation]) != 1 || !byCheck[checkDanglingRelation][0].Fixed {
+		t.Errorf("dangling relation findings = %+v, want one fixed finding", byCheck[checkDanglingRelation])
+	}
+
+	story, err := l.Get("003")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if story.Parent != "" {
+		t.Errorf("Parent = %q, want dangling parent cleared", story.Parent)
+	}
+}
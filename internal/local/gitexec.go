@@ -0,0 +1,131 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/logging"
+)
+
+// DefaultGitTimeout bounds a single git invocation when the workspace doesn't
+// configure git_timeout.
+const DefaultGitTimeout = 30 * time.Second
+
+// gitWaitDelay is how long a timed-out git command gets to release its output
+// pipes after being killed. Helpers git spawns (ssh, git-remote-https, the
+// fetch run by pull) can outlive it and would otherwise block the caller.
+const gitWaitDelay = time.Second
+
+// Pull and push are retried when they fail with a transient network error.
+// These are variables so tests can shorten the backoff.
+var (
+	gitRetryAttempts = 3
+	gitRetryBackoff  = 500 * time.Millisecond
+)
+
+// transientGitFailures are output fragments git prints when the remote could
+// not be reached for reasons that may clear up on their own.
+var transientGitFailures = []string{
+	"Could not resolve host",
+	"Could not resolve hostname",
+	"Connection refused",
+	"Connection reset",
+	"Connection timed out",
+	"Failed to connect",
+	"Operation timed out",
+	"Temporary failure in name resolution",
+	"The remote end hung up unexpectedly",
+	"early EOF",
+}
+
+// GitTimeoutError is returned when a git command runs longer than the
+// workspace's git_timeout and is killed.
+type GitTimeoutError struct {
+	Operation string
+	Timeout   time.Duration
+}
+
+func (e *GitTimeoutError) Error() string {
+	return fmt.Sprintf("git %s timed out after %s", e.Operation, e.Timeout)
+}
+
+// runGit runs git in the repository containing the backlog and returns its
+// combined output. The command is killed once git_timeout elapses, in which
+// case the error is a GitTimeoutError naming op.
+func (l *Local) runGit(op string, args ...string) ([]byte, error) {
+	return l.execGit(op, (*exec.Cmd).CombinedOutput, args...)
+}
+
+// gitOutput is like runGit but returns only standard output.
+func (l *Local) gitOutput(op string, args ...string) ([]byte, error) {
+	return l.execGit(op, (*exec.Cmd).Output, args...)
+}
+
+func (l *Local) execGit(op string, run func(*exec.Cmd) ([]byte, error), args ...string) ([]byte, error) {
+	timeout := l.gitTimeout
+	if timeout <= 0 {
+		timeout = DefaultGitTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = filepath.Dir(l.path)
+	cmd.WaitDelay = gitWaitDelay
+	start := time.Now()
+	out, err := run(cmd)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = &GitTimeoutError{Operation: op, Timeout: timeout}
+	}
+
+	attrs := []any{"args", strings.Join(args, " "), "duration", time.Since(start)}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	slog.Info("git command", attrs...)
+	slog.Debug("git command output", "args", strings.Join(args, " "), "output", logging.Truncate(string(out)))
+	return out, err
+}
+
+// retryGit calls attempt until it succeeds, fails for a reason other than a
+// transient network error, or gitRetryAttempts is reached, doubling the
+// backoff between tries. op names the git operation in log records.
+func retryGit(op string, attempt func() ([]byte, error)) ([]byte, error) {
+	backoff := gitRetryBackoff
+	for i := 1; ; i++ {
+		out, err := attempt()
+		if err == nil || i >= gitRetryAttempts || !isTransientGitFailure(out, err) {
+			return out, err
+		}
+		slog.Info("retrying git command after transient failure", "op", op, "attempt", i, "backoff", backoff)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// isTransientGitFailure reports whether a failed git command looks like a
+// network hiccup worth retrying. Timeouts are not retried: the command has
+// already used its whole budget.
+func isTransientGitFailure(output []byte, err error) bool {
+	if err == nil || isGitTimeout(err) {
+		return false
+	}
+	for _, fragment := range transientGitFailures {
+		if strings.Contains(string(output), fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// isGitTimeout reports whether err is, or wraps, a GitTimeoutError.
+func isGitTimeout(err error) bool {
+	var timeoutErr *GitTimeoutError
+	return errors.As(err, &timeoutErr)
+}
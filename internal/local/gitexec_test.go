@@ -0,0 +1,253 @@
+package local
+
+import (
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// stallingRemote starts a listener that accepts connections and never
+// answers, and returns a git:// URL pointing at it.
+func stallingRemote(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	var conns []net.Conn
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+	t.Cleanup(func() {
+		ln.Close()
+		<-done
+		for _, conn := range conns {
+			conn.Close()
+		}
+	})
+	return "git://" + ln.Addr().String() + "/backlog.git"
+}
+
+// setupGitBacklog creates a git_sync backlog whose origin is remoteURL and
+// whose branch tracks origin/main.
+func setupGitBacklog(t *testing.T, remoteURL string, timeout time.Duration) (*Local, string) {
+	t.Helper()
+	repoDir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-q", "-b", "main")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test")
+	run("remote", "add", "origin", remoteURL)
+	run("config", "branch.main.remote", "origin")
+	run("config", "branch.main.merge", "refs/heads/main")
+
+	backlogDir := filepath.Join(repoDir, ".backlog")
+	l := New()
+	cfg := backend.Config{
+		Workspace: &WorkspaceConfig{Path: backlogDir, GitSync: true, GitTimeout: timeout},
+		AgentID:   "test-agent",
+	}
+	if err := l.Connect(cfg); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	run("commit", "-q", "--allow-empty", "-m", "init")
+	return l, repoDir
+}
+
+func TestGitPullTimeout(t *testing.T) {
+	l, repoDir := setupGitBacklog(t, stallingRemote(t), 300*time.Millisecond)
+
+	start := time.Now()
+	err := l.gitPull()
+	elapsed := time.Since(start)
+
+	var timeoutErr *GitTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("gitPull() error = %v, want GitTimeoutError", err)
+	}
+	if timeoutErr.Operation != "pull" {
+		t.Errorf("Operation = %q, want pull", timeoutErr.Operation)
+	}
+	if elapsed > 5*time.Second {
+		t.Errorf("gitPull() took %s, want it bounded by the timeout", elapsed)
+	}
+	for _, dir := range []string{"rebase-merge", "rebase-apply"} {
+		if _, err := os.Stat(filepath.Join(repoDir, ".git", dir)); err == nil {
+			t.Errorf("repository left mid-rebase (.git/%s exists)", dir)
+		}
+	}
+}
+
+func TestGitPushTimeout(t *testing.T) {
+	l, _ := setupGitBacklog(t, stallingRemote(t), 300*time.Millisecond)
+
+	err := l.gitPush()
+	var timeoutErr *GitTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("gitPush() error = %v, want GitTimeoutError", err)
+	}
+	if timeoutErr.Operation != "push" {
+		t.Errorf("Operation = %q, want push", timeoutErr.Operation)
+	}
+}
+
+func TestCheckGitSyncStateTimeout(t *testing.T) {
+	l, _ := setupGitBacklog(t, stallingRemote(t), 300*time.Millisecond)
+
+	err := l.checkGitSyncState()
+	var timeoutErr *GitTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("checkGitSyncState() error = %v, want GitTimeoutError", err)
+	}
+	if timeoutErr.Operation != "fetch" {
+		t.Errorf("Operation = %q, want fetch", timeoutErr.Operation)
+	}
+}
+
+func TestRetryGit(t *testing.T) {
+	oldBackoff := gitRetryBackoff
+	gitRetryBackoff = time.Millisecond
+	t.Cleanup(func() { gitRetryBackoff = oldBackoff })
+
+	failure := errors.New("exit status 128")
+	tests := []struct {
+		name      string
+		output    string
+		err       error
+		wantCalls int
+	}{
+		{"success", "", nil, 1},
+		{"transient failure", "fatal: unable to connect: Connection refused", failure, gitRetryAttempts},
+		{"permanent failure", "fatal: repository not found", failure, 1},
+		{"timeout", "", &GitTimeoutError{Operation: "pull"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			_, err := retryGit("pull", func() ([]byte, error) {
+				calls++
+				return []byte(tt.output), tt.err
+			})
+			if err != tt.err {
+				t.Errorf("retryGit() error = %v, want %v", err, tt.err)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("attempts = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestGitCommitSkipsCredentials(t *testing.T) {
+	l, repoDir := setupGitBacklog(t, filepath.Join(t.TempDir(), "remote.git"), time.Minute)
+
+	for _, path := range []string{
+		filepath.Join(l.path, "credentials.yaml"),
+		filepath.Join(l.path, "todo", "credentials.yaml"),
+		filepath.Join(l.path, "todo", "001-task.md"),
+	} {
+		if err := os.WriteFile(path, []byte("github:\n  token: secret\n"), 0600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+	if err := l.gitCommit("add", "001"); err != nil {
+		t.Fatalf("gitCommit() error = %v", err)
+	}
+
+	out, err := exec.Command("git", "-C", repoDir, "ls-files").Output()
+	if err != nil {
+		t.Fatalf("git ls-files: %v", err)
+	}
+	if string(out) != ".backlog/todo/001-task.md\n" {
+		t.Errorf("committed files:\n%s\nwant only the task file", out)
+	}
+	if dirty, err := l.hasUncommittedChanges(); err != nil || dirty {
+		t.Errorf("hasUncommittedChanges() = %v, %v; want credentials ignored", dirty, err)
+	}
+}
+
+func TestGitCommitCustomMessage(t *testing.T) {
+	l, repoDir := setupGitBacklog(t, filepath.Join(t.TempDir(), "remote.git"), time.Minute)
+	lastSubject := func() string {
+		t.Helper()
+		out, err := exec.Command("git", "-C", repoDir, "log", "-1", "--format=%s").Output()
+		if err != nil {
+			t.Fatalf("git log: %v", err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	l.commitMessage = "Start on the login fix"
+	task, err := l.Create(backend.TaskInput{Title: "Fix login"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if got, want := lastSubject(), "add: Start on the login fix"; got != want {
+		t.Errorf("commit subject = %q, want %q", got, want)
+	}
+	if _, err := l.Claim(task.ID, "test-agent"); err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+	if got, want := lastSubject(), "claim: Start on the login fix [agent:test-agent]"; got != want {
+		t.Errorf("commit subject = %q, want %q", got, want)
+	}
+
+	l.commitMessage = ""
+	priority := backend.PriorityHigh
+	if _, err := l.Update(task.ID, backend.TaskChanges{Priority: &priority}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if got, want := lastSubject(), "edit: "+task.ID; got != want {
+		t.Errorf("commit subject = %q, want the default %q", got, want)
+	}
+}
+
+func TestDetachedHead(t *testing.T) {
+	l, repoDir := setupGitBacklog(t, filepath.Join(t.TempDir(), "remote.git"), time.Minute)
+	task, err := l.Create(backend.TaskInput{Title: "Fix login"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if out, err := exec.Command("git", "-C", repoDir, "checkout", "-q", "--detach").CombinedOutput(); err != nil {
+		t.Fatalf("git checkout --detach: %v\n%s", err, out)
+	}
+
+	var detached *DetachedHeadError
+	if _, err := l.Move(task.ID, backend.StatusInProgress); !errors.As(err, &detached) {
+		t.Errorf("Move() error = %v, want DetachedHeadError", err)
+	}
+	if got, _ := l.Get(task.ID); got.Status != task.Status {
+		t.Errorf("Status = %s, want %s after a refused move", got.Status, task.Status)
+	}
+	if _, err := l.Create(backend.TaskInput{Title: "Another"}); !errors.As(err, &detached) {
+		t.Errorf("Create() error = %v, want DetachedHeadError", err)
+	}
+	if _, err := l.Sync(false); !errors.As(err, &detached) {
+		t.Errorf("Sync() error = %v, want DetachedHeadError", err)
+	}
+	if !strings.Contains(detached.Error(), "check out a branch") {
+		t.Errorf("error = %q, want advice to check out a branch", detached)
+	}
+}
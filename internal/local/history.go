@@ -0,0 +1,219 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// Separators for git log output; neither can appear in a commit subject.
+const (
+	historyRecordSep = "\x1e"
+	historyFieldSep  = "\x1f"
+)
+
+var (
+	// historyActionPattern matches the "<action>: " prefix of commits made by gitCommit.
+	historyActionPattern = regexp.MustCompile(`^([a-z-]+): `)
+	// historyAgentPattern matches the "[agent:<id>]" suffix of claim, release, and reopen commits.
+	historyAgentPattern = regexp.MustCompile(`\[agent:([^\]]+)\]`)
+)
+
+// historyCommit is a commit that touched a task file.
+type historyCommit struct {
+	entry backend.HistoryEntry
+	paths []string // Paths of the task file touched by the commit, for --diff
+}
+
+// History returns the task's history from the git log of its file, following
+// renames across status directories. Without git_sync, or when git has no
+// history for the file, it returns a single entry built from the task's
+// timestamps along with a note explaining why.
+func (l *Local) History(id string, includeDiff bool) (*backend.TaskHistory, error) {
+	if !l.connected {
+		return nil, errors.New("not connected")
+	}
+
+	task, err := l.findTask(id)
+	if err != nil {
+		return nil, err
+	}
+	filePath, err := l.findTaskFile(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !l.gitSync {
+		return syntheticHistory(task, "git_sync is disabled, so only the recorded timestamps are available"), nil
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return syntheticHistory(task, "git is not available, so only the recorded timestamps are available"), nil
+	}
+
+	commits, err := l.gitFileLog(filePath)
+	if isGitTimeout(err) {
+		return nil, err
+	}
+	if err != nil {
+		return syntheticHistory(task, fmt.Sprintf("git history is unavailable (%v), so only the recorded timestamps are available", err)), nil
+	}
+	if len(commits) == 0 {
+		return syntheticHistory(task, "the task file has not been committed yet"), nil
+	}
+
+	history := &backend.TaskHistory{TaskID: task.ID}
+	// git log lists newest first
+	for i := len(commits) - 1; i >= 0; i-- {
+		entry := commits[i].entry
+		if includeDiff {
+			diff, err := l.gitShowPatch(entry.Commit, commits[i].paths)
+			if err != nil {
+				return nil, err
+			}
+			entry.Diff = diff
+		}
+		history.Entries = append(history.Entries, entry)
+	}
+	return history, nil
+}
+
+// syntheticHistory builds a single-entry history from the task's timestamps.
+func syntheticHistory(task *backend.Task, reason string) *backend.TaskHistory {
+	note := reason
+	if !task.Updated.IsZero() && !task.Updated.Equal(task.Created) {
+		note = fmt.Sprintf("%s; last updated %s", reason, task.Updated.Format(time.RFC3339))
+	}
+	return &backend.TaskHistory{
+		TaskID: task.ID,
+		Entries: []backend.HistoryEntry{{
+			Action:    "add",
+			Timestamp: task.Created,
+			Status:    task.Status,
+		}},
+		Note: note,
+	}
+}
+
+// gitFileLog returns the commits that touched a file, newest first,
+// following it across renames.
+func (l *Local) gitFileLog(filePath string) ([]historyCommit, error) {
+	gitDir := filepath.Dir(l.path)
+	relPath, err := filepath.Rel(gitDir, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// A claim or edit that moves a task rewrites much of a small file, so
+	// renames are detected at a lower similarity than git's default 50%
+	output, err := l.gitOutput("log", "log", "--follow", "-M30%", "--name-status",
+		"--format="+historyRecordSep+"%H"+historyFieldSep+"%aI"+historyFieldSep+"%an"+historyFieldSep+"%s",
+		"--", relPath)
+	if err != nil {
+		if isGitTimeout(err) {
+			return nil, err
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("git log failed: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+
+	return parseFileLog(string(output)), nil
+}
+
+// parseFileLog parses "git log --name-status" output written with the
+// history separators.
+func parseFileLog(output string) []historyCommit {
+	var commits []historyCommit
+	for _, record := range strings.Split(output, historyRecordSep) {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		fields := strings.SplitN(lines[0], historyFieldSep, 4)
+		if len(fields) != 4 {
+			continue
+		}
+
+		commit := historyCommit{entry: backend.HistoryEntry{
+			Commit:  fields[0],
+			Action:  "commit",
+			Author:  fields[2],
+			Message: fields[3],
+		}}
+		if ts, err := time.Parse(time.RFC3339, fields[1]); err == nil {
+			commit.entry.Timestamp = ts
+		}
+		if m := historyActionPattern.FindStringSubmatch(fields[3]); m != nil {
+			commit.entry.Action = m[1]
+		}
+		if m := historyAgentPattern.FindStringSubmatch(fields[3]); m != nil {
+			commit.entry.Agent = m[1]
+		}
+
+		// Name-status lines are "M\tpath", "A\tpath", or "R100\told\tnew"
+		for _, line := range lines[1:] {
+			parts := strings.Split(strings.TrimSpace(line), "\t")
+			if len(parts) < 2 {
+				continue
+			}
+			commit.paths = append(commit.paths, parts[1:]...)
+			path := parts[len(parts)-1]
+			if status := backend.Status(filepath.Base(filepath.Dir(path))); status.IsValid() && !strings.HasPrefix(parts[0], "D") {
+				commit.entry.Status = status
+			}
+		}
+
+		commits = append(commits, commit)
+	}
+	return commits
+}
+
+// gitShowPatch returns the patch a commit applied to the given paths.
+func (l *Local) gitShowPatch(commit string, paths []string) (string, error) {
+	args := []string{"show", "--format=", "-M", commit, "--"}
+	// name-status paths are relative to the repository root
+	for _, path := range paths {
+		args = append(args, ":(top)"+path)
+	}
+	output, err := l.gitOutput("show", args...)
+	if err != nil {
+		if isGitTimeout(err) {
+			return "", err
+		}
+		return "", fmt.Errorf("git show failed: %w", err)
+	}
+	return string(output), nil
+}
+
+// TagTime returns the date of a git tag in the repository holding the
+// backlog: the tagger date of an annotated tag, or the commit date of a
+// lightweight one. Without git_sync the backlog isn't treated as versioned,
+// so it fails.
+// Implements the backend.TagDater interface.
+func (l *Local) TagTime(tag string) (time.Time, error) {
+	if !l.connected {
+		return time.Time{}, errors.New("not connected")
+	}
+	if !l.gitSync {
+		return time.Time{}, errors.New("git_sync is disabled, so git tags aren't available")
+	}
+
+	output, err := l.gitOutput("tag lookup", "for-each-ref", "--format=%(creatordate:iso-strict)", "refs/tags/"+tag)
+	if err != nil {
+		return time.Time{}, err
+	}
+	date := strings.TrimSpace(string(output))
+	if date == "" {
+		return time.Time{}, fmt.Errorf("tag %q not found", tag)
+	}
+	t, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unexpected date %q for tag %q", date, tag)
+	}
+	return t.UTC(), nil
+}
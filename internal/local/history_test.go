@@ -0,0 +1,137 @@
+package local
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+func TestParseFileLog(t *testing.T) {
+	output := historyRecordSep + "ccc" + historyFieldSep + "2025-01-17T09:00:00Z" + historyFieldSep + "Alex" + historyFieldSep + "claim: 001 [agent:claude-1]\n\n" +
+		"R092\t.backlog/todo/001-fix-login.md\t.backlog/in-progress/001-fix-login.md\n" +
+		historyRecordSep + "bbb" + historyFieldSep + "2025-01-16T12:00:00+02:00" + historyFieldSep + "Alex" + historyFieldSep + "Tidy up by hand\n\n" +
+		"M\t.backlog/todo/001-fix-login.md\n" +
+		historyRecordSep + "aaa" + historyFieldSep + "2025-01-15T10:30:00Z" + historyFieldSep + "Alex" + historyFieldSep + "add: 001\n\n" +
+		"A\t.backlog/todo/001-fix-login.md\n"
+
+	commits := parseFileLog(output)
+	if len(commits) != 3 {
+		t.Fatalf("expected 3 commits, got %d", len(commits))
+	}
+
+	claim := commits[0]
+	if claim.entry.Commit != "ccc" || claim.entry.Action != "claim" || claim.entry.Agent != "claude-1" || claim.entry.Author != "Alex" {
+		t.Errorf("unexpected claim entry: %+v", claim.entry)
+	}
+	if claim.entry.Status != backend.StatusInProgress {
+		t.Errorf("expected status after rename to be in-progress, got %q", claim.entry.Status)
+	}
+	if len(claim.paths) != 2 {
+		t.Errorf("expected both rename paths, got %v", claim.paths)
+	}
+
+	manual := commits[1]
+	if manual.entry.Action != "commit" || manual.entry.Agent != "" {
+		t.Errorf("expected a plain commit without agent, got %+v", manual.entry)
+	}
+	if manual.entry.Timestamp.UTC().Hour() != 10 {
+		t.Errorf("expected timestamp with offset to parse, got %v", manual.entry.Timestamp)
+	}
+
+	add := commits[2]
+	if add.entry.Action != "add" || add.entry.Status != backend.StatusTodo || add.entry.Message != "add: 001" {
+		t.Errorf("unexpected add entry: %+v", add.entry)
+	}
+}
+
+func TestHistoryWithoutGitSync(t *testing.T) {
+	l, _ := setupBacklog(t)
+
+	task, err := l.Create(backend.TaskInput{Title: "No git here"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	history, err := l.History(task.ID, false)
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(history.Entries) != 1 || history.Entries[0].Action != "add" {
+		t.Errorf("expected a single synthetic add entry, got %+v", history.Entries)
+	}
+	if history.Note == "" {
+		t.Error("expected a note explaining the synthetic history")
+	}
+
+	if _, err := l.History("999", false); err == nil {
+		t.Error("expected error for missing task")
+	}
+}
+
+func TestTagTime(t *testing.T) {
+	remoteDir := filepath.Join(t.TempDir(), "remote.git")
+	if out, err := exec.Command("git", "init", "-q", "--bare", "-b", "main", remoteDir).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v\n%s", err, out)
+	}
+	l, repoDir := setupGitBacklog(t, remoteDir, time.Minute)
+
+	cmd := exec.Command("git", "-C", repoDir, "tag", "-a", "v1.0.0", "-m", "Release 1.0.0")
+	cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE=2025-05-01T12:00:00Z")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git tag: %v\n%s", err, out)
+	}
+
+	got, err := l.TagTime("v1.0.0")
+	if err != nil {
+		t.Fatalf("TagTime() error = %v", err)
+	}
+	if want := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("TagTime() = %v, want %v", got, want)
+	}
+
+	if _, err := l.TagTime("v9.9.9"); err == nil {
+		t.Error("expected error for missing tag")
+	}
+
+	l.gitSync = false
+	if _, err := l.TagTime("v1.0.0"); err == nil {
+		t.Error("expected error without git_sync")
+	}
+}
+
+func TestHistoryFromGit(t *testing.T) {
+	remote := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v\n%s", err, out)
+	}
+	l, _ := setupGitBacklog(t, remote, time.Minute)
+	task, err := l.Create(backend.TaskInput{Title: "Bounded history"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	history, err := l.History(task.ID, true)
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(history.Entries) != 1 || !strings.Contains(history.Entries[0].Diff, "+title: Bounded history") {
+		t.Fatalf("expected one entry with the add diff, got %+v", history.Entries)
+	}
+
+	// Every git command now runs out of time before it starts
+	l.gitTimeout = time.Nanosecond
+	_, err = l.History(task.ID, false)
+	var timeoutErr *GitTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("History() error = %v, want GitTimeoutError", err)
+	}
+	if timeoutErr.Operation != "log" {
+		t.Errorf("Operation = %q, want log", timeoutErr.Operation)
+	}
+}
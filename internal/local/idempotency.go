@@ -0,0 +1,94 @@
+package local
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// idempotencyFile is the file inside the .backlog directory that records the
+// idempotency keys of recently created tasks.
+const idempotencyFile = ".idempotency"
+
+// idempotencyRecord is the task created for an idempotency key.
+type idempotencyRecord struct {
+	ID      string    `json:"id"`
+	Created time.Time `json:"created"`
+}
+
+// lookupIdempotencyKey returns the task created earlier with key, or nil if
+// the key is unknown, has expired, or its task has since been deleted.
+func (l *Local) lookupIdempotencyKey(key string) (*backend.Task, error) {
+	records, err := l.readIdempotencyRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	record, ok := records[key]
+	if !ok || time.Since(record.Created) > l.idempotencyWindow {
+		return nil, nil
+	}
+	task, err := l.findTask(record.ID)
+	if err != nil {
+		return nil, nil
+	}
+
+	if task.Meta == nil {
+		task.Meta = make(map[string]any)
+	}
+	task.Meta[backend.MetaIdempotentReplay] = true
+	return task, nil
+}
+
+// recordIdempotencyKey remembers that key created the task with the given ID,
+// dropping keys older than the idempotency window.
+func (l *Local) recordIdempotencyKey(key, id string) error {
+	records, err := l.readIdempotencyRecords()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for k, record := range records {
+		if now.Sub(record.Created) > l.idempotencyWindow {
+			delete(records, k)
+		}
+	}
+	records[key] = idempotencyRecord{ID: id, Created: now}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", idempotencyFile, err)
+	}
+	// Write through a temp file so a concurrent reader never sees a partial file
+	path := filepath.Join(l.path, idempotencyFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", idempotencyFile, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", idempotencyFile, err)
+	}
+	return nil
+}
+
+// readIdempotencyRecords reads the recorded keys. A missing file has none.
+func (l *Local) readIdempotencyRecords() (map[string]idempotencyRecord, error) {
+	records := make(map[string]idempotencyRecord)
+	data, err := os.ReadFile(filepath.Join(l.path, idempotencyFile))
+	if os.IsNotExist(err) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", idempotencyFile, err)
+	}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", idempotencyFile, err)
+	}
+	return records, nil
+}
@@ -0,0 +1,192 @@
+package local
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+func TestCreateIdempotencyKey(t *testing.T) {
+	l, _ := setupBacklog(t)
+
+	input := backend.TaskInput{Title: "Nightly report failed", IdempotencyKey: "report-1"}
+	first, err := l.Create(input)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if replay, _ := first.Meta[backend.MetaIdempotentReplay].(bool); replay {
+		t.Error("first Create() should not be a replay")
+	}
+
+	second, err := l.Create(input)
+	if err != nil {
+		t.Fatalf("Create() retry error = %v", err)
+	}
+	if second.ID != first.ID {
+		t.Errorf("retry ID = %q, want %q", second.ID, first.ID)
+	}
+	if replay, _ := second.Meta[backend.MetaIdempotentReplay].(bool); !replay {
+		t.Error("retried Create() should be marked as a replay")
+	}
+
+	other, err := l.Create(backend.TaskInput{Title: "Nightly report failed", IdempotencyKey: "report-2"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if other.ID == first.ID {
+		t.Error("a different key should create a new task")
+	}
+
+	list, err := l.List(backend.TaskFilters{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list.Tasks) != 2 {
+		t.Errorf("got %d tasks, want 2", len(list.Tasks))
+	}
+}
+
+func TestCreateIdempotencyKeyExpired(t *testing.T) {
+	l, _ := setupBacklog(t)
+	l.idempotencyWindow = time.Millisecond
+
+	input := backend.TaskInput{Title: "Flaky test", IdempotencyKey: "flaky"}
+	first, err := l.Create(input)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	second, err := l.Create(input)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if second.ID == first.ID {
+		t.Error("an expired key should create a new task")
+	}
+}
+
+func TestCreateIdempotencyKeyDeletedTask(t *testing.T) {
+	l, _ := setupBacklog(t)
+
+	input := backend.TaskInput{Title: "Short-lived", IdempotencyKey: "gone"}
+	first, err := l.Create(input)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := l.Delete(first.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	second, err := l.Create(input)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if replay, _ := second.Meta[backend.MetaIdempotentReplay].(bool); replay {
+		t.Error("a key whose task was deleted should create a new task")
+	}
+	if _, err := l.Get(second.ID); err != nil {
+		t.Errorf("Get(%q) error = %v", second.ID, err)
+	}
+}
+
+func TestCreatePinnedID(t *testing.T) {
+	l, _ := setupBacklog(t)
+
+	task, err := l.Create(backend.TaskInput{Title: "Split the parser", ID: "parser-split"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if task.ID != "parser-split" {
+		t.Errorf("ID = %q, want parser-split", task.ID)
+	}
+	if _, err := l.Get("parser-split"); err != nil {
+		t.Errorf("Get() error = %v", err)
+	}
+
+	if _, err := l.Create(backend.TaskInput{Title: "Another", ID: "parser-split"}); !backend.IsAlreadyExists(err) {
+		t.Errorf("Create() with a taken ID error = %v, want already exists", err)
+	}
+	if _, err := l.Create(backend.TaskInput{Title: "Bad", ID: "../escape"}); err == nil {
+		t.Error("Create() with an unsafe ID should fail")
+	}
+}
+
+func TestCreateIfAbsent(t *testing.T) {
+	l, _ := setupBacklog(t)
+
+	first, err := l.Create(backend.TaskInput{Title: "Write docs", IfAbsent: true})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if exists, _ := first.Meta[backend.MetaAlreadyExists].(bool); exists {
+		t.Error("first Create() should create a task")
+	}
+
+	second, err := l.Create(backend.TaskInput{Title: "Write docs", IfAbsent: true})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if exists, _ := second.Meta[backend.MetaAlreadyExists].(bool); !exists || second.ID != first.ID {
+		t.Errorf("second Create() = %s (exists %v), want existing task %s", second.ID, exists, first.ID)
+	}
+
+	// A done task no longer counts as a duplicate
+	if _, err := l.Move(first.ID, backend.StatusDone); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	third, err := l.Create(backend.TaskInput{Title: "Write docs", IfAbsent: true})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if third.ID == first.ID {
+		t.Error("Create() should not match a done task")
+	}
+}
+
+func TestCreateWaitsForCreateLock(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+	other := New()
+	if err := other.Connect(backend.Config{Workspace: &WorkspaceConfig{Path: backlogDir}, AgentID: "other-agent"}); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	// Another process's create has to wait until the lock is released
+	created := make(chan error, 1)
+	err := l.withCreateLock(func() error {
+		go func() {
+			_, err := other.Create(backend.TaskInput{Title: "Decomposed step", IfAbsent: true})
+			created <- err
+		}()
+		select {
+		case err := <-created:
+			return fmt.Errorf("Create() finished while the create lock was held: %v", err)
+		case <-time.After(200 * time.Millisecond):
+		}
+		_, _, err := l.createLocked(backend.TaskInput{Title: "Decomposed step", IfAbsent: true})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("withCreateLock() error = %v", err)
+	}
+
+	select {
+	case err := <-created:
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	case <-time.After(createLockWait):
+		t.Fatal("Create() didn't finish after the create lock was released")
+	}
+
+	// The waiting create found the task made under the lock
+	list, err := l.List(backend.TaskFilters{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list.Tasks) != 1 {
+		t.Errorf("got %d tasks, want 1", len(list.Tasks))
+	}
+}
@@ -0,0 +1,164 @@
+package local
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ignoreFileName is the name of the file inside the .backlog directory that
+// lists paths to exclude from task scanning.
+const ignoreFileName = ".backlogignore"
+
+// ignorePattern is a single compiled line of a .backlogignore file.
+type ignorePattern struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// ignoreMatcher decides whether a path inside the .backlog directory should
+// be skipped when scanning for tasks. Patterns follow gitignore semantics:
+// blank lines and lines starting with # are ignored, a leading ! re-includes
+// a previously excluded path, a trailing / matches only directories, and a
+// pattern containing a / is anchored to the .backlog directory. The last
+// matching pattern wins.
+type ignoreMatcher struct {
+	patterns []ignorePattern
+}
+
+// loadIgnoreFile reads the .backlogignore file from the backlog directory.
+// A missing file yields a matcher that ignores nothing.
+func loadIgnoreFile(backlogDir string) (*ignoreMatcher, error) {
+	content, err := os.ReadFile(filepath.Join(backlogDir, ignoreFileName))
+	if os.IsNotExist(err) {
+		return &ignoreMatcher{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ignoreFileName, err)
+	}
+	return parseIgnore(content), nil
+}
+
+// parseIgnore compiles the lines of a .backlogignore file.
+func parseIgnore(content []byte) *ignoreMatcher {
+	m := &ignoreMatcher{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var p ignorePattern
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\`) {
+			// Escaped leading # or !
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		// Patterns without a slash match at any depth; others are anchored
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+
+		expr := globToRegexp(line)
+		if !anchored {
+			expr = "(?:.*/)?" + expr
+		}
+		re, err := regexp.Compile("^" + expr + "$")
+		if err != nil {
+			// Skip malformed patterns, as git does
+			continue
+		}
+		p.re = re
+		m.patterns = append(m.patterns, p)
+	}
+
+	return m
+}
+
+// globToRegexp translates a gitignore glob into a regular expression.
+func globToRegexp(glob string) string {
+	var buf strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					// "**/" matches zero or more directories
+					i++
+					buf.WriteString("(?:.*/)?")
+				} else {
+					buf.WriteString(".*")
+				}
+			} else {
+				buf.WriteString("[^/]*")
+			}
+		case '?':
+			buf.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end == -1 {
+				buf.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			buf.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			buf.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return buf.String()
+}
+
+// Ignored reports whether the file at relPath (slash-separated and relative
+// to the .backlog directory) is excluded, either directly or because one of
+// its parent directories is.
+func (m *ignoreMatcher) Ignored(relPath string) bool {
+	if m == nil || len(m.patterns) == 0 {
+		return false
+	}
+
+	// A file inside an excluded directory cannot be re-included, matching git
+	for dir := path.Dir(relPath); dir != "."; dir = path.Dir(dir) {
+		if m.match(dir, true) {
+			return true
+		}
+	}
+	return m.match(relPath, false)
+}
+
+// match applies the patterns to a single path, with the last match winning.
+func (m *ignoreMatcher) match(relPath string, isDir bool) bool {
+	ignored := false
+	for _, p := range m.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		if p.re.MatchString(relPath) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,86 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+func TestIgnoreMatcher(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns string
+		path     string
+		want     bool
+	}{
+		{"no patterns", "", "todo/001-task.md", false},
+		{"basename at any depth", "README.md", "todo/README.md", true},
+		{"star glob", "*-template.md", "backlog/000-template.md", true},
+		{"star does not cross directories", "todo*.md", "todo/001-task.md", false},
+		{"anchored pattern", "todo/notes.md", "todo/notes.md", true},
+		{"anchored pattern other dir", "todo/notes.md", "review/notes.md", false},
+		{"leading slash anchors", "/review/*.md", "review/001-task.md", true},
+		{"double star", "**/draft-*.md", "done/draft-1.md", true},
+		{"character class", "00[0-1].md", "todo/001.md", true},
+		{"negation re-includes", "*.md\n!001-*.md", "todo/001-task.md", false},
+		{"last match wins", "!001-*.md\n*.md", "todo/001-task.md", true},
+		{"directory pattern excludes contents", "review/", "review/001-task.md", true},
+		{"directory pattern does not match files", "notes.md/", "todo/notes.md", false},
+		{"comments and blank lines", "# templates\n\nREADME.md", "todo/README.md", true},
+		{"escaped hash", `\#notes.md`, "todo/#notes.md", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := parseIgnore([]byte(tt.patterns))
+			if got := m.Ignored(tt.path); got != tt.want {
+				t.Errorf("Ignored(%q) with patterns %q = %v, want %v", tt.path, tt.patterns, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIgnoredFilesAreNotTasks(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+
+	task, err := l.Create(backend.TaskInput{Title: "Real task"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// A template with valid frontmatter and a high ID would otherwise be listed
+	template := "---\nid: \"900\"\ntitle: Template\n---\n\n## Description\n\nCopy me.\n"
+	if err := os.WriteFile(filepath.Join(backlogDir, "backlog", "900-template.md"), []byte(template), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(backlogDir, ignoreFileName), []byte("*-template.md\n"), 0644); err != nil {
+		t.Fatalf("failed to write ignore file: %v", err)
+	}
+
+	// Reconnect to pick up the ignore file
+	if err := l.Connect(backend.Config{Workspace: &WorkspaceConfig{Path: backlogDir}}); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	list, err := l.List(backend.TaskFilters{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list.Tasks) != 1 || list.Tasks[0].ID != task.ID {
+		t.Errorf("List() = %+v, want only task %s", list.Tasks, task.ID)
+	}
+
+	if _, err := l.Get("900"); err == nil {
+		t.Error("Get() of an ignored file should fail")
+	}
+
+	id, err := l.generateID()
+	if err != nil {
+		t.Fatalf("generateID() error = %v", err)
+	}
+	if id != "002" {
+		t.Errorf("generateID() = %q, want %q", id, "002")
+	}
+}
@@ -0,0 +1,324 @@
+package local
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"gopkg.in/yaml.v3"
+)
+
+// indexFile is the file inside the .backlog directory that caches parsed
+// task files, so that listing a large backlog doesn't re-parse every file.
+const indexFile = ".index.json"
+
+// indexVersion identifies the entry format. An index written with another
+// version is discarded and rebuilt.
+const indexVersion = 1
+
+// racyWindow is how old a file must be before its parse is cached. A file
+// changed twice within the file system's timestamp granularity can keep the
+// same mtime and size, so recently modified files are always re-parsed.
+const racyWindow = 2 * time.Second
+
+// taskIndex caches parsed task files keyed by their slash-separated path
+// relative to the backlog directory. An entry is only trusted while the
+// file's size and modification time still match it, so files edited by hand
+// or changed by a git pull are re-parsed.
+type taskIndex struct {
+	Version int                    `json:"version"`
+	Entries map[string]*indexEntry `json:"entries"`
+
+	// dirty is set when the index differs from the file on disk.
+	dirty bool
+}
+
+// indexEntry is the cached parse of a single task file. Meta is split into
+// typed fields so that comments survive the JSON round trip, and unknown
+// frontmatter fields are kept as YAML so their values keep their types.
+type indexEntry struct {
+	ModTime   int64             `json:"mtime"`
+	Size      int64             `json:"size"`
+	Task      backend.Task      `json:"task"`
+	Comments  []backend.Comment `json:"comments,omitempty"`
+	Blocks    []string          `json:"blocks,omitempty"`
+	BlockedBy []string          `json:"blocked_by,omitempty"`
+	Extra     string            `json:"extra,omitempty"`
+
+	// LastCommentID is the highest comment ID handed out, when deleting
+	// comments left it above the IDs in use.
+	LastCommentID string `json:"last_comment_id,omitempty"`
+}
+
+// newIndexEntry records a freshly parsed task for a file. It returns nil if
+// the task can't be cached.
+func newIndexEntry(task *backend.Task, info os.FileInfo) *indexEntry {
+	e := &indexEntry{
+		ModTime:   info.ModTime().UnixNano(),
+		Size:      info.Size(),
+		Task:      *task,
+		Blocks:    metaStringSlice(task.Meta, "blocks"),
+		BlockedBy: metaStringSlice(task.Meta, "blocked_by"),
+	}
+	e.Task.Meta = nil
+	e.Task.Duration = 0
+	e.Task.Checklist = nil
+	e.Task.ChecklistProgress = nil
+	if comments, ok := task.Meta["comments"].([]backend.Comment); ok {
+		e.Comments = comments
+	}
+	e.LastCommentID, _ = task.Meta[lastCommentIDMetaKey].(string)
+	if extra, ok := task.Meta[extraMetaKey].(map[string]any); ok && len(extra) > 0 {
+		data, err := yaml.Marshal(extra)
+		if err != nil {
+			return nil
+		}
+		e.Extra = string(data)
+	}
+	return e
+}
+
+// matches reports whether the entry was recorded for the file as it is now.
+func (e *indexEntry) matches(info os.FileInfo) bool {
+	return e.ModTime == info.ModTime().UnixNano() && e.Size == info.Size()
+}
+
+// task returns a copy of the cached task in the given status directory, or
+// nil if the entry can't be decoded.
+func (e *indexEntry) task(status backend.Status) *backend.Task {
+	var extra map[string]any
+	if e.Extra != "" {
+		if err := yaml.Unmarshal([]byte(e.Extra), &extra); err != nil {
+			return nil
+		}
+	}
+
+	task := e.Task
+	task.Status = status
+	task.Labels = append([]string(nil), e.Task.Labels...)
+	if len(task.Labels) == 0 {
+		task.Labels = nil
+	}
+	setTaskMeta(&task, e.Comments, e.Blocks, e.BlockedBy, extra)
+	setLastCommentID(&task, e.LastCommentID)
+	backend.SetDuration(&task, time.Now().UTC())
+	backend.SetChecklist(&task)
+	return &task
+}
+
+// indexPath returns the path of the index file.
+func (l *Local) indexPath() string {
+	return filepath.Join(l.path, indexFile)
+}
+
+// indexPathspec returns a git pathspec excluding the index file, which is
+// specific to one checkout and never committed.
+func (l *Local) indexPathspec() string {
+	return ":(exclude)" + l.indexPath()
+}
+
+// indexKey returns the index key for a task file.
+func (l *Local) indexKey(filePath string) string {
+	rel, err := filepath.Rel(l.path, filePath)
+	if err != nil {
+		return filepath.ToSlash(filePath)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// loadIndex returns the task index, reading it on first use. A missing,
+// corrupt, or outdated index file yields an empty index that is rebuilt as
+// tasks are read. It returns nil when the index is disabled.
+func (l *Local) loadIndex() *taskIndex {
+	if !l.indexEnabled {
+		return nil
+	}
+	if l.index != nil {
+		return l.index
+	}
+
+	l.index = &taskIndex{Version: indexVersion, Entries: make(map[string]*indexEntry)}
+	data, err := os.ReadFile(l.indexPath())
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			slog.Debug("rebuilding task index", "reason", err)
+		}
+		l.index.dirty = true
+		return l.index
+	}
+
+	var stored taskIndex
+	if err := json.Unmarshal(data, &stored); err != nil || stored.Version != indexVersion || stored.Entries == nil {
+		slog.Debug("rebuilding task index", "reason", "corrupt or outdated index", "error", err)
+		l.index.dirty = true
+		return l.index
+	}
+	l.index.Entries = stored.Entries
+	return l.index
+}
+
+// saveIndex writes the task index if it changed since it was loaded.
+func (l *Local) saveIndex() error {
+	if l.index == nil || !l.index.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(l.index)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", indexFile, err)
+	}
+	// Write through a temp file so a concurrent reader never sees a partial file
+	tmp, err := os.CreateTemp(l.path, indexFile+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", indexFile, err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), l.indexPath())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", indexFile, err)
+	}
+
+	l.index.dirty = false
+	return nil
+}
+
+// flushIndex saves the task index, logging rather than returning a failure
+// since the index is only a cache.
+func (l *Local) flushIndex() {
+	if err := l.saveIndex(); err != nil {
+		slog.Warn("failed to save task index", "error", err)
+	}
+}
+
+// readIndexedTask reads a task file found while scanning a status
+// directory, using the cached parse when the file is unchanged. It is safe
+// to call from several goroutines once the index is loaded.
+func (l *Local) readIndexedTask(filePath string, status backend.Status, entry os.DirEntry) (*backend.Task, error) {
+	idx := l.loadIndex()
+	if idx == nil {
+		return l.readTaskFile(filePath, status)
+	}
+	info, err := entry.Info()
+	if err != nil {
+		return l.readTaskFile(filePath, status)
+	}
+
+	key := l.indexKey(filePath)
+	l.indexMu.Lock()
+	cached, ok := idx.Entries[key]
+	l.indexMu.Unlock()
+	if ok && cached.matches(info) {
+		if task := cached.task(status); task != nil {
+			return task, nil
+		}
+	}
+
+	task, err := l.readTaskFile(filePath, status)
+	l.indexMu.Lock()
+	defer l.indexMu.Unlock()
+	if err != nil || time.Since(info.ModTime()) < racyWindow {
+		if ok {
+			delete(idx.Entries, key)
+			idx.dirty = true
+		}
+		return task, err
+	}
+	if entry := newIndexEntry(task, info); entry != nil {
+		idx.Entries[key] = entry
+	} else {
+		delete(idx.Entries, key)
+	}
+	idx.dirty = true
+	return task, nil
+}
+
+// pruneIndex drops the entries for files in dirPath that were not seen
+// while scanning it, such as files deleted or moved since they were indexed.
+func (l *Local) pruneIndex(dirPath string, seen map[string]bool) {
+	if l.index == nil {
+		return
+	}
+	prefix := l.indexKey(dirPath) + "/"
+	for key := range l.index.Entries {
+		if strings.HasPrefix(key, prefix) && !strings.Contains(key[len(prefix):], "/") && !seen[key] {
+			delete(l.index.Entries, key)
+			l.index.dirty = true
+		}
+	}
+}
+
+// indexedTaskFile returns the file of a task under root according to an
+// already loaded index, or "" if the index doesn't know it or is stale.
+// The index isn't loaded just for this: reading it costs more than listing
+// the status directories.
+func (l *Local) indexedTaskFile(root, id string) string {
+	if l.index == nil {
+		return ""
+	}
+	prefix := l.indexKey(root) + "/"
+	if root == l.path {
+		prefix = ""
+	}
+	for key, e := range l.index.Entries {
+		if e.Task.ID != id || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		rest := strings.Split(key[len(prefix):], "/")
+		if len(rest) != 2 || l.isIgnored(backend.Status(rest[0]), rest[1]) || !taskFileMatchesID(strings.TrimSuffix(rest[1], ".md"), id) {
+			continue
+		}
+		filePath := filepath.Join(l.path, filepath.FromSlash(key))
+		if _, err := os.Stat(filePath); err == nil {
+			return filePath
+		}
+	}
+	return ""
+}
+
+// forgetIndexedTask drops the index entries of a task whose file is being
+// rewritten, so a stale path or parse is never served for it.
+func (l *Local) forgetIndexedTask(id string) {
+	if l.index == nil {
+		return
+	}
+	for key, e := range l.index.Entries {
+		if e.Task.ID == id {
+			delete(l.index.Entries, key)
+			l.index.dirty = true
+		}
+	}
+}
+
+// Reindex discards the task index and rebuilds it from every task file,
+// including soft-deleted ones. Implements the backend.Reindexer interface.
+func (l *Local) Reindex() (*backend.ReindexResult, error) {
+	if !l.connected {
+		return nil, errors.New("not connected")
+	}
+	if !l.indexEnabled {
+		return nil, errors.New("the task index is disabled for this workspace (index: false)")
+	}
+
+	l.index = &taskIndex{Version: indexVersion, Entries: make(map[string]*indexEntry), dirty: true}
+	list, err := l.List(backend.TaskFilters{IncludeDone: true, IncludeDeleted: true})
+	if err != nil {
+		return nil, err
+	}
+	if err := l.saveIndex(); err != nil {
+		return nil, err
+	}
+
+	return &backend.ReindexResult{Path: l.indexPath(), Tasks: list.Count, Skipped: list.Warnings}, nil
+}
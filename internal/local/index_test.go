@@ -0,0 +1,299 @@
+package local
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// backdate sets a file's modification time far enough in the past that its
+// parse is cached by the index.
+func backdate(t *testing.T, path string, age time.Duration) {
+	t.Helper()
+	old := time.Now().Add(-age)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatalf("Chtimes(%s) error = %v", path, err)
+	}
+}
+
+// reconnect returns a new backend for the same backlog directory, so the
+// index is read back from disk.
+func reconnect(t testing.TB, backlogDir string, ws WorkspaceConfig) *Local {
+	t.Helper()
+	ws.Path = backlogDir
+	l := New()
+	if err := l.Connect(backend.Config{Workspace: &ws, AgentID: "test-agent"}); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	return l
+}
+
+// editTaskFile replaces old with new in a task file.
+func editTaskFile(t *testing.T, path, old, new string) {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !strings.Contains(string(content), old) {
+		t.Fatalf("%s does not contain %q", path, old)
+	}
+	writeTestFile(t, path, strings.Replace(string(content), old, new, 1))
+}
+
+func listByID(t *testing.T, l *Local, filters backend.TaskFilters) map[string]backend.Task {
+	t.Helper()
+	list, err := l.List(filters)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	tasks := make(map[string]backend.Task, len(list.Tasks))
+	for _, task := range list.Tasks {
+		tasks[task.ID] = task
+	}
+	return tasks
+}
+
+func readIndex(t *testing.T, backlogDir string) taskIndex {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(backlogDir, indexFile))
+	if err != nil {
+		t.Fatalf("failed to read index: %v", err)
+	}
+	var idx taskIndex
+	if err := json.Unmarshal(data, &idx); err != nil {
+		t.Fatalf("index is not valid JSON: %v", err)
+	}
+	return idx
+}
+
+func TestIndexServesUnchangedFiles(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+	task, err := l.Create(backend.TaskInput{Title: "Original title", Labels: []string{"api"}})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := l.AddComment(task.ID, "first comment"); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	path, _ := l.findTaskFile(task.ID)
+	backdate(t, path, time.Hour)
+	listByID(t, l, backend.TaskFilters{})
+
+	idx := readIndex(t, backlogDir)
+	if idx.Version != indexVersion || len(idx.Entries) != 1 {
+		t.Fatalf("index = version %d with %d entries, want version %d with 1 entry", idx.Version, len(idx.Entries), indexVersion)
+	}
+
+	// Same size and mtime: the cached parse is trusted without reading the file
+	info, _ := os.Stat(path)
+	editTaskFile(t, path, "Original title", "Modified title")
+	if err := os.Chtimes(path, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+
+	cached := listByID(t, reconnect(t, backlogDir, WorkspaceConfig{}), backend.TaskFilters{})[task.ID]
+	if cached.Title != "Original title" {
+		t.Errorf("Title = %q, want the cached %q", cached.Title, "Original title")
+	}
+	if len(cached.Labels) != 1 || cached.Labels[0] != "api" {
+		t.Errorf("Labels = %v, want [api]", cached.Labels)
+	}
+	if comments, _ := cached.Meta["comments"].([]backend.Comment); len(comments) != 1 || comments[0].Body != "first comment" {
+		t.Errorf("Meta[comments] = %v, want the cached comment", cached.Meta["comments"])
+	}
+	if cached.Status != backend.StatusBacklog {
+		t.Errorf("Status = %q, want %q", cached.Status, backend.StatusBacklog)
+	}
+}
+
+func TestIndexRereadsHandEditedFiles(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+	kept, _ := l.Create(backend.TaskInput{Title: "Kept"})
+	edited, _ := l.Create(backend.TaskInput{Title: "Before edit"})
+	removed, _ := l.Create(backend.TaskInput{Title: "Removed"})
+	for _, id := range []string{kept.ID, edited.ID, removed.ID} {
+		path, _ := l.findTaskFile(id)
+		backdate(t, path, time.Hour)
+	}
+	listByID(t, l, backend.TaskFilters{})
+
+	// Edit one file by hand and delete another, as a user would in an editor
+	editedPath, _ := l.findTaskFile(edited.ID)
+	editTaskFile(t, editedPath, "Before edit", "After a hand edit")
+	backdate(t, editedPath, 30*time.Minute)
+	removedPath, _ := l.findTaskFile(removed.ID)
+	if err := os.Remove(removedPath); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	tasks := listByID(t, reconnect(t, backlogDir, WorkspaceConfig{}), backend.TaskFilters{})
+	if got := tasks[edited.ID].Title; got != "After a hand edit" {
+		t.Errorf("edited Title = %q, want %q", got, "After a hand edit")
+	}
+	if got := tasks[kept.ID].Title; got != "Kept" {
+		t.Errorf("kept Title = %q, want %q", got, "Kept")
+	}
+	if _, ok := tasks[removed.ID]; ok {
+		t.Errorf("removed task %s still listed", removed.ID)
+	}
+
+	idx := readIndex(t, backlogDir)
+	if len(idx.Entries) != 2 {
+		t.Errorf("index has %d entries, want 2 after pruning the removed file", len(idx.Entries))
+	}
+}
+
+func TestIndexRereadsFilesChangedByPull(t *testing.T) {
+	remote := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", "--bare", "-b", "main", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v\n%s", err, out)
+	}
+	l, repoDir := setupGitBacklog(t, remote, 0)
+	git := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	task, err := l.Create(backend.TaskInput{Title: "Before pull"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	git(repoDir, "push", "-q", "origin", "main")
+	path, _ := l.findTaskFile(task.ID)
+	backdate(t, path, time.Hour)
+	listByID(t, l, backend.TaskFilters{})
+
+	// Another checkout renames the task and moves it to done
+	other := t.TempDir()
+	git(other, "clone", "-q", remote, ".")
+	git(other, "config", "user.email", "other@example.com")
+	git(other, "config", "user.name", "Other")
+	rel, _ := filepath.Rel(repoDir, path)
+	otherPath := filepath.Join(other, rel)
+	editTaskFile(t, otherPath, "Before pull", "After pull")
+	if err := os.MkdirAll(filepath.Join(other, ".backlog", "done"), 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	git(other, "mv", rel, filepath.Join(".backlog", "done", filepath.Base(rel)))
+	git(other, "commit", "-q", "-am", "move: "+task.ID)
+	git(other, "push", "-q", "origin", "main")
+
+	if err := l.gitPull(); err != nil {
+		t.Fatalf("gitPull() error = %v", err)
+	}
+
+	tasks := listByID(t, l, backend.TaskFilters{IncludeDone: true})
+	got, ok := tasks[task.ID]
+	if !ok {
+		t.Fatalf("task %s not listed after pull", task.ID)
+	}
+	if got.Title != "After pull" || got.Status != backend.StatusDone {
+		t.Errorf("task = %q in %s, want %q in done", got.Title, got.Status, "After pull")
+	}
+	if dirty, err := l.hasUncommittedChanges(); err != nil || dirty {
+		t.Errorf("hasUncommittedChanges() = %v, %v; want the index ignored", dirty, err)
+	}
+
+	// Mutations commit task files but never the index
+	if _, err := l.Create(backend.TaskInput{Title: "Committed"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	out, err := exec.Command("git", "-C", repoDir, "ls-files").Output()
+	if err != nil {
+		t.Fatalf("git ls-files: %v", err)
+	}
+	if strings.Contains(string(out), indexFile) {
+		t.Errorf("index was committed:\n%s", out)
+	}
+}
+
+func TestIndexRebuildsCorruptIndex(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+	task, _ := l.Create(backend.TaskInput{Title: "Survives corruption"})
+	path, _ := l.findTaskFile(task.ID)
+	backdate(t, path, time.Hour)
+	writeTestFile(t, filepath.Join(backlogDir, indexFile), "{not json")
+
+	tasks := listByID(t, reconnect(t, backlogDir, WorkspaceConfig{}), backend.TaskFilters{})
+	if got := tasks[task.ID].Title; got != "Survives corruption" {
+		t.Errorf("Title = %q, want %q", got, "Survives corruption")
+	}
+	if idx := readIndex(t, backlogDir); len(idx.Entries) != 1 {
+		t.Errorf("rebuilt index has %d entries, want 1", len(idx.Entries))
+	}
+}
+
+func TestIndexDisabled(t *testing.T) {
+	_, backlogDir := setupBacklog(t)
+	l := reconnect(t, backlogDir, WorkspaceConfig{DisableIndex: true})
+	task, _ := l.Create(backend.TaskInput{Title: "Not indexed"})
+	path, _ := l.findTaskFile(task.ID)
+	backdate(t, path, time.Hour)
+
+	listByID(t, l, backend.TaskFilters{})
+	l.Disconnect()
+
+	if _, err := os.Stat(filepath.Join(backlogDir, indexFile)); !os.IsNotExist(err) {
+		t.Errorf("index file exists with index disabled (err = %v)", err)
+	}
+	if _, err := l.Reindex(); err == nil {
+		t.Error("Reindex() succeeded with index disabled, want error")
+	}
+}
+
+func TestReindex(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+	live, _ := l.Create(backend.TaskInput{Title: "Live"})
+	done, _ := l.Create(backend.TaskInput{Title: "Done"})
+	if _, err := l.Move(done.ID, backend.StatusDone); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	deleted, _ := l.Create(backend.TaskInput{Title: "Deleted"})
+	if err := l.SoftDelete(deleted.ID); err != nil {
+		t.Fatalf("SoftDelete() error = %v", err)
+	}
+	writeTestFile(t, filepath.Join(backlogDir, "todo", "broken.md"), "no frontmatter")
+	writeTestFile(t, filepath.Join(backlogDir, indexFile), `{"version":1,"entries":{"todo/stale.md":{}}}`)
+	filepath.Walk(backlogDir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && strings.HasSuffix(path, ".md") {
+			backdate(t, path, time.Hour)
+		}
+		return nil
+	})
+
+	result, err := l.Reindex()
+	if err != nil {
+		t.Fatalf("Reindex() error = %v", err)
+	}
+	if result.Tasks != 3 || len(result.Skipped) != 1 {
+		t.Errorf("Reindex() = %d tasks, %d skipped; want 3 tasks, 1 skipped", result.Tasks, len(result.Skipped))
+	}
+	if result.Path != filepath.Join(backlogDir, indexFile) {
+		t.Errorf("Path = %q, want %q", result.Path, filepath.Join(backlogDir, indexFile))
+	}
+
+	idx := readIndex(t, backlogDir)
+	if _, ok := idx.Entries["todo/stale.md"]; ok {
+		t.Error("stale entry survived reindex")
+	}
+	ids := make(map[string]bool)
+	for _, entry := range idx.Entries {
+		ids[entry.Task.ID] = true
+	}
+	for _, id := range []string{live.ID, done.ID, deleted.ID} {
+		if !ids[id] {
+			t.Errorf("task %s missing from the index", id)
+		}
+	}
+}
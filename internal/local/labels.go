@@ -0,0 +1,327 @@
+package local
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"gopkg.in/yaml.v3"
+)
+
+// labelsFileName is the name of the file inside the .backlog directory that
+// registers known labels. It maps each label name to its metadata:
+//
+//	bug:
+//	  color: "#d73a4a"
+//	  description: Something isn't working
+const labelsFileName = "labels.yaml"
+
+// loadLabels reads the label registry from the backlog directory. A missing
+// file yields no labels.
+func loadLabels(backlogDir string) ([]backend.Label, error) {
+	content, err := os.ReadFile(filepath.Join(backlogDir, labelsFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", labelsFileName, err)
+	}
+
+	var registry map[string]backend.Label
+	if err := yaml.Unmarshal(content, &registry); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", labelsFileName, err)
+	}
+
+	labels := make([]backend.Label, 0, len(registry))
+	for name, label := range registry {
+		label.Name = name
+		labels = append(labels, label)
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+	return labels, nil
+}
+
+// ListLabels returns the labels registered in .backlog/labels.yaml.
+// Implements the backend.LabelLister interface.
+func (l *Local) ListLabels() ([]backend.Label, error) {
+	if !l.connected {
+		return nil, errors.New("not connected")
+	}
+	return loadLabels(l.path)
+}
+
+// LabelUsage returns the registered labels and the labels carried by tasks,
+// with the number of tasks carrying each. Trashed tasks are not counted.
+// Implements the backend.LabelManager interface.
+func (l *Local) LabelUsage() ([]backend.Label, error) {
+	if !l.connected {
+		return nil, errors.New("not connected")
+	}
+	labels, err := loadLabels(l.path)
+	if err != nil {
+		return nil, err
+	}
+	taskList, err := l.List(backend.TaskFilters{IncludeDone: true})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, task := range taskList.Tasks {
+		for _, label := range task.Labels {
+			counts[label]++
+		}
+	}
+	registered := make(map[string]bool, len(labels))
+	for i := range labels {
+		n := counts[labels[i].Name]
+		labels[i].Tasks = &n
+		registered[labels[i].Name] = true
+	}
+	for name, n := range counts {
+		if !registered[name] {
+			labels = append(labels, backend.Label{Name: name, Tasks: &n})
+		}
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+	return labels, nil
+}
+
+// RenameLabel replaces oldName with newName on every task carrying it, done
+// ones included, and in labels.yaml, as a single commit when git sync is
+// enabled. Implements the backend.LabelManager interface.
+func (l *Local) RenameLabel(oldName, newName string) (*backend.LabelChange, error) {
+	if !l.connected {
+		return nil, errors.New("not connected")
+	}
+	for _, label := range []string{oldName, newName} {
+		if l.isAgentLabel(label) {
+			return nil, &backend.AgentLabelError{Label: label}
+		}
+	}
+	newName = l.normalizeLabel(newName)
+	if err := l.checkGitSyncState(); err != nil {
+		return nil, err
+	}
+
+	change := &backend.LabelChange{Action: "rename", Label: oldName, NewName: newName, Tasks: []string{}}
+	found, err := l.rewriteLabel(oldName, func(labels []string) []string {
+		renamed := []string{newName}
+		for _, label := range labels {
+			if !l.sameLabel(label, oldName) && label != newName {
+				renamed = append(renamed, label)
+			}
+		}
+		sort.Strings(renamed)
+		return renamed
+	}, change)
+	if err != nil {
+		return nil, err
+	}
+	registered, err := l.editRegistryLabel(oldName, newName)
+	if err != nil {
+		return nil, err
+	}
+	if !found && !registered {
+		return nil, fmt.Errorf("%w: %s", backend.ErrLabelNotFound, oldName)
+	}
+
+	// Git commit if enabled
+	if err := l.gitCommit("label", fmt.Sprintf("rename %s to %s", oldName, newName)); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+	if err := l.pushSyncChanges(); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// RemoveLabel removes name from labels.yaml and, with force, from every
+// task carrying it, as a single commit when git sync is enabled. Implements
+// the backend.LabelManager interface.
+func (l *Local) RemoveLabel(name string, force bool) (*backend.LabelChange, error) {
+	if !l.connected {
+		return nil, errors.New("not connected")
+	}
+	if l.isAgentLabel(name) {
+		return nil, &backend.AgentLabelError{Label: name}
+	}
+	if err := l.checkGitSyncState(); err != nil {
+		return nil, err
+	}
+
+	if !force {
+		tasks, err := l.tasksWithLabel(name)
+		if err != nil {
+			return nil, err
+		}
+		if len(tasks) > 0 {
+			return nil, &backend.LabelInUseError{Label: name, Tasks: len(tasks)}
+		}
+	}
+
+	change := &backend.LabelChange{Action: "remove", Label: name, Tasks: []string{}}
+	found, err := l.rewriteLabel(name, func(labels []string) []string {
+		return slices.DeleteFunc(labels, func(label string) bool { return l.sameLabel(label, name) })
+	}, change)
+	if err != nil {
+		return nil, err
+	}
+	registered, err := l.editRegistryLabel(name, "")
+	if err != nil {
+		return nil, err
+	}
+	if !found && !registered {
+		return nil, fmt.Errorf("%w: %s", backend.ErrLabelNotFound, name)
+	}
+
+	// Git commit if enabled
+	if err := l.gitCommit("label", "remove "+name); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+	if err := l.pushSyncChanges(); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// tasksWithLabel returns the tasks carrying label, done ones included.
+func (l *Local) tasksWithLabel(label string) ([]backend.Task, error) {
+	taskList, err := l.List(backend.TaskFilters{IncludeDone: true})
+	if err != nil {
+		return nil, err
+	}
+	var tasks []backend.Task
+	for _, task := range taskList.Tasks {
+		if slices.ContainsFunc(task.Labels, func(other string) bool { return l.sameLabel(other, label) }) {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// rewriteLabel replaces the labels of every task carrying label with
+// rewrite's result, recording the tasks that changed in change. It reports
+// whether any task carried the label.
+func (l *Local) rewriteLabel(label string, rewrite func(labels []string) []string, change *backend.LabelChange) (bool, error) {
+	tasks, err := l.tasksWithLabel(label)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now().UTC()
+	for _, t := range tasks {
+		task, err := l.findTask(t.ID)
+		if err != nil {
+			return false, err
+		}
+		labels := rewrite(slices.Clone(task.Labels))
+		if slices.Equal(labels, task.Labels) {
+			continue
+		}
+		task.Labels = labels
+		task.Updated = now
+		if err := l.writeTask(task); err != nil {
+			return false, fmt.Errorf("failed to write task %s: %w", task.ID, err)
+		}
+		change.Tasks = append(change.Tasks, task.ID)
+	}
+	return len(tasks) > 0, nil
+}
+
+// editRegistryLabel renames name to newName in labels.yaml, or removes it
+// when newName is empty or already registered, editing the YAML in place so
+// that comments and order are kept. It reports whether name was registered.
+func (l *Local) editRegistryLabel(name, newName string) (bool, error) {
+	path := filepath.Join(l.path, labelsFileName)
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", labelsFileName, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return false, fmt.Errorf("invalid %s: %w", labelsFileName, err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return false, nil
+	}
+	mapping := doc.Content[0]
+	at, exists := -1, false
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key := mapping.Content[i].Value
+		if key == newName {
+			exists = true
+		} else if l.sameLabel(key, name) && at < 0 {
+			at = i
+		}
+	}
+	if at < 0 {
+		return false, nil
+	}
+	if newName == "" || exists {
+		mapping.Content = slices.Delete(mapping.Content, at, at+2)
+	} else {
+		mapping.Content[at].Value = newName
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", labelsFileName, err)
+	}
+	if err := enc.Close(); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", labelsFileName, err)
+	}
+	l.recordUndo(path)
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", labelsFileName, err)
+	}
+	return true, nil
+}
+
+// isAgentLabel reports whether label is an agent label marking a claim.
+func (l *Local) isAgentLabel(label string) bool {
+	return strings.HasPrefix(label, l.agentLabelPrefix+":")
+}
+
+// normalizeLabel returns label as it is written to task files: lowercased
+// when the workspace sets labels.normalize: lower. Agent labels keep their
+// case, since agent IDs are matched exactly.
+func (l *Local) normalizeLabel(label string) string {
+	if !l.lowercaseLabels || l.isAgentLabel(label) {
+		return label
+	}
+	return strings.ToLower(label)
+}
+
+// normalizeLabels normalizes labels, dropping the duplicates that leaves.
+func (l *Local) normalizeLabels(labels []string) []string {
+	if !l.lowercaseLabels {
+		return labels
+	}
+	var normalized []string
+	for _, label := range labels {
+		label = l.normalizeLabel(label)
+		if !slices.Contains(normalized, label) {
+			normalized = append(normalized, label)
+		}
+	}
+	return normalized
+}
+
+// sameLabel reports whether a and b are the same label, ignoring case when
+// labels are normalized.
+func (l *Local) sameLabel(a, b string) bool {
+	return l.normalizeLabel(a) == l.normalizeLabel(b)
+}
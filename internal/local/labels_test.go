@@ -0,0 +1,213 @@
+package local
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+func TestListLabels(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+
+	labels, err := l.ListLabels()
+	if err != nil {
+		t.Fatalf("ListLabels() without registry error = %v", err)
+	}
+	if len(labels) != 0 {
+		t.Errorf("ListLabels() without registry = %v, want none", labels)
+	}
+
+	registry := `feature:
+  color: green
+bug:
+  color: "#d73a4a"
+  description: Something isn't working
+`
+	if err := os.WriteFile(filepath.Join(backlogDir, labelsFileName), []byte(registry), 0644); err != nil {
+		t.Fatalf("failed to write registry: %v", err)
+	}
+
+	labels, err = l.ListLabels()
+	if err != nil {
+		t.Fatalf("ListLabels() error = %v", err)
+	}
+	want := []backend.Label{
+		{Name: "bug", Color: "#d73a4a", Description: "Something isn't working"},
+		{Name: "feature", Color: "green"},
+	}
+	if len(labels) != len(want) {
+		t.Fatalf("ListLabels() = %v, want %v", labels, want)
+	}
+	for i := range want {
+		if labels[i] != want[i] {
+			t.Errorf("ListLabels()[%d] = %+v, want %+v", i, labels[i], want[i])
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(backlogDir, labelsFileName), []byte("bug: [unclosed"), 0644); err != nil {
+		t.Fatalf("failed to write registry: %v", err)
+	}
+	if _, err := l.ListLabels(); err == nil {
+		t.Error("ListLabels() with invalid registry succeeded, want error")
+	}
+}
+
+func TestLabelUsage(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+	registry := "bug:\n  color: red\nwontfix:\n  color: gray\n"
+	if err := os.WriteFile(filepath.Join(backlogDir, labelsFileName), []byte(registry), 0644); err != nil {
+		t.Fatalf("failed to write registry: %v", err)
+	}
+	for _, input := range []backend.TaskInput{
+		{Title: "One", Labels: []string{"bug", "infra"}},
+		{Title: "Two", Labels: []string{"infra"}, Status: backend.StatusDone},
+	} {
+		if _, err := l.Create(input); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	labels, err := l.LabelUsage()
+	if err != nil {
+		t.Fatalf("LabelUsage() error = %v", err)
+	}
+	var got []string
+	for _, label := range labels {
+		got = append(got, label.Name+"="+strconv.Itoa(*label.Tasks))
+	}
+	if want := "bug=1,infra=2,wontfix=0"; strings.Join(got, ",") != want {
+		t.Errorf("LabelUsage() = %v, want %s", got, want)
+	}
+	if labels[0].Color != "red" {
+		t.Errorf("bug color = %q, want the registry's", labels[0].Color)
+	}
+}
+
+func TestRenameLabel(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+	registry := "# Team labels\ninfrastrucure:\n  color: blue # infra work\nbug:\n  color: red\n"
+	if err := os.WriteFile(filepath.Join(backlogDir, labelsFileName), []byte(registry), 0644); err != nil {
+		t.Fatalf("failed to write registry: %v", err)
+	}
+	typo, err := l.Create(backend.TaskInput{Title: "Typo", Labels: []string{"infrastrucure", "bug"}})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	both, err := l.Create(backend.TaskInput{Title: "Both", Labels: []string{"infra", "infrastrucure"}, Status: backend.StatusDone})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	other, err := l.Create(backend.TaskInput{Title: "Other", Labels: []string{"bug"}})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	change, err := l.RenameLabel("infrastrucure", "infra")
+	if err != nil {
+		t.Fatalf("RenameLabel() error = %v", err)
+	}
+	if !slices.Equal(change.Tasks, []string{typo.ID, both.ID}) {
+		t.Errorf("changed tasks = %v, want %s and %s", change.Tasks, typo.ID, both.ID)
+	}
+	for id, want := range map[string][]string{typo.ID: {"bug", "infra"}, both.ID: {"infra"}, other.ID: {"bug"}} {
+		task, err := l.Get(id)
+		if err != nil {
+			t.Fatalf("Get(%s) error = %v", id, err)
+		}
+		if !slices.Equal(task.Labels, want) {
+			t.Errorf("task %s labels = %v, want %v", id, task.Labels, want)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(backlogDir, labelsFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# Team labels", "infra:\n  color: blue # infra work"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("registry does not contain %q:\n%s", want, content)
+		}
+	}
+
+	if _, err := l.RenameLabel("missing", "other"); !errors.Is(err, backend.ErrLabelNotFound) {
+		t.Errorf("RenameLabel(missing) error = %v, want ErrLabelNotFound", err)
+	}
+	var agentErr *backend.AgentLabelError
+	if _, err := l.RenameLabel("agent:test-agent", "mine"); !errors.As(err, &agentErr) {
+		t.Errorf("RenameLabel(agent label) error = %v, want AgentLabelError", err)
+	}
+	if _, err := l.RenameLabel("bug", "agent:someone"); !errors.As(err, &agentErr) {
+		t.Errorf("RenameLabel(to agent label) error = %v, want AgentLabelError", err)
+	}
+}
+
+func TestRemoveLabel(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+	if err := os.WriteFile(filepath.Join(backlogDir, labelsFileName), []byte("stale:\n  color: gray\nbug:\n  color: red\n"), 0644); err != nil {
+		t.Fatalf("failed to write registry: %v", err)
+	}
+	task, err := l.Create(backend.TaskInput{Title: "Stale", Labels: []string{"stale", "bug"}})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	var inUse *backend.LabelInUseError
+	if _, err := l.RemoveLabel("stale", false); !errors.As(err, &inUse) || inUse.Tasks != 1 {
+		t.Fatalf("RemoveLabel() error = %v, want LabelInUseError for 1 task", err)
+	}
+
+	change, err := l.RemoveLabel("stale", true)
+	if err != nil {
+		t.Fatalf("RemoveLabel(force) error = %v", err)
+	}
+	if !slices.Equal(change.Tasks, []string{task.ID}) {
+		t.Errorf("changed tasks = %v, want %s", change.Tasks, task.ID)
+	}
+	got, _ := l.Get(task.ID)
+	if !slices.Equal(got.Labels, []string{"bug"}) {
+		t.Errorf("labels = %v, want [bug]", got.Labels)
+	}
+	labels, _ := l.ListLabels()
+	if len(labels) != 1 || labels[0].Name != "bug" {
+		t.Errorf("registry = %v, want only bug", labels)
+	}
+
+	if _, err := l.RemoveLabel("stale", false); !errors.Is(err, backend.ErrLabelNotFound) {
+		t.Errorf("RemoveLabel() of a removed label error = %v, want ErrLabelNotFound", err)
+	}
+}
+
+func TestLowercaseLabels(t *testing.T) {
+	l, _ := setupBacklog(t)
+	l.lowercaseLabels = true
+
+	task, err := l.Create(backend.TaskInput{Title: "Mixed", Labels: []string{"Infra", "infra", "Bug"}})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if !slices.Equal(task.Labels, []string{"infra", "bug"}) {
+		t.Errorf("Create() labels = %v, want lowercased without duplicates", task.Labels)
+	}
+
+	task, err = l.Update(task.ID, backend.TaskChanges{AddLabels: []string{"API", "agent:Claude-1"}, RemoveLabels: []string{"BUG"}})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if !slices.Equal(task.Labels, []string{"agent:Claude-1", "api", "infra"}) {
+		t.Errorf("Update() labels = %v, want lowercased, keeping the agent label's case", task.Labels)
+	}
+
+	list, err := l.List(backend.TaskFilters{Labels: []string{"INFRA"}})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list.Tasks) != 1 {
+		t.Errorf("List(INFRA) = %d tasks, want the filter to ignore case", len(list.Tasks))
+	}
+}
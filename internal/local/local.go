@@ -6,12 +6,15 @@ package local
 import (
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/alexbrand/backlog/internal/backend"
@@ -43,16 +46,44 @@ type WorkspaceConfig struct {
 	LockMode LockMode
 	// GitSync enables automatic git commits after mutations.
 	GitSync bool
+	// IdempotencyWindow is how long idempotency keys passed to Create are
+	// remembered. Zero means backend.DefaultIdempotencyWindow.
+	IdempotencyWindow time.Duration
+	// GitTimeout bounds each git command run for git_sync and git locking.
+	// Zero means DefaultGitTimeout.
+	GitTimeout time.Duration
+	// DisableIndex turns off the .index.json cache of parsed task files.
+	DisableIndex bool
+	// CommitMessage replaces the "<id>" part of the commit messages made
+	// for git_sync, as in "move: <message>". Empty means the default.
+	CommitMessage string
+	// UndoLimit is how many recent mutations are kept in .backlog/.undo for
+	// backlog undo. Zero disables recording them.
+	UndoLimit int
+	// LowercaseLabels lowercases labels written to tasks and makes label
+	// filters ignore case. Agent labels are left as they are.
+	LowercaseLabels bool
 }
 
 // Local implements the Backend interface using the local filesystem.
 type Local struct {
-	path             string
-	agentID          string
-	agentLabelPrefix string
-	lockMode         LockMode
-	gitSync          bool
-	connected        bool
+	path              string
+	agentID           string
+	agentLabelPrefix  string
+	lockMode          LockMode
+	gitSync           bool
+	idempotencyWindow time.Duration
+	gitTimeout        time.Duration
+	commitMessage     string
+	lowercaseLabels   bool
+	ignore            *ignoreMatcher
+	indexEnabled      bool
+	index             *taskIndex
+	indexMu           sync.Mutex // guards index entries while a list parses files concurrently
+	undoLimit         int
+	pendingUndo       []undoFile
+	undoIncomplete    bool
+	connected         bool
 }
 
 // New creates a new Local backend instance.
@@ -98,6 +129,19 @@ func (l *Local) Connect(cfg backend.Config) error {
 	// Set git sync
 	l.gitSync = wsCfg.GitSync
 
+	l.idempotencyWindow = wsCfg.IdempotencyWindow
+	if l.idempotencyWindow <= 0 {
+		l.idempotencyWindow = backend.DefaultIdempotencyWindow
+	}
+
+	l.gitTimeout = wsCfg.GitTimeout
+	if l.gitTimeout <= 0 {
+		l.gitTimeout = DefaultGitTimeout
+	}
+
+	l.commitMessage = wsCfg.CommitMessage
+	l.lowercaseLabels = wsCfg.LowercaseLabels
+
 	// Create the .backlog directory if it doesn't exist
 	if _, err := os.Stat(l.path); os.IsNotExist(err) {
 		if err := l.initDirectory(); err != nil {
@@ -105,12 +149,26 @@ func (l *Local) Connect(cfg backend.Config) error {
 		}
 	}
 
+	// Load exclusion patterns for auxiliary files kept in the backlog tree
+	ignore, err := loadIgnoreFile(l.path)
+	if err != nil {
+		return err
+	}
+	l.ignore = ignore
+
+	l.indexEnabled = !wsCfg.DisableIndex
+	l.index = nil
+
+	l.undoLimit = wsCfg.UndoLimit
+	l.discardUndo()
+
 	l.connected = true
 	return nil
 }
 
 // Disconnect closes the backend connection.
 func (l *Local) Disconnect() error {
+	l.flushIndex()
 	l.connected = false
 	return nil
 }
@@ -145,60 +203,27 @@ func (l *Local) HealthCheck() (backend.HealthStatus, error) {
 
 // List returns tasks matching the given filters.
 func (l *Local) List(filters backend.TaskFilters) (*backend.TaskList, error) {
+	return l.ListSorted(filters, nil)
+}
+
+// ListSorted returns tasks matching the given filters, sorted by keys ahead
+// of the default order, which breaks ties. Implements the backend.Sorter
+// interface.
+func (l *Local) ListSorted(filters backend.TaskFilters, keys []backend.SortKey) (*backend.TaskList, error) {
 	if !l.connected {
 		return nil, errors.New("not connected")
 	}
 
 	// Initialize as empty slice (not nil) so JSON encoding produces [] not null
 	tasks := []backend.Task{}
-
-	// Determine which status directories to scan
-	statusDirs := []backend.Status{
-		backend.StatusBacklog,
-		backend.StatusTodo,
-		backend.StatusInProgress,
-		backend.StatusReview,
-	}
-	if filters.IncludeDone {
-		statusDirs = append(statusDirs, backend.StatusDone)
+	scanned, warnings, err := l.scanTasks(filters, func(task *backend.Task) {
+		tasks = append(tasks, *task)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Filter by status if specified
-	if len(filters.Status) > 0 {
-		statusDirs = filters.Status
-	}
-
-	// Scan each status directory
-	for _, status := range statusDirs {
-		dirPath := filepath.Join(l.path, string(status))
-		entries, err := os.ReadDir(dirPath)
-		if os.IsNotExist(err) {
-			continue
-		}
-		if err != nil {
-			return nil, fmt.Errorf("failed to read directory %s: %w", dirPath, err)
-		}
-
-		for _, entry := range entries {
-			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
-				continue
-			}
-
-			filePath := filepath.Join(dirPath, entry.Name())
-			task, err := l.readTaskFile(filePath, status)
-			if err != nil {
-				// Skip files that can't be parsed
-				continue
-			}
-
-			// Apply filters
-			if !l.matchesFilters(task, filters) {
-				continue
-			}
-
-			tasks = append(tasks, *task)
-		}
-	}
+	slog.Debug("evaluated list filters", "backend", Name, "scanned", scanned, "matched", len(tasks), "skipped_unparseable", len(warnings))
 
 	// Sort by priority (urgent first), then by sort_order if set, then by created (oldest first),
 	// then by ID for deterministic order
@@ -226,6 +251,7 @@ func (l *Local) List(filters backend.TaskFilters) (*backend.TaskList, error) {
 		}
 		return tasks[i].ID < tasks[j].ID
 	})
+	backend.SortTasks(tasks, keys)
 
 	// Apply limit
 	hasMore := false
@@ -235,12 +261,37 @@ func (l *Local) List(filters backend.TaskFilters) (*backend.TaskList, error) {
 	}
 
 	return &backend.TaskList{
-		Tasks:   tasks,
-		Count:   len(tasks),
-		HasMore: hasMore,
+		Tasks:    tasks,
+		Count:    len(tasks),
+		HasMore:  hasMore,
+		Warnings: warnings,
+		Skipped:  len(warnings),
 	}, nil
 }
 
+// Count returns the number of tasks matching the given filters, ignoring
+// Limit. Unlike List, it doesn't keep or sort the matching tasks.
+// Implements the backend.Counter interface.
+func (l *Local) Count(filters backend.TaskFilters) (int, error) {
+	if !l.connected {
+		return 0, errors.New("not connected")
+	}
+
+	count := 0
+	scanned, warnings, err := l.scanTasks(filters, func(*backend.Task) {
+		count++
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, warning := range warnings {
+		slog.Warn(warning)
+	}
+
+	slog.Debug("counted tasks", "backend", Name, "scanned", scanned, "matched", count)
+	return count, nil
+}
+
 // Get returns a single task by ID.
 func (l *Local) Get(id string) (*backend.Task, error) {
 	if !l.connected {
@@ -250,16 +301,164 @@ func (l *Local) Get(id string) (*backend.Task, error) {
 	return l.findTask(id)
 }
 
+// GetRaw returns the task file contents exactly as stored on disk.
+// Implements the backend.RawGetter interface.
+func (l *Local) GetRaw(id string) ([]byte, error) {
+	if !l.connected {
+		return nil, errors.New("not connected")
+	}
+
+	filePath, err := l.findTaskFile(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(filePath)
+}
+
 // Create creates a new task and returns it.
 func (l *Local) Create(input backend.TaskInput) (*backend.Task, error) {
 	if !l.connected {
 		return nil, errors.New("not connected")
 	}
 
-	// Generate a new ID
-	id, err := l.generateID()
+	if input.ID != "" && !pinnedIDPattern.MatchString(input.ID) {
+		return nil, fmt.Errorf("invalid task ID %q: use up to 64 letters, digits, _ or -, starting with a letter or digit", input.ID)
+	}
+
+	task, created, err := l.createTask(input)
+	if err != nil || !created {
+		return task, err
+	}
+
+	// Git commit if enabled
+	if err := l.gitCommit("add", task.ID); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return task, nil
+}
+
+// CreateAndClaim creates a task and claims it for an agent with a single git
+// commit. In git lock mode the claim is pushed like any other; if the push
+// fails, the commit is kept and the error returned for 'backlog sync' to
+// resolve. A task matched by an idempotency key or IfAbsent is claimed as
+// Claim would.
+// Implements the backend.CreateClaimer interface.
+func (l *Local) CreateAndClaim(input backend.TaskInput, agentID string) (*backend.Task, *backend.ClaimResult, error) {
+	if !l.connected {
+		return nil, nil, errors.New("not connected")
+	}
+	if input.ID != "" && !pinnedIDPattern.MatchString(input.ID) {
+		return nil, nil, fmt.Errorf("invalid task ID %q: use up to 64 letters, digits, _ or -, starting with a letter or digit", input.ID)
+	}
+	if agentID == "" {
+		agentID = l.agentID
+	} else {
+		// Update l.agentID for use in gitCommit message
+		l.agentID = agentID
+	}
+
+	// Pull before writing anything, since the pull needs a clean tree
+	if l.lockMode == LockModeGit {
+		if err := l.gitPull(); err != nil {
+			return nil, nil, fmt.Errorf("failed to pull: %w", err)
+		}
+	}
+
+	task, created, err := l.createTask(input)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !created {
+		claim, _, err := l.claim(task.ID, agentID, claimOptions{})
+		return task, claim, err
+	}
+
+	var claim *backend.ClaimResult
+	if l.lockMode == LockModeGit {
+		claim, _, err = l.claimInWorkingTree(task.ID, agentID, claimOptions{})
+	} else {
+		claim, _, err = l.claimFileLocked(task.ID, agentID, claimOptions{})
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate ID: %w", err)
+		// Keep the new task, committed on its own
+		if commitErr := l.gitCommit("add", task.ID); commitErr != nil {
+			slog.Warn("failed to commit task after its claim failed", "task", task.ID, "error", commitErr)
+		}
+		return task, nil, err
+	}
+
+	if err := l.gitCommit("add+claim", task.ID); err != nil {
+		return task, nil, fmt.Errorf("failed to commit: %w", err)
+	}
+	if l.lockMode == LockModeGit {
+		if err := l.gitPush(); err != nil {
+			return task, nil, fmt.Errorf("failed to push: %w", err)
+		}
+	}
+
+	return task, claim, nil
+}
+
+// createTask creates a task under the create lock, without committing it.
+// It reports whether a task was created, rather than an existing one
+// returned for an idempotency key or IfAbsent match.
+func (l *Local) createTask(input backend.TaskInput) (*backend.Task, bool, error) {
+	// The create lock makes choosing the ID and checking for an existing
+	// task atomic with writing the new one, so two identical adds racing
+	// in this backlog can't both create a task. Clones synced through git
+	// don't share the lock and can still race until they sync.
+	var task *backend.Task
+	var created bool
+	err := l.withCreateLock(func() error {
+		var err error
+		task, created, err = l.createLocked(input)
+		return err
+	})
+	return task, created, err
+}
+
+// pinnedIDPattern limits pinned task IDs to characters that are safe in
+// task file names.
+var pinnedIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+// createLocked creates a task while the create lock is held. It reports
+// whether a task was created, rather than an existing one returned for an
+// idempotency key or IfAbsent match.
+func (l *Local) createLocked(input backend.TaskInput) (*backend.Task, bool, error) {
+	if input.IdempotencyKey != "" {
+		if task, err := l.lookupIdempotencyKey(input.IdempotencyKey); err != nil || task != nil {
+			return task, false, err
+		}
+	}
+
+	// The create lock makes the search and the create atomic
+	if task, err := backend.LookupExisting(input, l.List); err != nil || task != nil {
+		return task, false, err
+	}
+
+	if input.Parent != "" {
+		if _, err := l.findTask(input.Parent); err != nil {
+			return nil, false, fmt.Errorf("parent task not found: %s", input.Parent)
+		}
+	}
+
+	// Use the pinned ID, or generate a new one. Trashed tasks keep their
+	// IDs, so a pinned ID can't reuse one either.
+	id := input.ID
+	if id != "" {
+		if _, err := l.findTaskFile(id); err == nil {
+			return nil, false, &backend.AlreadyExistsError{ID: id}
+		}
+		if _, err := l.findTaskFileIn(l.trashPath(), id); err == nil {
+			return nil, false, &backend.AlreadyExistsError{ID: id, Where: "in the trash"}
+		}
+	} else {
+		var err error
+		if id, err = l.generateID(); err != nil {
+			return nil, false, fmt.Errorf("failed to generate ID: %w", err)
+		}
 	}
 
 	// Set defaults
@@ -280,31 +479,38 @@ func (l *Local) Create(input backend.TaskInput) (*backend.Task, error) {
 		Status:      status,
 		Priority:    priority,
 		Assignee:    input.Assignee,
-		Labels:      input.Labels,
+		Labels:      l.normalizeLabels(input.Labels),
+		Parent:      input.Parent,
 		Created:     now,
 		Updated:     now,
 	}
+	backend.SetChecklist(task)
 
 	// Write the task file
 	if err := l.writeTask(task); err != nil {
-		return nil, fmt.Errorf("failed to write task: %w", err)
+		return nil, false, fmt.Errorf("failed to write task: %w", err)
 	}
 
-	// Git commit if enabled
-	if err := l.gitCommit("add", id); err != nil {
-		return nil, fmt.Errorf("failed to commit: %w", err)
+	// Record the key before committing so it's shared with other clones
+	if input.IdempotencyKey != "" {
+		if err := l.recordIdempotencyKey(input.IdempotencyKey, id); err != nil {
+			return nil, false, err
+		}
 	}
 
-	return task, nil
+	return task, true, nil
 }
 
 // Update modifies an existing task and returns the updated task.
 // This is the public method that commits changes to git if enabled.
 func (l *Local) Update(id string, changes backend.TaskChanges) (*backend.Task, error) {
-	task, err := l.updateInternal(id, changes)
+	task, changed, err := l.updateTask(id, changes)
 	if err != nil {
 		return nil, err
 	}
+	if !changed {
+		return task, nil
+	}
 
 	// Git commit if enabled
 	if err := l.gitCommit("edit", id); err != nil {
@@ -317,20 +523,28 @@ func (l *Local) Update(id string, changes backend.TaskChanges) (*backend.Task, e
 // updateInternal modifies an existing task without git commit.
 // Used internally by Claim, Release, etc. that handle their own commits.
 func (l *Local) updateInternal(id string, changes backend.TaskChanges) (*backend.Task, error) {
+	task, _, err := l.updateTask(id, changes)
+	return task, err
+}
+
+// updateTask applies changes to a task and reports whether anything
+// changed. Changes that leave the task as it was don't touch its file.
+func (l *Local) updateTask(id string, changes backend.TaskChanges) (*backend.Task, bool, error) {
 	if !l.connected {
-		return nil, errors.New("not connected")
+		return nil, false, errors.New("not connected")
 	}
 
 	// Find the old file path before applying changes
 	oldFilePath, err := l.findTaskFile(id)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	task, err := l.readTaskFile(oldFilePath, l.statusFromPath(oldFilePath))
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
+	before := *task
 
 	// Apply changes
 	if changes.Title != nil {
@@ -338,6 +552,7 @@ func (l *Local) updateInternal(id string, changes backend.TaskChanges) (*backend
 	}
 	if changes.Description != nil {
 		task.Description = *changes.Description
+		backend.SetChecklist(task)
 	}
 	if changes.Priority != nil {
 		task.Priority = *changes.Priority
@@ -345,51 +560,77 @@ func (l *Local) updateInternal(id string, changes backend.TaskChanges) (*backend
 	if changes.Assignee != nil {
 		task.Assignee = *changes.Assignee
 	}
+	if changes.Estimate != nil {
+		task.Estimate = *changes.Estimate
+	}
+	if changes.Spent != nil {
+		task.Spent = *changes.Spent
+	}
+	if changes.Parent != nil {
+		if err := l.checkParent(task.ID, *changes.Parent); err != nil {
+			return nil, false, err
+		}
+		task.Parent = *changes.Parent
+	}
 
-	// Handle label changes
+	// Handle label changes. Labels are a set: adding one the task already
+	// has leaves its labels untouched, and removing one it doesn't have is
+	// not an error.
 	if len(changes.AddLabels) > 0 {
 		labelSet := make(map[string]bool)
 		for _, l := range task.Labels {
 			labelSet[l] = true
 		}
-		for _, l := range changes.AddLabels {
-			labelSet[l] = true
+		added := false
+		for _, label := range changes.AddLabels {
+			label = l.normalizeLabel(label)
+			if !labelSet[label] {
+				labelSet[label] = true
+				added = true
+			}
 		}
-		task.Labels = make([]string, 0, len(labelSet))
-		for l := range labelSet {
-			task.Labels = append(task.Labels, l)
+		if added {
+			task.Labels = make([]string, 0, len(labelSet))
+			for l := range labelSet {
+				task.Labels = append(task.Labels, l)
+			}
+			sort.Strings(task.Labels)
 		}
-		sort.Strings(task.Labels)
 	}
 	if len(changes.RemoveLabels) > 0 {
 		removeSet := make(map[string]bool)
-		for _, l := range changes.RemoveLabels {
-			removeSet[l] = true
+		for _, label := range changes.RemoveLabels {
+			removeSet[l.normalizeLabel(label)] = true
 		}
 		newLabels := make([]string, 0, len(task.Labels))
-		for _, l := range task.Labels {
-			if !removeSet[l] {
-				newLabels = append(newLabels, l)
+		for _, label := range task.Labels {
+			if !removeSet[l.normalizeLabel(label)] {
+				newLabels = append(newLabels, label)
 			}
 		}
 		task.Labels = newLabels
 	}
 
+	// Nothing to write if the changes leave the task as it was
+	if len(backend.DiffTasks(&before, task)) == 0 {
+		return task, false, nil
+	}
 	task.Updated = time.Now().UTC()
 
 	// Write the updated task
 	if err := l.writeTask(task); err != nil {
-		return nil, fmt.Errorf("failed to write task: %w", err)
+		return nil, false, fmt.Errorf("failed to write task: %w", err)
 	}
 
 	// Remove old file if the filename changed (due to title change)
 	newFilename := generateFilename(task.ID, task.Title)
 	newFilePath := filepath.Join(l.path, string(task.Status), newFilename)
 	if oldFilePath != newFilePath {
+		l.recordUndo(oldFilePath)
 		os.Remove(oldFilePath)
 	}
 
-	return task, nil
+	return task, true, nil
 }
 
 // Delete removes a task by ID.
@@ -403,6 +644,7 @@ func (l *Local) Delete(id string) error {
 		return err
 	}
 
+	l.recordUndo(filePath)
 	if err := os.Remove(filePath); err != nil {
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
@@ -418,30 +660,8 @@ func (l *Local) Delete(id string) error {
 // Move transitions a task to a new status.
 // This is the public method that commits changes to git if enabled.
 func (l *Local) Move(id string, status backend.Status) (*backend.Task, error) {
-	// When git_sync is enabled, check for uncommitted changes first
-	if l.gitSync {
-		hasUncommitted, err := l.hasUncommittedChanges()
-		if err != nil {
-			return nil, fmt.Errorf("failed to check for uncommitted changes: %w", err)
-		}
-		if hasUncommitted {
-			return nil, &UncommittedChangesError{
-				Message: "please commit or stash your changes before running this command",
-			}
-		}
-
-		// Check if remote is ahead - if so, fail with conflict error
-		// This ensures we detect when another agent has pushed changes
-		ahead, err := l.isRemoteAhead()
-		if err != nil {
-			return nil, fmt.Errorf("failed to check remote status: %w", err)
-		}
-		if ahead {
-			return nil, &SyncConflictError{
-				Operation: "sync",
-				Message:   "conflict: remote has changes - run 'backlog sync' to update",
-			}
-		}
+	if err := l.checkGitSyncState(); err != nil {
+		return nil, err
 	}
 
 	task, err := l.moveInternal(id, status)
@@ -454,26 +674,173 @@ func (l *Local) Move(id string, status backend.Status) (*backend.Task, error) {
 		return nil, fmt.Errorf("failed to commit: %w", err)
 	}
 
-	// Push to remote if git_sync is enabled
-	if l.gitSync {
-		if err := l.gitPush(); err != nil {
-			// Check if it's a push conflict
-			if _, isConflict := err.(*GitPushConflictError); isConflict {
-				return nil, &SyncConflictError{
-					Operation: "push",
-					Message:   "remote has changes that conflict with local changes",
-				}
+	if err := l.pushSyncChanges(); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// Reopen moves a done task back to the given status and adds a comment
+// recording why, as a single git commit when git sync is enabled.
+// Implements the backend.Reopener interface.
+func (l *Local) Reopen(id string, status backend.Status, comment string) (*backend.Task, error) {
+	if err := l.checkGitSyncState(); err != nil {
+		return nil, err
+	}
+
+	task, err := l.findTask(id)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != backend.StatusDone {
+		return nil, fmt.Errorf("task %s is not done (status: %s)", id, task.Status)
+	}
+
+	if _, err := l.moveInternal(id, status); err != nil {
+		return nil, err
+	}
+	if _, err := l.addCommentInternal(id, comment); err != nil {
+		return nil, err
+	}
+
+	// Git commit if enabled
+	if err := l.gitCommit("reopen", id); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	if err := l.pushSyncChanges(); err != nil {
+		return nil, err
+	}
+
+	return l.findTask(id)
+}
+
+// MoveAndAssign moves a task and sets its assignee as a single git commit
+// when git sync is enabled. Implements the backend.MoveAssigner interface.
+func (l *Local) MoveAndAssign(id string, status backend.Status, assignee string) (*backend.Task, error) {
+	if err := l.checkGitSyncState(); err != nil {
+		return nil, err
+	}
+
+	// Both changes go into one write, so a failure can't leave the task
+	// moved without its assignee
+	task, err := l.moveTask(id, status, func(task *backend.Task) {
+		task.Assignee = assignee
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Git commit if enabled
+	if err := l.gitCommit("move", id); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	if err := l.pushSyncChanges(); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// checkGitSyncState verifies that a mutation can proceed when git_sync is
+// enabled: a branch must be checked out, the working tree must be clean and
+// the remote must not be ahead.
+func (l *Local) checkGitSyncState() error {
+	if !l.gitSync {
+		return nil
+	}
+
+	if err := l.checkBranch(); err != nil {
+		return err
+	}
+
+	hasUncommitted, err := l.hasUncommittedChanges()
+	if err != nil {
+		return fmt.Errorf("failed to check for uncommitted changes: %w", err)
+	}
+	if hasUncommitted {
+		return &UncommittedChangesError{
+			Message: "please commit or stash your changes before running this command",
+		}
+	}
+
+	// Check if remote is ahead - if so, fail with conflict error
+	// This ensures we detect when another agent has pushed changes
+	ahead, err := l.isRemoteAhead()
+	if err != nil {
+		return fmt.Errorf("failed to check remote status: %w", err)
+	}
+	if ahead {
+		return &SyncConflictError{
+			Operation: "sync",
+			Message:   "conflict: remote has changes - run 'backlog sync' to update",
+		}
+	}
+
+	return nil
+}
+
+// Pull rebases the backlog onto the remote when git_sync is enabled, so a
+// following mutation isn't refused because the remote is ahead. The working
+// tree must be clean and on a branch. A conflicting rebase is aborted and reported as a
+// SyncConflictError. Implements the backend.Puller interface.
+func (l *Local) Pull() error {
+	if !l.connected {
+		return errors.New("not connected")
+	}
+	if !l.gitSync {
+		return nil
+	}
+
+	if err := l.checkBranch(); err != nil {
+		return err
+	}
+
+	hasUncommitted, err := l.hasUncommittedChanges()
+	if err != nil {
+		return fmt.Errorf("failed to check for uncommitted changes: %w", err)
+	}
+	if hasUncommitted {
+		return &UncommittedChangesError{
+			Message: "please commit or stash your changes before running this command",
+		}
+	}
+
+	return l.gitPull()
+}
+
+// pushSyncChanges pushes committed changes to the remote if git_sync is enabled.
+func (l *Local) pushSyncChanges() error {
+	if !l.gitSync {
+		return nil
+	}
+
+	if err := l.gitPush(); err != nil {
+		// Check if it's a push conflict
+		if _, isConflict := err.(*GitPushConflictError); isConflict {
+			return &SyncConflictError{
+				Operation: "push",
+				Message:   "remote has changes that conflict with local changes",
 			}
-			return nil, fmt.Errorf("failed to push: %w", err)
 		}
+		return fmt.Errorf("failed to push: %w", err)
 	}
 
-	return task, nil
+	return nil
 }
 
 // moveInternal transitions a task to a new status without git commit.
 // Used internally by Claim, Release, etc. that handle their own commits.
 func (l *Local) moveInternal(id string, status backend.Status) (*backend.Task, error) {
+	return l.moveTask(id, status, nil)
+}
+
+// moveTask is moveInternal that also applies edit, if it isn't nil, to the
+// task before it is written. The task is written to its new place before
+// the old file is removed, so a failure leaves the task as it was.
+func (l *Local) moveTask(id string, status backend.Status, edit func(*backend.Task)) (*backend.Task, error) {
 	if !l.connected {
 		return nil, errors.New("not connected")
 	}
@@ -491,16 +858,23 @@ func (l *Local) moveInternal(id string, status backend.Status) (*backend.Task, e
 	task.Status = status
 	task.Updated = time.Now().UTC()
 
-	// If status changed, we need to move the file
-	if oldStatus != status {
-		// Remove old file
-		oldPath, err := l.findTaskFile(id)
-		if err != nil {
-			return nil, err
-		}
-		if err := os.Remove(oldPath); err != nil {
-			return nil, fmt.Errorf("failed to remove old task file: %w", err)
+	// Stop the clock on completion; reopening a task clears it again
+	if status == backend.StatusDone {
+		if oldStatus != backend.StatusDone || task.CompletedAt == nil {
+			completed := task.Updated
+			task.CompletedAt = &completed
 		}
+	} else {
+		task.CompletedAt = nil
+	}
+	backend.SetDuration(task, task.Updated)
+	if edit != nil {
+		edit(task)
+	}
+
+	oldPath, err := l.findTaskFile(id)
+	if err != nil {
+		return nil, err
 	}
 
 	// Write to new location
@@ -508,6 +882,15 @@ func (l *Local) moveInternal(id string, status backend.Status) (*backend.Task, e
 		return nil, fmt.Errorf("failed to write task: %w", err)
 	}
 
+	// If status changed, remove the file from the old status directory
+	if oldStatus != status {
+		l.recordUndo(oldPath)
+		if err := os.Remove(oldPath); err != nil {
+			os.Remove(filepath.Join(l.path, string(status), generateFilename(task.ID, task.Title)))
+			return nil, fmt.Errorf("failed to remove old task file: %w", err)
+		}
+	}
+
 	return task, nil
 }
 
@@ -544,8 +927,46 @@ func (l *Local) ListComments(id string) ([]backend.Comment, error) {
 	return comments, nil
 }
 
+// ListCommentsFiltered returns the comments on a task that match filters.
+// Older task files only record the day a comment was written, so for those
+// comments the Since cutoff is rounded down to its day: comments from that
+// day are included.
+func (l *Local) ListCommentsFiltered(id string, filters backend.CommentFilters) ([]backend.Comment, error) {
+	comments, err := l.ListComments(id)
+	if err != nil {
+		return nil, err
+	}
+	if !filters.Since.IsZero() {
+		day := filters.Since.UTC().Truncate(24 * time.Hour)
+		var since []backend.Comment
+		for _, comment := range comments {
+			dateOnly := comment.Created.Equal(comment.Created.Truncate(24 * time.Hour))
+			if comment.Created.After(filters.Since) || (dateOnly && !comment.Created.Before(day)) {
+				since = append(since, comment)
+			}
+		}
+		comments, filters.Since = since, time.Time{}
+	}
+	return backend.FilterComments(comments, filters), nil
+}
+
 // AddComment adds a comment to a task.
 func (l *Local) AddComment(id string, body string) (*backend.Comment, error) {
+	comment, err := l.addCommentInternal(id, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Git commit if enabled
+	if err := l.gitCommit("comment", id); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return comment, nil
+}
+
+// addCommentInternal appends a comment to a task without git commit.
+func (l *Local) addCommentInternal(id string, body string) (*backend.Comment, error) {
 	if !l.connected {
 		return nil, errors.New("not connected")
 	}
@@ -566,30 +987,145 @@ func (l *Local) AddComment(id string, body string) (*backend.Comment, error) {
 		comments = existing
 	}
 
-	// Use task ID as the comment ID for consistency with other backends
+	// Task files store comment times to the second
+	lastID, _ := task.Meta[lastCommentIDMetaKey].(string)
 	comment := backend.Comment{
-		ID:      id,
+		ID:      nextCommentID(comments, lastID),
 		Author:  l.agentID,
 		Body:    body,
-		Created: time.Now().UTC(),
+		Created: time.Now().UTC().Truncate(time.Second),
 	}
 
 	comments = append(comments, comment)
 	task.Meta["comments"] = comments
+	delete(task.Meta, lastCommentIDMetaKey)
 	task.Updated = time.Now().UTC()
 
 	if err := l.writeTask(task); err != nil {
 		return nil, fmt.Errorf("failed to write task: %w", err)
 	}
 
-	// Git commit if enabled
-	if err := l.gitCommit("comment", id); err != nil {
+	comment.TaskID = task.ID
+	return &comment, nil
+}
+
+// EditComment replaces the body of a comment on a task.
+// Implements the backend.CommentEditor interface.
+func (l *Local) EditComment(taskID, commentID, body string, force bool) (*backend.Comment, error) {
+	task, comments, i, err := l.findComment(taskID, commentID, force)
+	if err != nil {
+		return nil, err
+	}
+
+	comments[i].Body = body
+	task.Updated = time.Now().UTC()
+	if err := l.writeTask(task); err != nil {
+		return nil, fmt.Errorf("failed to write task: %w", err)
+	}
+
+	if err := l.gitCommit("edit-comment", task.ID); err != nil {
 		return nil, fmt.Errorf("failed to commit: %w", err)
 	}
 
+	comment := comments[i]
+	comment.TaskID = task.ID
 	return &comment, nil
 }
 
+// DeleteComment removes a comment from a task.
+// Implements the backend.CommentEditor interface.
+func (l *Local) DeleteComment(taskID, commentID string, force bool) error {
+	task, comments, i, err := l.findComment(taskID, commentID, force)
+	if err != nil {
+		return err
+	}
+
+	// Remember the deleted ID if it was the highest, so it isn't reused
+	deletedID := comments[i].ID
+	comments = append(comments[:i], comments[i+1:]...)
+	if len(comments) == 0 {
+		delete(task.Meta, "comments")
+	} else {
+		task.Meta["comments"] = comments
+	}
+	lastID, _ := task.Meta[lastCommentIDMetaKey].(string)
+	if n := commentNumber(deletedID); n > max(commentNumber(lastID), highestCommentNumber(comments)) {
+		task.Meta[lastCommentIDMetaKey] = strconv.Itoa(n)
+	}
+	task.Updated = time.Now().UTC()
+	if err := l.writeTask(task); err != nil {
+		return fmt.Errorf("failed to write task: %w", err)
+	}
+
+	if err := l.gitCommit("delete-comment", task.ID); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+// findComment returns a task, its comments, and the index of the comment
+// with the given ID. Comments by another agent are refused unless force is
+// set.
+func (l *Local) findComment(taskID, commentID string, force bool) (*backend.Task, []backend.Comment, int, error) {
+	if !l.connected {
+		return nil, nil, 0, errors.New("not connected")
+	}
+
+	task, err := l.findTask(taskID)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	comments, _ := task.Meta["comments"].([]backend.Comment)
+	for i, comment := range comments {
+		if !sameCommentID(comment.ID, commentID) {
+			continue
+		}
+		if !force && comment.Author != l.agentID {
+			return nil, nil, 0, &backend.CommentAuthorError{CommentID: commentID, Author: comment.Author}
+		}
+		return task, comments, i, nil
+	}
+	return nil, nil, 0, fmt.Errorf("comment %s not found on task %s", commentID, taskID)
+}
+
+// nextCommentID returns a numeric ID for a new comment, one above the
+// highest ID in use or lastID, the highest handed out before, so that IDs
+// keep increasing even after comments are deleted.
+func nextCommentID(comments []backend.Comment, lastID string) string {
+	return strconv.Itoa(max(highestCommentNumber(comments), commentNumber(lastID)) + 1)
+}
+
+// highestCommentNumber returns the highest comment ID number, or 0 if there
+// are no numbered comments.
+func highestCommentNumber(comments []backend.Comment) int {
+	highest := 0
+	for _, comment := range comments {
+		highest = max(highest, commentNumber(comment.ID))
+	}
+	return highest
+}
+
+// commentNumber returns n for a comment ID "<n>", or "c<n>" as written
+// before IDs were plain numbers, and 0 for any other ID.
+func commentNumber(id string) int {
+	n, err := strconv.Atoi(strings.TrimPrefix(id, "c"))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// sameCommentID reports whether two comment IDs name the same comment, so
+// that "2" finds a comment stored as "c2" and the other way round.
+func sameCommentID(a, b string) bool {
+	if a == b {
+		return true
+	}
+	n := commentNumber(a)
+	return n > 0 && n == commentNumber(b)
+}
+
 // Helper functions
 
 // initDirectory creates the backlog directory structure with all status subdirectories.
@@ -631,8 +1167,19 @@ func (l *Local) findTask(id string) (*backend.Task, error) {
 	return l.readTaskFile(filePath, status)
 }
 
-// findTaskFile finds the file path for a task by ID.
+// findTaskFile finds the file path for a task by ID. Soft-deleted tasks are
+// not found.
 func (l *Local) findTaskFile(id string) (string, error) {
+	return l.findTaskFileIn(l.path, id)
+}
+
+// findTaskFileIn finds the file path for a task by ID in the status
+// directories under root.
+func (l *Local) findTaskFileIn(root, id string) (string, error) {
+	if filePath := l.indexedTaskFile(root, id); filePath != "" {
+		return filePath, nil
+	}
+
 	statuses := []backend.Status{
 		backend.StatusBacklog,
 		backend.StatusTodo,
@@ -642,7 +1189,7 @@ func (l *Local) findTaskFile(id string) (string, error) {
 	}
 
 	for _, status := range statuses {
-		dirPath := filepath.Join(l.path, string(status))
+		dirPath := filepath.Join(root, string(status))
 		entries, err := os.ReadDir(dirPath)
 		if os.IsNotExist(err) {
 			continue
@@ -652,19 +1199,25 @@ func (l *Local) findTaskFile(id string) (string, error) {
 		}
 
 		for _, entry := range entries {
-			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") || l.isIgnored(status, entry.Name()) {
 				continue
 			}
 
 			// Check if filename starts with the ID
 			baseName := strings.TrimSuffix(entry.Name(), ".md")
-			if baseName == id || strings.HasPrefix(baseName, id+"-") {
+			if taskFileMatchesID(baseName, id) {
 				return filepath.Join(dirPath, entry.Name()), nil
 			}
 		}
 	}
 
-	return "", fmt.Errorf("task not found: %s", id)
+	return "", &backend.NotFoundError{ID: id}
+}
+
+// isIgnored reports whether a file in a status directory is excluded by the
+// .backlogignore file and should not be treated as a task.
+func (l *Local) isIgnored(status backend.Status, name string) bool {
+	return l.ignore.Ignored(string(status) + "/" + name)
 }
 
 // statusFromPath extracts the status from a file path.
@@ -679,33 +1232,36 @@ func (l *Local) generateID() (string, error) {
 
 	statuses := []backend.Status{
 		backend.StatusBacklog,
-		backend.StatusTodo,
-		backend.StatusInProgress,
-		backend.StatusReview,
-		backend.StatusDone,
-	}
-
-	for _, status := range statuses {
-		dirPath := filepath.Join(l.path, string(status))
-		entries, err := os.ReadDir(dirPath)
-		if os.IsNotExist(err) {
-			continue
-		}
-		if err != nil {
-			continue
-		}
+		backend.StatusTodo,
+		backend.StatusInProgress,
+		backend.StatusReview,
+		backend.StatusDone,
+	}
 
-		for _, entry := range entries {
-			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+	// Trashed tasks keep their IDs so they can be restored without a clash
+	for _, root := range []string{l.path, l.trashPath()} {
+		for _, status := range statuses {
+			dirPath := filepath.Join(root, string(status))
+			entries, err := os.ReadDir(dirPath)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
 				continue
 			}
 
-			baseName := strings.TrimSuffix(entry.Name(), ".md")
-			// Extract ID from filename (format: "001-title" or just "001")
-			parts := strings.SplitN(baseName, "-", 2)
-			if len(parts) > 0 {
-				if num, err := strconv.Atoi(parts[0]); err == nil && num > maxID {
-					maxID = num
+			for _, entry := range entries {
+				if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") || l.isIgnored(status, entry.Name()) {
+					continue
+				}
+
+				baseName := strings.TrimSuffix(entry.Name(), ".md")
+				// Extract ID from filename (format: "001-title" or just "001")
+				parts := strings.SplitN(baseName, "-", 2)
+				if len(parts) > 0 {
+					if num, err := strconv.Atoi(parts[0]); err == nil && num > maxID {
+						maxID = num
+					}
 				}
 			}
 		}
@@ -745,14 +1301,35 @@ func (l *Local) matchesFilters(task *backend.Task, filters backend.TaskFilters)
 		}
 	}
 
+	// Parent filter
+	if filters.Parent != "" && task.Parent != filters.Parent {
+		return false
+	}
+
+	// Time filters
+	if !filters.CreatedSince.IsZero() && task.Created.Before(filters.CreatedSince) {
+		return false
+	}
+	if !filters.UpdatedSince.IsZero() && task.Updated.Before(filters.UpdatedSince) {
+		return false
+	}
+
+	// Comments filter
+	if filters.HasComments != nil {
+		comments, _ := task.Meta["comments"].([]backend.Comment)
+		if (len(comments) > 0) != *filters.HasComments {
+			return false
+		}
+	}
+
 	// Labels filter (task must have all specified labels)
 	if len(filters.Labels) > 0 {
 		taskLabels := make(map[string]bool)
 		for _, label := range task.Labels {
-			taskLabels[label] = true
+			taskLabels[l.normalizeLabel(label)] = true
 		}
 		for _, required := range filters.Labels {
-			if !taskLabels[required] {
+			if !taskLabels[l.normalizeLabel(required)] {
 				return false
 			}
 		}
@@ -782,8 +1359,39 @@ func priorityOrder(p backend.Priority) int {
 // Claim claims a task for the current agent.
 // Implements the backend.Claimer interface.
 func (l *Local) Claim(id string, agentID string) (*backend.ClaimResult, error) {
+	claim, _, err := l.claim(id, agentID, claimOptions{})
+	return claim, err
+}
+
+// ClaimRestartClock claims a task like Claim, but resets its started_at to
+// now even if it was started by an earlier claim.
+// Implements the backend.ClockRestarter interface.
+func (l *Local) ClaimRestartClock(id string, agentID string) (*backend.ClaimResult, error) {
+	claim, _, err := l.claim(id, agentID, claimOptions{restartClock: true})
+	return claim, err
+}
+
+// Steal claims a task like Claim, but takes it over from another agent
+// holding the claim: in file mode its lock is overwritten even if it hasn't
+// expired, and in git mode its agent label is replaced.
+// Implements the backend.Stealer interface.
+func (l *Local) Steal(id string, agentID string) (*backend.ClaimResult, string, error) {
+	return l.claim(id, agentID, claimOptions{steal: true})
+}
+
+// claimOptions change how a task is claimed.
+type claimOptions struct {
+	// restartClock resets started_at even if an earlier claim set it.
+	restartClock bool
+
+	// steal takes the task over from another agent holding the claim.
+	steal bool
+}
+
+// claim claims a task and returns the agent it was stolen from, if any.
+func (l *Local) claim(id string, agentID string, opts claimOptions) (*backend.ClaimResult, string, error) {
 	if !l.connected {
-		return nil, errors.New("not connected")
+		return nil, "", errors.New("not connected")
 	}
 
 	// Use the provided agentID, or fall back to the configured one
@@ -796,29 +1404,70 @@ func (l *Local) Claim(id string, agentID string) (*backend.ClaimResult, error) {
 
 	// Git mode: pull → check → claim → commit → push
 	if l.lockMode == LockModeGit {
-		return l.claimWithGit(id, agentID)
+		return l.claimWithGit(id, agentID, opts)
 	}
 
 	// File mode: use file-based locking
-	return l.claimWithFileLock(id, agentID)
+	return l.claimWithFileLock(id, agentID, opts)
 }
 
 // claimWithGit implements git-based claim coordination.
 // Flow: pull latest → check agent labels → make changes → commit → push
 // Push failures indicate another agent claimed the task first (exit code 2).
-func (l *Local) claimWithGit(id string, agentID string) (*backend.ClaimResult, error) {
+func (l *Local) claimWithGit(id string, agentID string, opts claimOptions) (*backend.ClaimResult, string, error) {
 	// Pull latest changes from remote
 	if err := l.gitPull(); err != nil {
-		return nil, fmt.Errorf("failed to pull: %w", err)
+		return nil, "", fmt.Errorf("failed to pull: %w", err)
 	}
 
-	// Find the task (re-read after pull to get latest state)
+	// Remember where we started so a rejected claim can be undone
+	base, _ := l.gitHead()
+
+	claim, claimedBy, err := l.claimInWorkingTree(id, agentID, opts)
+	if err != nil || claim.AlreadyOwned {
+		return claim, claimedBy, err
+	}
+
+	// Commit the changes
+	if err := l.gitCommit("claim", id); err != nil {
+		return nil, "", fmt.Errorf("failed to commit: %w", err)
+	}
+
+	// Push to remote - this is the coordination point
+	// If push fails with non-fast-forward, another agent claimed first
+	if err := l.gitPush(); err != nil {
+		// Check if it's a push conflict (another agent beat us)
+		if _, isConflict := err.(*GitPushConflictError); isConflict {
+			slog.Info("claim push rejected by remote", "task", id, "agent", agentID, "reset_to", base)
+			// Drop the rejected claim commit so the next pull, such as
+			// "next --claim" trying another candidate, starts clean
+			if base != "" {
+				l.gitResetHard(base)
+			}
+			return nil, "", &ClaimConflictError{
+				TaskID:       id,
+				ClaimedBy:    "another agent (push conflict)",
+				CurrentAgent: agentID,
+			}
+		}
+		return nil, "", fmt.Errorf("failed to push: %w", err)
+	}
+
+	return claim, claimedBy, nil
+}
+
+// claimInWorkingTree claims a task by editing its file, without committing,
+// for git lock mode. The task is re-read so that a preceding pull is taken
+// into account; agent labels are what mark it as claimed. It returns the
+// agent the task was stolen from, if any.
+func (l *Local) claimInWorkingTree(id string, agentID string, opts claimOptions) (*backend.ClaimResult, string, error) {
 	task, err := l.findTask(id)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	// Check if task is already claimed by checking agent labels
+	var claimedBy string
 	existingAgentLabels := l.findAgentLabels(task.Labels)
 	if len(existingAgentLabels) > 0 {
 		// Extract agent ID from label (format: "agent:agent-id")
@@ -827,14 +1476,19 @@ func (l *Local) claimWithGit(id string, agentID string) (*backend.ClaimResult, e
 			return &backend.ClaimResult{
 				Task:         task,
 				AlreadyOwned: true,
-			}, nil
+			}, "", nil
 		}
-		// Claimed by another agent
-		return nil, &ClaimConflictError{
-			TaskID:       id,
-			ClaimedBy:    claimedByAgent,
-			CurrentAgent: agentID,
+		if !opts.steal {
+			// Claimed by another agent
+			return nil, "", &ClaimConflictError{
+				TaskID:       id,
+				ClaimedBy:    claimedByAgent,
+				CurrentAgent: agentID,
+			}
 		}
+		// The other agent's label is replaced below
+		slog.Warn("stealing claim", "task", id, "holder", claimedByAgent, "agent", agentID)
+		claimedBy = claimedByAgent
 	}
 
 	// Clean up any stale file locks (git mode doesn't use them)
@@ -849,83 +1503,93 @@ func (l *Local) claimWithGit(id string, agentID string) (*backend.ClaimResult, e
 	}
 
 	// Apply label changes
-	task, err = l.updateInternal(id, changes)
-	if err != nil {
-		return nil, fmt.Errorf("failed to update task: %w", err)
+	if _, err := l.updateInternal(id, changes); err != nil {
+		return nil, "", fmt.Errorf("failed to update task: %w", err)
 	}
 
 	// Move to in-progress
-	task, err = l.moveInternal(id, backend.StatusInProgress)
-	if err != nil {
-		return nil, fmt.Errorf("failed to move task: %w", err)
-	}
-
-	// Commit the changes
-	if err := l.gitCommit("claim", id); err != nil {
-		return nil, fmt.Errorf("failed to commit: %w", err)
+	if _, err := l.moveInternal(id, backend.StatusInProgress); err != nil {
+		return nil, "", fmt.Errorf("failed to move task: %w", err)
 	}
 
-	// Push to remote - this is the coordination point
-	// If push fails with non-fast-forward, another agent claimed first
-	if err := l.gitPush(); err != nil {
-		// Check if it's a push conflict (another agent beat us)
-		if _, isConflict := err.(*GitPushConflictError); isConflict {
-			return nil, &ClaimConflictError{
-				TaskID:       id,
-				ClaimedBy:    "another agent (push conflict)",
-				CurrentAgent: agentID,
-			}
-		}
-		return nil, fmt.Errorf("failed to push: %w", err)
+	task, err = l.startClock(id, opts.restartClock)
+	if err != nil {
+		return nil, "", err
 	}
 
 	return &backend.ClaimResult{
 		Task:         task,
 		AlreadyOwned: false,
-	}, nil
+	}, claimedBy, nil
 }
 
 // claimWithFileLock implements file-based claim coordination.
-func (l *Local) claimWithFileLock(id string, agentID string) (*backend.ClaimResult, error) {
+func (l *Local) claimWithFileLock(id string, agentID string, opts claimOptions) (*backend.ClaimResult, string, error) {
+	claim, claimedBy, err := l.claimFileLocked(id, agentID, opts)
+	if err != nil || claim.AlreadyOwned {
+		return claim, claimedBy, err
+	}
+
+	// Git commit if enabled
+	if err := l.gitCommit("claim", id); err != nil {
+		return nil, "", fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return claim, claimedBy, nil
+}
+
+// claimFileLocked claims a task under a lock file, without committing. It
+// returns the agent the task was stolen from, if any.
+func (l *Local) claimFileLocked(id string, agentID string, opts claimOptions) (*backend.ClaimResult, string, error) {
 	// Find the task
 	task, err := l.findTask(id)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	// Atomically acquire the lock; an active lock held by anyone is returned instead
+	now := time.Now().UTC()
+	lock := &LockFile{
+		Agent:     agentID,
+		ClaimedAt: now,
+		ExpiresAt: now.Add(DefaultLockTTL),
 	}
 
-	// Check for existing lock
-	existingLock, err := l.readLock(id)
+	existingLock, err := l.acquireLock(id, lock)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read lock: %w", err)
+		return nil, "", fmt.Errorf("failed to acquire lock: %w", err)
 	}
 
 	// Check if task is already claimed
-	if existingLock != nil && existingLock.isActive() {
+	var claimedBy string
+	if existingLock != nil {
 		// Check if claimed by the same agent
 		if existingLock.Agent == agentID {
 			return &backend.ClaimResult{
 				Task:         task,
 				AlreadyOwned: true,
-			}, nil
+			}, "", nil
 		}
-		// Claimed by another agent
-		return nil, &ClaimConflictError{
-			TaskID:       id,
-			ClaimedBy:    existingLock.Agent,
-			CurrentAgent: agentID,
+		if !opts.steal {
+			// Claimed by another agent
+			return nil, "", &ClaimConflictError{
+				TaskID:       id,
+				ClaimedBy:    existingLock.Agent,
+				CurrentAgent: agentID,
+			}
+		}
+		// Overwrite the other agent's lock even though it hasn't expired
+		slog.Warn("stealing active lock", "task", id, "holder", existingLock.Agent, "agent", agentID, "expires_at", existingLock.ExpiresAt)
+		if err := l.writeLock(id, lock); err != nil {
+			return nil, "", err
+		}
+		claimedBy = existingLock.Agent
+	} else if opts.steal {
+		// The lock expired, but the label left behind still names the holder
+		if holder := l.agentFromLabels(l.findAgentLabels(task.Labels)); holder != "" && holder != agentID {
+			slog.Warn("stealing claim", "task", id, "holder", holder, "agent", agentID)
+			claimedBy = holder
 		}
-	}
-
-	// Create new lock
-	now := time.Now().UTC()
-	lock := &LockFile{
-		Agent:     agentID,
-		ClaimedAt: now,
-		ExpiresAt: now.Add(DefaultLockTTL),
-	}
-
-	if err := l.writeLock(id, lock); err != nil {
-		return nil, fmt.Errorf("failed to write lock: %w", err)
 	}
 
 	// Remove any existing agent labels, add the new one, and set assignee to agent ID
@@ -941,55 +1605,96 @@ func (l *Local) claimWithFileLock(id string, agentID string) (*backend.ClaimResu
 	if err != nil {
 		// Try to remove the lock if we fail to update the task
 		l.removeLock(id)
-		return nil, fmt.Errorf("failed to update task: %w", err)
+		return nil, "", fmt.Errorf("failed to update task: %w", err)
 	}
 
-	task, err = l.moveInternal(id, backend.StatusInProgress)
-	if err != nil {
+	if _, err := l.moveInternal(id, backend.StatusInProgress); err != nil {
 		// Try to remove the lock if we fail to move the task
 		l.removeLock(id)
-		return nil, fmt.Errorf("failed to move task: %w", err)
+		return nil, "", fmt.Errorf("failed to move task: %w", err)
 	}
 
-	// Git commit if enabled
-	if err := l.gitCommit("claim", id); err != nil {
-		return nil, fmt.Errorf("failed to commit: %w", err)
+	task, err = l.startClock(id, opts.restartClock)
+	if err != nil {
+		l.removeLock(id)
+		return nil, "", err
 	}
 
 	return &backend.ClaimResult{
 		Task:         task,
 		AlreadyOwned: false,
-	}, nil
+		ExpiresAt:    lock.ExpiresAt,
+	}, claimedBy, nil
+}
+
+// startClock records started_at on a freshly claimed task. A task that was
+// claimed and released before keeps its original start time unless restart
+// is set.
+func (l *Local) startClock(id string, restart bool) (*backend.Task, error) {
+	filePath, err := l.findTaskFile(id)
+	if err != nil {
+		return nil, err
+	}
+	task, err := l.readTaskFile(filePath, l.statusFromPath(filePath))
+	if err != nil {
+		return nil, err
+	}
+
+	if task.StartedAt == nil || restart {
+		started := time.Now().UTC()
+		task.StartedAt = &started
+		if err := l.writeTask(task); err != nil {
+			return nil, fmt.Errorf("failed to write task: %w", err)
+		}
+		backend.SetDuration(task, started)
+	}
+
+	return task, nil
 }
 
 // Release releases a claimed task back to todo status.
 // Implements the backend.Claimer interface.
 func (l *Local) Release(id string) error {
+	_, err := l.release(id, false)
+	return err
+}
+
+// ForceRelease releases a claimed task back to todo status whichever agent
+// holds the claim, removing its lock file and agent label.
+// Implements the backend.ForceReleaser interface.
+func (l *Local) ForceRelease(id string) (string, error) {
+	return l.release(id, true)
+}
+
+// release releases a task, skipping the ownership check if force is set,
+// and returns the agent that held the claim.
+func (l *Local) release(id string, force bool) (string, error) {
 	if !l.connected {
-		return errors.New("not connected")
+		return "", errors.New("not connected")
 	}
 
-	// Git mode: pull → release → commit → push
+	// Git mode: pull → release → commit → push. It doesn't check
+	// ownership, so force makes no difference there.
 	if l.lockMode == LockModeGit {
 		return l.releaseWithGit(id)
 	}
 
 	// File mode: use file-based locking
-	return l.releaseWithFileLock(id)
+	return l.releaseWithFileLock(id, force)
 }
 
 // releaseWithGit implements git-based release coordination.
 // Flow: pull latest → make changes → commit → push
-func (l *Local) releaseWithGit(id string) error {
+func (l *Local) releaseWithGit(id string) (string, error) {
 	// Pull latest changes from remote
 	if err := l.gitPull(); err != nil {
-		return fmt.Errorf("failed to pull: %w", err)
+		return "", fmt.Errorf("failed to pull: %w", err)
 	}
 
 	// Find the task (re-read after pull to get latest state)
 	task, err := l.findTask(id)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// Clean up any stale file locks (git mode doesn't use them)
@@ -1002,45 +1707,46 @@ func (l *Local) releaseWithGit(id string) error {
 			RemoveLabels: agentLabels,
 		})
 		if err != nil {
-			return fmt.Errorf("failed to remove agent labels: %w", err)
+			return "", fmt.Errorf("failed to remove agent labels: %w", err)
 		}
 	}
 
 	// Unassign the task (uses updateInternal internally)
 	_, err = l.Unassign(id)
 	if err != nil {
-		return fmt.Errorf("failed to unassign task: %w", err)
+		return "", fmt.Errorf("failed to unassign task: %w", err)
 	}
 
 	// Move to todo
 	_, err = l.moveInternal(id, backend.StatusTodo)
 	if err != nil {
-		return fmt.Errorf("failed to move task to todo: %w", err)
+		return "", fmt.Errorf("failed to move task to todo: %w", err)
 	}
 
 	// Commit the changes
 	if err := l.gitCommit("release", id); err != nil {
-		return fmt.Errorf("failed to commit: %w", err)
+		return "", fmt.Errorf("failed to commit: %w", err)
 	}
 
 	// Push to remote
 	if err := l.gitPush(); err != nil {
 		// For release, a push conflict is still an error but not the same as claim conflict
 		if _, isConflict := err.(*GitPushConflictError); isConflict {
-			return fmt.Errorf("failed to push release: remote has conflicting changes")
+			return "", fmt.Errorf("failed to push release: remote has conflicting changes")
 		}
-		return fmt.Errorf("failed to push: %w", err)
+		return "", fmt.Errorf("failed to push: %w", err)
 	}
 
-	return nil
+	return l.agentFromLabels(agentLabels), nil
 }
 
 // releaseWithFileLock implements file-based release coordination.
-func (l *Local) releaseWithFileLock(id string) error {
+// With force, a task claimed by a different agent is released too.
+func (l *Local) releaseWithFileLock(id string, force bool) (string, error) {
 	// Find the task
 	task, err := l.findTask(id)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// Check if task is claimed (either by lock file or agent label)
@@ -1049,27 +1755,24 @@ func (l *Local) releaseWithFileLock(id string) error {
 
 	// Task is not claimed if there's no active lock and no agent label
 	if (lock == nil || !lock.isActive()) && len(agentLabels) == 0 {
-		return &ReleaseConflictError{
+		return "", &ReleaseConflictError{
 			TaskID:       id,
 			CurrentAgent: l.agentID,
 			NotClaimed:   true,
 		}
 	}
 
-	// Check if task is claimed by a different agent
+	// Check if task is claimed by a different agent. An agent label still
+	// counts once its lock has expired.
 	var claimedBy string
 	if lock != nil && lock.isActive() {
 		claimedBy = lock.Agent
-	} else if len(agentLabels) > 0 {
-		// Extract agent ID from label (format: "agent:id")
-		parts := strings.SplitN(agentLabels[0], ":", 2)
-		if len(parts) == 2 {
-			claimedBy = parts[1]
-		}
+	} else {
+		claimedBy = l.agentFromLabels(agentLabels)
 	}
 
-	if claimedBy != "" && claimedBy != l.agentID {
-		return &ReleaseConflictError{
+	if claimedBy != "" && claimedBy != l.agentID && !force {
+		return "", &ReleaseConflictError{
 			TaskID:       id,
 			CurrentAgent: l.agentID,
 			ClaimedBy:    claimedBy,
@@ -1078,7 +1781,7 @@ func (l *Local) releaseWithFileLock(id string) error {
 
 	// Remove the lock file
 	if err := l.removeLock(id); err != nil {
-		return fmt.Errorf("failed to remove lock: %w", err)
+		return "", fmt.Errorf("failed to remove lock: %w", err)
 	}
 
 	// Remove agent labels
@@ -1087,28 +1790,28 @@ func (l *Local) releaseWithFileLock(id string) error {
 			RemoveLabels: agentLabels,
 		})
 		if err != nil {
-			return fmt.Errorf("failed to remove agent labels: %w", err)
+			return "", fmt.Errorf("failed to remove agent labels: %w", err)
 		}
 	}
 
 	// Unassign the task (uses updateInternal internally)
 	_, err = l.Unassign(id)
 	if err != nil {
-		return fmt.Errorf("failed to unassign task: %w", err)
+		return "", fmt.Errorf("failed to unassign task: %w", err)
 	}
 
 	// Move to todo
 	_, err = l.moveInternal(id, backend.StatusTodo)
 	if err != nil {
-		return fmt.Errorf("failed to move task to todo: %w", err)
+		return "", fmt.Errorf("failed to move task to todo: %w", err)
 	}
 
 	// Git commit if enabled
 	if err := l.gitCommit("release", id); err != nil {
-		return fmt.Errorf("failed to commit: %w", err)
+		return "", fmt.Errorf("failed to commit: %w", err)
 	}
 
-	return nil
+	return claimedBy, nil
 }
 
 // Reorder changes the sort position of a task within its status and priority group.
@@ -1271,9 +1974,31 @@ func calculateSortOrder(targetID string, sortedTasks []backend.Task, position ba
 		return calculateAfterOrder(others, position.AfterID)
 	}
 
+	if position.Position != 0 {
+		return calculatePositionOrder(others, position.Position)
+	}
+
 	return 0, fmt.Errorf("no position specified")
 }
 
+// calculatePositionOrder computes a sort_order that places the task at the
+// 1-based position n among the others.
+func calculatePositionOrder(others []backend.Task, n int) (float64, error) {
+	if n < 1 || n > len(others)+1 {
+		return 0, fmt.Errorf("position %d is out of range: the task's status and priority group has %d tasks (valid: 1-%d)", n, len(others)+1, len(others)+1)
+	}
+	switch {
+	case len(others) == 0:
+		return 1024, nil
+	case n == 1:
+		return others[0].SortOrder - 1024, nil
+	case n == len(others)+1:
+		return others[len(others)-1].SortOrder + 1024, nil
+	}
+	// Midpoint between the tasks now at n-1 and n
+	return (others[n-2].SortOrder + others[n-1].SortOrder) / 2, nil
+}
+
 // calculateBeforeOrder computes a sort_order that places the task before the reference task.
 func calculateBeforeOrder(others []backend.Task, beforeID string) (float64, error) {
 	for i, t := range others {
@@ -1478,9 +2203,51 @@ func (l *Local) ListRelations(id string) ([]backend.Relation, error) {
 		})
 	}
 
+	// Parent and children
+	if task.Parent != "" {
+		if parent, err := l.findTask(task.Parent); err == nil {
+			relations = append(relations, backend.Relation{
+				Type:       backend.RelationParent,
+				TaskID:     parent.ID,
+				TaskTitle:  parent.Title,
+				TaskStatus: parent.Status,
+			})
+		}
+	}
+	children, err := l.List(backend.TaskFilters{Parent: task.ID, IncludeDone: true})
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range children.Tasks {
+		relations = append(relations, backend.Relation{
+			Type:       backend.RelationChild,
+			TaskID:     child.ID,
+			TaskTitle:  child.Title,
+			TaskStatus: child.Status,
+		})
+	}
+
 	return relations, nil
 }
 
+// checkParent validates making parentID the parent of task id: the parent
+// must exist and must not be the task itself or one of its descendants.
+func (l *Local) checkParent(id, parentID string) error {
+	seen := make(map[string]bool)
+	for ancestor := parentID; ancestor != "" && !seen[ancestor]; {
+		seen[ancestor] = true
+		if ancestor == id {
+			return fmt.Errorf("task %s cannot be a sub-task of itself or its own sub-tasks", id)
+		}
+		parent, err := l.findTask(ancestor)
+		if err != nil {
+			return fmt.Errorf("parent task not found: %s", ancestor)
+		}
+		ancestor = parent.Parent
+	}
+	return nil
+}
+
 // metaStringSlice extracts a []string from a task's Meta map.
 func metaStringSlice(meta map[string]any, key string) []string {
 	if meta == nil {
@@ -1525,6 +2292,14 @@ func (l *Local) findAgentLabels(labels []string) []string {
 	return agentLabels
 }
 
+// agentFromLabels returns the agent ID of the first agent label, or "".
+func (l *Local) agentFromLabels(agentLabels []string) string {
+	if len(agentLabels) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(agentLabels[0], l.agentLabelPrefix+":")
+}
+
 // ClaimConflictError represents an error when a task is already claimed by another agent.
 type ClaimConflictError struct {
 	TaskID       string
@@ -1553,37 +2328,47 @@ func (e *ReleaseConflictError) Error() string {
 }
 
 // gitCommit creates a git commit with the given message if git sync is enabled.
-// The action parameter is one of: add, edit, move, claim, release, comment.
-// The taskID is the ID of the task being modified.
-// The agentID is included in the commit message for claim/release operations.
+// The action parameter is one of: add, add+claim, edit, move, claim, release, reopen, comment, doctor.
+// The taskID is the ID of the task being modified, or a summary for doctor.
+// The agentID is included in the commit message for add+claim/claim/release/reopen operations.
+// A configured CommitMessage takes the place of the task ID.
+// The task files the operation changed are saved as an undo entry first,
+// whether or not git sync is enabled.
 func (l *Local) gitCommit(action, taskID string) error {
+	l.saveUndo(action, taskID)
 	if !l.gitSync {
 		return nil
 	}
+	if err := l.checkBranch(); err != nil {
+		return err
+	}
 
 	// Build commit message
+	subject := taskID
+	if l.commitMessage != "" {
+		subject = l.commitMessage
+	}
 	var message string
-	if action == "claim" || action == "release" {
-		message = fmt.Sprintf("%s: %s [agent:%s]", action, taskID, l.agentID)
+	if action == "add+claim" || action == "claim" || action == "release" || action == "reopen" {
+		message = fmt.Sprintf("%s: %s [agent:%s]", action, subject, l.agentID)
 	} else {
-		message = fmt.Sprintf("%s: %s", action, taskID)
+		message = fmt.Sprintf("%s: %s", action, subject)
 	}
 
-	// Get the parent directory of the .backlog folder to run git commands
-	// l.path is absolute, so we get its parent for the git repo root
-	gitDir := filepath.Dir(l.path)
-
-	// Stage all changes in the .backlog directory
-	addCmd := exec.Command("git", "add", l.path)
-	addCmd.Dir = gitDir
-	if output, err := addCmd.CombinedOutput(); err != nil {
+	// Stage all changes in the .backlog directory except the local index,
+	// the undo log and any credentials file
+	if output, err := l.runGit("add", "add", "--", l.path, l.indexPathspec(), l.undoPathspec(), l.credentialsPathspec()); err != nil {
+		if isGitTimeout(err) {
+			return err
+		}
 		return fmt.Errorf("git add failed: %w\n%s", err, output)
 	}
 
 	// Commit the changes
-	commitCmd := exec.Command("git", "commit", "-m", message)
-	commitCmd.Dir = gitDir
-	if output, err := commitCmd.CombinedOutput(); err != nil {
+	if output, err := l.runGit("commit", "commit", "-m", message); err != nil {
+		if isGitTimeout(err) {
+			return err
+		}
 		// If nothing to commit, that's OK
 		if strings.Contains(string(output), "nothing to commit") {
 			return nil
@@ -1594,33 +2379,42 @@ func (l *Local) gitCommit(action, taskID string) error {
 	return nil
 }
 
+// credentialsPathspec returns a git pathspec excluding credentials.yaml
+// files anywhere in the backlog, so that a token saved next to the tasks is
+// never committed.
+func (l *Local) credentialsPathspec() string {
+	return ":(exclude,glob)" + filepath.ToSlash(filepath.Join(l.path, "**", "credentials.yaml"))
+}
+
 // gitPull pulls changes from the remote repository.
-// Returns an error if pull fails or has conflicts.
+// Returns an error if pull fails or has conflicts, and a GitTimeoutError if
+// it runs past git_timeout. Transient network failures are retried.
 // If there's no remote configured or no tracking branch, it's a no-op.
 func (l *Local) gitPull() error {
-	gitDir := filepath.Dir(l.path)
-
 	// Check if there's a remote configured first
-	remoteCmd := exec.Command("git", "remote")
-	remoteCmd.Dir = gitDir
-	remoteOutput, err := remoteCmd.Output()
-	if err != nil || strings.TrimSpace(string(remoteOutput)) == "" {
+	if !l.hasGitRemote() {
 		// No remote configured, nothing to pull
 		return nil
 	}
+	if err := l.checkBranch(); err != nil {
+		return err
+	}
 
 	// Use git pull with -c option to set rebase mode, handling divergent branches
-	pullCmd := exec.Command("git", "-c", "pull.rebase=true", "pull")
-	pullCmd.Dir = gitDir
-	pullOutput, err := pullCmd.CombinedOutput()
+	pullOutput, err := retryGit("pull", func() ([]byte, error) {
+		return l.runGit("pull", "-c", "pull.rebase=true", "pull")
+	})
 	if err != nil {
+		if isGitTimeout(err) {
+			// The killed pull may have stopped mid-rebase
+			l.runGit("rebase", "rebase", "--abort")
+			return err
+		}
 		outputStr := string(pullOutput)
 		// Check for conflicts
 		if strings.Contains(outputStr, "CONFLICT") || strings.Contains(outputStr, "conflict") {
 			// Abort the rebase to leave the repo in a clean state
-			abortCmd := exec.Command("git", "rebase", "--abort")
-			abortCmd.Dir = gitDir
-			abortCmd.CombinedOutput()
+			l.runGit("rebase", "rebase", "--abort")
 			return &SyncConflictError{
 				Operation: "pull",
 				Message:   outputStr,
@@ -1641,24 +2435,23 @@ func (l *Local) gitPull() error {
 }
 
 // gitPush pushes changes to the remote repository.
-// Returns a ClaimConflictError if push is rejected (for use with git-based claims).
-// If there's no remote configured, it's a no-op.
+// Returns a GitPushConflictError if push is rejected (for use with git-based
+// claims) and a GitTimeoutError if it runs past git_timeout. Transient network
+// failures are retried. If there's no remote configured, it's a no-op.
 func (l *Local) gitPush() error {
-	gitDir := filepath.Dir(l.path)
-
 	// Check if there's a remote configured first
-	remoteCmd := exec.Command("git", "remote")
-	remoteCmd.Dir = gitDir
-	remoteOutput, err := remoteCmd.Output()
-	if err != nil || strings.TrimSpace(string(remoteOutput)) == "" {
+	if !l.hasGitRemote() {
 		// No remote configured, nothing to push
 		return nil
 	}
 
-	pushCmd := exec.Command("git", "push")
-	pushCmd.Dir = gitDir
-	pushOutput, err := pushCmd.CombinedOutput()
+	pushOutput, err := retryGit("push", func() ([]byte, error) {
+		return l.runGit("push", "push")
+	})
 	if err != nil {
+		if isGitTimeout(err) {
+			return err
+		}
 		outputStr := string(pushOutput)
 		// Check for rejection (conflict)
 		if strings.Contains(outputStr, "rejected") ||
@@ -1683,6 +2476,29 @@ func (l *Local) gitPush() error {
 	return nil
 }
 
+// hasGitRemote reports whether the repository has a remote configured.
+func (l *Local) hasGitRemote() bool {
+	output, err := l.gitOutput("remote", "remote")
+	return err == nil && strings.TrimSpace(string(output)) != ""
+}
+
+// gitHead returns the commit hash of HEAD.
+func (l *Local) gitHead() (string, error) {
+	out, err := l.gitOutput("rev-parse", "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// gitResetHard resets the branch and working tree to the given commit.
+func (l *Local) gitResetHard(rev string) error {
+	if out, err := l.runGit("reset", "reset", "--hard", rev); err != nil {
+		return fmt.Errorf("git reset failed: %w\n%s", err, out)
+	}
+	return nil
+}
+
 // GitPushConflictError represents a conflict when pushing to remote.
 // This is returned when a git push is rejected due to non-fast-forward updates,
 // indicating another agent has pushed changes since we last pulled.
@@ -1705,80 +2521,158 @@ func (e *UncommittedChangesError) Error() string {
 	return fmt.Sprintf("uncommitted changes: %s", e.Message)
 }
 
+// DetachedHeadError is returned when git_sync is enabled but no branch is
+// checked out, as in many CI checkouts. Commits would succeed but could not
+// be pushed, so the mutation is refused up front.
+type DetachedHeadError struct{}
+
+func (e *DetachedHeadError) Error() string {
+	return "detached HEAD: git sync needs a branch to commit to and push; check out a branch (git switch <branch>) and try again"
+}
+
+// checkBranch returns a DetachedHeadError if HEAD does not point at a
+// branch. Outside a git repository there is nothing to check.
+func (l *Local) checkBranch() error {
+	_, err := l.runGit("symbolic-ref", "symbolic-ref", "-q", "HEAD")
+	if err == nil || isGitTimeout(err) {
+		return err
+	}
+	// symbolic-ref -q exits 1 when HEAD is detached and 128 when this
+	// isn't a repository at all
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return &DetachedHeadError{}
+	}
+	return nil
+}
+
 // isRemoteAhead checks if the remote repository has commits that local doesn't have.
 // This is used to detect when another agent has pushed changes.
 func (l *Local) isRemoteAhead() (bool, error) {
-	gitDir := filepath.Dir(l.path)
-
 	// Check if there's a remote configured
-	remoteCmd := exec.Command("git", "remote")
-	remoteCmd.Dir = gitDir
-	remoteOutput, err := remoteCmd.Output()
-	if err != nil || strings.TrimSpace(string(remoteOutput)) == "" {
+	if !l.hasGitRemote() {
 		// No remote configured
 		return false, nil
 	}
 
-	// Fetch the latest from remote without merging
-	fetchCmd := exec.Command("git", "fetch")
-	fetchCmd.Dir = gitDir
-	if _, err := fetchCmd.CombinedOutput(); err != nil {
-		// Fetch failed (maybe network issue), don't treat as conflict
+	_, behind, err := l.remoteDivergence()
+	if err != nil {
+		if isGitTimeout(err) {
+			return false, err
+		}
+		// Fetch failed (maybe network issue) or no upstream configured,
+		// don't treat as conflict
 		return false, nil
 	}
 
-	// Compare local HEAD with remote tracking branch
-	// Check how many commits we're behind
-	behindCmd := exec.Command("git", "rev-list", "--count", "HEAD..@{upstream}")
-	behindCmd.Dir = gitDir
-	behindOutput, err := behindCmd.Output()
-	if err != nil {
-		// No upstream configured or other issue
-		return false, nil
+	// Remote has commits we don't have
+	return behind > 0, nil
+}
+
+// remoteDivergence fetches from the remote without merging and counts the
+// commits HEAD has that its upstream branch doesn't (ahead) and the other way
+// around (behind). It fails if the fetch fails or there is no upstream.
+func (l *Local) remoteDivergence() (ahead, behind int, err error) {
+	if _, err := l.git("fetch", "--quiet"); err != nil {
+		if isGitTimeout(err) {
+			return 0, 0, err
+		}
+		return 0, 0, fmt.Errorf("git fetch failed: %w", err)
 	}
 
-	behind := strings.TrimSpace(string(behindOutput))
-	if behind == "" || behind == "0" {
-		return false, nil
+	counts, err := l.git("rev-list", "--left-right", "--count", "HEAD...@{upstream}")
+	if err != nil {
+		if isGitTimeout(err) {
+			return 0, 0, err
+		}
+		return 0, 0, fmt.Errorf("failed to compare with upstream: %w", err)
 	}
 
-	// Remote has commits we don't have
-	return true, nil
+	fields := strings.Fields(counts)
+	if len(fields) == 2 {
+		ahead, err = strconv.Atoi(fields[0])
+		if err == nil {
+			behind, err = strconv.Atoi(fields[1])
+		}
+	}
+	if len(fields) != 2 || err != nil {
+		return 0, 0, fmt.Errorf("unexpected git rev-list output: %q", counts)
+	}
+	return ahead, behind, nil
 }
 
 // hasUncommittedChanges checks if there are uncommitted changes in the git repository.
 // Returns true if there are staged or unstaged changes.
 func (l *Local) hasUncommittedChanges() (bool, error) {
-	gitDir := filepath.Dir(l.path)
-
 	// Check if we're in a git repository
-	gitCmd := exec.Command("git", "rev-parse", "--git-dir")
-	gitCmd.Dir = gitDir
-	if err := gitCmd.Run(); err != nil {
+	if _, err := l.runGit("rev-parse", "rev-parse", "--git-dir"); err != nil {
+		if isGitTimeout(err) {
+			return false, err
+		}
 		// Not a git repository
 		return false, nil
 	}
 
-	// Check for uncommitted changes using git status
-	statusCmd := exec.Command("git", "status", "--porcelain")
-	statusCmd.Dir = gitDir
-	output, err := statusCmd.Output()
+	files, err := l.uncommittedFiles(":/")
+	if err != nil {
+		return false, err
+	}
+	return len(files) > 0, nil
+}
+
+// uncommittedFiles lists the paths under scope, relative to the repository
+// root, that git status reports as changed or untracked.
+func (l *Local) uncommittedFiles(scope string) ([]string, error) {
+	// Ignore the index, the undo log, and credentials, which are local to
+	// this checkout and never committed
+	output, err := l.gitOutput("status", "status", "--porcelain", "-z", "--untracked-files=all",
+		"--", scope, l.indexPathspec(), l.undoPathspec(), l.credentialsPathspec())
 	if err != nil {
-		return false, fmt.Errorf("failed to check git status: %w", err)
+		if isGitTimeout(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to check git status: %w", err)
 	}
 
-	return len(strings.TrimSpace(string(output))) > 0, nil
+	// Each entry is "XY path"; renames and copies are followed by the
+	// original path as an entry of its own
+	var files []string
+	entries := strings.Split(string(output), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		files = append(files, entry[3:])
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++
+		}
+	}
+	return files, nil
 }
 
 // Sync synchronizes the local backlog with a remote git repository.
-// Implements the backend.Syncer interface.
+// Conflicting task changes are merged where that needs no choice; see
+// SyncResolving. Implements the backend.Syncer interface.
 func (l *Local) Sync(force bool) (*backend.SyncResult, error) {
+	return l.SyncResolving(force, nil)
+}
+
+// SyncResolving synchronizes the local backlog with a remote git
+// repository. If the pull conflicts, it is aborted and the upstream branch
+// is merged instead, with conflicting task files merged field by field and
+// fields both sides changed passed to choose. Implements the
+// backend.ConflictResolver interface.
+func (l *Local) SyncResolving(force bool, choose backend.ConflictChooser) (*backend.SyncResult, error) {
 	if !l.connected {
 		return nil, errors.New("not connected")
 	}
+	// Task files rewritten while resolving conflicts are not undoable
+	defer l.discardUndo()
 
-	// Get the parent directory of the .backlog folder to run git commands
-	gitDir := filepath.Dir(l.path)
+	if err := l.checkBranch(); err != nil {
+		return nil, err
+	}
 
 	result := &backend.SyncResult{}
 
@@ -1787,20 +2681,27 @@ func (l *Local) Sync(force bool) (*backend.SyncResult, error) {
 	if force {
 		pullArgs = append(pullArgs, "--rebase")
 	}
-	pullCmd := exec.Command("git", pullArgs...)
-	pullCmd.Dir = gitDir
-	pullOutput, err := pullCmd.CombinedOutput()
+	pullOutput, err := retryGit("pull", func() ([]byte, error) {
+		return l.runGit("pull", pullArgs...)
+	})
 	if err != nil {
-		// Check for conflicts
+		if isGitTimeout(err) {
+			// The killed pull may have stopped mid-rebase
+			l.runGit("rebase", "rebase", "--abort")
+			return nil, err
+		}
 		outputStr := string(pullOutput)
 		if strings.Contains(outputStr, "CONFLICT") || strings.Contains(outputStr, "conflict") {
-			return nil, &SyncConflictError{
-				Operation: "pull",
-				Message:   outputStr,
+			// Redo the pull as a merge whose conflicts are resolved task by task
+			l.abortPull()
+			resolved, err := l.mergeUpstreamResolving(choose)
+			if err != nil {
+				return nil, err
 			}
-		}
-		// Check if it's just "already up to date"
-		if !strings.Contains(outputStr, "Already up to date") &&
+			result.Resolved = resolved
+			result.Conflicts = len(resolved)
+			result.Updated = 1
+		} else if !strings.Contains(outputStr, "Already up to date") &&
 			!strings.Contains(outputStr, "Already up-to-date") {
 			return nil, fmt.Errorf("git pull failed: %w\n%s", err, outputStr)
 		}
@@ -1819,10 +2720,13 @@ func (l *Local) Sync(force bool) (*backend.SyncResult, error) {
 	if force {
 		pushArgs = append(pushArgs, "--force")
 	}
-	pushCmd := exec.Command("git", pushArgs...)
-	pushCmd.Dir = gitDir
-	pushOutput, err := pushCmd.CombinedOutput()
+	pushOutput, err := retryGit("push", func() ([]byte, error) {
+		return l.runGit("push", pushArgs...)
+	})
 	if err != nil {
+		if isGitTimeout(err) {
+			return nil, err
+		}
 		outputStr := string(pushOutput)
 		// Check for conflicts or rejection
 		if strings.Contains(outputStr, "rejected") ||
@@ -1853,6 +2757,10 @@ func (l *Local) Sync(force bool) (*backend.SyncResult, error) {
 type SyncConflictError struct {
 	Operation string
 	Message   string
+
+	// Conflicts lists the task fields a sync couldn't merge, if it got as
+	// far as trying.
+	Conflicts []backend.FieldConflict
 }
 
 func (e *SyncConflictError) Error() string {
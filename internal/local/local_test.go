@@ -1,8 +1,10 @@
 package local
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -184,6 +186,36 @@ func TestGetNotFound(t *testing.T) {
 	if err == nil {
 		t.Fatal("Get() for nonexistent task should return error")
 	}
+	var notFound *backend.NotFoundError
+	if !errors.As(err, &notFound) || notFound.ID != "nonexistent" {
+		t.Errorf("Get() error = %#v, want *backend.NotFoundError for nonexistent", err)
+	}
+}
+
+func TestGetRaw(t *testing.T) {
+	l, dir := setupBacklog(t)
+
+	created, err := l.Create(backend.TaskInput{Title: "Raw task"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	raw, err := l.GetRaw(created.ID)
+	if err != nil {
+		t.Fatalf("GetRaw() error = %v", err)
+	}
+
+	onDisk, err := os.ReadFile(filepath.Join(dir, "backlog", created.ID+"-raw-task.md"))
+	if err != nil {
+		t.Fatalf("failed to read task file: %v", err)
+	}
+	if string(raw) != string(onDisk) {
+		t.Errorf("GetRaw() = %q, want file contents %q", raw, onDisk)
+	}
+
+	if _, err := l.GetRaw("nonexistent"); err == nil {
+		t.Error("GetRaw() for nonexistent task should return error")
+	}
 }
 
 func TestList(t *testing.T) {
@@ -230,6 +262,130 @@ func TestListWithFilters(t *testing.T) {
 	}
 }
 
+func TestListWithTimeFilters(t *testing.T) {
+	l, _ := setupBacklog(t)
+
+	old, _ := l.Create(backend.TaskInput{Title: "Old task"})
+	recent, _ := l.Create(backend.TaskInput{Title: "Recent task"})
+
+	// Backdate the first task: created two days ago, updated yesterday
+	now := time.Now().UTC()
+	old.Created = now.Add(-48 * time.Hour)
+	old.Updated = now.Add(-24 * time.Hour)
+	if err := l.writeTask(old); err != nil {
+		t.Fatalf("writeTask() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		filters backend.TaskFilters
+		want    []string
+	}{
+		{"created since", backend.TaskFilters{CreatedSince: now.Add(-time.Hour)}, []string{recent.ID}},
+		{"updated since", backend.TaskFilters{UpdatedSince: now.Add(-36 * time.Hour)}, []string{old.ID, recent.ID}},
+		{"both", backend.TaskFilters{CreatedSince: now.Add(-72 * time.Hour), UpdatedSince: now.Add(-time.Hour)}, []string{recent.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := l.List(tt.filters)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			var got []string
+			for _, task := range list.Tasks {
+				got = append(got, task.ID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("List() IDs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListWithCommentsFilter(t *testing.T) {
+	l, _ := setupBacklog(t)
+
+	discussed, _ := l.Create(backend.TaskInput{Title: "Discussed"})
+	quiet, _ := l.Create(backend.TaskInput{Title: "Quiet"})
+	if _, err := l.AddComment(discussed.ID, "Looks good"); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+
+	has, none := true, false
+	tests := []struct {
+		name    string
+		filters backend.TaskFilters
+		want    []string
+	}{
+		{"has comments", backend.TaskFilters{HasComments: &has}, []string{discussed.ID}},
+		{"no comments", backend.TaskFilters{HasComments: &none}, []string{quiet.ID}},
+		{"either", backend.TaskFilters{}, []string{discussed.ID, quiet.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := l.List(tt.filters)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			var got []string
+			for _, task := range list.Tasks {
+				got = append(got, task.ID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("List() IDs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListReportsUnparseableFiles(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+
+	_, _ = l.Create(backend.TaskInput{Title: "Good task"})
+	broken := filepath.Join(backlogDir, "backlog", "002-broken.md")
+	if err := os.WriteFile(broken, []byte("---\nid: [oops\n---\n"), 0644); err != nil {
+		t.Fatalf("failed to write broken file: %v", err)
+	}
+
+	list, err := l.List(backend.TaskFilters{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if list.Count != 1 {
+		t.Errorf("Count = %d, want 1", list.Count)
+	}
+	if len(list.Warnings) != 1 || !strings.Contains(list.Warnings[0], broken) {
+		t.Errorf("Warnings = %v, want one naming %s", list.Warnings, broken)
+	}
+	if list.Skipped != 1 {
+		t.Errorf("Skipped = %d, want 1", list.Skipped)
+	}
+}
+
+func TestListSorted(t *testing.T) {
+	l, _ := setupBacklog(t)
+
+	_, _ = l.Create(backend.TaskInput{Title: "Urgent", Priority: backend.PriorityUrgent})
+	_, _ = l.Create(backend.TaskInput{Title: "Low", Priority: backend.PriorityLow})
+	_, _ = l.Create(backend.TaskInput{Title: "Another low", Priority: backend.PriorityLow})
+
+	// Ties on priority keep the default order, oldest first
+	list, err := l.ListSorted(backend.TaskFilters{Limit: 2}, []backend.SortKey{{Field: "priority"}})
+	if err != nil {
+		t.Fatalf("ListSorted() error = %v", err)
+	}
+	var got []string
+	for _, task := range list.Tasks {
+		got = append(got, task.Title)
+	}
+	if strings.Join(got, ",") != "Low,Another low" {
+		t.Errorf("ListSorted() titles = %v, want [Low Another low]", got)
+	}
+	if !list.HasMore {
+		t.Error("list.HasMore = false, want true after sorting then limiting")
+	}
+}
+
 func TestListWithLimit(t *testing.T) {
 	l, _ := setupBacklog(t)
 
@@ -251,6 +407,39 @@ func TestListWithLimit(t *testing.T) {
 	}
 }
 
+func TestCount(t *testing.T) {
+	l, _ := setupBacklog(t)
+
+	_, _ = l.Create(backend.TaskInput{Title: "High 1", Priority: backend.PriorityHigh})
+	_, _ = l.Create(backend.TaskInput{Title: "Low 1", Priority: backend.PriorityLow})
+	high, _ := l.Create(backend.TaskInput{Title: "High 2", Priority: backend.PriorityHigh})
+	if _, err := l.Move(high.ID, backend.StatusDone); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		filters backend.TaskFilters
+		want    int
+	}{
+		{"default", backend.TaskFilters{}, 2},
+		{"include done", backend.TaskFilters{IncludeDone: true}, 3},
+		{"priority", backend.TaskFilters{Priority: []backend.Priority{backend.PriorityHigh}, IncludeDone: true}, 2},
+		{"ignores limit", backend.TaskFilters{Limit: 1}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := l.Count(tt.filters)
+			if err != nil {
+				t.Fatalf("Count() error = %v", err)
+			}
+			if count != tt.want {
+				t.Errorf("Count() = %d, want %d", count, tt.want)
+			}
+		})
+	}
+}
+
 func TestUpdate(t *testing.T) {
 	l, _ := setupBacklog(t)
 
@@ -290,6 +479,156 @@ func TestUpdateLabels(t *testing.T) {
 	}
 }
 
+func TestUpdateLabelsSetSemantics(t *testing.T) {
+	l, _ := setupBacklog(t)
+
+	created, _ := l.Create(backend.TaskInput{
+		Title:  "Task",
+		Labels: []string{"zeta", "alpha"},
+	})
+
+	// Adding a label the task has and removing one it doesn't are no-ops
+	task, err := l.Update(created.ID, backend.TaskChanges{
+		AddLabels:    []string{"zeta"},
+		RemoveLabels: []string{"missing"},
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if strings.Join(task.Labels, ",") != "zeta,alpha" {
+		t.Errorf("task.Labels = %v, want [zeta alpha] unchanged", task.Labels)
+	}
+
+	// A label given twice is added once
+	task, err = l.Update(created.ID, backend.TaskChanges{AddLabels: []string{"beta", "beta"}})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if strings.Join(task.Labels, ",") != "alpha,beta,zeta" {
+		t.Errorf("task.Labels = %v, want [alpha beta zeta]", task.Labels)
+	}
+}
+
+func TestUpdateNoChanges(t *testing.T) {
+	l, _ := setupBacklog(t)
+
+	created, _ := l.Create(backend.TaskInput{Title: "Task", Priority: backend.PriorityHigh})
+
+	// Changes that leave the task as it was don't rewrite it
+	title := "Task"
+	priority := backend.PriorityHigh
+	task, changed, err := l.updateTask(created.ID, backend.TaskChanges{Title: &title, Priority: &priority})
+	if err != nil {
+		t.Fatalf("updateTask() error = %v", err)
+	}
+	if changed {
+		t.Error("updateTask() reported a change for a no-op update")
+	}
+	if !task.Updated.Equal(created.Updated) {
+		t.Errorf("task.Updated = %v, want unchanged %v", task.Updated, created.Updated)
+	}
+}
+
+func TestUpdateTimeTracking(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+
+	created, _ := l.Create(backend.TaskInput{Title: "Tracked"})
+
+	estimate := backend.Duration(24 * time.Hour)
+	spent := backend.Duration(90 * time.Minute)
+	if _, err := l.Update(created.ID, backend.TaskChanges{
+		Estimate: &estimate,
+		Spent:    &spent,
+	}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	// Durations should be persisted in human-readable form
+	content, err := os.ReadFile(filepath.Join(backlogDir, "backlog", "001-tracked.md"))
+	if err != nil {
+		t.Fatalf("failed to read task file: %v", err)
+	}
+	if !contains(string(content), "estimate: 1d") || !contains(string(content), "spent: 1h30m") {
+		t.Errorf("task file missing duration fields:\n%s", content)
+	}
+
+	task, err := l.Get(created.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if task.Estimate != estimate {
+		t.Errorf("task.Estimate = %v, want %v", task.Estimate, estimate)
+	}
+	if task.Spent != spent {
+		t.Errorf("task.Spent = %v, want %v", task.Spent, spent)
+	}
+}
+
+func TestClaimClock(t *testing.T) {
+	l, _ := setupBacklog(t)
+
+	created, _ := l.Create(backend.TaskInput{Title: "Clocked", Status: backend.StatusTodo})
+
+	claim, err := l.Claim(created.ID, "")
+	if err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+	if claim.Task.StartedAt == nil {
+		t.Fatal("Claim() did not record started_at")
+	}
+	started := *claim.Task.StartedAt
+
+	// Re-claiming a released task keeps the original start time
+	if err := l.Release(created.ID); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	claim, err = l.Claim(created.ID, "")
+	if err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+	if claim.Task.StartedAt == nil || !claim.Task.StartedAt.Equal(started) {
+		t.Errorf("re-claim StartedAt = %v, want %v", claim.Task.StartedAt, started)
+	}
+
+	// Restarting the clock moves it forward
+	if err := l.Release(created.ID); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	claim, err = l.ClaimRestartClock(created.ID, "")
+	if err != nil {
+		t.Fatalf("ClaimRestartClock() error = %v", err)
+	}
+	if claim.Task.StartedAt == nil || !claim.Task.StartedAt.After(started) {
+		t.Errorf("restarted StartedAt = %v, want after %v", claim.Task.StartedAt, started)
+	}
+
+	// Completing records completed_at and a fixed duration
+	done, err := l.Move(created.ID, backend.StatusDone)
+	if err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if done.CompletedAt == nil {
+		t.Fatal("Move(done) did not record completed_at")
+	}
+	task, err := l.Get(created.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if want := backend.Duration(task.CompletedAt.Sub(*task.StartedAt)); task.Duration != want {
+		t.Errorf("Duration = %v, want %v", task.Duration, want)
+	}
+
+	// Reopening clears completed_at
+	reopened, err := l.Move(created.ID, backend.StatusTodo)
+	if err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if reopened.CompletedAt != nil {
+		t.Errorf("reopened CompletedAt = %v, want nil", reopened.CompletedAt)
+	}
+}
+
 func TestMove(t *testing.T) {
 	l, backlogDir := setupBacklog(t)
 
@@ -313,6 +652,81 @@ func TestMove(t *testing.T) {
 	}
 }
 
+func TestReopen(t *testing.T) {
+	l, _ := setupBacklog(t)
+
+	created, _ := l.Create(backend.TaskInput{Title: "Task", Status: backend.StatusDone})
+
+	task, err := l.Reopen(created.ID, backend.StatusTodo, "Regression found")
+	if err != nil {
+		t.Fatalf("Reopen() error = %v", err)
+	}
+	if task.Status != backend.StatusTodo {
+		t.Errorf("Status = %q, want %q", task.Status, backend.StatusTodo)
+	}
+
+	comments, _ := l.ListComments(created.ID)
+	if len(comments) != 1 || comments[0].Body != "Regression found" {
+		t.Errorf("comments = %+v, want one reopen comment", comments)
+	}
+
+	// Reopening a task that is not done is refused
+	if _, err := l.Reopen(created.ID, backend.StatusTodo, "Again"); err == nil {
+		t.Error("Reopen() of a task that is not done should return error")
+	}
+}
+
+func TestMoveAndAssign(t *testing.T) {
+	l, _ := setupBacklog(t)
+
+	created, _ := l.Create(backend.TaskInput{Title: "Task"})
+
+	task, err := l.MoveAndAssign(created.ID, backend.StatusInProgress, "alice")
+	if err != nil {
+		t.Fatalf("MoveAndAssign() error = %v", err)
+	}
+	if task.Status != backend.StatusInProgress || task.Assignee != "alice" {
+		t.Errorf("task = %s/%q, want in-progress/alice", task.Status, task.Assignee)
+	}
+
+	got, _ := l.Get(created.ID)
+	if got.Status != backend.StatusInProgress || got.Assignee != "alice" {
+		t.Errorf("stored task = %s/%q, want in-progress/alice", got.Status, got.Assignee)
+	}
+
+	// An empty assignee unassigns
+	task, err = l.MoveAndAssign(created.ID, backend.StatusReview, "")
+	if err != nil {
+		t.Fatalf("MoveAndAssign() error = %v", err)
+	}
+	if task.Status != backend.StatusReview || task.Assignee != "" {
+		t.Errorf("task = %s/%q, want review/unassigned", task.Status, task.Assignee)
+	}
+}
+
+func TestMoveAndAssignFailureLeavesTask(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+
+	created, _ := l.Create(backend.TaskInput{Title: "Task"})
+
+	// A directory in the way of the moved file makes the write fail
+	blocked := filepath.Join(backlogDir, "in-progress", generateFilename(created.ID, created.Title))
+	if err := os.Mkdir(blocked, 0755); err != nil {
+		t.Fatalf("Mkdir() error = %v", err)
+	}
+	if _, err := l.MoveAndAssign(created.ID, backend.StatusInProgress, "alice"); err == nil || !strings.Contains(err.Error(), "failed to write task") {
+		t.Fatalf("MoveAndAssign() error = %v, want a write failure", err)
+	}
+
+	got, err := l.Get(created.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status != created.Status || got.Assignee != "" {
+		t.Errorf("stored task = %s/%q, want it left at %s and unassigned", got.Status, got.Assignee, created.Status)
+	}
+}
+
 func TestDelete(t *testing.T) {
 	l, _ := setupBacklog(t)
 
@@ -395,6 +809,136 @@ func TestListComments(t *testing.T) {
 	}
 }
 
+func TestListCommentsFiltered(t *testing.T) {
+	l, _ := setupBacklog(t)
+
+	created, _ := l.Create(backend.TaskInput{Title: "Task"})
+	_, _ = l.AddComment(created.ID, "Comment 1")
+	_, _ = l.AddComment(created.ID, "Comment 2")
+
+	// Comments record their time to the second
+	comments, err := l.ListCommentsFiltered(created.ID, backend.CommentFilters{Since: time.Now().Add(-time.Minute)})
+	if err != nil {
+		t.Fatalf("ListCommentsFiltered() error = %v", err)
+	}
+	if len(comments) != 2 {
+		t.Errorf("len(comments) = %d, want 2", len(comments))
+	}
+
+	comments, err = l.ListCommentsFiltered(created.ID, backend.CommentFilters{Since: time.Now().Add(24 * time.Hour)})
+	if err != nil {
+		t.Fatalf("ListCommentsFiltered() error = %v", err)
+	}
+	if len(comments) != 0 {
+		t.Errorf("len(comments) = %d, want 0 after a cutoff tomorrow", len(comments))
+	}
+
+	comments, err = l.ListCommentsFiltered(created.ID, backend.CommentFilters{Limit: 1})
+	if err != nil {
+		t.Fatalf("ListCommentsFiltered() error = %v", err)
+	}
+	if len(comments) != 1 || comments[0].Body != "Comment 2" {
+		t.Errorf("comments = %+v, want only Comment 2", comments)
+	}
+}
+
+func TestEditAndDeleteComment(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+
+	created, _ := l.Create(backend.TaskInput{Title: "Task"})
+	first, _ := l.AddComment(created.ID, "Comment 1")
+	second, _ := l.AddComment(created.ID, "Comment 2")
+	if first.ID != "1" || second.ID != "2" || second.TaskID != created.ID {
+		t.Fatalf("AddComment() IDs = %q, %q on %q; want 1, 2 on %q", first.ID, second.ID, second.TaskID, created.ID)
+	}
+
+	// IDs written as "c<n>" by older versions still find the comment
+	edited, err := l.EditComment(created.ID, "c1", "Comment 1, reworded", false)
+	if err != nil {
+		t.Fatalf("EditComment() error = %v", err)
+	}
+	if edited.ID != "1" || edited.Body != "Comment 1, reworded" {
+		t.Errorf("EditComment() = %+v, want 1 with the new body", edited)
+	}
+
+	if err := l.DeleteComment(created.ID, "1", false); err != nil {
+		t.Fatalf("DeleteComment() error = %v", err)
+	}
+	third, _ := l.AddComment(created.ID, "Comment 3")
+
+	// Remaining comments keep their IDs, and a new comment doesn't reuse 2
+	comments, _ := l.ListComments(created.ID)
+	if len(comments) != 2 || comments[0].ID != "2" || comments[0].Body != "Comment 2" || third.ID != "3" {
+		t.Errorf("comments = %+v, new ID %q; want 2 and 3", comments, third.ID)
+	}
+
+	path, _ := l.findTaskFile(created.ID)
+	content, _ := os.ReadFile(path)
+	if !strings.Contains(string(content), "@test-agent <!-- id: 2 -->") {
+		t.Errorf("task file does not store comment IDs:\n%s", content)
+	}
+
+	// Comments by another agent need force
+	other := New()
+	if err := other.Connect(backend.Config{Workspace: &WorkspaceConfig{Path: backlogDir}, AgentID: "other-agent"}); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	var authorErr *backend.CommentAuthorError
+	if _, err := other.EditComment(created.ID, "2", "Hijacked", false); !errors.As(err, &authorErr) || authorErr.Author != "test-agent" {
+		t.Errorf("EditComment() error = %v, want CommentAuthorError by test-agent", err)
+	}
+	if err := other.DeleteComment(created.ID, "2", true); err != nil {
+		t.Errorf("DeleteComment(force) error = %v", err)
+	}
+
+	if err := l.DeleteComment(created.ID, "9", false); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("DeleteComment() of a missing comment error = %v, want not found", err)
+	}
+}
+
+func TestCommentIDsAndTimesPersist(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+
+	created, _ := l.Create(backend.TaskInput{Title: "Task"})
+	first, _ := l.AddComment(created.ID, "Comment 1")
+	_, _ = l.AddComment(created.ID, "Comment 2")
+
+	// Deleting every comment keeps the highest ID handed out
+	for _, id := range []string{"2", "1"} {
+		if err := l.DeleteComment(created.ID, id, false); err != nil {
+			t.Fatalf("DeleteComment(%s) error = %v", id, err)
+		}
+	}
+	path, _ := l.findTaskFile(created.ID)
+	content, _ := os.ReadFile(path)
+	if !strings.Contains(string(content), "## Comments\n\n<!-- last-id: 2 -->\n") {
+		t.Errorf("task file does not record the last comment ID:\n%s", content)
+	}
+
+	third, err := reconnect(t, backlogDir, WorkspaceConfig{}).AddComment(created.ID, "Comment 3")
+	if err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	if third.ID != "3" {
+		t.Errorf("new comment ID = %q, want 3", third.ID)
+	}
+	content, _ = os.ReadFile(path)
+	if strings.Contains(string(content), "last-id") {
+		t.Errorf("task file keeps the last ID once a comment has it:\n%s", content)
+	}
+
+	comments, err := reconnect(t, backlogDir, WorkspaceConfig{}).ListComments(created.ID)
+	if err != nil {
+		t.Fatalf("ListComments() error = %v", err)
+	}
+	if len(comments) != 1 || !comments[0].Created.Equal(third.Created) {
+		t.Errorf("comments = %+v, want 3 created at %s", comments, third.Created)
+	}
+	if first.Created.Nanosecond() != 0 {
+		t.Errorf("comment time %s is not stored to the second", first.Created)
+	}
+}
+
 func TestGenerateID(t *testing.T) {
 	l, _ := setupBacklog(t)
 
@@ -428,7 +972,12 @@ func TestSlugify(t *testing.T) {
 		{"Hello World", "hello-world"},
 		{"Test_Task", "test-task"},
 		{"Already-Slugified", "already-slugified"},
-		{"Special!@#$Characters", "specialcharacters"},
+		{"Special!@#$Characters", "special-characters"},
+		{"Don't panic: it's fine", "dont-panic-its-fine"},
+		{"Café résumé für Søren", "cafe-resume-fur-soren"},
+		{"Fix 🐛 in login 🚀", "fix-in-login"},
+		{"תיקון באג", ""},
+		{"🎉🎉", ""},
 		{"Multiple   Spaces", "multiple-spaces"},
 		{"  Trim  ", "trim"},
 		{"123 Numbers", "123-numbers"},
@@ -793,3 +1342,91 @@ func TestLinkNonExistentTask(t *testing.T) {
 		t.Fatal("Link() with non-existent source should return error")
 	}
 }
+
+func TestParentChild(t *testing.T) {
+	l, _ := setupBacklog(t)
+
+	parent, err := l.Create(backend.TaskInput{Title: "Epic"})
+	if err != nil {
+		t.Fatalf("Create parent error = %v", err)
+	}
+	child1, err := l.Create(backend.TaskInput{Title: "Part one", Parent: parent.ID})
+	if err != nil {
+		t.Fatalf("Create child1 error = %v", err)
+	}
+	child2, err := l.Create(backend.TaskInput{Title: "Part two", Status: backend.StatusDone, Parent: parent.ID})
+	if err != nil {
+		t.Fatalf("Create child2 error = %v", err)
+	}
+	if _, err := l.Create(backend.TaskInput{Title: "Unrelated"}); err != nil {
+		t.Fatalf("Create unrelated error = %v", err)
+	}
+
+	// Parent survives a round trip through the task file
+	got, err := l.Get(child1.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Parent != parent.ID {
+		t.Errorf("Parent = %q, want %q", got.Parent, parent.ID)
+	}
+
+	list, err := l.List(backend.TaskFilters{Parent: parent.ID, IncludeDone: true})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if list.Count != 2 {
+		t.Errorf("List(parent) count = %d, want 2", list.Count)
+	}
+
+	relations, err := l.ListRelations(parent.ID)
+	if err != nil {
+		t.Fatalf("ListRelations() error = %v", err)
+	}
+	children := map[string]backend.Status{}
+	for _, r := range relations {
+		if r.Type != backend.RelationChild {
+			t.Errorf("relation.Type = %q, want %q", r.Type, backend.RelationChild)
+		}
+		children[r.TaskID] = r.TaskStatus
+	}
+	if children[child1.ID] != backend.StatusBacklog || children[child2.ID] != backend.StatusDone {
+		t.Errorf("children = %v, want %s backlog and %s done", children, child1.ID, child2.ID)
+	}
+
+	relations, err = l.ListRelations(child1.ID)
+	if err != nil {
+		t.Fatalf("ListRelations(child) error = %v", err)
+	}
+	if len(relations) != 1 || relations[0].Type != backend.RelationParent || relations[0].TaskID != parent.ID {
+		t.Errorf("ListRelations(child) = %+v, want parent %s", relations, parent.ID)
+	}
+
+	// Clearing the parent makes the task top-level again
+	empty := ""
+	updated, err := l.Update(child1.ID, backend.TaskChanges{Parent: &empty})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated.Parent != "" {
+		t.Errorf("Parent after clearing = %q, want empty", updated.Parent)
+	}
+}
+
+func TestParentValidation(t *testing.T) {
+	l, _ := setupBacklog(t)
+
+	if _, err := l.Create(backend.TaskInput{Title: "Orphan", Parent: "999"}); err == nil {
+		t.Error("Create() with non-existent parent should return error")
+	}
+
+	parent, _ := l.Create(backend.TaskInput{Title: "Epic"})
+	child, _ := l.Create(backend.TaskInput{Title: "Story", Parent: parent.ID})
+
+	for _, id := range []string{parent.ID, child.ID} {
+		newParent := id
+		if _, err := l.Update(parent.ID, backend.TaskChanges{Parent: &newParent}); err == nil {
+			t.Errorf("Update() making %s the parent of %s should fail", id, parent.ID)
+		}
+	}
+}
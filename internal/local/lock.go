@@ -2,6 +2,7 @@ package local
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
@@ -14,6 +15,21 @@ const (
 
 	// locksDir is the directory name for lock files.
 	locksDir = ".locks"
+
+	// lockAcquireAttempts bounds how many times acquireLock retries after
+	// losing a race to create or break a lock.
+	lockAcquireAttempts = 5
+
+	// createLockID names the lock that serializes task creation, so that
+	// concurrent adds can't pick the same ID or both miss a duplicate.
+	createLockID = ".create"
+
+	// createLockTTL is how long the create lock is held at most, so that an
+	// add that crashed while holding it doesn't block creation for long.
+	createLockTTL = 30 * time.Second
+
+	// createLockWait bounds how long Create waits for the create lock.
+	createLockWait = 10 * time.Second
 )
 
 // LockFile represents a file-based lock for a task.
@@ -40,7 +56,127 @@ func (l *Local) readLock(taskID string) (*LockFile, error) {
 		return nil, fmt.Errorf("failed to read lock file: %w", err)
 	}
 
-	return parseLockFile(content)
+	lock, err := parseLockFile(content)
+	if err != nil {
+		return nil, err
+	}
+
+	// A lock without an expiry is still being written by the agent that
+	// created it; treat it as fresh from its modification time.
+	if lock.ExpiresAt.IsZero() {
+		info, err := os.Stat(lockPath)
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat lock file: %w", err)
+		}
+		lock.ExpiresAt = info.ModTime().UTC().Add(DefaultLockTTL)
+	}
+
+	return lock, nil
+}
+
+// acquireLock atomically creates the lock file for a task using O_EXCL, so
+// only one agent can hold the lock even when claims race. If an active lock
+// already exists, it is returned and the lock is not acquired. Expired locks
+// are broken and acquisition is retried.
+func (l *Local) acquireLock(taskID string, lock *LockFile) (*LockFile, error) {
+	// Ensure locks directory exists
+	locksPath := filepath.Join(l.path, locksDir)
+	if err := os.MkdirAll(locksPath, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create locks directory: %w", err)
+	}
+
+	lockPath := l.lockFilePath(taskID)
+	for attempt := 0; attempt < lockAcquireAttempts; attempt++ {
+		slog.Debug("acquiring lock", "task", taskID, "agent", lock.Agent, "attempt", attempt+1)
+		f, err := os.OpenFile(lockPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, err = f.WriteString(formatLockFile(lock))
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(lockPath)
+				return nil, fmt.Errorf("failed to write lock file: %w", err)
+			}
+			slog.Info("acquired lock", "task", taskID, "agent", lock.Agent, "expires_at", lock.ExpiresAt)
+			return nil, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create lock file: %w", err)
+		}
+
+		existing, err := l.readLock(taskID)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			continue // Released since we tried to create it
+		}
+		if existing.isActive() {
+			slog.Info("lock held by another agent", "task", taskID, "holder", existing.Agent, "expires_at", existing.ExpiresAt)
+			return existing, nil
+		}
+
+		slog.Info("breaking stale lock", "task", taskID, "holder", existing.Agent, "expired_at", existing.ExpiresAt)
+		if err := l.breakStaleLock(taskID); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("lock for task %s is contended", taskID)
+}
+
+// withCreateLock runs fn while holding the create lock, waiting up to
+// createLockWait for another process to release it.
+func (l *Local) withCreateLock(fn func() error) error {
+	now := time.Now().UTC()
+	lock := &LockFile{Agent: l.agentID, ClaimedAt: now, ExpiresAt: now.Add(createLockTTL)}
+	deadline := now.Add(createLockWait)
+	for {
+		existing, err := l.acquireLock(createLockID, lock)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for another task creation to finish (agent %s)", existing.Agent)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	defer l.removeLock(createLockID)
+	return fn()
+}
+
+// breakStaleLock removes an expired lock file. The lock is first renamed to a
+// unique path so that only one agent can break it; if the renamed lock turns
+// out to be active (another agent acquired it in the meantime), it is restored.
+func (l *Local) breakStaleLock(taskID string) error {
+	lockPath := l.lockFilePath(taskID)
+	stalePath := fmt.Sprintf("%s.stale-%d-%d", lockPath, os.Getpid(), time.Now().UnixNano())
+
+	if err := os.Rename(lockPath, stalePath); err != nil {
+		if os.IsNotExist(err) {
+			return nil // Another agent already broke it
+		}
+		return fmt.Errorf("failed to break stale lock: %w", err)
+	}
+	defer os.Remove(stalePath)
+
+	content, err := os.ReadFile(stalePath)
+	if err != nil {
+		return fmt.Errorf("failed to read stale lock: %w", err)
+	}
+	if lock, err := parseLockFile(content); err == nil && (lock.ExpiresAt.IsZero() || lock.isActive()) {
+		// Link fails if a new lock already exists, which is fine either way
+		os.Link(stalePath, lockPath)
+	}
+
+	return nil
 }
 
 // writeLock writes a lock file for a task.
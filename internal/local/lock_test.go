@@ -1,8 +1,11 @@
 package local
 
 import (
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -146,6 +149,10 @@ func TestClaim(t *testing.T) {
 	if lock.Agent != "test-agent" {
 		t.Errorf("lock.Agent = %q, want %q", lock.Agent, "test-agent")
 	}
+	// The lock file keeps whole seconds
+	if !result.ExpiresAt.Truncate(time.Second).Equal(lock.ExpiresAt) {
+		t.Errorf("ExpiresAt = %v, want the lock's %v", result.ExpiresAt, lock.ExpiresAt)
+	}
 
 	// Verify agent label was added
 	claimedTask, _ := l.Get(task.ID)
@@ -389,3 +396,131 @@ func TestFindAgentLabels(t *testing.T) {
 		})
 	}
 }
+
+func TestAcquireLockConcurrent(t *testing.T) {
+	l, _ := setupBacklog(t)
+
+	const agents = 20
+	var wg sync.WaitGroup
+	acquired := make(chan string, agents)
+	start := make(chan struct{})
+
+	for i := 0; i < agents; i++ {
+		agentID := fmt.Sprintf("agent-%d", i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			now := time.Now().UTC()
+			existing, err := l.acquireLock("001", &LockFile{Agent: agentID, ClaimedAt: now, ExpiresAt: now.Add(DefaultLockTTL)})
+			if err != nil {
+				t.Errorf("acquireLock() error = %v", err)
+				return
+			}
+			if existing == nil {
+				acquired <- agentID
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(acquired)
+
+	var winners []string
+	for agentID := range acquired {
+		winners = append(winners, agentID)
+	}
+	if len(winners) != 1 {
+		t.Fatalf("acquireLock() succeeded for %d agents, want exactly 1: %v", len(winners), winners)
+	}
+
+	lock, err := l.readLock("001")
+	if err != nil {
+		t.Fatalf("readLock() error = %v", err)
+	}
+	if lock.Agent != winners[0] {
+		t.Errorf("lock.Agent = %q, want %q", lock.Agent, winners[0])
+	}
+}
+
+func TestAcquireLockBreaksStaleLock(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+
+	expired := &LockFile{
+		Agent:     "old-agent",
+		ClaimedAt: time.Now().UTC().Add(-1 * time.Hour),
+		ExpiresAt: time.Now().UTC().Add(-30 * time.Minute),
+	}
+	if err := l.writeLock("001", expired); err != nil {
+		t.Fatalf("writeLock() error = %v", err)
+	}
+
+	now := time.Now().UTC()
+	existing, err := l.acquireLock("001", &LockFile{Agent: "new-agent", ClaimedAt: now, ExpiresAt: now.Add(DefaultLockTTL)})
+	if err != nil {
+		t.Fatalf("acquireLock() error = %v", err)
+	}
+	if existing != nil {
+		t.Fatalf("acquireLock() returned existing lock held by %q, want stale lock broken", existing.Agent)
+	}
+
+	lock, _ := l.readLock("001")
+	if lock == nil || lock.Agent != "new-agent" {
+		t.Errorf("lock = %+v, want lock held by new-agent", lock)
+	}
+
+	// Breaking the stale lock should not leave temporary files behind
+	entries, _ := os.ReadDir(filepath.Join(backlogDir, locksDir))
+	if len(entries) != 1 {
+		t.Errorf("locks directory has %d entries, want 1", len(entries))
+	}
+}
+
+func TestReadLockWithoutExpiryIsActive(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+
+	// Simulate a lock file that was created but not yet written
+	lockPath := filepath.Join(backlogDir, locksDir, "001.lock")
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		t.Fatalf("failed to create locks dir: %v", err)
+	}
+	if err := os.WriteFile(lockPath, nil, 0644); err != nil {
+		t.Fatalf("failed to write lock file: %v", err)
+	}
+
+	lock, err := l.readLock("001")
+	if err != nil {
+		t.Fatalf("readLock() error = %v", err)
+	}
+	if !lock.isActive() {
+		t.Error("freshly created empty lock should be treated as active")
+	}
+}
+
+func TestCreateAndClaim(t *testing.T) {
+	l, repoDir := setupGitBacklog(t, filepath.Join(t.TempDir(), "remote.git"), time.Minute)
+
+	task, claim, err := l.CreateAndClaim(backend.TaskInput{Title: "Own task"}, "claude-1")
+	if err != nil {
+		t.Fatalf("CreateAndClaim() error = %v", err)
+	}
+	if claim == nil || claim.AlreadyOwned {
+		t.Fatalf("CreateAndClaim() claim = %+v, want a new claim", claim)
+	}
+	if claim.Task.ID != task.ID || claim.Task.Status != backend.StatusInProgress || claim.Task.Assignee != "claude-1" {
+		t.Errorf("claimed task = %+v, want %s in progress for claude-1", claim.Task, task.ID)
+	}
+	if lock, err := l.readLock(task.ID); err != nil || lock == nil || lock.Agent != "claude-1" {
+		t.Errorf("readLock() = %+v, %v; want a lock held by claude-1", lock, err)
+	}
+
+	// The create and the claim are one commit
+	out, err := exec.Command("git", "-C", repoDir, "log", "--format=%s").Output()
+	if err != nil {
+		t.Fatalf("git log: %v", err)
+	}
+	want := "add+claim: " + task.ID + " [agent:claude-1]\ninit\n"
+	if string(out) != want {
+		t.Errorf("commits:\n%s\nwant:\n%s", out, want)
+	}
+}
@@ -0,0 +1,41 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// Put writes a task exactly as given, keeping its ID, status, and
+// timestamps, and replaces any existing file for the same ID. It reports
+// whether the task was new. Unlike Create and Update it never commits; it
+// exists to mirror tasks from another backend into a local directory, such
+// as the Linear cache kept by backlog sync.
+func (l *Local) Put(task *backend.Task) (bool, error) {
+	if !l.connected {
+		return false, errors.New("not connected")
+	}
+	if !task.Status.IsValid() {
+		return false, fmt.Errorf("invalid status: %s", task.Status)
+	}
+
+	oldPath, err := l.findTaskFile(task.ID)
+	created := err != nil
+
+	if err := l.writeTask(task); err != nil {
+		return false, err
+	}
+
+	// Drop the old file if the status or title moved it
+	newPath := filepath.Join(l.path, string(task.Status), generateFilename(task.ID, task.Title))
+	if !created && oldPath != newPath {
+		if err := os.Remove(oldPath); err != nil && !os.IsNotExist(err) {
+			return false, fmt.Errorf("failed to remove old task file: %w", err)
+		}
+	}
+
+	return created, nil
+}
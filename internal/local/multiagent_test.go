@@ -407,6 +407,131 @@ func TestMultiAgentReleaseConflicts(t *testing.T) {
 	}
 }
 
+// TestMultiAgentForceRelease tests that a supervisor can force-release a task
+// left behind by another agent, whether its lock is active or has expired.
+func TestMultiAgentForceRelease(t *testing.T) {
+	tmpDir := t.TempDir()
+	backlogDir := filepath.Join(tmpDir, ".backlog")
+
+	// Create directory structure
+	for _, dir := range []string{"backlog", "todo", "in-progress", "review", "done", ".locks"} {
+		if err := os.MkdirAll(filepath.Join(backlogDir, dir), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+	}
+
+	deadAgent := New()
+	supervisor := New()
+	deadAgent.Connect(backend.Config{
+		Workspace:        &WorkspaceConfig{Path: backlogDir},
+		AgentID:          "dead-agent",
+		AgentLabelPrefix: "agent",
+	})
+	supervisor.Connect(backend.Config{
+		Workspace:        &WorkspaceConfig{Path: backlogDir},
+		AgentID:          "supervisor",
+		AgentLabelPrefix: "agent",
+	})
+
+	for _, expired := range []bool{false, true} {
+		task, _ := deadAgent.Create(backend.TaskInput{Title: "Stuck Task", Status: backend.StatusTodo})
+		if _, err := deadAgent.Claim(task.ID, "dead-agent"); err != nil {
+			t.Fatalf("Claim() error = %v", err)
+		}
+		if expired {
+			lock, _ := deadAgent.readLock(task.ID)
+			lock.ExpiresAt = time.Now().Add(-time.Minute)
+			if err := deadAgent.writeLock(task.ID, lock); err != nil {
+				t.Fatalf("writeLock() error = %v", err)
+			}
+		}
+
+		// The agent label still guards the task once its lock has expired
+		if _, ok := supervisor.Release(task.ID).(*ReleaseConflictError); !ok {
+			t.Errorf("expired=%v: Release() should fail with *ReleaseConflictError", expired)
+		}
+
+		claimedBy, err := supervisor.ForceRelease(task.ID)
+		if err != nil {
+			t.Fatalf("expired=%v: ForceRelease() error = %v", expired, err)
+		}
+		if claimedBy != "dead-agent" {
+			t.Errorf("expired=%v: ForceRelease() = %q, want %q", expired, claimedBy, "dead-agent")
+		}
+
+		released, _ := supervisor.Get(task.ID)
+		if released.Status != backend.StatusTodo || released.Assignee != "" || len(released.Labels) != 0 {
+			t.Errorf("expired=%v: released task = %+v, want an unassigned, unlabeled todo task", expired, released)
+		}
+		if lock, _ := supervisor.readLock(task.ID); lock != nil {
+			t.Errorf("expired=%v: lock file should be removed after a forced release", expired)
+		}
+	}
+}
+
+// TestMultiAgentSteal tests that an agent can take over a task another agent
+// claimed, whether its lock is active or has expired.
+func TestMultiAgentSteal(t *testing.T) {
+	tmpDir := t.TempDir()
+	backlogDir := filepath.Join(tmpDir, ".backlog")
+
+	// Create directory structure
+	for _, dir := range []string{"backlog", "todo", "in-progress", "review", "done", ".locks"} {
+		if err := os.MkdirAll(filepath.Join(backlogDir, dir), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+	}
+
+	deadAgent := New()
+	rescuer := New()
+	deadAgent.Connect(backend.Config{
+		Workspace:        &WorkspaceConfig{Path: backlogDir},
+		AgentID:          "dead-agent",
+		AgentLabelPrefix: "agent",
+	})
+	rescuer.Connect(backend.Config{
+		Workspace:        &WorkspaceConfig{Path: backlogDir},
+		AgentID:          "rescuer",
+		AgentLabelPrefix: "agent",
+	})
+
+	for _, expired := range []bool{false, true} {
+		task, _ := deadAgent.Create(backend.TaskInput{Title: "Stuck Task", Status: backend.StatusTodo})
+		if _, err := deadAgent.Claim(task.ID, "dead-agent"); err != nil {
+			t.Fatalf("Claim() error = %v", err)
+		}
+		if expired {
+			lock, _ := deadAgent.readLock(task.ID)
+			lock.ExpiresAt = time.Now().Add(-time.Minute)
+			if err := deadAgent.writeLock(task.ID, lock); err != nil {
+				t.Fatalf("writeLock() error = %v", err)
+			}
+		} else if _, err := rescuer.Claim(task.ID, "rescuer"); err == nil {
+			t.Fatal("Claim() of an actively locked task should fail without stealing")
+		}
+
+		result, claimedBy, err := rescuer.Steal(task.ID, "rescuer")
+		if err != nil {
+			t.Fatalf("expired=%v: Steal() error = %v", expired, err)
+		}
+		if claimedBy != "dead-agent" {
+			t.Errorf("expired=%v: Steal() claimedBy = %q, want %q", expired, claimedBy, "dead-agent")
+		}
+		if result.Task.Assignee != "rescuer" || len(result.Task.Labels) != 1 || result.Task.Labels[0] != "agent:rescuer" {
+			t.Errorf("expired=%v: stolen task = %+v, want assigned to rescuer with only its label", expired, result.Task)
+		}
+		if lock, _ := rescuer.readLock(task.ID); lock == nil || lock.Agent != "rescuer" {
+			t.Errorf("expired=%v: lock = %+v, want it held by rescuer", expired, lock)
+		}
+
+		// Stealing a task the agent already holds takes nothing from anyone
+		result, claimedBy, err = rescuer.Steal(task.ID, "rescuer")
+		if err != nil || !result.AlreadyOwned || claimedBy != "" {
+			t.Errorf("expired=%v: Steal() of an owned task = %+v, %q, %v; want already owned", expired, result, claimedBy, err)
+		}
+	}
+}
+
 // TestMultiAgentClaimWithCustomLabelPrefix tests claiming with custom label prefixes
 func TestMultiAgentClaimWithCustomLabelPrefix(t *testing.T) {
 	tmpDir := t.TempDir()
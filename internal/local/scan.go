@@ -0,0 +1,177 @@
+package local
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// scanWorkers is the number of goroutines that parse task files while
+// listing: one per CPU, up to 8. Parsing is CPU-bound, so more workers than
+// CPUs only add contention.
+var scanWorkers = min(runtime.NumCPU(), 8)
+
+// scanDir is a status directory to scan and the task files found in it.
+type scanDir struct {
+	path   string
+	status backend.Status
+	files  []os.DirEntry
+	err    error
+}
+
+// scanResult is the outcome of reading one task file.
+type scanResult struct {
+	task *backend.Task
+	err  error
+}
+
+// scanTasks reads the task files in the status directories the filters
+// select and calls visit for each task matching them. It returns the number
+// of task files read and warnings for files that couldn't be parsed.
+//
+// The directories are listed concurrently and the files parsed by a pool of
+// workers, but visit is called from the calling goroutine in directory
+// order, so callers need no locking and see the same order as a sequential
+// scan.
+func (l *Local) scanTasks(filters backend.TaskFilters, visit func(task *backend.Task)) (int, []string, error) {
+	var warnings []string
+
+	// Determine which status directories to scan
+	statusDirs := []backend.Status{
+		backend.StatusBacklog,
+		backend.StatusTodo,
+		backend.StatusInProgress,
+		backend.StatusReview,
+	}
+	if filters.IncludeDone {
+		statusDirs = append(statusDirs, backend.StatusDone)
+	}
+
+	// Filter by status if specified
+	if len(filters.Status) > 0 {
+		statusDirs = filters.Status
+	}
+
+	// Soft-deleted tasks live in the same status layout under the trash
+	roots := []string{l.path}
+	if filters.IncludeDeleted {
+		roots = append(roots, l.trashPath())
+	}
+
+	var dirs []*scanDir
+	for _, root := range roots {
+		for _, status := range statusDirs {
+			dirs = append(dirs, &scanDir{path: filepath.Join(root, string(status)), status: status})
+		}
+	}
+	if err := l.listScanDirs(dirs); err != nil {
+		return 0, nil, err
+	}
+
+	// Load the index before the workers share it
+	l.loadIndex()
+	results := l.readScanDirs(dirs)
+
+	scanned := 0
+	for i, dir := range dirs {
+		seen := make(map[string]bool, len(dir.files))
+		for j, entry := range dir.files {
+			seen[l.indexKey(filepath.Join(dir.path, entry.Name()))] = true
+			result := results[i][j]
+			if result.err != nil {
+				// Skip files that can't be parsed, but report them so they
+				// don't silently disappear from listings
+				warnings = append(warnings, fmt.Sprintf("skipped unparseable task file %v", result.err))
+				continue
+			}
+			scanned++
+
+			// Apply filters
+			if l.matchesFilters(result.task, filters) {
+				visit(result.task)
+			}
+		}
+		l.pruneIndex(dir.path, seen)
+	}
+	l.flushIndex()
+
+	return scanned, warnings, nil
+}
+
+// listScanDirs reads each directory in its own goroutine, keeping the task
+// files in it. A missing directory has no files; any other failure to read
+// one is returned.
+func (l *Local) listScanDirs(dirs []*scanDir) error {
+	var wg sync.WaitGroup
+	for _, dir := range dirs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			entries, err := os.ReadDir(dir.path)
+			if os.IsNotExist(err) {
+				return
+			}
+			if err != nil {
+				dir.err = fmt.Errorf("failed to read directory %s: %w", dir.path, err)
+				return
+			}
+			for _, entry := range entries {
+				if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") || l.isIgnored(dir.status, entry.Name()) {
+					continue
+				}
+				dir.files = append(dir.files, entry)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, dir := range dirs {
+		if dir.err != nil {
+			return dir.err
+		}
+	}
+	return nil
+}
+
+// readScanDirs parses the files of every directory with a bounded pool of
+// workers. results[i][j] holds the outcome for dirs[i].files[j].
+func (l *Local) readScanDirs(dirs []*scanDir) [][]scanResult {
+	type job struct{ dir, file int }
+
+	results := make([][]scanResult, len(dirs))
+	total := 0
+	for i, dir := range dirs {
+		results[i] = make([]scanResult, len(dir.files))
+		total += len(dir.files)
+	}
+
+	workers := min(scanWorkers, total)
+	jobs := make(chan job)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				dir := dirs[j.dir]
+				entry := dir.files[j.file]
+				task, err := l.readIndexedTask(filepath.Join(dir.path, entry.Name()), dir.status, entry)
+				results[j.dir][j.file] = scanResult{task: task, err: err}
+			}
+		}()
+	}
+	for i, dir := range dirs {
+		for j := range dir.files {
+			jobs <- job{dir: i, file: j}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,125 @@
+package local
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// writeTaskFixtures writes n task files spread over the status directories,
+// with every tenth one unparseable, and returns the backlog directory.
+func writeTaskFixtures(tb testing.TB, n int) string {
+	tb.Helper()
+	backlogDir := filepath.Join(tb.TempDir(), ".backlog")
+	statuses := []backend.Status{backend.StatusBacklog, backend.StatusTodo, backend.StatusInProgress, backend.StatusReview, backend.StatusDone}
+	priorities := []backend.Priority{backend.PriorityUrgent, backend.PriorityHigh, backend.PriorityMedium, backend.PriorityLow}
+	for _, status := range statuses {
+		if err := os.MkdirAll(filepath.Join(backlogDir, string(status)), 0755); err != nil {
+			tb.Fatal(err)
+		}
+	}
+
+	created := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	for i := 1; i <= n; i++ {
+		id := fmt.Sprintf("%04d", i)
+		content := []byte("---\nid: [broken\n---\n")
+		if i%10 != 0 {
+			var err error
+			content, err = MarshalTask(&backend.Task{
+				ID:          id,
+				Title:       "Task " + id,
+				Priority:    priorities[i%len(priorities)],
+				Labels:      []string{"generated"},
+				Description: "Generated for the list benchmark.\n\n- [ ] first\n- [x] second",
+				Created:     created.Add(time.Duration(i%97) * time.Minute),
+				Updated:     created,
+			})
+			if err != nil {
+				tb.Fatal(err)
+			}
+		}
+		path := filepath.Join(backlogDir, string(statuses[i%len(statuses)]), id+"-task.md")
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return backlogDir
+}
+
+// withScanWorkers sets scanWorkers for the rest of the test, so the pool
+// runs concurrently even on a single CPU.
+func withScanWorkers(tb testing.TB, n int) {
+	tb.Helper()
+	old := scanWorkers
+	scanWorkers = n
+	tb.Cleanup(func() { scanWorkers = old })
+}
+
+func TestListConcurrentScanMatchesSequential(t *testing.T) {
+	backlogDir := writeTaskFixtures(t, 300)
+	filters := backend.TaskFilters{IncludeDone: true}
+
+	for _, ws := range []WorkspaceConfig{{DisableIndex: true}, {}} {
+		withScanWorkers(t, 1)
+		want, err := reconnect(t, backlogDir, ws).List(filters)
+		if err != nil {
+			t.Fatalf("List() sequential error = %v", err)
+		}
+
+		withScanWorkers(t, 8)
+		// The first list builds the index and the second reads it
+		for range 2 {
+			got, err := reconnect(t, backlogDir, ws).List(filters)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if !slices.Equal(taskIDs(got.Tasks), taskIDs(want.Tasks)) {
+				t.Errorf("List() order differs from a sequential scan (index disabled: %v)", ws.DisableIndex)
+			}
+			if !slices.Equal(got.Warnings, want.Warnings) {
+				t.Errorf("Warnings = %v, want %v", got.Warnings, want.Warnings)
+			}
+			if got.Count != 270 || got.Skipped != 30 {
+				t.Errorf("Count = %d, Skipped = %d; want 270 and 30", got.Count, got.Skipped)
+			}
+		}
+	}
+}
+
+func taskIDs(tasks []backend.Task) []string {
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+// BenchmarkList lists a 5000-task backlog without the index, so every file
+// is parsed, scanning sequentially and with the default worker pool.
+func BenchmarkList(b *testing.B) {
+	backlogDir := writeTaskFixtures(b, 5000)
+	filters := backend.TaskFilters{IncludeDone: true}
+
+	for _, bm := range []struct {
+		name    string
+		workers int
+	}{
+		{"sequential", 1},
+		{"concurrent", scanWorkers},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			withScanWorkers(b, bm.workers)
+			l := reconnect(b, backlogDir, WorkspaceConfig{DisableIndex: true})
+			for b.Loop() {
+				if _, err := l.List(filters); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
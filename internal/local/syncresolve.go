@@ -0,0 +1,676 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// unmergedFile is a file git left conflicted, with the blob of each stage
+// it has: 1 is the merge base, 2 the local side and 3 the remote side. A
+// missing stage means the file doesn't exist on that side.
+type unmergedFile struct {
+	path   string // relative to the directory containing the backlog
+	stages [4]string
+}
+
+// taskVersion is one side's version of a conflicted task, or nil where the
+// side deleted it.
+type taskVersion struct {
+	task *backend.Task
+	path string
+}
+
+// conflictedTask collects the conflicted files of one task. A task that
+// both sides moved to different status directories has a file for each.
+type conflictedTask struct {
+	id                 string
+	files              []string
+	base, ours, theirs *taskVersion
+}
+
+// taskResolution is how a conflicted task is written back: the merged
+// task, or nil to delete it.
+type taskResolution struct {
+	conflict *conflictedTask
+	task     *backend.Task
+}
+
+// abortPull abandons a conflicted pull, whether git ran it as a merge or a
+// rebase.
+func (l *Local) abortPull() {
+	l.runGit("rebase", "rebase", "--abort")
+	l.runGit("merge", "merge", "--abort")
+}
+
+// mergeUpstreamResolving merges the upstream branch after a pull conflicted
+// and resolves the conflicts in task files field by field: labels and
+// relations are united, the later updated time is kept, both sides'
+// comments are kept, and the status that moved further along the workflow
+// wins. Fields both sides changed differently, such as the title, and
+// conflicting files that aren't tasks are passed to choose. With a nil
+// choose the merge is aborted instead and a SyncConflictError lists every
+// unresolved field. The resolution is committed as "sync: resolve
+// conflicts in <ids>" and the resolved task IDs are returned.
+func (l *Local) mergeUpstreamResolving(choose backend.ConflictChooser) ([]string, error) {
+	untracked, err := l.untrackedFiles()
+	if err != nil {
+		return nil, err
+	}
+	abort := func() { l.abortMerge(untracked) }
+
+	output, err := l.runGit("merge", "merge", "--no-ff", "--no-edit", "--no-commit", "@{upstream}")
+	if err != nil && !strings.Contains(string(output), "CONFLICT") {
+		abort()
+		if isGitTimeout(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("git merge failed: %w\n%s", err, output)
+	}
+
+	resolved, err := l.resolveUnmerged(choose)
+	if err != nil {
+		abort()
+		return nil, err
+	}
+
+	message := "sync: merge remote changes"
+	if len(resolved) > 0 {
+		message = "sync: resolve conflicts in " + strings.Join(resolved, ", ")
+	}
+	if output, err := l.runGit("commit", "commit", "-m", message); err != nil {
+		abort()
+		if isGitTimeout(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("git commit failed: %w\n%s", err, output)
+	}
+	return resolved, nil
+}
+
+// untrackedFiles returns the untracked files in the repository, relative
+// to the directory containing the backlog.
+func (l *Local) untrackedFiles() (map[string]bool, error) {
+	output, err := l.gitOutput("ls-files", "ls-files", "--others", "--exclude-standard", "-z", "--", ":/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+	files := make(map[string]bool)
+	for _, path := range strings.Split(string(output), "\x00") {
+		if path != "" {
+			files[path] = true
+		}
+	}
+	return files, nil
+}
+
+// abortMerge aborts a merge and deletes the files it left untracked, which
+// git merge --abort keeps when a rename conflicted. Files in untracked were
+// there before the merge and are kept.
+func (l *Local) abortMerge(untracked map[string]bool) {
+	l.runGit("merge", "merge", "--abort")
+	after, err := l.untrackedFiles()
+	if err != nil {
+		return
+	}
+	root := filepath.Dir(l.path)
+	for path := range after {
+		if !untracked[path] {
+			os.Remove(filepath.Join(root, path))
+		}
+	}
+}
+
+// resolveUnmerged resolves and stages every conflicted file of an
+// in-progress merge. Nothing is written unless every conflict is resolved.
+// It returns the resolved task IDs, followed by the names of other resolved
+// files.
+func (l *Local) resolveUnmerged(choose backend.ConflictChooser) ([]string, error) {
+	files, err := l.unmergedFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, others, err := l.groupConflictedTasks(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var unresolved []backend.FieldConflict
+	var resolutions []taskResolution
+	for _, c := range tasks {
+		task, conflicts, err := resolveTask(c, choose)
+		if err != nil {
+			return nil, err
+		}
+		if len(conflicts) > 0 {
+			unresolved = append(unresolved, conflicts...)
+			continue
+		}
+		resolutions = append(resolutions, taskResolution{conflict: c, task: task})
+	}
+
+	otherSides := make([]backend.ConflictSide, len(others))
+	for i, f := range others {
+		conflict := backend.FieldConflict{
+			File:   f.path,
+			Field:  "content",
+			Ours:   sideState(f.stages[2]),
+			Theirs: sideState(f.stages[3]),
+		}
+		if choose == nil {
+			unresolved = append(unresolved, conflict)
+			continue
+		}
+		if otherSides[i], err = choose(conflict); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(unresolved) > 0 {
+		return nil, unresolvedConflictError(unresolved)
+	}
+
+	var resolved []string
+	for _, r := range resolutions {
+		if err := l.writeResolution(r); err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, r.conflict.id)
+	}
+	for i, f := range others {
+		if err := l.resolveFile(f, otherSides[i]); err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, filepath.Base(f.path))
+	}
+	return resolved, nil
+}
+
+// unresolvedConflictError reports the fields a sync couldn't merge.
+func unresolvedConflictError(conflicts []backend.FieldConflict) *SyncConflictError {
+	var parts []string
+	fields := make(map[string][]string)
+	for _, c := range conflicts {
+		name := c.TaskID
+		if name == "" {
+			name = c.File
+		}
+		if _, ok := fields[name]; !ok {
+			parts = append(parts, name)
+		}
+		fields[name] = append(fields[name], c.Field)
+	}
+	for i, name := range parts {
+		parts[i] = fmt.Sprintf("%s (%s)", name, strings.Join(fields[name], ", "))
+	}
+	return &SyncConflictError{
+		Operation: "pull",
+		Message:   "unresolved conflicts in " + strings.Join(parts, "; ") + "; the merge was aborted, rerun with --strategy ours, theirs, or interactive",
+		Conflicts: conflicts,
+	}
+}
+
+// sideState describes whether a conflicted file exists on one side.
+func sideState(blob string) string {
+	if blob == "" {
+		return "deleted"
+	}
+	return "modified"
+}
+
+// unmergedFiles lists the conflicted files of an in-progress merge.
+func (l *Local) unmergedFiles() ([]*unmergedFile, error) {
+	output, err := l.gitOutput("ls-files", "ls-files", "-u", "-z", "--", ":/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conflicted files: %w", err)
+	}
+
+	var files []*unmergedFile
+	byPath := make(map[string]*unmergedFile)
+	for _, record := range strings.Split(string(output), "\x00") {
+		// Each record is "<mode> <object> <stage>\t<path>"
+		info, path, ok := strings.Cut(record, "\t")
+		fields := strings.Fields(info)
+		if !ok || len(fields) != 3 {
+			continue
+		}
+		stage, err := strconv.Atoi(fields[2])
+		if err != nil || stage < 1 || stage > 3 {
+			continue
+		}
+		f := byPath[path]
+		if f == nil {
+			f = &unmergedFile{path: path}
+			byPath[path] = f
+			files = append(files, f)
+		}
+		f.stages[stage] = fields[1]
+	}
+	return files, nil
+}
+
+// groupConflictedTasks groups conflicted task files by task ID and reads
+// each side's version of the task from the commits being merged. The index
+// can't be used for that: for a task renamed differently on each side, git
+// stores the text merged with conflict markers. Files that aren't tasks are
+// returned separately.
+func (l *Local) groupConflictedTasks(files []*unmergedFile) ([]*conflictedTask, []*unmergedFile, error) {
+	base, _ := l.git("merge-base", "HEAD", "MERGE_HEAD")
+	revs := [4]string{1: base, 2: "HEAD", 3: "MERGE_HEAD"}
+
+	var tasks []*conflictedTask
+	var others, unread []*unmergedFile
+	byID := make(map[string]*conflictedTask)
+	for _, f := range files {
+		if _, ok := l.taskFileStatus(f.path); !ok {
+			others = append(others, f)
+			continue
+		}
+		id := ""
+		for _, rev := range []string{revs[2], revs[3], revs[1]} {
+			if v, err := l.readTaskAt(rev, f.path); err == nil {
+				id = v.task.ID
+				break
+			}
+		}
+		if id == "" {
+			unread = append(unread, f)
+			continue
+		}
+		if c := byID[id]; c != nil {
+			c.files = append(c.files, f.path)
+			continue
+		}
+		c := &conflictedTask{id: id, files: []string{f.path}}
+		byID[id] = c
+		tasks = append(tasks, c)
+	}
+
+	// A file in no commit is one git placed itself, such as a task renamed
+	// on one side into a directory the other side renamed, so it belongs to
+	// the task its name matches
+	for _, f := range unread {
+		name := strings.TrimSuffix(filepath.Base(f.path), ".md")
+		var owner *conflictedTask
+		for _, c := range tasks {
+			if taskFileMatchesID(name, c.id) {
+				owner = c
+				break
+			}
+		}
+		if owner == nil {
+			others = append(others, f)
+			continue
+		}
+		owner.files = append(owner.files, f.path)
+	}
+
+	for _, c := range tasks {
+		versions := [4]*taskVersion{}
+		for stage := 1; stage <= 3; stage++ {
+			if revs[stage] == "" {
+				continue
+			}
+			v, err := l.findTaskAt(revs[stage], c.id)
+			if err != nil {
+				return nil, nil, err
+			}
+			versions[stage] = v
+		}
+		c.base, c.ours, c.theirs = versions[1], versions[2], versions[3]
+	}
+	return tasks, others, nil
+}
+
+// findTaskAt returns the version of a task in a commit, or nil if the
+// commit has no file for it in a status directory.
+func (l *Local) findTaskAt(rev, id string) (*taskVersion, error) {
+	list, err := l.git("ls-tree", "-r", "--name-only", rev, "--", filepath.Base(l.path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks in %s: %w", rev, err)
+	}
+	for _, path := range strings.Split(list, "\n") {
+		if _, ok := l.taskFileStatus(path); !ok || !taskFileMatchesID(strings.TrimSuffix(filepath.Base(path), ".md"), id) {
+			continue
+		}
+		v, err := l.readTaskAt(rev, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s in %s: %w", path, rev, err)
+		}
+		if v.task.ID == id {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+// readTaskAt reads the task file at path, relative to the directory
+// containing the backlog, in a commit.
+func (l *Local) readTaskAt(rev, path string) (*taskVersion, error) {
+	content, err := l.git("show", rev+":./"+path)
+	if err != nil {
+		return nil, err
+	}
+	task, err := UnmarshalTask([]byte(content))
+	if err != nil {
+		return nil, err
+	}
+	task.Status, _ = l.taskFileStatus(path)
+	return &taskVersion{task: task, path: path}, nil
+}
+
+// taskFileStatus reports whether path, relative to the directory containing
+// the backlog, is a task file in a status directory, and which status.
+func (l *Local) taskFileStatus(path string) (backend.Status, bool) {
+	if filepath.Ext(path) != ".md" {
+		return "", false
+	}
+	dir := filepath.Dir(filepath.Clean(path))
+	status := backend.Status(filepath.Base(dir))
+	if !status.IsValid() || filepath.Dir(dir) != filepath.Base(l.path) {
+		return "", false
+	}
+	return status, true
+}
+
+// resolveTask merges a conflicted task's sides. It returns the task to
+// write, or nil if the chosen side deleted it, and the conflicts left
+// unresolved because choose is nil.
+func resolveTask(c *conflictedTask, choose backend.ConflictChooser) (*backend.Task, []backend.FieldConflict, error) {
+	if c.ours == nil || c.theirs == nil {
+		conflict := backend.FieldConflict{
+			TaskID: c.id,
+			File:   c.files[0],
+			Field:  "deleted",
+			Ours:   strconv.FormatBool(c.ours == nil),
+			Theirs: strconv.FormatBool(c.theirs == nil),
+		}
+		if choose == nil {
+			return nil, []backend.FieldConflict{conflict}, nil
+		}
+		side, err := choose(conflict)
+		if err != nil {
+			return nil, nil, err
+		}
+		kept := c.ours
+		if side == backend.ConflictTheirs {
+			kept = c.theirs
+		}
+		if kept == nil {
+			return nil, nil, nil
+		}
+		return kept.task, nil, nil
+	}
+
+	var base *backend.Task
+	if c.base != nil {
+		base = c.base.task
+	}
+	merged, fields := mergeTask(base, c.ours.task, c.theirs.task)
+
+	var unresolved []backend.FieldConflict
+	for _, field := range fields {
+		conflict := backend.FieldConflict{
+			TaskID: c.id,
+			File:   c.ours.path,
+			Field:  field.name,
+			Ours:   field.get(c.ours.task),
+			Theirs: field.get(c.theirs.task),
+		}
+		if choose == nil {
+			unresolved = append(unresolved, conflict)
+			continue
+		}
+		side, err := choose(conflict)
+		if err != nil {
+			return nil, nil, err
+		}
+		if side == backend.ConflictTheirs {
+			field.set(merged, c.theirs.task)
+		}
+	}
+	return merged, unresolved, nil
+}
+
+// writeResolution replaces a conflicted task's files with the resolved task
+// and stages the result. Besides the conflicted files, any file the merge
+// left for the task in a status directory is replaced, such as one git
+// moved along with a directory rename.
+func (l *Local) writeResolution(r taskResolution) error {
+	root := filepath.Dir(l.path)
+	paths := append([]string{}, r.conflict.files...)
+	for _, status := range backend.ValidStatuses() {
+		matches, _ := filepath.Glob(filepath.Join(l.path, string(status), "*.md"))
+		for _, match := range matches {
+			if taskFileMatchesID(strings.TrimSuffix(filepath.Base(match), ".md"), r.conflict.id) {
+				rel, _ := filepath.Rel(root, match)
+				paths = append(paths, rel)
+			}
+		}
+	}
+
+	args := append([]string{"rm", "--cached", "--quiet", "--ignore-unmatch", "--"}, paths...)
+	if output, err := l.runGit("rm", args...); err != nil {
+		return fmt.Errorf("git rm failed: %w\n%s", err, output)
+	}
+	for _, path := range paths {
+		if err := os.Remove(filepath.Join(root, path)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove conflicted file: %w", err)
+		}
+	}
+
+	l.forgetIndexedTask(r.conflict.id)
+	if r.task == nil {
+		return nil
+	}
+	if err := l.writeTask(r.task); err != nil {
+		return err
+	}
+	path := filepath.Join(filepath.Base(l.path), string(r.task.Status), generateFilename(r.task.ID, r.task.Title))
+	if output, err := l.runGit("add", "add", "--", path); err != nil {
+		return fmt.Errorf("git add failed: %w\n%s", err, output)
+	}
+	return nil
+}
+
+// resolveFile resolves a conflicted file that isn't a task by taking one
+// side as a whole.
+func (l *Local) resolveFile(f *unmergedFile, side backend.ConflictSide) error {
+	stage := 2
+	if side == backend.ConflictTheirs {
+		stage = 3
+	}
+
+	var args []string
+	if f.stages[stage] == "" {
+		args = []string{"rm", "--quiet", "--", f.path}
+	} else {
+		args = []string{"checkout", "--" + string(side), "--", f.path}
+	}
+	if output, err := l.runGit(args[0], args...); err != nil {
+		return fmt.Errorf("git %s failed: %w\n%s", args[0], err, output)
+	}
+	if f.stages[stage] != "" {
+		if output, err := l.runGit("add", "add", "--", f.path); err != nil {
+			return fmt.Errorf("git add failed: %w\n%s", err, output)
+		}
+	}
+	return nil
+}
+
+// mergeField is a task field merged by three-way comparison: a side that
+// left it as it was in the merge base takes the other side's change, and
+// differing changes on both sides conflict.
+type mergeField struct {
+	name string
+	get  func(t *backend.Task) string
+	set  func(dst, src *backend.Task)
+}
+
+var mergeFields = []mergeField{
+	{"title", func(t *backend.Task) string { return t.Title }, func(d, s *backend.Task) { d.Title = s.Title }},
+	{"description", func(t *backend.Task) string { return t.Description }, func(d, s *backend.Task) { d.Description = s.Description }},
+	{"priority", func(t *backend.Task) string { return string(t.Priority) }, func(d, s *backend.Task) { d.Priority = s.Priority }},
+	{"assignee", func(t *backend.Task) string { return t.Assignee }, func(d, s *backend.Task) { d.Assignee = s.Assignee }},
+	{"parent", func(t *backend.Task) string { return t.Parent }, func(d, s *backend.Task) { d.Parent = s.Parent }},
+	{"sort_order", func(t *backend.Task) string { return strconv.FormatFloat(t.SortOrder, 'g', -1, 64) }, func(d, s *backend.Task) { d.SortOrder = s.SortOrder }},
+	{"estimate", func(t *backend.Task) string { return t.Estimate.String() }, func(d, s *backend.Task) { d.Estimate = s.Estimate }},
+	{"spent", func(t *backend.Task) string { return t.Spent.String() }, func(d, s *backend.Task) { d.Spent = s.Spent }},
+	{"created", func(t *backend.Task) string { return t.Created.Format(time.RFC3339) }, func(d, s *backend.Task) { d.Created = s.Created }},
+	{"url", func(t *backend.Task) string { return t.URL }, func(d, s *backend.Task) { d.URL = s.URL }},
+	{"deleted_at", func(t *backend.Task) string { return timeString(t.DeletedAt) }, func(d, s *backend.Task) { d.DeletedAt = s.DeletedAt }},
+	{"extra", func(t *backend.Task) string {
+		if extra := metaExtra(t); len(extra) > 0 {
+			return fmt.Sprint(extra)
+		}
+		return ""
+	}, func(d, s *backend.Task) { setMetaExtra(d, metaExtra(s)) }},
+}
+
+// mergeTask merges two sides of a task changed since base, which is nil if
+// both sides added it. It returns the merged task and the fields both sides
+// changed differently, which are left at the local side's value.
+func mergeTask(base, ours, theirs *backend.Task) (*backend.Task, []mergeField) {
+	merged := *ours
+	merged.Meta = nil
+	comments := mergeComments(metaComments(ours), metaComments(theirs))
+	blocks := unionStrings(metaStrings(ours, "blocks"), metaStrings(theirs, "blocks"))
+	blockedBy := unionStrings(metaStrings(ours, "blocked_by"), metaStrings(theirs, "blocked_by"))
+	setTaskMeta(&merged, comments, blocks, blockedBy, metaExtra(ours))
+	oursLast, _ := ours.Meta[lastCommentIDMetaKey].(string)
+	theirsLast, _ := theirs.Meta[lastCommentIDMetaKey].(string)
+	if last := max(commentNumber(oursLast), commentNumber(theirsLast)); last > highestCommentNumber(comments) {
+		setLastCommentID(&merged, strconv.Itoa(last))
+	}
+
+	var conflicts []mergeField
+	for _, field := range mergeFields {
+		o, t := field.get(ours), field.get(theirs)
+		switch {
+		case o == t:
+		case base != nil && o == field.get(base):
+			field.set(&merged, theirs)
+		case base != nil && t == field.get(base):
+		default:
+			conflicts = append(conflicts, field)
+		}
+	}
+
+	// Status moves forward through the workflow, so the side further along
+	// wins, along with its completion time
+	statusSide := ours
+	switch {
+	case ours.Status == theirs.Status:
+	case base != nil && ours.Status == base.Status:
+		statusSide = theirs
+	case base != nil && theirs.Status == base.Status:
+	case statusRank(theirs.Status) > statusRank(ours.Status):
+		statusSide = theirs
+	}
+	merged.Status = statusSide.Status
+	merged.CompletedAt = statusSide.CompletedAt
+	if ours.Status == theirs.Status {
+		merged.CompletedAt = laterTime(ours.CompletedAt, theirs.CompletedAt)
+	}
+	merged.StartedAt = earlierTime(ours.StartedAt, theirs.StartedAt)
+
+	merged.Labels = unionStrings(ours.Labels, theirs.Labels)
+	if theirs.Updated.After(ours.Updated) {
+		merged.Updated = theirs.Updated
+	}
+	return &merged, conflicts
+}
+
+// statusRank returns a status's position in the workflow.
+func statusRank(s backend.Status) int {
+	return slices.Index(backend.ValidStatuses(), s)
+}
+
+// unionStrings returns a's values followed by those of b that a lacks.
+func unionStrings(a, b []string) []string {
+	var union []string
+	for _, s := range append(append([]string{}, a...), b...) {
+		if !slices.Contains(union, s) {
+			union = append(union, s)
+		}
+	}
+	return union
+}
+
+// mergeComments returns ours followed by the comments of theirs that ours
+// lacks. Comments are the same if they have the same ID, or without IDs
+// the same author, date and body.
+func mergeComments(ours, theirs []backend.Comment) []backend.Comment {
+	key := func(c backend.Comment) string {
+		if c.ID != "" {
+			return c.ID
+		}
+		return c.Author + "\x00" + c.Created.Format(time.DateOnly) + "\x00" + c.Body
+	}
+	seen := make(map[string]bool)
+	merged := append([]backend.Comment{}, ours...)
+	for _, c := range ours {
+		seen[key(c)] = true
+	}
+	for _, c := range theirs {
+		if !seen[key(c)] {
+			merged = append(merged, c)
+		}
+	}
+	return merged
+}
+
+func metaComments(t *backend.Task) []backend.Comment {
+	comments, _ := t.Meta["comments"].([]backend.Comment)
+	return comments
+}
+
+func metaStrings(t *backend.Task, key string) []string {
+	values, _ := t.Meta[key].([]string)
+	return values
+}
+
+func metaExtra(t *backend.Task) map[string]any {
+	extra, _ := t.Meta[extraMetaKey].(map[string]any)
+	return extra
+}
+
+func setMetaExtra(t *backend.Task, extra map[string]any) {
+	if len(extra) == 0 {
+		delete(t.Meta, extraMetaKey)
+		return
+	}
+	if t.Meta == nil {
+		t.Meta = make(map[string]any)
+	}
+	t.Meta[extraMetaKey] = extra
+}
+
+func timeString(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func earlierTime(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.Before(*a)) {
+		return b
+	}
+	return a
+}
+
+func laterTime(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.After(*a)) {
+		return b
+	}
+	return a
+}
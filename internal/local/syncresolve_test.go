@@ -0,0 +1,220 @@
+package local
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+func TestMergeTask(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	base := &backend.Task{
+		ID: "001", Title: "Fix login", Description: "Original", Status: backend.StatusTodo,
+		Priority: backend.PriorityHigh, Labels: []string{"bug"}, Updated: day(1),
+	}
+	ours := *base
+	ours.Labels = []string{"bug", "auth"}
+	ours.Priority = backend.PriorityUrgent
+	ours.Updated = day(3)
+	ours.Meta = map[string]any{"comments": []backend.Comment{{Author: "alex", Body: "Local note", Created: day(3)}}}
+	theirs := *base
+	theirs.Labels = []string{"bug", "agent:claude-1"}
+	theirs.Status = backend.StatusInProgress
+	theirs.Description = "Remote description"
+	theirs.Updated = day(2)
+	theirs.Meta = map[string]any{"comments": []backend.Comment{{Author: "claude-1", Body: "Remote note", Created: day(2)}}}
+
+	merged, conflicts := mergeTask(base, &ours, &theirs)
+	if len(conflicts) != 0 {
+		t.Fatalf("conflicts = %v, want none", conflicts)
+	}
+	if want := []string{"bug", "auth", "agent:claude-1"}; !reflect.DeepEqual(merged.Labels, want) {
+		t.Errorf("Labels = %v, want %v", merged.Labels, want)
+	}
+	if merged.Status != backend.StatusInProgress {
+		t.Errorf("Status = %s, want in-progress", merged.Status)
+	}
+	if merged.Priority != backend.PriorityUrgent || merged.Description != "Remote description" {
+		t.Errorf("Priority, Description = %s, %q; want each side's change", merged.Priority, merged.Description)
+	}
+	if !merged.Updated.Equal(day(3)) {
+		t.Errorf("Updated = %v, want the later %v", merged.Updated, day(3))
+	}
+	if comments := metaComments(merged); len(comments) != 2 || comments[0].Body != "Local note" || comments[1].Body != "Remote note" {
+		t.Errorf("comments = %+v, want the local then the remote comment", comments)
+	}
+	if metaComments(&ours)[0].Body != "Local note" || len(metaComments(&ours)) != 1 {
+		t.Error("mergeTask modified the local side's comments")
+	}
+
+	// Both sides changed the title and moved the status
+	ours.Title = "Fix login flow"
+	ours.Status = backend.StatusReview
+	theirs.Title = "Fix the login page"
+	merged, conflicts = mergeTask(base, &ours, &theirs)
+	if len(conflicts) != 1 || conflicts[0].name != "title" {
+		t.Fatalf("conflicts = %v, want title", conflicts)
+	}
+	if merged.Title != "Fix login flow" {
+		t.Errorf("Title = %q, want the local side's until resolved", merged.Title)
+	}
+	if merged.Status != backend.StatusReview {
+		t.Errorf("Status = %s, want review, the further along", merged.Status)
+	}
+
+	// Without a merge base any difference conflicts
+	_, conflicts = mergeTask(nil, &ours, &theirs)
+	var names []string
+	for _, c := range conflicts {
+		names = append(names, c.name)
+	}
+	if want := []string{"title", "description", "priority"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("conflicts without base = %v, want %v", names, want)
+	}
+}
+
+// setupConflictingSync creates a backlog whose task 001 was changed both
+// locally and, along with a move to in-progress, on its remote. It returns
+// the local backlog and its repository.
+func setupConflictingSync(t *testing.T, local, remote func(*backend.Task) backend.TaskChanges) (*Local, string) {
+	t.Helper()
+	remoteDir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", "--bare", "-b", "main", remoteDir).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v\n%s", err, out)
+	}
+	l, repoDir := setupGitBacklog(t, remoteDir, 0)
+	git := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git(repoDir, "config", "pull.rebase", "true")
+	if _, err := l.Create(backend.TaskInput{Title: "Fix login", Description: "Original", Labels: []string{"bug"}}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	git(repoDir, "push", "-q", "origin", "main")
+
+	// Change the task on each side without git sync, then commit by hand
+	change := func(dir string, changes func(*backend.Task) backend.TaskChanges, status backend.Status) {
+		t.Helper()
+		side := New()
+		if err := side.Connect(backend.Config{Workspace: &WorkspaceConfig{Path: filepath.Join(dir, ".backlog")}}); err != nil {
+			t.Fatalf("Connect() error = %v", err)
+		}
+		task, err := side.Get("001")
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if _, err := side.Update("001", changes(task)); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if status != "" {
+			if _, err := side.Move("001", status); err != nil {
+				t.Fatalf("Move() error = %v", err)
+			}
+		}
+		git(dir, "-c", "user.email=test@example.com", "-c", "user.name=Test", "add", "-A")
+		git(dir, "-c", "user.email=test@example.com", "-c", "user.name=Test", "commit", "-q", "-m", "edit: 001")
+	}
+
+	other := t.TempDir()
+	git(other, "clone", "-q", remoteDir, ".")
+	change(other, remote, backend.StatusInProgress)
+	git(other, "push", "-q", "origin", "main")
+	change(repoDir, local, "")
+	return l, repoDir
+}
+
+func TestSyncResolvesTaskConflicts(t *testing.T) {
+	l, repoDir := setupConflictingSync(t,
+		func(*backend.Task) backend.TaskChanges {
+			return backend.TaskChanges{AddLabels: []string{"auth"}}
+		},
+		func(*backend.Task) backend.TaskChanges {
+			return backend.TaskChanges{AddLabels: []string{"agent:other"}}
+		})
+
+	result, err := l.Sync(false)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if !reflect.DeepEqual(result.Resolved, []string{"001"}) {
+		t.Errorf("Resolved = %v, want [001]", result.Resolved)
+	}
+
+	task, err := l.Get("001")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if want := []string{"auth", "bug", "agent:other"}; !reflect.DeepEqual(task.Labels, want) {
+		t.Errorf("Labels = %v, want %v", task.Labels, want)
+	}
+	if task.Status != backend.StatusInProgress {
+		t.Errorf("Status = %s, want in-progress", task.Status)
+	}
+
+	out, err := exec.Command("git", "-C", repoDir, "log", "-1", "--format=%s").Output()
+	if err != nil || strings.TrimSpace(string(out)) != "sync: resolve conflicts in 001" {
+		t.Errorf("last commit = %q, %v; want the resolution commit", out, err)
+	}
+	if out, _ := exec.Command("git", "-C", repoDir, "status", "--porcelain").Output(); len(out) > 0 {
+		t.Errorf("working tree not clean after sync:\n%s", out)
+	}
+}
+
+func TestSyncAbortsUnresolvedConflicts(t *testing.T) {
+	retitle := func(title string) func(*backend.Task) backend.TaskChanges {
+		return func(*backend.Task) backend.TaskChanges { return backend.TaskChanges{Title: &title} }
+	}
+	l, repoDir := setupConflictingSync(t, retitle("Fix login flow"), retitle("Fix the login page"))
+	head, _ := exec.Command("git", "-C", repoDir, "rev-parse", "HEAD").Output()
+
+	_, err := l.Sync(false)
+	var conflictErr *SyncConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("Sync() error = %v, want a SyncConflictError", err)
+	}
+	if len(conflictErr.Conflicts) != 1 || conflictErr.Conflicts[0].TaskID != "001" || conflictErr.Conflicts[0].Field != "title" {
+		t.Errorf("Conflicts = %+v, want the title of 001", conflictErr.Conflicts)
+	}
+	if !strings.Contains(err.Error(), "001 (title)") {
+		t.Errorf("error = %q, want it to name the task and field", err)
+	}
+	after, _ := exec.Command("git", "-C", repoDir, "rev-parse", "HEAD").Output()
+	if string(after) != string(head) {
+		t.Error("HEAD moved after an aborted sync")
+	}
+	if out, _ := exec.Command("git", "-C", repoDir, "status", "--porcelain").Output(); len(out) > 0 {
+		t.Errorf("working tree not clean after an aborted sync:\n%s", out)
+	}
+
+	// Choosing the remote side resolves it
+	result, err := l.SyncResolving(false, func(c backend.FieldConflict) (backend.ConflictSide, error) {
+		return backend.ConflictTheirs, nil
+	})
+	if err != nil {
+		t.Fatalf("SyncResolving() error = %v", err)
+	}
+	if result.Conflicts != 1 {
+		t.Errorf("Conflicts = %d, want 1", result.Conflicts)
+	}
+	if task, err := l.Get("001"); err != nil || task.Title != "Fix the login page" {
+		t.Errorf("Get() = %v, %v; want the remote title", task, err)
+	}
+	files, _ := filepath.Glob(filepath.Join(repoDir, ".backlog", "*", "001-*.md"))
+	if len(files) != 1 || filepath.Base(filepath.Dir(files[0])) != "in-progress" {
+		t.Errorf("task files = %v, want one in in-progress", files)
+	}
+	if out, _ := exec.Command("git", "-C", repoDir, "status", "--porcelain").Output(); len(out) > 0 {
+		t.Errorf("working tree not clean after sync:\n%s", out)
+	}
+}
@@ -0,0 +1,188 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"gopkg.in/yaml.v3"
+)
+
+// SyncStatus fetches from the remote and reports how the backlog has
+// diverged from its upstream branch: commits ahead and behind, uncommitted
+// changes, the remote URL, and the tasks a pull would update, add, or remove. Nothing
+// is merged, rebased, or pushed. Without a remote or an upstream branch the
+// status is returned with a message instead of an error.
+func (l *Local) SyncStatus() (*backend.SyncStatus, error) {
+	if !l.connected {
+		return nil, errors.New("not connected")
+	}
+
+	gitDir := filepath.Dir(l.path)
+	status := &backend.SyncStatus{Updated: []string{}, Added: []string{}, Removed: []string{}}
+
+	if _, err := l.git("rev-parse", "--git-dir"); err != nil {
+		return nil, fmt.Errorf("%s is not in a git repository", gitDir)
+	}
+
+	dirty, err := l.hasUncommittedChanges()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for uncommitted changes: %w", err)
+	}
+	status.Dirty = dirty
+
+	remotes, err := l.git("remote")
+	if err != nil || remotes == "" {
+		status.InSync = true
+		status.Message = "no remote configured"
+		return status, nil
+	}
+
+	remote := defaultRemote(strings.Fields(remotes))
+	upstream, err := l.git("rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+	if err == nil {
+		status.Upstream = upstream
+		remote, _, _ = strings.Cut(upstream, "/")
+	}
+	// The URL is informational; a remote without one is still compared
+	status.Remote, _ = l.git("remote", "get-url", remote)
+	if status.Upstream == "" {
+		status.InSync = true
+		status.Message = "no upstream branch configured"
+		return status, nil
+	}
+
+	status.Ahead, status.Behind, err = l.remoteDivergence()
+	if err != nil {
+		return nil, err
+	}
+	status.InSync = status.Ahead == 0 && status.Behind == 0
+
+	if status.Behind > 0 {
+		// Three-dot diff compares the merge base with the upstream: what a pull brings in
+		diff, err := l.git("diff", "--name-status", "--no-renames", "--relative", "HEAD...@{upstream}", "--", filepath.Base(l.path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to diff with %s: %w", upstream, err)
+		}
+		base, err := l.git("merge-base", "HEAD", "@{upstream}")
+		if err != nil {
+			return nil, fmt.Errorf("failed to find merge base with %s: %w", upstream, err)
+		}
+		changes := parseNameStatus(diff, filepath.Base(l.path))
+		for i := range changes {
+			rev := "@{upstream}"
+			if changes[i].change == 'D' {
+				rev = base
+			}
+			changes[i].id = l.taskIDAt(rev, changes[i].path)
+		}
+		status.Updated, status.Added, status.Removed = classifyTaskChanges(changes)
+	}
+
+	return status, nil
+}
+
+// defaultRemote picks the remote a branch without an upstream would most
+// likely track: origin if it exists, otherwise the first one.
+func defaultRemote(remotes []string) string {
+	for _, r := range remotes {
+		if r == "origin" {
+			return r
+		}
+	}
+	return remotes[0]
+}
+
+// taskFileChange is a task file touched by a diff.
+type taskFileChange struct {
+	change byte // A, M, or D
+	path   string
+	id     string
+}
+
+// parseNameStatus parses "git diff --name-status --no-renames" output,
+// keeping task files directly inside a status directory of backlogDir.
+func parseNameStatus(output, backlogDir string) []taskFileChange {
+	var changes []taskFileChange
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.Split(strings.TrimSpace(line), "\t")
+		if len(parts) != 2 || parts[0] == "" || !strings.HasSuffix(parts[1], ".md") {
+			continue
+		}
+		dir, _ := filepath.Split(parts[1])
+		if status := backend.Status(filepath.Base(dir)); !status.IsValid() || filepath.Dir(filepath.Clean(dir)) != backlogDir {
+			continue
+		}
+		changes = append(changes, taskFileChange{change: parts[0][0], path: parts[1]})
+	}
+	return changes
+}
+
+// classifyTaskChanges groups file changes by task. A task whose file was
+// both removed and added, as happens when it moves between status
+// directories, counts as updated.
+func classifyTaskChanges(changes []taskFileChange) (updated, added, removed []string) {
+	seen := make(map[string]map[byte]bool)
+	for _, c := range changes {
+		if seen[c.id] == nil {
+			seen[c.id] = make(map[byte]bool)
+		}
+		seen[c.id][c.change] = true
+	}
+
+	updated, added, removed = []string{}, []string{}, []string{}
+	for id, kinds := range seen {
+		switch {
+		case kinds['M'] || (kinds['A'] && kinds['D']):
+			updated = append(updated, id)
+		case kinds['A']:
+			added = append(added, id)
+		case kinds['D']:
+			removed = append(removed, id)
+		}
+	}
+	sort.Strings(updated)
+	sort.Strings(added)
+	sort.Strings(removed)
+	return updated, added, removed
+}
+
+// taskIDAt returns the ID of the task stored at path in the given revision,
+// falling back to the filename when the file can't be read or parsed.
+func (l *Local) taskIDAt(rev, path string) string {
+	fallback, _, _ := strings.Cut(strings.TrimSuffix(filepath.Base(path), ".md"), "-")
+
+	content, err := l.git("show", rev+":./"+path)
+	if err != nil {
+		return fallback
+	}
+	frontmatter, _, err := parseFrontmatter([]byte(content))
+	if err != nil {
+		return fallback
+	}
+	var fm struct {
+		ID string `yaml:"id"`
+	}
+	if err := yaml.Unmarshal(frontmatter, &fm); err != nil || fm.ID == "" {
+		return fallback
+	}
+	return fm.ID
+}
+
+// git runs a read-only git command next to the backlog and returns its
+// trimmed output.
+func (l *Local) git(args ...string) (string, error) {
+	output, err := l.gitOutput(args[0], args...)
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", errors.New(strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
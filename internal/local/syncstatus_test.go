@@ -0,0 +1,120 @@
+package local
+
+import (
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+func TestParseNameStatus(t *testing.T) {
+	output := "M\t.backlog/todo/001-fix-login.md\n" +
+		"A\t.backlog/in-progress/002-add-search.md\n" +
+		"D\t.backlog/todo/002-add-search.md\n" +
+		"A\t.backlog/.idempotency\n" +
+		"A\t.backlog/templates/bug.md\n" +
+		"M\tdocs/todo/readme.md\n"
+
+	changes := parseNameStatus(output, ".backlog")
+	want := []taskFileChange{
+		{change: 'M', path: ".backlog/todo/001-fix-login.md"},
+		{change: 'A', path: ".backlog/in-progress/002-add-search.md"},
+		{change: 'D', path: ".backlog/todo/002-add-search.md"},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("parseNameStatus() = %+v, want %+v", changes, want)
+	}
+}
+
+func TestClassifyTaskChanges(t *testing.T) {
+	changes := []taskFileChange{
+		{change: 'M', id: "001"},
+		{change: 'D', id: "002"},
+		{change: 'A', id: "002"},
+		{change: 'A', id: "004"},
+		{change: 'A', id: "003"},
+		{change: 'D', id: "005"},
+	}
+
+	updated, added, removed := classifyTaskChanges(changes)
+	if want := []string{"001", "002"}; !reflect.DeepEqual(updated, want) {
+		t.Errorf("updated = %v, want %v", updated, want)
+	}
+	if want := []string{"003", "004"}; !reflect.DeepEqual(added, want) {
+		t.Errorf("added = %v, want %v", added, want)
+	}
+	if want := []string{"005"}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("removed = %v, want %v", removed, want)
+	}
+}
+
+func TestSyncStatusDivergence(t *testing.T) {
+	remote := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", "--bare", "-b", "main", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v\n%s", err, out)
+	}
+	l, repoDir := setupGitBacklog(t, remote, 0)
+	git := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git(repoDir, "push", "-q", "origin", "main")
+
+	// One commit only on the remote, one only local
+	other := t.TempDir()
+	git(other, "clone", "-q", remote, ".")
+	git(other, "-c", "user.email=other@example.com", "-c", "user.name=Other", "commit", "-q", "--allow-empty", "-m", "remote change")
+	git(other, "push", "-q", "origin", "main")
+	if _, err := l.Create(backend.TaskInput{Title: "Local change"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	ahead, behind, err := l.remoteDivergence()
+	if err != nil || ahead != 1 || behind != 1 {
+		t.Errorf("remoteDivergence() = %d, %d, %v; want 1, 1, nil", ahead, behind, err)
+	}
+	if remoteAhead, err := l.isRemoteAhead(); err != nil || !remoteAhead {
+		t.Errorf("isRemoteAhead() = %v, %v; want true", remoteAhead, err)
+	}
+
+	writeTestFile(t, filepath.Join(repoDir, "notes.txt"), "not a task")
+	status, err := l.SyncStatus()
+	if err != nil {
+		t.Fatalf("SyncStatus() error = %v", err)
+	}
+	if status.Ahead != 1 || status.Behind != 1 || status.InSync {
+		t.Errorf("SyncStatus() = %d ahead, %d behind, in sync %v; want 1, 1, false", status.Ahead, status.Behind, status.InSync)
+	}
+	if !status.Dirty {
+		t.Error("Dirty = false, want true with an uncommitted file")
+	}
+	if status.Remote != remote || status.Upstream != "origin/main" {
+		t.Errorf("Remote = %q, Upstream = %q; want %q, %q", status.Remote, status.Upstream, remote, "origin/main")
+	}
+}
+
+func TestSyncStatusWithoutUpstream(t *testing.T) {
+	l, repoDir := setupGitBacklog(t, "https://example.invalid/backlog.git", 0)
+	cmd := exec.Command("git", "config", "--unset", "branch.main.remote")
+	cmd.Dir = repoDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git config: %v\n%s", err, out)
+	}
+
+	status, err := l.SyncStatus()
+	if err != nil {
+		t.Fatalf("SyncStatus() error = %v", err)
+	}
+	if status.Message != "no upstream branch configured" || !status.InSync {
+		t.Errorf("SyncStatus() = %+v, want in sync with a message", status)
+	}
+	if status.Remote != "https://example.invalid/backlog.git" {
+		t.Errorf("Remote = %q, want the origin URL", status.Remote)
+	}
+}
@@ -3,10 +3,12 @@ package local
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,18 +18,66 @@ import (
 
 // taskFrontmatter represents the YAML frontmatter of a task file.
 type taskFrontmatter struct {
-	ID        string           `yaml:"id"`
-	Title     string           `yaml:"title"`
-	Priority  backend.Priority `yaml:"priority,omitempty"`
-	Assignee  string           `yaml:"assignee,omitempty"`
-	Labels    []string         `yaml:"labels,omitempty"`
-	Blocks    []string         `yaml:"blocks,omitempty"`
-	BlockedBy []string         `yaml:"blocked_by,omitempty"`
-	SortOrder float64          `yaml:"sort_order,omitempty"`
-	Created   time.Time        `yaml:"created"`
-	Updated   time.Time        `yaml:"updated"`
+	ID          string           `yaml:"id"`
+	Title       string           `yaml:"title"`
+	Priority    backend.Priority `yaml:"priority,omitempty"`
+	Assignee    string           `yaml:"assignee,omitempty"`
+	Labels      []string         `yaml:"labels,omitempty"`
+	Parent      string           `yaml:"parent,omitempty"`
+	Blocks      []string         `yaml:"blocks,omitempty"`
+	BlockedBy   []string         `yaml:"blocked_by,omitempty"`
+	SortOrder   float64          `yaml:"sort_order,omitempty"`
+	Estimate    backend.Duration `yaml:"estimate,omitempty"`
+	Spent       backend.Duration `yaml:"spent,omitempty"`
+	Created     time.Time        `yaml:"created"`
+	Updated     time.Time        `yaml:"updated"`
+	URL         string           `yaml:"url,omitempty"`
+	DeletedAt   *time.Time       `yaml:"deleted_at,omitempty"`
+	StartedAt   *time.Time       `yaml:"started_at,omitempty"`
+	CompletedAt *time.Time       `yaml:"completed_at,omitempty"`
+
+	// Extra holds frontmatter keys the tool does not know about, such as
+	// hand-added metadata, so they survive rewrites of the file.
+	Extra map[string]any `yaml:",inline"`
 }
 
+// extraMetaKey is the Task.Meta key holding unknown frontmatter fields.
+const extraMetaKey = "extra"
+
+// ParseError is returned when a task file cannot be parsed.
+type ParseError struct {
+	// Path is the path to the task file.
+	Path string
+	// Line is the 1-based line in the file where parsing failed, or 0 if
+	// unknown. For YAML syntax errors this is the parser's best guess.
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	if e.Path == "" {
+		if e.Line > 0 {
+			return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+		}
+		return e.Err.Error()
+	}
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %v", e.Path, e.Line, e.Err)
+	}
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// yamlLineRe matches the line number in yaml.v3 error messages.
+var yamlLineRe = regexp.MustCompile(`line (\d+)`)
+
+// maxLineSize is the longest line accepted in a task file. Descriptions
+// may contain long generated lines, so this is well above bufio's default.
+const maxLineSize = 1024 * 1024
+
 // readTaskFile reads a task from a markdown file with YAML frontmatter.
 func (l *Local) readTaskFile(filePath string, status backend.Status) (*backend.Task, error) {
 	content, err := os.ReadFile(filePath)
@@ -35,30 +85,62 @@ func (l *Local) readTaskFile(filePath string, status backend.Status) (*backend.T
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
+	task, err := UnmarshalTask(content)
+	if err != nil {
+		var parseErr *ParseError
+		if errors.As(err, &parseErr) {
+			parseErr.Path = filePath
+		}
+		return nil, err
+	}
+	task.Status = status
+	backend.SetDuration(task, time.Now().UTC())
+	backend.SetChecklist(task)
+
+	return task, nil
+}
+
+// UnmarshalTask parses a task from content in the task file format. The
+// status is not part of the format and is left empty. Errors are returned as
+// a *ParseError without a Path.
+func UnmarshalTask(content []byte) (*backend.Task, error) {
 	frontmatter, body, err := parseFrontmatter(content)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+		// Delimiter problems are reported against the opening line
+		return nil, &ParseError{Line: 1, Err: fmt.Errorf("failed to parse frontmatter: %w", err)}
 	}
 
 	var fm taskFrontmatter
 	if err := yaml.Unmarshal(frontmatter, &fm); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal frontmatter: %w", err)
+		parseErr := &ParseError{Err: fmt.Errorf("failed to unmarshal frontmatter: %w", err)}
+		if m := yamlLineRe.FindStringSubmatch(err.Error()); m != nil {
+			// Frontmatter lines are offset by the opening delimiter
+			line, _ := strconv.Atoi(m[1])
+			parseErr.Line = line + 1
+		}
+		return nil, parseErr
 	}
 
 	// Extract description from body (everything before ## Comments section)
-	description, comments := parseBody(body)
+	description, comments, lastCommentID := parseBody(body)
 
 	task := &backend.Task{
 		ID:          fm.ID,
 		Title:       fm.Title,
 		Description: description,
-		Status:      status,
 		Priority:    fm.Priority,
 		Assignee:    fm.Assignee,
 		Labels:      fm.Labels,
+		Parent:      fm.Parent,
 		SortOrder:   fm.SortOrder,
+		Estimate:    fm.Estimate,
+		Spent:       fm.Spent,
 		Created:     fm.Created,
 		Updated:     fm.Updated,
+		URL:         fm.URL,
+		DeletedAt:   fm.DeletedAt,
+		StartedAt:   fm.StartedAt,
+		CompletedAt: fm.CompletedAt,
 	}
 
 	// Set default priority if empty
@@ -66,29 +148,63 @@ func (l *Local) readTaskFile(filePath string, status backend.Status) (*backend.T
 		task.Priority = backend.PriorityNone
 	}
 
-	// Initialize meta for comments and relations
-	if len(comments) > 0 || len(fm.Blocks) > 0 || len(fm.BlockedBy) > 0 {
-		if task.Meta == nil {
-			task.Meta = make(map[string]any)
-		}
-		if len(comments) > 0 {
-			task.Meta["comments"] = comments
-		}
-		if len(fm.Blocks) > 0 {
-			task.Meta["blocks"] = fm.Blocks
-		}
-		if len(fm.BlockedBy) > 0 {
-			task.Meta["blocked_by"] = fm.BlockedBy
-		}
-	}
+	setTaskMeta(task, comments, fm.Blocks, fm.BlockedBy, fm.Extra)
+	setLastCommentID(task, lastCommentID)
 
 	return task, nil
 }
 
+// setTaskMeta initializes meta for comments, relations, and unknown
+// frontmatter fields, leaving it nil when there are none.
+func setTaskMeta(task *backend.Task, comments []backend.Comment, blocks, blockedBy []string, extra map[string]any) {
+	if len(comments) == 0 && len(blocks) == 0 && len(blockedBy) == 0 && len(extra) == 0 {
+		return
+	}
+	if task.Meta == nil {
+		task.Meta = make(map[string]any)
+	}
+	if len(comments) > 0 {
+		task.Meta["comments"] = comments
+	}
+	if len(blocks) > 0 {
+		task.Meta["blocks"] = blocks
+	}
+	if len(blockedBy) > 0 {
+		task.Meta["blocked_by"] = blockedBy
+	}
+	if len(extra) > 0 {
+		task.Meta[extraMetaKey] = extra
+	}
+}
+
+// lastCommentIDMetaKey is the Task.Meta key holding the highest comment ID
+// handed out, when deleting comments has left it above the IDs in use.
+const lastCommentIDMetaKey = "last_comment_id"
+
+// setLastCommentID records the highest comment ID handed out on a task. It
+// does nothing for an empty ID.
+func setLastCommentID(task *backend.Task, id string) {
+	if id == "" {
+		return
+	}
+	if task.Meta == nil {
+		task.Meta = make(map[string]any)
+	}
+	task.Meta[lastCommentIDMetaKey] = id
+}
+
 // writeTask writes a task to a markdown file with YAML frontmatter.
 func (l *Local) writeTask(task *backend.Task) error {
+	return l.writeTaskIn(l.path, task)
+}
+
+// writeTaskIn writes a task into the status directory for task.Status under
+// root, which is the backlog directory or its trash.
+func (l *Local) writeTaskIn(root string, task *backend.Task) error {
+	l.forgetIndexedTask(task.ID)
+
 	// Ensure the status directory exists
-	statusDir := filepath.Join(l.path, string(task.Status))
+	statusDir := filepath.Join(root, string(task.Status))
 	if err := os.MkdirAll(statusDir, 0755); err != nil {
 		return fmt.Errorf("failed to create status directory: %w", err)
 	}
@@ -97,9 +213,28 @@ func (l *Local) writeTask(task *backend.Task) error {
 	filename := generateFilename(task.ID, task.Title)
 	filePath := filepath.Join(statusDir, filename)
 
-	// Extract blocks/blocked_by from meta
+	content, err := MarshalTask(task)
+	if err != nil {
+		return err
+	}
+
+	l.recordUndo(filePath)
+	if err := os.WriteFile(filePath, content, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
+
+// MarshalTask renders a task in the task file format: YAML frontmatter
+// followed by the description and comments as markdown. The status is not
+// part of the format; the local backend keeps it in the directory name.
+func MarshalTask(task *backend.Task) ([]byte, error) {
+	// Extract blocks/blocked_by and unknown frontmatter fields from meta
 	var blocks, blockedBy []string
+	var extra map[string]any
 	if task.Meta != nil {
+		extra, _ = task.Meta[extraMetaKey].(map[string]any)
 		if b, ok := task.Meta["blocks"].([]string); ok {
 			blocks = b
 		}
@@ -110,21 +245,29 @@ func (l *Local) writeTask(task *backend.Task) error {
 
 	// Build frontmatter
 	fm := taskFrontmatter{
-		ID:        task.ID,
-		Title:     task.Title,
-		Priority:  task.Priority,
-		Assignee:  task.Assignee,
-		Labels:    task.Labels,
-		Blocks:    blocks,
-		BlockedBy: blockedBy,
-		SortOrder: task.SortOrder,
-		Created:   task.Created,
-		Updated:   task.Updated,
+		ID:          task.ID,
+		Title:       task.Title,
+		Priority:    task.Priority,
+		Assignee:    task.Assignee,
+		Labels:      task.Labels,
+		Parent:      task.Parent,
+		Blocks:      blocks,
+		BlockedBy:   blockedBy,
+		SortOrder:   task.SortOrder,
+		Estimate:    task.Estimate,
+		Spent:       task.Spent,
+		Created:     task.Created,
+		Updated:     task.Updated,
+		URL:         task.URL,
+		DeletedAt:   task.DeletedAt,
+		StartedAt:   task.StartedAt,
+		CompletedAt: task.CompletedAt,
+		Extra:       extra,
 	}
 
 	frontmatterBytes, err := yaml.Marshal(&fm)
 	if err != nil {
-		return fmt.Errorf("failed to marshal frontmatter: %w", err)
+		return nil, fmt.Errorf("failed to marshal frontmatter: %w", err)
 	}
 
 	// Build file content
@@ -140,31 +283,48 @@ func (l *Local) writeTask(task *backend.Task) error {
 		buf.WriteString("\n")
 	}
 
-	// Add comments if present
-	if task.Meta != nil {
-		if comments, ok := task.Meta["comments"].([]backend.Comment); ok && len(comments) > 0 {
-			buf.WriteString("\n## Comments\n")
-			for _, comment := range comments {
-				buf.WriteString(fmt.Sprintf("\n### %s @%s\n\n",
-					comment.Created.Format("2006-01-02"),
-					comment.Author))
-				buf.WriteString(comment.Body)
-				buf.WriteString("\n")
+	// Add comments if present. The highest ID handed out is kept, even with
+	// no comments left, so that deleted comments' IDs aren't reused.
+	comments, _ := task.Meta["comments"].([]backend.Comment)
+	lastCommentID, _ := task.Meta[lastCommentIDMetaKey].(string)
+	if len(comments) > 0 || lastCommentID != "" {
+		buf.WriteString("\n## Comments\n")
+		if lastCommentID != "" {
+			buf.WriteString(fmt.Sprintf("\n<!-- last-id: %s -->\n", lastCommentID))
+		}
+		for _, comment := range comments {
+			buf.WriteString(fmt.Sprintf("\n### %s @%s",
+				formatCommentTime(comment.Created),
+				comment.Author))
+			if comment.ID != "" {
+				buf.WriteString(fmt.Sprintf(" <!-- id: %s -->", comment.ID))
 			}
+			buf.WriteString("\n\n")
+			buf.WriteString(comment.Body)
+			buf.WriteString("\n")
 		}
 	}
 
-	if err := os.WriteFile(filePath, buf.Bytes(), 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
-	}
+	return buf.Bytes(), nil
+}
 
-	return nil
+// ParseDocument splits a markdown document in the task file format into its
+// raw YAML frontmatter and its description. It lets other file types, such as
+// task templates, share the task file reader.
+func ParseDocument(content []byte) ([]byte, string, error) {
+	frontmatter, body, err := parseFrontmatter(content)
+	if err != nil {
+		return nil, "", err
+	}
+	description, _, _ := parseBody(body)
+	return frontmatter, description, nil
 }
 
 // parseFrontmatter parses YAML frontmatter from markdown content.
 // Returns the frontmatter bytes and the remaining body.
 func parseFrontmatter(content []byte) ([]byte, []byte, error) {
 	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
 
 	// Check for opening delimiter
 	if !scanner.Scan() {
@@ -205,15 +365,17 @@ func parseFrontmatter(content []byte) ([]byte, []byte, error) {
 	return frontmatter.Bytes(), body.Bytes(), nil
 }
 
-// parseBody parses the markdown body to extract description and comments.
-func parseBody(body []byte) (string, []backend.Comment) {
+// parseBody parses the markdown body to extract the description, the
+// comments, and the highest comment ID handed out if the comments section
+// records one.
+func parseBody(body []byte) (string, []backend.Comment, string) {
 	content := string(body)
 
 	// Find the ## Comments section
-	commentsIdx := strings.Index(content, "\n## Comments\n")
+	commentsIdx := findCommentsSection(content)
 	if commentsIdx == -1 {
 		// No comments section, entire body is description
-		return extractDescription(content), nil
+		return extractDescription(content), nil, ""
 	}
 
 	// Split into description and comments
@@ -223,7 +385,35 @@ func parseBody(body []byte) (string, []backend.Comment) {
 	description := extractDescription(descPart)
 	comments := parseComments(commentsPart)
 
-	return description, comments
+	// The last ID is recorded before the first comment
+	head := commentsPart
+	if loc := commentHeaderRe.FindStringIndex(commentsPart); loc != nil {
+		head = commentsPart[:loc[0]]
+	}
+	var lastCommentID string
+	if m := lastCommentIDRe.FindStringSubmatch(head); m != nil {
+		lastCommentID = m[1]
+	}
+
+	return description, comments, lastCommentID
+}
+
+// findCommentsSection returns the index of the newline preceding the
+// "## Comments" heading, or -1 if there is none. Headings inside fenced code
+// blocks belong to the description and are skipped.
+func findCommentsSection(content string) int {
+	inFence := false
+	offset := 0
+	for _, line := range strings.SplitAfter(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		} else if !inFence && offset > 0 && line == "## Comments\n" {
+			return offset - 1
+		}
+		offset += len(line)
+	}
+	return -1
 }
 
 // extractDescription extracts the description from the body part.
@@ -242,17 +432,53 @@ func extractDescription(content string) string {
 	return content
 }
 
-// parseComments parses the comments section of a task file.
+// commentHeaderRe matches comment headers: ### 2025-01-16T09:14:05Z @alex,
+// optionally followed by the comment's ID in an HTML comment: <!-- id: 2 -->.
+// Files written before comment times were stored have only the date.
+var commentHeaderRe = regexp.MustCompile(`###\s+(\d{4}-\d{2}-\d{2}(?:T\d{2}:\d{2}:\d{2}(?:Z|[+-]\d{2}:\d{2}))?)\s+@(\S+)(?:[ \t]+<!--\s*id:\s*(\S+?)\s*-->)?`)
+
+// lastCommentIDRe matches the record of the highest comment ID handed out:
+// <!-- last-id: 3 -->.
+var lastCommentIDRe = regexp.MustCompile(`(?m)^<!--\s*last-id:\s*(\S+?)\s*-->[ \t]*$`)
+
+// formatCommentTime formats a comment's creation time for its header, in
+// UTC to the second. A time at midnight UTC is written as just the date, so
+// comments from files that only stored the date are written back unchanged.
+func formatCommentTime(t time.Time) string {
+	t = t.UTC()
+	if t.Equal(t.Truncate(24 * time.Hour)) {
+		return t.Format(time.DateOnly)
+	}
+	return t.Format(time.RFC3339)
+}
+
+// parseCommentTime parses a comment header's time, a date or an RFC 3339
+// timestamp.
+func parseCommentTime(s string) time.Time {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC()
+	}
+	t, _ := time.Parse(time.DateOnly, s)
+	return t
+}
+
+// parseComments parses the comments section of a task file. Comments
+// written before IDs were stored get their position as ID ("1", "2", ...),
+// or the next free number if another comment has it. The ID becomes
+// permanent the next time the file is written. IDs stored as "c<n>" by
+// older versions are kept as they are.
 func parseComments(content string) []backend.Comment {
 	var comments []backend.Comment
 
-	// Match comment headers: ### 2025-01-16 @alex
-	commentHeaderRe := regexp.MustCompile(`###\s+(\d{4}-\d{2}-\d{2})\s+@(\S+)`)
-
 	// Split by comment headers
 	parts := commentHeaderRe.Split(content, -1)
 	matches := commentHeaderRe.FindAllStringSubmatch(content, -1)
 
+	used := make(map[int]bool, len(matches))
+	for _, match := range matches {
+		used[commentNumber(match[3])] = true
+	}
+
 	for i, match := range matches {
 		if i+1 >= len(parts) {
 			break
@@ -262,10 +488,20 @@ func parseComments(content string) []backend.Comment {
 		author := match[2]
 		body := strings.TrimSpace(parts[i+1])
 
-		created, _ := time.Parse("2006-01-02", dateStr)
+		created := parseCommentTime(dateStr)
+
+		id := match[3]
+		if id == "" {
+			n := i + 1
+			for used[n] {
+				n++
+			}
+			used[n] = true
+			id = strconv.Itoa(n)
+		}
 
 		comments = append(comments, backend.Comment{
-			ID:      fmt.Sprintf("c%d", i+1),
+			ID:      id,
 			Author:  author,
 			Body:    body,
 			Created: created,
@@ -275,36 +511,45 @@ func parseComments(content string) []backend.Comment {
 	return comments
 }
 
-// generateFilename generates a filename from task ID and title.
+// maxSlugBytes caps the slug part of a task filename. Slugs are ASCII, so
+// this also bounds the filename length on every filesystem.
+const maxSlugBytes = 50
+
+// generateFilename generates a filename from task ID and title. The title is
+// reduced to an ASCII slug; when nothing of it survives (e.g. an all-emoji or
+// right-to-left title) the filename is just the ID. The full title is kept in
+// the frontmatter either way.
 func generateFilename(id, title string) string {
-	// Sanitize title for filename
 	slug := slugify(title)
-	if slug == "" {
-		return id + ".md"
-	}
 
-	// Limit slug length
-	if len(slug) > 50 {
-		slug = slug[:50]
+	// Limit slug length without leaving a dangling hyphen
+	if len(slug) > maxSlugBytes {
+		slug = strings.TrimRight(slug[:maxSlugBytes], "-")
 	}
 
+	if slug == "" {
+		return id + ".md"
+	}
 	return fmt.Sprintf("%s-%s.md", id, slug)
 }
 
-// slugify converts a string to a URL-safe slug.
+// slugify converts a string to a filename-safe ASCII slug. Accented Latin
+// letters are transliterated, apostrophes are dropped, and any other run of
+// punctuation, whitespace, or non-Latin characters becomes a single hyphen.
 func slugify(s string) string {
-	// Convert to lowercase
-	s = strings.ToLower(s)
-
-	// Replace spaces and underscores with hyphens
-	s = strings.ReplaceAll(s, " ", "-")
-	s = strings.ReplaceAll(s, "_", "-")
-
-	// Remove any character that isn't alphanumeric or hyphen
 	var result strings.Builder
-	for _, r := range s {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
 			result.WriteRune(r)
+		case r == '\'' || r == '’':
+			// "don't" reads better as "dont" than "don-t"
+		default:
+			if t, ok := transliterations[r]; ok {
+				result.WriteString(t)
+			} else {
+				result.WriteByte('-')
+			}
 		}
 	}
 
@@ -319,3 +564,59 @@ func slugify(s string) string {
 
 	return s
 }
+
+// transliterations maps lowercase accented Latin letters to ASCII so that
+// titles like "Café résumé" keep readable slugs.
+var transliterations = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae",
+	'ç': "c", 'ć': "c", 'č': "c",
+	'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i", 'ı': "i",
+	'ł': "l", 'ľ': "l",
+	'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o",
+	'œ': "oe",
+	'ř': "r",
+	'ß': "ss", 'ś': "s", 'š': "s", 'ş': "s",
+	'ť': "t", 'ţ': "t",
+	'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u",
+	'ý': "y", 'ÿ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// taskFileMatchesID reports whether a task file's base name (without ".md")
+// belongs to the task with the given ID. Files are named "<id>-<slug>" or
+// just "<id>". For numeric IDs the file's whole leading number must equal the
+// ID, so the slug never takes part in the match and "01" can't pick up
+// "012-x". Other IDs keep the original "<id>-" prefix match.
+func taskFileMatchesID(baseName, id string) bool {
+	if baseName == id {
+		return true
+	}
+	if prefix, ok := numericPrefix(baseName); ok {
+		if prefix == id {
+			return true
+		}
+		if _, numericID := numericPrefix(id); numericID && !strings.Contains(id, "-") {
+			return false
+		}
+	}
+	return strings.HasPrefix(baseName, id+"-")
+}
+
+// numericPrefix returns the leading run of digits in a task file's base name
+// when it is followed by a hyphen or the end of the name.
+func numericPrefix(baseName string) (string, bool) {
+	i := 0
+	for i < len(baseName) && baseName[i] >= '0' && baseName[i] <= '9' {
+		i++
+	}
+	if i == 0 || (i < len(baseName) && baseName[i] != '-') {
+		return "", false
+	}
+	return baseName[:i], true
+}
@@ -1,8 +1,10 @@
 package local
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -228,11 +230,18 @@ func TestParseBody(t *testing.T) {
 			wantDesc:       "Line 1.\n\nLine 2.\n\nLine 3.",
 			wantCommentLen: 0,
 		},
+		{
+			name:            "comments heading inside fenced code block",
+			body:            "\n## Description\n\nExample:\n\n```md\n## Comments\n```\n\n## Comments\n\n### 2025-01-16 @alex\n\nReal comment.\n",
+			wantDesc:        "Example:\n\n```md\n## Comments\n```",
+			wantCommentLen:  1,
+			wantFirstAuthor: "alex",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			desc, comments := parseBody([]byte(tt.body))
+			desc, comments, _ := parseBody([]byte(tt.body))
 
 			if desc != tt.wantDesc {
 				t.Errorf("description =\n%q\nwant\n%q", desc, tt.wantDesc)
@@ -327,8 +336,8 @@ func TestParseComments(t *testing.T) {
 			wantLen: 1,
 			validate: func(t *testing.T, comments []backend.Comment) {
 				c := comments[0]
-				if c.ID != "c1" {
-					t.Errorf("ID = %q, want %q", c.ID, "c1")
+				if c.ID != "1" {
+					t.Errorf("ID = %q, want %q", c.ID, "1")
 				}
 				if c.Author != "alex" {
 					t.Errorf("Author = %q, want %q", c.Author, "alex")
@@ -363,13 +372,48 @@ Third comment.
 					if c.Author != authors[i] {
 						t.Errorf("comment[%d].Author = %q, want %q", i, c.Author, authors[i])
 					}
-					expectedID := "c" + string(rune('1'+i))
+					expectedID := string(rune('1' + i))
 					if c.ID != expectedID {
 						t.Errorf("comment[%d].ID = %q, want %q", i, c.ID, expectedID)
 					}
 				}
 			},
 		},
+		{
+			name:    "stored IDs",
+			content: "### 2025-01-16 @alex <!-- id: 4 -->\n\nKept.\n\n### 2025-01-17 @bob\n\nLegacy.\n\n### 2025-01-18 @carol <!-- id: c2 -->\n\nOlder format.\n",
+			wantLen: 3,
+			validate: func(t *testing.T, comments []backend.Comment) {
+				// The comment without an ID can't take its position's number,
+				// 2, which c2 has; "c<n>" IDs from older files are kept
+				for i, want := range []string{"4", "3", "c2"} {
+					if comments[i].ID != want {
+						t.Errorf("comment[%d].ID = %q, want %q", i, comments[i].ID, want)
+					}
+				}
+				if comments[0].Author != "alex" || comments[0].Body != "Kept." {
+					t.Errorf("comment[0] = %+v, want alex: Kept.", comments[0])
+				}
+			},
+		},
+		{
+			name:    "timestamps",
+			content: "### 2025-01-16T09:14:05Z @alex <!-- id: 1 -->\n\nUTC.\n\n### 2025-01-16T10:00:00+02:00 @bob\n\nOffset.\n",
+			wantLen: 2,
+			validate: func(t *testing.T, comments []backend.Comment) {
+				for i, want := range []time.Time{
+					time.Date(2025, 1, 16, 9, 14, 5, 0, time.UTC),
+					time.Date(2025, 1, 16, 8, 0, 0, 0, time.UTC),
+				} {
+					if !comments[i].Created.Equal(want) {
+						t.Errorf("comment[%d].Created = %v, want %v", i, comments[i].Created, want)
+					}
+				}
+				if comments[0].ID != "1" || comments[1].Author != "bob" {
+					t.Errorf("comments = %+v, want 1 and bob's", comments)
+				}
+			},
+		},
 		{
 			name:    "comment with multiline body",
 			content: "### 2025-01-16 @user\n\nLine 1.\n\nLine 2.\n\nLine 3.",
@@ -474,6 +518,46 @@ func TestGenerateFilename(t *testing.T) {
 			title: "---leading-trailing---",
 			want:  "010-leading-trailing.md",
 		},
+		{
+			id:    "011",
+			title: "Ship it 🚀🚀 before Friday ✅",
+			want:  "011-ship-it-before-friday.md",
+		},
+		{
+			id:    "012",
+			title: "🎉🎉🎉",
+			want:  "012.md",
+		},
+		{
+			id:    "013",
+			title: "إصلاح خطأ تسجيل الدخول",
+			want:  "013.md",
+		},
+		{
+			id:    "014",
+			title: "Fix עברית login",
+			want:  "014-fix-login.md",
+		},
+		{
+			id:    "015",
+			title: "Mise à jour du café crème",
+			want:  "015-mise-a-jour-du-cafe-creme.md",
+		},
+		{
+			id:    "016",
+			title: "Ticket for the release of version one - followup",
+			want:  "016-ticket-for-the-release-of-version-one-followup.md",
+		},
+		{
+			id:    "017",
+			title: "Refactor the authentication middleware everything else",
+			want:  "017-refactor-the-authentication-middleware-everything.md",
+		},
+		{
+			id:    "018",
+			title: strings.Repeat("日本語", 100),
+			want:  "018.md",
+		},
 	}
 
 	for _, tt := range tests {
@@ -486,6 +570,30 @@ func TestGenerateFilename(t *testing.T) {
 	}
 }
 
+func TestTaskFileMatchesID(t *testing.T) {
+	tests := []struct {
+		baseName string
+		id       string
+		want     bool
+	}{
+		{"001-simple-task", "001", true},
+		{"001", "001", true},
+		{"012-other-task", "01", false},
+		{"001-012-numbers-first", "012", false},
+		{"001-café-über", "001", true}, // named before slugs were ASCII-only
+		{"GH-12-imported", "GH-12", true},
+		{"GH-123-imported", "GH-12", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.baseName+"/"+tt.id, func(t *testing.T) {
+			if got := taskFileMatchesID(tt.baseName, tt.id); got != tt.want {
+				t.Errorf("taskFileMatchesID(%q, %q) = %v, want %v", tt.baseName, tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestReadTaskFileComplete(t *testing.T) {
 	tmpDir := t.TempDir()
 	backlogDir := filepath.Join(tmpDir, ".backlog")
@@ -709,6 +817,81 @@ func TestReadTaskFileErrors(t *testing.T) {
 	}
 }
 
+func TestReadTaskFileParseErrorLine(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+
+	filePath := filepath.Join(backlogDir, "todo", "001-broken.md")
+	content := "---\nid: \"001\"\ntitle: Broken\npriority: [high\n---\n"
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	_, err := l.readTaskFile(filePath, backend.StatusTodo)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("error = %v, want *ParseError", err)
+	}
+	if parseErr.Path != filePath {
+		t.Errorf("Path = %q, want %q", parseErr.Path, filePath)
+	}
+	if parseErr.Line < 2 || parseErr.Line > 5 {
+		t.Errorf("Line = %d, want a line within the frontmatter", parseErr.Line)
+	}
+	if !strings.Contains(err.Error(), filePath+":") {
+		t.Errorf("error = %q, want it to name the file", err.Error())
+	}
+}
+
+func TestUnknownFrontmatterFieldsSurviveRewrites(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+
+	filePath := filepath.Join(backlogDir, "todo", "001-custom.md")
+	content := "---\nid: \"001\"\ntitle: Custom\nsprint: 24\nteam:\n    name: core\n    size: 3\ncreated: 2025-01-15T09:00:00Z\nupdated: 2025-01-15T09:00:00Z\n---\n\nBody\n"
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	task, err := l.Get("001")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	extra, ok := task.Meta[extraMetaKey].(map[string]any)
+	if !ok || extra["sprint"] != 24 {
+		t.Fatalf("Meta[%q] = %v, want sprint: 24", extraMetaKey, task.Meta[extraMetaKey])
+	}
+
+	newTitle := "Custom renamed"
+	if _, err := l.Update("001", backend.TaskChanges{Title: &newTitle}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if _, err := l.Move("001", backend.StatusInProgress); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if _, err := l.Claim("001", "test-agent"); err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+
+	first, err := l.GetRaw("001")
+	if err != nil {
+		t.Fatalf("GetRaw() error = %v", err)
+	}
+	for _, want := range []string{"sprint: 24\n", "team:\n    name: core\n    size: 3\n"} {
+		if !strings.Contains(string(first), want) {
+			t.Errorf("rewritten file missing %q:\n%s", want, first)
+		}
+	}
+
+	// Rewriting an unchanged task must produce identical output
+	task, _ = l.Get("001")
+	if err := l.writeTask(task); err != nil {
+		t.Fatalf("writeTask() error = %v", err)
+	}
+	second, _ := l.GetRaw("001")
+	if string(first) != string(second) {
+		t.Errorf("rewrite is not stable:\nfirst:\n%s\nsecond:\n%s", first, second)
+	}
+}
+
 func TestReadTaskFileNonExistent(t *testing.T) {
 	l := New()
 	// Don't connect, just test file read
@@ -719,6 +902,33 @@ func TestReadTaskFileNonExistent(t *testing.T) {
 	}
 }
 
+func TestWriteTaskAndReadBackVerbatimDescription(t *testing.T) {
+	l, _ := setupBacklog(t)
+
+	description := "Summary line.\n\n\n```go\nfunc main() {\n\n\tfmt.Println(`hi`)\n}\n```\n\n" +
+		"~~~\n## Comments\n---\n~~~\n\n" + strings.Repeat("x", 100*1024)
+
+	task, err := l.Create(backend.TaskInput{Title: "Verbatim", Description: description})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := l.AddComment(task.ID, "A comment"); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+
+	readTask, err := l.Get(task.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if readTask.Description != description {
+		t.Errorf("Description was not preserved verbatim:\ngot  %q\nwant %q", readTask.Description[:80], description[:80])
+	}
+	comments, _ := readTask.Meta["comments"].([]backend.Comment)
+	if len(comments) != 1 || comments[0].Body != "A comment" {
+		t.Errorf("comments = %+v, want one comment", comments)
+	}
+}
+
 func TestWriteTaskAndReadBack(t *testing.T) {
 	tmpDir := t.TempDir()
 	backlogDir := filepath.Join(tmpDir, ".backlog")
@@ -751,13 +961,13 @@ func TestWriteTaskAndReadBack(t *testing.T) {
 		Meta: map[string]any{
 			"comments": []backend.Comment{
 				{
-					ID:      "c1",
+					ID:      "1",
 					Author:  "alex",
 					Body:    "First comment.",
 					Created: time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC),
 				},
 				{
-					ID:      "c2",
+					ID:      "2",
 					Author:  "bob",
 					Body:    "Second comment.",
 					Created: time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC),
@@ -898,3 +1108,49 @@ func TestTaskFile_BlocksBlockedBy(t *testing.T) {
 		t.Error("frontmatter should contain 'blocked_by:'")
 	}
 }
+
+func TestMarshalTaskRoundTrip(t *testing.T) {
+	created := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	task := &backend.Task{
+		ID:          "GH-12",
+		Title:       "Fix login",
+		Description: "The form rejects valid passwords.",
+		Priority:    backend.PriorityHigh,
+		Assignee:    "alex",
+		Labels:      []string{"bug", "auth"},
+		Created:     created,
+		Updated:     created,
+		URL:         "https://github.com/o/r/issues/12",
+	}
+
+	content, err := MarshalTask(task)
+	if err != nil {
+		t.Fatalf("MarshalTask() error = %v", err)
+	}
+	got, err := UnmarshalTask(content)
+	if err != nil {
+		t.Fatalf("UnmarshalTask() error = %v", err)
+	}
+
+	if got.ID != task.ID || got.Title != task.Title || got.Description != task.Description ||
+		got.Priority != task.Priority || got.Assignee != task.Assignee || got.URL != task.URL {
+		t.Errorf("UnmarshalTask() = %+v, want %+v", got, task)
+	}
+	if strings.Join(got.Labels, ",") != "bug,auth" {
+		t.Errorf("labels = %v, want [bug auth]", got.Labels)
+	}
+	if got.Status != "" {
+		t.Errorf("status = %q, want empty", got.Status)
+	}
+}
+
+func TestUnmarshalTaskParseError(t *testing.T) {
+	_, err := UnmarshalTask([]byte("---\ntitle: [unclosed\n---\n"))
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("UnmarshalTask() error = %v, want *ParseError", err)
+	}
+	if !strings.HasPrefix(err.Error(), "line ") {
+		t.Errorf("error = %q, want it to start with the line number", err.Error())
+	}
+}
@@ -0,0 +1,114 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// trashDir is the directory under .backlog that soft-deleted tasks are moved
+// to. It mirrors the status directories so a restored task goes back to the
+// status it was deleted from.
+const trashDir = ".trash"
+
+// trashPath returns the absolute path of the trash directory.
+func (l *Local) trashPath() string {
+	return filepath.Join(l.path, trashDir)
+}
+
+// trashedIDs returns the IDs of soft-deleted tasks, read from their filenames.
+func (l *Local) trashedIDs() []string {
+	var ids []string
+	for _, status := range backend.ValidStatuses() {
+		entries, err := os.ReadDir(filepath.Join(l.trashPath(), string(status)))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+				continue
+			}
+			id, _, _ := strings.Cut(strings.TrimSuffix(entry.Name(), ".md"), "-")
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// SoftDelete moves a task into .backlog/.trash with a deleted_at stamp
+// instead of removing it. Implements the backend.SoftDeleter interface.
+func (l *Local) SoftDelete(id string) error {
+	if !l.connected {
+		return errors.New("not connected")
+	}
+
+	filePath, err := l.findTaskFile(id)
+	if err != nil {
+		return err
+	}
+
+	task, err := l.readTaskFile(filePath, l.statusFromPath(filePath))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	task.DeletedAt = &now
+	if err := l.writeTaskIn(l.trashPath(), task); err != nil {
+		return fmt.Errorf("failed to write task: %w", err)
+	}
+	l.recordUndo(filePath)
+	if err := os.Remove(filePath); err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+
+	// Git commit if enabled
+	if err := l.gitCommit("delete", id); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return nil
+}
+
+// Restore moves a soft-deleted task out of the trash and back into the status
+// it was deleted from. Implements the backend.SoftDeleter interface.
+func (l *Local) Restore(id string) (*backend.Task, error) {
+	if !l.connected {
+		return nil, errors.New("not connected")
+	}
+
+	filePath, err := l.findTaskFileIn(l.trashPath(), id)
+	if err != nil {
+		return nil, fmt.Errorf("%w in the trash", err)
+	}
+	if _, err := l.findTaskFile(id); err == nil {
+		return nil, &backend.AlreadyExistsError{ID: id}
+	}
+
+	task, err := l.readTaskFile(filePath, l.statusFromPath(filePath))
+	if err != nil {
+		return nil, err
+	}
+
+	task.DeletedAt = nil
+	task.Updated = time.Now().UTC()
+	if err := l.writeTask(task); err != nil {
+		return nil, fmt.Errorf("failed to write task: %w", err)
+	}
+	l.recordUndo(filePath)
+	if err := os.Remove(filePath); err != nil {
+		return nil, fmt.Errorf("failed to remove task from trash: %w", err)
+	}
+
+	// Git commit if enabled
+	if err := l.gitCommit("restore", id); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return task, nil
+}
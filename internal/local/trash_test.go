@@ -0,0 +1,78 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+func TestSoftDeleteAndRestore(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+
+	task, err := l.Create(backend.TaskInput{Title: "Parked work"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := l.Move(task.ID, backend.StatusTodo); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+
+	if err := l.SoftDelete(task.ID); err != nil {
+		t.Fatalf("SoftDelete() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(backlogDir, trashDir, "todo", generateFilename(task.ID, task.Title))); err != nil {
+		t.Errorf("expected task file in trash: %v", err)
+	}
+	if _, err := l.Get(task.ID); err == nil {
+		t.Error("Get() found a soft-deleted task")
+	}
+
+	list, err := l.List(backend.TaskFilters{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if list.Count != 0 {
+		t.Errorf("List() returned %d tasks, want 0", list.Count)
+	}
+
+	list, err = l.List(backend.TaskFilters{IncludeDeleted: true})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if list.Count != 1 || list.Tasks[0].DeletedAt == nil {
+		t.Fatalf("List(IncludeDeleted) = %+v, want the task with DeletedAt set", list.Tasks)
+	}
+
+	// The trashed task's ID must not be handed out again
+	next, err := l.generateID()
+	if err != nil {
+		t.Fatalf("generateID() error = %v", err)
+	}
+	if next == task.ID {
+		t.Errorf("generateID() reused soft-deleted ID %s", next)
+	}
+
+	restored, err := l.Restore(task.ID)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if restored.Status != backend.StatusTodo {
+		t.Errorf("restored status = %s, want todo", restored.Status)
+	}
+	if restored.DeletedAt != nil {
+		t.Errorf("restored DeletedAt = %v, want nil", restored.DeletedAt)
+	}
+	got, err := l.Get(task.ID)
+	if err != nil {
+		t.Fatalf("Get() after Restore() error = %v", err)
+	}
+	if got.DeletedAt != nil {
+		t.Errorf("deleted_at survived restore: %v", got.DeletedAt)
+	}
+
+	if _, err := l.Restore(task.ID); err == nil {
+		t.Error("Restore() of a live task succeeded, want error")
+	}
+}
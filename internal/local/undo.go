@@ -0,0 +1,337 @@
+package local
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// undoDir is the directory under .backlog that holds one file per recent
+// mutation, recording the task files it changed so that it can be reverted.
+// It belongs to one checkout and is never committed.
+const undoDir = ".undo"
+
+// DefaultUndoLimit is how many mutations are kept for backlog undo when a
+// workspace doesn't set undo_limit.
+const DefaultUndoLimit = 20
+
+// undoFile is a task file changed by a mutation.
+type undoFile struct {
+	// Path is the file's path relative to the backlog directory.
+	Path string `json:"path"`
+
+	// Before is the file's content before the mutation, or nil if it
+	// didn't exist.
+	Before *string `json:"before"`
+
+	// After is the SHA-256 of the file's content after the mutation, or
+	// empty if the mutation removed it.
+	After string `json:"after,omitempty"`
+}
+
+// undoEntry is a mutation recorded in the undo directory.
+type undoEntry struct {
+	Action    string     `json:"action"`
+	TaskID    string     `json:"task_id"`
+	Agent     string     `json:"agent,omitempty"`
+	Timestamp time.Time  `json:"timestamp"`
+	Files     []undoFile `json:"files"`
+}
+
+// UndoConflictError is returned when undoing a mutation would overwrite a
+// task file that has changed since, such as by a sync or a later edit.
+type UndoConflictError struct {
+	Action string
+	TaskID string
+	Path   string
+}
+
+func (e *UndoConflictError) Error() string {
+	return fmt.Sprintf("cannot undo %s %s: %s has changed since (synced or edited), refusing to overwrite it", e.Action, e.TaskID, e.Path)
+}
+
+// undoPath returns the path of the undo directory.
+func (l *Local) undoPath() string {
+	return filepath.Join(l.path, undoDir)
+}
+
+// undoPathspec returns a git pathspec excluding the undo directory.
+func (l *Local) undoPathspec() string {
+	return ":(exclude)" + l.undoPath()
+}
+
+// recordUndo remembers the content of a task file before the current
+// mutation first changes or removes it. The mutation is saved as an undo
+// entry when it is committed.
+func (l *Local) recordUndo(filePath string) {
+	if l.undoLimit <= 0 {
+		return
+	}
+	rel, err := filepath.Rel(l.path, filePath)
+	if err != nil {
+		l.undoIncomplete = true
+		return
+	}
+	rel = filepath.ToSlash(rel)
+	for _, f := range l.pendingUndo {
+		if f.Path == rel {
+			return
+		}
+	}
+
+	file := undoFile{Path: rel}
+	data, err := os.ReadFile(filePath)
+	if err == nil {
+		content := string(data)
+		file.Before = &content
+	} else if !os.IsNotExist(err) {
+		l.undoIncomplete = true
+		return
+	}
+	l.pendingUndo = append(l.pendingUndo, file)
+}
+
+// discardUndo forgets the task files recorded since the last mutation.
+func (l *Local) discardUndo() {
+	l.pendingUndo, l.undoIncomplete = nil, false
+}
+
+// saveUndo stores the task files recorded since the last mutation as an
+// undo entry for action on taskID, dropping the oldest entries beyond the
+// undo limit. Failing to save it doesn't fail the mutation.
+func (l *Local) saveUndo(action, taskID string) {
+	files, incomplete := l.pendingUndo, l.undoIncomplete
+	l.discardUndo()
+	if l.undoLimit <= 0 {
+		return
+	}
+	if incomplete {
+		slog.Warn("not recording undo entry: a changed file couldn't be read", "action", action, "task", taskID)
+		return
+	}
+
+	// Files written back unchanged need no undoing
+	changed := files[:0]
+	for _, f := range files {
+		f.After = fileHash(filepath.Join(l.path, filepath.FromSlash(f.Path)))
+		if f.Before == nil && f.After == "" || f.Before != nil && hashContent([]byte(*f.Before)) == f.After {
+			continue
+		}
+		changed = append(changed, f)
+	}
+	if len(changed) == 0 {
+		return
+	}
+
+	entry := undoEntry{
+		Action:    action,
+		TaskID:    taskID,
+		Agent:     l.agentID,
+		Timestamp: time.Now().UTC(),
+		Files:     changed,
+	}
+	if err := l.appendUndoEntry(entry); err != nil {
+		slog.Warn("failed to record undo entry", "action", action, "task", taskID, "error", err)
+	}
+}
+
+// appendUndoEntry writes entry after the existing ones and removes the
+// oldest beyond the undo limit.
+func (l *Local) appendUndoEntry(entry undoEntry) error {
+	if err := os.MkdirAll(l.undoPath(), 0755); err != nil {
+		return err
+	}
+	names, err := l.undoEntryNames()
+	if err != nil {
+		return err
+	}
+
+	seq := 1
+	if len(names) > 0 {
+		last, _ := strconv.Atoi(strings.TrimSuffix(names[len(names)-1], ".json"))
+		seq = last + 1
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write through a temp file so a concurrent reader never sees a partial entry
+	path := filepath.Join(l.undoPath(), fmt.Sprintf("%06d.json", seq))
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	names = append(names, filepath.Base(path))
+	for len(names) > l.undoLimit {
+		if err := os.Remove(filepath.Join(l.undoPath(), names[0])); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		names = names[1:]
+	}
+	return nil
+}
+
+// undoEntryNames returns the file names of the undo entries, oldest first.
+func (l *Local) undoEntryNames() ([]string, error) {
+	dirEntries, err := os.ReadDir(l.undoPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range dirEntries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// readUndoEntry reads the undo entry in the named file.
+func (l *Local) readUndoEntry(name string) (*undoEntry, error) {
+	data, err := os.ReadFile(filepath.Join(l.undoPath(), name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read undo entry: %w", err)
+	}
+	var entry undoEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse undo entry %s: %w", name, err)
+	}
+	return &entry, nil
+}
+
+// UndoEntries returns the mutations that can be undone, most recent first.
+// Implements the backend.Undoer interface.
+func (l *Local) UndoEntries() ([]backend.UndoEntry, error) {
+	if !l.connected {
+		return nil, errors.New("not connected")
+	}
+	names, err := l.undoEntryNames()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []backend.UndoEntry{}
+	for i := len(names) - 1; i >= 0; i-- {
+		entry, err := l.readUndoEntry(names[i])
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry.public())
+	}
+	return entries, nil
+}
+
+// Undo reverts the most recent recorded mutation, restoring the task files
+// it changed, and commits the result as "undo: <action> <id>" when git sync
+// is enabled. It refuses with an UndoConflictError if any of those files has
+// changed since. Implements the backend.Undoer interface.
+func (l *Local) Undo() (*backend.UndoEntry, error) {
+	if !l.connected {
+		return nil, errors.New("not connected")
+	}
+	if err := l.checkGitSyncState(); err != nil {
+		return nil, err
+	}
+
+	names, err := l.undoEntryNames()
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, backend.ErrNothingToUndo
+	}
+	name := names[len(names)-1]
+	entry, err := l.readUndoEntry(name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range entry.Files {
+		if fileHash(filepath.Join(l.path, filepath.FromSlash(f.Path))) != f.After {
+			return nil, &UndoConflictError{Action: entry.Action, TaskID: entry.TaskID, Path: f.Path}
+		}
+	}
+
+	for _, f := range entry.Files {
+		path := filepath.Join(l.path, filepath.FromSlash(f.Path))
+		if f.Before == nil {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("failed to remove %s: %w", f.Path, err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create status directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(*f.Before), 0644); err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %w", f.Path, err)
+		}
+	}
+	l.forgetIndexedTask(entry.TaskID)
+
+	if err := os.Remove(filepath.Join(l.undoPath(), name)); err != nil {
+		return nil, fmt.Errorf("failed to remove undo entry: %w", err)
+	}
+
+	// Git commit if enabled
+	if err := l.gitCommit("undo", entry.Action+" "+entry.TaskID); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+	if err := l.pushSyncChanges(); err != nil {
+		return nil, err
+	}
+
+	undone := entry.public()
+	return &undone, nil
+}
+
+// public returns the entry as reported by backend.Undoer.
+func (e *undoEntry) public() backend.UndoEntry {
+	files := make([]string, len(e.Files))
+	for i, f := range e.Files {
+		files[i] = f.Path
+	}
+	return backend.UndoEntry{
+		Action:    e.Action,
+		TaskID:    e.TaskID,
+		Agent:     e.Agent,
+		Timestamp: e.Timestamp,
+		Files:     files,
+	}
+}
+
+// fileHash returns the SHA-256 of a file's content, or "" if it doesn't
+// exist or can't be read.
+func fileHash(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return hashContent(data)
+}
+
+// hashContent returns the hex SHA-256 of data.
+func hashContent(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,218 @@
+package local
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+func setupUndoBacklog(t *testing.T, limit int) (*Local, string) {
+	t.Helper()
+	l, backlogDir := setupBacklog(t)
+	l.undoLimit = limit
+	return l, backlogDir
+}
+
+func TestUndoRevertsMutations(t *testing.T) {
+	l, backlogDir := setupUndoBacklog(t, DefaultUndoLimit)
+
+	task, err := l.Create(backend.TaskInput{Title: "Fix login", Description: "Original"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	title, description := "Fix the login page", "Rewritten"
+	if _, err := l.Update(task.ID, backend.TaskChanges{Title: &title, Description: &description}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if _, err := l.Move(task.ID, backend.StatusInProgress); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if err := l.Delete(task.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	entries, err := l.UndoEntries()
+	if err != nil {
+		t.Fatalf("UndoEntries() error = %v", err)
+	}
+	var actions []string
+	for _, entry := range entries {
+		actions = append(actions, entry.Action)
+	}
+	if strings.Join(actions, ",") != "delete,move,edit,add" {
+		t.Fatalf("UndoEntries() actions = %v, want most recent first", actions)
+	}
+
+	// Undoing the delete recreates the task where it was
+	undone, err := l.Undo()
+	if err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if undone.Action != "delete" || undone.TaskID != task.ID {
+		t.Errorf("Undo() = %+v, want the delete of %s", undone, task.ID)
+	}
+	got, err := l.Get(task.ID)
+	if err != nil {
+		t.Fatalf("Get() after undoing delete error = %v", err)
+	}
+	if got.Status != backend.StatusInProgress {
+		t.Errorf("status = %s, want in-progress", got.Status)
+	}
+
+	// Undoing the move puts the file back in its old status directory
+	if _, err := l.Undo(); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	got, err = l.Get(task.ID)
+	if err != nil {
+		t.Fatalf("Get() after undoing move error = %v", err)
+	}
+	if got.Status != task.Status {
+		t.Errorf("status = %s, want %s", got.Status, task.Status)
+	}
+	if _, err := os.Stat(filepath.Join(backlogDir, "in-progress", generateFilename(task.ID, title))); !os.IsNotExist(err) {
+		t.Errorf("moved file still in in-progress: %v", err)
+	}
+
+	// Undoing the edit restores the title, renaming the file back
+	if _, err := l.Undo(); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	got, err = l.Get(task.ID)
+	if err != nil {
+		t.Fatalf("Get() after undoing edit error = %v", err)
+	}
+	if got.Title != "Fix login" || got.Description != "Original" {
+		t.Errorf("task = %q/%q, want the original title and description", got.Title, got.Description)
+	}
+	if _, err := os.Stat(filepath.Join(backlogDir, string(task.Status), generateFilename(task.ID, title))); !os.IsNotExist(err) {
+		t.Errorf("renamed file still exists: %v", err)
+	}
+
+	// Undoing the add removes the task
+	if _, err := l.Undo(); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if _, err := l.Get(task.ID); !backend.IsNotFound(err) {
+		t.Errorf("Get() after undoing add error = %v, want not found", err)
+	}
+
+	if _, err := l.Undo(); !errors.Is(err, backend.ErrNothingToUndo) {
+		t.Errorf("Undo() with no entries error = %v, want ErrNothingToUndo", err)
+	}
+}
+
+func TestUndoLimit(t *testing.T) {
+	l, _ := setupUndoBacklog(t, 2)
+
+	for _, title := range []string{"One", "Two", "Three"} {
+		if _, err := l.Create(backend.TaskInput{Title: title}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	entries, err := l.UndoEntries()
+	if err != nil {
+		t.Fatalf("UndoEntries() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].TaskID != "003" || entries[1].TaskID != "002" {
+		t.Errorf("UndoEntries() = %+v, want the last two adds", entries)
+	}
+}
+
+func TestUndoRefusesChangedFile(t *testing.T) {
+	l, backlogDir := setupUndoBacklog(t, DefaultUndoLimit)
+
+	task, err := l.Create(backend.TaskInput{Title: "Fix login"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	priority := backend.PriorityHigh
+	if _, err := l.Update(task.ID, backend.TaskChanges{Priority: &priority}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	// Someone else's change arrives, as a pull would bring it
+	path := filepath.Join(backlogDir, string(task.Status), generateFilename(task.ID, task.Title))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := strings.Replace(string(data), "priority: high", "priority: urgent", 1)
+	if err := os.WriteFile(path, []byte(changed), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var conflict *UndoConflictError
+	if _, err := l.Undo(); !errors.As(err, &conflict) {
+		t.Fatalf("Undo() error = %v, want UndoConflictError", err)
+	}
+	after, _ := os.ReadFile(path)
+	if string(after) != changed {
+		t.Error("Undo() overwrote the changed file")
+	}
+	if entries, _ := l.UndoEntries(); len(entries) != 2 {
+		t.Errorf("UndoEntries() = %d entries, want the refused entry kept", len(entries))
+	}
+}
+
+func TestUndoDisabled(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+
+	if _, err := l.Create(backend.TaskInput{Title: "Fix login"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(backlogDir, undoDir)); !os.IsNotExist(err) {
+		t.Errorf("undo directory created with undo disabled: %v", err)
+	}
+	if _, err := l.Undo(); !errors.Is(err, backend.ErrNothingToUndo) {
+		t.Errorf("Undo() error = %v, want ErrNothingToUndo", err)
+	}
+}
+
+func TestUndoCommitsWithGitSync(t *testing.T) {
+	remoteDir := filepath.Join(t.TempDir(), "remote.git")
+	if out, err := exec.Command("git", "init", "-q", "--bare", "-b", "main", remoteDir).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v\n%s", err, out)
+	}
+	l, repoDir := setupGitBacklog(t, remoteDir, time.Minute)
+	l.undoLimit = DefaultUndoLimit
+	if out, err := exec.Command("git", "-C", repoDir, "push", "-q", "-u", "origin", "main").CombinedOutput(); err != nil {
+		t.Fatalf("git push: %v\n%s", err, out)
+	}
+
+	task, err := l.Create(backend.TaskInput{Title: "Fix login"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := l.Move(task.ID, backend.StatusDone); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if _, err := l.Undo(); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+
+	out, err := exec.Command("git", "-C", repoDir, "log", "-1", "--format=%s").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git log: %v\n%s", err, out)
+	}
+	if want := "undo: move " + task.ID; strings.TrimSpace(string(out)) != want {
+		t.Errorf("last commit = %q, want %q", strings.TrimSpace(string(out)), want)
+	}
+	out, err = exec.Command("git", "-C", repoDir, "ls-files").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git ls-files: %v\n%s", err, out)
+	}
+	if strings.Contains(string(out), undoDir) {
+		t.Errorf("undo log was committed:\n%s", out)
+	}
+	if dirty, err := l.hasUncommittedChanges(); err != nil || dirty {
+		t.Errorf("hasUncommittedChanges() = %v, %v; want a clean tree", dirty, err)
+	}
+}
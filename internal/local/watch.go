@@ -0,0 +1,57 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchChanges watches the status directories, where claiming, releasing,
+// adding or editing a task creates, renames or writes a task file. Status
+// directories that don't exist yet are not watched; polling covers them.
+func (l *Local) WatchChanges() (<-chan struct{}, func(), error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to watch backlog: %w", err)
+	}
+	for _, status := range backend.ValidStatuses() {
+		dir := filepath.Join(l.path, string(status))
+		if err := watcher.Add(dir); err != nil && !errors.Is(err, os.ErrNotExist) {
+			watcher.Close()
+			return nil, nil, fmt.Errorf("failed to watch %s: %w", dir, err)
+		}
+	}
+
+	changes := make(chan struct{}, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case _, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			case _, ok := <-watcher.Errors:
+				// A dropped event only delays the wake-up until the next poll
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	stop := func() {
+		watcher.Close()
+		<-done
+	}
+	return changes, stop, nil
+}
@@ -0,0 +1,33 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+func TestWatchChanges(t *testing.T) {
+	l, backlogDir := setupBacklog(t)
+	if err := os.RemoveAll(filepath.Join(backlogDir, "review")); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, stop, err := l.WatchChanges()
+	if err != nil {
+		t.Fatalf("WatchChanges() error = %v, want a missing status directory skipped", err)
+	}
+	defer stop()
+
+	if _, err := l.Create(backend.TaskInput{Title: "Fix login"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change reported after a task was added")
+	}
+
+}
@@ -0,0 +1,95 @@
+// Package logging configures the CLI's leveled diagnostic logs. Records are
+// written with log/slog to stderr, so they never mix with command output on
+// stdout. Packages log through the slog default logger; until Setup is
+// called, it emits only warnings and errors, at DefaultLevel.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// EnvVar is the environment variable that sets the log level.
+const EnvVar = "BACKLOG_LOG"
+
+// Log formats accepted by Setup.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// DefaultLevel is the level used when neither --verbose nor BACKLOG_LOG is set.
+const DefaultLevel = slog.LevelWarn
+
+// maxOutput is how many bytes of command output or HTTP body a record keeps.
+const maxOutput = 2048
+
+// The standard default logger emits info records, which would print
+// progress messages on every command that runs before Setup, or without it
+// in tests and other programs.
+func init() {
+	slog.SetLogLoggerLevel(DefaultLevel)
+}
+
+// ParseLevel parses a level name: debug, info, warn, or error.
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("invalid log level %q (valid: debug, info, warn, error)", name)
+}
+
+// VerbosityLevel returns the level selected by repeating --verbose: once for
+// info, twice or more for debug.
+func VerbosityLevel(count int) slog.Level {
+	switch {
+	case count >= 2:
+		return slog.LevelDebug
+	case count == 1:
+		return slog.LevelInfo
+	}
+	return DefaultLevel
+}
+
+// Setup installs the default logger, writing records at level and above to w
+// in the given format ("text" or "json").
+func Setup(w io.Writer, level slog.Level, format string) error {
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch format {
+	case "", FormatText:
+		handler = slog.NewTextHandler(w, opts)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, opts)
+	default:
+		return fmt.Errorf("invalid log format %q (valid: text, json)", format)
+	}
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// Truncate shortens s to the size kept in log records, noting how much was cut.
+func Truncate(s string) string {
+	if len(s) <= maxOutput {
+		return s
+	}
+	return fmt.Sprintf("%s... (%d bytes truncated)", s[:maxOutput], len(s)-maxOutput)
+}
+
+// tokenPattern matches GitHub and Linear API tokens.
+var tokenPattern = regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9_]{16,}|github_pat_[A-Za-z0-9_]{20,}|lin_(api|oauth)_[A-Za-z0-9]{20,})\b`)
+
+// Redact masks API tokens in s.
+func Redact(s string) string {
+	return tokenPattern.ReplaceAllString(s, "[REDACTED]")
+}
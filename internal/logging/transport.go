@@ -0,0 +1,100 @@
+package logging
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// sensitiveHeaders are never logged with their values.
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// Transport is an http.RoundTripper that logs each request with its method,
+// path, status, and duration at info level. At debug level it also logs the
+// request and response bodies. Credentials are always redacted.
+type Transport struct {
+	// Base is the underlying transport. Nil means http.DefaultTransport.
+	Base http.RoundTripper
+}
+
+// NewTransport wraps base with request logging.
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{Base: base}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ctx := req.Context()
+	logger := slog.Default()
+	if !logger.Enabled(ctx, slog.LevelInfo) {
+		return base.RoundTrip(req)
+	}
+	debug := logger.Enabled(ctx, slog.LevelDebug)
+
+	if debug {
+		attrs := []any{"method", req.Method, "url", Redact(req.URL.Redacted()), "headers", redactHeaders(req.Header)}
+		if req.Body != nil && req.GetBody != nil {
+			if body, err := req.GetBody(); err == nil {
+				content, _ := io.ReadAll(body)
+				body.Close()
+				attrs = append(attrs, "body", Truncate(Redact(string(content))))
+			}
+		}
+		logger.Debug("http request", attrs...)
+	}
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	duration := time.Since(start)
+	if err != nil {
+		logger.Info("http request failed", "method", req.Method, "path", req.URL.Path, "duration", duration, "error", err)
+		return nil, err
+	}
+	logger.Info("http request", "method", req.Method, "path", req.URL.Path, "status", resp.StatusCode, "duration", duration)
+
+	if debug {
+		logResponseBody(logger, req, resp)
+	}
+	return resp, nil
+}
+
+// logResponseBody logs the response body and replaces it with an unread copy
+// for the caller.
+func logResponseBody(logger *slog.Logger, req *http.Request, resp *http.Response) {
+	content, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(content))
+	if err != nil {
+		return
+	}
+	logger.Debug("http response",
+		"method", req.Method,
+		"path", req.URL.Path,
+		"status", resp.StatusCode,
+		"headers", redactHeaders(resp.Header),
+		"body", Truncate(Redact(string(content))))
+}
+
+// redactHeaders returns the headers as a flat map, with credential values
+// replaced.
+func redactHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for name, values := range h {
+		if len(values) > 0 {
+			out[name] = Redact(values[0])
+		}
+	}
+	for _, name := range sensitiveHeaders {
+		if _, ok := out[name]; ok {
+			out[name] = "[REDACTED]"
+		}
+	}
+	return out
+}
@@ -0,0 +1,185 @@
+package output
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// ColorMode controls when table output is colorized.
+type ColorMode string
+
+const (
+	// ColorAuto colorizes when stdout is a terminal and NO_COLOR is unset.
+	ColorAuto ColorMode = "auto"
+	// ColorAlways always colorizes table output.
+	ColorAlways ColorMode = "always"
+	// ColorNever never colorizes output.
+	ColorNever ColorMode = "never"
+)
+
+// IsValid checks if the color mode is a valid value.
+func (m ColorMode) IsValid() bool {
+	switch m {
+	case ColorAuto, ColorAlways, ColorNever:
+		return true
+	default:
+		return false
+	}
+}
+
+// colorEnabled is whether formatters created by New emit ANSI colors.
+// Only the table format uses colors; machine-readable formats never do.
+var colorEnabled bool
+
+// SetColor enables or disables colors for formatters created by New.
+func SetColor(enabled bool) {
+	colorEnabled = enabled
+}
+
+// ColorEnabled reports whether formatters created by New emit ANSI colors.
+func ColorEnabled() bool {
+	return colorEnabled
+}
+
+// labelColors maps label names to the colors table output paints them in.
+var labelColors map[string]string
+
+// SetLabelColors sets the label colors used by formatters created by New,
+// taken from a backend's label registry.
+func SetLabelColors(labels []backend.Label) {
+	labelColors = make(map[string]string, len(labels))
+	for _, label := range labels {
+		if label.Color != "" {
+			labelColors[label.Name] = label.Color
+		}
+	}
+}
+
+// ResolveColor decides whether to colorize output written to f. In auto mode
+// colors are used only for terminals, and never when NO_COLOR is set
+// (https://no-color.org) or TERM is "dumb".
+func ResolveColor(mode ColorMode, f *os.File) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// ANSI foreground color codes. All codes are two digits so that every
+// colorized cell carries the same number of invisible bytes, which keeps
+// tabwriter columns aligned.
+const (
+	ansiRed     = "31"
+	ansiGreen   = "32"
+	ansiYellow  = "33"
+	ansiBlue    = "34"
+	ansiMagenta = "35"
+	ansiCyan    = "36"
+	ansiDefault = "39"
+	ansiGray    = "90"
+)
+
+// statusColor returns the color used for a status.
+func statusColor(s backend.Status) string {
+	switch s {
+	case backend.StatusBacklog:
+		return ansiGray
+	case backend.StatusTodo:
+		return ansiBlue
+	case backend.StatusInProgress:
+		return ansiYellow
+	case backend.StatusReview:
+		return ansiMagenta
+	case backend.StatusDone:
+		return ansiGreen
+	default:
+		return ansiDefault
+	}
+}
+
+// priorityColor returns the color used for a priority.
+func priorityColor(p backend.Priority) string {
+	switch p {
+	case backend.PriorityUrgent:
+		return ansiRed
+	case backend.PriorityHigh:
+		return ansiYellow
+	case backend.PriorityLow:
+		return ansiCyan
+	case backend.PriorityNone:
+		return ansiGray
+	default:
+		return ansiDefault
+	}
+}
+
+// paint wraps s in the given ANSI color.
+func paint(color, s string) string {
+	return "\x1b[" + color + "m" + s + "\x1b[0m"
+}
+
+// labelPalette is the set of colors labels are painted in, with the RGB
+// value each stands for when matching hex colors.
+var labelPalette = []struct {
+	name    string
+	code    string
+	r, g, b int
+}{
+	{"red", ansiRed, 205, 49, 49},
+	{"green", ansiGreen, 13, 188, 121},
+	{"yellow", ansiYellow, 229, 229, 16},
+	{"blue", ansiBlue, 36, 114, 200},
+	{"magenta", ansiMagenta, 188, 63, 188},
+	{"cyan", ansiCyan, 17, 168, 205},
+	{"gray", ansiGray, 128, 128, 128},
+}
+
+// labelColor returns the ANSI color for a label color given by name or as a
+// hex value. Hex values are mapped to the nearest palette color, since table
+// cells must all carry the same number of invisible bytes. Unrecognized
+// colors use the terminal default.
+func labelColor(color string) string {
+	color = strings.ToLower(strings.TrimSpace(color))
+	if color == "grey" {
+		color = "gray"
+	}
+	for _, c := range labelPalette {
+		if c.name == color {
+			return c.code
+		}
+	}
+
+	hex := strings.TrimPrefix(color, "#")
+	if len(hex) != 6 {
+		return ansiDefault
+	}
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return ansiDefault
+	}
+	r, g, b := int(rgb>>16), int(rgb>>8&0xff), int(rgb&0xff)
+
+	best, bestDist := ansiDefault, -1
+	for _, c := range labelPalette {
+		dist := (r-c.r)*(r-c.r) + (g-c.g)*(g-c.g) + (b-c.b)*(b-c.b)
+		if bestDist < 0 || dist < bestDist {
+			best, bestDist = c.code, dist
+		}
+	}
+	return best
+}
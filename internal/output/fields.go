@@ -0,0 +1,256 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// taskField is a task attribute that list output can render as a column
+// or JSON key.
+type taskField struct {
+	// header is the column heading in table output.
+	header string
+
+	// json returns the value written under the field's name in JSON output.
+	json func(t *backend.Task) any
+
+	// text returns the value as plain text, or "" if the task has none.
+	text func(t *backend.Task) string
+}
+
+// timeText formats a timestamp for text output, or "" if it is unset.
+func timeText(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+// ageText formats how long before now t was, in the largest whole unit up
+// to days, or as a date once it is a month old.
+func ageText(t, now time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	age := now.Sub(t)
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age/time.Minute))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age/time.Hour))
+	case age < 30*24*time.Hour:
+		return fmt.Sprintf("%dd", int(age/(24*time.Hour)))
+	}
+	return t.Format("2006-01-02")
+}
+
+// firstLine returns the first line of s, marked with "…" if more follow.
+func firstLine(s string) string {
+	line, rest, more := strings.Cut(strings.TrimSpace(s), "\n")
+	line = strings.TrimSpace(line)
+	if more && strings.TrimSpace(rest) != "" {
+		return line + " …"
+	}
+	return line
+}
+
+// durationText formats a duration for text output, or "" if it is zero.
+func durationText(d backend.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return d.String()
+}
+
+// durationJSON returns a duration for JSON output, or nil if it is zero.
+func durationJSON(d backend.Duration) any {
+	if d <= 0 {
+		return nil
+	}
+	return d
+}
+
+// existingTask reports whether a create returned an existing task, for an
+// idempotency key or --if-absent match, instead of creating one.
+func existingTask(task *backend.Task) bool {
+	replay, _ := task.Meta[backend.MetaIdempotentReplay].(bool)
+	exists, _ := task.Meta[backend.MetaAlreadyExists].(bool)
+	return replay || exists
+}
+
+// taskFields maps field names, which double as JSON keys, to their
+// accessors.
+var taskFields = map[string]taskField{
+	"id": {
+		header: "ID",
+		json:   func(t *backend.Task) any { return t.ID },
+		text:   func(t *backend.Task) string { return t.ID },
+	},
+	"title": {
+		header: "TITLE",
+		json:   func(t *backend.Task) any { return t.Title },
+		text:   func(t *backend.Task) string { return t.Title },
+	},
+	"description": {
+		header: "DESCRIPTION",
+		json:   func(t *backend.Task) any { return t.Description },
+		text:   func(t *backend.Task) string { return t.Description },
+	},
+	"status": {
+		header: "STATUS",
+		json:   func(t *backend.Task) any { return t.Status },
+		text:   func(t *backend.Task) string { return string(t.Status) },
+	},
+	"priority": {
+		header: "PRIORITY",
+		json:   func(t *backend.Task) any { return t.Priority },
+		text:   func(t *backend.Task) string { return string(t.Priority) },
+	},
+	"assignee": {
+		header: "ASSIGNEE",
+		json:   func(t *backend.Task) any { return t.Assignee },
+		text:   func(t *backend.Task) string { return t.Assignee },
+	},
+	"labels": {
+		header: "LABELS",
+		json: func(t *backend.Task) any {
+			if t.Labels == nil {
+				return []string{}
+			}
+			return t.Labels
+		},
+		text: func(t *backend.Task) string { return joinLabels(t.Labels) },
+	},
+	"parent": {
+		header: "PARENT",
+		json:   func(t *backend.Task) any { return t.Parent },
+		text:   func(t *backend.Task) string { return t.Parent },
+	},
+	"created": {
+		header: "CREATED",
+		json:   func(t *backend.Task) any { return t.Created },
+		text:   func(t *backend.Task) string { return timeText(&t.Created) },
+	},
+	"updated": {
+		header: "UPDATED",
+		json:   func(t *backend.Task) any { return t.Updated },
+		text:   func(t *backend.Task) string { return timeText(&t.Updated) },
+	},
+	"estimate": {
+		header: "ESTIMATE",
+		json:   func(t *backend.Task) any { return durationJSON(t.Estimate) },
+		text:   func(t *backend.Task) string { return durationText(t.Estimate) },
+	},
+	"spent": {
+		header: "SPENT",
+		json:   func(t *backend.Task) any { return durationJSON(t.Spent) },
+		text:   func(t *backend.Task) string { return durationText(t.Spent) },
+	},
+	"started_at": {
+		header: "STARTED",
+		json:   func(t *backend.Task) any { return t.StartedAt },
+		text:   func(t *backend.Task) string { return timeText(t.StartedAt) },
+	},
+	"completed_at": {
+		header: "COMPLETED",
+		json:   func(t *backend.Task) any { return t.CompletedAt },
+		text:   func(t *backend.Task) string { return timeText(t.CompletedAt) },
+	},
+	"duration": {
+		header: "DURATION",
+		json:   func(t *backend.Task) any { return durationJSON(t.Duration) },
+		text:   func(t *backend.Task) string { return durationText(t.Duration) },
+	},
+	"checklist": {
+		header: "CHECKLIST",
+		json: func(t *backend.Task) any {
+			if t.Checklist == nil {
+				return []backend.ChecklistItem{}
+			}
+			return t.Checklist
+		},
+		text: func(t *backend.Task) string {
+			if t.ChecklistProgress == nil {
+				return ""
+			}
+			return t.ChecklistProgress.String()
+		},
+	},
+	"url": {
+		header: "URL",
+		json:   func(t *backend.Task) any { return t.URL },
+		text:   func(t *backend.Task) string { return t.URL },
+	},
+}
+
+// fieldOrder lists the field names in the order help and errors show them.
+var fieldOrder = []string{
+	"id", "title", "description", "status", "priority", "assignee", "labels", "parent",
+	"created", "updated", "estimate", "spent", "started_at", "completed_at", "duration", "checklist", "url",
+}
+
+// DefaultFields are the columns of table output when no fields are selected.
+var DefaultFields = []string{"id", "status", "priority", "title", "assignee", "labels"}
+
+// FieldNames returns the names of the fields list output can select.
+func FieldNames() []string {
+	return append([]string(nil), fieldOrder...)
+}
+
+// ParseFields validates field names given on the command line and returns
+// them normalized, in the order given.
+func ParseFields(names []string) ([]string, error) {
+	var parsed []string
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if _, ok := taskFields[name]; !ok {
+			return nil, fmt.Errorf("unknown field %q (valid: %s)", name, strings.Join(fieldOrder, ", "))
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("field %q given more than once", name)
+		}
+		seen[name] = true
+		parsed = append(parsed, name)
+	}
+	if len(parsed) == 0 {
+		return nil, fmt.Errorf("at least one field is required (valid: %s)", strings.Join(fieldOrder, ", "))
+	}
+	return parsed, nil
+}
+
+// selectedFields is the fields task lists are rendered with by formatters
+// created by New. Nil means each format's full default output.
+var selectedFields []string
+
+// SetFields selects the fields task lists are rendered with by formatters
+// created by New. Pass nil to restore the default output.
+func SetFields(fields []string) {
+	selectedFields = fields
+}
+
+// fieldsJSON returns a task as a JSON object holding only the given fields.
+func fieldsJSON(task *backend.Task, fields []string) map[string]any {
+	result := make(map[string]any, len(fields))
+	for _, name := range fields {
+		result[name] = taskFields[name].json(task)
+	}
+	return result
+}
+
+// fieldsText returns a task's values for the given fields as plain text.
+func fieldsText(task *backend.Task, fields []string) []string {
+	values := make([]string, len(fields))
+	for i, name := range fields {
+		values[i] = taskFields[name].text(task)
+	}
+	return values
+}
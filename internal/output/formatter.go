@@ -6,6 +6,8 @@ import (
 
 	"github.com/alexbrand/backlog/internal/backend"
 	"github.com/alexbrand/backlog/internal/config"
+	"github.com/alexbrand/backlog/internal/credentials"
+	"github.com/alexbrand/backlog/internal/template"
 )
 
 // Format represents an output format type.
@@ -16,23 +18,37 @@ const (
 	FormatJSON   Format = "json"
 	FormatPlain  Format = "plain"
 	FormatIDOnly Format = "id-only"
+
+	// FormatMarkdown renders tasks as markdown documents. Other output
+	// is the table format's, without colors.
+	FormatMarkdown Format = "markdown"
 )
 
 // ValidFormats returns all valid format values.
 func ValidFormats() []Format {
-	return []Format{FormatTable, FormatJSON, FormatPlain, FormatIDOnly}
+	return []Format{FormatTable, FormatJSON, FormatPlain, FormatIDOnly, FormatMarkdown}
 }
 
 // IsValid checks if the format is a valid output format.
 func (f Format) IsValid() bool {
 	switch f {
-	case FormatTable, FormatJSON, FormatPlain, FormatIDOnly:
+	case FormatTable, FormatJSON, FormatPlain, FormatIDOnly, FormatMarkdown:
 		return true
 	default:
 		return false
 	}
 }
 
+// TaskGroup is a set of tasks sharing a value of the field a list is
+// grouped by.
+type TaskGroup struct {
+	// Key is the shared value, e.g. a status name or "unassigned".
+	Key string
+
+	// Tasks are the group's tasks, in list order.
+	Tasks []backend.Task
+}
+
 // Formatter defines the interface for outputting backlog data in various formats.
 type Formatter interface {
 	// FormatTask outputs a single task.
@@ -41,45 +57,87 @@ type Formatter interface {
 	// FormatTaskList outputs a list of tasks.
 	FormatTaskList(w io.Writer, list *backend.TaskList) error
 
+	// FormatGroupedTaskList outputs a list of tasks partitioned into groups
+	// by the named field.
+	FormatGroupedTaskList(w io.Writer, groupBy string, groups []TaskGroup, list *backend.TaskList) error
+
+	// FormatTaskCount outputs only the number of tasks matching a list's
+	// filters.
+	FormatTaskCount(w io.Writer, count int) error
+
 	// FormatTaskWithComments outputs a single task with its comments.
 	FormatTaskWithComments(w io.Writer, task *backend.Task, comments []backend.Comment) error
 
-	// FormatComment outputs a single comment.
+	// FormatComment outputs a newly added comment.
 	FormatComment(w io.Writer, comment *backend.Comment) error
 
+	// FormatCommentEdited outputs a comment whose body was replaced.
+	FormatCommentEdited(w io.Writer, comment *backend.Comment) error
+
+	// FormatCommentDeleted outputs the result of deleting a comment.
+	FormatCommentDeleted(w io.Writer, taskID, commentID string) error
+
 	// FormatComments outputs a list of comments.
 	FormatComments(w io.Writer, comments []backend.Comment) error
 
+	// FormatCommentList outputs the comments listed by "comment list",
+	// which came from the named workspace.
+	FormatCommentList(w io.Writer, source string, comments []backend.Comment) error
+
 	// FormatCreated outputs the result of creating a task.
 	FormatCreated(w io.Writer, task *backend.Task) error
 
-	// FormatMoved outputs the result of moving a task to a new status.
-	FormatMoved(w io.Writer, task *backend.Task, oldStatus, newStatus backend.Status) error
+	// FormatMoved outputs the result of moving a task to a new status,
+	// along with the fields the move changed.
+	FormatMoved(w io.Writer, task *backend.Task, oldStatus, newStatus backend.Status, changes []backend.FieldChange) error
 
-	// FormatUpdated outputs the result of updating a task.
-	FormatUpdated(w io.Writer, task *backend.Task) error
+	// FormatUpdated outputs the result of updating a task, along with the
+	// fields the update changed. No changes means the update was a no-op.
+	FormatUpdated(w io.Writer, task *backend.Task, changes []backend.FieldChange) error
 
 	// FormatClaimed outputs the result of claiming a task.
 	FormatClaimed(w io.Writer, task *backend.Task, agentID string, alreadyOwned bool) error
 
+	// FormatNextClaimed outputs the task claimed by "next --claim", along
+	// with the candidates that were skipped before it.
+	FormatNextClaimed(w io.Writer, result *backend.ClaimResult, agentID string, skipped []backend.SkippedCandidate) error
+
+	// FormatNextClaimedBatch outputs the tasks claimed by "next --claim
+	// --count", along with any candidates that were skipped.
+	FormatNextClaimedBatch(w io.Writer, results []backend.ClaimResult, agentID string, skipped []backend.SkippedCandidate) error
+
 	// FormatReleased outputs the result of releasing a task.
 	FormatReleased(w io.Writer, task *backend.Task) error
 
 	// FormatSynced outputs the result of a sync operation.
 	FormatSynced(w io.Writer, result *backend.SyncResult) error
 
-	// FormatError outputs an error.
-	FormatError(w io.Writer, code string, message string, details map[string]any) error
+	// FormatSyncStatus outputs how the backlog has diverged from its remote.
+	FormatSyncStatus(w io.Writer, status *backend.SyncStatus) error
+
+	// FormatError outputs an error. code follows the exit code; errorCode
+	// names the specific failure, such as CLAIM_CONFLICT.
+	FormatError(w io.Writer, code string, errorCode string, message string, details map[string]any) error
 
 	// FormatConfig outputs configuration.
 	FormatConfig(w io.Writer, cfg *config.Config) error
 
+	// FormatResolvedConfig outputs the effective configuration with the
+	// source of each value.
+	FormatResolvedConfig(w io.Writer, values []config.ResolvedValue) error
+
+	// FormatConfigSettings outputs the effective settings of a workspace.
+	FormatConfigSettings(w io.Writer, workspace string, settings []config.Setting) error
+
 	// FormatHealthCheck outputs health check results.
 	FormatHealthCheck(w io.Writer, backendName string, ws *config.Workspace, status *backend.HealthStatus) error
 
 	// FormatDeleted outputs the result of deleting a task.
 	FormatDeleted(w io.Writer, id string) error
 
+	// FormatRestored outputs the result of restoring a soft-deleted task.
+	FormatRestored(w io.Writer, task *backend.Task) error
+
 	// FormatReordered outputs the result of reordering a task.
 	FormatReordered(w io.Writer, task *backend.Task) error
 
@@ -88,20 +146,75 @@ type Formatter interface {
 
 	// FormatUnlinked outputs the result of unlinking two tasks.
 	FormatUnlinked(w io.Writer, sourceID, targetID string) error
+
+	// FormatLabels outputs the labels known to a backend.
+	FormatLabels(w io.Writer, labels []backend.Label) error
+
+	// FormatLabelChange outputs the result of renaming or removing a label.
+	FormatLabelChange(w io.Writer, change *backend.LabelChange) error
+
+	// FormatTemplates outputs a list of task templates.
+	FormatTemplates(w io.Writer, templates []template.Template) error
+
+	// FormatDoctorReport outputs the result of a workspace integrity check.
+	FormatDoctorReport(w io.Writer, report *backend.DoctorReport) error
+
+	// FormatReindexed outputs the result of rebuilding a task index.
+	FormatReindexed(w io.Writer, result *backend.ReindexResult) error
+
+	// FormatHistory outputs the history of a task.
+	FormatHistory(w io.Writer, history *backend.TaskHistory) error
+
+	// FormatBlame outputs the last change to each field of a task.
+	FormatBlame(w io.Writer, blame *backend.TaskBlame) error
+
+	// FormatUndone outputs the mutation that undo reverted.
+	FormatUndone(w io.Writer, entry *backend.UndoEntry) error
+
+	// FormatUndoEntries outputs the mutations that can be undone.
+	FormatUndoEntries(w io.Writer, entries []backend.UndoEntry) error
+
+	// FormatUncommitted outputs the backlog files verify-clean found
+	// uncommitted.
+	FormatUncommitted(w io.Writer, files []string) error
+
+	// FormatDryRun outputs the change a command would make under --dry-run.
+	FormatDryRun(w io.Writer, result *backend.DryRunResult) error
+
+	// FormatWhoami outputs the resolved agent identity.
+	FormatWhoami(w io.Writer, identity *backend.AgentIdentity) error
+
+	// FormatTimeReport outputs how long completed tasks took.
+	FormatTimeReport(w io.Writer, report *backend.TimeReport) error
+
+	// FormatChangelog outputs the tasks completed in a window, by section.
+	FormatChangelog(w io.Writer, changelog *backend.Changelog) error
+
+	// FormatCapabilities outputs which optional operations a backend supports.
+	FormatCapabilities(w io.Writer, caps *backend.Capabilities) error
+
+	// FormatWorkspaceCapabilities outputs which optional operations the
+	// backend of each configured workspace supports.
+	FormatWorkspaceCapabilities(w io.Writer, workspaces []backend.WorkspaceCapabilities) error
+
+	// FormatCredentials outputs where workspaces' credentials come from.
+	FormatCredentials(w io.Writer, creds []credentials.WorkspaceCredential) error
 }
 
 // New creates a formatter for the specified format.
 func New(format Format) Formatter {
 	switch format {
 	case FormatJSON:
-		return &JSONFormatter{}
+		return &JSONFormatter{Fields: selectedFields}
 	case FormatPlain:
-		return &PlainFormatter{}
+		return &PlainFormatter{Fields: selectedFields}
 	case FormatIDOnly:
 		return &IDOnlyFormatter{}
+	case FormatMarkdown:
+		return &MarkdownFormatter{TableFormatter{LabelColors: labelColors, Fields: selectedFields}}
 	case FormatTable:
 		fallthrough
 	default:
-		return &TableFormatter{}
+		return &TableFormatter{Color: colorEnabled, LabelColors: labelColors, Fields: selectedFields}
 	}
 }
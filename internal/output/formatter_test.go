@@ -3,6 +3,8 @@ package output
 import (
 	"bytes"
 	"encoding/json"
+	"os"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -188,6 +190,109 @@ func TestTableFormatterFormatTaskList(t *testing.T) {
 	}
 }
 
+func TestTableFormatterColor(t *testing.T) {
+	list := testTaskList()
+
+	var plain, colored bytes.Buffer
+	if err := (&TableFormatter{}).FormatTaskList(&plain, list); err != nil {
+		t.Fatalf("FormatTaskList() error = %v", err)
+	}
+	if err := (&TableFormatter{Color: true}).FormatTaskList(&colored, list); err != nil {
+		t.Fatalf("FormatTaskList() error = %v", err)
+	}
+
+	if strings.Contains(plain.String(), "\x1b[") {
+		t.Errorf("uncolored output contains ANSI codes: %q", plain.String())
+	}
+	for _, want := range []string{"\x1b[33mhigh\x1b[0m", "\x1b[34mtodo\x1b[0m", "\x1b[33min-progress\x1b[0m"} {
+		if !strings.Contains(colored.String(), want) {
+			t.Errorf("colored output should contain %q, got %q", want, colored.String())
+		}
+	}
+
+	// Colors must not change column alignment
+	stripped := regexp.MustCompile("\x1b\\[[0-9]+m").ReplaceAllString(colored.String(), "")
+	if stripped != plain.String() {
+		t.Errorf("colored output misaligned:\n%s\nwant:\n%s", stripped, plain.String())
+	}
+}
+
+func TestTableFormatterLabelColors(t *testing.T) {
+	list := &backend.TaskList{Tasks: []backend.Task{
+		{ID: "001", Title: "Crash on start", Status: backend.StatusTodo, Priority: backend.PriorityHigh, Labels: []string{"bug", "ui"}},
+	}, Count: 1}
+	f := &TableFormatter{Color: true, LabelColors: map[string]string{"bug": "#d73a4a"}}
+
+	var buf bytes.Buffer
+	if err := f.FormatTaskList(&buf, list); err != nil {
+		t.Fatalf("FormatTaskList() error = %v", err)
+	}
+	for _, want := range []string{"LABELS", "\x1b[31mbug\x1b[0m", "\x1b[39mui\x1b[0m"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output should contain %q, got %q", want, buf.String())
+		}
+	}
+}
+
+func TestLabelColor(t *testing.T) {
+	tests := map[string]string{
+		"red":      ansiRed,
+		"Grey":     ansiGray,
+		"#d73a4a":  ansiRed,
+		"0e8a16":   ansiGreen,
+		"#1d76db":  ansiBlue,
+		"#fbca04":  ansiYellow,
+		"teal-ish": ansiDefault,
+		"":         ansiDefault,
+	}
+	for color, want := range tests {
+		if got := labelColor(color); got != want {
+			t.Errorf("labelColor(%q) = %q, want %q", color, got, want)
+		}
+	}
+}
+
+func TestNewAppliesColorOnlyToTable(t *testing.T) {
+	SetColor(true)
+	defer SetColor(false)
+
+	for _, format := range ValidFormats() {
+		var buf bytes.Buffer
+		if err := New(format).FormatTaskList(&buf, testTaskList()); err != nil {
+			t.Fatalf("%s: FormatTaskList() error = %v", format, err)
+		}
+		hasColor := strings.Contains(buf.String(), "\x1b[")
+		if hasColor != (format == FormatTable) {
+			t.Errorf("%s: colored = %v, want %v", format, hasColor, format == FormatTable)
+		}
+	}
+}
+
+func TestResolveColor(t *testing.T) {
+	// A regular file is never a terminal
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatalf("CreateTemp() error = %v", err)
+	}
+	defer f.Close()
+
+	t.Setenv("NO_COLOR", "")
+	if ResolveColor(ColorAuto, f) {
+		t.Error("auto should not colorize a non-terminal")
+	}
+	if !ResolveColor(ColorAlways, f) {
+		t.Error("always should colorize")
+	}
+	if ResolveColor(ColorNever, f) {
+		t.Error("never should not colorize")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if !ResolveColor(ColorAlways, f) {
+		t.Error("always should override NO_COLOR")
+	}
+}
+
 func TestTableFormatterEmptyList(t *testing.T) {
 	f := &TableFormatter{}
 	var buf bytes.Buffer
@@ -258,11 +363,81 @@ func TestJSONFormatterFormatTaskList(t *testing.T) {
 	}
 }
 
+func TestFormatTaskCount(t *testing.T) {
+	for _, format := range []Format{FormatTable, FormatPlain, FormatIDOnly} {
+		var buf bytes.Buffer
+		if err := New(format).FormatTaskCount(&buf, 7); err != nil {
+			t.Fatalf("%s: FormatTaskCount() error = %v", format, err)
+		}
+		if buf.String() != "7\n" {
+			t.Errorf("%s: FormatTaskCount() = %q, want %q", format, buf.String(), "7\n")
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := New(FormatJSON).FormatTaskCount(&buf, 0); err != nil {
+		t.Fatalf("json: FormatTaskCount() error = %v", err)
+	}
+	var result map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if len(result) != 1 || result["count"] != float64(0) {
+		t.Errorf("FormatTaskCount() = %v, want only count 0", result)
+	}
+}
+
+func TestFormatUpdatedChanges(t *testing.T) {
+	task := &backend.Task{ID: "012", Title: "Set up CI"}
+	changes := []backend.FieldChange{
+		{Field: "priority", Before: backend.PriorityMedium, After: backend.PriorityHigh},
+		{Field: "assignee", Before: "", After: "agent-7"},
+		{Field: "labels", Before: []string{}, After: []string{"infra"}, Added: []string{"infra"}},
+	}
+
+	var buf bytes.Buffer
+	if err := (&TableFormatter{}).FormatUpdated(&buf, task, changes); err != nil {
+		t.Fatalf("FormatUpdated() error = %v", err)
+	}
+	want := "Updated 012: Set up CI\n  priority: medium → high\n  assignee: (none) → agent-7\n  labels: +infra\n"
+	if buf.String() != want {
+		t.Errorf("FormatUpdated() = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := (&TableFormatter{}).FormatUpdated(&buf, task, nil); err != nil {
+		t.Fatalf("FormatUpdated() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "no changes") {
+		t.Errorf("FormatUpdated() = %q, want it to say no changes", buf.String())
+	}
+
+	buf.Reset()
+	if err := (&JSONFormatter{}).FormatUpdated(&buf, task, changes); err != nil {
+		t.Fatalf("FormatUpdated() error = %v", err)
+	}
+	var result struct {
+		Changes map[string]struct {
+			Before any `json:"before"`
+			After  any `json:"after"`
+		} `json:"changes"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if got := result.Changes["priority"]; got.Before != "medium" || got.After != "high" {
+		t.Errorf("changes.priority = %+v, want medium -> high", got)
+	}
+	if len(result.Changes) != 3 {
+		t.Errorf("changes = %v, want 3 fields", result.Changes)
+	}
+}
+
 func TestJSONFormatterFormatError(t *testing.T) {
 	f := &JSONFormatter{}
 	var buf bytes.Buffer
 
-	err := f.FormatError(&buf, "NOT_FOUND", "Task GH-999 not found", nil)
+	err := f.FormatError(&buf, "NOT_FOUND", "NOT_FOUND", "Task GH-999 not found", nil)
 	if err != nil {
 		t.Fatalf("FormatError() error = %v", err)
 	}
@@ -280,11 +455,50 @@ func TestJSONFormatterFormatError(t *testing.T) {
 	if errObj["code"] != "NOT_FOUND" {
 		t.Errorf("code = %v, want NOT_FOUND", errObj["code"])
 	}
+	if errObj["error_code"] != "NOT_FOUND" {
+		t.Errorf("error_code = %v, want NOT_FOUND", errObj["error_code"])
+	}
 	if errObj["message"] != "Task GH-999 not found" {
 		t.Errorf("message = %v, want Task GH-999 not found", errObj["message"])
 	}
 }
 
+func TestJSONFormatterFormatHealthCheckRateLimit(t *testing.T) {
+	f := &JSONFormatter{}
+	reset := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	status := &backend.HealthStatus{
+		OK:        true,
+		Message:   "ok",
+		RateLimit: &backend.RateLimit{Limit: 5000, Remaining: 42, Reset: reset},
+	}
+
+	var buf bytes.Buffer
+	if err := f.FormatHealthCheck(&buf, "github", nil, status); err != nil {
+		t.Fatalf("FormatHealthCheck() error = %v", err)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	rateLimit, ok := result["rate_limit"].(map[string]any)
+	if !ok {
+		t.Fatalf("rate_limit = %v, want an object", result["rate_limit"])
+	}
+	if rateLimit["limit"] != float64(5000) || rateLimit["remaining"] != float64(42) || rateLimit["reset"] != "2025-01-15T10:00:00Z" {
+		t.Errorf("rate_limit = %v", rateLimit)
+	}
+
+	// Backends that don't report a rate limit leave the key out
+	buf.Reset()
+	if err := f.FormatHealthCheck(&buf, "local", nil, &backend.HealthStatus{OK: true}); err != nil {
+		t.Fatalf("FormatHealthCheck() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "rate_limit") {
+		t.Errorf("output = %s, want no rate_limit", buf.String())
+	}
+}
+
 func TestPlainFormatterFormatTask(t *testing.T) {
 	f := &PlainFormatter{}
 	var buf bytes.Buffer
@@ -318,6 +532,112 @@ func TestPlainFormatterFormatTaskList(t *testing.T) {
 	}
 }
 
+func TestTableFormatterFields(t *testing.T) {
+	f := &TableFormatter{Fields: []string{"title", "id", "assignee"}}
+	var buf bytes.Buffer
+	if err := f.FormatTaskList(&buf, testTaskList()); err != nil {
+		t.Fatalf("FormatTaskList() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if got := strings.Fields(lines[0]); strings.Join(got, " ") != "TITLE ID ASSIGNEE" {
+		t.Errorf("header = %q, want TITLE ID ASSIGNEE", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "Implement auth flow") || !strings.HasSuffix(lines[1], "@alex") {
+		t.Errorf("first row = %q", lines[1])
+	}
+	if !strings.HasSuffix(lines[2], "—") {
+		t.Errorf("unassigned task should show a dash, got %q", lines[2])
+	}
+	if strings.Contains(buf.String(), "STATUS") {
+		t.Error("unselected STATUS column should be omitted")
+	}
+}
+
+func TestTableFormatterChecklistColumn(t *testing.T) {
+	list := testTaskList()
+	f := &TableFormatter{}
+	var buf bytes.Buffer
+	if err := f.FormatTaskList(&buf, list); err != nil {
+		t.Fatalf("FormatTaskList() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "CHECKLIST") {
+		t.Error("CHECKLIST column shown without any checklists")
+	}
+
+	list.Tasks[0].Description = "- [x] Design\n- [ ] Build\n- [ ] Ship"
+	backend.SetChecklist(&list.Tasks[0])
+	buf.Reset()
+	if err := f.FormatTaskList(&buf, list); err != nil {
+		t.Fatalf("FormatTaskList() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !strings.HasSuffix(strings.TrimSpace(lines[0]), "CHECKLIST") {
+		t.Errorf("header = %q, want a trailing CHECKLIST column", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "1/3") || !strings.HasSuffix(lines[2], "—") {
+		t.Errorf("rows = %q, want 1/3 and a dash", lines[1:])
+	}
+}
+
+func TestJSONFormatterFields(t *testing.T) {
+	f := &JSONFormatter{Fields: []string{"id", "assignee", "labels"}}
+	var buf bytes.Buffer
+	if err := f.FormatTaskList(&buf, testTaskList()); err != nil {
+		t.Fatalf("FormatTaskList() error = %v", err)
+	}
+
+	var result struct {
+		Tasks []map[string]any `json:"tasks"`
+		Count int              `json:"count"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if result.Count != 2 || len(result.Tasks) != 2 {
+		t.Fatalf("count = %d, len(tasks) = %d, want 2", result.Count, len(result.Tasks))
+	}
+	first := result.Tasks[0]
+	if len(first) != 3 || first["id"] != "GH-123" || first["assignee"] != "alex" {
+		t.Errorf("first task = %v, want only id, assignee, and labels", first)
+	}
+	if labels, ok := first["labels"].([]any); !ok || len(labels) != 0 {
+		t.Errorf("labels = %v, want empty array", first["labels"])
+	}
+}
+
+func TestPlainFormatterFields(t *testing.T) {
+	f := &PlainFormatter{Fields: []string{"priority", "id"}}
+	var buf bytes.Buffer
+	if err := f.FormatTaskList(&buf, testTaskList()); err != nil {
+		t.Fatalf("FormatTaskList() error = %v", err)
+	}
+
+	want := "high\tGH-123\nmedium\tGH-124\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	got, err := ParseFields([]string{" ID", "title", "started_at"})
+	if err != nil {
+		t.Fatalf("ParseFields() error = %v", err)
+	}
+	if strings.Join(got, ",") != "id,title,started_at" {
+		t.Errorf("ParseFields() = %v", got)
+	}
+
+	for _, names := range [][]string{{"id", "bogus"}, {"id", "id"}, {""}} {
+		if _, err := ParseFields(names); err == nil {
+			t.Errorf("ParseFields(%q) should fail", names)
+		}
+	}
+	if _, err := ParseFields([]string{"bogus"}); err == nil || !strings.Contains(err.Error(), `unknown field "bogus"`) {
+		t.Errorf("expected error naming the unknown field, got %v", err)
+	}
+}
+
 func TestIDOnlyFormatterFormatTask(t *testing.T) {
 	f := &IDOnlyFormatter{}
 	var buf bytes.Buffer
@@ -396,4 +716,167 @@ func TestJSONFormatterFormatCreated(t *testing.T) {
 	if result["title"] != "Implement auth flow" {
 		t.Errorf("title = %v, want Implement auth flow", result["title"])
 	}
+	if result["created"] != true {
+		t.Errorf("created = %v, want true", result["created"])
+	}
+}
+
+func TestFormatCreatedExistingTask(t *testing.T) {
+	task := testTask()
+	task.Meta = map[string]any{backend.MetaAlreadyExists: true}
+
+	var buf bytes.Buffer
+	if err := (&JSONFormatter{}).FormatCreated(&buf, task); err != nil {
+		t.Fatalf("FormatCreated() error = %v", err)
+	}
+	var result map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if result["created"] != false {
+		t.Errorf("created = %v, want false", result["created"])
+	}
+
+	buf.Reset()
+	if err := (&TableFormatter{}).FormatCreated(&buf, task); err != nil {
+		t.Fatalf("FormatCreated() error = %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "Exists GH-123") {
+		t.Errorf("output = %q, want it to report the existing task", buf.String())
+	}
+}
+
+func TestMarkdownFormatterFormatTask(t *testing.T) {
+	task := testTask()
+	task.Labels = []string{"a|b"}
+	task.Meta = map[string]any{"relations": []backend.Relation{
+		{Type: backend.RelationBlockedBy, TaskID: "GH-7", TaskTitle: "Set up OAuth app", TaskStatus: backend.StatusDone},
+	}}
+	comments := []backend.Comment{
+		{ID: "c1", Author: "sam", Body: "Looks good\n", Created: time.Date(2025, 1, 16, 10, 0, 0, 0, time.UTC)},
+	}
+
+	var buf bytes.Buffer
+	if err := New(FormatMarkdown).FormatTaskWithComments(&buf, task, comments); err != nil {
+		t.Fatalf("FormatTaskWithComments() error = %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"# Implement auth flow (GH-123)\n\n| Field | Value |\n|-------|-------|\n| Status | in-progress |\n",
+		"| Assignee | @alex |\n",
+		`| Labels | a\|b |` + "\n",
+		"| Created | 2025-01-15 09:00 |\n",
+		"| URL | https://github.com/alexbrand/myproject/issues/123 |\n",
+		"## Description\n\nOAuth2 implementation details...\n",
+		"## Relations\n\n- Blocked by **GH-7**: Set up OAuth app (done)\n",
+		"## Comments\n\n### @sam, 2025-01-16 10:00\n\nLooks good\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "| Estimate |") {
+		t.Errorf("output has a row for an unset field:\n%s", out)
+	}
+}
+
+func TestTableFormatterFormatChangelog(t *testing.T) {
+	since := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	changelog := &backend.Changelog{
+		Since:    &since,
+		SinceTag: "v1.2.0",
+		Count:    2,
+		Sections: []backend.ChangelogSection{
+			{Title: "Features", Label: "type:feature", Tasks: []backend.ChangelogEntry{
+				{ID: "GH-12", Title: "Add dark mode", URL: "https://github.com/alexbrand/myproject/issues/12", Authors: []string{"alex", "claude-1"}},
+			}},
+			{Title: "Other", Tasks: []backend.ChangelogEntry{{ID: "003", Title: "Tidy docs"}}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := New(FormatTable).FormatChangelog(&buf, changelog); err != nil {
+		t.Fatalf("FormatChangelog() error = %v", err)
+	}
+	want := "## Changes since v1.2.0 (2025-05-01)\n\n" +
+		"### Features\n\n- Add dark mode ([GH-12](https://github.com/alexbrand/myproject/issues/12)) by @alex, @claude-1\n\n" +
+		"### Other\n\n- Tidy docs (003)\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestFormatWorkspaceCapabilities(t *testing.T) {
+	ops := func(claim, reorder bool) []backend.Capability {
+		return []backend.Capability{
+			{Name: "claim", Commands: "claim", Supported: claim},
+			{Name: "reorder", Commands: "reorder", Supported: reorder},
+		}
+	}
+	workspaces := []backend.WorkspaceCapabilities{
+		{Workspace: "main", Capabilities: backend.Capabilities{Backend: "local", Operations: ops(true, true)}},
+		{Workspace: "team", Capabilities: backend.Capabilities{Backend: "github", Operations: ops(true, false)}},
+		{Workspace: "tracker", Capabilities: backend.Capabilities{Backend: "exec", Operations: []backend.Capability{}}, Error: "handshake failed"},
+	}
+
+	var buf bytes.Buffer
+	if err := New(FormatTable).FormatWorkspaceCapabilities(&buf, workspaces); err != nil {
+		t.Fatalf("FormatWorkspaceCapabilities() error = %v", err)
+	}
+	want := "OPERATION  main (local)  team (github)  tracker (exec)  COMMANDS\n" +
+		"claim      yes           yes            ?               claim\n" +
+		"reorder    yes           no             ?               reorder\n" +
+		"\ntracker: handshake failed\n"
+	if buf.String() != want {
+		t.Errorf("table output = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := New(FormatIDOnly).FormatWorkspaceCapabilities(&buf, workspaces[:2]); err != nil {
+		t.Fatalf("FormatWorkspaceCapabilities() error = %v", err)
+	}
+	if buf.String() != "claim\n" {
+		t.Errorf("id-only output = %q, want the operations every workspace supports", buf.String())
+	}
+}
+
+func TestFormatCommentList(t *testing.T) {
+	now := time.Now()
+	comments := []backend.Comment{
+		{ID: "c1", Author: "alex", Body: "Found the bug\n\nDetails follow.", Created: now.Add(-3 * time.Hour), TaskID: "001"},
+		{ID: "c2", Author: "claude-1", Body: "Fixed", Created: time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC), TaskID: "001"},
+	}
+
+	var buf bytes.Buffer
+	if err := New(FormatTable).FormatCommentList(&buf, "main", comments); err != nil {
+		t.Fatalf("FormatCommentList() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || strings.Fields(lines[0])[2] != "AGE" {
+		t.Fatalf("table =\n%s\nwant a header and two rows", buf.String())
+	}
+	if got := strings.Join(strings.Fields(lines[1]), " "); got != "c1 alex 3h Found the bug …" {
+		t.Errorf("row = %q", got)
+	}
+	if got := strings.Join(strings.Fields(lines[2]), " "); got != "c2 claude-1 2025-01-16 Fixed" {
+		t.Errorf("row = %q", got)
+	}
+
+	buf.Reset()
+	if err := New(FormatJSON).FormatCommentList(&buf, "main", comments); err != nil {
+		t.Fatalf("FormatCommentList() error = %v", err)
+	}
+	var result struct {
+		Comments []map[string]any `json:"comments"`
+		Count    int              `json:"count"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if result.Count != 2 || result.Comments[1]["source"] != "main" || result.Comments[1]["task_id"] != "001" {
+		t.Errorf("comments = %v, want source and task_id on each", result.Comments)
+	}
+	if result.Comments[0]["body"] != "Found the bug\n\nDetails follow." {
+		t.Errorf("body = %q, want the full body", result.Comments[0]["body"])
+	}
 }
@@ -6,6 +6,8 @@ import (
 
 	"github.com/alexbrand/backlog/internal/backend"
 	"github.com/alexbrand/backlog/internal/config"
+	"github.com/alexbrand/backlog/internal/credentials"
+	"github.com/alexbrand/backlog/internal/template"
 )
 
 // IDOnlyFormatter outputs only task IDs, one per line.
@@ -25,6 +27,27 @@ func (f *IDOnlyFormatter) FormatTaskList(w io.Writer, list *backend.TaskList) er
 	return nil
 }
 
+// FormatGroupedTaskList outputs task IDs in group order, one per line. Tasks
+// in several groups (e.g. with several labels) are listed once.
+func (f *IDOnlyFormatter) FormatGroupedTaskList(w io.Writer, groupBy string, groups []TaskGroup, list *backend.TaskList) error {
+	seen := make(map[string]bool)
+	for _, group := range groups {
+		for _, task := range group.Tasks {
+			if !seen[task.ID] {
+				seen[task.ID] = true
+				fmt.Fprintln(w, task.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// FormatTaskCount outputs the number of matching tasks.
+func (f *IDOnlyFormatter) FormatTaskCount(w io.Writer, count int) error {
+	fmt.Fprintln(w, count)
+	return nil
+}
+
 // FormatTaskWithComments outputs only the task ID (comments are ignored).
 func (f *IDOnlyFormatter) FormatTaskWithComments(w io.Writer, task *backend.Task, _ []backend.Comment) error {
 	fmt.Fprintln(w, task.ID)
@@ -37,6 +60,18 @@ func (f *IDOnlyFormatter) FormatComment(w io.Writer, comment *backend.Comment) e
 	return nil
 }
 
+// FormatCommentEdited outputs only the comment ID.
+func (f *IDOnlyFormatter) FormatCommentEdited(w io.Writer, comment *backend.Comment) error {
+	fmt.Fprintln(w, comment.ID)
+	return nil
+}
+
+// FormatCommentDeleted outputs only the deleted comment's ID.
+func (f *IDOnlyFormatter) FormatCommentDeleted(w io.Writer, taskID, commentID string) error {
+	fmt.Fprintln(w, commentID)
+	return nil
+}
+
 // FormatComments outputs only comment IDs, one per line.
 func (f *IDOnlyFormatter) FormatComments(w io.Writer, comments []backend.Comment) error {
 	for _, comment := range comments {
@@ -45,6 +80,11 @@ func (f *IDOnlyFormatter) FormatComments(w io.Writer, comments []backend.Comment
 	return nil
 }
 
+// FormatCommentList outputs only comment IDs, one per line.
+func (f *IDOnlyFormatter) FormatCommentList(w io.Writer, _ string, comments []backend.Comment) error {
+	return f.FormatComments(w, comments)
+}
+
 // FormatCreated outputs only the created task ID.
 func (f *IDOnlyFormatter) FormatCreated(w io.Writer, task *backend.Task) error {
 	fmt.Fprintln(w, task.ID)
@@ -52,13 +92,13 @@ func (f *IDOnlyFormatter) FormatCreated(w io.Writer, task *backend.Task) error {
 }
 
 // FormatMoved outputs only the moved task ID.
-func (f *IDOnlyFormatter) FormatMoved(w io.Writer, task *backend.Task, _, _ backend.Status) error {
+func (f *IDOnlyFormatter) FormatMoved(w io.Writer, task *backend.Task, _, _ backend.Status, _ []backend.FieldChange) error {
 	fmt.Fprintln(w, task.ID)
 	return nil
 }
 
 // FormatUpdated outputs only the updated task ID.
-func (f *IDOnlyFormatter) FormatUpdated(w io.Writer, task *backend.Task) error {
+func (f *IDOnlyFormatter) FormatUpdated(w io.Writer, task *backend.Task, _ []backend.FieldChange) error {
 	fmt.Fprintln(w, task.ID)
 	return nil
 }
@@ -69,6 +109,20 @@ func (f *IDOnlyFormatter) FormatClaimed(w io.Writer, task *backend.Task, _ strin
 	return nil
 }
 
+// FormatNextClaimed outputs only the claimed task ID.
+func (f *IDOnlyFormatter) FormatNextClaimed(w io.Writer, result *backend.ClaimResult, _ string, _ []backend.SkippedCandidate) error {
+	fmt.Fprintln(w, result.Task.ID)
+	return nil
+}
+
+// FormatNextClaimedBatch outputs the claimed task IDs, one per line.
+func (f *IDOnlyFormatter) FormatNextClaimedBatch(w io.Writer, results []backend.ClaimResult, _ string, _ []backend.SkippedCandidate) error {
+	for _, result := range results {
+		fmt.Fprintln(w, result.Task.ID)
+	}
+	return nil
+}
+
 // FormatReleased outputs only the released task ID.
 func (f *IDOnlyFormatter) FormatReleased(w io.Writer, task *backend.Task) error {
 	fmt.Fprintln(w, task.ID)
@@ -82,8 +136,18 @@ func (f *IDOnlyFormatter) FormatSynced(w io.Writer, result *backend.SyncResult)
 	return nil
 }
 
+// FormatSyncStatus outputs the IDs of tasks a pull would touch, one per line.
+func (f *IDOnlyFormatter) FormatSyncStatus(w io.Writer, status *backend.SyncStatus) error {
+	for _, ids := range [][]string{status.Updated, status.Added, status.Removed} {
+		for _, id := range ids {
+			fmt.Fprintln(w, id)
+		}
+	}
+	return nil
+}
+
 // FormatError outputs an error message (errors are always shown).
-func (f *IDOnlyFormatter) FormatError(w io.Writer, code string, message string, details map[string]any) error {
+func (f *IDOnlyFormatter) FormatError(w io.Writer, code string, errorCode string, message string, details map[string]any) error {
 	fmt.Fprintf(w, "error: %s\n", message)
 	return nil
 }
@@ -94,6 +158,18 @@ func (f *IDOnlyFormatter) FormatConfig(w io.Writer, cfg *config.Config) error {
 	return nil
 }
 
+// FormatResolvedConfig outputs the configuration (empty for id-only format).
+func (f *IDOnlyFormatter) FormatResolvedConfig(w io.Writer, values []config.ResolvedValue) error {
+	// id-only format doesn't output configuration
+	return nil
+}
+
+// FormatConfigSettings outputs workspace settings (empty for id-only format).
+func (f *IDOnlyFormatter) FormatConfigSettings(w io.Writer, workspace string, settings []config.Setting) error {
+	// id-only format doesn't output settings
+	return nil
+}
+
 // FormatHealthCheck outputs health check results (empty for id-only format).
 func (f *IDOnlyFormatter) FormatHealthCheck(w io.Writer, backendName string, ws *config.Workspace, status *backend.HealthStatus) error {
 	// id-only format doesn't output health status
@@ -106,6 +182,12 @@ func (f *IDOnlyFormatter) FormatDeleted(w io.Writer, id string) error {
 	return nil
 }
 
+// FormatRestored outputs only the restored task ID.
+func (f *IDOnlyFormatter) FormatRestored(w io.Writer, task *backend.Task) error {
+	fmt.Fprintln(w, task.ID)
+	return nil
+}
+
 // FormatReordered outputs only the reordered task ID.
 func (f *IDOnlyFormatter) FormatReordered(w io.Writer, task *backend.Task) error {
 	fmt.Fprintln(w, task.ID)
@@ -123,3 +205,163 @@ func (f *IDOnlyFormatter) FormatUnlinked(w io.Writer, sourceID, targetID string)
 	fmt.Fprintln(w, sourceID)
 	return nil
 }
+
+// FormatLabels outputs only label names, one per line.
+func (f *IDOnlyFormatter) FormatLabels(w io.Writer, labels []backend.Label) error {
+	for _, label := range labels {
+		fmt.Fprintln(w, label.Name)
+	}
+	return nil
+}
+
+// FormatLabelChange outputs the IDs of the tasks whose labels changed, one
+// per line.
+func (f *IDOnlyFormatter) FormatLabelChange(w io.Writer, change *backend.LabelChange) error {
+	for _, id := range change.Tasks {
+		fmt.Fprintln(w, id)
+	}
+	return nil
+}
+
+// FormatTemplates outputs only template names, one per line.
+func (f *IDOnlyFormatter) FormatTemplates(w io.Writer, templates []template.Template) error {
+	for _, t := range templates {
+		fmt.Fprintln(w, t.Name)
+	}
+	return nil
+}
+
+// FormatDoctorReport outputs the IDs of tasks with unresolved findings, one per line.
+func (f *IDOnlyFormatter) FormatDoctorReport(w io.Writer, report *backend.DoctorReport) error {
+	seen := make(map[string]bool)
+	for _, finding := range report.Findings {
+		if finding.Fixed || finding.TaskID == "" || seen[finding.TaskID] {
+			continue
+		}
+		seen[finding.TaskID] = true
+		fmt.Fprintln(w, finding.TaskID)
+	}
+	return nil
+}
+
+// FormatReindexed outputs the number of tasks indexed.
+func (f *IDOnlyFormatter) FormatReindexed(w io.Writer, result *backend.ReindexResult) error {
+	fmt.Fprintln(w, result.Tasks)
+	return nil
+}
+
+// FormatHistory outputs the commit hashes of a task's history, one per line.
+func (f *IDOnlyFormatter) FormatHistory(w io.Writer, history *backend.TaskHistory) error {
+	for _, entry := range history.Entries {
+		if entry.Commit != "" {
+			fmt.Fprintln(w, entry.Commit)
+		}
+	}
+	return nil
+}
+
+// FormatBlame outputs the commits that last changed the task's fields, one
+// per line, each listed once.
+func (f *IDOnlyFormatter) FormatBlame(w io.Writer, blame *backend.TaskBlame) error {
+	seen := make(map[string]bool)
+	for _, field := range blame.Fields {
+		if field.Commit != "" && !field.Uncommitted && !seen[field.Commit] {
+			seen[field.Commit] = true
+			fmt.Fprintln(w, field.Commit)
+		}
+	}
+	return nil
+}
+
+// FormatUndone outputs only the ID of the task whose change was reverted.
+func (f *IDOnlyFormatter) FormatUndone(w io.Writer, entry *backend.UndoEntry) error {
+	fmt.Fprintln(w, entry.TaskID)
+	return nil
+}
+
+// FormatUndoEntries outputs the task IDs of the mutations that can be
+// undone, one per line.
+func (f *IDOnlyFormatter) FormatUndoEntries(w io.Writer, entries []backend.UndoEntry) error {
+	for _, entry := range entries {
+		fmt.Fprintln(w, entry.TaskID)
+	}
+	return nil
+}
+
+// FormatUncommitted outputs the uncommitted backlog files, one per line.
+func (f *IDOnlyFormatter) FormatUncommitted(w io.Writer, files []string) error {
+	for _, file := range files {
+		fmt.Fprintln(w, file)
+	}
+	return nil
+}
+
+// FormatDryRun outputs the ID of the task a command would change. Nothing is
+// printed for add, since the ID is only assigned on creation.
+func (f *IDOnlyFormatter) FormatDryRun(w io.Writer, result *backend.DryRunResult) error {
+	if result.Task != nil && result.Task.ID != "" {
+		fmt.Fprintln(w, result.Task.ID)
+	}
+	return nil
+}
+
+// FormatWhoami outputs only the agent ID.
+func (f *IDOnlyFormatter) FormatWhoami(w io.Writer, identity *backend.AgentIdentity) error {
+	fmt.Fprintln(w, identity.ID)
+	return nil
+}
+
+// FormatTimeReport outputs only the total duration.
+func (f *IDOnlyFormatter) FormatTimeReport(w io.Writer, report *backend.TimeReport) error {
+	fmt.Fprintln(w, report.Total)
+	return nil
+}
+
+// FormatChangelog outputs only the IDs of the tasks, section by section.
+func (f *IDOnlyFormatter) FormatChangelog(w io.Writer, changelog *backend.Changelog) error {
+	for _, section := range changelog.Sections {
+		for _, entry := range section.Tasks {
+			fmt.Fprintln(w, entry.ID)
+		}
+	}
+	return nil
+}
+
+// FormatCapabilities outputs the names of the supported operations.
+func (f *IDOnlyFormatter) FormatCapabilities(w io.Writer, caps *backend.Capabilities) error {
+	for _, op := range caps.Operations {
+		if op.Supported {
+			fmt.Fprintln(w, op.Name)
+		}
+	}
+	return nil
+}
+
+// FormatWorkspaceCapabilities outputs the names of the operations supported
+// by every workspace listed.
+func (f *IDOnlyFormatter) FormatWorkspaceCapabilities(w io.Writer, workspaces []backend.WorkspaceCapabilities) error {
+	if len(workspaces) == 0 {
+		return nil
+	}
+	for _, op := range workspaces[0].Operations {
+		everywhere := true
+		for _, ws := range workspaces[1:] {
+			everywhere = everywhere && ws.Supports(op.Name)
+		}
+		if op.Supported && everywhere {
+			fmt.Fprintln(w, op.Name)
+		}
+	}
+	return nil
+}
+
+// FormatCredentials outputs the names of the workspaces that have a
+// credential.
+func (f *IDOnlyFormatter) FormatCredentials(w io.Writer, creds []credentials.WorkspaceCredential) error {
+	for _, c := range creds {
+		if c.Store != "" && c.Workspace != "" {
+			fmt.Fprintln(w, c.Workspace)
+		}
+	}
+	return nil
+}
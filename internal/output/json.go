@@ -6,17 +6,23 @@ import (
 
 	"github.com/alexbrand/backlog/internal/backend"
 	"github.com/alexbrand/backlog/internal/config"
+	"github.com/alexbrand/backlog/internal/credentials"
+	"github.com/alexbrand/backlog/internal/template"
 )
 
 // JSONFormatter outputs data in JSON format.
-type JSONFormatter struct{}
+type JSONFormatter struct {
+	// Fields selects the keys of each task in task lists. Nil means every
+	// key.
+	Fields []string
+}
 
 // FormatTask outputs a single task as JSON.
 func (f *JSONFormatter) FormatTask(w io.Writer, task *backend.Task) error {
-	// If relations are present in Meta, include blocks/blocked_by arrays at the top level
+	// If relations are present in Meta, include blocks/blocked_by/children arrays at the top level
 	if task.Meta != nil {
 		if relations, ok := task.Meta["relations"].([]backend.Relation); ok && len(relations) > 0 {
-			var blocks, blockedBy []map[string]any
+			var blocks, blockedBy, children []map[string]any
 			for _, r := range relations {
 				entry := map[string]any{
 					"id":     r.TaskID,
@@ -27,6 +33,8 @@ func (f *JSONFormatter) FormatTask(w io.Writer, task *backend.Task) error {
 					blocks = append(blocks, entry)
 				} else if r.Type == backend.RelationBlockedBy {
 					blockedBy = append(blockedBy, entry)
+				} else if r.Type == backend.RelationChild {
+					children = append(children, entry)
 				}
 			}
 			result := map[string]any{
@@ -41,12 +49,29 @@ func (f *JSONFormatter) FormatTask(w io.Writer, task *backend.Task) error {
 				"updated":     task.Updated,
 				"url":         task.URL,
 			}
+			if task.Estimate > 0 {
+				result["estimate"] = task.Estimate
+			}
+			if task.Spent > 0 {
+				result["spent"] = task.Spent
+			}
+			addTaskTimes(result, task)
+			if task.ChecklistProgress != nil {
+				result["checklist"] = task.Checklist
+				result["checklist_progress"] = task.ChecklistProgress
+			}
 			if len(blocks) > 0 {
 				result["blocks"] = blocks
 			}
 			if len(blockedBy) > 0 {
 				result["blocked_by"] = blockedBy
 			}
+			if task.Parent != "" {
+				result["parent"] = task.Parent
+			}
+			if len(children) > 0 {
+				result["children"] = children
+			}
 			return f.writeJSON(w, result)
 		}
 	}
@@ -55,7 +80,70 @@ func (f *JSONFormatter) FormatTask(w io.Writer, task *backend.Task) error {
 
 // FormatTaskList outputs a list of tasks as JSON.
 func (f *JSONFormatter) FormatTaskList(w io.Writer, list *backend.TaskList) error {
-	return f.writeJSON(w, list)
+	if f.Fields == nil {
+		return f.writeJSON(w, list)
+	}
+	result := map[string]any{
+		"tasks":   f.selectFields(list.Tasks),
+		"count":   list.Count,
+		"hasMore": list.HasMore,
+	}
+	if len(list.Warnings) > 0 {
+		result["warnings"] = list.Warnings
+	}
+	if list.Skipped > 0 {
+		result["skipped"] = list.Skipped
+	}
+	if list.Filters != nil {
+		result["filters"] = list.Filters
+	}
+	return f.writeJSON(w, result)
+}
+
+// selectFields returns tasks for a task list, reduced to the selected
+// fields if any are set.
+func (f *JSONFormatter) selectFields(tasks []backend.Task) any {
+	if f.Fields == nil {
+		return tasks
+	}
+	selected := make([]map[string]any, len(tasks))
+	for i := range tasks {
+		selected[i] = fieldsJSON(&tasks[i], f.Fields)
+	}
+	return selected
+}
+
+// FormatGroupedTaskList outputs grouped tasks as JSON, with groups keyed by
+// their value and "order" giving the display order of the keys.
+func (f *JSONFormatter) FormatGroupedTaskList(w io.Writer, groupBy string, groups []TaskGroup, list *backend.TaskList) error {
+	byKey := make(map[string]any, len(groups))
+	order := make([]string, len(groups))
+	for i, group := range groups {
+		byKey[group.Key] = f.selectFields(group.Tasks)
+		order[i] = group.Key
+	}
+	result := map[string]any{
+		"group_by": groupBy,
+		"groups":   byKey,
+		"order":    order,
+		"count":    list.Count,
+		"hasMore":  list.HasMore,
+	}
+	if len(list.Warnings) > 0 {
+		result["warnings"] = list.Warnings
+	}
+	if list.Skipped > 0 {
+		result["skipped"] = list.Skipped
+	}
+	if list.Filters != nil {
+		result["filters"] = list.Filters
+	}
+	return f.writeJSON(w, result)
+}
+
+// FormatTaskCount outputs the number of matching tasks as JSON.
+func (f *JSONFormatter) FormatTaskCount(w io.Writer, count int) error {
+	return f.writeJSON(w, map[string]any{"count": count})
 }
 
 // FormatTaskWithComments outputs a single task with its comments as JSON.
@@ -75,14 +163,52 @@ func (f *JSONFormatter) FormatTaskWithComments(w io.Writer, task *backend.Task,
 		"meta":        task.Meta,
 		"comments":    comments,
 	}
+	if task.Estimate > 0 {
+		result["estimate"] = task.Estimate
+	}
+	if task.Spent > 0 {
+		result["spent"] = task.Spent
+	}
+	addTaskTimes(result, task)
 	return f.writeJSON(w, result)
 }
 
+// addTaskTimes adds a task's start and completion times and its duration to
+// a JSON object built field by field.
+func addTaskTimes(result map[string]any, task *backend.Task) {
+	if task.StartedAt != nil {
+		result["started_at"] = task.StartedAt
+	}
+	if task.CompletedAt != nil {
+		result["completed_at"] = task.CompletedAt
+	}
+	if task.Duration > 0 {
+		result["duration"] = task.Duration
+	}
+	if task.TimeApproximate {
+		result["time_approximate"] = true
+	}
+}
+
 // FormatComment outputs a single comment as JSON.
 func (f *JSONFormatter) FormatComment(w io.Writer, comment *backend.Comment) error {
 	return f.writeJSON(w, comment)
 }
 
+// FormatCommentEdited outputs a comment whose body was replaced as JSON.
+func (f *JSONFormatter) FormatCommentEdited(w io.Writer, comment *backend.Comment) error {
+	return f.writeJSON(w, comment)
+}
+
+// FormatCommentDeleted outputs the result of deleting a comment as JSON.
+func (f *JSONFormatter) FormatCommentDeleted(w io.Writer, taskID, commentID string) error {
+	return f.writeJSON(w, map[string]any{
+		"task_id":    taskID,
+		"comment_id": commentID,
+		"deleted":    true,
+	})
+}
+
 // FormatComments outputs a list of comments as JSON.
 func (f *JSONFormatter) FormatComments(w io.Writer, comments []backend.Comment) error {
 	return f.writeJSON(w, map[string]any{
@@ -91,6 +217,26 @@ func (f *JSONFormatter) FormatComments(w io.Writer, comments []backend.Comment)
 	})
 }
 
+// sourcedComment is a comment in "comment list" JSON output, with the
+// workspace it came from.
+type sourcedComment struct {
+	backend.Comment
+	Source string `json:"source"`
+}
+
+// FormatCommentList outputs comments as JSON, each with its source
+// workspace.
+func (f *JSONFormatter) FormatCommentList(w io.Writer, source string, comments []backend.Comment) error {
+	sourced := make([]sourcedComment, len(comments))
+	for i, comment := range comments {
+		sourced[i] = sourcedComment{Comment: comment, Source: source}
+	}
+	return f.writeJSON(w, map[string]any{
+		"comments": sourced,
+		"count":    len(comments),
+	})
+}
+
 // FormatCreated outputs the result of creating a task as JSON.
 func (f *JSONFormatter) FormatCreated(w io.Writer, task *backend.Task) error {
 	return f.writeJSON(w, map[string]any{
@@ -100,29 +246,51 @@ func (f *JSONFormatter) FormatCreated(w io.Writer, task *backend.Task) error {
 		"status":   task.Status,
 		"labels":   task.Labels,
 		"priority": task.Priority,
+		"created":  !existingTask(task),
 	})
 }
 
 // FormatMoved outputs the result of moving a task as JSON.
-func (f *JSONFormatter) FormatMoved(w io.Writer, task *backend.Task, oldStatus, newStatus backend.Status) error {
+func (f *JSONFormatter) FormatMoved(w io.Writer, task *backend.Task, oldStatus, newStatus backend.Status, changes []backend.FieldChange) error {
 	return f.writeJSON(w, map[string]any{
 		"id":       task.ID,
 		"title":    task.Title,
 		"status":   newStatus,
 		"labels":   task.Labels,
 		"priority": task.Priority,
+		"changes":  jsonChanges(changes),
 	})
 }
 
 // FormatUpdated outputs the result of updating a task as JSON.
-func (f *JSONFormatter) FormatUpdated(w io.Writer, task *backend.Task) error {
-	return f.writeJSON(w, map[string]any{
+func (f *JSONFormatter) FormatUpdated(w io.Writer, task *backend.Task, changes []backend.FieldChange) error {
+	result := map[string]any{
 		"id":       task.ID,
 		"title":    task.Title,
 		"url":      task.URL,
 		"labels":   task.Labels,
 		"priority": task.Priority,
-	})
+		"changes":  jsonChanges(changes),
+	}
+	if task.ChecklistProgress != nil {
+		result["checklist"] = task.Checklist
+		result["checklist_progress"] = task.ChecklistProgress
+	}
+	return f.writeJSON(w, result)
+}
+
+// jsonChanges returns field changes as an object keyed by field name, with
+// the "before" and "after" values of each. It is empty, not null, when
+// nothing changed.
+func jsonChanges(changes []backend.FieldChange) map[string]any {
+	result := make(map[string]any, len(changes))
+	for _, change := range changes {
+		result[change.Field] = map[string]any{
+			"before": change.Before,
+			"after":  change.After,
+		}
+	}
+	return result
 }
 
 // FormatClaimed outputs the result of claiming a task as JSON.
@@ -139,6 +307,43 @@ func (f *JSONFormatter) FormatClaimed(w io.Writer, task *backend.Task, agentID s
 	})
 }
 
+// FormatNextClaimed outputs the task claimed by "next --claim" as JSON,
+// including the candidates that were skipped.
+func (f *JSONFormatter) FormatNextClaimed(w io.Writer, result *backend.ClaimResult, agentID string, skipped []backend.SkippedCandidate) error {
+	if skipped == nil {
+		skipped = []backend.SkippedCandidate{}
+	}
+	claimed := claimedJSON(result, agentID)
+	claimed["candidates_skipped"] = skipped
+	return f.writeJSON(w, claimed)
+}
+
+// FormatNextClaimedBatch outputs the tasks claimed by "next --claim --count"
+// as a JSON array. Skipped candidates are left out so the output is just
+// the claims; they are reported when nothing could be claimed.
+func (f *JSONFormatter) FormatNextClaimedBatch(w io.Writer, results []backend.ClaimResult, agentID string, _ []backend.SkippedCandidate) error {
+	claims := make([]map[string]any, len(results))
+	for i := range results {
+		claims[i] = claimedJSON(&results[i], agentID)
+	}
+	return f.writeJSON(w, claims)
+}
+
+// claimedJSON returns the JSON fields describing a claimed task.
+func claimedJSON(result *backend.ClaimResult, agentID string) map[string]any {
+	task := result.Task
+	return map[string]any{
+		"id":           task.ID,
+		"title":        task.Title,
+		"status":       task.Status,
+		"agent":        agentID,
+		"alreadyOwned": result.AlreadyOwned,
+		"url":          task.URL,
+		"labels":       task.Labels,
+		"assignee":     task.Assignee,
+	}
+}
+
 // FormatReleased outputs the result of releasing a task as JSON.
 func (f *JSONFormatter) FormatReleased(w io.Writer, task *backend.Task) error {
 	return f.writeJSON(w, map[string]any{
@@ -153,21 +358,32 @@ func (f *JSONFormatter) FormatReleased(w io.Writer, task *backend.Task) error {
 
 // FormatSynced outputs the result of a sync operation as JSON.
 func (f *JSONFormatter) FormatSynced(w io.Writer, result *backend.SyncResult) error {
+	resolved := result.Resolved
+	if resolved == nil {
+		resolved = []string{}
+	}
 	return f.writeJSON(w, map[string]any{
 		"created":   result.Created,
 		"updated":   result.Updated,
 		"deleted":   result.Deleted,
 		"pushed":    result.Pushed,
 		"conflicts": result.Conflicts,
+		"resolved":  resolved,
 	})
 }
 
+// FormatSyncStatus outputs how the backlog has diverged from its remote as JSON.
+func (f *JSONFormatter) FormatSyncStatus(w io.Writer, status *backend.SyncStatus) error {
+	return f.writeJSON(w, status)
+}
+
 // FormatError outputs an error as JSON.
-func (f *JSONFormatter) FormatError(w io.Writer, code string, message string, details map[string]any) error {
+func (f *JSONFormatter) FormatError(w io.Writer, code string, errorCode string, message string, details map[string]any) error {
 	errObj := map[string]any{
 		"error": map[string]any{
-			"code":    code,
-			"message": message,
+			"code":       code,
+			"error_code": errorCode,
+			"message":    message,
 		},
 	}
 	if details != nil {
@@ -183,13 +399,48 @@ func (f *JSONFormatter) FormatConfig(w io.Writer, cfg *config.Config) error {
 	return f.writeJSON(w, cfg)
 }
 
+// FormatResolvedConfig outputs the effective configuration as a JSON object
+// keyed by config key.
+func (f *JSONFormatter) FormatResolvedConfig(w io.Writer, values []config.ResolvedValue) error {
+	byKey := make(map[string]config.ResolvedValue, len(values))
+	for _, v := range values {
+		byKey[v.Key] = v
+	}
+	return f.writeJSON(w, map[string]any{"values": byKey})
+}
+
+// FormatConfigSettings outputs workspace settings as a JSON object keyed by
+// setting name.
+func (f *JSONFormatter) FormatConfigSettings(w io.Writer, workspace string, settings []config.Setting) error {
+	values := make(map[string]string, len(settings))
+	var defaults []string
+	for _, setting := range settings {
+		values[setting.Key] = setting.Value
+		if setting.Default {
+			defaults = append(defaults, setting.Key)
+		}
+	}
+	if defaults == nil {
+		defaults = []string{}
+	}
+	return f.writeJSON(w, map[string]any{
+		"workspace": workspace,
+		"settings":  values,
+		"defaults":  defaults,
+	})
+}
+
 // FormatHealthCheck outputs health check results as JSON.
 func (f *JSONFormatter) FormatHealthCheck(w io.Writer, backendName string, ws *config.Workspace, status *backend.HealthStatus) error {
 	result := map[string]any{
-		"backend": backendName,
-		"healthy": status.OK,
-		"message": status.Message,
-		"latency": status.Latency.String(),
+		"backend":    backendName,
+		"healthy":    status.OK,
+		"message":    status.Message,
+		"latency":    status.Latency.String(),
+		"latency_ms": float64(status.Latency.Microseconds()) / 1000,
+	}
+	if status.RateLimit != nil {
+		result["rate_limit"] = status.RateLimit
 	}
 	if ws != nil {
 		wsInfo := map[string]any{}
@@ -211,6 +462,17 @@ func (f *JSONFormatter) FormatDeleted(w io.Writer, id string) error {
 	})
 }
 
+// FormatRestored outputs the result of restoring a soft-deleted task as JSON.
+func (f *JSONFormatter) FormatRestored(w io.Writer, task *backend.Task) error {
+	return f.writeJSON(w, map[string]any{
+		"id":       task.ID,
+		"title":    task.Title,
+		"status":   task.Status,
+		"url":      task.URL,
+		"restored": true,
+	})
+}
+
 // FormatReordered outputs the result of reordering a task as JSON.
 func (f *JSONFormatter) FormatReordered(w io.Writer, task *backend.Task) error {
 	return f.writeJSON(w, map[string]any{
@@ -242,6 +504,126 @@ func (f *JSONFormatter) FormatUnlinked(w io.Writer, sourceID, targetID string) e
 	})
 }
 
+// FormatLabels outputs labels as JSON.
+func (f *JSONFormatter) FormatLabels(w io.Writer, labels []backend.Label) error {
+	if labels == nil {
+		labels = []backend.Label{}
+	}
+	return f.writeJSON(w, map[string]any{
+		"labels": labels,
+		"count":  len(labels),
+	})
+}
+
+// FormatLabelChange outputs the result of renaming or removing a label as
+// JSON.
+func (f *JSONFormatter) FormatLabelChange(w io.Writer, change *backend.LabelChange) error {
+	return f.writeJSON(w, change)
+}
+
+// FormatTemplates outputs a list of task templates as JSON.
+func (f *JSONFormatter) FormatTemplates(w io.Writer, templates []template.Template) error {
+	return f.writeJSON(w, map[string]any{
+		"templates": templates,
+		"count":     len(templates),
+	})
+}
+
+// FormatDoctorReport outputs workspace integrity findings as JSON.
+func (f *JSONFormatter) FormatDoctorReport(w io.Writer, report *backend.DoctorReport) error {
+	return f.writeJSON(w, report)
+}
+
+// FormatReindexed outputs the result of rebuilding a task index as JSON.
+func (f *JSONFormatter) FormatReindexed(w io.Writer, result *backend.ReindexResult) error {
+	return f.writeJSON(w, result)
+}
+
+// FormatHistory outputs a task's history as JSON.
+func (f *JSONFormatter) FormatHistory(w io.Writer, history *backend.TaskHistory) error {
+	if history.Entries == nil {
+		history.Entries = []backend.HistoryEntry{}
+	}
+	return f.writeJSON(w, history)
+}
+
+// FormatBlame outputs the last change to each field of a task as JSON.
+func (f *JSONFormatter) FormatBlame(w io.Writer, blame *backend.TaskBlame) error {
+	if blame.Fields == nil {
+		blame.Fields = []backend.FieldBlame{}
+	}
+	return f.writeJSON(w, blame)
+}
+
+// FormatUndone outputs the mutation that undo reverted as JSON.
+func (f *JSONFormatter) FormatUndone(w io.Writer, entry *backend.UndoEntry) error {
+	return f.writeJSON(w, struct {
+		*backend.UndoEntry
+		Undone bool `json:"undone"`
+	}{entry, true})
+}
+
+// FormatUndoEntries outputs the mutations that can be undone as JSON.
+func (f *JSONFormatter) FormatUndoEntries(w io.Writer, entries []backend.UndoEntry) error {
+	if entries == nil {
+		entries = []backend.UndoEntry{}
+	}
+	return f.writeJSON(w, map[string]any{"entries": entries})
+}
+
+// FormatUncommitted outputs the uncommitted backlog files as JSON.
+func (f *JSONFormatter) FormatUncommitted(w io.Writer, files []string) error {
+	if files == nil {
+		files = []string{}
+	}
+	return f.writeJSON(w, map[string]any{"clean": len(files) == 0, "files": files})
+}
+
+// FormatDryRun outputs the change a command would make as JSON.
+func (f *JSONFormatter) FormatDryRun(w io.Writer, result *backend.DryRunResult) error {
+	return f.writeJSON(w, struct {
+		DryRun bool `json:"dry_run"`
+		*backend.DryRunResult
+	}{DryRun: true, DryRunResult: result})
+}
+
+// FormatWhoami outputs the resolved agent identity as JSON.
+func (f *JSONFormatter) FormatWhoami(w io.Writer, identity *backend.AgentIdentity) error {
+	return f.writeJSON(w, identity)
+}
+
+// FormatTimeReport outputs duration totals as JSON.
+func (f *JSONFormatter) FormatTimeReport(w io.Writer, report *backend.TimeReport) error {
+	return f.writeJSON(w, report)
+}
+
+// FormatChangelog outputs a changelog as JSON.
+func (f *JSONFormatter) FormatChangelog(w io.Writer, changelog *backend.Changelog) error {
+	return f.writeJSON(w, changelog)
+}
+
+// FormatCapabilities outputs a backend's optional operations as JSON.
+func (f *JSONFormatter) FormatCapabilities(w io.Writer, caps *backend.Capabilities) error {
+	return f.writeJSON(w, caps)
+}
+
+// FormatWorkspaceCapabilities outputs the optional operations of each
+// workspace's backend as JSON.
+func (f *JSONFormatter) FormatWorkspaceCapabilities(w io.Writer, workspaces []backend.WorkspaceCapabilities) error {
+	if workspaces == nil {
+		workspaces = []backend.WorkspaceCapabilities{}
+	}
+	return f.writeJSON(w, map[string]any{"workspaces": workspaces})
+}
+
+// FormatCredentials outputs where workspaces' credentials come from as JSON.
+func (f *JSONFormatter) FormatCredentials(w io.Writer, creds []credentials.WorkspaceCredential) error {
+	if creds == nil {
+		creds = []credentials.WorkspaceCredential{}
+	}
+	return f.writeJSON(w, creds)
+}
+
 // writeJSON encodes the value as indented JSON and writes it to w.
 func (f *JSONFormatter) writeJSON(w io.Writer, v any) error {
 	enc := json.NewEncoder(w)
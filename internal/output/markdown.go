@@ -0,0 +1,117 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// MarkdownFormatter renders a task as a markdown document for pasting into
+// pull requests and issues. The document has the same structure whichever
+// backend the task came from. Output other than tasks is the table format's,
+// without colors.
+type MarkdownFormatter struct {
+	TableFormatter
+}
+
+// FormatTask outputs a task as a markdown document: a heading with the title
+// and ID, a table of its fields, the description and its relations.
+func (f *MarkdownFormatter) FormatTask(w io.Writer, task *backend.Task) error {
+	fmt.Fprintf(w, "# %s (%s)\n", task.Title, task.ID)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "| Field | Value |")
+	fmt.Fprintln(w, "|-------|-------|")
+	row := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "| %s | %s |\n", name, markdownCell(value))
+		}
+	}
+	row("Status", string(task.Status))
+	row("Priority", string(task.Priority))
+	if task.Assignee != "" {
+		row("Assignee", "@"+task.Assignee)
+	} else {
+		row("Assignee", "—")
+	}
+	row("Labels", strings.Join(task.Labels, ", "))
+	row("Parent", task.Parent)
+	row("Estimate", durationText(task.Estimate))
+	row("Spent", durationText(task.Spent))
+	row("Created", timeText(&task.Created))
+	row("Updated", timeText(&task.Updated))
+	row("Started", timeText(task.StartedAt))
+	row("Completed", timeText(task.CompletedAt))
+	row("Duration", durationText(task.Duration))
+	if task.ChecklistProgress != nil {
+		row("Checklist", task.ChecklistProgress.String())
+	}
+	row("URL", task.URL)
+
+	if description := strings.TrimSpace(task.Description); description != "" {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "## Description")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, description)
+	}
+
+	relations, _ := task.Meta["relations"].([]backend.Relation)
+	if len(relations) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "## Relations")
+		fmt.Fprintln(w)
+		for _, r := range relations {
+			fmt.Fprintf(w, "- %s **%s**: %s (%s)\n", relationText(r.Type), r.TaskID, r.TaskTitle, r.TaskStatus)
+		}
+	}
+	return nil
+}
+
+// FormatTaskWithComments outputs a task as a markdown document followed by
+// its comments.
+func (f *MarkdownFormatter) FormatTaskWithComments(w io.Writer, task *backend.Task, comments []backend.Comment) error {
+	if err := f.FormatTask(w, task); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "## Comments")
+	fmt.Fprintln(w)
+	if len(comments) == 0 {
+		fmt.Fprintln(w, "_No comments._")
+		return nil
+	}
+	for i, comment := range comments {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "### @%s, %s\n", comment.Author, comment.Created.Format("2006-01-02 15:04"))
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, strings.TrimSpace(comment.Body))
+	}
+	return nil
+}
+
+// relationText describes a relation from the shown task's side.
+func relationText(t backend.RelationType) string {
+	switch t {
+	case backend.RelationBlocks:
+		return "Blocks"
+	case backend.RelationBlockedBy:
+		return "Blocked by"
+	case backend.RelationParent:
+		return "Sub-task of"
+	case backend.RelationChild:
+		return "Sub-task"
+	}
+	return string(t)
+}
+
+// markdownCell escapes a value for a markdown table cell, which can't hold
+// pipes or line breaks.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -3,13 +3,21 @@ package output
 import (
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/alexbrand/backlog/internal/backend"
 	"github.com/alexbrand/backlog/internal/config"
+	"github.com/alexbrand/backlog/internal/credentials"
+	"github.com/alexbrand/backlog/internal/template"
 )
 
 // PlainFormatter outputs data in plain text format, suitable for scripting.
-type PlainFormatter struct{}
+type PlainFormatter struct {
+	// Fields selects and orders the tab-separated values of task lists.
+	// Nil means id, status, priority, and title.
+	Fields []string
+}
 
 // FormatTask outputs a single task in plain format.
 // Includes all task fields for detailed view (used by show command).
@@ -42,6 +50,10 @@ func joinLabels(labels []string) string {
 
 // formatTaskSummary outputs a single task in summary format (one line).
 func (f *PlainFormatter) formatTaskSummary(w io.Writer, task *backend.Task) error {
+	if f.Fields != nil {
+		fmt.Fprintln(w, strings.Join(fieldsText(task, f.Fields), "\t"))
+		return nil
+	}
 	fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", task.ID, task.Status, task.Priority, task.Title)
 	return nil
 }
@@ -57,6 +69,26 @@ func (f *PlainFormatter) FormatTaskList(w io.Writer, list *backend.TaskList) err
 	return nil
 }
 
+// FormatGroupedTaskList outputs grouped tasks in plain format, with each
+// summary line prefixed by its group's value.
+func (f *PlainFormatter) FormatGroupedTaskList(w io.Writer, groupBy string, groups []TaskGroup, list *backend.TaskList) error {
+	for _, group := range groups {
+		for _, task := range group.Tasks {
+			fmt.Fprintf(w, "%s\t", group.Key)
+			if err := f.formatTaskSummary(w, &task); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// FormatTaskCount outputs the number of matching tasks.
+func (f *PlainFormatter) FormatTaskCount(w io.Writer, count int) error {
+	fmt.Fprintln(w, count)
+	return nil
+}
+
 // FormatTaskWithComments outputs a single task with its comments in plain format.
 func (f *PlainFormatter) FormatTaskWithComments(w io.Writer, task *backend.Task, comments []backend.Comment) error {
 	if err := f.FormatTask(w, task); err != nil {
@@ -72,6 +104,19 @@ func (f *PlainFormatter) FormatComment(w io.Writer, comment *backend.Comment) er
 	return nil
 }
 
+// FormatCommentEdited outputs a comment whose body was replaced in plain
+// format.
+func (f *PlainFormatter) FormatCommentEdited(w io.Writer, comment *backend.Comment) error {
+	return f.FormatComment(w, comment)
+}
+
+// FormatCommentDeleted outputs the result of deleting a comment in plain
+// format.
+func (f *PlainFormatter) FormatCommentDeleted(w io.Writer, taskID, commentID string) error {
+	fmt.Fprintf(w, "%s\t%s\n", taskID, commentID)
+	return nil
+}
+
 // FormatComments outputs a list of comments in plain format.
 func (f *PlainFormatter) FormatComments(w io.Writer, comments []backend.Comment) error {
 	for _, comment := range comments {
@@ -82,6 +127,15 @@ func (f *PlainFormatter) FormatComments(w io.Writer, comments []backend.Comment)
 	return nil
 }
 
+// FormatCommentList outputs comments in plain format, one per line: the ID,
+// author, creation time, and first line of the body.
+func (f *PlainFormatter) FormatCommentList(w io.Writer, _ string, comments []backend.Comment) error {
+	for _, comment := range comments {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", comment.ID, comment.Author, comment.Created.Format(time.RFC3339), firstLine(comment.Body))
+	}
+	return nil
+}
+
 // FormatCreated outputs the result of creating a task in plain format.
 func (f *PlainFormatter) FormatCreated(w io.Writer, task *backend.Task) error {
 	fmt.Fprintln(w, task.ID)
@@ -89,13 +143,13 @@ func (f *PlainFormatter) FormatCreated(w io.Writer, task *backend.Task) error {
 }
 
 // FormatMoved outputs the result of moving a task in plain format.
-func (f *PlainFormatter) FormatMoved(w io.Writer, task *backend.Task, oldStatus, newStatus backend.Status) error {
+func (f *PlainFormatter) FormatMoved(w io.Writer, task *backend.Task, oldStatus, newStatus backend.Status, _ []backend.FieldChange) error {
 	fmt.Fprintf(w, "%s\t%s\t%s\n", task.ID, oldStatus, newStatus)
 	return nil
 }
 
 // FormatUpdated outputs the result of updating a task in plain format.
-func (f *PlainFormatter) FormatUpdated(w io.Writer, task *backend.Task) error {
+func (f *PlainFormatter) FormatUpdated(w io.Writer, task *backend.Task, _ []backend.FieldChange) error {
 	fmt.Fprintln(w, task.ID)
 	return nil
 }
@@ -106,21 +160,59 @@ func (f *PlainFormatter) FormatClaimed(w io.Writer, task *backend.Task, agentID
 	return nil
 }
 
+// FormatNextClaimed outputs the claimed task in plain format. Skipped
+// candidates are left out so the output stays a single line.
+func (f *PlainFormatter) FormatNextClaimed(w io.Writer, result *backend.ClaimResult, agentID string, _ []backend.SkippedCandidate) error {
+	return f.FormatClaimed(w, result.Task, agentID, result.AlreadyOwned)
+}
+
+// FormatNextClaimedBatch outputs one line per claimed task in plain format.
+func (f *PlainFormatter) FormatNextClaimedBatch(w io.Writer, results []backend.ClaimResult, agentID string, _ []backend.SkippedCandidate) error {
+	for _, result := range results {
+		if err := f.FormatClaimed(w, result.Task, agentID, result.AlreadyOwned); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // FormatReleased outputs the result of releasing a task in plain format.
 func (f *PlainFormatter) FormatReleased(w io.Writer, task *backend.Task) error {
 	fmt.Fprintf(w, "%s\t%s\n", task.ID, task.Status)
 	return nil
 }
 
-// FormatSynced outputs the result of a sync operation in plain format.
+// FormatSynced outputs the result of a sync operation in plain format: a
+// tab-separated line of counts, then one "resolved<TAB>id" line per task
+// whose conflicts were resolved.
 func (f *PlainFormatter) FormatSynced(w io.Writer, result *backend.SyncResult) error {
 	fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%d\n",
 		result.Created, result.Updated, result.Deleted, result.Pushed, result.Conflicts)
+	for _, id := range result.Resolved {
+		fmt.Fprintf(w, "resolved\t%s\n", id)
+	}
+	return nil
+}
+
+// FormatSyncStatus outputs a tab-separated summary line (upstream, ahead,
+// behind, dirty, in sync), then one "change<TAB>id" line per task a pull
+// would touch.
+func (f *PlainFormatter) FormatSyncStatus(w io.Writer, status *backend.SyncStatus) error {
+	fmt.Fprintf(w, "%s\t%d\t%d\t%t\t%t\n", status.Upstream, status.Ahead, status.Behind, status.Dirty, status.InSync)
+	for _, id := range status.Updated {
+		fmt.Fprintf(w, "updated\t%s\n", id)
+	}
+	for _, id := range status.Added {
+		fmt.Fprintf(w, "added\t%s\n", id)
+	}
+	for _, id := range status.Removed {
+		fmt.Fprintf(w, "removed\t%s\n", id)
+	}
 	return nil
 }
 
 // FormatError outputs an error in plain format.
-func (f *PlainFormatter) FormatError(w io.Writer, code string, message string, details map[string]any) error {
+func (f *PlainFormatter) FormatError(w io.Writer, code string, errorCode string, message string, details map[string]any) error {
 	fmt.Fprintf(w, "error: %s\n", message)
 	return nil
 }
@@ -131,6 +223,24 @@ func (f *PlainFormatter) FormatConfig(w io.Writer, cfg *config.Config) error {
 	return nil
 }
 
+// FormatResolvedConfig outputs the effective configuration in plain format,
+// one tab-separated key, value, source, and origin per line.
+func (f *PlainFormatter) FormatResolvedConfig(w io.Writer, values []config.ResolvedValue) error {
+	for _, v := range values {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", v.Key, v.Value, v.Source, v.Origin)
+	}
+	return nil
+}
+
+// FormatConfigSettings outputs workspace settings in plain format, one
+// key=value pair per line.
+func (f *PlainFormatter) FormatConfigSettings(w io.Writer, workspace string, settings []config.Setting) error {
+	for _, setting := range settings {
+		fmt.Fprintf(w, "%s=%s\n", setting.Key, setting.Value)
+	}
+	return nil
+}
+
 // FormatHealthCheck outputs health check results in plain format.
 func (f *PlainFormatter) FormatHealthCheck(w io.Writer, backendName string, ws *config.Workspace, status *backend.HealthStatus) error {
 	if status.OK {
@@ -147,6 +257,12 @@ func (f *PlainFormatter) FormatDeleted(w io.Writer, id string) error {
 	return nil
 }
 
+// FormatRestored outputs the result of restoring a task in plain format.
+func (f *PlainFormatter) FormatRestored(w io.Writer, task *backend.Task) error {
+	fmt.Fprintf(w, "%s\t%s\n", task.ID, task.Status)
+	return nil
+}
+
 // FormatReordered outputs the result of reordering a task in plain format.
 func (f *PlainFormatter) FormatReordered(w io.Writer, task *backend.Task) error {
 	fmt.Fprintln(w, task.ID)
@@ -164,3 +280,187 @@ func (f *PlainFormatter) FormatUnlinked(w io.Writer, sourceID, targetID string)
 	fmt.Fprintf(w, "%s\t%s\n", sourceID, targetID)
 	return nil
 }
+
+// FormatLabels outputs labels in plain format, one tab-separated label
+// per line: name, color, description, and the task count when counted.
+func (f *PlainFormatter) FormatLabels(w io.Writer, labels []backend.Label) error {
+	for _, label := range labels {
+		if label.Tasks != nil {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", label.Name, label.Color, label.Description, *label.Tasks)
+		} else {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", label.Name, label.Color, label.Description)
+		}
+	}
+	return nil
+}
+
+// FormatLabelChange outputs the result of renaming or removing a label in
+// plain format: the action, label, and new name, then one changed task ID
+// per line.
+func (f *PlainFormatter) FormatLabelChange(w io.Writer, change *backend.LabelChange) error {
+	fmt.Fprintf(w, "%s\t%s\t%s\n", change.Action, change.Label, change.NewName)
+	for _, id := range change.Tasks {
+		fmt.Fprintln(w, id)
+	}
+	return nil
+}
+
+// FormatTemplates outputs a list of task templates in plain format.
+func (f *PlainFormatter) FormatTemplates(w io.Writer, templates []template.Template) error {
+	for _, t := range templates {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", t.Name, t.Title, strings.Join(t.Placeholders, ","))
+	}
+	return nil
+}
+
+// FormatDoctorReport outputs workspace integrity findings in plain format.
+func (f *PlainFormatter) FormatDoctorReport(w io.Writer, report *backend.DoctorReport) error {
+	for _, finding := range report.Findings {
+		status := "open"
+		if finding.Fixed {
+			status = "fixed"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			finding.Severity, finding.Check, finding.TaskID, finding.Path, status, finding.Message)
+	}
+	return nil
+}
+
+// FormatReindexed outputs the result of rebuilding a task index in plain
+// format, followed by one line per skipped file.
+func (f *PlainFormatter) FormatReindexed(w io.Writer, result *backend.ReindexResult) error {
+	fmt.Fprintf(w, "%d\t%s\n", result.Tasks, result.Path)
+	for _, skipped := range result.Skipped {
+		fmt.Fprintf(w, "skipped\t%s\n", skipped)
+	}
+	return nil
+}
+
+// FormatHistory outputs a task's history in plain format, one
+// tab-separated event per line.
+func (f *PlainFormatter) FormatHistory(w io.Writer, history *backend.TaskHistory) error {
+	for _, entry := range history.Entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			entry.Timestamp.Format(time.RFC3339), entry.Action, entry.Status, entry.Agent, entry.Commit)
+		if entry.Diff != "" {
+			fmt.Fprint(w, entry.Diff)
+		}
+	}
+	return nil
+}
+
+// FormatBlame outputs the last change to each field of a task in plain
+// format, one tab-separated field per line: field, value, commit, author,
+// agent, timestamp. Uncommitted values have the commit "uncommitted".
+func (f *PlainFormatter) FormatBlame(w io.Writer, blame *backend.TaskBlame) error {
+	for _, field := range blame.Fields {
+		commit, timestamp := field.Commit, ""
+		if field.Timestamp != nil {
+			timestamp = field.Timestamp.Format(time.RFC3339)
+		}
+		if field.Uncommitted {
+			commit = "uncommitted"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", field.Field, field.Value, commit, field.Author, field.Agent, timestamp)
+	}
+	return nil
+}
+
+// FormatUndone outputs the mutation that undo reverted in plain format: the
+// action and task ID, then one line per restored file.
+func (f *PlainFormatter) FormatUndone(w io.Writer, entry *backend.UndoEntry) error {
+	fmt.Fprintf(w, "%s\t%s\n", entry.Action, entry.TaskID)
+	for _, file := range entry.Files {
+		fmt.Fprintln(w, file)
+	}
+	return nil
+}
+
+// FormatUndoEntries outputs the mutations that can be undone in plain
+// format, one tab-separated entry per line: timestamp, action, task ID,
+// agent.
+func (f *PlainFormatter) FormatUndoEntries(w io.Writer, entries []backend.UndoEntry) error {
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", entry.Timestamp.Format(time.RFC3339), entry.Action, entry.TaskID, entry.Agent)
+	}
+	return nil
+}
+
+// FormatUncommitted outputs the uncommitted backlog files in plain format,
+// one path per line.
+func (f *PlainFormatter) FormatUncommitted(w io.Writer, files []string) error {
+	for _, file := range files {
+		fmt.Fprintln(w, file)
+	}
+	return nil
+}
+
+// FormatDryRun outputs the change a command would make as a single
+// tab-separated line: action, ID, from status, to status, detail.
+func (f *PlainFormatter) FormatDryRun(w io.Writer, result *backend.DryRunResult) error {
+	id := ""
+	if result.Task != nil {
+		id = result.Task.ID
+	}
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", result.Action, id, result.FromStatus, result.ToStatus, result.Detail)
+	return nil
+}
+
+// FormatWhoami outputs the resolved agent identity in plain format.
+func (f *PlainFormatter) FormatWhoami(w io.Writer, identity *backend.AgentIdentity) error {
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", identity.ID, identity.Source, identity.Origin, identity.Label)
+	return nil
+}
+
+// FormatTimeReport outputs duration totals in plain format, one
+// tab-separated line per label followed by the overall total.
+func (f *PlainFormatter) FormatTimeReport(w io.Writer, report *backend.TimeReport) error {
+	for _, lt := range report.Labels {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", lt.Label, lt.Tasks, lt.Total, lt.Average)
+	}
+	fmt.Fprintf(w, "total\t%d\t%s\t%s\n", report.Tasks, report.Total, report.Average)
+	return nil
+}
+
+// FormatChangelog outputs a changelog in plain format, one tab-separated
+// section title, ID, title, completion time, URL, and comma-separated
+// authors per task.
+func (f *PlainFormatter) FormatChangelog(w io.Writer, changelog *backend.Changelog) error {
+	for _, section := range changelog.Sections {
+		for _, entry := range section.Tasks {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", section.Title, entry.ID, entry.Title,
+				entry.CompletedAt.Format(time.RFC3339), entry.URL, strings.Join(entry.Authors, ","))
+		}
+	}
+	return nil
+}
+
+// FormatCapabilities outputs a backend's optional operations in plain
+// format, one tab-separated name and true or false per line.
+func (f *PlainFormatter) FormatCapabilities(w io.Writer, caps *backend.Capabilities) error {
+	for _, op := range caps.Operations {
+		fmt.Fprintf(w, "%s\t%t\n", op.Name, op.Supported)
+	}
+	return nil
+}
+
+// FormatWorkspaceCapabilities outputs the optional operations of each
+// workspace's backend in plain format, one tab-separated workspace, name,
+// and true or false per line.
+func (f *PlainFormatter) FormatWorkspaceCapabilities(w io.Writer, workspaces []backend.WorkspaceCapabilities) error {
+	for _, ws := range workspaces {
+		for _, op := range ws.Operations {
+			fmt.Fprintf(w, "%s\t%s\t%t\n", ws.Workspace, op.Name, op.Supported)
+		}
+	}
+	return nil
+}
+
+// FormatCredentials outputs where workspaces' credentials come from in plain
+// format, one tab-separated workspace, backend, and store per line.
+func (f *PlainFormatter) FormatCredentials(w io.Writer, creds []credentials.WorkspaceCredential) error {
+	for _, c := range creds {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", c.Workspace, c.Backend, c.Store)
+	}
+	return nil
+}
@@ -5,13 +5,73 @@ import (
 	"io"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/alexbrand/backlog/internal/backend"
 	"github.com/alexbrand/backlog/internal/config"
+	"github.com/alexbrand/backlog/internal/credentials"
+	"github.com/alexbrand/backlog/internal/template"
 )
 
 // TableFormatter outputs data in a human-readable table format.
-type TableFormatter struct{}
+type TableFormatter struct {
+	// Color enables ANSI colors for statuses, priorities, and labels.
+	Color bool
+
+	// LabelColors maps label names to their registry colors.
+	LabelColors map[string]string
+
+	// Fields selects and orders the columns of task lists. Nil means
+	// DefaultFields.
+	Fields []string
+}
+
+// status returns the status, colorized if enabled.
+func (f *TableFormatter) status(s backend.Status) string {
+	if !f.Color {
+		return string(s)
+	}
+	return paint(statusColor(s), string(s))
+}
+
+// priority returns the priority, colorized if enabled.
+func (f *TableFormatter) priority(p backend.Priority) string {
+	if !f.Color {
+		return string(p)
+	}
+	return paint(priorityColor(p), string(p))
+}
+
+// label returns a label name, colorized with its registry color if enabled.
+// Labels without a color are painted in the default color so that every
+// cell carries the same invisible bytes.
+func (f *TableFormatter) label(name string) string {
+	if !f.Color {
+		return name
+	}
+	return paint(labelColor(f.LabelColors[name]), name)
+}
+
+// labels returns a comma-separated list of labels, or a dash if there are none.
+func (f *TableFormatter) labels(names []string) string {
+	if len(names) == 0 {
+		return "—"
+	}
+	painted := make([]string, len(names))
+	for i, name := range names {
+		painted[i] = f.label(name)
+	}
+	return strings.Join(painted, ", ")
+}
+
+// header returns a column header for a colorized column. It carries the
+// same invisible bytes as the cells below it so tabwriter keeps them aligned.
+func (f *TableFormatter) header(s string) string {
+	if !f.Color {
+		return s
+	}
+	return paint(ansiDefault, s)
+}
 
 // FormatTask outputs a single task in detailed format.
 func (f *TableFormatter) FormatTask(w io.Writer, task *backend.Task) error {
@@ -21,8 +81,8 @@ func (f *TableFormatter) FormatTask(w io.Writer, task *backend.Task) error {
 	fmt.Fprintln(w)
 
 	// Fields
-	fmt.Fprintf(w, "Status:    %s\n", task.Status)
-	fmt.Fprintf(w, "Priority:  %s\n", task.Priority)
+	fmt.Fprintf(w, "Status:    %s\n", f.status(task.Status))
+	fmt.Fprintf(w, "Priority:  %s\n", f.priority(task.Priority))
 
 	if task.Assignee != "" {
 		fmt.Fprintf(w, "Assignee:  @%s\n", task.Assignee)
@@ -34,22 +94,53 @@ func (f *TableFormatter) FormatTask(w io.Writer, task *backend.Task) error {
 		fmt.Fprintf(w, "Labels:    %s\n", strings.Join(task.Labels, ", "))
 	}
 
+	if task.Parent != "" {
+		fmt.Fprintf(w, "Parent:    %s\n", task.Parent)
+	}
+
 	fmt.Fprintf(w, "Created:   %s\n", task.Created.Format("2006-01-02 15:04"))
 	fmt.Fprintf(w, "Updated:   %s\n", task.Updated.Format("2006-01-02 15:04"))
 
+	if task.Estimate > 0 {
+		fmt.Fprintf(w, "Estimate:  %s\n", task.Estimate)
+	}
+	if task.Spent > 0 {
+		fmt.Fprintf(w, "Spent:     %s\n", task.Spent)
+	}
+	if task.StartedAt != nil {
+		fmt.Fprintf(w, "Started:   %s\n", task.StartedAt.Format("2006-01-02 15:04"))
+	}
+	if task.CompletedAt != nil {
+		fmt.Fprintf(w, "Completed: %s\n", task.CompletedAt.Format("2006-01-02 15:04"))
+	}
+	if task.Duration > 0 {
+		approx := ""
+		if task.TimeApproximate {
+			approx = " (approximate)"
+		}
+		fmt.Fprintf(w, "Duration:  %s%s\n", task.Duration, approx)
+	}
+	if task.ChecklistProgress != nil {
+		fmt.Fprintf(w, "Checklist: %s\n", task.ChecklistProgress)
+	}
+
 	if task.URL != "" {
 		fmt.Fprintf(w, "URL:       %s\n", task.URL)
 	}
 
 	// Relations
+	var children []backend.Relation
 	if task.Meta != nil {
 		if relations, ok := task.Meta["relations"].([]backend.Relation); ok {
 			var blocks, blockedBy []backend.Relation
 			for _, r := range relations {
-				if r.Type == backend.RelationBlocks {
+				switch r.Type {
+				case backend.RelationBlocks:
 					blocks = append(blocks, r)
-				} else if r.Type == backend.RelationBlockedBy {
+				case backend.RelationBlockedBy:
 					blockedBy = append(blockedBy, r)
+				case backend.RelationChild:
+					children = append(children, r)
 				}
 			}
 			if len(blocks) > 0 {
@@ -77,6 +168,18 @@ func (f *TableFormatter) FormatTask(w io.Writer, task *backend.Task) error {
 		fmt.Fprintln(w, task.Description)
 	}
 
+	// Sub-tasks with their statuses
+	if len(children) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "## Sub-tasks")
+		fmt.Fprintln(w)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, r := range children {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", r.TaskID, f.status(r.TaskStatus), r.TaskTitle)
+		}
+		tw.Flush()
+	}
+
 	return nil
 }
 
@@ -87,34 +190,118 @@ func (f *TableFormatter) FormatTaskList(w io.Writer, list *backend.TaskList) err
 		return nil
 	}
 
+	return f.writeTaskTable(w, list.Tasks)
+}
+
+// FormatGroupedTaskList outputs a table per group, each under a header with
+// the group's value and size.
+func (f *TableFormatter) FormatGroupedTaskList(w io.Writer, groupBy string, groups []TaskGroup, list *backend.TaskList) error {
+	if len(groups) == 0 {
+		fmt.Fprintln(w, "No tasks found.")
+		return nil
+	}
+
+	for i, group := range groups {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		key := group.Key
+		switch groupBy {
+		case "status":
+			key = f.status(backend.Status(key))
+		case "priority":
+			key = f.priority(backend.Priority(key))
+		}
+		fmt.Fprintf(w, "%s (%d)\n", key, len(group.Tasks))
+		fmt.Fprintln(w, strings.Repeat("━", 40))
+		if err := f.writeTaskTable(w, group.Tasks); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTaskTable writes tasks as an aligned table with a header row.
+func (f *TableFormatter) writeTaskTable(w io.Writer, tasks []backend.Task) error {
+	fields := f.Fields
+	if fields == nil {
+		fields = DefaultFields
+		// Show checklist progress when any task has a checklist
+		for _, task := range tasks {
+			if task.ChecklistProgress != nil {
+				fields = append(append([]string{}, DefaultFields...), "checklist")
+				break
+			}
+		}
+	}
 	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
 
 	// Header
-	fmt.Fprintln(tw, "ID\tSTATUS\tPRIORITY\tTITLE\tASSIGNEE")
+	headers := make([]string, len(fields))
+	for i, name := range fields {
+		headers[i] = taskFields[name].header
+		switch name {
+		case "status", "priority", "labels":
+			headers[i] = f.header(headers[i])
+		}
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
 
 	// Rows
-	for _, task := range list.Tasks {
-		assignee := "—"
-		if task.Assignee != "" {
-			assignee = "@" + task.Assignee
+	for _, task := range tasks {
+		cells := make([]string, len(fields))
+		for i, name := range fields {
+			cells[i] = f.cell(&task, name)
 		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+
+	return tw.Flush()
+}
 
+// cell returns a task's value for a field as a table cell, or a dash if the
+// task has none.
+func (f *TableFormatter) cell(task *backend.Task, name string) string {
+	switch name {
+	case "status":
+		return f.status(task.Status)
+	case "priority":
+		return f.priority(task.Priority)
+	case "labels":
+		return f.labels(task.Labels)
+	case "assignee":
+		if task.Assignee == "" {
+			return "—"
+		}
+		return "@" + task.Assignee
+	case "title":
 		// Truncate title if too long
-		title := task.Title
-		if len(title) > 40 {
-			title = title[:37] + "..."
+		if len(task.Title) > 40 {
+			return task.Title[:37] + "..."
 		}
-
-		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
-			task.ID,
-			task.Status,
-			task.Priority,
-			title,
-			assignee,
-		)
+		return task.Title
+	case "description":
+		// Only the first line fits in a row
+		line, _, _ := strings.Cut(task.Description, "\n")
+		if len(line) > 40 {
+			line = line[:37] + "..."
+		}
+		if line == "" {
+			return "—"
+		}
+		return line
 	}
+	if v := taskFields[name].text(task); v != "" {
+		return v
+	}
+	return "—"
+}
 
-	return tw.Flush()
+// FormatTaskCount outputs the number of matching tasks, with nothing else
+// so scripts can read it directly.
+func (f *TableFormatter) FormatTaskCount(w io.Writer, count int) error {
+	fmt.Fprintln(w, count)
+	return nil
 }
 
 // FormatTaskWithComments outputs a single task with its comments.
@@ -129,15 +316,34 @@ func (f *TableFormatter) FormatTaskWithComments(w io.Writer, task *backend.Task,
 	return f.FormatComments(w, comments)
 }
 
-// FormatComment outputs a single comment.
+// FormatComment outputs a newly added comment.
 func (f *TableFormatter) FormatComment(w io.Writer, comment *backend.Comment) error {
-	fmt.Fprintf(w, "Comment added to %s\n", comment.ID)
+	fmt.Fprintf(w, "Comment added to %s\n", comment.TaskID)
 	fmt.Fprintln(w)
-	fmt.Fprintf(w, "### %s @%s\n", comment.Created.Format("2006-01-02"), comment.Author)
-	fmt.Fprintln(w, comment.Body)
+	writeComment(w, comment)
 	return nil
 }
 
+// FormatCommentEdited outputs a comment whose body was replaced.
+func (f *TableFormatter) FormatCommentEdited(w io.Writer, comment *backend.Comment) error {
+	fmt.Fprintf(w, "Comment %s on %s updated\n", comment.ID, comment.TaskID)
+	fmt.Fprintln(w)
+	writeComment(w, comment)
+	return nil
+}
+
+// FormatCommentDeleted outputs the result of deleting a comment.
+func (f *TableFormatter) FormatCommentDeleted(w io.Writer, taskID, commentID string) error {
+	fmt.Fprintf(w, "Deleted comment %s from %s\n", commentID, taskID)
+	return nil
+}
+
+// writeComment writes a comment's heading, including its ID, and body.
+func writeComment(w io.Writer, comment *backend.Comment) {
+	fmt.Fprintf(w, "### %s @%s (%s)\n", comment.Created.Format("2006-01-02"), comment.Author, comment.ID)
+	fmt.Fprintln(w, comment.Body)
+}
+
 // FormatComments outputs a list of comments.
 func (f *TableFormatter) FormatComments(w io.Writer, comments []backend.Comment) error {
 	if len(comments) == 0 {
@@ -149,9 +355,7 @@ func (f *TableFormatter) FormatComments(w io.Writer, comments []backend.Comment)
 	fmt.Fprintln(w)
 
 	for i, comment := range comments {
-		if err := f.FormatComment(w, &comment); err != nil {
-			return err
-		}
+		writeComment(w, &comment)
 		if i < len(comments)-1 {
 			fmt.Fprintln(w)
 		}
@@ -160,24 +364,102 @@ func (f *TableFormatter) FormatComments(w io.Writer, comments []backend.Comment)
 	return nil
 }
 
+// FormatCommentList outputs comments as a table of their ID, author, age,
+// and the first line of their body.
+func (f *TableFormatter) FormatCommentList(w io.Writer, _ string, comments []backend.Comment) error {
+	if len(comments) == 0 {
+		fmt.Fprintln(w, "No comments.")
+		return nil
+	}
+
+	now := time.Now()
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tAUTHOR\tAGE\tBODY")
+	for _, comment := range comments {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", comment.ID, comment.Author, ageText(comment.Created, now), firstLine(comment.Body))
+	}
+	return tw.Flush()
+}
+
 // FormatCreated outputs the result of creating a task.
 func (f *TableFormatter) FormatCreated(w io.Writer, task *backend.Task) error {
+	if existingTask(task) {
+		fmt.Fprintf(w, "Exists %s: %s\n", task.ID, task.Title)
+		return nil
+	}
 	fmt.Fprintf(w, "Created %s: %s\n", task.ID, task.Title)
 	return nil
 }
 
-// FormatMoved outputs the result of moving a task to a new status.
-func (f *TableFormatter) FormatMoved(w io.Writer, task *backend.Task, oldStatus, newStatus backend.Status) error {
-	fmt.Fprintf(w, "Moved %s: %s → %s\n", task.ID, oldStatus, newStatus)
+// FormatMoved outputs the result of moving a task to a new status, followed
+// by any other fields the move changed.
+func (f *TableFormatter) FormatMoved(w io.Writer, task *backend.Task, oldStatus, newStatus backend.Status, changes []backend.FieldChange) error {
+	fmt.Fprintf(w, "Moved %s: %s → %s\n", task.ID, f.status(oldStatus), f.status(newStatus))
+	for _, change := range changes {
+		if change.Field != "status" {
+			fmt.Fprintf(w, "  %s\n", f.change(change))
+		}
+	}
 	return nil
 }
 
-// FormatUpdated outputs the result of updating a task.
-func (f *TableFormatter) FormatUpdated(w io.Writer, task *backend.Task) error {
+// FormatUpdated outputs the result of updating a task, one line per changed
+// field.
+func (f *TableFormatter) FormatUpdated(w io.Writer, task *backend.Task, changes []backend.FieldChange) error {
+	if len(changes) == 0 {
+		fmt.Fprintf(w, "Updated %s: %s (no changes)\n", task.ID, task.Title)
+		return nil
+	}
 	fmt.Fprintf(w, "Updated %s: %s\n", task.ID, task.Title)
+	for _, change := range changes {
+		fmt.Fprintf(w, "  %s\n", f.change(change))
+	}
 	return nil
 }
 
+// change formats a field change as "priority: medium → high". Labels show
+// what was added and removed, and descriptions aren't shown at all.
+func (f *TableFormatter) change(change backend.FieldChange) string {
+	var summary string
+	switch change.Field {
+	case "description":
+		summary = "updated"
+	case "labels":
+		var parts []string
+		for _, label := range change.Added {
+			parts = append(parts, "+"+f.label(label))
+		}
+		for _, label := range change.Removed {
+			parts = append(parts, "-"+f.label(label))
+		}
+		summary = strings.Join(parts, " ")
+	default:
+		summary = f.changeValue(change.Before) + " → " + f.changeValue(change.After)
+	}
+	return change.Field + ": " + summary
+}
+
+// changeValue formats one side of a field change.
+func (f *TableFormatter) changeValue(value any) string {
+	switch v := value.(type) {
+	case backend.Status:
+		return f.status(v)
+	case backend.Priority:
+		return f.priority(v)
+	case backend.Duration:
+		if v == 0 {
+			return "(none)"
+		}
+		return v.String()
+	case string:
+		if v == "" {
+			return "(none)"
+		}
+		return v
+	}
+	return fmt.Sprint(value)
+}
+
 // FormatClaimed outputs the result of claiming a task.
 func (f *TableFormatter) FormatClaimed(w io.Writer, task *backend.Task, agentID string, alreadyOwned bool) error {
 	if alreadyOwned {
@@ -188,6 +470,32 @@ func (f *TableFormatter) FormatClaimed(w io.Writer, task *backend.Task, agentID
 	return nil
 }
 
+// FormatNextClaimed outputs the task claimed by "next --claim", followed by
+// any candidates that were skipped.
+func (f *TableFormatter) FormatNextClaimed(w io.Writer, result *backend.ClaimResult, agentID string, skipped []backend.SkippedCandidate) error {
+	if err := f.FormatClaimed(w, result.Task, agentID, result.AlreadyOwned); err != nil {
+		return err
+	}
+	for _, s := range skipped {
+		fmt.Fprintf(w, "  skipped %s: %s\n", s.ID, s.Reason)
+	}
+	return nil
+}
+
+// FormatNextClaimedBatch outputs each task claimed by "next --claim --count",
+// followed by the skipped candidates.
+func (f *TableFormatter) FormatNextClaimedBatch(w io.Writer, results []backend.ClaimResult, agentID string, skipped []backend.SkippedCandidate) error {
+	for _, result := range results {
+		if err := f.FormatClaimed(w, result.Task, agentID, result.AlreadyOwned); err != nil {
+			return err
+		}
+	}
+	for _, s := range skipped {
+		fmt.Fprintf(w, "  skipped %s: %s\n", s.ID, s.Reason)
+	}
+	return nil
+}
+
 // FormatReleased outputs the result of releasing a task.
 func (f *TableFormatter) FormatReleased(w io.Writer, task *backend.Task) error {
 	fmt.Fprintf(w, "Released %s: %s\n", task.ID, task.Title)
@@ -217,11 +525,62 @@ func (f *TableFormatter) FormatSynced(w io.Writer, result *backend.SyncResult) e
 	if result.Conflicts > 0 {
 		fmt.Fprintf(w, "  Conflicts: %d\n", result.Conflicts)
 	}
+	if len(result.Resolved) > 0 {
+		fmt.Fprintf(w, "  Resolved:  %s\n", strings.Join(result.Resolved, ", "))
+	}
+	return nil
+}
+
+// FormatSyncStatus outputs how the backlog has diverged from its remote.
+func (f *TableFormatter) FormatSyncStatus(w io.Writer, status *backend.SyncStatus) error {
+	dirty := "no"
+	if status.Dirty {
+		dirty = "yes"
+	}
+
+	if status.Message != "" {
+		fmt.Fprintf(w, "Sync status unavailable: %s.\n", status.Message)
+		if status.Remote != "" {
+			fmt.Fprintf(w, "Remote: %s\n", status.Remote)
+		}
+		fmt.Fprintf(w, "Uncommitted changes: %s\n", dirty)
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Upstream:\t%s\n", status.Upstream)
+	if status.Remote != "" {
+		fmt.Fprintf(tw, "Remote:\t%s\n", status.Remote)
+	}
+	fmt.Fprintf(tw, "Ahead:\t%d\n", status.Ahead)
+	fmt.Fprintf(tw, "Behind:\t%d\n", status.Behind)
+	fmt.Fprintf(tw, "Uncommitted changes:\t%s\n", dirty)
+	for _, row := range []struct {
+		label string
+		ids   []string
+	}{
+		{"Pull would update:", status.Updated},
+		{"Pull would add:", status.Added},
+		{"Pull would remove:", status.Removed},
+	} {
+		if len(row.ids) > 0 {
+			fmt.Fprintf(tw, "%s\t%s\n", row.label, strings.Join(row.ids, ", "))
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if status.InSync {
+		fmt.Fprintf(w, "\nIn sync with %s.\n", status.Upstream)
+	} else {
+		fmt.Fprintf(w, "\nDiverged from %s.\n", status.Upstream)
+	}
 	return nil
 }
 
 // FormatError outputs an error message.
-func (f *TableFormatter) FormatError(w io.Writer, code string, message string, details map[string]any) error {
+func (f *TableFormatter) FormatError(w io.Writer, code string, errorCode string, message string, details map[string]any) error {
 	fmt.Fprintf(w, "error: %s\n", message)
 	return nil
 }
@@ -234,6 +593,35 @@ func (f *TableFormatter) FormatConfig(w io.Writer, cfg *config.Config) error {
 	return nil
 }
 
+// FormatResolvedConfig outputs the effective configuration as an aligned
+// key/value list annotated with each value's source.
+func (f *TableFormatter) FormatResolvedConfig(w io.Writer, values []config.ResolvedValue) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+	for _, v := range values {
+		source := v.Source
+		if v.Origin != "" {
+			source = fmt.Sprintf("%s (%s)", v.Source, v.Origin)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", v.Key, v.Value, source)
+	}
+	return tw.Flush()
+}
+
+// FormatConfigSettings outputs workspace settings as an aligned key/value list.
+func (f *TableFormatter) FormatConfigSettings(w io.Writer, workspace string, settings []config.Setting) error {
+	fmt.Fprintf(w, "Workspace: %s\n", workspace)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, setting := range settings {
+		value := setting.Value
+		if setting.Default {
+			value += " (default)"
+		}
+		fmt.Fprintf(tw, "  %s\t%s\n", setting.Key, value)
+	}
+	return tw.Flush()
+}
+
 // FormatHealthCheck outputs health check results.
 func (f *TableFormatter) FormatHealthCheck(w io.Writer, backendName string, ws *config.Workspace, status *backend.HealthStatus) error {
 	if status.OK {
@@ -241,6 +629,9 @@ func (f *TableFormatter) FormatHealthCheck(w io.Writer, backendName string, ws *
 	} else {
 		fmt.Fprintf(w, "%s: unhealthy - %s\n", backendName, status.Message)
 	}
+	if rl := status.RateLimit; rl != nil {
+		fmt.Fprintf(w, "rate limit: %d/%d remaining, resets at %s\n", rl.Remaining, rl.Limit, rl.Reset.Local().Format(time.Kitchen))
+	}
 	if ws != nil && ws.Project > 0 {
 		fmt.Fprintf(w, "project: %d\n", ws.Project)
 	}
@@ -253,6 +644,12 @@ func (f *TableFormatter) FormatDeleted(w io.Writer, id string) error {
 	return nil
 }
 
+// FormatRestored outputs the result of restoring a soft-deleted task.
+func (f *TableFormatter) FormatRestored(w io.Writer, task *backend.Task) error {
+	fmt.Fprintf(w, "Restored %s to %s: %s\n", task.ID, task.Status, task.Title)
+	return nil
+}
+
 // FormatReordered outputs the result of reordering a task.
 func (f *TableFormatter) FormatReordered(w io.Writer, task *backend.Task) error {
 	fmt.Fprintf(w, "Reordered %s: %s\n", task.ID, task.Title)
@@ -270,3 +667,469 @@ func (f *TableFormatter) FormatUnlinked(w io.Writer, sourceID, targetID string)
 	fmt.Fprintf(w, "Unlinked %s from %s\n", sourceID, targetID)
 	return nil
 }
+
+// FormatLabels outputs labels as a table, each name in its color.
+func (f *TableFormatter) FormatLabels(w io.Writer, labels []backend.Label) error {
+	if len(labels) == 0 {
+		fmt.Fprintln(w, "No labels found.")
+		return nil
+	}
+
+	// Task counts are shown when the backend counted them
+	counted := labels[0].Tasks != nil
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	// Header
+	if counted {
+		fmt.Fprintf(tw, "%s\tTASKS\tCOLOR\tDESCRIPTION\n", f.header("NAME"))
+	} else {
+		fmt.Fprintf(tw, "%s\tCOLOR\tDESCRIPTION\n", f.header("NAME"))
+	}
+
+	// Rows
+	for _, label := range labels {
+		color := label.Color
+		if color == "" {
+			color = "—"
+		}
+		description := label.Description
+		if description == "" {
+			description = "—"
+		}
+		name := label.Name
+		if f.Color {
+			name = paint(labelColor(label.Color), name)
+		}
+		if counted {
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", name, *label.Tasks, color, description)
+		} else {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", name, color, description)
+		}
+	}
+
+	return tw.Flush()
+}
+
+// FormatLabelChange outputs the result of renaming or removing a label and
+// the tasks it changed.
+func (f *TableFormatter) FormatLabelChange(w io.Writer, change *backend.LabelChange) error {
+	if change.Action == "rename" {
+		fmt.Fprintf(w, "Renamed label %s to %s on %d task(s)\n", change.Label, change.NewName, len(change.Tasks))
+	} else {
+		fmt.Fprintf(w, "Removed label %s from %d task(s)\n", change.Label, len(change.Tasks))
+	}
+	for _, id := range change.Tasks {
+		fmt.Fprintf(w, "  %s\n", id)
+	}
+	return nil
+}
+
+// FormatTemplates outputs a list of task templates as a table.
+func (f *TableFormatter) FormatTemplates(w io.Writer, templates []template.Template) error {
+	if len(templates) == 0 {
+		fmt.Fprintln(w, "No templates found.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	// Header
+	fmt.Fprintln(tw, "NAME\tTITLE\tLABELS\tPLACEHOLDERS")
+
+	// Rows
+	for _, t := range templates {
+		title := t.Title
+		if title == "" {
+			title = "—"
+		}
+		labels := "—"
+		if len(t.Labels) > 0 {
+			labels = strings.Join(t.Labels, ", ")
+		}
+		placeholders := "—"
+		if len(t.Placeholders) > 0 {
+			placeholders = strings.Join(t.Placeholders, ", ")
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", t.Name, title, labels, placeholders)
+	}
+
+	return tw.Flush()
+}
+
+// FormatDoctorReport outputs workspace integrity findings, one per block.
+func (f *TableFormatter) FormatDoctorReport(w io.Writer, report *backend.DoctorReport) error {
+	if len(report.Findings) == 0 {
+		fmt.Fprintln(w, "No problems found.")
+		return nil
+	}
+
+	for _, finding := range report.Findings {
+		fixed := ""
+		if finding.Fixed {
+			fixed = " (fixed)"
+		}
+		fmt.Fprintf(w, "[%s] %s: %s: %s%s\n", finding.Severity, finding.Check, finding.Path, finding.Message, fixed)
+		fmt.Fprintf(w, "  fix: %s\n", finding.Suggestion)
+	}
+
+	remaining := len(report.Findings) - report.Fixed
+	fmt.Fprintf(w, "\n%d problem(s) found, %d fixed, %d remaining.\n", len(report.Findings), report.Fixed, remaining)
+	return nil
+}
+
+// FormatReindexed outputs the result of rebuilding a task index.
+func (f *TableFormatter) FormatReindexed(w io.Writer, result *backend.ReindexResult) error {
+	fmt.Fprintf(w, "Indexed %d task(s) in %s\n", result.Tasks, result.Path)
+	for _, skipped := range result.Skipped {
+		fmt.Fprintf(w, "  %s\n", skipped)
+	}
+	return nil
+}
+
+// FormatHistory outputs a task's history, one event per row. Entries with a
+// patch are printed as blocks so the diff follows its event.
+func (f *TableFormatter) FormatHistory(w io.Writer, history *backend.TaskHistory) error {
+	fmt.Fprintf(w, "History of %s\n", history.TaskID)
+	fmt.Fprintln(w, strings.Repeat("━", 40))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, entry := range history.Entries {
+		agent := "—"
+		if entry.Agent != "" {
+			agent = "@" + entry.Agent
+		}
+		commit := entry.Commit
+		if len(commit) > 7 {
+			commit = commit[:7]
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			entry.Timestamp.Format("2006-01-02 15:04"), entry.Action, f.status(entry.Status), agent, commit)
+		if entry.Diff != "" {
+			tw.Flush()
+			fmt.Fprintf(w, "\n%s\n\n", strings.TrimRight(entry.Diff, "\n"))
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if history.Note != "" {
+		fmt.Fprintf(w, "\nNote: %s\n", history.Note)
+	}
+	return nil
+}
+
+// FormatBlame outputs the last change to each field of a task, one field
+// per row.
+func (f *TableFormatter) FormatBlame(w io.Writer, blame *backend.TaskBlame) error {
+	fmt.Fprintf(w, "Blame for %s\n", blame.TaskID)
+	fmt.Fprintln(w, strings.Repeat("━", 40))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, field := range blame.Fields {
+		value := field.Value
+		if value == "" {
+			value = "—"
+		}
+		author, date, commit := "—", "—", "—"
+		if field.Author != "" {
+			author = field.Author
+		}
+		if field.Agent != "" {
+			author += " (@" + field.Agent + ")"
+		}
+		if field.Timestamp != nil {
+			date = field.Timestamp.Format("2006-01-02 15:04")
+		}
+		if field.Commit != "" {
+			commit = field.Commit
+			if len(commit) > 7 {
+				commit = commit[:7]
+			}
+		}
+		if field.Uncommitted {
+			author, date, commit = "—", "—", "uncommitted"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", field.Field, value, author, date, commit)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if blame.Note != "" {
+		fmt.Fprintf(w, "\nNote: %s\n", blame.Note)
+	}
+	return nil
+}
+
+// FormatUndone outputs the mutation that undo reverted and the files it
+// restored.
+func (f *TableFormatter) FormatUndone(w io.Writer, entry *backend.UndoEntry) error {
+	fmt.Fprintf(w, "Reverted %s %s\n", entry.Action, entry.TaskID)
+	for _, file := range entry.Files {
+		fmt.Fprintf(w, "  %s\n", file)
+	}
+	return nil
+}
+
+// FormatUndoEntries outputs the mutations that can be undone, one per row,
+// the next to be undone first.
+func (f *TableFormatter) FormatUndoEntries(w io.Writer, entries []backend.UndoEntry) error {
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "Nothing to undo")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tACTION\tTASK\tAGENT\tFILES")
+	for _, entry := range entries {
+		agent := "—"
+		if entry.Agent != "" {
+			agent = "@" + entry.Agent
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\n",
+			entry.Timestamp.Local().Format("2006-01-02 15:04"), entry.Action, entry.TaskID, agent, len(entry.Files))
+	}
+	return tw.Flush()
+}
+
+// FormatUncommitted outputs the uncommitted backlog files, one per line
+// under a summary, or a note that the backlog is clean.
+func (f *TableFormatter) FormatUncommitted(w io.Writer, files []string) error {
+	if len(files) == 0 {
+		fmt.Fprintln(w, "Backlog is clean")
+		return nil
+	}
+	fmt.Fprintf(w, "%d uncommitted backlog file(s):\n", len(files))
+	for _, file := range files {
+		fmt.Fprintf(w, "  %s\n", file)
+	}
+	return nil
+}
+
+// dryRunVerbs maps dry-run actions to the verb used in the summary line.
+var dryRunVerbs = map[string]string{
+	"add":     "create",
+	"edit":    "update",
+	"move":    "move",
+	"claim":   "claim",
+	"release": "release",
+	"delete":  "delete",
+	"reorder": "reorder",
+}
+
+// FormatDryRun outputs a summary of the change a command would make. Adds and
+// edits are followed by the fields of the resulting task.
+func (f *TableFormatter) FormatDryRun(w io.Writer, result *backend.DryRunResult) error {
+	verb := dryRunVerbs[result.Action]
+	if verb == "" {
+		verb = result.Action
+	}
+
+	summary := "Would " + verb
+	if result.Task != nil {
+		if result.Task.ID != "" {
+			summary += " " + result.Task.ID + ":"
+		}
+		summary += " " + result.Task.Title
+	}
+	if result.FromStatus != "" && result.FromStatus != result.ToStatus {
+		summary += fmt.Sprintf(" (%s → %s)", f.status(result.FromStatus), f.status(result.ToStatus))
+	}
+	if result.Detail != "" {
+		summary += " [" + result.Detail + "]"
+	}
+	fmt.Fprintln(w, summary)
+
+	if result.Task == nil {
+		return nil
+	}
+	switch result.Action {
+	case "add":
+		// The task has no ID yet, so list its fields rather than the full view
+		task := result.Task
+		fmt.Fprintf(w, "\nStatus:    %s\n", f.status(task.Status))
+		fmt.Fprintf(w, "Priority:  %s\n", f.priority(task.Priority))
+		if len(task.Labels) > 0 {
+			fmt.Fprintf(w, "Labels:    %s\n", strings.Join(task.Labels, ", "))
+		}
+		if task.Assignee != "" {
+			fmt.Fprintf(w, "Assignee:  @%s\n", task.Assignee)
+		}
+		if task.Parent != "" {
+			fmt.Fprintf(w, "Parent:    %s\n", task.Parent)
+		}
+	case "edit":
+		fmt.Fprintln(w)
+		return f.FormatTask(w, result.Task)
+	}
+	return nil
+}
+
+// FormatWhoami outputs the resolved agent identity.
+func (f *TableFormatter) FormatWhoami(w io.Writer, identity *backend.AgentIdentity) error {
+	source := identity.Source
+	if identity.Origin != "" {
+		source += " (" + identity.Origin + ")"
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Agent ID:\t%s\n", identity.ID)
+	fmt.Fprintf(tw, "Source:\t%s\n", source)
+	if identity.Workspace != "" {
+		fmt.Fprintf(tw, "Workspace:\t%s\n", identity.Workspace)
+	}
+	fmt.Fprintf(tw, "Label prefix:\t%s\n", identity.LabelPrefix)
+	fmt.Fprintf(tw, "Claim label:\t%s\n", identity.Label)
+	return tw.Flush()
+}
+
+// FormatTimeReport outputs duration totals, overall and per label.
+func (f *TableFormatter) FormatTimeReport(w io.Writer, report *backend.TimeReport) error {
+	if report.Tasks == 0 {
+		fmt.Fprintln(w, "No completed tasks with recorded times.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "LABEL\tTASKS\tTOTAL\tAVERAGE\n")
+	for _, lt := range report.Labels {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", lt.Label, lt.Tasks, lt.Total, lt.Average)
+	}
+	fmt.Fprintf(tw, "(all)\t%d\t%s\t%s\n", report.Tasks, report.Total, report.Average)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if report.Approximate {
+		fmt.Fprintln(w, "\nSome times are approximate, derived from backend timestamps.")
+	}
+	return nil
+}
+
+// FormatChangelog outputs a changelog as markdown, ready to paste into
+// release notes: a heading per section and a bullet per task, linked to the
+// task's URL when it has one.
+func (f *TableFormatter) FormatChangelog(w io.Writer, changelog *backend.Changelog) error {
+	heading := "Changes"
+	switch {
+	case changelog.SinceTag != "":
+		heading += fmt.Sprintf(" since %s (%s)", changelog.SinceTag, changelog.Since.Format("2006-01-02"))
+	case changelog.Since != nil:
+		heading += " since " + changelog.Since.Format("2006-01-02")
+	}
+	fmt.Fprintf(w, "## %s\n", heading)
+
+	if changelog.Count == 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "No tasks were completed.")
+		return nil
+	}
+
+	for _, section := range changelog.Sections {
+		fmt.Fprintf(w, "\n### %s\n\n", section.Title)
+		for _, entry := range section.Tasks {
+			ref := entry.ID
+			if entry.URL != "" {
+				ref = fmt.Sprintf("[%s](%s)", entry.ID, entry.URL)
+			}
+			line := fmt.Sprintf("- %s (%s)", entry.Title, ref)
+			if len(entry.Authors) > 0 {
+				line += " by @" + strings.Join(entry.Authors, ", @")
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+	return nil
+}
+
+// FormatCapabilities outputs a backend's optional operations and whether
+// each is supported.
+func (f *TableFormatter) FormatCapabilities(w io.Writer, caps *backend.Capabilities) error {
+	fmt.Fprintf(w, "Backend: %s\n\n", caps.Backend)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "OPERATION\tSUPPORTED\tCOMMANDS\n")
+	for _, op := range caps.Operations {
+		supported := "no"
+		if op.Supported {
+			supported = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", op.Name, supported, op.Commands)
+	}
+	return tw.Flush()
+}
+
+// FormatWorkspaceCapabilities outputs the optional operations of each
+// workspace's backend side by side, one column per workspace.
+func (f *TableFormatter) FormatWorkspaceCapabilities(w io.Writer, workspaces []backend.WorkspaceCapabilities) error {
+	if len(workspaces) == 0 {
+		fmt.Fprintln(w, "No workspaces configured.")
+		return nil
+	}
+
+	// Every backend reports the same operations in the same order, but one
+	// that failed reports none
+	var operations []backend.Capability
+	for _, ws := range workspaces {
+		if len(ws.Operations) > 0 {
+			operations = ws.Operations
+			break
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprint(tw, "OPERATION")
+	for _, ws := range workspaces {
+		fmt.Fprintf(tw, "\t%s (%s)", ws.Workspace, ws.Backend)
+	}
+	fmt.Fprint(tw, "\tCOMMANDS\n")
+	for _, op := range operations {
+		fmt.Fprint(tw, op.Name)
+		for _, ws := range workspaces {
+			supported := "no"
+			switch {
+			case ws.Error != "":
+				supported = "?"
+			case ws.Supports(op.Name):
+				supported = "yes"
+			}
+			fmt.Fprintf(tw, "\t%s", supported)
+		}
+		fmt.Fprintf(tw, "\t%s\n", op.Commands)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, ws := range workspaces {
+		if ws.Error != "" {
+			fmt.Fprintf(w, "\n%s: %s\n", ws.Workspace, ws.Error)
+		}
+	}
+	return nil
+}
+
+// FormatCredentials outputs where workspaces' credentials come from.
+func (f *TableFormatter) FormatCredentials(w io.Writer, creds []credentials.WorkspaceCredential) error {
+	if len(creds) == 0 {
+		fmt.Fprintln(w, "No GitHub or Linear workspaces configured.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "WORKSPACE\tBACKEND\tSTORE\n")
+	for _, c := range creds {
+		workspace := c.Workspace
+		if workspace == "" {
+			workspace = "(all)"
+		}
+		store := c.Store
+		if store == "" {
+			store = "(none)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", workspace, c.Backend, store)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,193 @@
+// Package template provides task templates stored under .backlog/templates.
+package template
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/local"
+	"gopkg.in/yaml.v3"
+)
+
+// DirName is the name of the templates directory inside the backlog directory.
+const DirName = "templates"
+
+// placeholderRe matches {{key}} placeholders, allowing surrounding spaces.
+var placeholderRe = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// Template is a reusable set of task defaults. Templates use the same format
+// as task files: YAML frontmatter for fields and a markdown body for the
+// description. Title, description, and labels may contain {{placeholders}}.
+type Template struct {
+	Name         string           `json:"name"`
+	Title        string           `json:"title,omitempty"`
+	Description  string           `json:"description,omitempty"`
+	Status       backend.Status   `json:"status,omitempty"`
+	Priority     backend.Priority `json:"priority,omitempty"`
+	Labels       []string         `json:"labels,omitempty"`
+	Assignee     string           `json:"assignee,omitempty"`
+	Placeholders []string         `json:"placeholders,omitempty"`
+}
+
+// templateFrontmatter represents the YAML frontmatter of a template file.
+type templateFrontmatter struct {
+	Title    string           `yaml:"title"`
+	Status   backend.Status   `yaml:"status"`
+	Priority backend.Priority `yaml:"priority"`
+	Labels   []string         `yaml:"labels"`
+	Assignee string           `yaml:"assignee"`
+}
+
+// NotFoundError is returned when a named template does not exist.
+type NotFoundError struct {
+	Name string
+	Dir  string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("template %q not found in %s", e.Name, e.Dir)
+}
+
+// MissingValuesError is returned when placeholders have no value.
+type MissingValuesError struct {
+	Template string
+	Keys     []string
+}
+
+func (e *MissingValuesError) Error() string {
+	return fmt.Sprintf("template %q is missing values for: %s (use --set key=value)",
+		e.Template, strings.Join(e.Keys, ", "))
+}
+
+// Parse parses a template from its file content.
+func Parse(name string, content []byte) (*Template, error) {
+	frontmatter, description, err := local.ParseDocument(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %q: %w", name, err)
+	}
+
+	var fm templateFrontmatter
+	if err := yaml.Unmarshal(frontmatter, &fm); err != nil {
+		return nil, fmt.Errorf("failed to parse template %q frontmatter: %w", name, err)
+	}
+
+	if fm.Status != "" && !fm.Status.IsValid() {
+		return nil, fmt.Errorf("template %q has invalid status %q", name, fm.Status)
+	}
+	if fm.Priority != "" && !fm.Priority.IsValid() {
+		return nil, fmt.Errorf("template %q has invalid priority %q", name, fm.Priority)
+	}
+
+	t := &Template{
+		Name:        name,
+		Title:       fm.Title,
+		Description: description,
+		Status:      fm.Status,
+		Priority:    fm.Priority,
+		Labels:      fm.Labels,
+		Assignee:    fm.Assignee,
+	}
+	t.Placeholders = t.placeholders()
+
+	return t, nil
+}
+
+// Load reads the named template from dir.
+func Load(dir, name string) (*Template, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid template name %q", name)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, name+".md"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, &NotFoundError{Name: name, Dir: dir}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template %q: %w", name, err)
+	}
+
+	return Parse(name, content)
+}
+
+// List returns all templates in dir, sorted by name. A missing directory
+// yields an empty list.
+func List(dir string) ([]Template, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Template{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read templates directory: %w", err)
+	}
+
+	templates := []Template{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+			continue
+		}
+		t, err := Load(dir, strings.TrimSuffix(entry.Name(), ".md"))
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *t)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+
+	return templates, nil
+}
+
+// Render returns a copy of the template with placeholders replaced by values.
+// Every placeholder must have a value.
+func (t *Template) Render(values map[string]string) (*Template, error) {
+	var missing []string
+	for _, key := range t.Placeholders {
+		if _, ok := values[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &MissingValuesError{Template: t.Name, Keys: missing}
+	}
+
+	substitute := func(s string) string {
+		return placeholderRe.ReplaceAllStringFunc(s, func(match string) string {
+			return values[placeholderRe.FindStringSubmatch(match)[1]]
+		})
+	}
+
+	rendered := *t
+	rendered.Title = substitute(t.Title)
+	rendered.Description = substitute(t.Description)
+	rendered.Labels = nil
+	for _, label := range t.Labels {
+		rendered.Labels = append(rendered.Labels, substitute(label))
+	}
+	rendered.Placeholders = nil
+
+	return &rendered, nil
+}
+
+// placeholders returns the unique placeholder keys used by the template, sorted.
+func (t *Template) placeholders() []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, s := range append([]string{t.Title, t.Description}, t.Labels...) {
+		for _, match := range placeholderRe.FindAllStringSubmatch(s, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				keys = append(keys, match[1])
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
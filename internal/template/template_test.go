@@ -0,0 +1,149 @@
+package template
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+const bugTemplate = `---
+title: "Bug: {{summary}}"
+priority: high
+labels: [bug, "area:{{ area }}"]
+---
+
+## Description
+
+Steps to reproduce:
+
+{{steps}}
+
+Reported for {{summary}}.
+`
+
+func TestParse(t *testing.T) {
+	tmpl, err := Parse("bug", []byte(bugTemplate))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if tmpl.Title != "Bug: {{summary}}" {
+		t.Errorf("Title = %q", tmpl.Title)
+	}
+	if tmpl.Priority != backend.PriorityHigh {
+		t.Errorf("Priority = %q, want %q", tmpl.Priority, backend.PriorityHigh)
+	}
+	if tmpl.Description != "Steps to reproduce:\n\n{{steps}}\n\nReported for {{summary}}." {
+		t.Errorf("Description = %q", tmpl.Description)
+	}
+	if want := []string{"area", "steps", "summary"}; !reflect.DeepEqual(tmpl.Placeholders, want) {
+		t.Errorf("Placeholders = %v, want %v", tmpl.Placeholders, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"no frontmatter", "Just a body"},
+		{"invalid yaml", "---\nlabels: [oops\n---\n"},
+		{"invalid priority", "---\npriority: whenever\n---\n"},
+		{"invalid status", "---\nstatus: someday\n---\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse("broken", []byte(tt.content)); err == nil {
+				t.Error("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	tmpl, err := Parse("bug", []byte(bugTemplate))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	rendered, err := tmpl.Render(map[string]string{
+		"summary": "Login fails",
+		"steps":   "1. Submit the form",
+		"area":    "auth",
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if rendered.Title != "Bug: Login fails" {
+		t.Errorf("Title = %q", rendered.Title)
+	}
+	if rendered.Description != "Steps to reproduce:\n\n1. Submit the form\n\nReported for Login fails." {
+		t.Errorf("Description = %q", rendered.Description)
+	}
+	if want := []string{"bug", "area:auth"}; !reflect.DeepEqual(rendered.Labels, want) {
+		t.Errorf("Labels = %v, want %v", rendered.Labels, want)
+	}
+	if tmpl.Title != "Bug: {{summary}}" {
+		t.Error("Render() must not modify the original template")
+	}
+}
+
+func TestRenderMissingValues(t *testing.T) {
+	tmpl, err := Parse("bug", []byte(bugTemplate))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	_, err = tmpl.Render(map[string]string{"summary": "Login fails"})
+	var missing *MissingValuesError
+	if !errors.As(err, &missing) {
+		t.Fatalf("error = %v, want *MissingValuesError", err)
+	}
+	if want := []string{"area", "steps"}; !reflect.DeepEqual(missing.Keys, want) {
+		t.Errorf("Keys = %v, want %v", missing.Keys, want)
+	}
+}
+
+func TestLoadAndList(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"review.md": "---\ntitle: Review PR {{pr}}\nlabels: [review]\n---\n",
+		"bug.md":    bugTemplate,
+		"notes.txt": "not a template",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	templates, err := List(dir)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(templates) != 2 || templates[0].Name != "bug" || templates[1].Name != "review" {
+		t.Errorf("List() = %+v, want bug and review", templates)
+	}
+
+	if _, err := Load(dir, "missing"); err == nil {
+		t.Error("Load() of a missing template should return error")
+	} else if _, ok := err.(*NotFoundError); !ok {
+		t.Errorf("Load() error = %T, want *NotFoundError", err)
+	}
+
+	if _, err := Load(dir, "../bug"); err == nil {
+		t.Error("Load() should reject names containing path separators")
+	}
+
+	empty, err := List(filepath.Join(dir, "does-not-exist"))
+	if err != nil || len(empty) != 0 {
+		t.Errorf("List() of missing dir = %v, %v; want empty list", empty, err)
+	}
+}
@@ -0,0 +1,315 @@
+// execadapter is a reference program for the exec backend. It keeps tasks
+// in a JSON file and speaks the exec backend's line protocol on standard
+// input and output. The spec suite runs backlog against it, and it doubles
+// as a starting point for adapters to real trackers.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/execbackend"
+)
+
+// Store is the adapter's task file.
+type Store struct {
+	Tasks    []backend.Task               `json:"tasks"`
+	Comments map[string][]backend.Comment `json:"comments,omitempty"`
+	NextID   int                          `json:"next_id"`
+}
+
+// adapter serves requests against a store.
+type adapter struct {
+	path         string
+	capabilities []string
+	agentID      string
+	labelPrefix  string
+	store        Store
+}
+
+func main() {
+	storePath := flag.String("store", "exec-tasks.json", "JSON file the tasks are kept in")
+	capabilities := flag.String("capabilities", "claim,comments", "comma-separated capabilities to advertise")
+	flag.Parse()
+
+	a := &adapter{path: *storePath, labelPrefix: "agent"}
+	if *capabilities != "" {
+		a.capabilities = strings.Split(*capabilities, ",")
+	}
+	if err := a.load(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	out := json.NewEncoder(os.Stdout)
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for scanner.Scan() {
+		var req execbackend.Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid request: %v\n", err)
+			os.Exit(2)
+		}
+		resp, err := a.serve(req)
+		if err == nil && mutates(req.Op) {
+			err = a.save()
+		}
+		if err != nil {
+			respErr := &execbackend.ResponseError{Code: "ERROR", Message: err.Error()}
+			var pe *protocolError
+			if errors.As(err, &pe) {
+				respErr = &pe.ResponseError
+			}
+			resp = &execbackend.Response{Error: respErr}
+		}
+		if err := out.Encode(resp); err != nil {
+			os.Exit(1)
+		}
+	}
+}
+
+// protocolError is a failure reported to backlog with a protocol error code.
+type protocolError struct {
+	execbackend.ResponseError
+}
+
+func (e *protocolError) Error() string {
+	return e.Message
+}
+
+func responseError(code, format string, args ...any) error {
+	return &protocolError{execbackend.ResponseError{Code: code, Message: fmt.Sprintf(format, args...)}}
+}
+
+// mutates reports whether op changes the store, which is then saved.
+func mutates(op string) bool {
+	switch op {
+	case "create", "update", "delete", "move", "assign", "unassign", "add_comment", "claim", "release":
+		return true
+	}
+	return false
+}
+
+func (a *adapter) load() error {
+	data, err := os.ReadFile(a.path)
+	if errors.Is(err, os.ErrNotExist) {
+		a.store.NextID = 1
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &a.store)
+}
+
+func (a *adapter) save() error {
+	data, err := json.MarshalIndent(a.store, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(a.path, data, 0o644)
+}
+
+func (a *adapter) serve(req execbackend.Request) (*execbackend.Response, error) {
+	switch req.Op {
+	case "handshake":
+		a.agentID = req.AgentID
+		if req.AgentLabelPrefix != "" {
+			a.labelPrefix = req.AgentLabelPrefix
+		}
+		return &execbackend.Response{
+			Name:         "execadapter",
+			Version:      "0.1.0",
+			Protocol:     execbackend.ProtocolVersion,
+			Capabilities: a.capabilities,
+		}, nil
+	case "health":
+		return &execbackend.Response{OK: true, Message: fmt.Sprintf("execadapter is serving %d tasks from %s", len(a.store.Tasks), a.path)}, nil
+	case "list":
+		return a.list(req.Filters), nil
+	case "create":
+		return a.create(req.Input)
+	case "list_comments", "add_comment", "claim", "release":
+		if !slices.Contains(a.capabilities, capabilityOf(req.Op)) {
+			return nil, responseError(execbackend.CodeUnsupported, "%s is not enabled", capabilityOf(req.Op))
+		}
+	}
+
+	task, err := a.find(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	switch req.Op {
+	case "get":
+	case "update":
+		applyChanges(task, req.Changes)
+	case "delete":
+		a.store.Tasks = slices.DeleteFunc(a.store.Tasks, func(t backend.Task) bool { return t.ID == req.ID })
+		return &execbackend.Response{}, nil
+	case "move":
+		task.Status = req.Status
+	case "assign":
+		task.Assignee = req.Assignee
+	case "unassign":
+		task.Assignee = ""
+	case "list_comments":
+		return &execbackend.Response{Comments: a.store.Comments[task.ID]}, nil
+	case "add_comment":
+		comment := backend.Comment{ID: fmt.Sprintf("%s-c%d", task.ID, len(a.store.Comments[task.ID])+1), Author: a.agentID, Body: req.Body, Created: time.Now().UTC()}
+		if a.store.Comments == nil {
+			a.store.Comments = make(map[string][]backend.Comment)
+		}
+		a.store.Comments[task.ID] = append(a.store.Comments[task.ID], comment)
+		return &execbackend.Response{Comment: &comment}, nil
+	case "claim":
+		return a.claim(task, req.AgentID)
+	case "release":
+		return a.release(task, req.AgentID)
+	default:
+		return nil, responseError(execbackend.CodeUnsupported, "unknown operation %q", req.Op)
+	}
+	task.Updated = time.Now().UTC()
+	return &execbackend.Response{Task: task}, nil
+}
+
+func capabilityOf(op string) string {
+	if op == "claim" || op == "release" {
+		return execbackend.CapabilityClaim
+	}
+	return execbackend.CapabilityComments
+}
+
+func (a *adapter) find(id string) (*backend.Task, error) {
+	for i := range a.store.Tasks {
+		if a.store.Tasks[i].ID == id {
+			return &a.store.Tasks[i], nil
+		}
+	}
+	return nil, responseError(execbackend.CodeNotFound, "task not found: %s", id)
+}
+
+func (a *adapter) list(filters *execbackend.Filters) *execbackend.Response {
+	if filters == nil {
+		filters = &execbackend.Filters{}
+	}
+	var tasks []backend.Task
+	for _, task := range a.store.Tasks {
+		switch {
+		case len(filters.Status) > 0 && !slices.Contains(filters.Status, task.Status):
+		case len(filters.Status) == 0 && !filters.IncludeDone && task.Status == backend.StatusDone:
+		case len(filters.Priority) > 0 && !slices.Contains(filters.Priority, task.Priority):
+		case filters.Assignee == "unassigned" && task.Assignee != "":
+		case filters.Assignee != "" && filters.Assignee != "unassigned" && task.Assignee != filters.Assignee:
+		case filters.Parent != "" && task.Parent != filters.Parent:
+		case slices.ContainsFunc(filters.Labels, func(l string) bool { return !slices.Contains(task.Labels, l) }):
+		case filters.HasComments != nil && (len(a.store.Comments[task.ID]) > 0) != *filters.HasComments:
+		default:
+			tasks = append(tasks, task)
+		}
+	}
+	resp := &execbackend.Response{Tasks: tasks}
+	if filters.Limit > 0 && len(tasks) > filters.Limit {
+		resp.Tasks, resp.HasMore = tasks[:filters.Limit], true
+	}
+	return resp
+}
+
+func (a *adapter) create(input *execbackend.Input) (*execbackend.Response, error) {
+	if input == nil || input.Title == "" {
+		return nil, responseError("INVALID_INPUT", "title is required")
+	}
+	now := time.Now().UTC()
+	task := backend.Task{
+		ID:          input.ID,
+		Title:       input.Title,
+		Description: input.Description,
+		Status:      input.Status,
+		Priority:    input.Priority,
+		Assignee:    input.Assignee,
+		Labels:      input.Labels,
+		Parent:      input.Parent,
+		Created:     now,
+		Updated:     now,
+	}
+	if task.ID == "" {
+		task.ID = fmt.Sprintf("EXT-%d", a.store.NextID)
+		a.store.NextID++
+	}
+	if task.Status == "" {
+		task.Status = backend.StatusBacklog
+	}
+	if task.Priority == "" {
+		task.Priority = backend.PriorityNone
+	}
+	a.store.Tasks = append(a.store.Tasks, task)
+	return &execbackend.Response{Task: &task}, nil
+}
+
+func applyChanges(task *backend.Task, changes *execbackend.Changes) {
+	if changes == nil {
+		return
+	}
+	if changes.Title != nil {
+		task.Title = *changes.Title
+	}
+	if changes.Description != nil {
+		task.Description = *changes.Description
+	}
+	if changes.Priority != nil {
+		task.Priority = *changes.Priority
+	}
+	if changes.Assignee != nil {
+		task.Assignee = *changes.Assignee
+	}
+	if changes.Parent != nil {
+		task.Parent = *changes.Parent
+	}
+	for _, label := range changes.AddLabels {
+		if !slices.Contains(task.Labels, label) {
+			task.Labels = append(task.Labels, label)
+		}
+	}
+	task.Labels = slices.DeleteFunc(task.Labels, func(l string) bool { return slices.Contains(changes.RemoveLabels, l) })
+}
+
+func (a *adapter) claim(task *backend.Task, agentID string) (*execbackend.Response, error) {
+	if task.Assignee == agentID {
+		return &execbackend.Response{Task: task, AlreadyOwned: true}, nil
+	}
+	if task.Assignee != "" {
+		return nil, &protocolError{execbackend.ResponseError{
+			Code:      execbackend.CodeClaimConflict,
+			Message:   fmt.Sprintf("task %s is already claimed by agent %s", task.ID, task.Assignee),
+			ClaimedBy: task.Assignee,
+		}}
+	}
+	task.Assignee = agentID
+	task.Status = backend.StatusInProgress
+	task.Labels = append(task.Labels, a.labelPrefix+":"+agentID)
+	task.Updated = time.Now().UTC()
+	return &execbackend.Response{Task: task}, nil
+}
+
+func (a *adapter) release(task *backend.Task, agentID string) (*execbackend.Response, error) {
+	if task.Assignee != agentID {
+		return nil, &protocolError{execbackend.ResponseError{
+			Code:      execbackend.CodeReleaseConflict,
+			Message:   fmt.Sprintf("task %s is not claimed by agent %s", task.ID, agentID),
+			ClaimedBy: task.Assignee,
+		}}
+	}
+	label := a.labelPrefix + ":" + agentID
+	task.Assignee = ""
+	task.Status = backend.StatusTodo
+	task.Labels = slices.DeleteFunc(task.Labels, func(l string) bool { return l == label })
+	task.Updated = time.Now().UTC()
+	return &execbackend.Response{Task: task}, nil
+}
@@ -9,9 +9,11 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"time"
+	"unicode"
 )
 
 // Feature represents a parsed Gherkin feature
@@ -21,9 +23,19 @@ type Feature struct {
 	Tags        []string
 	Background  *Background
 	Scenarios   []Scenario
+	Rules       []Rule
 	FilePath    string
 }
 
+// Rule represents a Gherkin rule grouping scenarios within a feature
+type Rule struct {
+	Name        string
+	Description string
+	Tags        []string
+	Background  *Background
+	Scenarios   []Scenario
+}
+
 // Background represents a feature's background section
 type Background struct {
 	Name  string
@@ -59,6 +71,7 @@ type ExampleTable struct {
 type DocData struct {
 	Title           string
 	GeneratedAt     string
+	Tags            string
 	TotalFeatures   int
 	TotalScenarios  int
 	FeaturesByPhase []PhaseGroup
@@ -78,10 +91,20 @@ type FeatureDoc struct {
 	FilePath       string
 	Background     *BackgroundDoc
 	Scenarios      []ScenarioDoc
+	Rules          []RuleDoc
 	ScenarioCount  int
 	OutlineCount   int
 }
 
+// RuleDoc is a rule formatted for documentation
+type RuleDoc struct {
+	Name        string
+	Description string
+	Tags        string
+	Background  *BackgroundDoc
+	Scenarios   []ScenarioDoc
+}
+
 // BackgroundDoc is a background formatted for documentation
 type BackgroundDoc struct {
 	Steps []StepDoc
@@ -89,6 +112,7 @@ type BackgroundDoc struct {
 
 // ScenarioDoc is a scenario formatted for documentation
 type ScenarioDoc struct {
+	Anchor      string
 	Name        string
 	Description string
 	Tags        string
@@ -117,11 +141,18 @@ func main() {
 	featuresDir := flag.String("features", "features", "Directory containing .feature files")
 	outputFile := flag.String("output", "docs.html", "Output HTML file")
 	title := flag.String("title", "Backlog CLI - Living Documentation", "Documentation title")
+	tags := flag.String("tags", "", "Only document scenarios matching a tag expression, like @smoke,~@wip")
 	flag.Parse()
 
+	filter, err := parseTagFilter(*tags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid -tags: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Find all feature files
 	var featureFiles []string
-	err := filepath.WalkDir(*featuresDir, func(path string, d fs.DirEntry, err error) error {
+	err = filepath.WalkDir(*featuresDir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -152,7 +183,7 @@ func main() {
 	}
 
 	// Generate documentation
-	docData := buildDocData(features, *title)
+	docData := buildDocData(features, *title, filter)
 	if err := generateHTML(docData, *outputFile); err != nil {
 		fmt.Fprintf(os.Stderr, "Error generating HTML: %v\n", err)
 		os.Exit(1)
@@ -178,12 +209,16 @@ func parseGherkin(content string, filePath string) (Feature, error) {
 	lines := strings.Split(content, "\n")
 
 	var currentSection string
+	var currentRule *Rule
 	var currentScenario *Scenario
 	var currentStep *Step
 	var inDocString bool
 	var docStringIndent int
 	var inExamples bool
 	var currentExamples *ExampleTable
+	// Tags collected since the last keyword; they belong to the next
+	// Feature, Rule, Scenario or Examples line
+	var pendingTags []string
 
 	for i := 0; i < len(lines); i++ {
 		line := lines[i]
@@ -223,12 +258,7 @@ func parseGherkin(content string, filePath string) (Feature, error) {
 
 		// Handle tags
 		if strings.HasPrefix(trimmed, "@") {
-			tags := parseTags(trimmed)
-			if currentScenario != nil && currentSection == "scenario" {
-				// Tags before examples don't apply to scenario
-			} else if currentSection == "" || currentSection == "feature" {
-				feature.Tags = append(feature.Tags, tags...)
-			}
+			pendingTags = append(pendingTags, parseTags(trimmed)...)
 			continue
 		}
 
@@ -250,26 +280,53 @@ func parseGherkin(content string, filePath string) (Feature, error) {
 		// Feature
 		if strings.HasPrefix(trimmed, "Feature:") {
 			feature.Name = strings.TrimSpace(strings.TrimPrefix(trimmed, "Feature:"))
+			feature.Tags, pendingTags = pendingTags, nil
 			currentSection = "feature"
 			continue
 		}
 
-		// Feature description (lines after Feature: before Background/Scenario)
-		if currentSection == "feature" && !strings.HasPrefix(trimmed, "Background:") &&
-			!strings.HasPrefix(trimmed, "Scenario:") && !strings.HasPrefix(trimmed, "Scenario Outline:") {
-			if feature.Description != "" {
-				feature.Description += "\n"
+		// Feature or rule description (lines after Feature: or Rule: before
+		// the next keyword)
+		if (currentSection == "feature" || currentSection == "rule") && !strings.HasPrefix(trimmed, "Background:") &&
+			!strings.HasPrefix(trimmed, "Scenario:") && !strings.HasPrefix(trimmed, "Scenario Outline:") &&
+			!strings.HasPrefix(trimmed, "Rule:") {
+			description := &feature.Description
+			if currentSection == "rule" {
+				description = &currentRule.Description
 			}
-			feature.Description += trimmed
+			if *description != "" {
+				*description += "\n"
+			}
+			*description += trimmed
 			continue
 		}
 
-		// Background
+		// Rule: scenarios and backgrounds from here on belong to it
+		if strings.HasPrefix(trimmed, "Rule:") {
+			currentSection = "rule"
+			feature.Rules = append(feature.Rules, Rule{
+				Name: strings.TrimSpace(strings.TrimPrefix(trimmed, "Rule:")),
+				Tags: pendingTags,
+			})
+			pendingTags = nil
+			currentRule = &feature.Rules[len(feature.Rules)-1]
+			currentScenario = nil
+			currentStep = nil
+			inExamples = false
+			continue
+		}
+
+		// Background, of the feature or of the current rule
 		if strings.HasPrefix(trimmed, "Background:") {
 			currentSection = "background"
-			feature.Background = &Background{
+			background := &Background{
 				Name: strings.TrimSpace(strings.TrimPrefix(trimmed, "Background:")),
 			}
+			if currentRule != nil {
+				currentRule.Background = background
+			} else {
+				feature.Background = background
+			}
 			currentScenario = nil
 			inExamples = false
 			continue
@@ -288,31 +345,25 @@ func parseGherkin(content string, filePath string) (Feature, error) {
 				name = strings.TrimSpace(strings.TrimPrefix(trimmed, "Scenario:"))
 			}
 
-			// Look back for tags on the previous non-empty line
-			var tags []string
-			for j := i - 1; j >= 0; j-- {
-				prevTrimmed := strings.TrimSpace(lines[j])
-				if prevTrimmed == "" {
-					continue
-				}
-				if strings.HasPrefix(prevTrimmed, "@") {
-					tags = parseTags(prevTrimmed)
-				}
-				break
-			}
-
 			scenario := Scenario{
 				Name:      name,
-				Tags:      tags,
+				Tags:      pendingTags,
 				IsOutline: isOutline,
 			}
-			feature.Scenarios = append(feature.Scenarios, scenario)
-			currentScenario = &feature.Scenarios[len(feature.Scenarios)-1]
+			pendingTags = nil
+			scenarios := &feature.Scenarios
+			if currentRule != nil {
+				scenarios = &currentRule.Scenarios
+			}
+			*scenarios = append(*scenarios, scenario)
+			currentScenario = &(*scenarios)[len(*scenarios)-1]
 			continue
 		}
 
 		// Examples
 		if strings.HasPrefix(trimmed, "Examples:") {
+			// Examples tags only filter which rows run
+			pendingTags = nil
 			inExamples = true
 			if currentScenario != nil {
 				exampleTable := ExampleTable{
@@ -333,9 +384,13 @@ func parseGherkin(content string, filePath string) (Feature, error) {
 					Text:    strings.TrimPrefix(trimmed, keyword),
 				}
 
-				if currentSection == "background" && feature.Background != nil {
-					feature.Background.Steps = append(feature.Background.Steps, step)
-					currentStep = &feature.Background.Steps[len(feature.Background.Steps)-1]
+				background := feature.Background
+				if currentRule != nil {
+					background = currentRule.Background
+				}
+				if currentSection == "background" && background != nil {
+					background.Steps = append(background.Steps, step)
+					currentStep = &background.Steps[len(background.Steps)-1]
 				} else if currentScenario != nil {
 					currentScenario.Steps = append(currentScenario.Steps, step)
 					currentStep = &currentScenario.Steps[len(currentScenario.Steps)-1]
@@ -371,12 +426,16 @@ func parseTableRow(line string) []string {
 	return row
 }
 
-func buildDocData(features []Feature, title string) DocData {
+// buildDocData formats the features for the template, keeping only the
+// scenarios that match filter. Features and rules left without scenarios
+// are dropped, so the totals count what the page shows.
+func buildDocData(features []Feature, title string, filter tagFilter) DocData {
 	// Group features by category based on file name
 	groups := categorizeFeatures(features)
 
-	var totalScenarios int
+	var totalFeatures, totalScenarios int
 	var phaseGroups []PhaseGroup
+	anchors := newAnchorSet()
 
 	for _, group := range groups {
 		pg := PhaseGroup{Name: group.name}
@@ -389,70 +448,243 @@ func buildDocData(features []Feature, title string) DocData {
 				FilePath:    f.FilePath,
 			}
 
-			if f.Background != nil {
-				bd := &BackgroundDoc{}
-				for _, s := range f.Background.Steps {
-					bd.Steps = append(bd.Steps, StepDoc{
-						Keyword:   s.Keyword,
-						Text:      s.Text,
-						DocString: s.DocString,
-						DataTable: s.DataTable,
-						HasExtra:  s.DocString != "" || len(s.DataTable) > 0,
-					})
+			fd.Background = buildBackgroundDoc(f.Background)
+
+			addScenarios := func(scenarios []Scenario, inherited []string) []ScenarioDoc {
+				var docs []ScenarioDoc
+				for _, s := range scenarios {
+					// Scenarios left out still take their anchor, so a
+					// filtered page links the same way as the full one
+					anchor := anchors.add(scenarioAnchor(f.FilePath, s.Name))
+					if !filter.match(append(slices.Clip(inherited), s.Tags...)) {
+						continue
+					}
+					docs = append(docs, buildScenarioDoc(s, anchor))
+					fd.ScenarioCount++
+					if s.IsOutline {
+						fd.OutlineCount++
+					}
 				}
-				fd.Background = bd
+				return docs
 			}
 
-			for _, s := range f.Scenarios {
-				sd := ScenarioDoc{
-					Name:        s.Name,
-					Description: strings.TrimSpace(s.Description),
-					Tags:        strings.Join(s.Tags, " "),
-					IsOutline:   s.IsOutline,
-				}
-
-				for _, step := range s.Steps {
-					sd.Steps = append(sd.Steps, StepDoc{
-						Keyword:   step.Keyword,
-						Text:      step.Text,
-						DocString: step.DocString,
-						DataTable: step.DataTable,
-						HasExtra:  step.DocString != "" || len(step.DataTable) > 0,
-					})
-				}
-
-				for _, ex := range s.Examples {
-					sd.Examples = append(sd.Examples, ExampleTableDoc{
-						Name:    ex.Name,
-						Headers: ex.Headers,
-						Rows:    ex.Rows,
-					})
-				}
-
-				fd.Scenarios = append(fd.Scenarios, sd)
-				totalScenarios++
-
-				if s.IsOutline {
-					fd.OutlineCount++
+			fd.Scenarios = addScenarios(f.Scenarios, f.Tags)
+			for _, r := range f.Rules {
+				scenarios := addScenarios(r.Scenarios, append(slices.Clip(f.Tags), r.Tags...))
+				if filter != nil && len(scenarios) == 0 {
+					continue
 				}
+				fd.Rules = append(fd.Rules, RuleDoc{
+					Name:        r.Name,
+					Description: strings.TrimSpace(r.Description),
+					Tags:        strings.Join(r.Tags, " "),
+					Background:  buildBackgroundDoc(r.Background),
+					Scenarios:   scenarios,
+				})
+			}
+			if filter != nil && fd.ScenarioCount == 0 {
+				continue
 			}
-			fd.ScenarioCount = len(fd.Scenarios)
+			totalFeatures++
+			totalScenarios += fd.ScenarioCount
 
 			pg.Features = append(pg.Features, fd)
 		}
 
-		phaseGroups = append(phaseGroups, pg)
+		if len(pg.Features) > 0 {
+			phaseGroups = append(phaseGroups, pg)
+		}
 	}
 
 	return DocData{
 		Title:           title,
 		GeneratedAt:     time.Now().Format("2006-01-02 15:04:05"),
-		TotalFeatures:   len(features),
+		Tags:            filter.String(),
+		TotalFeatures:   totalFeatures,
 		TotalScenarios:  totalScenarios,
 		FeaturesByPhase: phaseGroups,
 	}
 }
 
+// tagFilter is a tag expression in godog's syntax, as GODOG_TAGS takes: a
+// list of terms joined by "&&", each a comma-separated list of tags of
+// which one must match. A tag prefixed with "~" matches scenarios without
+// it. The nil filter matches every scenario.
+type tagFilter [][]string
+
+// parseTagFilter parses a tag expression such as "@smoke,~@wip" or
+// "@linear && ~@slow". An empty expression gives the nil filter.
+func parseTagFilter(expr string) (tagFilter, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+	var filter tagFilter
+	for _, term := range strings.Split(expr, "&&") {
+		var anyOf []string
+		for _, tag := range strings.Split(term, ",") {
+			tag = strings.TrimSpace(tag)
+			if name := strings.TrimPrefix(tag, "~"); len(name) < 2 || name[0] != '@' {
+				return nil, fmt.Errorf("%q is not a tag; want @tag or ~@tag", tag)
+			}
+			anyOf = append(anyOf, tag)
+		}
+		filter = append(filter, anyOf)
+	}
+	return filter, nil
+}
+
+// match reports whether a scenario with the given tags, including those
+// inherited from its feature and rule, passes the filter.
+func (f tagFilter) match(tags []string) bool {
+	for _, anyOf := range f {
+		ok := false
+		for _, tag := range anyOf {
+			if negated, found := strings.CutPrefix(tag, "~"); found {
+				ok = !slices.Contains(tags, negated)
+			} else {
+				ok = slices.Contains(tags, tag)
+			}
+			if ok {
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the filter as a normalized tag expression.
+func (f tagFilter) String() string {
+	terms := make([]string, len(f))
+	for i, anyOf := range f {
+		terms[i] = strings.Join(anyOf, ",")
+	}
+	return strings.Join(terms, " && ")
+}
+
+// buildBackgroundDoc formats a background, returning nil if there is none.
+func buildBackgroundDoc(b *Background) *BackgroundDoc {
+	if b == nil {
+		return nil
+	}
+	bd := &BackgroundDoc{}
+	for _, s := range b.Steps {
+		bd.Steps = append(bd.Steps, buildStepDoc(s))
+	}
+	return bd
+}
+
+// buildScenarioDoc formats a scenario under the given anchor.
+func buildScenarioDoc(s Scenario, anchor string) ScenarioDoc {
+	sd := ScenarioDoc{
+		Anchor:      anchor,
+		Name:        s.Name,
+		Description: strings.TrimSpace(s.Description),
+		Tags:        strings.Join(s.Tags, " "),
+		IsOutline:   s.IsOutline,
+	}
+
+	for _, step := range s.Steps {
+		sd.Steps = append(sd.Steps, buildStepDoc(step))
+	}
+
+	for _, ex := range s.Examples {
+		sd.Examples = append(sd.Examples, ExampleTableDoc{
+			Name:    ex.Name,
+			Headers: ex.Headers,
+			Rows:    ex.Rows,
+		})
+	}
+	return sd
+}
+
+func buildStepDoc(s Step) StepDoc {
+	return StepDoc{
+		Keyword:   s.Keyword,
+		Text:      s.Text,
+		DocString: s.DocString,
+		DataTable: s.DataTable,
+		HasExtra:  s.DocString != "" || len(s.DataTable) > 0,
+	}
+}
+
+// maxSlugLength caps the runes of a slug so anchors of long scenario names
+// stay readable in URLs.
+const maxSlugLength = 60
+
+// slugify turns a name into a URL fragment: lowercase letters and digits,
+// including non-ASCII ones, joined by single hyphens. Slugs longer than
+// maxSlugLength are cut at the last word that fits. A name without letters
+// or digits gives "scenario".
+func slugify(name string) string {
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			word.WriteRune(r)
+		} else {
+			flush()
+		}
+	}
+	flush()
+
+	var slug []rune
+	for _, w := range words {
+		runes := []rune(w)
+		sep := 0
+		if len(slug) > 0 {
+			sep = 1
+		}
+		if len(slug)+sep+len(runes) > maxSlugLength {
+			if len(slug) == 0 {
+				slug = runes[:maxSlugLength]
+			}
+			break
+		}
+		if sep == 1 {
+			slug = append(slug, '-')
+		}
+		slug = append(slug, runes...)
+	}
+	if len(slug) == 0 {
+		return "scenario"
+	}
+	return string(slug)
+}
+
+// scenarioAnchor returns the anchor for a scenario: the slugs of its feature
+// file, without the extension, and of its name.
+func scenarioAnchor(featureFile, name string) string {
+	return slugify(strings.TrimSuffix(featureFile, ".feature")) + "--" + slugify(name)
+}
+
+// anchorSet hands out unique anchors, numbering repeats in the order they
+// are added: "add--x", "add--x-2", "add--x-3".
+type anchorSet map[string]bool
+
+func newAnchorSet() anchorSet {
+	return make(anchorSet)
+}
+
+// add returns anchor, or anchor with the lowest free numeric suffix if it
+// has been used, and marks the result as used.
+func (s anchorSet) add(anchor string) string {
+	unique := anchor
+	for n := 2; s[unique]; n++ {
+		unique = fmt.Sprintf("%s-%d", anchor, n)
+	}
+	s[unique] = true
+	return unique
+}
+
 type featureGroup struct {
 	name     string
 	features []Feature
@@ -649,6 +881,27 @@ const htmlTemplate = `<!DOCTYPE html>
             font-size: 0.75rem;
             margin-left: 0.25rem;
         }
+        .nav-scenarios {
+            margin: 0 0 0.375rem 0.5rem;
+        }
+        .nav-scenarios summary {
+            cursor: pointer;
+            color: var(--color-text-muted);
+            font-size: 0.75rem;
+            padding: 0.125rem 0.5rem;
+        }
+        .nav-scenario-link {
+            display: block;
+            padding: 0.125rem 0.5rem 0.125rem 1rem;
+            color: var(--color-text-muted);
+            text-decoration: none;
+            font-size: 0.75rem;
+            border-radius: 0.25rem;
+        }
+        .nav-scenario-link:hover {
+            background: var(--color-surface-hover);
+            color: var(--color-text);
+        }
         /* Main content */
         .main {
             flex: 1;
@@ -710,6 +963,25 @@ const htmlTemplate = `<!DOCTYPE html>
             color: var(--color-accent);
             margin-bottom: 0.5rem;
         }
+        .rule {
+            border-left: 3px solid var(--color-tag);
+            padding-left: 1rem;
+            margin: 1.5rem 0 1rem;
+        }
+        .rule-title {
+            font-size: 1rem;
+            font-weight: 600;
+            margin-bottom: 0.25rem;
+        }
+        .rule-keyword {
+            color: var(--color-tag);
+        }
+        .rule-description {
+            color: var(--color-text-muted);
+            font-size: 0.875rem;
+            margin-bottom: 0.75rem;
+            white-space: pre-line;
+        }
         .scenario {
             border: 1px solid var(--color-border);
             border-radius: 0.375rem;
@@ -729,6 +1001,24 @@ const htmlTemplate = `<!DOCTYPE html>
         .scenario-title {
             font-weight: 500;
         }
+        .scenario-link {
+            color: var(--color-text-muted);
+            text-decoration: none;
+            font-size: 0.875rem;
+            margin-left: 0.375rem;
+            opacity: 0;
+        }
+        .scenario-header:hover .scenario-link,
+        .scenario-link:focus {
+            opacity: 1;
+        }
+        .scenario-link.copied {
+            color: var(--color-success);
+            opacity: 1;
+        }
+        .scenario:target {
+            border-color: var(--color-accent);
+        }
         .scenario-outline-badge {
             background: rgba(129, 140, 248, 0.2);
             color: var(--color-keyword);
@@ -859,6 +1149,7 @@ const htmlTemplate = `<!DOCTYPE html>
         <nav class="sidebar">
             <h1>{{.Title}}</h1>
             <p class="sidebar-meta">Generated: {{.GeneratedAt}}</p>
+            {{if .Tags}}<p class="sidebar-meta">Tags: {{.Tags}}</p>{{end}}
 
             <div class="sidebar-stats">
                 <div class="sidebar-stat">
@@ -879,9 +1170,24 @@ const htmlTemplate = `<!DOCTYPE html>
             <div class="nav-group">
                 <div class="nav-group-title">{{.Name}}</div>
                 {{range .Features}}
-                <a href="#{{.FilePath}}" class="nav-link" data-feature="{{.Name}}">
-                    {{.Name}}<span class="nav-link-count">({{.ScenarioCount}})</span>
-                </a>
+                <div class="nav-feature" data-feature="{{.Name}}">
+                    <a href="#{{.FilePath}}" class="nav-link">
+                        {{.Name}}<span class="nav-link-count">({{.ScenarioCount}})</span>
+                    </a>
+                    {{if .ScenarioCount}}
+                    <details class="nav-scenarios">
+                        <summary>Scenarios</summary>
+                        {{range .Scenarios}}
+                        <a href="#{{.Anchor}}" class="nav-scenario-link">{{.Name}}</a>
+                        {{end}}
+                        {{range .Rules}}
+                        {{range .Scenarios}}
+                        <a href="#{{.Anchor}}" class="nav-scenario-link">{{.Name}}</a>
+                        {{end}}
+                        {{end}}
+                    </details>
+                    {{end}}
+                </div>
                 {{end}}
             </div>
             {{end}}
@@ -910,83 +1216,19 @@ const htmlTemplate = `<!DOCTYPE html>
                     {{end}}
                 </div>
                 <div class="feature-content">
-                    {{if .Background}}
-                    <div class="background">
-                        <div class="background-title">Background</div>
-                        {{range .Background.Steps}}
-                        <div class="step">
-                            <span class="step-keyword">{{.Keyword}}</span> {{.Text}}
-                            {{if .HasExtra}}
-                            <div class="step-extra">
-                                {{if .DocString}}<pre class="docstring">{{.DocString}}</pre>{{end}}
-                                {{if .DataTable}}
-                                <table class="data-table">
-                                    {{range $i, $row := .DataTable}}
-                                    <tr>
-                                        {{range $cell := $row}}
-                                        {{if eq $i 0}}<th>{{$cell}}</th>{{else}}<td>{{$cell}}</td>{{end}}
-                                        {{end}}
-                                    </tr>
-                                    {{end}}
-                                </table>
-                                {{end}}
-                            </div>
-                            {{end}}
-                        </div>
-                        {{end}}
-                    </div>
-                    {{end}}
+                    {{if .Background}}{{template "background" .Background}}{{end}}
 
-                    {{range .Scenarios}}
-                    <div class="scenario">
-                        <div class="scenario-header" onclick="this.parentElement.classList.toggle('expanded')">
-                            <div>
-                                <span class="scenario-title">{{.Name}}</span>
-                                {{if .IsOutline}}<span class="scenario-outline-badge">Outline</span>{{end}}
-                                {{if .Tags}}<div class="scenario-tags">{{.Tags}}</div>{{end}}
-                            </div>
-                        </div>
-                        <div class="scenario-content">
-                            {{range .Steps}}
-                            <div class="step">
-                                <span class="step-keyword">{{.Keyword}}</span> {{.Text}}
-                                {{if .HasExtra}}
-                                <div class="step-extra">
-                                    {{if .DocString}}<pre class="docstring">{{.DocString}}</pre>{{end}}
-                                    {{if .DataTable}}
-                                    <table class="data-table">
-                                        {{range $i, $row := .DataTable}}
-                                        <tr>
-                                            {{range $cell := $row}}
-                                            {{if eq $i 0}}<th>{{$cell}}</th>{{else}}<td>{{$cell}}</td>{{end}}
-                                            {{end}}
-                                        </tr>
-                                        {{end}}
-                                    </table>
-                                    {{end}}
-                                </div>
-                                {{end}}
-                            </div>
-                            {{end}}
+                    {{range .Scenarios}}{{template "scenario" .}}{{end}}
 
-                            {{if .Examples}}
-                            <div class="examples">
-                                {{range .Examples}}
-                                <div class="examples-title">Examples{{if .Name}}: {{.Name}}{{end}}</div>
-                                <table class="data-table">
-                                    <tr>
-                                        {{range .Headers}}<th>{{.}}</th>{{end}}
-                                    </tr>
-                                    {{range .Rows}}
-                                    <tr>
-                                        {{range .}}<td>{{.}}</td>{{end}}
-                                    </tr>
-                                    {{end}}
-                                </table>
-                                {{end}}
-                            </div>
-                            {{end}}
-                        </div>
+                    {{range .Rules}}
+                    <div class="rule">
+                        <h4 class="rule-title"><span class="rule-keyword">Rule:</span> {{.Name}}</h4>
+                        {{if .Tags}}<div class="scenario-tags">{{.Tags}}</div>{{end}}
+                        {{if .Description}}
+                        <p class="rule-description">{{.Description}}</p>
+                        {{end}}
+                        {{if .Background}}{{template "background" .Background}}{{end}}
+                        {{range .Scenarios}}{{template "scenario" .}}{{end}}
                     </div>
                     {{end}}
                 </div>
@@ -1012,7 +1254,7 @@ const htmlTemplate = `<!DOCTYPE html>
         function filterFeatures(query) {
             query = query.toLowerCase();
             const features = document.querySelectorAll('.feature');
-            const navLinks = document.querySelectorAll('.nav-link');
+            const navItems = document.querySelectorAll('.nav-feature');
 
             features.forEach(f => {
                 const name = f.dataset.name.toLowerCase();
@@ -1024,16 +1266,42 @@ const htmlTemplate = `<!DOCTYPE html>
                 }
             });
 
-            navLinks.forEach(link => {
-                const name = link.dataset.feature.toLowerCase();
+            navItems.forEach(item => {
+                const name = item.dataset.feature.toLowerCase();
                 if (query === '' || name.includes(query)) {
-                    link.classList.remove('hidden');
+                    item.classList.remove('hidden');
                 } else {
-                    link.classList.add('hidden');
+                    item.classList.add('hidden');
                 }
             });
         }
 
+        // Copy a scenario's URL to the clipboard without toggling it
+        function copyScenarioLink(event, link) {
+            event.preventDefault();
+            event.stopPropagation();
+            const url = location.href.split('#')[0] + link.getAttribute('href');
+            history.replaceState(null, '', link.getAttribute('href'));
+            if (navigator.clipboard) {
+                navigator.clipboard.writeText(url).then(() => {
+                    link.classList.add('copied');
+                    setTimeout(() => link.classList.remove('copied'), 1500);
+                });
+            }
+        }
+
+        // Expand and scroll to the scenario named by the URL fragment
+        function openScenarioFromHash() {
+            const id = decodeURIComponent(location.hash.slice(1));
+            const target = id && document.getElementById(id);
+            if (target && target.classList.contains('scenario')) {
+                target.classList.add('expanded');
+                target.scrollIntoView({ block: 'start' });
+            }
+        }
+        window.addEventListener('hashchange', openScenarioFromHash);
+        document.addEventListener('DOMContentLoaded', openScenarioFromHash);
+
         // Smooth scroll to anchor
         document.querySelectorAll('.nav-link').forEach(link => {
             link.addEventListener('click', function(e) {
@@ -1047,5 +1315,84 @@ const htmlTemplate = `<!DOCTYPE html>
     </script>
 </body>
 </html>
+{{define "background"}}
+    <div class="background">
+        <div class="background-title">Background</div>
+        {{range .Steps}}
+        <div class="step">
+            <span class="step-keyword">{{.Keyword}}</span> {{.Text}}
+            {{if .HasExtra}}
+            <div class="step-extra">
+                {{if .DocString}}<pre class="docstring">{{.DocString}}</pre>{{end}}
+                {{if .DataTable}}
+                <table class="data-table">
+                    {{range $i, $row := .DataTable}}
+                    <tr>
+                        {{range $cell := $row}}
+                        {{if eq $i 0}}<th>{{$cell}}</th>{{else}}<td>{{$cell}}</td>{{end}}
+                        {{end}}
+                    </tr>
+                    {{end}}
+                </table>
+                {{end}}
+            </div>
+            {{end}}
+        </div>
+        {{end}}
+    </div>
+{{end}}
+{{define "scenario"}}
+    <div class="scenario" id="{{.Anchor}}">
+        <div class="scenario-header" onclick="this.parentElement.classList.toggle('expanded')">
+            <div>
+                <span class="scenario-title">{{.Name}}</span>
+                <a href="#{{.Anchor}}" class="scenario-link" title="Copy link to this scenario" onclick="copyScenarioLink(event, this)">&#128279;</a>
+                {{if .IsOutline}}<span class="scenario-outline-badge">Outline</span>{{end}}
+                {{if .Tags}}<div class="scenario-tags">{{.Tags}}</div>{{end}}
+            </div>
+        </div>
+        <div class="scenario-content">
+            {{range .Steps}}
+            <div class="step">
+                <span class="step-keyword">{{.Keyword}}</span> {{.Text}}
+                {{if .HasExtra}}
+                <div class="step-extra">
+                    {{if .DocString}}<pre class="docstring">{{.DocString}}</pre>{{end}}
+                    {{if .DataTable}}
+                    <table class="data-table">
+                        {{range $i, $row := .DataTable}}
+                        <tr>
+                            {{range $cell := $row}}
+                            {{if eq $i 0}}<th>{{$cell}}</th>{{else}}<td>{{$cell}}</td>{{end}}
+                            {{end}}
+                        </tr>
+                        {{end}}
+                    </table>
+                    {{end}}
+                </div>
+                {{end}}
+            </div>
+            {{end}}
+
+            {{if .Examples}}
+            <div class="examples">
+                {{range .Examples}}
+                <div class="examples-title">Examples{{if .Name}}: {{.Name}}{{end}}</div>
+                <table class="data-table">
+                    <tr>
+                        {{range .Headers}}<th>{{.}}</th>{{end}}
+                    </tr>
+                    {{range .Rows}}
+                    <tr>
+                        {{range .}}<td>{{.}}</td>{{end}}
+                    </tr>
+                    {{end}}
+                </table>
+                {{end}}
+            </div>
+            {{end}}
+        </div>
+    </div>
+{{end}}
 `
 
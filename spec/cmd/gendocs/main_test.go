@@ -0,0 +1,314 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Add task with --priority flag", "add-task-with-priority-flag"},
+		{"  Show   JSON (raw) output!  ", "show-json-raw-output"},
+		{"Größe ändern", "größe-ändern"},
+		{"タスクを追加する", "タスクを追加する"},
+		{"Claim 🚀 task", "claim-task"},
+		{"!!!", "scenario"},
+		{"", "scenario"},
+	}
+	for _, tt := range tests {
+		if got := slugify(tt.name); got != tt.want {
+			t.Errorf("slugify(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSlugifyLongNames(t *testing.T) {
+	long := strings.Repeat("claiming a task ", 20)
+	slug := slugify(long)
+	if n := utf8.RuneCountInString(slug); n > maxSlugLength {
+		t.Errorf("slug has %d runes, want at most %d", n, maxSlugLength)
+	}
+	if strings.HasSuffix(slug, "-") || !strings.HasPrefix(slug, "claiming-a-task-") {
+		t.Errorf("slugify() = %q, want whole words without a trailing hyphen", slug)
+	}
+	if slugify(long) != slug {
+		t.Error("slugify() is not deterministic")
+	}
+
+	// A single word longer than the limit is cut
+	word := strings.Repeat("ü", maxSlugLength+10)
+	if got := slugify(word); utf8.RuneCountInString(got) != maxSlugLength {
+		t.Errorf("slugify() of a long word has %d runes, want %d", utf8.RuneCountInString(got), maxSlugLength)
+	}
+}
+
+func TestScenarioAnchorsDeduplicate(t *testing.T) {
+	anchors := newAnchorSet()
+	got := []string{
+		anchors.add(scenarioAnchor("git_sync.feature", "Sync pulls")),
+		anchors.add(scenarioAnchor("git_sync.feature", "Sync pulls")),
+		anchors.add(scenarioAnchor("git_sync.feature", "Sync pulls!")),
+		anchors.add(scenarioAnchor("git_sync.feature", "Sync pulls 2")),
+		anchors.add(scenarioAnchor("add.feature", "Sync pulls")),
+	}
+	want := []string{
+		"git-sync--sync-pulls",
+		"git-sync--sync-pulls-2",
+		"git-sync--sync-pulls-3",
+		"git-sync--sync-pulls-2-2",
+		"add--sync-pulls",
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("anchor %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestBuildDocDataAnchors(t *testing.T) {
+	feature, err := parseGherkin(`Feature: Add
+  Scenario: Add a task
+    When I run "backlog add x"
+
+  @smoke
+  Scenario: Add a task
+    When I run "backlog add y"
+`, "features/add.feature")
+	if err != nil {
+		t.Fatalf("parseGherkin() error = %v", err)
+	}
+
+	data := buildDocData([]Feature{feature}, "Docs", nil)
+	scenarios := data.FeaturesByPhase[0].Features[0].Scenarios
+	if len(scenarios) != 2 || scenarios[0].Anchor != "add--add-a-task" || scenarios[1].Anchor != "add--add-a-task-2" {
+		t.Errorf("scenarios = %+v, want anchors add--add-a-task and add--add-a-task-2", scenarios)
+	}
+
+	// A tag filter doesn't renumber the scenarios it keeps
+	filter, _ := parseTagFilter("@smoke")
+	data = buildDocData([]Feature{feature}, "Docs", filter)
+	scenarios = data.FeaturesByPhase[0].Features[0].Scenarios
+	if len(scenarios) != 1 || scenarios[0].Anchor != "add--add-a-task-2" {
+		t.Errorf("filtered scenarios = %+v, want only add--add-a-task-2", scenarios)
+	}
+}
+
+func TestParseGherkinRules(t *testing.T) {
+	feature, err := parseGherkin(`Feature: Claim
+  Agents take ownership of tasks
+
+  Background:
+    Given a backlog
+
+  Scenario: Claim a task
+    When I run "backlog claim 001"
+
+  @locking
+  Rule: Only one agent holds a task
+    Claims by other agents are refused
+
+    Background:
+      Given task "001" is claimed by "agent-a"
+
+    Scenario: Another agent is refused
+      When I run "backlog claim 001"
+      Then the exit code should be 2
+
+  Rule: Releasing frees the task
+    Scenario: Claim after release
+      When I run "backlog release 001"
+`, "features/claim.feature")
+	if err != nil {
+		t.Fatalf("parseGherkin() error = %v", err)
+	}
+
+	if len(feature.Scenarios) != 1 || feature.Scenarios[0].Name != "Claim a task" {
+		t.Errorf("feature scenarios = %+v, want only the one before the rules", feature.Scenarios)
+	}
+	if feature.Description != "Agents take ownership of tasks" {
+		t.Errorf("feature description = %q", feature.Description)
+	}
+	if feature.Background == nil || len(feature.Background.Steps) != 1 {
+		t.Fatalf("feature background = %+v, want one step", feature.Background)
+	}
+	if len(feature.Rules) != 2 {
+		t.Fatalf("rules = %+v, want 2", feature.Rules)
+	}
+
+	rule := feature.Rules[0]
+	if rule.Name != "Only one agent holds a task" || rule.Description != "Claims by other agents are refused" {
+		t.Errorf("rule = %q/%q", rule.Name, rule.Description)
+	}
+	if len(rule.Tags) != 1 || rule.Tags[0] != "@locking" {
+		t.Errorf("rule tags = %v, want [@locking]", rule.Tags)
+	}
+	if rule.Background == nil || len(rule.Background.Steps) != 1 || rule.Background.Steps[0].Keyword != "Given" {
+		t.Errorf("rule background = %+v, want its own Given step", rule.Background)
+	}
+	if len(rule.Scenarios) != 1 || len(rule.Scenarios[0].Steps) != 2 {
+		t.Errorf("rule scenarios = %+v, want one scenario with two steps", rule.Scenarios)
+	}
+	if len(feature.Rules[1].Scenarios) != 1 || feature.Rules[1].Background != nil {
+		t.Errorf("second rule = %+v, want one scenario and no background", feature.Rules[1])
+	}
+
+	data := buildDocData([]Feature{feature}, "Docs", nil)
+	fd := data.FeaturesByPhase[0].Features[0]
+	if fd.ScenarioCount != 3 || data.TotalScenarios != 3 {
+		t.Errorf("ScenarioCount = %d, TotalScenarios = %d; want rule scenarios counted", fd.ScenarioCount, data.TotalScenarios)
+	}
+	if got := fd.Rules[1].Scenarios[0].Anchor; got != "claim--claim-after-release" {
+		t.Errorf("rule scenario anchor = %q", got)
+	}
+
+	out := filepath.Join(t.TempDir(), "index.html")
+	if err := generateHTML(data, out); err != nil {
+		t.Fatalf("generateHTML() error = %v", err)
+	}
+	html, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Only one agent holds a task", `id="claim--another-agent-is-refused"`, `class="rule"`} {
+		if !strings.Contains(string(html), want) {
+			t.Errorf("HTML does not contain %q", want)
+		}
+	}
+}
+
+func TestParseGherkinTags(t *testing.T) {
+	feature, err := parseGherkin(`# Tags on each keyword belong to it alone
+@local
+@git
+Feature: Sync
+  Keeps the backlog in step with the remote
+
+  @git-remote
+  Scenario: Pull remote changes
+    When I run "backlog sync"
+
+  Scenario: Untagged
+    When I run "backlog sync --status"
+
+  @slow @flaky
+  # a comment between tags and the keyword
+  Scenario Outline: Retry <n> times
+    When I run "backlog sync"
+
+    @smoke
+    Examples:
+      | n |
+      | 1 |
+
+  @wip
+  Scenario: Last
+    When I run "backlog sync"
+`, "features/sync.feature")
+	if err != nil {
+		t.Fatalf("parseGherkin() error = %v", err)
+	}
+
+	if got := strings.Join(feature.Tags, " "); got != "@local @git" {
+		t.Errorf("feature tags = %q, want %q", got, "@local @git")
+	}
+	want := []string{"@git-remote", "", "@slow @flaky", "@wip"}
+	if len(feature.Scenarios) != len(want) {
+		t.Fatalf("scenarios = %d, want %d", len(feature.Scenarios), len(want))
+	}
+	for i, s := range feature.Scenarios {
+		if got := strings.Join(s.Tags, " "); got != want[i] {
+			t.Errorf("scenario %q tags = %q, want %q", s.Name, got, want[i])
+		}
+	}
+}
+
+func TestBuildDocDataTagFilter(t *testing.T) {
+	smoke, err := parseGherkin(`@smoke
+Feature: Add
+  Scenario: Add a task
+    When I run "backlog add 'Fix login'"
+
+  @wip
+  Scenario: Add from a template
+    When I run "backlog add --template bug"
+`, "add.feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, err := parseGherkin(`Feature: Claim
+  Scenario: Claim a task
+    When I run "backlog claim 001"
+
+  @smoke
+  Rule: Only one agent holds a task
+    Scenario: Another agent is refused
+      When I run "backlog claim 001"
+
+  Rule: Release
+    Scenario: Release a claim
+      When I run "backlog release 001"
+`, "claim.feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := parseGherkin(`Feature: List
+  @wip
+  Scenario: List everything
+    When I run "backlog list"
+`, "list.feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	features := []Feature{smoke, rules, other}
+
+	filter, err := parseTagFilter("@smoke && ~@wip")
+	if err != nil {
+		t.Fatalf("parseTagFilter() error = %v", err)
+	}
+	data := buildDocData(features, "Docs", filter)
+	if data.TotalFeatures != 2 || data.TotalScenarios != 2 {
+		t.Errorf("TotalFeatures = %d, TotalScenarios = %d; want 2 and 2", data.TotalFeatures, data.TotalScenarios)
+	}
+	if data.Tags != "@smoke && ~@wip" {
+		t.Errorf("Tags = %q", data.Tags)
+	}
+	var names []string
+	for _, pg := range data.FeaturesByPhase {
+		for _, fd := range pg.Features {
+			for _, sd := range fd.Scenarios {
+				names = append(names, sd.Name)
+			}
+			for _, rd := range fd.Rules {
+				for _, sd := range rd.Scenarios {
+					names = append(names, rd.Name+": "+sd.Name)
+				}
+			}
+		}
+	}
+	want := "Add a task|Only one agent holds a task: Another agent is refused"
+	if got := strings.Join(names, "|"); got != want {
+		t.Errorf("scenarios = %q, want %q", got, want)
+	}
+
+	// Commas are OR
+	filter, _ = parseTagFilter("@smoke,~@wip")
+	if data := buildDocData(features, "Docs", filter); data.TotalFeatures != 2 || data.TotalScenarios != 5 {
+		t.Errorf("@smoke,~@wip: TotalFeatures = %d, TotalScenarios = %d; want 2 and 5", data.TotalFeatures, data.TotalScenarios)
+	}
+
+	if data := buildDocData(features, "Docs", nil); data.TotalFeatures != 3 || data.TotalScenarios != 6 {
+		t.Errorf("no filter: TotalFeatures = %d, TotalScenarios = %d; want 3 and 6", data.TotalFeatures, data.TotalScenarios)
+	}
+
+	for _, expr := range []string{"smoke", "@smoke,", "~", "@a && && @b"} {
+		if _, err := parseTagFilter(expr); err == nil {
+			t.Errorf("parseTagFilter(%q) error = nil, want an invalid tag", expr)
+		}
+	}
+}
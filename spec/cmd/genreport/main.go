@@ -2,13 +2,18 @@
 package main
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"html/template"
+	"mime"
 	"os"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // Cucumber JSON structures
@@ -37,10 +42,19 @@ type Scenario struct {
 }
 
 type Step struct {
-	Keyword string `json:"keyword"`
-	Name    string `json:"name"`
-	Line    int    `json:"line"`
-	Result  Result `json:"result"`
+	Keyword    string      `json:"keyword"`
+	Name       string      `json:"name"`
+	Line       int         `json:"line"`
+	Result     Result      `json:"result"`
+	Embeddings []Embedding `json:"embeddings"`
+}
+
+// Embedding is an attachment added to a step, such as a screenshot or a
+// captured command output. Data is base64 encoded.
+type Embedding struct {
+	MimeType string `json:"mime_type"`
+	Data     string `json:"data"`
+	Name     string `json:"name,omitempty"`
 }
 
 type Result struct {
@@ -67,7 +81,25 @@ type ReportData struct {
 	PassedSteps   int
 	FailedSteps   int
 	SkippedSteps  int
+	Duration      string
 	Features      []FeatureReport
+
+	// Pending and undefined steps are counted apart from skipped ones, as
+	// are the scenarios containing them, since they mark unimplemented
+	// steps rather than steps that didn't run.
+	PendingScenarios   int
+	UndefinedScenarios int
+	PendingSteps       int
+	UndefinedSteps     int
+
+	// Slowest lists the scenarios that took longest, slowest first.
+	Slowest []ScenarioReport
+
+	// OnlyFailures is set when features without failures were left out.
+	// OmittedFeatures and OmittedScenarios count what was left out.
+	OnlyFailures     bool
+	OmittedFeatures  int
+	OmittedScenarios int
 }
 
 type FeatureReport struct {
@@ -78,44 +110,109 @@ type FeatureReport struct {
 	PassCount   int
 	FailCount   int
 	SkipCount   int
+	Duration    string
+	DurationNS  int64
+
+	// PendingCount and UndefinedCount count the scenarios with pending or
+	// undefined steps.
+	PendingCount   int
+	UndefinedCount int
+
+	// CollapsedCount is the number of scenarios with Collapsed set, counting
+	// each example of an outline.
+	CollapsedCount int
 }
 
 type ScenarioReport struct {
-	Name   string
-	Tags   string
-	Status string
-	Steps  []StepReport
+	Name       string
+	Feature    string
+	Tags       string
+	Status     string
+	Steps      []StepReport
+	Duration   string
+	DurationNS int64
+
+	// Collapsed scenarios are folded away under a summary line.
+	Collapsed bool
+
+	// Examples holds the runs of a scenario outline, one per example row,
+	// each counted as a scenario of its own. The outline's status is the
+	// worst of its examples' and its duration their sum. Example numbers
+	// the runs from 1.
+	Examples []ScenarioReport
+	Example  int
+
+	// outline is the ID of the scenario outline an entry belongs to.
+	outline string
 }
 
+// runs returns how many scenarios sr stands for: one, or one per example
+// of an outline.
+func (sr ScenarioReport) runs() int {
+	return max(len(sr.Examples), 1)
+}
+
+// maxSlowest is how many scenarios the slowest scenarios list shows.
+const maxSlowest = 10
+
 type StepReport struct {
-	Keyword  string
-	Name     string
-	Status   string
-	Duration string
-	Error    string
+	Keyword     string
+	Name        string
+	Status      string
+	Duration    string
+	Error       string
+	Attachments []AttachmentReport
+}
+
+// AttachmentReport is an embedding prepared for display. Kind selects how it
+// is rendered: "text" inline, "image" as an <img>, "link" as a download.
+type AttachmentReport struct {
+	Name      string
+	MimeType  string
+	Size      string
+	Kind      string
+	Text      string
+	FullText  string
+	Truncated bool
+	DataURI   template.URL
 }
 
+// maxInlineText is how much of a text attachment is shown before the rest
+// is hidden behind a "show full" toggle.
+const maxInlineText = 4096
+
 func main() {
-	inputFile := flag.String("input", "cucumber.json", "Input Cucumber JSON file")
+	inputFile := flag.String("input", "cucumber.json", "Input Cucumber JSON file(s), comma-separated")
 	outputFile := flag.String("output", "report.html", "Output HTML file")
 	title := flag.String("title", "Backlog CLI - Specification Report", "Report title")
+	onlyFailures := flag.Bool("only-failures", false, "Only include features with failed scenarios")
+	failOnPending := flag.Bool("fail-on-pending", false, "Exit non-zero if any scenario has pending or undefined steps")
 	flag.Parse()
 
 	// Read input JSON
-	data, err := os.ReadFile(*inputFile)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error reading input file: %v\n", err)
-		os.Exit(1)
-	}
+	var reports []CucumberReport
+	for _, path := range strings.Split(*inputFile, ",") {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading input file: %v\n", err)
+			os.Exit(1)
+		}
 
-	var report CucumberReport
-	if err := json.Unmarshal(data, &report); err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing JSON: %v\n", err)
-		os.Exit(1)
+		var report CucumberReport
+		if err := json.Unmarshal(data, &report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing JSON in %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		reports = append(reports, report)
 	}
+	report := mergeReports(reports...)
 
 	// Transform to report data
 	reportData := transformReport(report, *title)
+	if *onlyFailures {
+		keepOnlyFailures(&reportData)
+	}
+	reportData.Slowest = slowestScenarios(reportData.Features, maxSlowest)
 
 	// Generate HTML
 	if err := generateHTML(reportData, *outputFile); err != nil {
@@ -124,9 +221,66 @@ func main() {
 	}
 
 	fmt.Printf("HTML report generated: %s\n", *outputFile)
-	fmt.Printf("Features: %d, Scenarios: %d (passed: %d, failed: %d, skipped: %d)\n",
+	fmt.Printf("Features: %d, Scenarios: %d (passed: %d, failed: %d, skipped: %d, pending: %d, undefined: %d)\n",
 		reportData.TotalFeatures, reportData.TotalScenarios,
-		reportData.PassedScenarios, reportData.FailedScenarios, reportData.SkippedScenarios)
+		reportData.PassedScenarios, reportData.FailedScenarios, reportData.SkippedScenarios,
+		reportData.PendingScenarios, reportData.UndefinedScenarios)
+	if reportData.OnlyFailures {
+		fmt.Printf("Omitted %d passing features (%d scenarios)\n",
+			reportData.OmittedFeatures, reportData.OmittedScenarios)
+	}
+
+	// The report is still written, so that CI can link to it
+	if *failOnPending && reportData.PendingScenarios+reportData.UndefinedScenarios > 0 {
+		fmt.Fprintf(os.Stderr, "%d scenario(s) have pending or undefined steps\n",
+			reportData.PendingScenarios+reportData.UndefinedScenarios)
+		os.Exit(1)
+	}
+}
+
+// mergeReports combines Cucumber reports into one with a single entry per
+// feature. Scenarios that ran concurrently, or in separate runs, can leave a
+// feature split across several entries in any order; merged features are
+// sorted by URI and their scenarios by line, dropping duplicates.
+func mergeReports(reports ...CucumberReport) CucumberReport {
+	byURI := make(map[string]*Feature)
+	var uris []string
+	for _, report := range reports {
+		for _, feature := range report {
+			merged, ok := byURI[feature.URI]
+			if !ok {
+				f := feature
+				f.Elements = nil
+				merged = &f
+				byURI[feature.URI] = merged
+				uris = append(uris, feature.URI)
+			}
+			merged.Elements = append(merged.Elements, feature.Elements...)
+		}
+	}
+	sort.Strings(uris)
+
+	result := make(CucumberReport, 0, len(uris))
+	for _, uri := range uris {
+		feature := byURI[uri]
+		sort.SliceStable(feature.Elements, func(i, j int) bool {
+			return feature.Elements[i].Line < feature.Elements[j].Line
+		})
+		// Scenario outlines share a line, so examples are told apart by ID
+		seen := make(map[string]bool)
+		elements := feature.Elements[:0]
+		for _, element := range feature.Elements {
+			key := fmt.Sprintf("%s:%d:%s", element.Type, element.Line, element.ID)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			elements = append(elements, element)
+		}
+		feature.Elements = elements
+		result = append(result, *feature)
+	}
+	return result
 }
 
 func transformReport(report CucumberReport, title string) ReportData {
@@ -135,6 +289,7 @@ func transformReport(report CucumberReport, title string) ReportData {
 		GeneratedAt: time.Now().Format("2006-01-02 15:04:05"),
 	}
 
+	var total int64
 	for _, feature := range report {
 		fr := FeatureReport{
 			Name: feature.Name,
@@ -142,16 +297,25 @@ func transformReport(report CucumberReport, title string) ReportData {
 			Tags: formatTags(feature.Tags),
 		}
 
+		// A background runs before the scenario that follows it, so its
+		// time is counted as part of that scenario
+		var background int64
 		for _, scenario := range feature.Elements {
 			if scenario.Type == "background" {
+				background += stepsDuration(scenario.Steps)
 				continue // Skip background sections
 			}
 
 			sr := ScenarioReport{
-				Name:   scenario.Name,
-				Tags:   formatTags(scenario.Tags),
-				Status: "passed",
+				Name:       scenario.Name,
+				Feature:    feature.Name,
+				Tags:       formatTags(scenario.Tags),
+				Status:     "passed",
+				DurationNS: background + stepsDuration(scenario.Steps),
 			}
+			sr.Duration = formatDuration(sr.DurationNS)
+			fr.DurationNS += sr.DurationNS
+			background = 0
 
 			for _, step := range scenario.Steps {
 				stepStatus := step.Result.Status
@@ -166,6 +330,9 @@ func transformReport(report CucumberReport, title string) ReportData {
 					Duration: formatDuration(step.Result.Duration),
 					Error:    step.Result.Error,
 				}
+				for _, embedding := range step.Embeddings {
+					str.Attachments = append(str.Attachments, transformEmbedding(embedding))
+				}
 				sr.Steps = append(sr.Steps, str)
 
 				data.TotalSteps++
@@ -174,16 +341,35 @@ func transformReport(report CucumberReport, title string) ReportData {
 					data.PassedSteps++
 				case "failed":
 					data.FailedSteps++
-					sr.Status = "failed"
+				case "pending":
+					data.PendingSteps++
+				case "undefined":
+					data.UndefinedSteps++
 				default:
+					stepStatus = "skipped"
 					data.SkippedSteps++
-					if sr.Status == "passed" {
-						sr.Status = "skipped"
-					}
 				}
+				sr.Status = worseStatus(sr.Status, stepStatus)
 			}
 
-			fr.Scenarios = append(fr.Scenarios, sr)
+			// Example rows of an outline are reported one after another,
+			// so each is added to the outline entry before it
+			if key := outlineID(scenario.ID); key == "" {
+				fr.Scenarios = append(fr.Scenarios, sr)
+			} else {
+				n := len(fr.Scenarios)
+				if n == 0 || fr.Scenarios[n-1].outline != key {
+					fr.Scenarios = append(fr.Scenarios, ScenarioReport{
+						Name:    sr.Name,
+						Feature: sr.Feature,
+						Tags:    sr.Tags,
+						Status:  "passed",
+						outline: key,
+					})
+					n++
+				}
+				fr.Scenarios[n-1].addExample(sr)
+			}
 			data.TotalScenarios++
 
 			switch sr.Status {
@@ -193,19 +379,202 @@ func transformReport(report CucumberReport, title string) ReportData {
 			case "failed":
 				data.FailedScenarios++
 				fr.FailCount++
+			case "pending":
+				data.PendingScenarios++
+				fr.PendingCount++
+			case "undefined":
+				data.UndefinedScenarios++
+				fr.UndefinedCount++
 			default:
 				data.SkippedScenarios++
 				fr.SkipCount++
 			}
 		}
 
+		fr.Duration = formatDuration(fr.DurationNS)
+		total += fr.DurationNS
 		data.Features = append(data.Features, fr)
 		data.TotalFeatures++
 	}
+	data.Duration = formatDuration(total)
 
 	return data
 }
 
+// outlineID returns the ID of the scenario outline that a scenario with the
+// given ID is an example row of, or "" if it isn't one. Cucumber IDs an
+// example as "feature;outline;examples;row".
+func outlineID(id string) string {
+	parts := strings.Split(id, ";")
+	if len(parts) < 4 {
+		return ""
+	}
+	return strings.Join(parts[:len(parts)-2], ";")
+}
+
+// addExample adds the run of an example row to an outline entry.
+func (sr *ScenarioReport) addExample(example ScenarioReport) {
+	example.Example = len(sr.Examples) + 1
+	sr.Examples = append(sr.Examples, example)
+	sr.DurationNS += example.DurationNS
+	sr.Duration = formatDuration(sr.DurationNS)
+	sr.Status = worseStatus(sr.Status, example.Status)
+}
+
+// statusSeverity orders the statuses of steps and scenarios from best to
+// worst. A scenario takes the worst status of its steps: a pending step
+// makes the steps after it skipped, but the scenario pending.
+var statusSeverity = map[string]int{
+	"passed":    0,
+	"skipped":   1,
+	"pending":   2,
+	"undefined": 3,
+	"failed":    4,
+}
+
+// worseStatus returns whichever of a and b is worse.
+func worseStatus(a, b string) string {
+	if statusSeverity[b] > statusSeverity[a] {
+		return b
+	}
+	return a
+}
+
+// stepsDuration returns the total duration of steps in nanoseconds. Steps
+// that didn't run have no duration and count as zero.
+func stepsDuration(steps []Step) int64 {
+	var total int64
+	for _, step := range steps {
+		if step.Result.Duration > 0 {
+			total += step.Result.Duration
+		}
+	}
+	return total
+}
+
+// keepOnlyFailures drops the features without failed scenarios from the
+// report, counting what was dropped, and collapses the passed scenarios of
+// the features that remain. The summary counts still cover the whole run.
+func keepOnlyFailures(data *ReportData) {
+	data.OnlyFailures = true
+	kept := data.Features[:0]
+	for _, fr := range data.Features {
+		if fr.FailCount == 0 {
+			data.OmittedFeatures++
+			data.OmittedScenarios += fr.PassCount + fr.SkipCount + fr.PendingCount + fr.UndefinedCount
+			continue
+		}
+		for i := range fr.Scenarios {
+			if fr.Scenarios[i].Status == "passed" {
+				fr.Scenarios[i].Collapsed = true
+				fr.CollapsedCount += fr.Scenarios[i].runs()
+			}
+		}
+		kept = append(kept, fr)
+	}
+	data.Features = kept
+}
+
+// slowestScenarios returns up to n scenarios of features, slowest first.
+// Scenarios that took equally long stay in report order, and scenarios
+// without a duration are left out. Outline examples are ranked one by one.
+func slowestScenarios(features []FeatureReport, n int) []ScenarioReport {
+	var scenarios []ScenarioReport
+	for _, fr := range features {
+		for _, sr := range fr.Scenarios {
+			runs := []ScenarioReport{sr}
+			if sr.Examples != nil {
+				runs = sr.Examples
+			}
+			for _, run := range runs {
+				if run.DurationNS > 0 {
+					scenarios = append(scenarios, run)
+				}
+			}
+		}
+	}
+	sort.SliceStable(scenarios, func(i, j int) bool {
+		return scenarios[i].DurationNS > scenarios[j].DurationNS
+	})
+	if len(scenarios) > n {
+		scenarios = scenarios[:n]
+	}
+	return scenarios
+}
+
+// transformEmbedding decodes an embedding for display. Data that isn't valid
+// base64 is shown as text so that it is never silently dropped.
+func transformEmbedding(e Embedding) AttachmentReport {
+	mediaType, _, err := mime.ParseMediaType(e.MimeType)
+	if err != nil {
+		mediaType = "application/octet-stream"
+	}
+	a := AttachmentReport{Name: e.Name, MimeType: mediaType}
+
+	data, err := base64.StdEncoding.DecodeString(e.Data)
+	if err != nil {
+		a.Kind = "text"
+		a.Size = formatSize(len(e.Data))
+		a.setText("invalid base64 data: " + e.Data)
+		return a
+	}
+	a.Size = formatSize(len(data))
+
+	switch mediaType {
+	case "text/plain":
+		a.Kind = "text"
+		a.setText(string(data))
+	case "application/json":
+		a.Kind = "text"
+		var pretty bytes.Buffer
+		if json.Indent(&pretty, data, "", "  ") == nil {
+			data = pretty.Bytes()
+		}
+		a.setText(string(data))
+	case "image/png":
+		a.Kind = "image"
+		a.DataURI = dataURI(mediaType, e.Data)
+	default:
+		a.Kind = "link"
+		a.DataURI = dataURI(mediaType, e.Data)
+	}
+	return a
+}
+
+// setText sets the inline text of an attachment, truncating it on a rune
+// boundary if it is longer than maxInlineText.
+func (a *AttachmentReport) setText(text string) {
+	text = strings.ToValidUTF8(text, "\uFFFD")
+	if len(text) <= maxInlineText {
+		a.Text = text
+		return
+	}
+	cut := maxInlineText
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	a.Text = text[:cut]
+	a.FullText = text
+	a.Truncated = true
+}
+
+// dataURI builds a data URI from a parsed media type and base64 data that
+// has already been validated, so it is safe to use as a link target.
+func dataURI(mediaType, data string) template.URL {
+	return template.URL("data:" + mediaType + ";base64," + data)
+}
+
+func formatSize(n int) string {
+	switch {
+	case n < 1024:
+		return fmt.Sprintf("%d B", n)
+	case n < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+	}
+}
+
 func formatTags(tags []Tag) string {
 	if len(tags) == 0 {
 		return ""
@@ -257,6 +626,8 @@ const htmlTemplate = `<!DOCTYPE html>
             --color-passed: #22c55e;
             --color-failed: #ef4444;
             --color-skipped: #f59e0b;
+            --color-pending: #a855f7;
+            --color-undefined: #64748b;
             --color-bg: #0f172a;
             --color-surface: #1e293b;
             --color-border: #334155;
@@ -316,6 +687,8 @@ const htmlTemplate = `<!DOCTYPE html>
         .stat-passed .stat-value { color: var(--color-passed); }
         .stat-failed .stat-value { color: var(--color-failed); }
         .stat-skipped .stat-value { color: var(--color-skipped); }
+        .stat-pending .stat-value { color: var(--color-pending); }
+        .stat-undefined .stat-value { color: var(--color-undefined); }
         .feature {
             background: var(--color-surface);
             border-radius: 0.5rem;
@@ -355,6 +728,8 @@ const htmlTemplate = `<!DOCTYPE html>
         .badge-passed { background: rgba(34, 197, 94, 0.2); color: var(--color-passed); }
         .badge-failed { background: rgba(239, 68, 68, 0.2); color: var(--color-failed); }
         .badge-skipped { background: rgba(245, 158, 11, 0.2); color: var(--color-skipped); }
+        .badge-pending { background: rgba(168, 85, 247, 0.2); color: var(--color-pending); }
+        .badge-undefined { background: rgba(100, 116, 139, 0.2); color: var(--color-undefined); }
         .scenarios {
             padding: 0 1rem 1rem;
         }
@@ -387,9 +762,16 @@ const htmlTemplate = `<!DOCTYPE html>
             padding: 0.5rem;
             display: none;
         }
-        .scenario.expanded .steps {
+        .scenario.expanded > .steps {
             display: block;
         }
+        .examples .scenario {
+            margin-top: 0.5rem;
+        }
+        .example-number {
+            color: var(--color-text-muted);
+            margin-right: 0.25rem;
+        }
         .step {
             padding: 0.5rem;
             font-family: 'SF Mono', Monaco, Consolas, monospace;
@@ -403,6 +785,8 @@ const htmlTemplate = `<!DOCTYPE html>
         .step-passed { background: rgba(34, 197, 94, 0.1); border-left: 3px solid var(--color-passed); }
         .step-failed { background: rgba(239, 68, 68, 0.1); border-left: 3px solid var(--color-failed); }
         .step-skipped { background: rgba(245, 158, 11, 0.1); border-left: 3px solid var(--color-skipped); }
+        .step-pending { background: rgba(168, 85, 247, 0.1); border-left: 3px solid var(--color-pending); }
+        .step-undefined { background: rgba(100, 116, 139, 0.1); border-left: 3px solid var(--color-undefined); }
         .step-keyword {
             color: #818cf8;
             font-weight: 600;
@@ -422,6 +806,47 @@ const htmlTemplate = `<!DOCTYPE html>
             white-space: pre-wrap;
             word-break: break-word;
         }
+        .attachment {
+            margin-top: 0.5rem;
+            font-size: 0.75rem;
+        }
+        .attachment summary {
+            cursor: pointer;
+            color: var(--color-text-muted);
+        }
+        .attachment-meta {
+            color: var(--color-text-muted);
+            margin-left: 0.5rem;
+        }
+        .attachment pre {
+            margin-top: 0.5rem;
+            padding: 0.5rem;
+            background: rgba(0,0,0,0.3);
+            border-radius: 0.25rem;
+            white-space: pre-wrap;
+            word-break: break-word;
+            max-height: 30rem;
+            overflow: auto;
+        }
+        .attachment img {
+            display: block;
+            margin-top: 0.5rem;
+            max-width: 100%;
+            border: 1px solid var(--color-border);
+            border-radius: 0.25rem;
+        }
+        .attachment a {
+            color: #818cf8;
+        }
+        .show-full {
+            background: none;
+            border: 1px solid var(--color-border);
+            color: var(--color-text);
+            padding: 0.125rem 0.5rem;
+            border-radius: 0.25rem;
+            cursor: pointer;
+            margin-top: 0.25rem;
+        }
         .tags {
             color: #a78bfa;
             font-size: 0.75rem;
@@ -438,6 +863,42 @@ const htmlTemplate = `<!DOCTYPE html>
         .toggle-all:hover {
             background: rgba(255,255,255,0.1);
         }
+        .duration {
+            color: var(--color-text-muted);
+            font-size: 0.75rem;
+        }
+        .omitted {
+            color: var(--color-text-muted);
+            font-size: 0.875rem;
+            margin-bottom: 1rem;
+        }
+        .slowest {
+            background: var(--color-surface);
+            border-radius: 0.5rem;
+            padding: 1rem;
+            margin-bottom: 2rem;
+        }
+        .slowest h2 {
+            font-size: 1rem;
+            font-weight: 600;
+            margin-bottom: 0.5rem;
+        }
+        .slowest ol {
+            padding-left: 1.5rem;
+            font-size: 0.875rem;
+        }
+        .slowest .duration {
+            display: inline-block;
+            min-width: 4rem;
+        }
+        .collapsed-scenarios {
+            margin-top: 0.75rem;
+        }
+        .collapsed-scenarios summary {
+            cursor: pointer;
+            color: var(--color-text-muted);
+            font-size: 0.875rem;
+        }
     </style>
 </head>
 <body>
@@ -468,8 +929,41 @@ const htmlTemplate = `<!DOCTYPE html>
                 <div class="stat-value">{{.SkippedScenarios}}</div>
                 <div class="stat-label">Skipped</div>
             </div>
+            {{if .PendingScenarios}}
+            <div class="stat stat-pending">
+                <div class="stat-value">{{.PendingScenarios}}</div>
+                <div class="stat-label">Pending</div>
+            </div>
+            {{end}}
+            {{if .UndefinedScenarios}}
+            <div class="stat stat-undefined">
+                <div class="stat-value">{{.UndefinedScenarios}}</div>
+                <div class="stat-label">Undefined</div>
+            </div>
+            {{end}}
+            {{if .Duration}}
+            <div class="stat">
+                <div class="stat-value">{{.Duration}}</div>
+                <div class="stat-label">Duration</div>
+            </div>
+            {{end}}
         </div>
 
+        {{if .Slowest}}
+        <div class="slowest">
+            <h2>Slowest scenarios</h2>
+            <ol>
+                {{range .Slowest}}
+                <li><span class="duration">{{.Duration}}</span> {{.Name}} <span class="duration">{{.Feature}}</span></li>
+                {{end}}
+            </ol>
+        </div>
+        {{end}}
+
+        {{if .OnlyFailures}}
+        <p class="omitted">Showing only features with failures; {{.OmittedFeatures}} passing feature(s) with {{.OmittedScenarios}} scenario(s) omitted.</p>
+        {{end}}
+
         <button class="toggle-all" onclick="toggleAll()">Expand/Collapse All</button>
 
         {{range .Features}}
@@ -481,31 +975,21 @@ const htmlTemplate = `<!DOCTYPE html>
                     {{if .Tags}}<div class="tags">{{.Tags}}</div>{{end}}
                 </div>
                 <div class="feature-stats">
+                    {{if .Duration}}<span class="duration">{{.Duration}}</span>{{end}}
                     {{if gt .PassCount 0}}<span class="badge badge-passed">{{.PassCount}} passed</span>{{end}}
                     {{if gt .FailCount 0}}<span class="badge badge-failed">{{.FailCount}} failed</span>{{end}}
                     {{if gt .SkipCount 0}}<span class="badge badge-skipped">{{.SkipCount}} skipped</span>{{end}}
+                    {{if gt .PendingCount 0}}<span class="badge badge-pending">{{.PendingCount}} pending</span>{{end}}
+                    {{if gt .UndefinedCount 0}}<span class="badge badge-undefined">{{.UndefinedCount}} undefined</span>{{end}}
                 </div>
             </div>
             <div class="scenarios">
-                {{range .Scenarios}}
-                <div class="scenario">
-                    <div class="scenario-header" onclick="toggleScenario(this)">
-                        <div>
-                            <span class="scenario-name">{{.Name}}</span>
-                            {{if .Tags}}<span class="scenario-tags">{{.Tags}}</span>{{end}}
-                        </div>
-                        <span class="badge badge-{{.Status}}">{{.Status}}</span>
-                    </div>
-                    <div class="steps">
-                        {{range .Steps}}
-                        <div class="step step-{{.Status}}">
-                            <span class="step-keyword">{{.Keyword}}</span>{{.Name}}
-                            {{if .Duration}}<span class="step-duration">{{.Duration}}</span>{{end}}
-                            {{if .Error}}<div class="step-error">{{.Error}}</div>{{end}}
-                        </div>
-                        {{end}}
-                    </div>
-                </div>
+                {{range .Scenarios}}{{if not .Collapsed}}{{template "scenario" .}}{{end}}{{end}}
+                {{if .CollapsedCount}}
+                <details class="collapsed-scenarios">
+                    <summary>{{.CollapsedCount}} passed scenario(s)</summary>
+                    {{range .Scenarios}}{{if .Collapsed}}{{template "scenario" .}}{{end}}{{end}}
+                </details>
                 {{end}}
             </div>
         </div>
@@ -520,6 +1004,14 @@ const htmlTemplate = `<!DOCTYPE html>
         function toggleScenario(header) {
             header.parentElement.classList.toggle('expanded');
         }
+        function toggleFullText(button) {
+            const full = button.previousElementSibling;
+            const preview = full.previousElementSibling;
+            const showFull = full.hidden;
+            full.hidden = !showFull;
+            preview.hidden = showFull;
+            button.textContent = showFull ? 'Show less' : 'Show full';
+        }
         function toggleAll() {
             const scenarios = document.querySelectorAll('.scenarios');
             const allHidden = Array.from(scenarios).every(s => s.style.display === 'none');
@@ -533,4 +1025,53 @@ const htmlTemplate = `<!DOCTYPE html>
     </script>
 </body>
 </html>
+{{define "scenario"}}
+                <div class="scenario">
+                    <div class="scenario-header" onclick="toggleScenario(this)">
+                        <div>
+                            {{if .Example}}<span class="example-number">#{{.Example}}</span>{{end}}<span class="scenario-name">{{.Name}}</span>
+                            {{if .Tags}}<span class="scenario-tags">{{.Tags}}</span>{{end}}
+                            {{if .Examples}}<span class="scenario-tags">{{len .Examples}} examples</span>{{end}}
+                        </div>
+                        <div>
+                            {{if .Duration}}<span class="duration">{{.Duration}}</span>{{end}}
+                            <span class="badge badge-{{.Status}}">{{.Status}}</span>
+                        </div>
+                    </div>
+                    {{if .Examples}}
+                    <div class="steps examples">
+                        {{range .Examples}}{{template "scenario" .}}{{end}}
+                    </div>
+                    {{else}}
+                    <div class="steps">
+                        {{range .Steps}}
+                        <div class="step step-{{.Status}}">
+                            <span class="step-keyword">{{.Keyword}}</span>{{.Name}}
+                            {{if .Duration}}<span class="step-duration">{{.Duration}}</span>{{end}}
+                            {{if .Error}}<div class="step-error">{{.Error}}</div>{{end}}
+                            {{range .Attachments}}
+                            {{if eq .Kind "text"}}
+                            <details class="attachment">
+                                <summary>{{or .Name "Attachment"}}<span class="attachment-meta">{{.MimeType}}, {{.Size}}</span></summary>
+                                <pre class="attachment-text">{{.Text}}</pre>
+                                {{if .Truncated}}<pre class="attachment-text" hidden>{{.FullText}}</pre>
+                                <button class="show-full" onclick="toggleFullText(this)">Show full</button>{{end}}
+                            </details>
+                            {{else if eq .Kind "image"}}
+                            <details class="attachment" open>
+                                <summary>{{or .Name "Screenshot"}}<span class="attachment-meta">{{.MimeType}}, {{.Size}}</span></summary>
+                                <img src="{{.DataURI}}" alt="{{or .Name "Screenshot"}}">
+                            </details>
+                            {{else}}
+                            <div class="attachment">
+                                <a href="{{.DataURI}}" download="{{or .Name "attachment"}}">{{or .Name "Download attachment"}}</a><span class="attachment-meta">{{.MimeType}}, {{.Size}}</span>
+                            </div>
+                            {{end}}
+                            {{end}}
+                        </div>
+                        {{end}}
+                    </div>
+                    {{end}}
+                </div>
+{{end}}
 `
@@ -0,0 +1,380 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestMergeReports(t *testing.T) {
+	first := CucumberReport{
+		{URI: "features/move.feature", Name: "Move", Elements: []Scenario{
+			{ID: "move;b", Line: 20, Type: "scenario"},
+		}},
+		{URI: "features/add.feature", Name: "Add", Elements: []Scenario{
+			{ID: "add;b", Line: 12, Type: "scenario"},
+		}},
+	}
+	second := CucumberReport{
+		{URI: "features/add.feature", Name: "Add", Elements: []Scenario{
+			{ID: "add;a", Line: 6, Type: "scenario"},
+			{ID: "add;b", Line: 12, Type: "scenario"},
+		}},
+		{URI: "features/move.feature", Name: "Move", Elements: []Scenario{
+			{ID: "move;a", Line: 8, Type: "scenario"},
+		}},
+	}
+
+	merged := mergeReports(first, second)
+	if len(merged) != 2 {
+		t.Fatalf("got %d features, want 2", len(merged))
+	}
+
+	add, move := merged[0], merged[1]
+	if add.URI != "features/add.feature" || move.URI != "features/move.feature" {
+		t.Errorf("features not sorted by URI: %s, %s", add.URI, move.URI)
+	}
+	if len(add.Elements) != 2 || add.Elements[0].ID != "add;a" || add.Elements[1].ID != "add;b" {
+		t.Errorf("add scenarios = %+v, want add;a then add;b without duplicates", add.Elements)
+	}
+	if len(move.Elements) != 2 || move.Elements[0].Line != 8 || move.Elements[1].Line != 20 {
+		t.Errorf("move scenarios = %+v, want sorted by line", move.Elements)
+	}
+}
+
+func TestMergeReportsKeepsOutlineExamples(t *testing.T) {
+	report := CucumberReport{
+		{URI: "features/add.feature", Elements: []Scenario{
+			{ID: "add;priority;;3", Line: 30, Type: "scenario"},
+			{ID: "add;priority;;2", Line: 30, Type: "scenario"},
+		}},
+	}
+
+	merged := mergeReports(report)
+	if len(merged[0].Elements) != 2 {
+		t.Errorf("got %d scenarios, want both outline examples", len(merged[0].Elements))
+	}
+}
+
+func TestEmbeddings(t *testing.T) {
+	raw, err := os.ReadFile(filepath.Join("testdata", "embeddings.json"))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	var report CucumberReport
+	if err := json.Unmarshal(raw, &report); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	data := transformReport(report, "Report")
+	steps := data.Features[0].Scenarios[0].Steps
+	var attachments []AttachmentReport
+	for _, step := range steps {
+		attachments = append(attachments, step.Attachments...)
+	}
+	if len(attachments) != 4 {
+		t.Fatalf("got %d attachments, want 4", len(attachments))
+	}
+
+	tests := []struct {
+		kind     string
+		mimeType string
+	}{
+		{"text", "text/plain"},
+		{"text", "application/json"},
+		{"image", "image/png"},
+		{"link", "text/csv"},
+	}
+	for i, tt := range tests {
+		if attachments[i].Kind != tt.kind || attachments[i].MimeType != tt.mimeType {
+			t.Errorf("attachment %d = %s %s, want %s %s", i, attachments[i].Kind, attachments[i].MimeType, tt.kind, tt.mimeType)
+		}
+	}
+	if want := "{\n  \"id\": \"001\",\n  \"title\": \"First task\"\n}"; attachments[1].Text != want {
+		t.Errorf("JSON attachment = %q, want it pretty-printed", attachments[1].Text)
+	}
+
+	out := filepath.Join(t.TempDir(), "report.html")
+	if err := generateHTML(data, out); err != nil {
+		t.Fatalf("generateHTML() error = %v", err)
+	}
+	html, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	for _, want := range []string{
+		`<details class="attachment">`,
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+		`<img src="data:image/png;base64,iVBORw0KGgo`,
+		`<a href="data:text/csv;base64,aWQsdGl0bGUKMDAxLEZpcnN0IHRhc2sK" download="tasks.csv">`,
+	} {
+		if !strings.Contains(string(html), want) {
+			t.Errorf("report does not contain %q", want)
+		}
+	}
+	if strings.Contains(string(html), "<script>alert(1)") {
+		t.Error("text attachment was not escaped")
+	}
+}
+
+func TestEmbeddingTruncation(t *testing.T) {
+	text := strings.Repeat("é", maxInlineText)
+	a := transformEmbedding(Embedding{MimeType: "text/plain", Data: base64.StdEncoding.EncodeToString([]byte(text))})
+	if !a.Truncated || a.FullText != text {
+		t.Fatalf("Truncated = %v, want the full text kept behind the toggle", a.Truncated)
+	}
+	if len(a.Text) > maxInlineText || !utf8.ValidString(a.Text) {
+		t.Errorf("inline text is %d bytes (valid UTF-8: %v), want at most %d", len(a.Text), utf8.ValidString(a.Text), maxInlineText)
+	}
+
+	short := transformEmbedding(Embedding{MimeType: "text/plain", Data: base64.StdEncoding.EncodeToString([]byte("short"))})
+	if short.Truncated || short.Text != "short" {
+		t.Errorf("short attachment = %+v, want it shown in full", short)
+	}
+
+	invalid := transformEmbedding(Embedding{MimeType: "image/png", Data: "not base64!"})
+	if invalid.Kind != "text" || invalid.DataURI != "" {
+		t.Errorf("invalid data = %+v, want it shown as text without a data URI", invalid)
+	}
+}
+
+// timedScenario returns a scenario whose steps took the given durations in
+// milliseconds; a negative duration leaves the step without a result.
+func timedScenario(name, status string, ms ...int64) Scenario {
+	s := Scenario{Name: name, Type: "scenario"}
+	for _, d := range ms {
+		step := Step{Name: "step", Result: Result{Status: status, Duration: d * int64(time.Millisecond)}}
+		if d < 0 {
+			step.Result = Result{}
+		}
+		s.Steps = append(s.Steps, step)
+	}
+	return s
+}
+
+func TestDurations(t *testing.T) {
+	background := timedScenario("", "passed", 5)
+	background.Type = "background"
+	report := CucumberReport{
+		{URI: "features/add.feature", Name: "Add", Elements: []Scenario{
+			background,
+			timedScenario("slow", "passed", 100, 0, -1),
+			background,
+			timedScenario("quick", "passed", 10),
+		}},
+	}
+
+	data := transformReport(report, "Report")
+	add := data.Features[0]
+	if got := add.Scenarios[0].DurationNS; got != int64(105*time.Millisecond) {
+		t.Errorf("slow duration = %s, want 105ms including the background", time.Duration(got))
+	}
+	if add.Scenarios[1].Duration != "15ms" {
+		t.Errorf("quick duration = %q, want 15ms", add.Scenarios[1].Duration)
+	}
+	if add.Duration != "120ms" || data.Duration != "120ms" {
+		t.Errorf("feature/total duration = %q/%q, want 120ms", add.Duration, data.Duration)
+	}
+}
+
+func TestSlowestScenarios(t *testing.T) {
+	var scenarios []Scenario
+	for i := 0; i < 12; i++ {
+		scenarios = append(scenarios, timedScenario(fmt.Sprintf("s%02d", i), "passed", int64(i%3)))
+	}
+	data := transformReport(CucumberReport{{Name: "Add", Elements: scenarios}}, "Report")
+
+	var names []string
+	for _, sr := range slowestScenarios(data.Features, maxSlowest) {
+		names = append(names, sr.Name)
+	}
+	// Ties keep report order and untimed scenarios are left out
+	want := "s02,s05,s08,s11,s01,s04,s07,s10"
+	if strings.Join(names, ",") != want {
+		t.Errorf("slowest = %v, want %s", names, want)
+	}
+
+	if got := slowestScenarios(data.Features, 3); len(got) != 3 || got[2].Name != "s08" {
+		t.Errorf("slowestScenarios(3) = %d scenarios, want the top 3", len(got))
+	}
+}
+
+func TestOnlyFailures(t *testing.T) {
+	report := CucumberReport{
+		{URI: "features/add.feature", Name: "Add", Elements: []Scenario{
+			timedScenario("adds", "passed", 1),
+			timedScenario("adds again", "passed", 1),
+		}},
+		{URI: "features/move.feature", Name: "Move", Elements: []Scenario{
+			timedScenario("moves", "passed", 2),
+			timedScenario("breaks", "failed", 30),
+		}},
+	}
+
+	data := transformReport(report, "Report")
+	keepOnlyFailures(&data)
+	data.Slowest = slowestScenarios(data.Features, maxSlowest)
+
+	if len(data.Features) != 1 || data.Features[0].Name != "Move" {
+		t.Fatalf("features = %+v, want only Move", data.Features)
+	}
+	if data.OmittedFeatures != 1 || data.OmittedScenarios != 2 {
+		t.Errorf("omitted = %d features, %d scenarios; want 1, 2", data.OmittedFeatures, data.OmittedScenarios)
+	}
+	if data.TotalScenarios != 4 {
+		t.Errorf("TotalScenarios = %d, want the whole run counted", data.TotalScenarios)
+	}
+	move := data.Features[0]
+	if move.CollapsedCount != 1 || !move.Scenarios[0].Collapsed || move.Scenarios[1].Collapsed {
+		t.Errorf("scenarios = %+v, want only the passed one collapsed", move.Scenarios)
+	}
+	if len(data.Slowest) != 2 || data.Slowest[0].Name != "breaks" {
+		t.Errorf("slowest = %+v, want the failures' features only", data.Slowest)
+	}
+
+	out := filepath.Join(t.TempDir(), "report.html")
+	if err := generateHTML(data, out); err != nil {
+		t.Fatalf("generateHTML() error = %v", err)
+	}
+	html, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	for _, want := range []string{
+		"1 passing feature(s) with 2 scenario(s) omitted",
+		"<h2>Slowest scenarios</h2>",
+		`<summary>1 passed scenario(s)</summary>`,
+		`<span class="duration">30ms</span>`,
+	} {
+		if !strings.Contains(string(html), want) {
+			t.Errorf("report does not contain %q", want)
+		}
+	}
+	if strings.Contains(string(html), "adds again") {
+		t.Error("report contains a scenario of an omitted feature")
+	}
+}
+
+func TestScenarioOutlineExamples(t *testing.T) {
+	example := func(row int, status string, ms int64) Scenario {
+		s := timedScenario("set priority", status, ms)
+		s.ID = fmt.Sprintf("add;set-priority;;%d", row)
+		s.Keyword = "Scenario Outline"
+		s.Line = 30
+		return s
+	}
+	report := mergeReports(CucumberReport{
+		{URI: "features/add.feature", Name: "Add", Elements: []Scenario{
+			{ID: "add;plain", Name: "plain", Line: 10, Type: "scenario", Steps: timedScenario("", "passed", 1).Steps},
+			example(2, "passed", 5),
+			example(3, "failed", 20),
+			example(4, "passed", 7),
+		}},
+	})
+
+	data := transformReport(report, "Report")
+	if data.TotalScenarios != 4 || data.PassedScenarios != 3 || data.FailedScenarios != 1 {
+		t.Errorf("scenarios = %d total, %d passed, %d failed; want each example counted",
+			data.TotalScenarios, data.PassedScenarios, data.FailedScenarios)
+	}
+	add := data.Features[0]
+	if len(add.Scenarios) != 2 {
+		t.Fatalf("got %d scenario entries, want the plain scenario and one outline", len(add.Scenarios))
+	}
+	outline := add.Scenarios[1]
+	if len(outline.Examples) != 3 || outline.Status != "failed" || outline.Duration != "32ms" {
+		t.Errorf("outline = %d examples, %s, %s; want 3, failed, 32ms", len(outline.Examples), outline.Status, outline.Duration)
+	}
+	if ex := outline.Examples[1]; ex.Example != 2 || ex.Status != "failed" {
+		t.Errorf("second example = #%d %s, want #2 failed", ex.Example, ex.Status)
+	}
+	if slowest := slowestScenarios(data.Features, 1); slowest[0].DurationNS != int64(20*time.Millisecond) {
+		t.Errorf("slowest = %s, want the failed example ranked on its own", slowest[0].Duration)
+	}
+
+	keepOnlyFailures(&data)
+	if got := data.Features[0].CollapsedCount; got != 1 {
+		t.Errorf("CollapsedCount = %d, want only the plain scenario collapsed", got)
+	}
+
+	out := filepath.Join(t.TempDir(), "report.html")
+	if err := generateHTML(data, out); err != nil {
+		t.Fatalf("generateHTML() error = %v", err)
+	}
+	html, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	for _, want := range []string{
+		`<span class="scenario-tags">3 examples</span>`,
+		`<div class="steps examples">`,
+		`<span class="example-number">#3</span>`,
+	} {
+		if !strings.Contains(string(html), want) {
+			t.Errorf("report does not contain %q", want)
+		}
+	}
+}
+
+func TestPendingAndUndefinedSteps(t *testing.T) {
+	scenario := func(name string, statuses ...string) Scenario {
+		s := Scenario{Name: name, Type: "scenario"}
+		for _, status := range statuses {
+			s.Steps = append(s.Steps, Step{Name: "step", Result: Result{Status: status}})
+		}
+		return s
+	}
+	report := CucumberReport{
+		{URI: "features/add.feature", Name: "Add", Elements: []Scenario{
+			scenario("works", "passed"),
+			scenario("not written yet", "passed", "pending", "skipped"),
+			scenario("no step definition", "undefined", "pending"),
+			scenario("breaks", "undefined", "failed"),
+			scenario("never ran", "skipped", ""),
+		}},
+	}
+
+	data := transformReport(report, "Report")
+	if data.PendingScenarios != 1 || data.UndefinedScenarios != 1 || data.FailedScenarios != 1 || data.SkippedScenarios != 1 {
+		t.Errorf("scenarios = %d pending, %d undefined, %d failed, %d skipped; want 1 each",
+			data.PendingScenarios, data.UndefinedScenarios, data.FailedScenarios, data.SkippedScenarios)
+	}
+	if data.PendingSteps != 2 || data.UndefinedSteps != 2 || data.SkippedSteps != 3 {
+		t.Errorf("steps = %d pending, %d undefined, %d skipped; want 2, 2, 3",
+			data.PendingSteps, data.UndefinedSteps, data.SkippedSteps)
+	}
+	add := data.Features[0]
+	if add.PendingCount != 1 || add.UndefinedCount != 1 || add.SkipCount != 1 {
+		t.Errorf("feature counts = %d pending, %d undefined, %d skipped; want 1 each",
+			add.PendingCount, add.UndefinedCount, add.SkipCount)
+	}
+	for i, want := range []string{"passed", "pending", "undefined", "failed", "skipped"} {
+		if got := add.Scenarios[i].Status; got != want {
+			t.Errorf("%s status = %s, want %s", add.Scenarios[i].Name, got, want)
+		}
+	}
+
+	out := filepath.Join(t.TempDir(), "report.html")
+	if err := generateHTML(data, out); err != nil {
+		t.Fatalf("generateHTML() error = %v", err)
+	}
+	html, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	for _, want := range []string{
+		`<div class="stat stat-pending">`,
+		`<span class="badge badge-undefined">1 undefined</span>`,
+		`<div class="step step-pending">`,
+	} {
+		if !strings.Contains(string(html), want) {
+			t.Errorf("report does not contain %q", want)
+		}
+	}
+}
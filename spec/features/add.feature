@@ -37,6 +37,61 @@ Feature: Adding Tasks
     Then the exit code should be 0
     And the created task should have description containing "task body from a file"
 
+  Scenario: Add task with description-file preserves markdown verbatim
+    Given a fresh backlog directory
+    And a file "spec.md" with the following content:
+      """
+      Implement the `retry` helper.
+
+      ```go
+      func retry(n int) error {
+
+          return nil
+      }
+      ```
+
+      Trailing paragraph with `backticks`.
+      """
+    When I run "backlog add 'Retry helper' --description-file=spec.md"
+    Then the exit code should be 0
+    And the created task should have description containing:
+      """
+      ```go
+      func retry(n int) error {
+
+          return nil
+      }
+      ```
+      """
+    And the created task should have description containing "Trailing paragraph with `backticks`."
+
+  Scenario: Add task with description from stdin
+    Given a fresh backlog directory
+    When I run "backlog add 'Piped task' --description -" with input:
+      """
+      First paragraph.
+
+      ```sh
+      echo "hello"
+      ```
+      """
+    Then the exit code should be 0
+    And the created task should have description containing:
+      """
+      First paragraph.
+
+      ```sh
+      echo "hello"
+      ```
+      """
+
+  Scenario: Add task rejects both inline description and description-file
+    Given a fresh backlog directory
+    And a file "spec.md" with content "From a file"
+    When I run "backlog add 'Conflicting' --description='Inline' --description-file=spec.md"
+    Then the exit code should be 1
+    And stderr should contain "cannot be used together"
+
   Scenario: Add task with explicit status
     Given a fresh backlog directory
     When I run "backlog add 'Ready task' --status=todo"
@@ -50,6 +105,76 @@ Feature: Adding Tasks
     Then the exit code should be 0
     And the task count should be 2
 
+  Scenario: Add task with a repeated idempotency key returns the existing task
+    Given a fresh backlog directory
+    When I run "backlog add 'Nightly report failed' --idempotency-key report-1"
+    And I run "backlog add 'Nightly report failed' --idempotency-key report-1"
+    Then the exit code should be 0
+    And the task count should be 1
+
+  Scenario: Add task with a different idempotency key creates a new task
+    Given a fresh backlog directory
+    When I run "backlog add 'Nightly report failed' --idempotency-key report-1"
+    And I run "backlog add 'Nightly report failed' --idempotency-key report-2"
+    Then the exit code should be 0
+    And the task count should be 2
+
+  Scenario: Add task with an invalid idempotency key fails
+    Given a fresh backlog directory
+    When I run "backlog add 'Bad key' --idempotency-key 'has spaces'"
+    Then the exit code should be 1
+    And stderr should contain "invalid idempotency key"
+    And the task count should be 0
+
+  Scenario: Add task with a pinned ID
+    Given a fresh backlog directory
+    When I run "backlog add 'Split the parser' --id parser-split -f json"
+    Then the exit code should be 0
+    And the JSON output should have "id" equal to "parser-split"
+    And the JSON output should have "created" equal to "true"
+
+  Scenario: Add task with a pinned ID that is taken fails with a conflict
+    Given a fresh backlog directory
+    When I run "backlog add 'Split the parser' --id parser-split"
+    And I run "backlog add 'Something else' --id parser-split"
+    Then the exit code should be 2
+    And stderr should contain "already exists"
+    And the task count should be 1
+
+  Scenario: Add task with --if-absent returns a task with the same title
+    Given a fresh backlog directory
+    When I run "backlog add 'Write the docs'"
+    And I run "backlog add 'Write the docs' --if-absent -f json"
+    Then the exit code should be 0
+    And the JSON output should have "id" equal to "001"
+    And the JSON output should have "created" equal to "false"
+    And the task count should be 1
+
+  Scenario: Add task with --if-absent prints the existing task in table output
+    Given a fresh backlog directory
+    When I run "backlog add 'Write the docs'"
+    And I run "backlog add 'Write the docs' --if-absent"
+    Then the exit code should be 0
+    And stdout should contain "Exists 001: Write the docs"
+
+  Scenario: Add task with --if-absent matches on the source key instead of the title
+    Given a fresh backlog directory
+    When I run "backlog add 'Split the parser' --source-key plan-42"
+    And I run "backlog add 'Split parser into modules' --if-absent --source-key plan-42 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "created" equal to "false"
+    And the JSON output should have array "labels" containing "source:plan-42"
+    And the task count should be 1
+
+  Scenario: Add task with --if-absent ignores done tasks
+    Given a backlog with the following tasks:
+      | id  | title          | status | priority |
+      | 001 | Write the docs | done   | medium   |
+    When I run "backlog add 'Write the docs' --if-absent -f json"
+    Then the exit code should be 0
+    And the JSON output should have "created" equal to "true"
+    And the task count should be 2
+
   Scenario: Add task outputs created task ID
     Given a fresh backlog directory
     When I run "backlog add 'Test task'"
@@ -62,6 +187,63 @@ Feature: Adding Tasks
     Then the exit code should be 0
     And the JSON output should have "title" equal to "JSON task"
 
+  Scenario Outline: Add task accepts priority aliases in any case
+    Given a fresh backlog directory
+    When I run "backlog add 'Alias test' -p <input> -f json"
+    Then the exit code should be 0
+    And the JSON output should have "priority" equal to "<priority>"
+    And the created task should have priority "<priority>"
+
+    Examples:
+      | input  | priority |
+      | High   | high     |
+      | P0     | urgent   |
+      | p1     | high     |
+      | med    | medium   |
+      | p3     | low      |
+      | NONE   | none     |
+
+  Scenario: Add task with status alias stores the canonical status
+    Given a fresh backlog directory
+    When I run "backlog add 'Alias status' --status=in_progress"
+    Then the exit code should be 0
+    And a task file should exist in "in-progress" directory
+
+  Scenario: Add task with emoji and accents keeps an ASCII filename
+    Given a fresh backlog directory
+    When I run "backlog add 'Ship the café menu 🚀 before Friday ✅' -f json"
+    Then the exit code should be 0
+    And the file ".backlog/backlog/001-ship-the-cafe-menu-before-friday.md" should exist
+    When I run "backlog show 001 -f json"
+    Then the JSON output should have "title" equal to "Ship the café menu 🚀 before Friday ✅"
+
+  Scenario: Add task whose title has no ASCII falls back to the ID filename
+    Given a fresh backlog directory
+    When I run "backlog add 'תיקון באג בהתחברות'"
+    Then the exit code should be 0
+    And the file ".backlog/backlog/001.md" should exist
+    When I run "backlog show 001 -f json"
+    Then the JSON output should have "title" equal to "תיקון באג בהתחברות"
+
+  Scenario: Task files named before slugs were ASCII-only are still found
+    Given a fresh backlog directory
+    And a file ".backlog/todo/007-mise-à-jour.md" with the following content:
+      """
+      ---
+      id: "007"
+      title: Mise à jour
+      ---
+      """
+    When I run "backlog show 007 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "title" equal to "Mise à jour"
+
+  Scenario: Add task with unknown priority fails
+    Given a fresh backlog directory
+    When I run "backlog add 'Bad priority' -p whenever"
+    Then the exit code should be 1
+    And stderr should contain "valid: urgent, high, medium, low, none"
+
   Scenario Outline: Add task with each priority level
     Given a fresh backlog directory
     When I run "backlog add 'Priority test' --priority=<priority>"
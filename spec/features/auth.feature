@@ -0,0 +1,85 @@
+Feature: Credential Management
+  As a user of the backlog CLI
+  I want to store GitHub and Linear credentials outside plaintext files
+  So that tokens don't leak into repositories
+
+  Background:
+    Given a fresh backlog directory
+    And the environment variable "GITHUB_TOKEN" is not set
+    And the environment variable "LINEAR_API_KEY" is not set
+    And a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: .backlog
+          default: true
+        work:
+          backend: github
+          repo: test-owner/test-repo
+        team:
+          backend: linear
+          team: ENG
+      """
+
+  Scenario: Store a workspace's GitHub token in the encrypted store
+    When I run "backlog auth set github --store encrypted -w work" with input:
+      """
+      ghp_stored_secret
+      """
+    Then the exit code should be 0
+    And stdout should contain "encrypted"
+    And stdout should not contain "ghp_stored_secret"
+
+  Scenario: List shows the store each workspace's credential comes from
+    Given I run "backlog auth set github --store encrypted -w work" with input:
+      """
+      ghp_stored_secret
+      """
+    When I run "backlog auth list -f json"
+    Then the exit code should be 0
+    And the JSON output should have "[0].workspace" equal to "team"
+    And the JSON output should have "[0].store" equal to ""
+    And the JSON output should have "[1].workspace" equal to "work"
+    And the JSON output should have "[1].store" equal to "encrypted"
+    And stdout should not contain "ghp_stored_secret"
+
+  Scenario: The environment variable takes precedence over stored credentials
+    Given I run "backlog auth set linear --store encrypted" with input:
+      """
+      lin_api_stored
+      """
+    And the environment variable "LINEAR_API_KEY" is "lin_api_env"
+    When I run "backlog auth list -f json"
+    Then the exit code should be 0
+    And the JSON output should have "[0].workspace" equal to "team"
+    And the JSON output should have "[0].store" equal to "env"
+
+  Scenario: Reject a backend that takes no credentials
+    When I run "backlog auth set jira" with input:
+      """
+      secret
+      """
+    Then the exit code should be 1
+    And stderr should contain "must be github or linear"
+
+  Scenario: Reject a credential for a workspace on another backend
+    When I run "backlog auth set linear --store encrypted -w work" with input:
+      """
+      lin_api_stored
+      """
+    Then the exit code should be 1
+    And stderr should contain "uses the github backend"
+
+  @github
+  Scenario: GitHub backend connects with the stored token
+    Given I run "backlog auth set github --store encrypted -w work" with input:
+      """
+      ghp_stored_secret
+      """
+    And a mock GitHub API server is running
+    And the mock GitHub API expects token "ghp_stored_secret"
+    When I run "backlog list -w work -f json"
+    Then the exit code should be 0
+    And the JSON output should have "tasks" as an array
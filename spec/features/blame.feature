@@ -0,0 +1,50 @@
+Feature: Task Blame
+  As a user auditing multi-agent coordination
+  I want to see who last changed each field of a task
+  So that I can tell which agent or person set its status, assignee, or labels
+
+  Background:
+    Given a git repository is initialized
+    And a backlog with the following tasks:
+      | id    | title        | status | priority |
+      | task1 | Seed task    | todo   | low      |
+    And git_sync is enabled in the config
+    And the environment variable "BACKLOG_AGENT_ID" is "test-agent"
+
+  Scenario: Blame attributes each field to the commit that last changed it
+    When I run "backlog add 'Write the docs' --label docs"
+    And I run "backlog edit 001 --priority high"
+    And I run "backlog claim 001"
+    And I run "backlog blame 001 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "task_id" equal to "001"
+    And the JSON output should have "fields[0].field" equal to "title"
+    And the JSON output should have "fields[0].message" equal to "add: 001"
+    And the JSON output should have "fields[0].author" equal to "Test User"
+    And the JSON output should have "fields[1].field" equal to "status"
+    And the JSON output should have "fields[1].value" equal to "in-progress"
+    And the JSON output should have "fields[1].agent" equal to "test-agent"
+    And the JSON output should have "fields[2].field" equal to "priority"
+    And the JSON output should have "fields[2].message" equal to "edit: 001"
+    And the JSON output should have "fields[3].field" equal to "assignee"
+    And the JSON output should have "fields[3].message" containing "claim: 001"
+
+  Scenario: Blame as a table
+    When I run "backlog add 'Write the docs'"
+    And I run "backlog move 001 todo"
+    And I run "backlog blame 001"
+    Then the exit code should be 0
+    And stdout should contain "Blame for 001"
+    And stdout should match pattern "status\s+todo\s+Test User"
+    And stdout should match pattern "title\s+Write the docs\s+Test User"
+
+  Scenario: Blame without git_sync lists fields without attribution
+    Given git_sync is disabled in the config
+    When I run "backlog blame task1 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "fields[1].value" equal to "todo"
+    And the JSON output should have "note" containing "git_sync is disabled"
+
+  Scenario: Blame of a missing task fails
+    When I run "backlog blame nope"
+    Then the exit code should be 3
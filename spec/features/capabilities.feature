@@ -0,0 +1,86 @@
+Feature: Backend Capabilities
+  As an agent working against an unfamiliar backend
+  I want to know which optional operations it supports
+  So that I can skip commands that would fail instead of handling their errors
+
+  Scenario: Local backend supports every optional operation
+    Given a fresh backlog directory
+    When I run "backlog capabilities"
+    Then the exit code should be 0
+    And stdout should contain "Backend: local"
+    And stdout should match pattern "reorder\s+yes\s+reorder"
+    And stdout should match pattern "history\s+yes\s+history"
+    And stdout should not contain " no "
+
+  Scenario: Capabilities as JSON
+    Given a fresh backlog directory
+    When I run "backlog capabilities -f json"
+    Then the exit code should be 0
+    And the JSON output should have "backend" equal to "local"
+    And the JSON output should have "operations[0].name" equal to "claim"
+    And the JSON output should have "operations[0].commands" equal to "claim, release, next --claim, add --claim"
+    And the JSON output should have "operations[0].supported" equal to "true"
+
+  Scenario: Linear capabilities are reported without contacting the API
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: linear
+      workspaces:
+        linear:
+          backend: linear
+          team: ENG
+          api_key_env: LINEAR_API_KEY
+          default: true
+      """
+    When I run "backlog capabilities -f json"
+    Then the exit code should be 0
+    And the JSON output should have "backend" equal to "linear"
+    And the JSON output should have "operations[4].name" equal to "reorder"
+    And the JSON output should have "operations[4].supported" equal to "true"
+    And the JSON output should have "operations[12].name" equal to "history"
+    And the JSON output should have "operations[12].supported" equal to "false"
+
+  Scenario: Id-only output lists the supported operations
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: linear
+      workspaces:
+        linear:
+          backend: linear
+          team: ENG
+          api_key_env: LINEAR_API_KEY
+          default: true
+      """
+    When I run "backlog capabilities -f id-only"
+    Then the exit code should be 0
+    And stdout should contain "reorder"
+    And stdout should not contain "history"
+    And stdout should not contain "doctor"
+
+  @linear
+  Scenario: An unsupported operation has a specific error code
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: linear
+      workspaces:
+        linear:
+          backend: linear
+          team: ENG
+          api_key_env: LINEAR_API_KEY
+          default: true
+      """
+    And the environment variable "LINEAR_API_KEY" is "lin_api_valid_test_key"
+    And a mock Linear API server is running
+    When I run "backlog history ENG-1 -f json"
+    Then the exit code should be 1
+    And the JSON output should have "error.error_code" equal to "UNSUPPORTED"
+    And the JSON output should have "error.message" containing "does not support task history"
@@ -0,0 +1,101 @@
+Feature: Changelog
+  As a team lead at the end of a sprint
+  I want a summary of the tasks that got finished
+  So that I can paste it into release notes
+
+  Background:
+    Given a backlog with the following tasks:
+      | id | title             | status | labels                     | assignee | updated              | completed_at         | url                   |
+      | t1 | Add dark mode     | done   | type:feature               |          | 2025-05-03T10:00:00Z | 2025-05-03T10:00:00Z | https://example.com/1 |
+      | t2 | Fix crash on save | done   | type:bug, agent:claude-1   | alex     | 2025-05-04T10:00:00Z | 2025-05-04T10:00:00Z |                       |
+      | t3 | Tidy docs         | done   |                            |          | 2025-05-05T10:00:00Z | 2025-05-05T10:00:00Z |                       |
+      | t4 | Speed up search   | done   | type:perf                  |          | 2025-05-06T10:00:00Z | 2025-05-06T10:00:00Z |                       |
+      | t5 | Old fix           | done   | type:bug                   |          | 2025-04-20T10:00:00Z | 2025-04-20T10:00:00Z |                       |
+      | t6 | Waiting on review | review | type:feature               |          | 2025-05-07T10:00:00Z |                      |                       |
+
+  Scenario: Changelog groups completed tasks by type label
+    When I run "backlog changelog --since 2025-05-01"
+    Then the exit code should be 0
+    And stdout should contain "## Changes since 2025-05-01"
+    And stdout should contain "### Features"
+    And stdout should contain "- Add dark mode ([t1](https://example.com/1))"
+    And stdout should contain "### Bug Fixes"
+    And stdout should contain "- Fix crash on save (t2)"
+    And stdout should contain "### perf"
+    And stdout should contain "### Other"
+    And stdout should contain "- Tidy docs (t3)"
+    And stdout should not contain "Old fix"
+    And stdout should not contain "Waiting on review"
+
+  Scenario: Changelog as JSON
+    When I run "backlog changelog --since 2025-05-01 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "count" equal to "4"
+    And the JSON output should have "sections[0].title" equal to "Features"
+    And the JSON output should have "sections[0].tasks[0].url" equal to "https://example.com/1"
+    And the JSON output should have "sections[1].label" equal to "type:bug"
+    And the JSON output should have "sections[3].title" equal to "Other"
+    And the JSON output should have "sections[3].tasks[0].id" equal to "t3"
+
+  Scenario: Changelog credits authors
+    When I run "backlog changelog --since 2025-05-01 --include-authors"
+    Then the exit code should be 0
+    And stdout should contain "- Fix crash on save (t2) by @alex, @claude-1"
+
+  Scenario: Changelog sections come from the workspace config
+    Given a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: local
+      workspaces:
+        local:
+          backend: local
+          path: ./.backlog
+          default: true
+          changelog:
+            sections:
+              - label: perf
+                title: Performance
+      """
+    When I run "backlog changelog --since 2025-05-01 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "sections[0].title" equal to "Performance"
+    And the JSON output should have "sections[1].title" equal to "bug"
+
+  Scenario: Changelog groups by another label prefix
+    When I run "backlog changelog --since 2025-05-01 --label-prefix agent -f json"
+    Then the exit code should be 0
+    And the JSON output should have "sections[0].title" equal to "claude-1"
+    And the JSON output should have "sections[1].title" equal to "Other"
+
+  Scenario: Changelog since a git tag
+    Given a git repository is initialized
+    And git_sync is enabled in the config
+    And a backlog with the following tasks:
+      | id | title         | status | labels       | updated              | completed_at         |
+      | t1 | Add dark mode | done   | type:feature | 2025-05-03T10:00:00Z | 2025-05-03T10:00:00Z |
+      | t2 | Old fix       | done   | type:bug     | 2025-04-20T10:00:00Z | 2025-04-20T10:00:00Z |
+      | t3 | Legacy task   | done   |              | 2025-05-02T10:00:00Z |                      |
+    And the repository is tagged "v1.0.0" at "2025-05-01T00:00:00Z"
+    When I run "backlog changelog --since-tag v1.0.0"
+    Then the exit code should be 0
+    And stdout should contain "## Changes since v1.0.0 (2025-05-01)"
+    And stdout should contain "- Add dark mode (t1)"
+    And stdout should contain "- Legacy task (t3)"
+    And stdout should not contain "Old fix"
+
+  Scenario: Changelog since a tag needs git_sync
+    When I run "backlog changelog --since-tag v1.0.0"
+    Then the exit code should be 1
+    And stderr should contain "git_sync"
+
+  Scenario: Changelog rejects both --since and --since-tag
+    When I run "backlog changelog --since 2025-05-01 --since-tag v1.0.0"
+    Then the exit code should be 1
+    And stderr should contain "cannot be used together"
+
+  Scenario: Changelog rejects an invalid --since
+    When I run "backlog changelog --since yesterday"
+    Then the exit code should be 1
+    And stderr should contain "--since"
@@ -0,0 +1,121 @@
+Feature: Task Checklists
+  As a user tracking work in markdown checklists
+  I want to see checklist progress and check items off from the CLI
+  So that I know how far along a task is without opening it
+
+  Background:
+    Given a fresh backlog directory
+    And a file ".backlog/todo/001-ship-release.md" with the following content:
+      """
+      ---
+      id: "001"
+      title: Ship release
+      priority: high
+      created: 2025-01-15T09:00:00Z
+      updated: 2025-01-15T09:00:00Z
+      ---
+
+      ## Description
+
+      - [x] Write the changelog
+      - [ ] Write release tests
+      - [ ] Run tests in CI
+      """
+    And a file ".backlog/todo/002-no-checklist.md" with the following content:
+      """
+      ---
+      id: "002"
+      title: No checklist
+      priority: low
+      created: 2025-01-15T09:00:00Z
+      updated: 2025-01-15T09:00:00Z
+      ---
+
+      ## Description
+
+      Just prose.
+      """
+
+  Scenario: Show JSON includes the structured checklist
+    When I run "backlog show 001 -f json"
+    Then the exit code should be 0
+    And the JSON output array "checklist" should have length 3
+    And the JSON output should have "checklist[0].text" equal to "Write the changelog"
+    And the JSON output should have "checklist[0].done" equal to "true"
+    And the JSON output should have "checklist[1].index" equal to "2"
+    And the JSON output should have "checklist_progress.done" equal to "1"
+    And the JSON output should have "checklist_progress.total" equal to "3"
+
+  Scenario: Show and list display checklist progress
+    When I run "backlog show 001"
+    Then the exit code should be 0
+    And stdout should contain "Checklist: 1/3"
+    When I run "backlog list"
+    Then the exit code should be 0
+    And stdout should contain "CHECKLIST"
+    And stdout should contain "1/3"
+
+  Scenario: List leaves out the checklist column when no task has one
+    When I run "backlog list --priority low"
+    Then the exit code should be 0
+    And stdout should not contain "CHECKLIST"
+
+  Scenario: Check an item by index
+    When I run "backlog check 001 2 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "checklist[1].done" equal to "true"
+    And the JSON output should have "changes.checklist.before" equal to "1/3"
+    And the JSON output should have "changes.checklist.after" equal to "2/3"
+    And the task "001" should have description containing "- [x] Write release tests"
+
+  Scenario: Check an item by a piece of its text
+    When I run "backlog check 001 ci"
+    Then the exit code should be 0
+    And stdout should contain "checklist: 1/3 → 2/3"
+    And the task "001" should have description containing "- [x] Run tests in CI"
+
+  Scenario: Uncheck an item
+    When I run "backlog uncheck 001 changelog"
+    Then the exit code should be 0
+    And the task "001" should have description containing "- [ ] Write the changelog"
+
+  Scenario: Ambiguous text lists the candidates and fails
+    When I run "backlog check 001 tests"
+    Then the exit code should be 1
+    And stderr should contain "matches 2 checklist items"
+    And stderr should contain "2. Write release tests"
+    And stderr should contain "3. Run tests in CI"
+    And the task "001" should have description containing "- [ ] Write release tests"
+
+  Scenario: Ambiguous text reports the candidates in JSON
+    When I run "backlog check 001 tests -f json"
+    Then the exit code should be 1
+    And the JSON output should have "error.code" equal to "INVALID_INPUT"
+    And the JSON output array "error.details.candidates" should have length 2
+
+  Scenario: Reject an item index out of range
+    When I run "backlog check 001 4"
+    Then the exit code should be 1
+    And stderr should contain "out of range"
+
+  Scenario: Reject a task without a checklist
+    When I run "backlog check 002 1"
+    Then the exit code should be 1
+    And stderr should contain "no checklist items"
+
+  Scenario: Checking an item that is already checked changes nothing
+    When I run "backlog check 001 changelog"
+    Then the exit code should be 0
+    And stdout should contain "no changes"
+
+  Scenario: Dry run shows the checked item without saving it
+    When I run "backlog check 001 2 --dry-run -f json"
+    Then the exit code should be 0
+    And the JSON output should have "task.checklist_progress.done" equal to "2"
+    And the task "001" should have description containing "- [ ] Write release tests"
+
+  Scenario: Dry run shows the unchecked item without saving it
+    When I run "backlog uncheck 001 1 --dry-run -f json"
+    Then the exit code should be 0
+    And the JSON output should have "task.checklist_progress.done" equal to "0"
+    And the task "001" should have description containing "- [x] Write the changelog"
@@ -23,6 +23,13 @@ Feature: Claiming Tasks
     Then the exit code should be 0
     And the task "task1" should have label "agent:test-agent"
 
+  Scenario: Claim uses agent ID from per-command environment
+    When I run "backlog claim task1" with env "BACKLOG_AGENT_ID=env-agent"
+    Then the exit code should be 0
+    And the task "task1" should have label "agent:env-agent"
+    When I run "backlog claim task1"
+    Then the exit code should be 2
+
   Scenario: Claim moves task to in-progress
     When I run "backlog claim task1"
     Then the exit code should be 0
@@ -47,6 +54,42 @@ Feature: Claiming Tasks
     Then the exit code should be 2
     And stderr should contain "already claimed"
 
+  Scenario: Claim conflict suggests --steal
+    Given the environment variable "BACKLOG_AGENT_ID" is "different-agent"
+    When I run "backlog claim task2"
+    Then the exit code should be 2
+    And stderr should contain "use --steal to take it over"
+
+  Scenario: Steal takes over a task with another agent's active lock
+    Given the environment variable "BACKLOG_AGENT_ID" is "rescuer"
+    And task "task2" has an active lock from agent "claude-1"
+    When I run "backlog claim task2 --steal"
+    Then the exit code should be 0
+    And stdout should contain "Claimed"
+    And the task "task2" should have label "agent:rescuer"
+    And the task "task2" should not have label "agent:claude-1"
+    And the task "task2" should have comment containing "claim stolen from claude-1 by rescuer"
+
+  Scenario: Steal of an unclaimed task records no audit comment
+    Given the environment variable "BACKLOG_AGENT_ID" is "rescuer"
+    When I run "backlog claim task1 --steal"
+    Then the exit code should be 0
+    And the task "task1" should have label "agent:rescuer"
+    When I run "backlog show task1 --comments"
+    Then stdout should not contain "stolen"
+
+  Scenario: Steal with --dry-run previews the takeover
+    Given the environment variable "BACKLOG_AGENT_ID" is "rescuer"
+    When I run "backlog claim task2 --steal --dry-run -f json"
+    Then the exit code should be 0
+    And the JSON output should have "detail" equal to "agent rescuer, stolen from agent claude-1"
+    And the task "task2" should have label "agent:claude-1"
+
+  Scenario: Steal cannot be combined with --restart-clock
+    When I run "backlog claim task2 --steal --restart-clock"
+    Then the exit code should be 1
+    And stderr should contain "cannot be used together"
+
   Scenario: Claim with explicit agent-id flag
     When I run "backlog claim task1 --agent-id=my-custom-agent"
     Then the exit code should be 0
@@ -80,10 +123,11 @@ Feature: Claiming Tasks
     Then the exit code should be 0
     And the task "task1" should have label "agent:global-default-agent"
 
-  Scenario: Claim falls back to hostname
+  Scenario: Claim falls back to the generated agent ID
+    Given the environment variable "BACKLOG_AGENT_ID" is not set
     When I run "backlog claim task1"
     Then the exit code should be 0
-    And the task "task1" should have agent label
+    And the task "task1" should have the generated agent label
 
   Scenario: Claim creates lock file in file mode
     When I run "backlog claim task1"
@@ -101,3 +145,49 @@ Feature: Claiming Tasks
     And the JSON output should be valid
     And the JSON output should have "id" equal to "task1"
     And the JSON output should have "status" equal to "in-progress"
+
+  Scenario: Claim posts a comment when claim_comment is set
+    Given a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: .backlog
+          claim_comment: true
+          default: true
+      """
+    And the environment variable "BACKLOG_AGENT_ID" is "build-agent-3"
+    When I run "backlog claim task1"
+    Then the exit code should be 0
+    And the task "task1" should have comment containing "claimed by agent `build-agent-3` at"
+    And the task "task1" should have comment containing "— expires"
+
+  Scenario: Claim with --comment posts the message
+    Given the environment variable "BACKLOG_AGENT_ID" is "build-agent-3"
+    When I run "backlog claim task1 --comment='starting work on the retry logic'"
+    Then the exit code should be 0
+    And the task "task1" should have comment containing "claimed by agent `build-agent-3`"
+    And the task "task1" should have comment containing "starting work on the retry logic"
+
+  Scenario: Claiming a task already owned posts no comment
+    Given the environment variable "BACKLOG_AGENT_ID" is "claude-1"
+    When I run "backlog claim task2 --comment='starting again'"
+    Then the exit code should be 0
+    When I run "backlog show task2 --comments"
+    Then stdout should not contain "starting again"
+
+  Scenario: Add with --claim creates a task claimed by the agent
+    Given the environment variable "BACKLOG_AGENT_ID" is "test-agent"
+    When I run "backlog add 'Fix flaky test' --claim -f json"
+    Then the exit code should be 0
+    And the JSON output should have "status" equal to "in-progress"
+    And the JSON output should have array "labels" containing "agent:test-agent"
+    And the JSON output should have "created" equal to "true"
+
+  Scenario: Add with --claim and --dry-run previews the claim
+    Given the environment variable "BACKLOG_AGENT_ID" is "test-agent"
+    When I run "backlog add 'Fix flaky test' --claim --dry-run -f json"
+    Then the exit code should be 0
+    And the JSON output should have "task.status" equal to "in-progress"
+    And the JSON output should have "detail" equal to "would be claimed by agent test-agent"
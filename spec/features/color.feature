@@ -0,0 +1,56 @@
+Feature: Colored Output
+  As a user of the backlog CLI
+  I want statuses and priorities colored in table output when I ask for it
+  So that I can scan the backlog quickly without breaking scripts that parse output
+
+  Background:
+    Given a backlog with the following tasks:
+      | id    | title         | status      | priority |
+      | task1 | Fix outage    | todo        | urgent   |
+      | task2 | Ship feature  | done        | medium   |
+      | task3 | Write docs    | in-progress | low      |
+
+  Scenario: Output is not colored when stdout is not a terminal
+    When I run "backlog list --include-done"
+    Then the exit code should be 0
+    And stdout should not contain ANSI colors
+
+  Scenario: Color always colors statuses and priorities consistently
+    When I run "backlog list --include-done --color=always"
+    Then the exit code should be 0
+    And stdout should contain "urgent" colored red
+    And stdout should contain "done" colored green
+    And stdout should contain "in-progress" colored yellow
+    And stdout should contain "low" colored cyan
+
+  Scenario: Task details are colored
+    When I run "backlog show task1 --color=always"
+    Then the exit code should be 0
+    And stdout should contain "todo" colored blue
+    And stdout should contain "urgent" colored red
+
+  Scenario: Color never disables colors
+    When I run "backlog list --include-done --color=never"
+    Then the exit code should be 0
+    And stdout should not contain ANSI colors
+
+  Scenario: No-color flag overrides color always
+    When I run "backlog list --include-done --color=always --no-color"
+    Then the exit code should be 0
+    And stdout should not contain ANSI colors
+
+  Scenario Outline: Machine-readable formats are never colored
+    When I run "backlog list --include-done --color=always -f <format>"
+    Then the exit code should be 0
+    And stdout should not contain ANSI colors
+
+    Examples:
+      | format  |
+      | json    |
+      | plain   |
+      | id-only |
+
+  Scenario: Invalid color mode is rejected
+    When I run "backlog list --color=sometimes"
+    Then the exit code should be 1
+    And stderr should contain "invalid --color value"
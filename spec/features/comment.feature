@@ -1,6 +1,6 @@
 Feature: Adding Comments
   As a user of the backlog CLI
-  I want to add comments to tasks
+  I want to add, edit, and delete comments on tasks
   So that I can track progress and communicate about work items
 
   Background:
@@ -38,7 +38,8 @@ Feature: Adding Comments
     When I run "backlog comment task1 'JSON comment test' -f json"
     Then the exit code should be 0
     And the JSON output should be valid
-    And the JSON output should have "id" equal to "task1"
+    And the JSON output should have "id" equal to "1"
+    And the JSON output should have "task_id" equal to "task1"
 
   Scenario: Add multiple comments to same task
     When I run "backlog comment task1 'First comment'"
@@ -46,13 +47,140 @@ Feature: Adding Comments
     Then the exit code should be 0
     And the task "task1" should have comment containing "First comment"
     And the task "task1" should have comment containing "Second comment"
+    And the task "task1" should have 2 comments
+
+  Scenario: New comments follow the comments already on a task
+    Given task "task2" has the following comments:
+      | author | date       | body          |
+      | alex   | 2025-01-16 | Can reproduce |
+    When I run "backlog comment task2 'Fixed in the retry logic'"
+    Then the exit code should be 0
+    And the task "task2" should have 2 comments
+    And the task "task2" should have a comment by "alex" containing "Can reproduce"
+    And the task "task2" should have comment containing "Fixed in the retry logic"
 
   Scenario: Comment with empty message fails
     When I run "backlog comment task1 ''"
     Then the exit code should be 1
     And stderr should contain "message"
 
+  Scenario: Comment with only whitespace fails
+    When I run "backlog comment task1 '   '"
+    Then the exit code should be 1
+    And stderr should contain "comment message cannot be empty"
+    And the task "task1" should have 0 comments
+
   Scenario: Comment requires task ID argument
     When I run "backlog comment"
     Then the exit code should be 1
     And stderr should contain "requires"
+
+  Scenario: Comments get stable IDs
+    When I run "backlog comment task1 'First comment' -f json"
+    Then the JSON output should have "id" equal to "1"
+    When I run "backlog comment task1 'Second comment' -f json"
+    Then the JSON output should have "id" equal to "2"
+    And the file ".backlog/in-progress/task1-implement-auth.md" should contain "<!-- id: 2 -->"
+
+  Scenario: List comments oldest first
+    Given task "task1" has the following comments:
+      | id | author | date       | body                  |
+      | 2  | bob    | 2025-01-17 | Reviewed the approach |
+      | 1  | alex   | 2025-01-16 | Started on OAuth      |
+    When I run "backlog comment list task1 -f plain"
+    Then the exit code should be 0
+    And stdout should match pattern "^1\talex\t2025-01-16T00:00:00Z\tStarted on OAuth\n2\tbob\t"
+
+  Scenario: List comments newest first
+    Given task "task1" has the following comments:
+      | id | author | date       | body                  |
+      | 1  | alex   | 2025-01-16 | Started on OAuth      |
+      | 2  | bob    | 2025-01-17 | Reviewed the approach |
+    When I run "backlog comment list task1 --reverse -f json"
+    Then the exit code should be 0
+    And the JSON output should have "comments[0].id" equal to "2"
+    And the JSON output should have "comments[1].author" equal to "alex"
+    And the JSON output should have "comments[1].task_id" equal to "task1"
+    And the JSON output should have "comments[1].created" equal to "2025-01-16T00:00:00Z"
+
+  Scenario: Listed comments name their workspace
+    Given a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: main
+      workspaces:
+        main:
+          backend: local
+          path: .backlog
+      """
+    And task "task1" has the following comments:
+      | id | author | date       | body             |
+      | 1  | alex   | 2025-01-16 | Started on OAuth |
+    When I run "backlog comment list task1 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "comments[0].source" equal to "main"
+
+  Scenario: List comments in a table
+    When I run "backlog comment task1 'Starting work' --agent-id agent-1"
+    And I run "backlog comment list task1"
+    Then the exit code should be 0
+    And stdout should contain "AUTHOR"
+    And stdout should contain "AGE"
+    And stdout should match pattern "1\s+agent-1\s+just now\s+Starting work"
+    And the file ".backlog/in-progress/task1-implement-auth.md" should contain "Z @agent-1 <!-- id: 1 -->"
+
+  Scenario: Listing comments on a missing task returns exit code 3
+    When I run "backlog comment list nonexistent-task"
+    Then the exit code should be 3
+
+  Scenario: Edit own comment
+    Given the environment variable "BACKLOG_AGENT_ID" is "agent-1"
+    And I run "backlog comment task1 'Frist draft'"
+    When I run "backlog comment task1 --edit 1 --body 'First draft' -f json"
+    Then the exit code should be 0
+    And the JSON output should have "id" equal to "1"
+    And the JSON output should have "body" equal to "First draft"
+    And the file ".backlog/in-progress/task1-implement-auth.md" should not contain "Frist draft"
+
+  Scenario: Delete own comment keeps the other IDs
+    Given the environment variable "BACKLOG_AGENT_ID" is "agent-1"
+    And I run "backlog comment task1 'Keep me'"
+    And I run "backlog comment task1 'Delete me'"
+    When I run "backlog comment task1 --delete 2"
+    Then the exit code should be 0
+    And stdout should contain "Deleted comment 2 from task1"
+    And the file ".backlog/in-progress/task1-implement-auth.md" should not contain "Delete me"
+    When I run "backlog comment task1 'Added later' -f json"
+    Then the JSON output should have "id" equal to "3"
+
+  Scenario: Another agent's comment needs --force
+    Given I run "backlog comment task1 'Mine' --agent-id agent-1"
+    When I run "backlog comment task1 --edit 1 --body 'Theirs' --agent-id agent-2"
+    Then the exit code should be 2
+    And stderr should contain "written by agent-1"
+    And stderr should contain "--force"
+    When I run "backlog comment task1 --delete 1 --force --agent-id agent-2"
+    Then the exit code should be 0
+    And the file ".backlog/in-progress/task1-implement-auth.md" should not contain "Mine"
+
+  Scenario: Editing a missing comment returns exit code 3
+    When I run "backlog comment task1 --edit 9 --body 'Nothing here' --agent-id agent-1"
+    Then the exit code should be 3
+    And stderr should contain "not found"
+
+  Scenario: Comment IDs written with a "c" prefix still work
+    Given the environment variable "BACKLOG_AGENT_ID" is "agent-1"
+    And task "task1" has the following comments:
+      | id | author  | date       | body        |
+      | c1 | agent-1 | 2025-01-16 | Frist draft |
+    When I run "backlog comment task1 --edit 1 --body 'First draft' -f json"
+    Then the exit code should be 0
+    And the JSON output should have "id" equal to "c1"
+    When I run "backlog comment task1 'Second' -f json"
+    Then the JSON output should have "id" equal to "2"
+
+  Scenario: Edit requires a body
+    When I run "backlog comment task1 --edit 1 --agent-id agent-1"
+    Then the exit code should be 1
+    And stderr should contain "--edit requires --body"
@@ -0,0 +1,130 @@
+Feature: Shell Completion
+  As a user of the backlog CLI
+  I want task IDs, statuses, and labels to complete in my shell
+  So that I don't have to type them by hand
+
+  Background:
+    Given a backlog with the following tasks:
+      | id  | title                                                   | status      | priority | labels       |
+      | 001 | Fix the login redirect loop for expired mobile sessions | todo        | high     | auth,bug     |
+      | 002 | Add dark mode                                           | in-progress | medium   | ui           |
+      | 003 | Old cleanup                                             | done        | low      |              |
+
+  Scenario: Generate completion scripts for supported shells
+    When I run "backlog completion bash"
+    Then the exit code should be 0
+    And stdout should contain "bash completion V2 for backlog"
+    When I run "backlog completion zsh"
+    Then the exit code should be 0
+    And stdout should contain "#compdef backlog"
+    When I run "backlog completion fish"
+    Then the exit code should be 0
+    And stdout should contain "complete -c backlog"
+
+  Scenario: Reject unsupported shells
+    When I run "backlog completion tcsh"
+    Then the exit code should be 1
+    And stderr should contain "invalid argument"
+
+  Scenario: Task ID arguments complete with truncated titles
+    When I run "backlog __complete show ''"
+    Then the exit code should be 0
+    And stdout should match pattern "001\tFix the login redirect loop for expired…"
+    And stdout should match pattern "002\tAdd dark mode"
+    And stdout should match pattern "003\tOld cleanup"
+
+  Scenario: Task ID completion filters by prefix
+    When I run "backlog __complete claim 00"
+    Then stdout should contain "001"
+    When I run "backlog __complete claim 002"
+    Then stdout should contain "002"
+    And stdout should not contain "001"
+
+  Scenario: Move completes the task ID and then the status
+    When I run "backlog __complete move 001 ''"
+    Then the exit code should be 0
+    And stdout should contain "in-progress"
+    And stdout should contain "review"
+    And stdout should not contain "001"
+
+  Scenario: Status flags complete canonical statuses
+    When I run "backlog __complete list --status ''"
+    Then stdout should contain "backlog"
+    And stdout should contain "in-progress"
+    And stdout should contain "done"
+
+  Scenario: Label flags complete labels used by existing tasks
+    When I run "backlog __complete list --label ''"
+    Then stdout should contain "auth"
+    And stdout should contain "bug"
+    And stdout should contain "ui"
+
+  Scenario: Workspace flag completes configured workspace names
+    Given a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: primary
+      workspaces:
+        primary:
+          backend: local
+          path: ./.backlog
+        tracker:
+          backend: github
+          repo: test-owner/test-repo
+      """
+    When I run "backlog __complete list --workspace ''"
+    Then stdout should match pattern "primary\tlocal"
+    And stdout should match pattern "tracker\tgithub"
+
+  Scenario: Commands without arguments do not complete file names
+    When I run "backlog __complete doctor ''"
+    Then stdout should contain ":4"
+
+  @github
+  Scenario: Remote workspaces complete from the cache refreshed by list
+    Given a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        github:
+          backend: github
+          repo: test-owner/test-repo
+          api_key_env: GITHUB_TOKEN
+          default: true
+      """
+    And the environment variable "GITHUB_TOKEN" is "ghp_valid_test_token"
+    And a mock GitHub API server is running
+    And the mock GitHub API has the following issues:
+      | number | title          | state | labels |
+      | 1      | First issue    | open  | ready  |
+      | 2      | Second issue   | open  |        |
+    When I run "backlog __complete show ''"
+    Then stdout should not contain "GH-1"
+    And the mock GitHub API should have received 0 requests
+    When I run "backlog list"
+    And I run "backlog __complete show ''"
+    Then stdout should match pattern "GH-1\tFirst issue"
+    And stdout should match pattern "GH-2\tSecond issue"
+
+  @github
+  Scenario: Remote completion queries the backend when completion.remote is set
+    Given a config file with the following content:
+      """
+      version: 1
+      completion:
+        remote: true
+      workspaces:
+        github:
+          backend: github
+          repo: test-owner/test-repo
+          api_key_env: GITHUB_TOKEN
+          default: true
+      """
+    And the environment variable "GITHUB_TOKEN" is "ghp_valid_test_token"
+    And a mock GitHub API server is running
+    And the mock GitHub API has the following issues:
+      | number | title          | state | labels |
+      | 1      | First issue    | open  | ready  |
+    When I run "backlog __complete show ''"
+    Then stdout should match pattern "GH-1\tFirst issue"
@@ -83,6 +83,14 @@ Feature: Configuration
     And the JSON output should be valid
     And the JSON output should have "tasks[0].id" equal to "task1"
 
+  Scenario: Sync without a config file uses the local backlog
+    Given a fresh backlog directory
+    And the config file is removed
+    When I run "backlog sync"
+    Then the exit code should be 1
+    And stderr should contain "git pull failed"
+    And stderr should not contain "panic"
+
   Scenario: Invalid config file returns exit code 4
     Given a fresh backlog directory
     And a config file with the following content:
@@ -92,3 +100,290 @@ Feature: Configuration
     When I run "backlog list"
     Then the exit code should be 4
     And stderr should contain "config"
+
+  Scenario: Config get prints a workspace setting
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        local:
+          backend: local
+          path: ./.backlog
+          lock_mode: file
+          default: true
+      """
+    When I run "backlog config get lock_mode"
+    Then the exit code should be 0
+    And stdout should be "file"
+
+  Scenario: Config get falls back to built-in defaults
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        local:
+          backend: local
+          path: ./.backlog
+          default: true
+      """
+    When I run "backlog config get agent_label_prefix -f json"
+    Then the exit code should be 0
+    And the JSON output should have "settings.agent_label_prefix" equal to "agent"
+    And the JSON output should have array "defaults" containing "agent_label_prefix"
+
+  Scenario: Config get rejects unknown keys
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        local:
+          backend: local
+          path: ./.backlog
+          default: true
+      """
+    When I run "backlog config get lock_mod"
+    Then the exit code should be 1
+    And stderr should contain "unknown workspace key"
+
+  Scenario: Config set writes the setting and keeps comments
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        local:
+          backend: local
+          path: ./.backlog
+          # sync tasks through the repository
+          git_sync: true
+          lock_mode: file # until everyone upgrades
+          default: true
+      """
+    When I run "backlog config set lock_mode git"
+    Then the exit code should be 0
+    And the file ".backlog/config.yaml" should contain "lock_mode: git # until everyone upgrades"
+    And the file ".backlog/config.yaml" should contain "# sync tasks through the repository"
+    When I run "backlog config get lock_mode"
+    Then stdout should be "git"
+
+  Scenario: Config set rejects an invalid lock mode
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        local:
+          backend: local
+          path: ./.backlog
+          default: true
+      """
+    When I run "backlog config set lock_mode flock"
+    Then the exit code should be 1
+    And stderr should contain "valid: file, git"
+
+  Scenario: Config set requires git_sync for git lock mode
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        local:
+          backend: local
+          path: ./.backlog
+          default: true
+      """
+    When I run "backlog config set lock_mode git"
+    Then the exit code should be 1
+    And stderr should contain "lock_mode git requires git_sync: true"
+    And the file ".backlog/config.yaml" should not contain "lock_mode"
+
+  Scenario: Config set targets the workspace selected with --workspace
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: primary
+      workspaces:
+        primary:
+          backend: local
+          path: ./.backlog
+        secondary:
+          backend: local
+          path: ./.backlog-secondary
+      """
+    When I run "backlog -w secondary config set git_sync true"
+    Then the exit code should be 0
+    When I run "backlog -w secondary config get git_sync"
+    Then stdout should be "true"
+    When I run "backlog config get git_sync"
+    Then stdout should be "false"
+
+  Scenario: Config list shows the effective workspace settings
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        local:
+          backend: local
+          path: ./.backlog
+          default: true
+      """
+    When I run "backlog config list"
+    Then the exit code should be 0
+    And stdout should contain "Workspace: local"
+    And stdout should contain "lock_mode"
+    And stdout should contain "file (default)"
+
+  Scenario: Config values interpolate environment variables
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: ${BACKLOG_TEST_TASKS:-./.backlog}
+          agent_id: ${BACKLOG_TEST_AGENT}
+          default: true
+      """
+    And the environment variable "BACKLOG_TEST_AGENT" is "ci-runner"
+    When I run "backlog config get agent_id"
+    Then the exit code should be 0
+    And stdout should be "ci-runner"
+    When I run "backlog config get path"
+    Then stdout should be "./.backlog"
+
+  Scenario: Unset variable without a default is a config error naming the key
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: github
+          repo: ${BACKLOG_TEST_UNSET_REPO}
+          default: true
+      """
+    When I run "backlog config show"
+    Then the exit code should be 4
+    And stderr should contain "workspaces.main.repo"
+    And stderr should contain "BACKLOG_TEST_UNSET_REPO is not set"
+
+  Scenario: Resolved config shows each value's source
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        agent_id: file-agent
+      workspaces:
+        main:
+          backend: local
+          path: ${BACKLOG_TEST_TASKS:-./.backlog}
+          agent_label_prefix: ${BACKLOG_TEST_PREFIX}
+          default: true
+      """
+    And the environment variable "BACKLOG_TEST_PREFIX" is "bot"
+    And the environment variable "BACKLOG_AGENT_ID" is "env-agent"
+    When I run "backlog config show --resolved -f plain"
+    Then the exit code should be 0
+    And stdout should match pattern "defaults\.agent_id\tenv-agent\tenv\tBACKLOG_AGENT_ID"
+    And stdout should match pattern "workspaces\.main\.agent_label_prefix\tbot\tenv\tBACKLOG_TEST_PREFIX"
+    And stdout should match pattern "workspaces\.main\.path\t\./\.backlog\tfile"
+    And stdout should match pattern "workspaces\.main\.lock_mode\tfile\tdefault"
+    When I run "backlog config show --resolved --agent-id flag-agent -f plain"
+    Then stdout should match pattern "defaults\.agent_id\tflag-agent\tflag\t--agent-id"
+
+  Scenario: Resolved config redacts credentials
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: github
+          repo: owner/repo
+          default: true
+      """
+    And the environment variable "GITHUB_TOKEN" is "ghp_supersecret"
+    When I run "backlog config show --resolved"
+    Then the exit code should be 0
+    And stdout should contain "credentials.github.token"
+    And stdout should contain "[redacted]"
+    And stdout should not contain "ghp_supersecret"
+
+  Scenario: Command defaults apply when the flag is not given
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: ./.backlog
+          default: true
+      command_defaults:
+        list:
+          labels: [team:infra]
+        next:
+          priority: [urgent, high]
+      """
+    And a backlog with the following tasks:
+      | id    | title        | status | priority | labels     |
+      | task1 | Infra task   | todo   | low      | team:infra |
+      | task2 | Web task     | todo   | urgent   | team:web   |
+      | task3 | Other task   | todo   | medium   |            |
+    When I run "backlog list -f json"
+    Then the exit code should be 0
+    And the JSON output should have "count" equal to "1"
+    And the JSON output should have "tasks[0].id" equal to "task1"
+    When I run "backlog list --label team:web -f json"
+    Then the JSON output should have "count" equal to "1"
+    And the JSON output should have "tasks[0].id" equal to "task2"
+    When I run "backlog list --no-defaults -f json"
+    Then the JSON output should have "count" equal to "3"
+    When I run "backlog next -f id-only"
+    Then stdout should contain "task2"
+    When I run "backlog next --priority low -f id-only"
+    Then stdout should contain "task1"
+
+  Scenario: Command defaults show up in resolved config
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: ./.backlog
+          default: true
+      command_defaults:
+        list:
+          labels: [team:infra]
+      """
+    When I run "backlog config show --resolved -f plain"
+    Then the exit code should be 0
+    And stdout should match pattern "command_defaults\.list\.labels\t.*team:infra.*\tfile"
+
+  Scenario: Unknown command defaults key is rejected
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: ./.backlog
+          default: true
+      command_defaults:
+        next:
+          status: [todo]
+      """
+    When I run "backlog list"
+    Then the exit code should be 4
+    And stderr should contain "command_defaults.next: unknown filter key"
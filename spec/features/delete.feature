@@ -11,20 +11,20 @@ Feature: Deleting Tasks
       | task3 | In progress work   | in-progress | low      | Working on this      |
 
   Scenario: Delete a task
-    When I run "backlog delete task1"
+    When I run "backlog delete task1 --yes"
     Then the exit code should be 0
     And stdout should contain "task1"
     And stdout should contain "Deleted"
 
   Scenario: Deleted task no longer appears in list
-    When I run "backlog delete task1"
+    When I run "backlog delete task1 --yes"
     Then the exit code should be 0
     When I run "backlog list"
     Then stdout should not contain "Task to delete"
     And stdout should contain "Another task"
 
   Scenario: Show deleted task returns not found
-    When I run "backlog delete task1"
+    When I run "backlog delete task1 --yes"
     Then the exit code should be 0
     When I run "backlog show task1"
     Then the exit code should be 3
@@ -35,38 +35,111 @@ Feature: Deleting Tasks
     Then the exit code should be 3
     And stderr should contain "not found"
 
-  Scenario: Delete task in JSON format
+  Scenario: Delete without a terminal requires --yes
+    When I run "backlog delete task1"
+    Then the exit code should be 1
+    And stderr should contain "confirmation required, pass --yes"
+    And the task "task1" should have title "Task to delete"
+
+  Scenario: Delete in JSON format never prompts
     When I run "backlog delete task1 -f json"
+    Then the exit code should be 1
+    And the JSON output should have "error.error_code" equal to "CONFIRMATION_REQUIRED"
+    And the task "task1" should have title "Task to delete"
+
+  Scenario: Soft delete needs no confirmation
+    When I run "backlog delete task1 --soft"
+    Then the exit code should be 0
+
+  Scenario: Quiet delete prints nothing
+    When I run "backlog delete task1 -y -q"
+    Then the exit code should be 0
+    And stdout should be empty
+    When I run "backlog show task1"
+    Then the exit code should be 3
+
+  Scenario: Delete task in JSON format
+    When I run "backlog delete task1 --yes -f json"
     Then the exit code should be 0
     And the JSON output should be valid
     And the JSON output should have "id" equal to "task1"
     And the JSON output should have "deleted" equal to "true"
 
   Scenario: Delete task in plain format
-    When I run "backlog delete task1 -f plain"
+    When I run "backlog delete task1 --yes -f plain"
     Then the exit code should be 0
     And stdout should contain "task1"
 
   Scenario: Delete task in id-only format
-    When I run "backlog delete task1 -f id-only"
+    When I run "backlog delete task1 --yes -f id-only"
     Then the exit code should be 0
     And stdout should contain "task1"
 
   Scenario: Delete task from todo status
-    When I run "backlog delete task2"
+    When I run "backlog delete task2 --yes"
     Then the exit code should be 0
     When I run "backlog show task2"
     Then the exit code should be 3
 
   Scenario: Delete task from in-progress status
-    When I run "backlog delete task3"
+    When I run "backlog delete task3 --yes"
     Then the exit code should be 0
     When I run "backlog show task3"
     Then the exit code should be 3
 
   Scenario: Remaining tasks are still accessible after delete
-    When I run "backlog delete task1"
+    When I run "backlog delete task1 --yes"
     Then the exit code should be 0
     When I run "backlog show task2"
     Then the exit code should be 0
     And stdout should contain "Another task"
+
+  Scenario: Soft delete hides the task from list and show
+    When I run "backlog delete task1 --soft"
+    Then the exit code should be 0
+    And stdout should contain "Deleted task1"
+    When I run "backlog list"
+    Then stdout should not contain "Task to delete"
+    When I run "backlog show task1"
+    Then the exit code should be 3
+
+  Scenario: List with --include-deleted shows soft-deleted tasks
+    When I run "backlog delete task1 --soft"
+    Then the exit code should be 0
+    When I run "backlog list --include-deleted -f json"
+    Then the exit code should be 0
+    And stdout should contain "Task to delete"
+    When I run "backlog list --include-deleted -s backlog -f json"
+    Then the JSON output should have "tasks[0].deleted_at" matching pattern "^[0-9]{4}-[0-9]{2}-[0-9]{2}T"
+
+  Scenario: Restore brings a soft-deleted task back to its status
+    When I run "backlog delete task3 --soft"
+    Then the exit code should be 0
+    When I run "backlog restore task3 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "id" equal to "task3"
+    And the JSON output should have "status" equal to "in-progress"
+    And the task "task3" should be in directory "in-progress"
+    When I run "backlog show task3 -f json"
+    Then the exit code should be 0
+    And stdout should not contain "deleted_at"
+
+  Scenario: Restore a task that was not soft-deleted returns exit code 3
+    When I run "backlog restore task2"
+    Then the exit code should be 3
+    And stderr should contain "not found"
+
+  Scenario: Hard-deleted tasks cannot be restored
+    When I run "backlog delete task1 --yes"
+    Then the exit code should be 0
+    When I run "backlog restore task1"
+    Then the exit code should be 3
+
+  Scenario: New tasks do not reuse the ID of a soft-deleted task
+    Given a backlog with the following tasks:
+      | id  | title    | status  |
+      | 007 | Old work | backlog |
+    When I run "backlog delete 007 --soft"
+    And I run "backlog add 'New work' -f json"
+    Then the exit code should be 0
+    And the JSON output should have "id" equal to "008"
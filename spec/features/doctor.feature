@@ -0,0 +1,152 @@
+Feature: Workspace Doctor
+  As a user of a local backlog that is edited by hand and by many agents
+  I want to check the workspace for integrity problems and repair the safe ones
+  So that tasks don't silently go missing or point at work that no longer exists
+
+  Background:
+    Given a fresh backlog directory
+    And a file ".backlog/todo/001-first.md" with the following content:
+      """
+      ---
+      id: "001"
+      title: First
+      blocks:
+          - "002"
+          - "099"
+      created: 2025-01-15T09:00:00Z
+      updated: 2025-01-15T09:00:00Z
+      ---
+      """
+    And a file ".backlog/todo/002-second.md" with the following content:
+      """
+      ---
+      id: "002"
+      title: Second
+      blocked_by:
+          - "001"
+      created: 2025-01-15T09:00:00Z
+      updated: 2025-01-15T09:00:00Z
+      ---
+      """
+
+  Scenario: Clean workspace passes
+    Given a file ".backlog/todo/001-first.md" with the following content:
+      """
+      ---
+      id: "001"
+      title: First
+      created: 2025-01-15T09:00:00Z
+      updated: 2025-01-15T09:00:00Z
+      ---
+      """
+    When I run "backlog doctor"
+    Then the exit code should be 0
+    And stdout should contain "No problems found."
+
+  Scenario: Doctor reports problems and fails
+    Given a file ".backlog/done/notes.md" with the following content:
+      """
+      ---
+      id: "003"
+      title: Misnamed
+      ---
+      """
+    And a file ".backlog/review/002-copy.md" with the following content:
+      """
+      ---
+      id: "002"
+      title: Copy
+      ---
+      """
+    And a file ".backlog/backlog/004-broken.md" with the following content:
+      """
+      ---
+      id: "004"
+      title: [unclosed
+      ---
+      """
+    And a file ".backlog/.locks/042.lock" with content "agent: ghost"
+    When I run "backlog doctor"
+    Then the exit code should be 1
+    And stdout should contain "[warning] dangling_relation: todo/001-first.md: blocks references missing task 099"
+    And stdout should contain "[error] filename_mismatch: done/notes.md"
+    And stdout should contain "rename the file to 003-misnamed.md"
+    And stdout should contain "[error] duplicate_id: todo/002-second.md"
+    And stdout should contain "[error] unparseable: backlog/004-broken.md"
+    And stdout should contain "[warning] orphaned_lock: .locks/042.lock"
+    And stderr should be empty
+
+  Scenario: Doctor JSON output lists findings structurally
+    When I run "backlog doctor -f json"
+    Then the exit code should be 1
+    And the JSON output should be valid
+    And the JSON output should have "ok" equal to "false"
+    And the JSON output should have array length "findings" equal to 1
+    And the JSON output should have "findings[0].check" equal to "dangling_relation"
+    And the JSON output should have "findings[0].severity" equal to "warning"
+    And the JSON output should have "findings[0].task_id" equal to "001"
+    And the JSON output should have "findings[0].path" equal to "todo/001-first.md"
+
+  Scenario: Fix prunes dangling relations and removes orphaned locks
+    Given a file ".backlog/.locks/042.lock" with content "agent: ghost"
+    When I run "backlog doctor --fix --yes"
+    Then the exit code should be 0
+    And stdout should contain "(fixed)"
+    And the file ".backlog/todo/001-first.md" should contain "002"
+    And the file ".backlog/todo/001-first.md" should not contain "099"
+    And no lock file should exist for task "042"
+    When I run "backlog doctor"
+    Then the exit code should be 0
+
+  Scenario: Fix without a terminal requires --yes
+    When I run "backlog doctor --fix"
+    Then the exit code should be 1
+    And stderr should contain "confirmation required, pass --yes"
+    And the file ".backlog/todo/001-first.md" should contain "099"
+
+  Scenario: Fix rewrites the frontmatter status to match the directory
+    Given a file ".backlog/todo/003-third.md" with the following content:
+      """
+      ---
+      id: "003"
+      title: Third
+      status: review
+      ---
+      """
+    When I run "backlog doctor --fix --yes"
+    Then the exit code should be 0
+    And the task "003" should be in directory "todo"
+    And the file ".backlog/todo/003-third.md" should contain "status: todo"
+
+  Scenario: Fix moves the file to match the frontmatter status
+    Given a file ".backlog/todo/003-third.md" with the following content:
+      """
+      ---
+      id: "003"
+      title: Third
+      status: review
+      ---
+      """
+    When I run "backlog doctor --fix --yes --status-source=frontmatter"
+    Then the exit code should be 0
+    And the task "003" should be in directory "review"
+    And the file ".backlog/review/003-third.md" should contain "status: review"
+
+  Scenario: Fix leaves problems that need a human decision
+    Given a file ".backlog/done/notes.md" with the following content:
+      """
+      ---
+      id: "003"
+      title: Misnamed
+      ---
+      """
+    When I run "backlog doctor --fix --yes -f json"
+    Then the exit code should be 1
+    And the JSON output should have "fixed" equal to "1"
+    And the JSON output should have "ok" equal to "false"
+    And the file ".backlog/done/notes.md" should exist
+
+  Scenario: Invalid status source is rejected
+    When I run "backlog doctor --status-source=git"
+    Then the exit code should be 1
+    And stderr should contain "invalid --status-source"
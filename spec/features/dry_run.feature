@@ -0,0 +1,108 @@
+Feature: Dry Run
+  As an agent using the backlog CLI
+  I want to preview what a command would change
+  So that I can check a mutation before making it
+
+  Background:
+    Given a backlog with the following tasks:
+      | id    | title          | status | priority | labels  |
+      | task1 | Existing task  | todo   | high     | feature |
+      | task2 | Other task     | todo   | low      |         |
+
+  Scenario: Dry-run add prints the task without creating it
+    When I run "backlog add 'Preview task' --priority urgent --label docs --dry-run -f json"
+    Then the exit code should be 0
+    And the JSON output should have "dry_run" equal to "true"
+    And the JSON output should have "action" equal to "add"
+    And the JSON output should have "task.title" equal to "Preview task"
+    And the JSON output should have "task.status" equal to "backlog"
+    And the JSON output should have "task.priority" equal to "urgent"
+    And stderr should contain "dry run: no changes were made"
+    When I run "backlog list -f json"
+    Then the JSON output should have array length "tasks" equal to 2
+
+  Scenario: Dry-run move reports the transition without moving the task
+    When I run "backlog move task1 in-progress --dry-run -f json"
+    Then the exit code should be 0
+    And the JSON output should have "action" equal to "move"
+    And the JSON output should have "from_status" equal to "todo"
+    And the JSON output should have "to_status" equal to "in-progress"
+    And the task "task1" should have status "todo"
+
+  Scenario: Dry-run move in table format summarizes the change
+    When I run "backlog move task1 done --dry-run"
+    Then the exit code should be 0
+    And stdout should contain "Would move task1: Existing task"
+    And stdout should contain "done"
+    And the task "task1" should have status "todo"
+
+  Scenario: Dry-run edit shows the resulting task without saving it
+    When I run "backlog edit task1 --title 'Renamed' --remove-label feature --dry-run -f json"
+    Then the exit code should be 0
+    And the JSON output should have "task.title" equal to "Renamed"
+    And the task "task1" should have label "feature"
+    When I run "backlog show task1 -f json"
+    Then the JSON output should have "title" equal to "Existing task"
+
+  Scenario: Dry-run claim does not claim the task
+    Given the environment variable "BACKLOG_AGENT_ID" is "test-agent"
+    When I run "backlog claim task1 --dry-run -f json"
+    Then the exit code should be 0
+    And the JSON output should have "to_status" equal to "in-progress"
+    And the JSON output should have array "task.labels" containing "agent:test-agent"
+    And the task "task1" should have status "todo"
+    And no lock file should exist for task "task1"
+
+  Scenario: Dry-run claim reports a conflict with another agent
+    Given task "task1" is claimed by agent "other-agent"
+    And the environment variable "BACKLOG_AGENT_ID" is "test-agent"
+    When I run "backlog claim task1 --dry-run"
+    Then the exit code should be 2
+
+  Scenario: Dry-run release does not release the task
+    Given task "task1" is claimed by agent "me"
+    When I run "backlog release task1 --agent-id me --dry-run -f json"
+    Then the exit code should be 0
+    And the JSON output should have "to_status" equal to "todo"
+    And the task "task1" should have label "agent:me"
+
+  Scenario: Dry-run delete keeps the task
+    When I run "backlog delete task1 --dry-run -f id-only"
+    Then the exit code should be 0
+    And stdout should be "task1"
+    And the task "task1" should have status "todo"
+
+  Scenario: Dry-run reorder reports the target position
+    When I run "backlog reorder task2 --before task1 --dry-run -f plain"
+    Then the exit code should be 0
+    And stdout should contain "reorder"
+    And stdout should contain "before task1"
+
+  Scenario: Dry-run on a missing task reports not found
+    When I run "backlog move nonexistent done --dry-run"
+    Then the exit code should be 3
+
+  Scenario: Dry-run makes no git commits
+    Given a git repository is initialized
+    And git_sync is enabled in the config
+    When I run "backlog move task1 in-progress --dry-run"
+    Then the exit code should be 0
+    And no new git commits should exist
+
+  Scenario: Read-only commands accept --dry-run
+    When I run "backlog label list --dry-run"
+    Then the exit code should be 0
+    When I run "backlog comment list task1 --dry-run"
+    Then the exit code should be 0
+    When I run "backlog schema task --dry-run"
+    Then the exit code should be 0
+
+  Scenario: Commands that cannot preview reject --dry-run
+    When I run "backlog comment task1 'Hello' --dry-run"
+    Then the exit code should be 1
+    And stderr should contain "--dry-run is not supported"
+
+  Scenario: next --claim rejects --dry-run
+    When I run "backlog next --claim --dry-run"
+    Then the exit code should be 1
+    And stderr should contain "--dry-run is not supported"
@@ -26,6 +26,49 @@ Feature: Editing Tasks
     Then the exit code should be 0
     And the task "task1" should have description containing "New description here"
 
+  Scenario: Edit task description from file survives later comments
+    Given a file "notes.md" with the following content:
+      """
+      Steps to reproduce:
+
+      ```
+      ## Comments
+      not a real comments section
+      ```
+      """
+    When I run "backlog edit task1 --description-file=notes.md"
+    And I run "backlog comment task1 'Looked into it'"
+    Then the exit code should be 0
+    And the task "task1" should have description containing:
+      """
+      ```
+      ## Comments
+      not a real comments section
+      ```
+      """
+    And the task "task1" should have comment containing "Looked into it"
+
+  Scenario: Edit task description from stdin
+    When I run "backlog edit task1 --description -" with input:
+      """
+      Rewritten description.
+
+      With a second paragraph.
+      """
+    Then the exit code should be 0
+    And the task "task1" should have description containing:
+      """
+      Rewritten description.
+
+      With a second paragraph.
+      """
+
+  Scenario: Edit rejects both inline description and description-file
+    Given a file "notes.md" with content "From a file"
+    When I run "backlog edit task1 --description='Inline' --description-file=notes.md"
+    Then the exit code should be 1
+    And stderr should contain "cannot be used together"
+
   Scenario: Add label to task
     When I run "backlog edit task1 --add-label=backend"
     Then the exit code should be 0
@@ -38,6 +81,25 @@ Feature: Editing Tasks
     And the task "task2" should have label "bug"
     And the task "task2" should not have label "critical"
 
+  Scenario: Add and remove several labels with repeated flags
+    When I run "backlog edit task2 --add-label=backend --add-label=api --remove-label=bug --remove-label=critical"
+    Then the exit code should be 0
+    And the task "task2" should have label "backend"
+    And the task "task2" should have label "api"
+    And the task "task2" should not have label "bug"
+    And the task "task2" should not have label "critical"
+
+  Scenario: Adding a label the task already has is a no-op
+    When I run "backlog edit task1 --add-label=feature -f json"
+    Then the exit code should be 0
+    And the JSON output array "labels" should have length 1
+    And the task "task1" should have label "feature"
+
+  Scenario: Removing a label the task doesn't have succeeds
+    When I run "backlog edit task1 --remove-label=nonexistent"
+    Then the exit code should be 0
+    And the task "task1" should have label "feature"
+
   Scenario: Edit multiple fields at once
     When I run "backlog edit task1 --title='Multi-edit task' --priority=high --add-label=urgent"
     Then the exit code should be 0
@@ -67,7 +129,108 @@ Feature: Editing Tasks
     And the JSON output should have "id" equal to "task1"
     And the JSON output should have "title" equal to "JSON edit"
 
+  Scenario: Edit shows what changed
+    When I run "backlog edit task1 --priority=high --add-label=infra"
+    Then the exit code should be 0
+    And stdout should contain "Updated task1"
+    And stdout should contain "priority: medium → high"
+    And stdout should contain "labels: +infra"
+    And stdout should not contain "title:"
+
+  Scenario: Edit reports before and after values in JSON
+    When I run "backlog edit task2 --priority=low --remove-label=bug -f json"
+    Then the exit code should be 0
+    And the JSON output should have "changes.priority.before" equal to "high"
+    And the JSON output should have "changes.priority.after" equal to "low"
+    And the JSON output should have "changes.labels" as an object
+    And the JSON output should not have array "changes.labels.after" containing "bug"
+
+  Scenario: Edit that changes nothing reports no changes and makes no commit
+    Given a git repository is initialized
+    And git_sync is enabled in the config
+    When I run "backlog edit task1 --priority=medium --add-label=feature"
+    Then the exit code should be 0
+    And stdout should contain "no changes"
+    And no new git commits should exist
+
   Scenario: Edit task with invalid priority fails
     When I run "backlog edit task1 --priority=invalid"
     Then the exit code should be 1
     And stderr should contain "invalid priority"
+
+  Scenario: Interactive edit applies changes made in the editor
+    Given a file "edit.sh" with the following content:
+      """
+      sed -i -e 's/^title: .*/title: Edited in editor/' -e 's/^priority: .*/priority: urgent/' -e 's/- bug$/- regression/' -e 's/Fix the login bug/Rewritten description/' "$1"
+      """
+    And the environment variable "VISUAL" is "sh edit.sh"
+    When I run "backlog edit task2 --interactive"
+    Then the exit code should be 0
+    And stderr should contain "Changes to task2"
+    And stderr should contain "priority: high -> urgent"
+    And stderr should contain "labels: +regression -bug"
+    And the task "task2" should have title "Edited in editor"
+    And the task "task2" should have priority "urgent"
+    And the task "task2" should have label "regression"
+    And the task "task2" should not have label "bug"
+    And the task "task2" should have label "critical"
+    And the task "task2" should have description containing "Rewritten description"
+
+  Scenario: Interactive edit shows the task in the task file format
+    Given a file "edit.sh" with the following content:
+      """
+      cp "$1" seen.md
+      """
+    And the environment variable "VISUAL" is "sh edit.sh"
+    When I run "backlog edit task2 -i"
+    Then the exit code should be 0
+    And the file "seen.md" should contain "title: Bug to fix"
+    And the file "seen.md" should contain "## Description"
+    And the file "seen.md" should contain "Fix the login bug"
+
+  Scenario: Interactive edit with an unchanged file makes no changes
+    Given the environment variable "VISUAL" is "true"
+    When I run "backlog edit task1 --interactive"
+    Then the exit code should be 0
+    And stderr should contain "no changes made"
+    And stdout should be empty
+    And the task "task1" should have title "Original title"
+
+  Scenario: Interactive edit with an empty file makes no changes
+    Given a file "edit.sh" with the following content:
+      """
+      : > "$1"
+      """
+    And the environment variable "VISUAL" is "sh edit.sh"
+    When I run "backlog edit task1 --interactive"
+    Then the exit code should be 0
+    And stderr should contain "no changes made"
+    And the task "task1" should have title "Original title"
+
+  Scenario: Interactive edit reopens the editor on a parse error without losing edits
+    Given a file "edit.sh" with the following content:
+      """
+      if grep -q '^# backlog: error:' "$1"; then
+        cp "$1" second-pass.md
+        sed -i 's/^priority: .*/priority: low/' "$1"
+      else
+        sed -i -e 's/^priority: .*/priority: [oops/' -e 's/^title: .*/title: Kept title/' "$1"
+      fi
+      """
+    And the environment variable "VISUAL" is "sh edit.sh"
+    When I run "backlog edit task1 --interactive"
+    Then the exit code should be 0
+    And the file "second-pass.md" should contain "# backlog: error:"
+    And the file "second-pass.md" should contain "title: Kept title"
+    And the task "task1" should have title "Kept title"
+    And the task "task1" should have priority "low"
+
+  Scenario: Interactive edit cannot be combined with field flags
+    When I run "backlog edit task1 --interactive --title=Other"
+    Then the exit code should be 1
+    And stderr should contain "cannot be combined"
+
+  Scenario: Interactive edit of a missing task
+    Given the environment variable "VISUAL" is "true"
+    When I run "backlog edit nope --interactive"
+    Then the exit code should be 3
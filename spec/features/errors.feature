@@ -52,6 +52,52 @@ Feature: Error Handling
     Then the exit code should be 4
     And stderr should contain "config"
 
+  Scenario: Unknown config key reports its location
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: .backlog
+          default: true
+          lock_mod: file
+      """
+    When I run "backlog list"
+    Then the exit code should be 4
+    And stderr should contain "config.yaml:7:5: unknown key"
+    And stderr should contain "lock_mod"
+
+  Scenario: Config value of the wrong type reports its location
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: .backlog
+          default: true
+          git_sync: sometimes
+      """
+    When I run "backlog list"
+    Then the exit code should be 4
+    And stderr should contain "config.yaml:7:15: workspaces.main.git_sync must be a boolean"
+
+  Scenario: Version runs with a broken config file
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          lock_mod: file
+      """
+    When I run "backlog version"
+    Then the exit code should be 0
+    And stdout should contain "backlog version"
+
   Scenario: Error message goes to stderr not stdout
     Given a fresh backlog directory
     When I run "backlog show nonexistent-task"
@@ -67,8 +113,28 @@ Feature: Error Handling
     And the JSON output should be valid
     And the JSON output should have "error" as an object
     And the JSON output should have "error.code" equal to "NOT_FOUND"
+    And the JSON output should have "error.error_code" equal to "NOT_FOUND"
     And the JSON output should have "error.message" containing "not found"
 
+  Scenario: JSON error for a claim conflict has a specific error code
+    Given a backlog with the following tasks:
+      | id    | title     | status      | priority | agent_id    |
+      | task1 | Test task | in-progress | medium   | other-agent |
+    When I run "backlog claim task1 --agent-id=my-agent -f json"
+    Then the exit code should be 2
+    And the JSON output should have "error.code" equal to "CONFLICT"
+    And the JSON output should have "error.error_code" equal to "CLAIM_CONFLICT"
+    And the JSON output should have "error.message" containing "already claimed"
+
+  Scenario: JSON error for a release conflict has a specific error code
+    Given a backlog with the following tasks:
+      | id    | title     | status | priority |
+      | task1 | Test task | todo   | medium   |
+    When I run "backlog release task1 --agent-id=my-agent -f json"
+    Then the exit code should be 2
+    And the JSON output should have "error.code" equal to "CONFLICT"
+    And the JSON output should have "error.error_code" equal to "RELEASE_CONFLICT"
+
   Scenario: JSON error format when --format=json for config error
     Given a fresh backlog directory
     And a config file with the following content:
@@ -99,6 +165,7 @@ Feature: Error Handling
     And the JSON output should be valid
     And the JSON output should have "error" as an object
     And the JSON output should have "error.code" equal to "INVALID_INPUT"
+    And the JSON output should have "error.error_code" equal to "INVALID_INPUT"
 
   # The following scenarios document expected behavior for remote backends.
   # They are marked with @remote tag to indicate they require remote backend testing.
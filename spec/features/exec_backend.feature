@@ -0,0 +1,184 @@
+Feature: Exec Backend
+  As a team using a tracker backlog has no built-in backend for
+  I want backlog to drive an external program that talks to it
+  So that agents can work against that tracker with the usual commands
+
+  The exec backend runs the workspace's command and speaks line-delimited
+  JSON with it. These scenarios use the reference adapter in
+  spec/cmd/execadapter, which keeps its tasks in a JSON file.
+
+  Scenario: List tasks from the external program
+    Given a fresh backlog directory
+    And an exec workspace using the reference adapter
+    And the reference adapter has the following tasks:
+      | id    | title           | status      | priority |
+      | EXT-1 | Fix login       | todo        | high     |
+      | EXT-2 | Write docs      | in-progress | low      |
+      | EXT-3 | Old release     | done        | none     |
+    When I run "backlog list -f json"
+    Then the exit code should be 0
+    And the JSON output should have "count" equal to "2"
+    And the JSON output should have "tasks[0].id" equal to "EXT-1"
+    And the JSON output should have "tasks[1].title" equal to "Write docs"
+
+  Scenario: Create, move, and show a task
+    Given a fresh backlog directory
+    And an exec workspace using the reference adapter
+    When I run "backlog add 'Ship the adapter' --priority high -f json"
+    Then the exit code should be 0
+    And the JSON output should have "id" equal to "EXT-1"
+    When I run "backlog move EXT-1 review"
+    Then the exit code should be 0
+    When I run "backlog show EXT-1 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "title" equal to "Ship the adapter"
+    And the JSON output should have "status" equal to "review"
+    And the JSON output should have "priority" equal to "high"
+
+  Scenario: A missing task is not found
+    Given a fresh backlog directory
+    And an exec workspace using the reference adapter
+    When I run "backlog show EXT-9 -f json"
+    Then the exit code should be 3
+    And the JSON output should have "error.error_code" equal to "NOT_FOUND"
+
+  Scenario: Claim and release when the program advertises claim
+    Given a fresh backlog directory
+    And an exec workspace using the reference adapter
+    And the reference adapter has the following tasks:
+      | id    | title     | status |
+      | EXT-1 | Fix login | todo   |
+    When I run "backlog claim EXT-1 --agent-id claude-1 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "status" equal to "in-progress"
+    And the JSON output should have "assignee" equal to "claude-1"
+    When I run "backlog release EXT-1 --agent-id claude-1"
+    Then the exit code should be 0
+    When I run "backlog show EXT-1 -f json"
+    Then the JSON output should have "status" equal to "todo"
+
+  Scenario: Claiming a task another agent holds is a conflict
+    Given a fresh backlog directory
+    And an exec workspace using the reference adapter
+    And the reference adapter has the following tasks:
+      | id    | title     | status      | assignee |
+      | EXT-1 | Fix login | in-progress | claude-2 |
+    When I run "backlog claim EXT-1 --agent-id claude-1 -f json"
+    Then the exit code should be 2
+    And the JSON output should have "error.error_code" equal to "CLAIM_CONFLICT"
+
+  Scenario: Claim is unavailable when the program doesn't advertise it
+    Given a fresh backlog directory
+    And an exec workspace using the reference adapter with capabilities "comments"
+    And the reference adapter has the following tasks:
+      | id    | title     | status |
+      | EXT-1 | Fix login | todo   |
+    When I run "backlog claim EXT-1 --agent-id claude-1 -f json"
+    Then the exit code should be 1
+    And the JSON output should have "error.error_code" equal to "UNSUPPORTED"
+    When I run "backlog capabilities -f json"
+    Then the exit code should be 0
+    And the JSON output should have "backend" equal to "exec"
+    And the JSON output should have "operations[0].name" equal to "claim"
+    And the JSON output should have "operations[0].supported" equal to "false"
+
+  Scenario: Capabilities come from the handshake
+    Given a fresh backlog directory
+    And an exec workspace using the reference adapter
+    When I run "backlog capabilities -f json"
+    Then the exit code should be 0
+    And the JSON output should have "operations[0].name" equal to "claim"
+    And the JSON output should have "operations[0].supported" equal to "true"
+
+  Scenario: Comments are unavailable when the program doesn't advertise them
+    Given a fresh backlog directory
+    And an exec workspace using the reference adapter with capabilities "claim"
+    And the reference adapter has the following tasks:
+      | id    | title     | status |
+      | EXT-1 | Fix login | todo   |
+    When I run "backlog comment EXT-1 'Looking into it' --agent-id claude-1 -f json"
+    Then the exit code should be 1
+    And the JSON output should have "error.error_code" equal to "UNSUPPORTED"
+
+  Scenario: A program that fails to start the handshake
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: tracker
+      workspaces:
+        tracker:
+          backend: exec
+          command: sh
+          args:
+            - -c
+            - echo "tracker token missing" >&2; exit 4
+          default: true
+      """
+    When I run "backlog list -f json"
+    Then the exit code should be 1
+    And the JSON output should have "error.error_code" equal to "HANDSHAKE_FAILED"
+    And the JSON output should have "error.message" containing "tracker token missing"
+
+  Scenario: A response that isn't JSON is a protocol error
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: tracker
+      workspaces:
+        tracker:
+          backend: exec
+          command: sh
+          args:
+            - -c
+            - read line; printf '%s\n' '{"protocol":1}'; read line; echo garbage
+          default: true
+      """
+    When I run "backlog list -f json"
+    Then the exit code should be 1
+    And the JSON output should have "error.error_code" equal to "PROTOCOL_ERROR"
+
+  Scenario: A program that stops responding times out
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: tracker
+      workspaces:
+        tracker:
+          backend: exec
+          command: sh
+          args:
+            - -c
+            - read line; printf '%s\n' '{"protocol":1}'; read line; sleep 10
+          timeout: 1s
+          default: true
+      """
+    When I run "backlog list -f json"
+    Then the exit code should be 1
+    And the JSON output should have "error.error_code" equal to "BACKEND_TIMEOUT"
+
+  Scenario: A program that exits mid-request
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: tracker
+      workspaces:
+        tracker:
+          backend: exec
+          command: sh
+          args:
+            - -c
+            - read line; printf '%s\n' '{"protocol":1}'; read line; echo "lost connection" >&2; exit 3
+          default: true
+      """
+    When I run "backlog list -f json"
+    Then the exit code should be 1
+    And the JSON output should have "error.error_code" equal to "BACKEND_EXITED"
+    And the JSON output should have "error.message" containing "exited with status 3"
@@ -0,0 +1,63 @@
+Feature: Task File Frontmatter
+  As a user who edits task files by hand
+  I want custom frontmatter to survive backlog commands and broken files to be reported
+  So that hand-added metadata is never lost and no task silently disappears
+
+  Background:
+    Given a fresh backlog directory
+    And a file ".backlog/todo/001-custom.md" with the following content:
+      """
+      ---
+      id: "001"
+      title: Custom fields
+      priority: high
+      sprint: 24
+      reviewers:
+          - alice
+          - bob
+      created: 2025-01-15T09:00:00Z
+      updated: 2025-01-15T09:00:00Z
+      ---
+
+      ## Description
+
+      Hand-edited task.
+      """
+
+  Scenario: Unknown frontmatter fields survive edit, move and claim
+    When I run "backlog edit 001 --title='Renamed task'"
+    And I run "backlog move 001 in-progress"
+    And I run "backlog claim 001 --agent-id=agent-1"
+    And I run "backlog show 001 --raw"
+    Then the exit code should be 0
+    And stdout should contain "title: Renamed task"
+    And stdout should contain "sprint: 24"
+    And stdout should contain "- alice"
+    And stdout should contain "- bob"
+
+  Scenario: Unknown frontmatter fields are exposed in task meta
+    When I run "backlog show 001 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "meta.extra.sprint" equal to "24"
+
+  Scenario: List warns about unparseable task files
+    Given a file ".backlog/todo/002-broken.md" with the following content:
+      """
+      ---
+      id: "002"
+      title: Broken
+      sort_order: first
+      ---
+      """
+    When I run "backlog list"
+    Then the exit code should be 0
+    And stdout should contain "Custom fields"
+    And stderr should contain "warning: skipped unparseable task file"
+    And stderr should contain "002-broken.md:4"
+
+  Scenario: List JSON output includes warnings for unparseable task files
+    Given a file ".backlog/todo/002-broken.md" with content "no frontmatter here"
+    When I run "backlog list -f json"
+    Then the exit code should be 0
+    And the JSON output should have "count" equal to "1"
+    And the JSON output should have "warnings" as an array
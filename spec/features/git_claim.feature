@@ -1,3 +1,4 @@
+@git-remote
 Feature: Git-Based Claims
   As an agent using the backlog CLI with git-based locking
   I want claims to be coordinated through git commits and pushes
@@ -36,6 +37,16 @@ Feature: Git-Based Claims
     Then the exit code should be 2
     And stderr should contain "conflict"
 
+  Scenario: Steal with lock_mode git replaces the other agent's label
+    Given the environment variable "BACKLOG_AGENT_ID" is "agent-a"
+    And another agent has claimed task "task1" and pushed while we were working
+    When I run "backlog claim task1 --steal"
+    Then the exit code should be 0
+    And the task "task1" should have label "agent:agent-a"
+    And the task "task1" should not have label "agent:other-agent"
+    And the task "task1" should have comment containing "claim stolen from other-agent by agent-a"
+    And the remote should have the latest commit
+
   Scenario: Release with lock_mode git commits and pushes
     Given task "task1" is claimed by agent "git-agent"
     And the environment variable "BACKLOG_AGENT_ID" is "git-agent"
@@ -66,3 +77,23 @@ Feature: Git-Based Claims
     When I run "backlog release task1"
     Then the exit code should be 1
     And stderr should contain "remote"
+
+  Scenario: Batch claim with lock_mode git makes a single commit and push
+    Given the environment variable "BACKLOG_AGENT_ID" is "git-agent"
+    When I run "backlog next --claim --count=2 -f json"
+    Then the exit code should be 0
+    And the JSON output array "" should have length 2
+    And the last git commit message should match pattern "^claim: task1, task2 \[agent:git-agent\]$"
+    And the remote should have the latest commit
+    And the task "task1" should have label "agent:git-agent"
+    And the task "task2" should have label "agent:git-agent"
+
+  Scenario: Batch claim with lock_mode git skips tasks claimed on the remote
+    Given the environment variable "BACKLOG_AGENT_ID" is "agent-a"
+    And another agent has claimed task "task1" and pushed while we were working
+    When I run "backlog next --claim --count=2 -f json"
+    Then the exit code should be 0
+    And the JSON output array "" should have length 1
+    And the JSON output should have "[0].id" equal to "task2"
+    And the last git commit message should match pattern "^claim: task2 \[agent:agent-a\]$"
+    And the remote should have the latest commit
@@ -16,11 +16,26 @@ Feature: Git Sync
     Then the exit code should be 0
     And a git commit should exist with message containing "move: task1"
 
+  Scenario: Move with assignee makes a single commit
+    When I run "backlog move task1 in-progress --assignee alice"
+    Then the exit code should be 0
+    And the task "task1" should have status "in-progress"
+    And the task "task1" should have assignee "alice"
+    And 1 new git commit should exist
+    And the last git commit message should match pattern "^move: task1"
+
   Scenario: Add task creates git commit
     When I run "backlog add 'New feature task' --priority=high"
     Then the exit code should be 0
     And a git commit should exist with message containing "add:"
 
+  Scenario: Add with --claim creates a single git commit with agent info
+    Given the environment variable "BACKLOG_AGENT_ID" is "test-agent"
+    When I run "backlog add 'Own task' --claim"
+    Then the exit code should be 0
+    And 1 new git commit should exist
+    And the last git commit message should match pattern "^add\+claim: \S+ \[agent:test-agent\]$"
+
   Scenario: Edit task creates git commit
     When I run "backlog edit task1 --priority=urgent"
     Then the exit code should be 0
@@ -45,17 +60,34 @@ Feature: Git Sync
     Then the exit code should be 0
     And a git commit should exist with message containing "comment: task1"
 
+  Scenario: Reopen creates a single git commit with agent info
+    Given the environment variable "BACKLOG_AGENT_ID" is "test-agent"
+    When I run "backlog move task1 done"
+    And I run "backlog reopen task1 --reason='Regression found'"
+    Then the exit code should be 0
+    And the last git commit message should match pattern "^reopen: task1 \[agent:test-agent\]$"
+    And the task "task1" should have comment containing "Reopened by test-agent: Regression found"
+
+  Scenario: Doctor fixes are recorded as a single git commit
+    When I run "backlog link task1 --blocks task2"
+    And I run "backlog delete task2 --yes"
+    And I run "backlog doctor --fix --yes"
+    Then the exit code should be 0
+    And the last git commit message should match pattern "^doctor: fixed 1 issue$"
+
   Scenario: Commit message format is correct
     When I run "backlog move task1 in-progress"
     Then the exit code should be 0
     And the last git commit message should match pattern "^(add|edit|move|claim|release|comment): .+"
 
+  @git-remote
   Scenario: Sync pulls and pushes
     Given a remote git repository
     When I run "backlog sync"
     Then the exit code should be 0
     And the local repository should be in sync with remote
 
+  @git-remote
   Scenario: Sync with --force overwrites local changes
     Given a remote git repository
     And the remote has different content than local
@@ -63,6 +95,112 @@ Feature: Git Sync
     Then the exit code should be 0
     And the local repository should match the remote
 
+  @git-remote
+  Scenario: Sync merges labels both sides added to a task
+    Given a remote git repository
+    And the remote adds label "remote-label" to task "task1"
+    And a local commit adds label "local-label" to task "task1"
+    When I run "backlog sync -f json"
+    Then the exit code should be 0
+    And the JSON output should have "resolved[0]" equal to "task1"
+    And the task "task1" should have label "remote-label"
+    And the task "task1" should have label "local-label"
+    And the task "task1" should have label "feature"
+    And a git commit should exist with message containing "sync: resolve conflicts in task1"
+    And the local repository should be in sync with remote
+
+  @git-remote
+  Scenario: Sync aborts on conflicting titles and reports the task and field
+    Given a remote git repository
+    And the remote changes the title of task "task1" to "Remote title"
+    And a local commit changes the title of task "task1" to "Local title"
+    When I run "backlog sync -f json"
+    Then the exit code should be 2
+    And the JSON output should have "error.error_code" equal to "SYNC_CONFLICT"
+    And the JSON output should have "error.details.tasks[0]" equal to "task1"
+    And the JSON output should have "error.details.conflicts[0].field" equal to "title"
+    And the JSON output should have "error.details.conflicts[0].theirs" equal to "Remote title"
+    And the task "task1" should have title "Local title"
+
+  @git-remote
+  Scenario: Sync with --strategy theirs keeps the remote side of conflicting fields
+    Given a remote git repository
+    And the remote changes the title of task "task1" to "Remote title"
+    And a local commit changes the title of task "task1" to "Local title"
+    When I run "backlog sync --strategy theirs"
+    Then the exit code should be 0
+    And stdout should contain "Resolved:  task1"
+    And the task "task1" should have title "Remote title"
+    And the last git commit message should match pattern "^sync: resolve conflicts in task1$"
+
+  Scenario: Sync rejects an unknown conflict strategy
+    When I run "backlog sync --strategy mine"
+    Then the exit code should be 1
+    And stderr should contain "invalid --strategy"
+    And stderr should contain "valid: ours, theirs, interactive"
+
+  Scenario: Sync status without a remote reports it instead of failing
+    When I run "backlog sync --status -f json"
+    Then the exit code should be 0
+    And the JSON output should have "message" equal to "no remote configured"
+    And the JSON output should have "in_sync" equal to "true"
+
+  Scenario: Sync status accepts --dry-run but sync does not
+    When I run "backlog sync --status --dry-run -f json"
+    Then the exit code should be 0
+    And the JSON output should have "message" equal to "no remote configured"
+    When I run "backlog sync --dry-run"
+    Then the exit code should be 1
+    And stderr should contain "--dry-run is not supported"
+
+  Scenario: Sync status without an upstream branch reports it instead of failing
+    Given a git repository with remote "https://example.invalid/backlog.git"
+    When I run "backlog sync --status"
+    Then the exit code should be 0
+    And stdout should contain "no upstream branch configured"
+
+  Scenario: Sync status reports the remote URL without an upstream branch
+    Given a git repository with remote "https://example.invalid/backlog.git"
+    When I run "backlog sync --status -f json"
+    Then the exit code should be 0
+    And the JSON output should have "remote" equal to "https://example.invalid/backlog.git"
+    And the JSON output should have "ahead" equal to "0"
+    And the JSON output should have "behind" equal to "0"
+
+  @git-remote
+  Scenario: Sync status when in sync exits 0
+    Given a remote git repository
+    When I run "backlog sync --status -f json"
+    Then the exit code should be 0
+    And the JSON output should have "in_sync" equal to "true"
+    And the JSON output should have "behind" equal to "0"
+    And the JSON output should have "dirty" equal to "false"
+
+  @git-remote
+  Scenario: Sync status reports uncommitted changes as dirty
+    Given a remote git repository
+    And a file "notes.txt" with content "not a task"
+    When I run "backlog sync --status -f json"
+    Then the exit code should be 0
+    And the JSON output should have "dirty" equal to "true"
+
+  @git-remote
+  Scenario: Sync status reports incoming task changes without pulling
+    Given a remote git repository
+    And another agent has claimed task "task1" and pushed while we were working
+    When I run "backlog sync --status -f json"
+    Then the exit code should be 2
+    And the JSON output should have "in_sync" equal to "false"
+    And the JSON output should have "behind" equal to "1"
+    And the JSON output should have array "updated" containing "task1"
+    And the task "task1" should have status "todo"
+
+  Scenario: Sync status cannot be combined with --force
+    When I run "backlog sync --status --force"
+    Then the exit code should be 1
+    And stderr should contain "--status cannot be combined with --force"
+
+  @git-remote
   Scenario: Failed push returns exit code 2
     Given a remote git repository
     And the remote has been updated by another agent
@@ -70,6 +208,43 @@ Feature: Git Sync
     Then the exit code should be 2
     And stderr should contain "conflict"
 
+  @git-remote
+  Scenario: Move with --force-sync pulls remote changes first
+    Given a remote git repository
+    And the remote has been updated by another agent
+    When I run "backlog move task1 in-progress --force-sync"
+    Then the exit code should be 0
+    And the task "task1" should have status "in-progress"
+    And the local repository should include the remote commit
+    And the remote should have the latest commit
+
+  @git-remote
+  Scenario: Move with --force-sync sees the remote version of the task
+    Given a remote git repository
+    And another agent has claimed task "task1" and pushed while we were working
+    When I run "backlog move task1 review --force-sync"
+    Then the exit code should be 0
+    And stdout should contain "in-progress → review"
+    And the task "task1" should have status "review"
+
+  @git-remote
+  Scenario: Move with --force-sync still refuses uncommitted changes
+    Given a remote git repository
+    And there are uncommitted changes in the repository
+    When I run "backlog move task1 in-progress --force-sync"
+    Then the exit code should be 1
+    And stderr should contain "uncommitted changes"
+
+  Scenario: Mutations in a detached HEAD fail with a clear error
+    Given the repository is in a detached HEAD state
+    When I run "backlog move task1 in-progress -f json"
+    Then the exit code should be 1
+    And the JSON output should have "error.error_code" equal to "DETACHED_HEAD"
+    And the task "task1" should have status "todo"
+    When I run "backlog sync"
+    Then the exit code should be 1
+    And stderr should contain "check out a branch"
+
   Scenario: No commit when git_sync is disabled
     Given git_sync is disabled in the config
     When I run "backlog move task1 in-progress"
@@ -81,3 +256,29 @@ Feature: Git Sync
     When I run "backlog move task1 in-progress"
     Then the exit code should be 1
     And stderr should contain "uncommitted changes"
+
+  Scenario: Uncommitted changes are reported with a stable JSON error code
+    Given there are uncommitted changes in the repository
+    When I run "backlog move task1 in-progress -f json"
+    Then the exit code should be 1
+    And the JSON output should have "error.code" equal to "ERROR"
+    And the JSON output should have "error.error_code" equal to "UNCOMMITTED_CHANGES"
+    And the JSON output should have "error.message" containing "uncommitted changes"
+
+  Scenario: Custom commit message keeps the action prefix
+    When I run "backlog move task1 in-progress --message 'Start the auth work'"
+    Then the exit code should be 0
+    And 1 new git commit should exist
+    And the last git commit message should match pattern "^move: Start the auth work$"
+
+  Scenario: Claim with a custom commit message keeps the agent
+    Given the environment variable "BACKLOG_AGENT_ID" is "test-agent"
+    When I run "backlog claim task1 --message 'Picking up auth'"
+    Then the exit code should be 0
+    And the last git commit message should match pattern "^claim: Picking up auth \[agent:test-agent\]$"
+
+  Scenario: Empty commit message is rejected
+    When I run "backlog move task1 in-progress --message ' '"
+    Then the exit code should be 1
+    And stderr should contain "--message cannot be empty"
+    And no new git commits should exist
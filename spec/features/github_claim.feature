@@ -70,6 +70,14 @@ Feature: GitHub Claims
     And the JSON output should be valid
     And the JSON output should have array "labels" containing "agent:custom-agent"
 
+  @github
+  Scenario: Add with --claim creates and claims an issue
+    Given the environment variable "BACKLOG_AGENT_ID" is "claude-1"
+    When I run "backlog add 'New issue' --claim -f json"
+    Then the exit code should be 0
+    And the JSON output should have "status" equal to "in-progress"
+    And the JSON output should have array "labels" containing "agent:claude-1"
+
   @github
   Scenario: Claim already-claimed issue by same agent is no-op
     Given the mock GitHub API has the following issues:
@@ -103,8 +111,23 @@ Feature: GitHub Claims
     And the JSON output should be valid
     And the JSON output should have "error" as an object
     And the JSON output should have "error.code" equal to "CONFLICT"
+    And the JSON output should have "error.error_code" equal to "CLAIM_CONFLICT"
     And the JSON output should have "error.message" containing "already claimed"
 
+  @github
+  Scenario: Claim backs off when another agent claims the issue concurrently
+    Given the mock GitHub API has the following issues:
+      | number | title        | state | labels | assignee | body          |
+      | 57     | Racing claim | open  | ready  |          | Two claimants |
+    And the environment variable "BACKLOG_AGENT_ID" is "my-agent"
+    And another client adds label "agent:other-agent" to GitHub issue "GH-57" during the next label change
+    When I run "backlog claim GH-57"
+    Then the exit code should be 2
+    And stderr should contain "already claimed by agent other-agent"
+    And the GitHub issue "GH-57" should have label "agent:other-agent"
+    And the GitHub issue "GH-57" should not have label "agent:my-agent"
+    And the GitHub issue "GH-57" should have label "ready"
+
   @github
   Scenario: Claim non-existent issue returns exit code 3
     When I run "backlog claim GH-9999"
@@ -169,6 +192,111 @@ Feature: GitHub Claims
     Then the exit code should be 2
     And stderr should contain "claimed by different agent"
 
+  @github
+  Scenario: Force release frees an issue claimed by a different agent
+    Given the mock GitHub API has the following issues:
+      | number | title          | state | labels                       | assignee   | body      |
+      | 65     | Stuck on agent | open  | in-progress,agent:dead-agent | other-user | Abandoned |
+    And the environment variable "BACKLOG_AGENT_ID" is "supervisor"
+    When I run "backlog release GH-65 --force --yes -f json"
+    Then the exit code should be 0
+    And the JSON output should have "status" equal to "todo"
+    And the JSON output should not have array "labels" containing "agent:dead-agent"
+    And the JSON output should have "assignee" equal to ""
+    When I run "backlog show GH-65 --comments"
+    Then stdout should contain "force-released from dead-agent by supervisor"
+
+  @github
+  Scenario: Claim assigns the login mapped to the agent
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: github
+      workspaces:
+        github:
+          backend: github
+          repo: test-owner/test-repo
+          api_key_env: GITHUB_TOKEN
+          agent_label_prefix: agent
+          assignee_map:
+            claude-1: claude-bot
+          default: true
+      """
+    And the environment variable "GITHUB_TOKEN" is "ghp_valid_test_token"
+    And a mock GitHub API server is running
+    And the mock GitHub API authenticated user is "api-user"
+    And the mock GitHub API has the following issues:
+      | number | title          | state | labels | assignee | body               |
+      | 66     | Unclaimed task | open  | ready  |          | Task to be claimed |
+    And the environment variable "BACKLOG_AGENT_ID" is "claude-1"
+    When I run "backlog claim GH-66 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "assignee" equal to "claude-bot"
+    And the JSON output should have array "labels" containing "agent:claude-1"
+    When I run "backlog release GH-66 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "assignee" equal to ""
+
+  @github
+  Scenario: Label-only claim leaves the assignee alone
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: github
+      workspaces:
+        github:
+          backend: github
+          repo: test-owner/test-repo
+          api_key_env: GITHUB_TOKEN
+          agent_label_prefix: agent
+          assign_on_claim: label-only
+          default: true
+      """
+    And the environment variable "GITHUB_TOKEN" is "ghp_valid_test_token"
+    And a mock GitHub API server is running
+    And the mock GitHub API authenticated user is "api-user"
+    And the mock GitHub API has the following issues:
+      | number | title       | state | labels | assignee | body               |
+      | 67     | Owned task  | open  | ready  | alice    | Task to be claimed |
+    And the environment variable "BACKLOG_AGENT_ID" is "claude-1"
+    When I run "backlog claim GH-67 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "assignee" equal to "alice"
+    And the JSON output should have array "labels" containing "agent:claude-1"
+    And the JSON output should have "status" equal to "in-progress"
+    When I run "backlog release GH-67 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "assignee" equal to "alice"
+    And the JSON output should not have array "labels" containing "agent:claude-1"
+
+  @github
+  Scenario: Invalid assign_on_claim is a configuration error
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: github
+      workspaces:
+        github:
+          backend: github
+          repo: test-owner/test-repo
+          api_key_env: GITHUB_TOKEN
+          agent_label_prefix: agent
+          assign_on_claim: sometimes
+          default: true
+      """
+    And the environment variable "GITHUB_TOKEN" is "ghp_valid_test_token"
+    And a mock GitHub API server is running
+    And the mock GitHub API authenticated user is "api-user"
+    When I run "backlog claim GH-1"
+    Then the exit code should be 4
+    And stderr should contain "assign_on_claim"
+
   @github
   Scenario: Release unclaimed issue fails
     Given the mock GitHub API has the following issues:
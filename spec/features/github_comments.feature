@@ -41,7 +41,7 @@ Feature: GitHub Comments
     When I run "backlog comment GH-101 'Progress update: 50% complete' -f json"
     Then the exit code should be 0
     And the JSON output should be valid
-    And the JSON output should have "id" equal to "GH-101"
+    And the JSON output should have "task_id" equal to "GH-101"
 
   @github
   Scenario: Comment on non-existent issue returns exit code 3
@@ -32,6 +32,22 @@ Feature: GitHub CRUD Operations
     And the JSON output should have "id" matching pattern "GH-[0-9]+"
     And the JSON output should have "url" containing "github.com"
 
+  @github
+  Scenario: Add with --if-absent returns an open issue with the same title
+    Given the mock GitHub API has the following issues:
+      | number | title          | state | labels | assignee | body |
+      | 5      | Write the docs | open  | ready  |          |      |
+    When I run "backlog add 'Write the docs' --if-absent -f json"
+    Then the exit code should be 0
+    And the JSON output should have "id" equal to "GH-5"
+    And the JSON output should have "created" equal to "false"
+
+  @github
+  Scenario: Add with a pinned ID is rejected
+    When I run "backlog add 'New feature request' --id feature-1"
+    Then the exit code should be 1
+    And stderr should contain "does not support pinned task IDs"
+
   @github
   Scenario: Add creates GitHub issue with priority and labels
     When I run "backlog add 'Urgent bug fix' --priority=urgent --label=bug --label=critical -f json"
@@ -49,6 +65,26 @@ Feature: GitHub CRUD Operations
     And the JSON output should be valid
     And the JSON output should have "title" equal to "Feature with details"
 
+  @github
+  Scenario: Add creates GitHub issue from a template
+    Given a file ".backlog/templates/bug.md" with the following content:
+      """
+      ---
+      title: "Bug: {{summary}}"
+      labels: [bug]
+      ---
+
+      Reported against {{version}}.
+      """
+    When I run "backlog add --template bug --set summary='Login fails' --set version=1.2 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "title" equal to "Bug: Login fails"
+    And the GitHub issue "GH-1" should have label "bug"
+    And the GitHub issue "GH-1" should have body containing:
+      """
+      Reported against 1.2.
+      """
+
   @github
   Scenario: Add creates GitHub issue with status sets label
     When I run "backlog add 'Ready for work' --status=todo -f json"
@@ -89,6 +125,13 @@ Feature: GitHub CRUD Operations
     Then the exit code should be 3
     And stderr should contain "not found"
 
+  @github
+  Scenario: Show non-existent issue reports NOT_FOUND in JSON
+    When I run "backlog show GH-9999 -f json"
+    Then the exit code should be 3
+    And the JSON output should have "error.error_code" equal to "NOT_FOUND"
+    And the JSON output should have "error.message" equal to "task not found: GH-9999"
+
   @github
   Scenario: Show with JSON error format for non-existent issue
     When I run "backlog show GH-9999 -f json"
@@ -108,6 +151,77 @@ Feature: GitHub CRUD Operations
     And the JSON output should have "id" equal to "GH-10"
     And the JSON output should have "title" equal to "Updated title"
 
+  @github
+  Scenario: Interactive edit changes assignee and labels of a GitHub issue
+    Given the mock GitHub API has the following issues:
+      | number | title       | state | labels    | assignee | body          |
+      | 12     | Needs owner | open  | ready,bug |          | Task to claim |
+    And a file "edit.sh" with the following content:
+      """
+      sed -i -e 's/^title: .*/title: Needs owner\nassignee: carol/' -e 's/- bug$/- docs/' "$1"
+      """
+    And the environment variable "VISUAL" is "sh edit.sh"
+    When I run "backlog edit GH-12 --interactive -f json"
+    Then the exit code should be 0
+    And the JSON output should be valid
+    And stderr should contain "assignee: (none) -> @carol"
+    And the GitHub issue "GH-12" should be assigned to "carol"
+    And the GitHub issue "GH-12" should have label "docs"
+    And the GitHub issue "GH-12" should not have label "bug"
+
+  @github
+  Scenario: Edit passes description-file through as the issue body
+    Given the mock GitHub API has the following issues:
+      | number | title        | state | labels | assignee | body           |
+      | 11     | Needs detail | open  | ready  |          | Task to update |
+    And a file "body.md" with the following content:
+      """
+      Details:
+
+      ```go
+      fmt.Println("hi")
+      ```
+      """
+    When I run "backlog edit GH-11 --description-file=body.md"
+    Then the exit code should be 0
+    And the GitHub issue "GH-11" should have body containing:
+      """
+      ```go
+      fmt.Println("hi")
+      ```
+      """
+
+  @github
+  Scenario: Add with --parent records the parent in the issue body
+    Given the mock GitHub API has the following issues:
+      | number | title         | state | labels | assignee | body       |
+      | 50     | Payments epic | open  | ready  |          | Epic scope |
+    When I run "backlog add 'Card form' --description='Collect card details' --parent GH-50"
+    Then the exit code should be 0
+    When I run "backlog list --parent GH-50 -f json"
+    Then the JSON output should have array length "tasks" equal to 1
+    And the JSON output should have "tasks[0].title" equal to "Card form"
+    And the JSON output should have "tasks[0].parent" equal to "GH-50"
+    And the JSON output should have "tasks[0].description" equal to "Collect card details"
+
+  @github
+  Scenario: Edit --parent keeps the existing description
+    Given the mock GitHub API has the following issues:
+      | number | title         | state | labels | assignee | body           |
+      | 50     | Payments epic | open  | ready  |          | Epic scope     |
+      | 51     | Card form     | open  | ready  |          | Collect cards  |
+    When I run "backlog edit GH-51 --parent GH-50"
+    Then the exit code should be 0
+    And the GitHub issue "GH-51" should have body containing:
+      """
+      Collect cards
+
+      parent: #50
+      """
+    When I run "backlog show GH-51 -f json"
+    Then the JSON output should have "parent" equal to "GH-50"
+    And the JSON output should have "description" equal to "Collect cards"
+
   @github
   Scenario: Edit updates issue priority
     Given the mock GitHub API has the following issues:
@@ -118,6 +232,56 @@ Feature: GitHub CRUD Operations
     And the JSON output should be valid
     And the JSON output should have "priority" equal to "urgent"
 
+  @github
+  Scenario: Priority round-trips through a priority label
+    When I run "backlog add 'Fix checkout' --priority=high -f json"
+    Then the exit code should be 0
+    And the GitHub issue "GH-1" should have label "priority:high"
+    And the GitHub repository label "priority:high" should have color "d93f0b"
+    When I run "backlog show GH-1 -f json"
+    Then the JSON output should have "priority" equal to "high"
+    And the JSON output should not have array "labels" containing "priority:high"
+    When I run "backlog list --priority=high -f json"
+    Then the JSON output should have array length "tasks" equal to 1
+    And the JSON output should have "tasks[0].id" equal to "GH-1"
+
+  @github
+  Scenario: Changing priority replaces the old priority label
+    Given the mock GitHub API has the following issues:
+      | number | title        | state | labels                 | assignee | body      |
+      | 13     | Reprioritize | open  | ready,bug,priority:low |          | Some task |
+    When I run "backlog edit GH-13 --priority=urgent --add-label=backend -f json"
+    Then the exit code should be 0
+    And the JSON output should have "priority" equal to "urgent"
+    And the GitHub issue "GH-13" should have label "priority:urgent"
+    And the GitHub issue "GH-13" should not have label "priority:low"
+    And the GitHub issue "GH-13" should have label "bug"
+    And the GitHub issue "GH-13" should have label "backend"
+    When I run "backlog edit GH-13 --priority=none -f json"
+    Then the exit code should be 0
+    And the JSON output should have "priority" equal to "none"
+    And the GitHub issue "GH-13" should not have label "priority:urgent"
+
+  @github
+  Scenario: Priority labels are matched ignoring case
+    Given the mock GitHub API has the following issues:
+      | number | title      | state | labels              | assignee | body      |
+      | 14     | Shouty     | open  | ready,Priority:HIGH |          | Some task |
+    When I run "backlog list --priority=high -f json"
+    Then the JSON output should have array length "tasks" equal to 1
+    When I run "backlog edit GH-14 --priority=low -f json"
+    Then the exit code should be 0
+    And the GitHub issue "GH-14" should have label "priority:low"
+    And the GitHub issue "GH-14" should not have label "Priority:HIGH"
+
+  @github
+  Scenario: Existing priority labels in the repository are left as they are
+    Given the GitHub repository has label "priority:medium" with color "ededed"
+    When I run "backlog add 'Tidy up' --priority=medium -f json"
+    Then the exit code should be 0
+    And the GitHub issue "GH-1" should have label "priority:medium"
+    And the GitHub repository label "priority:medium" should have color "ededed"
+
   @github
   Scenario: Edit adds label to issue
     Given the mock GitHub API has the following issues:
@@ -159,6 +323,17 @@ Feature: GitHub CRUD Operations
     And the JSON output should have array "labels" containing "in-progress"
     And the JSON output should not have array "labels" containing "ready"
 
+  @github
+  Scenario: Move with assignee assigns the GitHub issue
+    Given the mock GitHub API has the following issues:
+      | number | title           | state | labels | assignee | body          |
+      | 21     | Task to pick up | open  | ready  |          | Move this one |
+    When I run "backlog move GH-21 in-progress --assignee alice -f json"
+    Then the exit code should be 0
+    And the JSON output should have "status" equal to "in-progress"
+    And the GitHub issue "GH-21" should be assigned to "alice"
+    And the GitHub issue "GH-21" should have label "in-progress"
+
   @github
   Scenario: Move to review status
     Given the mock GitHub API has the following issues:
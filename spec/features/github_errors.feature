@@ -0,0 +1,66 @@
+Feature: GitHub API Error Handling
+  As a user of the backlog CLI
+  I want GitHub API failures to be handled predictably
+  So that transient outages are retried and persistent ones fail cleanly
+
+  # Note: These scenarios inject failures into the mock GitHub API server.
+  # Read-only (idempotent) requests are retried on 5xx responses; writes are not.
+
+  Background:
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: github
+      workspaces:
+        github:
+          backend: github
+          repo: test-owner/test-repo
+          api_key_env: GITHUB_TOKEN
+          timeout: 1s
+          default: true
+      """
+    And the environment variable "GITHUB_TOKEN" is "ghp_valid_test_token"
+    And a mock GitHub API server is running
+    And the mock GitHub API has the following issues:
+      | number | title          | state | labels | assignee | body |
+      | 1      | Existing issue | open  | ready  |          |      |
+
+  @github
+  Scenario: Transient server errors on reads are retried
+    Given the mock GitHub API fails the first 2 requests
+    When I run "backlog show GH-1 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "title" equal to "Existing issue"
+    And the mock GitHub API should have received 3 requests
+
+  @github
+  Scenario: Persistent server errors on reads fail after bounded retries
+    Given the mock GitHub API returns 503 for GET /repos/*/issues/1
+    When I run "backlog show GH-1"
+    Then the exit code should be 1
+    And stderr should contain "503"
+    And the mock GitHub API should have received 3 requests
+
+  @github
+  Scenario: Server errors on writes are not retried
+    Given the mock GitHub API returns 500 for POST /repos/*/issues
+    When I run "backlog add 'New task'"
+    Then the exit code should be 1
+    And stderr should contain "github API error"
+    And stderr should contain "500"
+    And the mock GitHub API should have received 1 request
+
+  @github
+  Scenario: Malformed JSON response returns an error
+    Given the mock GitHub API returns malformed JSON for GET /repos/*/issues/1
+    When I run "backlog show GH-1"
+    Then the exit code should be 1
+
+  @github
+  Scenario: Slow responses are bounded by the client timeout
+    Given the mock GitHub API times out after 5 seconds
+    When I run "backlog list"
+    Then the exit code should be 1
+    And stderr should contain "Timeout"
@@ -89,6 +89,20 @@ Feature: GitHub List
     And the JSON output should have "tasks[1].title" equal to "Alice's other task"
     And the JSON output should have "tasks[1].assignee" equal to "alice"
 
+  @github
+  Scenario: List filters by the authenticated user with @me
+    Given the mock GitHub API has the following issues:
+      | number | title           | state | labels | assignee |
+      | 1      | My task         | open  | ready  | api-user |
+      | 2      | Bob's task      | open  | ready  | bob      |
+      | 3      | Unassigned task | open  | ready  |          |
+    And the mock GitHub API authenticated user is "api-user"
+    And the environment variable "BACKLOG_AGENT_ID" is "claude-1"
+    When I run "backlog list --assignee=@me -f json"
+    Then the exit code should be 0
+    And the JSON output should have "count" equal to "1"
+    And the JSON output should have "tasks[0].title" equal to "My task"
+
   @github
   Scenario: List respects limit
     Given the mock GitHub API has the following issues:
@@ -106,3 +120,58 @@ Feature: GitHub List
     And the JSON output should have "tasks[0].title" equal to "First issue"
     And the JSON output should have "tasks[1].title" equal to "Second issue"
     And the JSON output should have "tasks[2].title" equal to "Third issue"
+
+  @github
+  Scenario: Show hydrates body, labels, assignee, and priority
+    Given the mock GitHub API has the following issues:
+      | number | title             | state | labels                    | assignee | body                |
+      | 42     | Implement feature | open  | in-progress,priority:high | alice    | Feature description |
+    When I run "backlog show GH-42 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "id" equal to "GH-42"
+    And the JSON output should have "description" equal to "Feature description"
+    And the JSON output should have "status" equal to "in-progress"
+    And the JSON output should have "priority" equal to "high"
+    And the JSON output should have "assignee" equal to "alice"
+    And the JSON output should have array "labels" containing "in-progress"
+
+  @github
+  Scenario: Priority labels follow a configured prefix
+    Given a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: github
+      workspaces:
+        github:
+          backend: github
+          repo: test-owner/test-repo
+          api_key_env: GITHUB_TOKEN
+          priority_label_prefix: "prio/"
+          default: true
+      """
+    And the mock GitHub API has the following issues:
+      | number | title     | state | labels              |
+      | 1      | Hot fix   | open  | ready,prio/urgent   |
+      | 2      | Old style | open  | ready,priority:high |
+    When I run "backlog list --priority=urgent -f json"
+    Then the exit code should be 0
+    And the JSON output should have array length "tasks" equal to 1
+    And the JSON output should have "tasks[0].id" equal to "GH-1"
+    When I run "backlog add 'New task' --priority=low -f json"
+    Then the exit code should be 0
+    And the GitHub issue "GH-3" should have label "prio/low"
+    And the GitHub repository label "prio/low" should have color "0e8a16"
+
+  @github
+  Scenario: Verbose logging keeps stdout parseable
+    Given the mock GitHub API has the following issues:
+      | number | title       | state | labels |
+      | 1      | First issue | open  | ready  |
+    When I run "backlog list -vv -f json"
+    Then the exit code should be 0
+    And the JSON output should be valid
+    And the JSON output should have "tasks[0].title" equal to "First issue"
+    And stderr should contain "method=GET path=/api/v3/repos/test-owner/test-repo/issues status=200"
+    And stderr should contain "http response"
+    And stderr should not contain "ghp_valid_test_token"
@@ -19,13 +19,47 @@ Feature: Global Flags
     Then the exit code should be 0
     And stdout should be empty
 
-  Scenario: Verbose flag shows debug information
+  Scenario: Quiet flag silences change summaries
+    Given a backlog with the following tasks:
+      | id    | title           | status | priority |
+      | task1 | First task      | todo   | high     |
+    When I run "backlog move task1 in-progress -q"
+    Then the exit code should be 0
+    And stdout should be empty
+    And the task "task1" should have status "in-progress"
+    When I run "backlog edit task1 --priority=low --quiet"
+    Then the exit code should be 0
+    And stdout should be empty
+
+  Scenario: Quiet flag keeps requested data and JSON output
+    Given a backlog with the following tasks:
+      | id    | title           | status | priority |
+      | task1 | First task      | todo   | high     |
+    When I run "backlog list -q"
+    Then the exit code should be 0
+    And stdout should contain "First task"
+    When I run "backlog add 'New task' -q -f json"
+    Then the exit code should be 0
+    And the JSON output should have "title" equal to "New task"
+
+  Scenario: Quiet flag still reports errors on stderr
+    Given a backlog with the following tasks:
+      | id    | title           | status | priority |
+      | task1 | First task      | todo   | high     |
+    When I run "backlog move nonexistent done -q"
+    Then the exit code should be 3
+    And stdout should be empty
+    And stderr should contain "not found"
+
+  Scenario: Verbose flag logs progress to stderr
     Given a backlog with the following tasks:
       | id    | title           | status | priority |
       | task1 | First task      | todo   | high     |
     When I run "backlog list -v"
     Then the exit code should be 0
-    And stderr should contain "debug"
+    And stderr should contain "level=INFO"
+    And stderr should contain "listing tasks"
+    And stderr should not contain "level=DEBUG"
 
   Scenario: Verbose flag with long form
     Given a backlog with the following tasks:
@@ -33,7 +67,51 @@ Feature: Global Flags
       | task1 | First task      | todo   | high     |
     When I run "backlog list --verbose"
     Then the exit code should be 0
-    And stderr should contain "debug"
+    And stderr should contain "listing tasks"
+
+  Scenario: Repeated verbose flag enables debug logs
+    Given a backlog with the following tasks:
+      | id    | title           | status | priority |
+      | task1 | First task      | todo   | high     |
+    When I run "backlog list -vv"
+    Then the exit code should be 0
+    And stderr should contain "level=DEBUG"
+    And stderr should contain "evaluated list filters"
+    And stderr should contain "matched=1"
+
+  Scenario: BACKLOG_LOG sets the log level
+    Given a backlog with the following tasks:
+      | id    | title           | status | priority |
+      | task1 | First task      | todo   | high     |
+    When I run "backlog list" with env "BACKLOG_LOG=debug"
+    Then the exit code should be 0
+    And stderr should contain "evaluated list filters"
+
+  Scenario: Invalid BACKLOG_LOG value is rejected
+    Given a backlog with the following tasks:
+      | id    | title           | status | priority |
+      | task1 | First task      | todo   | high     |
+    When I run "backlog list" with env "BACKLOG_LOG=chatty"
+    Then the exit code should be 1
+    And stderr should contain "invalid log level"
+
+  Scenario: Log records are JSON with --log-format json
+    Given a backlog with the following tasks:
+      | id    | title           | status | priority |
+      | task1 | First task      | todo   | high     |
+    When I run "backlog list -v --log-format json -f json"
+    Then the exit code should be 0
+    And the JSON output should have "tasks[0].id" equal to "task1"
+    And stderr should contain "listing tasks"
+    And stderr should not contain "level=INFO"
+
+  Scenario: No log records without verbose
+    Given a backlog with the following tasks:
+      | id    | title           | status | priority |
+      | task1 | First task      | todo   | high     |
+    When I run "backlog list"
+    Then the exit code should be 0
+    And stderr should be empty
 
   Scenario: Format flag changes output format
     Given a backlog with the following tasks:
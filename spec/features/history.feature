@@ -0,0 +1,72 @@
+Feature: Task History
+  As a user of the backlog CLI
+  I want to see how a task changed over time
+  So that I can follow its lifecycle without digging through git
+
+  Scenario: History lists the commits for a task, oldest first
+    Given a git repository is initialized
+    And a backlog with the following tasks:
+      | id    | title        | status | priority |
+      | task1 | Seed task    | todo   | low      |
+    And git_sync is enabled in the config
+    And the environment variable "BACKLOG_AGENT_ID" is "test-agent"
+    When I run "backlog add 'Write the docs'"
+    And I run "backlog move 001 todo"
+    And I run "backlog claim 001"
+    And I run "backlog history 001 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "task_id" equal to "001"
+    And the JSON output should have array length "entries" equal to 3
+    And the JSON output should have "entries[0].action" equal to "add"
+    And the JSON output should have "entries[0].status" equal to "backlog"
+    And the JSON output should have "entries[1].action" equal to "move"
+    And the JSON output should have "entries[1].status" equal to "todo"
+    And the JSON output should have "entries[2].action" equal to "claim"
+    And the JSON output should have "entries[2].agent" equal to "test-agent"
+    And the JSON output should have "entries[2].status" equal to "in-progress"
+
+  Scenario: History follows the task file across renames
+    Given a git repository is initialized
+    And a backlog with the following tasks:
+      | id    | title        | status | priority |
+      | task1 | Seed task    | todo   | low      |
+    And git_sync is enabled in the config
+    When I run "backlog add 'Original title'"
+    And I run "backlog edit 001 --title 'Better title'"
+    And I run "backlog move 001 done"
+    And I run "backlog history 001"
+    Then the exit code should be 0
+    And stdout should contain "History of 001"
+    And stdout should match pattern "add\s+backlog"
+    And stdout should match pattern "edit\s+backlog"
+    And stdout should match pattern "move\s+done"
+
+  Scenario: History includes patches with --diff
+    Given a git repository is initialized
+    And a backlog with the following tasks:
+      | id    | title        | status | priority |
+      | task1 | Seed task    | todo   | low      |
+    And git_sync is enabled in the config
+    When I run "backlog add 'Patch me'"
+    And I run "backlog edit 001 --priority high"
+    And I run "backlog history 001 --diff -f json"
+    Then the exit code should be 0
+    And the JSON output should have "entries[1].diff" containing "+priority: high"
+
+  Scenario: History falls back to timestamps without git_sync
+    Given a backlog with the following tasks:
+      | id    | title        | status | priority |
+      | task1 | Seed task    | todo   | low      |
+    When I run "backlog history task1 -f json"
+    Then the exit code should be 0
+    And the JSON output should have array length "entries" equal to 1
+    And the JSON output should have "entries[0].action" equal to "add"
+    And the JSON output should have "entries[0].status" equal to "todo"
+    And the JSON output should have "note" containing "git_sync is disabled"
+
+  Scenario: History of a missing task fails
+    Given a backlog with the following tasks:
+      | id    | title        | status | priority |
+      | task1 | Seed task    | todo   | low      |
+    When I run "backlog history nope"
+    Then the exit code should be 3
@@ -0,0 +1,254 @@
+Feature: Lifecycle Hooks
+  As a team using the backlog CLI
+  I want commands to run when tasks are created, moved, or claimed
+  So that I can notify people and trigger automation
+
+  # Each hook is run through the shell with the event as JSON on stdin.
+  # The record-event.sh script writes that JSON to a file the scenarios check.
+
+  Background:
+    Given a backlog with the following tasks:
+      | id    | title         | status | priority |
+      | task1 | Existing task | todo   | high     |
+    And a file "record-event.sh" with the following content:
+      """
+      cat > "event-$BACKLOG_EVENT.json"
+      echo "$BACKLOG_EVENT $BACKLOG_TASK_ID $BACKLOG_PREVIOUS_STATUS $BACKLOG_AGENT_ID" > "env-$BACKLOG_EVENT.txt"
+      """
+
+  Scenario: on_move hook receives the event on stdin
+    Given a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: ./.backlog
+          default: true
+          hooks:
+            on_move: ["sh ./record-event.sh"]
+      """
+    When I run "backlog move task1 review --agent-id reviewer"
+    Then the exit code should be 0
+    And the JSON file "event-move.json" should have "event" equal to "move"
+    And the JSON file "event-move.json" should have "previous_status" equal to "todo"
+    And the JSON file "event-move.json" should have "task.status" equal to "review"
+    And the JSON file "event-move.json" should have "workspace" equal to "main"
+    And the file "env-move.txt" should contain "move task1 todo reviewer"
+
+  Scenario: on_create hook receives the new task
+    Given a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: ./.backlog
+          default: true
+          hooks:
+            on_create: ["sh ./record-event.sh"]
+      """
+    When I run "backlog add 'Write release notes' --priority medium"
+    Then the exit code should be 0
+    And the JSON file "event-create.json" should have "task.title" equal to "Write release notes"
+    And the file "env-create.txt" should contain "create 001"
+
+  Scenario: on_claim hook receives the agent and previous status
+    Given a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: ./.backlog
+          default: true
+          hooks:
+            on_claim: ["sh ./record-event.sh"]
+      """
+    And the environment variable "BACKLOG_AGENT_ID" is "agent-1"
+    When I run "backlog claim task1"
+    Then the exit code should be 0
+    And the JSON file "event-claim.json" should have "agent" equal to "agent-1"
+    And the JSON file "event-claim.json" should have "previous_status" equal to "todo"
+    And the file "env-claim.txt" should contain "claim task1 todo agent-1"
+
+  Scenario: Hooks only run for their own event
+    Given a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: ./.backlog
+          default: true
+          hooks:
+            on_claim: ["sh ./record-event.sh"]
+      """
+    When I run "backlog move task1 review"
+    Then the exit code should be 0
+    And the file "event-move.json" should not exist
+
+  Scenario: A failing hook is a warning by default
+    Given a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: ./.backlog
+          default: true
+          hooks:
+            on_move: ["echo notify failed >&2; exit 1", "sh ./record-event.sh"]
+      """
+    When I run "backlog move task1 review"
+    Then the exit code should be 0
+    And stderr should contain "warning: on_move hook"
+    And stderr should contain "notify failed"
+    And the task "task1" should have status "review"
+    And the file "event-move.json" should exist
+
+  Scenario: A failing hook fails the command in strict mode
+    Given a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: ./.backlog
+          default: true
+          hooks:
+            strict: true
+            on_move: ["exit 3", "sh ./record-event.sh"]
+      """
+    When I run "backlog move task1 review -f json"
+    Then the exit code should be 1
+    And the JSON output should have "error.details.hook" equal to "exit 3"
+    And the task "task1" should have status "review"
+    And the file "event-move.json" should not exist
+
+  Scenario: A hook that runs too long is stopped
+    Given a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: ./.backlog
+          default: true
+          hooks:
+            timeout: 200ms
+            on_move: ["sleep 5"]
+      """
+    When I run "backlog move task1 review"
+    Then the exit code should be 0
+    And stderr should contain "timed out after 200ms"
+
+  Scenario: on_transition hooks run for matching moves only
+    Given a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: ./.backlog
+          default: true
+          hooks:
+            on_transition:
+              todo->review: ["echo \"$BACKLOG_TASK_ID $BACKLOG_FROM $BACKLOG_TO\" > review.txt"]
+              "*->done": ["echo done > done.txt"]
+      """
+    When I run "backlog move task1 in-progress"
+    Then the exit code should be 0
+    And the file "review.txt" should not exist
+    When I run "backlog move task1 todo"
+    And I run "backlog move task1 review"
+    Then the exit code should be 0
+    And the file "review.txt" should contain "task1 todo review"
+    And the file "done.txt" should not exist
+    When I run "backlog move task1 done"
+    Then the exit code should be 0
+    And the file "done.txt" should contain "done"
+
+  Scenario: A failing on_transition hook fails the command in strict mode
+    Given a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: ./.backlog
+          default: true
+          hooks:
+            strict: true
+            on_transition:
+              todo->review: ["exit 3"]
+      """
+    When I run "backlog move task1 review"
+    Then the exit code should be 1
+    And stderr should contain "todo->review"
+    And the task "task1" should have status "review"
+
+  Scenario: An on_transition key with an unknown status is a configuration error
+    Given a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: ./.backlog
+          default: true
+          hooks:
+            on_transition:
+              todo->shipped: ["echo shipped"]
+      """
+    When I run "backlog move task1 review"
+    Then the exit code should be 4
+    And stderr should contain "shipped"
+    And the task "task1" should have status "todo"
+
+  Scenario: Hooks do not run in dry-run mode
+    Given a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: ./.backlog
+          default: true
+          hooks:
+            on_move: ["sh ./record-event.sh"]
+      """
+    When I run "backlog move task1 review --dry-run"
+    Then the exit code should be 0
+    And the file "event-move.json" should not exist
+
+  @github
+  Scenario: Hooks run for GitHub workspaces
+    Given a fresh backlog directory
+    And a file "record-event.sh" with the following content:
+      """
+      cat > "event-$BACKLOG_EVENT.json"
+      """
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: github
+      workspaces:
+        github:
+          backend: github
+          repo: test-owner/test-repo
+          api_key_env: GITHUB_TOKEN
+          default: true
+          hooks:
+            on_move: ["sh ./record-event.sh"]
+      """
+    And the environment variable "GITHUB_TOKEN" is "ghp_valid_test_token"
+    And a mock GitHub API server is running
+    And the mock GitHub API has the following issues:
+      | number | title          | state | labels |
+      | 1      | Remote task    | open  | ready  |
+    When I run "backlog move GH-1 review"
+    Then the exit code should be 0
+    And the JSON file "event-move.json" should have "task.id" equal to "GH-1"
+    And the JSON file "event-move.json" should have "workspace" equal to "github"
@@ -0,0 +1,77 @@
+Feature: Local Task Index
+  As a user of a large local backlog
+  I want parsed task files cached in an index
+  So that listing stays fast without missing edits made outside the CLI
+
+  Scenario: Listing writes the index
+    Given a backlog with the following tasks:
+      | id  | title       | status | priority |
+      | 001 | First task  | todo   | high     |
+      | 002 | Second task | todo   | low      |
+    When I run "backlog list"
+    Then the exit code should be 0
+    And the file ".backlog/.index.json" should exist
+    And the JSON file ".backlog/.index.json" should have "version" equal to "1"
+
+  Scenario: Reindex rebuilds the index from every task file
+    Given a backlog with the following tasks:
+      | id  | title       | status | priority |
+      | 001 | First task  | todo   | high     |
+      | 002 | Second task | done   | low      |
+    When I run "backlog reindex -f json"
+    Then the exit code should be 0
+    And the JSON output should have "tasks" equal to "2"
+    And the file ".backlog/.index.json" should exist
+
+  Scenario: Hand edits are picked up after the index is written
+    Given a backlog with the following tasks:
+      | id  | title      | status | priority |
+      | 001 | First task | todo   | high     |
+    When I run "backlog list"
+    Then the exit code should be 0
+    Given a file ".backlog/todo/001-first-task.md" with the following content:
+      """
+      ---
+      id: "001"
+      title: Edited by hand
+      priority: high
+      created: 2025-01-15T09:00:00Z
+      updated: 2025-01-15T09:00:00Z
+      ---
+      """
+    When I run "backlog list"
+    Then the exit code should be 0
+    And stdout should contain "Edited by hand"
+    And stdout should not contain "First task"
+
+  Scenario: A corrupt index is rebuilt
+    Given a backlog with the following tasks:
+      | id  | title      | status | priority |
+      | 001 | First task | todo   | high     |
+    And a file ".backlog/.index.json" with content "{not json"
+    When I run "backlog list"
+    Then the exit code should be 0
+    And stdout should contain "First task"
+    And the JSON file ".backlog/.index.json" should have "version" equal to "1"
+
+  Scenario: The index can be disabled per workspace
+    Given a backlog with the following tasks:
+      | id  | title      | status | priority |
+      | 001 | First task | todo   | high     |
+    And a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: .backlog
+          index: false
+          default: true
+      """
+    When I run "backlog list"
+    Then the exit code should be 0
+    And stdout should contain "First task"
+    And the file ".backlog/.index.json" should not exist
+    When I run "backlog reindex"
+    Then the exit code should be 1
+    And stderr should contain "index: false"
@@ -23,6 +23,9 @@ Feature: Initialization
     And the directory ".backlog/.locks" should exist
     And the file ".backlog/config.yaml" should exist
     And the file ".backlog/config.yaml" should contain "backend: local"
+    And the file ".backlog/.gitignore" should contain "credentials.yaml"
+    And the file ".backlog/.gitignore" should contain ".index.json"
+    And the file ".backlog/.gitignore" should contain ".undo/"
 
   Scenario: Initialize backlog with GitHub backend
     When I run "backlog init" with input:
@@ -0,0 +1,206 @@
+Feature: Label Registry
+  As a team using the backlog CLI
+  I want labels registered with a color and description
+  So that we keep a controlled vocabulary and can spot labels at a glance
+
+  Background:
+    Given a backlog with the following tasks:
+      | id    | title        | status | priority | labels   |
+      | task1 | Crash report | todo   | high     | bug      |
+      | task2 | Write guide  | todo   | low      | docs,new |
+    And a file ".backlog/labels.yaml" with the following content:
+      """
+      bug:
+        color: "#d73a4a"
+        description: Something is broken
+      docs:
+        color: blue
+      """
+
+  Scenario: List registered labels
+    When I run "backlog label list"
+    Then the exit code should be 0
+    And stdout should contain "bug"
+    And stdout should contain "#d73a4a"
+    And stdout should contain "Something is broken"
+    And stdout should contain "docs"
+
+  Scenario: List registered labels as JSON
+    When I run "backlog label list -f json"
+    Then the exit code should be 0
+    And the JSON output should have "count" equal to "3"
+    And the JSON output should have "labels[0].name" equal to "bug"
+    And the JSON output should have "labels[0].color" equal to "#d73a4a"
+    And the JSON output should have "labels[0].tasks" equal to "1"
+    And the JSON output should have "labels[1].name" equal to "docs"
+
+  Scenario: Labels carried by tasks are listed with their task counts
+    Given a file ".backlog/labels.yaml" with content ""
+    When I run "backlog add 'Another bug' --label bug"
+    And I run "backlog label list -f json"
+    Then the exit code should be 0
+    And the JSON output should have "count" equal to "3"
+    And the JSON output should have "labels[0].name" equal to "bug"
+    And the JSON output should have "labels[0].tasks" equal to "2"
+    And the JSON output should have "labels[2].name" equal to "new"
+    And the JSON output should have "labels[2].tasks" equal to "1"
+
+  Scenario: A registered label no task carries is listed with a count of zero
+    Given a file ".backlog/labels.yaml" with the following content:
+      """
+      wontfix:
+        color: gray
+      """
+    When I run "backlog label list"
+    Then the exit code should be 0
+    And stdout should contain "TASKS"
+    And stdout should contain "wontfix"
+
+  Scenario: Invalid registry is reported
+    Given a file ".backlog/labels.yaml" with content "bug: [unclosed"
+    When I run "backlog label list"
+    Then the exit code should be 1
+    And stderr should contain "invalid labels.yaml"
+
+  Scenario: Table output shows labels in their registry colors
+    When I run "backlog list --color=always"
+    Then the exit code should be 0
+    And stdout should contain "LABELS"
+    And stdout should contain "bug" colored red
+    And stdout should contain "docs" colored blue
+
+  Scenario: Labels are not colored without color output
+    When I run "backlog list"
+    Then the exit code should be 0
+    And stdout should contain "docs, new"
+    And stdout should not contain ANSI colors
+
+  Scenario: Unknown labels are accepted silently by default
+    When I run "backlog add 'Tidy up' --label chore"
+    Then the exit code should be 0
+    And stderr should be empty
+
+  Scenario: Strict labels warns about unregistered labels on add
+    Given a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: ./.backlog
+          default: true
+          strict_labels: true
+      """
+    When I run "backlog add 'Tidy up' --label chore --label bug"
+    Then the exit code should be 0
+    And stderr should contain "chore"
+    And stderr should contain "is not in the label registry"
+    And stderr should not contain "bug"
+
+  Scenario: Strict labels warns about unregistered labels on edit
+    Given a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: ./.backlog
+          default: true
+          strict_labels: true
+      """
+    When I run "backlog edit task1 --add-label chore"
+    Then the exit code should be 0
+    And stderr should contain "chore"
+    And stderr should contain "is not in the label registry"
+
+  Scenario: Rename a label on every task and in the registry
+    When I run "backlog edit task2 --add-label bgu"
+    And I run "backlog label rename bgu bug -f json"
+    Then the exit code should be 0
+    And the JSON output should have "action" equal to "rename"
+    And the JSON output should have "new_name" equal to "bug"
+    And the JSON output should have array length "tasks" equal to 1
+    And the task "task2" should have label "bug"
+    And the task "task2" should not have label "bgu"
+    When I run "backlog label rename docs documentation"
+    Then the exit code should be 0
+    And stdout should contain "Renamed label docs to documentation on 1 task(s)"
+    And the task "task2" should have label "documentation"
+    And the file ".backlog/labels.yaml" should contain "documentation:"
+    And the file ".backlog/labels.yaml" should not contain "docs:"
+
+  Scenario: Rename is a single commit with git_sync
+    Given a git repository is initialized
+    And git_sync is enabled in the config
+    When I run "backlog add 'More docs' --label docs"
+    And I run "backlog label rename docs documentation"
+    Then the exit code should be 0
+    And the last git commit message should match pattern "^label: rename docs to documentation$"
+    And the task "task2" should have label "documentation"
+
+  Scenario: Renaming an unknown label fails
+    When I run "backlog label rename nope other"
+    Then the exit code should be 3
+    And stderr should contain "label not found"
+
+  Scenario: Rename refuses agent labels
+    Given the environment variable "BACKLOG_AGENT_ID" is "test-agent"
+    When I run "backlog claim task1"
+    And I run "backlog label rename agent:test-agent mine"
+    Then the exit code should be 1
+    And stderr should contain "is an agent label"
+    And the task "task1" should have agent label
+    When I run "backlog label rename bug agent:test-agent"
+    Then the exit code should be 1
+    And stderr should contain "is an agent label"
+    And the task "task1" should have label "bug"
+
+  Scenario: Removing a label in use is refused without --force
+    When I run "backlog label rm bug"
+    Then the exit code should be 2
+    And stderr should contain "is on 1 task(s)"
+    And the task "task1" should have label "bug"
+
+  Scenario: Removing a label with --force removes it from its tasks
+    When I run "backlog label rm docs --force --yes"
+    Then the exit code should be 0
+    And stdout should contain "Removed label docs from 1 task(s)"
+    And the task "task2" should not have label "docs"
+    And the task "task2" should have label "new"
+    And the file ".backlog/labels.yaml" should not contain "docs:"
+
+  Scenario: Removing a label with --force without a terminal requires --yes
+    When I run "backlog label rm docs --force"
+    Then the exit code should be 1
+    And stderr should contain "confirmation required, pass --yes"
+    And the task "task2" should have label "docs"
+
+  Scenario: Removing an unused registered label
+    Given a file ".backlog/labels.yaml" with the following content:
+      """
+      wontfix:
+        color: gray
+      """
+    When I run "backlog label rm wontfix"
+    Then the exit code should be 0
+    And the file ".backlog/labels.yaml" should not contain "wontfix"
+
+  Scenario: labels.normalize lowercases labels and makes filters ignore case
+    Given a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: ./.backlog
+          default: true
+          labels:
+            normalize: lower
+      """
+    When I run "backlog add 'Tidy up' --label Infra"
+    Then the exit code should be 0
+    And the created task should have label "infra"
+    When I run "backlog edit task1 --add-label INFRA"
+    Then the task "task1" should have label "infra"
+    When I run "backlog list --label Infra -f json"
+    Then the JSON output should have "count" equal to "2"
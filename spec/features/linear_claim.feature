@@ -101,6 +101,7 @@ Feature: Linear Claims
     And the JSON output should be valid
     And the JSON output should have "error" as an object
     And the JSON output should have "error.code" equal to "CONFLICT"
+    And the JSON output should have "error.error_code" equal to "CLAIM_CONFLICT"
     And the JSON output should have "error.message" containing "already claimed"
 
   @linear
@@ -176,6 +177,19 @@ Feature: Linear Claims
     Then the exit code should be 2
     And stderr should contain "claimed by different agent"
 
+  @linear
+  Scenario: Force release frees an issue claimed by a different agent
+    Given the mock Linear API has the following issues:
+      | identifier | title          | state       | labels           | assignee   | team |
+      | ENG-66     | Stuck on agent | In Progress | agent:dead-agent | other-user | ENG  |
+    And the environment variable "BACKLOG_AGENT_ID" is "supervisor"
+    When I run "backlog release ENG-66 --force --yes -f json"
+    Then the exit code should be 0
+    And the JSON output should have "status" equal to "todo"
+    And the JSON output should have "assignee" equal to ""
+    When I run "backlog show ENG-66 --comments"
+    Then stdout should contain "force-released from dead-agent by supervisor"
+
   @linear
   Scenario: Release unclaimed issue fails
     Given the mock Linear API has the following issues:
@@ -240,3 +254,12 @@ Feature: Linear Claims
     And the JSON output should be valid
     And the JSON output should have array "labels" containing "agent:flag-agent"
     And the JSON output should not have array "labels" containing "agent:env-agent"
+
+  @linear
+  Scenario: Claim with --comment posts a comment on the issue
+    Given the mock Linear API has the following issues:
+      | identifier | title          | state | labels | assignee | team |
+      | ENG-73     | Unclaimed task | Todo  |        |          | ENG  |
+    When I run "backlog claim ENG-73 --comment='starting work on the retry logic'"
+    Then the exit code should be 0
+    And the Linear issue "ENG-73" should have 1 comment
@@ -0,0 +1,106 @@
+Feature: Linear Comments
+  As a user of the backlog CLI with Linear backend
+  I want to add and view comments on Linear issues
+  So that I can track progress and communicate about work items
+
+  # Note: These scenarios test the Linear backend's comment operations.
+  # All scenarios require a mock Linear API server for testing without real credentials.
+
+  Background:
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: linear
+      workspaces:
+        linear:
+          backend: linear
+          team: ENG
+          api_key_env: LINEAR_API_KEY
+          default: true
+      """
+    And the environment variable "LINEAR_API_KEY" is "lin_api_valid_test_key"
+    And a mock Linear API server is running
+
+  @linear
+  Scenario: Comment adds issue comment via API
+    Given the mock Linear API has the following issues:
+      | identifier | title           | state | priority | team |
+      | ENG-50     | Task to comment | Todo  | medium   | ENG  |
+    When I run "backlog comment ENG-50 'Starting work on this feature'"
+    Then the exit code should be 0
+    And stdout should contain "Comment added"
+    And the Linear issue "ENG-50" should have 1 comment
+
+  @linear
+  Scenario: Show with --comments fetches comment thread
+    Given the mock Linear API has the following issues:
+      | identifier | title           | state | priority | team |
+      | ENG-51     | Task with notes | Todo  | medium   | ENG  |
+    And the mock Linear issue "ENG-51" has the following comments:
+      | author | body                              |
+      | alice  | Started research on this feature  |
+      | bob    | Found some relevant documentation |
+    When I run "backlog show ENG-51 --comments"
+    Then the exit code should be 0
+    And stdout should contain "Started research on this feature"
+    And stdout should contain "Found some relevant documentation"
+    And stdout should contain "alice"
+    And stdout should contain "bob"
+
+  @linear
+  Scenario: Show with --comments in JSON format includes comments array
+    Given the mock Linear API has the following issues:
+      | identifier | title           | state | priority | team |
+      | ENG-52     | Task with notes | Todo  | medium   | ENG  |
+    And the mock Linear issue "ENG-52" has the following comments:
+      | author | body                         |
+      | alice  | First comment on the issue   |
+      | bob    | Second comment with feedback |
+    When I run "backlog show ENG-52 --comments -f json"
+    Then the exit code should be 0
+    And the JSON output should be valid
+    And the JSON output array "comments" should have length 2
+
+  @linear
+  Scenario: Show with --comments --since and --limit narrows the thread
+    Given the mock Linear API has the following issues:
+      | identifier | title           | state | priority | team |
+      | ENG-53     | Long discussion | Todo  | medium   | ENG  |
+    And the mock Linear issue "ENG-53" has the following comments:
+      | author | body            | created              |
+      | alice  | Opening remarks | 2025-01-10T09:00:00Z |
+      | bob    | Early feedback  | 2025-01-16T09:00:00Z |
+      | alice  | Revised plan    | 2025-01-17T09:00:00Z |
+      | bob    | Final approval  | 2025-01-18T09:00:00Z |
+    When I run "backlog show ENG-53 --comments --since 2025-01-15T00:00:00Z --limit 2 -f json"
+    Then the exit code should be 0
+    And the JSON output array "comments" should have length 2
+    And stdout should contain "Revised plan"
+    And stdout should contain "Final approval"
+    And stdout should not contain "Early feedback"
+    And stdout should not contain "Opening remarks"
+
+  @linear
+  Scenario: Comment on non-existent issue returns exit code 3
+    When I run "backlog comment ENG-9999 'This should fail'"
+    Then the exit code should be 3
+
+  @linear
+  Scenario: List filters by whether issues have comments
+    Given the mock Linear API has the following issues:
+      | identifier | title            | state | priority | team |
+      | ENG-54     | Discussed issue  | Todo  | medium   | ENG  |
+      | ENG-55     | Untouched issue  | Todo  | medium   | ENG  |
+    And the mock Linear issue "ENG-54" has the following comments:
+      | author | body          |
+      | alice  | Any progress? |
+    When I run "backlog list --has-comments -f json"
+    Then the exit code should be 0
+    And the JSON output should have "count" equal to "1"
+    And the JSON output should have "tasks[0].id" equal to "ENG-54"
+    When I run "backlog list --no-comments -f json"
+    Then the exit code should be 0
+    And the JSON output should have "count" equal to "1"
+    And the JSON output should have "tasks[0].id" equal to "ENG-55"
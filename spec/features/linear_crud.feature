@@ -70,6 +70,15 @@ Feature: Linear CRUD Operations
     And the JSON output should have "id" matching pattern "ENG-[0-9]+"
     And the JSON output should have "url" containing "linear.app"
 
+  @linear
+  Scenario: Add with a repeated idempotency key returns the existing Linear issue
+    When I run "backlog add 'Nightly report failed' --idempotency-key report-1"
+    And I run "backlog add 'Nightly report failed' --idempotency-key report-1"
+    And I run "backlog list -f json"
+    Then the exit code should be 0
+    And the JSON output array "tasks" should have length 1
+    And stdout should not contain "backlog-idempotency-key"
+
   @linear
   Scenario: Add creates Linear issue with priority
     When I run "backlog add 'Urgent bug fix' --priority=urgent -f json"
@@ -108,12 +117,43 @@ Feature: Linear CRUD Operations
     And stdout should contain "ENG-99"
     And stdout should contain "Big feature"
 
+  @linear
+  Scenario: Show renders an issue as the same markdown document as a local task
+    Given the mock Linear API has the following issues:
+      | identifier | title             | state | priority | assignee | description                  | team |
+      | ENG-42     | Implement feature | Todo  | high     | alice    | Detailed feature description | ENG  |
+    When I run "backlog show ENG-42 -f markdown"
+    Then the exit code should be 0
+    And stdout should match pattern "^# Implement feature \(ENG-42\)\n"
+    And stdout should contain "| Status | todo |"
+    And stdout should contain "| Assignee | @alice |"
+    And stdout should contain "## Description"
+    And stdout should contain "Detailed feature description"
+
+  @linear
+  Scenario: Show raw prints the issue JSON from the API
+    Given the mock Linear API has the following issues:
+      | identifier | title             | state | priority | assignee | description                  | team |
+      | ENG-42     | Implement feature | Todo  | high     | alice    | Detailed feature description | ENG  |
+    When I run "backlog show ENG-42 --raw"
+    Then the exit code should be 0
+    And the JSON output should be valid
+    And the JSON output should have "identifier" equal to "ENG-42"
+    And the JSON output should have "state.name" equal to "Todo"
+
   @linear
   Scenario: Show non-existent issue returns exit code 3
     When I run "backlog show ENG-9999"
     Then the exit code should be 3
     And stderr should contain "not found"
 
+  @linear
+  Scenario: Show non-existent issue reports NOT_FOUND in JSON
+    When I run "backlog show ENG-9999 -f json"
+    Then the exit code should be 3
+    And the JSON output should have "error.error_code" equal to "NOT_FOUND"
+    And the JSON output should have "error.message" equal to "task not found: ENG-9999"
+
   @linear
   Scenario: Show with JSON error format for non-existent issue
     When I run "backlog show ENG-9999 -f json"
@@ -133,6 +173,27 @@ Feature: Linear CRUD Operations
     And the JSON output should have "id" equal to "ENG-10"
     And the JSON output should have "title" equal to "Updated title"
 
+  @linear
+  Scenario: Edit passes description from stdin through to the issue
+    Given the mock Linear API has the following issues:
+      | identifier | title        | state | priority | assignee | description    | team |
+      | ENG-11     | Needs detail | Todo  | medium   |          | Task to update | ENG  |
+    When I run "backlog edit ENG-11 --description -" with input:
+      """
+      Details:
+
+      ```go
+      fmt.Println("hi")
+      ```
+      """
+    Then the exit code should be 0
+    And the Linear issue "ENG-11" should have description containing:
+      """
+      ```go
+      fmt.Println("hi")
+      ```
+      """
+
   @linear
   Scenario: Edit updates issue priority
     Given the mock Linear API has the following issues:
@@ -194,3 +255,168 @@ Feature: Linear CRUD Operations
     When I run "backlog move ENG-9999 todo"
     Then the exit code should be 3
     And stderr should contain "not found"
+
+  @linear
+  Scenario: List filters by label
+    Given the mock Linear API has the following issues:
+      | identifier | title          | state | priority | labels       | team |
+      | ENG-30     | Fix login bug  | Todo  | high     | bug,backend  | ENG  |
+      | ENG-31     | Add dark mode  | Todo  | medium   | feature      | ENG  |
+      | ENG-32     | Fix crash      | Todo  | urgent   | bug          | ENG  |
+    When I run "backlog list --label=bug -f json"
+    Then the exit code should be 0
+    And the JSON output should be valid
+    And the JSON output array "tasks" should have length 2
+    And stdout should not contain "Add dark mode"
+
+  @linear
+  Scenario: List filters unassigned issues
+    Given the mock Linear API has the following issues:
+      | identifier | title          | state | priority | assignee | team |
+      | ENG-33     | Assigned task  | Todo  | medium   | alice    | ENG  |
+      | ENG-34     | Open task      | Todo  | medium   |          | ENG  |
+    When I run "backlog list --assignee=unassigned -f json"
+    Then the exit code should be 0
+    And the JSON output array "tasks" should have length 1
+    And the JSON output should have "tasks[0].id" equal to "ENG-34"
+
+  @linear
+  Scenario: List filters by a named assignee
+    Given the mock Linear API has the following issues:
+      | identifier | title          | state | priority | assignee | team |
+      | ENG-41     | Alice's task   | Todo  | medium   | alice    | ENG  |
+      | ENG-42     | Bob's task     | Todo  | medium   | bob      | ENG  |
+      | ENG-43     | Open task      | Todo  | medium   |          | ENG  |
+    When I run "backlog list --assignee=bob@example.com -f json"
+    Then the exit code should be 0
+    And the JSON output array "tasks" should have length 1
+    And the JSON output should have "tasks[0].id" equal to "ENG-42"
+
+  @linear
+  Scenario: List with an unknown assignee suggests close matches
+    Given the mock Linear API has the following issues:
+      | identifier | title        | state | priority | assignee | team |
+      | ENG-44     | Alice's task | Todo  | medium   | alice    | ENG  |
+    When I run "backlog list --assignee=alise"
+    Then the exit code should be 1
+    And stderr should contain "no Linear user matches"
+    And stderr should contain "did you mean alice <alice@example.com>?"
+
+  @linear
+  Scenario: List respects limit
+    Given the mock Linear API has the following issues:
+      | identifier | title  | state | priority | team |
+      | ENG-35     | First  | Todo  | medium   | ENG  |
+      | ENG-36     | Second | Todo  | medium   | ENG  |
+      | ENG-37     | Third  | Todo  | medium   | ENG  |
+    When I run "backlog list --limit=2 -f json"
+    Then the exit code should be 0
+    And the JSON output array "tasks" should have length 2
+
+  @linear
+  Scenario: List count-only counts matching issues
+    Given the mock Linear API has the following issues:
+      | identifier | title    | state       | priority | team |
+      | ENG-38     | First    | Todo        | medium   | ENG  |
+      | ENG-39     | Second   | Todo        | high     | ENG  |
+      | ENG-40     | Working  | In Progress | medium   | ENG  |
+      | ENG-41     | Finished | Done        | medium   | ENG  |
+    When I run "backlog list --count-only"
+    Then the exit code should be 0
+    And stdout should be "3"
+    When I run "backlog list --status=todo --count-only -f json"
+    Then the exit code should be 0
+    And the JSON output should have "count" equal to "2"
+
+  @linear
+  Scenario: Delete archives the Linear issue
+    Given the mock Linear API has the following issues:
+      | identifier | title          | state | priority | team |
+      | ENG-40     | Obsolete task  | Todo  | low      | ENG  |
+    When I run "backlog delete ENG-40 --yes"
+    Then the exit code should be 0
+    And the Linear issue "ENG-40" should be archived
+    When I run "backlog list -f json"
+    Then stdout should not contain "Obsolete task"
+
+  @linear
+  Scenario: Soft delete archives and restore unarchives the Linear issue
+    Given the mock Linear API has the following issues:
+      | identifier | title          | state | priority | team |
+      | ENG-41     | Parked task    | Todo  | low      | ENG  |
+    When I run "backlog delete ENG-41 --soft"
+    Then the exit code should be 0
+    And the Linear issue "ENG-41" should be archived
+    When I run "backlog list --include-deleted -f json"
+    Then the exit code should be 0
+    And the JSON output should have "tasks[0].id" equal to "ENG-41"
+    And the JSON output should have "tasks[0].deleted_at" matching pattern "^[0-9]{4}-[0-9]{2}-[0-9]{2}T"
+    When I run "backlog restore ENG-41 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "restored" equal to "true"
+    And the Linear issue "ENG-41" should not be archived
+    When I run "backlog list -f json"
+    Then stdout should contain "Parked task"
+
+  @linear
+  Scenario: Move uses custom workflow states
+    Given the mock Linear API has the following workflow states:
+      | name        | type      |
+      | Triage      | backlog   |
+      | Todo        | unstarted |
+      | Doing       | started   |
+      | In Review   | started   |
+      | Shipped     | completed |
+    And the mock Linear API has the following issues:
+      | identifier | title       | state | priority | team |
+      | ENG-41     | Ship it     | Todo  | medium   | ENG  |
+    When I run "backlog move ENG-41 done"
+    Then the exit code should be 0
+    And the Linear issue "ENG-41" should have state "Shipped"
+
+  @linear
+  Scenario: Sub-tasks map to Linear parent issues
+    Given the mock Linear API has the following issues:
+      | identifier | title         | state | priority | team |
+      | ENG-50     | Payments epic | Todo  | high     | ENG  |
+      | ENG-51     | Unrelated     | Todo  | low      | ENG  |
+    When I run "backlog add 'Card form' --parent ENG-50"
+    Then the exit code should be 0
+    When I run "backlog list --parent ENG-50 -f json"
+    Then the JSON output should have array length "tasks" equal to 1
+    And the JSON output should have "tasks[0].title" equal to "Card form"
+    And the JSON output should have "tasks[0].parent" equal to "ENG-50"
+    When I run "backlog show ENG-50"
+    Then stdout should contain "## Sub-tasks"
+    And stdout should contain "Card form"
+
+  @linear
+  Scenario: Label list shows Linear label colors
+    Given the mock Linear API has the following labels:
+      | name    | color   | description         |
+      | bug     | #eb5757 | Something is broken |
+      | feature | #4ea7fc |                     |
+    When I run "backlog label list -f json"
+    Then the exit code should be 0
+    And the JSON output should have "count" equal to "2"
+    And the JSON output should have "labels[0].name" equal to "bug"
+    And the JSON output should have "labels[0].color" equal to "#eb5757"
+    And the JSON output should have "labels[0].description" equal to "Something is broken"
+    And the JSON output should have "labels[1].color" equal to "#4ea7fc"
+
+  @linear
+  Scenario: Interactive edit assigns a Linear issue
+    Given the mock Linear API has the following issues:
+      | identifier | title      | state | priority | assignee | team |
+      | ENG-30     | Unassigned | Todo  | medium   |          | ENG  |
+    And a file "edit.sh" with the following content:
+      """
+      sed -i 's/^title: .*/title: Now assigned\nassignee: test-user/' "$1"
+      """
+    And the environment variable "VISUAL" is "sh edit.sh"
+    When I run "backlog edit ENG-30 --interactive"
+    Then the exit code should be 0
+    And stderr should contain "assignee: (none) -> @test-user"
+    When I run "backlog show ENG-30 -f json"
+    Then the JSON output should have "title" equal to "Now assigned"
+    And the JSON output should have "assignee" equal to "test-user"
@@ -0,0 +1,95 @@
+Feature: Linear Offline Cache
+  As an agent working with a Linear workspace
+  I want to mirror Linear issues into a local cache
+  So that I can read tasks without network access
+
+  Background:
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: linear
+      workspaces:
+        linear:
+          backend: linear
+          team: ENG
+          api_key_env: LINEAR_API_KEY
+          default: true
+      """
+    And the environment variable "LINEAR_API_KEY" is "lin_api_valid_test_key"
+    And a mock Linear API server is running
+    And the mock Linear API has the following issues:
+      | identifier | title             | state       | priority | description         |
+      | ENG-1      | Implement feature | Todo        | high     | Feature description |
+      | ENG-2      | Fix critical bug  | In Progress | urgent   |                     |
+      | ENG-3      | Ship release      | Done        | low      |                     |
+
+  @linear
+  Scenario: Sync mirrors all issues into the cache
+    When I run "backlog sync -f json"
+    Then the exit code should be 0
+    And the JSON output should have "created" equal to "3"
+    And the file ".backlog/.cache/linear/todo/ENG-1-implement-feature.md" should exist
+    And the file ".backlog/.cache/linear/in-progress/ENG-2-fix-critical-bug.md" should exist
+    And the file ".backlog/.cache/linear/done/ENG-3-ship-release.md" should exist
+    And the file ".backlog/.cache/linear/.last-sync" should exist
+    And the file ".backlog/.cache/linear/.gitignore" should contain "*"
+
+  @linear
+  Scenario: Show reads a cached issue without network access
+    Given I run "backlog sync"
+    And the environment variable "LINEAR_API_URL" is "http://127.0.0.1:1/graphql"
+    When I run "backlog show ENG-1 --cached -f json"
+    Then the exit code should be 0
+    And the JSON output should have "title" equal to "Implement feature"
+    And the JSON output should have "status" equal to "todo"
+    And the JSON output should have "priority" equal to "high"
+    And the JSON output should have "description" equal to "Feature description"
+    And the JSON output should have "url" containing "ENG-1"
+
+  @linear
+  Scenario: Later syncs fetch only issues updated since the last one
+    Given I run "backlog sync"
+    When I run "backlog sync"
+    Then the exit code should be 0
+    And stdout should contain "Already up to date."
+    When I run "backlog move ENG-1 in-progress"
+    And I run "backlog sync -f json"
+    Then the exit code should be 0
+    And the JSON output should have "created" equal to "0"
+    And the JSON output should have "updated" equal to "1"
+    And the file ".backlog/.cache/linear/in-progress/ENG-1-implement-feature.md" should exist
+    And the file ".backlog/.cache/linear/todo/ENG-1-implement-feature.md" should not exist
+
+  @linear
+  Scenario: Forced sync drops issues that no longer exist
+    Given I run "backlog sync"
+    And I run "backlog delete ENG-2 --yes"
+    When I run "backlog sync --force -f json"
+    Then the exit code should be 0
+    And the JSON output should have "deleted" equal to "1"
+    And the file ".backlog/.cache/linear/in-progress/ENG-2-fix-critical-bug.md" should not exist
+
+  @linear
+  Scenario: Show --cached before any sync fails
+    When I run "backlog show ENG-1 --cached"
+    Then the exit code should be 3
+    And stderr should contain "run 'backlog sync' first"
+
+  Scenario: Show --cached is only for Linear workspaces
+    Given a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: main
+      workspaces:
+        main:
+          backend: local
+          path: ./.backlog
+          default: true
+      """
+    And a task "001" exists with status "todo"
+    When I run "backlog show 001 --cached"
+    Then the exit code should be 1
+    And stderr should contain "only supported for linear workspaces"
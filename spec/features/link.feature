@@ -16,12 +16,16 @@ Feature: Task Dependencies
     Then the exit code should be 0
     And stdout should contain "task1"
     And stdout should contain "task2"
+    And the task "task1" should block "task2"
+    And the task "task2" should be blocked by "task1"
 
   Scenario: Link two tasks with blocked-by
     When I run "backlog link task2 --blocked-by task1"
     Then the exit code should be 0
     And stdout should contain "task2"
     And stdout should contain "task1"
+    And the task "task2" should be blocked by "task1"
+    And the task "task1" should block "task2"
 
   Scenario: Show displays blocking relationships
     When I run "backlog link task1 --blocks task2"
@@ -45,6 +45,20 @@ Feature: Listing Tasks
     And stdout should contain "Third task"
     And stdout should contain "bob"
 
+  Scenario: List table rows match their columns
+    Given a backlog with the following tasks:
+      | id    | title           | status      | priority | assignee | labels   |
+      | task1 | First task      | todo        | high     | alice    | api,auth |
+      | task2 | Second task     | in-progress | medium   |          |          |
+      | task3 | Completed task  | done        | low      |          |          |
+    When I run "backlog list"
+    Then the exit code should be 0
+    And the output table should contain rows:
+      | id    | title       | assignee | status      | labels    |
+      | task2 | Second task |          | in-progress |           |
+      | task1 | First task  | @alice   | todo        | api, auth |
+    And the output table should not contain a row with id "task3"
+
   Scenario: List tasks in JSON format
     Given a backlog with the following tasks:
       | id    | title           | status      | priority | assignee | labels        |
@@ -149,6 +163,18 @@ Feature: Listing Tasks
     And stdout should not contain "Fourth task"
     And stdout should not contain "Fifth task"
 
+  Scenario: List filters accept aliases in any case
+    Given a backlog with the following tasks:
+      | id    | title           | status      | priority |
+      | task1 | First task      | todo        | high     |
+      | task2 | Second task     | in-progress | urgent   |
+      | task3 | Third task      | in-progress | low      |
+    When I run "backlog list --status=WIP --priority=P0"
+    Then the exit code should be 0
+    And stdout should contain "Second task"
+    And stdout should not contain "First task"
+    And stdout should not contain "Third task"
+
   Scenario: List with label filter
     Given a backlog with the following tasks:
       | id    | title           | status      | priority | labels        |
@@ -237,4 +263,312 @@ Feature: Listing Tasks
     Then the exit code should be 0
     And the JSON output should be valid
     And the JSON output should have "count" equal to "3"
-    And the JSON output should have "hasMore" equal to "true"
\ No newline at end of file
+    And the JSON output should have "hasMore" equal to "true"
+  Scenario: List skips files excluded by .backlogignore
+    Given a backlog with the following tasks:
+      | id  | title     | status | priority |
+      | 001 | Real task | todo   | high     |
+    And a file ".backlog/todo/900-template.md" with the following content:
+      """
+      ---
+      id: "900"
+      title: Task template
+      ---
+
+      Copy this file to start a new task.
+      """
+    And a file ".backlog/.backlogignore" with the following content:
+      """
+      # Auxiliary docs kept alongside tasks
+      *-template.md
+      """
+    When I run "backlog list"
+    Then the exit code should be 0
+    And stdout should contain "Real task"
+    And stdout should not contain "Task template"
+    When I run "backlog add 'Next task' -f json"
+    Then the exit code should be 0
+    And the JSON output should have "id" equal to "002"
+
+  Scenario: Group tasks by status
+    Given a backlog with the following tasks:
+      | id    | title        | status      | priority |
+      | task1 | Ready task   | todo        | high     |
+      | task2 | Active task  | in-progress | medium   |
+      | task3 | Later task   | backlog     | low      |
+      | task4 | Another one  | todo        | urgent   |
+    When I run "backlog list --group-by status"
+    Then the exit code should be 0
+    And stdout should match pattern "(?s)backlog \(1\).*todo \(2\).*in-progress \(1\)"
+    And stdout should match pattern "(?s)task4.*task1"
+
+  Scenario: Group tasks by status as JSON
+    Given a backlog with the following tasks:
+      | id    | title        | status      | priority |
+      | task1 | Ready task   | todo        | high     |
+      | task2 | Active task  | in-progress | medium   |
+      | task3 | Another one  | todo        | urgent   |
+    When I run "backlog list --group-by status -f json"
+    Then the exit code should be 0
+    And the JSON output should have "group_by" equal to "status"
+    And the JSON output should have array length "groups.todo" equal to 2
+    And the JSON output should have "groups.todo[0].id" equal to "task3"
+    And the JSON output should have "groups.in-progress[0].id" equal to "task2"
+    And the JSON output should have "order[0]" equal to "todo"
+    And the JSON output should have "count" equal to "3"
+
+  Scenario: Group tasks by assignee puts unassigned tasks last
+    Given a backlog with the following tasks:
+      | id    | title        | status | priority | assignee |
+      | task1 | Open task    | todo   | high     |          |
+      | task2 | Bob's task   | todo   | medium   | bob      |
+      | task3 | Alice's task | todo   | low      | alice    |
+    When I run "backlog list --group-by assignee -f plain"
+    Then the exit code should be 0
+    And stdout should match pattern "(?s)^alice\ttask3.*bob\ttask2.*unassigned\ttask1"
+
+  Scenario: Group tasks by label lists a task under each of its labels
+    Given a backlog with the following tasks:
+      | id    | title        | status | priority | labels  |
+      | task1 | Both labels  | todo   | high     | bug,ui  |
+      | task2 | No labels    | todo   | medium   |         |
+    When I run "backlog list --group-by label -f json"
+    Then the exit code should be 0
+    And the JSON output should have "groups.bug[0].id" equal to "task1"
+    And the JSON output should have "groups.ui[0].id" equal to "task1"
+    And the JSON output should have "groups.unlabeled[0].id" equal to "task2"
+
+  Scenario: Invalid group-by field
+    Given a backlog with the following tasks:
+      | id    | title        | status | priority |
+      | task1 | Ready task   | todo   | high     |
+    When I run "backlog list --group-by milestone"
+    Then the exit code should be 1
+    And stderr should contain "invalid group-by field"
+
+  Scenario: Select and order table columns with --fields
+    Given a backlog with the following tasks:
+      | id    | title        | status | priority | assignee |
+      | task1 | Ready task   | todo   | high     | alice    |
+    When I run "backlog list --fields title,id,assignee"
+    Then the exit code should be 0
+    And stdout should match pattern "^TITLE\s+ID\s+ASSIGNEE\n"
+    And stdout should match pattern "Ready task\s+task1\s+@alice"
+    And stdout should not contain "PRIORITY"
+
+  Scenario: Select JSON keys with --fields
+    Given a backlog with the following tasks:
+      | id    | title        | status | priority | assignee |
+      | task1 | Ready task   | todo   | high     | alice    |
+    When I run "backlog list --fields id,assignee -f json"
+    Then the exit code should be 0
+    And the JSON output should have "tasks[0].id" equal to "task1"
+    And the JSON output should have "tasks[0].assignee" equal to "alice"
+    And stdout should not contain "priority"
+
+  Scenario: Unknown field is rejected
+    Given a backlog with the following tasks:
+      | id    | title        | status | priority |
+      | task1 | Ready task   | todo   | high     |
+    When I run "backlog list --fields id,milestone"
+    Then the exit code should be 1
+    And stderr should contain "unknown field"
+    And stderr should contain "milestone"
+
+  Scenario: Filter by creation and update time
+    Given a backlog with the following tasks:
+      | id    | title        | status | priority |
+      | task1 | New task     | todo   | high     |
+    And a file ".backlog/todo/002-old.md" with the following content:
+      """
+      ---
+      id: "002"
+      title: Old task
+      priority: medium
+      created: 2025-01-15T09:00:00Z
+      updated: 2025-01-20T09:00:00Z
+      ---
+      """
+    When I run "backlog list --created-since 1d -f json"
+    Then the exit code should be 0
+    And the JSON output should have "count" equal to "1"
+    And the JSON output should have "tasks[0].id" equal to "task1"
+    When I run "backlog list --updated-since 2025-01-18T00:00:00Z -f json"
+    Then the JSON output should have "count" equal to "2"
+    And the JSON output should have "filters.updated_since" equal to "2025-01-18T00:00:00Z"
+    When I run "backlog list --created-since 2025-01-16T00:00:00Z --updated-since 2w -f id-only"
+    Then stdout should contain "task1"
+    And stdout should not contain "002"
+
+  Scenario: Invalid time filter shows accepted formats
+    Given a backlog with the following tasks:
+      | id    | title        | status | priority |
+      | task1 | Ready task   | todo   | high     |
+    When I run "backlog list --updated-since yesterday"
+    Then the exit code should be 1
+    And stderr should contain "--updated-since"
+    And stderr should contain "2h, 3d, 1w"
+
+  Scenario: Count matching tasks with --count-only
+    Given a backlog with the following tasks:
+      | id    | title          | status      | priority |
+      | task1 | First task     | todo        | high     |
+      | task2 | Second task    | todo        | low      |
+      | task3 | Working task   | in-progress | high     |
+      | task4 | Completed task | done        | high     |
+    When I run "backlog list --count-only"
+    Then the exit code should be 0
+    And stdout should be "3"
+    When I run "backlog list --status=todo --count-only"
+    Then stdout should be "2"
+    When I run "backlog list --priority=high --include-done --count-only -f json"
+    Then the exit code should be 0
+    And the JSON output should have "count" equal to "3"
+    And stdout should not contain "tasks"
+
+  Scenario: Count-only is capped by --limit
+    Given a backlog with the following tasks:
+      | id    | title       | status | priority |
+      | task1 | First task  | todo   | high     |
+      | task2 | Second task | todo   | low      |
+      | task3 | Third task  | todo   | medium   |
+    When I run "backlog list --count-only --limit=2"
+    Then the exit code should be 0
+    And stdout should be "2"
+
+  Scenario: Count-only with no matching tasks
+    Given a backlog with the following tasks:
+      | id    | title      | status | priority |
+      | task1 | First task | todo   | high     |
+    When I run "backlog list --label=nothing --count-only -f json"
+    Then the exit code should be 0
+    And the JSON output should have "count" equal to "0"
+
+  Scenario: Count-only cannot be combined with group-by
+    Given a backlog with the following tasks:
+      | id    | title      | status | priority |
+      | task1 | First task | todo   | high     |
+    When I run "backlog list --count-only --group-by=status"
+    Then the exit code should be 1
+    And stderr should contain "--count-only cannot be combined with --group-by"
+
+  Scenario: Sort by several keys with --sort
+    Given a backlog with the following tasks:
+      | id     | title         | status      | priority | created              |
+      | task1  | Old high      | todo        | high     | 2025-01-01T09:00:00Z |
+      | task2  | New urgent    | todo        | urgent   | 2025-01-05T09:00:00Z |
+      | task3  | New high      | in-progress | high     | 2025-01-04T09:00:00Z |
+      | task10 | Old low       | backlog     | low      | 2025-01-02T09:00:00Z |
+    When I run "backlog list --sort -priority,-created -f json"
+    Then the exit code should be 0
+    And the tasks in the output should be ordered by "priority desc, created desc"
+    And the JSON output should have "tasks[0].id" equal to "task2"
+    And the JSON output should have "tasks[1].id" equal to "task3"
+    When I run "backlog list --sort created"
+    Then the exit code should be 0
+    And the tasks in the output should be ordered by "created asc"
+    When I run "backlog list --sort id"
+    Then the tasks in the output should be ordered by "id"
+    When I run "backlog list --sort status,title"
+    Then the tasks in the output should be ordered by "status asc, title asc"
+
+  Scenario: The default order is priority, then oldest first
+    Given a backlog with the following tasks:
+      | id    | title    | status | priority | created              |
+      | task1 | Newer    | todo   | high     | 2025-01-03T09:00:00Z |
+      | task2 | Older    | todo   | high     | 2025-01-01T09:00:00Z |
+      | task3 | Urgent   | todo   | urgent   | 2025-01-04T09:00:00Z |
+      | task4 | Low      | todo   | low      | 2025-01-02T09:00:00Z |
+    When I run "backlog list"
+    Then the exit code should be 0
+    And the tasks in the output should be ordered by "priority desc, created asc"
+
+  Scenario: --limit applies after sorting
+    Given a backlog with the following tasks:
+      | id    | title  | status | priority | created              |
+      | task1 | First  | todo   | urgent   | 2025-01-01T09:00:00Z |
+      | task2 | Second | todo   | low      | 2025-01-02T09:00:00Z |
+      | task3 | Third  | todo   | medium   | 2025-01-03T09:00:00Z |
+    When I run "backlog list --sort -created --limit 2 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "tasks[0].id" equal to "task3"
+    And the JSON output should have "tasks[1].id" equal to "task2"
+    And the JSON output should have "hasMore" equal to "true"
+
+  Scenario: Invalid sort keys list the valid ones
+    Given a backlog with the following tasks:
+      | id    | title      | status | priority |
+      | task1 | First task | todo   | high     |
+    When I run "backlog list --sort -priority,size"
+    Then the exit code should be 1
+    And stderr should contain "invalid sort field"
+    And stderr should contain "size"
+    And stderr should contain "priority, created, updated, id, title, status, duration"
+
+  Scenario: Filter by whether tasks have comments
+    Given a backlog with the following tasks:
+      | id    | title           | status | priority |
+      | task1 | Reviewed task   | review | high     |
+      | task2 | Unreviewed task | review | medium   |
+      | task3 | Quiet todo      | todo   | low      |
+    And task "task1" has the following comments:
+      | author | date       | body        |
+      | alex   | 2025-01-16 | Looks good  |
+    When I run "backlog list --has-comments -f id-only"
+    Then the exit code should be 0
+    And stdout should contain "task1"
+    And stdout should not contain "task2"
+    And stdout should not contain "task3"
+    When I run "backlog list --status review --no-comments -f id-only"
+    Then the exit code should be 0
+    And stdout should contain "task2"
+    And stdout should not contain "task1"
+    And stdout should not contain "task3"
+    When I run "backlog list --no-comments --count-only"
+    Then stdout should contain "2"
+
+  Scenario: --has-comments and --no-comments are exclusive
+    Given a backlog with the following tasks:
+      | id    | title      | status | priority |
+      | task1 | First task | todo   | high     |
+    When I run "backlog list --has-comments --no-comments"
+    Then the exit code should be 1
+    And stderr should contain "cannot be used together"
+
+  Scenario: --mine lists tasks assigned to or claimed by the current agent
+    Given a backlog with the following tasks:
+      | id    | title           | status      | priority | assignee | labels         |
+      | task1 | Assigned to me  | todo        | low      | claude-1 |                |
+      | task2 | Claimed by me   | in-progress | urgent   | alex     | agent:claude-1 |
+      | task3 | Someone else's  | todo        | high     | alex     | agent:claude-2 |
+      | task4 | Unassigned      | todo        | medium   |          |                |
+    And the environment variable "BACKLOG_AGENT_ID" is "claude-1"
+    When I run "backlog list --mine -f json"
+    Then the exit code should be 0
+    And the JSON output should have "count" equal to "2"
+    And the JSON output should have "tasks[0].id" equal to "task2"
+    And the JSON output should have "tasks[1].id" equal to "task1"
+    When I run "backlog list --mine --status todo -f id-only"
+    Then stdout should contain "task1"
+    And stdout should not contain "task2"
+    When I run "backlog list --mine --count-only"
+    Then stdout should contain "2"
+
+  Scenario: A task both assigned to and claimed by me is listed once by --mine
+    Given a backlog with the following tasks:
+      | id    | title        | status | priority | assignee | labels         |
+      | task1 | Claimed task | todo   | high     | claude-1 | agent:claude-1 |
+      | task2 | Other task   | todo   | low      | alex     |                |
+    And the environment variable "BACKLOG_AGENT_ID" is "claude-1"
+    When I run "backlog list --mine --limit 5 -f json"
+    Then the exit code should be 0
+    And the JSON output should have "count" equal to "1"
+    And the JSON output should have "tasks[0].id" equal to "task1"
+
+  Scenario: --mine cannot be combined with --assignee
+    Given a backlog with the following tasks:
+      | id    | title      | status | priority |
+      | task1 | First task | todo   | high     |
+    When I run "backlog list --mine --assignee alex"
+    Then the exit code should be 1
+    And stderr should contain "--mine cannot be combined with --assignee"
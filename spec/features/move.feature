@@ -45,6 +45,28 @@ Feature: Moving Tasks
     Then the exit code should be 1
     And stderr should contain "invalid status"
 
+  Scenario Outline: Move task accepts status aliases in any case
+    When I run "backlog move task1 <input>"
+    Then the exit code should be 0
+    And stdout should contain "<status>"
+    And the task "task1" should have status "<status>"
+
+    Examples:
+      | input       | status      |
+      | InProgress  | in-progress |
+      | in_progress | in-progress |
+      | WIP         | in-progress |
+      | doing       | in-progress |
+      | TODO        | todo        |
+      | closed      | done        |
+      | complete    | done        |
+
+  Scenario: Invalid status error lists accepted values
+    When I run "backlog move task1 someday"
+    Then the exit code should be 1
+    And stderr should contain "valid: backlog, todo, in-progress, review, done"
+    And the task "task1" should have status "backlog"
+
   Scenario: Move non-existent task returns exit code 3
     When I run "backlog move nonexistent-task todo"
     Then the exit code should be 3
@@ -76,3 +98,34 @@ Feature: Moving Tasks
     And the task "task2" should have priority "urgent"
     And the task "task2" should have assignee "alex"
     And the task "task2" should have label "bug"
+
+  Scenario: Move with assignee sets the assignee
+    When I run "backlog move task1 in-progress --assignee alice"
+    Then the exit code should be 0
+    And the task "task1" should have status "in-progress"
+    And the task "task1" should have assignee "alice"
+
+  Scenario: Move with an empty assignee unassigns the task
+    When I run "backlog move task2 in-progress --assignee="
+    Then the exit code should be 0
+    And the task "task2" should have status "in-progress"
+    And the task "task2" should have assignee ""
+
+  Scenario: Move with assignee in dry-run mode changes nothing
+    When I run "backlog move task1 in-progress --assignee alice --dry-run -f json"
+    Then the exit code should be 0
+    And the JSON output should have "task.assignee" equal to "alice"
+    And the task "task1" should have status "backlog"
+    And the task "task1" should have assignee ""
+
+  Scenario: Move with assignee shows the other fields it changed
+    When I run "backlog move task1 in-progress --assignee agent-7"
+    Then the exit code should be 0
+    And stdout should contain "Moved task1: backlog → in-progress"
+    And stdout should contain "assignee: (none) → agent-7"
+
+  Scenario: Move reports the status change in JSON
+    When I run "backlog move task3 review -f json"
+    Then the exit code should be 0
+    And the JSON output should have "changes.status.before" equal to "in-progress"
+    And the JSON output should have "changes.status.after" equal to "review"
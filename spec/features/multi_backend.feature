@@ -133,3 +133,65 @@ Feature: Multi-Backend Support
     And the JSON output should be valid
     And the JSON output should have "tasks" as an array
     And the JSON output should have "count" equal to "1"
+
+  @multi-backend
+  Scenario: Capabilities are compared across workspaces
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: local
+      workspaces:
+        local:
+          backend: local
+          path: ./.backlog
+          default: true
+        linear:
+          backend: linear
+          team: ENG
+          api_key_env: LINEAR_API_KEY
+      """
+    When I run "backlog workspace capabilities -f json"
+    Then the exit code should be 0
+    And the JSON output should have "workspaces[0].workspace" equal to "linear"
+    And the JSON output should have "workspaces[0].backend" equal to "linear"
+    And the JSON output should have "workspaces[0].operations[4].name" equal to "reorder"
+    And the JSON output should have "workspaces[0].operations[4].supported" equal to "true"
+    And the JSON output should have "workspaces[0].operations[12].name" equal to "history"
+    And the JSON output should have "workspaces[0].operations[12].supported" equal to "false"
+    And the JSON output should have "workspaces[1].workspace" equal to "local"
+    And the JSON output should have "workspaces[1].operations[12].supported" equal to "true"
+    When I run "backlog workspace capabilities"
+    Then the exit code should be 0
+    And stdout should match pattern "history\s+no\s+yes\s+history"
+    When I run "backlog workspace capabilities local -f id-only"
+    Then the exit code should be 0
+    And stdout should contain "history"
+    When I run "backlog workspace capabilities -f id-only"
+    Then the exit code should be 0
+    And stdout should contain "reorder"
+    And stdout should not contain "history"
+
+  @multi-backend
+  Scenario: Capabilities of an unknown workspace
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: local
+      workspaces:
+        local:
+          backend: local
+          path: ./.backlog
+          default: true
+        linear:
+          backend: linear
+          team: ENG
+          api_key_env: LINEAR_API_KEY
+      """
+    When I run "backlog workspace capabilities nope"
+    Then the exit code should be 4
+    And stderr should contain "nope"
+    And stderr should contain "not found"
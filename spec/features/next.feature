@@ -109,3 +109,135 @@ Feature: Next Task
     Then the exit code should be 0
     And the JSON output should have "id" equal to "taskA"
     And the JSON output should have "title" equal to "First task"
+
+  Scenario: Next with --claim skips a candidate locked by another agent
+    Given the environment variable "BACKLOG_AGENT_ID" is "test-agent"
+    And a file ".backlog/.locks/task1.lock" with the following content:
+      """
+      agent: other-agent
+      claimed_at: 2026-01-01T00:00:00Z
+      expires_at: 2099-01-01T00:00:00Z
+      """
+    When I run "backlog next --claim -f json"
+    Then the exit code should be 0
+    And the JSON output should have "id" equal to "task2"
+    And the JSON output should have "candidates_skipped[0].id" equal to "task1"
+    And the JSON output should have "candidates_skipped[0].reason" containing "already claimed by agent other-agent"
+    And the task "task2" should have status "in-progress"
+
+  Scenario: Next with --claim skips blocked candidates
+    Given the environment variable "BACKLOG_AGENT_ID" is "test-agent"
+    When I run "backlog link task1 --blocked-by task3"
+    And I run "backlog next --claim -f json"
+    Then the exit code should be 0
+    And the JSON output should have "id" equal to "task2"
+    And the JSON output should have "candidates_skipped[0].id" equal to "task1"
+    And the JSON output should have "candidates_skipped[0].reason" equal to "blocked by task3"
+    And the task "task1" should have status "todo"
+
+  Scenario: Next with --claim reports an empty skipped list when the first candidate is claimed
+    Given the environment variable "BACKLOG_AGENT_ID" is "test-agent"
+    When I run "backlog next --claim -f json"
+    Then the exit code should be 0
+    And the JSON output should have "id" equal to "task1"
+    And the JSON output should have array length "candidates_skipped" equal to 0
+
+  Scenario: Next with --claim gives up after --max-attempts conflicts
+    Given the environment variable "BACKLOG_AGENT_ID" is "test-agent"
+    And a file ".backlog/.locks/task1.lock" with the following content:
+      """
+      agent: other-agent
+      claimed_at: 2026-01-01T00:00:00Z
+      expires_at: 2099-01-01T00:00:00Z
+      """
+    When I run "backlog next --claim --max-attempts=1 -f json"
+    Then the exit code should be 2
+    And the JSON output should have "error.code" equal to "CONFLICT"
+    And the JSON output should have "error.error_code" equal to "CLAIM_CONFLICT"
+    And the JSON output should have "error.details.candidates_skipped[0].id" equal to "task1"
+    And the task "task2" should have status "todo"
+
+  Scenario: Next with --claim rejects a non-positive --max-attempts
+    When I run "backlog next --claim --max-attempts=0"
+    Then the exit code should be 1
+    And stderr should contain "--max-attempts must be at least 1"
+
+  Scenario: Next with --claim --count claims a batch of tasks
+    Given the environment variable "BACKLOG_AGENT_ID" is "test-agent"
+    When I run "backlog next --claim --count=3 -f json"
+    Then the exit code should be 0
+    And the JSON output array "" should have length 3
+    And the JSON output should have "[0].id" equal to "task1"
+    And the JSON output should have "[1].id" equal to "task2"
+    And the JSON output should have "[2].id" equal to "task3"
+    And the JSON output should have "[2].agent" equal to "test-agent"
+    And the task "task1" should have status "in-progress"
+    And the task "task3" should have label "agent:test-agent"
+    And the task "task4" should have status "todo"
+
+  Scenario: Next with --claim --count skips candidates claimed by other agents
+    Given the environment variable "BACKLOG_AGENT_ID" is "test-agent"
+    And a file ".backlog/.locks/task2.lock" with the following content:
+      """
+      agent: other-agent
+      claimed_at: 2026-01-01T00:00:00Z
+      expires_at: 2099-01-01T00:00:00Z
+      """
+    When I run "backlog next --claim --count=2"
+    Then the exit code should be 0
+    And stdout should contain "Claimed task1"
+    And stdout should contain "Claimed task3"
+    And stdout should contain "skipped task2: conflict"
+    And the task "task2" should have status "todo"
+
+  Scenario: Next with --claim --count succeeds with fewer tasks than requested
+    Given the environment variable "BACKLOG_AGENT_ID" is "test-agent"
+    When I run "backlog next --claim --count=10 -f id-only"
+    Then the exit code should be 0
+    And stdout should contain "task1"
+    And stdout should contain "task5"
+    And the task "task5" should have status "in-progress"
+
+  Scenario: Next --count requires --claim
+    When I run "backlog next --count=2"
+    Then the exit code should be 1
+    And stderr should contain "--count requires --claim"
+
+  Scenario: Next --wait returns at once when a task is available
+    When I run "backlog next --wait=10s -f id-only"
+    Then the exit code should be 0
+    And stdout should be "task1"
+
+  Scenario: Next --wait times out with its own exit code
+    Given a backlog with the following tasks:
+      | id    | title        | status | priority | assignee | labels  | agent_id |
+      | taskA | Claimed task | todo   | high     | alex     | backend | claude-1 |
+    When I run "backlog next --wait=300ms --poll-interval=50ms -f json"
+    Then the exit code should be 7
+    And the JSON output should have "error.code" equal to "TIMEOUT"
+    And the JSON output should have "error.message" containing "no task became available within 300ms"
+
+  Scenario: Next --wait --claim wakes when a task becomes available
+    Given the environment variable "BACKLOG_AGENT_ID" is "test-agent"
+    And a backlog with the following tasks:
+      | id    | title        | status | priority | assignee | labels  | agent_id |
+      | taskA | Claimed task | todo   | high     | alex     | backend | claude-1 |
+      | taskB | Done task    | done   | high     |          |         |          |
+    When I run "backlog next --claim --wait=20s --poll-interval=1h -f id-only" while task "taskB" is moved to "todo" after 300ms
+    Then the exit code should be 0
+    And stdout should be "taskB"
+    And the task "taskB" should have status "in-progress"
+
+  Scenario: Next --wait --heartbeat reports each empty poll in JSON
+    Given a backlog with the following tasks:
+      | id    | title        | status | priority | assignee | labels  | agent_id |
+      | taskA | Claimed task | todo   | high     | alex     | backend | claude-1 |
+    When I run "backlog next --wait=300ms --poll-interval=50ms --heartbeat -f json"
+    Then the exit code should be 7
+    And stdout should contain "heartbeat"
+    And stdout should contain "waited_seconds"
+
+  Scenario: Next --heartbeat requires --wait
+    When I run "backlog next --heartbeat"
+    Then the exit code should be 1
+    And stderr should contain "--heartbeat requires --wait"
@@ -0,0 +1,155 @@
+Feature: Pinging the Backend
+  As an agent about to start a batch run
+  I want to check that the backend is reachable
+  So that connectivity and credential problems surface before any work starts
+
+  Scenario: Ping the local backend
+    Given a fresh backlog directory
+    When I run "backlog ping"
+    Then the exit code should be 0
+    And stdout should contain "local: healthy"
+
+  Scenario: Ping reports the health status as JSON
+    Given a fresh backlog directory
+    When I run "backlog ping -f json"
+    Then the exit code should be 0
+    And the JSON output should have "backend" equal to "local"
+    And the JSON output should have "healthy" equal to "true"
+    And the JSON output should have "message" equal to "ok"
+
+  @github
+  Scenario: Ping a reachable GitHub repository
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: github
+      workspaces:
+        github:
+          backend: github
+          repo: test-owner/test-repo
+          api_key_env: GITHUB_TOKEN
+          default: true
+      """
+    And the environment variable "GITHUB_TOKEN" is "ghp_valid_test_token"
+    And a mock GitHub API server is running
+    When I run "backlog ping"
+    Then the exit code should be 0
+    And stdout should contain "github: healthy"
+
+  @github
+  Scenario: Ping an unreachable GitHub repository exits non-zero
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: github
+      workspaces:
+        github:
+          backend: github
+          repo: test-owner/test-repo
+          api_key_env: GITHUB_TOKEN
+          default: true
+      """
+    And the environment variable "GITHUB_TOKEN" is "ghp_valid_test_token"
+    And a mock GitHub API server is running
+    And the mock GitHub API returns 404 for GET /repos/test-owner/test-repo
+    When I run "backlog ping -f json"
+    Then the exit code should be 1
+    And the JSON output should have "healthy" equal to "false"
+    And the JSON output should have "message" equal to "repository not found or not accessible"
+
+  @github
+  Scenario: Ping reports the GitHub rate limit
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: github
+      workspaces:
+        github:
+          backend: github
+          repo: test-owner/test-repo
+          api_key_env: GITHUB_TOKEN
+          default: true
+      """
+    And the environment variable "GITHUB_TOKEN" is "ghp_valid_test_token"
+    And a mock GitHub API server is running
+    And the mock GitHub API rate limit has 4000 of 5000 requests remaining, resetting in 3600 seconds
+    When I run "backlog ping -f json"
+    Then the exit code should be 0
+    And the JSON output should have "rate_limit.limit" equal to "5000"
+    And the JSON output should have "rate_limit.remaining" equal to "3999"
+    And stderr should not contain "rate limit"
+
+  @github
+  Scenario: A low GitHub rate limit is reported on stderr
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: github
+      workspaces:
+        github:
+          backend: github
+          repo: test-owner/test-repo
+          api_key_env: GITHUB_TOKEN
+          default: true
+      """
+    And the environment variable "GITHUB_TOKEN" is "ghp_valid_test_token"
+    And a mock GitHub API server is running
+    And the mock GitHub API rate limit has 50 of 5000 requests remaining, resetting in 3600 seconds
+    When I run "backlog ping"
+    Then the exit code should be 0
+    And stdout should contain "rate limit: 49/5000 remaining"
+    And stderr should contain "rate limit is running low"
+
+  @github
+  Scenario: An exhausted GitHub rate limit waits for the reset
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: github
+      workspaces:
+        github:
+          backend: github
+          repo: test-owner/test-repo
+          api_key_env: GITHUB_TOKEN
+          default: true
+      """
+    And the environment variable "GITHUB_TOKEN" is "ghp_valid_test_token"
+    And a mock GitHub API server is running
+    And the mock GitHub API rate limit has 0 of 5000 requests remaining, resetting in 2 seconds
+    When I run "backlog ping"
+    Then the exit code should be 0
+    And stdout should contain "github: healthy"
+    And stderr should contain "waiting for it to reset"
+
+  @github
+  Scenario: An exhausted GitHub rate limit fails fast with --no-wait
+    Given a fresh backlog directory
+    And a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: github
+      workspaces:
+        github:
+          backend: github
+          repo: test-owner/test-repo
+          api_key_env: GITHUB_TOKEN
+          default: true
+      """
+    And the environment variable "GITHUB_TOKEN" is "ghp_valid_test_token"
+    And a mock GitHub API server is running
+    And the mock GitHub API rate limit has 0 of 5000 requests remaining, resetting in 2 seconds
+    When I run "backlog list --no-wait"
+    Then the exit code should be 1
+    And stderr should contain "github API rate limit exhausted"
+    And stderr should not contain "waiting for it to reset"
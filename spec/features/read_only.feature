@@ -0,0 +1,73 @@
+Feature: Read-only Workspaces
+  As a user sharing a backlog with automation
+  I want to mark a workspace read-only
+  So that commands can read it but never change it by accident
+
+  Background:
+    Given a backlog with the following tasks:
+      | id    | title          | status | priority | assignee | labels  |
+      | task1 | Existing task  | todo   | high     |          | feature |
+    And a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        prod:
+          backend: local
+          path: ./.backlog
+          default: true
+          read_only: true
+      """
+
+  Scenario: Add fails fast in a read-only workspace
+    When I run "backlog add 'New task'"
+    Then the exit code should be 5
+    And stderr should contain "workspace 'prod' is read-only"
+    And stderr should contain "BACKLOG_ALLOW_WRITE=1"
+
+  Scenario: Mutating commands leave the task unchanged
+    When I run "backlog move task1 in-progress"
+    Then the exit code should be 5
+    And the task "task1" should have status "todo"
+    When I run "backlog claim task1"
+    Then the exit code should be 5
+    When I run "backlog comment task1 'Looks good'"
+    Then the exit code should be 5
+    When I run "backlog next --claim"
+    Then the exit code should be 5
+
+  Scenario: JSON error for a read-only workspace has a specific error code
+    When I run "backlog delete task1 -f json"
+    Then the exit code should be 5
+    And the JSON output should have "error.code" equal to "READ_ONLY"
+
+  Scenario: Read commands work normally
+    When I run "backlog list"
+    Then the exit code should be 0
+    And stdout should contain "Existing task"
+    When I run "backlog show task1"
+    Then the exit code should be 0
+    When I run "backlog next"
+    Then the exit code should be 0
+    And stdout should contain "task1"
+
+  Scenario: Dry runs are allowed in a read-only workspace
+    When I run "backlog move task1 in-progress --dry-run"
+    Then the exit code should be 0
+    And the task "task1" should have status "todo"
+
+  Scenario: BACKLOG_ALLOW_WRITE=1 allows writes for automation
+    Given the environment variable "BACKLOG_ALLOW_WRITE" is "1"
+    When I run "backlog move task1 in-progress"
+    Then the exit code should be 0
+    And the task "task1" should have status "in-progress"
+
+  Scenario: Override flag requires confirmation on a terminal
+    When I run "backlog move task1 in-progress --override-read-only"
+    Then the exit code should be 5
+    And stderr should contain "needs confirmation on a terminal"
+    And the task "task1" should have status "todo"
+
+  Scenario: Config get shows the read_only setting
+    When I run "backlog config get read_only"
+    Then the exit code should be 0
+    And stdout should contain "true"
@@ -62,8 +62,67 @@ Feature: Releasing Tasks
     Then the exit code should be 2
     And stderr should contain "claimed by different agent"
 
+  Scenario: Force release frees a task with another agent's active lock
+    Given the environment variable "BACKLOG_AGENT_ID" is "supervisor"
+    And task "task2" has an active lock from agent "other"
+    When I run "backlog release task2 --force --yes"
+    Then the exit code should be 0
+    And stdout should contain "Released"
+    And the task "task2" should have status "todo"
+    And the task "task2" should not have label "agent:other"
+    And the task "task2" should have assignee ""
+    And no lock file should exist for task "task2"
+    And the task "task2" should have comment containing "force-released from other by supervisor"
+
+  Scenario: Release of an expired lock still needs force while another agent's label remains
+    Given the environment variable "BACKLOG_AGENT_ID" is "supervisor"
+    And task "task2" has a lock from agent "other" that expired 5 minutes ago
+    When I run "backlog release task2"
+    Then the exit code should be 2
+    And stderr should contain "claimed by different agent"
+    When I run "backlog release task2 --force --yes"
+    Then the exit code should be 0
+    And the task "task2" should have status "todo"
+    And the task "task2" should not have label "agent:other"
+    And no lock file should exist for task "task2"
+    And the task "task2" should have comment containing "force-released from other by supervisor"
+
+  Scenario: Force release without a terminal requires --yes
+    Given the environment variable "BACKLOG_AGENT_ID" is "supervisor"
+    And task "task2" is claimed by agent "other"
+    When I run "backlog release task2 --force"
+    Then the exit code should be 1
+    And stderr should contain "confirmation required, pass --yes"
+    And the task "task2" should have label "agent:other"
+
+  Scenario: Force release of own task records no audit comment
+    Given the environment variable "BACKLOG_AGENT_ID" is "me"
+    And task "task1" is claimed by agent "me"
+    When I run "backlog release task1 --force --yes"
+    Then the exit code should be 0
+    And the task "task1" should have status "todo"
+    When I run "backlog show task1 --comments"
+    Then stdout should not contain "force-released"
+
   Scenario: Release unclaimed task fails
     Given the environment variable "BACKLOG_AGENT_ID" is "me"
     When I run "backlog release task3"
     Then the exit code should be 2
     And stderr should contain "not claimed"
+
+  Scenario: Release posts a comment when claim_comment is set
+    Given a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: .backlog
+          claim_comment: true
+          default: true
+      """
+    And the environment variable "BACKLOG_AGENT_ID" is "me"
+    And task "task1" is claimed by agent "me"
+    When I run "backlog release task1"
+    Then the exit code should be 0
+    And the task "task1" should have comment containing "released by agent `me` at"
@@ -0,0 +1,71 @@
+Feature: Reopening Tasks
+  As a user of the backlog CLI
+  I want to reopen completed tasks with a reason
+  So that there is a record of why finished work came back
+
+  Background:
+    Given a backlog with the following tasks:
+      | id    | title          | status      | priority |
+      | task1 | Shipped work   | done        | high     |
+      | task2 | Ongoing work   | in-progress | medium   |
+
+  Scenario: Reopen moves a done task to todo
+    When I run "backlog reopen task1 --reason='Regression in login flow'"
+    Then the exit code should be 0
+    And stdout should contain "task1"
+    And the task "task1" should have status "todo"
+    And the task "task1" should be in directory "todo"
+
+  Scenario: Reopen records the reason and agent as a comment
+    Given the environment variable "BACKLOG_AGENT_ID" is "agent-7"
+    When I run "backlog reopen task1 --reason='Regression in login flow'"
+    Then the exit code should be 0
+    And the task "task1" should have comment containing "Reopened by agent-7: Regression in login flow"
+
+  Scenario: Reopen to an explicit status
+    When I run "backlog reopen task1 --reason='Needs another look' --status=review"
+    Then the exit code should be 0
+    And the task "task1" should have status "review"
+
+  Scenario: Reopen uses the workspace default status
+    Given a config file with the following content:
+      """
+      version: 1
+      defaults:
+        workspace: local
+      workspaces:
+        local:
+          backend: local
+          path: ./.backlog
+          reopen_status: backlog
+          default: true
+      """
+    When I run "backlog reopen task1 --reason='Deprioritized follow-up'"
+    Then the exit code should be 0
+    And the task "task1" should have status "backlog"
+
+  Scenario: Reopen with JSON output
+    When I run "backlog reopen task1 --reason='Regression' -f json"
+    Then the exit code should be 0
+    And the JSON output should have "status" equal to "todo"
+
+  Scenario: Reopen refuses a task that is not done
+    When I run "backlog reopen task2 --reason='Not finished'"
+    Then the exit code should be 2
+    And stderr should contain "not done"
+    And the task "task2" should have status "in-progress"
+
+  Scenario: Reopen requires a reason
+    When I run "backlog reopen task1"
+    Then the exit code should be 1
+    And stderr should contain "--reason"
+    And the task "task1" should have status "done"
+
+  Scenario: Reopen rejects done as the target status
+    When I run "backlog reopen task1 --reason='Oops' --status=done"
+    Then the exit code should be 1
+    And stderr should contain "invalid reopen status"
+
+  Scenario: Reopen non-existent task returns exit code 3
+    When I run "backlog reopen nonexistent --reason='Missing'"
+    Then the exit code should be 3
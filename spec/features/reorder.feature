@@ -15,6 +15,8 @@ Feature: Reordering Tasks
     When I run "backlog reorder task3 --first"
     Then the exit code should be 0
     And stdout should contain "task3"
+    And the task "task3" should be ordered before "task1"
+    And the task "task1" should be ordered before "task2"
     When I run "backlog list --status=todo -f json"
     Then the exit code should be 0
     And the JSON output should have "tasks[0].id" equal to "task3"
@@ -31,6 +33,7 @@ Feature: Reordering Tasks
     When I run "backlog reorder task3 --before task1"
     Then the exit code should be 0
     And stdout should contain "task3"
+    And the task "task3" should be ordered before "task1"
     When I run "backlog list --status=todo -f json"
     Then the exit code should be 0
     And the JSON output should have "tasks[0].id" equal to "task3"
@@ -46,10 +49,47 @@ Feature: Reordering Tasks
     And the JSON output should have "tasks[1].id" equal to "task3"
     And the JSON output should have "tasks[2].id" equal to "task1"
 
+  Scenario: Reorder task to an absolute position
+    When I run "backlog reorder task3 --to 2"
+    Then the exit code should be 0
+    And stdout should contain "task3"
+    When I run "backlog list --status=todo -f json"
+    Then the exit code should be 0
+    And the JSON output should have "tasks[0].id" equal to "task1"
+    And the JSON output should have "tasks[1].id" equal to "task3"
+    And the JSON output should have "tasks[2].id" equal to "task2"
+
+  Scenario: Reorder task to the first and last positions by number
+    When I run "backlog reorder task3 --to 1"
+    Then the exit code should be 0
+    When I run "backlog reorder task1 --to 3"
+    Then the exit code should be 0
+    When I run "backlog list --status=todo -f json"
+    Then the exit code should be 0
+    And the JSON output should have "tasks[0].id" equal to "task3"
+    And the JSON output should have "tasks[1].id" equal to "task2"
+    And the JSON output should have "tasks[2].id" equal to "task1"
+
+  Scenario: Reorder to a position past the end of the group fails
+    When I run "backlog reorder task1 --to 4"
+    Then the exit code should be 1
+    And stderr should contain "position 4 is out of range"
+    And stderr should contain "valid: 1-3"
+
+  Scenario: Reorder to a negative position fails
+    When I run "backlog reorder task1 --to -1"
+    Then the exit code should be 1
+    And stderr should contain "--to must be a position of 1 or more"
+
+  Scenario: Reorder with --to and another position flag fails
+    When I run "backlog reorder task1 --to 2 --first"
+    Then the exit code should be 1
+    And stderr should contain "only one of"
+
   Scenario: Reorder with no position flag fails
     When I run "backlog reorder task1"
     Then the exit code should be 1
-    And stderr should contain "one of --before, --after, --first, or --last is required"
+    And stderr should contain "one of --before, --after, --first, --last, or --to is required"
 
   Scenario: Reorder with multiple position flags fails
     When I run "backlog reorder task1 --first --last"
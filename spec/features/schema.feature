@@ -0,0 +1,35 @@
+Feature: JSON Schema
+  As an agent framework consuming backlog's JSON output
+  I want a JSON Schema of the task objects
+  So that I can validate responses before acting on them
+
+  Scenario: Schema describes tasks, task input, and task lists
+    Given a fresh backlog directory
+    When I run "backlog schema"
+    Then the exit code should be 0
+    And the JSON output should have "$schema" equal to "https://json-schema.org/draft/2020-12/schema"
+    And the JSON output should have "$defs.Task" as an object
+    And the JSON output should have "$defs.TaskInput" as an object
+    And the JSON output should have "$defs.TaskList.properties.tasks.items.$ref" equal to "#/$defs/Task"
+    And the JSON output should have array "$defs.TaskInput.required" containing "title"
+
+  Scenario: Status and priority are enumerations
+    Given a fresh backlog directory
+    When I run "backlog schema task"
+    Then the exit code should be 0
+    And the JSON output should have "$ref" equal to "#/$defs/Task"
+    And the JSON output should have array "$defs.Task.properties.status.enum" containing "in-progress"
+    And the JSON output should have array "$defs.Task.properties.priority.enum" containing "urgent"
+    And the JSON output should have array "$defs.Task.required" containing "assignee"
+    And the JSON output should have "$defs.Task.properties.created.format" equal to "date-time"
+
+  Scenario: Schema doesn't need a backlog
+    When I run "backlog schema task-list"
+    Then the exit code should be 0
+    And the JSON output should have "$ref" equal to "#/$defs/TaskList"
+
+  Scenario: Unknown schema name
+    Given a fresh backlog directory
+    When I run "backlog schema comment"
+    Then the exit code should be 1
+    And stderr should contain "task-input"
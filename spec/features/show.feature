@@ -49,6 +49,43 @@ Feature: Showing Tasks
     And stdout should contain "@alex"
     And stdout should contain "@bot"
 
+  Scenario: Show comments since a time
+    Given a backlog with the following tasks:
+      | id    | title           | status      | priority | assignee | labels        | description                  |
+      | task1 | Implement auth  | in-progress | high     | alex     | feature,auth  | OAuth2 implementation needed |
+    And task "task1" has the following comments:
+      | author | date       | body                                |
+      | alex   | 2025-01-16 | Started research on OAuth providers |
+      | bot    | 2025-01-17 | Found relevant documentation        |
+      | alex   | 2025-01-18 | Picked a provider                   |
+    When I run "backlog show task1 --comments --since 2025-01-17T15:00:00Z"
+    Then the exit code should be 0
+    And stdout should contain "Found relevant documentation"
+    And stdout should contain "Picked a provider"
+    And stdout should not contain "Started research on OAuth providers"
+
+  Scenario: Show only the most recent comments
+    Given a backlog with the following tasks:
+      | id    | title           | status      | priority | assignee | labels        | description                  |
+      | task1 | Implement auth  | in-progress | high     | alex     | feature,auth  | OAuth2 implementation needed |
+    And task "task1" has the following comments:
+      | author | date       | body                                |
+      | alex   | 2025-01-16 | Started research on OAuth providers |
+      | bot    | 2025-01-17 | Found relevant documentation        |
+      | alex   | 2025-01-18 | Picked a provider                   |
+    When I run "backlog show task1 --comments --limit 1 -f json"
+    Then the exit code should be 0
+    And the JSON output array "comments" should have length 1
+    And stdout should contain "Picked a provider"
+
+  Scenario: Show --since without --comments fails
+    Given a backlog with the following tasks:
+      | id    | title          | status | priority | assignee | labels | description |
+      | task1 | Implement auth | todo   | high     |          |        |             |
+    When I run "backlog show task1 --since 2d"
+    Then the exit code should be 1
+    And stderr should contain "require --comments"
+
   Scenario: Show non-existent task returns exit code 3
     Given a fresh backlog directory
     When I run "backlog show nonexistent-task"
@@ -70,3 +107,53 @@ Feature: Showing Tasks
     When I run "backlog show task3"
     Then the exit code should be 0
     And stdout should contain "done"
+
+  Scenario: Show raw prints the task file unmodified
+    Given a backlog with the following tasks:
+      | id    | title          | status      | priority | assignee | labels       | description                  |
+      | task1 | Implement auth | in-progress | high     | alex     | feature,auth | OAuth2 implementation needed |
+    When I run "backlog show task1 --raw"
+    Then the exit code should be 0
+    And stdout should match pattern "^---\n"
+    And stdout should contain "title: Implement auth"
+    And stdout should contain "priority: high"
+    And stdout should contain "OAuth2 implementation needed"
+    And stdout should not contain "Status:"
+
+  Scenario: Show task as a markdown document
+    Given a backlog with the following tasks:
+      | id    | title          | status      | priority | assignee | labels       | description                  |
+      | task1 | Implement auth | in-progress | high     | alex     | feature,auth | OAuth2 implementation needed |
+      | task2 | Set up OAuth   | done        | medium   |          |              |                              |
+    And I run "backlog link task2 --blocks task1"
+    When I run "backlog show task1 -f markdown"
+    Then the exit code should be 0
+    And stdout should match pattern "^# Implement auth \(task1\)\n"
+    And stdout should contain "| Status | in-progress |"
+    And stdout should contain "| Priority | high |"
+    And stdout should contain "| Assignee | @alex |"
+    And stdout should contain "| Labels | feature, auth |"
+    And stdout should contain "## Description"
+    And stdout should contain "OAuth2 implementation needed"
+    And stdout should contain "- Blocked by **task2**: Set up OAuth (done)"
+    And stdout should not contain "## Comments"
+    And stdout should not contain "━"
+
+  Scenario: Show task as markdown with comments
+    Given a backlog with the following tasks:
+      | id    | title          | status      | priority | assignee | labels | description |
+      | task1 | Implement auth | in-progress | high     | alex     |        |             |
+    And task "task1" has the following comments:
+      | author | date       | body                                |
+      | alex   | 2025-01-16 | Started research on OAuth providers |
+    When I run "backlog show task1 --comments -f markdown"
+    Then the exit code should be 0
+    And stdout should contain "## Comments"
+    And stdout should contain "### @alex, 2025-01-16"
+    And stdout should contain "Started research on OAuth providers"
+
+  Scenario: Show raw for non-existent task returns exit code 3
+    Given a fresh backlog directory
+    When I run "backlog show nonexistent-task --raw"
+    Then the exit code should be 3
+    And stderr should contain "not found"
@@ -0,0 +1,84 @@
+Feature: Sub-tasks
+  As a user of the backlog CLI
+  I want to break tasks down into sub-tasks
+  So that I can track the parts of larger pieces of work
+
+  Background:
+    Given a backlog with the following tasks:
+      | id  | title          | status      | priority |
+      | 012 | Payments epic  | in-progress | high     |
+      | 013 | Card form      | todo        | medium   |
+      | 014 | Refund flow    | done        | medium   |
+      | 015 | Unrelated task | todo        | low      |
+
+  Scenario: Add a sub-task with --parent
+    When I run "backlog add 'Receipt emails' --parent 012"
+    Then the exit code should be 0
+    When I run "backlog show 016 -f json"
+    Then the JSON output should have "parent" equal to "012"
+
+  Scenario: Add fails when the parent does not exist
+    When I run "backlog add 'Orphan' --parent 999"
+    Then the exit code should be 1
+    And stderr should contain "parent task not found"
+
+  Scenario: Show lists sub-tasks with their statuses
+    When I run "backlog edit 013 --parent 012"
+    And I run "backlog edit 014 --parent 012"
+    And I run "backlog show 012"
+    Then the exit code should be 0
+    And stdout should contain "## Sub-tasks"
+    And stdout should contain "013  todo  Card form"
+    And stdout should contain "014  done  Refund flow"
+
+  Scenario: Show includes sub-tasks in JSON output
+    When I run "backlog edit 013 --parent 012"
+    And I run "backlog show 012 -f json"
+    Then the JSON output should have "children[0].id" equal to "013"
+    And the JSON output should have "children[0].status" equal to "todo"
+
+  Scenario: Show displays the parent of a sub-task
+    When I run "backlog edit 013 --parent 012"
+    And I run "backlog show 013"
+    Then stdout should contain "Parent:    012"
+
+  Scenario: List filters to sub-tasks of a parent
+    When I run "backlog edit 013 --parent 012"
+    And I run "backlog edit 014 --parent 012"
+    And I run "backlog list --parent 012 --include-done -f json"
+    Then the exit code should be 0
+    And the JSON output should have array length "tasks" equal to 2
+    And stdout should not contain "Unrelated task"
+
+  Scenario: Clear the parent with an empty --parent
+    When I run "backlog edit 013 --parent 012"
+    And I run "backlog edit 013 --parent ''"
+    And I run "backlog list --parent 012 -f json"
+    Then the JSON output should have array length "tasks" equal to 0
+
+  Scenario: A task cannot become a sub-task of its own sub-task
+    When I run "backlog edit 013 --parent 012"
+    And I run "backlog edit 012 --parent 013"
+    Then the exit code should be 1
+    And stderr should contain "cannot be a sub-task of itself or its own sub-tasks"
+
+  Scenario: Moving a parent to done with open sub-tasks warns
+    When I run "backlog edit 013 --parent 012"
+    And I run "backlog edit 014 --parent 012"
+    And I run "backlog move 012 done"
+    Then the exit code should be 0
+    And stderr should contain "warning: task 012 has 1 open sub-task(s): 013"
+    And the task "012" should have status "done"
+
+  Scenario: Moving a parent to done with open sub-tasks fails with --strict
+    When I run "backlog edit 013 --parent 012"
+    And I run "backlog move 012 done --strict"
+    Then the exit code should be 2
+    And stderr should contain "task 012 has 1 open sub-task(s): 013"
+    And the task "012" should have status "in-progress"
+
+  Scenario: Moving a parent to done with finished sub-tasks succeeds with --strict
+    When I run "backlog edit 014 --parent 012"
+    And I run "backlog move 012 done --strict"
+    Then the exit code should be 0
+    And stderr should be empty
@@ -0,0 +1,102 @@
+Feature: Task Templates
+  As a user of the backlog CLI
+  I want to create tasks from reusable templates
+  So that repetitive tasks like bug reports share a consistent structure
+
+  Background:
+    Given a fresh backlog directory
+    And a file ".backlog/templates/bug.md" with the following content:
+      """
+      ---
+      title: "Bug: {{summary}}"
+      priority: high
+      status: todo
+      labels: [bug]
+      ---
+
+      ## Description
+
+      Steps to reproduce:
+
+      {{steps}}
+      """
+    And a file ".backlog/templates/review.md" with the following content:
+      """
+      ---
+      labels: [review]
+      ---
+
+      Review checklist for {{pr}}.
+      """
+
+  Scenario: Add task from template with placeholders
+    When I run "backlog add --template bug --set summary='Login fails' --set 'steps=Submit the form'"
+    Then the exit code should be 0
+    And the task "001" should have title "Bug: Login fails"
+    And the task "001" should have priority "high"
+    And the task "001" should have status "todo"
+    And the task "001" should have label "bug"
+    And the task "001" should have description containing:
+      """
+      Steps to reproduce:
+
+      Submit the form
+      """
+
+  Scenario: Command-line fields override template defaults
+    When I run "backlog add 'Crash on startup' --template bug --set summary=x --set steps=y --priority=urgent --status=backlog --label=crash"
+    Then the exit code should be 0
+    And the task "001" should have title "Crash on startup"
+    And the task "001" should have priority "urgent"
+    And the task "001" should have status "backlog"
+    And the task "001" should have label "bug"
+    And the task "001" should have label "crash"
+
+  Scenario: Template without a title requires a title argument
+    When I run "backlog add --template review --set pr=42"
+    Then the exit code should be 1
+    And stderr should contain "title is required"
+
+  Scenario: Template with a title argument
+    When I run "backlog add 'Review PR 42' --template review --set pr=#42"
+    Then the exit code should be 0
+    And the task "001" should have title "Review PR 42"
+    And the task "001" should have description containing "Review checklist for #42."
+
+  Scenario: Missing placeholder values fail with a clear error
+    When I run "backlog add --template bug --set summary='Login fails'"
+    Then the exit code should be 1
+    And stderr should contain "missing values for: steps"
+    And the task count should be 0
+
+  Scenario: Unknown template returns exit code 3
+    When I run "backlog add 'Task' --template nonexistent"
+    Then the exit code should be 3
+    And stderr should contain "nonexistent"
+    And stderr should contain "not found"
+
+  Scenario: Set without template is rejected
+    When I run "backlog add 'Task' --set key=value"
+    Then the exit code should be 1
+    And stderr should contain "--set requires --template"
+
+  Scenario: List templates
+    When I run "backlog template list"
+    Then the exit code should be 0
+    And stdout should contain "NAME"
+    And stdout should contain "bug"
+    And stdout should contain "Bug: {{summary}}"
+    And stdout should contain "steps, summary"
+    And stdout should contain "review"
+
+  Scenario: List templates as JSON
+    When I run "backlog template list -f json"
+    Then the exit code should be 0
+    And the JSON output should have "count" equal to "2"
+    And the JSON output should have "templates[0].name" equal to "bug"
+    And the JSON output should have "templates[1].name" equal to "review"
+
+  Scenario: Templates are not listed as tasks
+    When I run "backlog list"
+    Then the exit code should be 0
+    And stdout should contain "No tasks found."
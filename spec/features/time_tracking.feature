@@ -0,0 +1,204 @@
+Feature: Time Tracking
+  As a user of the backlog CLI
+  I want to record estimates and time spent on tasks
+  So that I can compare planned effort against actual effort
+
+  Background:
+    Given a backlog with the following tasks:
+      | id    | title           | status      | priority |
+      | task1 | Estimate me     | todo        | medium   |
+      | task2 | Track my time   | in-progress | high     |
+
+  Scenario: Set an estimate on a task
+    When I run "backlog estimate task1 1d"
+    Then the exit code should be 0
+    And stdout should contain "task1"
+    When I run "backlog show task1 -f json"
+    Then the JSON output should have "estimate" equal to "1d"
+
+  Scenario: Estimate accepts week shorthand combined with other units
+    When I run "backlog estimate task1 1w2d"
+    Then the exit code should be 0
+    When I run "backlog show task1 -f json"
+    Then the JSON output should have "estimate" equal to "9d"
+
+  Scenario: Track time accumulates spent time
+    When I run "backlog track task2 --spent 2h"
+    Then the exit code should be 0
+    When I run "backlog track task2 --spent 30m"
+    Then the exit code should be 0
+    When I run "backlog show task2 -f json"
+    Then the JSON output should have "spent" equal to "2h30m"
+
+  Scenario: Time tracking fields are persisted in the task file
+    When I run "backlog estimate task1 4h"
+    And I run "backlog track task1 --spent 1h"
+    Then the file ".backlog/todo/task1-estimate-me.md" should contain "estimate: 4h"
+    And the file ".backlog/todo/task1-estimate-me.md" should contain "spent: 1h"
+
+  Scenario: List JSON output includes time tracking fields
+    When I run "backlog estimate task1 1d"
+    And I run "backlog list -f json"
+    Then the exit code should be 0
+    And the JSON output should have "tasks[1].id" equal to "task1"
+    And the JSON output should have "tasks[1].estimate" equal to "1d"
+
+  Scenario: Invalid duration returns an error
+    When I run "backlog track task2 --spent soon"
+    Then the exit code should be 1
+    And stderr should contain "invalid duration"
+
+  Scenario: Track requires the --spent flag
+    When I run "backlog track task2"
+    Then the exit code should be 1
+    And stderr should contain "spent"
+
+  Scenario: Track time on non-existent task returns exit code 3
+    When I run "backlog track nonexistent --spent 1h"
+    Then the exit code should be 3
+    And stderr should contain "not found"
+
+  Scenario: Claiming a task records when it started
+    When I run "backlog claim task1 --agent-id=agent-1"
+    Then the exit code should be 0
+    And the file ".backlog/in-progress/task1-estimate-me.md" should contain "started_at:"
+    When I run "backlog show task1 -f json"
+    Then the JSON output should have "started_at" matching pattern "^\d{4}-\d{2}-\d{2}T"
+
+  Scenario: Moving a claimed task to done records completion and duration
+    When I run "backlog claim task1 --agent-id=agent-1"
+    And I run "backlog move task1 done"
+    Then the exit code should be 0
+    And the file ".backlog/done/task1-estimate-me.md" should contain "completed_at:"
+    When I run "backlog show task1 -f json"
+    Then the JSON output should have "completed_at" matching pattern "^\d{4}-\d{2}-\d{2}T"
+
+  Scenario: Show and list expose recorded times and the computed duration
+    Given a file ".backlog/done/task3-finished.md" with the following content:
+      """
+      ---
+      id: task3
+      title: Finished
+      priority: medium
+      labels:
+        - bug
+      created: 2025-01-09T09:00:00Z
+      updated: 2025-01-11T11:30:00Z
+      started_at: 2025-01-10T09:00:00Z
+      completed_at: 2025-01-11T11:30:00Z
+      ---
+      """
+    When I run "backlog show task3 -f json"
+    Then the JSON output should have "started_at" equal to "2025-01-10T09:00:00Z"
+    And the JSON output should have "completed_at" equal to "2025-01-11T11:30:00Z"
+    And the JSON output should have "duration" equal to "1d2h30m"
+    When I run "backlog list --status done -f json"
+    Then the JSON output should have "tasks[0].duration" equal to "1d2h30m"
+
+  Scenario: Re-claiming a released task keeps its original start time
+    Given a file ".backlog/todo/task3-released.md" with the following content:
+      """
+      ---
+      id: task3
+      title: Released
+      priority: medium
+      created: 2025-01-09T09:00:00Z
+      updated: 2025-01-10T12:00:00Z
+      started_at: 2025-01-10T09:00:00Z
+      ---
+      """
+    When I run "backlog claim task3 --agent-id=agent-1"
+    Then the exit code should be 0
+    And the file ".backlog/in-progress/task3-released.md" should contain "started_at: 2025-01-10T09:00:00Z"
+
+  Scenario: Re-claiming with --restart-clock resets the start time
+    Given a file ".backlog/todo/task3-released.md" with the following content:
+      """
+      ---
+      id: task3
+      title: Released
+      priority: medium
+      created: 2025-01-09T09:00:00Z
+      updated: 2025-01-10T12:00:00Z
+      started_at: 2025-01-10T09:00:00Z
+      ---
+      """
+    When I run "backlog claim task3 --agent-id=agent-1 --restart-clock"
+    Then the exit code should be 0
+    And the file ".backlog/in-progress/task3-released.md" should contain "started_at:"
+    And the file ".backlog/in-progress/task3-released.md" should not contain "started_at: 2025-01-10T09:00:00Z"
+
+  Scenario: List can be sorted by duration
+    Given a file ".backlog/done/task3-short.md" with the following content:
+      """
+      ---
+      id: task3
+      title: Short
+      priority: urgent
+      created: 2025-01-09T09:00:00Z
+      updated: 2025-01-10T10:00:00Z
+      started_at: 2025-01-10T09:00:00Z
+      completed_at: 2025-01-10T10:00:00Z
+      ---
+      """
+    And a file ".backlog/done/task4-long.md" with the following content:
+      """
+      ---
+      id: task4
+      title: Long
+      priority: low
+      created: 2025-01-09T09:00:00Z
+      updated: 2025-01-12T09:00:00Z
+      started_at: 2025-01-10T09:00:00Z
+      completed_at: 2025-01-12T09:00:00Z
+      ---
+      """
+    When I run "backlog list --status done --sort -duration -f json"
+    Then the exit code should be 0
+    And the JSON output should have "tasks[0].id" equal to "task4"
+    And the JSON output should have "tasks[1].id" equal to "task3"
+
+  Scenario: Invalid sort field returns an error
+    When I run "backlog list --sort size"
+    Then the exit code should be 1
+    And stderr should contain "invalid sort field"
+
+  Scenario: Time report totals completed tasks overall and per label
+    Given a file ".backlog/done/task3-short.md" with the following content:
+      """
+      ---
+      id: task3
+      title: Short
+      priority: medium
+      labels:
+        - bug
+      created: 2025-01-09T09:00:00Z
+      updated: 2025-01-10T11:00:00Z
+      started_at: 2025-01-10T09:00:00Z
+      completed_at: 2025-01-10T11:00:00Z
+      ---
+      """
+    And a file ".backlog/done/task4-long.md" with the following content:
+      """
+      ---
+      id: task4
+      title: Long
+      priority: medium
+      labels:
+        - bug
+        - ui
+      created: 2025-01-09T09:00:00Z
+      updated: 2025-01-10T13:00:00Z
+      started_at: 2025-01-10T09:00:00Z
+      completed_at: 2025-01-10T13:00:00Z
+      ---
+      """
+    When I run "backlog time -f json"
+    Then the exit code should be 0
+    And the JSON output should have "tasks" equal to "2"
+    And the JSON output should have "total" equal to "6h"
+    And the JSON output should have "average" equal to "3h"
+    And the JSON output should have "labels[0].label" equal to "bug"
+    And the JSON output should have "labels[0].total" equal to "6h"
+    And the JSON output should have "labels[1].label" equal to "ui"
+    And the JSON output should have "labels[1].total" equal to "4h"
@@ -0,0 +1,98 @@
+Feature: Undo
+  As an agent working in a local backlog
+  I want to revert my last change
+  So that a mistaken edit, move, or delete doesn't need to be repaired by hand
+
+  Background:
+    Given a backlog with the following tasks:
+      | id    | title          | status | priority | description      |
+      | task1 | Fix login      | todo   | high     | Original details |
+      | task2 | Write the docs | todo   | low      |                  |
+
+  Scenario: Undo reverts an edit
+    When I run "backlog edit task1 --priority=urgent --description 'New details'"
+    Then the exit code should be 0
+    When I run "backlog undo"
+    Then the exit code should be 0
+    And stdout should contain "Reverted edit task1"
+    And the task "task1" should have priority "high"
+    And the task "task1" should have description containing "Original details"
+
+  Scenario: Undo moves a task back to its old status
+    When I run "backlog move task1 in-progress"
+    Then the exit code should be 0
+    When I run "backlog undo"
+    Then the exit code should be 0
+    And the task "task1" should be in directory "todo"
+
+  Scenario: Undo recreates a deleted task
+    When I run "backlog delete task1 --yes"
+    Then the exit code should be 0
+    When I run "backlog undo -f json"
+    Then the exit code should be 0
+    And the JSON output should have "action" equal to "delete"
+    And the JSON output should have "task_id" equal to "task1"
+    And the JSON output should have "undone" equal to "true"
+    And the task "task1" should have title "Fix login"
+
+  Scenario: Repeated undo walks back through earlier changes
+    When I run "backlog move task1 in-progress"
+    And I run "backlog edit task2 --priority=urgent"
+    And I run "backlog undo"
+    And I run "backlog undo"
+    Then the exit code should be 0
+    And the task "task1" should have status "todo"
+    And the task "task2" should have priority "low"
+
+  Scenario: List the changes that can be undone
+    When I run "backlog move task1 in-progress"
+    And I run "backlog edit task2 --priority=urgent"
+    And I run "backlog undo --list -f json"
+    Then the exit code should be 0
+    And the JSON output should have array length "entries" equal to 2
+    And the JSON output should have "entries[0].action" equal to "edit"
+    And the JSON output should have "entries[0].task_id" equal to "task2"
+    And the JSON output should have "entries[1].action" equal to "move"
+
+  Scenario: Nothing to undo
+    When I run "backlog undo"
+    Then the exit code should be 3
+    And stderr should contain "nothing to undo"
+
+  Scenario: Undo refuses to overwrite a file changed since
+    When I run "backlog edit task1 --priority=urgent"
+    And a file ".backlog/todo/task1-fix-login.md" with the following content:
+      """
+      ---
+      id: task1
+      title: Fix login
+      priority: medium
+      ---
+      Edited by hand
+      """
+    And I run "backlog undo"
+    Then the exit code should be 2
+    And stderr should contain "has changed since"
+    And the task "task1" should have priority "medium"
+
+  Scenario: Undo commits the revert when git_sync is enabled
+    Given a git repository is initialized
+    And git_sync is enabled in the config
+    When I run "backlog move task1 done"
+    And I run "backlog undo"
+    Then the exit code should be 0
+    And the task "task1" should have status "todo"
+    And the last git commit message should match pattern "^undo: move task1$"
+
+  @git-remote
+  Scenario: Undo after a sync that changed the task is refused
+    Given a git repository is initialized
+    And git_sync is enabled in the config
+    And a remote git repository
+    When I run "backlog edit task1 --priority=urgent"
+    And the remote changes the title of task "task1" to "Remote title"
+    And I run "backlog sync"
+    And I run "backlog undo"
+    Then the exit code should be 2
+    And stderr should contain "has changed since"
+    And the task "task1" should have title "Remote title"
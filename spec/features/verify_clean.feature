@@ -0,0 +1,57 @@
+Feature: Verify Clean
+  As an agent committing source changes in a repository with a backlog
+  I want to check that no backlog changes are left uncommitted
+  So that task state doesn't drift from what is committed
+
+  Background:
+    Given a backlog with the following tasks:
+      | id    | title          | status | priority |
+      | task1 | Fix login      | todo   | high     |
+      | task2 | Write the docs | todo   | low      |
+
+  Scenario: A committed backlog is clean
+    Given a git repository is initialized
+    And git_sync is enabled in the config
+    When I run "backlog edit task1 --priority=urgent"
+    And I run "backlog verify-clean"
+    Then the exit code should be 0
+    And stdout should contain "Backlog is clean"
+
+  Scenario: Changes outside the backlog don't count
+    Given a git repository is initialized
+    And git_sync is enabled in the config
+    And there are uncommitted changes in the repository
+    When I run "backlog verify-clean"
+    Then the exit code should be 0
+    And stdout should not contain "uncommitted.txt"
+
+  Scenario: A hand-edited task file is reported
+    Given a git repository is initialized
+    And git_sync is enabled in the config
+    And a file ".backlog/todo/task1-fix-login.md" with the following content:
+      """
+      ---
+      id: task1
+      title: Fix login
+      priority: medium
+      ---
+      Edited by hand
+      """
+    When I run "backlog verify-clean"
+    Then the exit code should be 1
+    And stdout should contain "1 uncommitted backlog file(s)"
+    And stdout should contain ".backlog/todo/task1-fix-login.md"
+
+  Scenario: Changes made without git_sync are reported as JSON
+    Given a git repository is initialized
+    And git_sync is disabled in the config
+    When I run "backlog add 'New task'"
+    And I run "backlog verify-clean -f json"
+    Then the exit code should be 1
+    And the JSON output should have "clean" equal to "false"
+    And stdout should contain "-new-task.md"
+
+  Scenario: A backlog outside a git repository can't be verified
+    When I run "backlog verify-clean"
+    Then the exit code should be 1
+    And stderr should contain "not in a git repository"
@@ -0,0 +1,144 @@
+Feature: Agent Identity
+  As an agent using the backlog CLI
+  I want a stable agent ID even when none is configured
+  So that my claims, locks, and comments are always attributed to me
+
+  Background:
+    Given a backlog with the following tasks:
+      | id    | title          | status | priority |
+      | task1 | Unclaimed task | todo   | high     |
+    And the environment variable "BACKLOG_AGENT_ID" is not set
+
+  Scenario: Whoami generates and persists an agent ID on first use
+    When I run "backlog whoami"
+    Then the exit code should be 0
+    And stdout should contain the generated agent ID
+    And stdout should contain "generated"
+    And stdout should contain "agent-id"
+    And stdout should contain "Claim label:"
+
+  Scenario: The generated agent ID is reused across commands
+    Given a generated agent ID "build-box-3f9a1c" exists
+    When I run "backlog claim task1"
+    Then the exit code should be 0
+    And the task "task1" should have label "agent:build-box-3f9a1c"
+    When I run "backlog whoami -f id-only"
+    Then stdout should be "build-box-3f9a1c"
+
+  Scenario: Claim without a configured agent ID uses the generated one
+    When I run "backlog claim task1"
+    Then the exit code should be 0
+    And the task "task1" should have the generated agent label
+
+  Scenario: Whoami reports the environment variable as the source
+    Given the environment variable "BACKLOG_AGENT_ID" is "env-agent"
+    When I run "backlog whoami -f json"
+    Then the exit code should be 0
+    And the JSON output should have "agent_id" equal to "env-agent"
+    And the JSON output should have "source" equal to "env"
+    And the JSON output should have "origin" equal to "BACKLOG_AGENT_ID"
+    And the JSON output should have "label" equal to "agent:env-agent"
+
+  Scenario: The --agent-id flag takes precedence over the environment
+    Given the environment variable "BACKLOG_AGENT_ID" is "env-agent"
+    When I run "backlog whoami --agent-id=flag-agent -f json"
+    Then the exit code should be 0
+    And the JSON output should have "agent_id" equal to "flag-agent"
+    And the JSON output should have "source" equal to "flag"
+
+  Scenario: Whoami shows the workspace agent ID and label prefix
+    Given a config file with the following content:
+      """
+      version: 1
+      defaults:
+        agent_id: global-agent
+      workspaces:
+        main:
+          backend: local
+          path: .backlog
+          agent_id: workspace-agent
+          agent_label_prefix: bot
+          default: true
+      """
+    When I run "backlog whoami -f json"
+    Then the exit code should be 0
+    And the JSON output should have "agent_id" equal to "workspace-agent"
+    And the JSON output should have "source" equal to "config"
+    And the JSON output should have "origin" equal to "workspaces.main.agent_id"
+    And the JSON output should have "workspace" equal to "main"
+    And the JSON output should have "agent_label_prefix" equal to "bot"
+    And the JSON output should have "label" equal to "bot:workspace-agent"
+
+  Scenario: Whoami falls back to the global default agent ID
+    Given a config file with the following content:
+      """
+      version: 1
+      defaults:
+        agent_id: global-agent
+      workspaces:
+        main:
+          backend: local
+          path: .backlog
+          default: true
+      """
+    When I run "backlog whoami -f json"
+    Then the exit code should be 0
+    And the JSON output should have "agent_id" equal to "global-agent"
+    And the JSON output should have "origin" equal to "defaults.agent_id"
+
+  Scenario: Claim refuses to run without an agent ID
+    Given the environment variable "HOME" is not set
+    When I run "backlog claim task1"
+    Then the exit code should be 4
+    And stderr should contain "no agent ID"
+    And the task "task1" should not have label "agent:"
+    And the task "task1" should have status "todo"
+
+  Scenario: Release and comment refuse to run without an agent ID
+    Given the environment variable "HOME" is not set
+    When I run "backlog release task1"
+    Then the exit code should be 4
+    And stderr should contain "no agent ID"
+    When I run "backlog comment task1 'Looking into it'"
+    Then the exit code should be 4
+    And stderr should contain "no agent ID"
+
+  Scenario: The agent ID is read from agent_id_file
+    Given a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: .backlog
+          agent_id: workspace-agent
+          agent_id_file: secrets/agent-id
+          default: true
+      """
+    And a file "secrets/agent-id" with the following content:
+      """
+      file-agent
+      """
+    When I run "backlog whoami -f json"
+    Then the exit code should be 0
+    And the JSON output should have "agent_id" equal to "file-agent"
+    And the JSON output should have "source" equal to "file"
+    And the JSON output should have "origin" equal to "secrets/agent-id"
+    Given the environment variable "BACKLOG_AGENT_ID" is "env-agent"
+    When I run "backlog whoami -f json"
+    Then the JSON output should have "agent_id" equal to "env-agent"
+
+  Scenario: A missing agent_id_file is a configuration error
+    Given a config file with the following content:
+      """
+      version: 1
+      workspaces:
+        main:
+          backend: local
+          path: .backlog
+          agent_id_file: secrets/missing
+          default: true
+      """
+    When I run "backlog claim task1"
+    Then the exit code should be 4
+    And stderr should contain "agent_id_file"
@@ -1,8 +1,10 @@
 package spec
 
 import (
+	"flag"
 	"io"
 	"os"
+	"strconv"
 	"testing"
 
 	"github.com/cucumber/godog"
@@ -43,7 +45,7 @@ func TestFeatures(t *testing.T) {
 		Format:      format,
 		Paths:       []string{"features"},
 		Randomize:   0,
-		Concurrency: 1,
+		Concurrency: concurrency(),
 		Tags:        tags,
 	}
 
@@ -61,3 +63,20 @@ func InitializeScenario(ctx *godog.ScenarioContext) {
 	// Register common step definitions
 	steps.InitializeCommonSteps(ctx)
 }
+
+// concurrency returns how many scenarios run at once. It follows go test's
+// -parallel flag, which defaults to GOMAXPROCS, so "go test -parallel 1"
+// runs the suite serially. GODOG_CONCURRENCY overrides it.
+func concurrency() int {
+	if env := os.Getenv("GODOG_CONCURRENCY"); env != "" {
+		if n, err := strconv.Atoi(env); err == nil && n > 0 {
+			return n
+		}
+	}
+	if f := flag.Lookup("test.parallel"); f != nil {
+		if n, err := strconv.Atoi(f.Value.String()); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 1
+}
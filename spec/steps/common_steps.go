@@ -4,6 +4,7 @@ package steps
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/cucumber/godog"
 
+	"github.com/alexbrand/backlog/internal/backend"
 	"github.com/alexbrand/backlog/spec/support"
 )
 
@@ -23,8 +25,27 @@ const (
 	testEnvKey    contextKey = "testEnv"
 	cliRunnerKey  contextKey = "cliRunner"
 	lastResultKey contextKey = "lastResult"
+	gitRemoteKey  contextKey = "gitRemote"
 )
 
+// gitRemoteTag marks scenarios that create bare git remotes. Scenarios run
+// concurrently, but these occasionally race on global git configuration, so
+// gitRemoteSemaphore lets only one of them run at a time.
+const gitRemoteTag = "@git-remote"
+
+var gitRemoteSemaphore = make(chan struct{}, 1)
+
+// hasTag reports whether a scenario carries the given tag, directly or
+// through its feature.
+func hasTag(sc *godog.Scenario, tag string) bool {
+	for _, t := range sc.Tags {
+		if t.Name == tag {
+			return true
+		}
+	}
+	return false
+}
+
 // getTestEnv retrieves the TestEnv from context.
 func getTestEnv(ctx context.Context) *support.TestEnv {
 	if env, ok := ctx.Value(testEnvKey).(*support.TestEnv); ok {
@@ -62,10 +83,16 @@ func InitializeCommonSteps(ctx *godog.ScenarioContext) {
 		// Assumes `go build` has been run and backlog binary is in PATH or current dir
 		runner := support.NewCLIRunner("")
 		runner.WorkDir = env.TempDir
+		runner.BaseEnv = env.Environ
 
 		ctx = context.WithValue(ctx, testEnvKey, env)
 		ctx = context.WithValue(ctx, cliRunnerKey, runner)
 
+		if hasTag(sc, gitRemoteTag) {
+			gitRemoteSemaphore <- struct{}{}
+			ctx = context.WithValue(ctx, gitRemoteKey, true)
+		}
+
 		return ctx, nil
 	})
 
@@ -88,6 +115,10 @@ func InitializeCommonSteps(ctx *godog.ScenarioContext) {
 				fmt.Printf("Warning: cleanup failed: %v\n", cleanupErr)
 			}
 		}
+
+		if held, _ := ctx.Value(gitRemoteKey).(bool); held {
+			<-gitRemoteSemaphore
+		}
 		return ctx, nil
 	})
 
@@ -95,6 +126,7 @@ func InitializeCommonSteps(ctx *godog.ScenarioContext) {
 	ctx.Step(`^a fresh backlog directory$`, aFreshBacklogDirectory)
 	ctx.Step(`^a backlog with the following tasks:$`, aBacklogWithTheFollowingTasks)
 	ctx.Step(`^a file "([^"]*)" with content "([^"]*)"$`, aFileWithContent)
+	ctx.Step(`^a file "([^"]*)" with the following content:$`, aFileWithTheFollowingContent)
 	ctx.Step(`^a git repository with remote "([^"]*)"$`, aGitRepositoryWithRemote)
 	ctx.Step(`^a task "([^"]*)" exists with status "([^"]*)"$`, aTaskExistsWithStatus)
 	ctx.Step(`^a task "([^"]*)" exists with priority "([^"]*)"$`, aTaskExistsWithPriority)
@@ -107,24 +139,37 @@ func InitializeCommonSteps(ctx *godog.ScenarioContext) {
 	// When steps
 	ctx.Step(`^I run "([^"]*)"$`, iRun)
 	ctx.Step(`^I run "([^"]*)" with input:$`, iRunWithInput)
+	ctx.Step(`^I run "([^"]*)" with env "([^"]*)"$`, iRunWithEnv)
+	ctx.Step(`^I run "([^"]*)" while task "([^"]*)" is moved to "([^"]*)" after (\d+)ms$`, iRunWhileTaskIsMoved)
 
 	// Then steps
 	ctx.Step(`^the exit code should be (\d+)$`, theExitCodeShouldBe)
 	ctx.Step(`^stdout should contain "([^"]*)"$`, stdoutShouldContain)
 	ctx.Step(`^stdout should not contain "([^"]*)"$`, stdoutShouldNotContain)
 	ctx.Step(`^stderr should contain "([^"]*)"$`, stderrShouldContain)
+	ctx.Step(`^stderr should not contain "([^"]*)"$`, stderrShouldNotContain)
 	ctx.Step(`^stdout should be empty$`, stdoutShouldBeEmpty)
+	ctx.Step(`^stdout should be "([^"]*)"$`, stdoutShouldBe)
+	ctx.Step(`^stdout should contain ANSI colors$`, stdoutShouldContainANSIColors)
+	ctx.Step(`^stdout should not contain ANSI colors$`, stdoutShouldNotContainANSIColors)
+	ctx.Step(`^stdout should contain "([^"]*)" colored (red|green|yellow|blue|magenta|cyan|gray)$`, stdoutShouldContainColored)
 	ctx.Step(`^stderr should be empty$`, stderrShouldBeEmpty)
 	ctx.Step(`^the output should match:$`, theOutputShouldMatch)
+	ctx.Step(`^the output table should contain rows:$`, theOutputTableShouldContainRows)
+	ctx.Step(`^the output table should not contain a row with id "([^"]*)"$`, theOutputTableShouldNotContainARowWithID)
+	ctx.Step(`^the tasks in the output should be ordered by "([^"]*)"$`, theTasksInTheOutputShouldBeOrderedBy)
 	ctx.Step(`^the JSON output should have "([^"]*)" equal to "([^"]*)"$`, theJSONOutputShouldHaveEqualTo)
 	ctx.Step(`^the directory "([^"]*)" should exist$`, theDirectoryShouldExist)
 	ctx.Step(`^the file "([^"]*)" should exist$`, theFileShouldExist)
+	ctx.Step(`^the file "([^"]*)" should not exist$`, theFileShouldNotExist)
+	ctx.Step(`^the JSON file "([^"]*)" should have "([^"]*)" equal to "([^"]*)"$`, theJSONFileShouldHaveEqualTo)
 	ctx.Step(`^the file "([^"]*)" should contain "([^"]*)"$`, theFileShouldContain)
 	ctx.Step(`^the file "([^"]*)" should not contain "([^"]*)"$`, theFileShouldNotContain)
 	ctx.Step(`^a task file should exist in "([^"]*)" directory$`, aTaskFileShouldExistInDirectory)
 	ctx.Step(`^the created task should have priority "([^"]*)"$`, theCreatedTaskShouldHavePriority)
 	ctx.Step(`^the created task should have label "([^"]*)"$`, theCreatedTaskShouldHaveLabel)
 	ctx.Step(`^the created task should have description containing "([^"]*)"$`, theCreatedTaskShouldHaveDescriptionContaining)
+	ctx.Step(`^the created task should have description containing:$`, theCreatedTaskShouldHaveDescriptionContainingDocString)
 	ctx.Step(`^the task count should be (\d+)$`, theTaskCountShouldBe)
 	ctx.Step(`^stdout should match pattern "([^"]*)"$`, stdoutShouldMatchPattern)
 	ctx.Step(`^the JSON output should be valid$`, theJSONOutputShouldBeValid)
@@ -149,12 +194,23 @@ func InitializeCommonSteps(ctx *godog.ScenarioContext) {
 	ctx.Step(`^the task "([^"]*)" should have assignee "([^"]*)"$`, theTaskShouldHaveAssignee)
 	ctx.Step(`^the task "([^"]*)" should have label "([^"]*)"$`, theTaskShouldHaveLabel)
 	ctx.Step(`^the task "([^"]*)" should have comment containing "([^"]*)"$`, theTaskShouldHaveCommentContaining)
+	ctx.Step(`^the task "([^"]*)" should have a comment by "([^"]*)" containing "([^"]*)"$`, theTaskShouldHaveACommentByContaining)
+	ctx.Step(`^the task "([^"]*)" should have (\d+) comments?$`, theTaskShouldHaveComments)
+	ctx.Step(`^the task "([^"]*)" should block "([^"]*)"$`, theTaskShouldBlock)
+	ctx.Step(`^the task "([^"]*)" should not block "([^"]*)"$`, theTaskShouldNotBlock)
+	ctx.Step(`^the task "([^"]*)" should be blocked by "([^"]*)"$`, theTaskShouldBeBlockedBy)
+	ctx.Step(`^the task "([^"]*)" should not be blocked by "([^"]*)"$`, theTaskShouldNotBeBlockedBy)
+	ctx.Step(`^the task "([^"]*)" should be ordered before "([^"]*)"$`, theTaskShouldBeOrderedBefore)
 	ctx.Step(`^the task "([^"]*)" should not have label "([^"]*)"$`, theTaskShouldNotHaveLabel)
 	ctx.Step(`^the task "([^"]*)" should have description containing "([^"]*)"$`, theTaskShouldHaveDescriptionContaining)
+	ctx.Step(`^the task "([^"]*)" should have description containing:$`, theTaskShouldHaveDescriptionContainingDocString)
 
 	// Claim-specific verification steps
 	ctx.Step(`^the task "([^"]*)" should be assigned$`, theTaskShouldBeAssigned)
 	ctx.Step(`^the task "([^"]*)" should have agent label$`, theTaskShouldHaveAgentLabel)
+	ctx.Step(`^the task "([^"]*)" should have the generated agent label$`, theTaskShouldHaveTheGeneratedAgentLabel)
+	ctx.Step(`^a generated agent ID "([^"]*)" exists$`, aGeneratedAgentIDExists)
+	ctx.Step(`^stdout should contain the generated agent ID$`, stdoutShouldContainTheGeneratedAgentID)
 	ctx.Step(`^a lock file should exist for task "([^"]*)"$`, aLockFileShouldExistForTask)
 	ctx.Step(`^no lock file should exist for task "([^"]*)"$`, noLockFileShouldExistForTask)
 	ctx.Step(`^task "([^"]*)" should be claimed by "([^"]*)"$`, taskShouldBeClaimedBy)
@@ -182,8 +238,14 @@ func InitializeCommonSteps(ctx *godog.ScenarioContext) {
 	ctx.Step(`^there are uncommitted changes in the repository$`, thereAreUncommittedChangesInTheRepository)
 	ctx.Step(`^the remote has a new commit$`, theRemoteHasANewCommit)
 	ctx.Step(`^another agent has claimed task "([^"]*)" and pushed while we were working$`, anotherAgentHasClaimedTaskAndPushed)
+	ctx.Step(`^the remote changes the title of task "([^"]*)" to "([^"]*)"$`, theRemoteChangesTheTitleOfTask)
+	ctx.Step(`^the remote adds label "([^"]*)" to task "([^"]*)"$`, theRemoteAddsLabelToTask)
+	ctx.Step(`^a local commit changes the title of task "([^"]*)" to "([^"]*)"$`, aLocalCommitChangesTheTitleOfTask)
+	ctx.Step(`^a local commit adds label "([^"]*)" to task "([^"]*)"$`, aLocalCommitAddsLabelToTask)
 	ctx.Step(`^task "([^"]*)" has a stale lock file$`, taskHasStaleLockFile)
 	ctx.Step(`^the remote repository is unreachable$`, theRemoteRepositoryIsUnreachable)
+	ctx.Step(`^the repository is in a detached HEAD state$`, theRepositoryIsInADetachedHeadState)
+	ctx.Step(`^the repository is tagged "([^"]*)" at "([^"]*)"$`, theRepositoryIsTaggedAt)
 
 	// Git sync verification steps
 	ctx.Step(`^a git commit should exist with message containing "([^"]*)"$`, aGitCommitShouldExistWithMessageContaining)
@@ -191,6 +253,7 @@ func InitializeCommonSteps(ctx *godog.ScenarioContext) {
 	ctx.Step(`^the local repository should be in sync with remote$`, theLocalRepositoryShouldBeInSyncWithRemote)
 	ctx.Step(`^the local repository should match the remote$`, theLocalRepositoryShouldMatchTheRemote)
 	ctx.Step(`^no new git commits should exist$`, noNewGitCommitsShouldExist)
+	ctx.Step(`^(\d+) new git commits? should exist$`, newGitCommitsShouldExist)
 	ctx.Step(`^the remote should have the latest commit$`, theRemoteShouldHaveTheLatestCommit)
 	ctx.Step(`^the local repository should include the remote commit$`, theLocalRepositoryShouldIncludeTheRemoteCommit)
 
@@ -205,12 +268,23 @@ func InitializeCommonSteps(ctx *godog.ScenarioContext) {
 	ctx.Step(`^the mock GitHub API authenticated user is "([^"]*)"$`, theMockGitHubAPIAuthenticatedUserIs)
 	ctx.Step(`^the mock GitHub issue "([^"]*)" has the following comments:$`, theMockGitHubIssueHasTheFollowingComments)
 	ctx.Step(`^the JSON output array "([^"]*)" should have length (\d+)$`, theJSONOutputArrayShouldHaveLength)
+	ctx.Step(`^the mock GitHub API returns (\d+) for ([A-Z]+) (\S+)$`, theMockGitHubAPIReturnsStatusFor)
+	ctx.Step(`^the mock GitHub API returns malformed JSON for ([A-Z]+) (\S+)$`, theMockGitHubAPIReturnsMalformedJSONFor)
+	ctx.Step(`^the mock GitHub API times out after (\d+) seconds?$`, theMockGitHubAPITimesOutAfter)
+	ctx.Step(`^the mock GitHub API fails the first (\d+) requests?$`, theMockGitHubAPIFailsTheFirstRequests)
+	ctx.Step(`^the mock GitHub API rate limit has (\d+) of (\d+) requests remaining, resetting in (\d+) seconds?$`, theMockGitHubAPIRateLimitHasRemaining)
+	ctx.Step(`^the mock GitHub API should have received (\d+) requests?$`, theMockGitHubAPIShouldHaveReceivedRequests)
 
 	// GitHub assertion steps
 	ctx.Step(`^a GitHub repository "([^"]*)" with issues:$`, aGitHubRepositoryWithIssues)
 	ctx.Step(`^the GitHub token is "([^"]*)"$`, theGitHubTokenIs)
 	ctx.Step(`^the GitHub issue "([^"]*)" should have label "([^"]*)"$`, theGitHubIssueShouldHaveLabel)
+	ctx.Step(`^the GitHub issue "([^"]*)" should not have label "([^"]*)"$`, theGitHubIssueShouldNotHaveLabel)
+	ctx.Step(`^the GitHub repository has label "([^"]*)" with color "([^"]*)"$`, theGitHubRepositoryHasLabelWithColor)
+	ctx.Step(`^the GitHub repository label "([^"]*)" should have color "([^"]*)"$`, theGitHubRepositoryLabelShouldHaveColor)
+	ctx.Step(`^another client adds label "([^"]*)" to GitHub issue "([^"]*)" during the next label change$`, anotherClientAddsLabelDuringNextLabelChange)
 	ctx.Step(`^the GitHub issue "([^"]*)" should be assigned to "([^"]*)"$`, theGitHubIssueShouldBeAssignedTo)
+	ctx.Step(`^the GitHub issue "([^"]*)" should have body containing:$`, theGitHubIssueShouldHaveBodyContaining)
 
 	// GitHub Projects v2 steps
 	ctx.Step(`^a GitHub project (\d+) with columns:$`, aGitHubProjectWithColumns)
@@ -225,11 +299,20 @@ func InitializeCommonSteps(ctx *godog.ScenarioContext) {
 	ctx.Step(`^the mock Linear API expects key "([^"]*)"$`, theMockLinearAPIExpectsKey)
 	ctx.Step(`^the mock Linear API has the following issues:$`, theMockLinearAPIHasTheFollowingIssues)
 	ctx.Step(`^the mock Linear API authenticated user is "([^"]*)"$`, theMockLinearAPIAuthenticatedUserIs)
+	ctx.Step(`^the mock Linear API has the following workflow states:$`, theMockLinearAPIHasTheFollowingWorkflowStates)
+	ctx.Step(`^the mock Linear issue "([^"]*)" has the following comments:$`, theMockLinearIssueHasTheFollowingComments)
+	ctx.Step(`^the mock Linear API has the following labels:$`, theMockLinearAPIHasTheFollowingLabels)
 
 	// Linear assertion steps
+	ctx.Step(`^an exec workspace using the reference adapter$`, anExecWorkspaceUsingTheReferenceAdapter)
+	ctx.Step(`^an exec workspace using the reference adapter with capabilities "([^"]*)"$`, anExecWorkspaceUsingTheReferenceAdapterWithCapabilities)
+	ctx.Step(`^the reference adapter has the following tasks:$`, theReferenceAdapterHasTheFollowingTasks)
 	ctx.Step(`^a Linear team "([^"]*)" with issues:$`, aLinearTeamWithIssues)
 	ctx.Step(`^the Linear issue "([^"]*)" should have state "([^"]*)"$`, theLinearIssueShouldHaveState)
 	ctx.Step(`^the Linear issue "([^"]*)" should have label "([^"]*)"$`, theLinearIssueShouldHaveLabel)
+	ctx.Step(`^the Linear issue "([^"]*)" should (not )?be archived$`, theLinearIssueShouldBeArchived)
+	ctx.Step(`^the Linear issue "([^"]*)" should have (\d+) comments?$`, theLinearIssueShouldHaveComments)
+	ctx.Step(`^the Linear issue "([^"]*)" should have description containing:$`, theLinearIssueShouldHaveDescriptionContaining)
 }
 
 // aFreshBacklogDirectory creates a new empty .backlog directory.
@@ -336,6 +419,10 @@ func aBacklogWithTheFollowingTasks(ctx context.Context, table *godog.Table) (con
 		}
 
 		task.AgentID = getValue("agent_id")
+		task.Created = getValue("created")
+		task.Updated = getValue("updated")
+		task.CompletedAt = getValue("completed_at")
+		task.URL = getValue("url")
 
 		tasks = append(tasks, task)
 	}
@@ -373,6 +460,62 @@ func iRunWithInput(ctx context.Context, command string, input *godog.DocString)
 	return ctx, nil
 }
 
+// iRunWithEnv executes a CLI command with extra environment variables for this
+// invocation only. Multiple variables are separated by spaces: "A=1 B=2".
+func iRunWithEnv(ctx context.Context, command, envSpec string) (context.Context, error) {
+	runner := getCLIRunner(ctx)
+	if runner == nil {
+		return ctx, fmt.Errorf("CLI runner not initialized")
+	}
+
+	env := make(map[string]string)
+	for _, pair := range strings.Fields(envSpec) {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return ctx, fmt.Errorf("invalid environment variable %q, expected KEY=VALUE", pair)
+		}
+		env[key] = value
+	}
+
+	result := runner.RunWithEnv(command, env)
+	ctx = context.WithValue(ctx, lastResultKey, result)
+
+	return ctx, nil
+}
+
+// iRunWhileTaskIsMoved executes a CLI command and, while it runs, moves a
+// task's file to another status directory after a delay, as another agent
+// would. It is for commands that wait for a change, such as next --wait.
+func iRunWhileTaskIsMoved(ctx context.Context, command, taskID, status string, delayMS int) (context.Context, error) {
+	runner := getCLIRunner(ctx)
+	if runner == nil {
+		return ctx, fmt.Errorf("CLI runner not initialized")
+	}
+	env := getTestEnv(ctx)
+	if env == nil {
+		return ctx, fmt.Errorf("test environment not initialized")
+	}
+
+	task := support.NewTaskFileReader(env.Path(".backlog")).ReadTask(taskID)
+	if task.ParseErr != nil {
+		return ctx, fmt.Errorf("failed to read task %s: %w", taskID, task.ParseErr)
+	}
+	target := filepath.Join(env.Path(".backlog"), status, filepath.Base(task.Path))
+
+	moved := make(chan error, 1)
+	go func() {
+		time.Sleep(time.Duration(delayMS) * time.Millisecond)
+		moved <- os.Rename(task.Path, target)
+	}()
+	result := runner.Run(command)
+	if err := <-moved; err != nil {
+		return ctx, fmt.Errorf("failed to move task %s: %w", taskID, err)
+	}
+	ctx = context.WithValue(ctx, lastResultKey, result)
+
+	return ctx, nil
+}
+
 // theExitCodeShouldBe verifies the exit code of the last command.
 func theExitCodeShouldBe(ctx context.Context, expected int) error {
 	result := getLastResult(ctx)
@@ -430,6 +573,20 @@ func stderrShouldContain(ctx context.Context, expected string) error {
 	return nil
 }
 
+// stderrShouldNotContain verifies stderr does not contain a substring.
+func stderrShouldNotContain(ctx context.Context, unexpected string) error {
+	result := getLastResult(ctx)
+	if result == nil {
+		return fmt.Errorf("no command has been run")
+	}
+
+	if strings.Contains(result.Stderr, unexpected) {
+		return fmt.Errorf("expected stderr to not contain %q, but it does:\n%s", unexpected, result.Stderr)
+	}
+
+	return nil
+}
+
 // stdoutShouldBeEmpty verifies stdout is empty.
 func stdoutShouldBeEmpty(ctx context.Context) error {
 	result := getLastResult(ctx)
@@ -444,6 +601,73 @@ func stdoutShouldBeEmpty(ctx context.Context) error {
 	return nil
 }
 
+func stdoutShouldBe(ctx context.Context, expected string) error {
+	result := getLastResult(ctx)
+	if result == nil {
+		return fmt.Errorf("no command has been run")
+	}
+
+	if strings.TrimSpace(result.Stdout) != expected {
+		return fmt.Errorf("expected stdout to be %q, got:\n%s", expected, result.Stdout)
+	}
+
+	return nil
+}
+
+// ansiColorCodes maps color names to their ANSI foreground codes.
+var ansiColorCodes = map[string]string{
+	"red":     "31",
+	"green":   "32",
+	"yellow":  "33",
+	"blue":    "34",
+	"magenta": "35",
+	"cyan":    "36",
+	"gray":    "90",
+}
+
+// stdoutShouldContainANSIColors verifies stdout contains ANSI escape sequences.
+func stdoutShouldContainANSIColors(ctx context.Context) error {
+	result := getLastResult(ctx)
+	if result == nil {
+		return fmt.Errorf("no command has been run")
+	}
+
+	if !strings.Contains(result.Stdout, "\x1b[") {
+		return fmt.Errorf("expected stdout to contain ANSI colors, got:\n%s", result.Stdout)
+	}
+
+	return nil
+}
+
+// stdoutShouldNotContainANSIColors verifies stdout has no ANSI escape sequences.
+func stdoutShouldNotContainANSIColors(ctx context.Context) error {
+	result := getLastResult(ctx)
+	if result == nil {
+		return fmt.Errorf("no command has been run")
+	}
+
+	if strings.Contains(result.Stdout, "\x1b[") {
+		return fmt.Errorf("expected stdout to have no ANSI colors, got:\n%q", result.Stdout)
+	}
+
+	return nil
+}
+
+// stdoutShouldContainColored verifies stdout contains text wrapped in the given color.
+func stdoutShouldContainColored(ctx context.Context, text, color string) error {
+	result := getLastResult(ctx)
+	if result == nil {
+		return fmt.Errorf("no command has been run")
+	}
+
+	expected := "\x1b[" + ansiColorCodes[color] + "m" + text + "\x1b[0m"
+	if !strings.Contains(result.Stdout, expected) {
+		return fmt.Errorf("expected stdout to contain %q colored %s, got:\n%q", text, color, result.Stdout)
+	}
+
+	return nil
+}
+
 // stderrShouldBeEmpty verifies stderr is empty.
 func stderrShouldBeEmpty(ctx context.Context) error {
 	result := getLastResult(ctx)
@@ -475,6 +699,125 @@ func theOutputShouldMatch(ctx context.Context, expected *godog.DocString) error
 	return nil
 }
 
+// theOutputTableShouldContainRows verifies that the table in stdout has a
+// row for each row of the data table, matched by the id column. Only the
+// listed cells are compared, so column order, extra columns, and extra rows
+// don't matter. An empty expected cell matches an empty cell or the "—"
+// placeholder.
+func theOutputTableShouldContainRows(ctx context.Context, expected *godog.Table) error {
+	result := getLastResult(ctx)
+	if result == nil {
+		return fmt.Errorf("no command has been run")
+	}
+	if len(expected.Rows) < 2 {
+		return fmt.Errorf("table must have at least a header row and one data row")
+	}
+
+	var columns []string
+	idIndex := -1
+	for i, cell := range expected.Rows[0].Cells {
+		columns = append(columns, strings.ToLower(cell.Value))
+		if columns[i] == "id" {
+			idIndex = i
+		}
+	}
+	if idIndex < 0 {
+		return fmt.Errorf("table must have an id column to match rows by")
+	}
+
+	table, err := support.ParseOutputTable(result.Stdout)
+	if err != nil {
+		return err
+	}
+	for _, column := range columns {
+		if !table.HasColumn(column) {
+			return fmt.Errorf("output table has no %q column (columns: %s)\nstdout:\n%s", column, strings.Join(table.Columns, ", "), result.Stdout)
+		}
+	}
+
+	for _, row := range expected.Rows[1:] {
+		id := row.Cells[idIndex].Value
+		actual, ok := table.Row("id", id)
+		if !ok {
+			return fmt.Errorf("output table has no row with id %q\nstdout:\n%s", id, result.Stdout)
+		}
+		for i, cell := range row.Cells {
+			got := actual[columns[i]]
+			if got == cell.Value || (cell.Value == "" && got == "—") {
+				continue
+			}
+			return fmt.Errorf("row %q: expected %s %q, got %q\nstdout:\n%s", id, columns[i], cell.Value, got, result.Stdout)
+		}
+	}
+
+	return nil
+}
+
+// theTasksInTheOutputShouldBeOrderedBy verifies that the tasks listed in
+// stdout, as JSON or a table, are ordered by a spec such as "priority desc,
+// created asc". Fields missing from a table, such as created, are read from
+// the task files by ID.
+func theTasksInTheOutputShouldBeOrderedBy(ctx context.Context, order string) error {
+	result := getLastResult(ctx)
+	if result == nil {
+		return fmt.Errorf("no command has been run")
+	}
+	env := getTestEnv(ctx)
+	if env == nil {
+		return fmt.Errorf("test environment not initialized")
+	}
+
+	keys, err := support.ParseTaskOrder(order)
+	if err != nil {
+		return err
+	}
+	rows, err := support.TaskRows(result.Stdout)
+	if err != nil {
+		return err
+	}
+	if len(rows) < 2 {
+		return fmt.Errorf("expected at least two tasks to check their order, got %d\nstdout:\n%s", len(rows), result.Stdout)
+	}
+
+	reader := support.NewTaskFileReader(env.Path(".backlog"))
+	for _, row := range rows {
+		for _, key := range keys {
+			if _, ok := row[key.Field]; ok {
+				continue
+			}
+			task := reader.ReadTask(row["id"])
+			if task.ParseErr != nil {
+				return fmt.Errorf("output has no %s for task %q and its file could not be read: %w", key.Field, row["id"], task.ParseErr)
+			}
+			row[key.Field] = task.GetField(key.Field)
+		}
+	}
+
+	if err := support.CheckTaskOrder(rows, keys); err != nil {
+		return fmt.Errorf("%w\nstdout:\n%s", err, result.Stdout)
+	}
+	return nil
+}
+
+// theOutputTableShouldNotContainARowWithID verifies that the table in stdout
+// has no row with the given id.
+func theOutputTableShouldNotContainARowWithID(ctx context.Context, id string) error {
+	result := getLastResult(ctx)
+	if result == nil {
+		return fmt.Errorf("no command has been run")
+	}
+
+	table, err := support.ParseOutputTable(result.Stdout)
+	if err != nil {
+		return err
+	}
+	if _, ok := table.Row("id", id); ok {
+		return fmt.Errorf("output table has a row with id %q\nstdout:\n%s", id, result.Stdout)
+	}
+
+	return nil
+}
+
 // theJSONOutputShouldHaveEqualTo verifies a JSON path has the expected value.
 func theJSONOutputShouldHaveEqualTo(ctx context.Context, path, expected string) error {
 	result := getLastResult(ctx)
@@ -516,6 +859,11 @@ func aFileWithContent(ctx context.Context, path, content string) (context.Contex
 	return ctx, nil
 }
 
+// aFileWithTheFollowingContent creates a file in the test directory with multi-line content.
+func aFileWithTheFollowingContent(ctx context.Context, path string, content *godog.DocString) (context.Context, error) {
+	return aFileWithContent(ctx, path, content.Content)
+}
+
 // aGitRepositoryWithRemote initializes a git repository with the specified remote URL.
 func aGitRepositoryWithRemote(ctx context.Context, remoteURL string) (context.Context, error) {
 	env := getTestEnv(ctx)
@@ -586,6 +934,53 @@ func theFileShouldExist(ctx context.Context, path string) error {
 	return nil
 }
 
+// theFileShouldNotExist verifies that a file does not exist.
+func theFileShouldNotExist(ctx context.Context, path string) error {
+	env := getTestEnv(ctx)
+	if env == nil {
+		return fmt.Errorf("test environment not initialized")
+	}
+
+	if _, err := os.Stat(env.Path(path)); err == nil {
+		return fmt.Errorf("file %q exists but should not", path)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("error checking file %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// theJSONFileShouldHaveEqualTo verifies a value at a JSON path in a file.
+func theJSONFileShouldHaveEqualTo(ctx context.Context, path, jsonPath, expected string) error {
+	env := getTestEnv(ctx)
+	if env == nil {
+		return fmt.Errorf("test environment not initialized")
+	}
+
+	content, err := env.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read file %q: %w", path, err)
+	}
+
+	jsonResult := support.ParseJSON(content)
+	if !jsonResult.Valid() {
+		return fmt.Errorf("file %q is not valid JSON: %s\ncontent:\n%s", path, jsonResult.Error(), content)
+	}
+
+	val := jsonResult.Get(jsonPath)
+	var actual string
+	if val == nil {
+		actual = "null"
+	} else {
+		actual = fmt.Sprintf("%v", val)
+	}
+	if actual != expected {
+		return fmt.Errorf("expected JSON path %q in %q to be %q, got %q", jsonPath, path, expected, actual)
+	}
+
+	return nil
+}
+
 // theFileShouldContain verifies that a file contains a substring.
 func theFileShouldContain(ctx context.Context, path, expected string) error {
 	env := getTestEnv(ctx)
@@ -700,6 +1095,11 @@ func theCreatedTaskShouldHaveDescriptionContaining(ctx context.Context, expected
 	return nil
 }
 
+// theCreatedTaskShouldHaveDescriptionContainingDocString verifies the task description contains a multi-line block.
+func theCreatedTaskShouldHaveDescriptionContainingDocString(ctx context.Context, expected *godog.DocString) error {
+	return theCreatedTaskShouldHaveDescriptionContaining(ctx, expected.Content)
+}
+
 // theTaskCountShouldBe verifies the total number of tasks.
 func theTaskCountShouldBe(ctx context.Context, expected int) error {
 	env := getTestEnv(ctx)
@@ -913,7 +1313,7 @@ func taskHasTheFollowingComments(ctx context.Context, taskID string, table *godo
 	}
 
 	// Parse comments from table
-	var comments []support.CommentFixture
+	var comments []support.TaskComment
 	for _, row := range table.Rows[1:] {
 		getValue := func(col string) string {
 			if idx, ok := colIndex[col]; ok && idx < len(row.Cells) {
@@ -922,12 +1322,12 @@ func taskHasTheFollowingComments(ctx context.Context, taskID string, table *godo
 			return ""
 		}
 
-		comment := support.CommentFixture{
+		comments = append(comments, support.TaskComment{
+			ID:     getValue("id"),
 			Author: getValue("author"),
 			Date:   getValue("date"),
 			Body:   getValue("body"),
-		}
-		comments = append(comments, comment)
+		})
 	}
 
 	// Read the existing task file
@@ -937,27 +1337,8 @@ func taskHasTheFollowingComments(ctx context.Context, taskID string, table *godo
 		return ctx, fmt.Errorf("failed to read task %s: %w", taskID, task.ParseErr)
 	}
 
-	// Build comments section
-	var commentsSection strings.Builder
-	commentsSection.WriteString("\n## Comments\n")
-	for _, comment := range comments {
-		commentsSection.WriteString(fmt.Sprintf("\n### %s @%s\n", comment.Date, comment.Author))
-		commentsSection.WriteString(comment.Body)
-		commentsSection.WriteString("\n")
-	}
-
-	// Read the original file content and append comments
-	content, err := os.ReadFile(task.Path)
-	if err != nil {
-		return ctx, fmt.Errorf("failed to read task file: %w", err)
-	}
-
-	newContent := string(content) + commentsSection.String()
-	if err := os.WriteFile(task.Path, []byte(newContent), 0644); err != nil {
-		return ctx, fmt.Errorf("failed to write task file: %w", err)
-	}
-
-	return ctx, nil
+	task.AddComments(comments...)
+	return ctx, task.Save()
 }
 
 // theTaskShouldHaveStatus verifies a task has the expected status.
@@ -1096,64 +1477,196 @@ func theTaskShouldHaveCommentContaining(ctx context.Context, taskID, expectedTex
 		return fmt.Errorf("failed to read task %s: %w", taskID, task.ParseErr)
 	}
 
-	// Read the full file content to check for comments
-	content, err := os.ReadFile(task.Path)
-	if err != nil {
-		return fmt.Errorf("failed to read task file: %w", err)
-	}
-
-	if !strings.Contains(string(content), expectedText) {
-		return fmt.Errorf("expected task %s to have comment containing %q, but it doesn't.\nFile content:\n%s", taskID, expectedText, string(content))
+	if !task.HasComment(expectedText) {
+		return fmt.Errorf("expected task %s to have comment containing %q, but it has:\n%s", taskID, expectedText, describeComments(task.Comments))
 	}
 
 	return nil
 }
 
-// theTaskShouldNotHaveLabel verifies a task does not have a specific label.
-func theTaskShouldNotHaveLabel(ctx context.Context, taskID, unexpectedLabel string) error {
+// readTaskFile reads a task file from the test environment's backlog.
+func readTaskFile(ctx context.Context, taskID string) (*support.TaskFile, error) {
 	env := getTestEnv(ctx)
 	if env == nil {
-		return fmt.Errorf("test environment not initialized")
+		return nil, fmt.Errorf("test environment not initialized")
 	}
 
-	reader := support.NewTaskFileReader(env.Path(".backlog"))
-	task := reader.ReadTask(taskID)
+	task := support.NewTaskFileReader(env.Path(".backlog")).ReadTask(taskID)
 	if task.ParseErr != nil {
-		return fmt.Errorf("failed to read task %s: %w", taskID, task.ParseErr)
+		return nil, fmt.Errorf("failed to read task %s: %w", taskID, task.ParseErr)
 	}
+	return task, nil
+}
 
-	if task.HasLabel(unexpectedLabel) {
-		return fmt.Errorf("expected task %s to not have label %q, but it has labels: %v", taskID, unexpectedLabel, task.Labels)
+// theTaskShouldHaveACommentByContaining verifies a task has a comment by
+// author containing specific text.
+func theTaskShouldHaveACommentByContaining(ctx context.Context, taskID, author, expectedText string) error {
+	task, err := readTaskFile(ctx, taskID)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	for _, c := range task.Comments {
+		if c.Author == author && strings.Contains(c.Body, expectedText) {
+			return nil
+		}
+	}
+	return fmt.Errorf("expected task %s to have a comment by @%s containing %q, but it has:\n%s", taskID, author, expectedText, describeComments(task.Comments))
 }
 
-// theTaskShouldHaveDescriptionContaining verifies a task description contains expected text.
-func theTaskShouldHaveDescriptionContaining(ctx context.Context, taskID, expectedText string) error {
-	env := getTestEnv(ctx)
-	if env == nil {
-		return fmt.Errorf("test environment not initialized")
+// theTaskShouldHaveComments verifies the number of comments on a task.
+func theTaskShouldHaveComments(ctx context.Context, taskID string, expected int) error {
+	task, err := readTaskFile(ctx, taskID)
+	if err != nil {
+		return err
 	}
 
-	reader := support.NewTaskFileReader(env.Path(".backlog"))
-	task := reader.ReadTask(taskID)
-	if task.ParseErr != nil {
-		return fmt.Errorf("failed to read task %s: %w", taskID, task.ParseErr)
+	if len(task.Comments) != expected {
+		return fmt.Errorf("expected task %s to have %d comment(s), but it has %d:\n%s", taskID, expected, len(task.Comments), describeComments(task.Comments))
 	}
+	return nil
+}
 
-	if !strings.Contains(task.Description, expectedText) {
-		return fmt.Errorf("expected task %s description to contain %q, got:\n%s", taskID, expectedText, task.Description)
+// theTaskShouldBlock verifies a task lists another in its blocks field.
+func theTaskShouldBlock(ctx context.Context, taskID, blockedID string) error {
+	task, err := readTaskFile(ctx, taskID)
+	if err != nil {
+		return err
 	}
 
+	if !task.BlocksTask(blockedID) {
+		return fmt.Errorf("expected task %s to block %s, but it blocks: %v", taskID, blockedID, task.Blocks)
+	}
 	return nil
 }
 
-// aTaskExistsWithStatus creates a task with the given title and status.
-func aTaskExistsWithStatus(ctx context.Context, title, status string) (context.Context, error) {
-	env := getTestEnv(ctx)
-	if env == nil {
-		return ctx, fmt.Errorf("test environment not initialized")
+// theTaskShouldNotBlock verifies a task does not list another in its blocks
+// field.
+func theTaskShouldNotBlock(ctx context.Context, taskID, blockedID string) error {
+	task, err := readTaskFile(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	if task.BlocksTask(blockedID) {
+		return fmt.Errorf("expected task %s to not block %s, but it blocks: %v", taskID, blockedID, task.Blocks)
+	}
+	return nil
+}
+
+// theTaskShouldBeBlockedBy verifies a task lists another in its blocked_by
+// field.
+func theTaskShouldBeBlockedBy(ctx context.Context, taskID, blockerID string) error {
+	task, err := readTaskFile(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	if !task.IsBlockedBy(blockerID) {
+		return fmt.Errorf("expected task %s to be blocked by %s, but it is blocked by: %v", taskID, blockerID, task.BlockedBy)
+	}
+	return nil
+}
+
+// theTaskShouldNotBeBlockedBy verifies a task does not list another in its
+// blocked_by field.
+func theTaskShouldNotBeBlockedBy(ctx context.Context, taskID, blockerID string) error {
+	task, err := readTaskFile(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	if task.IsBlockedBy(blockerID) {
+		return fmt.Errorf("expected task %s to not be blocked by %s, but it is blocked by: %v", taskID, blockerID, task.BlockedBy)
+	}
+	return nil
+}
+
+// theTaskShouldBeOrderedBefore verifies a task's sort_order places it above
+// another. Both tasks need an explicit sort_order.
+func theTaskShouldBeOrderedBefore(ctx context.Context, taskID, otherID string) error {
+	task, err := readTaskFile(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	other, err := readTaskFile(ctx, otherID)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range []*support.TaskFile{task, other} {
+		if t.SortOrder == 0 {
+			return fmt.Errorf("expected task %s to have a sort_order, but it has none", t.ID)
+		}
+	}
+	if task.SortOrder >= other.SortOrder {
+		return fmt.Errorf("expected task %s (sort_order %g) to be ordered before %s (sort_order %g)", taskID, task.SortOrder, otherID, other.SortOrder)
+	}
+	return nil
+}
+
+// describeComments lists comments for failure messages.
+func describeComments(comments []support.TaskComment) string {
+	if len(comments) == 0 {
+		return "  (no comments)"
+	}
+	var b strings.Builder
+	for _, c := range comments {
+		fmt.Fprintf(&b, "  %s @%s: %q\n", c.Date, c.Author, c.Body)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// theTaskShouldNotHaveLabel verifies a task does not have a specific label.
+func theTaskShouldNotHaveLabel(ctx context.Context, taskID, unexpectedLabel string) error {
+	env := getTestEnv(ctx)
+	if env == nil {
+		return fmt.Errorf("test environment not initialized")
+	}
+
+	reader := support.NewTaskFileReader(env.Path(".backlog"))
+	task := reader.ReadTask(taskID)
+	if task.ParseErr != nil {
+		return fmt.Errorf("failed to read task %s: %w", taskID, task.ParseErr)
+	}
+
+	if task.HasLabel(unexpectedLabel) {
+		return fmt.Errorf("expected task %s to not have label %q, but it has labels: %v", taskID, unexpectedLabel, task.Labels)
+	}
+
+	return nil
+}
+
+// theTaskShouldHaveDescriptionContaining verifies a task description contains expected text.
+func theTaskShouldHaveDescriptionContaining(ctx context.Context, taskID, expectedText string) error {
+	env := getTestEnv(ctx)
+	if env == nil {
+		return fmt.Errorf("test environment not initialized")
+	}
+
+	reader := support.NewTaskFileReader(env.Path(".backlog"))
+	task := reader.ReadTask(taskID)
+	if task.ParseErr != nil {
+		return fmt.Errorf("failed to read task %s: %w", taskID, task.ParseErr)
+	}
+
+	if !strings.Contains(task.Description, expectedText) {
+		return fmt.Errorf("expected task %s description to contain %q, got:\n%s", taskID, expectedText, task.Description)
+	}
+
+	return nil
+}
+
+// theTaskShouldHaveDescriptionContainingDocString verifies a task description contains a multi-line block.
+func theTaskShouldHaveDescriptionContainingDocString(ctx context.Context, taskID string, expected *godog.DocString) error {
+	return theTaskShouldHaveDescriptionContaining(ctx, taskID, expected.Content)
+}
+
+// aTaskExistsWithStatus creates a task with the given title and status.
+func aTaskExistsWithStatus(ctx context.Context, title, status string) (context.Context, error) {
+	env := getTestEnv(ctx)
+	if env == nil {
+		return ctx, fmt.Errorf("test environment not initialized")
 	}
 
 	// Ensure backlog directory exists
@@ -1348,33 +1861,18 @@ func taskIsClaimedByAgent(ctx context.Context, taskID, agentID string) (context.
 		return ctx, fmt.Errorf("failed to read task %s: %w", taskID, task.ParseErr)
 	}
 
-	// Read the file content
-	content, err := os.ReadFile(task.Path)
-	if err != nil {
-		return ctx, fmt.Errorf("failed to read task file: %w", err)
-	}
-
-	// Add agent_id to frontmatter if not present
-	contentStr := string(content)
-	if !strings.Contains(contentStr, "agent_id:") {
-		// Insert agent_id before the closing ---
-		contentStr = strings.Replace(contentStr, "\n---\n", fmt.Sprintf("\nagent_id: %s\n---\n", agentID), 1)
-	}
-
-	// Add agent label if not present
-	agentLabel := fmt.Sprintf("agent:%s", agentID)
-	if !strings.Contains(contentStr, agentLabel) {
-		// Update the labels line
-		if strings.Contains(contentStr, "labels: []") {
-			contentStr = strings.Replace(contentStr, "labels: []", fmt.Sprintf("labels: [%s]", agentLabel), 1)
-		} else if strings.Contains(contentStr, "labels: [") {
-			// Add to existing labels
-			contentStr = strings.Replace(contentStr, "labels: [", fmt.Sprintf("labels: [%s, ", agentLabel), 1)
+	// Record the claim the way older versions of the CLI did: agent_id in
+	// the frontmatter alongside the agent label
+	if !task.IsClaimed() {
+		if err := task.SetField("agent_id", agentID); err != nil {
+			return ctx, fmt.Errorf("failed to set agent_id on task %s: %w", taskID, err)
 		}
 	}
-
-	if err := os.WriteFile(task.Path, []byte(contentStr), 0644); err != nil {
-		return ctx, fmt.Errorf("failed to write task file: %w", err)
+	if err := task.AddLabel(fmt.Sprintf("agent:%s", agentID)); err != nil {
+		return ctx, fmt.Errorf("failed to add agent label to task %s: %w", taskID, err)
+	}
+	if err := task.Save(); err != nil {
+		return ctx, err
 	}
 
 	// Create lock file
@@ -1459,6 +1957,69 @@ func theTaskShouldHaveAgentLabel(ctx context.Context, taskID string) error {
 	return nil
 }
 
+// generatedAgentIDPath is where the CLI persists its auto-generated agent ID
+// for the scenario's HOME.
+func generatedAgentIDPath(env *support.TestEnv) string {
+	return filepath.Join(env.Getenv("HOME"), ".config", "backlog", "agent-id")
+}
+
+// readGeneratedAgentID returns the agent ID the CLI generated and persisted.
+func readGeneratedAgentID(env *support.TestEnv) (string, error) {
+	data, err := os.ReadFile(generatedAgentIDPath(env))
+	if err != nil {
+		return "", fmt.Errorf("expected a generated agent ID: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// theTaskShouldHaveTheGeneratedAgentLabel verifies that the task was claimed
+// with the auto-generated agent ID.
+func theTaskShouldHaveTheGeneratedAgentLabel(ctx context.Context, taskID string) error {
+	env := getTestEnv(ctx)
+	if env == nil {
+		return fmt.Errorf("test environment not initialized")
+	}
+
+	agentID, err := readGeneratedAgentID(env)
+	if err != nil {
+		return err
+	}
+	return theTaskShouldHaveLabel(ctx, taskID, "agent:"+agentID)
+}
+
+// aGeneratedAgentIDExists persists an auto-generated agent ID, as a previous
+// run of the CLI would have.
+func aGeneratedAgentIDExists(ctx context.Context, agentID string) (context.Context, error) {
+	env := getTestEnv(ctx)
+	if env == nil {
+		return ctx, fmt.Errorf("test environment not initialized")
+	}
+
+	path := generatedAgentIDPath(env)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return ctx, fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(agentID+"\n"), 0644); err != nil {
+		return ctx, fmt.Errorf("failed to write agent ID: %w", err)
+	}
+	return ctx, nil
+}
+
+// stdoutShouldContainTheGeneratedAgentID verifies that the output shows the
+// persisted auto-generated agent ID.
+func stdoutShouldContainTheGeneratedAgentID(ctx context.Context) error {
+	env := getTestEnv(ctx)
+	if env == nil {
+		return fmt.Errorf("test environment not initialized")
+	}
+
+	agentID, err := readGeneratedAgentID(env)
+	if err != nil {
+		return err
+	}
+	return stdoutShouldContain(ctx, agentID)
+}
+
 // aLockFileShouldExistForTask verifies that a lock file exists for the task.
 func aLockFileShouldExistForTask(ctx context.Context, taskID string) error {
 	env := getTestEnv(ctx)
@@ -1698,25 +2259,22 @@ func taskHasExpiredLockMinutesAgo(ctx context.Context, taskID, agentID string, m
 	reader := support.NewTaskFileReader(env.Path(".backlog"))
 	task := reader.ReadTask(taskID)
 	if task.ParseErr == nil {
-		// Read the file content
-		taskContent, err := os.ReadFile(task.Path)
-		if err == nil {
-			contentStr := string(taskContent)
-			agentLabel := fmt.Sprintf("agent:%s", agentID)
-			if !strings.Contains(contentStr, agentLabel) {
-				if strings.Contains(contentStr, "labels: []") {
-					contentStr = strings.Replace(contentStr, "labels: []", fmt.Sprintf("labels: [%s]", agentLabel), 1)
-				} else if strings.Contains(contentStr, "labels: [") {
-					contentStr = strings.Replace(contentStr, "labels: [", fmt.Sprintf("labels: [%s, ", agentLabel), 1)
-				}
-				os.WriteFile(task.Path, []byte(contentStr), 0644)
-			}
+		if err := addAgentLabel(task, agentID); err != nil {
+			return ctx, err
 		}
 	}
 
 	return ctx, nil
 }
 
+// addAgentLabel adds the claim label for agentID to a task file.
+func addAgentLabel(task *support.TaskFile, agentID string) error {
+	if err := task.AddLabel(fmt.Sprintf("agent:%s", agentID)); err != nil {
+		return fmt.Errorf("failed to add agent label to task %s: %w", task.ID, err)
+	}
+	return task.Save()
+}
+
 // taskHasActiveLock creates an active (non-expired) lock file.
 func taskHasActiveLock(ctx context.Context, taskID, agentID string) (context.Context, error) {
 	env := getTestEnv(ctx)
@@ -1742,18 +2300,8 @@ func taskHasActiveLock(ctx context.Context, taskID, agentID string) (context.Con
 	reader := support.NewTaskFileReader(env.Path(".backlog"))
 	task := reader.ReadTask(taskID)
 	if task.ParseErr == nil {
-		taskContent, err := os.ReadFile(task.Path)
-		if err == nil {
-			contentStr := string(taskContent)
-			agentLabel := fmt.Sprintf("agent:%s", agentID)
-			if !strings.Contains(contentStr, agentLabel) {
-				if strings.Contains(contentStr, "labels: []") {
-					contentStr = strings.Replace(contentStr, "labels: []", fmt.Sprintf("labels: [%s]", agentLabel), 1)
-				} else if strings.Contains(contentStr, "labels: [") {
-					contentStr = strings.Replace(contentStr, "labels: [", fmt.Sprintf("labels: [%s, ", agentLabel), 1)
-				}
-				os.WriteFile(task.Path, []byte(contentStr), 0644)
-			}
+		if err := addAgentLabel(task, agentID); err != nil {
+			return ctx, err
 		}
 	}
 
@@ -1954,6 +2502,9 @@ func aRemoteGitRepository(ctx context.Context) (context.Context, error) {
 	if env == nil {
 		return ctx, fmt.Errorf("test environment not initialized")
 	}
+	if held, _ := ctx.Value(gitRemoteKey).(bool); !held {
+		return ctx, fmt.Errorf("scenarios that create a remote git repository must be tagged %s", gitRemoteTag)
+	}
 
 	// Create a bare repository in a temporary location
 	remoteDir, err := os.MkdirTemp("", "backlog-remote-*")
@@ -2149,6 +2700,31 @@ func noNewGitCommitsShouldExist(ctx context.Context) error {
 	return nil
 }
 
+// newGitCommitsShouldExist verifies how many commits were made since the
+// initial commit count was recorded.
+func newGitCommitsShouldExist(ctx context.Context, expected int) error {
+	env := getTestEnv(ctx)
+	if env == nil {
+		return fmt.Errorf("test environment not initialized")
+	}
+
+	initialCount, ok := ctx.Value(initialCommitCountKey).(int)
+	if !ok {
+		return fmt.Errorf("initial commit count not found in context")
+	}
+
+	currentCount, err := getGitCommitCount(env.TempDir)
+	if err != nil {
+		return fmt.Errorf("failed to get current commit count: %w", err)
+	}
+
+	if currentCount-initialCount != expected {
+		return fmt.Errorf("expected %d new commit(s) (initial: %d), but found %d commits", expected, initialCount, currentCount)
+	}
+
+	return nil
+}
+
 // thereAreUncommittedChangesInTheRepository creates uncommitted changes.
 func thereAreUncommittedChangesInTheRepository(ctx context.Context) (context.Context, error) {
 	env := getTestEnv(ctx)
@@ -2444,18 +3020,12 @@ func anotherAgentHasClaimedTaskAndPushed(ctx context.Context, taskID string) (co
 	} else {
 		// Modify the task file to add agent label
 		taskFile := matches[0]
-		content, err := os.ReadFile(taskFile)
-		if err != nil {
-			return ctx, fmt.Errorf("failed to read task file: %w", err)
-		}
-		contentStr := string(content)
-		if strings.Contains(contentStr, "labels: []") {
-			contentStr = strings.Replace(contentStr, "labels: []", "labels: [agent:other-agent]", 1)
-		} else if strings.Contains(contentStr, "labels: [") {
-			contentStr = strings.Replace(contentStr, "labels: [", "labels: [agent:other-agent, ", 1)
+		task := support.NewTaskFileReader(filepath.Join(cloneDir, ".backlog")).ReadTaskFile(taskFile)
+		if task.ParseErr != nil {
+			return ctx, fmt.Errorf("failed to read task file: %w", task.ParseErr)
 		}
-		if err := os.WriteFile(taskFile, []byte(contentStr), 0644); err != nil {
-			return ctx, fmt.Errorf("failed to write task file: %w", err)
+		if err := addAgentLabel(task, "other-agent"); err != nil {
+			return ctx, err
 		}
 
 		// Move file to in-progress if it's not already there
@@ -2486,6 +3056,125 @@ func anotherAgentHasClaimedTaskAndPushed(ctx context.Context, taskID string) (co
 	return ctx, nil
 }
 
+// setTaskTitle returns a change that replaces a task's title.
+func setTaskTitle(title string) func(*support.TaskFile) error {
+	return func(task *support.TaskFile) error {
+		return task.SetField("title", title)
+	}
+}
+
+// addTaskLabel returns a change that adds a label first in a task's labels.
+func addTaskLabel(label string) func(*support.TaskFile) error {
+	return func(task *support.TaskFile) error {
+		return task.AddLabel(label)
+	}
+}
+
+// commitTaskFileChange rewrites a task's file in the repository at dir with
+// change and commits it.
+func commitTaskFileChange(dir, taskID string, change func(*support.TaskFile) error) error {
+	matches, _ := filepath.Glob(filepath.Join(dir, ".backlog", "*", taskID+"-*.md"))
+	if len(matches) == 0 {
+		return fmt.Errorf("no task file for %s in %s", taskID, dir)
+	}
+	task := support.NewTaskFileReader(filepath.Join(dir, ".backlog")).ReadTaskFile(matches[0])
+	if task.ParseErr != nil {
+		return fmt.Errorf("failed to read task file: %w", task.ParseErr)
+	}
+	if err := change(task); err != nil {
+		return fmt.Errorf("failed to change task %s: %w", taskID, err)
+	}
+	if err := task.Save(); err != nil {
+		return err
+	}
+
+	for _, args := range [][]string{
+		{"add", "-A"},
+		{"-c", "user.email=test@example.com", "-c", "user.name=Test", "commit", "-m", "edit: " + taskID},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("git %s failed: %w\nOutput: %s", args[0], err, output)
+		}
+	}
+	return nil
+}
+
+// pushRemoteTaskChange commits and pushes the local backlog, then changes a
+// task in a separate clone of the remote and pushes that, as another agent
+// would.
+func pushRemoteTaskChange(ctx context.Context, taskID string, change func(*support.TaskFile) error) (context.Context, error) {
+	env := getTestEnv(ctx)
+	if env == nil {
+		return ctx, fmt.Errorf("test environment not initialized")
+	}
+	remotePath, ok := ctx.Value(remoteRepoPathKey).(string)
+	if !ok || remotePath == "" {
+		return ctx, fmt.Errorf("remote repository path not found in context")
+	}
+
+	cmd := exec.Command("git", "add", "-A")
+	cmd.Dir = env.TempDir
+	cmd.CombinedOutput()
+	cmd = exec.Command("git", "commit", "-m", "Add backlog tasks")
+	cmd.Dir = env.TempDir
+	cmd.CombinedOutput() // Ignore errors - might already be committed
+	cmd = exec.Command("git", "push")
+	cmd.Dir = env.TempDir
+	if output, err := cmd.CombinedOutput(); err != nil && !strings.Contains(string(output), "Everything up-to-date") {
+		return ctx, fmt.Errorf("failed to push local changes: %w\nOutput: %s", err, output)
+	}
+
+	cloneDir, err := os.MkdirTemp("", "backlog-other-agent-*")
+	if err != nil {
+		return ctx, fmt.Errorf("failed to create clone directory: %w", err)
+	}
+	defer os.RemoveAll(cloneDir)
+	cmd = exec.Command("git", "clone", remotePath, cloneDir)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return ctx, fmt.Errorf("failed to clone remote: %w\nOutput: %s", err, output)
+	}
+
+	if err := commitTaskFileChange(cloneDir, taskID, change); err != nil {
+		return ctx, err
+	}
+	cmd = exec.Command("git", "push")
+	cmd.Dir = cloneDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return ctx, fmt.Errorf("failed to push remote change: %w\nOutput: %s", err, output)
+	}
+	return ctx, nil
+}
+
+// theRemoteChangesTheTitleOfTask pushes a title change from another clone.
+func theRemoteChangesTheTitleOfTask(ctx context.Context, taskID, title string) (context.Context, error) {
+	return pushRemoteTaskChange(ctx, taskID, setTaskTitle(title))
+}
+
+// theRemoteAddsLabelToTask pushes a new label from another clone.
+func theRemoteAddsLabelToTask(ctx context.Context, label, taskID string) (context.Context, error) {
+	return pushRemoteTaskChange(ctx, taskID, addTaskLabel(label))
+}
+
+// aLocalCommitChangesTheTitleOfTask commits a title change without pushing.
+func aLocalCommitChangesTheTitleOfTask(ctx context.Context, taskID, title string) (context.Context, error) {
+	env := getTestEnv(ctx)
+	if env == nil {
+		return ctx, fmt.Errorf("test environment not initialized")
+	}
+	return ctx, commitTaskFileChange(env.TempDir, taskID, setTaskTitle(title))
+}
+
+// aLocalCommitAddsLabelToTask commits a new label without pushing.
+func aLocalCommitAddsLabelToTask(ctx context.Context, label, taskID string) (context.Context, error) {
+	env := getTestEnv(ctx)
+	if env == nil {
+		return ctx, fmt.Errorf("test environment not initialized")
+	}
+	return ctx, commitTaskFileChange(env.TempDir, taskID, addTaskLabel(label))
+}
+
 // taskHasStaleLockFile creates a stale (expired) lock file for a task.
 func taskHasStaleLockFile(ctx context.Context, taskID string) (context.Context, error) {
 	env := getTestEnv(ctx)
@@ -2532,21 +3221,68 @@ func theRemoteRepositoryIsUnreachable(ctx context.Context) (context.Context, err
 	return ctx, nil
 }
 
-// ============================================================================
-// Mock GitHub API Step Definitions
-// ============================================================================
-
-const (
-	mockGitHubServerKey  contextKey = "mockGitHubServer"
-	mockLinearServerKey  contextKey = "mockLinearServer"
-)
+func theRepositoryIsInADetachedHeadState(ctx context.Context) (context.Context, error) {
+	env := getTestEnv(ctx)
+	if env == nil {
+		return ctx, fmt.Errorf("test environment not initialized")
+	}
 
-// getMockGitHubServer retrieves the MockGitHubServer from context.
-func getMockGitHubServer(ctx context.Context) *support.MockGitHubServer {
-	if server, ok := ctx.Value(mockGitHubServerKey).(*support.MockGitHubServer); ok {
-		return server
+	// Commit everything so far, then detach HEAD as CI checkouts do
+	for _, args := range [][]string{
+		{"add", "-A"},
+		{"commit", "-q", "-m", "setup"},
+		{"checkout", "-q", "--detach"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = env.TempDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return ctx, fmt.Errorf("git %s failed: %w\nOutput: %s", args[0], err, output)
+		}
 	}
-	return nil
+
+	return ctx, nil
+}
+
+// theRepositoryIsTaggedAt commits everything so far and creates an annotated
+// tag dated at the given RFC3339 time.
+func theRepositoryIsTaggedAt(ctx context.Context, tag, date string) (context.Context, error) {
+	env := getTestEnv(ctx)
+	if env == nil {
+		return ctx, fmt.Errorf("test environment not initialized")
+	}
+
+	for _, args := range [][]string{
+		{"add", "-A"},
+		{"commit", "-q", "--allow-empty", "-m", "setup"},
+		{"tag", "-a", tag, "-m", "Release " + tag},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = env.TempDir
+		// The tagger date is the committer date
+		cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE="+date)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return ctx, fmt.Errorf("git %s failed: %w\nOutput: %s", args[0], err, output)
+		}
+	}
+
+	return ctx, nil
+}
+
+// ============================================================================
+// Mock GitHub API Step Definitions
+// ============================================================================
+
+const (
+	mockGitHubServerKey  contextKey = "mockGitHubServer"
+	mockLinearServerKey  contextKey = "mockLinearServer"
+)
+
+// getMockGitHubServer retrieves the MockGitHubServer from context.
+func getMockGitHubServer(ctx context.Context) *support.MockGitHubServer {
+	if server, ok := ctx.Value(mockGitHubServerKey).(*support.MockGitHubServer); ok {
+		return server
+	}
+	return nil
 }
 
 // getMockLinearServer retrieves the MockLinearServer from context.
@@ -2702,7 +3438,7 @@ func theEnvironmentVariableIsSetToAValidToken(ctx context.Context, key string) (
 	}
 
 	// Check if the token is already set in the real environment
-	if os.Getenv(key) == "" {
+	if env.Getenv(key) == "" {
 		return ctx, fmt.Errorf("environment variable %s must be set for remote tests", key)
 	}
 
@@ -2720,6 +3456,76 @@ func theMockGitHubAPIAuthenticatedUserIs(ctx context.Context, username string) (
 	return ctx, nil
 }
 
+// theMockGitHubAPIReturnsStatusFor makes matching mock GitHub API requests fail with the given status.
+func theMockGitHubAPIReturnsStatusFor(ctx context.Context, status int, method, pathPattern string) (context.Context, error) {
+	server := getMockGitHubServer(ctx)
+	if server == nil {
+		return ctx, fmt.Errorf("mock GitHub API server not running - call 'a mock GitHub API server is running' first")
+	}
+
+	body := fmt.Sprintf(`{"message":%q}`, http.StatusText(status))
+	server.SetEndpointFailure(method, pathPattern, status, body)
+	return ctx, nil
+}
+
+// theMockGitHubAPIReturnsMalformedJSONFor makes matching mock GitHub API requests return an unparseable body.
+func theMockGitHubAPIReturnsMalformedJSONFor(ctx context.Context, method, pathPattern string) (context.Context, error) {
+	server := getMockGitHubServer(ctx)
+	if server == nil {
+		return ctx, fmt.Errorf("mock GitHub API server not running - call 'a mock GitHub API server is running' first")
+	}
+
+	server.SetEndpointFailure(method, pathPattern, http.StatusOK, `{"number": 1, "title": `)
+	return ctx, nil
+}
+
+// theMockGitHubAPITimesOutAfter delays every mock GitHub API response by the given number of seconds.
+func theMockGitHubAPITimesOutAfter(ctx context.Context, seconds int) (context.Context, error) {
+	server := getMockGitHubServer(ctx)
+	if server == nil {
+		return ctx, fmt.Errorf("mock GitHub API server not running - call 'a mock GitHub API server is running' first")
+	}
+
+	server.SetLatency("*", time.Duration(seconds)*time.Second)
+	return ctx, nil
+}
+
+// theMockGitHubAPIFailsTheFirstRequests makes the next n mock GitHub API requests fail with a 500.
+func theMockGitHubAPIFailsTheFirstRequests(ctx context.Context, n int) (context.Context, error) {
+	server := getMockGitHubServer(ctx)
+	if server == nil {
+		return ctx, fmt.Errorf("mock GitHub API server not running - call 'a mock GitHub API server is running' first")
+	}
+
+	server.SetFailureCount(n)
+	return ctx, nil
+}
+
+// theMockGitHubAPIRateLimitHasRemaining makes the mock GitHub API report a
+// rate limit with the given requests left, resetting after a delay.
+func theMockGitHubAPIRateLimitHasRemaining(ctx context.Context, remaining, limit, seconds int) (context.Context, error) {
+	server := getMockGitHubServer(ctx)
+	if server == nil {
+		return ctx, fmt.Errorf("mock GitHub API server not running - call 'a mock GitHub API server is running' first")
+	}
+
+	server.SetRateLimit(limit, remaining, time.Now().Add(time.Duration(seconds)*time.Second))
+	return ctx, nil
+}
+
+// theMockGitHubAPIShouldHaveReceivedRequests verifies the number of requests the mock GitHub API received.
+func theMockGitHubAPIShouldHaveReceivedRequests(ctx context.Context, expected int) error {
+	server := getMockGitHubServer(ctx)
+	if server == nil {
+		return fmt.Errorf("mock GitHub API server not running")
+	}
+
+	if got := server.RequestCount(); got != expected {
+		return fmt.Errorf("expected mock GitHub API to receive %d requests, got %d", expected, got)
+	}
+	return nil
+}
+
 // theMockGitHubIssueHasTheFollowingComments sets up mock comments for a specific GitHub issue.
 func theMockGitHubIssueHasTheFollowingComments(ctx context.Context, issueNumber string, table *godog.Table) (context.Context, error) {
 	server := getMockGitHubServer(ctx)
@@ -2844,6 +3650,73 @@ func theGitHubIssueShouldHaveLabel(ctx context.Context, issueID, label string) e
 	return fmt.Errorf("GitHub issue %s does not have label %q (has labels: %v)", issueID, label, issue.Labels)
 }
 
+// theGitHubIssueShouldNotHaveLabel verifies that a GitHub issue lacks the specified label.
+func theGitHubIssueShouldNotHaveLabel(ctx context.Context, issueID, label string) error {
+	server := getMockGitHubServer(ctx)
+	if server == nil {
+		return fmt.Errorf("mock GitHub API server not running")
+	}
+
+	issue := server.GetIssue(parseGitHubIssueNumber(issueID))
+	if issue == nil {
+		return fmt.Errorf("GitHub issue %s not found in mock server", issueID)
+	}
+
+	for _, l := range issue.Labels {
+		if l == label {
+			return fmt.Errorf("GitHub issue %s should not have label %q (has labels: %v)", issueID, label, issue.Labels)
+		}
+	}
+
+	return nil
+}
+
+// theGitHubRepositoryHasLabelWithColor defines a label in the mock repository.
+func theGitHubRepositoryHasLabelWithColor(ctx context.Context, label, color string) error {
+	server := getMockGitHubServer(ctx)
+	if server == nil {
+		return fmt.Errorf("mock GitHub API server not running")
+	}
+
+	server.SetRepoLabel(label, color)
+	return nil
+}
+
+// theGitHubRepositoryLabelShouldHaveColor verifies that a label is defined in
+// the mock repository with the given color.
+func theGitHubRepositoryLabelShouldHaveColor(ctx context.Context, label, color string) error {
+	server := getMockGitHubServer(ctx)
+	if server == nil {
+		return fmt.Errorf("mock GitHub API server not running")
+	}
+
+	repoLabel := server.GetRepoLabel(label)
+	if repoLabel == nil {
+		return fmt.Errorf("GitHub repository label %q does not exist", label)
+	}
+	if repoLabel.Color != color {
+		return fmt.Errorf("GitHub repository label %q has color %q, want %q", label, repoLabel.Color, color)
+	}
+	return nil
+}
+
+// anotherClientAddsLabelDuringNextLabelChange simulates another client adding
+// a label to an issue between our read and our label update.
+func anotherClientAddsLabelDuringNextLabelChange(ctx context.Context, label, issueID string) error {
+	server := getMockGitHubServer(ctx)
+	if server == nil {
+		return fmt.Errorf("mock GitHub API server not running")
+	}
+
+	issueNumber := parseGitHubIssueNumber(issueID)
+	if issueNumber <= 0 {
+		return fmt.Errorf("invalid issue ID format: %s (expected 'GH-{number}' or '{number}')", issueID)
+	}
+
+	server.SetConcurrentLabels(issueNumber, label)
+	return nil
+}
+
 // theGitHubIssueShouldBeAssignedTo verifies that a GitHub issue is assigned to the specified user.
 // The issue ID should be in the format "GH-{number}" or just the number.
 func theGitHubIssueShouldBeAssignedTo(ctx context.Context, issueID, assignee string) error {
@@ -2870,6 +3743,30 @@ func theGitHubIssueShouldBeAssignedTo(ctx context.Context, issueID, assignee str
 	return nil
 }
 
+// theGitHubIssueShouldHaveBodyContaining verifies a GitHub issue body contains a multi-line block.
+func theGitHubIssueShouldHaveBodyContaining(ctx context.Context, issueID string, expected *godog.DocString) error {
+	server := getMockGitHubServer(ctx)
+	if server == nil {
+		return fmt.Errorf("mock GitHub API server not running")
+	}
+
+	issueNumber := parseGitHubIssueNumber(issueID)
+	if issueNumber <= 0 {
+		return fmt.Errorf("invalid issue ID format: %s (expected 'GH-{number}' or '{number}')", issueID)
+	}
+
+	issue := server.GetIssue(issueNumber)
+	if issue == nil {
+		return fmt.Errorf("GitHub issue %s not found in mock server", issueID)
+	}
+
+	if !strings.Contains(issue.Body, expected.Content) {
+		return fmt.Errorf("expected GitHub issue %s body to contain:\n%s\ngot:\n%s", issueID, expected.Content, issue.Body)
+	}
+
+	return nil
+}
+
 // parseGitHubIssueNumber extracts the issue number from an ID string.
 // Handles both "GH-42" and "42" formats.
 func parseGitHubIssueNumber(issueID string) int {
@@ -3150,6 +4047,134 @@ func theMockLinearAPIAuthenticatedUserIs(ctx context.Context, username string) (
 	return ctx, nil
 }
 
+// theMockLinearAPIHasTheFollowingWorkflowStates replaces the mock Linear team's workflow states.
+func theMockLinearAPIHasTheFollowingWorkflowStates(ctx context.Context, table *godog.Table) (context.Context, error) {
+	server := getMockLinearServer(ctx)
+	if server == nil {
+		return ctx, fmt.Errorf("mock Linear API server not running - call 'a mock Linear API server is running' first")
+	}
+
+	if len(table.Rows) < 2 {
+		return ctx, fmt.Errorf("table must have at least a header row and one data row")
+	}
+
+	header := table.Rows[0]
+	colIndex := make(map[string]int)
+	for i, cell := range header.Cells {
+		colIndex[cell.Value] = i
+	}
+
+	var states []support.MockLinearState
+	for _, row := range table.Rows[1:] {
+		getValue := func(col string) string {
+			if idx, ok := colIndex[col]; ok && idx < len(row.Cells) {
+				return row.Cells[idx].Value
+			}
+			return ""
+		}
+
+		state := support.MockLinearState{
+			ID:   getValue("id"),
+			Name: getValue("name"),
+			Type: getValue("type"),
+		}
+
+		// Generate ID if not specified
+		if state.ID == "" {
+			state.ID = "state-" + strings.ToLower(strings.ReplaceAll(state.Name, " ", "-"))
+		}
+
+		states = append(states, state)
+	}
+
+	server.SetWorkflowStates(states)
+	return ctx, nil
+}
+
+// theMockLinearAPIHasTheFollowingLabels registers labels with their colors and descriptions.
+func theMockLinearAPIHasTheFollowingLabels(ctx context.Context, table *godog.Table) (context.Context, error) {
+	server := getMockLinearServer(ctx)
+	if server == nil {
+		return ctx, fmt.Errorf("mock Linear API server not running - call 'a mock Linear API server is running' first")
+	}
+
+	if len(table.Rows) < 2 {
+		return ctx, fmt.Errorf("table must have at least a header row and one data row")
+	}
+
+	header := table.Rows[0]
+	colIndex := make(map[string]int)
+	for i, cell := range header.Cells {
+		colIndex[cell.Value] = i
+	}
+
+	var labels []support.MockLinearLabel
+	for _, row := range table.Rows[1:] {
+		getValue := func(col string) string {
+			if idx, ok := colIndex[col]; ok && idx < len(row.Cells) {
+				return row.Cells[idx].Value
+			}
+			return ""
+		}
+
+		labels = append(labels, support.MockLinearLabel{
+			Name:        getValue("name"),
+			Color:       getValue("color"),
+			Description: getValue("description"),
+		})
+	}
+
+	server.SetLabels(labels)
+	return ctx, nil
+}
+
+// theMockLinearIssueHasTheFollowingComments sets up mock comments for a specific Linear issue.
+func theMockLinearIssueHasTheFollowingComments(ctx context.Context, identifier string, table *godog.Table) (context.Context, error) {
+	server := getMockLinearServer(ctx)
+	if server == nil {
+		return ctx, fmt.Errorf("mock Linear API server not running - call 'a mock Linear API server is running' first")
+	}
+
+	if len(table.Rows) < 2 {
+		return ctx, fmt.Errorf("table must have at least a header row and one data row")
+	}
+
+	header := table.Rows[0]
+	colIndex := make(map[string]int)
+	for i, cell := range header.Cells {
+		colIndex[cell.Value] = i
+	}
+
+	var comments []support.MockLinearComment
+	for i, row := range table.Rows[1:] {
+		getValue := func(col string) string {
+			if idx, ok := colIndex[col]; ok && idx < len(row.Cells) {
+				return row.Cells[idx].Value
+			}
+			return ""
+		}
+
+		createdAt := time.Now().Add(time.Duration(i) * time.Minute)
+		if created := getValue("created"); created != "" {
+			t, err := time.Parse(time.RFC3339, created)
+			if err != nil {
+				return ctx, fmt.Errorf("invalid created time %q: %w", created, err)
+			}
+			createdAt = t
+		}
+
+		comments = append(comments, support.MockLinearComment{
+			ID:        fmt.Sprintf("comment-%s-%d", identifier, i+1),
+			Author:    getValue("author"),
+			Body:      getValue("body"),
+			CreatedAt: createdAt,
+		})
+	}
+
+	server.SetComments(identifier, comments)
+	return ctx, nil
+}
+
 // aLinearTeamWithIssues sets up a mock Linear team with the specified issues.
 // This is a convenience step that combines starting the mock server and setting up issues.
 func aLinearTeamWithIssues(ctx context.Context, teamKey string, table *godog.Table) (context.Context, error) {
@@ -3261,3 +4286,161 @@ func theMockGitHubAPIHasTheFollowingProjects(ctx context.Context, table *godog.T
 
 	return ctx, nil
 }
+
+// theLinearIssueShouldHaveDescriptionContaining verifies a Linear issue description contains a multi-line block.
+func theLinearIssueShouldHaveDescriptionContaining(ctx context.Context, issueID string, expected *godog.DocString) error {
+	server := getMockLinearServer(ctx)
+	if server == nil {
+		return fmt.Errorf("mock Linear API server not running")
+	}
+
+	issue := server.GetIssueByIdentifier(issueID)
+	if issue == nil {
+		issue = server.GetIssue(issueID)
+	}
+	if issue == nil {
+		return fmt.Errorf("issue %q not found", issueID)
+	}
+
+	if !strings.Contains(issue.Description, expected.Content) {
+		return fmt.Errorf("expected issue %q description to contain:\n%s\ngot:\n%s", issueID, expected.Content, issue.Description)
+	}
+
+	return nil
+}
+
+// theLinearIssueShouldBeArchived verifies that a Linear issue has been
+// archived, or with "not ", that it hasn't.
+func theLinearIssueShouldBeArchived(ctx context.Context, issueID, not string) error {
+	server := getMockLinearServer(ctx)
+	if server == nil {
+		return fmt.Errorf("mock Linear API server not running")
+	}
+
+	issue := server.GetIssueByIdentifier(issueID)
+	if issue == nil {
+		issue = server.GetIssue(issueID)
+	}
+
+	if issue == nil {
+		return fmt.Errorf("issue %q not found", issueID)
+	}
+
+	if not != "" && issue.Archived {
+		return fmt.Errorf("expected issue %q not to be archived", issueID)
+	}
+	if not == "" && !issue.Archived {
+		return fmt.Errorf("expected issue %q to be archived", issueID)
+	}
+
+	return nil
+}
+
+// theLinearIssueShouldHaveComments verifies the number of comments on a Linear issue.
+func theLinearIssueShouldHaveComments(ctx context.Context, issueID string, expected int) error {
+	server := getMockLinearServer(ctx)
+	if server == nil {
+		return fmt.Errorf("mock Linear API server not running")
+	}
+
+	if got := len(server.GetComments(issueID)); got != expected {
+		return fmt.Errorf("expected issue %q to have %d comments, got %d", issueID, expected, got)
+	}
+
+	return nil
+}
+
+// anExecWorkspaceUsingTheReferenceAdapter configures an exec workspace that
+// runs the reference adapter with every capability it supports.
+func anExecWorkspaceUsingTheReferenceAdapter(ctx context.Context) (context.Context, error) {
+	return anExecWorkspaceUsingTheReferenceAdapterWithCapabilities(ctx, "claim,comments")
+}
+
+// anExecWorkspaceUsingTheReferenceAdapterWithCapabilities configures an exec
+// workspace that runs the reference adapter advertising only the given
+// comma-separated capabilities, which may be empty.
+func anExecWorkspaceUsingTheReferenceAdapterWithCapabilities(ctx context.Context, capabilities string) (context.Context, error) {
+	env := getTestEnv(ctx)
+	if env == nil {
+		return ctx, fmt.Errorf("test environment not initialized")
+	}
+
+	adapterPath, err := support.ExecAdapterPath()
+	if err != nil {
+		return ctx, err
+	}
+
+	var caps []string
+	if capabilities != "" {
+		caps = strings.Split(capabilities, ",")
+	}
+	if !env.FileExists(".backlog") {
+		if err := env.CreateBacklogDir(); err != nil {
+			return ctx, fmt.Errorf("failed to create backlog directory: %w", err)
+		}
+	}
+	if err := env.CreateFile(".backlog/config.yaml", support.ExecWorkspaceConfig(adapterPath, caps)); err != nil {
+		return ctx, fmt.Errorf("failed to create config file: %w", err)
+	}
+	return ctx, nil
+}
+
+// theReferenceAdapterHasTheFollowingTasks writes the reference adapter's
+// task file from a table with id, title, status, priority, assignee, and
+// comma-separated labels columns.
+func theReferenceAdapterHasTheFollowingTasks(ctx context.Context, table *godog.Table) (context.Context, error) {
+	env := getTestEnv(ctx)
+	if env == nil {
+		return ctx, fmt.Errorf("test environment not initialized")
+	}
+	if len(table.Rows) < 2 {
+		return ctx, fmt.Errorf("table must have at least a header row and one data row")
+	}
+
+	colIndex := make(map[string]int)
+	for i, cell := range table.Rows[0].Cells {
+		colIndex[cell.Value] = i
+	}
+
+	now := time.Now().UTC()
+	var tasks []backend.Task
+	for _, row := range table.Rows[1:] {
+		getValue := func(col string) string {
+			if idx, ok := colIndex[col]; ok && idx < len(row.Cells) {
+				return row.Cells[idx].Value
+			}
+			return ""
+		}
+
+		task := backend.Task{
+			ID:       getValue("id"),
+			Title:    getValue("title"),
+			Status:   backend.Status(getValue("status")),
+			Priority: backend.Priority(getValue("priority")),
+			Assignee: getValue("assignee"),
+			Created:  now,
+			Updated:  now,
+		}
+		if task.Status == "" {
+			task.Status = backend.StatusTodo
+		}
+		if task.Priority == "" {
+			task.Priority = backend.PriorityNone
+		}
+		if labels := getValue("labels"); labels != "" {
+			for _, label := range strings.Split(labels, ",") {
+				task.Labels = append(task.Labels, strings.TrimSpace(label))
+			}
+		}
+		tasks = append(tasks, task)
+	}
+
+	content, err := support.ExecAdapterStoreContent(tasks)
+	if err != nil {
+		return ctx, err
+	}
+	if err := env.CreateFile(support.ExecAdapterStore, content); err != nil {
+		return ctx, fmt.Errorf("failed to write the reference adapter's tasks: %w", err)
+	}
+	return ctx, nil
+}
@@ -4,7 +4,9 @@ package support
 import (
 	"bytes"
 	"os/exec"
+	"sort"
 	"strings"
+	"time"
 )
 
 // CommandResult holds the result of executing a CLI command.
@@ -17,6 +19,13 @@ type CommandResult struct {
 	ExitCode int
 	// Command is the full command that was executed
 	Command string
+	// Args is the resolved argv, including the binary path, for debugging
+	Args []string
+	// Env is the environment added on top of the base environment,
+	// including runner-wide and per-call variables
+	Env []string
+	// Duration is how long the command took to run
+	Duration time.Duration
 	// Err is the underlying error if any (not including non-zero exit codes)
 	Err error
 }
@@ -29,6 +38,10 @@ type CLIRunner struct {
 	WorkDir string
 	// Env is additional environment variables to set
 	Env []string
+	// BaseEnv returns the environment that Env is added to. It defaults to
+	// the process environment; scenarios set it to TestEnv.Environ so that
+	// concurrent scenarios never share variables.
+	BaseEnv func() []string
 	// LastResult stores the result of the last command execution
 	LastResult *CommandResult
 }
@@ -75,9 +88,26 @@ func (r *CLIRunner) RunWithInput(input string, commandStr string) *CommandResult
 	return r.RunArgsWithInput(input, args...)
 }
 
+// RunWithEnv executes a command string with additional environment variables
+// that apply to this invocation only. They take precedence over the runner's Env.
+// Example: RunWithEnv("backlog list", map[string]string{"BACKLOG_AGENT_ID": "bot"})
+func (r *CLIRunner) RunWithEnv(commandStr string, env map[string]string) *CommandResult {
+	args := parseArgs(commandStr)
+	// Strip "backlog" prefix if present since the binary is already named "backlog"
+	if len(args) > 0 && args[0] == "backlog" {
+		args = args[1:]
+	}
+	return r.run("", env, args)
+}
+
 // RunArgsWithInput executes a command with explicit arguments and stdin input.
 // Example: RunArgsWithInput("y\n", "config", "init")
 func (r *CLIRunner) RunArgsWithInput(input string, args ...string) *CommandResult {
+	return r.run(input, nil, args)
+}
+
+// run executes the binary with the given stdin input, per-call environment and arguments.
+func (r *CLIRunner) run(input string, env map[string]string, args []string) *CommandResult {
 	cmd := exec.Command(r.BinaryPath, args...)
 
 	var stdout, stderr bytes.Buffer
@@ -92,16 +122,36 @@ func (r *CLIRunner) RunArgsWithInput(input string, args ...string) *CommandResul
 		cmd.Dir = r.WorkDir
 	}
 
-	// Always set cmd.Env to include parent environment plus any runner-specific env
-	// This ensures env vars set via os.Setenv are passed to the child process
-	cmd.Env = append(cmd.Environ(), r.Env...)
+	// cmd.Env is the base environment plus any runner-specific env.
+	// Per-call variables come last so they override earlier values.
+	extraEnv := append([]string{}, r.Env...)
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		extraEnv = append(extraEnv, key+"="+env[key])
+	}
+	base := cmd.Environ()
+	if r.BaseEnv != nil {
+		base = r.BaseEnv()
+		if cmd.Dir != "" {
+			base = append(base, "PWD="+cmd.Dir)
+		}
+	}
+	cmd.Env = append(base, extraEnv...)
 
+	start := time.Now()
 	err := cmd.Run()
 
 	result := &CommandResult{
-		Stdout:  stdout.String(),
-		Stderr:  stderr.String(),
-		Command: r.BinaryPath + " " + strings.Join(args, " "),
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		Command:  r.BinaryPath + " " + strings.Join(args, " "),
+		Args:     cmd.Args,
+		Env:      extraEnv,
+		Duration: time.Since(start),
 	}
 
 	// Get exit code
@@ -131,16 +181,30 @@ func (r *CLIRunner) ClearEnv() {
 	r.Env = []string{}
 }
 
-// parseArgs parses a command string into arguments.
-// Handles quoted strings and basic shell-like parsing.
+// parseArgs parses a command string into arguments using shell-like rules.
+// Single and double quotes group words (and may produce an empty argument),
+// a backslash escapes the next character (within double quotes, only a quote
+// or backslash), and any unquoted whitespace separates arguments.
 func parseArgs(commandStr string) []string {
 	var args []string
 	var current strings.Builder
+	inToken := false
 	inQuote := false
 	quoteChar := rune(0)
+	escaped := false
 
 	for _, char := range commandStr {
 		switch {
+		case escaped:
+			// Inside double quotes, only a quote or backslash can be escaped
+			if quoteChar == '"' && char != '"' && char != '\\' {
+				current.WriteRune('\\')
+			}
+			current.WriteRune(char)
+			escaped = false
+		case char == '\\' && quoteChar != '\'':
+			escaped = true
+			inToken = true
 		case char == '"' || char == '\'':
 			if inQuote {
 				if char == quoteChar {
@@ -152,18 +216,21 @@ func parseArgs(commandStr string) []string {
 			} else {
 				inQuote = true
 				quoteChar = char
+				inToken = true
 			}
-		case char == ' ' && !inQuote:
-			if current.Len() > 0 {
+		case (char == ' ' || char == '\t' || char == '\n') && !inQuote:
+			if inToken {
 				args = append(args, current.String())
 				current.Reset()
+				inToken = false
 			}
 		default:
 			current.WriteRune(char)
+			inToken = true
 		}
 	}
 
-	if current.Len() > 0 {
+	if inToken {
 		args = append(args, current.String())
 	}
 
@@ -53,6 +53,31 @@ func TestParseArgs(t *testing.T) {
 			input:    `edit GH-123 --title="New title" --priority=high`,
 			expected: []string{"edit", "GH-123", "--title=New title", "--priority=high"},
 		},
+		{
+			name:     "empty quoted argument",
+			input:    `comment task1 ''`,
+			expected: []string{"comment", "task1", ""},
+		},
+		{
+			name:     "escaped quote inside double quotes",
+			input:    `add "Say \"hi\" now"`,
+			expected: []string{"add", `Say "hi" now`},
+		},
+		{
+			name:     "escaped space",
+			input:    `add fix\ the\ thing`,
+			expected: []string{"add", "fix the thing"},
+		},
+		{
+			name:     "backslash is literal inside single quotes",
+			input:    `add 'C:\path'`,
+			expected: []string{"add", `C:\path`},
+		},
+		{
+			name:     "tabs separate arguments",
+			input:    "list\t--status=todo",
+			expected: []string{"list", "--status=todo"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -255,3 +280,89 @@ func TestCommandResultHelpers(t *testing.T) {
 		}
 	})
 }
+
+func TestCLIRunnerRunWithEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping on Windows - sh not available")
+	}
+
+	runner := NewCLIRunner("sh")
+	runner.SetEnv("BACKLOG_TEST_VAR", "runner")
+
+	result := runner.RunWithEnv(`-c 'echo "$BACKLOG_TEST_VAR"'`, map[string]string{"BACKLOG_TEST_VAR": "call"})
+	if result.StdoutTrimmed() != "call" {
+		t.Errorf("Stdout = %q, want per-call env to override runner env", result.StdoutTrimmed())
+	}
+
+	// Per-call env does not leak into later invocations
+	result = runner.Run(`-c 'echo "$BACKLOG_TEST_VAR"'`)
+	if result.StdoutTrimmed() != "runner" {
+		t.Errorf("Stdout = %q, want %q", result.StdoutTrimmed(), "runner")
+	}
+}
+
+func TestCLIRunnerBaseEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping on Windows - sh not available")
+	}
+
+	env, err := NewTestEnv()
+	if err != nil {
+		t.Fatalf("NewTestEnv() error = %v", err)
+	}
+	defer env.Cleanup()
+	env.SetEnv("BACKLOG_TEST_VAR", "scenario")
+
+	runner := NewCLIRunner("sh")
+	runner.BaseEnv = env.Environ
+	result := runner.Run(`-c 'echo "$BACKLOG_TEST_VAR"'`)
+	if result.StdoutTrimmed() != "scenario" {
+		t.Errorf("Stdout = %q, want the scenario's variable", result.StdoutTrimmed())
+	}
+
+	// Runner env is still applied on top of the base environment
+	runner.SetEnv("BACKLOG_TEST_VAR", "runner")
+	result = runner.Run(`-c 'echo "$BACKLOG_TEST_VAR"'`)
+	if result.StdoutTrimmed() != "runner" {
+		t.Errorf("Stdout = %q, want %q", result.StdoutTrimmed(), "runner")
+	}
+}
+
+func TestCLIRunnerRunWithInput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping on Windows - cat not available")
+	}
+
+	runner := NewCLIRunner("cat")
+	result := runner.RunWithInput("piped input\n", "")
+	if result.Stdout != "piped input\n" {
+		t.Errorf("Stdout = %q, want %q", result.Stdout, "piped input\n")
+	}
+}
+
+func TestCommandResultDebugInfo(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping on Windows - echo behavior differs")
+	}
+
+	runner := NewCLIRunner("echo")
+	result := runner.RunWithEnv(`backlog add 'fix the thing'`, map[string]string{"B": "2", "A": "1"})
+
+	wantArgs := []string{"echo", "add", "fix the thing"}
+	if len(result.Args) != len(wantArgs) {
+		t.Fatalf("Args = %q, want %q", result.Args, wantArgs)
+	}
+	for i := range wantArgs {
+		if result.Args[i] != wantArgs[i] {
+			t.Errorf("Args[%d] = %q, want %q", i, result.Args[i], wantArgs[i])
+		}
+	}
+
+	if len(result.Env) != 2 || result.Env[0] != "A=1" || result.Env[1] != "B=2" {
+		t.Errorf("Env = %q, want [A=1 B=2]", result.Env)
+	}
+
+	if result.Duration <= 0 {
+		t.Errorf("Duration = %v, want > 0", result.Duration)
+	}
+}
@@ -0,0 +1,75 @@
+package support
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// ExecAdapterStore is the file the reference exec adapter keeps its tasks
+// in, relative to the test directory it runs in.
+const ExecAdapterStore = "exec-tasks.json"
+
+// execAdapterPackage is the reference adapter's import path.
+const execAdapterPackage = "github.com/alexbrand/backlog/spec/cmd/execadapter"
+
+var (
+	execAdapterOnce sync.Once
+	execAdapterPath string
+	execAdapterErr  error
+)
+
+// ExecAdapterPath builds the reference exec backend program in
+// spec/cmd/execadapter, once per test run, and returns the path of the
+// binary.
+func ExecAdapterPath() (string, error) {
+	execAdapterOnce.Do(func() {
+		dir, err := os.MkdirTemp("", "backlog-execadapter-")
+		if err != nil {
+			execAdapterErr = err
+			return
+		}
+		path := filepath.Join(dir, "execadapter")
+		out, err := exec.Command("go", "build", "-o", path, execAdapterPackage).CombinedOutput()
+		if err != nil {
+			execAdapterErr = fmt.Errorf("failed to build the reference exec adapter: %v\n%s", err, out)
+			return
+		}
+		execAdapterPath = path
+	})
+	return execAdapterPath, execAdapterErr
+}
+
+// ExecWorkspaceConfig returns a config whose default workspace runs the
+// reference adapter at adapterPath, advertising capabilities.
+func ExecWorkspaceConfig(adapterPath string, capabilities []string) string {
+	return fmt.Sprintf(`version: 1
+defaults:
+  workspace: tracker
+workspaces:
+  tracker:
+    backend: exec
+    command: %s
+    args: ["-capabilities", %q]
+    default: true
+`, adapterPath, strings.Join(capabilities, ","))
+}
+
+// ExecAdapterStoreContent returns the reference adapter's task file holding
+// tasks. New tasks are numbered after them.
+func ExecAdapterStoreContent(tasks []backend.Task) (string, error) {
+	data, err := json.MarshalIndent(map[string]any{
+		"tasks":   tasks,
+		"next_id": len(tasks) + 1,
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
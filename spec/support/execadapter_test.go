@@ -0,0 +1,88 @@
+package support
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/execbackend"
+)
+
+func TestExecWorkspaceConfig(t *testing.T) {
+	var cfg struct {
+		Workspaces map[string]struct {
+			Backend string   `yaml:"backend"`
+			Command string   `yaml:"command"`
+			Args    []string `yaml:"args"`
+		} `yaml:"workspaces"`
+	}
+	content := ExecWorkspaceConfig("/tmp/execadapter", []string{"claim", "comments"})
+	if err := yaml.Unmarshal([]byte(content), &cfg); err != nil {
+		t.Fatalf("config is not valid YAML: %v\n%s", err, content)
+	}
+	ws := cfg.Workspaces["tracker"]
+	if ws.Backend != "exec" || ws.Command != "/tmp/execadapter" {
+		t.Errorf("workspace = %+v, want the exec backend running /tmp/execadapter", ws)
+	}
+	if len(ws.Args) != 2 || ws.Args[1] != "claim,comments" {
+		t.Errorf("args = %q, want the capabilities flag", ws.Args)
+	}
+}
+
+func TestExecAdapter(t *testing.T) {
+	path, err := ExecAdapterPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	store, err := ExecAdapterStoreContent([]backend.Task{{ID: "EXT-1", Title: "Existing", Status: backend.StatusTodo}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	storePath := filepath.Join(dir, ExecAdapterStore)
+	if err := os.WriteFile(storePath, []byte(store), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := execbackend.New()
+	err = e.Connect(backend.Config{
+		AgentID: "agent-1",
+		Workspace: &execbackend.WorkspaceConfig{
+			Command: path,
+			Args:    []string{"-store", storePath, "-capabilities", "claim"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer e.Disconnect()
+	b := e.Negotiated()
+
+	created, err := b.Create(backend.TaskInput{Title: "New"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if created.ID != "EXT-2" {
+		t.Errorf("created ID = %s, want EXT-2", created.ID)
+	}
+
+	claimer, ok := b.(backend.Claimer)
+	if !ok {
+		t.Fatal("adapter advertised claim, but the backend is not a Claimer")
+	}
+	result, err := claimer.Claim("EXT-1", "agent-1")
+	if err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	if result.Task.Status != backend.StatusInProgress {
+		t.Errorf("claimed status = %s, want in-progress", result.Task.Status)
+	}
+
+	if _, err := b.AddComment("EXT-1", "hi"); err == nil {
+		t.Error("AddComment succeeded, but the adapter didn't advertise comments")
+	}
+}
@@ -28,6 +28,10 @@ type TaskFixture struct {
 	Assignee    string           `yaml:"assignee,omitempty"`
 	Labels      []string         `yaml:"labels,omitempty"`
 	AgentID     string           `yaml:"agent_id,omitempty"`
+	Created     string           `yaml:"created,omitempty"`
+	Updated     string           `yaml:"updated,omitempty"`
+	CompletedAt string           `yaml:"completed_at,omitempty"`
+	URL         string           `yaml:"url,omitempty"`
 	Comments    []CommentFixture `yaml:"comments,omitempty"`
 }
 
@@ -206,6 +210,22 @@ func (l *FixtureLoader) createTaskFile(env *TestEnv, task *TaskFixture) error {
 		frontmatter.WriteString(fmt.Sprintf("agent_id: %s\n", task.AgentID))
 	}
 
+	if task.Created != "" {
+		frontmatter.WriteString(fmt.Sprintf("created: %s\n", task.Created))
+	}
+
+	if task.Updated != "" {
+		frontmatter.WriteString(fmt.Sprintf("updated: %s\n", task.Updated))
+	}
+
+	if task.CompletedAt != "" {
+		frontmatter.WriteString(fmt.Sprintf("completed_at: %s\n", task.CompletedAt))
+	}
+
+	if task.URL != "" {
+		frontmatter.WriteString(fmt.Sprintf("url: %s\n", task.URL))
+	}
+
 	frontmatter.WriteString("---\n")
 
 	// Build content
@@ -25,6 +25,13 @@ type MockGitHubIssue struct {
 	Body     string
 }
 
+// MockGitHubLabel represents a label defined in the repository.
+type MockGitHubLabel struct {
+	Name        string
+	Color       string
+	Description string
+}
+
 // MockGitHubComment represents a comment on a GitHub issue.
 type MockGitHubComment struct {
 	ID     int
@@ -65,6 +72,9 @@ type MockGitHubServer struct {
 	// Comments stored by issue number
 	Comments map[int][]MockGitHubComment
 
+	// RepoLabels are the labels defined in the repository, by name
+	RepoLabels map[string]*MockGitHubLabel
+
 	// ExpectedToken if set, validates Authorization header
 	ExpectedToken string
 
@@ -100,6 +110,47 @@ type MockGitHubServer struct {
 
 	// ProjectListError if set, returns this error for list projects queries
 	ProjectListError string
+
+	// endpointFailures are canned responses returned instead of the normal handler
+	endpointFailures []mockEndpointFailure
+
+	// latencies are delays applied before handling matching requests
+	latencies []mockEndpointLatency
+
+	// failureCount is the number of remaining requests that should fail with a 500
+	failureCount int
+
+	// requestCount is the total number of requests received
+	requestCount int
+
+	// concurrentLabels are added to an issue just before the next request
+	// that adds labels to it, simulating another client racing the change
+	concurrentLabels map[int][]string
+
+	// rateLimit, if set, is reported in X-RateLimit-* headers and spent by
+	// each request
+	rateLimit *mockRateLimit
+}
+
+// mockRateLimit is the state of the mock API rate limit.
+type mockRateLimit struct {
+	limit     int
+	remaining int
+	reset     time.Time
+}
+
+// mockEndpointFailure is a canned response for requests matching a method and path pattern.
+type mockEndpointFailure struct {
+	method  string
+	pattern *regexp.Regexp
+	status  int
+	body    string
+}
+
+// mockEndpointLatency is a delay applied to requests matching a path pattern.
+type mockEndpointLatency struct {
+	pattern *regexp.Regexp
+	delay   time.Duration
 }
 
 // NewMockGitHubServer creates and starts a new mock GitHub API server.
@@ -107,6 +158,7 @@ func NewMockGitHubServer() *MockGitHubServer {
 	mock := &MockGitHubServer{
 		Issues:            make(map[int]*MockGitHubIssue),
 		Comments:          make(map[int][]MockGitHubComment),
+		RepoLabels:        make(map[string]*MockGitHubLabel),
 		AuthenticatedUser: "test-user",
 		NextIssueNumber:   1,
 		NextCommentID:     1,
@@ -133,12 +185,143 @@ func NewMockGitHubServer() *MockGitHubServer {
 	mux.HandleFunc("/repos/", mock.handleRepos)
 	mux.HandleFunc("/api/v3/repos/", mock.handleRepos)
 
-	mock.Server = httptest.NewServer(mux)
+	mock.Server = httptest.NewServer(mock.injectFailures(mux))
 	mock.URL = mock.Server.URL
 
 	return mock
 }
 
+// SetEndpointFailure makes requests matching method and pathPattern return the
+// given status and body instead of being handled normally. An empty method or
+// "*" matches any method. In pathPattern, "*" matches any sequence of
+// characters, including "/" (e.g., "/repos/*/issues").
+func (m *MockGitHubServer) SetEndpointFailure(method, pathPattern string, status int, body string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.endpointFailures = append(m.endpointFailures, mockEndpointFailure{
+		method:  strings.ToUpper(method),
+		pattern: compilePathPattern(pathPattern),
+		status:  status,
+		body:    body,
+	})
+}
+
+// SetLatency delays requests matching pathPattern by the given duration.
+// The delay is abandoned if the client gives up on the request.
+func (m *MockGitHubServer) SetLatency(pathPattern string, delay time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.latencies = append(m.latencies, mockEndpointLatency{
+		pattern: compilePathPattern(pathPattern),
+		delay:   delay,
+	})
+}
+
+// SetFailureCount makes the next n requests fail with a 500 before the server
+// resumes handling requests normally.
+func (m *MockGitHubServer) SetFailureCount(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failureCount = n
+}
+
+// SetRateLimit reports a rate limit of limit requests in X-RateLimit-*
+// headers, with remaining requests left until reset. Each request spends
+// one; once none are left, requests fail with a 403 like GitHub's until the
+// reset, when the full limit is restored for another hour.
+func (m *MockGitHubServer) SetRateLimit(limit, remaining int, reset time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// The headers carry whole seconds, so the reset is too
+	m.rateLimit = &mockRateLimit{limit: limit, remaining: remaining, reset: reset.Truncate(time.Second)}
+}
+
+// RequestCount returns the total number of requests the server has received.
+func (m *MockGitHubServer) RequestCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.requestCount
+}
+
+// compilePathPattern converts a path pattern where "*" matches any sequence of
+// characters into an anchored regular expression.
+func compilePathPattern(pattern string) *regexp.Regexp {
+	quoted := regexp.QuoteMeta(pattern)
+	return regexp.MustCompile("^" + strings.ReplaceAll(quoted, `\*`, ".*") + "$")
+}
+
+// injectFailures wraps next with the configured latency and failure behavior.
+func (m *MockGitHubServer) injectFailures(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/api/v3")
+
+		m.mu.Lock()
+		m.requestCount++
+		failNext := m.failureCount > 0
+		if failNext {
+			m.failureCount--
+		}
+		var delay time.Duration
+		for _, l := range m.latencies {
+			if l.pattern.MatchString(path) {
+				delay += l.delay
+			}
+		}
+		rateLimited := false
+		if rl := m.rateLimit; rl != nil {
+			if !time.Now().Before(rl.reset) {
+				rl.remaining = rl.limit
+				rl.reset = time.Now().Add(time.Hour).Truncate(time.Second)
+			}
+			if rl.remaining > 0 {
+				rl.remaining--
+			} else {
+				rateLimited = true
+			}
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(rl.limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(rl.remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(rl.reset.Unix(), 10))
+			w.Header().Set("X-RateLimit-Resource", "core")
+		}
+		var failure *mockEndpointFailure
+		for i := range m.endpointFailures {
+			f := &m.endpointFailures[i]
+			if (f.method == "" || f.method == "*" || f.method == r.Method) && f.pattern.MatchString(path) {
+				failure = f
+				break
+			}
+		}
+		m.mu.Unlock()
+
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		if rateLimited {
+			m.writeError(w, http.StatusForbidden, "API rate limit exceeded", "https://docs.github.com/rest/overview/resources-in-the-rest-api#rate-limiting")
+			return
+		}
+
+		if failNext {
+			m.writeError(w, http.StatusInternalServerError, "Server Error", "Server Error")
+			return
+		}
+
+		if failure != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(failure.status)
+			io.WriteString(w, failure.body)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 // Close shuts down the mock server.
 func (m *MockGitHubServer) Close() {
 	if m.Server != nil {
@@ -218,6 +401,32 @@ func (m *MockGitHubServer) SetComments(issueNumber int, comments []MockGitHubCom
 	m.Comments[issueNumber] = comments
 }
 
+// SetConcurrentLabels makes the next add-labels request for an issue find
+// the given labels already present, as if another client added them first.
+func (m *MockGitHubServer) SetConcurrentLabels(issueNumber int, labels ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.concurrentLabels == nil {
+		m.concurrentLabels = make(map[int][]string)
+	}
+	m.concurrentLabels[issueNumber] = labels
+}
+
+// SetRepoLabel defines a label in the repository.
+func (m *MockGitHubServer) SetRepoLabel(name, color string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.RepoLabels[name] = &MockGitHubLabel{Name: name, Color: color}
+}
+
+// GetRepoLabel retrieves a repository label by name for assertions.
+// Returns nil if the label does not exist.
+func (m *MockGitHubServer) GetRepoLabel(name string) *MockGitHubLabel {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.RepoLabels[name]
+}
+
 // GetIssue retrieves an issue by number for assertions.
 // Returns nil if the issue does not exist.
 func (m *MockGitHubServer) GetIssue(number int) *MockGitHubIssue {
@@ -292,6 +501,8 @@ func (m *MockGitHubServer) handleRepos(w http.ResponseWriter, r *http.Request) {
 	// /repos/{owner}/{repo}/issues/{number}/comments
 	// /repos/{owner}/{repo}/issues/{number}/labels
 	// /repos/{owner}/{repo}/issues/{number}/labels/{name}
+	// /repos/{owner}/{repo}/labels
+	// /repos/{owner}/{repo}/labels/{name}
 
 	repoPattern := regexp.MustCompile(`^/repos/([^/]+)/([^/]+)$`)
 	issuesListPattern := regexp.MustCompile(`^/repos/[^/]+/[^/]+/issues$`)
@@ -299,6 +510,8 @@ func (m *MockGitHubServer) handleRepos(w http.ResponseWriter, r *http.Request) {
 	commentsPattern := regexp.MustCompile(`^/repos/[^/]+/[^/]+/issues/(\d+)/comments$`)
 	labelsPattern := regexp.MustCompile(`^/repos/[^/]+/[^/]+/issues/(\d+)/labels$`)
 	labelPattern := regexp.MustCompile(`^/repos/[^/]+/[^/]+/issues/(\d+)/labels/(.+)$`)
+	repoLabelsPattern := regexp.MustCompile(`^/repos/[^/]+/[^/]+/labels$`)
+	repoLabelPattern := regexp.MustCompile(`^/repos/[^/]+/[^/]+/labels/(.+)$`)
 
 	switch {
 	case repoPattern.MatchString(path):
@@ -306,6 +519,11 @@ func (m *MockGitHubServer) handleRepos(w http.ResponseWriter, r *http.Request) {
 		m.handleRepository(w, r, matches[1], matches[2])
 	case issuesListPattern.MatchString(path):
 		m.handleIssuesList(w, r)
+	case repoLabelsPattern.MatchString(path):
+		m.createRepoLabel(w, r)
+	case repoLabelPattern.MatchString(path):
+		matches := repoLabelPattern.FindStringSubmatch(path)
+		m.getRepoLabel(w, r, matches[1])
 	case commentsPattern.MatchString(path):
 		matches := commentsPattern.FindStringSubmatch(path)
 		issueNumber, _ := strconv.Atoi(matches[1])
@@ -515,12 +733,12 @@ func (m *MockGitHubServer) updateIssue(w http.ResponseWriter, r *http.Request, n
 	bodyBytes, _ := io.ReadAll(r.Body)
 
 	var input struct {
-		Title     *string  `json:"title,omitempty"`
-		Body      *string  `json:"body,omitempty"`
-		State     *string  `json:"state,omitempty"`
-		Labels    []string `json:"labels,omitempty"`
-		Assignee  *string  `json:"assignee,omitempty"`
-		Assignees []string `json:"assignees,omitempty"`
+		Title     *string   `json:"title,omitempty"`
+		Body      *string   `json:"body,omitempty"`
+		State     *string   `json:"state,omitempty"`
+		Labels    *[]string `json:"labels,omitempty"`
+		Assignee  *string   `json:"assignee,omitempty"`
+		Assignees []string  `json:"assignees,omitempty"`
 	}
 
 	if err := json.Unmarshal(bodyBytes, &input); err != nil {
@@ -538,8 +756,8 @@ func (m *MockGitHubServer) updateIssue(w http.ResponseWriter, r *http.Request, n
 		issue.State = *input.State
 	}
 	// Always update labels if provided in request (even if empty)
-	if len(input.Labels) > 0 {
-		issue.Labels = input.Labels
+	if input.Labels != nil {
+		issue.Labels = *input.Labels
 	}
 	if input.Assignee != nil {
 		issue.Assignee = *input.Assignee
@@ -684,35 +902,38 @@ func (m *MockGitHubServer) addLabels(w http.ResponseWriter, r *http.Request, iss
 		return
 	}
 
-	var input struct {
-		Labels []string `json:"labels"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+	labels, err := decodeLabelsRequest(r)
+	if err != nil {
 		m.writeError(w, http.StatusBadRequest, "Invalid JSON", err.Error())
 		return
 	}
 
+	// Apply labels from a simulated concurrent client first
+	if racing, ok := m.concurrentLabels[issueNumber]; ok {
+		issue.Labels = append(issue.Labels, racing...)
+		delete(m.concurrentLabels, issueNumber)
+	}
+
 	// Add labels, avoiding duplicates
 	existing := make(map[string]bool)
 	for _, label := range issue.Labels {
 		existing[label] = true
 	}
-	for _, label := range input.Labels {
+	for _, label := range labels {
 		if !existing[label] {
 			issue.Labels = append(issue.Labels, label)
 		}
 	}
 
-	var labels []map[string]interface{}
+	var result []map[string]interface{}
 	for _, label := range issue.Labels {
-		labels = append(labels, map[string]interface{}{
+		result = append(result, map[string]interface{}{
 			"name": label,
 		})
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(labels)
+	json.NewEncoder(w).Encode(result)
 }
 
 // setLabels handles PUT /repos/{owner}/{repo}/issues/{number}/labels
@@ -726,26 +947,45 @@ func (m *MockGitHubServer) setLabels(w http.ResponseWriter, r *http.Request, iss
 		return
 	}
 
-	var input struct {
-		Labels []string `json:"labels"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+	labels, err := decodeLabelsRequest(r)
+	if err != nil {
 		m.writeError(w, http.StatusBadRequest, "Invalid JSON", err.Error())
 		return
 	}
 
-	issue.Labels = input.Labels
+	issue.Labels = labels
 
-	var labels []map[string]interface{}
+	var result []map[string]interface{}
 	for _, label := range issue.Labels {
-		labels = append(labels, map[string]interface{}{
+		result = append(result, map[string]interface{}{
 			"name": label,
 		})
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(labels)
+	json.NewEncoder(w).Encode(result)
+}
+
+// decodeLabelsRequest reads the labels from an add/set labels request. Like
+// the real API, it accepts both a bare array and a {"labels": [...]} object.
+func decodeLabelsRequest(r *http.Request) ([]string, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var labels []string
+	if err := json.Unmarshal(body, &labels); err == nil {
+		return labels, nil
+	}
+
+	var input struct {
+		Labels []string `json:"labels"`
+	}
+	if err := json.Unmarshal(body, &input); err != nil {
+		return nil, err
+	}
+	return input.Labels, nil
 }
 
 // handleLabel handles DELETE /repos/{owner}/{repo}/issues/{number}/labels/{name}
@@ -785,6 +1025,67 @@ func (m *MockGitHubServer) handleLabel(w http.ResponseWriter, r *http.Request, i
 	json.NewEncoder(w).Encode(labels)
 }
 
+// getRepoLabel handles GET /repos/{owner}/{repo}/labels/{name}
+func (m *MockGitHubServer) getRepoLabel(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodGet {
+		m.writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed", "Method Not Allowed")
+		return
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	label, ok := m.RepoLabels[name]
+	if !ok {
+		m.writeError(w, http.StatusNotFound, "Not Found", fmt.Sprintf("Label %q not found", name))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(repoLabelToJSON(label))
+}
+
+// createRepoLabel handles POST /repos/{owner}/{repo}/labels
+func (m *MockGitHubServer) createRepoLabel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		m.writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed", "Method Not Allowed")
+		return
+	}
+
+	var input struct {
+		Name        string `json:"name"`
+		Color       string `json:"color"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		m.writeError(w, http.StatusBadRequest, "Invalid JSON", err.Error())
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.RepoLabels[input.Name]; exists {
+		m.writeError(w, http.StatusUnprocessableEntity, "Validation Failed", "already_exists")
+		return
+	}
+	label := &MockGitHubLabel{Name: input.Name, Color: input.Color, Description: input.Description}
+	m.RepoLabels[input.Name] = label
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(repoLabelToJSON(label))
+}
+
+// repoLabelToJSON converts a MockGitHubLabel to the GitHub API JSON format.
+func repoLabelToJSON(label *MockGitHubLabel) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        label.Name,
+		"color":       label.Color,
+		"description": label.Description,
+	}
+}
+
 // issueToJSON converts a MockGitHubIssue to the GitHub API JSON format.
 func (m *MockGitHubServer) issueToJSON(issue *MockGitHubIssue) map[string]interface{} {
 	result := map[string]interface{}{
@@ -796,6 +1097,7 @@ func (m *MockGitHubServer) issueToJSON(issue *MockGitHubIssue) map[string]interf
 		"updated_at": time.Now().Format(time.RFC3339),
 		"html_url":   fmt.Sprintf("https://github.com/test-owner/test-repo/issues/%d", issue.Number),
 		"url":        fmt.Sprintf("https://api.github.com/repos/test-owner/test-repo/issues/%d", issue.Number),
+		"comments":   len(m.Comments[issue.Number]),
 	}
 
 	// Add labels array
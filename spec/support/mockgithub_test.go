@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestMockGitHubServer_NewServer(t *testing.T) {
@@ -428,3 +430,117 @@ func TestMockGitHubServer_Pagination(t *testing.T) {
 		t.Error("expected Link header for pagination")
 	}
 }
+
+func TestMockGitHubServer_EndpointFailure(t *testing.T) {
+	server := NewMockGitHubServer()
+	defer server.Close()
+	server.SetEndpointFailure("POST", "/repos/*/issues", http.StatusInternalServerError, `{"message":"boom"}`)
+
+	// Matching method and path returns the canned failure
+	resp, err := http.Post(server.URL+"/repos/owner/repo/issues", "application/json", strings.NewReader(`{"title":"x"}`))
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", resp.StatusCode)
+	}
+	if string(body) != `{"message":"boom"}` {
+		t.Errorf("expected canned body, got %q", body)
+	}
+
+	// Other methods on the same path are handled normally
+	resp, err = http.Get(server.URL + "/repos/owner/repo/issues")
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestMockGitHubServer_FailureCount(t *testing.T) {
+	server := NewMockGitHubServer()
+	defer server.Close()
+	server.SetFailureCount(2)
+
+	var statuses []int
+	for i := 0; i < 3; i++ {
+		resp, err := http.Get(server.URL + "/user")
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		resp.Body.Close()
+		statuses = append(statuses, resp.StatusCode)
+	}
+
+	want := []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK}
+	for i := range want {
+		if statuses[i] != want[i] {
+			t.Errorf("request %d: expected status %d, got %d", i+1, want[i], statuses[i])
+		}
+	}
+	if server.RequestCount() != 3 {
+		t.Errorf("expected 3 requests, got %d", server.RequestCount())
+	}
+}
+
+func TestMockGitHubServer_RateLimit(t *testing.T) {
+	server := NewMockGitHubServer()
+	defer server.Close()
+	reset := time.Now().Add(2 * time.Second)
+	server.SetRateLimit(5000, 1, reset)
+
+	get := func() *http.Response {
+		t.Helper()
+		resp, err := http.Get(server.URL + "/user")
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	resp := get()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("first request: status %d, remaining %q; want 200 with 0 remaining", resp.StatusCode, resp.Header.Get("X-RateLimit-Remaining"))
+	}
+	if got, want := resp.Header.Get("X-RateLimit-Reset"), strconv.FormatInt(reset.Unix(), 10); got != want {
+		t.Errorf("X-RateLimit-Reset = %q, want %q", got, want)
+	}
+	if got := resp.Header.Get("X-RateLimit-Limit"); got != "5000" {
+		t.Errorf("X-RateLimit-Limit = %q, want 5000", got)
+	}
+
+	if resp := get(); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("exhausted request: status %d, want 403", resp.StatusCode)
+	}
+
+	time.Sleep(time.Until(reset.Truncate(time.Second)))
+	resp = get()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("X-RateLimit-Remaining") != "4999" {
+		t.Errorf("after reset: status %d, remaining %q; want 200 with 4999 remaining", resp.StatusCode, resp.Header.Get("X-RateLimit-Remaining"))
+	}
+}
+
+func TestMockGitHubServer_Latency(t *testing.T) {
+	server := NewMockGitHubServer()
+	defer server.Close()
+	server.SetLatency("/user", 200*time.Millisecond)
+
+	client := &http.Client{Timeout: 50 * time.Millisecond}
+	if _, err := client.Get(server.URL + "/user"); err == nil {
+		t.Error("expected request to time out")
+	}
+
+	resp, err := http.Get(server.URL + "/repos/owner/repo/issues")
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -21,6 +23,17 @@ type MockLinearIssue struct {
 	Assignee    string
 	Labels      []string
 	TeamKey     string // e.g., "ENG"
+	Parent      string // ID of the parent issue, set via parentId
+	Archived    bool   // set by the issueArchive mutation, cleared by issueUnarchive
+	UpdatedAt   time.Time
+}
+
+// MockLinearComment represents a comment on a Linear issue.
+type MockLinearComment struct {
+	ID        string
+	Author    string
+	Body      string
+	CreatedAt time.Time
 }
 
 // MockLinearTeam represents a team in the mock Linear API.
@@ -37,6 +50,13 @@ type MockLinearState struct {
 	Type string // e.g., "backlog", "unstarted", "started", "completed", "canceled"
 }
 
+// MockLinearLabel holds the metadata of an issue label in Linear.
+type MockLinearLabel struct {
+	Name        string
+	Color       string
+	Description string
+}
+
 // MockLinearServer provides a mock implementation of the Linear GraphQL API for testing.
 type MockLinearServer struct {
 	Server *httptest.Server
@@ -54,6 +74,18 @@ type MockLinearServer struct {
 	// States stored by name (workflow states)
 	States map[string]*MockLinearState
 
+	// stateOrder lists state names in the order they are returned by the API
+	stateOrder []string
+
+	// Comments stored by issue ID
+	Comments map[string][]MockLinearComment
+
+	// Labels is the set of issue labels created via issueLabelCreate
+	Labels map[string]bool
+
+	// LabelDetails holds label colors and descriptions, keyed by name
+	LabelDetails map[string]MockLinearLabel
+
 	// ExpectedAPIKey if set, validates Authorization header
 	ExpectedAPIKey string
 
@@ -73,6 +105,9 @@ func NewMockLinearServer() *MockLinearServer {
 		Issues:            make(map[string]*MockLinearIssue),
 		Teams:             make(map[string]*MockLinearTeam),
 		States:            make(map[string]*MockLinearState),
+		Comments:          make(map[string][]MockLinearComment),
+		Labels:            make(map[string]bool),
+		LabelDetails:      make(map[string]MockLinearLabel),
 		AuthenticatedUser: "test-user",
 		NextIssueNumber:   1,
 	}
@@ -85,17 +120,13 @@ func NewMockLinearServer() *MockLinearServer {
 	}
 
 	// Set up default workflow states
-	defaultStates := []MockLinearState{
+	mock.SetWorkflowStates([]MockLinearState{
 		{ID: "state-backlog", Name: "Backlog", Type: "backlog"},
 		{ID: "state-todo", Name: "Todo", Type: "unstarted"},
 		{ID: "state-inprogress", Name: "In Progress", Type: "started"},
 		{ID: "state-review", Name: "In Review", Type: "started"},
 		{ID: "state-done", Name: "Done", Type: "completed"},
-	}
-	for _, state := range defaultStates {
-		s := state // Create a copy for the pointer
-		mock.States[state.Name] = &s
-	}
+	})
 
 	mux := http.NewServeMux()
 
@@ -122,10 +153,62 @@ func (m *MockLinearServer) SetIssues(issues []MockLinearIssue) {
 	m.Issues = make(map[string]*MockLinearIssue)
 	for i := range issues {
 		issue := issues[i]
+		if issue.UpdatedAt.IsZero() {
+			issue.UpdatedAt = time.Now()
+		}
 		m.Issues[issue.ID] = &issue
+
+		// Keep generated identifiers from colliding with the ones provided
+		if n := identifierNumber(issue.Identifier); n >= m.NextIssueNumber {
+			m.NextIssueNumber = n + 1
+		}
 	}
 }
 
+// SetWorkflowStates replaces the team's workflow states. States are returned
+// by the API in the order given.
+func (m *MockLinearServer) SetWorkflowStates(states []MockLinearState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.States = make(map[string]*MockLinearState)
+	m.stateOrder = nil
+	for i := range states {
+		state := states[i]
+		m.States[state.Name] = &state
+		m.stateOrder = append(m.stateOrder, state.Name)
+	}
+}
+
+// SetLabels registers issue labels with their colors and descriptions.
+func (m *MockLinearServer) SetLabels(labels []MockLinearLabel) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, label := range labels {
+		m.Labels[label.Name] = true
+		m.LabelDetails[label.Name] = label
+	}
+}
+
+// SetComments sets the comments for an issue, looked up by ID or identifier.
+func (m *MockLinearServer) SetComments(issueID string, comments []MockLinearComment) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if issue := m.findIssue(issueID); issue != nil {
+		issueID = issue.ID
+	}
+	m.Comments[issueID] = comments
+}
+
+// GetComments retrieves the comments for an issue, looked up by ID or identifier.
+func (m *MockLinearServer) GetComments(issueID string) []MockLinearComment {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if issue := m.findIssue(issueID); issue != nil {
+		issueID = issue.ID
+	}
+	return m.Comments[issueID]
+}
+
 // GetIssue retrieves an issue by ID for assertions.
 func (m *MockLinearServer) GetIssue(id string) *MockLinearIssue {
 	m.mu.RLock()
@@ -145,6 +228,35 @@ func (m *MockLinearServer) GetIssueByIdentifier(identifier string) *MockLinearIs
 	return nil
 }
 
+// findIssue looks up an issue by ID or identifier. Callers must hold m.mu.
+func (m *MockLinearServer) findIssue(id string) *MockLinearIssue {
+	if issue, ok := m.Issues[id]; ok {
+		return issue
+	}
+	for _, issue := range m.Issues {
+		if issue.Identifier == id {
+			return issue
+		}
+	}
+	return nil
+}
+
+// sortedIssues returns issues ordered by team and identifier number so
+// responses are deterministic. Callers must hold m.mu.
+func (m *MockLinearServer) sortedIssues() []*MockLinearIssue {
+	issues := make([]*MockLinearIssue, 0, len(m.Issues))
+	for _, issue := range m.Issues {
+		issues = append(issues, issue)
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].TeamKey != issues[j].TeamKey {
+			return issues[i].TeamKey < issues[j].TeamKey
+		}
+		return identifierNumber(issues[i].Identifier) < identifierNumber(issues[j].Identifier)
+	})
+	return issues
+}
+
 // validateAuth checks the Authorization header and returns an error response if invalid.
 func (m *MockLinearServer) validateAuth(w http.ResponseWriter, r *http.Request) bool {
 	m.mu.RLock()
@@ -206,21 +318,29 @@ func (m *MockLinearServer) handleGraphQL(w http.ResponseWriter, r *http.Request)
 		m.handleViewerQuery(w)
 	case strings.Contains(query, "issueLabelCreate"):
 		m.handleIssueLabelCreate(w, req.Variables)
+	case strings.Contains(query, "issueLabels"):
+		m.handleIssueLabelsQuery(w)
 	case strings.Contains(query, "commentCreate"):
 		m.handleCommentCreate(w, req.Variables)
+	case strings.Contains(query, "issueArchive"):
+		m.handleArchiveIssue(w, req.Variables, true)
+	case strings.Contains(query, "issueUnarchive"):
+		m.handleArchiveIssue(w, req.Variables, false)
 	case strings.Contains(query, "issueCreate") || strings.Contains(query, "createIssue"):
 		m.handleCreateIssue(w, req.Variables)
 	case strings.Contains(query, "issueUpdate") || strings.Contains(query, "updateIssue"):
 		m.handleUpdateIssue(w, req.Variables)
 	case strings.Contains(query, "issue(id:") || strings.Contains(query, "issue (id:"):
 		// Single issue query by ID - must come before issues list check
-		m.handleSingleIssueQuery(w, req.Variables)
+		m.handleSingleIssueQuery(w, req.Variables, strings.Contains(query, "comments"))
 	case strings.Contains(query, "issues") || strings.Contains(query, "Issues"):
 		m.handleIssuesQuery(w, req.Variables)
 	case strings.Contains(query, "workflowStates"):
 		m.handleWorkflowStatesQuery(w, req.Variables)
 	case strings.Contains(query, "team("):
 		m.handleTeamQuery(w, req.Variables)
+	case strings.Contains(query, "users"):
+		m.handleUsersQuery(w)
 	default:
 		// Default: return empty data for unrecognized queries
 		w.Header().Set("Content-Type", "application/json")
@@ -248,14 +368,79 @@ func (m *MockLinearServer) handleViewerQuery(w http.ResponseWriter) {
 	})
 }
 
+// handleUsersQuery handles the users query, returning the authenticated user
+// and everyone an issue is assigned to. Assignees are their own user IDs.
+func (m *MockLinearServer) handleUsersQuery(w http.ResponseWriter) {
+	m.mu.RLock()
+	user := m.AuthenticatedUser
+	nodes := []map[string]interface{}{
+		{
+			"id":          "user-id-123",
+			"name":        user,
+			"displayName": user,
+			"email":       user + "@example.com",
+		},
+	}
+	seen := map[string]bool{"user-id-123": true, user: true}
+	var assignees []string
+	for _, issue := range m.Issues {
+		if issue.Assignee != "" && !seen[issue.Assignee] {
+			seen[issue.Assignee] = true
+			assignees = append(assignees, issue.Assignee)
+		}
+	}
+	sort.Strings(assignees)
+	for _, assignee := range assignees {
+		nodes = append(nodes, map[string]interface{}{
+			"id":          assignee,
+			"name":        assignee,
+			"displayName": assignee,
+			"email":       assignee + "@example.com",
+		})
+	}
+	m.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": map[string]interface{}{
+			"users": map[string]interface{}{
+				"nodes": nodes,
+			},
+		},
+	})
+}
+
 // handleIssuesQuery handles queries for listing issues.
+// Supports the team, assignee, labels, priority, description, updatedAt and comments filters used by the
+// backend, plus first/after pagination.
 func (m *MockLinearServer) handleIssuesQuery(w http.ResponseWriter, variables map[string]interface{}) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	filter, _ := variables["filter"].(map[string]interface{})
+	after, _ := variables["after"].(string)
+	first := -1
+	if f, ok := variables["first"].(float64); ok {
+		first = int(f)
+	}
+
 	// Build issues list - use empty slice not nil to serialize as [] not null
 	issueNodes := make([]map[string]interface{}, 0)
-	for _, issue := range m.Issues {
+	hasNextPage := false
+	var endCursor interface{}
+	skipping := after != ""
+	for _, issue := range m.sortedIssues() {
+		if skipping {
+			if issue.ID == after {
+				skipping = false
+			}
+			continue
+		}
+
+		if issue.Archived && variables["includeArchived"] != true {
+			continue
+		}
+
 		// Apply team filter if specified
 		if teamKey, ok := variables["teamKey"].(string); ok && teamKey != "" {
 			if issue.TeamKey != teamKey {
@@ -263,8 +448,18 @@ func (m *MockLinearServer) handleIssuesQuery(w http.ResponseWriter, variables ma
 			}
 		}
 
+		if !m.issueMatchesFilter(issue, filter) {
+			continue
+		}
+
+		if first >= 0 && len(issueNodes) == first {
+			hasNextPage = true
+			break
+		}
+
 		issueNode := m.issueToGraphQL(issue)
 		issueNodes = append(issueNodes, issueNode)
+		endCursor = issue.ID
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -273,14 +468,133 @@ func (m *MockLinearServer) handleIssuesQuery(w http.ResponseWriter, variables ma
 			"issues": map[string]interface{}{
 				"nodes": issueNodes,
 				"pageInfo": map[string]interface{}{
-					"hasNextPage": false,
-					"endCursor":   nil,
+					"hasNextPage": hasNextPage,
+					"endCursor":   endCursor,
 				},
 			},
 		},
 	})
 }
 
+// issueMatchesFilter reports whether an issue satisfies a Linear IssueFilter.
+// Callers must hold m.mu.
+func (m *MockLinearServer) issueMatchesFilter(issue *MockLinearIssue, filter map[string]interface{}) bool {
+	if filter == nil {
+		return true
+	}
+
+	// team: { id: { eq: $teamId } }
+	if team, ok := filter["team"].(map[string]interface{}); ok {
+		if id, ok := team["id"].(map[string]interface{}); ok {
+			if eq, ok := id["eq"].(string); ok {
+				if t, exists := m.Teams[issue.TeamKey]; !exists || t.ID != eq {
+					return false
+				}
+			}
+		}
+	}
+
+	// assignee: { isMe: { eq: true } }, { id: { eq: "x" } } or { null: true }
+	if assignee, ok := filter["assignee"].(map[string]interface{}); ok {
+		if isMe, ok := assignee["isMe"].(map[string]interface{}); ok && isMe["eq"] == true {
+			if issue.Assignee != "user-id-123" && issue.Assignee != m.AuthenticatedUser {
+				return false
+			}
+		}
+		if id, ok := assignee["id"].(map[string]interface{}); ok {
+			if eq, ok := id["eq"].(string); ok && issue.Assignee != eq &&
+				!(eq == "user-id-123" && issue.Assignee == m.AuthenticatedUser) {
+				return false
+			}
+		}
+		if assignee["null"] == true && issue.Assignee != "" {
+			return false
+		}
+	}
+
+	// labels: { name: { eq: "x" } } or { and: [{ name: { eq: "x" } }, ...] }
+	if labels, ok := filter["labels"].(map[string]interface{}); ok {
+		conditions := []interface{}{labels}
+		if and, ok := labels["and"].([]interface{}); ok {
+			conditions = and
+		}
+		for _, c := range conditions {
+			cond, _ := c.(map[string]interface{})
+			name, _ := cond["name"].(map[string]interface{})
+			eq, _ := name["eq"].(string)
+			if eq == "" {
+				continue
+			}
+			found := false
+			for _, label := range issue.Labels {
+				if label == eq {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+	}
+
+	// parent: { id: { eq: $parentId } }
+	if parent, ok := filter["parent"].(map[string]interface{}); ok {
+		if id, ok := parent["id"].(map[string]interface{}); ok {
+			if eq, ok := id["eq"].(string); ok && issue.Parent != eq {
+				return false
+			}
+		}
+	}
+
+	// description: { contains: "x" }
+	if description, ok := filter["description"].(map[string]interface{}); ok {
+		if contains, ok := description["contains"].(string); ok && !strings.Contains(issue.Description, contains) {
+			return false
+		}
+	}
+
+	// updatedAt: { gt: "2025-01-01T00:00:00Z" }
+	if updatedAt, ok := filter["updatedAt"].(map[string]interface{}); ok {
+		if gt, ok := updatedAt["gt"].(string); ok {
+			if t, err := time.Parse(time.RFC3339Nano, gt); err == nil && !issue.UpdatedAt.After(t) {
+				return false
+			}
+		}
+	}
+
+	// comments: { some: {} } or { length: { eq: 0 } }
+	if comments, ok := filter["comments"].(map[string]interface{}); ok {
+		count := len(m.Comments[issue.ID])
+		if _, ok := comments["some"]; ok && count == 0 {
+			return false
+		}
+		if length, ok := comments["length"].(map[string]interface{}); ok {
+			if eq, ok := length["eq"].(float64); ok && count != int(eq) {
+				return false
+			}
+		}
+	}
+
+	// priority: { in: [1, 2] }
+	if priority, ok := filter["priority"].(map[string]interface{}); ok {
+		if in, ok := priority["in"].([]interface{}); ok {
+			found := false
+			for _, p := range in {
+				if f, ok := p.(float64); ok && int(f) == issue.Priority {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
 // handleCreateIssue handles issue creation mutations.
 func (m *MockLinearServer) handleCreateIssue(w http.ResponseWriter, variables map[string]interface{}) {
 	m.mu.Lock()
@@ -305,7 +619,7 @@ func (m *MockLinearServer) handleCreateIssue(w http.ResponseWriter, variables ma
 	}
 
 	issueID := generateID()
-	identifier := teamKey + "-" + string(rune('0'+m.NextIssueNumber))
+	identifier := teamKey + "-" + strconv.Itoa(m.NextIssueNumber)
 	m.NextIssueNumber++
 
 	issue := &MockLinearIssue{
@@ -314,6 +628,7 @@ func (m *MockLinearServer) handleCreateIssue(w http.ResponseWriter, variables ma
 		Title:      getString(input, "title"),
 		TeamKey:    teamKey,
 		State:      "Backlog",
+		UpdatedAt:  time.Now(),
 	}
 
 	if desc, ok := input["description"].(string); ok {
@@ -322,6 +637,9 @@ func (m *MockLinearServer) handleCreateIssue(w http.ResponseWriter, variables ma
 	if priority, ok := input["priority"].(float64); ok {
 		issue.Priority = int(priority)
 	}
+	if parentID, ok := input["parentId"].(string); ok {
+		issue.Parent = parentID
+	}
 
 	m.Issues[issueID] = issue
 
@@ -384,6 +702,11 @@ func (m *MockLinearServer) handleUpdateIssue(w http.ResponseWriter, variables ma
 		// If assigneeId key exists but is not a string (i.e., null), clear assignee
 		issue.Assignee = ""
 	}
+	if parentID, ok := input["parentId"].(string); ok {
+		issue.Parent = parentID
+	} else if _, hasKey := input["parentId"]; hasKey {
+		issue.Parent = ""
+	}
 
 	// Handle labelIds - replaces all labels
 	if labelIDsRaw, ok := input["labelIds"].([]interface{}); ok {
@@ -431,6 +754,7 @@ func (m *MockLinearServer) handleUpdateIssue(w http.ResponseWriter, variables ma
 		}
 		issue.Labels = newLabels
 	}
+	issue.UpdatedAt = time.Now()
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -443,6 +767,35 @@ func (m *MockLinearServer) handleUpdateIssue(w http.ResponseWriter, variables ma
 	})
 }
 
+// handleArchiveIssue handles the issueArchive and issueUnarchive mutations.
+func (m *MockLinearServer) handleArchiveIssue(w http.ResponseWriter, variables map[string]interface{}, archive bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	issueID, _ := variables["id"].(string)
+	issue := m.findIssue(issueID)
+	if issue == nil {
+		m.writeGraphQLError(w, "Issue not found", "NOT_FOUND")
+		return
+	}
+
+	issue.Archived = archive
+	issue.UpdatedAt = time.Now()
+
+	mutation := "issueArchive"
+	if !archive {
+		mutation = "issueUnarchive"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": map[string]interface{}{
+			mutation: map[string]interface{}{
+				"success": true,
+			},
+		},
+	})
+}
+
 // handleTeamQuery handles team queries.
 func (m *MockLinearServer) handleTeamQuery(w http.ResponseWriter, variables map[string]interface{}) {
 	m.mu.RLock()
@@ -458,7 +811,8 @@ func (m *MockLinearServer) handleTeamQuery(w http.ResponseWriter, variables map[
 
 	// Build states list
 	var stateNodes []map[string]interface{}
-	for _, state := range m.States {
+	for _, name := range m.stateOrder {
+		state := m.States[name]
 		stateNodes = append(stateNodes, map[string]interface{}{
 			"id":   state.ID,
 			"name": state.Name,
@@ -488,7 +842,8 @@ func (m *MockLinearServer) handleWorkflowStatesQuery(w http.ResponseWriter, vari
 
 	// Build states list from all registered states
 	var stateNodes []map[string]interface{}
-	for _, state := range m.States {
+	for _, name := range m.stateOrder {
+		state := m.States[name]
 		stateNodes = append(stateNodes, map[string]interface{}{
 			"id":   state.ID,
 			"name": state.Name,
@@ -507,7 +862,8 @@ func (m *MockLinearServer) handleWorkflowStatesQuery(w http.ResponseWriter, vari
 }
 
 // handleSingleIssueQuery handles queries for a single issue by ID or identifier.
-func (m *MockLinearServer) handleSingleIssueQuery(w http.ResponseWriter, variables map[string]interface{}) {
+// If withComments is set, the issue's comment thread is included.
+func (m *MockLinearServer) handleSingleIssueQuery(w http.ResponseWriter, variables map[string]interface{}, withComments bool) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -519,27 +875,59 @@ func (m *MockLinearServer) handleSingleIssueQuery(w http.ResponseWriter, variabl
 	}
 
 	// Try to find by ID first, then by identifier
-	var issue *MockLinearIssue
-	for _, i := range m.Issues {
-		if i.ID == issueID || i.Identifier == issueID {
-			issue = i
-			break
-		}
-	}
-
+	issue := m.findIssue(issueID)
 	if issue == nil {
 		m.writeGraphQLError(w, "Issue not found", "NOT_FOUND")
 		return
 	}
 
+	result := m.issueToGraphQL(issue)
+	if withComments {
+		since := commentFilterSince(variables)
+		commentNodes := make([]map[string]interface{}, 0)
+		for _, c := range m.Comments[issue.ID] {
+			if !since.IsZero() && !c.CreatedAt.After(since) {
+				continue
+			}
+			commentNodes = append(commentNodes, m.commentToGraphQL(c))
+		}
+		result["comments"] = map[string]interface{}{
+			"nodes": commentNodes,
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"data": map[string]interface{}{
-			"issue": m.issueToGraphQL(issue),
+			"issue": result,
 		},
 	})
 }
 
+// commentFilterSince returns the createdAt.gt cutoff of a comment query's
+// filter variable, or the zero time if there is none.
+func commentFilterSince(variables map[string]interface{}) time.Time {
+	filter, _ := variables["filter"].(map[string]interface{})
+	createdAt, _ := filter["createdAt"].(map[string]interface{})
+	gt, _ := createdAt["gt"].(string)
+	since, _ := time.Parse(time.RFC3339, gt)
+	return since
+}
+
+// commentToGraphQL converts a MockLinearComment to GraphQL response format.
+func (m *MockLinearServer) commentToGraphQL(c MockLinearComment) map[string]interface{} {
+	return map[string]interface{}{
+		"id":        c.ID,
+		"body":      c.Body,
+		"createdAt": c.CreatedAt.Format(time.RFC3339),
+		"user": map[string]interface{}{
+			"id":          "user-" + c.Author,
+			"name":        c.Author,
+			"displayName": c.Author,
+		},
+	}
+}
+
 // issueToGraphQL converts a MockLinearIssue to GraphQL response format.
 func (m *MockLinearServer) issueToGraphQL(issue *MockLinearIssue) map[string]interface{} {
 	result := map[string]interface{}{
@@ -549,9 +937,12 @@ func (m *MockLinearServer) issueToGraphQL(issue *MockLinearIssue) map[string]int
 		"description": issue.Description,
 		"priority":    issue.Priority,
 		"createdAt":   time.Now().Format(time.RFC3339),
-		"updatedAt":   time.Now().Format(time.RFC3339),
+		"updatedAt":   issue.UpdatedAt.UTC().Format(time.RFC3339Nano),
 		"url":         "https://linear.app/team/" + issue.TeamKey + "/issue/" + issue.Identifier,
 	}
+	if issue.Archived {
+		result["archivedAt"] = issue.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	}
 
 	// Add state
 	if state, exists := m.States[issue.State]; exists {
@@ -604,9 +995,34 @@ func (m *MockLinearServer) issueToGraphQL(issue *MockLinearIssue) map[string]int
 		"nodes": labelNodes,
 	}
 
+	// Add parent and children
+	if parent, exists := m.Issues[issue.Parent]; exists {
+		result["parent"] = m.issueSummary(parent)
+	}
+	childNodes := make([]map[string]interface{}, 0)
+	for _, child := range m.sortedIssues() {
+		if child.Parent == issue.ID && !child.Archived {
+			childNodes = append(childNodes, m.issueSummary(child))
+		}
+	}
+	result["children"] = map[string]interface{}{
+		"nodes": childNodes,
+	}
+
 	return result
 }
 
+// issueSummary returns the identifier, title, and state of an issue, as
+// selected for related issues.
+func (m *MockLinearServer) issueSummary(issue *MockLinearIssue) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         issue.ID,
+		"identifier": issue.Identifier,
+		"title":      issue.Title,
+		"state":      map[string]interface{}{"name": issue.State},
+	}
+}
+
 // handleIssueLabelCreate handles label creation mutations.
 func (m *MockLinearServer) handleIssueLabelCreate(w http.ResponseWriter, variables map[string]interface{}) {
 	m.mu.Lock()
@@ -620,6 +1036,7 @@ func (m *MockLinearServer) handleIssueLabelCreate(w http.ResponseWriter, variabl
 
 	labelName := getString(input, "name")
 	labelID := "label-" + labelName
+	m.Labels[labelName] = true
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -635,6 +1052,49 @@ func (m *MockLinearServer) handleIssueLabelCreate(w http.ResponseWriter, variabl
 	})
 }
 
+// handleIssueLabelsQuery handles the issueLabels query. It returns labels
+// created via issueLabelCreate plus any label already applied to an issue.
+func (m *MockLinearServer) handleIssueLabelsQuery(w http.ResponseWriter) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make(map[string]bool)
+	for name := range m.Labels {
+		names[name] = true
+	}
+	for _, issue := range m.Issues {
+		for _, label := range issue.Labels {
+			names[label] = true
+		}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	labelNodes := make([]map[string]interface{}, 0, len(sorted))
+	for _, name := range sorted {
+		details := m.LabelDetails[name]
+		labelNodes = append(labelNodes, map[string]interface{}{
+			"id":          "label-" + name,
+			"name":        name,
+			"color":       details.Color,
+			"description": details.Description,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": map[string]interface{}{
+			"issueLabels": map[string]interface{}{
+				"nodes": labelNodes,
+			},
+		},
+	})
+}
+
 // handleCommentCreate handles comment creation mutations.
 func (m *MockLinearServer) handleCommentCreate(w http.ResponseWriter, variables map[string]interface{}) {
 	m.mu.Lock()
@@ -646,19 +1106,26 @@ func (m *MockLinearServer) handleCommentCreate(w http.ResponseWriter, variables
 		return
 	}
 
-	body := getString(input, "body")
-	commentID := generateID()
+	issue := m.findIssue(getString(input, "issueId"))
+	if issue == nil {
+		m.writeGraphQLError(w, "Issue not found", "NOT_FOUND")
+		return
+	}
+
+	comment := MockLinearComment{
+		ID:        generateID(),
+		Author:    m.AuthenticatedUser,
+		Body:      getString(input, "body"),
+		CreatedAt: time.Now(),
+	}
+	m.Comments[issue.ID] = append(m.Comments[issue.ID], comment)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"data": map[string]interface{}{
 			"commentCreate": map[string]interface{}{
 				"success": true,
-				"comment": map[string]interface{}{
-					"id":        commentID,
-					"body":      body,
-					"createdAt": time.Now().Format(time.RFC3339),
-				},
+				"comment": m.commentToGraphQL(comment),
 			},
 		},
 	})
@@ -686,6 +1153,17 @@ func generateID() string {
 	return "id-" + time.Now().Format("20060102150405.000000000")
 }
 
+// identifierNumber returns the numeric suffix of an identifier like "ENG-123",
+// or 0 if it has none.
+func identifierNumber(identifier string) int {
+	idx := strings.LastIndex(identifier, "-")
+	if idx < 0 {
+		return 0
+	}
+	n, _ := strconv.Atoi(identifier[idx+1:])
+	return n
+}
+
 func getString(m map[string]interface{}, key string) string {
 	if v, ok := m[key].(string); ok {
 		return v
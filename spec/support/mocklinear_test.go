@@ -0,0 +1,187 @@
+package support
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+// postLinearQuery sends a GraphQL request to the mock and decodes the response.
+func postLinearQuery(t *testing.T, server *MockLinearServer, query string, variables map[string]interface{}) map[string]interface{} {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		t.Fatalf("failed to encode request: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/graphql", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", "test_key")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return result
+}
+
+// issueIdentifiers extracts identifiers from an issues query response.
+func issueIdentifiers(result map[string]interface{}) []string {
+	data, _ := result["data"].(map[string]interface{})
+	issues, _ := data["issues"].(map[string]interface{})
+	nodes, _ := issues["nodes"].([]interface{})
+
+	var ids []string
+	for _, n := range nodes {
+		node, _ := n.(map[string]interface{})
+		ids = append(ids, node["identifier"].(string))
+	}
+	return ids
+}
+
+func TestMockLinearServer_IssuesQueryFilters(t *testing.T) {
+	server := NewMockLinearServer()
+	defer server.Close()
+	server.SetIssues([]MockLinearIssue{
+		{ID: "i1", Identifier: "ENG-1", TeamKey: "ENG", Priority: 1, Labels: []string{"bug"}},
+		{ID: "i2", Identifier: "ENG-2", TeamKey: "ENG", Priority: 3, Assignee: "alice"},
+		{ID: "i3", Identifier: "ENG-3", TeamKey: "ENG", Priority: 1, Labels: []string{"bug", "ui"}},
+	})
+	server.SetComments("ENG-2", []MockLinearComment{{ID: "c1", Body: "On it"}})
+
+	query := `query ListIssues($first: Int, $filter: IssueFilter) { issues(first: $first, filter: $filter) { nodes { id } } }`
+
+	tests := []struct {
+		name   string
+		filter map[string]interface{}
+		want   []string
+	}{
+		{"no filter", nil, []string{"ENG-1", "ENG-2", "ENG-3"}},
+		{"single label", map[string]interface{}{"labels": map[string]interface{}{"name": map[string]interface{}{"eq": "bug"}}}, []string{"ENG-1", "ENG-3"}},
+		{"all labels", map[string]interface{}{"labels": map[string]interface{}{"and": []interface{}{
+			map[string]interface{}{"name": map[string]interface{}{"eq": "bug"}},
+			map[string]interface{}{"name": map[string]interface{}{"eq": "ui"}},
+		}}}, []string{"ENG-3"}},
+		{"unassigned", map[string]interface{}{"assignee": map[string]interface{}{"null": true}}, []string{"ENG-1", "ENG-3"}},
+		{"priority", map[string]interface{}{"priority": map[string]interface{}{"in": []interface{}{3}}}, []string{"ENG-2"}},
+		{"team", map[string]interface{}{"team": map[string]interface{}{"id": map[string]interface{}{"eq": "other-team"}}}, nil},
+		{"with comments", map[string]interface{}{"comments": map[string]interface{}{"some": map[string]interface{}{}}}, []string{"ENG-2"}},
+		{"without comments", map[string]interface{}{"comments": map[string]interface{}{"length": map[string]interface{}{"eq": 0}}}, []string{"ENG-1", "ENG-3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variables := map[string]interface{}{"first": 100}
+			if tt.filter != nil {
+				variables["filter"] = tt.filter
+			}
+			got := issueIdentifiers(postLinearQuery(t, server, query, variables))
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("got %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestMockLinearServer_IssuesQueryPagination(t *testing.T) {
+	server := NewMockLinearServer()
+	defer server.Close()
+	server.SetIssues([]MockLinearIssue{
+		{ID: "i10", Identifier: "ENG-10", TeamKey: "ENG"},
+		{ID: "i2", Identifier: "ENG-2", TeamKey: "ENG"},
+		{ID: "i3", Identifier: "ENG-3", TeamKey: "ENG"},
+	})
+
+	query := `query ListIssues($first: Int, $after: String) { issues(first: $first, after: $after) { nodes { id } } }`
+
+	result := postLinearQuery(t, server, query, map[string]interface{}{"first": 2})
+	if got := issueIdentifiers(result); len(got) != 2 || got[0] != "ENG-2" || got[1] != "ENG-3" {
+		t.Fatalf("first page = %v, want [ENG-2 ENG-3]", got)
+	}
+	pageInfo := result["data"].(map[string]interface{})["issues"].(map[string]interface{})["pageInfo"].(map[string]interface{})
+	if pageInfo["hasNextPage"] != true {
+		t.Errorf("expected hasNextPage on first page")
+	}
+
+	result = postLinearQuery(t, server, query, map[string]interface{}{"first": 2, "after": pageInfo["endCursor"]})
+	if got := issueIdentifiers(result); len(got) != 1 || got[0] != "ENG-10" {
+		t.Fatalf("second page = %v, want [ENG-10]", got)
+	}
+}
+
+func TestMockLinearServer_ArchiveIssue(t *testing.T) {
+	server := NewMockLinearServer()
+	defer server.Close()
+	server.SetIssues([]MockLinearIssue{{ID: "i1", Identifier: "ENG-1", TeamKey: "ENG"}})
+
+	postLinearQuery(t, server, `mutation ArchiveIssue($id: String!) { issueArchive(id: $id) { success } }`, map[string]interface{}{"id": "i1"})
+
+	if issue := server.GetIssue("i1"); issue == nil || !issue.Archived {
+		t.Fatal("expected issue to be archived")
+	}
+	result := postLinearQuery(t, server, `query { issues { nodes { id } } }`, nil)
+	if got := issueIdentifiers(result); len(got) != 0 {
+		t.Errorf("archived issue should not be listed, got %v", got)
+	}
+}
+
+func TestMockLinearServer_Comments(t *testing.T) {
+	server := NewMockLinearServer()
+	defer server.Close()
+	server.AuthenticatedUser = "alice"
+	server.SetIssues([]MockLinearIssue{{ID: "i1", Identifier: "ENG-1", TeamKey: "ENG"}})
+
+	postLinearQuery(t, server, `mutation CreateComment($input: CommentCreateInput!) { commentCreate(input: $input) { success } }`,
+		map[string]interface{}{"input": map[string]interface{}{"issueId": "i1", "body": "hello"}})
+
+	comments := server.GetComments("ENG-1")
+	if len(comments) != 1 || comments[0].Body != "hello" || comments[0].Author != "alice" {
+		t.Fatalf("unexpected comments: %+v", comments)
+	}
+
+	result := postLinearQuery(t, server, `query GetIssueComments($id: String!) { issue(id: $id) { comments { nodes { id } } } }`,
+		map[string]interface{}{"id": "ENG-1"})
+	issue := result["data"].(map[string]interface{})["issue"].(map[string]interface{})
+	nodes := issue["comments"].(map[string]interface{})["nodes"].([]interface{})
+	if len(nodes) != 1 {
+		t.Errorf("expected 1 comment in query response, got %d", len(nodes))
+	}
+}
+
+func TestMockLinearServer_SetWorkflowStates(t *testing.T) {
+	server := NewMockLinearServer()
+	defer server.Close()
+	server.SetWorkflowStates([]MockLinearState{
+		{ID: "s1", Name: "Triage", Type: "backlog"},
+		{ID: "s2", Name: "Shipped", Type: "completed"},
+	})
+
+	result := postLinearQuery(t, server, `query GetWorkflowStates($teamId: ID) { workflowStates { nodes { id } } }`, nil)
+	nodes := result["data"].(map[string]interface{})["workflowStates"].(map[string]interface{})["nodes"].([]interface{})
+	if len(nodes) != 2 {
+		t.Fatalf("expected 2 states, got %d", len(nodes))
+	}
+	if name := nodes[0].(map[string]interface{})["name"]; name != "Triage" {
+		t.Errorf("expected states in configured order, got %v first", name)
+	}
+}
@@ -0,0 +1,214 @@
+package support
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexbrand/backlog/internal/backend"
+)
+
+// OrderKey is one key of an expected task ordering.
+type OrderKey struct {
+	// Field is the task field compared, such as "priority" or "created"
+	Field string
+	// Desc expects the largest value first
+	Desc bool
+}
+
+func (k OrderKey) String() string {
+	if k.Desc {
+		return k.Field + " desc"
+	}
+	return k.Field + " asc"
+}
+
+// ParseTaskOrder parses an expected ordering such as "priority desc,
+// created asc". The direction defaults to ascending.
+func ParseTaskOrder(spec string) ([]OrderKey, error) {
+	var keys []OrderKey
+	for _, part := range strings.Split(spec, ",") {
+		words := strings.Fields(strings.ToLower(part))
+		if len(words) == 0 || len(words) > 2 {
+			return nil, fmt.Errorf("invalid ordering %q: want \"<field> [asc|desc]\"", strings.TrimSpace(part))
+		}
+		key := OrderKey{Field: words[0]}
+		if len(words) == 2 {
+			switch words[1] {
+			case "asc":
+			case "desc":
+				key.Desc = true
+			default:
+				return nil, fmt.Errorf("invalid direction %q in %q: want asc or desc", words[1], strings.TrimSpace(part))
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// TaskRows extracts the listed tasks from "backlog list" output as one map
+// of field name to value per task, in output order. JSON output is read
+// from its "tasks" array; anything else is parsed as a table, whose columns
+// are keyed by their lowercased headers and whose empty cells are "".
+func TaskRows(output string) ([]map[string]string, error) {
+	var list struct {
+		Tasks []map[string]any `json:"tasks"`
+	}
+	if err := json.Unmarshal([]byte(output), &list); err == nil {
+		rows := make([]map[string]string, 0, len(list.Tasks))
+		for _, task := range list.Tasks {
+			row := make(map[string]string, len(task))
+			for field, value := range task {
+				if value != nil {
+					row[field] = fmt.Sprint(value)
+				}
+			}
+			rows = append(rows, row)
+		}
+		return rows, nil
+	}
+
+	table, err := ParseOutputTable(output)
+	if err != nil {
+		return nil, err
+	}
+	// The "—" placeholder stands for an empty value
+	for _, row := range table.Rows {
+		for column, cell := range row {
+			if cell == "—" {
+				row[column] = ""
+			}
+		}
+	}
+	return table.Rows, nil
+}
+
+// CheckTaskOrder verifies that rows are ordered by keys, comparing by each
+// key in turn until one differs. It fails on the first out-of-order pair,
+// naming both tasks and the values compared. Priorities rank from none up
+// to urgent, statuses follow the workflow, times and durations compare by
+// value, numbers within IDs compare numerically, and other fields compare
+// as case-insensitive text.
+func CheckTaskOrder(rows []map[string]string, keys []OrderKey) error {
+	for i := 1; i < len(rows); i++ {
+		prev, cur := rows[i-1], rows[i]
+		for _, key := range keys {
+			c, err := compareField(key.Field, prev[key.Field], cur[key.Field])
+			if err != nil {
+				return err
+			}
+			if key.Desc {
+				c = -c
+			}
+			if c < 0 {
+				break
+			}
+			if c > 0 {
+				return fmt.Errorf("tasks out of order by %s at positions %d and %d: %s (%s) before %s (%s)",
+					key, i, i+1, prev["id"], describeRow(prev, keys), cur["id"], describeRow(cur, keys))
+			}
+		}
+	}
+	return nil
+}
+
+// describeRow lists the row's values for keys, e.g. "priority=high, created=...".
+func describeRow(row map[string]string, keys []OrderKey) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = fmt.Sprintf("%s=%s", key.Field, row[key.Field])
+	}
+	return strings.Join(parts, ", ")
+}
+
+var idNumberRe = regexp.MustCompile(`^(.*?)(\d+)$`)
+
+// compareField compares two values of field in ascending order.
+func compareField(field, a, b string) (int, error) {
+	switch field {
+	case "priority":
+		return cmp.Compare(priorityRank(a), priorityRank(b)), nil
+	case "status":
+		return cmp.Compare(statusRank(a), statusRank(b)), nil
+	case "created", "updated", "started_at", "completed_at":
+		ta, err := parseOrderTime(field, a)
+		if err != nil {
+			return 0, err
+		}
+		tb, err := parseOrderTime(field, b)
+		if err != nil {
+			return 0, err
+		}
+		return ta.Compare(tb), nil
+	case "duration", "estimate", "spent":
+		da, err := parseOrderDuration(field, a)
+		if err != nil {
+			return 0, err
+		}
+		db, err := parseOrderDuration(field, b)
+		if err != nil {
+			return 0, err
+		}
+		return cmp.Compare(da, db), nil
+	case "id":
+		ma, mb := idNumberRe.FindStringSubmatch(a), idNumberRe.FindStringSubmatch(b)
+		if ma != nil && mb != nil && ma[1] == mb[1] {
+			na, _ := strconv.Atoi(ma[2])
+			nb, _ := strconv.Atoi(mb[2])
+			return cmp.Compare(na, nb), nil
+		}
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b)), nil
+}
+
+func priorityRank(p string) int {
+	switch backend.Priority(p) {
+	case backend.PriorityLow:
+		return 1
+	case backend.PriorityMedium:
+		return 2
+	case backend.PriorityHigh:
+		return 3
+	case backend.PriorityUrgent:
+		return 4
+	}
+	return 0
+}
+
+func statusRank(s string) int {
+	for i, status := range backend.ValidStatuses() {
+		if string(status) == s {
+			return i
+		}
+	}
+	return len(backend.ValidStatuses())
+}
+
+// parseOrderTime parses a timestamp, treating a missing one as the zero time.
+func parseOrderTime(field, s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cannot compare %s %q: %w", field, s, err)
+	}
+	return t, nil
+}
+
+// parseOrderDuration parses a duration, treating a missing one as zero.
+func parseOrderDuration(field, s string) (backend.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := backend.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("cannot compare %s %q: %w", field, s, err)
+	}
+	return d, nil
+}
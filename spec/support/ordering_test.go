@@ -0,0 +1,69 @@
+package support
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alexbrand/backlog/internal/output"
+)
+
+func TestParseTaskOrder(t *testing.T) {
+	keys, err := ParseTaskOrder("priority desc, created asc, id")
+	if err != nil {
+		t.Fatalf("ParseTaskOrder() error = %v", err)
+	}
+	if len(keys) != 3 || keys[0] != (OrderKey{Field: "priority", Desc: true}) || keys[1] != (OrderKey{Field: "created"}) || keys[2] != (OrderKey{Field: "id"}) {
+		t.Errorf("ParseTaskOrder() = %+v", keys)
+	}
+
+	if _, err := ParseTaskOrder("priority sideways"); err == nil {
+		t.Error("expected an error for an invalid direction")
+	}
+}
+
+func TestCheckTaskOrder(t *testing.T) {
+	rows := []map[string]string{
+		{"id": "task2", "priority": "urgent", "created": "2025-01-02T00:00:00Z"},
+		{"id": "task10", "priority": "high", "created": "2025-01-01T00:00:00Z"},
+		{"id": "task3", "priority": "high", "created": "2025-01-03T00:00:00Z"},
+		{"id": "task1", "priority": "", "created": "2025-01-01T00:00:00Z"},
+	}
+	keys, _ := ParseTaskOrder("priority desc, created asc")
+	if err := CheckTaskOrder(rows, keys); err != nil {
+		t.Errorf("CheckTaskOrder() error = %v", err)
+	}
+
+	keys, _ = ParseTaskOrder("priority desc, created desc")
+	err := CheckTaskOrder(rows, keys)
+	if err == nil {
+		t.Fatal("expected an out-of-order error")
+	}
+	for _, want := range []string{"positions 2 and 3", "task10", "task3", "created=2025-01-01T00:00:00Z"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+
+	keys, _ = ParseTaskOrder("id")
+	if err := CheckTaskOrder([]map[string]string{{"id": "task2"}, {"id": "task10"}}, keys); err != nil {
+		t.Errorf("expected numeric ID order, got %v", err)
+	}
+}
+
+func TestTaskRows(t *testing.T) {
+	rows, err := TaskRows(`{"tasks": [{"id": "001", "priority": "high"}, {"id": "002", "assignee": null}], "count": 2}`)
+	if err != nil {
+		t.Fatalf("TaskRows() error = %v", err)
+	}
+	if len(rows) != 2 || rows[0]["priority"] != "high" || rows[1]["id"] != "002" {
+		t.Errorf("TaskRows() = %v", rows)
+	}
+
+	rows, err = TaskRows(renderTaskList(t, &output.TableFormatter{}))
+	if err != nil {
+		t.Fatalf("TaskRows() error = %v", err)
+	}
+	if len(rows) != 3 || rows[2]["id"] != "GH-10" || rows[0]["priority"] != "high" {
+		t.Errorf("TaskRows() = %v", rows)
+	}
+}
@@ -0,0 +1,113 @@
+package support
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// OutputTable is a table parsed from the CLI's table output, such as the
+// task list printed by "backlog list".
+type OutputTable struct {
+	// Columns holds the column headers in display order, lowercased
+	Columns []string
+	// Rows holds one map per row from column header to trimmed cell text
+	Rows []map[string]string
+}
+
+var (
+	ansiRe        = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+	tableHeaderRe = regexp.MustCompile(`^[A-Z][A-Z_]*( {2,}[A-Z][A-Z_]*)* *$`)
+)
+
+// ParseOutputTable parses the aligned tables in CLI output. A table starts
+// at a line of uppercase column headers and runs until a blank line, so the
+// per-group tables of "backlog list --group-by" are all read; group titles
+// and other lines outside a table are ignored.
+//
+// Columns are aligned with spaces, so a cell spans from its header's start
+// to the next header's start. This keeps multi-word cells and empty cells
+// intact. ANSI colors are stripped from cells after splitting, since the
+// alignment counts them.
+func ParseOutputTable(output string) (*OutputTable, error) {
+	table := &OutputTable{}
+	var starts []int
+	var columns []string
+
+	for _, line := range strings.Split(output, "\n") {
+		runes := []rune(strings.TrimRight(line, "\r"))
+		plain := stripANSI(string(runes))
+		switch {
+		case strings.TrimSpace(plain) == "":
+			starts = nil
+		case starts == nil && tableHeaderRe.MatchString(plain):
+			starts, columns = headerColumns(runes)
+			if table.Columns == nil {
+				table.Columns = columns
+			}
+		case starts != nil:
+			row := make(map[string]string, len(columns))
+			for i, start := range starts {
+				end := len(runes)
+				if i+1 < len(starts) && starts[i+1] < end {
+					end = starts[i+1]
+				}
+				cell := ""
+				if start < end {
+					cell = strings.TrimSpace(stripANSI(string(runes[start:end])))
+				}
+				row[columns[i]] = cell
+			}
+			table.Rows = append(table.Rows, row)
+		}
+	}
+
+	if table.Columns == nil {
+		return nil, fmt.Errorf("no table header found in output:\n%s", output)
+	}
+	return table, nil
+}
+
+// headerColumns returns the rune offset where each column starts in a raw
+// header line, and the lowercased column names. A colored header starts at
+// its escape sequence, like the colored cells below it.
+func headerColumns(runes []rune) ([]int, []string) {
+	var starts []int
+	for i, r := range runes {
+		if r != ' ' && (i == 0 || runes[i-1] == ' ') {
+			starts = append(starts, i)
+		}
+	}
+	var columns []string
+	for _, name := range strings.Fields(stripANSI(string(runes))) {
+		columns = append(columns, strings.ToLower(name))
+	}
+	return starts, columns
+}
+
+// stripANSI removes ANSI color sequences.
+func stripANSI(s string) string {
+	return ansiRe.ReplaceAllString(s, "")
+}
+
+// Row returns the first row whose cell in column equals value.
+func (t *OutputTable) Row(column, value string) (map[string]string, bool) {
+	column = strings.ToLower(column)
+	for _, row := range t.Rows {
+		if cell, ok := row[column]; ok && cell == value {
+			return row, true
+		}
+	}
+	return nil, false
+}
+
+// HasColumn reports whether the table has a column with the given header.
+func (t *OutputTable) HasColumn(column string) bool {
+	column = strings.ToLower(column)
+	for _, c := range t.Columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package support
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/alexbrand/backlog/internal/backend"
+	"github.com/alexbrand/backlog/internal/output"
+)
+
+var tableTestTasks = []backend.Task{
+	{ID: "001", Title: "Fix the login bug", Status: backend.StatusTodo, Priority: backend.PriorityHigh, Assignee: "alice", Labels: []string{"bug", "auth"}},
+	{ID: "002", Title: "Docs", Status: backend.StatusInProgress, Priority: backend.PriorityLow},
+	{ID: "GH-10", Title: "A title long enough to be truncated by the table formatter", Status: backend.StatusDone, Priority: backend.PriorityMedium},
+}
+
+// renderTaskList returns the task list as the table formatter prints it.
+func renderTaskList(t *testing.T, f *output.TableFormatter) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := f.FormatTaskList(&buf, &backend.TaskList{Tasks: tableTestTasks, Count: len(tableTestTasks)}); err != nil {
+		t.Fatalf("FormatTaskList() error = %v", err)
+	}
+	return buf.String()
+}
+
+func assertTableRow(t *testing.T, table *OutputTable, id string, want map[string]string) {
+	t.Helper()
+	row, ok := table.Row("id", id)
+	if !ok {
+		t.Fatalf("no row with id %q in %+v", id, table.Rows)
+	}
+	for column, value := range want {
+		if row[column] != value {
+			t.Errorf("row %s: %s = %q, want %q", id, column, row[column], value)
+		}
+	}
+}
+
+func TestParseOutputTable(t *testing.T) {
+	table, err := ParseOutputTable(renderTaskList(t, &output.TableFormatter{}))
+	if err != nil {
+		t.Fatalf("ParseOutputTable() error = %v", err)
+	}
+
+	wantColumns := []string{"id", "status", "priority", "title", "assignee", "labels"}
+	if len(table.Columns) != len(wantColumns) {
+		t.Fatalf("Columns = %v, want %v", table.Columns, wantColumns)
+	}
+	for i, column := range wantColumns {
+		if table.Columns[i] != column {
+			t.Errorf("Columns[%d] = %q, want %q", i, table.Columns[i], column)
+		}
+	}
+	if len(table.Rows) != 3 {
+		t.Fatalf("got %d rows, want 3", len(table.Rows))
+	}
+
+	assertTableRow(t, table, "001", map[string]string{
+		"title": "Fix the login bug", "status": "todo", "priority": "high",
+		"assignee": "@alice", "labels": "bug, auth",
+	})
+	assertTableRow(t, table, "002", map[string]string{
+		"title": "Docs", "status": "in-progress", "assignee": "—", "labels": "—",
+	})
+	assertTableRow(t, table, "GH-10", map[string]string{
+		"title": "A title long enough to be truncated b...",
+	})
+}
+
+func TestParseOutputTableColored(t *testing.T) {
+	table, err := ParseOutputTable(renderTaskList(t, &output.TableFormatter{Color: true}))
+	if err != nil {
+		t.Fatalf("ParseOutputTable() error = %v", err)
+	}
+
+	assertTableRow(t, table, "001", map[string]string{
+		"status": "todo", "priority": "high", "title": "Fix the login bug", "labels": "bug, auth",
+	})
+	assertTableRow(t, table, "GH-10", map[string]string{"status": "done", "priority": "medium"})
+}
+
+func TestParseOutputTableGrouped(t *testing.T) {
+	var buf bytes.Buffer
+	groups := []output.TaskGroup{
+		{Key: "todo", Tasks: tableTestTasks[:1]},
+		{Key: "done", Tasks: tableTestTasks[2:]},
+	}
+	f := &output.TableFormatter{}
+	if err := f.FormatGroupedTaskList(&buf, "status", groups, &backend.TaskList{Tasks: tableTestTasks}); err != nil {
+		t.Fatalf("FormatGroupedTaskList() error = %v", err)
+	}
+
+	table, err := ParseOutputTable(buf.String())
+	if err != nil {
+		t.Fatalf("ParseOutputTable() error = %v", err)
+	}
+	if len(table.Rows) != 2 {
+		t.Fatalf("got %d rows, want one per task across groups: %+v", len(table.Rows), table.Rows)
+	}
+	assertTableRow(t, table, "001", map[string]string{"status": "todo"})
+	assertTableRow(t, table, "GH-10", map[string]string{"status": "done"})
+}
+
+func TestParseOutputTableEmptyCells(t *testing.T) {
+	// A cell with no text at all is only padding, unlike the formatter's "—"
+	out := "ID   ASSIGNEE  TITLE\n" +
+		"001            Write the docs\n" +
+		"002  @bob      Ship it\n"
+
+	table, err := ParseOutputTable(out)
+	if err != nil {
+		t.Fatalf("ParseOutputTable() error = %v", err)
+	}
+	assertTableRow(t, table, "001", map[string]string{"assignee": "", "title": "Write the docs"})
+	assertTableRow(t, table, "002", map[string]string{"assignee": "@bob", "title": "Ship it"})
+	if !table.HasColumn("Assignee") || table.HasColumn("labels") {
+		t.Errorf("HasColumn() doesn't match columns %v", table.Columns)
+	}
+}
+
+func TestParseOutputTableWithoutTable(t *testing.T) {
+	if _, err := ParseOutputTable("No tasks found.\n"); err == nil {
+		t.Error("ParseOutputTable() succeeded without a table, want error")
+	}
+}
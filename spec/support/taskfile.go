@@ -3,9 +3,12 @@ package support
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -14,32 +17,65 @@ import (
 // TaskFile represents a parsed task markdown file with frontmatter.
 type TaskFile struct {
 	// Path is the absolute path to the task file
-	Path string
+	Path string `yaml:"-"`
 
 	// Status is derived from the directory name (backlog, todo, in-progress, review, done)
-	Status string
+	Status string `yaml:"-"`
 
 	// Frontmatter fields
-	ID       string   `yaml:"id"`
-	Title    string   `yaml:"title"`
-	Priority string   `yaml:"priority,omitempty"`
-	Assignee string   `yaml:"assignee,omitempty"`
-	Labels   []string `yaml:"labels,omitempty"`
-	AgentID  string   `yaml:"agent_id,omitempty"`
-	Created  string   `yaml:"created,omitempty"`
-	Updated  string   `yaml:"updated,omitempty"`
-
-	// Description is the content after the frontmatter
-	Description string
+	ID          string   `yaml:"id"`
+	Title       string   `yaml:"title"`
+	Priority    string   `yaml:"priority,omitempty"`
+	Assignee    string   `yaml:"assignee,omitempty"`
+	Labels      []string `yaml:"labels,omitempty"`
+	AgentID     string   `yaml:"agent_id,omitempty"` // written by older versions of the CLI
+	Parent      string   `yaml:"parent,omitempty"`
+	Blocks      []string `yaml:"blocks,omitempty"`
+	BlockedBy   []string `yaml:"blocked_by,omitempty"`
+	SortOrder   float64  `yaml:"sort_order,omitempty"`
+	Estimate    string   `yaml:"estimate,omitempty"`
+	Spent       string   `yaml:"spent,omitempty"`
+	Created     string   `yaml:"created,omitempty"`
+	Updated     string   `yaml:"updated,omitempty"`
+	URL         string   `yaml:"url,omitempty"`
+	DeletedAt   string   `yaml:"deleted_at,omitempty"`
+	StartedAt   string   `yaml:"started_at,omitempty"`
+	CompletedAt string   `yaml:"completed_at,omitempty"`
+
+	// Extra holds frontmatter fields not listed above, such as due dates
+	// or other hand-added metadata, as written
+	Extra map[string]yaml.Node `yaml:",inline"`
+
+	// Description is the content after the frontmatter, up to the comments
+	Description string `yaml:"-"`
+
+	// Comments are parsed from the "## Comments" section, in file order
+	Comments []TaskComment `yaml:"-"`
 
 	// RawFrontmatter is the raw YAML frontmatter content
-	RawFrontmatter string
+	RawFrontmatter string `yaml:"-"`
 
 	// RawContent is the raw content after frontmatter
-	RawContent string
+	RawContent string `yaml:"-"`
 
 	// ParseErr holds any error that occurred during parsing
-	ParseErr error
+	ParseErr error `yaml:"-"`
+}
+
+// TaskComment is a comment in a task file's "## Comments" section.
+type TaskComment struct {
+	// ID is the comment's ID, empty for comments written before IDs were
+	// stored
+	ID string
+
+	// Author is the name after the "@" in the comment header
+	Author string
+
+	// Date is the date in the comment header, as written (2025-01-16)
+	Date string
+
+	// Body is the comment text, trimmed
+	Body string
 }
 
 // TaskFileReader reads and parses task files from the .backlog directory.
@@ -110,7 +146,6 @@ func (r *TaskFileReader) ReadTaskFile(path string) *TaskFile {
 	}
 
 	task.RawFrontmatter = frontmatter
-	task.RawContent = body
 
 	// Parse YAML frontmatter
 	if err := yaml.Unmarshal([]byte(frontmatter), task); err != nil {
@@ -118,12 +153,18 @@ func (r *TaskFileReader) ReadTaskFile(path string) *TaskFile {
 		return task
 	}
 
-	// Extract description (content after frontmatter, excluding "## Description" header if present)
-	task.Description = extractDescription(body)
+	task.setBody(body)
 
 	return task
 }
 
+// setBody sets the raw content after the frontmatter and the description
+// and comments parsed from it.
+func (t *TaskFile) setBody(body string) {
+	t.RawContent = body
+	t.Description, t.Comments = parseBody(body)
+}
+
 // ListTasks lists all tasks in the backlog directory.
 func (r *TaskFileReader) ListTasks() []*TaskFile {
 	return r.ListTasksByStatus("")
@@ -243,11 +284,156 @@ func (t *TaskFile) GetField(name string) string {
 		return t.Updated
 	case "description":
 		return t.Description
+	case "parent":
+		return t.Parent
+	case "sort_order":
+		if t.SortOrder == 0 {
+			return ""
+		}
+		return strconv.FormatFloat(t.SortOrder, 'g', -1, 64)
+	case "estimate":
+		return t.Estimate
+	case "spent":
+		return t.Spent
+	case "url":
+		return t.URL
 	default:
+		if node, ok := t.Extra[name]; ok && node.Kind == yaml.ScalarNode && node.Tag != "!!null" {
+			return node.Value
+		}
 		return ""
 	}
 }
 
+// HasComment checks if any comment's body contains text.
+func (t *TaskFile) HasComment(text string) bool {
+	for _, c := range t.Comments {
+		if strings.Contains(c.Body, text) {
+			return true
+		}
+	}
+	return false
+}
+
+// BlocksTask checks if the task lists id in its blocks field.
+func (t *TaskFile) BlocksTask(id string) bool {
+	return containsString(t.Blocks, id)
+}
+
+// IsBlockedBy checks if the task lists id in its blocked_by field.
+func (t *TaskFile) IsBlockedBy(id string) bool {
+	return containsString(t.BlockedBy, id)
+}
+
+// AddLabel adds a label to the front of the task's labels, keeping the
+// list's YAML style. Adding a label the task already has does nothing.
+// Call Save to write the change.
+func (t *TaskFile) AddLabel(label string) error {
+	if t.HasLabel(label) {
+		return nil
+	}
+	return t.SetField("labels", append([]string{label}, t.Labels...))
+}
+
+// SetField sets a frontmatter field, adding it at the end if the task
+// doesn't have it, and leaves the other fields as written. A list keeps the
+// flow or block style it had. Call Save to write the change.
+func (t *TaskFile) SetField(key string, value any) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(t.RawFrontmatter), &doc); err != nil {
+		return fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	mapping := doc.Content[0]
+
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+
+	replaced := false
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if old := mapping.Content[i+1]; mapping.Content[i].Value == key {
+			if old.Style&yaml.FlowStyle != 0 {
+				node.Style |= yaml.FlowStyle
+			}
+			node.LineComment = old.LineComment
+			mapping.Content[i+1] = &node
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &node)
+	}
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal frontmatter: %w", err)
+	}
+
+	parsed := TaskFile{}
+	if err := yaml.Unmarshal(out, &parsed); err != nil {
+		return fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	parsed.Path, parsed.Status, parsed.ParseErr = t.Path, t.Status, t.ParseErr
+	parsed.RawFrontmatter = strings.TrimRight(string(out), "\n")
+	parsed.setBody(t.RawContent)
+	*t = parsed
+	return nil
+}
+
+// AddComments appends comments to the task's "## Comments" section,
+// starting the section if the task has none. Comments without an ID are
+// written without one, as older versions of the CLI did. Call Save to
+// write the change.
+func (t *TaskFile) AddComments(comments ...TaskComment) {
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(t.RawContent, "\n"))
+	if findCommentsSection(t.RawContent) == -1 {
+		b.WriteString("\n\n## Comments\n")
+	} else {
+		b.WriteString("\n")
+	}
+	for _, c := range comments {
+		fmt.Fprintf(&b, "\n### %s @%s", c.Date, c.Author)
+		if c.ID != "" {
+			fmt.Fprintf(&b, " <!-- id: %s -->", c.ID)
+		}
+		fmt.Fprintf(&b, "\n\n%s\n", c.Body)
+	}
+	t.setBody(strings.TrimRight(b.String(), "\n"))
+}
+
+// Save writes the task back to its file.
+func (t *TaskFile) Save() error {
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	if t.RawFrontmatter != "" {
+		buf.WriteString(t.RawFrontmatter)
+		buf.WriteString("\n")
+	}
+	buf.WriteString("---\n")
+	buf.WriteString(t.RawContent)
+	buf.WriteString("\n")
+	if err := os.WriteFile(t.Path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write task file: %w", err)
+	}
+	return nil
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
 // parseFrontmatter splits content into frontmatter and body.
 // Frontmatter is delimited by "---" at the start and end.
 func parseFrontmatter(content string) (frontmatter, body string, err error) {
@@ -299,6 +485,53 @@ func parseFrontmatter(content string) (frontmatter, body string, err error) {
 	return frontmatter, body, nil
 }
 
+// parseBody splits the content after the frontmatter into the description
+// and the comments.
+func parseBody(body string) (string, []TaskComment) {
+	idx := findCommentsSection(body)
+	if idx == -1 {
+		return extractDescription(body), nil
+	}
+	lines := strings.Split(body, "\n")
+	return extractDescription(strings.Join(lines[:idx], "\n")), parseComments(strings.Join(lines[idx+1:], "\n"))
+}
+
+// findCommentsSection returns the index of the "## Comments" heading among
+// the lines of body, or -1 if there is none. Headings inside fenced code
+// blocks belong to the description and are skipped.
+func findCommentsSection(body string) int {
+	inFence := false
+	for i, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		} else if !inFence && trimmed == "## Comments" {
+			return i
+		}
+	}
+	return -1
+}
+
+// commentHeaderRe matches comment headers: "### 2025-01-16 @alex",
+// optionally followed by the comment's ID: "<!-- id: 2 -->". Older files
+// have no ID.
+var commentHeaderRe = regexp.MustCompile(`(?m)^###[ \t]+(\S+)[ \t]+@(\S+)(?:[ \t]+<!--\s*id:\s*(\S+?)\s*-->)?[ \t]*$`)
+
+// parseComments parses the comments section of a task file.
+func parseComments(content string) []TaskComment {
+	var comments []TaskComment
+	parts := commentHeaderRe.Split(content, -1)
+	for i, match := range commentHeaderRe.FindAllStringSubmatch(content, -1) {
+		comments = append(comments, TaskComment{
+			ID:     match[3],
+			Author: match[2],
+			Date:   match[1],
+			Body:   strings.TrimSpace(parts[i+1]),
+		})
+	}
+	return comments
+}
+
 // extractDescription extracts the description from the body content.
 // It removes the "## Description" header if present.
 func extractDescription(body string) string {
@@ -1,7 +1,9 @@
 package support
 
 import (
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -518,3 +520,200 @@ title: Missing quote
 		t.Error("expected error message to be set")
 	}
 }
+
+func TestTaskFileReader_ReadTaskFile_CommentsAndRelations(t *testing.T) {
+	env, err := NewTestEnv()
+	if err != nil {
+		t.Fatalf("failed to create test env: %v", err)
+	}
+	defer env.Cleanup()
+
+	if err := env.CreateBacklogDir(); err != nil {
+		t.Fatalf("failed to create backlog dir: %v", err)
+	}
+
+	// As the local backend writes it, with a hand-added due field
+	taskContent := `---
+id: "002"
+title: Ship release
+priority: high
+labels:
+    - release
+parent: "001"
+blocks:
+    - "003"
+blocked_by:
+    - "004"
+    - "005"
+sort_order: 1536
+estimate: 2h
+created: 2025-01-15T09:00:00Z
+updated: 2025-01-18T14:30:00Z
+due: 2025-02-01
+---
+
+## Description
+
+Tag and publish.
+
+` + "```" + `markdown
+## Comments
+Not a real comments section.
+` + "```" + `
+
+## Comments
+
+### 2025-01-16 @alex <!-- id: c1 -->
+
+Started on the changelog.
+
+### 2025-01-17 @claude-1 <!-- id: c2 -->
+
+Waiting on 004.
+Then 005.
+`
+	if err := env.CreateFile(".backlog/in-progress/002-ship-release.md", taskContent); err != nil {
+		t.Fatalf("failed to create task file: %v", err)
+	}
+
+	reader := NewTaskFileReader(env.BacklogDir)
+	task := reader.ReadTask("002")
+	if !task.Valid() {
+		t.Fatalf("task should be valid, got error: %s", task.Error())
+	}
+
+	if task.Parent != "001" || !task.BlocksTask("003") || !task.IsBlockedBy("005") || task.IsBlockedBy("003") {
+		t.Errorf("relations = parent %q, blocks %v, blocked_by %v", task.Parent, task.Blocks, task.BlockedBy)
+	}
+	if task.SortOrder != 1536 || task.GetField("sort_order") != "1536" {
+		t.Errorf("sort_order = %v (%q), want 1536", task.SortOrder, task.GetField("sort_order"))
+	}
+	if task.GetField("estimate") != "2h" {
+		t.Errorf("estimate = %q, want 2h", task.GetField("estimate"))
+	}
+	if task.GetField("due") != "2025-02-01" {
+		t.Errorf("due = %q, want 2025-02-01 from the extra fields", task.GetField("due"))
+	}
+	if !strings.HasPrefix(task.Description, "Tag and publish.") || !strings.Contains(task.Description, "Not a real comments section.") {
+		t.Errorf("description = %q, want it to keep the fenced block", task.Description)
+	}
+
+	if len(task.Comments) != 2 {
+		t.Fatalf("expected 2 comments, got %+v", task.Comments)
+	}
+	want := TaskComment{ID: "c2", Author: "claude-1", Date: "2025-01-17", Body: "Waiting on 004.\nThen 005."}
+	if task.Comments[1] != want {
+		t.Errorf("comment = %+v, want %+v", task.Comments[1], want)
+	}
+	if !task.HasComment("changelog") || task.HasComment("Tag and publish") {
+		t.Error("expected HasComment to search comment bodies only")
+	}
+}
+
+func TestTaskFileReader_ReadTaskFile_OlderFormat(t *testing.T) {
+	env, err := NewTestEnv()
+	if err != nil {
+		t.Fatalf("failed to create test env: %v", err)
+	}
+	defer env.Cleanup()
+
+	if err := env.CreateBacklogDir(); err != nil {
+		t.Fatalf("failed to create backlog dir: %v", err)
+	}
+
+	// No description header, agent_id, and comments without IDs or a
+	// blank line after the header
+	taskContent := `---
+id: "001"
+title: Old task
+assignee: null
+labels: []
+agent_id: claude-1
+---
+Plain description.
+
+## Comments
+
+### 2025-01-16 @alex
+First comment
+### 2025-01-17 @bob
+Second comment
+`
+	if err := env.CreateFile(".backlog/todo/001-old-task.md", taskContent); err != nil {
+		t.Fatalf("failed to create task file: %v", err)
+	}
+
+	task := NewTaskFileReader(env.BacklogDir).ReadTask("001")
+	if !task.Valid() {
+		t.Fatalf("task should be valid, got error: %s", task.Error())
+	}
+	if task.Description != "Plain description." {
+		t.Errorf("description = %q", task.Description)
+	}
+	if !task.IsClaimed() || task.IsAssigned() {
+		t.Errorf("expected claimed and unassigned, got agent_id %q, assignee %q", task.AgentID, task.Assignee)
+	}
+	if len(task.Comments) != 2 || task.Comments[0].ID != "" || task.Comments[0].Body != "First comment" || task.Comments[1].Author != "bob" {
+		t.Errorf("comments = %+v", task.Comments)
+	}
+}
+
+func TestTaskFile_SetFieldAndSave(t *testing.T) {
+	env, err := NewTestEnv()
+	if err != nil {
+		t.Fatalf("failed to create test env: %v", err)
+	}
+	defer env.Cleanup()
+
+	if err := env.CreateBacklogDir(); err != nil {
+		t.Fatalf("failed to create backlog dir: %v", err)
+	}
+
+	taskContent := `---
+id: "001"
+title: Fix login
+labels: [bug] # triaged
+custom: kept
+---
+
+## Description
+
+Details.
+`
+	if err := env.CreateFile(".backlog/todo/001-fix-login.md", taskContent); err != nil {
+		t.Fatalf("failed to create task file: %v", err)
+	}
+
+	reader := NewTaskFileReader(env.BacklogDir)
+	task := reader.ReadTask("001")
+	if err := task.AddLabel("agent:claude-1"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	if err := task.SetField("agent_id", "claude-1"); err != nil {
+		t.Fatalf("SetField failed: %v", err)
+	}
+	task.AddComments(TaskComment{Author: "alex", Date: "2025-01-16", Body: "Looked into it"})
+	task.AddComments(TaskComment{ID: "c2", Author: "bob", Date: "2025-01-17", Body: "Me too"})
+	if err := task.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, err := os.ReadFile(task.Path)
+	if err != nil {
+		t.Fatalf("failed to read task file: %v", err)
+	}
+	content := string(data)
+	for _, want := range []string{`id: "001"`, "labels: ['agent:claude-1', bug] # triaged", "custom: kept", "agent_id: claude-1", "\n## Comments\n\n### 2025-01-16 @alex\n\nLooked into it\n\n### 2025-01-17 @bob <!-- id: c2 -->\n"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected file to contain %q, got:\n%s", want, content)
+		}
+	}
+	if strings.Count(content, "## Comments") != 1 {
+		t.Errorf("expected a single comments section, got:\n%s", content)
+	}
+
+	reread := reader.ReadTask("001")
+	if !reread.Valid() || reread.Description != "Details." || len(reread.Comments) != 2 || reread.AgentID != "claude-1" || reread.GetField("custom") != "kept" {
+		t.Errorf("reread task = %+v", reread)
+	}
+}
@@ -4,83 +4,102 @@ package support
 import (
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 )
 
-// TestEnv holds the test environment state for a scenario.
+// TestEnv holds the test environment state for a scenario. Scenarios run
+// concurrently, so a TestEnv never touches process-wide state: it doesn't
+// change the working directory, and environment variables set on it apply
+// only to the commands it runs (see Environ).
 type TestEnv struct {
 	// TempDir is the temporary directory for this test run
 	TempDir string
 	// BacklogDir is the .backlog directory within TempDir
 	BacklogDir string
-	// OriginalDir is the directory we were in before the test
-	OriginalDir string
-	// OriginalEnv stores original environment variables to restore
-	OriginalEnv map[string]string
+	// HomeDir is the HOME for commands run in this scenario, so state the
+	// CLI keeps in ~/.config/backlog never leaks between scenarios
+	HomeDir string
+	// Vars holds environment variables set for this scenario
+	Vars map[string]string
+	// Unset holds environment variables removed for this scenario
+	Unset map[string]bool
 }
 
-// NewTestEnv creates a new isolated test environment.
-// It creates a temporary directory and changes into it.
+// NewTestEnv creates a new isolated test environment in a fresh temporary
+// directory, with its own HOME.
 func NewTestEnv() (*TestEnv, error) {
-	// Get current directory to restore later
-	originalDir, err := os.Getwd()
-	if err != nil {
-		return nil, err
-	}
-
-	// Create temp directory
 	tempDir, err := os.MkdirTemp("", "backlog-test-*")
 	if err != nil {
 		return nil, err
 	}
-
-	// Change to temp directory
-	if err := os.Chdir(tempDir); err != nil {
+	homeDir, err := os.MkdirTemp("", "backlog-home-*")
+	if err != nil {
 		os.RemoveAll(tempDir)
 		return nil, err
 	}
 
 	return &TestEnv{
-		TempDir:     tempDir,
-		BacklogDir:  filepath.Join(tempDir, ".backlog"),
-		OriginalDir: originalDir,
-		OriginalEnv: make(map[string]string),
+		TempDir:    tempDir,
+		BacklogDir: filepath.Join(tempDir, ".backlog"),
+		HomeDir:    homeDir,
+		Vars:       map[string]string{"HOME": homeDir},
+		Unset:      make(map[string]bool),
 	}, nil
 }
 
-// Cleanup removes the temporary directory and restores the original state.
+// Cleanup removes the temporary and home directories.
 func (e *TestEnv) Cleanup() error {
-	// Restore original directory
-	if err := os.Chdir(e.OriginalDir); err != nil {
+	if err := os.RemoveAll(e.HomeDir); err != nil {
 		return err
 	}
-
-	// Restore original environment variables
-	for key, value := range e.OriginalEnv {
-		if value == "" {
-			os.Unsetenv(key)
-		} else {
-			os.Setenv(key, value)
-		}
-	}
-
-	// Remove temp directory
 	return os.RemoveAll(e.TempDir)
 }
 
-// SetEnv sets an environment variable and stores the original value for restoration.
+// SetEnv sets an environment variable for commands run in this scenario.
 func (e *TestEnv) SetEnv(key, value string) {
-	if _, exists := e.OriginalEnv[key]; !exists {
-		e.OriginalEnv[key] = os.Getenv(key)
-	}
-	os.Setenv(key, value)
+	delete(e.Unset, key)
+	e.Vars[key] = value
 }
 
-// UnsetEnv unsets an environment variable and stores the original value for restoration.
+// UnsetEnv removes an environment variable for commands run in this scenario.
 func (e *TestEnv) UnsetEnv(key string) {
-	if _, exists := e.OriginalEnv[key]; !exists {
-		e.OriginalEnv[key] = os.Getenv(key)
+	delete(e.Vars, key)
+	e.Unset[key] = true
+}
+
+// Getenv returns the value a command run in this scenario would see for key.
+func (e *TestEnv) Getenv(key string) string {
+	if e.Unset[key] {
+		return ""
+	}
+	if value, ok := e.Vars[key]; ok {
+		return value
+	}
+	return os.Getenv(key)
+}
+
+// Environ returns the environment for commands run in this scenario: the
+// process environment with the scenario's variables applied.
+func (e *TestEnv) Environ() []string {
+	var environ []string
+	for _, kv := range os.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		if _, overridden := e.Vars[key]; overridden || e.Unset[key] {
+			continue
+		}
+		environ = append(environ, kv)
+	}
+
+	keys := make([]string, 0, len(e.Vars))
+	for key := range e.Vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		environ = append(environ, key+"="+e.Vars[key])
 	}
-	os.Unsetenv(key)
+	return environ
 }
 
 // CreateBacklogDir creates the .backlog directory structure.
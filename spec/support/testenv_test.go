@@ -25,15 +25,10 @@ func TestNewTestEnv(t *testing.T) {
 		t.Errorf("TempDir should contain 'backlog-test-', got %s", env.TempDir)
 	}
 
-	// Check we changed to temp directory
+	// Scenarios run concurrently, so the working directory must not change
 	currentDir, _ := os.Getwd()
-	if currentDir != env.TempDir {
-		t.Errorf("Should be in temp directory, got %s, want %s", currentDir, env.TempDir)
-	}
-
-	// Check original directory was saved
-	if env.OriginalDir != originalDir {
-		t.Errorf("OriginalDir = %s, want %s", env.OriginalDir, originalDir)
+	if currentDir != originalDir {
+		t.Errorf("Working directory changed to %s, want %s", currentDir, originalDir)
 	}
 
 	// Check BacklogDir is set correctly
@@ -41,55 +36,67 @@ func TestNewTestEnv(t *testing.T) {
 	if env.BacklogDir != expectedBacklogDir {
 		t.Errorf("BacklogDir = %s, want %s", env.BacklogDir, expectedBacklogDir)
 	}
+
+	// Commands get their own HOME, outside the project directory
+	if env.Getenv("HOME") != env.HomeDir || strings.HasPrefix(env.HomeDir, env.TempDir) {
+		t.Errorf("HOME = %s, want separate HomeDir %s", env.Getenv("HOME"), env.HomeDir)
+	}
 }
 
 func TestTestEnv_Cleanup(t *testing.T) {
-	originalDir, _ := os.Getwd()
-
 	env, err := NewTestEnv()
 	if err != nil {
 		t.Fatalf("NewTestEnv() error = %v", err)
 	}
 
-	tempDir := env.TempDir
+	tempDir, homeDir := env.TempDir, env.HomeDir
 
-	// Cleanup should restore directory and remove temp dir
 	err = env.Cleanup()
 	if err != nil {
 		t.Fatalf("Cleanup() error = %v", err)
 	}
 
-	// Check we're back in original directory
-	currentDir, _ := os.Getwd()
-	if currentDir != originalDir {
-		t.Errorf("After cleanup, should be in %s, got %s", originalDir, currentDir)
-	}
-
-	// Check temp directory was removed
+	// Check temp and home directories were removed
 	if _, err := os.Stat(tempDir); !os.IsNotExist(err) {
 		t.Errorf("Temp directory should be removed after cleanup")
 	}
+	if _, err := os.Stat(homeDir); !os.IsNotExist(err) {
+		t.Errorf("Home directory should be removed after cleanup")
+	}
 }
 
 func TestTestEnv_SetEnv(t *testing.T) {
+	t.Setenv("TEST_BACKLOG_UNSET", "inherited")
 	env, err := NewTestEnv()
 	if err != nil {
 		t.Fatalf("NewTestEnv() error = %v", err)
 	}
 	defer env.Cleanup()
 
-	// Set a new env var
 	env.SetEnv("TEST_BACKLOG_VAR", "test-value")
+	env.UnsetEnv("TEST_BACKLOG_UNSET")
 
-	if got := os.Getenv("TEST_BACKLOG_VAR"); got != "test-value" {
-		t.Errorf("SetEnv did not set variable, got %s, want test-value", got)
+	// The process environment is left alone
+	if got := os.Getenv("TEST_BACKLOG_VAR"); got != "" {
+		t.Errorf("SetEnv changed the process environment, got %q", got)
+	}
+	if got := os.Getenv("TEST_BACKLOG_UNSET"); got != "inherited" {
+		t.Errorf("UnsetEnv changed the process environment, got %q", got)
 	}
 
-	// Cleanup should restore (unset) the variable
-	env.Cleanup()
+	if got := env.Getenv("TEST_BACKLOG_VAR"); got != "test-value" {
+		t.Errorf("Getenv(TEST_BACKLOG_VAR) = %q, want test-value", got)
+	}
+	if got := env.Getenv("TEST_BACKLOG_UNSET"); got != "" {
+		t.Errorf("Getenv(TEST_BACKLOG_UNSET) = %q, want empty", got)
+	}
 
-	if got := os.Getenv("TEST_BACKLOG_VAR"); got != "" {
-		t.Errorf("After cleanup, env var should be unset, got %s", got)
+	environ := strings.Join(env.Environ(), "\n")
+	if !strings.Contains(environ, "TEST_BACKLOG_VAR=test-value") {
+		t.Error("Environ() should include variables set on the scenario")
+	}
+	if strings.Contains(environ, "TEST_BACKLOG_UNSET=") {
+		t.Error("Environ() should omit variables unset on the scenario")
 	}
 }
 